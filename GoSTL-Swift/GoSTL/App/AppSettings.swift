@@ -0,0 +1,304 @@
+import Foundation
+
+/// Application-wide preferences shared by all windows
+@Observable
+final class AppSettings: @unchecked Sendable {
+    static let shared = AppSettings()
+
+    private let settingsFile: URL
+
+    /// Mouse button/modifier mapping for camera navigation
+    var navigationScheme: NavigationScheme = .gostl {
+        didSet { save() }
+    }
+
+    /// Keep rotating after a flick and glide through mouse wheel zoom steps
+    var navigationInertia: Bool = false {
+        didSet { save() }
+    }
+
+    /// Zoom toward the surface point under the mouse instead of the orbit target
+    var zoomToCursor: Bool = false {
+        didSet { save() }
+    }
+
+    /// Show a magnified inset around the cursor while picking points
+    var showMagnifier: Bool = true {
+        didSet { save() }
+    }
+
+    /// Magnification of the picking inset (see `magnifierZoomLevels`)
+    var magnifierZoom: Double = 3 {
+        didSet { save() }
+    }
+
+    /// Magnifications offered in the settings
+    static let magnifierZoomLevels: [Double] = [2, 3, 4]
+
+    /// Distance from the cursor within which picked points snap to a mesh vertex, in points on screen
+    var snapRadius: Double = 10 {
+        didSet { save() }
+    }
+
+    /// Draw the snap radius as a circle around the cursor while picking
+    var showSnapRadius: Bool = true {
+        didSet { save() }
+    }
+
+    /// Snap radii offered in the settings and by the +/- keys (0 turns vertex snapping off)
+    static let snapRadiusRange: ClosedRange<Double> = 0...40
+
+    /// Change of the snap radius per +/- key press
+    static let snapRadiusStep: Double = 2
+
+    /// Snap radius moved by a number of steps, kept within `snapRadiusRange`
+    static func snapRadius(_ radius: Double, steppedBy steps: Int) -> Double {
+        min(max(radius + Double(steps) * snapRadiusStep, snapRadiusRange.lowerBound), snapRadiusRange.upperBound)
+    }
+
+    /// Decimal places of lengths in labels, panels and reports (see `DisplayPrecision`)
+    var lengthDecimals: Int = DisplayPrecision.standard.lengthDecimals {
+        didSet { save() }
+    }
+
+    /// Decimal places of angles in labels, panels and reports
+    var angleDecimals: Int = DisplayPrecision.standard.angleDecimals {
+        didSet { save() }
+    }
+
+    /// Unit or notation of displayed lengths
+    var lengthScale: LengthScale = .automatic {
+        didSet { save() }
+    }
+
+    /// Offer scale fixes when a loaded model is implausibly small or large (see `ScaleCheck`)
+    var checkModelScale: Bool = true {
+        didSet { save() }
+    }
+
+    /// Check new models for mesh defects and show a banner when there are any (see `MeshQuality`)
+    var checkMeshQuality: Bool = true {
+        didSet { save() }
+    }
+
+    /// Shade the model with baked ambient occlusion in presentation mode (off: no bake, full interactivity)
+    var presentationOcclusion: Bool = false {
+        didSet { save() }
+    }
+
+    /// Options of the last view image export (File > Export View Image...)
+    var presentationShot = PresentationShotOptions() {
+        didSet { save() }
+    }
+
+    /// Open binary STL files too large to keep in memory as a decimated proxy with full-resolution chunks near the camera (see `StreamingModel`)
+    var streamLargeModels: Bool = true {
+        didSet { save() }
+    }
+
+    /// GPU memory for full-resolution chunks of a streamed model, in MB
+    var streamingBudget: Int = 1024 {
+        didSet { save() }
+    }
+
+    /// Saved panel layouts (View > Layouts)
+    var workspaceLayouts: [WorkspaceLayout] = [] {
+        didSet { save() }
+    }
+
+    /// Measurement templates for batches of parts (Tools > Batch Templates)
+    var measurementTemplates: [MeasurementTemplate] = [] {
+        didSet { save() }
+    }
+
+    /// Display precision from the display settings
+    /// - Parameter extent: Largest dimension of the model in millimeters, for the automatic unit
+    func precision(forModelExtent extent: Double?) -> DisplayPrecision {
+        let scale = lengthScale.resolved(forExtent: extent)
+        return DisplayPrecision(
+            lengthDecimals: lengthDecimals,
+            angleDecimals: angleDecimals,
+            unit: scale.unit,
+            engineeringNotation: scale.engineeringNotation
+        )
+    }
+
+    /// Field delimiter and decimal separator of copied and exported CSV
+    var csvFormat: CSVFormat = .standard {
+        didSet { save() }
+    }
+
+    /// Render OpenSCAD files through one 3MF export that keeps color() as materials,
+    /// instead of rendering every color separately
+    var openSCADThreeMFExport: Bool = false {
+        didSet { save() }
+    }
+
+    /// Python interpreter for CadQuery/build123d scripts (nil: detect, see `PythonCADRenderer`)
+    var pythonInterpreter: String? {
+        didSet { save() }
+    }
+
+    /// Root for temporary files (nil: system temp directory); read at launch by `TempWorkspace`
+    var temporaryDirectory: String? {
+        didSet { save() }
+    }
+
+    private init() {
+        // Stored next to the recent documents in ~/.config/gostl
+        let homeDir = FileManager.default.homeDirectoryForCurrentUser
+        let configDir = homeDir.appendingPathComponent(".config").appendingPathComponent("gostl")
+        settingsFile = configDir.appendingPathComponent("settings.json")
+
+        try? FileManager.default.createDirectory(at: configDir, withIntermediateDirectories: true)
+
+        load()
+    }
+
+    // MARK: - Persistence
+
+    /// All fields are optional so older settings files keep loading as new settings are added
+    private struct SettingsConfig: Codable {
+        var navigationScheme: NavigationScheme?
+        var navigationInertia: Bool?
+        var zoomToCursor: Bool?
+        var showMagnifier: Bool?
+        var magnifierZoom: Double?
+        var snapRadius: Double?
+        var showSnapRadius: Bool?
+        var lengthDecimals: Int?
+        var angleDecimals: Int?
+        var lengthScale: LengthScale?
+        var checkModelScale: Bool?
+        var checkMeshQuality: Bool?
+        var presentationOcclusion: Bool?
+        var presentationShot: PresentationShotOptions?
+        var streamLargeModels: Bool?
+        var streamingBudget: Int?
+        var csvFormat: CSVFormat?
+        var temporaryDirectory: String?
+        var openSCADThreeMFExport: Bool?
+        var pythonInterpreter: String?
+        var workspaceLayouts: [WorkspaceLayout]?
+        var measurementTemplates: [MeasurementTemplate]?
+    }
+
+    @ObservationIgnored private var isLoading = false
+
+    private func save() {
+        guard !isLoading else { return }
+
+        let config = SettingsConfig(
+            navigationScheme: navigationScheme,
+            navigationInertia: navigationInertia,
+            zoomToCursor: zoomToCursor,
+            showMagnifier: showMagnifier,
+            magnifierZoom: magnifierZoom,
+            snapRadius: snapRadius,
+            showSnapRadius: showSnapRadius,
+            lengthDecimals: lengthDecimals,
+            angleDecimals: angleDecimals,
+            lengthScale: lengthScale,
+            checkModelScale: checkModelScale,
+            checkMeshQuality: checkMeshQuality,
+            presentationOcclusion: presentationOcclusion,
+            presentationShot: presentationShot,
+            streamLargeModels: streamLargeModels,
+            streamingBudget: streamingBudget,
+            csvFormat: csvFormat,
+            temporaryDirectory: temporaryDirectory,
+            openSCADThreeMFExport: openSCADThreeMFExport,
+            pythonInterpreter: pythonInterpreter,
+            workspaceLayouts: workspaceLayouts,
+            measurementTemplates: measurementTemplates
+        )
+
+        do {
+            let encoder = JSONEncoder()
+            encoder.outputFormatting = .prettyPrinted
+            let data = try encoder.encode(config)
+            try data.write(to: settingsFile)
+        } catch {
+            print("ERROR: Failed to save settings: \(error)")
+        }
+    }
+
+    private func load() {
+        guard FileManager.default.fileExists(atPath: settingsFile.path) else {
+            return
+        }
+
+        isLoading = true
+        defer { isLoading = false }
+
+        do {
+            let data = try Data(contentsOf: settingsFile)
+            let config = try JSONDecoder().decode(SettingsConfig.self, from: data)
+
+            if let scheme = config.navigationScheme {
+                navigationScheme = scheme
+            }
+            if let inertia = config.navigationInertia {
+                navigationInertia = inertia
+            }
+            if let zoomToCursor = config.zoomToCursor {
+                self.zoomToCursor = zoomToCursor
+            }
+            if let showMagnifier = config.showMagnifier {
+                self.showMagnifier = showMagnifier
+            }
+            if let zoom = config.magnifierZoom, Self.magnifierZoomLevels.contains(zoom) {
+                magnifierZoom = zoom
+            }
+            if let radius = config.snapRadius {
+                snapRadius = Self.snapRadius(radius, steppedBy: 0)
+            }
+            if let showSnapRadius = config.showSnapRadius {
+                self.showSnapRadius = showSnapRadius
+            }
+            if let decimals = config.lengthDecimals {
+                lengthDecimals = DisplayPrecision.clamped(decimals)
+            }
+            if let decimals = config.angleDecimals {
+                angleDecimals = DisplayPrecision.clamped(decimals)
+            }
+            if let scale = config.lengthScale {
+                lengthScale = scale
+            }
+            if let check = config.checkModelScale {
+                checkModelScale = check
+            }
+            if let check = config.checkMeshQuality {
+                checkMeshQuality = check
+            }
+            if let occlusion = config.presentationOcclusion {
+                presentationOcclusion = occlusion
+            }
+            if let shot = config.presentationShot {
+                presentationShot = shot
+            }
+            if let stream = config.streamLargeModels {
+                streamLargeModels = stream
+            }
+            if let budget = config.streamingBudget {
+                streamingBudget = budget
+            }
+            if let format = config.csvFormat {
+                csvFormat = format
+            }
+            temporaryDirectory = config.temporaryDirectory
+            if let threeMF = config.openSCADThreeMFExport {
+                openSCADThreeMFExport = threeMF
+            }
+            pythonInterpreter = config.pythonInterpreter
+            if let layouts = config.workspaceLayouts {
+                workspaceLayouts = layouts
+            }
+            if let templates = config.measurementTemplates {
+                measurementTemplates = templates
+            }
+        } catch {
+            print("ERROR: Failed to load settings: \(error)")
+        }
+    }
+}
@@ -66,11 +66,27 @@ final class AppState: @unchecked Sendable {
     /// Spatial acceleration structure for fast ray casting and vertex snapping
     var spatialAccelerator: SpatialAccelerator?
 
+    /// Background work (reloads, spatial index, wireframe, analysis, exports)
+    let jobs = JobQueue()
+
+    /// Back buffer for models prepared by reload jobs
+    let modelBuffer = ModelBuffer()
+
+    /// Spatial index and wireframe buffers waiting for the next frame
+    let uploads = GPUUploadQueue()
+
+    /// Incremented whenever the model geometry changes; uploads built for an older generation are dropped
+    private(set) var modelGeneration = 0
+
     /// Whether the spatial accelerator is currently being built
-    var isBuildingAccelerator: Bool = false
+    var isBuildingAccelerator: Bool {
+        jobs.isRunning(.spatialIndex)
+    }
 
     /// Whether the wireframe is currently being built
-    var isBuildingWireframe: Bool = false
+    var isBuildingWireframe: Bool {
+        jobs.isRunning(.wireframe)
+    }
 
     /// Cached edges for wireframe rendering (extracted once when model loads)
     private var cachedEdges: [Edge]?
@@ -94,6 +110,22 @@ final class AppState: @unchecked Sendable {
     /// Information about the loaded model
     var modelInfo: ModelInfo?
 
+    /// Decimal places and length unit of values shown in this window (the automatic unit follows the
+    /// model's size; units saved for the model take precedence over the settings)
+    var displayPrecision: DisplayPrecision {
+        let size = modelInfo?.boundingBox.size
+        let extent = size.map { Swift.max($0.x, $0.y, $0.z) }
+        let settings = AppSettings.shared.precision(forModelExtent: extent)
+        return modelUnits?.precision(overriding: settings, forModelExtent: extent) ?? settings
+    }
+
+    /// Offset subtracted from the file's coordinates so rendering stays in a small float range.
+    /// The model and all measurements live in this recentered space; add the offset back
+    /// for anything shown to the user or written to disk.
+    var coordinateOffset: Vector3 = .zero {
+        didSet { measurementSystem.gridSnapOffset = coordinateOffset }
+    }
+
     /// GPU mesh data for rendering
     var meshData: MeshData?
 
@@ -116,7 +148,14 @@ final class AppState: @unchecked Sendable {
     var slicePlaneData: SlicePlaneData?
 
     /// GPU cut edge data for rendering sliced edges in axis colors
-    var cutEdgeData: CutEdgeData?
+    var cutEdgeData: CutEdgeData? {
+        didSet {
+            // Cut vertices are snap targets only while the section is shown
+            if cutEdgeData == nil {
+                measurementSystem.cutVertices = []
+            }
+        }
+    }
 
     /// GPU orientation cube data for camera navigation
     var orientationCubeData: OrientationCubeData?
@@ -133,6 +172,34 @@ final class AppState: @unchecked Sendable {
     /// Currently hovered face of the orientation cube (for hover effect)
     var hoveredCubeFace: CubeFace?
 
+    /// Mouse position over the view in pixels (Y=0 at bottom), nil while dragging or outside
+    /// Read by the renderer every frame, so not observed
+    @ObservationIgnored var cursorLocation: CGPoint?
+
+    /// Whether a tool is waiting for a point to be picked on the model
+    var isPicking: Bool {
+        measurementSystem.isCollecting
+            || (levelingState.isActive && !levelingState.isReadyForAxisSelection)
+            || referenceGeometry.isCollecting
+    }
+
+    /// Where to show the magnifier inset, if it is enabled and a point is being picked
+    var magnifierLocation: CGPoint? {
+        guard AppSettings.shared.showMagnifier, isPicking, model != nil else { return nil }
+        return cursorLocation
+    }
+
+    /// Where to draw the vertex snap radius: while picking points that snap to mesh vertices
+    /// (not with grid snap or pick refinement, which place points without vertex snapping)
+    var snapRadiusLocation: CGPoint? {
+        let settings = AppSettings.shared
+        guard settings.showSnapRadius, settings.snapRadius > 0, model != nil,
+              !measurementSystem.isGridSnapActive, !measurementSystem.refinePicks else { return nil }
+        let snapsToVertices = (measurementSystem.isCollecting && measurementSystem.mode != .triangleSelect)
+            || referenceGeometry.isCollecting
+        return snapsToVertices ? cursorLocation : nil
+    }
+
     /// Wireframe display mode
     var wireframeMode: WireframeMode = .edge
 
@@ -149,12 +216,157 @@ final class AppState: @unchecked Sendable {
     /// Whether to show face orientation coloring (front=teal, back=yellow)
     var showFaceOrientation: Bool = false
 
+    /// Outline render mode: white background with only the silhouette and feature edges drawn as dark lines
+    var outlineMode: Bool = false
+
+    /// Background color while outline mode is active
+    static let outlineClearColor: SIMD4<Float> = SIMD4(1, 1, 1, 1)
+
+    /// Edge adjacency for outline rendering, built on first use for the current model generation
+    @ObservationIgnored private var outlineEdges: (generation: Int, edges: OutlineEdges)?
+
+    /// GPU edges of the current outline view, rebuilt when the camera moves
+    @ObservationIgnored private(set) var outlineWireframeData: WireframeData?
+
+    /// Eye position the outline wireframe was built for
+    @ObservationIgnored private var outlineEye: Vector3?
+
+    /// Highlight the flat face or smooth region under the cursor (nil: off)
+    var hoverHighlight: SurfaceRegion.Kind? {
+        didSet {
+            if hoverHighlight != oldValue {
+                hoveredRegion = nil
+            }
+        }
+    }
+
+    /// Region under the cursor while the hover highlight is on
+    private(set) var hoveredRegion: SurfaceRegion? {
+        didSet { hoveredRegionRevision += 1 }
+    }
+    @ObservationIgnored private var hoveredRegionRevision = 0
+
+    /// Model generation the hovered region was grown on
+    @ObservationIgnored private var hoveredRegionGeneration = 0
+
+    /// Triangle adjacency for the hover highlight and face distances, built on first use for the current model generation
+    @ObservationIgnored private var triangleAdjacency: (generation: Int, adjacency: TriangleAdjacency)?
+
+    /// GPU triangles of the hovered region, rebuilt when the region changes
+    @ObservationIgnored private(set) var hoveredRegionBuffer: MTLBuffer?
+    @ObservationIgnored private(set) var hoveredRegionVertexCount = 0
+
+    /// Revision of the hovered region the highlight buffer was built for
+    @ObservationIgnored private var hoveredRegionBufferRevision = 0
+
+    static let hoveredRegionColor = SIMD4<Float>(1.0, 0.78, 0.25, 1.0)
+
+    /// Size of the 3D view in points, recorded by the renderer (used for vector exports)
+    @ObservationIgnored var viewSize: CGSize = .zero
+
     /// Measurement system for distance/angle/radius measurements
     var measurementSystem = MeasurementSystem()
 
+    /// Text log and screen reader announcements for measurement results
+    var measurementAnnouncer = MeasurementAnnouncer()
+
+    /// Whether to show the measurement log panel
+    var showMeasurementLog: Bool = false
+
+    /// Panels shown in their own windows instead of over the 3D view
+    var detachedPanels: Set<DetachedPanel> = []
+
+    /// Presentation mode for design reviews: panels hidden, labels enlarged, saved views flown to
+    private(set) var isPresenting: Bool = false
+
+    /// Saved view the presentation is at (index into `savedViews`)
+    private(set) var presentationViewIndex: Int?
+
+    /// Panels to restore when presentation mode ends
+    @ObservationIgnored private var layoutBeforePresentation: WorkspaceLayout?
+
+    /// Running camera move to a saved view
+    @ObservationIgnored private var cameraFlight: CameraFlight?
+
+    /// Frame timing and draw statistics for the performance HUD
+    var performanceStats = PerformanceStats()
+
+    /// Whether the model metadata panel is visible
+    var showMetadataPanel: Bool = false
+
+    /// Metadata of the loaded file (header text, 3MF metadata, size, date, source)
+    var modelMetadata: ModelMetadata?
+
+    /// User-defined properties of the loaded file, stored in its `.gostl` sidecar
+    private(set) var modelProperties: [ModelProperty] = []
+
+    /// Named cross-sections of the loaded file, stored in its `.gostl` sidecar
+    private(set) var sectionBookmarks: [SectionBookmark] = []
+
+    /// Whether the section bookmarks panel is visible
+    var showSectionBookmarks: Bool = false
+
+    /// Text pinned to points of the loaded file (original coordinates), stored in its `.gostl` sidecar
+    private(set) var annotations: [Annotation] = []
+
+    /// Whether the annotation pins and labels are shown
+    var showAnnotations: Bool = true
+
+    /// Named camera positions of the loaded file, stored in its `.gostl` sidecar
+    private(set) var savedViews: [ViewBookmark] = []
+
+    /// Display units of the loaded file, stored in its `.gostl` sidecar (nil: Settings > Display)
+    private(set) var modelUnits: ModelUnits?
+
+    /// Screen frames (top-left origin, in points) of the annotation labels as last laid out
+    @ObservationIgnored
+    var annotationLabelFrames: [UUID: CGRect] = [:]
+
+    /// Size of the view (in points) the annotation label frames were laid out in
+    @ObservationIgnored
+    var annotationLabelFramesViewSize: CGSize = .zero
+
+    /// Whether the clipping planes panel is visible
+    var showClippingPanel: Bool = false
+
+    /// User-defined datum planes, axes and points
+    var referenceGeometry = ReferenceGeometrySystem()
+
+    /// Whether the reference geometry panel is visible
+    var showReferencePanel: Bool = false
+
+    /// Last plugin analyzer run (shown in the plugin results panel, overlay drawn in the scene)
+    var pluginAnalysis: PluginAnalysis?
+
+    /// Comparison of a baseline measurement export against the current model (shown in the comparison panel)
+    var measurementComparison: MeasurementComparison?
+
+    /// Active session recorder (camera and measurement changes are sampled every frame)
+    var sessionRecorder: SessionRecorder?
+
+    /// Most recently finished session recording (not yet saved)
+    var lastSessionRecording: SessionRecording?
+
+    /// Active session replay (drives the camera and measurements)
+    var sessionPlayer: SessionPlayer?
+
+    /// View and measurements from before the replay (original coordinates), restored when it stops
+    @ObservationIgnored
+    private var stateBeforeReplay: (camera: CameraKeyframe, measurements: [Measurement])?
+
+    /// Whether the go-to palette is open
+    var showGoToPalette: Bool = false
+
+    /// Temporary marker shown at the last go-to target (cleared after a few seconds)
+    var goToMarker: Vector3?
+    private var goToMarkerClearTask: Task<Void, Never>?
+
     /// Slicing system for clipping model along axes
     var slicingState = SlicingState()
 
+    /// Slicer-like preview of single print layers
+    var layerPreview = LayerPreviewState()
+
     /// Leveling system for rotating model to align two points
     var levelingState = LevelingState()
 
@@ -168,9 +380,36 @@ final class AppState: @unchecked Sendable {
     /// Track if the model has been modified (e.g., by leveling)
     var isModelModified: Bool = false
 
+    /// Uniform scale applied to the file's geometry on every load and reload (1: as in the file)
+    /// Fixes unit mistakes without touching the file; saving writes the scaled model
+    private(set) var modelScale: Double = 1
+
+    /// Likely unit mistake of the loaded model, shown as a prompt until fixed or dismissed
+    var scaleCheck: ScaleCheck.Finding?
+
+    /// Index of a streamed file; `model` is its decimated proxy (nil for files loaded as a whole)
+    private(set) var streamingModel: StreamingModel?
+
+    /// Full-resolution chunks paged in around the camera (nil once the proxy was changed, e.g. leveled)
+    @ObservationIgnored private var streamingResidency: StreamingResidency?
+
+    /// What the streamed model shows at full resolution, for the banner
+    private(set) var streamingStatus: StreamingResidency.Status?
+
+    /// The prompt was dismissed for this file (not shown again on reloads)
+    private var isScaleCheckDismissed = false
+
+    /// Defects found by the load-time mesh check, nil while checking or when disabled
+    private(set) var meshQuality: MeshQuality?
+
+    /// The mesh quality banner was dismissed for this file (not shown again on reloads)
+    private(set) var isMeshQualityDismissed = false
+
     /// URL where the model was last saved (may differ from sourceFileURL after "Save As")
     var savedFileURL: URL?
     var isGo3mf: Bool = false
+    /// CadQuery/build123d script rendered through Python
+    var isPythonCAD: Bool = false
     var reloadRequestId: Int = 0  // Incremented to trigger reload - onChange fires on any change
     var isLoading: Bool = false
     var loadError: Error?
@@ -196,8 +435,205 @@ final class AppState: @unchecked Sendable {
         availablePlates.count > 1
     }
 
+    /// Extracted .zip archive the model was loaded from
+    private(set) var modelArchive: ModelArchive?
+
+    /// Path of the shown archive model, nil while all models are shown as an assembly
+    private(set) var selectedArchiveEntry: String?
+
+    /// Whether the current file is an archive with several models to choose from
+    var hasMultipleArchiveEntries: Bool {
+        (modelArchive?.entries.count ?? 0) > 1
+    }
+
+    /// Color groups of a rendered OpenSCAD file (one per color() color); empty for other files
+    var colorGroups: [ColorGroup] = []
+
+    /// IDs of hidden color groups, kept when the file is re-rendered
+    var hiddenColorGroups: Set<String> = []
+
+    /// The rendered OpenSCAD model with every color group, in file coordinates
+    @ObservationIgnored private var colorGroupSource: STLModel?
+
+    /// Top-level modules of the open .scad file that can be rendered in isolation
+    var openSCADModules: [OpenSCADModule] = []
+
+    /// Module rendered instead of the whole .scad file (nil renders the file)
+    var isolatedModule: String?
+
+    /// Frame the camera on the next reloaded model instead of keeping the view (after switching modules)
+    @ObservationIgnored private var frameNextReload = false
+
+    /// PCB viewing preset: grid on the board plane, optional Z exaggeration and component heights
+    private(set) var pcbPreset: Bool = false
+
+    /// Board found in the current model while the PCB preset is on
+    private(set) var pcbBoard: PCBBoard?
+
+    /// Display stretch along Z while the PCB preset is on
+    private(set) var pcbZExaggeration: Float = 1
+
+    /// Z exaggeration choices offered for PCB viewing
+    static let pcbExaggerationOptions: [Float] = [1, 2, 5, 10]
+
+    /// Second model checked against the main model for collisions and clearance, in its file's coordinates
+    @ObservationIgnored private var clearanceSource: STLModel?
+
+    /// File name of the second model (set while it loads)
+    private(set) var clearanceModelName: String?
+
+    /// Second model in render space
+    private(set) var clearanceModel: STLModel?
+
+    /// Last clearance check in render space (nil while loading or checking)
+    private(set) var clearanceResult: ClearanceResult?
+
+    /// Why loading or checking the second model failed
+    private(set) var clearanceError: String?
+
+    /// GPU data of the second model and the interpenetrating triangles
+    private(set) var clearanceRenderData: ClearanceRenderData?
+
+    /// Whether the second model is loading or being checked
+    var isCheckingClearance: Bool {
+        clearanceModelName != nil && clearanceResult == nil && clearanceError == nil
+    }
+
+    /// Second model shown side by side in the comparison pane, in its file's coordinates
+    @ObservationIgnored private var comparisonSource: STLModel?
+
+    /// File name of the compared model (set while it loads)
+    private(set) var comparisonModelName: String?
+
+    /// Compared model in render space, moved onto the main model when `registerComparison` is on
+    private(set) var comparisonModel: STLModel?
+
+    /// Rigid transform found by registering the compared model onto the main model
+    private(set) var comparisonRegistration: ModelRegistration.Result?
+
+    /// Why loading or registering the compared model failed
+    private(set) var comparisonError: String?
+
+    /// GPU data of the comparison pane
+    private(set) var comparisonRenderData: ComparisonRenderData?
+
+    /// Register the compared model onto the main model (otherwise both keep their file coordinates)
+    private(set) var registerComparison: Bool = true
+
+    /// Matching triangles of both models and ray casting in the comparison pane
+    @ObservationIgnored private var comparisonCorrespondence: ModelRegistration.Correspondence?
+    @ObservationIgnored private var comparisonAccelerator: SpatialAccelerator?
+
+    /// Whether the compared model is loading or being registered
+    var isPreparingComparison: Bool {
+        comparisonModelName != nil && comparisonModel == nil && comparisonError == nil
+    }
+
+    /// Iso-height contour lines on the model surface
+    private(set) var showContourLines: Bool = false
+
+    /// Contour spacing chosen by the user in mm (nil: a round interval for the model height)
+    private(set) var contourIntervalSetting: Double?
+
+    /// Spacing, level range and completeness of the displayed contour lines
+    private(set) var contourInterval: Double?
+    private(set) var contourLevels: ClosedRange<Int>?
+    private(set) var contourTruncated: Bool = false
+
+    /// GPU data for the contour lines
+    var contourData: CutEdgeData?
+
+    /// Faces colored by draft angle relative to the pull direction (for molding and casting)
+    private(set) var showDraftAnalysis: Bool = false
+    private(set) var draftPullDirection: DraftAnalysis.PullDirection = .z
+    private(set) var draftMinimumAngle: Double = DraftAnalysis.defaultMinimumAngle
+
+    /// Last draft analysis of the current model (nil while analyzing)
+    private(set) var draftResult: DraftAnalysis.Result?
+
+    /// Colors for the draft, shown instead of the model's own colors while they match the model generation
+    @ObservationIgnored private var draftShading: (generation: Int, colors: VertexColors)?
+
+    /// Faces colored by detected primitive surface (planes, cylinders, cones, spheres)
+    private(set) var showPrimitives: Bool = false
+
+    /// Last primitive detection of the current model in file coordinates (nil while detecting)
+    private(set) var primitiveResult: PrimitiveDetection.Result?
+
+    /// Colors for the primitives, shown instead of the model's own colors while they match the model generation
+    @ObservationIgnored private var primitiveShading: (generation: Int, colors: VertexColors)?
+
+    /// Faces colored by one coordinate with a legend (makes warping and tilt of flat parts visible)
+    private(set) var showHeightColors: Bool = false
+    private(set) var heightColorAxis: HeightColors.Axis = .z
+
+    /// Coordinate range of the gradient in file coordinates (nil while coloring)
+    private(set) var heightColorRange: ClosedRange<Double>?
+
+    /// Colors for the height, shown instead of the model's own colors while they match the model generation
+    @ObservationIgnored private var heightShading: (generation: Int, colors: VertexColors)?
+
+    /// Baked ambient occlusion of the model, applied while presenting with it enabled (see `AmbientOcclusion`)
+    @ObservationIgnored private var occlusionShading: (generation: Int, occlusion: [Float])?
+
+    /// Model generation an occlusion bake was started for (nil when none is pending)
+    @ObservationIgnored private var occlusionBakeGeneration: Int?
+
+    /// Whether the mesh buffer carries the baked occlusion
+    @ObservationIgnored private var isOcclusionShown = false
+
+    /// Virtual caliper with jaws across an axis or face normal (nil while not shown)
+    private(set) var caliper: Caliper?
+
+    /// Where the caliper jaws seat on the model (nil if no surface lies between them)
+    private(set) var caliperReading: Caliper.Reading?
+
+    /// GPU data for the caliper jaws
+    var caliperData: CaliperData?
+
+    /// Clicks probe the material thickness under the cursor instead of picking points
+    private(set) var isProbingThickness: Bool = false
+
+    /// Last probed thickness (render space; nil before the first probe or when it found no opposite wall)
+    private(set) var thicknessProbe: ThicknessProbe.Result?
+
+    /// Clicks measure from the picked flat face to the parallel face opposite it
+    private(set) var isMeasuringFaceDistance: Bool = false
+
+    /// Side of the picked face the face distance tool measures to
+    var faceDistanceSide: FaceDistance.Side = .nearest
+
+    /// Last face distance (render space; nil before the first pick or when no parallel face was found)
+    private(set) var faceDistance: FaceDistance.Result?
+
+    /// Measurement template being applied to a batch of parts (clicks pick its features)
+    private(set) var templateRun: TemplateRun?
+
+    /// Calibration panel: checks the scan of a printed reference artifact for scale errors
+    private(set) var showCalibration: Bool = false
+
+    /// Artifact the scan is checked against (changing it clears the report)
+    var calibrationArtifact: CalibrationArtifact = .cube20 {
+        didSet { calibrationReport = nil }
+    }
+
+    /// Last calibration result (nil before measuring or after the model changed)
+    private(set) var calibrationReport: CalibrationReport?
+
     init() {
         setupNotifications()
+
+        // Report completed measurements to the log / screen reader
+        measurementSystem.onMeasurementAdded = { [weak self] measurement in
+            guard let self = self else { return }
+            self.measurementAnnouncer.announce(measurement, showDiameter: self.measurementSystem.showDiameter, precision: self.displayPrecision)
+            self.fireHook(.measurementAdded, data: self.hookData(for: measurement))
+        }
+
+        // Let measurements snap to reference entities
+        referenceGeometry.onEntitiesChanged = { [weak self] entities in
+            self?.measurementSystem.referenceEntities = entities
+        }
     }
 
     deinit {
@@ -207,9 +643,10 @@ final class AppState: @unchecked Sendable {
         }
         notificationObservers.removeAll()
 
-        // Stop the file watcher
+        // Stop the file watcher and any background work
         fileWatcher?.stop()
         fileWatcher = nil
+        jobs.cancelAll()
     }
 
     /// Set up notification observers for menu commands
@@ -275,6 +712,143 @@ final class AppState: @unchecked Sendable {
             }
         })
 
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("AddClearanceModel"),
+            object: nil,
+            queue: .main
+        ) { [weak self] notification in
+            if let url = notification.object as? URL, let device = MTLCreateSystemDefaultDevice() {
+                self?.addClearanceModel(url: url, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("OpenComparisonModel"),
+            object: nil,
+            queue: .main
+        ) { [weak self] notification in
+            if let url = notification.object as? URL, let device = MTLCreateSystemDefaultDevice() {
+                self?.openComparison(url: url, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("ToggleComparisonRegistration"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let self = self, let device = MTLCreateSystemDefaultDevice() {
+                self.setRegisterComparison(!self.registerComparison, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("TogglePCBPreset"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let self = self, let device = MTLCreateSystemDefaultDevice() {
+                self.setPCBPreset(!self.pcbPreset, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("ToggleContourLines"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let self = self, let device = MTLCreateSystemDefaultDevice() {
+                self.setContourLines(!self.showContourLines, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("SetContourInterval"),
+            object: nil,
+            queue: .main
+        ) { [weak self] notification in
+            if let device = MTLCreateSystemDefaultDevice() {
+                self?.setContourInterval((notification.object as? NSNumber)?.doubleValue, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("ToggleDraftAnalysis"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let self = self, let device = MTLCreateSystemDefaultDevice() {
+                self.setDraftAnalysis(!self.showDraftAnalysis, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("SetDraftPullDirection"),
+            object: nil,
+            queue: .main
+        ) { [weak self] notification in
+            if let name = notification.object as? String,
+               let direction = DraftAnalysis.PullDirection(rawValue: name),
+               let device = MTLCreateSystemDefaultDevice() {
+                self?.setDraftPullDirection(direction, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("SetDraftMinimumAngle"),
+            object: nil,
+            queue: .main
+        ) { [weak self] notification in
+            if let angle = (notification.object as? NSNumber)?.doubleValue, let device = MTLCreateSystemDefaultDevice() {
+                self?.setDraftMinimumAngle(angle, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("TogglePrimitives"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let self = self, let device = MTLCreateSystemDefaultDevice() {
+                self.setPrimitives(!self.showPrimitives, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("ShowCaliper"),
+            object: nil,
+            queue: .main
+        ) { [weak self] notification in
+            guard let self, let device = MTLCreateSystemDefaultDevice() else { return }
+            if let name = notification.object as? String, let direction = Caliper.Direction(axis: name) {
+                self.showCaliper(direction, device: device)
+            } else {
+                self.showCaliperAlongSelectedFace(device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("ToggleHeightColors"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let self = self, let device = MTLCreateSystemDefaultDevice() {
+                self.setHeightColors(!self.showHeightColors, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("SetHeightColorAxis"),
+            object: nil,
+            queue: .main
+        ) { [weak self] notification in
+            if let name = notification.object as? String,
+               let axis = HeightColors.Axis(rawValue: name),
+               let device = MTLCreateSystemDefaultDevice() {
+                self?.setHeightColorAxis(axis, device: device)
+            }
+        })
+
         notificationObservers.append(NotificationCenter.default.addObserver(
             forName: NSNotification.Name("ToggleSlicing"),
             object: nil,
@@ -352,6 +926,14 @@ final class AppState: @unchecked Sendable {
             self?.copySelectedMeasurementsAsPolygon()
         })
 
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("CopyMeasurementsAsOpenSCADVariables"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            self?.copyMeasurementsAsOpenSCADVariables()
+        })
+
         notificationObservers.append(NotificationCenter.default.addObserver(
             forName: NSNotification.Name("CycleMaterial"),
             object: nil,
@@ -428,19 +1010,81 @@ final class AppState: @unchecked Sendable {
                 try? self?.undoLeveling(device: device)
             }
         })
-    }
-
-    /// Cycle to the next grid mode
-    func cycleGridMode() {
-        let allModes = GridMode.allCases
-        let currentIndex = allModes.firstIndex(of: gridMode) ?? 0
-        let nextIndex = (currentIndex + 1) % allModes.count
-        gridMode = allModes[nextIndex]
-        print(gridMode.description)
-    }
 
-    /// Cycle to the next wireframe mode
-    func cycleWireframeMode() {
+        // Scale notifications
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("ApplyScaleFix"),
+            object: nil,
+            queue: .main
+        ) { [weak self] notification in
+            if let name = notification.object as? String,
+               let fix = ScaleCheck.Fix(rawValue: name),
+               let device = MTLCreateSystemDefaultDevice() {
+                try? self?.applyScaleFix(fix, device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("ResetModelScale"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let device = MTLCreateSystemDefaultDevice() {
+                try? self?.resetModelScale(device: device)
+            }
+        })
+
+        // Alignment notifications
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("AlignPrincipalAxes"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let device = MTLCreateSystemDefaultDevice() {
+                try? self?.alignToPrincipalAxes(device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("StartLayFlatPick"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            self?.levelingState.startFacePick()
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("LayFlatOnSelection"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let device = MTLCreateSystemDefaultDevice() {
+                try? self?.layFlatOnSelectedTriangles(device: device)
+            }
+        })
+
+        notificationObservers.append(NotificationCenter.default.addObserver(
+            forName: NSNotification.Name("LayFlatOnLargestFace"),
+            object: nil,
+            queue: .main
+        ) { [weak self] _ in
+            if let device = MTLCreateSystemDefaultDevice() {
+                try? self?.layFlatOnLargestFace(device: device)
+            }
+        })
+    }
+
+    /// Cycle to the next grid mode
+    func cycleGridMode() {
+        let allModes = GridMode.allCases
+        let currentIndex = allModes.firstIndex(of: gridMode) ?? 0
+        let nextIndex = (currentIndex + 1) % allModes.count
+        gridMode = allModes[nextIndex]
+        print(gridMode.description)
+    }
+
+    /// Cycle to the next wireframe mode
+    func cycleWireframeMode() {
         let allModes = WireframeMode.allCases
         let currentIndex = allModes.firstIndex(of: wireframeMode) ?? 0
         let nextIndex = (currentIndex + 1) % allModes.count
@@ -474,8 +1118,8 @@ final class AppState: @unchecked Sendable {
             }
             let styledEdges = cachedStyledEdges!
 
-            if slicingState.isVisible {
-                wireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness, sliceBounds: slicingState.bounds)
+            if let sliceBounds = activeSliceBounds {
+                wireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness, sliceBounds: sliceBounds)
             } else {
                 wireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness)
             }
@@ -486,8 +1130,8 @@ final class AppState: @unchecked Sendable {
             }
             let edges = cachedEdges!
 
-            if slicingState.isVisible {
-                wireframeData = try WireframeData(device: device, edges: edges, thickness: thickness, sliceBounds: slicingState.bounds)
+            if let sliceBounds = activeSliceBounds {
+                wireframeData = try WireframeData(device: device, edges: edges, thickness: thickness, sliceBounds: sliceBounds)
             } else {
                 wireframeData = try WireframeData(device: device, edges: edges, thickness: thickness)
             }
@@ -504,7 +1148,20 @@ final class AppState: @unchecked Sendable {
     func updateGrid(device: MTLDevice) throws {
         guard let model = model else { return }
         let bbox = model.boundingBox()
-        self.gridData = try GridData(device: device, mode: gridMode, boundingBox: bbox)
+        self.gridData = try GridData(device: device, mode: gridMode, boundingBox: bbox, planeZ: pcbBoard?.bottomZ, coordinateOffset: coordinateOffset)
+
+        // Snap measurement points to the same spacing the grid displays, and off the model to its bottom plane
+        if let gridData = gridData {
+            let bounds = gridData.bounds
+            measurementSystem.gridSnapSpacing = Double(gridData.gridSpacing)
+            measurementSystem.gridSnapPlane = gridMode == .off ? nil : MeasurementSystem.GridPlane(
+                z: Double(bounds.bottomZ),
+                minX: Double(bounds.minX),
+                maxX: Double(bounds.maxX),
+                minY: Double(bounds.minY),
+                maxY: Double(bounds.maxY)
+            )
+        }
 
         // Generate text labels for grid
         if let gridData = gridData, gridMode != .off {
@@ -570,1127 +1227,3872 @@ final class AppState: @unchecked Sendable {
         }
     }
 
-    /// Update mesh data based on current slicing bounds (throttled during rapid updates)
-    /// When slicing is active, updates are throttled to ~30fps to keep UI responsive
-    func updateMeshData(device: MTLDevice) throws {
-        guard model != nil else { return }
+    /// Bounds the model is clipped to: the current print layer in layer preview, the slicing
+    /// bounds while slicing is shown, nil when the whole model is visible
+    var activeSliceBounds: [[Double]]? {
+        if layerPreview.isActive, let model {
+            return layerPreview.bounds(modelBounds: model.boundingBox())
+        }
+        return slicingState.isVisible ? slicingState.bounds : nil
+    }
 
-        // Throttle updates during slicing to maintain responsive UI
-        // Target: max 30 updates/sec during slider movement
-        let throttleInterval: CFAbsoluteTime = 0.033 // ~30fps
+    /// Start or stop the layer preview (replaces the slicing panel while active)
+    func toggleLayerPreview() {
+        if layerPreview.isActive {
+            layerPreview.deactivate()
+        } else if let model {
+            let bbox = model.boundingBox()
+            slicingState.isVisible = false
+            layerPreview.activate(minZ: bbox.min.z, maxZ: bbox.max.z)
+        }
+    }
 
-        let now = CFAbsoluteTimeGetCurrent()
-        let timeSinceLastUpdate = now - lastMeshUpdateTime
+    // MARK: - PCB Preset
 
-        if slicingState.isVisible && timeSinceLastUpdate < throttleInterval {
-            // Schedule a trailing update to ensure final position is rendered
-            pendingMeshUpdate?.cancel()
-            let workItem = DispatchWorkItem { [weak self] in
-                guard let self = self else { return }
-                try? self.updateMeshDataCore(device: device)
-            }
-            pendingMeshUpdate = workItem
-            let delay = throttleInterval - timeSinceLastUpdate
-            DispatchQueue.main.asyncAfter(deadline: .now() + delay, execute: workItem)
-            return
+    /// Turn the PCB viewing preset on or off and frame the model for the new view
+    func setPCBPreset(_ enabled: Bool, device: MTLDevice) {
+        guard enabled != pcbPreset else { return }
+        pcbPreset = enabled
+        applyPCBPreset(device: device)
+    }
+
+    /// Stretch the displayed model along Z around the board (measurements keep true dimensions)
+    func setPCBZExaggeration(_ factor: Float, device: MTLDevice) {
+        guard factor > 0, factor != pcbZExaggeration else { return }
+        pcbZExaggeration = factor
+        if pcbPreset {
+            applyPCBPreset(device: device)
         }
+    }
 
-        // Cancel any pending update since we're doing one now
-        pendingMeshUpdate?.cancel()
-        pendingMeshUpdate = nil
-        lastMeshUpdateTime = now
+    private func applyPCBPreset(device: MTLDevice) {
+        updatePCBBoard()
+        try? updateGrid(device: device)
+        if let model {
+            camera.frameBoundingBox(model.boundingBox())
+        }
+    }
 
-        try updateMeshDataCore(device: device)
+    /// Find the board in the current model and set up the camera's Z exaggeration around it
+    private func updatePCBBoard() {
+        pcbBoard = pcbPreset ? model.flatMap(PCBBoard.detect) : nil
+        camera.zExaggeration = pcbPreset ? pcbZExaggeration : 1
+        camera.exaggerationOrigin = Float(pcbBoard?.topZ ?? model?.boundingBox().min.z ?? 0)
     }
 
-    /// Core mesh update logic (called by throttled wrapper)
-    private func updateMeshDataCore(device: MTLDevice) throws {
-        guard let model else { return }
+    /// Add a measurement from the board surface to the top (or bottom) of a component and orbit around it
+    func measureComponentHeight(_ component: PCBBoard.Component) {
+        guard let board = pcbBoard else { return }
+        measurementSystem.add(board.heightMeasurement(for: component))
+        camera.animateTarget(to: component.extremePoint.float3)
+    }
 
-        lastMeshUpdateTime = CFAbsoluteTimeGetCurrent()
+    /// Add height measurements for the tallest component on each side of the board
+    func measureTallestComponents() {
+        guard let board = pcbBoard else { return }
+        for component in [board.tallestTopComponent, board.deepestBottomComponent].compactMap({ $0 }) {
+            measurementSystem.add(board.heightMeasurement(for: component))
+        }
+    }
 
-        // Calculate wireframe thickness based on model size
-        let bbox = model.boundingBox()
-        let modelSize = bbox.diagonal
-        let thickness = Float(modelSize) * 0.002
+    // MARK: - Clearance Check
+
+    /// Load a second model (e.g. a PCB into its enclosure) and check it against the main model
+    func addClearanceModel(url: URL, device: MTLDevice) {
+        clearanceSource = nil
+        clearanceModel = nil
+        clearanceResult = nil
+        clearanceRenderData = nil
+        clearanceError = nil
+        clearanceModelName = url.lastPathComponent
+
+        jobs.submit(.clearance, title: "Loading \(url.lastPathComponent)...") { job in
+            try ModelFileLoader.loadRendering(url: url, job: job)
+        } completion: { [weak self] result in
+            guard let self, self.clearanceModelName == url.lastPathComponent else { return }
+            switch result {
+            case .success(let other):
+                self.clearanceSource = other
+                self.updateClearance(device: device)
+            case .failure(let error):
+                guard !(error is CancellationError) else { return }
+                self.clearanceError = error.localizedDescription
+            }
+        }
+    }
 
-        // If slicing is active, use triangle slicer to clip geometry
-        if slicingState.isVisible {
-            let slicedResult = TriangleSlicer.sliceTriangles(model.triangles, bounds: slicingState.bounds)
+    /// Remove the second model and its results
+    func removeClearanceModel() {
+        jobs.cancel(.clearance)
+        clearanceSource = nil
+        clearanceModelName = nil
+        clearanceModel = nil
+        clearanceResult = nil
+        clearanceRenderData = nil
+        clearanceError = nil
+    }
 
-            // Only create mesh data if we have triangles
-            if !slicedResult.triangles.isEmpty {
-                let slicedModel = STLModel(triangles: slicedResult.triangles, name: model.name)
-                self.meshData = try MeshData(device: device, model: slicedModel)
+    /// Place the second model in render space and check it against the current main model
+    private func updateClearance(device: MTLDevice) {
+        guard let source = clearanceSource, let model else { return }
 
-                // Handle wireframe based on mode
-                if wireframeMode == .edge {
-                    // Edge mode with styled edges
-                    if cachedStyledEdges == nil {
-                        cachedStyledEdges = model.extractStyledEdges(angleThreshold: edgeAngleThreshold)
-                    }
-                    let styledEdges = cachedStyledEdges!
+        // Both files share the same coordinates, so the second model gets the main model's offset
+        let other = source.translated(by: -coordinateOffset)
+        clearanceModel = other
+        clearanceResult = nil
+        clearanceError = nil
+        do {
+            clearanceRenderData = try ClearanceRenderData(device: device, other: other)
+        } catch {
+            clearanceRenderData = nil
+        }
 
-                    // Immediately show unclipped wireframe (or keep current clipped one)
-                    if unclippedWireframeData == nil {
-                        unclippedWireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness)
-                    }
+        let triangles = model.triangles
+        let otherTriangles = other.triangles
+        let generation = modelGeneration
+        jobs.submit(.clearance, title: "Checking clearance...") { job in
+            try ClearanceCheck.check(model: triangles, other: otherTriangles, job: job)
+        } completion: { [weak self] result in
+            guard let self, generation == self.modelGeneration, self.clearanceModel != nil else { return }
+            switch result {
+            case .success(let check):
+                guard let check else {
+                    self.clearanceError = "The model or the clearance model is empty"
+                    return
+                }
+                self.clearanceResult = check
+                let highlights = check.intersectingTriangles.map { triangles[$0] }
+                    + check.otherIntersectingTriangles.map { otherTriangles[$0] }
+                self.clearanceRenderData?.updateHighlights(device: device, triangles: highlights)
+            case .failure(let error):
+                guard !(error is CancellationError) else { return }
+                self.clearanceError = error.localizedDescription
+            }
+        }
+    }
 
-                    // Use unclipped wireframe immediately for responsive UI
-                    if wireframeData == nil {
-                        wireframeData = unclippedWireframeData
-                    }
+    /// Add a distance measurement across the minimum clearance and orbit around it
+    func measureClearance() {
+        guard let result = clearanceResult else { return }
+        if let measurement = result.measurement() {
+            measurementSystem.add(measurement)
+        }
+        camera.animateTarget(to: result.location.float3)
+    }
 
-                    // Schedule debounced async wireframe clipping
-                    let bounds = slicingState.bounds
-                    scheduleWireframeUpdate(device: device, styledEdges: styledEdges, thickness: thickness, bounds: bounds)
-                } else if wireframeMode == .all {
-                    // All mode with plain edges
-                    if cachedEdges == nil {
-                        cachedEdges = model.extractEdges()
-                    }
-                    let edges = cachedEdges!
+    /// Orbit around the interpenetrating region (or the closest point)
+    func focusClearance() {
+        guard let result = clearanceResult, let model else { return }
+        let center = result.intersectionBounds(in: model)?.center ?? result.location
+        camera.animateTarget(to: center.float3)
+    }
 
-                    // Immediately show unclipped wireframe (or keep current clipped one)
-                    if unclippedWireframeData == nil {
-                        unclippedWireframeData = try WireframeData(device: device, edges: edges, thickness: thickness)
-                    }
+    /// Plugin overlay plus the minimum clearance line, drawn in the scene
+    var sceneOverlay: PluginAnalysisResult.Overlay? {
+        let pluginOverlay = pluginAnalysis?.result?.overlay
+        guard let result = clearanceResult, !result.isIntersecting else { return pluginOverlay }
 
-                    // Use unclipped wireframe immediately for responsive UI
-                    if wireframeData == nil {
-                        wireframeData = unclippedWireframeData
-                    }
+        var overlay = pluginOverlay ?? PluginAnalysisResult.Overlay()
+        overlay.points = (overlay.points ?? []) + [result.pointOnModel, result.pointOnOther]
+        if result.minimumDistance > 0 {
+            overlay.lines = (overlay.lines ?? []) + [PluginAnalysisResult.Line(from: result.pointOnModel, to: result.pointOnOther)]
+        }
+        return overlay
+    }
 
-                    // Schedule debounced async wireframe clipping
-                    let bounds = slicingState.bounds
-                    scheduleWireframeUpdate(device: device, edges: edges, thickness: thickness, bounds: bounds)
-                } else {
-                    self.wireframeData = nil
-                    self.unclippedWireframeData = nil
-                }
-            } else {
-                // No triangles in bounds - don't render mesh or wireframe
-                self.meshData = nil
-                self.wireframeData = nil
+    // MARK: - Side-by-Side Comparison
+
+    /// Show a second model (or another revision) next to the main model, seen through the same camera
+    func openComparison(url: URL, device: MTLDevice) {
+        resetComparison()
+        comparisonModelName = url.lastPathComponent
+
+        jobs.submit(.comparison, title: "Loading \(url.lastPathComponent)...") { job in
+            try ModelFileLoader.loadRendering(url: url, job: job)
+        } completion: { [weak self] result in
+            guard let self, self.comparisonModelName == url.lastPathComponent else { return }
+            switch result {
+            case .success(let other):
+                self.comparisonSource = other
+                self.updateComparison(device: device)
+            case .failure(let error):
+                guard !(error is CancellationError) else { return }
+                self.comparisonError = error.localizedDescription
             }
+        }
+    }
 
-            // Create cut edge visualization
-            if !slicedResult.cutEdges.isEmpty {
-                self.cutEdgeData = try CutEdgeData(device: device, cutEdges: slicedResult.cutEdges)
-            } else {
-                self.cutEdgeData = nil
-            }
+    /// Close the comparison pane
+    func closeComparison() {
+        jobs.cancel(.comparison)
+        resetComparison()
+        comparisonModelName = nil
+    }
 
-            // Create slice plane visualization
-            if slicingState.showPlanes && slicingState.activePlane != nil {
-                let planeSize = Float(bbox.diagonal * 1.5)
-                self.slicePlaneData = try SlicePlaneData(
-                    device: device,
-                    slicingState: slicingState,
-                    modelCenter: bbox.center,
-                    planeSize: planeSize
-                )
-            } else {
-                self.slicePlaneData = nil
-            }
-        } else {
-            // Show full model - no clipping needed, create wireframe directly
-            self.meshData = try MeshData(device: device, model: model)
+    /// Register the compared model onto the main model or show it in its own file coordinates
+    func setRegisterComparison(_ enabled: Bool, device: MTLDevice) {
+        guard enabled != registerComparison else { return }
+        registerComparison = enabled
+        updateComparison(device: device)
+    }
 
-            // Handle wireframe based on mode
-            if wireframeMode == .edge {
-                // Edge mode with styled edges
-                if cachedStyledEdges == nil {
-                    cachedStyledEdges = model.extractStyledEdges(angleThreshold: edgeAngleThreshold)
-                }
-                let styledEdges = cachedStyledEdges!
+    private func resetComparison() {
+        comparisonSource = nil
+        comparisonModel = nil
+        comparisonRegistration = nil
+        comparisonRenderData = nil
+        comparisonError = nil
+        comparisonCorrespondence = nil
+        comparisonAccelerator = nil
+    }
 
-                if unclippedWireframeData == nil {
-                    unclippedWireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness)
-                }
-                self.wireframeData = unclippedWireframeData
-            } else if wireframeMode == .all {
-                // All mode with plain edges
-                if cachedEdges == nil {
-                    cachedEdges = model.extractEdges()
-                }
-                let edges = cachedEdges!
+    /// Place the compared model in render space and match its triangles to the current main model
+    private func updateComparison(device: MTLDevice) {
+        guard let source = comparisonSource, let model else { return }
 
-                if unclippedWireframeData == nil {
-                    unclippedWireframeData = try WireframeData(device: device, edges: edges, thickness: thickness)
+        comparisonModel = nil
+        comparisonRegistration = nil
+        comparisonRenderData = nil
+        comparisonError = nil
+        comparisonCorrespondence = nil
+        comparisonAccelerator = nil
+
+        // Without registration both files share the same coordinates, like a clearance model
+        let placed = source.translated(by: -coordinateOffset)
+        let register = registerComparison
+        let triangles = model.triangles
+        let tolerance = ModelRegistration.defaultTolerance(for: model.boundingBox())
+        let generation = modelGeneration
+        jobs.submit(.comparison, title: register ? "Registering comparison..." : "Matching comparison...") { job in
+            let registration = register ? try ModelRegistration.register(placed.triangles, onto: triangles, job: job) : nil
+            let other = registration?.apply(to: placed) ?? placed
+            let correspondence = try ModelRegistration.correspondence(model: triangles, other: other.triangles, tolerance: tolerance, job: job)
+            return (registration, other, correspondence, SpatialAccelerator(triangles: other.triangles))
+        } completion: { [weak self] result in
+            guard let self, generation == self.modelGeneration, self.comparisonSource != nil else { return }
+            switch result {
+            case .success(let (registration, other, correspondence, accelerator)):
+                guard !other.triangles.isEmpty else {
+                    self.comparisonError = "The compared model is empty"
+                    return
                 }
-                self.wireframeData = unclippedWireframeData
-            } else {
-                self.wireframeData = nil
-                self.unclippedWireframeData = nil
+                do {
+                    self.comparisonRenderData = try ComparisonRenderData(device: device, model: other)
+                } catch {
+                    self.comparisonError = error.localizedDescription
+                    return
+                }
+                self.comparisonModel = other
+                self.comparisonRegistration = registration
+                self.comparisonCorrespondence = correspondence
+                self.comparisonAccelerator = accelerator
+            case .failure(let error):
+                guard !(error is CancellationError) else { return }
+                self.comparisonError = error.localizedDescription
             }
-
-            self.slicePlaneData = nil
-            self.cutEdgeData = nil
         }
     }
 
-    /// Schedule a debounced wireframe update (runs in background after brief delay)
-    private func scheduleWireframeUpdate(device: MTLDevice, edges: [Edge], thickness: Float, bounds: [[Double]]) {
-        // Cancel any existing background task
-        wireframeUpdateTask?.cancel()
+    /// Highlight the region of the compared model matching the main selection (called every comparison frame)
+    func updateComparisonHighlights(device: MTLDevice) {
+        guard let data = comparisonRenderData, let comparisonModel, let correspondence = comparisonCorrespondence else { return }
+        let selection = measurementSystem.selectedTriangles
+        guard selection != data.highlightedSelection else { return }
+        let matching = correspondence.otherTriangles(matching: selection)
+        data.updateHighlights(device: device, triangles: matching.sorted().map { comparisonModel.triangles[$0] }, selection: selection)
+    }
 
-        // Start background task with debounce built-in
-        wireframeUpdateTask = Task { @MainActor [weak self] in
-            // Short delay to debounce rapid updates
-            try? await Task.sleep(for: .milliseconds(16))
+    /// Toggle the main triangles matching the compared triangle hit by a ray (in render space)
+    func toggleComparisonSelection(ray: Ray) {
+        guard let accelerator = comparisonAccelerator, let correspondence = comparisonCorrespondence,
+              let hit = accelerator.raycast(ray: ray) else { return }
+        let matching = correspondence.mainTriangles(matching: hit.triangleIndex)
+        guard !matching.isEmpty else {
+            print("Comparison: No corresponding region on the main model (the surfaces differ here)")
+            return
+        }
+        if matching.isSubset(of: measurementSystem.selectedTriangles) {
+            measurementSystem.selectedTriangles.subtract(matching)
+        } else {
+            measurementSystem.selectedTriangles.formUnion(matching)
+        }
+    }
 
-            // Check if cancelled during sleep
-            if Task.isCancelled { return }
+    // MARK: - Contour Lines
 
-            // Create clipped wireframe (runs on main actor but WireframeData does its heavy lifting in parallel internally)
-            do {
-                let clippedWireframe = try WireframeData(device: device, edges: edges, thickness: thickness, sliceBounds: bounds)
+    /// Show or hide the iso-height contour lines
+    func setContourLines(_ enabled: Bool, device: MTLDevice) {
+        guard enabled != showContourLines else { return }
+        showContourLines = enabled
+        updateContours(device: device)
+    }
 
-                // Check if cancelled
-                if Task.isCancelled { return }
+    /// Change the contour spacing (nil: automatic)
+    func setContourInterval(_ interval: Double?, device: MTLDevice) {
+        contourIntervalSetting = interval
+        showContourLines = true
+        updateContours(device: device)
+    }
 
-                // Update directly (we're already on main actor)
-                self?.wireframeData = clippedWireframe
-            } catch {
-                print("ERROR: Background wireframe update failed: \(error)")
+    /// Recompute the contour lines for the current model and interval
+    private func updateContours(device: MTLDevice) {
+        guard showContourLines, let model else {
+            jobs.cancel(.contours)
+            contourData = nil
+            contourInterval = nil
+            contourLevels = nil
+            contourTruncated = false
+            return
+        }
+
+        let interval = contourIntervalSetting ?? ContourLines.autoInterval(for: model.boundingBox().size.z)
+        let triangles = model.triangles
+        let offset = coordinateOffset.z
+        let generation = modelGeneration
+        jobs.submit(.contours, title: "Contour lines...") { job in
+            try ContourLines.compute(triangles, interval: interval, offset: offset, job: job)
+        } completion: { [weak self] result in
+            guard let self, generation == self.modelGeneration, self.showContourLines else { return }
+            guard case .success(let contours) = result else { return }
+
+            self.contourInterval = contours.interval
+            self.contourLevels = contours.levels
+            self.contourTruncated = contours.isTruncated
+            guard let levels = contours.levels else {
+                self.contourData = nil
+                return
             }
+            let edges = contours.segments.map { CutEdge(start: $0.start, end: $0.end, axis: 2) }
+            let colors = contours.segments.map { ContourLines.color(for: $0.level, in: levels) }
+            self.contourData = try? CutEdgeData(device: device, cutEdges: edges, colors: colors)
         }
     }
 
-    /// Schedule a debounced wireframe update for styled edges (runs in background after brief delay)
-    private func scheduleWireframeUpdate(device: MTLDevice, styledEdges: [StyledEdge], thickness: Float, bounds: [[Double]]) {
-        // Cancel any existing background task
-        wireframeUpdateTask?.cancel()
+    // MARK: - Draft Analysis
 
-        // Start background task with debounce built-in
-        wireframeUpdateTask = Task { @MainActor [weak self] in
-            // Short delay to debounce rapid updates
-            try? await Task.sleep(for: .milliseconds(16))
+    /// Show or hide the draft angle coloring
+    func setDraftAnalysis(_ enabled: Bool, device: MTLDevice) {
+        guard enabled != showDraftAnalysis else { return }
+        showDraftAnalysis = enabled
+        updateDraftAnalysis(device: device)
+    }
 
-            // Check if cancelled during sleep
-            if Task.isCancelled { return }
+    /// Change the direction the mold is pulled off
+    func setDraftPullDirection(_ direction: DraftAnalysis.PullDirection, device: MTLDevice) {
+        draftPullDirection = direction
+        showDraftAnalysis = true
+        updateDraftAnalysis(device: device)
+    }
 
-            // Create clipped wireframe (runs on main actor but WireframeData does its heavy lifting in parallel internally)
-            do {
-                let clippedWireframe = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness, sliceBounds: bounds)
+    /// Change the minimum required draft in degrees
+    func setDraftMinimumAngle(_ angle: Double, device: MTLDevice) {
+        guard angle >= 0 else { return }
+        draftMinimumAngle = angle
+        showDraftAnalysis = true
+        updateDraftAnalysis(device: device)
+    }
 
-                // Check if cancelled
-                if Task.isCancelled { return }
+    /// Select the faces with less than the minimum draft (e.g. to inspect or measure them)
+    func selectInsufficientDraft() {
+        guard let result = draftResult else { return }
+        measurementSystem.selectedTriangles = Set(result.insufficientTriangles)
+        print("Draft: Selected \(result.insufficientTriangles.count) triangle(s) below \(result.minimumAngle)°")
+    }
 
-                // Update directly (we're already on main actor)
-                self?.wireframeData = clippedWireframe
-            } catch {
-                print("ERROR: Background wireframe update failed: \(error)")
+    /// Recompute the draft angles for the current model and settings
+    private func updateDraftAnalysis(device: MTLDevice) {
+        guard showDraftAnalysis, let model else {
+            jobs.cancel(.draft)
+            draftResult = nil
+            if draftShading != nil {
+                draftShading = nil
+                try? updateMeshData(device: device)
             }
+            return
+        }
+
+        // All replace the model's colors: only one at a time
+        stopHeightColors()
+        stopPrimitives()
+
+        draftResult = nil
+        let direction = draftPullDirection
+        let minimumAngle = draftMinimumAngle
+        let generation = modelGeneration
+        jobs.submit(.draft, title: "Draft analysis...") { job in
+            let result = try DraftAnalysis.analyze(model.triangles, pullDirection: direction, minimumAngle: minimumAngle, job: job)
+            return (result, DraftAnalysis.colors(for: result))
+        } completion: { [weak self] result in
+            guard let self, generation == self.modelGeneration, self.showDraftAnalysis else { return }
+            guard case .success(let (analysis, colors)) = result else { return }
+
+            self.draftResult = analysis
+            self.draftShading = (generation, colors)
+            try? self.updateMeshData(device: device)
         }
     }
 
-    /// Clear the current model (for empty files)
-    func clearModel() {
-        self.model = nil
-        self.spatialAccelerator = nil
-        self.isBuildingAccelerator = false
-        self.isBuildingWireframe = false
-        self.cachedEdges = nil
-        self.cachedFeatureEdges = nil
-        self.cachedStyledEdges = nil
-        self.meshData = nil
-        self.wireframeData = nil
-        self.slicePlaneData = nil
-        self.cutEdgeData = nil
-        self.gridData = nil
-        self.gridTextData = nil
-        self.measurementSystem.clearAll()
+    // MARK: - Primitive Surfaces
+
+    /// Show or hide the coloring by detected primitive surface
+    func setPrimitives(_ enabled: Bool, device: MTLDevice) {
+        guard enabled != showPrimitives else { return }
+        showPrimitives = enabled
+        updatePrimitives(device: device)
     }
 
-    /// Reset state for loading a new file (different from current file)
-    /// This clears all model-related state but preserves view settings like wireframe mode
-    /// - Parameter preserveSettings: If true, preserve wireframe mode, grid mode, build plate, etc.
-    func resetForNewFile(preserveSettings: Bool = false) {
-        // Stop existing file watcher
-        fileWatcher?.stop()
-        fileWatcher = nil
+    /// Select the triangles of a detected region (e.g. to measure or export it)
+    func selectPrimitiveRegion(_ index: Int) {
+        guard let result = primitiveResult, result.regions.indices.contains(index) else { return }
+        let region = result.regions[index]
+        measurementSystem.selectedTriangles = Set(region.triangles)
+        print("Primitives: Selected \(PrimitiveDetection.describe(region))")
+    }
 
-        // Clean up old temp file if exists
-        if let tempURL = tempSTLFileURL, (isOpenSCAD || isGo3mf) {
-            try? FileManager.default.removeItem(at: tempURL)
+    /// Detect the primitive surfaces of the current model
+    private func updatePrimitives(device: MTLDevice) {
+        guard showPrimitives, let model = originalModel else {
+            jobs.cancel(.primitives)
+            primitiveResult = nil
+            if primitiveShading != nil {
+                primitiveShading = nil
+                try? updateMeshData(device: device)
+            }
+            return
         }
 
-        // Clear model-related state
-        model = nil
-        spatialAccelerator = nil
-        isBuildingAccelerator = false
-        isBuildingWireframe = false
-        cachedEdges = nil
-        cachedFeatureEdges = nil
-        cachedStyledEdges = nil
-        unclippedWireframeData = nil
+        if showDraftAnalysis {
+            setDraftAnalysis(false, device: device)
+        }
+        stopHeightColors()
+
+        primitiveResult = nil
+        let generation = modelGeneration
+        jobs.submit(.primitives, title: "Detecting primitive surfaces...") { job in
+            let result = try PrimitiveDetection.detect(model.triangles, job: job)
+            return (result, PrimitiveDetection.colors(for: result))
+        } completion: { [weak self] result in
+            guard let self, generation == self.modelGeneration, self.showPrimitives else { return }
+            guard case .success(let (detection, colors)) = result else { return }
+
+            self.primitiveResult = detection
+            self.primitiveShading = (generation, colors)
+            try? self.updateMeshData(device: device)
+        }
+    }
 
-        // Clear GPU data
-        meshData = nil
-        wireframeData = nil
-        slicePlaneData = nil
-        cutEdgeData = nil
-        gridData = nil
-        gridTextData = nil
-        selectedTrianglesData = nil
+    /// Turn the primitive colors off without rebuilding the mesh (the caller recolors it)
+    private func stopPrimitives() {
+        jobs.cancel(.primitives)
+        showPrimitives = false
+        primitiveResult = nil
+        primitiveShading = nil
+    }
 
-        // Clear file references
-        sourceFileURL = nil
-        tempSTLFileURL = nil
-        isOpenSCAD = false
-        isGo3mf = false
-        savedFileURL = nil
-        isModelModified = false
+    // MARK: - Mesh Quality
 
-        // Clear 3MF plate data
-        threeMFParseResult = nil
-        selectedPlateId = nil
+    /// Check the current model for mesh defects in the background
+    private func updateMeshQuality() {
+        // A streamed model's proxy is decimated; its defects say nothing about the file
+        guard AppSettings.shared.checkMeshQuality, !isMeshQualityDismissed, streamingModel == nil, let model else {
+            jobs.cancel(.meshQuality)
+            meshQuality = nil
+            return
+        }
 
-        // Clear model info
-        modelInfo = nil
-        isEmptyFile = false
+        meshQuality = nil
+        let generation = modelGeneration
+        jobs.submit(.meshQuality, title: "Checking mesh quality...") { job in
+            try MeshQuality.check(model, job: job)
+        } completion: { [weak self] result in
+            guard let self, generation == self.modelGeneration, !self.isMeshQualityDismissed else { return }
+            guard case .success(let quality) = result else { return }
+            self.meshQuality = quality
+            if !quality.isClean {
+                print("Mesh quality: \(quality.summary)")
+            }
+        }
+    }
 
-        // Clear loading/error state
-        isLoading = false
-        loadError = nil
-        loadErrorID = nil
+    /// Hide the mesh quality banner for this file
+    func dismissMeshQuality() {
+        jobs.cancel(.meshQuality)
+        meshQuality = nil
+        isMeshQualityDismissed = true
+    }
 
-        // Reset slicing state
-        slicingState.fullReset()
+    /// Select the triangles affected by a mesh issue
+    func selectMeshIssue(_ issue: MeshQuality.Issue) {
+        guard let quality = meshQuality else { return }
+        let triangles = quality.triangles(of: issue)
+        guard !triangles.isEmpty else { return }
+        measurementSystem.selectedTriangles = Set(triangles)
+        print("Mesh quality: Selected \(triangles.count) triangle(s) with \(issue.title.lowercased())")
+    }
 
-        // Clear measurements
-        measurementSystem.clearAll()
+    /// Weld near-duplicate vertices, remove degenerate triangles and turn flipped ones around
+    /// (undo with Undo Leveling, save the model to keep the repair)
+    func repairMesh(device: MTLDevice) throws {
+        guard let model else { return }
+        let repaired = MeshQuality.repaired(model)
 
-        // Reset leveling state
-        levelingState.fullReset()
+        levelingState.storeForUndo(model.triangles)
+        // Triangle indices change when degenerate triangles are removed
+        measurementSystem.clearTriangleSelection()
+        try replaceModel(with: repaired, device: device)
+        isModelModified = true
+        print("Mesh quality: Repaired mesh (\(model.triangles.count - repaired.triangles.count) triangle(s) removed)")
+        updateMeshQuality()
+    }
 
-        // Optionally reset view settings
-        if !preserveSettings {
-            // Reset to default view settings for a fresh file
-            // Keep these as they are - user preferences
-        }
+    // MARK: - Caliper
+
+    /// Show the caliper across a direction, opened around the whole model
+    func showCaliper(_ direction: Caliper.Direction, device: MTLDevice) {
+        guard let model, let opened = Caliper(direction: direction, triangles: model.triangles) else { return }
+        caliper = opened
+        updateCaliper(device: device)
     }
 
-    /// Load an STL model and create mesh data for rendering
-    /// - Parameters:
-    ///   - model: The STL model to load
-    ///   - device: Metal device for GPU resources
-    ///   - preserveCamera: If true, preserve current camera position (for reloads)
-    func loadModel(_ model: STLModel, device: MTLDevice, preserveCamera: Bool = false) throws {
-        let loadStart = CFAbsoluteTimeGetCurrent()
+    /// Show the caliper across the plane fitted through the selected triangles
+    func showCaliperAlongSelectedFace(device: MTLDevice) {
+        guard let model else { return }
+        let selected = measurementSystem.selectedTriangles.sorted().compactMap { model.triangles.indices.contains($0) ? model.triangles[$0] : nil }
+        guard let normal = ModelAlignment.fittedPlaneNormal(of: selected) else { return }
+        showCaliper(.normal(normal), device: device)
+    }
 
-        self.model = model
-        self.cachedEdges = nil  // Clear edge cache for new model
-        self.cachedFeatureEdges = nil  // Clear feature edge cache for new model
-        self.cachedStyledEdges = nil  // Clear styled edge cache for new model
-        self.unclippedWireframeData = nil  // Clear cached wireframe for new model
-        self.spatialAccelerator = nil  // Clear while rebuilding
-        self.isBuildingAccelerator = true
+    /// Move the open jaws (positions along the caliper direction, render space)
+    func setCaliperOpening(_ opening: ClosedRange<Double>, device: MTLDevice) {
+        guard caliper != nil else { return }
+        caliper?.opening = opening
+        updateCaliper(device: device)
+    }
 
-        // Build spatial acceleration structure asynchronously for fast ray casting
-        // This allows the model to render immediately while acceleration builds in background
-        let triangles = model.triangles
-        DispatchQueue.global(qos: .userInitiated).async { [weak self] in
-            let accelerator = SpatialAccelerator(triangles: triangles)
-            DispatchQueue.main.async {
-                self?.spatialAccelerator = accelerator
-                self?.isBuildingAccelerator = false
-            }
+    func hideCaliper() {
+        caliper = nil
+        caliperReading = nil
+        caliperData = nil
+    }
+
+    /// Keep the current caliper reading as a distance measurement
+    func addCaliperMeasurement() {
+        guard let caliper, let caliperReading else { return }
+        measurementSystem.add(Caliper.measurement(for: caliperReading, along: caliper.direction.vector))
+        print("Caliper: Added \(displayPrecision.lengthWithUnit(caliperReading.distance)) across \(caliper.direction.displayName)")
+    }
+
+    /// Seat the jaws on the current model and rebuild their geometry
+    private func updateCaliper(device: MTLDevice) {
+        guard var current = caliper, let model else {
+            hideCaliper()
+            return
         }
 
-        // Calculate bounding box and thickness for wireframe
-        var t0 = CFAbsoluteTimeGetCurrent()
+        // A changed model may lie outside the old jaws: open them around it again
+        guard let extent = Caliper.extent(of: model.triangles, along: current.direction.vector) else {
+            hideCaliper()
+            return
+        }
+        if !current.opening.overlaps(extent) {
+            current.opening = extent
+        }
+        current.extent = extent
+        caliper = current
+
+        caliperReading = current.reading(of: model.triangles)
         let bbox = model.boundingBox()
-        print("  boundingBox: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+        caliperData = try? CaliperData(
+            device: device,
+            caliper: current,
+            reading: caliperReading,
+            modelCenter: bbox.center,
+            planeSize: Float(bbox.diagonal * 1.2)
+        )
+    }
 
-        let modelSize = bbox.diagonal
-        let thickness = Float(modelSize) * 0.002 // 0.2% of model size
+    // MARK: - Thickness Probe
 
-        // Show mesh immediately without wireframe
-        self.wireframeData = nil
-        t0 = CFAbsoluteTimeGetCurrent()
-        self.meshData = try MeshData(device: device, model: model)
-        print("  MeshData: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
-        print("  Total loadModel setup: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - loadStart) * 1000))ms")
+    /// Turn the thickness probe mode on or off
+    func setThicknessProbe(_ enabled: Bool) {
+        guard enabled != isProbingThickness else { return }
+        if enabled {
+            // Clicks probe instead of collecting points
+            measurementSystem.cancelMeasurement()
+            referenceGeometry.cancelTool()
+            levelingState.reset()
+            setFaceDistanceTool(false)
+            endTemplateRun()
+        }
+        isProbingThickness = enabled
+        thicknessProbe = nil
+    }
 
-        // Build wireframe asynchronously for large models
-        if model.triangles.count > 10000 && wireframeMode != .off {
-            isBuildingWireframe = true
-            let triangles = model.triangles
-            let currentWireframeMode = wireframeMode
-            let currentEdgeAngleThreshold = edgeAngleThreshold
-            let currentSlicingState = slicingState
+    /// Probe the thickness at the surface point under a view ray; each reading is kept as a measurement
+    func probeThickness(ray: Ray) {
+        guard let model else { return }
+        guard let result = ThicknessProbe.probe(ray: ray, triangles: model.triangles, accelerator: spatialAccelerator) else {
+            thicknessProbe = nil
+            print("Thickness: No opposite wall found (open mesh or inverted normals)")
+            return
+        }
+        thicknessProbe = result
+        measurementSystem.add(ThicknessProbe.measurement(for: result))
+        print("Thickness: \(displayPrecision.lengthWithUnit(result.thickness)) at \(result.entry + coordinateOffset)")
+    }
 
-            DispatchQueue.global(qos: .userInitiated).async { [weak self] in
-                // Extract edges in background
-                let styledEdges: [StyledEdge]?
-                let edges: [Edge]?
+    // MARK: - Face Distance
 
-                if currentWireframeMode == .edge {
-                    styledEdges = STLModel(triangles: triangles).extractStyledEdges(angleThreshold: currentEdgeAngleThreshold)
-                    edges = nil
-                } else {
-                    edges = STLModel(triangles: triangles).extractEdges()
-                    styledEdges = nil
-                }
+    /// Turn the face distance tool on or off
+    func setFaceDistanceTool(_ enabled: Bool) {
+        guard enabled != isMeasuringFaceDistance else { return }
+        if enabled {
+            // Clicks pick faces instead of collecting points
+            measurementSystem.cancelMeasurement()
+            referenceGeometry.cancelTool()
+            levelingState.reset()
+            setThicknessProbe(false)
+            endTemplateRun()
+        }
+        isMeasuringFaceDistance = enabled
+        faceDistance = nil
+    }
 
-                DispatchQueue.main.async {
-                    guard let self = self else { return }
+    /// Measure from the flat face under a view ray to the parallel face opposite it; each result is kept as a measurement
+    func measureFaceDistance(ray: Ray) {
+        guard let model else { return }
+        guard let result = FaceDistance.measure(
+            ray: ray,
+            triangles: model.triangles,
+            adjacency: currentTriangleAdjacency(of: model),
+            side: faceDistanceSide,
+            accelerator: spatialAccelerator
+        ) else {
+            faceDistance = nil
+            print("Face distance: No parallel face found opposite the picked face")
+            return
+        }
+        faceDistance = result
+        measurementSystem.add(FaceDistance.measurement(for: result))
+        print("Face distance: \(displayPrecision.lengthWithUnit(result.distance)) \(result.isThickness ? "thick" : "gap") at \(result.entry + coordinateOffset)")
+    }
 
-                    // Cache the extracted edges
-                    if let styledEdges = styledEdges {
-                        self.cachedStyledEdges = styledEdges
-                    }
-                    if let edges = edges {
-                        self.cachedEdges = edges
-                    }
+    // MARK: - Batch Templates
 
-                    // Create wireframe data
-                    do {
-                        if currentWireframeMode == .edge, let styledEdges = styledEdges {
-                            if currentSlicingState.isVisible {
-                                self.wireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness, sliceBounds: currentSlicingState.bounds)
-                            } else {
-                                self.wireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness)
-                            }
-                        } else if let edges = edges {
-                            if currentSlicingState.isVisible {
-                                self.wireframeData = try WireframeData(device: device, edges: edges, thickness: thickness, sliceBounds: currentSlicingState.bounds)
-                            } else {
-                                self.wireframeData = try WireframeData(device: device, edges: edges, thickness: thickness)
-                            }
-                        }
-                        self.unclippedWireframeData = self.wireframeData
-                    } catch {
-                        print("ERROR: Failed to create wireframe data: \(error)")
-                    }
+    /// Save a template, replacing the one it was edited from; names must be unique
+    func saveTemplate(_ template: MeasurementTemplate) throws {
+        let settings = AppSettings.shared
+        settings.measurementTemplates = try template.saved(into: settings.measurementTemplates)
+        print("Saved template '\(template.name)'")
+    }
 
-                    self.isBuildingWireframe = false
-                }
-            }
-        } else {
-            // For small models, build wireframe synchronously
-            try updateWireframe(device: device)
-        }
+    func removeTemplate(_ id: UUID) {
+        AppSettings.shared.measurementTemplates.removeAll { $0.id == id }
+    }
 
-        // Reinitialize measurement data with appropriate thickness for this model
-        t0 = CFAbsoluteTimeGetCurrent()
-        initializeMeasurements(device: device, thickness: thickness)
-        print("  initializeMeasurements: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+    /// Start applying a template; the results of an earlier batch are dropped
+    func startTemplateRun(_ template: MeasurementTemplate) {
+        // Clicks pick template features instead of collecting points
+        measurementSystem.cancelMeasurement()
+        referenceGeometry.cancelTool()
+        levelingState.reset()
+        setThicknessProbe(false)
+        setFaceDistanceTool(false)
+        templateRun = TemplateRun(template: template)
+        print("Batch: \(template.name), \(templateRun!.prompt)")
+    }
 
-        // Initialize grid based on model bounds
-        t0 = CFAbsoluteTimeGetCurrent()
-        try updateGrid(device: device)
-        print("  updateGrid: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+    func endTemplateRun() {
+        templateRun = nil
+    }
 
-        // Update build plate if one is selected
-        t0 = CFAbsoluteTimeGetCurrent()
-        if buildPlate != .off {
-            updateBuildPlate(device: device)
+    /// Pick the next point of the current template feature; the part is recorded after its last feature
+    func addTemplatePick(_ point: MeasurementPoint) {
+        guard templateRun != nil else { return }
+        if templateRun!.addPoint(point) {
+            completeTemplatePart()
         }
-        print("  updateBuildPlate: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+    }
 
-        // Frame the model in view (only for initial load, not reloads)
-        if !preserveCamera {
-            camera.frameBoundingBox(bbox)
+    /// Leave the current template feature out of this part
+    func skipTemplateFeature() {
+        guard templateRun != nil else { return }
+        if templateRun!.skipFeature() {
+            completeTemplatePart()
         }
+    }
 
-        // Handle slicing bounds based on whether this is a reload or new file
-        if preserveCamera {
-            // Reload - preserve slice positions, just update model bounds
-            slicingState.updateModelBounds(from: bbox)
-        } else {
-            // New file - initialize to full model bounds
-            slicingState.initializeBounds(from: bbox)
-        }
+    /// Drop the picks of the current part and start again with its first feature
+    func restartTemplatePart() {
+        templateRun?.restartPart()
+    }
 
-        // Handle measurements based on whether this is a reload or new file
-        if preserveCamera {
-            // Reload - validate existing measurements against new model
-            measurementSystem.validateMeasurements(model: model, accelerator: spatialAccelerator)
-        } else {
-            // New file - clear all measurements
-            measurementSystem.clearAll()
+    /// Record the current part under the file name and show its distances as measurements
+    private func completeTemplatePart() {
+        guard var run = templateRun else { return }
+        let lines = run.template.dimensions.filter { $0.quantity == .distance }.compactMap { dimension -> Measurement? in
+            guard let line = run.line(for: dimension), let distance = line.distance else { return nil }
+            var measurement = Measurement(
+                type: .distance,
+                points: [MeasurementPoint(position: line.from, normal: .zero, isAirPoint: true), MeasurementPoint(position: line.to, normal: .zero, isAirPoint: true)],
+                value: distance
+            )
+            measurement.note = dimension.name
+            return measurement
         }
+        let result = run.completePart(name: modelInfo?.fileName ?? "Part \(run.parts.count + 1)")
+        templateRun = run
+        lines.forEach(measurementSystem.add)
+        print("Batch: \(result.name) \(result.passed ? "passed" : "failed") (\(run.parts.count) part(s))")
+    }
 
-        // Clear loading state
-        isLoading = false
+    /// Combined results table of the batch
+    func templateResultsCSV() -> String? {
+        templateRun.map { $0.csv(format: AppSettings.shared.csvFormat) }
     }
 
-    /// Load a file from URL (supports both .stl and .scad files)
-    /// - Parameters:
-    ///   - url: The file URL to load
-    ///   - device: Metal device for GPU resources
-    ///   - isSameFile: If true, preserve settings like material (for reloads)
-    func loadFile(_ url: URL, device: MTLDevice, isSameFile: Bool = false) throws {
-        // Check if this is the same file (for determining whether to preserve settings)
-        let shouldPreserveSettings = isSameFile || (sourceFileURL == url)
+    func exportTemplateResults(to url: URL) throws {
+        guard let csv = templateResultsCSV() else { return }
+        try csv.write(to: url, atomically: true, encoding: .utf8)
+        print("Exported batch results to: \(url.path)")
+    }
 
-        // Reset state for the new file (cleans up watchers, temp files, etc.)
-        if !shouldPreserveSettings {
-            resetForNewFile(preserveSettings: false)
-        } else {
-            // Just stop watcher and clean temp file, but preserve settings
-            fileWatcher?.stop()
-            fileWatcher = nil
-            if let tempURL = tempSTLFileURL, (isOpenSCAD || isGo3mf), tempURL != url {
-                try? FileManager.default.removeItem(at: tempURL)
+    // MARK: - Calibration
+
+    /// Measure the loaded scan against the selected calibration artifact
+    func measureCalibration() {
+        guard let model else { return }
+        calibrationReport = CalibrationCheck.measure(triangles: model.triangles, artifact: calibrationArtifact)
+        if let report = calibrationReport {
+            print(report.text)
+        }
+    }
+
+    /// Show or hide the calibration panel (hiding drops the report)
+    func setCalibration(_ enabled: Bool) {
+        showCalibration = enabled
+        calibrationReport = nil
+    }
+
+    // MARK: - Height Colors
+
+    /// Show or hide the color-by-height rendering
+    func setHeightColors(_ enabled: Bool, device: MTLDevice) {
+        guard enabled != showHeightColors else { return }
+        showHeightColors = enabled
+        updateHeightColors(device: device)
+    }
+
+    /// Change the coordinate mapped to the gradient
+    func setHeightColorAxis(_ axis: HeightColors.Axis, device: MTLDevice) {
+        heightColorAxis = axis
+        showHeightColors = true
+        updateHeightColors(device: device)
+    }
+
+    /// Recolor the faces for the current model and axis
+    private func updateHeightColors(device: MTLDevice) {
+        guard showHeightColors, let model else {
+            jobs.cancel(.heightColors)
+            heightColorRange = nil
+            if heightShading != nil {
+                heightShading = nil
+                try? updateMeshData(device: device)
             }
+            return
         }
 
-        let fileExtension = url.pathExtension.lowercased()
+        if showDraftAnalysis {
+            setDraftAnalysis(false, device: device)
+        }
+        stopPrimitives()
+
+        heightColorRange = nil
+        let axis = heightColorAxis
+        let offset = coordinateOffset.value[axis.index]
+        let generation = modelGeneration
+        jobs.submit(.heightColors, title: "Coloring by height...") { job in
+            let range = HeightColors.range(of: model.triangles, axis: axis)
+            return (range, try range.map { try HeightColors.colors(of: model.triangles, axis: axis, range: $0, job: job) })
+        } completion: { [weak self] result in
+            guard let self, generation == self.modelGeneration, self.showHeightColors, axis == self.heightColorAxis else { return }
+            guard case .success(let (range, colors)) = result, let range, let colors else { return }
+
+            self.heightColorRange = (range.lowerBound + offset)...(range.upperBound + offset)
+            self.heightShading = (generation, colors)
+            try? self.updateMeshData(device: device)
+        }
+    }
 
-        if fileExtension == "scad" {
-            // OpenSCAD file - render with color extraction
-            print("Rendering OpenSCAD file: \(url.lastPathComponent)")
+    /// Turn the height colors off without rebuilding the mesh (the caller recolors it)
+    private func stopHeightColors() {
+        jobs.cancel(.heightColors)
+        showHeightColors = false
+        heightColorRange = nil
+        heightShading = nil
+    }
 
-            let workDir = url.deletingLastPathComponent()
-            let renderer = OpenSCADRenderer(workDir: workDir)
+    // MARK: - Cutaway
+
+    /// Farthest useful cutaway depth: beyond the far side of the scene
+    var maxCutawayDepth: Double {
+        camera.distance + (sceneBounds?.diagonal ?? 0)
+    }
+
+    /// Turn the camera cutaway on (cutting through the orbit target) or off
+    /// Turning it on shows the clipping planes panel, which holds the depth control
+    func toggleCutaway() {
+        if camera.cutawayDepth == nil {
+            camera.cutawayDepth = Float(camera.distance)
+            showClippingPanel = true
+        } else {
+            camera.cutawayDepth = nil
+        }
+    }
+
+    /// Move the cutaway plane nearer to (negative) or away from the camera by a fraction of the scene size
+    func adjustCutaway(by fraction: Double) {
+        guard let depth = camera.cutawayDepth else { return }
+        let step = Float((sceneBounds?.diagonal ?? camera.distance) * fraction)
+        camera.cutawayDepth = min(max(depth + step, 0), Float(maxCutawayDepth))
+    }
+
+    // MARK: - Outline
+
+    /// Edge adjacency of the current model for outline rendering
+    private func currentOutlineEdges() -> OutlineEdges? {
+        guard let model else { return nil }
+        if let cached = outlineEdges, cached.generation == modelGeneration {
+            return cached.edges
+        }
+        let edges = OutlineEdges(model: model, featureAngle: edgeAngleThreshold)
+        outlineEdges = (modelGeneration, edges)
+        outlineEye = nil
+        return edges
+    }
+
+    /// Rebuild the outline edges if the camera or model changed since the last frame
+    func updateOutlineWireframe(device: MTLDevice) {
+        guard outlineMode, let model, let edges = currentOutlineEdges() else {
+            outlineWireframeData = nil
+            outlineEye = nil
+            return
+        }
+
+        let position = camera.position
+        let eye = Vector3(Double(position.x), Double(position.y), Double(position.z))
+        if let outlineEye, outlineEye == eye, outlineWireframeData != nil {
+            return
+        }
+        outlineEye = eye
+
+        let thickness = Float(model.boundingBox().diagonal) * 0.002
+        let styledEdges = edges.visibleEdges(from: eye).map { StyledEdge(edge: $0, isFeatureEdge: true) }
+        // WireframeData throws for an empty edge list (e.g. a single flat face seen edge-on)
+        outlineWireframeData = try? WireframeData(device: device, styledEdges: styledEdges, thickness: thickness, sliceBounds: activeSliceBounds)
+    }
+
+    // MARK: - Hover Highlight
+
+    /// Grow the flat face or smooth region under the cursor (unchanged while the cursor stays on it)
+    func updateHoveredRegion(ray: Ray) {
+        guard let kind = hoverHighlight, let model,
+              let index = measurementSystem.findTriangleAtRay(ray: ray, model: model, accelerator: spatialAccelerator) else {
+            clearHoveredRegion()
+            return
+        }
+        if let hoveredRegion, hoveredRegion.kind == kind, hoveredRegionGeneration == modelGeneration, hoveredRegion.contains(index) {
+            return
+        }
+
+        hoveredRegion = SurfaceRegion.grow(from: index, kind: kind, in: model.triangles, adjacency: currentTriangleAdjacency(of: model))
+        hoveredRegionGeneration = modelGeneration
+    }
+
+    private func currentTriangleAdjacency(of model: STLModel) -> TriangleAdjacency {
+        if let cached = triangleAdjacency, cached.generation == modelGeneration {
+            return cached.adjacency
+        }
+        let adjacency = TriangleAdjacency(triangles: model.triangles)
+        triangleAdjacency = (modelGeneration, adjacency)
+        return adjacency
+    }
+
+    /// Drop the hover highlight when the cursor leaves the view
+    func clearHoveredRegion() {
+        if hoveredRegion != nil {
+            hoveredRegion = nil
+        }
+    }
+
+    /// Rebuild the highlight triangles if the hovered region changed since the last frame
+    func updateHoveredRegionHighlight(device: MTLDevice) {
+        if hoveredRegion != nil && hoveredRegionGeneration != modelGeneration {
+            hoveredRegion = nil
+        }
+        guard hoveredRegionRevision != hoveredRegionBufferRevision else { return }
+        hoveredRegionBufferRevision = hoveredRegionRevision
+
+        guard let region = hoveredRegion, let model else {
+            hoveredRegionBuffer = nil
+            hoveredRegionVertexCount = 0
+            return
+        }
+        let color = Self.hoveredRegionColor
+        let vertices = region.triangles.flatMap { index -> [VertexIn] in
+            let triangle = model.triangles[index]
+            let normal = triangle.normal.float3
+            return [
+                VertexIn(position: triangle.v1.float3, normal: normal, color: color),
+                VertexIn(position: triangle.v2.float3, normal: normal, color: color),
+                VertexIn(position: triangle.v3.float3, normal: normal, color: color)
+            ]
+        }
+        hoveredRegionBuffer = device.makeBuffer(bytes: vertices, length: vertices.count * MemoryLayout<VertexIn>.stride, options: [])
+        hoveredRegionVertexCount = hoveredRegionBuffer == nil ? 0 : vertices.count
+    }
+
+    /// Export the current outline view as an SVG line drawing with hidden lines removed
+    func exportOutlineSVG(to url: URL) throws {
+        guard let edges = currentOutlineEdges() else { return }
+
+        let size = viewSize.width > 0 && viewSize.height > 0 ? viewSize : CGSize(width: 1024, height: 768)
+        let position = camera.position
+        let eye = Vector3(Double(position.x), Double(position.y), Double(position.z))
+        let segments = OutlineEdges.project(
+            edges.visibleEdges(from: eye),
+            camera: camera,
+            viewSize: size,
+            accelerator: spatialAccelerator
+        )
+        try SVGExporter.write(segments: segments, size: size, to: url, title: modelInfo?.fileName)
+        print("Exported outline with \(segments.count) line(s) to: \(url.path)")
+    }
+
+    /// Update mesh data based on current slicing bounds (throttled during rapid updates)
+    /// When slicing is active, updates are throttled to ~30fps to keep UI responsive
+    func updateMeshData(device: MTLDevice) throws {
+        guard model != nil else { return }
+
+        // Throttle updates during slicing to maintain responsive UI
+        // Target: max 30 updates/sec during slider movement
+        let throttleInterval: CFAbsoluteTime = 0.033 // ~30fps
+
+        let now = CFAbsoluteTimeGetCurrent()
+        let timeSinceLastUpdate = now - lastMeshUpdateTime
+
+        if activeSliceBounds != nil && timeSinceLastUpdate < throttleInterval {
+            // Schedule a trailing update to ensure final position is rendered
+            pendingMeshUpdate?.cancel()
+            let workItem = DispatchWorkItem { [weak self] in
+                guard let self = self else { return }
+                try? self.updateMeshDataCore(device: device)
+            }
+            pendingMeshUpdate = workItem
+            let delay = throttleInterval - timeSinceLastUpdate
+            DispatchQueue.main.asyncAfter(deadline: .now() + delay, execute: workItem)
+            return
+        }
+
+        // Cancel any pending update since we're doing one now
+        pendingMeshUpdate?.cancel()
+        pendingMeshUpdate = nil
+        lastMeshUpdateTime = now
+
+        try updateMeshDataCore(device: device)
+    }
+
+    /// Core mesh update logic (called by throttled wrapper)
+    private func updateMeshDataCore(device: MTLDevice) throws {
+        guard let model else { return }
+
+        lastMeshUpdateTime = CFAbsoluteTimeGetCurrent()
+
+        // Draft analysis, primitives and height colors replace the model's own colors
+        let colors: VertexColors?
+        if let draftShading, draftShading.generation == modelGeneration {
+            colors = draftShading.colors
+        } else if let primitiveShading, primitiveShading.generation == modelGeneration {
+            colors = primitiveShading.colors
+        } else if let heightShading, heightShading.generation == modelGeneration {
+            colors = heightShading.colors
+        } else {
+            colors = nil
+        }
+
+        // Calculate wireframe thickness based on model size
+        let bbox = model.boundingBox()
+        let modelSize = bbox.diagonal
+        let thickness = Float(modelSize) * 0.002
+
+        if layerPreview.isActive {
+            layerPreview.updateRange(minZ: bbox.min.z, maxZ: bbox.max.z)
+        }
+
+        // If slicing or the layer preview is active, use triangle slicer to clip geometry
+        if let sliceBounds = activeSliceBounds {
+            let clipStart = CFAbsoluteTimeGetCurrent()
+            let slicedResult = TriangleSlicer.sliceTriangles(colors?.faceColored(model.triangles) ?? model.triangles, bounds: sliceBounds)
+            var triangles = slicedResult.triangles
+            // Cap the cut so the model reads as solid (always on for layers, like a slicer preview)
+            if slicingState.fillCrossSections || layerPreview.isActive {
+                let modelBounds = [[bbox.min.x, bbox.max.x], [bbox.min.y, bbox.max.y], [bbox.min.z, bbox.max.z]]
+                triangles += CrossSection.fills(model.triangles, bounds: sliceBounds, modelBounds: modelBounds)
+            }
+            performanceStats.recordClipping(CFAbsoluteTimeGetCurrent() - clipStart)
+
+            // Only create mesh data if we have triangles
+            if !triangles.isEmpty {
+                let slicedModel = STLModel(triangles: triangles, name: model.name)
+                self.meshData = try MeshData(device: device, model: slicedModel)
+
+                // Handle wireframe based on mode
+                if wireframeMode == .edge {
+                    // Edge mode with styled edges
+                    if cachedStyledEdges == nil {
+                        cachedStyledEdges = model.extractStyledEdges(angleThreshold: edgeAngleThreshold)
+                    }
+                    let styledEdges = cachedStyledEdges!
+
+                    // Immediately show unclipped wireframe (or keep current clipped one)
+                    if unclippedWireframeData == nil {
+                        unclippedWireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness)
+                    }
+
+                    // Use unclipped wireframe immediately for responsive UI
+                    if wireframeData == nil {
+                        wireframeData = unclippedWireframeData
+                    }
+
+                    // Schedule debounced async wireframe clipping
+                    let bounds = sliceBounds
+                    scheduleWireframeUpdate(device: device, styledEdges: styledEdges, thickness: thickness, bounds: bounds)
+                } else if wireframeMode == .all {
+                    // All mode with plain edges
+                    if cachedEdges == nil {
+                        cachedEdges = model.extractEdges()
+                    }
+                    let edges = cachedEdges!
+
+                    // Immediately show unclipped wireframe (or keep current clipped one)
+                    if unclippedWireframeData == nil {
+                        unclippedWireframeData = try WireframeData(device: device, edges: edges, thickness: thickness)
+                    }
+
+                    // Use unclipped wireframe immediately for responsive UI
+                    if wireframeData == nil {
+                        wireframeData = unclippedWireframeData
+                    }
+
+                    // Schedule debounced async wireframe clipping
+                    let bounds = sliceBounds
+                    scheduleWireframeUpdate(device: device, edges: edges, thickness: thickness, bounds: bounds)
+                } else {
+                    self.wireframeData = nil
+                    self.unclippedWireframeData = nil
+                }
+            } else {
+                // No triangles in bounds - don't render mesh or wireframe
+                self.meshData = nil
+                self.wireframeData = nil
+            }
+
+            // Create cut edge visualization
+            if !slicedResult.cutEdges.isEmpty {
+                self.cutEdgeData = try CutEdgeData(device: device, cutEdges: slicedResult.cutEdges)
+                measurementSystem.cutVertices = Array(Set(slicedResult.cutEdges.flatMap { [$0.start, $0.end] }))
+            } else {
+                self.cutEdgeData = nil
+            }
+
+            // Create slice plane visualization
+            if slicingState.isVisible && slicingState.showPlanes && slicingState.activePlane != nil {
+                let planeSize = Float(bbox.diagonal * 1.5)
+                self.slicePlaneData = try SlicePlaneData(
+                    device: device,
+                    slicingState: slicingState,
+                    modelCenter: bbox.center,
+                    planeSize: planeSize
+                )
+            } else {
+                self.slicePlaneData = nil
+            }
+        } else {
+            // Show full model - no clipping needed, create wireframe directly
+            // Baked occlusion only for the full model (clipped geometry no longer matches it)
+            let occlusion = isOcclusionShown && occlusionShading?.generation == modelGeneration ? occlusionShading?.occlusion : nil
+            self.meshData = try MeshData(device: device, model: model, colors: colors, occlusion: occlusion)
+
+            // Handle wireframe based on mode
+            if wireframeMode == .edge {
+                // Edge mode with styled edges
+                if cachedStyledEdges == nil {
+                    cachedStyledEdges = model.extractStyledEdges(angleThreshold: edgeAngleThreshold)
+                }
+                let styledEdges = cachedStyledEdges!
+
+                if unclippedWireframeData == nil {
+                    unclippedWireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness)
+                }
+                self.wireframeData = unclippedWireframeData
+            } else if wireframeMode == .all {
+                // All mode with plain edges
+                if cachedEdges == nil {
+                    cachedEdges = model.extractEdges()
+                }
+                let edges = cachedEdges!
+
+                if unclippedWireframeData == nil {
+                    unclippedWireframeData = try WireframeData(device: device, edges: edges, thickness: thickness)
+                }
+                self.wireframeData = unclippedWireframeData
+            } else {
+                self.wireframeData = nil
+                self.unclippedWireframeData = nil
+            }
+
+            self.slicePlaneData = nil
+            self.cutEdgeData = nil
+        }
+    }
+
+    /// Schedule a debounced wireframe update (runs in background after brief delay)
+    private func scheduleWireframeUpdate(device: MTLDevice, edges: [Edge], thickness: Float, bounds: [[Double]]) {
+        // Cancel any existing background task
+        wireframeUpdateTask?.cancel()
+
+        // Start background task with debounce built-in
+        wireframeUpdateTask = Task { @MainActor [weak self] in
+            // Short delay to debounce rapid updates
+            try? await Task.sleep(for: .milliseconds(16))
+
+            // Check if cancelled during sleep
+            if Task.isCancelled { return }
 
+            // Create clipped wireframe (runs on main actor but WireframeData does its heavy lifting in parallel internally)
             do {
-                // Render OpenSCAD with color extraction
-                let result = try renderer.renderToColoredModel(scadFile: url)
+                let clippedWireframe = try WireframeData(device: device, edges: edges, thickness: thickness, sliceBounds: bounds)
 
-                // Store warnings
-                self.renderWarnings = result.warnings
-                if !result.warnings.isEmpty {
-                    print("OpenSCAD warnings: \(result.warnings.count)")
+                // Check if cancelled
+                if Task.isCancelled { return }
+
+                // Update directly (we're already on main actor)
+                self?.wireframeData = clippedWireframe
+            } catch {
+                print("ERROR: Background wireframe update failed: \(error)")
+            }
+        }
+    }
+
+    /// Schedule a debounced wireframe update for styled edges (runs in background after brief delay)
+    private func scheduleWireframeUpdate(device: MTLDevice, styledEdges: [StyledEdge], thickness: Float, bounds: [[Double]]) {
+        // Cancel any existing background task
+        wireframeUpdateTask?.cancel()
+
+        // Start background task with debounce built-in
+        wireframeUpdateTask = Task { @MainActor [weak self] in
+            // Short delay to debounce rapid updates
+            try? await Task.sleep(for: .milliseconds(16))
+
+            // Check if cancelled during sleep
+            if Task.isCancelled { return }
+
+            // Create clipped wireframe (runs on main actor but WireframeData does its heavy lifting in parallel internally)
+            do {
+                let clippedWireframe = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness, sliceBounds: bounds)
+
+                // Check if cancelled
+                if Task.isCancelled { return }
+
+                // Update directly (we're already on main actor)
+                self?.wireframeData = clippedWireframe
+            } catch {
+                print("ERROR: Background wireframe update failed: \(error)")
+            }
+        }
+    }
+
+    /// Clear the current model (for empty files)
+    func clearModel() {
+        self.model = nil
+        self.modelGeneration += 1
+        self.spatialAccelerator = nil
+        jobs.cancel(.spatialIndex)
+        jobs.cancel(.wireframe)
+        self.cachedEdges = nil
+        self.cachedFeatureEdges = nil
+        self.cachedStyledEdges = nil
+        self.meshData = nil
+        self.wireframeData = nil
+        self.slicePlaneData = nil
+        self.cutEdgeData = nil
+        self.gridData = nil
+        self.gridTextData = nil
+        hideCaliper()
+        self.thicknessProbe = nil
+        self.faceDistance = nil
+        self.measurementSystem.clearAll()
+    }
+
+    /// Reset state for loading a new file (different from current file)
+    /// This clears all model-related state but preserves view settings like wireframe mode
+    /// - Parameter preserveSettings: If true, preserve wireframe mode, grid mode, build plate, etc.
+    func resetForNewFile(preserveSettings: Bool = false) {
+        // The state from before a replay belongs to the old file
+        stateBeforeReplay = nil
+
+        // Stop existing file watcher
+        fileWatcher?.stop()
+        fileWatcher = nil
+
+        // Clean up old temp file if exists
+        if let tempURL = tempSTLFileURL, (isOpenSCAD || isGo3mf) {
+            try? FileManager.default.removeItem(at: tempURL)
+        }
+
+        // Clear model-related state
+        model = nil
+        streamingModel = nil
+        streamingResidency = nil
+        streamingStatus = nil
+        modelGeneration += 1
+        modelBuffer.discard()
+        spatialAccelerator = nil
+        jobs.cancelAll()
+        cachedEdges = nil
+        cachedFeatureEdges = nil
+        cachedStyledEdges = nil
+        unclippedWireframeData = nil
+        coordinateOffset = .zero
+
+        // Clear GPU data
+        meshData = nil
+        wireframeData = nil
+        slicePlaneData = nil
+        cutEdgeData = nil
+        gridData = nil
+        gridTextData = nil
+        selectedTrianglesData = nil
+        hideCaliper()
+        thicknessProbe = nil
+        faceDistance = nil
+
+        // Clear file references
+        sourceFileURL = nil
+        tempSTLFileURL = nil
+        isOpenSCAD = false
+        isGo3mf = false
+        isPythonCAD = false
+        savedFileURL = nil
+        isModelModified = false
+
+        // Clear 3MF plate data
+        threeMFParseResult = nil
+        selectedPlateId = nil
+
+        // Remove the extracted archive
+        modelArchive?.remove()
+        modelArchive = nil
+        selectedArchiveEntry = nil
+
+        // Clear OpenSCAD color groups and module isolation
+        colorGroups = []
+        hiddenColorGroups = []
+        colorGroupSource = nil
+        openSCADModules = []
+        isolatedModule = nil
+        frameNextReload = false
+
+        // Clear model info
+        modelInfo = nil
+        modelMetadata = nil
+        modelProperties = []
+        isEmptyFile = false
+
+        // Clear loading/error state
+        isLoading = false
+        loadError = nil
+        loadErrorID = nil
+
+        // Reset slicing state
+        slicingState.fullReset()
+        layerPreview.fullReset()
+        camera.cutawayDepth = nil
+
+        // Clear measurements
+        measurementSystem.clearAll()
+
+        // Reset leveling state
+        levelingState.fullReset()
+
+        // Reference geometry belongs to the previous file
+        referenceGeometry.clearAll()
+
+        // Plugin analysis, comparison and clearance results belong to the previous file
+        pluginAnalysis = nil
+        measurementComparison = nil
+        removeClearanceModel()
+        closeComparison()
+
+        // Clear go-to marker
+        goToMarker = nil
+
+        // Scale fixes belong to the previous file
+        modelScale = 1
+        scaleCheck = nil
+        isScaleCheckDismissed = false
+
+        // Mesh defects belong to the previous file
+        jobs.cancel(.meshQuality)
+        meshQuality = nil
+        isMeshQualityDismissed = false
+
+        // Optionally reset view settings
+        if !preserveSettings {
+            // Reset to default view settings for a fresh file
+            // Keep these as they are - user preferences
+        }
+    }
+
+    /// Load an STL model and create mesh data for rendering
+    /// - Parameters:
+    ///   - model: The STL model to load
+    ///   - device: Metal device for GPU resources
+    ///   - preserveCamera: If true, preserve current camera position (for reloads)
+    func loadModel(_ model: STLModel, device: MTLDevice, preserveCamera: Bool = false) throws {
+        // Move far-away models near the origin before anything is converted to Float.
+        // Reloads keep the existing offset so measurements stay on the same vertices.
+        let t0 = CFAbsoluteTimeGetCurrent()
+        let keptOffset = preserveCamera && coordinateOffset != .zero ? coordinateOffset : nil
+        let fileModel = modelScale == 1 ? model : model.scaled(by: modelScale)
+        let prepared = try PreparedModel(fileModel: fileModel, device: device, coordinateOffset: keptOffset)
+        print("  MeshData: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+
+        try loadModel(prepared, device: device, preserveCamera: preserveCamera)
+
+        // Catch unit mistakes before anything is measured
+        if !preserveCamera {
+            let isUnchecked = modelScale == 1 && !isScaleCheckDismissed && AppSettings.shared.checkModelScale
+            scaleCheck = isUnchecked ? ScaleCheck.check(size: fileModel.boundingBox().size) : nil
+        }
+    }
+
+    /// Swap in a model whose vertex buffer has already been built (main thread).
+    /// Model, coordinate offset and mesh change together, so no frame mixes old and new data.
+    /// - Parameters:
+    ///   - prepared: Model in render space with its mesh data
+    ///   - device: Metal device for GPU resources
+    ///   - preserveCamera: If true, preserve current camera position (for reloads)
+    func loadModel(_ prepared: PreparedModel, device: MTLDevice, preserveCamera: Bool = false) throws {
+        let loadStart = CFAbsoluteTimeGetCurrent()
+
+        if prepared.coordinateOffset != coordinateOffset && prepared.coordinateOffset != .zero {
+            print("Recentering model by \(prepared.coordinateOffset) for rendering precision")
+        }
+        let model = prepared.model
+
+        // Chunks are in the previous render coordinates; a changed proxy is shown on its own
+        self.streamingResidency = nil
+        self.streamingStatus = nil
+
+        // Swap model, offset and vertex buffer as one unit
+        self.coordinateOffset = prepared.coordinateOffset
+        self.model = model
+        self.meshData = prepared.meshData
+        self.wireframeData = nil  // Show mesh immediately without wireframe
+        self.modelGeneration += 1
+
+        self.cachedEdges = nil  // Clear edge cache for new model
+        self.cachedFeatureEdges = nil  // Clear feature edge cache for new model
+        self.cachedStyledEdges = nil  // Clear styled edge cache for new model
+        self.unclippedWireframeData = nil  // Clear cached wireframe for new model
+        self.spatialAccelerator = nil  // Clear while rebuilding
+
+        // Look for mesh defects before anything is measured
+        updateMeshQuality()
+
+        // Picks of a part in progress belong to the previous model
+        templateRun?.restartPart()
+
+        // A calibration report describes the previous geometry (e.g. before aligning)
+        calibrationReport = nil
+
+        // Build spatial acceleration structure asynchronously for fast ray casting
+        // This allows the model to render immediately while acceleration builds in background
+        let triangles = model.triangles
+        let generation = modelGeneration
+        jobs.submit(.spatialIndex, title: "Building spatial index...") { _ in
+            SpatialAccelerator(triangles: triangles)
+        } completion: { [weak self] result in
+            guard let self = self, let accelerator = try? result.get() else { return }
+            self.uploads.enqueue(generation: generation) { [weak self] in
+                self?.spatialAccelerator = accelerator
+            }
+        }
+
+        // Calculate bounding box and thickness for wireframe
+        var t0 = CFAbsoluteTimeGetCurrent()
+        let bbox = model.boundingBox()
+        print("  boundingBox: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+
+        let modelSize = bbox.diagonal
+        let thickness = Float(modelSize) * 0.002 // 0.2% of model size
+
+        print("  Total loadModel setup: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - loadStart) * 1000))ms")
+
+        // Build wireframe asynchronously for large models
+        if model.triangles.count > 10000 && wireframeMode != .off {
+            let triangles = model.triangles
+            let currentWireframeMode = wireframeMode
+            let currentEdgeAngleThreshold = edgeAngleThreshold
+
+            jobs.submit(.wireframe, title: "Building wireframe...") { _ -> (styledEdges: [StyledEdge]?, edges: [Edge]?) in
+                // Extract edges in background
+                if currentWireframeMode == .edge {
+                    return (STLModel(triangles: triangles).extractStyledEdges(angleThreshold: currentEdgeAngleThreshold), nil)
+                }
+                return (nil, STLModel(triangles: triangles).extractEdges())
+            } completion: { [weak self] result in
+                guard let self = self, let extracted = try? result.get() else { return }
+                self.uploads.enqueue(generation: generation) { [weak self] in
+                    guard let self = self else { return }
+                    let (styledEdges, edges) = extracted
+
+                    // Cache the extracted edges
+                    if let styledEdges = styledEdges {
+                        self.cachedStyledEdges = styledEdges
+                    }
+                    if let edges = edges {
+                        self.cachedEdges = edges
+                    }
+
+                    // Create wireframe data
+                    do {
+                        if currentWireframeMode == .edge, let styledEdges = styledEdges {
+                            if let sliceBounds = self.activeSliceBounds {
+                                self.wireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness, sliceBounds: sliceBounds)
+                            } else {
+                                self.wireframeData = try WireframeData(device: device, styledEdges: styledEdges, thickness: thickness)
+                            }
+                        } else if let edges = edges {
+                            if let sliceBounds = self.activeSliceBounds {
+                                self.wireframeData = try WireframeData(device: device, edges: edges, thickness: thickness, sliceBounds: sliceBounds)
+                            } else {
+                                self.wireframeData = try WireframeData(device: device, edges: edges, thickness: thickness)
+                            }
+                        }
+                        self.unclippedWireframeData = self.wireframeData
+                    } catch {
+                        print("ERROR: Failed to create wireframe data: \(error)")
+                    }
+                }
+            }
+        } else {
+            // For small models, build wireframe synchronously
+            jobs.cancel(.wireframe)
+            try updateWireframe(device: device)
+        }
+
+        // Reinitialize measurement data with appropriate thickness for this model
+        t0 = CFAbsoluteTimeGetCurrent()
+        initializeMeasurements(device: device, thickness: thickness)
+        print("  initializeMeasurements: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+
+        // Find the board again so the grid and exaggeration follow the reloaded PCB
+        if pcbPreset {
+            updatePCBBoard()
+        }
+
+        // Check the second model against the new geometry
+        updateClearance(device: device)
+        updateComparison(device: device)
+        updateContours(device: device)
+        updateDraftAnalysis(device: device)
+        updatePrimitives(device: device)
+        updateHeightColors(device: device)
+        updateCaliper(device: device)
+
+        // Initialize grid based on model bounds
+        t0 = CFAbsoluteTimeGetCurrent()
+        try updateGrid(device: device)
+        print("  updateGrid: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+
+        // Update build plate if one is selected
+        t0 = CFAbsoluteTimeGetCurrent()
+        if buildPlate != .off {
+            updateBuildPlate(device: device)
+        }
+        print("  updateBuildPlate: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+
+        // Frame the model in view (only for initial load, not reloads)
+        if !preserveCamera {
+            camera.frameBoundingBox(bbox)
+        }
+
+        // Handle slicing bounds based on whether this is a reload or new file
+        if preserveCamera {
+            // Reload - preserve slice positions, just update model bounds
+            slicingState.updateModelBounds(from: bbox)
+            if layerPreview.isActive {
+                layerPreview.updateRange(minZ: bbox.min.z, maxZ: bbox.max.z)
+                try updateMeshData(device: device)
+            }
+        } else {
+            // New file - initialize to full model bounds
+            slicingState.initializeBounds(from: bbox)
+        }
+
+        // Handle measurements based on whether this is a reload or new file
+        if preserveCamera {
+            // Reload - validate existing measurements against new model
+            measurementSystem.validateMeasurements(model: model, accelerator: spatialAccelerator)
+        } else {
+            // New file - clear all measurements
+            measurementSystem.clearAll()
+        }
+
+        // Size reference planes and axes relative to the model
+        referenceGeometry.displaySize = max(modelSize * 0.6, 1.0)
+
+        // Clear loading state
+        isLoading = false
+    }
+
+    /// Load a file from URL (supports both .stl and .scad files)
+    /// - Parameters:
+    ///   - url: The file URL to load
+    ///   - device: Metal device for GPU resources
+    ///   - isSameFile: If true, preserve settings like material (for reloads)
+    func loadFile(_ url: URL, device: MTLDevice, isSameFile: Bool = false) throws {
+        // Check if this is the same file (for determining whether to preserve settings)
+        let shouldPreserveSettings = isSameFile || (sourceFileURL == url)
+
+        // Reset state for the new file (cleans up watchers, temp files, etc.)
+        if !shouldPreserveSettings {
+            resetForNewFile(preserveSettings: false)
+        } else {
+            // Just stop watcher and clean temp file, but preserve settings
+            fileWatcher?.stop()
+            fileWatcher = nil
+            jobs.cancel(.load)
+            if let tempURL = tempSTLFileURL, (isOpenSCAD || isGo3mf), tempURL != url {
+                try? FileManager.default.removeItem(at: tempURL)
+            }
+        }
+
+        let fileExtension = ModelFileLoader.modelExtension(of: url)
+
+        if fileExtension == "scad" {
+            // OpenSCAD file - render with color extraction
+            print("Rendering OpenSCAD file: \(url.lastPathComponent)")
+
+            let workDir = url.deletingLastPathComponent()
+            let renderer = OpenSCADRenderer(workDir: workDir)
+
+            // Keep the isolated module when the same file is reopened and still defines it
+            openSCADModules = OpenSCADModuleParser.topLevelModules(scadFile: url)
+            if let module = isolatedModule, !openSCADModules.contains(where: { $0.name == module }) {
+                isolatedModule = nil
+            }
+
+            do {
+                // Render OpenSCAD with color extraction
+                let result = try Self.renderOpenSCAD(
+                    renderer: renderer,
+                    scadFile: url,
+                    module: isolatedModule,
+                    viaThreeMF: AppSettings.shared.openSCADThreeMFExport
+                )
+
+                // Store warnings
+                self.renderWarnings = result.warnings
+                if !result.warnings.isEmpty {
+                    print("OpenSCAD warnings: \(result.warnings.count)")
+                }
+
+                // Load the model directly (already has colors assigned), without hidden color groups
+                setColorGroupSource(result.model)
+                try loadModel(ColorGroup.model(result.model, hiding: hiddenColorGroups), device: device)
+
+                // Update file watching state
+                self.sourceFileURL = url
+                self.tempSTLFileURL = nil  // No temp file needed with direct model
+                self.isOpenSCAD = true
+                self.is2DOpenSCAD = result.is2D
+                self.isGo3mf = false
+                self.isPythonCAD = false
+                self.isEmptyFile = false
+                self.threeMFParseResult = nil
+                self.selectedPlateId = nil
+                self.modelInfo = self.makeModelInfo(fileName: url.lastPathComponent)
+
+                if result.is2D {
+                    print("Detected 2D OpenSCAD file, extruded to 1mm height for visualization")
+                }
+                if result.colorsExtracted > 0 {
+                    print("Extracted \(result.colorsExtracted) colors from OpenSCAD file")
+                }
+                print("Successfully loaded: \(result.model.triangleCount) triangles")
+            } catch let error as OpenSCADError {
+                // Handle OpenSCAD-specific errors
+                if case .emptyFile(let messages) = error {
+                    // Empty file - show blank view with info
+                    print("OpenSCAD file is empty: \(url.lastPathComponent)")
+                    clearModel()
+                    self.sourceFileURL = url
+                    self.tempSTLFileURL = nil
+                    self.isOpenSCAD = true
+                    self.isGo3mf = false
+                    self.isPythonCAD = false
+                    self.isEmptyFile = true
+                    self.renderWarnings = messages
+                    self.threeMFParseResult = nil
+                    self.selectedPlateId = nil
+                    self.modelInfo = ModelInfo(fileName: url.lastPathComponent, triangleCount: 0, volume: 0, boundingBox: BoundingBox())
+                    self.isLoading = false
+                    refreshMetadata()
+                    return
+                }
+                // Store messages from other OpenSCAD errors before rethrowing
+                self.renderWarnings = error.messages
+                throw error
+            }
+
+        } else if fileExtension == "py" {
+            // CadQuery/build123d script - run it in the configured Python environment
+            print("Rendering Python CAD script: \(url.lastPathComponent)")
+
+            let renderer = PythonCADRenderer(workDir: url.deletingLastPathComponent())
+
+            do {
+                let result = try renderer.render(script: url)
+                self.renderWarnings = result.messages
+                try loadModel(result.model, device: device)
+
+                // Update file watching state
+                self.sourceFileURL = url
+                self.tempSTLFileURL = nil
+                self.isOpenSCAD = false
+                self.isGo3mf = false
+                self.isPythonCAD = true
+                self.isEmptyFile = false
+                self.threeMFParseResult = nil
+                self.selectedPlateId = nil
+                self.modelInfo = self.makeModelInfo(fileName: url.lastPathComponent)
+
+                print("Successfully loaded: \(result.model.triangleCount) triangles (\(result.objectCount) objects)")
+            } catch let error as PythonCADError {
+                // Store printed output and warnings before rethrowing
+                self.renderWarnings = error.messages
+                throw error
+            }
+
+        } else if fileExtension == "stl", AppSettings.shared.streamLargeModels, StreamingModel.shouldStream(url: url) {
+            // Binary STL too large to keep in memory - index it in the background and show a proxy
+            openStreaming(url, device: device)
+            return
+
+        } else if fileExtension == "stl" || PluginRegistry.shared.importer(forExtension: fileExtension) != nil {
+            // Regular STL file, or a format converted to STL by an importer plugin
+            print("Loading \(fileExtension.uppercased()) file: \(url.lastPathComponent)")
+            var t0 = CFAbsoluteTimeGetCurrent()
+            let model = try ModelFileLoader.load(url: url)
+            print("  Parsing: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms (\(model.triangleCount) triangles)")
+            t0 = CFAbsoluteTimeGetCurrent()
+            try loadModel(model, device: device)
+            print("  loadModel total: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+
+            // Update file watching state
+            self.sourceFileURL = url
+            self.tempSTLFileURL = nil
+            self.isOpenSCAD = false
+            self.isGo3mf = false
+            self.isPythonCAD = false
+            self.renderWarnings = []
+            self.threeMFParseResult = nil
+            self.selectedPlateId = nil
+            self.modelInfo = self.makeModelInfo(fileName: url.lastPathComponent)
+
+            print("Successfully loaded: \(model.triangleCount) triangles")
+
+        } else if fileExtension == "3mf" {
+            // 3MF file (3D Manufacturing Format)
+            print("Loading 3MF file: \(url.lastPathComponent)")
+            let parseResult = try ThreeMFParser.parseWithPlates(url: url)
+            self.threeMFParseResult = parseResult
+
+            // Select first plate by default, or show all if only one plate
+            let initialPlateId = parseResult.plates.first?.id
+            self.selectedPlateId = initialPlateId
+
+            let model: STLModel
+            if let plateId = initialPlateId, parseResult.plates.count > 1 {
+                model = parseResult.model(forPlate: plateId)
+                print("Loaded plate \(plateId): \(parseResult.plates.first { $0.id == plateId }?.name ?? "Unknown")")
+            } else {
+                model = parseResult.modelWithAllPlates()
+            }
+
+            try loadModel(model, device: device)
+
+            // Update file watching state
+            self.sourceFileURL = url
+            self.tempSTLFileURL = nil
+            self.isOpenSCAD = false
+            self.isGo3mf = false
+            self.isPythonCAD = false
+            self.renderWarnings = []
+            self.modelInfo = self.makeModelInfo(fileName: url.lastPathComponent)
+
+            print("Successfully loaded: \(model.triangleCount) triangles (\(parseResult.plates.count) plates)")
+
+        } else if fileExtension == "zip" {
+            // ZIP archive of models - extract it and show the first model (or the only one)
+            print("Opening archive: \(url.lastPathComponent)")
+            let archive = try ModelArchive.open(url: url)
+
+            // Keep the selection when the same archive is reopened and still contains it
+            let previous = shouldPreserveSettings ? selectedArchiveEntry : nil
+            let entry = previous.flatMap { path in archive.entries.first { $0.path == path } } ?? archive.entries[0]
+            let loaded: (model: STLModel, warnings: [String])
+            do {
+                loaded = try archive.load(path: entry.path, name: nil)
+                try loadModel(loaded.model, device: device)
+            } catch {
+                archive.remove()
+                throw error
+            }
+
+            // Update file watching state
+            modelArchive?.remove()
+            self.modelArchive = archive
+            self.selectedArchiveEntry = entry.path
+            self.sourceFileURL = url
+            self.tempSTLFileURL = nil
+            self.isOpenSCAD = false
+            self.isGo3mf = false
+            self.isPythonCAD = false
+            self.renderWarnings = loaded.warnings
+            self.threeMFParseResult = nil
+            self.selectedPlateId = nil
+            self.modelInfo = self.makeModelInfo(fileName: archiveFileName(for: url))
+
+            print("Successfully loaded \(entry.path) from archive (\(archive.entries.count) models)")
+
+        } else if fileExtension == "yaml" || fileExtension == "yml" {
+            // go3mf YAML configuration file - use external go3mf tool to build 3MF
+            print("Loading go3mf config: \(url.lastPathComponent)")
+
+            let workDir = url.deletingLastPathComponent()
+            let renderer = Go3mfToolRenderer(workDir: workDir)
+
+            // Create temporary 3MF file
+            let temp3MFURL = TempWorkspace.shared.makeURL(prefix: "go3mf", pathExtension: "3mf")
+
+            try renderer.buildTo3MF(yamlFile: url, outputFile: temp3MFURL)
+
+            // Load the generated 3MF file
+            let parseResult = try ThreeMFParser.parseWithPlates(url: temp3MFURL)
+            self.threeMFParseResult = parseResult
+
+            // Select first plate by default, or show all if only one plate
+            let initialPlateId = parseResult.plates.first?.id
+            self.selectedPlateId = initialPlateId
+
+            let model: STLModel
+            if let plateId = initialPlateId, parseResult.plates.count > 1 {
+                model = parseResult.model(forPlate: plateId)
+                print("Loaded plate \(plateId): \(parseResult.plates.first { $0.id == plateId }?.name ?? "Unknown")")
+            } else {
+                model = parseResult.modelWithAllPlates()
+            }
+
+            try loadModel(model, device: device)
+
+            // Update file watching state
+            self.sourceFileURL = url
+            self.tempSTLFileURL = temp3MFURL  // Store temp 3MF for cleanup
+            self.isOpenSCAD = false
+            self.isGo3mf = true
+            self.isPythonCAD = false
+            self.renderWarnings = []
+            self.modelInfo = self.makeModelInfo(fileName: url.lastPathComponent)
+
+            print("Successfully loaded go3mf config: \(model.triangleCount) triangles (\(parseResult.plates.count) plates)")
+
+        } else {
+            throw FileLoadError.unsupportedFileType(fileExtension)
+        }
+
+        refreshMetadata()
+        fireHook(.modelLoaded, data: modelHookData)
+    }
+
+    /// Select a different plate from a 3MF file, or nil to show all plates together
+    func selectPlate(_ plateId: Int?, device: MTLDevice) throws {
+        guard let parseResult = threeMFParseResult else {
+            print("No 3MF parse result available")
+            return
+        }
+
+        if let plateId, !parseResult.plates.contains(where: { $0.id == plateId }) {
+            print("Invalid plate ID: \(plateId)")
+            return
+        }
+
+        self.selectedPlateId = plateId
+        let model = plateId.map { parseResult.model(forPlate: $0) } ?? parseResult.modelWithAllPlates()
+
+        // Clear cached data for the new model
+        cachedEdges = nil
+        cachedFeatureEdges = nil
+        cachedStyledEdges = nil
+        unclippedWireframeData = nil
+        wireframeData = nil
+        gridData = nil
+        gridTextData = nil
+
+        try loadModel(model, device: device, preserveCamera: false)
+
+        // Update model info
+        if let sourceURL = sourceFileURL {
+            self.modelInfo = self.makeModelInfo(fileName: sourceURL.lastPathComponent)
+        }
+
+        let plateName = parseResult.plates.first { $0.id == plateId }?.name ?? "All plates"
+        print("Switched to plate \(plateName) (\(model.triangleCount) triangles)")
+    }
+
+    // MARK: - Archives
+
+    /// Show another model of the opened archive, or nil to show all models as an assembly
+    func selectArchiveEntry(_ path: String?, device: MTLDevice) throws {
+        guard let archive = modelArchive, let sourceURL = sourceFileURL else { return }
+
+        let loaded = try archive.load(path: path, name: ModelFileLoader.modelName(of: sourceURL))
+        selectedArchiveEntry = path
+
+        // Clear cached data for the new model
+        cachedEdges = nil
+        cachedFeatureEdges = nil
+        cachedStyledEdges = nil
+        unclippedWireframeData = nil
+        wireframeData = nil
+        gridData = nil
+        gridTextData = nil
+
+        try loadModel(loaded.model, device: device, preserveCamera: false)
+        renderWarnings = loaded.warnings
+        modelInfo = makeModelInfo(fileName: archiveFileName(for: sourceURL))
+
+        print("Switched to \(path ?? "all models") (\(loaded.model.triangleCount) triangles)")
+    }
+
+    /// "parts.zip › bracket.stl", or "parts.zip (all 3 models)" for the assembly
+    private func archiveFileName(for url: URL) -> String {
+        guard let archive = modelArchive else { return url.lastPathComponent }
+        guard let path = selectedArchiveEntry else {
+            return "\(url.lastPathComponent) (all \(archive.entries.count) models)"
+        }
+        return "\(url.lastPathComponent) › \((path as NSString).lastPathComponent)"
+    }
+
+    // MARK: - Color Groups
+
+    /// Remember a rendered OpenSCAD model and list its color groups (a single color is not a grouping)
+    private func setColorGroupSource(_ model: STLModel) {
+        let groups = ColorGroup.groups(in: model.triangles)
+        colorGroupSource = groups.count > 1 ? model : nil
+        colorGroups = groups.count > 1 ? groups : []
+        hiddenColorGroups.formIntersection(colorGroups.map(\.id))
+        // Hidden groups that cover everything are shown (see `ColorGroup.model(_:hiding:)`)
+        if hiddenColorGroups.count == colorGroups.count {
+            hiddenColorGroups = []
+        }
+    }
+
+    /// Show or hide the triangles of a color group; the last visible group cannot be hidden
+    func setColorGroup(_ id: String, visible: Bool, device: MTLDevice) throws {
+        guard let source = colorGroupSource else { return }
+
+        var hidden = hiddenColorGroups
+        if visible {
+            hidden.remove(id)
+        } else {
+            hidden.insert(id)
+        }
+        guard hidden != hiddenColorGroups, colorGroups.contains(where: { !hidden.contains($0.id) }) else { return }
+
+        hiddenColorGroups = hidden
+        try loadColorGroupSelection(from: source, device: device)
+    }
+
+    /// Show every color group again
+    func showAllColorGroups(device: MTLDevice) throws {
+        guard let source = colorGroupSource, !hiddenColorGroups.isEmpty else { return }
+        hiddenColorGroups = []
+        try loadColorGroupSelection(from: source, device: device)
+    }
+
+    private func loadColorGroupSelection(from source: STLModel, device: MTLDevice) throws {
+        let material = modelInfo?.material
+        let infill = modelInfo?.infill
+        try loadModel(ColorGroup.model(source, hiding: hiddenColorGroups), device: device, preserveCamera: true)
+
+        // Info shows the visible groups; keep the material choice
+        if let fileName = modelInfo?.fileName {
+            var info = makeModelInfo(fileName: fileName)
+            info.material = material ?? .pla
+            info.infill = infill ?? 1.0
+            modelInfo = info
+        }
+        print("Showing \(colorGroups.count - hiddenColorGroups.count) of \(colorGroups.count) color groups")
+    }
+
+    // MARK: - Module Isolation
+
+    /// Render one top-level module of the open .scad file, or the whole file for nil
+    func isolateModule(_ name: String?, device: MTLDevice) {
+        guard isOpenSCAD, name != isolatedModule else { return }
+        if let name, !openSCADModules.contains(where: { $0.name == name }) {
+            print("Unknown module: \(name)")
+            return
+        }
+
+        isolatedModule = name
+        frameNextReload = true
+        print(name.map { "Isolating module \($0)()" } ?? "Showing the whole file")
+        reloadModel(device: device)
+    }
+
+    /// Render a .scad file, or only one of its modules
+    private static func renderOpenSCAD(renderer: OpenSCADRenderer, scadFile: URL, module: String?, viaThreeMF: Bool) throws -> OpenSCADRenderer.ColoredRenderResult {
+        if let module {
+            return try renderer.renderModule(module, scadFile: scadFile, viaThreeMF: viaThreeMF)
+        }
+        return try renderer.renderColored(scadFile: scadFile, viaThreeMF: viaThreeMF)
+    }
+
+    /// Set up file watching for the currently loaded file
+    func setupFileWatcher() throws {
+        guard let sourceURL = sourceFileURL else {
+            print("setupFileWatcher: No source file to watch")
+            return
+        }
+
+        // Re-indexing a streamed file takes minutes; it is reloaded on request only
+        guard streamingModel == nil else { return }
+
+        // Stop existing watcher first
+        fileWatcher?.stop()
+        fileWatcher = nil
+
+        let watcher = FileWatcher()
+        var filesToWatch: [URL] = []
+
+        if isGo3mf {
+            // For go3mf YAML files, just watch the source file
+            // (go3mf tool handles dependency resolution internally)
+            filesToWatch = [sourceURL]
+        } else if isPythonCAD {
+            // For Python scripts, watch the script and the local modules it imports
+            let renderer = PythonCADRenderer(workDir: sourceURL.deletingLastPathComponent())
+            filesToWatch = renderer.resolveDependencies(script: sourceURL)
+        } else if isOpenSCAD {
+            // For OpenSCAD files, watch the source file and all dependencies
+            let workDir = sourceURL.deletingLastPathComponent()
+            let renderer = OpenSCADRenderer(workDir: workDir)
+            filesToWatch = renderer.resolveDependencies(scadFile: sourceURL)
+        } else {
+            // For STL/3MF files, just watch the source file
+            filesToWatch = [sourceURL]
+        }
+
+        // Set up callback for file changes
+        try watcher.watch(files: filesToWatch) { [weak self] changedFile in
+            guard let self = self else { return }
+            DispatchQueue.main.async {
+                self.reloadRequestId += 1
+            }
+        }
+
+        self.fileWatcher = watcher
+    }
+
+    /// Open the current .scad file in OpenSCAD application
+    func openInOpenSCAD() {
+        guard let sourceURL = sourceFileURL, isOpenSCAD else {
+            print("No OpenSCAD file loaded")
+            return
+        }
+
+        // Try to find OpenSCAD.app
+        let openSCADAppURL = URL(fileURLWithPath: "/Applications/OpenSCAD.app")
+
+        if FileManager.default.fileExists(atPath: openSCADAppURL.path) {
+            // Open the file with OpenSCAD.app
+            NSWorkspace.shared.open(
+                [sourceURL],
+                withApplicationAt: openSCADAppURL,
+                configuration: NSWorkspace.OpenConfiguration()
+            ) { _, error in
+                if let error = error {
+                    print("Failed to open in OpenSCAD: \(error.localizedDescription)")
+                }
+            }
+        } else {
+            // Fallback: try to open the file and let the system choose the app
+            print("OpenSCAD.app not found at /Applications/OpenSCAD.app")
+            NSWorkspace.shared.open(sourceURL)
+        }
+    }
+
+    /// Geometry produced by a background reload, applied on the main thread
+    private struct ReloadResult: @unchecked Sendable {
+        /// Ticket of the model staged in `modelBuffer`
+        var ticket = 0
+        var tempURL: URL?
+        var threeMFParseResult: ThreeMFParseResult?
+        var openSCADResult: OpenSCADRenderer.ColoredRenderResult?
+        var openSCADModules: [OpenSCADModule]?
+        var pythonCADMessages: [String]?
+        var modelArchive: ModelArchive?
+        var archiveWarnings: [String]?
+    }
+
+    /// Reload the model from the source file.
+    /// Rendering runs as a load job; a reload while one is running replaces it.
+    func reloadModel(device: MTLDevice) {
+        print("reloadModel called - isLoading: \(isLoading), isPaused: \(fileWatcher?.isPaused ?? false)")
+
+        guard let sourceURL = sourceFileURL else {
+            print("No source file to reload")
+            return
+        }
+
+        isLoading = true
+
+        if streamingModel != nil {
+            openStreaming(sourceURL, device: device)
+            return
+        }
+
+        // Pause file watcher during reload to prevent re-triggers from generated files
+        fileWatcher?.isPaused = true
+        print("Reloading model from: \(sourceURL.lastPathComponent)")
+
+        // Read everything the job needs here; the job itself must not touch app state
+        let isGo3mf = self.isGo3mf
+        let isOpenSCAD = self.isOpenSCAD
+        let isPythonCAD = self.isPythonCAD
+        let pythonInterpreter = AppSettings.shared.pythonInterpreter
+        let selectedPlateId = self.selectedPlateId
+        let hiddenColorGroups = self.hiddenColorGroups
+        let isolatedModule = self.isolatedModule
+        let isArchive = self.modelArchive != nil
+        let archiveEntry = self.selectedArchiveEntry
+        let viaThreeMF = AppSettings.shared.openSCADThreeMFExport
+        let keptOffset = coordinateOffset == .zero ? nil : coordinateOffset
+        let modelScale = self.modelScale
+        let modelBuffer = self.modelBuffer
+
+        jobs.submit(.load, title: "Reloading \(sourceURL.lastPathComponent)") { job -> ReloadResult in
+            let workDir = sourceURL.deletingLastPathComponent()
+            var reloaded = ReloadResult()
+            let model: STLModel
+
+            if isGo3mf {
+                // Build go3mf YAML config using external go3mf tool
+                let renderer = Go3mfToolRenderer(workDir: workDir)
+                renderer.job = job
+
+                let tempURL = TempWorkspace.shared.makeURL(prefix: "go3mf", pathExtension: "3mf")
+
+                job.reportProgress(nil, "Building with go3mf...")
+                try renderer.buildTo3MF(yamlFile: sourceURL, outputFile: tempURL)
+                try job.checkCancellation()
+
+                // Load the generated 3MF file (supports plates)
+                let parseResult = try ThreeMFParser.parseWithPlates(url: tempURL)
+
+                // Get model based on selected plate
+                if let plateId = selectedPlateId, parseResult.plates.count > 1 {
+                    model = parseResult.model(forPlate: plateId)
+                } else {
+                    model = parseResult.modelWithAllPlates()
+                }
+                reloaded.tempURL = tempURL
+                reloaded.threeMFParseResult = parseResult
+            } else if isOpenSCAD {
+                // Render OpenSCAD with color extraction
+                let renderer = OpenSCADRenderer(workDir: workDir)
+                renderer.job = job
+
+                // Render the whole file if the isolated module was removed or renamed
+                let modules = OpenSCADModuleParser.topLevelModules(scadFile: sourceURL)
+                let module = isolatedModule.flatMap { name in modules.contains { $0.name == name } ? name : nil }
+
+                let result = try Self.renderOpenSCAD(renderer: renderer, scadFile: sourceURL, module: module, viaThreeMF: viaThreeMF)
+                model = ColorGroup.model(result.model, hiding: hiddenColorGroups)
+                reloaded.openSCADResult = result
+                reloaded.openSCADModules = modules
+            } else if isPythonCAD {
+                // Run the CadQuery/build123d script
+                let renderer = PythonCADRenderer(workDir: workDir, interpreter: pythonInterpreter)
+                renderer.job = job
+
+                let result = try renderer.render(script: sourceURL)
+                model = result.model
+                reloaded.pythonCADMessages = result.messages
+            } else if isArchive {
+                // Extract the archive again and load the same model (the assembly if it is gone)
+                job.reportProgress(nil, "Extracting \(sourceURL.lastPathComponent)...")
+                let archive = try ModelArchive.open(url: sourceURL)
+                let path = archiveEntry.flatMap { path in archive.entries.contains { $0.path == path } ? path : nil }
+                    ?? archiveEntry.map { _ in archive.entries[0].path }
+                let loaded: (model: STLModel, warnings: [String])
+                do {
+                    loaded = try archive.load(path: path, name: ModelFileLoader.modelName(of: sourceURL))
+                } catch {
+                    archive.remove()
+                    throw error
+                }
+                model = loaded.model
+                reloaded.modelArchive = archive
+                reloaded.archiveWarnings = loaded.warnings
+            } else {
+                // Load STL/3MF directly (or through an importer plugin)
+                job.reportProgress(nil, "Reading \(sourceURL.lastPathComponent)...")
+                model = try ModelFileLoader.load(url: sourceURL, job: job)
+            }
+
+            // Build the vertex buffer here too; the main thread only swaps it in
+            try job.checkCancellation()
+            job.reportProgress(nil, "Preparing mesh...")
+            let fileModel = modelScale == 1 ? model : model.scaled(by: modelScale)
+            let prepared = try PreparedModel(fileModel: fileModel, device: device, coordinateOffset: keptOffset)
+            reloaded.ticket = try modelBuffer.stage(prepared, job: job)
+            return reloaded
+        } completion: { [weak self] result in
+            guard let self = self else { return }
+            switch result {
+            case .success(let reloaded):
+                self.applyReload(reloaded, from: sourceURL, device: device)
+            case .failure(let error as OpenSCADError):
+                if case .emptyFile(let messages) = error {
+                    print("OpenSCAD file is empty: \(sourceURL.lastPathComponent)")
+                    self.clearModel()
+                    self.isEmptyFile = true
+                    self.renderWarnings = messages
+                    self.modelInfo = ModelInfo(fileName: sourceURL.lastPathComponent)
+                    self.isLoading = false
+                    self.loadError = nil
+                    self.loadErrorID = nil
+
+                    // Re-setup file watcher (dependencies may have changed)
+                    try? self.setupFileWatcher()
+                } else {
+                    // Other OpenSCAD errors - show messages and error
+                    print("ERROR: Failed to reload model: \(error)")
+                    self.renderWarnings = error.messages
+                    self.failReload(error)
+                }
+            case .failure(let error as PythonCADError):
+                print("ERROR: Failed to reload model: \(error)")
+                self.renderWarnings = error.messages
+                self.failReload(error)
+            case .failure(let error):
+                print("ERROR: Failed to reload model: \(error)")
+                self.failReload(error)
+            }
+        }
+    }
+
+    /// Cancel a running reload and keep the model that is currently shown
+    func cancelReload() {
+        guard jobs.isRunning(.load) else { return }
+        jobs.cancel(.load)
+        isLoading = false
+        fileWatcher?.isPaused = false
+        print("Reload cancelled")
+    }
+
+    /// Swap in the result of a reload job (main thread)
+    private func applyReload(_ reloaded: ReloadResult, from sourceURL: URL, device: MTLDevice) {
+        // A newer reload has staged its own model and will apply it when it completes
+        guard let prepared = modelBuffer.swap(reloaded.ticket) else {
+            print("Reload superseded - keeping current model")
+            // The newer reload finishes the loading state; if it is gone too, nothing else will
+            if !jobs.isRunning(.load) {
+                isLoading = false
+                frameNextReload = false
+                fileWatcher?.isPaused = false
+            }
+            return
+        }
+
+        if let parseResult = reloaded.threeMFParseResult {
+            threeMFParseResult = parseResult
+        }
+        if let modules = reloaded.openSCADModules {
+            openSCADModules = modules
+            if let module = isolatedModule, !modules.contains(where: { $0.name == module }) {
+                print("Module \(module) no longer exists - showing the whole file")
+                isolatedModule = nil
+            }
+        }
+        if let messages = reloaded.pythonCADMessages {
+            renderWarnings = messages
+        }
+        if let archive = reloaded.modelArchive {
+            modelArchive?.remove()
+            modelArchive = archive
+            if let path = selectedArchiveEntry, !archive.entries.contains(where: { $0.path == path }) {
+                print("\(path) no longer exists in the archive - showing \(archive.entries[0].path)")
+                selectedArchiveEntry = archive.entries[0].path
+            }
+            renderWarnings = reloaded.archiveWarnings ?? []
+        }
+        if let result = reloaded.openSCADResult {
+            setColorGroupSource(result.model)
+            renderWarnings = result.warnings
+            is2DOpenSCAD = result.is2D
+            if result.is2D {
+                print("Detected 2D OpenSCAD file, extruded to 1mm height for visualization")
+            }
+            if result.colorsExtracted > 0 {
+                print("Extracted \(result.colorsExtracted) colors from OpenSCAD file")
+            }
+            if !result.warnings.isEmpty {
+                print("OpenSCAD warnings: \(result.warnings.count)")
+            }
+        }
+
+        do {
+            // Clean up old temp file if exists (for both OpenSCAD and go3mf)
+            if let oldTempURL = tempSTLFileURL, (isOpenSCAD || isGo3mf), oldTempURL != reloaded.tempURL {
+                try? FileManager.default.removeItem(at: oldTempURL)
+            }
+
+            // Update temp file reference
+            if let tempURL = reloaded.tempURL {
+                tempSTLFileURL = tempURL
+            }
+
+            // Swap in the prepared model, preserving camera position unless another module was selected
+            let preserveCamera = !frameNextReload
+            frameNextReload = false
+            try loadModel(prepared, device: device, preserveCamera: preserveCamera)
+
+            // Preserve the selected material and infill from previous model info
+            var newModelInfo = makeModelInfo(fileName: archiveFileName(for: sourceURL))
+            newModelInfo.material = modelInfo?.material ?? .pla
+            newModelInfo.infill = modelInfo?.infill ?? 1.0
+            modelInfo = newModelInfo
+            isEmptyFile = false
+            refreshMetadata(reloadingProperties: false)
+
+            print("Model reloaded successfully!")
+            fireHook(.modelReloaded, data: modelHookData)
+            isLoading = false
+            loadError = nil
+            loadErrorID = nil
+
+            // Re-setup file watcher to catch any new dependencies
+            // (e.g., new include/use statements added to OpenSCAD file)
+            try? setupFileWatcher()
+        } catch {
+            print("ERROR: Failed to apply reloaded model: \(error)")
+            failReload(error)
+        }
+    }
+
+    /// Show a reload error and keep watching the file
+    private func failReload(_ error: Error) {
+        isLoading = false
+        frameNextReload = false
+        loadError = error
+        loadErrorID = UUID()
+
+        // Resume file watcher
+        fileWatcher?.isPaused = false
+    }
+
+    /// Cycle to the next material type (for weight calculation)
+    func cycleMaterial() {
+        if var info = modelInfo {
+            info.material = info.material.next()
+            self.modelInfo = info
+            print("Material changed to: \(info.material.rawValue)")
+        }
+    }
+
+    /// Infill presets offered in the Tools menu
+    static let infillPresets: [Double] = [1.0, 0.5, 0.4, 0.2, 0.15, 0.1]
+
+    /// Set the infill used for weight and cost estimates
+    func setInfill(_ infill: Double) {
+        guard var info = modelInfo else { return }
+        info.infill = min(max(infill, 0), 1)
+        self.modelInfo = info
+    }
+
+    // MARK: - Depth Range
+
+    /// Bounds of everything that is rendered in the scene (model, grid and build plate)
+    var sceneBounds: BoundingBox? {
+        guard let modelBounds = modelInfo?.boundingBox else { return nil }
+
+        // The grid extends up to 20% beyond the model
+        let gridMargin = modelBounds.size * 0.2
+        var bounds = BoundingBox(min: modelBounds.min - gridMargin, max: modelBounds.max + gridMargin)
+        bounds.extend(Vector3.zero)
+
+        if buildPlate != .off {
+            let dimensions = buildPlate.dimensions
+            let half = Vector3(Double(dimensions.x), Double(dimensions.y), Double(dimensions.z)) * 0.5
+            let center = modelBounds.center
+            bounds.extend(BoundingBox(min: center - half, max: center + half))
+        }
+
+        return bounds
+    }
+
+    /// Install spatial index and wireframe buffers built since the last frame (called by the renderer)
+    @MainActor
+    func applyPendingUploads() {
+        uploads.drain(currentGeneration: modelGeneration)
+    }
+
+    /// Keep the vertex snap distance of picks at the snap radius on screen for the current view
+    /// (called every frame, after `viewSize` is recorded)
+    func updateVertexSnap() {
+        let position = camera.toModel(camera.position)
+        measurementSystem.snapEye = Vector3(Double(position.x), Double(position.y), Double(position.z))
+        measurementSystem.vertexSnapPerDistance = AppSettings.shared.snapRadius
+            * Camera.worldSize(ofPointAtDistance: 1, viewHeight: Double(viewSize.height))
+    }
+
+    /// Fit the camera near/far planes to the scene (called every frame when auto fitting is enabled)
+    func updateDepthRange() {
+        guard camera.autoDepthRange, let bounds = sceneBounds else { return }
+        camera.fitDepthRange(to: bounds)
+    }
+
+    // MARK: - Go To Methods
+
+    /// Named locations offered by the go-to palette search
+    var goToAnchors: [GoToAnchor] {
+        var anchors = [GoToAnchor(name: "Origin", position: renderPosition(.zero))]
+
+        if let model = model {
+            let bbox = model.boundingBox()
+            anchors.append(GoToAnchor(name: "Model center", position: bbox.center))
+            anchors.append(GoToAnchor(name: "Bounding box min", position: bbox.min))
+            anchors.append(GoToAnchor(name: "Bounding box max", position: bbox.max))
+        }
+
+        for (index, measurement) in measurementSystem.measurements.enumerated() where measurement.type != .triangleSelect {
+            let showDiameter = measurementSystem.showDiameter
+            let name = "\(measurement.label(showDiameter: showDiameter)) #\(index + 1): \(measurement.formattedValue(showDiameter: showDiameter, precision: displayPrecision))"
+            anchors.append(GoToAnchor(name: name, position: measurement.labelPosition))
+        }
+
+        return anchors
+    }
+
+    /// Navigate the camera to a go-to query and show a temporary marker there
+    /// - Returns: true if the query resolved to a location
+    @discardableResult
+    func goTo(_ query: GoToQuery) -> Bool {
+        guard let target = query.resolve(model: model, anchors: goToAnchors, coordinateOffset: coordinateOffset) else {
+            print("Go to: nothing found for \(query)")
+            return false
+        }
+
+        camera.focus(on: target.position)
+        goToMarker = target.position
+        measurementAnnouncer.announce("Moved to \(target.description)")
+        print("Go to: \(target.description) at \(target.position)")
+
+        // Remove the marker after a short while
+        goToMarkerClearTask?.cancel()
+        goToMarkerClearTask = Task { @MainActor [weak self] in
+            try? await Task.sleep(for: .seconds(4))
+            guard !Task.isCancelled else { return }
+            self?.goToMarker = nil
+        }
+        return true
+    }
+
+    // MARK: - Context Menu
+
+    /// Whether a context menu action is currently in effect (shown with a check mark)
+    func isContextMenuActionActive(_ action: ContextMenuAction) -> Bool {
+        switch action {
+        case .toggleSelection(let index):
+            return measurementSystem.selectedMeasurements.contains(index)
+        case .constrainToAxis(let axis):
+            return measurementSystem.isConstraintActive(on: axis)
+        case .constrainAlong(let direction):
+            return measurementSystem.constrainingDirection?.isApproximatelyEqual(to: direction.normalized(), tolerance: 1e-9) ?? false
+        default:
+            return false
+        }
+    }
+
+    /// Run an action chosen from the right-click context menu
+    @MainActor
+    func performContextMenuAction(_ action: ContextMenuAction) {
+        switch action {
+        case .deleteMeasurement(let index):
+            measurementSystem.removeMeasurement(at: index)
+        case .copyValue(let index):
+            guard index < measurementSystem.measurements.count else { return }
+            let measurement = measurementSystem.measurements[index]
+            let value = measurement.type == .radius && measurementSystem.showDiameter ? measurement.value * 2 : measurement.value
+            copyToPasteboard(String(format: "%.4f", value))
+        case .editNote(let index):
+            guard index < measurementSystem.measurements.count,
+                  let note = ContextMenu.promptForNote(measurementSystem.measurements[index].note) else { return }
+            measurementSystem.setNote(note, forMeasurement: index)
+        case .moreDecimals(let index):
+            measurementSystem.adjustDecimals(by: 1, forMeasurement: index, base: displayPrecision)
+        case .fewerDecimals(let index):
+            measurementSystem.adjustDecimals(by: -1, forMeasurement: index, base: displayPrecision)
+        case .resetDecimals(let index):
+            measurementSystem.resetDecimals(forMeasurement: index)
+        case .toggleSelection(let index):
+            if measurementSystem.selectedMeasurements.contains(index) {
+                measurementSystem.selectedMeasurements.remove(index)
+            } else {
+                measurementSystem.selectedMeasurements.insert(index)
+            }
+        case .copyCoordinates(let position):
+            let original = originalPosition(position)
+            copyToPasteboard(String(format: "%.4f, %.4f, %.4f", original.x, original.y, original.z))
+        case .startMeasurement(let type, let point):
+            measurementSystem.startMeasurement(type: type)
+            _ = measurementSystem.addPoint(point)
+        case .constrainToAxis(let axis):
+            measurementSystem.toggleAxisConstraint(axis)
+        case .constrainAlong(let direction):
+            measurementSystem.toggleDirectionConstraint(direction)
+        case .constrainToward(let position):
+            measurementSystem.setPointConstraint(toward: position)
+        case .setOrbitTarget(let position):
+            camera.animateTarget(to: position.float3)
+        case .addAnnotation(let point):
+            guard let text = ContextMenu.promptForAnnotation(nil) else { return }
+            do {
+                try addAnnotation(text, at: point)
+            } catch {
+                print("WARNING: Failed to save annotation: \(error.localizedDescription)")
+            }
+        case .editAnnotation(let id):
+            guard let annotation = annotations.first(where: { $0.id == id }),
+                  let text = ContextMenu.promptForAnnotation(annotation.text) else { return }
+            do {
+                try editAnnotation(id, text: text)
+            } catch {
+                print("WARNING: Failed to save annotation: \(error.localizedDescription)")
+            }
+        case .deleteAnnotation(let id):
+            do {
+                try removeAnnotation(id)
+            } catch {
+                print("WARNING: Failed to save annotations: \(error.localizedDescription)")
+            }
+        }
+    }
+
+    private func copyToPasteboard(_ text: String) {
+        let pasteboard = NSPasteboard.general
+        pasteboard.clearContents()
+        pasteboard.setString(text, forType: .string)
+    }
+
+    // MARK: - Leveling Methods
+
+    /// Apply leveling rotation to the model
+    /// Rotates the model so that the two selected points become level on the chosen axis
+    func applyLevelingRotation(device: MTLDevice) throws {
+        guard let model = model,
+              let point1 = levelingState.point1,
+              let point2 = levelingState.point2,
+              let axis = levelingState.selectedAxis else {
+            print("Leveling: Missing model or points")
+            return
+        }
+
+        // Store current triangles for undo
+        levelingState.storeForUndo(model.triangles)
+
+        // Calculate rotation
+        let bbox = model.boundingBox()
+        let (rotAxis, angle) = Rotation.calculateLevelingRotation(
+            point1: point1,
+            point2: point2,
+            targetAxis: axis,
+            center: bbox.center
+        )
+
+        // Skip if no rotation needed
+        guard abs(angle) > 1e-10 else {
+            print("Leveling: Points already level on \(LevelingState.axisName(for: axis)) axis")
+            levelingState.reset()
+            return
+        }
+
+        // Apply rotation to a fresh model (precomputed bounds would go stale)
+        var newModel = STLModel(triangles: model.triangles, name: model.name, header: model.header)
+        Rotation.rotateModel(&newModel, axis: rotAxis, angle: angle, center: bbox.center)
+        try replaceModel(with: newModel, device: device)
+
+        print("Leveling: Rotated \(angle * 180 / .pi)° around \(rotAxis) to level on \(LevelingState.axisName(for: axis)) axis")
+
+        // Mark model as modified
+        isModelModified = true
+
+        // Reset leveling state but keep undo available
+        levelingState.reset()
+    }
+
+    /// Undo the last leveling rotation
+    func undoLeveling(device: MTLDevice) throws {
+        guard let previousTriangles = levelingState.previousModelTriangles else {
+            print("Leveling: Nothing to undo")
+            return
+        }
+
+        // Restore previous model
+        try replaceModel(with: STLModel(triangles: previousTriangles, name: model?.name, header: model?.header), device: device)
+
+        // Clear undo state
+        levelingState.clearUndo()
+
+        print("Leveling: Undo complete")
+    }
+
+    /// Swap in a reoriented model and regenerate everything derived from it
+    private func replaceModel(with newModel: STLModel, device: MTLDevice) throws {
+        // Chunks of a streamed model are in the previous pose; the changed proxy is shown on its own
+        self.streamingResidency = nil
+        self.streamingStatus = nil
+
+        self.model = newModel
+        self.modelGeneration += 1
+
+        // Clear caches and regenerate GPU data
+        cachedEdges = nil
+        cachedFeatureEdges = nil
+        cachedStyledEdges = nil
+        unclippedWireframeData = nil
+        try updateMeshData(device: device)
+        try updateWireframe(device: device)
+        if pcbPreset {
+            updatePCBBoard()
+        }
+        updateClearance(device: device)
+        updateComparison(device: device)
+        updateContours(device: device)
+        updateDraftAnalysis(device: device)
+        updatePrimitives(device: device)
+        updateHeightColors(device: device)
+        updateCaliper(device: device)
+        try updateGrid(device: device)
+
+        // Update model info for the new model
+        if let sourceURL = sourceFileURL {
+            modelInfo = makeModelInfo(fileName: sourceURL.lastPathComponent)
+        }
+    }
+
+    // MARK: - Alignment
+
+    /// Rotate the model so its largest spread runs along X, the next along Y and the smallest along Z
+    /// Normalizes arbitrarily rotated imports before axis-constrained measurements (undo with Undo Leveling)
+    func alignToPrincipalAxes(device: MTLDevice) throws {
+        guard let model, let aligned = ModelAlignment.alignedToPrincipalAxes(model) else { return }
+
+        levelingState.storeForUndo(model.triangles)
+        try replaceModel(with: aligned, device: device)
+        isModelModified = true
+        print("Alignment: Aligned to principal axes")
+    }
+
+    /// Rotate the model onto its largest flat face, resting on Z = 0 (file coordinates)
+    func layFlatOnLargestFace(device: MTLDevice) throws {
+        guard let model, let face = ModelAlignment.largestSupportingFace(of: model) else { return }
+        try layFlat(faceNormal: face.normal, device: device)
+        print(String(format: "Alignment: Laid flat on a %.1f mm² face", face.area))
+    }
+
+    /// Rotate the model so the flat face containing a picked triangle lies on Z = 0
+    func layFlat(onFaceOf triangleIndex: Int, device: MTLDevice) throws {
+        guard let model, let face = ModelAlignment.face(containing: triangleIndex, in: model.triangles) else { return }
+        levelingState.reset()
+        try layFlat(faceNormal: face.normal, device: device)
+        print(String(format: "Alignment: Laid flat on a picked %.1f mm² face", face.area))
+    }
+
+    /// Rotate the model so the plane fitted through the selected triangles lies on Z = 0
+    func layFlatOnSelectedTriangles(device: MTLDevice) throws {
+        guard let model else { return }
+        let selected = measurementSystem.selectedTriangles.sorted().compactMap { model.triangles.indices.contains($0) ? model.triangles[$0] : nil }
+        guard let normal = ModelAlignment.fittedPlaneNormal(of: selected) else { return }
+
+        levelingState.reset()
+        // Triangle indices stay valid, so the selection keeps marking the same surface
+        try layFlat(faceNormal: normal, device: device)
+        print("Alignment: Laid flat on a plane fitted through \(selected.count) triangles")
+    }
+
+    private func layFlat(faceNormal: Vector3, device: MTLDevice) throws {
+        guard let model else { return }
+
+        levelingState.storeForUndo(model.triangles)
+        // Z = 0 of the file is at -offset in render space
+        let flat = ModelAlignment.laidFlat(model, faceNormal: faceNormal, floorZ: -coordinateOffset.z)
+        try replaceModel(with: flat, device: device)
+        isModelModified = true
+    }
+
+    // MARK: - Text Relief
+
+    /// Emboss or engrave text on the flat face of the selected triangles (undo with Undo Leveling)
+    func addTextRelief(_ relief: TextRelief) throws {
+        guard let device = MTLCreateSystemDefaultDevice() else { return }
+        guard let model, let picked = measurementSystem.selectedTriangles.min(),
+              let face = ModelAlignment.face(containing: picked, in: model.triangles) else {
+            throw TextReliefError.notFlat
+        }
+        let result = try relief.apply(to: model, face: face)
+
+        levelingState.storeForUndo(model.triangles)
+        // Triangle indices change with the new geometry
+        measurementSystem.clearTriangleSelection()
+        try replaceModel(with: result, device: device)
+        updateSelectedTriangles()
+        isModelModified = true
+        print("Text: \(relief.mode == .emboss ? "Embossed" : "Engraved") \"\(relief.text)\" on a \(String(format: "%.1f", face.area)) mm² face")
+    }
+
+    // MARK: - Model Scale
+
+    /// Scale the model to fix a unit mistake (the file is not changed; reloads keep the scale)
+    /// Measurements are cleared: they were taken at the wrong scale
+    func applyScaleFix(_ fix: ScaleCheck.Fix, device: MTLDevice) throws {
+        try setModelScale(modelScale * fix.factor, device: device)
+        print("Scale: Applied \(fix.title), model scale now \(modelScale)")
+    }
+
+    /// Show the model at the size in the file again
+    func resetModelScale(device: MTLDevice) throws {
+        try setModelScale(1, device: device)
+    }
+
+    /// Keep the model as it is and hide the prompt
+    func dismissScaleCheck() {
+        scaleCheck = nil
+        isScaleCheckDismissed = true
+    }
+
+    private func setModelScale(_ scale: Double, device: MTLDevice) throws {
+        guard let originalModel, scale != modelScale else { return }
+        // Back to the file's size, so loadModel applies the new scale to the file geometry
+        let fileModel = originalModel.scaled(by: 1 / modelScale)
+        modelScale = scale
+        isScaleCheckDismissed = true
+        levelingState.fullReset()
+        try loadModel(fileModel, device: device)
+        isModelModified = scale != 1
+        if let sourceURL = sourceFileURL {
+            modelInfo = makeModelInfo(fileName: sourceURL.lastPathComponent)
+        }
+    }
+
+    // MARK: - Coordinate Offset
+
+    /// Whether the loaded model was moved near the origin for rendering
+    var isRecentered: Bool {
+        coordinateOffset != .zero
+    }
+
+    /// Convert a rendering-space position back to the file's original coordinates
+    func originalPosition(_ position: Vector3) -> Vector3 {
+        position + coordinateOffset
+    }
+
+    /// Convert a position in the file's original coordinates to rendering space
+    func renderPosition(_ position: Vector3) -> Vector3 {
+        position - coordinateOffset
+    }
+
+    /// The loaded model in the file's original coordinates (for saving and export)
+    var originalModel: STLModel? {
+        guard let model = model else { return nil }
+        return isRecentered ? model.translated(by: coordinateOffset) : model
+    }
+
+    /// Build model info for the currently loaded model
+    private func makeModelInfo(fileName: String) -> ModelInfo {
+        ModelInfo(fileName: fileName, model: model ?? STLModel(), coordinateOffset: coordinateOffset)
+    }
+
+    // MARK: - Save/Export Methods
+
+    /// Check if the model can be saved (has been modified and has a model)
+    var canSave: Bool {
+        model != nil && isModelModified
+    }
+
+    /// Check if "Save" should use existing file (vs requiring "Save As")
+    var hasSaveDestination: Bool {
+        // Can save directly if we have a saved URL or if source is an STL file (not OpenSCAD/3MF)
+        if savedFileURL != nil {
+            return true
+        }
+        if let sourceURL = sourceFileURL, !isOpenSCAD && !isGo3mf && !isPythonCAD {
+            return ModelFileLoader.modelExtension(of: sourceURL) == "stl"
+        }
+        return false
+    }
+
+    /// Save the model to the current file in the background
+    /// - Throws: If there is no model or no destination (use Save As); write errors go to the completion handler
+    func saveModel(completion: @escaping @MainActor (Result<URL, Error>) -> Void) throws {
+        // Determine save destination
+        let destinationURL: URL
+        if let savedURL = savedFileURL {
+            destinationURL = savedURL
+        } else if let sourceURL = sourceFileURL, !isOpenSCAD && !isGo3mf && !isPythonCAD {
+            destinationURL = sourceURL
+        } else {
+            // No valid destination - caller should use saveModelAs instead
+            throw STLExportError.writeFailure("No save destination. Use Save As.")
+        }
+
+        try writeModel(to: destinationURL) { [weak self] result in
+            if case .success = result {
+                self?.savedFileURL = destinationURL
+                self?.isModelModified = false
+                print("Saved model to: \(destinationURL.path)")
+            }
+            completion(result)
+        }
+    }
+
+    /// Save the model to a new file in the background
+    /// - Parameters:
+    ///   - url: The destination URL
+    ///   - clearingMetadata: Write a fresh header and zero attribute bytes instead of preserving the source file's
+    func saveModelAs(to url: URL, clearingMetadata: Bool = false, completion: @escaping @MainActor (Result<URL, Error>) -> Void) throws {
+        try writeModel(to: url, clearingMetadata: clearingMetadata) { [weak self] result in
+            if case .success = result, let self = self {
+                self.savedFileURL = url
+                self.isModelModified = false
+
+                // Update model info with new filename
+                self.modelInfo = self.makeModelInfo(fileName: url.lastPathComponent)
+
+                print("Saved model as: \(url.path)")
+            }
+            completion(result)
+        }
+    }
+
+    /// Write the model (in file coordinates) as binary STL in an export job
+    private func writeModel(to url: URL, clearingMetadata: Bool = false, completion: @escaping @MainActor (Result<URL, Error>) -> Void) throws {
+        guard let model = originalModel else {
+            throw STLExportError.emptyModel
+        }
+        guard streamingModel == nil else {
+            throw StreamingError.proxyOnly
+        }
+
+        jobs.submit(.export, title: "Saving \(url.lastPathComponent)...", work: { _ -> URL in
+            try STLExporter.exportBinary(model: model, to: url, clearHeader: clearingMetadata, clearAttributes: clearingMetadata)
+            return url
+        }, completion: completion)
+    }
+
+    /// Dimension the overall width, depth and height of the model (replaces earlier overall dimensions)
+    func addBoundingDimensions() {
+        guard let model else { return }
+        measurementSystem.addBoundingDimensions(for: model.boundingBox())
+    }
+
+    /// Copy measurements or selected triangles as OpenSCAD code to clipboard
+    /// - Parameter closeMesh: If true, detect open edges and add faces to close the mesh
+    func copyMeasurementsAsOpenSCAD(closeMesh: Bool = false) {
+        // If we have selected triangles, export those as polyhedron
+        if !measurementSystem.selectedTriangles.isEmpty, let model = originalModel {
+            let code = OpenSCADGenerator.generate(from: model.triangles, indices: measurementSystem.selectedTriangles, closeMesh: closeMesh)
+            OpenSCADGenerator.copyToClipboard(code)
+            let modeStr = closeMesh ? " (closed solid)" : ""
+            print("Copied \(measurementSystem.selectedTriangles.count) triangle(s) as OpenSCAD polyhedron\(modeStr) to clipboard")
+            return
+        }
+
+        // Otherwise, use selected measurements if any, otherwise use all measurements
+        let measurementsToConvert: [Measurement]
+        if !measurementSystem.selectedMeasurements.isEmpty {
+            measurementsToConvert = measurementSystem.selectedMeasurements
+                .sorted()
+                .compactMap { index in
+                    index < measurementSystem.measurements.count ? measurementSystem.measurements[index] : nil
+                }
+        } else {
+            measurementsToConvert = measurementSystem.measurements
+        }
+
+        guard !measurementsToConvert.isEmpty else {
+            print("No measurements or triangles to convert to OpenSCAD")
+            return
+        }
+
+        let code = OpenSCADGenerator.generate(from: measurementsToConvert.map { $0.translated(by: coordinateOffset) })
+        OpenSCADGenerator.copyToClipboard(code)
+        print("Copied \(measurementsToConvert.count) measurement(s) as OpenSCAD to clipboard")
+    }
+
+    /// Copy selected measurement lines as OpenSCAD polygon to clipboard
+    func copySelectedMeasurementsAsPolygon() {
+        // Get selected measurements (or all if none selected)
+        let measurementsToConvert: [Measurement]
+        if !measurementSystem.selectedMeasurements.isEmpty {
+            measurementsToConvert = measurementSystem.selectedMeasurements
+                .sorted()
+                .compactMap { index in
+                    index < measurementSystem.measurements.count ? measurementSystem.measurements[index] : nil
                 }
+        } else {
+            measurementsToConvert = measurementSystem.measurements
+        }
 
-                // Load the model directly (already has colors assigned)
-                try loadModel(result.model, device: device)
+        // Filter to only distance measurements
+        let distanceMeasurements = measurementsToConvert.filter { $0.type == .distance }
 
-                // Update file watching state
-                self.sourceFileURL = url
-                self.tempSTLFileURL = nil  // No temp file needed with direct model
-                self.isOpenSCAD = true
-                self.is2DOpenSCAD = result.is2D
-                self.isGo3mf = false
-                self.isEmptyFile = false
-                self.threeMFParseResult = nil
-                self.selectedPlateId = nil
-                self.modelInfo = ModelInfo(fileName: url.lastPathComponent, model: result.model)
+        guard !distanceMeasurements.isEmpty else {
+            print("No distance measurements to convert to polygon")
+            return
+        }
 
-                if result.is2D {
-                    print("Detected 2D OpenSCAD file, extruded to 1mm height for visualization")
-                }
-                if result.colorsExtracted > 0 {
-                    print("Extracted \(result.colorsExtracted) colors from OpenSCAD file")
-                }
-                print("Successfully loaded: \(result.model.triangleCount) triangles")
-            } catch let error as OpenSCADError {
-                // Handle OpenSCAD-specific errors
-                if case .emptyFile(let messages) = error {
-                    // Empty file - show blank view with info
-                    print("OpenSCAD file is empty: \(url.lastPathComponent)")
-                    clearModel()
-                    self.sourceFileURL = url
-                    self.tempSTLFileURL = nil
-                    self.isOpenSCAD = true
-                    self.isGo3mf = false
-                    self.isEmptyFile = true
-                    self.renderWarnings = messages
-                    self.threeMFParseResult = nil
-                    self.selectedPlateId = nil
-                    self.modelInfo = ModelInfo(fileName: url.lastPathComponent, triangleCount: 0, volume: 0, boundingBox: BoundingBox())
-                    self.isLoading = false
-                    return
-                }
-                // Store messages from other OpenSCAD errors before rethrowing
-                self.renderWarnings = error.messages
-                throw error
-            }
+        let code = OpenSCADGenerator.generatePolygon(from: distanceMeasurements)
+        OpenSCADGenerator.copyToClipboard(code)
+        print("Copied \(distanceMeasurements.count) distance measurement(s) as OpenSCAD polygon to clipboard")
+    }
 
-        } else if fileExtension == "stl" {
-            // Regular STL file
-            print("Loading STL file: \(url.lastPathComponent)")
-            var t0 = CFAbsoluteTimeGetCurrent()
-            let model = try STLParser.parse(url: url)
-            print("  STL parsing: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms (\(model.triangleCount) triangles)")
-            t0 = CFAbsoluteTimeGetCurrent()
-            try loadModel(model, device: device)
-            print("  loadModel total: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms")
+    /// Selected measurements (or all if none are selected) with their export names, in file coordinates
+    private func namedMeasurementsForExport() -> [MeasurementSet.Entry] {
+        // Name all measurements so a selection keeps the names of the full export ("Distance 3")
+        let entries = measurementSet().entries
+        guard !measurementSystem.selectedMeasurements.isEmpty else { return entries }
+        return measurementSystem.selectedMeasurements.sorted().compactMap { index in
+            index < entries.count ? entries[index] : nil
+        }
+    }
 
-            // Update file watching state
-            self.sourceFileURL = url
-            self.tempSTLFileURL = nil
-            self.isOpenSCAD = false
-            self.isGo3mf = false
-            self.renderWarnings = []
-            self.threeMFParseResult = nil
-            self.selectedPlateId = nil
-            self.modelInfo = ModelInfo(fileName: url.lastPathComponent, model: model)
+    /// Copy measurements as named OpenSCAD variables to clipboard
+    func copyMeasurementsAsOpenSCADVariables() {
+        let entries = namedMeasurementsForExport()
+        guard !entries.isEmpty else {
+            print("No measurements to convert to OpenSCAD variables")
+            return
+        }
 
-            print("Successfully loaded: \(model.triangleCount) triangles")
+        let code = OpenSCADGenerator.variables(from: entries, source: modelInfo?.fileName)
+        OpenSCADGenerator.copyToClipboard(code)
+        print("Copied \(entries.count) measurement(s) as OpenSCAD variables to clipboard")
+    }
 
-        } else if fileExtension == "3mf" {
-            // 3MF file (3D Manufacturing Format)
-            print("Loading 3MF file: \(url.lastPathComponent)")
-            let parseResult = try ThreeMFParser.parseWithPlates(url: url)
-            self.threeMFParseResult = parseResult
+    // MARK: - Hooks
 
-            // Select first plate by default, or show all if only one plate
-            let initialPlateId = parseResult.plates.first?.id
-            self.selectedPlateId = initialPlateId
+    /// Fire automation hooks for an event on the current file
+    func fireHook(_ event: HookEvent, data: [String: Any] = [:]) {
+        HookManager.shared.fire(event, file: sourceFileURL, data: data)
+    }
 
-            let model: STLModel
-            if let plateId = initialPlateId, parseResult.plates.count > 1 {
-                model = parseResult.model(forPlate: plateId)
-                print("Loaded plate \(plateId): \(parseResult.plates.first { $0.id == plateId }?.name ?? "Unknown")")
-            } else {
-                model = parseResult.modelWithAllPlates()
-            }
+    /// Hook payload describing the loaded model (original coordinates)
+    private var modelHookData: [String: Any] {
+        guard let info = modelInfo else { return [:] }
+        let size = info.dimensions
+        return [
+            "fileName": info.fileName,
+            "triangleCount": info.triangleCount,
+            "volume": info.volume,
+            "dimensions": ["x": size.x, "y": size.y, "z": size.z]
+        ]
+    }
 
-            try loadModel(model, device: device)
+    /// Hook payload for a measurement (points in original coordinates)
+    private func hookData(for measurement: Measurement) -> [String: Any] {
+        [
+            "type": measurement.label(showDiameter: measurementSystem.showDiameter),
+            "value": measurement.value,
+            "formattedValue": measurement.formattedValue(showDiameter: measurementSystem.showDiameter, precision: displayPrecision),
+            "points": measurement.points.map { point -> [String: Double] in
+                let position = originalPosition(point.position)
+                return ["x": position.x, "y": position.y, "z": position.z]
+            }
+        ]
+    }
 
-            // Update file watching state
-            self.sourceFileURL = url
-            self.tempSTLFileURL = nil
-            self.isOpenSCAD = false
-            self.isGo3mf = false
-            self.renderWarnings = []
-            self.modelInfo = ModelInfo(fileName: url.lastPathComponent, model: model)
+    // MARK: - Scripting
+
+    /// Run an analysis script against the loaded model as a cancellable job
+    /// The script works in the file's original coordinates; measurements it creates are added to the scene.
+    func runScript(url: URL, completion: @escaping @MainActor (Result<ScriptReport, Error>) -> Void) {
+        let model = originalModel
+        let generation = modelGeneration
+
+        jobs.submit(.script, title: "Running \(url.lastPathComponent)...") { job in
+            try ScriptRuntime.runInSubprocess(url: url, model: model, job: job)
+        } completion: { [weak self] result in
+            guard let self else { return }
+            if case .success(let report) = result {
+                // Measurements belong to the model the script ran on
+                if generation == self.modelGeneration {
+                    self.measurementSystem.measurements.append(contentsOf: report.measurements.map { $0.translated(by: -self.coordinateOffset) })
+                }
+                print("Ran script \(url.lastPathComponent): \(report.measurements.count) measurement(s), \(report.failures.count) failure(s)")
+                self.fireHook(.analysisCompleted, data: [
+                    "kind": "script",
+                    "title": url.lastPathComponent,
+                    "passed": report.passed,
+                    "results": report.results.map { ["name": $0.name, "value": $0.value] as [String: Any] },
+                    "failures": report.failures
+                ])
+            }
+            completion(result)
+        }
+    }
 
-            print("Successfully loaded: \(model.triangleCount) triangles (\(parseResult.plates.count) plates)")
+    // MARK: - Plugins
 
-        } else if fileExtension == "yaml" || fileExtension == "yml" {
-            // go3mf YAML configuration file - use external go3mf tool to build 3MF
-            print("Loading go3mf config: \(url.lastPathComponent)")
+    /// Run a plugin analyzer on the loaded model in the background
+    func runPluginAnalyzer(_ analyzer: PluginManifest.Analyzer, plugin: Plugin) {
+        guard let model = originalModel else { return }
+        let offset = coordinateOffset
+        pluginAnalysis = PluginAnalysis(title: analyzer.title)
 
-            let workDir = url.deletingLastPathComponent()
-            let renderer = Go3mfToolRenderer(workDir: workDir)
+        jobs.submit(.analysis, title: "Running \(analyzer.title)...") { job -> PluginAnalysis in
+            do {
+                var result = try PluginRegistry.shared.analyze(model, plugin: plugin, analyzerID: analyzer.id, job: job)
+                // Plugins work in the file's coordinates; the overlay is drawn in render space
+                result.overlay = result.overlay?.translated(by: -offset)
+                return PluginAnalysis(title: analyzer.title, result: result)
+            } catch is CancellationError {
+                throw CancellationError()
+            } catch {
+                return PluginAnalysis(title: analyzer.title, errorMessage: error.localizedDescription)
+            }
+        } completion: { [weak self] result in
+            guard let self, let analysis = try? result.get(), self.pluginAnalysis?.title == analyzer.title else { return }
+            self.pluginAnalysis = analysis
+            if let result = analysis.result {
+                self.fireHook(.analysisCompleted, data: [
+                    "kind": "plugin",
+                    "title": analyzer.title,
+                    "plugin": plugin.manifest.name,
+                    "results": result.results.map { ["name": $0.name, "value": $0.value] }
+                ])
+            }
+        }
+    }
 
-            // Create temporary 3MF file
-            let temp3MFURL = FileManager.default.temporaryDirectory
-                .appendingPathComponent("gostl_go3mf_\(Int(Date().timeIntervalSince1970)).3mf")
+    /// Close the plugin results panel, stopping the analyzer if it is still running
+    func closePluginAnalysis() {
+        jobs.cancel(.analysis)
+        pluginAnalysis = nil
+    }
 
-            try renderer.buildTo3MF(yamlFile: url, outputFile: temp3MFURL)
+    /// Cancel a job from the UI, restoring the state it would have updated
+    func cancelJob(_ job: JobQueue.Job) {
+        switch job.kind {
+        case .load:
+            cancelReload()
+        case .analysis:
+            closePluginAnalysis()
+        case .spatialIndex, .wireframe, .export, .clearance, .contours, .draft, .primitives,
+             .heightColors, .comparison, .meshQuality, .occlusion, .script:
+            jobs.cancel(id: job.id)
+        }
+    }
 
-            // Load the generated 3MF file
-            let parseResult = try ThreeMFParser.parseWithPlates(url: temp3MFURL)
-            self.threeMFParseResult = parseResult
+    // MARK: - Metadata
 
-            // Select first plate by default, or show all if only one plate
-            let initialPlateId = parseResult.plates.first?.id
-            self.selectedPlateId = initialPlateId
+    /// Re-read metadata of the loaded file
+    /// - Parameter reloadingProperties: Also re-read the sidecar (off for reloads of the same file)
+    func refreshMetadata(reloadingProperties: Bool = true) {
+        guard let url = sourceFileURL else {
+            modelMetadata = nil
+            applySidecar(ModelSidecar())
+            return
+        }
+        modelMetadata = ModelMetadata.read(from: url)
+        if reloadingProperties {
+            do {
+                applySidecar(try ModelSidecar.load(for: url))
+            } catch {
+                print("WARNING: Failed to read \(ModelSidecar.url(for: url).lastPathComponent): \(error.localizedDescription)")
+                applySidecar(ModelSidecar())
+            }
+        }
+    }
 
-            let model: STLModel
-            if let plateId = initialPlateId, parseResult.plates.count > 1 {
-                model = parseResult.model(forPlate: plateId)
-                print("Loaded plate \(plateId): \(parseResult.plates.first { $0.id == plateId }?.name ?? "Unknown")")
-            } else {
-                model = parseResult.modelWithAllPlates()
+    /// Show what the sidecar stores; saved measurements and datums only fill empty lists, so
+    /// reopening the same file keeps what is on screen
+    private func applySidecar(_ sidecar: ModelSidecar) {
+        modelProperties = sidecar.properties
+        sectionBookmarks = sidecar.sections
+        annotations = sidecar.annotations
+        savedViews = sidecar.views
+        modelUnits = sidecar.units
+
+        if measurementSystem.measurements.isEmpty, !sidecar.measurements.isEmpty {
+            measurementSystem.measurements = sidecar.measurements.map { $0.measurement.translated(by: -coordinateOffset) }
+            if let model {
+                measurementSystem.validateMeasurements(model: model, accelerator: spatialAccelerator)
             }
+            print("Restored \(sidecar.measurements.count) measurement(s) from the project")
+        }
+        if referenceGeometry.entities.isEmpty, !sidecar.datums.isEmpty {
+            referenceGeometry.restore(
+                entities: sidecar.datums.map { $0.translated(by: -coordinateOffset) },
+                measurements: sidecar.datumMeasurements
+            )
+            print("Restored \(sidecar.datums.count) datum(s) from the project")
+        }
+    }
 
-            try loadModel(model, device: device)
+    // MARK: - Project
+
+    /// Write the measurements, datums and an analysis snapshot to the sidecar (File > Save Project)
+    func saveProject() throws {
+        guard let url = sourceFileURL, let model = originalModel else { return }
+        let measurements = measurementSet().entries
+        let datums = referenceGeometry.entities.map { $0.translated(by: coordinateOffset) }
+        let datumMeasurements = referenceGeometry.measurements
+        let snapshot = AnalysisSnapshot(record: AnalyzeCommand.record(for: model, fileName: url.lastPathComponent))
+
+        try ModelSidecar.update(for: url) { sidecar in
+            sidecar.measurements = measurements
+            sidecar.datums = datums
+            sidecar.datumMeasurements = datumMeasurements
+            sidecar.addSnapshot(snapshot)
+        }
+        print("Saved project to \(ModelSidecar.url(for: url).lastPathComponent): \(measurements.count) measurement(s), \(datums.count) datum(s)")
+    }
 
-            // Update file watching state
-            self.sourceFileURL = url
-            self.tempSTLFileURL = temp3MFURL  // Store temp 3MF for cleanup
-            self.isOpenSCAD = false
-            self.isGo3mf = true
-            self.renderWarnings = []
-            self.modelInfo = ModelInfo(fileName: url.lastPathComponent, model: model)
+    /// Use a unit for the loaded file only (nil: follow Settings > Display)
+    func setModelUnits(_ scale: LengthScale?) throws {
+        let units = scale.map { ModelUnits(lengthScale: $0, lengthDecimals: modelUnits?.lengthDecimals, angleDecimals: modelUnits?.angleDecimals) }
+        modelUnits = units
+        guard let url = sourceFileURL else { return }
+        try ModelSidecar.update(for: url) { $0.units = units }
+    }
 
-            print("Successfully loaded go3mf config: \(model.triangleCount) triangles (\(parseResult.plates.count) plates)")
+    // MARK: - Saved Views
+
+    /// Save the camera under a name (replaces a view of the same name)
+    func saveView(name: String) throws {
+        let trimmed = name.trimmingCharacters(in: .whitespaces)
+        guard !trimmed.isEmpty else { return }
+        let view = ViewBookmark(name: trimmed, camera: CameraKeyframe(camera: camera, coordinateOffset: coordinateOffset))
 
+        var views = savedViews
+        if let index = views.firstIndex(where: { $0.name == trimmed }) {
+            views[index] = view
         } else {
-            throw FileLoadError.unsupportedFileType(fileExtension)
+            views.append(view)
         }
+        try saveViews(views)
+        print("Saved view '\(trimmed)'")
     }
 
-    /// Select a different plate from a 3MF file
-    func selectPlate(_ plateId: Int, device: MTLDevice) throws {
-        guard let parseResult = threeMFParseResult else {
-            print("No 3MF parse result available")
-            return
+    /// Move the camera to a saved view (flies there in presentation mode)
+    func recallView(_ view: ViewBookmark) {
+        guard model != nil else { return }
+        if isPresenting {
+            presentationViewIndex = savedViews.firstIndex { $0.id == view.id }
+            flyTo(view.camera)
+        } else {
+            view.camera.apply(to: camera, coordinateOffset: coordinateOffset)
         }
+    }
 
-        guard parseResult.plates.contains(where: { $0.id == plateId }) else {
-            print("Invalid plate ID: \(plateId)")
-            return
-        }
+    func removeView(_ id: UUID) throws {
+        try saveViews(savedViews.filter { $0.id != id })
+    }
 
-        self.selectedPlateId = plateId
-        let model = parseResult.model(forPlate: plateId)
+    /// Keep the views and write them to the sidecar (in memory only for files without a location)
+    private func saveViews(_ views: [ViewBookmark]) throws {
+        savedViews = views
+        guard let url = sourceFileURL else { return }
+        try ModelSidecar.update(for: url) { $0.views = views }
+    }
 
-        // Clear cached data for the new model
-        cachedEdges = nil
-        cachedFeatureEdges = nil
-        cachedStyledEdges = nil
-        unclippedWireframeData = nil
-        wireframeData = nil
-        gridData = nil
-        gridTextData = nil
+    /// Add a property (stored in the sidecar immediately)
+    func addModelProperty(key: String, value: String) throws {
+        try saveModelProperties(modelProperties + [ModelProperty(key: key, value: value)])
+    }
 
-        try loadModel(model, device: device, preserveCamera: false)
+    /// Change key and value of a property
+    func updateModelProperty(_ id: UUID, key: String, value: String) throws {
+        var properties = modelProperties
+        guard let index = properties.firstIndex(where: { $0.id == id }) else { return }
+        properties[index].key = key
+        properties[index].value = value
+        try saveModelProperties(properties)
+    }
 
-        // Update model info
-        if let sourceURL = sourceFileURL {
-            self.modelInfo = ModelInfo(fileName: sourceURL.lastPathComponent, model: model)
-        }
+    func removeModelProperty(_ id: UUID) throws {
+        try saveModelProperties(modelProperties.filter { $0.id != id })
+    }
 
-        let plateName = parseResult.plates.first { $0.id == plateId }?.name ?? "Unknown"
-        print("Switched to plate \(plateId): \(plateName) (\(model.triangleCount) triangles)")
+    private func saveModelProperties(_ properties: [ModelProperty]) throws {
+        guard let url = sourceFileURL else { return }
+        try ModelSidecar.update(for: url) { $0.properties = properties }
+        modelProperties = properties
     }
 
-    /// Set up file watching for the currently loaded file
-    func setupFileWatcher() throws {
-        guard let sourceURL = sourceFileURL else {
-            print("setupFileWatcher: No source file to watch")
-            return
-        }
+    // MARK: - Section Bookmarks
 
-        // Stop existing watcher first
-        fileWatcher?.stop()
-        fileWatcher = nil
+    /// Save the current slicing bounds, cutaway and camera under a name (replaces a section of the same name)
+    func saveSectionBookmark(name: String) throws {
+        let trimmed = name.trimmingCharacters(in: .whitespaces)
+        guard !trimmed.isEmpty else { return }
+        let bookmark = SectionBookmark(name: trimmed, slicingState: slicingState, camera: camera, coordinateOffset: coordinateOffset)
 
-        let watcher = FileWatcher()
-        var filesToWatch: [URL] = []
+        var bookmarks = sectionBookmarks
+        if let index = bookmarks.firstIndex(where: { $0.name == trimmed }) {
+            bookmarks[index] = bookmark
+        } else {
+            bookmarks.append(bookmark)
+        }
+        try saveSectionBookmarks(bookmarks)
+        print("Saved section '\(trimmed)': \(bookmark.summary())")
+    }
 
-        if isGo3mf {
-            // For go3mf YAML files, just watch the source file
-            // (go3mf tool handles dependency resolution internally)
-            filesToWatch = [sourceURL]
-        } else if isOpenSCAD {
-            // For OpenSCAD files, watch the source file and all dependencies
-            let workDir = sourceURL.deletingLastPathComponent()
-            let renderer = OpenSCADRenderer(workDir: workDir)
-            filesToWatch = renderer.resolveDependencies(scadFile: sourceURL)
+    /// Restore a saved section: slice bounds, fill, cutaway and camera
+    func recallSectionBookmark(_ bookmark: SectionBookmark) {
+        guard model != nil else { return }
+
+        if let bounds = bookmark.renderBounds(coordinateOffset: coordinateOffset, modelBounds: slicingState.modelBounds) {
+            // The view observes the slicing state and re-slices the mesh
+            slicingState.bounds = bounds
+            slicingState.fillCrossSections = bookmark.fillCrossSections
+            slicingState.isVisible = true
         } else {
-            // For STL/3MF files, just watch the source file
-            filesToWatch = [sourceURL]
+            slicingState.isVisible = false
         }
+        bookmark.camera.apply(to: camera, coordinateOffset: coordinateOffset)
+        camera.cutawayDepth = bookmark.cutawayDepth
+        print("Recalled section '\(bookmark.name)'")
+    }
 
-        // Set up callback for file changes
-        try watcher.watch(files: filesToWatch) { [weak self] changedFile in
-            guard let self = self else { return }
-            DispatchQueue.main.async {
-                self.reloadRequestId += 1
+    func renameSectionBookmark(_ id: UUID, to name: String) throws {
+        let trimmed = name.trimmingCharacters(in: .whitespaces)
+        var bookmarks = sectionBookmarks
+        guard !trimmed.isEmpty, let index = bookmarks.firstIndex(where: { $0.id == id }) else { return }
+        bookmarks[index].name = trimmed
+        try saveSectionBookmarks(bookmarks)
+    }
+
+    func removeSectionBookmark(_ id: UUID) throws {
+        try saveSectionBookmarks(sectionBookmarks.filter { $0.id != id })
+    }
+
+    /// Keep the bookmarks and write them to the sidecar (in memory only for files without a location)
+    private func saveSectionBookmarks(_ bookmarks: [SectionBookmark]) throws {
+        sectionBookmarks = bookmarks
+        guard let url = sourceFileURL else { return }
+        try ModelSidecar.update(for: url) { $0.sections = bookmarks }
+    }
+
+    /// The model as it looks in a saved section, rendered from the saved camera
+    func sectionImage(_ bookmark: SectionBookmark, size: Int = 1024) throws -> CGImage {
+        guard let model else { throw OffscreenRenderError.renderFailed }
+        let bbox = model.boundingBox()
+        let modelBounds = [[bbox.min.x, bbox.max.x], [bbox.min.y, bbox.max.y], [bbox.min.z, bbox.max.z]]
+
+        var triangles = model.triangles
+        if let bounds = bookmark.renderBounds(coordinateOffset: coordinateOffset, modelBounds: modelBounds) {
+            triangles = TriangleSlicer.sliceTriangles(model.triangles, bounds: bounds).triangles
+            if bookmark.fillCrossSections {
+                triangles += CrossSection.fills(model.triangles, bounds: bounds, modelBounds: modelBounds)
             }
         }
 
-        self.fileWatcher = watcher
+        let sectionCamera = Camera()
+        bookmark.camera.apply(to: sectionCamera, coordinateOffset: coordinateOffset)
+        sectionCamera.cutawayDepth = bookmark.cutawayDepth
+        sectionCamera.fitDepthRange(to: bbox)
+
+        return try OffscreenRenderer().render(
+            model: STLModel(triangles: triangles, name: model.name),
+            camera: sectionCamera,
+            size: size,
+            material: modelInfo?.material ?? .pla,
+            background: clearColor
+        )
     }
 
-    /// Open the current .scad file in OpenSCAD application
-    func openInOpenSCAD() {
-        guard let sourceURL = sourceFileURL, isOpenSCAD else {
-            print("No OpenSCAD file loaded")
+    /// Render the current view as a presentation image (background, ground shadow, depth of field) and save it as PNG
+    func exportPresentationShot(options: PresentationShotOptions, to url: URL, completion: @escaping @MainActor (Result<URL, Error>) -> Void) {
+        guard let model else {
+            completion(.failure(OffscreenRenderError.renderFailed))
             return
         }
+        let keyframe = CameraKeyframe(camera: camera, coordinateOffset: coordinateOffset)
+        let offset = coordinateOffset
+        let cutawayDepth = camera.cutawayDepth
+        let zExaggeration = camera.zExaggeration
+        let exaggerationOrigin = camera.exaggerationOrigin
+        let material = modelInfo?.material ?? .pla
+        let background = SIMD3(clearColor.x, clearColor.y, clearColor.z)
+        // Reuse the presentation bake of this model if there is one
+        let baked = occlusionShading?.generation == modelGeneration ? occlusionShading?.occlusion : nil
+        let accelerator = spatialAccelerator
+
+        jobs.submit(.export, title: "Rendering \(url.lastPathComponent)...", work: { job -> URL in
+            var occlusion: [Float]?
+            if options.ambientOcclusion {
+                occlusion = try baked ?? AmbientOcclusion.bake(triangles: model.triangles, accelerator: accelerator, job: job)
+            }
 
-        // Try to find OpenSCAD.app
-        let openSCADAppURL = URL(fileURLWithPath: "/Applications/OpenSCAD.app")
+            let shotCamera = Camera()
+            keyframe.apply(to: shotCamera, coordinateOffset: offset)
+            shotCamera.cutawayDepth = cutawayDepth
+            shotCamera.zExaggeration = zExaggeration
+            shotCamera.exaggerationOrigin = exaggerationOrigin
+            shotCamera.fitDepthRange(to: model.boundingBox())
+
+            let image = try PresentationShot.render(
+                model: model,
+                camera: shotCamera,
+                options: options,
+                material: material,
+                viewerBackground: background,
+                occlusion: occlusion,
+                renderer: OffscreenRenderer()
+            )
+            try OffscreenRenderer.pngData(image).write(to: url, options: .atomic)
+            return url
+        }, completion: completion)
+    }
 
-        if FileManager.default.fileExists(atPath: openSCADAppURL.path) {
-            // Open the file with OpenSCAD.app
-            NSWorkspace.shared.open(
-                [sourceURL],
-                withApplicationAt: openSCADAppURL,
-                configuration: NSWorkspace.OpenConfiguration()
-            ) { _, error in
-                if let error = error {
-                    print("Failed to open in OpenSCAD: \(error.localizedDescription)")
+    // MARK: - Streaming
+
+    /// Index a binary STL too large for memory in a load job, then show its proxy and page in chunks near the camera
+    private func openStreaming(_ url: URL, device: MTLDevice) {
+        print("Streaming STL file: \(url.lastPathComponent)")
+        let chunkFileURL = TempWorkspace.shared.makeURL(prefix: "stream", pathExtension: "chunks")
+
+        jobs.submit(.load, title: "Indexing \(url.lastPathComponent)...") { job in
+            try StreamingModel.build(url: url, chunkFileURL: chunkFileURL, job: job)
+        } completion: { [weak self] result in
+            guard let self else { return }
+            switch result {
+            case .success(let streaming):
+                do {
+                    self.streamingModel = streaming
+                    try self.loadModel(streaming.proxy, device: device)
+                    self.startStreaming()
+                } catch {
+                    print("ERROR: Failed to load streamed model: \(error)")
+                    self.streamingModel = nil
+                    self.isLoading = false
+                    self.loadError = error
+                    self.loadErrorID = UUID()
+                    return
                 }
+
+                self.sourceFileURL = url
+                self.tempSTLFileURL = nil
+                self.isOpenSCAD = false
+                self.isGo3mf = false
+                self.isPythonCAD = false
+                self.renderWarnings = []
+                self.threeMFParseResult = nil
+                self.selectedPlateId = nil
+                self.modelInfo = self.makeModelInfo(fileName: url.lastPathComponent)
+                self.refreshMetadata()
+                self.fireHook(.modelLoaded, data: self.modelHookData)
+
+                print("Streaming \(streaming.triangleCount) triangles in \(streaming.chunks.count) chunks (proxy: \(streaming.proxy.triangleCount) triangles)")
+            case .failure(let error):
+                try? FileManager.default.removeItem(at: chunkFileURL)
+                self.isLoading = false
+                guard !(error is CancellationError) else { return }
+                print("ERROR: Failed to index \(url.lastPathComponent): \(error)")
+                self.loadError = error
+                self.loadErrorID = UUID()
             }
-        } else {
-            // Fallback: try to open the file and let the system choose the app
-            print("OpenSCAD.app not found at /Applications/OpenSCAD.app")
-            NSWorkspace.shared.open(sourceURL)
         }
     }
 
-    /// Reload the model from the source file
-    func reloadModel(device: MTLDevice) {
-        print("reloadModel called - isLoading: \(isLoading), isPaused: \(fileWatcher?.isPaused ?? false)")
+    /// Page chunks in for the proxy that was just loaded (render coordinates of the proxy)
+    private func startStreaming() {
+        guard let streamingModel else { return }
+        streamingResidency = StreamingResidency(
+            model: streamingModel,
+            scale: modelScale,
+            offset: coordinateOffset,
+            budget: AppSettings.shared.streamingBudget * 1_048_576
+        )
+    }
+
+    /// Chunks can replace parts of the proxy only while the mesh is drawn plainly, in the proxy's triangle order
+    private var canStreamChunks: Bool {
+        guard let streamingModel, let meshData else { return false }
+        return activeSliceBounds == nil && !isOcclusionShown && !showsAnalysisColors
+            && meshData.vertexCount == streamingModel.proxy.triangleCount * 3
+    }
 
-        guard let sourceURL = sourceFileURL else {
-            print("No source file to reload")
-            return
+    /// Per-frame: page full-resolution chunks in and out around the camera
+    func updateStreaming(device: MTLDevice) {
+        guard let residency = streamingResidency else { return }
+        residency.budget = AppSettings.shared.streamingBudget * 1_048_576
+        if canStreamChunks {
+            let aspect = viewSize.height > 0 ? Float(viewSize.width / viewSize.height) : 1
+            residency.update(camera: camera, aspect: aspect, device: device, uploads: uploads, generation: modelGeneration)
         }
-
-        // If already loading, skip - the file watcher will trigger again if needed
-        if isLoading {
-            print("Reload requested but already loading - skipping")
-            return
+        let status = residency.status
+        if status != streamingStatus {
+            streamingStatus = status
         }
+    }
 
-        isLoading = true
-
-        // Pause file watcher during reload to prevent re-triggers from generated files
-        fileWatcher?.isPaused = true
-        print("Reloading model from: \(sourceURL.lastPathComponent)")
+    /// Draw calls replacing the single draw of the proxy mesh (nil: draw the mesh as a whole)
+    func streamingDraws(proxy: MeshData) -> [StreamingResidency.Draw]? {
+        guard let residency = streamingResidency, canStreamChunks else { return nil }
+        return residency.draws(proxy: proxy)
+    }
 
-        // Perform loading in background
-        Task.detached { [weak self] in
-            guard let self = self else { return }
+    // MARK: - Annotations
 
-            do {
-                var model: STLModel
-                var tempURL: URL?
+    /// Pin a text annotation to a picked point (render space)
+    func addAnnotation(_ text: String, at point: MeasurementPoint) throws {
+        let trimmed = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !trimmed.isEmpty else { return }
+        let annotation = Annotation(text: trimmed, at: point, coordinateOffset: coordinateOffset)
+        try saveAnnotations(annotations + [annotation])
+        showAnnotations = true
+        print("Annotation: \(annotation.summary)")
+    }
 
-                if self.isGo3mf {
-                    // Build go3mf YAML config using external go3mf tool
-                    let workDir = sourceURL.deletingLastPathComponent()
-                    let renderer = Go3mfToolRenderer(workDir: workDir)
+    /// Change the text of an annotation (empty text removes it)
+    func editAnnotation(_ id: UUID, text: String) throws {
+        let trimmed = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !trimmed.isEmpty else {
+            try removeAnnotation(id)
+            return
+        }
+        var edited = annotations
+        guard let index = edited.firstIndex(where: { $0.id == id }) else { return }
+        edited[index].text = trimmed
+        try saveAnnotations(edited)
+    }
 
-                    let newTempURL = FileManager.default.temporaryDirectory
-                        .appendingPathComponent("gostl_go3mf_\(Int(Date().timeIntervalSince1970)).3mf")
+    func removeAnnotation(_ id: UUID) throws {
+        try saveAnnotations(annotations.filter { $0.id != id })
+    }
 
-                    try renderer.buildTo3MF(yamlFile: sourceURL, outputFile: newTempURL)
+    func removeAllAnnotations() throws {
+        try saveAnnotations([])
+    }
 
-                    // Load the generated 3MF file (supports plates)
-                    let parseResult = try ThreeMFParser.parseWithPlates(url: newTempURL)
+    /// Keep the annotations and write them to the sidecar (in memory only for files without a location)
+    private func saveAnnotations(_ updated: [Annotation]) throws {
+        annotations = updated
+        guard let url = sourceFileURL else { return }
+        try ModelSidecar.update(for: url) { $0.annotations = updated }
+    }
 
-                    // Get model based on selected plate
-                    if let plateId = self.selectedPlateId, parseResult.plates.count > 1 {
-                        model = parseResult.model(forPlate: plateId)
-                    } else {
-                        model = parseResult.modelWithAllPlates()
-                    }
+    // MARK: - Inspection
 
-                    tempURL = newTempURL
+    /// Add a tolerance check and report its result to hooks
+    @discardableResult
+    func addToleranceCheck(_ check: ToleranceCheck) -> ToleranceCheck? {
+        guard let added = referenceGeometry.addToleranceCheck(check),
+              let result = referenceGeometry.result(for: added) else {
+            return nil
+        }
+        fireHook(.analysisCompleted, data: [
+            "kind": "tolerance",
+            "title": referenceGeometry.title(for: added),
+            "characteristic": added.characteristic.rawValue,
+            "deviation": result.deviation,
+            "tolerance": result.tolerance,
+            "passed": result.passed
+        ])
+        return added
+    }
 
-                    // Update parse result on main thread
-                    await MainActor.run {
-                        self.threeMFParseResult = parseResult
-                    }
-                } else if self.isOpenSCAD {
-                    // Render OpenSCAD with color extraction
-                    let workDir = sourceURL.deletingLastPathComponent()
-                    let renderer = OpenSCADRenderer(workDir: workDir)
-
-                    let result = try renderer.renderToColoredModel(scadFile: sourceURL)
-                    model = result.model
-                    tempURL = nil  // No temp file needed with direct model
-
-                    // Update warnings and 2D status on main thread
-                    await MainActor.run {
-                        self.renderWarnings = result.warnings
-                        self.is2DOpenSCAD = result.is2D
-                        if result.is2D {
-                            print("Detected 2D OpenSCAD file, extruded to 1mm height for visualization")
-                        }
-                        if result.colorsExtracted > 0 {
-                            print("Extracted \(result.colorsExtracted) colors from OpenSCAD file")
-                        }
-                        if !result.warnings.isEmpty {
-                            print("OpenSCAD warnings: \(result.warnings.count)")
-                        }
-                    }
-                } else {
-                    // Load STL/3MF directly
-                    let ext = sourceURL.pathExtension.lowercased()
-                    if ext == "3mf" {
-                        model = try ThreeMFParser.parse(url: sourceURL)
-                    } else {
-                        model = try STLParser.parse(url: sourceURL)
-                    }
+    /// Unique vertices of the selected triangles (render space), used as the feature of surface tolerance checks
+    func selectedSurfacePoints() -> [Vector3] {
+        guard let model = model else { return [] }
+        var seen = Set<SIMD3<Double>>()
+        var points: [Vector3] = []
+        for index in measurementSystem.selectedTriangles.sorted() where index < model.triangles.count {
+            let triangle = model.triangles[index]
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                if seen.insert(SIMD3(vertex.x, vertex.y, vertex.z)).inserted {
+                    points.append(vertex)
                 }
+            }
+        }
+        return points
+    }
 
-                // Apply loaded model on main thread
-                await MainActor.run {
-                    do {
-                        // Clean up old temp file if exists (for both OpenSCAD and go3mf)
-                        if let oldTempURL = self.tempSTLFileURL, (self.isOpenSCAD || self.isGo3mf), oldTempURL != tempURL {
-                            try? FileManager.default.removeItem(at: oldTempURL)
-                        }
+    /// Plain-text inspection report for the loaded file
+    /// - Parameter sectionImages: Image file names of the saved sections, by bookmark
+    func inspectionReport(sectionImages: [UUID: String] = [:]) -> String {
+        let fileName = modelInfo?.fileName ?? "Untitled"
+        return InspectionReport.generate(
+            fileName: fileName,
+            referenceGeometry: referenceGeometry,
+            coordinateOffset: coordinateOffset,
+            analysis: originalModel.map { AnalysisRecord(fileName: fileName, analysis: $0.analyze()) },
+            properties: modelProperties,
+            sections: sectionBookmarks.map { (bookmark: $0, imageFile: sectionImages[$0.id]) },
+            annotations: annotations,
+            precision: displayPrecision
+        )
+    }
 
-                        // Update temp file reference
-                        if let tempURL = tempURL {
-                            self.tempSTLFileURL = tempURL
-                        }
+    /// Write the inspection report to a text file, with one PNG per saved section next to it
+    /// (`<report>-section-1.png`, ...)
+    func exportInspectionReport(to url: URL) throws {
+        let baseName = url.deletingPathExtension().lastPathComponent
+        var sectionImages: [UUID: String] = [:]
+        for (index, bookmark) in sectionBookmarks.enumerated() {
+            let imageURL = url.deletingLastPathComponent().appendingPathComponent("\(baseName)-section-\(index + 1).png")
+            do {
+                try OffscreenRenderer.pngData(sectionImage(bookmark)).write(to: imageURL, options: .atomic)
+                sectionImages[bookmark.id] = imageURL.lastPathComponent
+            } catch {
+                print("WARNING: Failed to render section '\(bookmark.name)': \(error.localizedDescription)")
+            }
+        }
 
-                        // Load the new model, preserving camera position
-                        try self.loadModel(model, device: device, preserveCamera: true)
+        try inspectionReport(sectionImages: sectionImages).write(to: url, atomically: true, encoding: .utf8)
+        print("Exported inspection report with \(sectionImages.count) section image(s) to: \(url.path)")
+    }
 
-                        // Preserve the selected material from previous model info
-                        let previousMaterial = self.modelInfo?.material ?? .pla
-                        var newModelInfo = ModelInfo(fileName: sourceURL.lastPathComponent, model: model)
-                        newModelInfo.material = previousMaterial
-                        self.modelInfo = newModelInfo
-                        self.isEmptyFile = false
+    /// Copy the inspection report to the clipboard
+    func copyInspectionReport() {
+        let pasteboard = NSPasteboard.general
+        pasteboard.clearContents()
+        pasteboard.setString(inspectionReport(), forType: .string)
+        print("Copied inspection report to clipboard")
+    }
 
-                        print("Model reloaded successfully!")
-                        self.isLoading = false
-                        self.loadError = nil
-                        self.loadErrorID = nil
+    // MARK: - Measurement Comparison
 
-                        // Re-setup file watcher to catch any new dependencies
-                        // (e.g., new include/use statements added to OpenSCAD file)
-                        try? self.setupFileWatcher()
-                    } catch {
-                        print("ERROR: Failed to apply reloaded model: \(error)")
-                        self.isLoading = false
-                        self.loadError = error
-                        self.loadErrorID = UUID()
+    /// Current measurements in file coordinates, named for comparison
+    func measurementSet() -> MeasurementSet {
+        MeasurementSet(
+            fileName: modelInfo?.fileName,
+            measurements: measurementSystem.measurements.map { $0.translated(by: coordinateOffset) },
+            annotations: annotations
+        )
+    }
 
-                        // Resume file watcher
-                        self.fileWatcher?.isPaused = false
-                    }
-                }
-            } catch let error as OpenSCADError {
-                await MainActor.run {
-                    if case .emptyFile(let messages) = error {
-                        print("OpenSCAD file is empty: \(sourceURL.lastPathComponent)")
-                        self.clearModel()
-                        self.isEmptyFile = true
-                        self.renderWarnings = messages
-                        self.modelInfo = ModelInfo(fileName: sourceURL.lastPathComponent)
-                        self.isLoading = false
-                        self.loadError = nil
-                        self.loadErrorID = nil
-
-                        // Re-setup file watcher (dependencies may have changed)
-                        try? self.setupFileWatcher()
-                    } else {
-                        // Other OpenSCAD errors - show messages and error
-                        print("ERROR: Failed to reload model: \(error)")
-                        self.renderWarnings = error.messages
-                        self.isLoading = false
-                        self.loadError = error
-                        self.loadErrorID = UUID()
-
-                        // Resume file watcher
-                        self.fileWatcher?.isPaused = false
-                    }
-                }
-            } catch {
-                await MainActor.run {
-                    print("ERROR: Failed to reload model: \(error)")
-                    self.isLoading = false
-                    self.loadError = error
-                    self.loadErrorID = UUID()
+    /// Write the current measurements to a JSON file
+    func exportMeasurements(to url: URL) throws {
+        try measurementSet().write(to: url)
+        print("Exported \(measurementSystem.measurements.count) measurement(s) to: \(url.path)")
+    }
 
-                    // Resume file watcher
-                    self.fileWatcher?.isPaused = false
-                }
+    /// Write measurements as named OpenSCAD variables to a .scad file
+    func exportOpenSCADVariables(to url: URL) throws {
+        let entries = namedMeasurementsForExport()
+        let code = OpenSCADGenerator.variables(from: entries, source: modelInfo?.fileName)
+        try code.write(to: url, atomically: true, encoding: .utf8)
+        print("Exported \(entries.count) measurement(s) as OpenSCAD variables to: \(url.path)")
+    }
+
+    /// The first slicing plane that cuts into the model (file coordinates), nil while slicing is hidden
+    var sectionSketchPlane: (axis: SectionProperties.Axis, position: Double)? {
+        guard slicingState.isVisible, model != nil else { return nil }
+        for axis in SectionProperties.Axis.allCases {
+            let (bounds, limits) = (slicingState.bounds[axis.index], slicingState.modelBounds[axis.index])
+            let offset = coordinateOffset.component(axis: axis.index)
+            if bounds[1] < limits[1] {
+                return (axis, bounds[1] + offset)
+            }
+            if bounds[0] > limits[0] {
+                return (axis, bounds[0] + offset)
             }
         }
+        return nil
     }
 
-    /// Cycle to the next material type (for weight calculation)
-    func cycleMaterial() {
-        if var info = modelInfo {
-            info.material = info.material.next()
-            self.modelInfo = info
-            print("Material changed to: \(info.material.rawValue)")
+    /// Fit lines and arcs to the section at the slicing plane and write them as DXF or OpenSCAD (.scad)
+    func exportSectionSketch(to url: URL) throws {
+        guard let model = originalModel, let plane = sectionSketchPlane else {
+            throw SectionSketchError.emptyModel
+        }
+
+        let sketch = try SectionSketch(model: model, axis: plane.axis, position: plane.position)
+        if url.pathExtension.lowercased() == "scad" {
+            let fileName = modelInfo?.fileName ?? url.lastPathComponent
+            let code = OpenSCADGenerator.profileModule(sketch, moduleName: "\((fileName as NSString).deletingPathExtension)_section", source: fileName)
+            try code.write(to: url, atomically: true, encoding: .utf8)
+        } else {
+            try DXFExporter.write(sketch, to: url)
         }
+        print("Exported section sketch with \(sketch.lineCount) line(s), \(sketch.arcCount) arc(s) and \(sketch.circleCount) circle(s) to: \(url.path)")
     }
 
-    // MARK: - Leveling Methods
+    /// Export the model as an OpenSCAD polyhedron module (in file coordinates)
+    /// - Parameter decimation: Grid cell size in mm for merging vertices (nil = full resolution)
+    func exportOpenSCADPolyhedron(to url: URL, decimation: Double? = nil) throws {
+        guard let model = originalModel, !model.triangles.isEmpty else {
+            throw STLExportError.emptyModel
+        }
 
-    /// Apply leveling rotation to the model
-    /// Rotates the model so that the two selected points become level on the chosen axis
-    func applyLevelingRotation(device: MTLDevice) throws {
-        guard let model = model,
-              let point1 = levelingState.point1,
-              let point2 = levelingState.point2,
-              let axis = levelingState.selectedAxis else {
-            print("Leveling: Missing model or points")
-            return
+        let fileName = modelInfo?.fileName ?? url.lastPathComponent
+        let code = OpenSCADGenerator.polyhedronModule(from: model, fileName: fileName, decimation: decimation)
+        try code.write(to: url, atomically: true, encoding: .utf8)
+        print("Exported OpenSCAD polyhedron to: \(url.path)")
+    }
+
+    /// Whether any of the analysis and tool panels of the bottom-right stack is open
+    var hasAnalysisPanels: Bool {
+        showReferencePanel || showClippingPanel || showMetadataPanel || pluginAnalysis != nil || measurementComparison != nil
+            || clearanceModelName != nil || showDraftAnalysis || showPrimitives || caliper != nil || isProbingThickness
+            || isMeasuringFaceDistance || templateRun != nil || showCalibration || showHeightColors || showSectionBookmarks || scaleCheck != nil
+    }
+
+    /// Whether an analysis currently replaces the model's colors (draft angles, primitives or height)
+    var showsAnalysisColors: Bool {
+        showDraftAnalysis || showPrimitives || showHeightColors
+    }
+
+    /// Colors of the shown analysis, nil while it is computing or none is shown
+    private var currentAnalysisColors: VertexColors? {
+        if let draftShading, draftShading.generation == modelGeneration {
+            return draftShading.colors
+        }
+        if let primitiveShading, primitiveShading.generation == modelGeneration {
+            return primitiveShading.colors
         }
+        if let heightShading, heightShading.generation == modelGeneration {
+            return heightShading.colors
+        }
+        return nil
+    }
 
-        // Store current triangles for undo
-        levelingState.storeForUndo(model.triangles)
+    /// Export the model with the shown analysis colors baked in as vertex colors (PLY or 3MF, file coordinates)
+    func exportAnalysisColors(to url: URL) throws {
+        guard let model = originalModel, !model.triangles.isEmpty else {
+            throw ColoredMeshExportError.emptyModel
+        }
+        guard let colors = currentAnalysisColors else {
+            throw ColoredMeshExportError.colorMismatch
+        }
 
-        // Calculate rotation
-        let bbox = model.boundingBox()
-        let (rotAxis, angle) = Rotation.calculateLevelingRotation(
-            point1: point1,
-            point2: point2,
-            targetAxis: axis,
-            center: bbox.center
+        try ColoredMeshExporter.export(
+            triangles: model.triangles,
+            colors: colors,
+            materialColor: (modelInfo?.material ?? .pla).baseColor,
+            name: modelInfo?.fileName ?? url.lastPathComponent,
+            to: url
         )
+        print("Exported model with analysis colors to: \(url.path)")
+    }
 
-        // Skip if no rotation needed
-        guard abs(angle) > 1e-10 else {
-            print("Leveling: Points already level on \(LevelingState.axisName(for: axis)) axis")
-            levelingState.reset()
-            return
-        }
+    /// Re-evaluate the measurements of a baseline export on the loaded model and compare the values
+    func compareMeasurements(withBaseline url: URL) throws {
+        guard let model = originalModel else { return }
+        let baseline = try MeasurementSet.load(from: url)
+        let current = baseline.reevaluated(on: model, fileName: modelInfo?.fileName)
+        let comparison = MeasurementComparison.compare(baseline: baseline, current: current)
+        measurementComparison = comparison
+        print("Compared \(comparison.rows.count) measurement(s) with \(url.lastPathComponent): \(comparison.exceeded.count) exceed the threshold")
+        fireHook(.analysisCompleted, data: [
+            "kind": "measurementComparison",
+            "title": url.lastPathComponent,
+            "passed": comparison.exceeded.isEmpty,
+            "results": comparison.rows.map { row in
+                ["name": row.name, "baseline": row.baseline ?? NSNull(), "current": row.current ?? NSNull(), "status": row.status.rawValue] as [String: Any]
+            }
+        ])
+    }
 
-        // Apply rotation to model
-        var newModel = model
-        Rotation.rotateModel(&newModel, axis: rotAxis, angle: angle, center: bbox.center)
+    // MARK: - Links
 
-        // Update model and regenerate GPU data
-        self.model = newModel
-        cachedEdges = nil
-        cachedFeatureEdges = nil
-        cachedStyledEdges = nil
-        unclippedWireframeData = nil
-        try updateMeshData(device: device)
-        try updateWireframe(device: device)
-        try updateGrid(device: device)
+    /// gostl:// link that reopens the current file with the current view and measurements
+    func currentLink() -> GoSTLLink? {
+        guard let file = sourceFileURL else { return nil }
+        return GoSTLLink(
+            file: file,
+            camera: CameraKeyframe(camera: camera, coordinateOffset: coordinateOffset),
+            measurements: measurementSystem.measurements.map { $0.translated(by: coordinateOffset) },
+            annotations: annotations
+        )
+    }
 
-        // Update model info for the new model
-        if let sourceURL = sourceFileURL {
-            modelInfo = ModelInfo(fileName: sourceURL.lastPathComponent, model: newModel)
+    /// Copy the gostl:// link of the current view to the clipboard
+    func copyLink() {
+        guard let link = try? currentLink()?.url() else { return }
+        let pasteboard = NSPasteboard.general
+        pasteboard.clearContents()
+        pasteboard.setString(link.absoluteString, forType: .string)
+        print("Copied link: \(link.absoluteString)")
+    }
+
+    /// Show the camera and measurements of a link on the loaded model
+    func apply(_ link: GoSTLLink) {
+        link.camera?.apply(to: camera, coordinateOffset: coordinateOffset)
+        if let measurements = link.measurements {
+            measurementSystem.measurements = measurements.map { $0.translated(by: -coordinateOffset) }
         }
+    }
 
-        print("Leveling: Rotated \(angle * 180 / .pi)° around \(rotAxis) to level on \(LevelingState.axisName(for: axis)) axis")
+    // MARK: - Workspace Layouts
 
-        // Mark model as modified
-        isModelModified = true
+    /// Label size in presentation mode, relative to the normal size
+    static let presentationLabelScale: CGFloat = 1.6
 
-        // Reset leveling state but keep undo available
-        levelingState.reset()
+    var labelScale: CGFloat {
+        isPresenting ? Self.presentationLabelScale : 1
     }
 
-    /// Undo the last leveling rotation
-    func undoLeveling(device: MTLDevice) throws {
-        guard let previousTriangles = levelingState.previousModelTriangles else {
-            print("Leveling: Nothing to undo")
-            return
-        }
+    /// The panels shown right now as a layout
+    func currentLayout(name: String) -> WorkspaceLayout {
+        var layout = WorkspaceLayout(name: name)
+        layout.showModelInfo = showModelInfo
+        layout.showMeasurementLog = showMeasurementLog
+        layout.showMetadataPanel = showMetadataPanel
+        layout.showClippingPanel = showClippingPanel
+        layout.showSectionBookmarks = showSectionBookmarks
+        layout.showPerformanceHUD = performanceStats.isVisible
+        layout.showAnnotations = showAnnotations
+        layout.detachedPanels = detachedPanels
+        return layout
+    }
 
-        // Restore previous model
-        self.model = STLModel(triangles: previousTriangles, name: model?.name)
+    /// Show and hide the panels of a layout
+    /// Detached panels need their windows opened or closed, see `DetachedPanelWindows.apply`.
+    func applyLayout(_ layout: WorkspaceLayout) {
+        showModelInfo = layout.showModelInfo
+        showMeasurementLog = layout.showMeasurementLog
+        showMetadataPanel = layout.showMetadataPanel
+        showClippingPanel = layout.showClippingPanel
+        showSectionBookmarks = layout.showSectionBookmarks && model != nil
+        performanceStats.isVisible = layout.showPerformanceHUD
+        showAnnotations = layout.showAnnotations
+    }
 
-        // Clear caches and regenerate GPU data
-        cachedEdges = nil
-        cachedFeatureEdges = nil
-        cachedStyledEdges = nil
-        unclippedWireframeData = nil
-        try updateMeshData(device: device)
-        try updateWireframe(device: device)
-        try updateGrid(device: device)
+    /// Save the current panels under a name (replaces a layout of the same name)
+    func saveLayout(name: String) {
+        let trimmed = name.trimmingCharacters(in: .whitespaces)
+        guard !trimmed.isEmpty else { return }
+        let layout = currentLayout(name: trimmed)
 
-        // Update model info for the restored model
-        if let model = model, let sourceURL = sourceFileURL {
-            modelInfo = ModelInfo(fileName: sourceURL.lastPathComponent, model: model)
+        let settings = AppSettings.shared
+        if let index = settings.workspaceLayouts.firstIndex(where: { $0.name == trimmed }) {
+            settings.workspaceLayouts[index] = layout
+        } else {
+            settings.workspaceLayouts.append(layout)
         }
+        print("Saved layout '\(trimmed)'")
+    }
 
-        // Clear undo state
-        levelingState.clearUndo()
-
-        print("Leveling: Undo complete")
+    func removeLayout(_ id: UUID) {
+        AppSettings.shared.workspaceLayouts.removeAll { $0.id == id }
     }
 
-    // MARK: - Save/Export Methods
+    // MARK: - Presentation Mode
 
-    /// Check if the model can be saved (has been modified and has a model)
-    var canSave: Bool {
-        model != nil && isModelModified
+    /// Enter or leave presentation mode; leaving restores the panels shown before
+    func setPresentationMode(_ presenting: Bool) {
+        guard presenting != isPresenting else { return }
+        cameraFlight = nil
+        presentationViewIndex = nil
+
+        if presenting {
+            layoutBeforePresentation = currentLayout(name: "")
+            applyLayout(.presentation)
+            showGoToPalette = false
+            isPresenting = true
+            print("Presentation mode started (\(savedViews.count) saved view(s))")
+        } else {
+            isPresenting = false
+            if let layout = layoutBeforePresentation {
+                applyLayout(layout)
+            }
+            layoutBeforePresentation = nil
+            print("Presentation mode ended")
+        }
     }
 
-    /// Check if "Save" should use existing file (vs requiring "Save As")
-    var hasSaveDestination: Bool {
-        // Can save directly if we have a saved URL or if source is an STL file (not OpenSCAD/3MF)
-        if savedFileURL != nil {
-            return true
+    /// Per-frame: bake the ambient occlusion in the background when a presentation needs it, and
+    /// swap the mesh when the occlusion should come or go
+    func updateOcclusion(device: MTLDevice) {
+        let isWanted = isPresenting && AppSettings.shared.presentationOcclusion && model != nil
+        let isBaked = occlusionShading?.generation == modelGeneration
+        if isWanted && !isBaked && occlusionBakeGeneration != modelGeneration {
+            bakeOcclusion()
+        } else if !isWanted && occlusionBakeGeneration != nil {
+            jobs.cancel(.occlusion)
+            occlusionBakeGeneration = nil
         }
-        if let sourceURL = sourceFileURL, !isOpenSCAD && !isGo3mf {
-            let ext = sourceURL.pathExtension.lowercased()
-            return ext == "stl"
+
+        let shows = isWanted && isBaked
+        if shows != isOcclusionShown {
+            isOcclusionShown = shows
+            try? updateMeshData(device: device)
         }
-        return false
     }
 
-    /// Save the model to the current file (or prompt for Save As if no destination)
-    func saveModel() throws {
-        guard let model = model else {
-            throw STLExportError.emptyModel
+    private func bakeOcclusion() {
+        guard let model else { return }
+        let generation = modelGeneration
+        let accelerator = spatialAccelerator
+        occlusionBakeGeneration = generation
+        jobs.submit(.occlusion, title: "Baking ambient occlusion...") { job in
+            try AmbientOcclusion.bake(triangles: model.triangles, accelerator: accelerator, job: job)
+        } completion: { [weak self] result in
+            // A failed bake keeps its generation, so it is not retried every frame
+            guard let self, generation == self.modelGeneration, case .success(let occlusion) = result else { return }
+            self.occlusionShading = (generation, occlusion)
+            self.occlusionBakeGeneration = nil
+            print("Ambient occlusion: Baked \(occlusion.count / 3) triangles")
         }
+    }
 
-        // Determine save destination
-        let destinationURL: URL
-        if let savedURL = savedFileURL {
-            destinationURL = savedURL
-        } else if let sourceURL = sourceFileURL, !isOpenSCAD && !isGo3mf {
-            destinationURL = sourceURL
+    /// Fly to the next (or previous, for a negative step) saved view, wrapping around
+    func presentSavedView(step: Int) {
+        guard isPresenting, model != nil, !savedViews.isEmpty else { return }
+        let count = savedViews.count
+        let index: Int
+        if let current = presentationViewIndex {
+            index = ((current + step) % count + count) % count
         } else {
-            // No valid destination - caller should use saveModelAs instead
-            throw STLExportError.writeFailure("No save destination. Use Save As.")
+            index = step >= 0 ? 0 : count - 1
         }
+        presentationViewIndex = index
+        flyTo(savedViews[index].camera)
+    }
 
-        try STLExporter.exportBinary(model: model, to: destinationURL)
-        savedFileURL = destinationURL
-        isModelModified = false
+    /// Start a smooth camera move to a keyframe (original coordinates)
+    private func flyTo(_ keyframe: CameraKeyframe) {
+        camera.stopInertia()
+        cameraFlight = CameraFlight(from: CameraKeyframe(camera: camera, coordinateOffset: coordinateOffset), to: keyframe)
+    }
 
-        print("Saved model to: \(destinationURL.path)")
+    /// Per-frame update of a running camera flight
+    func advanceCameraFlight(deltaTime: Double) {
+        guard var flight = cameraFlight else { return }
+        flight.advance(deltaTime: deltaTime).apply(to: camera, coordinateOffset: coordinateOffset)
+        cameraFlight = flight.isComplete ? nil : flight
     }
 
-    /// Save the model to a new file
-    /// - Parameter url: The destination URL
-    func saveModelAs(to url: URL) throws {
-        guard let model = model else {
-            throw STLExportError.emptyModel
-        }
+    // MARK: - Session Recording
 
-        try STLExporter.exportBinary(model: model, to: url)
-        savedFileURL = url
-        isModelModified = false
+    var isRecordingSession: Bool {
+        sessionRecorder != nil
+    }
 
-        // Update model info with new filename
-        modelInfo = ModelInfo(fileName: url.lastPathComponent, model: model)
+    var isReplayingSession: Bool {
+        sessionPlayer != nil
+    }
 
-        print("Saved model as: \(url.path)")
+    /// Start recording camera movements and measurement changes
+    func startSessionRecording() {
+        stopSessionReplay()
+        let recorder = SessionRecorder(fileName: modelInfo?.fileName)
+        recorder.record(camera: CameraKeyframe(camera: camera, coordinateOffset: coordinateOffset))
+        recorder.record(measurements: measurementSystem.measurements.map { $0.translated(by: coordinateOffset) })
+        sessionRecorder = recorder
+        print("Started session recording")
     }
 
-    /// Copy measurements or selected triangles as OpenSCAD code to clipboard
-    /// - Parameter closeMesh: If true, detect open edges and add faces to close the mesh
-    func copyMeasurementsAsOpenSCAD(closeMesh: Bool = false) {
-        // If we have selected triangles, export those as polyhedron
-        if !measurementSystem.selectedTriangles.isEmpty, let model = model {
-            let code = OpenSCADGenerator.generate(from: model.triangles, indices: measurementSystem.selectedTriangles, closeMesh: closeMesh)
-            OpenSCADGenerator.copyToClipboard(code)
-            let modeStr = closeMesh ? " (closed solid)" : ""
-            print("Copied \(measurementSystem.selectedTriangles.count) triangle(s) as OpenSCAD polyhedron\(modeStr) to clipboard")
-            return
-        }
+    /// Stop recording; the result is kept until it is saved or a new recording starts
+    func stopSessionRecording() {
+        guard let recorder = sessionRecorder else { return }
+        let recording = recorder.finish()
+        sessionRecorder = nil
+        lastSessionRecording = recording
+        print(String(format: "Stopped session recording (%.1f s, %d camera keyframes, %d measurement changes)",
+                     recording.duration, recording.cameraKeyframes.count, recording.measurementSnapshots.count))
+    }
 
-        // Otherwise, use selected measurements if any, otherwise use all measurements
-        let measurementsToConvert: [Measurement]
-        if !measurementSystem.selectedMeasurements.isEmpty {
-            measurementsToConvert = measurementSystem.selectedMeasurements
-                .sorted()
-                .compactMap { index in
-                    index < measurementSystem.measurements.count ? measurementSystem.measurements[index] : nil
-                }
-        } else {
-            measurementsToConvert = measurementSystem.measurements
-        }
+    /// Write the last recording to a session file
+    func saveSessionRecording(to url: URL) throws {
+        guard let recording = lastSessionRecording else { return }
+        try recording.write(to: url)
+        print("Saved session recording to: \(url.path)")
+    }
 
-        guard !measurementsToConvert.isEmpty else {
-            print("No measurements or triangles to convert to OpenSCAD")
-            return
+    /// Replay a recording on screen, or render it to a video file when a URL is given
+    func replaySession(_ recording: SessionRecording, videoURL: URL? = nil) {
+        if isRecordingSession {
+            stopSessionRecording()
         }
-
-        let code = OpenSCADGenerator.generate(from: measurementsToConvert)
-        OpenSCADGenerator.copyToClipboard(code)
-        print("Copied \(measurementsToConvert.count) measurement(s) as OpenSCAD to clipboard")
+        stopSessionReplay()
+        stateBeforeReplay = (
+            CameraKeyframe(camera: camera, coordinateOffset: coordinateOffset),
+            measurementSystem.measurements.map { $0.translated(by: coordinateOffset) }
+        )
+        let player = SessionPlayer(recording: recording, videoURL: videoURL)
+        sessionPlayer = player
+        applySessionState(of: player)
     }
 
-    /// Copy selected measurement lines as OpenSCAD polygon to clipboard
-    func copySelectedMeasurementsAsPolygon() {
-        // Get selected measurements (or all if none selected)
-        let measurementsToConvert: [Measurement]
-        if !measurementSystem.selectedMeasurements.isEmpty {
-            measurementsToConvert = measurementSystem.selectedMeasurements
-                .sorted()
-                .compactMap { index in
-                    index < measurementSystem.measurements.count ? measurementSystem.measurements[index] : nil
+    /// Stop a running replay (finishing the video file if one is being written) and
+    /// bring back the view and measurements from before it
+    func stopSessionReplay() {
+        guard let player = sessionPlayer else { return }
+        sessionPlayer = nil
+        if let state = stateBeforeReplay {
+            stateBeforeReplay = nil
+            state.camera.apply(to: camera, coordinateOffset: coordinateOffset)
+            measurementSystem.measurements = state.measurements.map { $0.translated(by: -coordinateOffset) }
+        }
+        guard let writer = player.videoWriter else { return }
+        writer.finish { error in
+            if let error = error {
+                print("Failed to write session video: \(error.localizedDescription)")
+            } else {
+                print("Exported session video to: \(writer.url.path)")
+                DispatchQueue.main.async {
+                    NSWorkspace.shared.activateFileViewerSelecting([writer.url])
                 }
-        } else {
-            measurementsToConvert = measurementSystem.measurements
+            }
         }
+    }
 
-        // Filter to only distance measurements
-        let distanceMeasurements = measurementsToConvert.filter { $0.type == .distance }
+    /// Per-frame update: sample the scene while recording, drive it while replaying
+    func advanceSession(deltaTime: Double) {
+        if let recorder = sessionRecorder {
+            recorder.record(camera: CameraKeyframe(camera: camera, coordinateOffset: coordinateOffset))
+            recorder.record(measurements: measurementSystem.measurements.map { $0.translated(by: coordinateOffset) })
+        }
 
-        guard !distanceMeasurements.isEmpty else {
-            print("No distance measurements to convert to polygon")
-            return
+        if let player = sessionPlayer {
+            player.advance(deltaTime: deltaTime)
+            if player.isComplete {
+                stopSessionReplay()
+            } else {
+                applySessionState(of: player)
+            }
         }
+    }
 
-        let code = OpenSCADGenerator.generatePolygon(from: distanceMeasurements)
-        OpenSCADGenerator.copyToClipboard(code)
-        print("Copied \(distanceMeasurements.count) distance measurement(s) as OpenSCAD polygon to clipboard")
+    /// Move the camera to the replay time and show the measurements recorded up to it
+    private func applySessionState(of player: SessionPlayer) {
+        player.recording.camera(at: player.time)?.apply(to: camera, coordinateOffset: coordinateOffset)
+        if let measurements = player.takeMeasurementChange() {
+            measurementSystem.measurements = measurements.map { $0.translated(by: -coordinateOffset) }
+        }
     }
 }
 
@@ -1701,7 +5103,7 @@ enum FileLoadError: LocalizedError {
     var errorDescription: String? {
         switch self {
         case .unsupportedFileType(let ext):
-            return "Unsupported file type: .\(ext) (expected .stl, .3mf, .scad, or .yaml)"
+            return "Unsupported file type: .\(ext) (expected .stl, .3mf, .scad, .py, .yaml or .zip)"
         }
     }
 }
@@ -18,146 +18,343 @@ struct ContentView: View {
         self.fileURL = fileURL
     }
 
+    /// Status text for the session recording / replay indicator
+    private var sessionIndicatorText: String {
+        if appState.isRecordingSession {
+            return "Recording session"
+        }
+        if let player = appState.sessionPlayer {
+            let action = player.videoURL == nil ? "Replaying" : "Rendering video"
+            return String(format: "%@ %.1f / %.1f s", action, player.time, player.recording.duration)
+        }
+        return ""
+    }
+
     var body: some View {
-        GeometryReader { geometry in
-            ZStack {
-                MetalView(appState: appState)
-                    .frame(maxWidth: .infinity, maxHeight: .infinity)
-                    .clipped()
-                    .onAppear {
-                        print("DEBUG: MetalView appeared, geometry: \(geometry.size)")
+        HStack(spacing: 0) {
+            GeometryReader { geometry in
+                ZStack {
+                    MetalView(appState: appState)
+                        .frame(maxWidth: .infinity, maxHeight: .infinity)
+                        .clipped()
+                        .onAppear {
+                            print("DEBUG: MetalView appeared, geometry: \(geometry.size)")
+                        }
+
+                    // Measurement labels (in 3D space)
+                    MeasurementLabelsOverlay(
+                        measurementSystem: appState.measurementSystem,
+                        camera: appState.camera,
+                        viewSize: geometry.size,
+                        coordinateOffset: appState.coordinateOffset,
+                        precision: appState.displayPrecision,
+                        labelScale: appState.labelScale
+                    )
+
+                    // Pinned annotations with leader lines (in 3D space)
+                    AnnotationLabelsOverlay(appState: appState, viewSize: geometry.size)
+
+                    // Reference geometry names and measurement values (in 3D space)
+                    ReferenceLabelsOverlay(
+                        referenceGeometry: appState.referenceGeometry,
+                        camera: appState.camera,
+                        viewSize: geometry.size,
+                        precision: appState.displayPrecision
+                    )
+
+                    // Selection rectangle overlay
+                    SelectionRectangleOverlay(measurementSystem: appState.measurementSystem)
+
+                    // Go-to target marker (temporary)
+                    if let marker = appState.goToMarker {
+                        GoToMarkerOverlay(position: marker, camera: appState.camera, viewSize: geometry.size)
                     }
 
-                // Measurement labels (in 3D space)
-                MeasurementLabelsOverlay(
-                    measurementSystem: appState.measurementSystem,
-                    camera: appState.camera,
-                    viewSize: geometry.size
-                )
+                    // Main menu panel (top-left)
+                    if appState.showModelInfo {
+                        VStack {
+                            HStack {
+                                MainMenuPanel(appState: appState)
+                                Spacer()
+                            }
+                            Spacer()
+                        }
+                    }
 
-                // Selection rectangle overlay
-                SelectionRectangleOverlay(measurementSystem: appState.measurementSystem)
+                    // Slicing panel (bottom-right)
+                    if !appState.isPresenting && appState.slicingState.isVisible {
+                        VStack {
+                            Spacer()
+                            HStack {
+                                Spacer()
+                                VStack(alignment: .trailing, spacing: 8) {
+                                    if appState.showHeightColors {
+                                        HeightColorsPanel(
+                                            appState: appState,
+                                            onClose: {
+                                                guard let device = MTLCreateSystemDefaultDevice() else { return }
+                                                appState.setHeightColors(false, device: device)
+                                            }
+                                        )
+                                    }
+                                    if appState.showSectionBookmarks {
+                                        SectionBookmarksPanel(
+                                            appState: appState,
+                                            onClose: { appState.showSectionBookmarks = false }
+                                        )
+                                    }
+                                    SlicingPanel(slicingState: appState.slicingState)
+                                }
+                                .padding(12)
+                            }
+                        }
+                    }
 
-                // Main menu panel (top-left)
-                if appState.showModelInfo {
-                    VStack {
-                        HStack {
-                            MainMenuPanel(appState: appState)
+                    // Layer preview panel (bottom-right, replaces slicing when active)
+                    if !appState.isPresenting && appState.layerPreview.isActive {
+                        VStack {
                             Spacer()
+                            HStack {
+                                Spacer()
+                                LayerPreviewPanel(
+                                    layerPreview: appState.layerPreview,
+                                    zOffset: appState.coordinateOffset.z,
+                                    onClose: { appState.layerPreview.deactivate() }
+                                )
+                                .padding(12)
+                            }
                         }
-                        Spacer()
                     }
-                }
 
-                // Slicing panel (bottom-right)
-                if appState.slicingState.isVisible {
-                    VStack {
-                        Spacer()
-                        HStack {
+                    // Leveling panel (bottom-right, replaces slicing when active)
+                    if !appState.isPresenting && appState.levelingState.isActive {
+                        VStack {
                             Spacer()
-                            SlicingPanel(slicingState: appState.slicingState)
+                            HStack {
+                                Spacer()
+                                LevelingPanel(
+                                    levelingState: appState.levelingState,
+                                    onApply: { axis in
+                                        appState.levelingState.selectAxis(axis)
+                                        applyLeveling()
+                                    },
+                                    onCancel: {
+                                        appState.levelingState.reset()
+                                    },
+                                    onUndo: {
+                                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                                        try? appState.undoLeveling(device: device)
+                                    }
+                                )
                                 .padding(12)
+                            }
                         }
                     }
-                }
 
-                // Leveling panel (bottom-right, replaces slicing when active)
-                if appState.levelingState.isActive {
-                    VStack {
-                        Spacer()
-                        HStack {
+                    // Lay-flat face picking panel (bottom-right)
+                    if !appState.isPresenting && appState.levelingState.isPickingFace {
+                        VStack {
                             Spacer()
-                            LevelingPanel(
-                                levelingState: appState.levelingState,
-                                onApply: { axis in
-                                    appState.levelingState.selectAxis(axis)
-                                    applyLeveling()
-                                },
-                                onCancel: {
-                                    appState.levelingState.reset()
-                                },
-                                onUndo: {
-                                    guard let device = MTLCreateSystemDefaultDevice() else { return }
-                                    try? appState.undoLeveling(device: device)
-                                }
-                            )
-                            .padding(12)
+                            HStack {
+                                Spacer()
+                                LayFlatPanel(
+                                    selectedTriangleCount: appState.measurementSystem.selectedTriangles.count,
+                                    canUndo: appState.levelingState.canUndo,
+                                    onUseSelection: {
+                                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                                        try? appState.layFlatOnSelectedTriangles(device: device)
+                                    },
+                                    onCancel: {
+                                        appState.levelingState.reset()
+                                    },
+                                    onUndo: {
+                                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                                        try? appState.undoLeveling(device: device)
+                                    }
+                                )
+                                .padding(12)
+                            }
                         }
                     }
-                }
 
-                // Plate selector (bottom-center) - only shown for 3MF files with multiple plates
-                if appState.hasMultiplePlates {
-                    VStack {
-                        Spacer()
-                        PlateSelector(appState: appState)
-                            .padding(.bottom, 16)
+                    // Analysis, reference geometry and clipping planes panels (bottom-right)
+                    if !appState.isPresenting && appState.hasAnalysisPanels && !appState.detachedPanels.contains(.analysis) && !appState.slicingState.isVisible && !appState.layerPreview.isActive && !appState.levelingState.isActive && !appState.levelingState.isPickingFace {
+                        VStack {
+                            Spacer()
+                            HStack {
+                                Spacer()
+                                AnalysisPanelStack(appState: appState)
+                                    .padding(12)
+                            }
+                        }
                     }
-                }
 
-                // Warnings panel (bottom-right) - only shown when there are warnings
-                if !appState.renderWarnings.isEmpty && !appState.slicingState.isVisible && !appState.levelingState.isActive {
-                    VStack {
-                        Spacer()
-                        HStack {
+                    // Plate selector (bottom-center) - only shown for 3MF files with multiple plates
+                    if !appState.isPresenting && appState.hasMultiplePlates {
+                        VStack {
                             Spacer()
-                            WarningsPanel(warnings: appState.renderWarnings)
-                                .padding(12)
+                            PlateSelector(appState: appState)
+                                .padding(.bottom, 16)
                         }
                     }
-                }
 
-                // Loading overlay (shown while waiting for file to load)
-                if appState.isLoading {
-                    LoadingOverlay()
-                        .transition(.opacity)
-                }
+                    // Archive model picker (bottom-center) - only shown for .zip files with several models
+                    if !appState.isPresenting && appState.hasMultipleArchiveEntries {
+                        VStack {
+                            Spacer()
+                            ArchiveSelector(appState: appState)
+                                .padding(.bottom, 16)
+                        }
+                    }
 
-                // Background processing indicator (shown while spatial index or wireframe builds)
-                if (appState.isBuildingAccelerator || appState.isBuildingWireframe) && !appState.isLoading {
-                    VStack {
-                        HStack(spacing: 8) {
-                            ProgressView()
-                                .progressViewStyle(CircularProgressViewStyle())
-                                .scaleEffect(0.8)
-                            VStack(alignment: .leading, spacing: 2) {
-                                if appState.isBuildingWireframe {
-                                    Text("Building wireframe...")
-                                        .font(.caption)
-                                        .foregroundColor(.secondary)
-                                }
-                                if appState.isBuildingAccelerator {
-                                    Text("Building spatial index...")
-                                        .font(.caption)
-                                        .foregroundColor(.secondary)
-                                }
+                    // Warnings panel (bottom-right) - only shown when there are warnings
+                    if !appState.isPresenting && !appState.renderWarnings.isEmpty && !appState.detachedPanels.contains(.openSCADLog) && !appState.slicingState.isVisible && !appState.layerPreview.isActive && !appState.levelingState.isActive && !appState.levelingState.isPickingFace {
+                        VStack {
+                            Spacer()
+                            HStack {
+                                Spacer()
+                                WarningsPanel(warnings: appState.renderWarnings)
+                                    .padding(12)
                             }
                         }
-                        .padding(.horizontal, 12)
-                        .padding(.vertical, 8)
-                        .background(.ultraThinMaterial, in: RoundedRectangle(cornerRadius: 8))
                     }
-                    .frame(maxWidth: .infinity, maxHeight: .infinity, alignment: .bottom)
-                    .padding(.bottom, 50)
-                    .transition(.opacity.combined(with: .move(edge: .bottom)))
-                    .animation(.easeInOut(duration: 0.3), value: appState.isBuildingAccelerator || appState.isBuildingWireframe)
-                }
 
-                // Empty file indicator (shown when OpenSCAD file has no geometry)
-                if appState.isEmptyFile {
-                    EmptyFileOverlay(fileName: appState.modelInfo?.fileName ?? "")
-                }
+                    // Performance HUD (top-right, below the orientation cube)
+                    if !appState.isPresenting && appState.performanceStats.isVisible {
+                        VStack {
+                            HStack {
+                                Spacer()
+                                PerformanceHUD(
+                                    stats: appState.performanceStats,
+                                    modelTriangleCount: appState.model?.triangleCount ?? 0
+                                )
+                                .padding(.trailing, 12)
+                                .padding(.top, 180)
+                            }
+                            Spacer()
+                        }
+                    }
 
-                // Error overlay (shown for tool errors)
-                if let error = overlayError {
-                    ErrorOverlay(error: error) {
-                        overlayError = nil
-                        appState.loadError = nil
-                        appState.loadErrorID = nil
+                    // Mesh quality banner (top-center) - only shown when the load-time check found defects
+                    if !appState.isPresenting, let quality = appState.meshQuality, !quality.isClean {
+                        VStack {
+                            MeshQualityBanner(appState: appState, quality: quality)
+                                .padding(.top, 12)
+                                .padding(.horizontal, 12)
+                            Spacer()
+                        }
+                        .transition(.move(edge: .top).combined(with: .opacity))
+                    }
+
+                    // Streaming banner (top-center) - only shown for models too large to load as a whole
+                    if !appState.isPresenting, let streamingModel = appState.streamingModel {
+                        VStack {
+                            StreamingBanner(appState: appState, streamingModel: streamingModel)
+                                .padding(.top, 12)
+                                .padding(.horizontal, 12)
+                            Spacer()
+                        }
+                    }
+
+                    // Go-to palette (top-center)
+                    if appState.showGoToPalette {
+                        VStack {
+                            GoToPalette(appState: appState)
+                                .padding(.top, 40)
+                            Spacer()
+                        }
+                    }
+
+                    // Session recording / replay indicator (top-center)
+                    if appState.isRecordingSession || appState.isReplayingSession {
+                        VStack {
+                            HStack(spacing: 6) {
+                                Image(systemName: appState.isRecordingSession ? "record.circle.fill" : "play.circle.fill")
+                                    .foregroundColor(appState.isRecordingSession ? .red : Color(red: 0.39, green: 0.78, blue: 1.0))
+                                Text(sessionIndicatorText)
+                                    .font(.caption)
+                                    .foregroundColor(.secondary)
+                            }
+                            .padding(.horizontal, 12)
+                            .padding(.vertical, 6)
+                            .background(.ultraThinMaterial, in: RoundedRectangle(cornerRadius: 8))
+                            .padding(.top, 12)
+                            Spacer()
+                        }
+                    }
+
+                    // Hovered face or region (bottom-center)
+                    if let region = appState.hoveredRegion {
+                        VStack {
+                            Spacer()
+                            HoveredRegionInfo(region: region, precision: appState.displayPrecision)
+                                .padding(.bottom, 12)
+                        }
+                    }
+
+                    // Measurement log (bottom-left)
+                    if appState.showMeasurementLog && !appState.detachedPanels.contains(.measurementLog) {
+                        VStack {
+                            Spacer()
+                            HStack {
+                                MeasurementLogPanel(
+                                    announcer: appState.measurementAnnouncer,
+                                    onClose: { appState.showMeasurementLog = false }
+                                )
+                                .padding(12)
+                                Spacer()
+                            }
+                        }
+                    }
+
+                    // Loading overlay (shown while waiting for file to load)
+                    if appState.isLoading {
+                        if let job = appState.jobs.job(.load) {
+                            LoadingOverlay(message: job.message, progress: job.progress) {
+                                appState.cancelReload()
+                            }
+                            .transition(.opacity)
+                        } else {
+                            LoadingOverlay()
+                                .transition(.opacity)
+                        }
+                    }
+
+                    // Background processing indicator (spatial index, wireframe, analysis, exports)
+                    let backgroundJobs = appState.jobs.jobs.filter { $0.kind != .load }
+                    if !appState.isPresenting && !backgroundJobs.isEmpty && !appState.isLoading {
+                        BackgroundJobsIndicator(jobs: backgroundJobs) { job in
+                            appState.cancelJob(job)
+                        }
+                        .frame(maxWidth: .infinity, maxHeight: .infinity, alignment: .bottom)
+                        .padding(.bottom, 50)
+                        .transition(.opacity.combined(with: .move(edge: .bottom)))
+                        .animation(.easeInOut(duration: 0.3), value: backgroundJobs.map(\.id))
+                    }
+
+                    // Empty file indicator (shown when OpenSCAD file has no geometry)
+                    if appState.isEmptyFile {
+                        EmptyFileOverlay(fileName: appState.modelInfo?.fileName ?? "")
+                    }
+
+                    // Error overlay (shown for tool errors)
+                    if let error = overlayError {
+                        ErrorOverlay(error: error) {
+                            overlayError = nil
+                            appState.loadError = nil
+                            appState.loadErrorID = nil
+                        }
+                        .transition(.move(edge: .bottom).combined(with: .opacity))
                     }
-                    .transition(.move(edge: .bottom).combined(with: .opacity))
                 }
             }
+
+            // Second model side by side, seen through the same camera
+            if appState.comparisonModelName != nil {
+                Divider()
+                ComparisonPane(appState: appState)
+                    .frame(maxWidth: .infinity, maxHeight: .infinity)
+            }
         }
         .frame(minWidth: 800, minHeight: 600)
         .navigationTitle(windowTitle)
@@ -208,6 +405,8 @@ struct ContentView: View {
             }
         }
         .onDisappear {
+            DetachedPanelWindows.shared.closeAll(of: appState)
+
             // Clean up notification observers
             if let observer = notificationObserver {
                 NotificationCenter.default.removeObserver(observer)
@@ -221,7 +420,25 @@ struct ContentView: View {
         .onChange(of: appState.slicingState.bounds) { _, _ in
             updateSlicedMesh()
         }
-        .onChange(of: appState.slicingState.isVisible) { _, _ in
+        .onChange(of: appState.slicingState.isVisible) { _, isVisible in
+            if isVisible {
+                appState.layerPreview.deactivate()
+            }
+            updateSlicedMesh()
+        }
+        .onChange(of: appState.slicingState.fillCrossSections) { _, _ in
+            updateSlicedMesh()
+        }
+        .onChange(of: appState.layerPreview.isActive) { _, _ in
+            updateSlicedMesh()
+        }
+        .onChange(of: appState.layerPreview.layer) { _, _ in
+            updateSlicedMesh()
+        }
+        .onChange(of: appState.layerPreview.layerHeight) { _, _ in
+            updateSlicedMesh()
+        }
+        .onChange(of: appState.layerPreview.showsLayersBelow) { _, _ in
             updateSlicedMesh()
         }
         .onChange(of: appState.slicingState.showPlanes) { _, newValue in
@@ -310,6 +527,9 @@ struct ContentView: View {
         Task { @MainActor in
             do {
                 try appState.loadFile(url, device: device)
+                if let link = FileOpenCoordinator.shared.takeLink(for: url) {
+                    appState.apply(link)
+                }
                 windowTitle = url.lastPathComponent
 
                 // Update window title after a short delay to ensure window is ready
@@ -421,6 +641,9 @@ struct ContentView: View {
                 appState.isLoading = true
                 do {
                     try appState.loadFile(url, device: device)
+                    if let link = FileOpenCoordinator.shared.takeLink(for: url) {
+                        appState.apply(link)
+                    }
 
                     // Update window properties
                     if let window = NSApp.windows.first(where: { $0.windowNumber == capturedWindowNumber }) {
@@ -538,15 +761,39 @@ struct ErrorAlert: Identifiable {
 
 /// Loading overlay shown while waiting for file to load
 struct LoadingOverlay: View {
+    /// Step reported by the load job (e.g. "Rendering colors 2/5...")
+    var message: String?
+    /// Fraction done, nil while unknown
+    var progress: Double?
+    /// Cancel action; the button is hidden for loads that cannot be cancelled
+    var onCancel: (() -> Void)?
+
     var body: some View {
         VStack(spacing: 16) {
-            ProgressView()
-                .controlSize(.large)
-                .tint(.white)
+            if let progress = progress {
+                ProgressView(value: progress)
+                    .frame(width: 160)
+                    .tint(.white)
+            } else {
+                ProgressView()
+                    .controlSize(.large)
+                    .tint(.white)
+            }
 
             Text("Loading...")
                 .font(.system(size: 16, weight: .medium))
                 .foregroundColor(.white.opacity(0.9))
+
+            if let message = message {
+                Text(message)
+                    .font(.caption)
+                    .foregroundColor(.white.opacity(0.7))
+            }
+
+            if let onCancel = onCancel {
+                Button("Cancel", action: onCancel)
+                    .keyboardShortcut(.cancelAction)
+            }
         }
         .padding(32)
         .background(
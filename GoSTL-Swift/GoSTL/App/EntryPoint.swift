@@ -0,0 +1,20 @@
+import Foundation
+
+/// Starts the headless command-line interface for known subcommands, the viewer otherwise
+@main
+enum EntryPoint {
+    @MainActor
+    static func main() {
+        // Temporary files of this process go away on exit, those of crashed runs on the next start
+        atexit { TempWorkspace.shared.cleanUp() }
+        DispatchQueue.global(qos: .utility).async {
+            TempWorkspace.shared.removeStaleSessions()
+        }
+
+        if GoSTLCommand.handles(CommandLine.arguments) {
+            GoSTLCommand.main()
+        } else {
+            GoSTLApp.main()
+        }
+    }
+}
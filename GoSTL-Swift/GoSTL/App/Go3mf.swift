@@ -19,6 +19,9 @@ enum Go3mfError: LocalizedError {
 class Go3mfToolRenderer {
     private let workDir: URL
 
+    /// Background job the build runs in; cancelling it terminates go3mf
+    var job: JobContext?
+
     /// Initialize renderer with a working directory
     init(workDir: URL) {
         self.workDir = workDir
@@ -126,8 +129,7 @@ class Go3mfToolRenderer {
         process.standardOutput = stdoutPipe
         process.standardError = stderrPipe
 
-        try process.run()
-        process.waitUntilExit()
+        try JobContext.run(process, in: job)
 
         if process.terminationStatus != 0 {
             let stderrData = stderrPipe.fileHandleForReading.readDataToEndOfFile()
@@ -82,6 +82,53 @@ final class FileOpenCoordinator {
         }
     }
 
+    /// Download an http(s) or s3 URL in the background and open the cached copy
+    func openRemote(_ url: URL) {
+        Task.detached {
+            do {
+                let (file, _) = try RemoteFileCache.shared.fetch(url)
+                await self.addFile(file)
+            } catch {
+                await Self.showOpenError(error)
+            }
+        }
+    }
+
+    static func showOpenError(_ error: Error) {
+        let alert = NSAlert()
+        alert.messageText = "Cannot Open URL"
+        alert.informativeText = error.localizedDescription
+        alert.alertStyle = .warning
+        alert.addButton(withTitle: "OK")
+        alert.runModal()
+    }
+
+    /// Views requested by gostl:// links, applied once their file has loaded
+    private var pendingLinks: [URL: GoSTLLink] = [:]
+
+    /// Called from application(_:open:) for gostl:// links
+    func openLink(_ link: GoSTLLink) {
+        let file = link.file.standardizedFileURL
+        pendingLinks[file] = link
+
+        // Reload in the window already showing the file, so the link's view replaces the current one
+        if let window = NSApp.windows.first(where: { $0.representedURL?.standardizedFileURL == file }) {
+            window.makeKeyAndOrderFront(nil)
+            NotificationCenter.default.post(
+                name: NSNotification.Name("LoadFileInWindow"),
+                object: link.file,
+                userInfo: ["windowNumber": window.windowNumber]
+            )
+            return
+        }
+        addFile(link.file)
+    }
+
+    /// The pending link for a file that has just loaded
+    func takeLink(for url: URL) -> GoSTLLink? {
+        pendingLinks.removeValue(forKey: url.standardizedFileURL)
+    }
+
     /// Called from ContentView.onAppear to get the initial file (if any)
     /// Returns immediately if launch is complete, otherwise waits
     func claimInitialFile() async -> URL? {
@@ -178,13 +225,25 @@ class AppDelegate: NSObject, NSApplicationDelegate {
             print("DEBUG: Detected open-documents Apple Event")
             FileOpenCoordinator.shared.setExpectingFiles()
         }
+        // gostl:// links arrive as get-URL events
+        if let appleEvent = NSAppleEventManager.shared().currentAppleEvent,
+           appleEvent.eventClass == AEEventClass(kInternetEventClass),
+           appleEvent.eventID == AEEventID(kAEGetURL) {
+            print("DEBUG: Detected get-URL Apple Event")
+            FileOpenCoordinator.shared.setExpectingFiles()
+        }
 
         // Parse command line arguments
         for arg in CommandLine.arguments.dropFirst() {
             if arg.hasPrefix("-") { continue }
+            if let remote = RemoteFileCache.remoteURL(arg) {
+                // Opens in the first window once downloaded
+                FileOpenCoordinator.shared.openRemote(remote)
+                break
+            }
             let url = URL(fileURLWithPath: arg)
-            let ext = url.pathExtension.lowercased()
-            if ["stl", "3mf", "scad", "yaml", "yml"].contains(ext) && FileManager.default.fileExists(atPath: url.path) {
+            let ext = ModelFileLoader.modelExtension(of: url)
+            if ModelFileLoader.viewerExtensions.contains(ext) && FileManager.default.fileExists(atPath: url.path) {
                 AppDelegate.commandLineFileURL = url
                 print("DEBUG: Command line file: \(url.lastPathComponent)")
                 break
@@ -223,16 +282,34 @@ class AppDelegate: NSObject, NSApplicationDelegate {
     func application(_ application: NSApplication, open urls: [URL]) {
         print("DEBUG: application(_:open:) called with \(urls.count) files")
         for url in urls {
-            let ext = url.pathExtension.lowercased()
-            guard ["stl", "3mf", "scad", "yaml", "yml"].contains(ext) else { continue }
+            if url.scheme?.lowercased() == GoSTLLink.scheme {
+                openLink(url)
+                continue
+            }
+            let ext = ModelFileLoader.modelExtension(of: url)
+            guard ModelFileLoader.viewerExtensions.contains(ext) else { continue }
             FileOpenCoordinator.shared.addFile(url)
         }
     }
 
+    /// Open a gostl:// link, reporting malformed links
+    private func openLink(_ url: URL) {
+        do {
+            FileOpenCoordinator.shared.openLink(try GoSTLLink(url: url))
+        } catch {
+            let alert = NSAlert()
+            alert.messageText = "Cannot Open Link"
+            alert.informativeText = error.localizedDescription
+            alert.alertStyle = .warning
+            alert.addButton(withTitle: "OK")
+            alert.runModal()
+        }
+    }
+
     private func configureAllWindows() {
         for window in NSApp.windows {
-            // Skip non-standard windows
-            guard !window.isSheet, window.styleMask.contains(.titled) else { continue }
+            // Skip non-standard windows and detached panels
+            guard !window.isSheet, window.styleMask.contains(.titled), !(window is DetachedPanelWindow) else { continue }
 
             // Ensure consistent tabbingIdentifier
             if window.tabbingIdentifier != "GoSTLWindow" {
@@ -252,7 +329,6 @@ class AppDelegate: NSObject, NSApplicationDelegate {
     }
 }
 
-@main
 struct GoSTLApp: App {
     @NSApplicationDelegateAdaptor(AppDelegate.self) var appDelegate
     @FocusedValue(\.appState) private var appState
@@ -287,6 +363,10 @@ struct GoSTLApp: App {
                 }
                 .keyboardShortcut("o", modifiers: .command)
 
+                Button("Open URL...") {
+                    openRemoteFile()
+                }
+
                 Divider()
 
                 Menu("Open Recent") {
@@ -319,6 +399,62 @@ struct GoSTLApp: App {
                 .keyboardShortcut("s", modifiers: [.command, .shift])
                 .disabled(appState?.model == nil)
 
+                Button("Save As Without STL Header/Attributes...") {
+                    saveFileAs(clearingMetadata: true)
+                }
+                .disabled(appState?.model == nil)
+
+                Button("Save Project") {
+                    saveProject()
+                }
+                .keyboardShortcut("s", modifiers: [.command, .option])
+                .disabled(appState?.model == nil || appState?.sourceFileURL == nil)
+
+                Button("Export Inspection Report...") {
+                    exportInspectionReport()
+                }
+                .disabled(appState?.model == nil)
+
+                Button("Export Measurements...") {
+                    exportMeasurements()
+                }
+                .disabled(appState?.measurementSystem.measurements.isEmpty != false)
+
+                Button("Export Measurements as OpenSCAD Variables...") {
+                    exportOpenSCADVariables()
+                }
+                .disabled(appState?.measurementSystem.measurements.isEmpty != false)
+
+                Button("Export Outline as SVG...") {
+                    exportOutlineSVG()
+                }
+                .disabled(appState?.model == nil)
+
+                Button("Export as OpenSCAD Polyhedron...") {
+                    exportOpenSCADPolyhedron()
+                }
+                .disabled(appState?.model == nil)
+
+                Button("Export Section Sketch...") {
+                    exportSectionSketch()
+                }
+                .disabled(appState?.sectionSketchPlane == nil)
+
+                Button("Export with Analysis Colors...") {
+                    exportAnalysisColors()
+                }
+                .disabled(appState?.showsAnalysisColors != true)
+
+                Button("Export View Image...") {
+                    exportViewImage()
+                }
+                .disabled(appState?.model == nil)
+
+                Button("Copy Link to View") {
+                    appState?.copyLink()
+                }
+                .disabled(appState?.sourceFileURL == nil)
+
                 Divider()
 
                 Button("Reload") {
@@ -335,6 +471,12 @@ struct GoSTLApp: App {
                 ))
                 .keyboardShortcut("i", modifiers: .command)
 
+                Toggle("Metadata", isOn: Binding(
+                    get: { appState?.showMetadataPanel ?? false },
+                    set: { appState?.showMetadataPanel = $0 }
+                ))
+                .keyboardShortcut("i", modifiers: [.command, .option])
+
                 Menu("Wireframe") {
                     Button("Off") {
                         NotificationCenter.default.post(name: NSNotification.Name("SetWireframeMode"), object: WireframeMode.off)
@@ -358,6 +500,26 @@ struct GoSTLApp: App {
                 ))
                 .keyboardShortcut("f", modifiers: [.command, .shift])
 
+                Toggle("Outline Mode", isOn: Binding(
+                    get: { appState?.outlineMode ?? false },
+                    set: { appState?.outlineMode = $0 }
+                ))
+                .keyboardShortcut("o", modifiers: [.command, .shift])
+
+                Menu("Highlight Under Cursor") {
+                    Toggle("Off", isOn: Binding(
+                        get: { appState?.hoverHighlight == nil },
+                        set: { if $0 { appState?.hoverHighlight = nil } }
+                    ))
+                    ForEach(SurfaceRegion.Kind.allCases) { kind in
+                        Toggle(kind.title, isOn: Binding(
+                            get: { appState?.hoverHighlight == kind },
+                            set: { appState?.hoverHighlight = $0 ? kind : nil }
+                        ))
+                    }
+                }
+                .disabled(appState == nil)
+
                 Divider()
 
                 Menu("Grid") {
@@ -447,6 +609,139 @@ struct GoSTLApp: App {
                 ))
                 .keyboardShortcut("x", modifiers: [.command, .shift])
 
+                Toggle("Layer Preview", isOn: Binding(
+                    get: { appState?.layerPreview.isActive ?? false },
+                    set: { _ in appState?.toggleLayerPreview() }
+                ))
+                .keyboardShortcut("y", modifiers: [.command, .shift])
+                .disabled(appState?.model == nil)
+
+                Toggle("Section Bookmarks", isOn: Binding(
+                    get: { appState?.showSectionBookmarks ?? false },
+                    set: { appState?.showSectionBookmarks = $0 }
+                ))
+                .disabled(appState?.model == nil)
+
+                Menu("Saved Views") {
+                    Button("Save Current View...") {
+                        saveView()
+                    }
+
+                    if let views = appState?.savedViews, !views.isEmpty {
+                        Divider()
+                        ForEach(views) { view in
+                            Button(view.name) {
+                                appState?.recallView(view)
+                            }
+                        }
+                        Divider()
+                        Menu("Remove") {
+                            ForEach(views) { view in
+                                Button(view.name) {
+                                    perform { try appState?.removeView(view.id) }
+                                }
+                            }
+                        }
+                    }
+                }
+                .disabled(appState?.model == nil)
+
+                Menu("Layouts") {
+                    Button("Save Current Layout...") {
+                        saveLayout()
+                    }
+
+                    let layouts = AppSettings.shared.workspaceLayouts
+                    if !layouts.isEmpty {
+                        Divider()
+                        ForEach(layouts) { layout in
+                            Button(layout.name) {
+                                applyLayout(layout)
+                            }
+                        }
+                        Divider()
+                        Menu("Remove") {
+                            ForEach(layouts) { layout in
+                                Button(layout.name) {
+                                    appState?.removeLayout(layout.id)
+                                }
+                            }
+                        }
+                    }
+                }
+                .disabled(appState == nil)
+
+                Toggle("Presentation Mode", isOn: Binding(
+                    get: { appState?.isPresenting ?? false },
+                    set: { setPresentationMode($0) }
+                ))
+                .keyboardShortcut("p", modifiers: [.command, .option])
+                .disabled(appState?.model == nil)
+
+                Toggle("Ambient Occlusion in Presentations", isOn: Binding(
+                    get: { AppSettings.shared.presentationOcclusion },
+                    set: { AppSettings.shared.presentationOcclusion = $0 }
+                ))
+
+                Menu("Units for This Model") {
+                    Toggle("Use Display Settings", isOn: Binding(
+                        get: { appState?.modelUnits == nil },
+                        set: { if $0 { perform { try appState?.setModelUnits(nil) } } }
+                    ))
+                    Divider()
+                    ForEach(LengthScale.allCases, id: \.self) { scale in
+                        Toggle(scale.displayName, isOn: Binding(
+                            get: { appState?.modelUnits?.lengthScale == scale },
+                            set: { if $0 { perform { try appState?.setModelUnits(scale) } } }
+                        ))
+                    }
+                }
+                .disabled(appState?.model == nil)
+
+                Toggle("PCB Preset", isOn: Binding(
+                    get: { appState?.pcbPreset ?? false },
+                    set: { _ in NotificationCenter.default.post(name: NSNotification.Name("TogglePCBPreset"), object: nil) }
+                ))
+                .keyboardShortcut("b", modifiers: [.command, .shift])
+                .disabled(appState?.model == nil)
+
+                Toggle("Contour Lines", isOn: Binding(
+                    get: { appState?.showContourLines ?? false },
+                    set: { _ in NotificationCenter.default.post(name: NSNotification.Name("ToggleContourLines"), object: nil) }
+                ))
+                .disabled(appState?.model == nil)
+
+                Menu("Contour Interval") {
+                    Toggle("Auto", isOn: Binding(
+                        get: { appState?.contourIntervalSetting == nil },
+                        set: { if $0 { NotificationCenter.default.post(name: NSNotification.Name("SetContourInterval"), object: nil) } }
+                    ))
+                    Divider()
+                    ForEach(ContourLines.intervalOptions, id: \.self) { interval in
+                        Toggle(String(format: "%g mm", interval), isOn: Binding(
+                            get: { appState?.contourIntervalSetting == interval },
+                            set: { if $0 { NotificationCenter.default.post(name: NSNotification.Name("SetContourInterval"), object: NSNumber(value: interval)) } }
+                        ))
+                    }
+                }
+                .disabled(appState?.model == nil)
+
+                Toggle("Color by Height", isOn: Binding(
+                    get: { appState?.showHeightColors ?? false },
+                    set: { _ in NotificationCenter.default.post(name: NSNotification.Name("ToggleHeightColors"), object: nil) }
+                ))
+                .disabled(appState?.model == nil)
+
+                Menu("Height Axis") {
+                    ForEach(HeightColors.Axis.allCases, id: \.self) { axis in
+                        Toggle(axis.displayName, isOn: Binding(
+                            get: { appState?.heightColorAxis == axis },
+                            set: { if $0 { NotificationCenter.default.post(name: NSNotification.Name("SetHeightColorAxis"), object: axis.rawValue) } }
+                        ))
+                    }
+                }
+                .disabled(appState?.model == nil)
+
                 Divider()
 
                 Toggle("Show Diameter", isOn: Binding(
@@ -454,6 +749,41 @@ struct GoSTLApp: App {
                     set: { appState?.measurementSystem.showDiameter = $0 }
                 ))
 
+                Toggle("Performance HUD", isOn: Binding(
+                    get: { appState?.performanceStats.isVisible ?? false },
+                    set: { appState?.performanceStats.isVisible = $0 }
+                ))
+                .keyboardShortcut("p", modifiers: [.command, .shift])
+
+                Toggle("Measurement Log", isOn: Binding(
+                    get: { appState?.showMeasurementLog ?? false },
+                    set: { appState?.showMeasurementLog = $0 }
+                ))
+                .keyboardShortcut("l", modifiers: [.command, .shift])
+
+                Menu("Detach Panels") {
+                    ForEach(DetachedPanel.allCases, id: \.self) { panel in
+                        Toggle(panel.title, isOn: Binding(
+                            get: { appState?.detachedPanels.contains(panel) ?? false },
+                            set: { detached in
+                                guard let appState else { return }
+                                DetachedPanelWindows.shared.setDetached(detached, panel, appState: appState)
+                            }
+                        ))
+                    }
+                }
+                .disabled(appState == nil)
+
+                Toggle("Announce Measurements", isOn: Binding(
+                    get: { appState?.measurementAnnouncer.isEnabled ?? false },
+                    set: { appState?.measurementAnnouncer.isEnabled = $0 }
+                ))
+
+                Toggle("Annotations", isOn: Binding(
+                    get: { appState?.showAnnotations ?? true },
+                    set: { appState?.showAnnotations = $0 }
+                ))
+
                 Divider()
 
                 Menu("Camera") {
@@ -494,6 +824,22 @@ struct GoSTLApp: App {
                     }
                     .keyboardShortcut("0", modifiers: .command)
                 }
+
+                Toggle("Clipping Planes", isOn: Binding(
+                    get: { appState?.showClippingPanel ?? false },
+                    set: { appState?.showClippingPanel = $0 }
+                ))
+
+                Toggle("Cutaway Follows Camera", isOn: Binding(
+                    get: { appState?.camera.cutawayDepth != nil },
+                    set: { _ in appState?.toggleCutaway() }
+                ))
+                .keyboardShortcut("u", modifiers: [.command, .shift])
+
+                Button("Go to...") {
+                    appState?.showGoToPalette = true
+                }
+                .keyboardShortcut("g", modifiers: [.command, .shift])
             }
 
             CommandMenu("Tools") {
@@ -511,6 +857,12 @@ struct GoSTLApp: App {
                     NotificationCenter.default.post(name: NSNotification.Name("StartMeasurement"), object: MeasurementType.radius)
                 }
 
+                Button("Dimension Bounding Box") {
+                    appState?.addBoundingDimensions()
+                }
+                .keyboardShortcut("b", modifiers: [])
+                .disabled(appState?.model == nil)
+
                 Divider()
 
                 Button("Select Triangles") {
@@ -518,6 +870,233 @@ struct GoSTLApp: App {
                 }
                 .keyboardShortcut("t", modifiers: [])
 
+                Toggle("Snap to Grid", isOn: Binding(
+                    get: { appState?.measurementSystem.gridSnapEnabled ?? false },
+                    set: { appState?.measurementSystem.gridSnapEnabled = $0 }
+                ))
+                .keyboardShortcut("s", modifiers: [])
+
+                Toggle("Refine Picks", isOn: Binding(
+                    get: { appState?.measurementSystem.refinePicks ?? false },
+                    set: { appState?.measurementSystem.refinePicks = $0 }
+                ))
+
+                Menu("Snap To") {
+                    ForEach(SnapTarget.allCases) { target in
+                        Toggle(target.title, isOn: Binding(
+                            get: { appState?.measurementSystem.snapTargets.contains(target) ?? false },
+                            set: { _ in appState?.measurementSystem.toggleSnapTarget(target) }
+                        ))
+                    }
+                }
+                .disabled(appState == nil)
+
+                Toggle("Magnifier", isOn: Binding(
+                    get: { AppSettings.shared.showMagnifier },
+                    set: { AppSettings.shared.showMagnifier = $0 }
+                ))
+                .keyboardShortcut("m", modifiers: [.command, .shift])
+
+                Divider()
+
+                Toggle("Reference Geometry", isOn: Binding(
+                    get: { appState?.showReferencePanel ?? false },
+                    set: { appState?.showReferencePanel = $0 }
+                ))
+                .keyboardShortcut("r", modifiers: [.command, .shift])
+
+                Button("Run Script...") {
+                    runScript()
+                }
+                .disabled(appState?.model == nil)
+
+                Menu("Plugin Analyzers") {
+                    ForEach(Array(PluginRegistry.shared.analyzers.enumerated()), id: \.offset) { _, entry in
+                        Button(entry.analyzer.title) {
+                            appState?.runPluginAnalyzer(entry.analyzer, plugin: entry.plugin)
+                        }
+                    }
+                }
+                .disabled(appState?.model == nil || PluginRegistry.shared.analyzers.isEmpty)
+
+                Button("Compare Measurements...") {
+                    compareMeasurements()
+                }
+                .disabled(appState?.model == nil)
+
+                Menu("Batch Templates") {
+                    Button("New Template...") {
+                        editTemplate(nil)
+                    }
+
+                    let templates = AppSettings.shared.measurementTemplates
+                    if !templates.isEmpty {
+                        Divider()
+                        ForEach(templates) { template in
+                            Button("Apply \(template.name)") {
+                                appState?.startTemplateRun(template)
+                            }
+                            .disabled(appState?.model == nil)
+                        }
+                        Divider()
+                        Menu("Edit") {
+                            ForEach(templates) { template in
+                                Button(template.name) {
+                                    editTemplate(template)
+                                }
+                            }
+                        }
+                        Menu("Remove") {
+                            ForEach(templates) { template in
+                                Button(template.name) {
+                                    appState?.removeTemplate(template.id)
+                                }
+                            }
+                        }
+                    }
+
+                    Divider()
+
+                    Button("Export Batch Results...") {
+                        exportTemplateResults()
+                    }
+                    .disabled(appState?.templateRun?.parts.isEmpty ?? true)
+
+                    Button("End Batch") {
+                        appState?.endTemplateRun()
+                    }
+                    .disabled(appState?.templateRun == nil)
+                }
+                .disabled(appState == nil)
+
+                Button("Check Clearance Against...") {
+                    chooseClearanceModel()
+                }
+                .disabled(appState?.model == nil)
+
+                Button("Remove Clearance Model") {
+                    appState?.removeClearanceModel()
+                }
+                .disabled(appState?.clearanceModelName == nil)
+
+                Button("Compare Side by Side...") {
+                    chooseComparisonModel()
+                }
+                .disabled(appState?.model == nil)
+
+                Toggle("Register Comparison", isOn: Binding(
+                    get: { appState?.registerComparison ?? true },
+                    set: { _ in NotificationCenter.default.post(name: NSNotification.Name("ToggleComparisonRegistration"), object: nil) }
+                ))
+                .disabled(appState?.comparisonModelName == nil)
+
+                Button("Close Comparison") {
+                    appState?.closeComparison()
+                }
+                .disabled(appState?.comparisonModelName == nil)
+
+                Toggle("Draft Analysis", isOn: Binding(
+                    get: { appState?.showDraftAnalysis ?? false },
+                    set: { _ in NotificationCenter.default.post(name: NSNotification.Name("ToggleDraftAnalysis"), object: nil) }
+                ))
+                .disabled(appState?.model == nil)
+
+                Menu("Pull Direction") {
+                    ForEach(DraftAnalysis.PullDirection.allCases, id: \.self) { direction in
+                        Toggle(direction.displayName, isOn: Binding(
+                            get: { appState?.draftPullDirection == direction },
+                            set: { if $0 { NotificationCenter.default.post(name: NSNotification.Name("SetDraftPullDirection"), object: direction.rawValue) } }
+                        ))
+                    }
+                }
+                .disabled(appState?.model == nil)
+
+                Menu("Minimum Draft") {
+                    ForEach(DraftAnalysis.minimumAngleOptions, id: \.self) { angle in
+                        Toggle(String(format: "%g°", angle), isOn: Binding(
+                            get: { appState?.draftMinimumAngle == angle },
+                            set: { if $0 { NotificationCenter.default.post(name: NSNotification.Name("SetDraftMinimumAngle"), object: NSNumber(value: angle)) } }
+                        ))
+                    }
+                }
+                .disabled(appState?.model == nil)
+
+                Toggle("Primitive Surfaces", isOn: Binding(
+                    get: { appState?.showPrimitives ?? false },
+                    set: { _ in NotificationCenter.default.post(name: NSNotification.Name("TogglePrimitives"), object: nil) }
+                ))
+                .disabled(appState?.model == nil)
+
+                Toggle("Thickness Probe", isOn: Binding(
+                    get: { appState?.isProbingThickness ?? false },
+                    set: { appState?.setThicknessProbe($0) }
+                ))
+                .disabled(appState?.model == nil)
+
+                Toggle("Face Distance", isOn: Binding(
+                    get: { appState?.isMeasuringFaceDistance ?? false },
+                    set: { appState?.setFaceDistanceTool($0) }
+                ))
+                .disabled(appState?.model == nil)
+
+                Toggle("Calibration Check", isOn: Binding(
+                    get: { appState?.showCalibration ?? false },
+                    set: { appState?.setCalibration($0) }
+                ))
+                .disabled(appState?.model == nil)
+
+                Menu("Caliper") {
+                    ForEach(["x", "y", "z"], id: \.self) { axis in
+                        Button("Across \(axis.uppercased())") {
+                            NotificationCenter.default.post(name: NSNotification.Name("ShowCaliper"), object: axis)
+                        }
+                    }
+
+                    Button("Across Selected Face") {
+                        NotificationCenter.default.post(name: NSNotification.Name("ShowCaliper"), object: nil)
+                    }
+                    .disabled(appState?.measurementSystem.selectedTriangles.isEmpty ?? true)
+
+                    Divider()
+
+                    Button("Hide Caliper") {
+                        appState?.hideCaliper()
+                    }
+                    .disabled(appState?.caliper == nil)
+                }
+                .disabled(appState?.model == nil)
+
+                Menu("Session Recording") {
+                    Button(appState?.isRecordingSession == true ? "Stop Recording" : "Start Recording") {
+                        if appState?.isRecordingSession == true {
+                            appState?.stopSessionRecording()
+                        } else {
+                            appState?.startSessionRecording()
+                        }
+                    }
+
+                    Button("Save Recording...") {
+                        saveSessionRecording()
+                    }
+                    .disabled(appState?.lastSessionRecording == nil)
+
+                    Divider()
+
+                    Button("Replay Session...") {
+                        replaySession(toVideo: false)
+                    }
+
+                    Button("Replay Session to Video...") {
+                        replaySession(toVideo: true)
+                    }
+
+                    Button("Stop Replay") {
+                        appState?.stopSessionReplay()
+                    }
+                    .disabled(appState?.isReplayingSession != true)
+                }
+                .disabled(appState?.model == nil)
+
                 Divider()
 
                 Button("Level Object") {
@@ -525,11 +1104,52 @@ struct GoSTLApp: App {
                 }
                 .keyboardShortcut("l", modifiers: .command)
 
+                Button("Align to Principal Axes") {
+                    NotificationCenter.default.post(name: NSNotification.Name("AlignPrincipalAxes"), object: nil)
+                }
+                .disabled(appState?.model == nil)
+
+                Button("Lay Flat on Largest Face") {
+                    NotificationCenter.default.post(name: NSNotification.Name("LayFlatOnLargestFace"), object: nil)
+                }
+                .disabled(appState?.model == nil)
+
+                Button("Lay Flat on Face...") {
+                    NotificationCenter.default.post(name: NSNotification.Name("StartLayFlatPick"), object: nil)
+                }
+                .disabled(appState?.model == nil)
+
+                Button("Lay Flat on Selected Triangles") {
+                    NotificationCenter.default.post(name: NSNotification.Name("LayFlatOnSelection"), object: nil)
+                }
+                .disabled(appState?.measurementSystem.selectedTriangles.isEmpty != false)
+
+                Button("Emboss Text on Selected Face...") {
+                    embossText()
+                }
+                .disabled(appState?.measurementSystem.selectedTriangles.isEmpty != false)
+
                 Button("Undo Leveling") {
                     NotificationCenter.default.post(name: NSNotification.Name("UndoLeveling"), object: nil)
                 }
                 .disabled(appState?.levelingState.canUndo != true)
 
+                Menu("Model Scale") {
+                    ForEach(ScaleCheck.Fix.allCases, id: \.self) { fix in
+                        Button(fix.title) {
+                            NotificationCenter.default.post(name: NSNotification.Name("ApplyScaleFix"), object: fix.rawValue)
+                        }
+                    }
+
+                    Divider()
+
+                    Button("Reset Scale") {
+                        NotificationCenter.default.post(name: NSNotification.Name("ResetModelScale"), object: nil)
+                    }
+                    .disabled(appState?.modelScale == 1)
+                }
+                .disabled(appState?.model == nil)
+
                 Divider()
 
                 Button("Clear All Measurements") {
@@ -537,6 +1157,15 @@ struct GoSTLApp: App {
                 }
                 .keyboardShortcut("k", modifiers: [.command, .shift])
 
+                Button("Remove All Annotations") {
+                    do {
+                        try appState?.removeAllAnnotations()
+                    } catch {
+                        print("WARNING: Failed to save annotations: \(error.localizedDescription)")
+                    }
+                }
+                .disabled(appState?.annotations.isEmpty ?? true)
+
                 Divider()
 
                 Button("Copy as OpenSCAD") {
@@ -554,6 +1183,10 @@ struct GoSTLApp: App {
                 }
                 .keyboardShortcut("p", modifiers: .command)
 
+                Button("Copy as OpenSCAD Variables") {
+                    NotificationCenter.default.post(name: NSNotification.Name("CopyMeasurementsAsOpenSCADVariables"), object: nil)
+                }
+
                 Divider()
 
                 Button("Change Material") {
@@ -561,6 +1194,16 @@ struct GoSTLApp: App {
                 }
                 .keyboardShortcut("m", modifiers: .command)
 
+                Menu("Infill") {
+                    ForEach(AppState.infillPresets, id: \.self) { infill in
+                        Toggle(String(format: "%.0f%%", infill * 100), isOn: Binding(
+                            get: { appState?.modelInfo?.infill == infill },
+                            set: { if $0 { appState?.setInfill(infill) } }
+                        ))
+                    }
+                }
+                .disabled(appState?.modelInfo?.material.supportsInfill != true)
+
                 Divider()
 
                 Button("Open with go3mf") {
@@ -581,6 +1224,10 @@ struct GoSTLApp: App {
                 }
             }
         }
+
+        Settings {
+            SettingsView()
+        }
     }
 
     private func showAboutPanel() {
@@ -600,13 +1247,7 @@ struct GoSTLApp: App {
 
     private func openFile() {
         let panel = NSOpenPanel()
-        panel.allowedContentTypes = [
-            .init(filenameExtension: "stl")!,
-            .init(filenameExtension: "3mf")!,
-            .init(filenameExtension: "scad")!,
-            .init(filenameExtension: "yaml")!,
-            .init(filenameExtension: "yml")!
-        ]
+        panel.allowedContentTypes = (ModelFileLoader.viewerExtensions + FileCompression.fileExtensions).compactMap { .init(filenameExtension: $0) }
         panel.allowsMultipleSelection = true
         panel.canChooseDirectories = false
         panel.canChooseFiles = true
@@ -620,49 +1261,580 @@ struct GoSTLApp: App {
         }
     }
 
+    private func openRemoteFile() {
+        let alert = NSAlert()
+        alert.messageText = "Open URL"
+        alert.informativeText = "Download a model from an http(s) or s3:// URL. Downloads are cached and only fetched again when the file has changed."
+        alert.addButton(withTitle: "Open")
+        alert.addButton(withTitle: "Cancel")
+
+        let field = NSTextField(frame: NSRect(x: 0, y: 0, width: 360, height: 24))
+        field.placeholderString = "https://example.com/part.stl"
+        // Prefill a link copied from an issue tracker
+        if let copied = NSPasteboard.general.string(forType: .string)?.trimmingCharacters(in: .whitespacesAndNewlines),
+           RemoteFileCache.remoteURL(copied) != nil {
+            field.stringValue = copied
+        }
+        alert.accessoryView = field
+        alert.window.initialFirstResponder = field
+
+        guard alert.runModal() == .alertFirstButtonReturn else { return }
+        let text = field.stringValue.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard let url = RemoteFileCache.remoteURL(text) else {
+            FileOpenCoordinator.showOpenError(RemoteFileError.unsupportedURL(text))
+            return
+        }
+        FileOpenCoordinator.shared.openRemote(url)
+    }
+
+    private func runScript() {
+        guard let appState = appState else { return }
+        let panel = NSOpenPanel()
+        panel.allowedContentTypes = [.init(filenameExtension: "js")!]
+        panel.allowsMultipleSelection = false
+        panel.canChooseDirectories = false
+        panel.canChooseFiles = true
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+
+            appState.runScript(url: url) { result in
+                let alert = NSAlert()
+                switch result {
+                case .success(let report):
+                    alert.messageText = report.passed ? "Script Finished" : "Script Reported Failures"
+                    alert.informativeText = report.output.suffix(20).joined(separator: "\n")
+                    alert.alertStyle = report.passed ? .informational : .warning
+                case .failure(is CancellationError):
+                    return
+                case .failure(let error):
+                    alert.messageText = "Script Failed"
+                    alert.informativeText = error.localizedDescription
+                    alert.alertStyle = .warning
+                }
+                alert.addButton(withTitle: "OK")
+                alert.runModal()
+            }
+        }
+    }
+
+    private func saveSessionRecording() {
+        guard let appState = appState else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = [.init(filenameExtension: SessionRecording.fileExtension)!]
+        let baseName = appState.sourceFileURL.map(ModelFileLoader.modelName(of:)) ?? "model"
+        panel.nameFieldStringValue = "\(baseName).\(SessionRecording.fileExtension)"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            do {
+                try appState.saveSessionRecording(to: url)
+            } catch {
+                self.showSaveError(error)
+            }
+        }
+    }
+
+    private func replaySession(toVideo: Bool) {
+        guard let appState = appState else { return }
+        let openPanel = NSOpenPanel()
+        openPanel.allowedContentTypes = [.init(filenameExtension: SessionRecording.fileExtension)!]
+        openPanel.allowsMultipleSelection = false
+        openPanel.canChooseDirectories = false
+        openPanel.canChooseFiles = true
+
+        openPanel.begin { response in
+            guard response == .OK, let sessionURL = openPanel.url else { return }
+            let recording: SessionRecording
+            do {
+                recording = try SessionRecording.load(from: sessionURL)
+            } catch {
+                let alert = NSAlert()
+                alert.messageText = "Cannot Open Session"
+                alert.informativeText = error.localizedDescription
+                alert.alertStyle = .warning
+                alert.addButton(withTitle: "OK")
+                alert.runModal()
+                return
+            }
+
+            guard toVideo else {
+                appState.replaySession(recording)
+                return
+            }
+
+            let savePanel = NSSavePanel()
+            savePanel.allowedContentTypes = [.init(filenameExtension: "mp4")!]
+            savePanel.nameFieldStringValue = sessionURL.deletingPathExtension().lastPathComponent + ".mp4"
+            savePanel.begin { response in
+                guard response == .OK, let videoURL = savePanel.url else { return }
+                appState.replaySession(recording, videoURL: videoURL)
+            }
+        }
+    }
+
     private func saveFile() {
         guard let appState = appState else { return }
+        // The model is written in the background; remember the window it belongs to
+        let window = NSApp.keyWindow
         do {
-            try appState.saveModel()
-            if let savedURL = appState.savedFileURL, let window = NSApp.keyWindow {
-                window.title = savedURL.lastPathComponent
-                window.representedURL = savedURL
-                recentDocuments.addDocument(savedURL)
+            try appState.saveModel { result in
+                switch result {
+                case .success(let savedURL):
+                    window?.title = savedURL.lastPathComponent
+                    window?.representedURL = savedURL
+                    self.recentDocuments.addDocument(savedURL)
+                case .failure(let error):
+                    self.showSaveError(error)
+                }
             }
         } catch {
             showSaveError(error)
         }
     }
 
-    private func saveFileAs() {
+    private func saveFileAs(clearingMetadata: Bool = false) {
         guard let appState = appState else { return }
         let panel = NSSavePanel()
-        panel.allowedContentTypes = [.init(filenameExtension: "stl")!]
+        panel.allowedContentTypes = (["stl"] + FileCompression.fileExtensions).compactMap { .init(filenameExtension: $0) }
+        panel.allowsOtherFileTypes = true
         panel.nameFieldStringValue = suggestFileName(for: appState)
 
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            let window = NSApp.keyWindow
+            do {
+                try appState.saveModelAs(to: url, clearingMetadata: clearingMetadata) { result in
+                    switch result {
+                    case .success:
+                        window?.title = url.lastPathComponent
+                        window?.representedURL = url
+                        self.recentDocuments.addDocument(url)
+                    case .failure(let error):
+                        self.showSaveError(error)
+                    }
+                }
+            } catch {
+                self.showSaveError(error)
+            }
+        }
+    }
+
+    private func exportInspectionReport() {
+        guard let appState = appState else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = [.init(filenameExtension: "txt")!]
+        let baseName = appState.sourceFileURL.map(ModelFileLoader.modelName(of:)) ?? "model"
+        panel.nameFieldStringValue = "\(baseName)-inspection.txt"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            do {
+                try appState.exportInspectionReport(to: url)
+            } catch {
+                self.showSaveError(error)
+            }
+        }
+    }
+
+    private func exportMeasurements() {
+        guard let appState = appState else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = [.init(filenameExtension: "json")!]
+        let baseName = appState.sourceFileURL.map(ModelFileLoader.modelName(of:)) ?? "model"
+        panel.nameFieldStringValue = "\(baseName)-measurements.json"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            do {
+                try appState.exportMeasurements(to: url)
+            } catch {
+                self.showSaveError(error)
+            }
+        }
+    }
+
+    private func exportOpenSCADVariables() {
+        guard let appState = appState else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = [.init(filenameExtension: "scad")!]
+        let baseName = appState.sourceFileURL.map(ModelFileLoader.modelName(of:)) ?? "model"
+        panel.nameFieldStringValue = "\(baseName)-measurements.scad"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            do {
+                try appState.exportOpenSCADVariables(to: url)
+            } catch {
+                self.showSaveError(error)
+            }
+        }
+    }
+
+    private func exportOutlineSVG() {
+        guard let appState = appState else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = [.init(filenameExtension: "svg")!]
+        let baseName = appState.sourceFileURL.map(ModelFileLoader.modelName(of:)) ?? "model"
+        panel.nameFieldStringValue = "\(baseName)-outline.svg"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            do {
+                try appState.exportOutlineSVG(to: url)
+            } catch {
+                self.showSaveError(error)
+            }
+        }
+    }
+
+    /// Decimation choices offered when exporting a polyhedron (grid cell size in mm)
+    private static let polyhedronDecimations: [(title: String, cellSize: Double?)] = [
+        ("Full resolution", nil),
+        ("Merge vertices within 0.1 mm", 0.1),
+        ("Merge vertices within 0.5 mm", 0.5),
+        ("Merge vertices within 1 mm", 1.0)
+    ]
+
+    private func exportOpenSCADPolyhedron() {
+        guard let appState = appState else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = [.init(filenameExtension: "scad")!]
+        let baseName = appState.sourceFileURL.map(ModelFileLoader.modelName(of:)) ?? "model"
+        panel.nameFieldStringValue = "\(baseName)-polyhedron.scad"
+
+        let decimation = NSPopUpButton(frame: NSRect(x: 0, y: 0, width: 260, height: 26), pullsDown: false)
+        decimation.addItems(withTitles: Self.polyhedronDecimations.map(\.title))
+        panel.accessoryView = decimation
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            do {
+                let cellSize = Self.polyhedronDecimations[max(decimation.indexOfSelectedItem, 0)].cellSize
+                try appState.exportOpenSCADPolyhedron(to: url, decimation: cellSize)
+            } catch {
+                self.showSaveError(error)
+            }
+        }
+    }
+
+    private func exportSectionSketch() {
+        guard let appState = appState, let plane = appState.sectionSketchPlane else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = ["dxf", "scad"].compactMap { .init(filenameExtension: $0) }
+        let baseName = appState.sourceFileURL.map(ModelFileLoader.modelName(of:)) ?? "model"
+        panel.nameFieldStringValue = "\(baseName)-section-\(plane.axis.rawValue).dxf"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            do {
+                try appState.exportSectionSketch(to: url)
+            } catch {
+                self.showSaveError(error)
+            }
+        }
+    }
+
+    private func exportAnalysisColors() {
+        guard let appState = appState else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = ColoredMeshExporter.Format.allCases.map { .init(filenameExtension: $0.rawValue)! }
+        let baseName = appState.sourceFileURL.map(ModelFileLoader.modelName(of:)) ?? "model"
+        panel.nameFieldStringValue = "\(baseName)-\(appState.showDraftAnalysis ? "draft" : appState.showPrimitives ? "primitives" : "height").ply"
+
         panel.begin { response in
             guard response == .OK, let url = panel.url else { return }
             do {
-                try appState.saveModelAs(to: url)
-                if let window = NSApp.keyWindow {
-                    window.title = url.lastPathComponent
-                    window.representedURL = url
+                try appState.exportAnalysisColors(to: url)
+            } catch {
+                self.showSaveError(error)
+            }
+        }
+    }
+
+    /// Save the current view as a presentation image, with the shot options in the save panel
+    private func exportViewImage() {
+        guard let appState = appState else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = [.init(filenameExtension: "png")!]
+        let baseName = appState.sourceFileURL.map(ModelFileLoader.modelName(of:)) ?? "model"
+        panel.nameFieldStringValue = "\(baseName)-view.png"
+
+        var options = AppSettings.shared.presentationShot
+        let size = NSPopUpButton(frame: .zero, pullsDown: false)
+        size.addItems(withTitles: PresentationShotOptions.sizes.map { "\($0) × \($0) px" })
+        size.selectItem(at: PresentationShotOptions.sizes.firstIndex(of: options.size) ?? 0)
+        let background = NSPopUpButton(frame: .zero, pullsDown: false)
+        background.addItems(withTitles: PresentationShotOptions.Background.allCases.map(\.title))
+        background.selectItem(at: PresentationShotOptions.Background.allCases.firstIndex(of: options.background) ?? 0)
+        let shadow = NSButton(checkboxWithTitle: "Ground shadow", target: nil, action: nil)
+        shadow.state = options.groundShadow ? .on : .off
+        let depthOfField = NSButton(checkboxWithTitle: "Depth of field", target: nil, action: nil)
+        depthOfField.state = options.depthOfField ? .on : .off
+        let occlusion = NSButton(checkboxWithTitle: "Ambient occlusion", target: nil, action: nil)
+        occlusion.state = options.ambientOcclusion ? .on : .off
+
+        let accessory = NSStackView(views: [size, background, shadow, depthOfField, occlusion])
+        accessory.orientation = .vertical
+        accessory.alignment = .leading
+        accessory.edgeInsets = NSEdgeInsets(top: 8, left: 8, bottom: 8, right: 8)
+        panel.accessoryView = accessory
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            options.size = PresentationShotOptions.sizes[max(size.indexOfSelectedItem, 0)]
+            options.background = PresentationShotOptions.Background.allCases[max(background.indexOfSelectedItem, 0)]
+            options.groundShadow = shadow.state == .on
+            options.depthOfField = depthOfField.state == .on
+            options.ambientOcclusion = occlusion.state == .on
+            AppSettings.shared.presentationShot = options
+
+            appState.exportPresentationShot(options: options, to: url) { result in
+                if case .failure(let error) = result {
+                    self.showSaveError(error)
                 }
-                self.recentDocuments.addDocument(url)
+            }
+        }
+    }
+
+    private func compareMeasurements() {
+        guard let appState = appState else { return }
+        let panel = NSOpenPanel()
+        panel.allowedContentTypes = [.init(filenameExtension: "json")!]
+        panel.allowsMultipleSelection = false
+        panel.canChooseDirectories = false
+        panel.canChooseFiles = true
+        panel.message = "Choose a measurement export of an earlier revision"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            do {
+                try appState.compareMeasurements(withBaseline: url)
+            } catch {
+                let alert = NSAlert()
+                alert.messageText = "Cannot Compare Measurements"
+                alert.informativeText = error.localizedDescription
+                alert.alertStyle = .warning
+                alert.addButton(withTitle: "OK")
+                alert.runModal()
+            }
+        }
+    }
+
+    /// Define a new measurement template or edit an existing one in its text syntax
+    private func editTemplate(_ template: MeasurementTemplate?) {
+        guard let appState = appState else { return }
+        let alert = NSAlert()
+        alert.messageText = template == nil ? "New Measurement Template" : "Edit Measurement Template"
+        alert.informativeText = """
+            One line per feature to pick ("name: point", "hole", "plane", "face" or "axis") and per dimension \
+            ("name = first to second", "name = angle first to second", optionally ": 40 ± 0.1").
+            """
+        alert.addButton(withTitle: "Save")
+        alert.addButton(withTitle: "Cancel")
+
+        let nameField = NSTextField(frame: NSRect(x: 0, y: 184, width: 360, height: 24))
+        nameField.placeholderString = "Name"
+        nameField.stringValue = template?.name ?? "Template \(AppSettings.shared.measurementTemplates.count + 1)"
+
+        let scrollView = NSScrollView(frame: NSRect(x: 0, y: 0, width: 360, height: 176))
+        scrollView.hasVerticalScroller = true
+        scrollView.borderType = .bezelBorder
+        let textView = NSTextView(frame: scrollView.bounds)
+        textView.font = .monospacedSystemFont(ofSize: 12, weight: .regular)
+        textView.isAutomaticQuoteSubstitutionEnabled = false
+        textView.autoresizingMask = [.width]
+        textView.string = template?.definition ?? "hole1 center: hole\nhole2 center: hole\nbase plane: plane\npitch = hole1 center to hole2 center"
+        scrollView.documentView = textView
+
+        let container = NSView(frame: NSRect(x: 0, y: 0, width: 360, height: 208))
+        container.addSubview(nameField)
+        container.addSubview(scrollView)
+        alert.accessoryView = container
+        alert.window.initialFirstResponder = nameField
+
+        guard alert.runModal() == .alertFirstButtonReturn else { return }
+        do {
+            var parsed = try MeasurementTemplate.parse(name: nameField.stringValue, definition: textView.string)
+            if let template {
+                parsed.id = template.id
+            }
+            try appState.saveTemplate(parsed)
+        } catch {
+            let errorAlert = NSAlert()
+            errorAlert.messageText = "Cannot Save Template"
+            errorAlert.informativeText = error.localizedDescription
+            errorAlert.alertStyle = .warning
+            errorAlert.addButton(withTitle: "OK")
+            errorAlert.runModal()
+        }
+    }
+
+    private func exportTemplateResults() {
+        guard let appState = appState, let run = appState.templateRun else { return }
+        let panel = NSSavePanel()
+        panel.allowedContentTypes = [.init(filenameExtension: "csv")!]
+        panel.nameFieldStringValue = "\(run.template.name).csv"
+        panel.message = "Export the results of all parts as a table"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            do {
+                try appState.exportTemplateResults(to: url)
             } catch {
                 self.showSaveError(error)
             }
         }
     }
 
+    private func chooseClearanceModel() {
+        guard appState?.model != nil else { return }
+        let panel = NSOpenPanel()
+        panel.allowedContentTypes = (ModelFileLoader.viewerExtensions + FileCompression.fileExtensions).compactMap { .init(filenameExtension: $0) }
+        panel.allowsMultipleSelection = false
+        panel.canChooseDirectories = false
+        panel.canChooseFiles = true
+        panel.message = "Choose a second model to check for collisions and clearance"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            NotificationCenter.default.post(name: NSNotification.Name("AddClearanceModel"), object: url)
+        }
+    }
+
+    private func chooseComparisonModel() {
+        guard appState?.model != nil else { return }
+        let panel = NSOpenPanel()
+        panel.allowedContentTypes = (ModelFileLoader.viewerExtensions + FileCompression.fileExtensions).compactMap { .init(filenameExtension: $0) }
+        panel.allowsMultipleSelection = false
+        panel.canChooseDirectories = false
+        panel.canChooseFiles = true
+        panel.message = "Choose a model or revision to show side by side"
+
+        panel.begin { response in
+            guard response == .OK, let url = panel.url else { return }
+            NotificationCenter.default.post(name: NSNotification.Name("OpenComparisonModel"), object: url)
+        }
+    }
+
     private func suggestFileName(for appState: AppState) -> String {
         if let savedURL = appState.savedFileURL { return savedURL.lastPathComponent }
         if let sourceURL = appState.sourceFileURL {
-            return "\(sourceURL.deletingPathExtension().lastPathComponent).stl"
+            // Keep the compression of a compressed STL (part.stl.gz)
+            let compression = FileCompression(url: sourceURL).map { ".\($0.rawValue)" } ?? ""
+            return "\(ModelFileLoader.modelName(of: sourceURL)).stl\(compression)"
         }
         return "model.stl"
     }
 
+    private func saveProject() {
+        perform { try appState?.saveProject() }
+    }
+
+    private func saveView() {
+        guard let appState = appState else { return }
+        let alert = NSAlert()
+        alert.messageText = "Save View"
+        alert.informativeText = "The camera position is saved with the model and can be recalled from View > Saved Views."
+        alert.addButton(withTitle: "Save")
+        alert.addButton(withTitle: "Cancel")
+
+        let field = NSTextField(frame: NSRect(x: 0, y: 0, width: 260, height: 24))
+        field.placeholderString = "Name"
+        field.stringValue = "View \(appState.savedViews.count + 1)"
+        alert.accessoryView = field
+        alert.window.initialFirstResponder = field
+
+        guard alert.runModal() == .alertFirstButtonReturn else { return }
+        perform { try appState.saveView(name: field.stringValue) }
+    }
+
+    private func saveLayout() {
+        guard let appState = appState else { return }
+        let alert = NSAlert()
+        alert.messageText = "Save Layout"
+        alert.informativeText = "The visible and detached panels are saved for all models and can be restored from View > Layouts."
+        alert.addButton(withTitle: "Save")
+        alert.addButton(withTitle: "Cancel")
+
+        let field = NSTextField(frame: NSRect(x: 0, y: 0, width: 260, height: 24))
+        field.placeholderString = "Name"
+        field.stringValue = "Layout \(AppSettings.shared.workspaceLayouts.count + 1)"
+        alert.accessoryView = field
+        alert.window.initialFirstResponder = field
+
+        guard alert.runModal() == .alertFirstButtonReturn else { return }
+        appState.saveLayout(name: field.stringValue)
+    }
+
+    private func applyLayout(_ layout: WorkspaceLayout) {
+        guard let appState = appState else { return }
+        appState.applyLayout(layout)
+        DetachedPanelWindows.shared.apply(layout.detachedPanels, appState: appState)
+    }
+
+    /// Enter or leave presentation mode with the model window in full screen
+    private func setPresentationMode(_ presenting: Bool) {
+        guard let appState = appState else { return }
+        appState.setPresentationMode(presenting)
+        guard let windowNumber = NSApp.keyWindow?.windowNumber else { return }
+        if presenting {
+            PresentationWindow.enter(windowNumber: windowNumber)
+        } else {
+            PresentationWindow.leave(windowNumber: windowNumber)
+        }
+    }
+
+    private func embossText() {
+        guard let appState = appState else { return }
+        let alert = NSAlert()
+        alert.messageText = "Emboss Text"
+        alert.informativeText = "The text goes on the center of the flat face with the selected triangles, as large as fits unless a letter height is given. Save the model to keep it."
+        alert.addButton(withTitle: "Emboss")
+        alert.addButton(withTitle: "Engrave")
+        alert.addButton(withTitle: "Cancel")
+
+        let textField = NSTextField(frame: NSRect(x: 0, y: 56, width: 260, height: 24))
+        textField.placeholderString = "Text, e.g. a part ID"
+        let depthField = NSTextField(frame: NSRect(x: 0, y: 28, width: 260, height: 24))
+        depthField.placeholderString = "Depth (mm)"
+        depthField.stringValue = String(format: "%g", ModelGenerator.reliefDepth)
+        let heightField = NSTextField(frame: NSRect(x: 0, y: 0, width: 260, height: 24))
+        heightField.placeholderString = "Letter height (mm, empty to fit)"
+        let fields = NSView(frame: NSRect(x: 0, y: 0, width: 260, height: 80))
+        [textField, depthField, heightField].forEach(fields.addSubview)
+        alert.accessoryView = fields
+        alert.window.initialFirstResponder = textField
+
+        let response = alert.runModal()
+        guard response != .alertThirdButtonReturn else { return }
+        let relief = TextRelief(
+            text: textField.stringValue,
+            mode: response == .alertFirstButtonReturn ? .emboss : .engrave,
+            depth: Double(depthField.stringValue).flatMap { $0 > 0 ? $0 : nil } ?? ModelGenerator.reliefDepth,
+            letterHeight: Double(heightField.stringValue).flatMap { $0 > 0 ? $0 : nil }
+        )
+        do {
+            try appState.addTextRelief(relief)
+        } catch {
+            let failure = NSAlert()
+            failure.messageText = "Cannot Add Text"
+            failure.informativeText = error.localizedDescription
+            failure.alertStyle = .warning
+            failure.addButton(withTitle: "OK")
+            failure.runModal()
+        }
+    }
+
+    /// Run a change that writes the model's sidecar, showing failures
+    private func perform(_ action: () throws -> Void) {
+        do {
+            try action()
+        } catch {
+            showSaveError(error)
+        }
+    }
+
     private func showSaveError(_ error: Error) {
         let alert = NSAlert()
         alert.messageText = "Failed to Save"
@@ -708,7 +1880,8 @@ struct GoSTLApp: App {
                 // Skip non-standard windows (panels, sheets, etc.)
                 guard window.isKind(of: NSWindow.self),
                       !window.isSheet,
-                      window.styleMask.contains(.titled) else { continue }
+                      window.styleMask.contains(.titled),
+                      !(window is DetachedPanelWindow) else { continue }
 
                 // Set consistent tabbingIdentifier for all main windows
                 if window.tabbingIdentifier != "GoSTLWindow" {
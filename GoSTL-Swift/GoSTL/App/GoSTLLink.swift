@@ -0,0 +1,133 @@
+import Foundation
+
+/// A `gostl://open` link that opens a model with a given view and measurements
+///
+///     gostl://open?file=/parts/case.stl&camera=120,20,210,0,0,15&measurements=/parts/rev-a.json
+///
+/// - `file`: Absolute path of the model
+/// - `camera`: Distance, pitch and yaw (degrees) and orbit target (x, y, z in file coordinates)
+/// - `measurements`: Path of a measurement export, or the export inline as base64url JSON
+struct GoSTLLink {
+    static let scheme = "gostl"
+
+    var file: URL
+    var camera: CameraKeyframe?
+    /// Measurements in file coordinates
+    var measurements: [Measurement]?
+
+    init(file: URL, camera: CameraKeyframe? = nil, measurements: [Measurement]? = nil) {
+        self.file = file
+        self.camera = camera
+        self.measurements = measurements
+    }
+
+    /// Parse a link; a `measurements` path is read immediately
+    init(url: URL) throws {
+        guard url.scheme?.lowercased() == Self.scheme,
+              url.host?.lowercased() == "open",
+              let components = URLComponents(url: url, resolvingAgainstBaseURL: false) else {
+            throw GoSTLLinkError.invalidLink(url.absoluteString)
+        }
+        let items = components.queryItems ?? []
+        func value(_ name: String) -> String? {
+            items.first { $0.name == name }?.value
+        }
+
+        guard let path = value("file"), !path.isEmpty else {
+            throw GoSTLLinkError.missingFile
+        }
+        file = URL(fileURLWithPath: (path as NSString).expandingTildeInPath)
+
+        if let camera = value("camera") {
+            let numbers = camera.split(separator: ",").compactMap { Double($0.trimmingCharacters(in: .whitespaces)) }
+            guard numbers.count == 6 else {
+                throw GoSTLLinkError.invalidCamera(camera)
+            }
+            self.camera = CameraKeyframe(
+                distance: numbers[0],
+                angleX: numbers[1] * .pi / 180,
+                angleY: numbers[2] * .pi / 180,
+                target: Vector3(numbers[3], numbers[4], numbers[5])
+            )
+        }
+
+        if let measurements = value("measurements") {
+            let data: Data
+            if measurements.hasPrefix("/") || measurements.hasPrefix("~") {
+                data = try Data(contentsOf: URL(fileURLWithPath: (measurements as NSString).expandingTildeInPath))
+            } else if let decoded = Self.base64URLDecode(measurements) {
+                data = decoded
+            } else {
+                throw GoSTLLinkError.invalidMeasurements
+            }
+            self.measurements = try MeasurementSet.decode(data).entries.map(\.measurement)
+        }
+    }
+
+    /// The link as a URL, with measurements inline so it works on other machines with the same file
+    func url() throws -> URL {
+        var components = URLComponents()
+        components.scheme = Self.scheme
+        components.host = "open"
+        var items = [URLQueryItem(name: "file", value: file.path)]
+        if let camera {
+            let values = [
+                camera.distance, camera.angleX * 180 / .pi, camera.angleY * 180 / .pi,
+                camera.target.x, camera.target.y, camera.target.z
+            ]
+            items.append(URLQueryItem(name: "camera", value: values.map(Self.number).joined(separator: ",")))
+        }
+        if let measurements, !measurements.isEmpty {
+            let set = MeasurementSet(fileName: file.lastPathComponent, measurements: measurements)
+            items.append(URLQueryItem(name: "measurements", value: Self.base64URLEncode(try set.encoded())))
+        }
+        components.queryItems = items
+        guard let url = components.url else {
+            throw GoSTLLinkError.invalidLink(file.path)
+        }
+        return url
+    }
+
+    /// Up to three decimals without trailing zeros ("120", "15.25")
+    private static func number(_ value: Double) -> String {
+        var text = String(format: "%.3f", value)
+        while text.hasSuffix("0") { text.removeLast() }
+        if text.hasSuffix(".") { text.removeLast() }
+        return text == "-0" ? "0" : text
+    }
+
+    private static func base64URLEncode(_ data: Data) -> String {
+        data.base64EncodedString()
+            .replacingOccurrences(of: "+", with: "-")
+            .replacingOccurrences(of: "/", with: "_")
+            .replacingOccurrences(of: "=", with: "")
+    }
+
+    private static func base64URLDecode(_ string: String) -> Data? {
+        var base64 = string
+            .replacingOccurrences(of: "-", with: "+")
+            .replacingOccurrences(of: "_", with: "/")
+        base64 += String(repeating: "=", count: (4 - base64.count % 4) % 4)
+        return Data(base64Encoded: base64)
+    }
+}
+
+enum GoSTLLinkError: LocalizedError {
+    case invalidLink(String)
+    case missingFile
+    case invalidCamera(String)
+    case invalidMeasurements
+
+    var errorDescription: String? {
+        switch self {
+        case .invalidLink(let link):
+            return "Not a gostl://open link: \(link)"
+        case .missingFile:
+            return "The link does not name a file"
+        case .invalidCamera(let value):
+            return "Invalid camera \"\(value)\" (expected distance,pitch,yaw,x,y,z)"
+        case .invalidMeasurements:
+            return "The link's measurements are neither a file path nor inline data"
+        }
+    }
+}
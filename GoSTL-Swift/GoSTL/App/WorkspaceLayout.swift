@@ -0,0 +1,62 @@
+import Foundation
+
+/// Named set of visible and detached panels (View > Layouts), stored in the settings so it applies
+/// to every model
+struct WorkspaceLayout: Codable, Equatable, Identifiable {
+    var id = UUID()
+    var name: String
+    var showModelInfo: Bool = true
+    var showMeasurementLog: Bool = false
+    var showMetadataPanel: Bool = false
+    var showClippingPanel: Bool = false
+    var showSectionBookmarks: Bool = false
+    var showPerformanceHUD: Bool = false
+    var showAnnotations: Bool = true
+    /// Panels in windows of their own
+    var detachedPanels: Set<DetachedPanel> = []
+
+    private enum CodingKeys: String, CodingKey {
+        case name, showModelInfo, showMeasurementLog, showMetadataPanel, showClippingPanel
+        case showSectionBookmarks, showPerformanceHUD, showAnnotations, detachedPanels
+    }
+
+    init(name: String) {
+        self.name = name
+    }
+
+    /// Missing fields keep their defaults, so layouts saved before a panel was added still load
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        name = try container.decode(String.self, forKey: .name)
+        showModelInfo = try container.decodeIfPresent(Bool.self, forKey: .showModelInfo) ?? showModelInfo
+        showMeasurementLog = try container.decodeIfPresent(Bool.self, forKey: .showMeasurementLog) ?? showMeasurementLog
+        showMetadataPanel = try container.decodeIfPresent(Bool.self, forKey: .showMetadataPanel) ?? showMetadataPanel
+        showClippingPanel = try container.decodeIfPresent(Bool.self, forKey: .showClippingPanel) ?? showClippingPanel
+        showSectionBookmarks = try container.decodeIfPresent(Bool.self, forKey: .showSectionBookmarks) ?? showSectionBookmarks
+        showPerformanceHUD = try container.decodeIfPresent(Bool.self, forKey: .showPerformanceHUD) ?? showPerformanceHUD
+        showAnnotations = try container.decodeIfPresent(Bool.self, forKey: .showAnnotations) ?? showAnnotations
+        // Panels this version does not know are left out
+        let panels = try container.decodeIfPresent([String].self, forKey: .detachedPanels) ?? []
+        detachedPanels = Set(panels.compactMap(DetachedPanel.init(rawValue:)))
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: CodingKeys.self)
+        try container.encode(name, forKey: .name)
+        try container.encode(showModelInfo, forKey: .showModelInfo)
+        try container.encode(showMeasurementLog, forKey: .showMeasurementLog)
+        try container.encode(showMetadataPanel, forKey: .showMetadataPanel)
+        try container.encode(showClippingPanel, forKey: .showClippingPanel)
+        try container.encode(showSectionBookmarks, forKey: .showSectionBookmarks)
+        try container.encode(showPerformanceHUD, forKey: .showPerformanceHUD)
+        try container.encode(showAnnotations, forKey: .showAnnotations)
+        try container.encode(detachedPanels.map(\.rawValue).sorted(), forKey: .detachedPanels)
+    }
+
+    /// Layout of presentation mode: nothing over the 3D view but the model, its labels and annotations
+    static let presentation: WorkspaceLayout = {
+        var layout = WorkspaceLayout(name: "Presentation")
+        layout.showModelInfo = false
+        return layout
+    }()
+}
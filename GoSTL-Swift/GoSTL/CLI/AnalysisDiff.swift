@@ -0,0 +1,71 @@
+import Foundation
+
+/// Changed metrics between two analyses of the same model (for `gostl analyze --watch`)
+struct AnalysisDiff {
+    struct Change: Equatable {
+        let key: String
+        let label: String
+        let unit: String?
+        let old: Double
+        let new: Double
+
+        var delta: Double {
+            new - old
+        }
+    }
+
+    /// Metrics shown in the compact diff; bounds and edge statistics only add noise while iterating
+    static let watchedKeys = [
+        "triangles", "dimensions.x", "dimensions.y", "dimensions.z",
+        "volume", "surface_area", "weight.pla_100"
+    ]
+
+    let changes: [Change]
+
+    /// Compare the numeric fields of two records; differences up to the tolerance are ignored
+    init(from old: AnalysisRecord, to new: AnalysisRecord, keys: [String] = AnalysisDiff.watchedKeys, tolerance: Double = 1e-6) {
+        changes = keys.compactMap { key in
+            guard let oldValue = old.value(for: key)?.number,
+                  let newValue = new.value(for: key)?.number,
+                  abs(newValue - oldValue) > tolerance,
+                  let field = new.fields.first(where: { $0.key == key }) else {
+                return nil
+            }
+            return Change(key: key, label: field.label, unit: field.unit, old: oldValue, new: newValue)
+        }
+    }
+
+    var isEmpty: Bool {
+        changes.isEmpty
+    }
+
+    /// One line per change: `Volume  1200.00 → 1250.50 mm³  (Δ +50.50, +4.2%)`
+    func formatted() -> String {
+        guard !changes.isEmpty else { return "no changes" }
+        let width = changes.map(\.label.count).max() ?? 0
+        return changes.map { change in
+            let isCount = change.key == "triangles"
+            let number = { (value: Double) in isCount ? String(Int(value)) : String(format: "%.2f", value) }
+            let unit = change.unit.map { " \($0)" } ?? ""
+            let sign = change.delta > 0 ? "+" : "-"
+            var line = change.label.padding(toLength: width, withPad: " ", startingAt: 0)
+                + "  \(number(change.old)) → \(number(change.new))\(unit)  (Δ \(sign)\(number(abs(change.delta)))"
+            if change.old != 0 {
+                line += String(format: ", %@%.1f%%", sign, abs(change.delta / change.old) * 100)
+            }
+            return line + ")"
+        }
+        .joined(separator: "\n")
+    }
+}
+
+extension AnalysisValue {
+    /// Numeric value of integer and number fields
+    var number: Double? {
+        switch self {
+        case .integer(let value): return Double(value)
+        case .number(let value): return value
+        case .text: return nil
+        }
+    }
+}
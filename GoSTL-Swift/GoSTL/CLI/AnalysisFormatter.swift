@@ -0,0 +1,269 @@
+import ArgumentParser
+import Foundation
+
+/// A single analysis result value
+enum AnalysisValue: Equatable {
+    case integer(Int)
+    case number(Double)
+    case text(String)
+
+    /// Full-precision representation for machine-readable output
+    var plain: String {
+        switch self {
+        case .integer(let value): return String(value)
+        case .number(let value): return String(value)
+        case .text(let value): return value
+        }
+    }
+
+    var jsonObject: Any {
+        switch self {
+        case .integer(let value): return value
+        case .number(let value): return value
+        case .text(let value): return value
+        }
+    }
+}
+
+/// Analysis results of one model as an ordered list of fields
+///
+/// Keys are stable identifiers for scripts (`volume`, `dimensions.x`, ...); dotted keys
+/// become nested objects in JSON. Labels and units come from `MetricRegistry`.
+struct AnalysisRecord {
+    struct Field {
+        let key: String
+        let label: String
+        let unit: String?
+        let value: AnalysisValue
+    }
+
+    let fields: [Field]
+
+    init(fields: [Field]) {
+        self.fields = fields
+    }
+
+    /// - Parameters:
+    ///   - estimate: Mass and cost for a chosen material (`--material`, `--infill`), omitted if nil
+    ///   - massProperties: Center of mass and inertia (`--mass-properties`), omitted if nil
+    ///   - stability: Tip-over check (`--stability`), omitted if nil
+    init(
+        fileName: String,
+        analysis: ModelAnalysis,
+        estimate: MassEstimate? = nil,
+        massProperties: MassProperties? = nil,
+        stability: StabilityCheck? = nil
+    ) {
+        let box = analysis.boundingBox
+        var fields = [
+            Field(key: "file", value: .text(fileName)),
+            Field(key: "triangles", value: .integer(analysis.triangleCount)),
+            Field(key: "edges", value: .integer(analysis.edgeCount)),
+            Field(key: "dimensions.x", value: .number(analysis.dimensions.x)),
+            Field(key: "dimensions.y", value: .number(analysis.dimensions.y)),
+            Field(key: "dimensions.z", value: .number(analysis.dimensions.z)),
+            Field(key: "bounds.min.x", value: .number(box.min.x)),
+            Field(key: "bounds.min.y", value: .number(box.min.y)),
+            Field(key: "bounds.min.z", value: .number(box.min.z)),
+            Field(key: "bounds.max.x", value: .number(box.max.x)),
+            Field(key: "bounds.max.y", value: .number(box.max.y)),
+            Field(key: "bounds.max.z", value: .number(box.max.z)),
+            Field(key: "volume", value: .number(analysis.volume)),
+            Field(key: "surface_area", value: .number(analysis.surfaceArea)),
+            Field(key: "surface_area.up", value: .number(analysis.orientationAreas.up)),
+            Field(key: "surface_area.down", value: .number(analysis.orientationAreas.down)),
+            Field(key: "surface_area.vertical", value: .number(analysis.orientationAreas.vertical)),
+            Field(key: "edge_length.min", value: .number(analysis.minEdgeLength)),
+            Field(key: "edge_length.max", value: .number(analysis.maxEdgeLength)),
+            Field(key: "edge_length.avg", value: .number(analysis.avgEdgeLength)),
+            Field(key: "weight.pla_100", value: .number(analysis.weightPLA100)),
+            Field(key: "weight.pla_15", value: .number(analysis.weightPLA15))
+        ]
+        if let estimate {
+            fields += [
+                Field(key: "material", value: .text(estimate.material.rawValue)),
+                Field(key: "infill", value: .number(estimate.infill * 100)),
+                Field(key: "mass", value: .number(estimate.mass)),
+                Field(key: "cost", value: .number(estimate.cost))
+            ]
+        }
+        if let properties = massProperties {
+            let center = properties.centerOfMass
+            let inertia = properties.inertia
+            fields += [
+                Field(key: "density", value: .number(properties.density)),
+                Field(key: "center_of_mass.x", value: .number(center.x)),
+                Field(key: "center_of_mass.y", value: .number(center.y)),
+                Field(key: "center_of_mass.z", value: .number(center.z)),
+                Field(key: "inertia.xx", value: .number(inertia.xx)),
+                Field(key: "inertia.yy", value: .number(inertia.yy)),
+                Field(key: "inertia.zz", value: .number(inertia.zz)),
+                Field(key: "inertia.xy", value: .number(inertia.xy)),
+                Field(key: "inertia.xz", value: .number(inertia.xz)),
+                Field(key: "inertia.yz", value: .number(inertia.yz))
+            ]
+            for index in 0..<3 {
+                let axis = properties.principalAxes[index]
+                fields += [
+                    Field(key: "principal_moments.\(index + 1)", value: .number(properties.principalMoments[index])),
+                    Field(key: "principal_axes.\(index + 1).x", value: .number(axis.x)),
+                    Field(key: "principal_axes.\(index + 1).y", value: .number(axis.y)),
+                    Field(key: "principal_axes.\(index + 1).z", value: .number(axis.z)),
+                    Field(key: "radius_of_gyration.\(index + 1)", value: .number(properties.radiiOfGyration[index]))
+                ]
+            }
+        }
+        if let stability {
+            fields += [
+                Field(key: "stability.stable", value: .text(stability.isStable ? "yes" : "no")),
+                Field(key: "stability.margin", value: .number(stability.margin)),
+                Field(key: "stability.tilt_angle", value: .number(stability.tiltAngle)),
+                Field(key: "stability.center_height", value: .number(stability.centerHeight))
+            ]
+        }
+        self.fields = fields
+    }
+
+    var keys: [String] {
+        fields.map(\.key)
+    }
+
+    /// Keys of model analysis records (for `--select` completion)
+    static var fieldKeys: [String] {
+        let empty = STLModel()
+        return AnalysisRecord(
+            fileName: "",
+            analysis: empty.analyze(),
+            estimate: Material.pla.estimate(volume: 0),
+            massProperties: empty.massProperties(density: Material.pla.density),
+            stability: StabilityCheck(centerOfMass: .zero, baseZ: 0, supportPolygon: [], margin: 0, tippingEdge: nil)
+        ).keys
+    }
+
+    func value(for key: String) -> AnalysisValue? {
+        fields.first { $0.key == key }?.value
+    }
+}
+
+extension AnalysisRecord.Field {
+    /// Field whose label and unit come from the metric registry
+    init(key: String, value: AnalysisValue) {
+        let (label, unit) = MetricRegistry.label(for: key)
+        self.init(key: key, label: label, unit: unit, value: value)
+    }
+}
+
+/// Errors reported while formatting analysis output
+enum AnalysisFormatError: LocalizedError {
+    case unknownField(String, available: [String])
+
+    var errorDescription: String? {
+        switch self {
+        case .unknownField(let key, let available):
+            return "Unknown field \"\(key)\". Available fields: \(available.joined(separator: ", "))"
+        }
+    }
+}
+
+/// Renders analysis records for `gostl analyze`
+protocol AnalysisFormatter {
+    func format(_ records: [AnalysisRecord]) throws -> String
+}
+
+/// Human-readable, aligned text (one block per model)
+struct TextAnalysisFormatter: AnalysisFormatter {
+    func format(_ records: [AnalysisRecord]) throws -> String {
+        records.map { record in
+            let width = record.fields.map(\.label.count).max() ?? 0
+            return record.fields.map { field in
+                let value: String
+                switch field.value {
+                case .number(let number): value = String(format: "%.2f", number)
+                default: value = field.value.plain
+                }
+                let unit = field.unit.map { " \($0)" } ?? ""
+                return field.label.padding(toLength: width, withPad: " ", startingAt: 0) + "  " + value + unit
+            }
+            .joined(separator: "\n")
+        }
+        .joined(separator: "\n\n")
+    }
+}
+
+/// JSON object per model (an array when several models are analyzed); dotted keys are nested
+struct JSONAnalysisFormatter: AnalysisFormatter {
+    func format(_ records: [AnalysisRecord]) throws -> String {
+        let objects = records.map { Self.object(for: $0) }
+        let root: Any = objects.count == 1 ? objects[0] : objects
+        let data = try JSONSerialization.data(withJSONObject: root, options: [.prettyPrinted, .sortedKeys])
+        return String(decoding: data, as: UTF8.self)
+    }
+
+    static func object(for record: AnalysisRecord) -> [String: Any] {
+        var object: [String: Any] = [:]
+        for field in record.fields {
+            insert(field.value.jsonObject, at: field.key.split(separator: ".").map(String.init)[...], into: &object)
+        }
+        return object
+    }
+
+    private static func insert(_ value: Any, at path: ArraySlice<String>, into object: inout [String: Any]) {
+        guard let key = path.first else { return }
+        if path.count == 1 {
+            object[key] = value
+            return
+        }
+        var child = object[key] as? [String: Any] ?? [:]
+        insert(value, at: path.dropFirst(), into: &child)
+        object[key] = child
+    }
+}
+
+/// CSV with a header row and one row per model
+struct CSVAnalysisFormatter: AnalysisFormatter {
+    var csv: CSVFormat = .standard
+
+    func format(_ records: [AnalysisRecord]) throws -> String {
+        guard let first = records.first else { return "" }
+        let rows = records.map { record in
+            csv.row(record.fields.map { field in
+                switch field.value {
+                case .text(let text): return text
+                case .integer, .number: return csv.localized(field.value.plain)
+                }
+            })
+        }
+        return ([csv.row(first.keys)] + rows).joined(separator: "\n")
+    }
+}
+
+/// Only the selected value, one line per model (for `--select`)
+struct ValueAnalysisFormatter: AnalysisFormatter {
+    let key: String
+
+    func format(_ records: [AnalysisRecord]) throws -> String {
+        try records.map { record in
+            guard let value = record.value(for: key) else {
+                throw AnalysisFormatError.unknownField(key, available: record.keys)
+            }
+            return value.plain
+        }
+        .joined(separator: "\n")
+    }
+}
+
+/// Output formats selectable with `--format`
+enum AnalysisOutputFormat: String, CaseIterable, ExpressibleByArgument {
+    case text
+    case json
+    case csv
+
+    /// - Parameter csv: Delimiter and decimal separator of CSV output
+    func formatter(csv: CSVFormat = .standard) -> AnalysisFormatter {
+        switch self {
+        case .text: return TextAnalysisFormatter()
+        case .json: return JSONAnalysisFormatter()
+        case .csv: return CSVAnalysisFormatter(csv: csv)
+        }
+    }
+}
@@ -0,0 +1,214 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl analyze <model>...` - print dimensions, volume and other model statistics
+struct AnalyzeCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "analyze",
+        abstract: "Print dimensions, volume, surface area and mesh statistics of models.",
+        discussion: """
+            Use --format json or --format csv for scripts, or --select <field> to print a single value \
+            (e.g. --select volume, --select dimensions.z). Values are in mm, mm², mm³ and g. \
+            The surface area is also split into up-facing, down-facing and vertical faces; faces within \
+            --vertical-tolerance degrees of vertical count as vertical. \
+            For spreadsheets in European locales, add --delimiter semicolon --decimal-separator comma. \
+            With --material and/or --infill, the estimated mass and material cost are added \
+            (e.g. --material petg --infill 20). \
+            --mass-properties adds the center of mass, the inertia tensor about it, principal moments and axes \
+            and radii of gyration for a uniform density (the material's, scaled by the infill; PLA by default), \
+            in mm, g and g·mm². \
+            --stability checks whether the model stands on its bottom face: the center of mass must lie over \
+            the support polygon; the margin to its nearest edge and the tilt before tipping over are reported. \
+            OpenSCAD files are rendered first. Models can also be http(s) or s3:// URLs; downloads are cached \
+            and revalidated with their ETag, so unchanged files are not downloaded again. With --watch, the analysis re-runs on every change to \
+            the model (and its OpenSCAD includes) and prints the metrics that changed.
+            """
+    )
+
+    @Argument(help: "Model files (.stl, .3mf, .scad, .py or a plugin format) or http(s)/s3 URLs", completion: .renderableModelFiles)
+    var models: [String]
+
+    @Option(name: .shortAndLong, help: "Output format (\(AnalysisOutputFormat.allCases.map(\.rawValue).joined(separator: ", ")))")
+    var format: AnalysisOutputFormat = .text
+
+    @Option(help: "Print only this field, one line per model", completion: .list(AnalysisRecord.fieldKeys))
+    var select: String?
+
+    @OptionGroup
+    var csvOptions: CSVOptions
+
+    @Option(help: "Faces within this angle of vertical count as vertical in the area breakdown (degrees)")
+    var verticalTolerance: Double = OrientationAreas.defaultVerticalTolerance
+
+    @Flag(help: "Re-run the analysis on every change and print the changed metrics")
+    var watch = false
+
+    @Option(help: "Material for the mass and cost estimate (\(Material.allCases.map { $0.rawValue.lowercased() }.joined(separator: ", ")))")
+    var material: Material?
+
+    @Option(help: "Infill percentage for the mass estimate (0-100, printed materials only)")
+    var infill: Double?
+
+    @Flag(help: "Add center of mass, inertia tensor, principal moments/axes and radii of gyration")
+    var massProperties = false
+
+    @Flag(help: "Add a tip-over check: stability margin and tilt angle when resting on the bottom face")
+    var stability = false
+
+    /// Material and infill fraction for the estimate, nil if neither option is given
+    private var estimateOptions: (material: Material, infill: Double)? {
+        guard material != nil || infill != nil || massProperties else { return nil }
+        return (material ?? .pla, (infill ?? 100) / 100)
+    }
+
+    func validate() throws {
+        if models.isEmpty {
+            throw ValidationError("Specify at least one model file.")
+        }
+        if watch && (models.count > 1 || format != .text || select != nil) {
+            throw ValidationError("--watch takes a single model and prints text output.")
+        }
+        if watch && RemoteFileCache.remoteURL(models[0]) != nil {
+            throw ValidationError("--watch needs a local file.")
+        }
+        if let infill, !(0...100).contains(infill) {
+            throw ValidationError("--infill must be between 0 and 100.")
+        }
+        if !(0..<90).contains(verticalTolerance) {
+            throw ValidationError("--vertical-tolerance must be an angle from 0 to 90 degrees.")
+        }
+    }
+
+    func run() throws {
+        if watch {
+            let watcher = AnalysisWatcher(
+                url: URL(fileURLWithPath: models[0]).standardizedFileURL,
+                estimate: estimateOptions,
+                verticalTolerance: verticalTolerance
+            )
+            watcher.start()
+            withExtendedLifetime(watcher) {
+                dispatchMain()
+            }
+        }
+
+        let records = try models.map {
+            try Self.record(
+                for: ModelFileLoader.url(forArgument: $0),
+                estimate: estimateOptions,
+                verticalTolerance: verticalTolerance,
+                massProperties: massProperties,
+                stability: stability
+            )
+        }
+
+        let formatter: AnalysisFormatter = select.map { ValueAnalysisFormatter(key: $0) } ?? format.formatter(csv: csvOptions.format)
+        print(try formatter.format(records))
+    }
+
+    /// - Parameters:
+    ///   - verticalTolerance: Angle from vertical for the orientation area breakdown (degrees)
+    ///   - massProperties: Add mass properties for the density of the estimate (PLA if there is none)
+    ///   - stability: Add the tip-over check
+    static func record(
+        for url: URL,
+        estimate: (material: Material, infill: Double)? = nil,
+        verticalTolerance: Double = OrientationAreas.defaultVerticalTolerance,
+        massProperties: Bool = false,
+        stability: Bool = false
+    ) throws -> AnalysisRecord {
+        try record(
+            for: ModelFileLoader.loadRendering(url: url),
+            fileName: url.lastPathComponent,
+            estimate: estimate,
+            verticalTolerance: verticalTolerance,
+            massProperties: massProperties,
+            stability: stability
+        )
+    }
+
+    /// Record of an already loaded model
+    static func record(
+        for model: STLModel,
+        fileName: String,
+        estimate: (material: Material, infill: Double)? = nil,
+        verticalTolerance: Double = OrientationAreas.defaultVerticalTolerance,
+        massProperties: Bool = false,
+        stability: Bool = false
+    ) -> AnalysisRecord {
+        let analysis = model.analyze(verticalTolerance: verticalTolerance)
+        let massEstimate = estimate.map { $0.material.estimate(volume: analysis.volume, infill: $0.infill) }
+        return AnalysisRecord(
+            fileName: fileName,
+            analysis: analysis,
+            estimate: massEstimate,
+            massProperties: massProperties
+                ? model.massProperties(density: massEstimate.map { $0.material.density * $0.infill } ?? Material.pla.density)
+                : nil,
+            stability: stability ? StabilityCheck.check(model: model) : nil
+        )
+    }
+}
+
+extension Material: ExpressibleByArgument {
+    init?(argument: String) {
+        guard let material = Material.named(argument) else { return nil }
+        self = material
+    }
+
+    static var allValueStrings: [String] {
+        allCases.map { $0.rawValue.lowercased() }
+    }
+}
+
+/// Re-analyzes a model whenever it or one of its OpenSCAD includes changes
+private final class AnalysisWatcher: @unchecked Sendable {
+    private let url: URL
+    private let estimate: (material: Material, infill: Double)?
+    private let verticalTolerance: Double
+    private let watcher = FileWatcher()
+    private var previous: AnalysisRecord?
+    private var watchedFiles: [URL] = []
+
+    init(url: URL, estimate: (material: Material, infill: Double)?, verticalTolerance: Double) {
+        self.url = url
+        self.estimate = estimate
+        self.verticalTolerance = verticalTolerance
+    }
+
+    func start() {
+        update()
+    }
+
+    /// Runs on the main queue
+    private func update() {
+        let time = DateFormatter.localizedString(from: Date(), dateStyle: .none, timeStyle: .medium)
+        do {
+            let record = try AnalyzeCommand.record(for: url, estimate: estimate, verticalTolerance: verticalTolerance)
+            if let previous {
+                print("[\(time)] \(url.lastPathComponent)")
+                print(AnalysisDiff(from: previous, to: record).formatted())
+            } else {
+                print(try TextAnalysisFormatter().format([record]))
+            }
+            previous = record
+        } catch {
+            print("[\(time)] \(url.lastPathComponent): \(error.localizedDescription)")
+        }
+        print("")
+
+        // Includes and imports may have been added or removed
+        let files = ModelFileLoader.dependencies(of: url)
+        guard files != watchedFiles else { return }
+        watchedFiles = files
+        do {
+            try watcher.watch(files: files) { [weak self] _ in
+                DispatchQueue.main.async {
+                    self?.update()
+                }
+            }
+        } catch {
+            print("Cannot watch \(url.lastPathComponent): \(error.localizedDescription)")
+        }
+    }
+}
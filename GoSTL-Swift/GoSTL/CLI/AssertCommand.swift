@@ -0,0 +1,126 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl assert <model> --max-x 200 --watertight ...` - validate a model for CI
+struct AssertCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "assert",
+        abstract: "Check a model against size, mesh, wall thickness and stability conditions.",
+        discussion: """
+            Prints one line per check (or a JSON report with --format json) and exits with status 1 \
+            if any check fails, e.g. for pre-merge validation of printable parts.
+            """
+    )
+
+    enum Format: String, CaseIterable, ExpressibleByArgument {
+        case text
+        case json
+    }
+
+    @Argument(help: "Model file (.stl, .3mf or a plugin format) or http(s)/s3 URL", completion: .modelFiles)
+    var model: String
+
+    @Option(help: "Maximum size along X (mm)")
+    var maxX: Double?
+
+    @Option(help: "Maximum size along Y (mm)")
+    var maxY: Double?
+
+    @Option(help: "Maximum size along Z (mm)")
+    var maxZ: Double?
+
+    @Option(help: "Minimum size along X (mm)")
+    var minX: Double?
+
+    @Option(help: "Minimum size along Y (mm)")
+    var minY: Double?
+
+    @Option(help: "Minimum size along Z (mm)")
+    var minZ: Double?
+
+    @Option(help: "Maximum number of triangles")
+    var maxTriangles: Int?
+
+    @Option(help: "Maximum volume (mm³)")
+    var maxVolume: Double?
+
+    @Option(help: "Minimum volume (mm³)")
+    var minVolume: Double?
+
+    @Flag(help: "Require a closed mesh without open or non-manifold edges")
+    var watertight = false
+
+    @Option(help: "Minimum wall thickness (mm)")
+    var minWall: Double?
+
+    @Flag(help: "Require the model to stand on its bottom face without tipping over")
+    var stable = false
+
+    @Option(help: "Minimum tilt before the model tips over (degrees)")
+    var minTilt: Double?
+
+    @Option(name: .shortAndLong, help: "Output format (text, json)")
+    var format: Format = .text
+
+    @Flag(name: .shortAndLong, help: "Only print failed checks")
+    var quiet = false
+
+    /// Conditions in the order they are reported
+    var conditions: [ModelCondition] {
+        var conditions: [ModelCondition] = []
+        for (axis, limit) in [maxX, maxY, maxZ].enumerated() {
+            if let limit { conditions.append(.maxSize(axis: axis, limit: limit)) }
+        }
+        for (axis, limit) in [minX, minY, minZ].enumerated() {
+            if let limit { conditions.append(.minSize(axis: axis, limit: limit)) }
+        }
+        if let maxTriangles { conditions.append(.maxTriangles(maxTriangles)) }
+        if let maxVolume { conditions.append(.maxVolume(maxVolume)) }
+        if let minVolume { conditions.append(.minVolume(minVolume)) }
+        if watertight { conditions.append(.watertight) }
+        if let minWall { conditions.append(.minWallThickness(minWall)) }
+        if stable { conditions.append(.stable) }
+        if let minTilt { conditions.append(.minTiltAngle(minTilt)) }
+        return conditions
+    }
+
+    func validate() throws {
+        if conditions.isEmpty {
+            throw ValidationError("Specify at least one condition, e.g. --max-x 200 or --watertight.")
+        }
+    }
+
+    func run() throws {
+        let url = try ModelFileLoader.url(forArgument: model)
+        let results = ModelConditionEvaluator.evaluate(conditions, on: try ModelFileLoader.load(url: url))
+        let failures = results.filter { !$0.passed }
+
+        switch format {
+        case .text:
+            for result in results where !quiet || !result.passed {
+                let detail = result.detail.map { " \($0)" } ?? ""
+                print("\(result.passed ? "PASS" : "FAIL") \(result.check): \(result.actual) (expected \(result.expected))\(detail)")
+            }
+        case .json:
+            let report = AssertReport(
+                file: url.lastPathComponent,
+                passed: failures.isEmpty,
+                checks: quiet ? failures : results
+            )
+            let encoder = JSONEncoder()
+            encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+            print(String(decoding: try encoder.encode(report), as: UTF8.self))
+        }
+
+        if !failures.isEmpty {
+            throw ExitCode.failure
+        }
+    }
+}
+
+/// JSON output of `gostl assert`
+struct AssertReport: Codable {
+    var file: String
+    var passed: Bool
+    var checks: [ConditionResult]
+}
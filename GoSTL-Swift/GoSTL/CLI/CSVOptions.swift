@@ -0,0 +1,17 @@
+import ArgumentParser
+
+/// `--delimiter` and `--decimal-separator` of commands that write CSV
+struct CSVOptions: ParsableArguments {
+    @Option(help: "CSV field delimiter (comma, semicolon, tab)")
+    var delimiter: CSVFormat.Delimiter = .comma
+
+    @Option(help: "CSV decimal separator (point, comma); use with --delimiter semicolon for European spreadsheets")
+    var decimalSeparator: CSVFormat.DecimalSeparator = .point
+
+    var format: CSVFormat {
+        CSVFormat(delimiter: delimiter, decimalSeparator: decimalSeparator)
+    }
+}
+
+extension CSVFormat.Delimiter: ExpressibleByArgument {}
+extension CSVFormat.DecimalSeparator: ExpressibleByArgument {}
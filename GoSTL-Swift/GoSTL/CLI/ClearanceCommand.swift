@@ -0,0 +1,54 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl clearance <model> <other> [--min 0.5]` - check two models for collisions and minimum clearance
+struct ClearanceCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "clearance",
+        abstract: "Check two models (e.g. an enclosure and a PCB) for collisions and minimum clearance.",
+        discussion: """
+            Both files are compared in their own coordinates. Prints the minimum clearance and where it \
+            occurs, or the interpenetrating triangles, and exits with status 1 if the models intersect \
+            or the clearance is below --min.
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf or a plugin format)", completion: .modelFiles)
+    var model: String
+
+    @Argument(help: "Second model file to check against", completion: .modelFiles)
+    var other: String
+
+    @Option(help: "Minimum required clearance (mm)")
+    var min: Double?
+
+    func run() throws {
+        let first = try ModelFileLoader.load(url: URL(fileURLWithPath: model))
+        let second = try ModelFileLoader.load(url: URL(fileURLWithPath: other))
+
+        guard let result = try ClearanceCheck.check(model: first.triangles, other: second.triangles) else {
+            throw ValidationError("Both models need at least one triangle")
+        }
+
+        if result.isIntersecting {
+            print("Models intersect: \(result.intersectingTriangles.count) triangles of \(URL(fileURLWithPath: model).lastPathComponent), "
+                + "\(result.otherIntersectingTriangles.count) triangles of \(URL(fileURLWithPath: other).lastPathComponent)")
+            if let bounds = result.intersectionBounds(in: first) {
+                print(String(
+                    format: "Region: %.3f x %.3f x %.3f mm at %.3f, %.3f, %.3f",
+                    bounds.size.x, bounds.size.y, bounds.size.z, bounds.center.x, bounds.center.y, bounds.center.z
+                ))
+            }
+            throw ExitCode.failure
+        }
+
+        print(String(format: "Minimum clearance: %.3f mm", result.minimumDistance))
+        print(String(format: "On model: %.3f, %.3f, %.3f", result.pointOnModel.x, result.pointOnModel.y, result.pointOnModel.z))
+        print(String(format: "On other: %.3f, %.3f, %.3f", result.pointOnOther.x, result.pointOnOther.y, result.pointOnOther.z))
+
+        if let min, result.minimumDistance < min {
+            print(String(format: "Clearance is below the required %.3f mm", min))
+            throw ExitCode.failure
+        }
+    }
+}
@@ -0,0 +1,61 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl compare <baseline.json> <current>` - diff measurement values between model revisions
+struct CompareCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "compare",
+        abstract: "Compare exported measurements between model revisions.",
+        discussion: """
+            The current revision is either another measurement export (.json) or a model file; \
+            for a model, the baseline measurements are re-evaluated on it by re-binding their points \
+            to the closest vertices. Exits with status 1 if any change exceeds the threshold.
+            """
+    )
+
+    @Argument(help: "Baseline measurement export (.json)", completion: .file(extensions: ["json"]))
+    var baseline: String
+
+    @Argument(help: "Measurement export (.json) or model file of the new revision", completion: .file())
+    var current: String
+
+    @Option(help: "Maximum allowed change of distances and radii (mm)")
+    var threshold: Double = MeasurementComparison.defaultThreshold
+
+    @Option(help: "Maximum allowed change of angles (degrees)")
+    var angleThreshold: Double = MeasurementComparison.defaultAngleThreshold
+
+    @Option(help: "Search radius for re-binding points to vertices of a model (mm)")
+    var searchRadius: Double = 0.5
+
+    @Flag(help: "Print CSV instead of a table")
+    var csv = false
+
+    @OptionGroup
+    var csvOptions: CSVOptions
+
+    func run() throws {
+        let baselineSet = try MeasurementSet.load(from: URL(fileURLWithPath: baseline))
+
+        let currentURL = URL(fileURLWithPath: current)
+        let currentSet: MeasurementSet
+        if currentURL.pathExtension.lowercased() == "json" {
+            currentSet = try MeasurementSet.load(from: currentURL)
+        } else {
+            let model = try ModelFileLoader.load(url: currentURL)
+            currentSet = baselineSet.reevaluated(on: model, fileName: currentURL.lastPathComponent, searchRadius: searchRadius)
+        }
+
+        let comparison = MeasurementComparison.compare(
+            baseline: baselineSet,
+            current: currentSet,
+            threshold: threshold,
+            angleThreshold: angleThreshold
+        )
+        print(csv ? comparison.csv(format: csvOptions.format) : comparison.table(), terminator: csv ? "" : "\n")
+
+        if !comparison.exceeded.isEmpty {
+            throw ExitCode.failure
+        }
+    }
+}
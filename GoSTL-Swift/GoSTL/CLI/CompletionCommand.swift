@@ -0,0 +1,42 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl completion <shell>` - print a shell completion script
+struct CompletionCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "completion",
+        abstract: "Print a shell completion script.",
+        discussion: """
+            Completes subcommands, options, model files and analysis fields. Install with e.g.
+              gostl completion zsh > "${fpath[1]}/_gostl"
+              gostl completion bash > /usr/local/etc/bash_completion.d/gostl
+              gostl completion fish > ~/.config/fish/completions/gostl.fish
+            """
+    )
+
+    @Argument(help: "Shell (bash, zsh, fish)", completion: .list(CompletionShell.allCases.map(\.rawValue)))
+    var shell: String
+
+    func validate() throws {
+        guard CompletionShell(rawValue: shell) != nil else {
+            throw ValidationError("Unsupported shell \"\(shell)\". Supported: \(CompletionShell.allCases.map(\.rawValue).joined(separator: ", "))")
+        }
+    }
+
+    func run() throws {
+        guard let shell = CompletionShell(rawValue: shell) else { return }
+        print(GoSTLCommand.completionScript(for: shell))
+    }
+}
+
+extension CompletionKind {
+    /// Model files the headless commands can load (built-in formats and plugin importers)
+    static var modelFiles: CompletionKind {
+        .file(extensions: ModelFileLoader.supportedExtensions + PluginRegistry.shared.importExtensions)
+    }
+
+    /// Model files including OpenSCAD sources (rendered before analysis)
+    static var renderableModelFiles: CompletionKind {
+        .file(extensions: ModelFileLoader.renderableExtensions + PluginRegistry.shared.importExtensions)
+    }
+}
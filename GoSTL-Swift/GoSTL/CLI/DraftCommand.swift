@@ -0,0 +1,75 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl draft <model> --pull z --min 1` - report faces with too little draft for molding
+struct DraftCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "draft",
+        abstract: "Check the draft angles of a model for molding and casting.",
+        discussion: """
+            The draft of a face is its angle to the pull direction (the direction the mold is pulled off): \
+            90° for faces looking along it, 0° for walls parallel to it, negative for faces turned away. \
+            Prints the area below --min degrees and the area on the opposite side (undercuts for a \
+            one-part mold), and exits with status 1 if any face has less than the minimum draft. \
+            Use --pull=-z for negative directions. With --colors, the model is also written with the draft \
+            colors baked in as vertex colors (.ply or .3mf), e.g. to share the result with other viewers.
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf or a plugin format)", completion: .modelFiles)
+    var model: String
+
+    @Option(help: "Pull direction (x, -x, y, -y, z, -z)")
+    var pull: DraftAnalysis.PullDirection = .z
+
+    @Option(help: "Minimum required draft (degrees)")
+    var min: Double = DraftAnalysis.defaultMinimumAngle
+
+    @Flag(help: "Fail on faces turned away from the pull direction as well (one-part molds)")
+    var noUndercuts = false
+
+    @Option(help: "Also write the model with the draft colors as vertex colors (.ply or .3mf)", completion: .file(extensions: ["ply", "3mf"]))
+    var colors: String?
+
+    func validate() throws {
+        if min < 0 || min >= 90 {
+            throw ValidationError("--min must be an angle from 0 to 90 degrees.")
+        }
+        if let colors, ColoredMeshExporter.Format(url: URL(fileURLWithPath: colors)) == nil {
+            throw ValidationError("--colors must be a .ply or .3mf file.")
+        }
+    }
+
+    func run() throws {
+        let url = URL(fileURLWithPath: model)
+        let stl = try ModelFileLoader.load(url: url)
+        let result = try DraftAnalysis.analyze(stl.triangles, pullDirection: pull, minimumAngle: min)
+        guard result.totalArea > 0 else {
+            throw ValidationError("The model has no faces")
+        }
+
+        let share = { (area: Double) in area / result.totalArea * 100 }
+        print("Pull direction: \(pull.displayName)")
+        print(String(format: "Below %g° draft: %d triangles, %.2f mm² (%.1f%%)",
+                     min, result.insufficientTriangles.count, result.insufficientArea, share(result.insufficientArea)))
+        print(String(format: "Opposite side: %d triangles, %.2f mm² (%.1f%%)",
+                     result.oppositeTriangles.count, result.oppositeArea, share(result.oppositeArea)))
+
+        if let colors {
+            try ColoredMeshExporter.export(
+                triangles: stl.triangles,
+                colors: DraftAnalysis.colors(for: result),
+                materialColor: Material.pla.baseColor,
+                name: url.lastPathComponent,
+                to: URL(fileURLWithPath: colors)
+            )
+            print(colors)
+        }
+
+        if !result.insufficientTriangles.isEmpty || (noUndercuts && !result.oppositeTriangles.isEmpty) {
+            throw ExitCode.failure
+        }
+    }
+}
+
+extension DraftAnalysis.PullDirection: ExpressibleByArgument {}
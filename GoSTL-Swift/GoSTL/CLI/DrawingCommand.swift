@@ -0,0 +1,75 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl drawing <model>` - export a technical drawing sheet as SVG or PDF
+struct DrawingCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "drawing",
+        abstract: "Export front, top, right and isometric views with overall dimensions as SVG or PDF.",
+        discussion: """
+            Draws the visible silhouette and feature edges of each view (hidden lines removed) on an A4 \
+            landscape sheet in third-angle projection, with the overall width, depth and height in mm and a \
+            title block. The scale is the largest standard scale that fits unless --scale is given \
+            (e.g. --scale 1:2). The format follows the output file extension (default: <model>.svg). \
+            OpenSCAD files are rendered first.
+            """
+    )
+
+    enum Format: String, CaseIterable, ExpressibleByArgument {
+        case svg
+        case pdf
+    }
+
+    @Argument(help: "Model file (.stl, .3mf, .scad, .py or a plugin format)", completion: .renderableModelFiles)
+    var model: String
+
+    @Option(name: .shortAndLong, help: "Output file (.svg or .pdf)", completion: .file(extensions: Format.allCases.map(\.rawValue)))
+    var output: String?
+
+    @Option(name: .shortAndLong, help: "Output format when it does not follow from the output file (svg, pdf)")
+    var format: Format?
+
+    @Option(name: .customLong("view"), help: "View to draw, repeatable (\(TechnicalDrawing.View.allCases.map(\.rawValue).joined(separator: ", "))); default: all")
+    var views: [TechnicalDrawing.View] = []
+
+    @Option(help: "Drawing scale, e.g. 1:2, 2:1 or 0.5")
+    var scale: String?
+
+    func validate() throws {
+        if let scale, TechnicalDrawing.parseScale(scale) == nil {
+            throw ValidationError("--scale must be a positive ratio like 1:2 or a factor like 0.5.")
+        }
+        if let output, format == nil, Format(rawValue: URL(fileURLWithPath: output).pathExtension.lowercased()) == nil {
+            throw ValidationError("Use an .svg or .pdf output file, or specify --format.")
+        }
+    }
+
+    func run() throws {
+        let url = URL(fileURLWithPath: model)
+        let format = self.format ?? output.flatMap { Format(rawValue: URL(fileURLWithPath: $0).pathExtension.lowercased()) } ?? .svg
+        let destination = output.map { URL(fileURLWithPath: $0) }
+            ?? url.deletingPathExtension().appendingPathExtension(format.rawValue)
+
+        let drawing = TechnicalDrawing(
+            model: try ModelFileLoader.loadRendering(url: url),
+            title: url.lastPathComponent,
+            views: views.isEmpty ? TechnicalDrawing.View.allCases : views,
+            scale: scale.flatMap(TechnicalDrawing.parseScale)
+        )
+        let sheet = drawing.sheet()
+
+        switch format {
+        case .svg:
+            try sheet.svg(title: drawing.title).write(to: destination, atomically: true, encoding: .utf8)
+        case .pdf:
+            guard let data = sheet.pdf(title: drawing.title) else {
+                print("Could not create the PDF document")
+                throw ExitCode.failure
+            }
+            try data.write(to: destination, options: .atomic)
+        }
+        print("\(destination.path) (scale \(TechnicalDrawing.scaleLabel(drawing.scale)))")
+    }
+}
+
+extension TechnicalDrawing.View: ExpressibleByArgument {}
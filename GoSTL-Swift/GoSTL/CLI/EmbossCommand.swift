@@ -0,0 +1,134 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl emboss <model> <text>` - raised or engraved text on a flat face
+struct EmbossCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "emboss",
+        abstract: "Emboss or engrave text on a flat face of a model.",
+        discussion: """
+            Puts the text on the outermost flat face towards --face, centered unless --offset is given, \
+            raised by --depth mm or cut --depth mm deep with --engrave. Letters are --height mm high \
+            (default: as large as fits the face) in a 5×7 pixel font (letters, digits, space and . - + / %; \
+            --font bold for wider strokes) and read upright: towards +Z on side faces, towards +Y on top and \
+            bottom faces, turned by --rotate degrees. A closed model stays closed. Writes \
+            <model>-embossed.stl or <model>-engraved.stl unless --out is given.
+            """
+    )
+
+    enum Side: String, CaseIterable, ExpressibleByArgument {
+        case top, bottom, front, back, left, right
+
+        var direction: Vector3 {
+            switch self {
+            case .top: return .unitZ
+            case .bottom: return Vector3(0, 0, -1)
+            case .front: return Vector3(0, -1, 0)
+            case .back: return .unitY
+            case .left: return Vector3(-1, 0, 0)
+            case .right: return .unitX
+            }
+        }
+    }
+
+    @Argument(help: "Model file (.stl, .3mf, a plugin format or an http(s)/s3 URL)", completion: .modelFiles)
+    var model: String
+
+    @Argument(help: "Text, e.g. a part ID")
+    var text: String
+
+    @Option(help: "Face to put the text on (\(Side.allCases.map(\.rawValue).joined(separator: ", ")))")
+    var face: Side = .top
+
+    @Flag(help: "Cut the text into the face instead of raising it")
+    var engrave = false
+
+    @Option(help: "Height of raised or depth of engraved text (mm)")
+    var depth: Double = ModelGenerator.reliefDepth
+
+    @Option(help: "Letter height (mm); default: as large as fits the face")
+    var height: Double?
+
+    @Option(help: "Font weight (\(BlockFont.Style.allCases.map(\.rawValue).joined(separator: ", ")))")
+    var font: BlockFont.Style = .regular
+
+    @Option(help: "Move the text from the face center, along the text and up (mm), e.g. 0,-8")
+    var offset: String?
+
+    @Option(help: "Turn the text on the face (degrees, counter-clockwise)")
+    var rotate: Double = 0
+
+    @Option(name: .shortAndLong, help: "Output file (.stl, .stl.gz or .stl.zst)", completion: .file(extensions: ["stl"]))
+    var out: String?
+
+    var relief: TextRelief {
+        get throws {
+            var relief = TextRelief(text: text, mode: engrave ? .engrave : .emboss, depth: depth, letterHeight: height, style: font, rotation: rotate)
+            if let offset {
+                let values = offset.split(separator: ",").map { Double($0.trimmingCharacters(in: .whitespaces)) }
+                guard values.count == 2, let x = values[0], let y = values[1] else {
+                    throw ValidationError("--offset must be two numbers, e.g. 0,-8.")
+                }
+                relief.offset = SIMD2(x, y)
+            }
+            return relief
+        }
+    }
+
+    func validate() throws {
+        if !(depth > 0) {
+            throw ValidationError("--depth must be positive.")
+        }
+        if let height, !(height > 0) {
+            throw ValidationError("--height must be positive.")
+        }
+        if let height, height / Double(BlockFont.rows) < BlockFont.minimumPixel {
+            throw ValidationError("--height must be at least \(String(format: "%g", BlockFont.minimumPixel * Double(BlockFont.rows))) mm to print.")
+        }
+        let unsupported = BlockFont.unsupportedCharacters(in: text)
+        if !unsupported.isEmpty {
+            throw ValidationError("Cannot emboss \(unsupported); use letters, digits, space and . - + / %.")
+        }
+        _ = try relief
+    }
+
+    func run() throws {
+        let url = try ModelFileLoader.url(forArgument: model)
+        let fileName = RemoteFileCache.remoteURL(model)?.lastPathComponent ?? url.lastPathComponent
+        let directory = RemoteFileCache.remoteURL(model) == nil
+            ? url.deletingLastPathComponent()
+            : URL(fileURLWithPath: FileManager.default.currentDirectoryPath)
+        let suffix = engrave ? "engraved" : "embossed"
+        let destination = out.map { URL(fileURLWithPath: $0) }
+            ?? directory.appendingPathComponent("\(URL(fileURLWithPath: fileName).deletingPathExtension().lastPathComponent)-\(suffix).stl")
+        guard destination.standardizedFileURL != url.standardizedFileURL else {
+            throw ValidationError("The output file would overwrite the model; specify --out.")
+        }
+
+        let loaded = try ModelFileLoader.loadRendering(url: url)
+        guard let planarFace = Self.face(of: loaded, towards: face) else {
+            throw ValidationError("The model has no flat face towards \(face.rawValue).")
+        }
+        let relief = try self.relief
+        let result = try relief.apply(to: loaded, face: planarFace)
+        try STLExporter.exportBinary(model: result, to: destination)
+
+        let letters = result.triangles.count - loaded.triangles.count
+        print("\(destination.path) (\(result.triangles.count) triangles, \(letters) added)")
+        print("  \"\(text)\" \(suffix) \(String(format: "%g", depth)) mm on the \(face.rawValue) face")
+        if loaded.topology().isWatertight && !result.topology().isWatertight {
+            FileHandle.standardError.write(Data("Warning: the result has open edges; check it in the viewer\n".utf8))
+        }
+    }
+
+    /// The outermost flat face pointing towards a side, the largest of equally far ones
+    static func face(of model: STLModel, towards side: Side) -> ModelAlignment.PlanarFace? {
+        let direction = side.direction
+        let minCosine = cos(ModelAlignment.angleTolerance * .pi / 180)
+        return ModelAlignment.planarFaces(of: model.triangles)
+            .filter { $0.normal.dot(direction) >= minCosine }
+            .max { ($0.offset, $0.area) < ($1.offset, $1.area) }
+    }
+}
+
+extension BlockFont.Style: ExpressibleByArgument {}
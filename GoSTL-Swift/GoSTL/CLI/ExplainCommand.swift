@@ -0,0 +1,74 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl explain [metric]` - describe analysis metrics
+struct ExplainCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "explain",
+        abstract: "Describe analysis metrics, their units and how they are computed.",
+        discussion: """
+            Without an argument, lists all metrics. Accepts metric keys (volume, dimensions.z), \
+            assert options (min-wall, --max-x) and search terms (wall).
+            """
+    )
+
+    @Argument(help: "Metric key, assert option or search term", completion: .list(MetricRegistry.all.map(\.key)))
+    var metric: String?
+
+    func run() throws {
+        guard let term = metric else {
+            print(Self.index())
+            return
+        }
+        print(Self.page(for: try Self.resolve(term)))
+    }
+
+    /// Metric for a key, assert option or unambiguous search term
+    static func resolve(_ term: String) throws -> Metric {
+        if let metric = MetricRegistry.metric(for: term) ?? MetricRegistry.metric(forAssertOption: term) {
+            return metric
+        }
+        let matches = MetricRegistry.search(term)
+        if matches.count == 1 {
+            return matches[0]
+        }
+        if matches.isEmpty {
+            throw ValidationError("Unknown metric \"\(term)\". Run `gostl explain` to list all metrics.")
+        }
+        throw ValidationError("\"\(term)\" matches several metrics: \(matches.map(\.key).joined(separator: ", "))")
+    }
+
+    /// One line per metric: key, unit and summary
+    static func index() -> String {
+        let width = MetricRegistry.all.map(\.key.count).max() ?? 0
+        return MetricRegistry.all.map { metric in
+            let unit = metric.unit.map { " [\($0)]" } ?? ""
+            return metric.key.padding(toLength: width, withPad: " ", startingAt: 0) + "  " + metric.summary + unit
+        }
+        .joined(separator: "\n")
+    }
+
+    /// Man-page style description of a metric
+    static func page(for metric: Metric) -> String {
+        var sections = [
+            ("NAME", "\(metric.key) - \(metric.summary)"),
+            ("LABEL", metric.label),
+            ("UNIT", metric.unit ?? "none"),
+            ("DESCRIPTION", metric.details)
+        ]
+        if !metric.assertOptions.isEmpty {
+            sections.append(("CHECKS", metric.assertOptions.map { "gostl assert \($0)" }.joined(separator: "\n")))
+        }
+        if AnalysisRecord.fieldKeys.contains(metric.key) {
+            sections.append(("OUTPUT", "gostl analyze --select \(metric.key)"))
+        }
+        if !metric.seeAlso.isEmpty {
+            sections.append(("SEE ALSO", metric.seeAlso.joined(separator: ", ")))
+        }
+
+        return sections.map { title, body in
+            title + "\n" + body.split(separator: "\n", omittingEmptySubsequences: false).map { "    " + $0 }.joined(separator: "\n")
+        }
+        .joined(separator: "\n\n")
+    }
+}
@@ -0,0 +1,128 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl fuzz <model>` - write a copy of a model with deliberate mesh defects
+struct FuzzCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "fuzz",
+        abstract: "Damage a model on purpose to test repair tools and viewers.",
+        discussion: """
+            Writes a copy of the model with the chosen defects: --holes punches holes of --hole-size \
+            connected triangles, --flip-normals turns triangles inside out, --duplicates adds triangles a \
+            second time, --degenerate collapses triangles to zero area and --noise moves every vertex by up \
+            to the given distance in mm. Amounts are triangle counts (10) or shares of the mesh (2%). The \
+            damage is random but repeatable: the seed is printed and --seed reproduces it. Writes \
+            <model>-damaged.stl unless --out is given.
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf, a plugin format or an http(s)/s3 URL)", completion: .modelFiles)
+    var model: String
+
+    @Option(help: "Number of holes to punch")
+    var holes: Int = 0
+
+    @Option(name: .customLong("hole-size"), help: "Connected triangles removed per hole")
+    var holeSize: Int = 4
+
+    @Option(name: .customLong("flip-normals"), help: "Triangles to turn inside out (count or percent, e.g. 2%)")
+    var flipNormals: DamageAmount = .none
+
+    @Option(help: "Triangles to duplicate (count or percent)")
+    var duplicates: DamageAmount = .none
+
+    @Option(help: "Triangles to collapse to zero area (count or percent)")
+    var degenerate: DamageAmount = .none
+
+    @Option(help: "Largest random vertex displacement (mm)")
+    var noise: Double = 0
+
+    @Option(help: "Random seed, to repeat a previous run")
+    var seed: UInt64?
+
+    @Option(name: .shortAndLong, help: "Output file (.stl, .stl.gz or .stl.zst)", completion: .file(extensions: ["stl"]))
+    var out: String?
+
+    var damage: MeshDamage {
+        MeshDamage(holes: holes, holeSize: holeSize, flippedNormals: flipNormals, duplicates: duplicates, degenerate: degenerate, noise: noise)
+    }
+
+    func validate() throws {
+        if holes < 0 {
+            throw ValidationError("--holes must not be negative.")
+        }
+        if holeSize < 1 {
+            throw ValidationError("--hole-size must be at least 1.")
+        }
+        if !(noise >= 0) {
+            throw ValidationError("--noise must not be negative.")
+        }
+        if damage.isEmpty {
+            throw ValidationError("Choose at least one defect: --holes, --flip-normals, --duplicates, --degenerate or --noise.")
+        }
+    }
+
+    func run() throws {
+        let url = try ModelFileLoader.url(forArgument: model)
+        let fileName = RemoteFileCache.remoteURL(model)?.lastPathComponent ?? url.lastPathComponent
+        let directory = RemoteFileCache.remoteURL(model) == nil
+            ? url.deletingLastPathComponent()
+            : URL(fileURLWithPath: FileManager.default.currentDirectoryPath)
+        let destination = out.map { URL(fileURLWithPath: $0) }
+            ?? directory.appendingPathComponent("\(URL(fileURLWithPath: fileName).deletingPathExtension().lastPathComponent)-damaged.stl")
+        guard destination.standardizedFileURL != url.standardizedFileURL else {
+            throw ValidationError("The output file would overwrite the model; specify --out.")
+        }
+
+        let loaded = try ModelFileLoader.load(url: url)
+        guard !loaded.triangles.isEmpty else {
+            throw STLExportError.emptyModel
+        }
+
+        let seed = seed ?? UInt64.random(in: 0...UInt64.max)
+        let (damaged, report) = damage.apply(to: loaded, seed: seed)
+        try STLExporter.exportBinary(model: damaged, to: destination)
+
+        print(destination.path)
+        for line in Self.summary(report) {
+            print("  \(line)")
+        }
+        let topology = damaged.topology()
+        print("  Result: \(topology.boundaryEdges) open edges, \(topology.nonManifoldEdges) non-manifold edges")
+        print("  Seed: \(seed) (repeat with --seed \(seed))")
+    }
+
+    /// One line per applied defect
+    static func summary(_ report: MeshDamage.Report) -> [String] {
+        var lines: [String] = []
+        if report.holes > 0 {
+            lines.append("Punched \(report.holes) hole\(report.holes == 1 ? "" : "s") (\(report.removedTriangles) triangles removed)")
+        }
+        if report.flippedNormals > 0 {
+            lines.append("Flipped \(report.flippedNormals) normals")
+        }
+        if report.duplicatedTriangles > 0 {
+            lines.append("Duplicated \(report.duplicatedTriangles) triangles")
+        }
+        if report.degenerateTriangles > 0 {
+            lines.append("Collapsed \(report.degenerateTriangles) triangles to zero area")
+        }
+        if report.movedVertices > 0 {
+            lines.append("Moved \(report.movedVertices) vertices")
+        }
+        return lines
+    }
+}
+
+extension DamageAmount: ExpressibleByArgument {
+    init?(argument: String) {
+        self.init(argument)
+    }
+
+    var defaultValueDescription: String {
+        switch self {
+        case .count(let count): return "\(count)"
+        case .fraction(let fraction): return "\(fraction * 100)%"
+        }
+    }
+}
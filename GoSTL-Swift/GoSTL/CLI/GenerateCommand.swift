@@ -0,0 +1,151 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl generate <shape>` - write parametric test and calibration models as STL
+struct GenerateCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "generate",
+        abstract: "Generate primitives and calibration models as STL.",
+        discussion: """
+            Shapes: cube (--size, e.g. 20 or 30x20x10), sphere (--r), cylinder (--r, --h), torus (--r of the \
+            tube, --major-r to its center), calibration-cube (--size, X/Y/Z embossed on their faces and \
+            --text on the left face) and tolerance-gauge (a plate with holes of --pin-d plus each of \
+            --clearances, labeled, and the pin). Models stand on z = 0, centered on the Z axis. Round \
+            shapes are smooth to 0.02 mm unless --segments is given. Writes <shape>.stl unless -o is given \
+            (.stl.gz and .stl.zst are compressed).
+            """
+    )
+
+    enum Shape: String, CaseIterable, ExpressibleByArgument {
+        case cube
+        case sphere
+        case cylinder
+        case torus
+        case calibrationCube = "calibration-cube"
+        case toleranceGauge = "tolerance-gauge"
+
+        /// Options that apply to the shape (by long name)
+        var options: Set<String> {
+            switch self {
+            case .cube: return ["size"]
+            case .sphere: return ["r", "segments"]
+            case .cylinder: return ["r", "h", "segments"]
+            case .torus: return ["r", "major-r", "segments"]
+            case .calibrationCube: return ["size", "text"]
+            case .toleranceGauge: return ["pin-d", "clearances", "thickness"]
+            }
+        }
+    }
+
+    /// Tube radius of a torus without --r (mm)
+    static let defaultTubeRadius = 5.0
+
+    @Argument(help: "Shape (\(Shape.allCases.map(\.rawValue).joined(separator: ", ")))")
+    var shape: Shape
+
+    @Option(name: .customLong("size"), help: "Edge length, or WIDTHxDEPTHxHEIGHT for a cube (mm)")
+    var size: String?
+
+    @Option(name: .customLong("r"), help: "Radius (mm); the tube radius of a torus")
+    var radius: Double?
+
+    @Option(name: .customLong("h"), help: "Height of a cylinder (mm)")
+    var height: Double?
+
+    @Option(name: .customLong("major-r"), help: "Distance from the center of a torus to the center of its tube (mm)")
+    var majorRadius: Double?
+
+    @Option(help: "Segments of round shapes (default: smooth to 0.02 mm)")
+    var segments: Int?
+
+    @Option(help: "Text embossed on the left face of the calibration cube, e.g. \"PLA\"")
+    var text: String?
+
+    @Option(name: .customLong("pin-d"), help: "Nominal pin diameter of the tolerance gauge (mm)")
+    var pinDiameter: Double?
+
+    @Option(help: "Comma-separated hole clearances of the tolerance gauge (mm)")
+    var clearances: String?
+
+    @Option(help: "Plate thickness of the tolerance gauge (mm)")
+    var thickness: Double?
+
+    @Option(name: .shortAndLong, help: "Output file (.stl, .stl.gz or .stl.zst)", completion: .file(extensions: ["stl"]))
+    var output: String?
+
+    func validate() throws {
+        let given: [(String, Bool)] = [
+            ("size", size != nil), ("r", radius != nil), ("h", height != nil), ("major-r", majorRadius != nil),
+            ("segments", segments != nil), ("text", text != nil), ("pin-d", pinDiameter != nil),
+            ("clearances", clearances != nil), ("thickness", thickness != nil)
+        ]
+        if let option = given.first(where: { $0.1 && !shape.options.contains($0.0) })?.0 {
+            throw ValidationError("--\(option) does not apply to \(shape.rawValue).")
+        }
+        if let size, Self.parseSize(size) == nil {
+            throw ValidationError("--size must be a positive length like 20, or 30x20x10.")
+        }
+        if let size, shape == .calibrationCube, Self.parseSize(size).map({ $0.x != $0.y || $0.y != $0.z }) == true {
+            throw ValidationError("--size of a calibration cube is a single edge length.")
+        }
+        for (name, value) in [("r", radius), ("h", height), ("major-r", majorRadius), ("pin-d", pinDiameter), ("thickness", thickness)] {
+            if let value, !(value > 0) {
+                throw ValidationError("--\(name) must be positive.")
+            }
+        }
+        if let majorRadius, majorRadius <= radius ?? Self.defaultTubeRadius {
+            throw ValidationError("--major-r must be larger than --r, or the torus intersects itself.")
+        }
+        if let segments, !(3...1000).contains(segments) {
+            throw ValidationError("--segments must be between 3 and 1000.")
+        }
+        if let clearances, Self.parseClearances(clearances) == nil {
+            throw ValidationError("--clearances must be comma-separated non-negative numbers, e.g. 0.1,0.2,0.3.")
+        }
+    }
+
+    func run() throws {
+        let model: STLModel
+        switch shape {
+        case .cube:
+            model = ModelGenerator.cube(size: size.flatMap(Self.parseSize) ?? Vector3(20, 20, 20))
+        case .sphere:
+            model = ModelGenerator.sphere(radius: radius ?? 10, segments: segments)
+        case .cylinder:
+            model = ModelGenerator.cylinder(radius: radius ?? 10, height: height ?? 20, segments: segments)
+        case .torus:
+            let tube = radius ?? Self.defaultTubeRadius
+            model = ModelGenerator.torus(majorRadius: majorRadius ?? tube * 3, minorRadius: tube, segments: segments, sides: segments)
+        case .calibrationCube:
+            model = try ModelGenerator.calibrationCube(size: size.flatMap(Self.parseSize)?.x ?? 20, text: text)
+        case .toleranceGauge:
+            model = try ModelGenerator.toleranceGauge(
+                pinDiameter: pinDiameter ?? 5,
+                clearances: clearances.flatMap(Self.parseClearances) ?? ModelGenerator.defaultClearances,
+                thickness: thickness ?? 4
+            )
+        }
+
+        let destination = URL(fileURLWithPath: output ?? "\(shape.rawValue).stl")
+        try STLExporter.exportBinary(model: model, to: destination)
+        let dimensions = model.boundingBox().size
+        print("\(destination.path) (\(model.triangles.count) triangles, \(String(format: "%.2f × %.2f × %.2f mm", dimensions.x, dimensions.y, dimensions.z)))")
+    }
+
+    /// "20" (cube) or "30x20x10"
+    static func parseSize(_ text: String) -> Vector3? {
+        let values = text.lowercased().split { $0 == "x" || $0 == "×" }.map { Double($0.trimmingCharacters(in: .whitespaces)) }
+        guard values.allSatisfy({ ($0 ?? 0) > 0 }) else { return nil }
+        switch values.count {
+        case 1: return values[0].map { Vector3($0, $0, $0) }
+        case 3: return Vector3(values[0]!, values[1]!, values[2]!)
+        default: return nil
+        }
+    }
+
+    static func parseClearances(_ text: String) -> [Double]? {
+        let values = text.split(separator: ",").map { Double($0.trimmingCharacters(in: .whitespaces)) }
+        guard !values.isEmpty, values.allSatisfy({ ($0 ?? -1) >= 0 }) else { return nil }
+        return values.compactMap { $0 }
+    }
+}
@@ -0,0 +1,28 @@
+import ArgumentParser
+import Foundation
+
+/// Headless command-line interface (`gostl <subcommand> ...`)
+struct GoSTLCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "gostl",
+        abstract: "Headless tools for STL/3MF inspection. Run without a subcommand to open the viewer.",
+        subcommands: [
+            OpenCommand.self, AnalyzeCommand.self, AssertCommand.self, CompareCommand.self,
+            ClearanceCommand.self, HeightmapCommand.self, SectionsCommand.self, DraftCommand.self, TUICommand.self,
+            ShareCommand.self, LabelCommand.self, ExplainCommand.self, ThumbsCommand.self, SelfTestRenderCommand.self,
+            ImageDiffCommand.self, GenerateCommand.self, FuzzCommand.self, MergeCommand.self, PatternCommand.self,
+            EmbossCommand.self, ManifestCommand.self, DrawingCommand.self, PolyhedronCommand.self, ScadVariablesCommand.self, SketchCommand.self, PrimitivesCommand.self, ScriptCommand.self,
+            PluginsCommand.self, CompletionCommand.self, InstallIntegrationCommand.self
+        ]
+    )
+
+    /// Whether the arguments select a headless subcommand instead of launching the viewer
+    static func handles(_ arguments: [String]) -> Bool {
+        guard let first = arguments.dropFirst().first else { return false }
+        // Help, and the callbacks completion scripts use to complete custom values
+        if first == "help" || first == "--help" || first == "-h" || first.hasPrefix("---completion") {
+            return true
+        }
+        return configuration.subcommands.contains { $0.configuration.commandName == first }
+    }
+}
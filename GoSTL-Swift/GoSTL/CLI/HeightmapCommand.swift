@@ -0,0 +1,66 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl heightmap <model> --axis z --resolution 0.1 --out depth.png` - grayscale height map of the top surface
+struct HeightmapCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "heightmap",
+        abstract: "Export a height map (depth image) of a model's top surface.",
+        discussion: """
+            Samples the highest surface seen from the positive side of --axis on a grid with --resolution mm \
+            spacing. The PNG is 16-bit grayscale from the lowest (dark) to the highest (white) height; black \
+            pixels have no surface. With --csv, the heights in mm are also written as a matrix (one image \
+            row per line, empty cells without surface), e.g. for CNC probing comparisons.
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf or a plugin format)", completion: .modelFiles)
+    var model: String
+
+    @Option(help: "Axis the surface is seen from")
+    var axis: HeightMap.Axis = .z
+
+    @Option(help: "Grid spacing (mm)")
+    var resolution: Double = 0.1
+
+    @Option(name: .shortAndLong, help: "Output PNG file (default: <model>-heightmap.png)", completion: .file(extensions: ["png"]))
+    var out: String?
+
+    @Option(help: "Also write the height matrix to this CSV file", completion: .file(extensions: ["csv"]))
+    var csv: String?
+
+    @OptionGroup
+    var csvOptions: CSVOptions
+
+    func validate() throws {
+        if resolution <= 0 {
+            throw ValidationError("--resolution must be a positive grid spacing in mm.")
+        }
+    }
+
+    func run() throws {
+        let url = URL(fileURLWithPath: model)
+        let destination = out.map { URL(fileURLWithPath: $0) }
+            ?? url.deletingLastPathComponent().appendingPathComponent("\(url.deletingPathExtension().lastPathComponent)-heightmap.png")
+        let loaded = try ModelFileLoader.load(url: url)
+
+        let map = try HeightMap.compute(model: loaded, axis: axis, resolution: resolution)
+        guard let image = map.grayscaleImage() else {
+            throw OffscreenRenderError.encodingFailed
+        }
+        try OffscreenRenderer.pngData(image).write(to: destination)
+        print(destination.path)
+
+        if let csv {
+            try map.csv(format: csvOptions.format).write(to: URL(fileURLWithPath: csv), atomically: true, encoding: .utf8)
+            print(csv)
+        }
+
+        print("\(map.width) x \(map.height) px at \(String(format: "%.3f", resolution)) mm")
+        if let range = map.range {
+            print(String(format: "Height %.3f to %.3f mm (range %.3f mm)", range.lowerBound, range.upperBound, range.upperBound - range.lowerBound))
+        }
+    }
+}
+
+extension HeightMap.Axis: ExpressibleByArgument {}
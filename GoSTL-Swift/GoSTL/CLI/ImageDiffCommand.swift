@@ -0,0 +1,58 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl imagediff <a> <b>` - perceptual comparison of two images
+struct ImageDiffCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "imagediff",
+        abstract: "Compare two images the way people see them.",
+        discussion: """
+            Compares the images pixel by pixel in CIELAB color space: differences below --threshold \
+            (ΔE, 2.3 is about the smallest difference people notice) and anti-aliased edges shifted by a \
+            pixel are ignored. Prints the number and share of differing pixels and exits with status 1 if \
+            the share exceeds --tolerance percent, e.g. to check renders or thumbnails in CI. --diff writes \
+            a PNG with the differing pixels in red.
+            """
+    )
+
+    @Argument(help: "First image (PNG, JPEG, TIFF, ...)", completion: .file(extensions: ["png", "jpg", "jpeg", "tiff"]))
+    var first: String
+
+    @Argument(help: "Second image of the same size", completion: .file(extensions: ["png", "jpg", "jpeg", "tiff"]))
+    var second: String
+
+    @Option(help: "Write an image with the differing pixels in red (PNG)", completion: .file(extensions: ["png"]))
+    var diff: String?
+
+    @Option(help: "Color difference (CIE ΔE) below which pixels count as equal")
+    var threshold = PerceptualDiff.defaultThreshold
+
+    @Option(help: "Share of differing pixels (percent) that still counts as equal")
+    var tolerance = PerceptualDiff.defaultTolerance
+
+    func validate() throws {
+        if threshold <= 0 {
+            throw ValidationError("--threshold must be positive.")
+        }
+        if !(0...100).contains(tolerance) {
+            throw ValidationError("--tolerance must be between 0 and 100.")
+        }
+    }
+
+    func run() throws {
+        let result = try PerceptualDiff.compare(
+            PerceptualDiff.loadImage(at: URL(fileURLWithPath: first)),
+            PerceptualDiff.loadImage(at: URL(fileURLWithPath: second)),
+            threshold: threshold
+        )
+        if let diff, let image = result.diffImage {
+            try OffscreenRenderer.pngData(image).write(to: URL(fileURLWithPath: diff), options: .atomic)
+        }
+
+        print(String(format: "%d of %d pixels differ (%.3f%%), max ΔE %.1f",
+                     result.differingPixels, result.width * result.height, result.percentage, result.maxDeltaE))
+        if !result.passes(tolerance: tolerance) {
+            throw ExitCode.failure
+        }
+    }
+}
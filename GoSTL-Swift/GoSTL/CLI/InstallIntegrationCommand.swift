@@ -0,0 +1,146 @@
+import AppKit
+import ArgumentParser
+import Foundation
+import UniformTypeIdentifiers
+
+/// `gostl install-integration` - make GoSTL.app the handler for model files and gostl:// links
+struct InstallIntegrationCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "install-integration",
+        abstract: "Register GoSTL as the default viewer for .stl, .3mf and .scad files and gostl:// links.",
+        discussion: """
+            Adds the document types and the gostl URL scheme to the app's Info.plist if they are missing, \
+            registers the app with Launch Services and makes it the default handler. The viewer runs on \
+            macOS only; there is no desktop entry or registry integration for other systems.
+            """
+    )
+
+    @Option(help: "Path to GoSTL.app (default: next to this executable, then ~/Applications and /Applications)", completion: .directory)
+    var app: String?
+
+    @Option(help: "File extensions to open with GoSTL")
+    var extensions = ["stl", "3mf", "scad"]
+
+    @Flag(inversion: .prefixedNo, help: "Make GoSTL the default handler (otherwise it is only offered under Open With)")
+    var setDefault = true
+
+    @Flag(help: "Print the steps without changing anything")
+    var dryRun = false
+
+    static let lsregister = "/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister"
+
+    func run() throws {
+        guard let appURL = app.map({ URL(fileURLWithPath: $0) }) ?? Self.locateApp() else {
+            throw ValidationError("GoSTL.app not found. Pass its location with --app.")
+        }
+        print("App: \(appURL.path)")
+
+        let plistURL = appURL.appendingPathComponent("Contents/Info.plist")
+        let data = try Data(contentsOf: plistURL)
+        guard let plist = try PropertyListSerialization.propertyList(from: data, format: nil) as? [String: Any] else {
+            throw ValidationError("\(plistURL.path) is not a property list.")
+        }
+        if let updated = Self.integrated(plist, extensions: extensions) {
+            print("Adding document types and the \(GoSTLLink.scheme):// scheme to Info.plist")
+            if !dryRun {
+                try PropertyListSerialization.data(fromPropertyList: updated, format: .xml, options: 0).write(to: plistURL)
+            }
+        }
+
+        print("Registering with Launch Services")
+        if !dryRun {
+            let process = Process()
+            process.executableURL = URL(fileURLWithPath: Self.lsregister)
+            process.arguments = ["-f", appURL.path]
+            try process.run()
+            process.waitUntilExit()
+        }
+
+        guard setDefault else { return }
+        var failures: [String] = []
+        for ext in extensions {
+            guard let type = UTType(filenameExtension: ext) else { continue }
+            print("Default viewer for .\(ext)")
+            if !dryRun, let error = Self.wait({ NSWorkspace.shared.setDefaultApplication(at: appURL, toOpen: type, completion: $0) }) {
+                failures.append(".\(ext): \(error.localizedDescription)")
+            }
+        }
+        print("Default handler for \(GoSTLLink.scheme):// links")
+        if !dryRun, let error = Self.wait({ NSWorkspace.shared.setDefaultApplication(at: appURL, toOpenURLsWithScheme: GoSTLLink.scheme, completion: $0) }) {
+            failures.append("\(GoSTLLink.scheme)://: \(error.localizedDescription)")
+        }
+
+        if !failures.isEmpty {
+            failures.forEach { print("Failed: \($0)") }
+            throw ExitCode.failure
+        }
+    }
+
+    /// GoSTL.app of this installation (Homebrew puts it next to bin/), or in an Applications folder
+    static func locateApp(
+        executable: URL = Bundle.main.executableURL ?? URL(fileURLWithPath: CommandLine.arguments[0]),
+        home: URL = FileManager.default.homeDirectoryForCurrentUser
+    ) -> URL? {
+        let resolved = executable.resolvingSymlinksInPath()
+        var candidates: [URL] = []
+        if let bundle = resolved.pathComponents.firstIndex(where: { $0.hasSuffix(".app") }) {
+            candidates.append(URL(fileURLWithPath: NSString.path(withComponents: Array(resolved.pathComponents[...bundle]))))
+        }
+        candidates.append(resolved.deletingLastPathComponent().deletingLastPathComponent().appendingPathComponent("GoSTL.app"))
+        candidates.append(home.appendingPathComponent("Applications/GoSTL.app"))
+        candidates.append(URL(fileURLWithPath: "/Applications/GoSTL.app"))
+        return candidates.first { FileManager.default.fileExists(atPath: $0.appendingPathComponent("Contents/Info.plist").path) }
+    }
+
+    /// The Info.plist with document types for the extensions and the gostl URL scheme, or nil if nothing is missing
+    static func integrated(_ plist: [String: Any], extensions: [String]) -> [String: Any]? {
+        var plist = plist
+        var changed = false
+
+        var documentTypes = plist["CFBundleDocumentTypes"] as? [[String: Any]] ?? []
+        let declared = Set(documentTypes.flatMap { ($0["CFBundleTypeExtensions"] as? [String] ?? []).map { $0.lowercased() } })
+        for ext in extensions where !declared.contains(ext.lowercased()) {
+            documentTypes.append([
+                "CFBundleTypeName": "\(ext.uppercased()) File",
+                "CFBundleTypeRole": "Viewer",
+                "LSHandlerRank": "Default",
+                "CFBundleTypeExtensions": [ext.lowercased(), ext.uppercased()]
+            ])
+            changed = true
+        }
+        plist["CFBundleDocumentTypes"] = documentTypes
+
+        var urlTypes = plist["CFBundleURLTypes"] as? [[String: Any]] ?? []
+        let schemes = urlTypes.flatMap { $0["CFBundleURLSchemes"] as? [String] ?? [] }
+        if !schemes.contains(GoSTLLink.scheme) {
+            urlTypes.append([
+                "CFBundleURLName": "GoSTL Link",
+                "CFBundleURLSchemes": [GoSTLLink.scheme]
+            ])
+            changed = true
+        }
+        plist["CFBundleURLTypes"] = urlTypes
+
+        return changed ? plist : nil
+    }
+
+    /// Run a Launch Services call and wait for its completion handler
+    /// (spins the run loop, since the handler may be delivered on the main queue)
+    private static func wait(_ call: (@escaping @Sendable (Error?) -> Void) -> Void) -> Error? {
+        final class Result: @unchecked Sendable {
+            var done = false
+            var error: Error?
+        }
+        let result = Result()
+        call { error in
+            DispatchQueue.main.async {
+                result.error = error
+                result.done = true
+            }
+        }
+        while !result.done {
+            RunLoop.main.run(mode: .default, before: Date(timeIntervalSinceNow: 0.05))
+        }
+        return result.error
+    }
+}
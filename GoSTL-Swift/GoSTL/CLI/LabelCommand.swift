@@ -0,0 +1,103 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl label <model>` - printable part label with key dimensions and a QR code
+struct LabelCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "label",
+        abstract: "Create a printable label with the model name, dimensions, volume and a QR code.",
+        discussion: """
+            Lays out the model's file name, overall dimensions, volume and today's date next to a QR code, \
+            for attaching to printed parts. The QR code links to --link (e.g. the inspection report or the \
+            repository), otherwise to the source file: the URL for remote models, a file:// URL for local \
+            ones. The label is 62×29 mm unless --size is given. The format follows the output file extension \
+            (default: <model>-label.png); PNG labels are rendered at --dpi for label printers.
+            """
+    )
+
+    enum Format: String, CaseIterable, ExpressibleByArgument {
+        case png
+        case pdf
+        case svg
+    }
+
+    @Argument(help: "Model file or http(s)/s3 URL (.stl, .3mf, .scad, .py or a plugin format)", completion: .renderableModelFiles)
+    var model: String
+
+    @Option(name: .shortAndLong, help: "Output file (.png, .pdf or .svg)", completion: .file(extensions: Format.allCases.map(\.rawValue)))
+    var output: String?
+
+    @Option(name: .shortAndLong, help: "Output format when it does not follow from the output file (png, pdf, svg)")
+    var format: Format?
+
+    @Option(help: "URL encoded in the QR code (default: the source file)")
+    var link: String?
+
+    @Option(help: "Label size in millimeters, e.g. 62x29 or 89x36")
+    var size: String?
+
+    @Option(help: "Resolution of PNG labels (dots per inch)")
+    var dpi: Double = 300
+
+    @Flag(help: "Leave out the QR code")
+    var noQR = false
+
+    @Flag(help: "Leave out the date")
+    var noDate = false
+
+    func validate() throws {
+        if let size, PartLabel.parseSize(size) == nil {
+            throw ValidationError("--size must be WIDTHxHEIGHT in millimeters, at least \(Int(PartLabel.minimumSize.width))x\(Int(PartLabel.minimumSize.height)).")
+        }
+        if !(72...1200).contains(dpi) {
+            throw ValidationError("--dpi must be between 72 and 1200.")
+        }
+        if let output, format == nil, Format(rawValue: URL(fileURLWithPath: output).pathExtension.lowercased()) == nil {
+            throw ValidationError("Use a .png, .pdf or .svg output file, or specify --format.")
+        }
+        if noQR, link != nil {
+            throw ValidationError("--link cannot be combined with --no-qr.")
+        }
+    }
+
+    func run() throws {
+        let remote = RemoteFileCache.remoteURL(model)
+        let url = try ModelFileLoader.url(forArgument: model)
+        let fileName = remote?.lastPathComponent ?? url.lastPathComponent
+        let format = self.format ?? output.flatMap { Format(rawValue: URL(fileURLWithPath: $0).pathExtension.lowercased()) } ?? .png
+        // Next to local models, in the current directory for remote ones
+        let directory = remote == nil ? url.deletingLastPathComponent() : URL(fileURLWithPath: FileManager.default.currentDirectoryPath)
+        let destination = output.map { URL(fileURLWithPath: $0) }
+            ?? directory.appendingPathComponent("\(URL(fileURLWithPath: fileName).deletingPathExtension().lastPathComponent)-label.\(format.rawValue)")
+
+        let label = PartLabel(
+            name: fileName,
+            analysis: try ModelFileLoader.loadRendering(url: url).analyze(),
+            link: noQR ? nil : link ?? remote?.absoluteString ?? url.standardizedFileURL.absoluteString,
+            date: noDate ? nil : Date(),
+            size: size.flatMap(PartLabel.parseSize) ?? PartLabel.defaultSize
+        )
+        let sheet = label.sheet()
+
+        switch format {
+        case .png:
+            guard let image = sheet.image(pixelsPerMillimeter: dpi / 25.4) else {
+                print("Could not render the label")
+                throw ExitCode.failure
+            }
+            try OffscreenRenderer.pngData(image).write(to: destination, options: .atomic)
+        case .pdf:
+            guard let data = sheet.pdf(title: fileName) else {
+                print("Could not create the PDF document")
+                throw ExitCode.failure
+            }
+            try data.write(to: destination, options: .atomic)
+        case .svg:
+            try sheet.svg(title: fileName).write(to: destination, atomically: true, encoding: .utf8)
+        }
+        if let link = label.link, QRCode.modules(for: link) == nil {
+            print("Warning: the link is too long for a QR code; the label has none")
+        }
+        print(destination.path)
+    }
+}
@@ -0,0 +1,76 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl manifest <dir>` - record or verify hashes and key figures of a model library
+struct ManifestCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "manifest",
+        abstract: "Record file hashes, triangle counts, dimensions and volumes of a model library, or verify them.",
+        discussion: """
+            Scans the folder recursively for .stl and .3mf files and writes a JSON manifest \
+            (to stdout unless --out is given). With --verify <manifest>, the folder is compared \
+            against a previous manifest: files whose geometry changed, missing and added files \
+            are reported and the command exits with status 1. Files that differ only in header, \
+            normals or attribute bytes are listed as "metadata changed" without failing.
+            """
+    )
+
+    @Argument(help: "Model library folder", completion: .directory)
+    var folder: String
+
+    @Option(name: .shortAndLong, help: "Write the manifest to this file", completion: .file(extensions: ["json"]))
+    var out: String?
+
+    @Option(help: "Verify the folder against this manifest", completion: .file(extensions: ["json"]))
+    var verify: String?
+
+    @Flag(help: "With --verify, also list unchanged files")
+    var verbose = false
+
+    func validate() throws {
+        var isDirectory: ObjCBool = false
+        guard FileManager.default.fileExists(atPath: folder, isDirectory: &isDirectory), isDirectory.boolValue else {
+            throw ValidationError("\(folder) is not a folder.")
+        }
+        if out != nil && verify != nil {
+            throw ValidationError("--out and --verify cannot be combined.")
+        }
+    }
+
+    func run() throws {
+        let folderURL = URL(fileURLWithPath: folder)
+
+        if let verify {
+            let manifest = try ModelManifest.load(from: URL(fileURLWithPath: verify))
+            let differences = manifest.verify(folder: folderURL)
+            for difference in differences where verbose || difference.status != .unchanged {
+                let details = difference.details.isEmpty ? "" : " (\(difference.details.joined(separator: ", ")))"
+                print("\(difference.status.rawValue): \(difference.path)\(details)")
+            }
+            let failures = differences.filter(\.status.isFailure).count
+            print("\(differences.count - failures) of \(differences.count) files OK")
+            if failures > 0 {
+                throw ExitCode.failure
+            }
+            return
+        }
+
+        var failures = 0
+        let manifest = ModelManifest.create(folder: folderURL) { path, error in
+            failures += 1
+            FileHandle.standardError.write(Data("\(path): \(error.localizedDescription)\n".utf8))
+        }
+
+        let data = try manifest.encoded()
+        if let out {
+            try data.write(to: URL(fileURLWithPath: out), options: .atomic)
+            print("\(manifest.entries.count) models written to \(out)")
+        } else {
+            print(String(decoding: data, as: UTF8.self))
+        }
+
+        if failures > 0 {
+            throw ExitCode.failure
+        }
+    }
+}
@@ -0,0 +1,132 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl merge <models>...` - combine models into one print plate
+struct MergeCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "merge",
+        abstract: "Combine models into one STL, laid out side by side.",
+        discussion: """
+            Places the models next to each other without overlap, each standing on z = 0 in its own \
+            orientation, and writes them as one file. --arrange shelf (the default) fills rows with the \
+            deepest parts first for a compact plate; --arrange grid puts every part in a cell of the \
+            largest part's size. Rows are at most --width mm long (default: a roughly square plate). \
+            --arrange nest packs the parts' actual outlines onto the --bed, turning them about Z in \
+            --rotation-step increments, and reports how much of the bed they cover; parts that do not \
+            fit are left out and the command fails after writing the rest. Repeat a file to print it \
+            several times.
+            """
+    )
+
+    @Argument(help: "Model files (.stl, .3mf, a plugin format or http(s)/s3 URLs)", completion: .modelFiles)
+    var models: [String]
+
+    @Option(help: "Layout (\(PlateArrangement.allCases.map(\.rawValue).joined(separator: ", ")))")
+    var arrange: PlateArrangement = .shelf
+
+    @Option(help: "Gap between parts (mm)")
+    var spacing: Double = 5
+
+    @Option(help: "Longest row (mm), e.g. the build plate width")
+    var width: Double?
+
+    @Option(help: "Bed for --arrange nest: WIDTHxDEPTH, d<diameter> for a round bed, or a preset (\(BuildPlate.allCases.filter { $0 != .off }.map(\.rawValue).joined(separator: ", ")))")
+    var bed: BedProfile?
+
+    @Option(help: "Angle between rotations tried by --arrange nest (degrees, 0 = keep orientation)")
+    var rotationStep: Double = PlateNesting.defaultRotationStep
+
+    @Option(help: "Grid size for --arrange nest (mm); smaller packs tighter but takes longer")
+    var resolution: Double = PlateNesting.defaultResolution
+
+    @Option(name: .shortAndLong, help: "Output file (.stl, .stl.gz or .stl.zst)", completion: .file(extensions: ["stl"]))
+    var out: String = "plate.stl"
+
+    func validate() throws {
+        if models.count < 2 {
+            throw ValidationError("Give at least two models to merge.")
+        }
+        if !(spacing >= 0) {
+            throw ValidationError("--spacing must not be negative.")
+        }
+        if let width, !(width > 0) {
+            throw ValidationError("--width must be positive.")
+        }
+        if arrange == .nest, bed == nil {
+            throw ValidationError("--arrange nest needs a --bed, e.g. 256x256, d200 or X1C.")
+        }
+        if arrange != .nest, bed != nil {
+            throw ValidationError("--bed applies to --arrange nest; use --width for shelf and grid.")
+        }
+        if !(rotationStep >= 0 && rotationStep <= 360) {
+            throw ValidationError("--rotation-step must be between 0 and 360.")
+        }
+        if !(resolution > 0) {
+            throw ValidationError("--resolution must be positive.")
+        }
+    }
+
+    func run() throws {
+        var parts: [STLModel] = []
+        for argument in models {
+            let part = try ModelFileLoader.loadRendering(url: ModelFileLoader.url(forArgument: argument))
+            guard !part.triangles.isEmpty else {
+                throw ValidationError("\(argument) has no triangles.")
+            }
+            parts.append(part)
+        }
+
+        if let bed {
+            return try nest(parts, on: bed)
+        }
+
+        let footprints = parts.map { part -> (width: Double, depth: Double) in
+            let size = part.boundingBox().size
+            return (size.x, size.y)
+        }
+        let layout = PlateLayout(footprints: footprints, arrangement: arrange, spacing: spacing, maxWidth: width)
+        let destination = URL(fileURLWithPath: out)
+        let plate = STLModel.merged(parts, layout: layout, name: destination.deletingPathExtension().lastPathComponent)
+        try STLExporter.exportBinary(model: plate, to: destination)
+
+        let height = plate.boundingBox().size.z
+        print("\(destination.path) (\(parts.count) parts, \(plate.triangles.count) triangles, \(String(format: "%.1f × %.1f × %.1f mm", layout.width, layout.depth, height)))")
+        for (argument, origin) in zip(models, layout.origins) {
+            print("  \(URL(fileURLWithPath: argument).lastPathComponent) at \(String(format: "%.1f, %.1f", origin.x, origin.y))")
+        }
+        if let width, let widest = footprints.map(\.width).max(), widest > width {
+            FileHandle.standardError.write(Data("Warning: a part is \(String(format: "%.1f", widest)) mm wide, more than --width\n".utf8))
+        }
+    }
+
+    private func nest(_ parts: [STLModel], on bed: BedProfile) throws {
+        let nesting = PlateNesting(parts: parts, bed: bed, spacing: spacing, rotationStep: rotationStep, resolution: resolution)
+        let destination = URL(fileURLWithPath: out)
+        if !nesting.placements.isEmpty {
+            let plate = nesting.merged(parts, name: destination.deletingPathExtension().lastPathComponent)
+            try STLExporter.exportBinary(model: plate, to: destination)
+
+            let size = plate.boundingBox().size
+            print("\(destination.path) (\(nesting.placements.count) parts, \(plate.triangles.count) triangles, \(String(format: "%.1f × %.1f × %.1f mm", size.x, size.y, size.z)))")
+            for placement in nesting.placements {
+                let name = URL(fileURLWithPath: models[placement.part]).lastPathComponent
+                let corner = placement.corner
+                print("  \(name) at \(String(format: "%.1f, %.1f", corner.x, corner.y)), rotated \(String(format: "%g", placement.rotation))°")
+            }
+            print("Packing efficiency: \(String(format: "%.1f", nesting.efficiency * 100)) % of the bed, \(String(format: "%.1f", nesting.density * 100)) % of the used area")
+        }
+        guard nesting.unplaced.isEmpty else {
+            let names = nesting.unplaced.map { URL(fileURLWithPath: models[$0]).lastPathComponent }
+            FileHandle.standardError.write(Data("Error: \(names.count) part(s) did not fit on the bed: \(names.joined(separator: ", "))\n".utf8))
+            throw ExitCode.failure
+        }
+    }
+}
+
+extension PlateArrangement: ExpressibleByArgument {}
+
+extension BedProfile: ExpressibleByArgument {
+    init?(argument: String) {
+        self.init(argument)
+    }
+}
@@ -0,0 +1,72 @@
+import ArgumentParser
+import CoreGraphics
+import Foundation
+
+/// `gostl open <model>` - open a model in the best viewer for the current environment
+struct OpenCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "open",
+        abstract: "Open a model in the best available viewer.",
+        discussion: """
+            With --viewer auto, the model opens in the GoSTL window when a graphical session is available \
+            and falls back to a text summary over SSH or without a window server. http(s) and s3:// URLs \
+            are downloaded into the cache first.
+            """
+    )
+
+    enum Viewer: String, CaseIterable, ExpressibleByArgument {
+        /// Pick the best viewer for the environment
+        case auto
+        /// The GoSTL window
+        case app
+        /// Text summary in the terminal
+        case text
+    }
+
+    @Argument(help: "Model file or http(s)/s3 URL", completion: .modelFiles)
+    var model: String
+
+    @Option(help: "Viewer to use (auto, app, text)")
+    var viewer: Viewer = .auto
+
+    func run() throws {
+        let url = try ModelFileLoader.url(forArgument: model)
+        guard FileManager.default.fileExists(atPath: url.path) else {
+            throw ValidationError("File not found: \(model)")
+        }
+
+        switch viewer == .auto ? Self.preferredViewer() : viewer {
+        case .app, .auto:
+            try Self.openInApp(url)
+        case .text:
+            let model = try ModelFileLoader.load(url: url)
+            let record = AnalysisRecord(fileName: url.lastPathComponent, analysis: model.analyze())
+            print(try TextAnalysisFormatter().format([record]))
+        }
+    }
+
+    /// The window viewer when a graphical session is reachable, text otherwise
+    static func preferredViewer(environment: [String: String] = ProcessInfo.processInfo.environment) -> Viewer {
+        let isRemote = environment["SSH_CONNECTION"] != nil || environment["SSH_TTY"] != nil
+        let hasWindowServer = CGSessionCopyCurrentDictionary() != nil
+        return hasWindowServer && !isRemote ? .app : .text
+    }
+
+    /// Show a file in the GoSTL window without blocking the terminal
+    static func openInApp(_ url: URL) throws {
+        let process = Process()
+        if Bundle.main.bundleURL.pathExtension == "app" {
+            // Let Launch Services reuse a running instance
+            process.executableURL = URL(fileURLWithPath: "/usr/bin/open")
+            process.arguments = ["-a", Bundle.main.bundleURL.path, url.path]
+        } else {
+            // Development build: start the viewer from this executable, detached from the terminal
+            process.executableURL = Bundle.main.executableURL
+            process.arguments = [url.path]
+            process.standardInput = FileHandle.nullDevice
+            process.standardOutput = FileHandle.nullDevice
+            process.standardError = FileHandle.nullDevice
+        }
+        try process.run()
+    }
+}
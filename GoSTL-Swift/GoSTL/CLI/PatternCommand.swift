@@ -0,0 +1,107 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl pattern <model>` - repeat a model in a row or around a circle
+struct PatternCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "pattern",
+        abstract: "Repeat a model in a row or around a circle as one STL.",
+        discussion: """
+            Linear patterns place --count copies along --axis, --spacing mm apart between their bounding \
+            boxes. With --polar, the copies go around a Z axis --radius mm from the model's center (default: \
+            just far enough for --spacing between neighbors), evenly over --angle degrees and turned with \
+            the pattern unless --no-rotate is given. The first copy stays where the model is. Writes \
+            <model>-pattern.stl unless --out is given; --open shows the result in the viewer.
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf, a plugin format or an http(s)/s3 URL)", completion: .modelFiles)
+    var model: String
+
+    @Option(help: "Number of copies, including the original")
+    var count: Int
+
+    @Flag(help: "Arrange the copies around a circle instead of in a row")
+    var polar = false
+
+    @Option(help: "Direction of a linear pattern (x, y, z)")
+    var axis: SectionProperties.Axis = .x
+
+    @Option(help: "Gap between neighboring copies (mm)")
+    var spacing: Double = 5
+
+    @Option(help: "Distance from the pattern axis to the model's center (mm)")
+    var radius: Double?
+
+    @Option(help: "Angle covered by a polar pattern (degrees); 360 spaces the copies evenly around the circle")
+    var angle: Double = 360
+
+    @Flag(help: "Keep the copies of a polar pattern in the model's orientation")
+    var noRotate = false
+
+    @Option(name: .shortAndLong, help: "Output file (.stl, .stl.gz or .stl.zst)", completion: .file(extensions: ["stl"]))
+    var out: String?
+
+    @Flag(help: "Open the result in the viewer")
+    var open = false
+
+    func validate() throws {
+        if count < 2 {
+            throw ValidationError("--count must be at least 2.")
+        }
+        if !(spacing >= 0) {
+            throw ValidationError("--spacing must not be negative.")
+        }
+        if !polar, radius != nil || angle != 360 || noRotate {
+            throw ValidationError("--radius, --angle and --no-rotate need --polar.")
+        }
+        if let radius, !(radius > 0) {
+            throw ValidationError("--radius must be positive.")
+        }
+        if !(angle != 0 && abs(angle) <= 360) {
+            throw ValidationError("--angle must be between -360 and 360 and not 0.")
+        }
+    }
+
+    func run() throws {
+        let url = try ModelFileLoader.url(forArgument: model)
+        let fileName = RemoteFileCache.remoteURL(model)?.lastPathComponent ?? url.lastPathComponent
+        let directory = RemoteFileCache.remoteURL(model) == nil
+            ? url.deletingLastPathComponent()
+            : URL(fileURLWithPath: FileManager.default.currentDirectoryPath)
+        let destination = out.map { URL(fileURLWithPath: $0) }
+            ?? directory.appendingPathComponent("\(URL(fileURLWithPath: fileName).deletingPathExtension().lastPathComponent)-pattern.stl")
+        guard destination.standardizedFileURL != url.standardizedFileURL else {
+            throw ValidationError("The output file would overwrite the model; specify --out.")
+        }
+
+        let loaded = try ModelFileLoader.loadRendering(url: url)
+        guard !loaded.triangles.isEmpty else {
+            throw STLExportError.emptyModel
+        }
+
+        let pattern: ModelPattern
+        if polar {
+            let clear = ModelPattern.minimumRadius(for: loaded, count: count, sweep: angle)
+            let radius = radius ?? ModelPattern.minimumRadius(for: loaded, count: count, sweep: angle, spacing: spacing)
+            pattern = .polar(count: count, radius: radius, sweep: angle, rotateCopies: !noRotate)
+            if radius < clear {
+                FileHandle.standardError.write(Data("Warning: copies may overlap below a radius of \(String(format: "%.1f", clear)) mm\n".utf8))
+            }
+        } else {
+            pattern = .linear(count: count, axis: axis, spacing: spacing)
+        }
+
+        let result = pattern.apply(to: loaded)
+        try STLExporter.exportBinary(model: result, to: destination)
+
+        let size = result.boundingBox().size
+        print("\(destination.path) (\(count) copies, \(result.triangles.count) triangles, \(String(format: "%.1f × %.1f × %.1f mm", size.x, size.y, size.z)))")
+        if case .polar(_, let radius, let sweep, _) = pattern {
+            print("  Radius \(String(format: "%.1f", radius)) mm, \(String(format: "%g", ModelPattern.angularStep(count: count, sweep: sweep)))° apart")
+        }
+        if open {
+            try OpenCommand.openInApp(destination)
+        }
+    }
+}
@@ -0,0 +1,30 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl plugins` - list installed plugins
+struct PluginsCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "plugins",
+        abstract: "List installed importer and analyzer plugins.",
+        discussion: "Plugins are loaded from ~/Library/Application Support/GoSTL/Plugins and GOSTL_PLUGIN_PATH."
+    )
+
+    func run() throws {
+        let registry = PluginRegistry.shared
+        guard !registry.plugins.isEmpty else {
+            print("No plugins installed")
+            return
+        }
+
+        for plugin in registry.plugins {
+            let version = plugin.manifest.version.map { " \($0)" } ?? ""
+            print("\(plugin.manifest.name)\(version) (\(plugin.directory.path))")
+            for importer in plugin.manifest.importers ?? [] {
+                print("  import: \(importer.extensions.map { ".\($0)" }.joined(separator: ", "))")
+            }
+            for analyzer in plugin.manifest.analyzers ?? [] {
+                print("  analyze: \(analyzer.id) - \(analyzer.title)")
+            }
+        }
+    }
+}
@@ -0,0 +1,57 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl polyhedron <model>` - write a mesh as an OpenSCAD polyhedron module
+struct PolyhedronCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "polyhedron",
+        abstract: "Export a model as an OpenSCAD polyhedron() module.",
+        discussion: """
+            Writes <model>.scad (or the --output file) with a module named after the model that contains \
+            the mesh as a polyhedron, so measured or repaired geometry can be used as a reference in \
+            parametric designs (use <part.scad> and call part()). With --decimate, vertices within the given \
+            grid cell size in mm are merged to reduce the file size.
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf or a plugin format)", completion: .modelFiles)
+    var model: String
+
+    @Option(name: .shortAndLong, help: "Output .scad file", completion: .file(extensions: ["scad"]))
+    var output: String?
+
+    @Option(help: "Merge vertices within grid cells of this size (mm)")
+    var decimate: Double?
+
+    @Option(help: "Module name (default: the model file name)")
+    var name: String?
+
+    func validate() throws {
+        if let decimate, decimate <= 0 {
+            throw ValidationError("--decimate must be a positive cell size in mm.")
+        }
+    }
+
+    func run() throws {
+        let url = URL(fileURLWithPath: model)
+        let destination = output.map { URL(fileURLWithPath: $0) }
+            ?? url.deletingPathExtension().appendingPathExtension("scad")
+        guard destination.standardizedFileURL != url.standardizedFileURL else {
+            throw ValidationError("The output file would overwrite the model; specify --output.")
+        }
+
+        let loaded = try ModelFileLoader.load(url: url)
+        guard !loaded.triangles.isEmpty else {
+            throw STLExportError.emptyModel
+        }
+
+        let code = OpenSCADGenerator.polyhedronModule(
+            from: loaded,
+            fileName: url.lastPathComponent,
+            moduleName: name,
+            decimation: decimate
+        )
+        try code.write(to: destination, atomically: true, encoding: .utf8)
+        print(destination.path)
+    }
+}
@@ -0,0 +1,79 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl primitives <model>` - list the planes, cylinders, cones and spheres of a mesh with their sizes
+struct PrimitivesCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "primitives",
+        abstract: "Detect planes, cylinders, cones and spheres in a model and report their parameters.",
+        discussion: """
+            Splits the mesh into surfaces at edges sharper than --feature-angle degrees and fits a \
+            cylinder, cone or sphere to each curved surface whose vertices all lie within --tolerance mm \
+            of it. Prints the share of the surface covered by each kind and every curved surface with its \
+            diameter, length, axis and whether it is a boss or a hole (sizes in mm, file coordinates). \
+            Use --planes to list the flat faces as well. With --colors, the model is also written with one \
+            color per primitive kind baked in as vertex colors (.ply or .3mf).
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf or a plugin format)", completion: .modelFiles)
+    var model: String
+
+    @Option(help: "Largest distance of a vertex from a fitted surface (mm)")
+    var tolerance: Double = PrimitiveDetection.defaultTolerance
+
+    @Option(help: "Facets meeting at a smaller angle may belong to one curved surface (degrees)")
+    var featureAngle: Double = PrimitiveDetection.defaultFeatureAngle
+
+    @Flag(help: "List the flat faces as well")
+    var planes = false
+
+    @Option(help: "Also write the model with the primitive colors as vertex colors (.ply or .3mf)", completion: .file(extensions: ["ply", "3mf"]))
+    var colors: String?
+
+    func validate() throws {
+        if tolerance <= 0 {
+            throw ValidationError("--tolerance must be a positive distance in mm.")
+        }
+        if featureAngle <= 0 || featureAngle >= 90 {
+            throw ValidationError("--feature-angle must be an angle between 0 and 90 degrees.")
+        }
+        if let colors, ColoredMeshExporter.Format(url: URL(fileURLWithPath: colors)) == nil {
+            throw ValidationError("--colors must be a .ply or .3mf file.")
+        }
+    }
+
+    func run() throws {
+        let url = URL(fileURLWithPath: model)
+        let stl = try ModelFileLoader.load(url: url)
+        let result = try PrimitiveDetection.detect(stl.triangles, tolerance: tolerance, featureAngle: featureAngle)
+        guard result.totalArea > 0 else {
+            throw ValidationError("The model has no faces")
+        }
+
+        print(String(format: "Recognized: %.1f%% of %.2f mm²", result.coverage * 100, result.totalArea))
+        for kind in PrimitiveDetection.Kind.allCases {
+            let area = result.area(of: kind)
+            print(String(format: "  %@: %d, %.2f mm² (%.1f%%)", kind.pluralName, result.count(of: kind), area, area / result.totalArea * 100))
+        }
+
+        let listed = result.regions.filter { planes || $0.shape.kind != .plane }
+        if !listed.isEmpty {
+            print("")
+        }
+        for region in listed {
+            print(String(format: "%@  (%.2f mm², %d triangles)", PrimitiveDetection.describe(region), region.area, region.triangles.count))
+        }
+
+        if let colors {
+            try ColoredMeshExporter.export(
+                triangles: stl.triangles,
+                colors: PrimitiveDetection.colors(for: result),
+                materialColor: Material.pla.baseColor,
+                name: url.lastPathComponent,
+                to: URL(fileURLWithPath: colors)
+            )
+            print(colors)
+        }
+    }
+}
@@ -0,0 +1,58 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl scad-vars <measurements>` - write measured values as named OpenSCAD variables
+struct ScadVariablesCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "scad-vars",
+        abstract: "Write measurements as named OpenSCAD variables.",
+        discussion: """
+            Reads a measurement export (.json) or the measurements saved in a model's project \
+            (<model>.gostl) and prints one variable per measurement, e.g. hole_spacing = 32.04;, to \
+            bootstrap re-modeling a scanned part. A measurement's note names its variable, otherwise its \
+            export name does (distance_1). Radii also get a diameter variable; angles are in degrees.
+            """
+    )
+
+    @Argument(help: "Measurement export (.json) or model file with a saved project", completion: .file())
+    var input: String
+
+    @Option(name: .shortAndLong, help: "Output .scad file (default: standard output)", completion: .file(extensions: ["scad"]))
+    var output: String?
+
+    @Option(help: "Decimal places of all values (default: each measurement's own, else 2)")
+    var decimals: Int?
+
+    func validate() throws {
+        if let decimals, !(0...6).contains(decimals) {
+            throw ValidationError("--decimals must be between 0 and 6.")
+        }
+    }
+
+    func run() throws {
+        let url = URL(fileURLWithPath: input)
+        let entries: [MeasurementSet.Entry]
+        let source: String?
+        if url.pathExtension.lowercased() == "json" {
+            let set = try MeasurementSet.load(from: url)
+            entries = set.entries
+            source = set.fileName
+        } else {
+            entries = try ModelSidecar.load(for: url).measurements
+            source = url.lastPathComponent
+        }
+
+        guard entries.contains(where: { $0.measurement.type != .triangleSelect }) else {
+            throw ValidationError("No measurements in \(url.lastPathComponent); save them with File > Save Project or File > Export Measurements.")
+        }
+
+        let code = OpenSCADGenerator.variables(from: entries, source: source, decimals: decimals)
+        guard let output else {
+            print(code)
+            return
+        }
+        let destination = URL(fileURLWithPath: output)
+        try (code + "\n").write(to: destination, atomically: true, encoding: .utf8)
+        print(destination.path)
+    }
+}
@@ -0,0 +1,47 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl script <script.js> [model]` - run an analysis script without the viewer
+struct ScriptCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "script",
+        abstract: "Run a JavaScript analysis script against a model.",
+        discussion: "The script sees the model as gostl.model. Exits with status 1 if the script calls gostl.fail()."
+    )
+
+    @Argument(help: "Script file (.js)", completion: .file(extensions: ["js"]))
+    var script: String
+
+    @Argument(help: "Model file (.stl or .3mf)", completion: .modelFiles)
+    var model: String?
+
+    @Option(name: .shortAndLong, help: "Write results and measurements as JSON to this file", completion: .file(extensions: ["json"]))
+    var output: String?
+
+    /// Where the viewer's script job picks up the complete report (written even if a check fails)
+    @Option(name: .customLong("report"), help: .hidden)
+    var reportFile: String?
+
+    func run() throws {
+        let scriptURL = URL(fileURLWithPath: script)
+        let runtime = ScriptRuntime(baseURL: scriptURL.deletingLastPathComponent())
+        runtime.onOutput = { print($0) }
+
+        if let model {
+            runtime.setModel(try ModelFileLoader.load(url: URL(fileURLWithPath: model)))
+        }
+
+        let report = try runtime.run(url: scriptURL)
+
+        if let output {
+            try report.jsonData().write(to: URL(fileURLWithPath: output))
+        }
+        if let reportFile {
+            try report.archiveData().write(to: URL(fileURLWithPath: reportFile))
+        }
+
+        if !report.passed {
+            throw ExitCode.failure
+        }
+    }
+}
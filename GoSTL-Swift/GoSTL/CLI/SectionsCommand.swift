@@ -0,0 +1,59 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl sections <model> --axis z --step 1` - area and second moment of area along an axis
+struct SectionsCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "sections",
+        abstract: "Sweep cross-sections along an axis and report area and second moment of area.",
+        discussion: """
+            Cuts the model at every multiple of --step mm along --axis (a round step giving about 25 \
+            stations by default) and prints one CSV row per station: position, area (mm²), centroid, \
+            the second moments of area about the centroidal axes parallel to the other two axes, their \
+            product, the principal minimum and maximum and the polar moment (mm⁴). For a beam along \
+            --axis, the bending stress is about M · c / I; the weakest station has the smallest i_min. \
+            With --out, the CSV is written to a file and the weakest station is printed.
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf or a plugin format)", completion: .modelFiles)
+    var model: String
+
+    @Option(help: "Axis the sections are swept along (normal of the cutting planes)")
+    var axis: SectionProperties.Axis = .z
+
+    @Option(help: "Distance between stations (mm)")
+    var step: Double?
+
+    @Option(name: .shortAndLong, help: "Write the CSV to this file instead of standard output", completion: .file(extensions: ["csv"]))
+    var out: String?
+
+    @OptionGroup
+    var csvOptions: CSVOptions
+
+    func validate() throws {
+        if let step, step <= 0 {
+            throw ValidationError("--step must be a positive distance in mm.")
+        }
+    }
+
+    func run() throws {
+        let loaded = try ModelFileLoader.load(url: URL(fileURLWithPath: model))
+        let sections = try SectionProperties.compute(model: loaded, axis: axis, step: step)
+        let csv = sections.csv(format: csvOptions.format)
+
+        guard let out else {
+            print(csv, terminator: "")
+            return
+        }
+        try csv.write(to: URL(fileURLWithPath: out), atomically: true, encoding: .utf8)
+        print(out)
+        print("\(sections.stations.count) stations along \(axis.rawValue.uppercased()) every \(String(format: "%g", sections.step)) mm")
+        if let weakest = sections.weakestStation {
+            print(String(format: "Weakest at %@ = %.3f mm: area %.3f mm², I min %.3f mm⁴",
+                         axis.rawValue.uppercased(), weakest.position, weakest.area, weakest.principal.min))
+        }
+    }
+}
+
+extension SectionProperties.Axis: ExpressibleByArgument {}
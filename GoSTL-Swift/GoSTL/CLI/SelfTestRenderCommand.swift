@@ -0,0 +1,98 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl selftest-render` - compare renders of reference models with golden images
+struct SelfTestRenderCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "selftest-render",
+        abstract: "Render reference models and compare them with golden images.",
+        discussion: """
+            Renders built-in reference models (cube, steps, sphere; several views, materials and the \
+            cutaway) and compares each with its golden PNG using a perceptual diff. Goldens depend on the \
+            GPU and driver, so record them with --update on the machine that runs the comparison, e.g. \
+            before changing shaders. Failing cases write the render and a diff image (differences in red) \
+            to --output. Exits with status 1 if a case fails or has no golden.
+            """
+    )
+
+    @Option(help: "Folder of golden images (default: ~/Library/Application Support/GoSTL/RenderGoldens)", completion: .directory)
+    var goldens: String?
+
+    @Flag(help: "Record the renders as new goldens instead of comparing")
+    var update = false
+
+    @Option(name: .shortAndLong, help: "Folder for renders and diff images of failing cases (default: a temporary folder)", completion: .directory)
+    var output: String?
+
+    @Option(name: .customLong("case"), help: "Case to run, repeatable (\(RenderSelfTest.cases.map(\.name).joined(separator: ", "))); default: all")
+    var cases: [String] = []
+
+    @Option(help: "Color difference (CIE ΔE) below which pixels count as equal")
+    var threshold = PerceptualDiff.defaultThreshold
+
+    @Option(help: "Share of differing pixels (percent) that still passes")
+    var tolerance = PerceptualDiff.defaultTolerance
+
+    func validate() throws {
+        let known = RenderSelfTest.cases.map(\.name)
+        if let unknown = cases.first(where: { !known.contains($0) }) {
+            throw ValidationError("Unknown case \(unknown); use one of \(known.joined(separator: ", ")).")
+        }
+        if threshold <= 0 {
+            throw ValidationError("--threshold must be positive.")
+        }
+        if !(0...100).contains(tolerance) {
+            throw ValidationError("--tolerance must be between 0 and 100.")
+        }
+    }
+
+    static func defaultGoldens() -> URL {
+        let applicationSupport = FileManager.default.urls(for: .applicationSupportDirectory, in: .userDomainMask).first
+            ?? FileManager.default.temporaryDirectory
+        return applicationSupport.appendingPathComponent("GoSTL/RenderGoldens", isDirectory: true)
+    }
+
+    func run() throws {
+        let test = RenderSelfTest(
+            goldens: goldens.map { URL(fileURLWithPath: $0, isDirectory: true) } ?? Self.defaultGoldens(),
+            threshold: threshold,
+            tolerance: tolerance
+        )
+        let artifacts = output.map { URL(fileURLWithPath: $0, isDirectory: true) }
+            ?? FileManager.default.temporaryDirectory.appendingPathComponent("gostl-selftest-render", isDirectory: true)
+        let renderer = try OffscreenRenderer()
+
+        var failures = 0
+        var missing = 0
+        for testCase in RenderSelfTest.cases(named: cases) {
+            let outcome = try test.run(testCase, with: renderer, update: update, artifacts: artifacts)
+            let padded = testCase.name.padding(toLength: 18, withPad: " ", startingAt: 0)
+            switch outcome {
+            case .passed(let result):
+                print("\(padded) ok      \(Self.summary(result))")
+            case .failed(let result):
+                failures += 1
+                print("\(padded) FAILED  \(Self.summary(result))")
+            case .missing:
+                missing += 1
+                print("\(padded) MISSING no golden at \(test.goldenURL(for: testCase).path)")
+            case .recorded:
+                print("\(padded) recorded \(test.goldenURL(for: testCase).path)")
+            }
+        }
+
+        if failures > 0 {
+            print("Renders and diff images of failing cases: \(artifacts.path)")
+        }
+        if missing > 0 {
+            print("Record goldens with gostl selftest-render --update")
+        }
+        if failures + missing > 0 {
+            throw ExitCode.failure
+        }
+    }
+
+    static func summary(_ result: PerceptualDiff.Result) -> String {
+        String(format: "%d pixels differ (%.3f%%), max ΔE %.1f", result.differingPixels, result.percentage, result.maxDeltaE)
+    }
+}
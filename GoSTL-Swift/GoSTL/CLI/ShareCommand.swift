@@ -0,0 +1,206 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl share <model>` - serve an interactive web view of a model on the local network
+struct ShareCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "share",
+        abstract: "Serve an interactive web view of a model on the local network.",
+        discussion: """
+            Starts a temporary web server for a self-contained page showing the model in 3D with its \
+            metrics, and the measurements and annotations of a measurement set (pinned annotations of the \
+            model are included too). Prints the URLs and a QR code to open on another device. To ask for \
+            basic auth (user --user), set the password in GOSTL_SHARE_PASSWORD or use --ask-password to type \
+            it without echo; --password also works but shows the password in ps and the shell history. Use \
+            --expires to stop the server after a while (e.g. 30m); otherwise it runs until Ctrl-C. Anyone on \
+            the network can reach the page, and basic auth is sent unencrypted; do not share confidential \
+            models on untrusted networks.
+            """
+    )
+
+    /// Time until the server stops, e.g. `90s`, `30m` or `2h` (plain numbers are minutes)
+    struct Expiry: Equatable {
+        var seconds: TimeInterval
+
+        init?(_ text: String) {
+            let units: [Character: TimeInterval] = ["s": 1, "m": 60, "h": 3600]
+            var number = text.lowercased()
+            var unit: TimeInterval = 60
+            if let last = number.last, let factor = units[last] {
+                unit = factor
+                number.removeLast()
+            }
+            guard let value = Double(number), value > 0, value.isFinite else { return nil }
+            seconds = value * unit
+        }
+
+        var description: String {
+            if seconds >= 3600, seconds.truncatingRemainder(dividingBy: 3600) == 0 {
+                return "\(Int(seconds / 3600)) h"
+            }
+            if seconds >= 60, seconds.truncatingRemainder(dividingBy: 60) == 0 {
+                return "\(Int(seconds / 60)) min"
+            }
+            return "\(Int(seconds.rounded(.up))) s"
+        }
+    }
+
+    @Argument(help: "Model file or http(s)/s3 URL (.stl, .3mf, .scad, .py or a plugin format)", completion: .renderableModelFiles)
+    var model: String
+
+    @Option(help: "Measurement set (.json) whose measurements and annotations are shown", completion: .file(extensions: ["json"]))
+    var measurements: String?
+
+    @Option(help: "Port to listen on (0 picks a free port)")
+    var port: Int = 0
+
+    /// Environment variable holding the basic auth password
+    static let passwordVariable = "GOSTL_SHARE_PASSWORD"
+
+    @Option(help: "User name for basic auth (with a password)")
+    var user = "gostl"
+
+    @Flag(help: "Ask for a basic auth password without echoing it")
+    var askPassword = false
+
+    @Option(help: "Password for basic auth (discouraged: visible in ps and the shell history; prefer GOSTL_SHARE_PASSWORD or --ask-password). Without a password the page is open to everyone on the network")
+    var password: String?
+
+    @Option(help: "Stop serving after this time, e.g. 90s, 30m or 2h")
+    var expires: Expiry?
+
+    @Flag(help: "Do not print the QR code")
+    var noQR = false
+
+    func validate() throws {
+        if !(0...65535).contains(port) {
+            throw ValidationError("--port must be between 0 and 65535.")
+        }
+        if let password, password.isEmpty {
+            throw ValidationError("--password must not be empty.")
+        }
+        if askPassword && password != nil {
+            throw ValidationError("Use either --ask-password or --password.")
+        }
+        if user.contains(":") {
+            throw ValidationError("--user must not contain a colon.")
+        }
+    }
+
+    func run() throws {
+        let url = try ModelFileLoader.url(forArgument: model)
+        let stl = try ModelFileLoader.loadRendering(url: url)
+        let fileName = RemoteFileCache.remoteURL(model).map { $0.lastPathComponent } ?? url.lastPathComponent
+
+        let set = try measurements.map { try MeasurementSet.load(from: URL(fileURLWithPath: $0)) }
+        let pinned = (try? ModelSidecar.load(for: url))?.annotations ?? []
+        let page = WebViewerExport.html(
+            model: stl,
+            title: fileName,
+            analysis: AnalyzeCommand.record(for: stl, fileName: fileName),
+            measurements: set?.entries ?? [],
+            annotations: pinned + (set?.annotations ?? [])
+        )
+
+        let credentials = try resolvedPassword().map { ShareServer.Credentials(user: user, password: $0) }
+        let server = try ShareServer(page: Data(page.utf8), credentials: credentials, port: port) { line in
+            FileHandle.standardError.write(Data("\(line)\n".utf8))
+        }
+        let banner = Banner(user: credentials?.user, expires: expires, showsQRCode: !noQR)
+        server.start { result in
+            switch result {
+            case .success(let port):
+                banner.print(port: port)
+            case .failure(let error):
+                FileHandle.standardError.write(Data("Error: cannot start the server: \(error.localizedDescription)\n".utf8))
+                Foundation.exit(1)
+            }
+        }
+
+        if let expires {
+            DispatchQueue.main.asyncAfter(deadline: .now() + expires.seconds) {
+                server.stop()
+                print("Share expired after \(expires.description).")
+                Foundation.exit(0)
+            }
+        }
+        withExtendedLifetime(server) {
+            dispatchMain()
+        }
+    }
+}
+
+extension ShareCommand {
+    /// Basic auth password from the prompt, `--password` or `GOSTL_SHARE_PASSWORD`, in that order
+    func resolvedPassword(
+        environment: [String: String] = ProcessInfo.processInfo.environment,
+        prompt: () -> String? = ShareCommand.promptPassword
+    ) throws -> String? {
+        if askPassword {
+            guard let typed = prompt(), !typed.isEmpty else {
+                throw ValidationError("No password entered.")
+            }
+            return typed
+        }
+        if password == nil, let value = environment[Self.passwordVariable] {
+            guard !value.isEmpty else {
+                throw ValidationError("\(Self.passwordVariable) must not be empty.")
+            }
+            return value
+        }
+        if password != nil {
+            FileHandle.standardError.write(Data("Warning: --password is visible to other users in ps and stays in the shell history; prefer \(Self.passwordVariable) or --ask-password.\n".utf8))
+        }
+        return password
+    }
+
+    /// Read a password from the terminal without echoing it
+    static func promptPassword() -> String? {
+        var buffer = [CChar](repeating: 0, count: 1024)
+        guard let line = readpassphrase("Password: ", &buffer, buffer.count, RPP_REQUIRE_TTY) else { return nil }
+        return String(cString: line)
+    }
+}
+
+/// What `gostl share` prints once the server is listening
+private struct Banner: Sendable {
+    var user: String?
+    var expires: ShareCommand.Expiry?
+    var showsQRCode: Bool
+
+    func print(port: UInt16) {
+        var hosts = ShareServer.localAddresses()
+        if let name = Host.current().name, !hosts.contains(name) {
+            hosts.append(name)
+        }
+        if hosts.isEmpty {
+            hosts = ["localhost"]
+        }
+        let urls = hosts.map { "http://\($0):\(port)/" }
+
+        var lines = ["Sharing on:"] + urls.map { "  \($0)" }
+        if let user {
+            lines.append("Password protected (user \(user)).")
+        } else {
+            lines.append("Not password protected; anyone on the network can open it.")
+        }
+        if let expires {
+            lines.append("Expires in \(expires.description). Press Ctrl-C to stop sharing earlier.")
+        } else {
+            lines.append("Press Ctrl-C to stop sharing.")
+        }
+        if showsQRCode, let code = QRCode.lines(for: urls[0]) {
+            lines.append("")
+            // Black on white, so the code also scans on dark terminal themes
+            lines += code.map { "\u{1B}[30;47m\($0)\u{1B}[0m" }
+        }
+        Swift.print(lines.joined(separator: "\n"))
+        fflush(stdout)
+    }
+}
+
+extension ShareCommand.Expiry: ExpressibleByArgument {
+    init?(argument: String) {
+        self.init(argument)
+    }
+}
@@ -0,0 +1,69 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl sketch <model> --axis z --at 5` - fit lines and arcs to a cross-section for re-modeling
+struct SketchCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "sketch",
+        abstract: "Export a cross-section as a DXF sketch or OpenSCAD profile of lines and arcs.",
+        discussion: """
+            Cuts the model with the plane normal to --axis at --at mm (the middle of the model by \
+            default) and fits lines, arcs and circles within --tolerance mm to the outline instead of \
+            keeping the dense polyline of the mesh. Writes a DXF (R12, mm; outlines on layer PROFILE, \
+            holes on layer HOLES) or, for a .scad output, an OpenSCAD module with the 2D profile. The \
+            drawing's X and Y are the plane's axes: X, Y for a Z section, Y, Z for X and Z, X for Y.
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf or a plugin format)", completion: .modelFiles)
+    var model: String
+
+    @Option(help: "Axis normal to the section plane")
+    var axis: SectionProperties.Axis = .z
+
+    @Option(help: "Position of the section plane along the axis (mm, default: middle of the model)")
+    var at: Double?
+
+    @Option(help: "Largest distance of the outline from the fitted lines and arcs (mm)")
+    var tolerance: Double = SectionSketch.defaultTolerance
+
+    @Option(name: .shortAndLong, help: "Output .dxf or .scad file (default: <model>-section.dxf)", completion: .file(extensions: ["dxf", "scad"]))
+    var out: String?
+
+    func validate() throws {
+        if tolerance <= 0 {
+            throw ValidationError("--tolerance must be a positive distance in mm.")
+        }
+        if let out, !["dxf", "scad"].contains(URL(fileURLWithPath: out).pathExtension.lowercased()) {
+            throw ValidationError("The output must be a .dxf or .scad file.")
+        }
+    }
+
+    func run() throws {
+        let url = URL(fileURLWithPath: model)
+        let loaded = try ModelFileLoader.load(url: url)
+        let box = loaded.boundingBox()
+        let position = at ?? (box.min.component(axis: axis.index) + box.max.component(axis: axis.index)) / 2
+        let sketch = try SectionSketch(model: loaded, axis: axis, position: position, tolerance: tolerance)
+
+        let destination = out.map { URL(fileURLWithPath: $0) }
+            ?? url.deletingLastPathComponent().appendingPathComponent("\(ModelFileLoader.modelName(of: url))-section.dxf")
+        if destination.pathExtension.lowercased() == "scad" {
+            let code = OpenSCADGenerator.profileModule(
+                sketch,
+                moduleName: "\(ModelFileLoader.modelName(of: url))_section",
+                source: url.lastPathComponent
+            )
+            try code.write(to: destination, atomically: true, encoding: .utf8)
+        } else {
+            try DXFExporter.write(sketch, to: destination)
+        }
+
+        print(destination.path)
+        print("\(sketch.loops.count) outlines at \(axis.rawValue.uppercased()) = \(String(format: "%g", position)) mm: "
+            + "\(sketch.lineCount) lines, \(sketch.arcCount) arcs, \(sketch.circleCount) circles from \(sketch.segmentCount) segments")
+        if sketch.openChains > 0 {
+            FileHandle.standardError.write(Data("Warning: \(sketch.openChains) open outline(s) skipped; the model is not watertight at this section\n".utf8))
+        }
+    }
+}
@@ -0,0 +1,231 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl tui <model>` - live analysis dashboard for terminals without the viewer (e.g. over SSH)
+struct TUICommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "tui",
+        abstract: "Show a live-updating analysis dashboard in the terminal.",
+        discussion: """
+            Renders the model (OpenSCAD and Python scripts are rendered first), shows its metrics, the \
+            files being watched and the render log (echoes, warnings and errors) full screen, and re-runs \
+            the analysis on every change to the model or its includes. Metrics that changed since the last \
+            run are marked with ▲/▼ and the difference. Press r to re-render and q (or Ctrl-C) to quit. \
+            Needs an interactive terminal; use gostl analyze --watch for plain output, e.g. in logs.
+            """
+    )
+
+    @Argument(help: "Model file (.stl, .3mf, .scad, .py or a plugin format)", completion: .renderableModelFiles)
+    var model: String
+
+    @Option(help: "Material for the mass and cost estimate (\(Material.allCases.map { $0.rawValue.lowercased() }.joined(separator: ", ")))")
+    var material: Material?
+
+    @Option(help: "Infill percentage for the mass estimate (0-100, printed materials only)")
+    var infill: Double?
+
+    @Option(help: "Faces within this angle of vertical count as vertical in the area breakdown (degrees)")
+    var verticalTolerance: Double = OrientationAreas.defaultVerticalTolerance
+
+    @Flag(help: "Add the tip-over check: stability margin and tilt angle when resting on the bottom face")
+    var stability = false
+
+    func validate() throws {
+        if let infill, !(0...100).contains(infill) {
+            throw ValidationError("--infill must be between 0 and 100.")
+        }
+        if !(0..<90).contains(verticalTolerance) {
+            throw ValidationError("--vertical-tolerance must be an angle from 0 to 90 degrees.")
+        }
+    }
+
+    func run() throws {
+        guard isatty(STDIN_FILENO) != 0, isatty(STDOUT_FILENO) != 0 else {
+            throw ValidationError("gostl tui needs an interactive terminal; use gostl analyze --watch for plain output.")
+        }
+        let url = URL(fileURLWithPath: model).standardizedFileURL
+        guard FileManager.default.fileExists(atPath: url.path) else {
+            throw ValidationError("\(model) does not exist.")
+        }
+
+        let estimate: (material: Material, infill: Double)? = material != nil || infill != nil
+            ? (material ?? .pla, (infill ?? 100) / 100)
+            : nil
+        let session = DashboardSession(url: url) { [verticalTolerance, stability] model in
+            AnalyzeCommand.record(
+                for: model,
+                fileName: url.lastPathComponent,
+                estimate: estimate,
+                verticalTolerance: verticalTolerance,
+                stability: stability
+            )
+        }
+        session.start()
+        withExtendedLifetime(session) {
+            dispatchMain()
+        }
+    }
+}
+
+/// Keeps the dashboard on screen: re-renders on changes and keys, redraws on resizes
+private final class DashboardSession: @unchecked Sendable {
+    private let url: URL
+    private let analyze: (STLModel) -> AnalysisRecord
+    private let screen = TerminalScreen()
+    private let watcher = FileWatcher()
+    private var dashboard: TerminalDashboard
+    private var isRendering = false
+    /// A change arrived while rendering; render again when done
+    private var isStale = false
+    private var sources: [DispatchSourceProtocol] = []
+
+    init(url: URL, analyze: @escaping (STLModel) -> AnalysisRecord) {
+        self.url = url
+        self.analyze = analyze
+        self.dashboard = TerminalDashboard(fileName: url.lastPathComponent)
+    }
+
+    func start() {
+        screen.enter()
+        for signalNumber in [SIGINT, SIGTERM, SIGWINCH] {
+            signal(signalNumber, SIG_IGN)
+            let source = DispatchSource.makeSignalSource(signal: signalNumber, queue: .main)
+            source.setEventHandler { [weak self] in
+                if signalNumber == SIGWINCH {
+                    self?.draw()
+                } else {
+                    self?.quit()
+                }
+            }
+            source.resume()
+            sources.append(source)
+        }
+
+        let input = DispatchSource.makeReadSource(fileDescriptor: STDIN_FILENO, queue: .main)
+        input.setEventHandler { [weak self] in
+            var buffer = [UInt8](repeating: 0, count: 64)
+            let count = read(STDIN_FILENO, &buffer, buffer.count)
+            guard count > 0 else {
+                self?.quit()
+                return
+            }
+            for key in buffer.prefix(count) {
+                switch key {
+                case UInt8(ascii: "q"), UInt8(ascii: "Q"):
+                    self?.quit()
+                case UInt8(ascii: "r"), UInt8(ascii: "R"):
+                    self?.update()
+                default:
+                    break
+                }
+            }
+        }
+        input.resume()
+        sources.append(input)
+
+        update()
+    }
+
+    /// Runs on the main queue
+    private func update() {
+        guard !isRendering else {
+            isStale = true
+            return
+        }
+        isRendering = true
+        dashboard.status = .rendering
+        draw()
+
+        // Render after the status is on screen; keys and resizes wait until it is done
+        DispatchQueue.main.async {
+            self.render()
+        }
+    }
+
+    private func render() {
+        let started = Date()
+        do {
+            let (model, messages) = try ModelFileLoader.loadRenderingWithMessages(url: url)
+            dashboard.update(record: analyze(model), messages: messages, duration: Date().timeIntervalSince(started))
+        } catch {
+            dashboard.fail(error)
+        }
+        isRendering = false
+
+        // Includes and imports may have been added or removed
+        let files = ModelFileLoader.dependencies(of: url)
+        if files != dashboard.watchedFiles {
+            dashboard.watchedFiles = files
+            do {
+                try watcher.watch(files: files) { [weak self] _ in
+                    DispatchQueue.main.async {
+                        self?.update()
+                    }
+                }
+            } catch {
+                dashboard.log.append("Cannot watch \(url.lastPathComponent): \(error.localizedDescription)")
+            }
+        }
+
+        if isStale {
+            isStale = false
+            update()
+        } else {
+            draw()
+        }
+    }
+
+    private func draw() {
+        let size = screen.size
+        screen.draw(dashboard.lines(width: size.width, height: size.height))
+    }
+
+    private func quit() {
+        watcher.stop()
+        screen.leave()
+        exit(0)
+    }
+}
+
+/// Full-screen terminal output: alternate screen, no echo or line buffering, hidden cursor
+private final class TerminalScreen {
+    private var original = termios()
+
+    /// Columns and rows, 80 × 24 if the terminal does not say
+    var size: (width: Int, height: Int) {
+        var window = winsize()
+        guard ioctl(STDOUT_FILENO, TIOCGWINSZ, &window) == 0, window.ws_col > 0, window.ws_row > 0 else {
+            return (80, 24)
+        }
+        return (Int(window.ws_col), Int(window.ws_row))
+    }
+
+    func enter() {
+        tcgetattr(STDIN_FILENO, &original)
+        var raw = original
+        raw.c_lflag &= ~tcflag_t(ECHO | ICANON)
+        tcsetattr(STDIN_FILENO, TCSANOW, &raw)
+        write("\u{1B}[?1049h\u{1B}[?25l")
+    }
+
+    func leave() {
+        write("\u{1B}[?25h\u{1B}[?1049l")
+        tcsetattr(STDIN_FILENO, TCSANOW, &original)
+    }
+
+    /// Overwrite the screen from the top; the first line is the title bar
+    func draw(_ lines: [String]) {
+        var output = "\u{1B}[H"
+        for (index, line) in lines.enumerated() {
+            if index > 0 {
+                output += "\r\n"
+            }
+            output += index == 0 ? "\u{1B}[7m\(line)\u{1B}[0m" : line + "\u{1B}[K"
+        }
+        write(output + "\u{1B}[J")
+    }
+
+    private func write(_ text: String) {
+        FileHandle.standardOutput.write(Data(text.utf8))
+    }
+}
@@ -0,0 +1,165 @@
+import Foundation
+
+/// Screen of `gostl tui`: analysis metrics, watcher status and the render log
+///
+/// The layout is a pure function of the state and the terminal size, so it can be tested without
+/// a terminal; `TUICommand` adds the escape sequences and redraws on every change.
+struct TerminalDashboard {
+    enum Status: Equatable {
+        case rendering
+        case ready(duration: TimeInterval)
+        case failed(String)
+    }
+
+    /// Record keys left out of the metrics panel (the dimensions say the same while iterating)
+    static let hiddenKeyPrefixes = ["file", "bounds.", "edge_length."]
+
+    /// Rows kept for the render log when the metrics do not fit
+    static let minimumLogRows = 3
+
+    /// Smallest terminal the dashboard is laid out for
+    static let minimumSize = (width: 30, height: 10)
+
+    var fileName: String
+    var status: Status = .rendering
+    var record: AnalysisRecord?
+    /// Metrics that changed in the last analysis, by key
+    var changes: [String: AnalysisDiff.Change] = [:]
+    var watchedFiles: [URL] = []
+    /// Completed analyses, successful or not
+    var runs = 0
+    var lastUpdate: Date?
+    /// Messages of the last render (echoes, warnings, errors)
+    var log: [String] = []
+
+    init(fileName: String) {
+        self.fileName = fileName
+    }
+
+    /// Show a new analysis, remembering which metrics changed since the previous one
+    mutating func update(record: AnalysisRecord, messages: [String], duration: TimeInterval, at date: Date = Date()) {
+        if let previous = self.record {
+            let diff = AnalysisDiff(from: previous, to: record, keys: record.keys)
+            changes = Dictionary(diff.changes.map { ($0.key, $0) }, uniquingKeysWith: { first, _ in first })
+        } else {
+            changes = [:]
+        }
+        self.record = record
+        log = messages
+        status = .ready(duration: duration)
+        runs += 1
+        lastUpdate = date
+    }
+
+    /// Keep the last metrics, but show the error and what the renderer printed
+    mutating func fail(_ error: Error, at date: Date = Date()) {
+        let messages: [String]
+        switch error {
+        case let error as OpenSCADError: messages = error.messages
+        case let error as PythonCADError: messages = error.messages
+        default: messages = []
+        }
+        status = .failed(error.localizedDescription)
+        log = messages.isEmpty ? error.localizedDescription.components(separatedBy: .newlines) : messages
+        changes = [:]
+        runs += 1
+        lastUpdate = date
+    }
+
+    // MARK: - Layout
+
+    /// Exactly `height` lines of at most `width` characters
+    func lines(width: Int, height: Int) -> [String] {
+        guard width >= Self.minimumSize.width, height >= Self.minimumSize.height else {
+            return [fit("Terminal too small", width: width)] + Array(repeating: "", count: max(height - 1, 0))
+        }
+
+        let time = lastUpdate.map { DateFormatter.localizedString(from: $0, dateStyle: .none, timeStyle: .medium) } ?? ""
+        let title = " GoSTL  \(fileName)"
+        let header = fit(title, width: width - time.count - 1)
+            .padding(toLength: width - time.count - 1, withPad: " ", startingAt: 0) + time + " "
+
+        var top = [header, "Status    \(statusText)", "Watching  \(watchingText)"]
+        top = top.map { fit($0, width: width) }
+        let footer = fit(" q quit   r re-render", width: width)
+
+        // Header lines, two section titles and the footer
+        let available = height - top.count - 3
+        let metrics = metricLines
+        let logRows = max(Self.minimumLogRows, available - metrics.count)
+        let metricRows = available - logRows
+
+        var metricPanel = Array(metrics.prefix(metricRows))
+        if metrics.count > metricRows, metricRows > 0 {
+            metricPanel[metricRows - 1] = "… \(metrics.count - metricRows + 1) more"
+        }
+        metricPanel += Array(repeating: "", count: metricRows - metricPanel.count)
+
+        let messages = log.isEmpty ? ["(no messages)"] : log
+        var logPanel = Array(messages.suffix(logRows))
+        logPanel += Array(repeating: "", count: logRows - logPanel.count)
+
+        return top
+            + [sectionTitle("Metrics", width: width)]
+            + metricPanel.map { fit($0, width: width) }
+            + [sectionTitle("Render log", width: width)]
+            + logPanel.map { fit($0, width: width) }
+            + [footer]
+    }
+
+    private var statusText: String {
+        switch status {
+        case .rendering:
+            return "Rendering…"
+        case .ready(let duration):
+            return String(format: "Up to date, analyzed in %.2f s (run %d)", duration, runs)
+        case .failed(let message):
+            let firstLine = message.components(separatedBy: .newlines).first ?? message
+            return "Failed: \(firstLine)"
+        }
+    }
+
+    private var watchingText: String {
+        guard !watchedFiles.isEmpty else { return "nothing" }
+        let names = watchedFiles.map(\.lastPathComponent).joined(separator: ", ")
+        return watchedFiles.count == 1 ? names : "\(watchedFiles.count) files: \(names)"
+    }
+
+    /// `Volume  1250.50 mm³  ▲ +50.50`, one per shown field
+    private var metricLines: [String] {
+        guard let record else { return ["Waiting for the first analysis…"] }
+        let fields = record.fields.filter { field in !Self.hiddenKeyPrefixes.contains { field.key.hasPrefix($0) } }
+        let labelWidth = fields.map(\.label.count).max() ?? 0
+        let values = fields.map { field -> String in
+            let value: String
+            switch field.value {
+            case .number(let number): value = String(format: "%.2f", number)
+            default: value = field.value.plain
+            }
+            return value + (field.unit.map { " \($0)" } ?? "")
+        }
+        let valueWidth = values.map(\.count).max() ?? 0
+
+        return zip(fields, values).map { field, value in
+            var line = field.label.padding(toLength: labelWidth, withPad: " ", startingAt: 0) + "  "
+                + value.padding(toLength: valueWidth, withPad: " ", startingAt: 0)
+            if let change = changes[field.key] {
+                let magnitude = field.key == "triangles" ? String(Int(abs(change.delta))) : String(format: "%.2f", abs(change.delta))
+                line += change.delta > 0 ? "  ▲ +\(magnitude)" : "  ▼ -\(magnitude)"
+            }
+            return line
+        }
+    }
+
+    private func sectionTitle(_ title: String, width: Int) -> String {
+        let text = "── \(title) "
+        return text + String(repeating: "─", count: max(width - text.count, 0))
+    }
+
+    /// Cut to the width, marking the cut with an ellipsis
+    private func fit(_ line: String, width: Int) -> String {
+        guard line.count > width else { return line }
+        guard width > 1 else { return String(line.prefix(max(width, 0))) }
+        return String(line.prefix(width - 1)) + "…"
+    }
+}
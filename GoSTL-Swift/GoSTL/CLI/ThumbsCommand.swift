@@ -0,0 +1,120 @@
+import ArgumentParser
+import Foundation
+
+/// `gostl thumbs <dir>` - render PNG previews of the models in a folder
+struct ThumbsCommand: ParsableCommand {
+    static let configuration = CommandConfiguration(
+        commandName: "thumbs",
+        abstract: "Render PNG thumbnails of models, optionally embedding them into 3MF files.",
+        discussion: """
+            Writes <model file name>.png for every model into the output folder (default: a "thumbnails" \
+            folder next to the models). Thumbnails newer than their model are kept unless --force is given. \
+            With --embed, 3MF files get the thumbnail as their package preview.
+            """
+    )
+
+    @Argument(help: "Model files or folders", completion: .file(extensions: ModelFileLoader.supportedExtensions))
+    var paths: [String]
+
+    @Option(name: .shortAndLong, help: "Output folder", completion: .directory)
+    var output: String?
+
+    @Option(help: "Width and height in pixels")
+    var size = 256
+
+    @Flag(help: "Also embed the thumbnail into .3mf files")
+    var embed = false
+
+    @Flag(help: "Use the thumbnail embedded in a 3MF file instead of rendering, if present")
+    var extract = false
+
+    @Flag(help: "Regenerate thumbnails that are up to date")
+    var force = false
+
+    @Flag(help: "Shade the thumbnails with baked ambient occlusion (slower, better depth cues)")
+    var ambientOcclusion = false
+
+    /// Default output folder name next to the models
+    static let defaultFolderName = "thumbnails"
+
+    func validate() throws {
+        if paths.isEmpty {
+            throw ValidationError("Specify a folder or model files.")
+        }
+        if !(16...2048).contains(size) {
+            throw ValidationError("--size must be between 16 and 2048.")
+        }
+        if embed && extract {
+            throw ValidationError("--embed and --extract cannot be combined.")
+        }
+    }
+
+    func run() throws {
+        let models = try paths.flatMap { try Self.modelFiles(at: URL(fileURLWithPath: $0)) }
+        guard !models.isEmpty else {
+            print("No .stl or .3mf files found")
+            return
+        }
+
+        var renderer: OffscreenRenderer?
+        var failures = 0
+        for model in models {
+            let folder = output.map { URL(fileURLWithPath: $0) }
+                ?? model.deletingLastPathComponent().appendingPathComponent(Self.defaultFolderName)
+            let thumbnail = Self.thumbnailURL(for: model, in: folder)
+            if !force && Self.isUpToDate(thumbnail, model: model) {
+                continue
+            }
+
+            do {
+                try FileManager.default.createDirectory(at: folder, withIntermediateDirectories: true)
+                let isThreeMF = ModelFileLoader.modelExtension(of: model) == "3mf" && FileCompression(url: model) == nil
+                var png = try extract && isThreeMF ? ThreeMFThumbnail.extract(from: Data(contentsOf: model)) : nil
+                if png == nil {
+                    if renderer == nil {
+                        renderer = try OffscreenRenderer()
+                    }
+                    png = try renderer?.renderPNG(model: ModelFileLoader.load(url: model), size: size, ambientOcclusion: ambientOcclusion)
+                }
+                guard let png else { continue }
+                // Embed first so the thumbnail stays newer than the rewritten model
+                if embed && isThreeMF {
+                    try ThreeMFThumbnail.embed(png: png, into: Data(contentsOf: model)).write(to: model, options: .atomic)
+                }
+                try png.write(to: thumbnail, options: .atomic)
+                print(thumbnail.path)
+            } catch {
+                failures += 1
+                print("\(model.lastPathComponent): \(error.localizedDescription)")
+            }
+        }
+
+        if failures > 0 {
+            throw ExitCode.failure
+        }
+    }
+
+    /// Model files at a path: the file itself, or the models directly inside a folder
+    static func modelFiles(at url: URL) throws -> [URL] {
+        var isDirectory: ObjCBool = false
+        guard FileManager.default.fileExists(atPath: url.path, isDirectory: &isDirectory) else {
+            throw ValidationError("\(url.path) does not exist.")
+        }
+        guard isDirectory.boolValue else { return [url] }
+
+        return try FileManager.default.contentsOfDirectory(at: url, includingPropertiesForKeys: nil, options: .skipsHiddenFiles)
+            .filter { ModelFileLoader.supportedExtensions.contains(ModelFileLoader.modelExtension(of: $0)) }
+            .sorted { $0.lastPathComponent < $1.lastPathComponent }
+    }
+
+    /// `part.stl` -> `<folder>/part.stl.png` (keeps `part.stl` and `part.3mf` apart)
+    static func thumbnailURL(for model: URL, in folder: URL) -> URL {
+        folder.appendingPathComponent(model.lastPathComponent + ".png")
+    }
+
+    private static func isUpToDate(_ thumbnail: URL, model: URL) -> Bool {
+        let attributes = { (url: URL) in try? FileManager.default.attributesOfItem(atPath: url.path)[.modificationDate] as? Date }
+        guard let thumbnailDate = attributes(thumbnail), let modelDate = attributes(model) else { return false }
+        return thumbnailDate >= modelDate
+    }
+}
@@ -18,6 +18,26 @@ final class Camera {
     /// Target point to orbit around
     var target: SIMD3<Float> = .zero
 
+    /// Near clipping plane distance
+    var nearPlane: Float = 0.1
+
+    /// Far clipping plane distance
+    var farPlane: Float = 10000.0
+
+    /// Whether near/far planes are fitted to the scene bounds automatically
+    var autoDepthRange: Bool = true
+
+    /// Distance of the cutaway plane ahead of the camera (nil = no cutaway)
+    /// Geometry between the camera and the plane is hidden, revealing the interior
+    var cutawayDepth: Float?
+
+    /// Display-only stretch along Z (1 = true scale), e.g. to make thin PCB components readable
+    /// Geometry, picking and measurements stay in model coordinates; only what is drawn is scaled.
+    var zExaggeration: Float = 1
+
+    /// Model Z that stays in place when exaggerating (e.g. the board plane)
+    var exaggerationOrigin: Float = 0
+
     // Default values for reset
     private var defaultDistance: Double = 100.0
     private var defaultAngleX: Double = 0.3
@@ -35,6 +55,11 @@ final class Camera {
         return target + SIMD3(x, y, z)
     }
 
+    /// Unit view direction (from the camera toward the target)
+    var forward: SIMD3<Float> {
+        simd_normalize(target - position)
+    }
+
     /// Up vector for the camera (Z-up coordinate system)
     var up: SIMD3<Float> {
         SIMD3(0, 0, 1)
@@ -47,9 +72,129 @@ final class Camera {
         matrix_lookAt(eye: position, center: target, up: up)
     }
 
-    /// Generate projection matrix
-    func projectionMatrix(aspect: Float, fov: Float = .pi / 4, near: Float = 0.1, far: Float = 10000.0) -> simd_float4x4 {
-        matrix_perspective(fov: fov, aspect: aspect, near: near, far: far)
+    /// Vertical field of view of the projection (radians)
+    static let fieldOfView: Float = .pi / 4
+
+    /// Generate projection matrix (uses the camera's near/far planes unless overridden)
+    func projectionMatrix(aspect: Float, fov: Float = Camera.fieldOfView, near: Float? = nil, far: Float? = nil) -> simd_float4x4 {
+        matrix_perspective(fov: fov, aspect: aspect, near: near ?? nearPlane, far: far ?? farPlane)
+    }
+
+    /// World size covered by one point on screen at a distance from the eye
+    /// - Parameter viewHeight: Height of the view in the same unit as the result (points or pixels)
+    static func worldSize(ofPointAtDistance distance: Double, viewHeight: Double) -> Double {
+        guard viewHeight > 0 else { return 0 }
+        return 2 * distance * tan(Double(fieldOfView) / 2) / viewHeight
+    }
+
+    // MARK: - Z Exaggeration
+
+    /// Whether the display is stretched along Z
+    var isExaggerated: Bool {
+        zExaggeration != 1
+    }
+
+    /// Transform from model coordinates to displayed (exaggerated) coordinates
+    func displayMatrix() -> simd_float4x4 {
+        guard isExaggerated else { return matrix_identity_float4x4 }
+        var matrix = matrix_identity_float4x4
+        matrix[2][2] = zExaggeration
+        matrix[3][2] = exaggerationOrigin * (1 - zExaggeration)
+        return matrix
+    }
+
+    /// Convert a model point to displayed coordinates
+    func toDisplay(_ point: SIMD3<Float>) -> SIMD3<Float> {
+        guard isExaggerated else { return point }
+        return SIMD3(point.x, point.y, exaggerationOrigin + (point.z - exaggerationOrigin) * zExaggeration)
+    }
+
+    /// Convert a displayed point back to model coordinates
+    func toModel(_ point: SIMD3<Float>) -> SIMD3<Float> {
+        guard isExaggerated else { return point }
+        return SIMD3(point.x, point.y, exaggerationOrigin + (point.z - exaggerationOrigin) / zExaggeration)
+    }
+
+    /// Convert a ray in displayed coordinates to model coordinates (for picking)
+    func toModel(_ ray: Ray) -> Ray {
+        guard isExaggerated else { return ray }
+        let direction = SIMD3(ray.direction.x, ray.direction.y, ray.direction.z / zExaggeration)
+        return Ray(origin: toModel(ray.origin), direction: simd_normalize(direction))
+    }
+
+    /// Bounding box as displayed
+    func toDisplay(_ bbox: BoundingBox) -> BoundingBox {
+        guard isExaggerated else { return bbox }
+        let low = toDisplay(bbox.min.float3)
+        let high = toDisplay(bbox.max.float3)
+        return BoundingBox(
+            min: Vector3(Double(low.x), Double(low.y), Double(Swift.min(low.z, high.z))),
+            max: Vector3(Double(high.x), Double(high.y), Double(Swift.max(low.z, high.z)))
+        )
+    }
+
+    // MARK: - Depth Range
+
+    /// Far/near ratio above which depth precision degrades visibly (z-fighting)
+    static let depthRatioWarningThreshold: Float = 100_000
+
+    /// Far/near ratio that auto fitting never exceeds
+    static let maxAutoDepthRatio: Float = 10_000
+
+    /// Ratio between far and near plane
+    var depthRatio: Float {
+        farPlane / max(nearPlane, .leastNonzeroMagnitude)
+    }
+
+    /// Whether the current near/far planes are likely to cause z-fighting
+    var hasDepthPrecisionWarning: Bool {
+        depthRatio > Self.depthRatioWarningThreshold
+    }
+
+    /// Set near/far planes manually (disables auto fitting)
+    func setDepthRange(near: Float, far: Float) {
+        autoDepthRange = false
+        nearPlane = max(near, 0.0001)
+        farPlane = max(far, nearPlane * 1.001)
+    }
+
+    /// Fit near/far planes tightly around the given scene bounds
+    /// Only updates the planes when they change noticeably, to avoid needless view updates
+    func fitDepthRange(to modelBounds: BoundingBox) {
+        let bounds = toDisplay(modelBounds)
+        let center = bounds.center.float3
+        let radius = max(Float(bounds.diagonal) / 2, 0.001)
+        let distanceToCenter = simd_distance(position, center)
+
+        // Sphere around the scene, with a small margin
+        let far = (distanceToCenter + radius) * 1.1
+        let near = max((distanceToCenter - radius) * 0.9, far / Self.maxAutoDepthRatio)
+
+        let tolerance: Float = 0.01
+        if abs(near - nearPlane) > nearPlane * tolerance || abs(far - farPlane) > farPlane * tolerance {
+            nearPlane = near
+            farPlane = far
+        }
+    }
+
+    // MARK: - Cutaway
+
+    /// Cutaway plane (normal, offset) in world space; points with `dot(normal, p) + offset < 0` are cut away
+    /// Zero when the cutaway is off, which the shaders treat as "keep everything"
+    var cutawayPlane: SIMD4<Float> {
+        guard let depth = cutawayDepth else { return .zero }
+        let normal = forward
+        let point = position + normal * depth
+        return SIMD4(normal, -simd_dot(normal, point))
+    }
+
+    /// Move a ray that starts at the camera to where it enters the cutaway plane,
+    /// so picking ignores the geometry that is cut away
+    func clipToCutaway(_ ray: Ray) -> Ray {
+        guard let depth = cutawayDepth, depth > 0 else { return ray }
+        let cosine = simd_dot(ray.direction, forward)
+        guard cosine > 0 else { return ray }
+        return Ray(origin: ray.origin + ray.direction * (depth / cosine), direction: ray.direction)
     }
 
     // MARK: - Camera Manipulation
@@ -63,12 +208,48 @@ final class Camera {
         angleX = max(-Double.pi / 2 + 0.1, min(Double.pi / 2 - 0.1, angleX))
     }
 
+    /// Step of snapped rotation (Control+drag)
+    static let snapAngle = Double.pi / 12
+
+    /// Set the angles rounded to multiples of `step`; the pitch stays clamped short of the poles
+    func setSnappedAngles(x: Double, y: Double, step: Double = snapAngle) {
+        stopInertia()
+        angleX = 0
+        angleY = 0
+        rotate(deltaX: (x / step).rounded() * step, deltaY: (y / step).rounded() * step)
+    }
+
+    /// Turn by exact quarter turns: yaw around Z, pitch up or down to the top or bottom view
+    ///
+    /// A view between quarter turns is first rounded to the nearest one, so repeated steps give
+    /// orthogonal views that match from screenshot to screenshot.
+    func rotateQuarterTurns(pitch: Int, yaw: Int) {
+        let step = Double.pi / 2
+        setSnappedAngles(
+            x: ((angleX / step).rounded() + Double(pitch)) * step,
+            y: ((angleY / step).rounded() + Double(yaw)) * step,
+            step: step
+        )
+    }
+
     /// Zoom camera (adjust distance)
     func zoom(delta: Double) {
         distance += delta
         distance = max(1.0, min(1000.0, distance)) // Clamp to reasonable range
     }
 
+    /// Zoom while keeping a world point fixed on screen (zoom to cursor)
+    /// - Parameter point: Point to zoom toward, or nil to zoom toward the target
+    func zoom(delta: Double, toward point: SIMD3<Float>?) {
+        let oldDistance = distance
+        zoom(delta: delta)
+        guard let point = point, oldDistance > 0 else { return }
+
+        // Scaling the camera around the point keeps it at the same screen position
+        let ratio = Float(distance / oldDistance)
+        target = point + (target - point) * ratio
+    }
+
     /// Pan camera (move target)
     func pan(delta: SIMD2<Float>) {
         let right = simd_normalize(simd_cross(target - position, up))
@@ -79,6 +260,7 @@ final class Camera {
 
     /// Reset to default view
     func reset() {
+        stopInertia()
         distance = defaultDistance
         angleX = defaultAngleX
         angleY = defaultAngleY
@@ -87,6 +269,7 @@ final class Camera {
 
     /// Set camera to a preset view
     func setPreset(_ preset: CameraPreset) {
+        stopInertia()
         let (x, y) = preset.angles
         angleX = x
         angleY = y
@@ -101,7 +284,9 @@ final class Camera {
     }
 
     /// Frame a bounding box in view
-    func frameBoundingBox(_ bbox: BoundingBox) {
+    func frameBoundingBox(_ modelBBox: BoundingBox) {
+        stopInertia()
+        let bbox = toDisplay(modelBBox)
         // Set target to center of bounding box
         target = bbox.center.float3
 
@@ -113,6 +298,105 @@ final class Camera {
         saveAsDefault()
     }
 
+    /// Center the view on a point, keeping the current viewing angles
+    /// - Parameter distance: New distance from the point (keeps the current distance if nil)
+    func focus(on point: Vector3, distance: Double? = nil) {
+        stopInertia()
+        target = toDisplay(point.float3)
+        if let distance = distance {
+            self.distance = max(1.0, min(1000.0, distance))
+        }
+    }
+
+    // MARK: - Inertia
+
+    /// Angular velocity (radians/s) carried on after a rotate drag is released
+    @ObservationIgnored var rotationVelocity: SIMD2<Double> = .zero
+
+    /// Zoom velocity (distance units/s) for gliding mouse-wheel zoom
+    @ObservationIgnored var zoomVelocity: Double = 0
+
+    /// Point the inertial zoom moves toward (nil zooms toward the target)
+    @ObservationIgnored var zoomAnchor: SIMD3<Float>?
+
+    /// How quickly inertial motion fades out (1/s). A velocity v travels v / damping in total.
+    static let inertiaDamping: Double = 6.0
+
+    /// Whether the camera is still moving on its own
+    var hasInertia: Bool {
+        rotationVelocity != .zero || zoomVelocity != 0
+    }
+
+    /// Stop any inertial motion and target transition immediately
+    func stopInertia() {
+        rotationVelocity = .zero
+        zoomVelocity = 0
+        zoomAnchor = nil
+        targetTransition = nil
+    }
+
+    /// Advance all automatic camera motion by one frame
+    func advance(deltaTime: Double) {
+        stepTargetTransition(deltaTime: deltaTime)
+        stepInertia(deltaTime: deltaTime)
+    }
+
+    /// Advance inertial motion by one frame
+    func stepInertia(deltaTime: Double) {
+        guard hasInertia, deltaTime > 0 else { return }
+
+        // Don't jump after a stalled frame
+        let dt = min(deltaTime, 0.1)
+
+        if rotationVelocity != .zero {
+            rotate(deltaX: rotationVelocity.x * dt, deltaY: rotationVelocity.y * dt)
+        }
+        if zoomVelocity != 0 {
+            zoom(delta: zoomVelocity * dt, toward: zoomAnchor)
+        }
+
+        let decay = exp(-Self.inertiaDamping * dt)
+        rotationVelocity *= decay
+        zoomVelocity *= decay
+
+        if simd_length(rotationVelocity) < 0.01 {
+            rotationVelocity = .zero
+        }
+        if abs(zoomVelocity) < distance * 0.001 {
+            zoomVelocity = 0
+            zoomAnchor = nil
+        }
+    }
+
+    // MARK: - Target Transition
+
+    /// Duration of the smooth orbit target change (seconds)
+    static let targetTransitionDuration: Double = 0.35
+
+    @ObservationIgnored private var targetTransition: (from: SIMD3<Float>, to: SIMD3<Float>, elapsed: Double)?
+
+    /// Whether the orbit target is currently moving to a new point
+    var isTransitioningTarget: Bool {
+        targetTransition != nil
+    }
+
+    /// Smoothly move the orbit target to a model point, keeping distance and angles
+    func animateTarget(to point: SIMD3<Float>) {
+        targetTransition = (from: target, to: toDisplay(point), elapsed: 0)
+    }
+
+    /// Advance the orbit target transition by one frame
+    func stepTargetTransition(deltaTime: Double) {
+        guard var transition = targetTransition, deltaTime > 0 else { return }
+
+        transition.elapsed += min(deltaTime, 0.1)
+        let t = min(transition.elapsed / Self.targetTransitionDuration, 1)
+        let eased = Float(t * t * (3 - 2 * t))  // Smoothstep
+        target = simd_mix(transition.from, transition.to, SIMD3(repeating: eased))
+
+        targetTransition = t < 1 ? transition : nil
+    }
+
     // MARK: - Ray Casting
 
     /// Generate a ray from screen coordinates
@@ -141,6 +425,12 @@ final class Camera {
         return Ray(origin: position, direction: direction)
     }
 
+    /// Ray for picking model geometry under the cursor (starts at the cutaway plane, if any)
+    /// in model coordinates
+    func pickRay(screenPos: CGPoint, viewSize: CGSize) -> Ray {
+        toModel(clipToCutaway(mouseRay(screenPos: screenPos, viewSize: viewSize)))
+    }
+
     /// Project a 3D world position to 2D screen coordinates
     /// - Returns: CGPoint in screen coordinates, or nil if behind camera
     func worldToScreen(point: Vector3, viewSize: CGSize) -> CGPoint? {
@@ -155,7 +445,7 @@ final class Camera {
         let view = viewMatrix()
 
         // Transform world position to clip space
-        let worldPos4 = SIMD4<Float>(worldPosition.float3, 1.0)
+        let worldPos4 = SIMD4<Float>(toDisplay(worldPosition.float3), 1.0)
         let clipPos = projection * view * worldPos4
 
         // Check if behind camera
@@ -0,0 +1,59 @@
+import Foundation
+
+/// Smooth camera move from one keyframe to another, e.g. between saved views in presentation mode
+///
+/// Eases in and out, and pulls back a little while the view turns so the model stays in frame on
+/// the way instead of sweeping through it.
+struct CameraFlight {
+    /// Duration of a flight (seconds)
+    static let defaultDuration: Double = 1.5
+
+    /// Extra distance halfway through a half turn, relative to the interpolated distance
+    static let pullBack: Double = 0.3
+
+    let from: CameraKeyframe
+    let to: CameraKeyframe
+    let duration: Double
+    private(set) var elapsed: Double = 0
+
+    init(from: CameraKeyframe, to: CameraKeyframe, duration: Double = defaultDuration) {
+        self.from = from
+        self.to = to
+        self.duration = duration
+    }
+
+    var isComplete: Bool {
+        elapsed >= duration
+    }
+
+    /// Camera at the current time of the flight
+    var keyframe: CameraKeyframe {
+        let t = duration > 0 ? min(elapsed / duration, 1) : 1
+        let eased = t * t * (3 - 2 * t)  // Smoothstep
+        var keyframe = CameraKeyframe.interpolate(from, to, t: eased)
+        keyframe.distance *= 1 + Self.pullBack * turn * sin(.pi * t)
+        return keyframe
+    }
+
+    /// Move on by one frame and return the camera to show
+    mutating func advance(deltaTime: Double) -> CameraKeyframe {
+        // Don't jump after a stalled frame
+        elapsed = min(elapsed + min(max(deltaTime, 0), 0.1), duration)
+        return keyframe
+    }
+
+    /// How far the view direction turns, as a fraction of a half turn (0: same direction, 1: opposite)
+    var turn: Double {
+        let cosine = Self.viewDirection(of: from).dot(Self.viewDirection(of: to))
+        return acos(min(max(cosine, -1), 1)) / .pi
+    }
+
+    /// Unit vector from the target toward the camera (see `Camera.position`)
+    private static func viewDirection(of keyframe: CameraKeyframe) -> Vector3 {
+        Vector3(
+            cos(keyframe.angleX) * sin(keyframe.angleY),
+            cos(keyframe.angleX) * cos(keyframe.angleY),
+            sin(keyframe.angleX)
+        )
+    }
+}
@@ -0,0 +1,80 @@
+import Foundation
+
+/// A named location the go-to palette can navigate to
+struct GoToAnchor {
+    let name: String
+    let position: Vector3
+}
+
+/// Parsed input of the go-to palette
+///
+/// Supported forms:
+/// - `10, 20, 5` or `10 20 5` - world coordinates (mm)
+/// - `v42` - vertex 42 (STL order: triangle index * 3 + corner)
+/// - `t42` - centroid of triangle 42
+/// - anything else - case-insensitive search over anchor names
+enum GoToQuery: Equatable {
+    case coordinates(Vector3)
+    case vertex(Int)
+    case triangle(Int)
+    case search(String)
+
+    /// Parse palette input, returns nil for empty input
+    static func parse(_ text: String) -> GoToQuery? {
+        let trimmed = text.trimmingCharacters(in: .whitespaces)
+        guard !trimmed.isEmpty else { return nil }
+
+        // Coordinates: three numbers separated by commas and/or whitespace
+        let components = trimmed
+            .split(whereSeparator: { $0 == "," || $0 == ";" || $0.isWhitespace })
+            .map(String.init)
+        if components.count == 3 {
+            let values = components.compactMap(Double.init)
+            if values.count == 3 {
+                return .coordinates(Vector3(values[0], values[1], values[2]))
+            }
+        }
+
+        // Feature IDs: v<index> / t<index>
+        let lower = trimmed.lowercased()
+        if let prefix = lower.first, prefix == "v" || prefix == "t",
+           let index = Int(lower.dropFirst().trimmingCharacters(in: .whitespaces)), index >= 0 {
+            return prefix == "v" ? .vertex(index) : .triangle(index)
+        }
+
+        return .search(trimmed)
+    }
+
+    /// Resolve the query to a world position
+    /// - Parameter coordinateOffset: Recentering offset of the model; typed coordinates are
+    ///   in the file's original space and get shifted into rendering space
+    /// - Returns: The target position and a short description, or nil if it doesn't resolve
+    func resolve(model: STLModel?, anchors: [GoToAnchor], coordinateOffset: Vector3 = .zero) -> (position: Vector3, description: String)? {
+        switch self {
+        case .coordinates(let position):
+            return (position - coordinateOffset, String(format: "%.2f, %.2f, %.2f", position.x, position.y, position.z))
+
+        case .vertex(let index):
+            guard let model = model, index < model.triangleCount * 3 else { return nil }
+            let position = model.triangles[index / 3].vertices[index % 3]
+            return (position, "Vertex \(index)")
+
+        case .triangle(let index):
+            guard let model = model, index < model.triangleCount else { return nil }
+            return (model.triangles[index].center(), "Triangle \(index)")
+
+        case .search:
+            guard let anchor = matchingAnchors(in: anchors).first else { return nil }
+            return (anchor.position, anchor.name)
+        }
+    }
+
+    /// Anchors matching a search query (prefix matches first)
+    func matchingAnchors(in anchors: [GoToAnchor]) -> [GoToAnchor] {
+        guard case .search(let text) = self else { return [] }
+        let needle = text.lowercased()
+        let matches = anchors.filter { $0.name.lowercased().contains(needle) }
+        return matches.filter { $0.name.lowercased().hasPrefix(needle) } +
+               matches.filter { !$0.name.lowercased().hasPrefix(needle) }
+    }
+}
@@ -0,0 +1,25 @@
+import Foundation
+
+/// A named camera position (View > Saved Views), saved in the model's `.gostl` sidecar
+///
+/// The orbit target is stored in the file's original coordinates, like `SectionBookmark`.
+struct ViewBookmark: Codable, Equatable, Identifiable {
+    var id = UUID()
+    var name: String
+    var camera: CameraKeyframe
+
+    private enum CodingKeys: String, CodingKey {
+        case name, camera
+    }
+
+    init(name: String, camera: CameraKeyframe) {
+        self.name = name
+        self.camera = camera
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        name = try container.decode(String.self, forKey: .name)
+        camera = try container.decode(CameraKeyframe.self, forKey: .camera)
+    }
+}
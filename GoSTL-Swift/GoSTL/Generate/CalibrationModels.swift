@@ -0,0 +1,282 @@
+import Foundation
+
+/// Errors that can occur while generating models
+enum GenerateError: LocalizedError {
+    case unsupportedCharacters(String)
+    case textTooLong(String, maxCharacters: Int)
+    case invalidParameter(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .unsupportedCharacters(let characters):
+            return "Cannot emboss \(characters); use letters, digits, space and . - + / %"
+        case .textTooLong(let text, let maxCharacters):
+            return "\"\(text)\" does not fit; use at most \(maxCharacters) characters or a larger model"
+        case .invalidParameter(let message):
+            return message
+        }
+    }
+}
+
+/// 5×7 pixel font for embossed text, printable without a font file
+///
+/// Every pixel becomes a small box, so text needs no outline triangulation and stays crisp at
+/// nozzle-sized pixels.
+enum BlockFont {
+    static let columns = 5
+    static let rows = 7
+    /// Smallest printable pixel (mm), about one extrusion width
+    static let minimumPixel = 0.4
+
+    /// Rows from the top, `#` for raised pixels
+    static let glyphs: [Character: [String]] = [
+        "A": [".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"],
+        "B": ["####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."],
+        "C": [".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."],
+        "D": ["####.", "#...#", "#...#", "#...#", "#...#", "#...#", "####."],
+        "E": ["#####", "#....", "#....", "####.", "#....", "#....", "#####"],
+        "F": ["#####", "#....", "#....", "####.", "#....", "#....", "#...."],
+        "G": [".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"],
+        "H": ["#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"],
+        "I": [".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."],
+        "J": ["..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."],
+        "K": ["#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"],
+        "L": ["#....", "#....", "#....", "#....", "#....", "#....", "#####"],
+        "M": ["#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"],
+        "N": ["#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"],
+        "O": [".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."],
+        "P": ["####.", "#...#", "#...#", "####.", "#....", "#....", "#...."],
+        "Q": [".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"],
+        "R": ["####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"],
+        "S": [".####", "#....", "#....", ".###.", "....#", "....#", "####."],
+        "T": ["#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."],
+        "U": ["#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."],
+        "V": ["#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."],
+        "W": ["#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."],
+        "X": ["#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"],
+        "Y": ["#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#.."],
+        "Z": ["#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"],
+        "0": [".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."],
+        "1": ["..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."],
+        "2": [".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"],
+        "3": ["#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."],
+        "4": ["...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."],
+        "5": ["#####", "#....", "####.", "....#", "....#", "#...#", ".###."],
+        "6": ["..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."],
+        "7": ["#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."],
+        "8": [".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."],
+        "9": [".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."],
+        ".": [".....", ".....", ".....", ".....", ".....", ".##..", ".##.."],
+        "-": [".....", ".....", ".....", ".###.", ".....", ".....", "....."],
+        "+": [".....", "..#..", "..#..", "#####", "..#..", "..#..", "....."],
+        "/": [".....", "....#", "...#.", "..#..", ".#...", "#....", "....."],
+        "%": ["##...", "##..#", "...#.", "..#..", ".#...", "#..##", "...##"],
+        " ": [".....", ".....", ".....", ".....", ".....", ".....", "....."]
+    ]
+
+    /// Stroke weight
+    enum Style: String, CaseIterable {
+        case regular
+        /// Strokes one pixel wider, for large text or coarse nozzles
+        case bold
+    }
+
+    /// Raised pixels of a line of text, rows from the top, one blank column between characters
+    static func bitmap(_ text: String, style: Style = .regular) -> [[Bool]] {
+        let characters = Array(text.uppercased())
+        return (0..<rows).map { row in
+            var line: [Bool] = []
+            for (index, character) in characters.enumerated() {
+                if index > 0 {
+                    line.append(false)
+                }
+                let pixels = Array(glyphs[character]?[row] ?? ".....").map { $0 == "#" }
+                switch style {
+                case .regular:
+                    line += pixels
+                case .bold:
+                    // Every stroke also covers the pixel to its right
+                    line += (0...columns).map { ($0 < columns && pixels[$0]) || ($0 > 0 && pixels[$0 - 1]) }
+                }
+            }
+            return line
+        }
+    }
+
+    /// Characters of the text the font cannot draw (lowercase letters are drawn as uppercase)
+    static func unsupportedCharacters(in text: String) -> String {
+        String(text.uppercased().filter { glyphs[$0] == nil })
+    }
+
+    /// Width of the text in pixels (one pixel of spacing between characters)
+    static func width(of text: String) -> Int {
+        text.isEmpty ? 0 : text.count * (columns + 1) - 1
+    }
+
+    /// Largest pixel size at which the text fits the area, nil if it needs pixels below `minimumPixel`
+    static func pixelSize(for text: String, width: Double, height: Double, maximum: Double = .infinity) -> Double? {
+        let pixel = min(width / Double(max(Self.width(of: text), 1)), height / Double(rows), maximum)
+        return pixel >= minimumPixel ? pixel : nil
+    }
+
+    /// Raised text on a face
+    /// - Parameters:
+    ///   - origin: Bottom-left corner of the text on the face
+    ///   - right: Unit vector along the text
+    ///   - up: Unit vector from the baseline to the top of the text
+    ///   - pixel: Pixel size (mm)
+    ///   - depth: Height of the text above the face (mm), along `right × up`
+    static func emboss(_ text: String, origin: Vector3, right: Vector3, up: Vector3, pixel: Double, depth: Double) -> [Triangle] {
+        let normal = right.cross(up).normalized()
+        var triangles: [Triangle] = []
+        for (index, character) in text.uppercased().enumerated() {
+            guard let glyph = glyphs[character] else { continue }
+            let left = Double(index * (columns + 1))
+            for (row, line) in glyph.enumerated() {
+                let pixels = Array(line)
+                let bottom = Double(rows - 1 - row)
+                // One box per run of raised pixels
+                var column = 0
+                while column < pixels.count {
+                    guard pixels[column] == "#" else {
+                        column += 1
+                        continue
+                    }
+                    let start = column
+                    while column < pixels.count, pixels[column] == "#" {
+                        column += 1
+                    }
+                    triangles += ModelGenerator.box(
+                        origin: origin + right * ((left + Double(start)) * pixel) + up * (bottom * pixel),
+                        x: right * (Double(column - start) * pixel),
+                        y: up * pixel,
+                        z: normal * depth
+                    )
+                }
+            }
+        }
+        return triangles
+    }
+}
+
+extension ModelGenerator {
+    /// Height of embossed text above the surface (mm)
+    static let reliefDepth = 0.6
+
+    /// Default clearances of the tolerance gauge (mm)
+    static let defaultClearances = [0.1, 0.2, 0.3, 0.4, 0.5]
+
+    /// XYZ calibration cube: X on the front, Y on the right and Z on top, each pointing along its axis
+    ///
+    /// The optional text (e.g. material and nozzle) is embossed on the left face.
+    static func calibrationCube(size: Double = 20, text: String? = nil) throws -> STLModel {
+        let half = size / 2
+        var triangles = box(from: Vector3(-half, -half, 0), to: Vector3(half, half, size))
+
+        let letterPixel = size * 0.6 / Double(BlockFont.rows)
+        let letterWidth = Double(BlockFont.columns) * letterPixel
+        let letterHeight = Double(BlockFont.rows) * letterPixel
+        // Front (-Y): reads along +X
+        triangles += BlockFont.emboss("X", origin: Vector3(-letterWidth / 2, -half, half - letterHeight / 2),
+                                      right: Vector3(1, 0, 0), up: Vector3(0, 0, 1), pixel: letterPixel, depth: reliefDepth)
+        // Right (+X): reads along +Y
+        triangles += BlockFont.emboss("Y", origin: Vector3(half, -letterWidth / 2, half - letterHeight / 2),
+                                      right: Vector3(0, 1, 0), up: Vector3(0, 0, 1), pixel: letterPixel, depth: reliefDepth)
+        // Top (+Z): upright when seen from the front
+        triangles += BlockFont.emboss("Z", origin: Vector3(-letterWidth / 2, -letterHeight / 2, size),
+                                      right: Vector3(1, 0, 0), up: Vector3(0, 1, 0), pixel: letterPixel, depth: reliefDepth)
+
+        if let text, !text.isEmpty {
+            let unsupported = BlockFont.unsupportedCharacters(in: text)
+            guard unsupported.isEmpty else {
+                throw GenerateError.unsupportedCharacters(unsupported)
+            }
+            guard let pixel = BlockFont.pixelSize(for: text, width: size * 0.85, height: size * 0.4) else {
+                throw GenerateError.textTooLong(text, maxCharacters: (Int(size * 0.85 / BlockFont.minimumPixel) + 1) / (BlockFont.columns + 1))
+            }
+            let width = Double(BlockFont.width(of: text)) * pixel
+            let height = Double(BlockFont.rows) * pixel
+            // Left (-X): reads along -Y
+            triangles += BlockFont.emboss(text, origin: Vector3(-half, width / 2, half - height / 2),
+                                          right: Vector3(0, -1, 0), up: Vector3(0, 0, 1), pixel: pixel, depth: reliefDepth)
+        }
+        return STLModel(triangles: triangles, name: "calibration-cube")
+    }
+
+    /// Plate with one hole per clearance for a pin of the nominal diameter, and the pin
+    ///
+    /// Hole i has the diameter `pinDiameter + clearances[i]` and its clearance embossed below it;
+    /// the pin stands behind the plate. The tightest hole the printed pin still fits shows the
+    /// clearance to design with.
+    static func toleranceGauge(pinDiameter: Double = 5, clearances: [Double] = defaultClearances, thickness: Double = 4) throws -> STLModel {
+        guard pinDiameter > 0, thickness > 0 else {
+            throw GenerateError.invalidParameter("The pin diameter and plate thickness must be positive")
+        }
+        guard !clearances.isEmpty, clearances.allSatisfy({ $0 >= 0 }) else {
+            throw GenerateError.invalidParameter("Give at least one clearance, none negative")
+        }
+
+        let wall = 2.4
+        let cell = pinDiameter + (clearances.max() ?? 0) + 2 * wall
+        let labels = clearances.map { formatClearance($0) }
+        let labelPixel = labels.compactMap { BlockFont.pixelSize(for: $0, width: cell - 1, height: .infinity, maximum: 0.6) }.min()
+        let strip = labelPixel.map { Double(BlockFont.rows) * $0 + 2 } ?? 0
+        let width = cell * Double(clearances.count)
+        let left = -width / 2
+
+        // Label strip in front (-Y), the holes behind it
+        var triangles = strip > 0 ? box(from: Vector3(left, -strip, 0), to: Vector3(-left, 0, thickness)) : []
+        for (index, clearance) in clearances.enumerated() {
+            let center = Vector3(left + cell * (Double(index) + 0.5), cell / 2, 0)
+            triangles += squareWithHole(center: center, side: cell, radius: (pinDiameter + clearance) / 2, height: thickness)
+            if let labelPixel {
+                let labelWidth = Double(BlockFont.width(of: labels[index])) * labelPixel
+                triangles += BlockFont.emboss(labels[index], origin: Vector3(center.x - labelWidth / 2, -strip + 1, thickness),
+                                              right: Vector3(1, 0, 0), up: Vector3(0, 1, 0), pixel: labelPixel, depth: reliefDepth)
+            }
+        }
+
+        // Pin on a square base, long enough to push through the plate
+        let base = pinDiameter + 2 * wall
+        let pinCenter = Vector3(0, cell + 5 + base / 2, 0)
+        triangles += box(from: pinCenter + Vector3(-base / 2, -base / 2, 0), to: pinCenter + Vector3(base / 2, base / 2, 2))
+        triangles += cylinder(center: pinCenter + Vector3(0, 0, 2), radius: pinDiameter / 2, height: thickness + 6,
+                              segments: segments(for: pinDiameter / 2))
+        return STLModel(triangles: triangles, name: "tolerance-gauge")
+    }
+
+    /// "0.1" style label of a clearance (at most two decimals)
+    static func formatClearance(_ clearance: Double) -> String {
+        var text = String(format: "%.2f", clearance)
+        while text.hasSuffix("0"), !text.hasSuffix(".0") {
+            text.removeLast()
+        }
+        return text
+    }
+
+    /// Square block with a round hole through it (bottom at `center`)
+    ///
+    /// The hole's points start at 45° so four of them face the square's corners, and each outer
+    /// point is the hole point pushed out to the square: every face is a planar quad.
+    static func squareWithHole(center: Vector3, side: Double, radius: Double, height: Double) -> [Triangle] {
+        let count = segments(for: radius)
+        let top = Vector3(0, 0, height)
+        var inner: [Vector3] = [], outer: [Vector3] = []
+        for index in 0..<count {
+            let angle = Double.pi / 4 + 2 * Double.pi * Double(index) / Double(count)
+            let direction = Vector3(cos(angle), sin(angle), 0)
+            inner.append(center + direction * radius)
+            outer.append(center + direction * (side / 2 / max(abs(direction.x), abs(direction.y))))
+        }
+
+        var triangles: [Triangle] = []
+        for index in 0..<count {
+            let next = (index + 1) % count
+            triangles += quad(outer[index] + top, outer[next] + top, inner[next] + top, inner[index] + top)
+            triangles += quad(outer[index], inner[index], inner[next], outer[next])
+            triangles += quad(outer[index], outer[next], outer[next] + top, outer[index] + top)
+            triangles += quad(inner[index], inner[index] + top, inner[next] + top, inner[next])
+        }
+        return triangles
+    }
+}
@@ -0,0 +1,127 @@
+import Foundation
+
+/// Parametric primitives for tests and printing (`gostl generate`)
+///
+/// Models stand on the build plate: centered on the Z axis with their lowest point at z = 0,
+/// closed and wound counter-clockwise seen from outside. Round shapes use `segments(for:)`
+/// unless a segment count is given.
+enum ModelGenerator {
+    /// Largest distance between a circle and its polygon for the default segment count (mm)
+    static let chordTolerance = 0.02
+
+    /// Segments of a circle with the radius: fine enough for `chordTolerance`, a multiple of 4
+    /// (so round shapes have points on the axes), between 12 and 360
+    static func segments(for radius: Double) -> Int {
+        guard radius > chordTolerance else { return 12 }
+        let count = Int((Double.pi / acos(1 - chordTolerance / radius)).rounded(.up))
+        return min(max((count + 3) / 4 * 4, 12), 360)
+    }
+
+    // MARK: - Primitives
+
+    static func cube(size: Vector3) -> STLModel {
+        STLModel(triangles: box(from: Vector3(-size.x / 2, -size.y / 2, 0), to: Vector3(size.x / 2, size.y / 2, size.z)), name: "cube")
+    }
+
+    static func sphere(radius: Double, segments: Int? = nil) -> STLModel {
+        let segments = segments ?? Self.segments(for: radius)
+        let rings = segments / 2
+        func point(_ ring: Int, _ segment: Int) -> Vector3 {
+            // Exact poles, so all triangles of the first and last ring share them
+            if ring == 0 || ring == rings {
+                return Vector3(0, 0, ring == 0 ? 2 * radius : 0)
+            }
+            let theta = Double.pi * Double(ring) / Double(rings)
+            // Modulo, so the seam closes with identical vertices
+            let phi = 2 * Double.pi * Double(segment % segments) / Double(segments)
+            return Vector3(radius * sin(theta) * cos(phi), radius * sin(theta) * sin(phi), radius + radius * cos(theta))
+        }
+
+        var triangles: [Triangle] = []
+        for ring in 0..<rings {
+            for segment in 0..<segments {
+                let a = point(ring, segment), b = point(ring + 1, segment)
+                let c = point(ring + 1, segment + 1), d = point(ring, segment + 1)
+                // The first and last ring meet in the poles
+                if ring > 0 {
+                    triangles.append(Triangle(v1: a, v2: b, v3: d))
+                }
+                if ring < rings - 1 {
+                    triangles.append(Triangle(v1: b, v2: c, v3: d))
+                }
+            }
+        }
+        return STLModel(triangles: triangles, name: "sphere")
+    }
+
+    static func cylinder(radius: Double, height: Double, segments: Int? = nil) -> STLModel {
+        STLModel(triangles: cylinder(center: Vector3(0, 0, 0), radius: radius, height: height, segments: segments ?? Self.segments(for: radius)), name: "cylinder")
+    }
+
+    /// Ring around the Z axis: `majorRadius` to the center of the tube, `minorRadius` of the tube
+    static func torus(majorRadius: Double, minorRadius: Double, segments: Int? = nil, sides: Int? = nil) -> STLModel {
+        let segments = segments ?? Self.segments(for: majorRadius + minorRadius)
+        let sides = sides ?? Self.segments(for: minorRadius)
+        func point(_ segment: Int, _ side: Int) -> Vector3 {
+            let u = 2 * Double.pi * Double(segment % segments) / Double(segments)
+            let v = 2 * Double.pi * Double(side % sides) / Double(sides)
+            let distance = majorRadius + minorRadius * cos(v)
+            return Vector3(distance * cos(u), distance * sin(u), minorRadius + minorRadius * sin(v))
+        }
+
+        var triangles: [Triangle] = []
+        for segment in 0..<segments {
+            for side in 0..<sides {
+                triangles += quad(point(segment, side), point(segment + 1, side), point(segment + 1, side + 1), point(segment, side + 1))
+            }
+        }
+        return STLModel(triangles: triangles, name: "torus")
+    }
+
+    // MARK: - Building Blocks
+
+    /// Two triangles of a planar quad, counter-clockwise seen from the side the normal points to
+    static func quad(_ a: Vector3, _ b: Vector3, _ c: Vector3, _ d: Vector3) -> [Triangle] {
+        [Triangle(v1: a, v2: b, v3: c), Triangle(v1: a, v2: c, v3: d)]
+    }
+
+    /// Axis-aligned box between two corners
+    static func box(from low: Vector3, to high: Vector3) -> [Triangle] {
+        let size = high - low
+        return box(origin: low, x: Vector3(size.x, 0, 0), y: Vector3(0, size.y, 0), z: Vector3(0, 0, size.z))
+    }
+
+    /// Box spanned by three edges from a corner; the edges must be right-handed (`x × y` along `z`)
+    static func box(origin: Vector3, x: Vector3, y: Vector3, z: Vector3) -> [Triangle] {
+        let corners = [
+            origin, origin + x, origin + x + y, origin + y,
+            origin + z, origin + x + z, origin + x + y + z, origin + y + z
+        ]
+        let faces = [(0, 3, 2, 1), (4, 5, 6, 7), (0, 1, 5, 4), (1, 2, 6, 5), (2, 3, 7, 6), (3, 0, 4, 7)]
+        return faces.flatMap { quad(corners[$0.0], corners[$0.1], corners[$0.2], corners[$0.3]) }
+    }
+
+    /// Upright cylinder with its bottom center at `center`
+    static func cylinder(center: Vector3, radius: Double, height: Double, segments: Int) -> [Triangle] {
+        let bottom = circle(center: center, radius: radius, segments: segments)
+        let top = bottom.map { $0 + Vector3(0, 0, height) }
+        let topCenter = center + Vector3(0, 0, height)
+
+        var triangles: [Triangle] = []
+        for index in 0..<segments {
+            let next = (index + 1) % segments
+            triangles.append(Triangle(v1: center, v2: bottom[next], v3: bottom[index]))
+            triangles.append(Triangle(v1: topCenter, v2: top[index], v3: top[next]))
+            triangles += quad(bottom[index], bottom[next], top[next], top[index])
+        }
+        return triangles
+    }
+
+    /// Points of a horizontal circle, counter-clockwise seen from above, starting on the +X axis
+    static func circle(center: Vector3, radius: Double, segments: Int) -> [Vector3] {
+        (0..<segments).map { index in
+            let angle = 2 * Double.pi * Double(index) / Double(segments)
+            return center + Vector3(radius * cos(angle), radius * sin(angle), 0)
+        }
+    }
+}
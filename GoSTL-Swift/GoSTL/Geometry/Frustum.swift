@@ -0,0 +1,29 @@
+import simd
+
+/// The six planes of a camera's view volume, for culling bounding boxes
+struct Frustum {
+    /// Planes as (normal, distance); points inside satisfy dot(normal, p) + distance >= 0
+    let planes: [SIMD4<Float>]
+
+    /// Extract the planes of a projection × view (× model) matrix with OpenGL clip depth (-w...w)
+    init(viewProjection matrix: simd_float4x4) {
+        func row(_ index: Int) -> SIMD4<Float> {
+            SIMD4(matrix[0][index], matrix[1][index], matrix[2][index], matrix[3][index])
+        }
+        let x = row(0), y = row(1), z = row(2), w = row(3)
+        planes = [w + x, w - x, w + y, w - y, w + z, w - z]
+    }
+
+    /// Whether a box is at least partly inside (conservative: boxes near a corner may pass)
+    func intersects(min: SIMD3<Float>, max: SIMD3<Float>) -> Bool {
+        for plane in planes {
+            let normal = SIMD3(plane.x, plane.y, plane.z)
+            // Corner farthest along the plane normal
+            let corner = min.replacing(with: max, where: normal .>= 0)
+            if simd_dot(normal, corner) + plane.w < 0 {
+                return false
+            }
+        }
+        return true
+    }
+}
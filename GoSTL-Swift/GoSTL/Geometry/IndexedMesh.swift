@@ -0,0 +1,94 @@
+import Foundation
+
+/// A mesh as shared vertices and triangle index triples (for polyhedron-style exports)
+struct IndexedMesh {
+    var points: [Vector3]
+    /// Vertex indices per triangle, in the winding order of the source triangles
+    var faces: [(Int, Int, Int)]
+
+    /// Index triangles, sharing vertices with identical coordinates
+    init(triangles: [Triangle]) {
+        var indexByPoint: [Vector3: Int] = [:]
+        var points: [Vector3] = []
+        let index = { (point: Vector3) -> Int in
+            if let existing = indexByPoint[point] {
+                return existing
+            }
+            points.append(point)
+            indexByPoint[point] = points.count - 1
+            return points.count - 1
+        }
+
+        faces = triangles.map { (index($0.v1), index($0.v2), index($0.v3)) }
+        self.points = points
+    }
+
+    /// Simplify the mesh by merging all vertices within each grid cell (vertex clustering)
+    ///
+    /// Each cell's vertices are replaced by their average. Triangles that collapse to a line or point,
+    /// and duplicates of the same three vertices, are dropped. Fast and predictable, but small features
+    /// below the cell size disappear and thin walls can close up.
+    /// - Parameter cellSize: Edge length of the grid cells in mm
+    func decimated(cellSize: Double) -> IndexedMesh {
+        guard cellSize > 0 else { return self }
+
+        struct Cell: Hashable {
+            let x: Int, y: Int, z: Int
+        }
+
+        var clusterByCell: [Cell: Int] = [:]
+        var sums: [Vector3] = []
+        var counts: [Int] = []
+        let clusterOfPoint = points.map { point -> Int in
+            let cell = Cell(
+                x: Int((point.x / cellSize).rounded(.down)),
+                y: Int((point.y / cellSize).rounded(.down)),
+                z: Int((point.z / cellSize).rounded(.down))
+            )
+            if let cluster = clusterByCell[cell] {
+                sums[cluster] = sums[cluster] + point
+                counts[cluster] += 1
+                return cluster
+            }
+            clusterByCell[cell] = sums.count
+            sums.append(point)
+            counts.append(1)
+            return sums.count - 1
+        }
+
+        var seen = Set<[Int]>()
+        var faces: [(Int, Int, Int)] = []
+        for face in self.faces {
+            let a = clusterOfPoint[face.0], b = clusterOfPoint[face.1], c = clusterOfPoint[face.2]
+            guard a != b, b != c, a != c, seen.insert([a, b, c].sorted()).inserted else { continue }
+            faces.append((a, b, c))
+        }
+
+        var mesh = IndexedMesh(points: zip(sums, counts).map { $0 / Double($1) }, faces: faces)
+        mesh.removeUnusedPoints()
+        return mesh
+    }
+
+    private init(points: [Vector3], faces: [(Int, Int, Int)]) {
+        self.points = points
+        self.faces = faces
+    }
+
+    /// Drop vertices no face refers to and renumber the faces
+    private mutating func removeUnusedPoints() {
+        let source = points
+        var newIndex = [Int?](repeating: nil, count: source.count)
+        var used: [Vector3] = []
+        let remap = { (index: Int) -> Int in
+            if let mapped = newIndex[index] {
+                return mapped
+            }
+            used.append(source[index])
+            newIndex[index] = used.count - 1
+            return used.count - 1
+        }
+
+        faces = faces.map { (remap($0.0), remap($0.1), remap($0.2)) }
+        points = used
+    }
+}
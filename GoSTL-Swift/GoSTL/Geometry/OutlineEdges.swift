@@ -0,0 +1,189 @@
+import CoreGraphics
+import Foundation
+
+/// Silhouette and feature edges of a mesh for line-drawing (outline) rendering
+///
+/// Edge adjacency is built once per model. Which edges are drawn depends on the viewpoint:
+/// feature edges (creases and open boundaries) are drawn when a neighbouring face points toward
+/// the eye, silhouette edges where a front-facing face meets a back-facing one.
+struct OutlineEdges {
+    /// A mesh edge that can appear in the drawing, with the normals of its adjacent faces
+    struct Candidate {
+        let edge: Edge
+        let normals: [Vector3]
+        let isFeature: Bool
+    }
+
+    /// Edges whose adjacent faces are not coplanar (coplanar edges never show up in the drawing)
+    let candidates: [Candidate]
+
+    /// Faces closer to coplanar than this can never produce a silhouette (cosine of ~0.1°)
+    private static let coplanarCosine = 0.999998
+
+    /// Build the edge adjacency of a model
+    /// - Parameters:
+    ///   - model: The mesh
+    ///   - featureAngle: Minimum angle in degrees between face normals for a crease to be drawn from every side
+    init(model: STLModel, featureAngle: Double = 30.0) {
+        var normalsByEdge: [Edge: [Vector3]] = [:]
+        normalsByEdge.reserveCapacity(model.triangles.count * 3)
+
+        for triangle in model.triangles {
+            for edge in [Edge(triangle.v1, triangle.v2), Edge(triangle.v2, triangle.v3), Edge(triangle.v3, triangle.v1)] {
+                normalsByEdge[edge, default: []].append(triangle.normal)
+            }
+        }
+
+        let featureCosine = cos(featureAngle * .pi / 180.0)
+        var candidates: [Candidate] = []
+        for (edge, normals) in normalsByEdge {
+            // Open boundaries and non-manifold edges are always features
+            guard normals.count == 2 else {
+                candidates.append(Candidate(edge: edge, normals: normals, isFeature: true))
+                continue
+            }
+
+            let cosine = normals[0].dot(normals[1])
+            guard cosine < Self.coplanarCosine else { continue }
+            candidates.append(Candidate(edge: edge, normals: normals, isFeature: cosine < featureCosine))
+        }
+        self.candidates = candidates
+    }
+
+    /// Edges to draw when looking from the given eye position (perspective view)
+    func visibleEdges(from eye: Vector3) -> [Edge] {
+        visibleEdges { eye - $0 }
+    }
+
+    /// Edges to draw when looking along the given direction (orthographic view)
+    func visibleEdges(viewDirection: Vector3) -> [Edge] {
+        let toEye = -viewDirection
+        return visibleEdges { _ in toEye }
+    }
+
+    private func visibleEdges(toEye: (Vector3) -> Vector3) -> [Edge] {
+        candidates.compactMap { candidate in
+            let direction = toEye(candidate.edge.start)
+            let frontFacing = candidate.normals.map { $0.dot(direction) > 0 }
+            let anyFront = frontFacing.contains(true)
+
+            if candidate.isFeature {
+                return anyFront ? candidate.edge : nil
+            }
+            return anyFront && frontFacing.contains(false) ? candidate.edge : nil
+        }
+    }
+
+    // MARK: - Projection
+
+    /// Project edges into 2D view coordinates (origin top-left), dropping hidden parts
+    /// - Parameters:
+    ///   - edges: Edges to project (typically `visibleEdges(from:)` for the camera position)
+    ///   - camera: Camera defining the view
+    ///   - viewSize: Size of the drawing
+    ///   - accelerator: Ray caster for hidden-line removal (nil draws every edge)
+    /// - Returns: Line segments in view coordinates
+    static func project(
+        _ edges: [Edge],
+        camera: Camera,
+        viewSize: CGSize,
+        accelerator: SpatialAccelerator?
+    ) -> [(CGPoint, CGPoint)] {
+        let position = camera.position
+        let eye = Vector3(Double(position.x), Double(position.y), Double(position.z))
+
+        // About one visibility sample every 4 pixels
+        return project(
+            edges,
+            sampleSpacing: 4,
+            projection: { camera.project(worldPosition: $0, viewSize: viewSize) },
+            isVisible: accelerator.map { accelerator in { Self.isVisible($0, from: eye, accelerator: accelerator) } }
+        )
+    }
+
+    /// Project edges with an arbitrary projection, dropping hidden parts
+    ///
+    /// Each edge is sampled along its length; samples with other geometry between them and the eye are hidden,
+    /// so partly covered edges are split into their visible runs.
+    /// - Parameters:
+    ///   - edges: Edges to project
+    ///   - sampleSpacing: Distance between visibility samples in projected units
+    ///   - projection: Maps a 3D point to 2D (nil = outside the view; edges with an endpoint outside are dropped)
+    ///   - isVisible: Visibility test for points on an edge (nil draws every edge completely)
+    /// - Returns: Line segments in projected coordinates
+    static func project(
+        _ edges: [Edge],
+        sampleSpacing: Double,
+        projection: (Vector3) -> CGPoint?,
+        isVisible: ((Vector3) -> Bool)?
+    ) -> [(CGPoint, CGPoint)] {
+        var segments: [(CGPoint, CGPoint)] = []
+
+        for edge in edges {
+            guard let start = projection(edge.start), let end = projection(edge.end) else {
+                continue
+            }
+
+            guard let isVisible else {
+                segments.append((start, end))
+                continue
+            }
+
+            // Bounded to keep large meshes fast
+            let projectedLength = hypot(end.x - start.x, end.y - start.y)
+            let sampleCount = min(max(Int(projectedLength / sampleSpacing), 2), 64)
+            var runStart: Double?
+            var lastVisible = 0.0
+
+            let appendRun = { (t0: Double, t1: Double) in
+                // Project the run ends in 3D, as perspective is not linear along the edge
+                guard t1 > t0,
+                      let from = projection(edge.start + (edge.end - edge.start) * t0),
+                      let to = projection(edge.start + (edge.end - edge.start) * t1) else {
+                    return
+                }
+                segments.append((from, to))
+            }
+
+            for index in 0...sampleCount {
+                let t = Double(index) / Double(sampleCount)
+                if isVisible(edge.start + (edge.end - edge.start) * t) {
+                    if runStart == nil {
+                        runStart = t
+                    }
+                    lastVisible = t
+                } else if let visibleStart = runStart {
+                    appendRun(visibleStart, lastVisible)
+                    runStart = nil
+                }
+            }
+            if let visibleStart = runStart {
+                appendRun(visibleStart, lastVisible)
+            }
+        }
+        return segments
+    }
+
+    /// Whether nothing lies between the eye and a point on the surface
+    static func isVisible(_ point: Vector3, from eye: Vector3, accelerator: SpatialAccelerator) -> Bool {
+        let offset = point - eye
+        let distance = offset.length
+        guard distance > 0 else { return true }
+        return isUnobstructed(point, ray: Ray(origin: eye.float3, direction: (offset / distance).float3), distance: distance, accelerator: accelerator)
+    }
+
+    /// Whether nothing lies in front of a point on the surface when looking along a direction (orthographic view)
+    /// - Parameter depth: Distance in front of the point to start the ray (beyond the model's extent)
+    static func isVisible(_ point: Vector3, along direction: Vector3, depth: Double, accelerator: SpatialAccelerator) -> Bool {
+        let origin = point - direction * depth
+        return isUnobstructed(point, ray: Ray(origin: origin.float3, direction: direction.float3), distance: depth, accelerator: accelerator)
+    }
+
+    private static func isUnobstructed(_ point: Vector3, ray: Ray, distance: Double, accelerator: SpatialAccelerator) -> Bool {
+        guard let hit = accelerator.raycast(ray: ray) else { return true }
+
+        // Points on the surface hit themselves; allow for float precision at grazing angles
+        let tolerance = max(distance * 1e-3, 1e-4)
+        return Double(hit.distance) >= distance - tolerance
+    }
+}
@@ -509,8 +509,117 @@ final class SpatialAccelerator: @unchecked Sendable {
         return closestVertex
     }
 
+    /// Find all vertices within a given radius, sorted by distance (closest first)
+    /// Used to offer a list of snap candidates for keyboard-driven picking
+    func findVertices(near point: Vector3, maxDistance: Double, limit: Int) -> [Vector3] {
+        if vertexGrid == nil {
+            buildVertexGrid()
+        }
+
+        let cellRadius = Int(ceil(maxDistance / gridCellSize))
+
+        let centerCell = (
+            x: Int((point.x - gridOrigin.x) / gridCellSize),
+            y: Int((point.y - gridOrigin.y) / gridCellSize),
+            z: Int((point.z - gridOrigin.z) / gridCellSize)
+        )
+
+        var found: [(vertex: Vector3, distance: Double)] = []
+
+        for dx in -cellRadius...cellRadius {
+            for dy in -cellRadius...cellRadius {
+                for dz in -cellRadius...cellRadius {
+                    let cx = centerCell.x + dx
+                    let cy = centerCell.y + dy
+                    let cz = centerCell.z + dz
+
+                    guard cx >= 0 && cx < gridDimensions.x &&
+                          cy >= 0 && cy < gridDimensions.y &&
+                          cz >= 0 && cz < gridDimensions.z else {
+                        continue
+                    }
+
+                    let key = cx + cy * gridDimensions.x + cz * gridDimensions.x * gridDimensions.y
+                    guard let cell = vertexGrid?[key] else { continue }
+
+                    for (vertex, _) in cell.vertices {
+                        let dist = vertex.distance(to: point)
+                        if dist <= maxDistance {
+                            found.append((vertex, dist))
+                        }
+                    }
+                }
+            }
+        }
+
+        return found
+            .sorted { $0.distance < $1.distance }
+            .prefix(limit)
+            .map { $0.vertex }
+    }
+
     // MARK: - Triangle Lookup
 
+    /// Indices of the triangles whose bounds come within a given distance of a point
+    /// Used to gather the local surface around a picked point
+    func findTriangles(near point: Vector3, maxDistance: Double) -> [Int] {
+        guard let root = bvhRoot else { return [] }
+        var found: [Int] = []
+        collectTriangles(node: root, point: point, maxDistance: maxDistance, found: &found)
+        return found
+    }
+
+    private func collectTriangles(node: BVHNode, point: Vector3, maxDistance: Double, found: inout [Int]) {
+        guard boxDistance(node.bounds, to: point) <= maxDistance else { return }
+
+        if let indices = node.triangleIndices {
+            found += indices.filter { boxDistance(triangleBoundsCache[$0], to: point) <= maxDistance }
+            return
+        }
+        if let left = node.left {
+            collectTriangles(node: left, point: point, maxDistance: maxDistance, found: &found)
+        }
+        if let right = node.right {
+            collectTriangles(node: right, point: point, maxDistance: maxDistance, found: &found)
+        }
+    }
+
+    /// Indices of the triangles whose bounds come within a given distance of a box
+    /// Used to find triangle pairs of two meshes for clearance checks
+    func findTriangles(near box: BoundingBox, maxDistance: Double) -> [Int] {
+        guard let root = bvhRoot else { return [] }
+        var found: [Int] = []
+        collectTriangles(node: root, box: box, maxDistance: maxDistance, found: &found)
+        return found
+    }
+
+    private func collectTriangles(node: BVHNode, box: BoundingBox, maxDistance: Double, found: inout [Int]) {
+        guard Self.boxDistance(node.bounds, box) <= maxDistance else { return }
+
+        if let indices = node.triangleIndices {
+            found += indices.filter { Self.boxDistance(triangleBoundsCache[$0], box) <= maxDistance }
+            return
+        }
+        if let left = node.left {
+            collectTriangles(node: left, box: box, maxDistance: maxDistance, found: &found)
+        }
+        if let right = node.right {
+            collectTriangles(node: right, box: box, maxDistance: maxDistance, found: &found)
+        }
+    }
+
+    /// Gap between two boxes (0 if they overlap)
+    static func boxDistance(_ a: BoundingBox, _ b: BoundingBox) -> Double {
+        let gap = (a.min - b.max).max(b.min - a.max).max(.zero)
+        return gap.length
+    }
+
+    /// Distance from a point to a box (0 inside)
+    private func boxDistance(_ box: BoundingBox, to point: Vector3) -> Double {
+        let closest = point.max(box.min).min(box.max)
+        return closest.distance(to: point)
+    }
+
     /// Find which triangle a ray intersects (returns index only)
     func findTriangleAtRay(ray: Ray) -> Int? {
         raycast(ray: ray)?.triangleIndex
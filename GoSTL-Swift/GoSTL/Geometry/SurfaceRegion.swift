@@ -0,0 +1,121 @@
+import Foundation
+
+/// Connected patch of a mesh grown from one triangle: the flat face or the smooth region it belongs to
+///
+/// Used to highlight the surface under the cursor, so it is obvious which face a plane fit or a
+/// measurement will reference.
+struct SurfaceRegion: Equatable {
+    enum Kind: String, CaseIterable, Identifiable {
+        /// Connected triangles in the plane of the seed (within `ModelAlignment`'s tolerances)
+        case flatFace
+        /// Connected triangles whose neighbors meet at less than `smoothAngle`
+        case smoothRegion
+
+        var id: String { rawValue }
+
+        var title: String {
+            switch self {
+            case .flatFace: return "Flat Face"
+            case .smoothRegion: return "Smooth Region"
+            }
+        }
+    }
+
+    /// Neighboring triangles meeting at less than this angle (degrees) belong to one smooth region
+    static let smoothAngle = 20.0
+
+    let kind: Kind
+    /// Indices of the triangles, sorted
+    let triangles: [Int]
+    /// Total area (mm²)
+    let area: Double
+    /// Area-weighted average normal
+    let normal: Vector3
+
+    private let members: Set<Int>
+
+    func contains(_ triangle: Int) -> Bool {
+        members.contains(triangle)
+    }
+
+    /// Grow the region of a kind from a triangle across shared edges
+    /// - Returns: nil for an invalid index or a degenerate seed triangle
+    static func grow(from seed: Int, kind: Kind, in triangles: [Triangle], adjacency: TriangleAdjacency) -> SurfaceRegion? {
+        guard triangles.indices.contains(seed) else { return nil }
+        let seedNormal = faceNormal(triangles[seed])
+        guard seedNormal.length > 0.5 else { return nil }
+
+        let flatCos = cos(ModelAlignment.angleTolerance * .pi / 180)
+        let smoothCos = cos(smoothAngle * .pi / 180)
+        let seedOffset = seedNormal.dot(triangles[seed].v1)
+
+        func belongs(_ index: Int, from current: Int) -> Bool {
+            let triangle = triangles[index]
+            let normal = faceNormal(triangle)
+            guard normal.length > 0.5 else { return false }
+            switch kind {
+            case .flatFace:
+                guard normal.dot(seedNormal) >= flatCos else { return false }
+                return [triangle.v1, triangle.v2, triangle.v3].allSatisfy {
+                    abs(seedNormal.dot($0) - seedOffset) <= ModelAlignment.planeTolerance
+                }
+            case .smoothRegion:
+                return normal.dot(faceNormal(triangles[current])) >= smoothCos
+            }
+        }
+
+        var members: Set<Int> = [seed]
+        var queue = [seed]
+        while let current = queue.popLast() {
+            for neighbor in adjacency.neighbors[current] where !members.contains(neighbor) && belongs(neighbor, from: current) {
+                members.insert(neighbor)
+                queue.append(neighbor)
+            }
+        }
+
+        var area = 0.0
+        var normalSum = Vector3.zero
+        for index in members {
+            let triangleArea = triangles[index].area()
+            area += triangleArea
+            normalSum = normalSum + faceNormal(triangles[index]) * triangleArea
+        }
+
+        return SurfaceRegion(
+            kind: kind,
+            triangles: members.sorted(),
+            area: area,
+            normal: normalSum.length > 0 ? normalSum.normalized() : seedNormal,
+            members: members
+        )
+    }
+
+    /// Normal from the winding (the stored normal of a file may be missing or wrong)
+    private static func faceNormal(_ triangle: Triangle) -> Vector3 {
+        Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3)
+    }
+}
+
+/// Triangles sharing an edge with each triangle of a mesh
+///
+/// Only manifold edges connect; open and non-manifold edges end a region.
+struct TriangleAdjacency {
+    let neighbors: [[Int]]
+
+    init(triangles: [Triangle]) {
+        var edgeTriangles: [Edge: [Int]] = [:]
+        edgeTriangles.reserveCapacity(triangles.count * 3 / 2)
+        for (index, triangle) in triangles.enumerated() {
+            for edge in [Edge(triangle.v1, triangle.v2), Edge(triangle.v2, triangle.v3), Edge(triangle.v3, triangle.v1)] {
+                edgeTriangles[edge, default: []].append(index)
+            }
+        }
+
+        var neighbors = [[Int]](repeating: [], count: triangles.count)
+        for pair in edgeTriangles.values where pair.count == 2 {
+            neighbors[pair[0]].append(pair[1])
+            neighbors[pair[1]].append(pair[0])
+        }
+        self.neighbors = neighbors
+    }
+}
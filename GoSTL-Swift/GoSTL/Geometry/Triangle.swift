@@ -29,15 +29,18 @@ struct Triangle {
     var v3: Vector3
     var normal: Vector3
     var color: TriangleColor?
+    /// Attribute bytes of a binary STL facet (0 for other formats), written back on export
+    var attribute: UInt16 = 0
 
     // MARK: - Initializers
 
-    init(v1: Vector3, v2: Vector3, v3: Vector3, normal: Vector3? = nil, color: TriangleColor? = nil) {
+    init(v1: Vector3, v2: Vector3, v3: Vector3, normal: Vector3? = nil, color: TriangleColor? = nil, attribute: UInt16 = 0) {
         self.v1 = v1
         self.v2 = v2
         self.v3 = v3
         self.normal = normal ?? Self.calculateNormal(v1: v1, v2: v2, v3: v3)
         self.color = color
+        self.attribute = attribute
     }
 
     // MARK: - Computed Properties
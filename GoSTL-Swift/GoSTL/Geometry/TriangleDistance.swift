@@ -0,0 +1,162 @@
+import Foundation
+
+/// Closest points between triangles (for clearance checks between two meshes)
+///
+/// Follows Ericson, "Real-Time Collision Detection": two triangles that don't intersect are closest
+/// either between a vertex and the other triangle or between two edges.
+enum TriangleDistance {
+    /// Closest points between two triangles
+    struct Result {
+        let pointA: Vector3
+        let pointB: Vector3
+        let distance: Double
+        /// An edge of one triangle passes through the other (touching faces are not intersecting)
+        let intersects: Bool
+    }
+
+    /// Parameters closer than this to a segment end or triangle border count as touching, not piercing
+    private static let endTolerance = 1e-9
+
+    static func closestPoints(_ a: Triangle, _ b: Triangle) -> Result {
+        // Interpenetration: an edge of one triangle pierces the other
+        for (p, q) in edges(of: a) {
+            if let point = segmentIntersection(from: p, to: q, triangle: b) {
+                return Result(pointA: point, pointB: point, distance: 0, intersects: true)
+            }
+        }
+        for (p, q) in edges(of: b) {
+            if let point = segmentIntersection(from: p, to: q, triangle: a) {
+                return Result(pointA: point, pointB: point, distance: 0, intersects: true)
+            }
+        }
+
+        var best = Result(pointA: a.v1, pointB: b.v1, distance: .infinity, intersects: false)
+        func consider(_ pointA: Vector3, _ pointB: Vector3) {
+            let distance = pointA.distance(to: pointB)
+            if distance < best.distance {
+                best = Result(pointA: pointA, pointB: pointB, distance: distance, intersects: false)
+            }
+        }
+
+        for vertex in [a.v1, a.v2, a.v3] {
+            consider(vertex, closestPoint(on: b, to: vertex))
+        }
+        for vertex in [b.v1, b.v2, b.v3] {
+            consider(closestPoint(on: a, to: vertex), vertex)
+        }
+        for (p1, q1) in edges(of: a) {
+            for (p2, q2) in edges(of: b) {
+                let closest = closestPoints(segment: (p1, q1), segment: (p2, q2))
+                consider(closest.0, closest.1)
+            }
+        }
+
+        return best
+    }
+
+    private static func edges(of triangle: Triangle) -> [(Vector3, Vector3)] {
+        [(triangle.v1, triangle.v2), (triangle.v2, triangle.v3), (triangle.v3, triangle.v1)]
+    }
+
+    /// Closest point on a triangle to a point
+    static func closestPoint(on triangle: Triangle, to point: Vector3) -> Vector3 {
+        let a = triangle.v1, b = triangle.v2, c = triangle.v3
+        let ab = b - a, ac = c - a, ap = point - a
+
+        let d1 = ab.dot(ap), d2 = ac.dot(ap)
+        if d1 <= 0 && d2 <= 0 { return a }
+
+        let bp = point - b
+        let d3 = ab.dot(bp), d4 = ac.dot(bp)
+        if d3 >= 0 && d4 <= d3 { return b }
+
+        let vc = d1 * d4 - d3 * d2
+        if vc <= 0 && d1 >= 0 && d3 <= 0 {
+            return a + ab * (d1 / (d1 - d3))
+        }
+
+        let cp = point - c
+        let d5 = ab.dot(cp), d6 = ac.dot(cp)
+        if d6 >= 0 && d5 <= d6 { return c }
+
+        let vb = d5 * d2 - d1 * d6
+        if vb <= 0 && d2 >= 0 && d6 <= 0 {
+            return a + ac * (d2 / (d2 - d6))
+        }
+
+        let va = d3 * d6 - d5 * d4
+        if va <= 0 && (d4 - d3) >= 0 && (d5 - d6) >= 0 {
+            return b + (c - b) * ((d4 - d3) / ((d4 - d3) + (d5 - d6)))
+        }
+
+        // Inside the face
+        let denominator = va + vb + vc
+        guard denominator != 0 else { return a }  // Degenerate triangle
+        return a + ab * (vb / denominator) + ac * (vc / denominator)
+    }
+
+    /// Closest points between two segments
+    static func closestPoints(segment first: (Vector3, Vector3), segment second: (Vector3, Vector3)) -> (Vector3, Vector3) {
+        let d1 = first.1 - first.0, d2 = second.1 - second.0
+        let r = first.0 - second.0
+        let a = d1.lengthSquared, e = d2.lengthSquared, f = d2.dot(r)
+        let epsilon = 1e-18
+
+        var s: Double, t: Double
+        if a <= epsilon && e <= epsilon {
+            return (first.0, second.0)
+        }
+        if a <= epsilon {
+            s = 0
+            t = min(max(f / e, 0), 1)
+        } else {
+            let c = d1.dot(r)
+            if e <= epsilon {
+                t = 0
+                s = min(max(-c / a, 0), 1)
+            } else {
+                let b = d1.dot(d2)
+                let denominator = a * e - b * b
+                s = denominator > epsilon ? min(max((b * f - c * e) / denominator, 0), 1) : 0
+                t = (b * s + f) / e
+                if t < 0 {
+                    t = 0
+                    s = min(max(-c / a, 0), 1)
+                } else if t > 1 {
+                    t = 1
+                    s = min(max((b - c) / a, 0), 1)
+                }
+            }
+        }
+
+        return (first.0 + d1 * s, second.0 + d2 * t)
+    }
+
+    /// Point where a segment passes through a triangle's interior, nil if it misses, runs parallel or
+    /// only touches the triangle with an end point or at its border
+    static func segmentIntersection(from start: Vector3, to end: Vector3, triangle: Triangle) -> Vector3? {
+        let direction = end - start
+        let edge1 = triangle.v2 - triangle.v1
+        let edge2 = triangle.v3 - triangle.v1
+        let h = direction.cross(edge2)
+        let determinant = edge1.dot(h)
+
+        // Parallel (coplanar contact is found by the edge distances)
+        guard abs(determinant) > 1e-12 * direction.length * edge1.length * edge2.length else { return nil }
+
+        let inverse = 1 / determinant
+        let s = start - triangle.v1
+        // Hits on the triangle's border are contact (e.g. a face resting on another), not piercing
+        let u = inverse * s.dot(h)
+        guard u > endTolerance && u < 1 - endTolerance else { return nil }
+
+        let q = s.cross(edge1)
+        let v = inverse * direction.dot(q)
+        guard v > endTolerance && u + v < 1 - endTolerance else { return nil }
+
+        let t = inverse * edge2.dot(q)
+        guard t > endTolerance && t < 1 - endTolerance else { return nil }
+
+        return start + direction * t
+    }
+}
@@ -0,0 +1,239 @@
+import Foundation
+
+/// Events that can trigger automation hooks
+enum HookEvent: String, Codable, CaseIterable {
+    case modelLoaded
+    case modelReloaded
+    case measurementAdded
+    case analysisCompleted
+}
+
+/// A configured hook: a shell command or an HTTP webhook fired for an event
+///
+/// Shell commands run through `/bin/sh -c` with the JSON payload on stdin and the event name
+/// in `GOSTL_EVENT`. Webhooks receive the payload as an HTTP POST with a JSON body.
+struct Hook: Codable, Equatable {
+    /// Event name, or "*" for all events
+    var event: String
+    var command: String?
+    var url: URL?
+    /// Extra HTTP headers for webhooks (e.g. authorization)
+    var headers: [String: String]?
+
+    func matches(_ event: HookEvent) -> Bool {
+        self.event == "*" || self.event == event.rawValue
+    }
+}
+
+/// Fires configured hooks with a JSON payload
+///
+/// Hooks are read from `~/.config/gostl/hooks.json`:
+/// ```json
+/// {
+///   "hooks": [
+///     { "event": "measurementAdded", "command": "cat >> ~/measurements.jsonl" },
+///     { "event": "*", "url": "https://qa.example.com/gostl", "headers": { "Authorization": "Bearer ..." } }
+///   ]
+/// }
+/// ```
+/// The file is read once and again whenever it changes, so hooks can be edited while the app is running.
+final class HookManager: @unchecked Sendable {
+    static let shared = HookManager(configFile: FileManager.default.homeDirectoryForCurrentUser
+        .appendingPathComponent(".config/gostl/hooks.json"))
+
+    let configFile: URL
+
+    private struct HookConfig: Codable {
+        var hooks: [Hook]
+    }
+
+    private let lock = NSLock()
+    private var cachedHooks: [Hook] = []
+    private var isLoaded = false
+    private var fileWatcher: FileWatcher?
+    private var directorySource: DispatchSourceFileSystemObject?
+
+    init(configFile: URL) {
+        self.configFile = configFile
+    }
+
+    deinit {
+        directorySource?.cancel()
+    }
+
+    /// Currently configured hooks (empty if the file is missing or invalid)
+    var hooks: [Hook] {
+        lock.lock()
+        defer { lock.unlock() }
+
+        if !isLoaded {
+            load()
+        }
+        return cachedHooks
+    }
+
+    /// Read the file again on next use (done automatically when it changes)
+    func reload() {
+        lock.lock()
+        defer { lock.unlock() }
+        isLoaded = false
+    }
+
+    /// Read the config file and watch it for changes (called with the lock held)
+    private func load() {
+        isLoaded = true
+        cachedHooks = []
+        if FileManager.default.fileExists(atPath: configFile.path) {
+            do {
+                let data = try Data(contentsOf: configFile)
+                cachedHooks = try JSONDecoder().decode(HookConfig.self, from: data).hooks
+            } catch {
+                print("ERROR: Failed to load hooks from \(configFile.path): \(error)")
+            }
+        }
+        watchConfig()
+    }
+
+    /// Watch the file for edits and its directory for the file being created, deleted or replaced
+    private func watchConfig() {
+        fileWatcher = nil
+        if FileManager.default.fileExists(atPath: configFile.path) {
+            let watcher = FileWatcher()
+            try? watcher.watch(files: [configFile]) { [weak self] _ in
+                self?.reload()
+            }
+            fileWatcher = watcher
+        }
+
+        guard directorySource == nil else { return }
+        let fd = open(configFile.deletingLastPathComponent().path, O_EVTONLY)
+        guard fd >= 0 else { return }
+        let source = DispatchSource.makeFileSystemObjectSource(fileDescriptor: fd, eventMask: [.write, .delete, .rename], queue: .global(qos: .utility))
+        source.setEventHandler { [weak self] in
+            self?.reload()
+        }
+        source.setCancelHandler {
+            close(fd)
+        }
+        source.resume()
+        directorySource = source
+    }
+
+    /// Build the JSON payload sent to hooks (values JSON cannot hold are cleaned, see `jsonValue(_:)`)
+    static func payload(event: HookEvent, file: URL?, data: [String: Any], date: Date = Date()) throws -> Data {
+        var object: [String: Any] = [
+            "event": event.rawValue,
+            "timestamp": ISO8601DateFormatter().string(from: date),
+            "data": jsonValue(data)
+        ]
+        if let file {
+            object["file"] = file.path
+        }
+        // JSONSerialization raises an exception instead of throwing for invalid objects
+        guard JSONSerialization.isValidJSONObject(object) else {
+            throw CocoaError(.propertyListWriteInvalid)
+        }
+        return try JSONSerialization.data(withJSONObject: object, options: [.sortedKeys])
+    }
+
+    /// A value JSON can hold: NaN and infinite numbers become null, dates ISO 8601 strings, URLs
+    /// paths and anything else its description
+    static func jsonValue(_ value: Any) -> Any {
+        switch value {
+        case let dictionary as [String: Any]:
+            return dictionary.mapValues(jsonValue)
+        case let array as [Any]:
+            return array.map(jsonValue)
+        case is String, is Bool, is NSNull:
+            return value
+        case let number as NSNumber:
+            return number.doubleValue.isFinite ? number : NSNull()
+        case let date as Date:
+            return ISO8601DateFormatter().string(from: date)
+        case let url as URL:
+            return url.isFileURL ? url.path : url.absoluteString
+        default:
+            return String(describing: value)
+        }
+    }
+
+    /// Fire all hooks matching the event in the background
+    func fire(_ event: HookEvent, file: URL?, data: [String: Any] = [:]) {
+        let matching = hooks.filter { $0.matches(event) }
+        guard !matching.isEmpty else { return }
+
+        let payload: Data
+        do {
+            payload = try Self.payload(event: event, file: file, data: data)
+        } catch {
+            print("ERROR: Failed to encode \(event.rawValue) hook payload: \(error)")
+            return
+        }
+
+        for hook in matching {
+            if let command = hook.command {
+                DispatchQueue.global(qos: .utility).async {
+                    Self.runCommand(command, event: event, payload: payload)
+                }
+            }
+            if let url = hook.url {
+                Self.post(payload, to: url, headers: hook.headers ?? [:], event: event)
+            }
+        }
+    }
+
+    // MARK: - Delivery
+
+    private static func runCommand(_ command: String, event: HookEvent, payload: Data) {
+        let process = Process()
+        process.executableURL = URL(fileURLWithPath: "/bin/sh")
+        process.arguments = ["-c", command]
+
+        var environment = ProcessInfo.processInfo.environment
+        environment["GOSTL_EVENT"] = event.rawValue
+        process.environment = environment
+
+        let stdinPipe = Pipe()
+        let input = stdinPipe.fileHandleForWriting
+        // A command that exits without reading the payload must fail the write, not raise SIGPIPE
+        _ = fcntl(input.fileDescriptor, F_SETNOSIGPIPE, 1)
+        process.standardInput = stdinPipe
+        process.standardOutput = FileHandle.nullDevice
+        process.standardError = FileHandle.nullDevice
+
+        do {
+            try process.run()
+            do {
+                try input.write(contentsOf: payload)
+            } catch {
+                print("Hook command for \(event.rawValue) did not read its payload: \(error)")
+            }
+            try? input.close()
+            process.waitUntilExit()
+            if process.terminationStatus != 0 {
+                print("Hook command for \(event.rawValue) exited with status \(process.terminationStatus): \(command)")
+            }
+        } catch {
+            print("ERROR: Failed to run hook command for \(event.rawValue): \(error)")
+        }
+    }
+
+    private static func post(_ payload: Data, to url: URL, headers: [String: String], event: HookEvent) {
+        var request = URLRequest(url: url)
+        request.httpMethod = "POST"
+        request.httpBody = payload
+        request.setValue("application/json", forHTTPHeaderField: "Content-Type")
+        request.setValue(event.rawValue, forHTTPHeaderField: "X-GoSTL-Event")
+        for (name, value) in headers {
+            request.setValue(value, forHTTPHeaderField: name)
+        }
+
+        URLSession.shared.dataTask(with: request) { _, response, error in
+            if let error {
+                print("ERROR: Webhook for \(event.rawValue) failed: \(error.localizedDescription)")
+            } else if let http = response as? HTTPURLResponse, !(200..<300).contains(http.statusCode) {
+                print("Webhook for \(event.rawValue) returned HTTP \(http.statusCode)")
+            }
+        }.resume()
+    }
+}
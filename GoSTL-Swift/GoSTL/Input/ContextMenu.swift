@@ -0,0 +1,200 @@
+import AppKit
+
+/// What a right-click landed on
+enum ContextMenuTarget: Equatable {
+    /// A measurement label
+    case measurement(Int)
+    /// An endpoint of a measurement (or of the measurement in progress, index nil)
+    case point(MeasurementPoint, measurement: Int?)
+    /// The model surface
+    case surface(MeasurementPoint)
+    /// A pinned annotation (label or pin), with its anchor in render space
+    case annotation(UUID, position: Vector3)
+}
+
+/// Action offered in the right-click context menu
+enum ContextMenuAction: Equatable {
+    case deleteMeasurement(Int)
+    case copyValue(Int)
+    case editNote(Int)
+    case moreDecimals(Int)
+    case fewerDecimals(Int)
+    case resetDecimals(Int)
+    case toggleSelection(Int)
+    case copyCoordinates(Vector3)
+    case startMeasurement(MeasurementType, at: MeasurementPoint)
+    case constrainToAxis(Int)
+    case constrainAlong(Vector3)
+    case constrainToward(Vector3)
+    case setOrbitTarget(Vector3)
+    case addAnnotation(at: MeasurementPoint)
+    case editAnnotation(UUID)
+    case deleteAnnotation(UUID)
+
+    var title: String {
+        switch self {
+        case .deleteMeasurement: return "Delete Measurement"
+        case .copyValue: return "Copy Value"
+        case .editNote: return "Add Note…"
+        case .moreDecimals: return "Show More Decimals"
+        case .fewerDecimals: return "Show Fewer Decimals"
+        case .resetDecimals: return "Default Decimals"
+        case .toggleSelection: return "Select"
+        case .copyCoordinates: return "Copy Coordinates"
+        case .startMeasurement(let type, _):
+            switch type {
+            case .distance: return "Start Distance Here"
+            case .angle: return "Start Angle Here"
+            case .radius: return "Start Radius Here"
+            case .triangleSelect: return "Select Triangles"
+            }
+        case .constrainToAxis(let axis): return "Constrain to \(["X", "Y", "Z"][axis]) Axis"
+        case .constrainAlong: return "Constrain Along Surface Normal"
+        case .constrainToward: return "Constrain Toward Point"
+        case .setOrbitTarget: return "Set Orbit Target"
+        case .addAnnotation: return "Add Annotation…"
+        case .editAnnotation: return "Edit Annotation…"
+        case .deleteAnnotation: return "Delete Annotation"
+        }
+    }
+}
+
+/// Builds the right-click context menu for measurements, points and the model surface
+enum ContextMenu {
+    /// Actions for a target, in groups separated by dividers
+    static func actions(for target: ContextMenuTarget, measurementSystem: MeasurementSystem) -> [[ContextMenuAction]] {
+        // Constraints apply to the second point of a distance measurement in progress
+        let canConstrain = measurementSystem.mode == .distance && !measurementSystem.currentPoints.isEmpty
+
+        switch target {
+        case .measurement(let index):
+            guard index < measurementSystem.measurements.count else { return [] }
+            let measurement = measurementSystem.measurements[index]
+            var decimals: [ContextMenuAction] = [.moreDecimals(index), .fewerDecimals(index)]
+            if measurement.decimals != nil {
+                decimals.append(.resetDecimals(index))
+            }
+            return [
+                [.copyValue(index), .editNote(index), .toggleSelection(index)],
+                [.setOrbitTarget(measurement.labelPosition)],
+                decimals,
+                [.deleteMeasurement(index)]
+            ]
+
+        case .point(let point, let measurement):
+            var groups: [[ContextMenuAction]] = [[.copyCoordinates(point.position), .addAnnotation(at: point)]]
+            if canConstrain {
+                groups.append([.constrainToward(point.position)])
+            } else {
+                groups.append([.startMeasurement(.distance, at: point), .startMeasurement(.radius, at: point)])
+            }
+            groups.append([.setOrbitTarget(point.position)])
+            if let measurement {
+                groups.append([.deleteMeasurement(measurement)])
+            }
+            return groups
+
+        case .surface(let point):
+            var groups: [[ContextMenuAction]] = [[.copyCoordinates(point.position), .addAnnotation(at: point)]]
+            if canConstrain {
+                groups.append([.constrainToAxis(0), .constrainToAxis(1), .constrainToAxis(2)])
+                if point.normal != .zero {
+                    groups[groups.count - 1].append(.constrainAlong(point.normal))
+                }
+            } else {
+                groups.append([
+                    .startMeasurement(.distance, at: point),
+                    .startMeasurement(.angle, at: point),
+                    .startMeasurement(.radius, at: point)
+                ])
+            }
+            groups.append([.setOrbitTarget(point.position)])
+            return groups
+
+        case .annotation(let id, let position):
+            return [
+                [.editAnnotation(id), .copyCoordinates(position)],
+                [.setOrbitTarget(position)],
+                [.deleteAnnotation(id)]
+            ]
+        }
+    }
+
+    /// AppKit menu for the action groups
+    /// - Parameter isChecked: Whether an action is currently in effect (shown with a check mark)
+    @MainActor
+    static func makeMenu(
+        _ groups: [[ContextMenuAction]],
+        isChecked: (ContextMenuAction) -> Bool = { _ in false },
+        perform: @escaping @MainActor (ContextMenuAction) -> Void
+    ) -> NSMenu {
+        let menu = NSMenu()
+        for (index, group) in groups.enumerated() {
+            if index > 0 {
+                menu.addItem(.separator())
+            }
+            for action in group {
+                let item = ActionMenuItem(title: action.title) { perform(action) }
+                item.state = isChecked(action) ? .on : .off
+                menu.addItem(item)
+            }
+        }
+        return menu
+    }
+
+    /// Ask for the note of a measurement
+    /// - Returns: The entered note, or nil if cancelled
+    @MainActor
+    static func promptForNote(_ note: String?) -> String? {
+        let alert = NSAlert()
+        alert.messageText = "Measurement Note"
+        alert.informativeText = "Shown in the tooltip of the measurement label. Leave empty to remove the note."
+        alert.addButton(withTitle: "OK")
+        alert.addButton(withTitle: "Cancel")
+
+        let field = NSTextField(frame: NSRect(x: 0, y: 0, width: 260, height: 24))
+        field.stringValue = note ?? ""
+        alert.accessoryView = field
+        alert.window.initialFirstResponder = field
+
+        return alert.runModal() == .alertFirstButtonReturn ? field.stringValue : nil
+    }
+
+    /// Ask for the text of an annotation
+    /// - Returns: The entered text, or nil if cancelled
+    @MainActor
+    static func promptForAnnotation(_ text: String?) -> String? {
+        let alert = NSAlert()
+        alert.messageText = text == nil ? "Add Annotation" : "Edit Annotation"
+        alert.informativeText = "Pinned to the point with a leader line, e.g. \"crack here\"."
+            + (text == nil ? "" : " Leave empty to remove the annotation.")
+        alert.addButton(withTitle: "OK")
+        alert.addButton(withTitle: "Cancel")
+
+        let field = NSTextField(frame: NSRect(x: 0, y: 0, width: 260, height: 24))
+        field.stringValue = text ?? ""
+        alert.accessoryView = field
+        alert.window.initialFirstResponder = field
+
+        return alert.runModal() == .alertFirstButtonReturn ? field.stringValue : nil
+    }
+}
+
+/// Menu item that runs a closure
+private final class ActionMenuItem: NSMenuItem {
+    private let handler: @MainActor () -> Void
+
+    init(title: String, handler: @escaping @MainActor () -> Void) {
+        self.handler = handler
+        super.init(title: title, action: #selector(run), keyEquivalent: "")
+        target = self
+    }
+
+    required init(coder: NSCoder) {
+        fatalError("init(coder:) has not been implemented")
+    }
+
+    @objc private func run() {
+        handler()
+    }
+}
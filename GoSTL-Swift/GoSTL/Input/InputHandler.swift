@@ -1,12 +1,17 @@
 import AppKit
 import SwiftUI
 import Metal
+import simd
 
 /// Handles mouse and keyboard input for camera control
 final class InputHandler {
     private var lastMousePosition: CGPoint?
     private var isRotating = false
     private var isPanning = false
+    private var isZooming = false
+    private var lastDragTime: CFAbsoluteTime = 0
+    private var rotationVelocity: SIMD2<Double> = .zero  // Smoothed drag velocity for inertia
+    private var unsnappedAngles: SIMD2<Double>?  // Free angles followed while Control snaps the rotation
     private var isSelecting = false  // Track selection rectangle mode
     private var isSelectingTriangles = false  // Track triangle selection rectangle mode
     private var optionWasPressed = false  // Track Option key state for constraint release
@@ -17,6 +22,7 @@ final class InputHandler {
 
     func handleMouseDown(at location: CGPoint, modifierFlags: NSEvent.ModifierFlags, appState: AppState, viewSize: CGSize? = nil, camera: Camera? = nil) {
         lastMousePosition = location
+        camera?.stopInertia()
 
         // Option+Command starts triangle selection rectangle in triangle mode
         if modifierFlags.contains(.option) && modifierFlags.contains(.command) &&
@@ -58,7 +64,7 @@ final class InputHandler {
             appState.measurementSystem.isPainting = true
             appState.measurementSystem.isPaintingToUnselect = modifierFlags.contains(.shift)
             // Select/unselect triangle under cursor immediately
-            let ray = camera.mouseRay(screenPos: location, viewSize: viewSize)
+            let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
             if let triangleIndex = appState.measurementSystem.findTriangleAtRay(ray: ray, model: model, accelerator: appState.spatialAccelerator) {
                 if appState.measurementSystem.isPaintingToUnselect {
                     appState.measurementSystem.selectedTriangles.remove(triangleIndex)
@@ -70,17 +76,37 @@ final class InputHandler {
         }
 
         // Always allow camera controls (even in measurement mode)
-        // Shift key for panning, otherwise rotate
-        if modifierFlags.contains(.shift) {
-            isPanning = true
-        } else {
-            isRotating = true
-        }
+        startNavigation(for: .left, modifierFlags: modifierFlags)
+    }
+
+    func handleMiddleMouseDown(at location: CGPoint, modifierFlags: NSEvent.ModifierFlags = [], camera: Camera? = nil) {
+        lastMousePosition = location
+        camera?.stopInertia()
+        startNavigation(for: .middle, modifierFlags: modifierFlags)
     }
 
-    func handleMiddleMouseDown(at location: CGPoint) {
+    /// Start navigating without picking or selecting (views that only share the camera, e.g. the comparison pane)
+    func handleNavigationMouseDown(at location: CGPoint, modifierFlags: NSEvent.ModifierFlags, camera: Camera) {
         lastMousePosition = location
-        isPanning = true
+        camera.stopInertia()
+        startNavigation(for: .left, modifierFlags: modifierFlags)
+    }
+
+    /// Begin rotating, panning or zooming according to the selected navigation scheme
+    private func startNavigation(for button: MouseButton, modifierFlags: NSEvent.ModifierFlags) {
+        switch AppSettings.shared.navigationScheme.action(for: button, modifiers: modifierFlags) {
+        case .rotate:
+            isRotating = true
+            rotationVelocity = .zero
+            unsnappedAngles = nil
+            lastDragTime = CFAbsoluteTimeGetCurrent()
+        case .pan:
+            isPanning = true
+        case .zoom:
+            isZooming = true
+        case nil:
+            break
+        }
     }
 
     func handleMouseDragged(to location: CGPoint, camera: Camera, viewSize: CGSize, appState: AppState) {
@@ -106,7 +132,7 @@ final class InputHandler {
         // Handle paint mode dragging - continuously select/unselect triangles
         if appState.measurementSystem.isPainting,
            let model = appState.model {
-            let ray = camera.mouseRay(screenPos: location, viewSize: viewSize)
+            let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
             if let triangleIndex = appState.measurementSystem.findTriangleAtRay(ray: ray, model: model, accelerator: appState.spatialAccelerator) {
                 if appState.measurementSystem.isPaintingToUnselect {
                     appState.measurementSystem.selectedTriangles.remove(triangleIndex)
@@ -126,10 +152,31 @@ final class InputHandler {
         if isRotating {
             // Rotate camera
             let sensitivity = 0.005
-            camera.rotate(
-                deltaX: Double(delta.y) * sensitivity,  // Y movement = pitch
-                deltaY: -Double(delta.x) * sensitivity  // X movement = yaw (inverted)
+            let rotation = SIMD2(
+                Double(delta.y) * sensitivity,  // Y movement = pitch
+                -Double(delta.x) * sensitivity  // X movement = yaw (inverted)
             )
+
+            // Holding Control snaps to 15° steps; the free angles are followed so the snap can move on
+            if NSEvent.modifierFlags.contains(.control) {
+                let free = (unsnappedAngles ?? SIMD2(camera.angleX, camera.angleY)) + rotation
+                unsnappedAngles = free
+                camera.setSnappedAngles(x: free.x, y: free.y)
+                rotationVelocity = .zero
+                lastDragTime = CFAbsoluteTimeGetCurrent()
+                lastMousePosition = location
+                return
+            }
+            unsnappedAngles = nil
+            camera.rotate(deltaX: rotation.x, deltaY: rotation.y)
+
+            // Track a smoothed angular velocity to hand over to inertia on release
+            let now = CFAbsoluteTimeGetCurrent()
+            let dt = now - lastDragTime
+            if dt > 0.001 {
+                rotationVelocity = rotationVelocity * 0.3 + (rotation / dt) * 0.7
+            }
+            lastDragTime = now
         } else if isPanning {
             // Pan camera (inverted so drag direction matches view movement)
             // Scale sensitivity with distance, but ensure minimum responsiveness when zoomed in
@@ -139,6 +186,10 @@ final class InputHandler {
                 -Float(delta.x) * sensitivity,
                 -Float(delta.y) * sensitivity
             ))
+        } else if isZooming {
+            // Drag up to zoom in, scaled with distance like panning
+            let sensitivity = max(camera.distance * 0.005, 0.05)
+            camera.zoom(delta: -Double(delta.y) * sensitivity)
         }
 
         lastMousePosition = location
@@ -169,8 +220,17 @@ final class InputHandler {
             appState.measurementSystem.isPainting = false
         }
 
+        // Let the camera keep spinning if the drag was released while still moving
+        if isRotating, AppSettings.shared.navigationInertia, let camera = camera,
+           CFAbsoluteTimeGetCurrent() - lastDragTime < Self.inertiaReleaseWindow {
+            camera.rotationVelocity = rotationVelocity
+        }
+
         isRotating = false
         isPanning = false
+        isZooming = false
+        rotationVelocity = .zero
+        unsnappedAngles = nil
         lastMousePosition = nil
     }
 
@@ -220,11 +280,35 @@ final class InputHandler {
             }
         }
 
+        // Check for thickness probing (measurements take precedence)
+        if appState.isProbingThickness && !appState.measurementSystem.isCollecting {
+            appState.probeThickness(ray: camera.pickRay(screenPos: location, viewSize: viewSize))
+            return
+        }
+
+        // Check for face distance picking (measurements take precedence)
+        if appState.isMeasuringFaceDistance && !appState.measurementSystem.isCollecting {
+            appState.measureFaceDistance(ray: camera.pickRay(screenPos: location, viewSize: viewSize))
+            return
+        }
+
+        // Check for lay-flat face picking
+        if appState.levelingState.isPickingFace {
+            guard let model = appState.model else { return }
+
+            let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
+            if let index = appState.measurementSystem.findTriangleAtRay(ray: ray, model: model, accelerator: appState.spatialAccelerator),
+               let device = MTLCreateSystemDefaultDevice() {
+                try? appState.layFlat(onFaceOf: index, device: device)
+            }
+            return
+        }
+
         // Check for leveling mode point picking
         if appState.levelingState.isActive && !appState.levelingState.isReadyForAxisSelection {
             guard let model = appState.model else { return }
 
-            let ray = camera.mouseRay(screenPos: location, viewSize: viewSize)
+            let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
             if let position = RayPicking.findIntersection(ray: ray, model: model, accelerator: appState.spatialAccelerator) {
                 let completed = appState.levelingState.addPoint(position)
                 if completed {
@@ -236,6 +320,32 @@ final class InputHandler {
             return
         }
 
+        // Check for batch template feature picking (measurements take precedence)
+        if appState.templateRun != nil && !appState.measurementSystem.isCollecting {
+            guard let model = appState.model else { return }
+
+            let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
+            if let point = appState.measurementSystem.pickPoint(ray: ray, model: model, accelerator: appState.spatialAccelerator) {
+                appState.addTemplatePick(point)
+            }
+            return
+        }
+
+        // Check for reference geometry point picking (measurements take precedence)
+        if appState.referenceGeometry.isCollecting && !appState.measurementSystem.isCollecting {
+            guard let model = appState.model else { return }
+
+            let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
+            if let point = appState.measurementSystem.pickPoint(ray: ray, model: model, accelerator: appState.spatialAccelerator) {
+                if let entity = appState.referenceGeometry.addPoint(point) {
+                    print("Reference: Created \(entity.name)")
+                } else {
+                    print("Reference: Picked point \(point.position)")
+                }
+            }
+            return
+        }
+
         // Then check for measurement clicks
         guard appState.measurementSystem.isCollecting,
               let model = appState.model else {
@@ -244,7 +354,7 @@ final class InputHandler {
 
         // Handle triangle selection mode
         if appState.measurementSystem.mode == .triangleSelect {
-            let ray = camera.mouseRay(screenPos: location, viewSize: viewSize)
+            let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
             if let triangleIndex = appState.measurementSystem.findTriangleAtRay(ray: ray, model: model, accelerator: appState.spatialAccelerator) {
                 appState.measurementSystem.toggleTriangleSelection(triangleIndex)
             }
@@ -268,17 +378,69 @@ final class InputHandler {
         }
 
         // Generate ray from mouse position
-        let ray = camera.mouseRay(screenPos: location, viewSize: viewSize)
+        let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
 
-        // Find intersection with model
-        if let point = appState.measurementSystem.findIntersection(ray: ray, model: model, accelerator: appState.spatialAccelerator) {
+        // Find intersection with model (or the grid when grid snap is active)
+        if let point = appState.measurementSystem.pickPoint(ray: ray, model: model, accelerator: appState.spatialAccelerator) {
             _ = appState.measurementSystem.addPoint(point)
             print("Picked point: \(point.position)")
         }
     }
 
+    /// Handle double-click: pivot the camera around the surface point under the cursor
+    /// - Returns: true if the double-click changed the orbit target
+    @discardableResult
+    func handleDoubleClick(at location: CGPoint, camera: Camera, viewSize: CGSize, appState: AppState) -> Bool {
+        // Double-clicks while picking points belong to the active tool
+        guard !appState.measurementSystem.isCollecting,
+              !appState.levelingState.isActive,
+              !appState.referenceGeometry.isCollecting,
+              let model = appState.model else {
+            return false
+        }
+
+        // Leave the orientation cube and measurement labels to their click handlers
+        let cubeHit = checkOrientationCubeHover(at: location, viewSize: viewSize, appState: appState)
+        guard cubeHit.face == nil, cubeHit.axisLabel == nil,
+              findMeasurementLabelAtLocation(
+                location: location,
+                camera: camera,
+                viewSize: viewSize,
+                measurementSystem: appState.measurementSystem
+              ) == nil else {
+            return false
+        }
+
+        let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
+        guard let point = RayPicking.findIntersection(
+            ray: ray,
+            model: model,
+            accelerator: appState.spatialAccelerator,
+            snapThreshold: 0
+        ) else {
+            return false
+        }
+
+        camera.animateTarget(to: point.float3)
+        print("Orbit target set to \(appState.originalPosition(point))")
+        return true
+    }
+
     /// Handle mouse move for hover detection
     func handleMouseMoved(at location: CGPoint, camera: Camera, viewSize: CGSize, appState: AppState) {
+        appState.cursorLocation = location
+
+        // Measurement label under the mouse shows its details tooltip
+        let hoveredLabel = findMeasurementLabelAtLocation(
+            location: location,
+            camera: camera,
+            viewSize: viewSize,
+            measurementSystem: appState.measurementSystem
+        )
+        if appState.measurementSystem.hoveredLabel != hoveredLabel {
+            appState.measurementSystem.hoveredLabel = hoveredLabel
+        }
+
         // Check if mouse is over orientation cube first
         let cubeHit = checkOrientationCubeHover(at: location, viewSize: viewSize, appState: appState)
 
@@ -299,13 +461,26 @@ final class InputHandler {
         // Update leveling hover point
         if appState.levelingState.isActive && !appState.levelingState.isReadyForAxisSelection {
             if let model = appState.model {
-                let ray = camera.mouseRay(screenPos: location, viewSize: viewSize)
+                let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
                 appState.levelingState.hoverPoint = RayPicking.findIntersection(ray: ray, model: model, accelerator: appState.spatialAccelerator)
             }
         } else {
             appState.levelingState.hoverPoint = nil
         }
 
+        // Update the highlighted face or region under the cursor
+        if appState.hoverHighlight != nil {
+            appState.updateHoveredRegion(ray: camera.pickRay(screenPos: location, viewSize: viewSize))
+        }
+
+        // Update reference geometry hover point
+        if appState.referenceGeometry.isCollecting && !appState.measurementSystem.isCollecting, let model = appState.model {
+            let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
+            appState.referenceGeometry.hoverPoint = appState.measurementSystem.pickPoint(ray: ray, model: model, accelerator: appState.spatialAccelerator)
+        } else {
+            appState.referenceGeometry.hoverPoint = nil
+        }
+
         // Then check for measurement hover
         guard appState.measurementSystem.isCollecting else {
             appState.measurementSystem.hoverPoint = nil
@@ -315,15 +490,17 @@ final class InputHandler {
         }
 
         // Generate ray from mouse position
-        let ray = camera.mouseRay(screenPos: location, viewSize: viewSize)
+        let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
 
         // Update hover based on mode
+        let pickStart = CFAbsoluteTimeGetCurrent()
         if appState.measurementSystem.mode == .triangleSelect {
             appState.measurementSystem.updateTriangleHover(ray: ray, model: appState.model, accelerator: appState.spatialAccelerator)
         } else {
             // Update hover point for other modes
             appState.measurementSystem.updateHover(ray: ray, model: appState.model, accelerator: appState.spatialAccelerator)
         }
+        appState.performanceStats.recordPicking(CFAbsoluteTimeGetCurrent() - pickStart)
     }
 
     /// Check if mouse is hovering over orientation cube and which face or axis label
@@ -403,6 +580,16 @@ final class InputHandler {
         // Convert click location from AppKit (Y=0 bottom) to SwiftUI (Y=0 top) for comparison
         let swiftUILocation = CGPoint(x: location.x, y: viewSize.height - location.y)
 
+        // Labels as placed by the label layout (possibly moved away from their anchors), in points
+        if !measurementSystem.labelFrames.isEmpty, viewSize.width > 0 {
+            let scale = measurementSystem.labelFramesViewSize.width / viewSize.width
+            let point = CGPoint(x: swiftUILocation.x * scale, y: swiftUILocation.y * scale)
+            return measurementSystem.labelFrames
+                .filter { $0.key < measurementSystem.measurements.count && $0.value.contains(point) }
+                .map(\.key)
+                .min()
+        }
+
         // Check each measurement label
         for (index, measurement) in measurementSystem.measurements.enumerated() {
             if let screenPos = camera.project(worldPosition: measurement.labelPosition, viewSize: viewSize) {
@@ -426,10 +613,109 @@ final class InputHandler {
         return nil
     }
 
-    func handleScroll(deltaY: CGFloat, camera: Camera) {
+    // MARK: - Context Menu
+
+    /// Maximum distance (pixels) between the cursor and a measurement point for the point menu
+    private static let contextMenuPointRadius: CGFloat = 12
+
+    /// What a right-click at the location refers to: a measurement label, a measurement point or the model surface
+    func contextMenuTarget(at location: CGPoint, camera: Camera, viewSize: CGSize, appState: AppState) -> ContextMenuTarget? {
+        let measurementSystem = appState.measurementSystem
+        if let index = findMeasurementLabelAtLocation(
+            location: location,
+            camera: camera,
+            viewSize: viewSize,
+            measurementSystem: measurementSystem
+        ) {
+            return .measurement(index)
+        }
+
+        // Closest measurement point near the cursor (projected positions have Y=0 at the top)
+        let cursor = CGPoint(x: location.x, y: viewSize.height - location.y)
+        if let annotation = findAnnotation(at: cursor, camera: camera, viewSize: viewSize, appState: appState) {
+            return .annotation(annotation.id, position: annotation.renderPosition(coordinateOffset: appState.coordinateOffset))
+        }
+        var closest: (point: MeasurementPoint, measurement: Int?, distance: CGFloat)?
+        let candidates = measurementSystem.measurements.enumerated().flatMap { index, measurement in
+            measurement.points.map { (point: $0, measurement: Optional(index)) }
+        } + measurementSystem.currentPoints.map { (point: $0, measurement: Int?.none) }
+        // Measurement points are skipped when the selection filter excludes them
+        for candidate in candidates where measurementSystem.snapTargets.contains(.measurementPoints) {
+            guard let screen = camera.project(worldPosition: candidate.point.position, viewSize: viewSize) else { continue }
+            let distance = hypot(screen.x - cursor.x, screen.y - cursor.y)
+            if distance <= Self.contextMenuPointRadius && distance < (closest?.distance ?? .infinity) {
+                closest = (candidate.point, candidate.measurement, distance)
+            }
+        }
+        if let closest {
+            return .point(closest.point, measurement: closest.measurement)
+        }
+
+        guard let model = appState.model else { return nil }
+        let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
+        return measurementSystem.pickPoint(ray: ray, model: model, accelerator: appState.spatialAccelerator).map { .surface($0) }
+    }
+
+    /// Annotation whose label or pin is under the cursor (Y=0 at the top, drawable pixels)
+    private func findAnnotation(at cursor: CGPoint, camera: Camera, viewSize: CGSize, appState: AppState) -> Annotation? {
+        guard appState.showAnnotations, !appState.annotations.isEmpty else { return nil }
+
+        // Labels as placed by the overlay, in points
+        if appState.annotationLabelFramesViewSize.width > 0, viewSize.width > 0 {
+            let scale = appState.annotationLabelFramesViewSize.width / viewSize.width
+            let point = CGPoint(x: cursor.x * scale, y: cursor.y * scale)
+            if let id = appState.annotationLabelFrames.first(where: { $0.value.contains(point) })?.key {
+                return appState.annotations.first { $0.id == id }
+            }
+        }
+
+        // Pins at the anchors
+        return appState.annotations.first { annotation in
+            let position = annotation.renderPosition(coordinateOffset: appState.coordinateOffset)
+            guard let screen = camera.project(worldPosition: position, viewSize: viewSize) else { return false }
+            return hypot(screen.x - cursor.x, screen.y - cursor.y) <= Self.contextMenuPointRadius
+        }
+    }
+
+    /// A rotate drag released within this time (s) after the last movement keeps spinning
+    private static let inertiaReleaseWindow: CFAbsoluteTime = 0.08
+
+    func handleScroll(
+        deltaY: CGFloat,
+        camera: Camera,
+        at location: CGPoint? = nil,
+        viewSize: CGSize? = nil,
+        appState: AppState? = nil,
+        hasPreciseDeltas: Bool = true
+    ) {
         // Zoom with scroll wheel (inverted for natural scrolling)
         let sensitivity = 1.0
-        camera.zoom(delta: -Double(deltaY) * sensitivity)
+        let delta = -Double(deltaY) * sensitivity
+        let settings = AppSettings.shared
+
+        var anchor: SIMD3<Float>?
+        if settings.zoomToCursor, let location = location, let viewSize = viewSize, let appState = appState {
+            anchor = zoomAnchor(at: location, camera: camera, viewSize: viewSize, appState: appState)
+        }
+
+        // Mouse wheels step in coarse notches, so glide through them.
+        // Trackpads already deliver their own momentum.
+        if settings.navigationInertia && !hasPreciseDeltas {
+            camera.zoomVelocity += delta * Camera.inertiaDamping
+            camera.zoomAnchor = anchor
+        } else {
+            camera.zoom(delta: delta, toward: anchor)
+        }
+    }
+
+    /// Point to zoom toward (in displayed coordinates): the surface under the cursor, or the cursor ray at target depth
+    private func zoomAnchor(at location: CGPoint, camera: Camera, viewSize: CGSize, appState: AppState) -> SIMD3<Float> {
+        let ray = camera.pickRay(screenPos: location, viewSize: viewSize)
+        if let hit = appState.spatialAccelerator?.raycast(ray: ray) {
+            return camera.toDisplay(hit.position.float3)
+        }
+        let viewRay = camera.mouseRay(screenPos: location, viewSize: viewSize)
+        return camera.position + viewRay.direction * Float(camera.distance)
     }
 
     /// Debug ray casting - right-click to see detailed intersection info
@@ -557,10 +843,13 @@ final class InputHandler {
     func handleFlagsChanged(event: NSEvent, appState: AppState) {
         let optionPressed = event.modifierFlags.contains(.option)
 
+        // Holding Control temporarily inverts grid snapping
+        appState.measurementSystem.gridSnapModifierHeld = event.modifierFlags.contains(.control)
+
         // Option key just pressed - toggle point constraint or release axis constraint
         if optionPressed && !optionWasPressed {
-            // If we have an axis constraint, release it
-            if case .axis = appState.measurementSystem.constraint {
+            // If we have an axis or reference direction constraint, release it
+            if appState.measurementSystem.constraint != nil && !appState.measurementSystem.hasPointConstraint {
                 appState.measurementSystem.constraint = nil
                 appState.measurementSystem.constrainedEndpoint = nil
                 print("Axis constraint released (Option key)")
@@ -602,6 +891,49 @@ final class InputHandler {
             return true
         }
 
+        // Presentation mode: step through the saved views, ESC ends it
+        if appState.isPresenting, handlePresentationKey(event: event, appState: appState) {
+            return true
+        }
+
+        // Layer preview: Space plays/pauses, comma/period step one layer (Shift: ten layers)
+        if appState.layerPreview.isActive {
+            let stepSize = event.modifierFlags.contains(.shift) ? 10 : 1
+            switch characters {
+            case " ":
+                appState.layerPreview.togglePlaying()
+                return true
+            case ",", "<":
+                appState.layerPreview.step(by: -stepSize)
+                return true
+            case ".", ">":
+                appState.layerPreview.step(by: stepSize)
+                return true
+            default:
+                break
+            }
+        }
+
+        // Cutaway: brackets move the cut nearer/farther (Shift: larger steps)
+        if appState.camera.cutawayDepth != nil {
+            let fraction = event.modifierFlags.contains(.shift) ? 0.1 : 0.02
+            switch characters {
+            case "[", "{":
+                appState.adjustCutaway(by: -fraction)
+                return true
+            case "]", "}":
+                appState.adjustCutaway(by: fraction)
+                return true
+            default:
+                break
+            }
+        }
+
+        // Typed length while a distance constraint is active (digits take precedence over camera presets)
+        if handleLengthInput(event: event, characters: characters, appState: appState) {
+            return true
+        }
+
         switch characters {
         // Camera presets
         case "1":
@@ -661,6 +993,14 @@ final class InputHandler {
                 return true
             }
             return false
+        case "b":
+            // Overall dimensions of the bounding box (Cmd+B is handled by the menu)
+            if !event.modifierFlags.contains(.command) && appState.model != nil {
+                appState.addBoundingDimensions()
+                print("Added overall dimensions")
+                return true
+            }
+            return false
         case "f":
             // Frame model in view
             if let model = appState.model {
@@ -719,6 +1059,25 @@ final class InputHandler {
             }
             return false
 
+        case "s":
+            // Toggle grid snapping for measurement points (Cmd+S is save)
+            if !event.modifierFlags.contains(.command) {
+                appState.measurementSystem.gridSnapEnabled.toggle()
+                print("Grid snap: \(appState.measurementSystem.gridSnapEnabled ? "on" : "off")")
+                return true
+            }
+            return false
+
+        case "+", "=", "-":
+            // Grow or shrink the radius within which picks snap to vertices (Cmd+/- is left to the menus)
+            if !event.modifierFlags.contains(.command) {
+                let settings = AppSettings.shared
+                settings.snapRadius = AppSettings.snapRadius(settings.snapRadius, steppedBy: characters == "-" ? -1 : 1)
+                print("Snap radius: \(Int(settings.snapRadius)) pt")
+                return true
+            }
+            return false
+
         case "o":
             // Open current file with go3mf
             openWithGo3mf(sourceFileURL: appState.sourceFileURL)
@@ -731,14 +1090,50 @@ final class InputHandler {
             return true
 
         default:
+            // Tab / Shift+Tab to cycle snap candidates (keyboard-only picking)
+            if event.keyCode == 48 {  // Tab key code
+                return handleSnapCandidateCycle(reverse: event.modifierFlags.contains(.shift), camera: camera, appState: appState)
+            }
+            // Return / Enter to confirm the highlighted snap candidate
+            if event.keyCode == 36 || event.keyCode == 76 {  // 36 = Return, 76 = Keypad Enter
+                return handleSnapCandidateConfirm(appState: appState)
+            }
+            // Arrow keys to orbit the camera (keyboard-only navigation); Shift turns by exact 90° steps
+            if let direction = arrowKeyDirection(for: event.keyCode) {
+                if event.modifierFlags.contains(.shift) {
+                    camera.rotateQuarterTurns(pitch: direction.pitch, yaw: direction.yaw)
+                } else {
+                    camera.rotate(deltaX: Double(direction.pitch) * arrowRotationStep, deltaY: Double(direction.yaw) * arrowRotationStep)
+                }
+                appState.measurementSystem.clearSnapCandidates()
+                return true
+            }
             // ESC key to cancel measurement, leveling, clear selection, or reset view
             if event.keyCode == 53 {  // ESC key code
                 // First, cancel leveling if active
-                if appState.levelingState.isActive {
+                if appState.levelingState.isActive || appState.levelingState.isPickingFace {
                     appState.levelingState.reset()
                     print("Leveling cancelled")
                     return true
                 }
+                // Leave the thickness probe mode
+                if appState.isProbingThickness {
+                    appState.setThicknessProbe(false)
+                    print("Thickness probe ended")
+                    return true
+                }
+                // Leave the face distance tool
+                if appState.isMeasuringFaceDistance {
+                    appState.setFaceDistanceTool(false)
+                    print("Face distance ended")
+                    return true
+                }
+                // Cancel reference geometry creation
+                if appState.referenceGeometry.isCollecting {
+                    appState.referenceGeometry.cancelTool()
+                    print("Reference geometry creation cancelled")
+                    return true
+                }
                 // Clear any selection
                 if !appState.measurementSystem.selectedMeasurements.isEmpty {
                     appState.measurementSystem.selectedMeasurements.removeAll()
@@ -777,4 +1172,126 @@ final class InputHandler {
             return false
         }
     }
+
+    // MARK: - Presentation Mode
+
+    /// Right arrow, Space and Page Down fly to the next saved view, Left arrow and Page Up to the
+    /// previous one; ESC leaves presentation mode (and the full screen it entered)
+    /// - Returns: true if the key was used by the presentation
+    private func handlePresentationKey(event: NSEvent, appState: AppState) -> Bool {
+        switch event.keyCode {
+        case 124, 49, 121:  // Right, Space, Page Down
+            guard !appState.savedViews.isEmpty else { return false }
+            appState.presentSavedView(step: 1)
+            return true
+        case 123, 116:  // Left, Page Up
+            guard !appState.savedViews.isEmpty else { return false }
+            appState.presentSavedView(step: -1)
+            return true
+        case 53:  // ESC
+            appState.setPresentationMode(false)
+            let windowNumber = event.windowNumber
+            Task { @MainActor in
+                PresentationWindow.leave(windowNumber: windowNumber)
+            }
+            return true
+        default:
+            return false
+        }
+    }
+
+    // MARK: - Length Input
+
+    /// Edit and confirm the typed length of a constrained distance
+    /// - Returns: true if the key was used for the length input
+    private func handleLengthInput(event: NSEvent, characters: String, appState: AppState) -> Bool {
+        let measurementSystem = appState.measurementSystem
+        guard measurementSystem.acceptsLengthInput,
+              event.modifierFlags.intersection([.command, .control, .option]).isEmpty else {
+            return false
+        }
+
+        switch event.keyCode {
+        case 36, 76:  // Return, Keypad Enter
+            guard measurementSystem.lengthInput != nil else { return false }
+            if let point = measurementSystem.placeTypedLength() {
+                print("Placed point at typed length: \(point.position)")
+            } else {
+                NSSound.beep()
+            }
+            return true
+        case 51, 117:  // Backspace, Forward Delete
+            return measurementSystem.removeLastLengthInputCharacter()
+        case 53:  // ESC
+            return measurementSystem.cancelLengthInput()
+        default:
+            guard characters.count == 1, let character = characters.first else { return false }
+            return measurementSystem.appendLengthInput(character)
+        }
+    }
+
+    // MARK: - Keyboard Picking
+
+    /// Rotation step per arrow key press (radians)
+    private let arrowRotationStep = Double.pi / 12
+
+    /// Map arrow key codes to camera rotation directions (pitch, yaw)
+    private func arrowKeyDirection(for keyCode: UInt16) -> (pitch: Int, yaw: Int)? {
+        switch keyCode {
+        case 123: return (0, -1)  // Left
+        case 124: return (0, 1)   // Right
+        case 125: return (-1, 0)  // Down
+        case 126: return (1, 0)   // Up
+        default: return nil
+        }
+    }
+
+    /// Cycle through snap candidates around the view center
+    /// The first Tab collects candidates along the camera's line of sight
+    private func handleSnapCandidateCycle(reverse: Bool, camera: Camera, appState: AppState) -> Bool {
+        let measurementSystem = appState.measurementSystem
+        guard measurementSystem.isCollecting,
+              measurementSystem.mode != .triangleSelect,
+              let model = appState.model else {
+            return false
+        }
+
+        let candidate: MeasurementPoint?
+        if measurementSystem.snapCandidates.isEmpty {
+            let ray = camera.toModel(Ray(origin: camera.position, direction: camera.forward))
+            measurementSystem.updateSnapCandidates(ray: ray, model: model, accelerator: appState.spatialAccelerator)
+            candidate = measurementSystem.snapCandidates.first
+        } else {
+            candidate = measurementSystem.cycleSnapCandidate(reverse: reverse)
+        }
+
+        guard let candidate = candidate else {
+            appState.measurementAnnouncer.announce("No model surface at the view center")
+            return true
+        }
+
+        appState.measurementAnnouncer.announceCandidate(
+            candidate,
+            index: measurementSystem.snapCandidateIndex,
+            count: measurementSystem.snapCandidates.count,
+            coordinateOffset: appState.coordinateOffset
+        )
+        return true
+    }
+
+    /// Confirm the highlighted snap candidate as the next measurement point
+    private func handleSnapCandidateConfirm(appState: AppState) -> Bool {
+        let measurementSystem = appState.measurementSystem
+        guard measurementSystem.isCollecting, measurementSystem.mode != .triangleSelect else {
+            return false
+        }
+
+        guard let point = measurementSystem.confirmHoverPoint() else {
+            appState.measurementAnnouncer.announce("No point highlighted, press Tab to pick one")
+            return true
+        }
+
+        print("Picked point (keyboard): \(point.position)")
+        return true
+    }
 }
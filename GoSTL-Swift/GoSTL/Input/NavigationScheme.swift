@@ -0,0 +1,85 @@
+import AppKit
+
+/// Mouse button that started a drag
+enum MouseButton {
+    case left
+    case middle
+    case right
+}
+
+/// Camera operation performed by a mouse drag
+enum NavigationAction {
+    case rotate
+    case pan
+    case zoom
+}
+
+/// Mouse navigation presets matching popular CAD tools
+enum NavigationScheme: String, CaseIterable, Codable, Identifiable {
+    case gostl = "GoSTL"
+    case fusion360 = "Fusion 360"
+    case solidworks = "SolidWorks"
+    case blender = "Blender"
+
+    var id: String { rawValue }
+
+    /// Camera action for a drag with the given button and modifiers
+    /// - Returns: The navigation action, or nil if the drag doesn't move the camera
+    func action(for button: MouseButton, modifiers: NSEvent.ModifierFlags) -> NavigationAction? {
+        let shift = modifiers.contains(.shift)
+        let control = modifiers.contains(.control)
+
+        switch button {
+        case .left:
+            // Left drag keeps the GoSTL behavior in every scheme so trackpads still work
+            return shift ? .pan : .rotate
+
+        case .middle:
+            switch self {
+            case .gostl:
+                return .pan
+            case .fusion360:
+                return shift ? .rotate : .pan
+            case .solidworks:
+                if control { return .pan }
+                return shift ? .zoom : .rotate
+            case .blender:
+                if shift { return .pan }
+                return control ? .zoom : .rotate
+            }
+
+        case .right:
+            return nil
+        }
+    }
+
+    /// Human readable gesture for each navigation action (for settings and docs)
+    var bindings: [(action: String, gesture: String)] {
+        switch self {
+        case .gostl:
+            return [
+                ("Rotate", "Left drag"),
+                ("Pan", "Shift + left drag or middle drag"),
+                ("Zoom", "Scroll wheel")
+            ]
+        case .fusion360:
+            return [
+                ("Rotate", "Shift + middle drag"),
+                ("Pan", "Middle drag"),
+                ("Zoom", "Scroll wheel")
+            ]
+        case .solidworks:
+            return [
+                ("Rotate", "Middle drag"),
+                ("Pan", "Control + middle drag"),
+                ("Zoom", "Shift + middle drag or scroll wheel")
+            ]
+        case .blender:
+            return [
+                ("Rotate", "Middle drag"),
+                ("Pan", "Shift + middle drag"),
+                ("Zoom", "Control + middle drag or scroll wheel")
+            ]
+        }
+    }
+}
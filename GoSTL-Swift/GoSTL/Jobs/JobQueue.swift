@@ -0,0 +1,223 @@
+import Foundation
+import Observation
+
+/// Kind of background work run by a `JobQueue`
+enum JobKind: String, Sendable {
+    /// Loading or rendering the source file (OpenSCAD, go3mf, STL/3MF parsing)
+    case load
+    /// Building the BVH used for picking and snapping
+    case spatialIndex
+    /// Extracting wireframe edges
+    case wireframe
+    /// Plugin analyzers and other model analysis
+    case analysis
+    /// Writing files
+    case export
+    /// Loading a second model and checking it against the main model for collisions
+    case clearance
+    /// Extracting iso-height contour lines
+    case contours
+    /// Coloring faces by draft angle
+    case draft
+    /// Detecting primitive surfaces
+    case primitives
+    /// Coloring faces by height
+    case heightColors
+    /// Loading and registering the model shown side by side
+    case comparison
+    /// Checking a loaded mesh for defects
+    case meshQuality
+    /// Baking ambient occlusion for presentations
+    case occlusion
+    /// Running a JavaScript analysis script
+    case script
+
+    /// A new job of this kind cancels the running one (a newer reload makes the old result useless)
+    var supersedesRunningJob: Bool {
+        self != .export
+    }
+
+    /// Whether the user may cancel the job (exports are not interrupted halfway through a file)
+    var isCancellable: Bool {
+        switch self {
+        case .load, .analysis, .clearance, .contours, .draft, .primitives, .heightColors, .comparison, .meshQuality, .occlusion, .script: return true
+        case .spatialIndex, .wireframe, .export: return false
+        }
+    }
+}
+
+/// Handle passed to the work closure of a job: cancellation and progress reporting
+final class JobContext: @unchecked Sendable {
+    private let lock = NSLock()
+    private var cancelled = false
+    private var processes: [Process] = []
+    private let onProgress: @Sendable (Double?, String?) -> Void
+
+    /// - Parameter onProgress: Called from the worker thread with a fraction (nil if unknown) and a message
+    init(onProgress: @escaping @Sendable (Double?, String?) -> Void = { _, _ in }) {
+        self.onProgress = onProgress
+    }
+
+    var isCancelled: Bool {
+        lock.lock()
+        defer { lock.unlock() }
+        return cancelled
+    }
+
+    /// Mark the job as cancelled and terminate the processes it is waiting for
+    func cancel() {
+        lock.lock()
+        cancelled = true
+        let running = processes
+        processes.removeAll()
+        lock.unlock()
+
+        for process in running where process.isRunning {
+            process.terminate()
+        }
+    }
+
+    /// Throw `CancellationError` if the job has been cancelled
+    func checkCancellation() throws {
+        if isCancelled {
+            throw CancellationError()
+        }
+    }
+
+    /// Report progress as a fraction from 0 to 1 (nil if unknown) with an optional status message
+    func reportProgress(_ fraction: Double?, _ message: String? = nil) {
+        onProgress(fraction.map { min(max($0, 0), 1) }, message)
+    }
+
+    /// Run an external process and wait for it; cancelling the job terminates the process
+    func run(_ process: Process) throws {
+        try checkCancellation()
+        try process.run()
+        attach(process)
+        process.waitUntilExit()
+        detach(process)
+        try checkCancellation()
+    }
+
+    /// Terminate a started process when the job is cancelled (immediately if it already is)
+    func attach(_ process: Process) {
+        lock.lock()
+        let alreadyCancelled = cancelled
+        if !alreadyCancelled {
+            processes.append(process)
+        }
+        lock.unlock()
+
+        if alreadyCancelled, process.isRunning {
+            process.terminate()
+        }
+    }
+
+    /// Stop tracking a process that has exited
+    func detach(_ process: Process) {
+        lock.lock()
+        processes.removeAll { $0 === process }
+        lock.unlock()
+    }
+
+    /// Run a process within a job if there is one, otherwise just run it and wait
+    static func run(_ process: Process, in job: JobContext?) throws {
+        if let job = job {
+            try job.run(process)
+        } else {
+            try process.run()
+            process.waitUntilExit()
+        }
+    }
+}
+
+/// Runs work off the main thread and delivers results back on it.
+///
+/// All methods must be called on the main thread. Results of cancelled jobs are dropped:
+/// whoever cancels a job is responsible for restoring the state it would have set.
+@Observable
+final class JobQueue: @unchecked Sendable {
+    /// A running job as shown in the UI
+    struct Job: Identifiable {
+        let id: UUID
+        let kind: JobKind
+        let title: String
+        var progress: Double?
+        var message: String?
+        fileprivate let context: JobContext
+    }
+
+    /// Running jobs in the order they were submitted
+    private(set) var jobs: [Job] = []
+
+    @ObservationIgnored
+    private let queue = DispatchQueue(label: "com.gostl.jobs", qos: .userInitiated, attributes: .concurrent)
+
+    /// Run `work` in the background and pass its result to `completion` on the main thread
+    /// - Returns: The job ID, for cancelling it
+    @discardableResult
+    func submit<T: Sendable>(
+        _ kind: JobKind,
+        title: String,
+        work: @escaping @Sendable (JobContext) throws -> T,
+        completion: @escaping @MainActor (Result<T, Error>) -> Void
+    ) -> UUID {
+        if kind.supersedesRunningJob {
+            cancel(kind)
+        }
+
+        let id = UUID()
+        let context = JobContext { [weak self] progress, message in
+            DispatchQueue.main.async {
+                self?.update(id, progress: progress, message: message)
+            }
+        }
+        jobs.append(Job(id: id, kind: kind, title: title, context: context))
+
+        queue.async { [weak self] in
+            let result = Result { try work(context) }
+            DispatchQueue.main.async {
+                self?.jobs.removeAll { $0.id == id }
+                guard !context.isCancelled else { return }
+                MainActor.assumeIsolated {
+                    completion(result)
+                }
+            }
+        }
+        return id
+    }
+
+    /// The running job of a kind (the most recent one if there are several)
+    func job(_ kind: JobKind) -> Job? {
+        jobs.last { $0.kind == kind }
+    }
+
+    func isRunning(_ kind: JobKind) -> Bool {
+        jobs.contains { $0.kind == kind }
+    }
+
+    /// Cancel a job; its completion handler will not be called
+    func cancel(id: UUID) {
+        guard let index = jobs.firstIndex(where: { $0.id == id }) else { return }
+        jobs.remove(at: index).context.cancel()
+    }
+
+    /// Cancel all running jobs of a kind
+    func cancel(_ kind: JobKind) {
+        for job in jobs where job.kind == kind {
+            cancel(id: job.id)
+        }
+    }
+
+    func cancelAll() {
+        for job in jobs {
+            cancel(id: job.id)
+        }
+    }
+
+    private func update(_ id: UUID, progress: Double?, message: String?) {
+        guard let index = jobs.firstIndex(where: { $0.id == id }) else { return }
+        jobs[index].progress = progress
+        jobs[index].message = message
+    }
+}
@@ -19,6 +19,9 @@ final class LevelingState: @unchecked Sendable {
     /// Selected axis to level to (0=X, 1=Y, 2=Z), nil if not yet selected
     var selectedAxis: Int?
 
+    /// Whether a click on a face lays the model flat on it (instead of picking two points)
+    var isPickingFace: Bool = false
+
     /// Previous model triangles for undo support
     var previousModelTriangles: [Triangle]?
 
@@ -63,6 +66,12 @@ final class LevelingState: @unchecked Sendable {
         selectedAxis = nil
     }
 
+    /// Start picking a face to lay the model flat on
+    func startFacePick() {
+        reset()
+        isPickingFace = true
+    }
+
     /// Add a point to the leveling selection
     /// - Parameter position: The 3D position of the selected point
     /// - Returns: true if both points are now selected
@@ -87,6 +96,7 @@ final class LevelingState: @unchecked Sendable {
     /// Reset leveling state (cancel or complete)
     func reset() {
         isActive = false
+        isPickingFace = false
         point1 = nil
         point2 = nil
         hoverPoint = nil
@@ -0,0 +1,244 @@
+import Foundation
+import simd
+
+/// One-click orientation of imported models: principal-axis alignment and laying flat on a face
+enum ModelAlignment {
+    /// Principal axes of a mesh surface, ordered from the largest to the smallest spread
+    struct PrincipalAxes {
+        let centroid: Vector3
+        let axes: [Vector3]
+        let variances: [Double]
+    }
+
+    /// Coplanar triangles forming one flat face
+    struct PlanarFace {
+        /// Outward normal of the face
+        let normal: Vector3
+        /// Plane offset along the normal (normal · point)
+        let offset: Double
+        let area: Double
+        let triangles: [Int]
+    }
+
+    /// Normals within this many degrees (and planes within `planeTolerance` mm) form one face
+    static let angleTolerance = 0.5
+    static let planeTolerance = 0.01
+
+    // MARK: - Principal Axes
+
+    /// Principal axes of the surface (area weighted, so the tessellation density doesn't matter)
+    static func principalAxes(of triangles: [Triangle]) -> PrincipalAxes? {
+        var totalArea = 0.0
+        var weightedCentroid = SIMD3<Double>.zero
+        var secondMoment = simd_double3x3()
+
+        for triangle in triangles {
+            let area = triangle.area()
+            guard area > 0 else { continue }
+            let a = triangle.v1.value, b = triangle.v2.value, c = triangle.v3.value
+            let centroid = (a + b + c) / 3
+
+            // ∫ x xᵀ dA over a triangle = A/12 · (9 m mᵀ + a aᵀ + b bᵀ + c cᵀ)
+            let moment = 9 * outer(centroid, centroid) + outer(a, a) + outer(b, b) + outer(c, c)
+            secondMoment += (area / 12) * moment
+            weightedCentroid += area * centroid
+            totalArea += area
+        }
+        guard totalArea > 0 else { return nil }
+
+        let mean = weightedCentroid / totalArea
+        let covariance = (1 / totalArea) * secondMoment - outer(mean, mean)
+        let (values, vectors) = symmetricEigen(covariance)
+
+        let order = [0, 1, 2].sorted { values[$0] > values[$1] }
+        return PrincipalAxes(
+            centroid: Vector3(value: mean),
+            axes: order.map { Vector3(value: simd_normalize(vectors[$0])) },
+            variances: order.map { values[$0] }
+        )
+    }
+
+    /// The model rotated about its bounding box center so the largest spread runs along X, the next
+    /// along Y and the smallest along Z
+    ///
+    /// Axes with (nearly) equal spread, e.g. of a cube or a cylinder's cross-section, are not unique.
+    static func alignedToPrincipalAxes(_ model: STLModel) -> STLModel? {
+        guard let principal = principalAxes(of: model.triangles) else { return nil }
+
+        // Flip each axis towards its dominant world direction so already aligned models stay put
+        let first = towardsDominantComponent(principal.axes[0])
+        let second = towardsDominantComponent(principal.axes[1])
+        let third = first.cross(second).normalized()
+
+        let rotation = simd_double3x3(rows: [first.value, second.value, third.value])
+        return rotated(model, by: rotation, center: model.boundingBox().center)
+    }
+
+    // MARK: - Planar Faces
+
+    /// Flat faces of the model, largest first
+    static func planarFaces(of triangles: [Triangle]) -> [PlanarFace] {
+        struct Key: Hashable {
+            let nx: Int, ny: Int, nz: Int, offset: Int
+        }
+        let normalStep = angleTolerance * .pi / 180
+
+        var groups: [Key: [Int]] = [:]
+        for (index, triangle) in triangles.enumerated() {
+            let normal = Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3)
+            guard normal.length > 0.5 else { continue }
+            let key = Key(
+                nx: Int((normal.x / normalStep).rounded()),
+                ny: Int((normal.y / normalStep).rounded()),
+                nz: Int((normal.z / normalStep).rounded()),
+                offset: Int((normal.dot(triangle.v1) / planeTolerance).rounded())
+            )
+            groups[key, default: []].append(index)
+        }
+
+        return groups.values.compactMap { indices in
+            var area = 0.0
+            var normalSum = Vector3.zero
+            var offsetSum = 0.0
+            for index in indices {
+                let triangle = triangles[index]
+                let triangleArea = triangle.area()
+                let normal = Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3)
+                area += triangleArea
+                normalSum = normalSum + normal * triangleArea
+                offsetSum += normal.dot(triangle.v1) * triangleArea
+            }
+            guard area > 0, normalSum.length > 0 else { return nil }
+            return PlanarFace(normal: normalSum.normalized(), offset: offsetSum / area, area: area, triangles: indices)
+        }
+        .sorted { $0.area > $1.area }
+    }
+
+    /// The flat face a triangle belongs to: all triangles in its plane with the same orientation
+    static func face(containing index: Int, in triangles: [Triangle]) -> PlanarFace? {
+        guard triangles.indices.contains(index) else { return nil }
+        let picked = triangles[index]
+        let normal = Triangle.calculateNormal(v1: picked.v1, v2: picked.v2, v3: picked.v3)
+        guard normal.length > 0.5 else { return nil }
+
+        let offset = normal.dot(picked.v1)
+        let minCosine = cos(angleTolerance * .pi / 180)
+        var members: [Int] = []
+        var area = 0.0
+        for (candidate, triangle) in triangles.enumerated() {
+            let candidateNormal = Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3)
+            guard candidateNormal.dot(normal) >= minCosine else { continue }
+            let inPlane = [triangle.v1, triangle.v2, triangle.v3].allSatisfy { abs(normal.dot($0) - offset) <= planeTolerance }
+            if inPlane {
+                members.append(candidate)
+                area += triangle.area()
+            }
+        }
+        return PlanarFace(normal: normal, offset: offset, area: area, triangles: members)
+    }
+
+    /// Outward normal of the plane best fitting a set of triangles (e.g. a selection on a slightly
+    /// uneven or finely tessellated surface)
+    static func fittedPlaneNormal(of triangles: [Triangle]) -> Vector3? {
+        let outward = triangles.reduce(Vector3.zero) { sum, triangle in
+            sum + Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3) * triangle.area()
+        }
+        guard outward.length > 0, let principal = principalAxes(of: triangles) else { return nil }
+
+        // Least-squares plane: the axis of least spread, unless the selection is too narrow to tell
+        let normal = principal.variances[1] > principal.variances[2] * 4 ? principal.axes[2] : outward.normalized()
+        return normal.dot(outward) < 0 ? -normal : normal
+    }
+
+    /// Largest flat face the model can rest on (no part of the model below its plane)
+    static func largestSupportingFace(of model: STLModel) -> PlanarFace? {
+        let faces = planarFaces(of: model.triangles)
+        let tolerance = Swift.max(planeTolerance, model.boundingBox().diagonal * 1e-6)
+
+        for face in faces {
+            let reach = model.triangles.reduce(-Double.infinity) { reach, triangle in
+                Swift.max(reach, face.normal.dot(triangle.v1), face.normal.dot(triangle.v2), face.normal.dot(triangle.v3))
+            }
+            if reach <= face.offset + tolerance {
+                return face
+            }
+        }
+        return faces.first
+    }
+
+    // MARK: - Lay Flat
+
+    /// The model rotated so a face with the given outward normal points down, resting on `floorZ`
+    static func laidFlat(_ model: STLModel, faceNormal: Vector3, floorZ: Double) -> STLModel {
+        let normal = faceNormal.normalized()
+        let down = -Vector3.unitZ
+        let center = model.boundingBox().center
+
+        // Fresh model: the precomputed bounds of the original would go stale when rotating
+        var result = STLModel(triangles: model.triangles, name: model.name, header: model.header)
+        let axis = normal.cross(down)
+        if axis.length > 1e-9 {
+            let angle = acos(Swift.max(-1, Swift.min(1, normal.dot(down))))
+            Rotation.rotateModel(&result, axis: axis.normalized(), angle: angle, center: center)
+        } else if normal.dot(down) < 0 {
+            // Face points straight up: turn the model over
+            Rotation.rotateModel(&result, axis: Vector3.unitX, angle: .pi, center: center)
+        }
+
+        return result.translated(by: Vector3(0, 0, floorZ - result.boundingBox().min.z))
+    }
+
+    // MARK: - Helpers
+
+    private static func rotated(_ model: STLModel, by rotation: simd_double3x3, center: Vector3) -> STLModel {
+        let origin = center.value
+        var result = STLModel(triangles: model.triangles, name: model.name, header: model.header)
+        for index in result.triangles.indices {
+            result.triangles[index].v1 = Vector3(value: rotation * (result.triangles[index].v1.value - origin) + origin)
+            result.triangles[index].v2 = Vector3(value: rotation * (result.triangles[index].v2.value - origin) + origin)
+            result.triangles[index].v3 = Vector3(value: rotation * (result.triangles[index].v3.value - origin) + origin)
+            result.triangles[index].updateNormal()
+        }
+        return result
+    }
+
+    private static func towardsDominantComponent(_ axis: Vector3) -> Vector3 {
+        let dominant = [axis.x, axis.y, axis.z].max { abs($0) < abs($1) } ?? 0
+        return dominant < 0 ? -axis : axis
+    }
+
+    private static func outer(_ a: SIMD3<Double>, _ b: SIMD3<Double>) -> simd_double3x3 {
+        simd_double3x3(columns: (a * b.x, a * b.y, a * b.z))
+    }
+
+    /// Eigenvalues and eigenvectors of a symmetric 3x3 matrix (cyclic Jacobi rotations)
+    static func symmetricEigen(_ matrix: simd_double3x3) -> (values: [Double], vectors: [SIMD3<Double>]) {
+        var a = matrix
+        var v = matrix_identity_double3x3
+
+        for _ in 0..<50 {
+            let offDiagonal = abs(a[1][0]) + abs(a[2][0]) + abs(a[2][1])
+            let scale = abs(a[0][0]) + abs(a[1][1]) + abs(a[2][2])
+            if offDiagonal <= 1e-15 * Swift.max(scale, 1e-300) { break }
+
+            for (p, q) in [(0, 1), (0, 2), (1, 2)] where a[q][p] != 0 {
+                let theta = (a[q][q] - a[p][p]) / (2 * a[q][p])
+                let t = (theta >= 0 ? 1.0 : -1.0) / (abs(theta) + (theta * theta + 1).squareRoot())
+                let c = 1 / (t * t + 1).squareRoot()
+                let s = t * c
+
+                // Rotation P with P(p,p) = P(q,q) = c, P(p,q) = s, P(q,p) = -s (subscripts are [column][row])
+                var rotation = matrix_identity_double3x3
+                rotation[p][p] = c
+                rotation[q][q] = c
+                rotation[q][p] = s
+                rotation[p][q] = -s
+
+                a = rotation.transpose * a * rotation
+                v = v * rotation
+            }
+        }
+
+        return ([a[0][0], a[1][1], a[2][2]], [v[0], v[1], v[2]])
+    }
+}
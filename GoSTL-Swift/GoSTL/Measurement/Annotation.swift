@@ -0,0 +1,53 @@
+import Foundation
+
+/// Text pinned to a point of the model (e.g. "crack here" during a review), separate from measurements
+///
+/// Anchors are stored in the file's original coordinates so annotations stay in place when the
+/// model is reloaded and recentered. Saved in the model's `.gostl` sidecar.
+struct Annotation: Codable, Equatable, Identifiable {
+    var id = UUID()
+    var text: String
+    /// Anchor on the surface in original coordinates
+    var position: Vector3
+    /// Surface normal at the anchor (zero for points in the air)
+    var normal: Vector3
+    var createdAt: Date
+
+    private enum CodingKeys: String, CodingKey {
+        case text, position, normal, createdAt
+    }
+
+    init(text: String, position: Vector3, normal: Vector3 = .zero, createdAt: Date = Date()) {
+        self.text = text
+        self.position = position
+        self.normal = normal
+        self.createdAt = createdAt
+    }
+
+    /// Annotation at a picked point (render space)
+    init(text: String, at point: MeasurementPoint, coordinateOffset: Vector3) {
+        self.init(text: text, position: point.position + coordinateOffset, normal: point.normal)
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        text = try container.decode(String.self, forKey: .text)
+        position = try container.decode(Vector3.self, forKey: .position)
+        normal = try container.decodeIfPresent(Vector3.self, forKey: .normal) ?? .zero
+        createdAt = try container.decodeIfPresent(Date.self, forKey: .createdAt) ?? Date(timeIntervalSince1970: 0)
+    }
+
+    /// Anchor in render space
+    func renderPosition(coordinateOffset: Vector3) -> Vector3 {
+        position - coordinateOffset
+    }
+
+    /// One line for reports, e.g. `"crack here" at (12.500, 3.000, 40.250)`
+    var summary: String {
+        summary(precision: .report)
+    }
+
+    func summary(precision: DisplayPrecision) -> String {
+        "\"\(text)\" at \(precision.point(position))"
+    }
+}
@@ -0,0 +1,36 @@
+import Foundation
+
+/// Overall X/Y/Z dimensions of a model, placed like drawing dimensions around its bounding box
+///
+/// Each dimension is a distance measurement whose line runs parallel to a bounding box edge,
+/// offset outward so it does not cross the model, with extension lines back to the box corners:
+/// width in front (-Y), depth on the right (+X) and height on the front-left edge (-X).
+enum BoundingDimensions {
+    /// Distance of the dimension lines from the bounding box, relative to its largest extent
+    static let offsetFraction = 0.1
+
+    /// Dimensions for a bounding box (render space); axes without extent are left out
+    static func measurements(for box: BoundingBox) -> [Measurement] {
+        let size = box.size
+        let largest = max(size.x, size.y, size.z)
+        guard largest > 0 else { return [] }
+        let gap = largest * offsetFraction
+
+        let (lo, hi) = (box.min, box.max)
+        let dimensions: [(extent: Double, anchors: [Vector3], offset: Vector3)] = [
+            (size.x, [Vector3(lo.x, lo.y, lo.z), Vector3(hi.x, lo.y, lo.z)], Vector3(0, -gap, 0)),
+            (size.y, [Vector3(hi.x, lo.y, lo.z), Vector3(hi.x, hi.y, lo.z)], Vector3(gap, 0, 0)),
+            (size.z, [Vector3(lo.x, lo.y, lo.z), Vector3(lo.x, lo.y, hi.z)], Vector3(-gap, 0, 0))
+        ]
+
+        return dimensions.filter { $0.extent > 0 }.map { dimension in
+            let normal = dimension.offset.normalized()
+            let points = dimension.anchors.map {
+                MeasurementPoint(position: $0 + dimension.offset, normal: normal, isAirPoint: true)
+            }
+            var measurement = Measurement(type: .distance, points: points, value: dimension.extent)
+            measurement.extensionAnchors = dimension.anchors
+            return measurement
+        }
+    }
+}
@@ -0,0 +1,142 @@
+import Foundation
+
+/// Virtual caliper: two parallel jaws across a direction that close onto the model between them
+///
+/// Like measuring the physical part, the reading needs no picked points: each jaw moves inward from
+/// where it was placed until it touches the outermost surface between the jaws, so rounded faces are
+/// measured at their crest instead of at whichever vertex happened to be clicked. Surface that
+/// reaches past a jaw stops it where it is.
+struct Caliper: Equatable {
+    enum Direction: Equatable {
+        case x, y, z
+        /// Normal of a face (unit length)
+        case normal(Vector3)
+
+        var vector: Vector3 {
+            switch self {
+            case .x: return .unitX
+            case .y: return .unitY
+            case .z: return .unitZ
+            case .normal(let normal): return normal
+            }
+        }
+
+        var displayName: String {
+            switch self {
+            case .x: return "X"
+            case .y: return "Y"
+            case .z: return "Z"
+            case .normal: return "Normal"
+            }
+        }
+
+        /// Axis directions by name ("x", "y", "z"), e.g. from menu notifications
+        init?(axis name: String) {
+            switch name {
+            case "x": self = .x
+            case "y": self = .y
+            case "z": self = .z
+            default: return nil
+            }
+        }
+    }
+
+    /// Where the jaws touch the model
+    struct Reading: Equatable {
+        /// Seated jaw positions along the direction
+        let low: Double
+        let high: Double
+        /// Surface points the jaws rest on
+        let lowContact: Vector3
+        let highContact: Vector3
+
+        var distance: Double {
+            high - low
+        }
+    }
+
+    var direction: Direction
+    /// Positions of the open jaws along the direction (render space, mm)
+    var opening: ClosedRange<Double>
+    /// Range of the model along the direction (where the jaws can be placed)
+    var extent: ClosedRange<Double>
+
+    /// Caliper opened around the whole model; nil for an empty model
+    init?(direction: Direction, triangles: [Triangle]) {
+        guard let extent = Self.extent(of: triangles, along: direction.vector) else { return nil }
+        self.direction = direction
+        self.opening = extent
+        self.extent = extent
+    }
+
+    /// Where the jaws of this caliper seat on the model
+    func reading(of triangles: [Triangle]) -> Reading? {
+        Self.reading(of: triangles, along: direction.vector, between: opening)
+    }
+
+    // MARK: - Seating
+
+    /// Range of the model along a direction: the jaws fully open
+    static func extent(of triangles: [Triangle], along direction: Vector3) -> ClosedRange<Double>? {
+        var low = Double.infinity
+        var high = -Double.infinity
+        for triangle in triangles {
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                let height = vertex.dot(direction)
+                low = min(low, height)
+                high = max(high, height)
+            }
+        }
+        return low <= high ? low...high : nil
+    }
+
+    /// Outermost surface points between the jaws, nil if no surface lies between them
+    ///
+    /// The surface between the jaws is each triangle clipped to the slab; its extremes are vertices
+    /// inside the slab or points where edges cross a jaw.
+    static func reading(of triangles: [Triangle], along direction: Vector3, between opening: ClosedRange<Double>) -> Reading? {
+        var low: (height: Double, point: Vector3)?
+        var high: (height: Double, point: Vector3)?
+        func consider(_ height: Double, _ point: Vector3) {
+            if low == nil || height < low!.height {
+                low = (height, point)
+            }
+            if high == nil || height > high!.height {
+                high = (height, point)
+            }
+        }
+
+        for triangle in triangles {
+            let vertices = [triangle.v1, triangle.v2, triangle.v3]
+            let heights = vertices.map { $0.dot(direction) }
+            guard heights.max()! >= opening.lowerBound, heights.min()! <= opening.upperBound else { continue }
+
+            for index in 0..<3 {
+                let (a, b) = (vertices[index], vertices[(index + 1) % 3])
+                let (ha, hb) = (heights[index], heights[(index + 1) % 3])
+                if opening.contains(ha) {
+                    consider(ha, a)
+                }
+                // Edge crossing a jaw
+                for jaw in [opening.lowerBound, opening.upperBound] where (ha - jaw) * (hb - jaw) < 0 {
+                    let t = (jaw - ha) / (hb - ha)
+                    consider(jaw, a + (b - a) * t)
+                }
+            }
+        }
+
+        guard let low, let high else { return nil }
+        return Reading(low: low.height, high: high.height, lowContact: low.point, highContact: high.point)
+    }
+
+    /// Distance measurement of a reading: from the low contact across to the high jaw
+    static func measurement(for reading: Reading, along direction: Vector3) -> Measurement {
+        let points = [
+            MeasurementPoint(position: reading.lowContact, normal: -direction),
+            MeasurementPoint(position: reading.lowContact + direction * reading.distance, normal: direction, isAirPoint: true)
+        ]
+        var measurement = Measurement(type: .distance, points: points, value: reading.distance)
+        measurement.note = "Caliper"
+        return measurement
+    }
+}
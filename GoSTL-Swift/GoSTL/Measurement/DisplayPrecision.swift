@@ -0,0 +1,167 @@
+import Foundation
+
+/// Decimal places, unit and notation of displayed lengths and angles (labels, panels and reports)
+///
+/// Set in Settings > Display; a measurement can override the decimals for its own value, e.g.
+/// three decimals for a ±0.05 mm fit while everything else shows two. Model files are in
+/// millimeters; lengths are converted to `unit` for display.
+struct DisplayPrecision: Codable, Equatable, Sendable {
+    var lengthDecimals: Int
+    var angleDecimals: Int
+    var unit: LengthUnit
+    /// Mantissa and a power of ten in steps of three, e.g. "12.35e3"
+    var engineeringNotation: Bool
+
+    /// Decimal places offered in the settings and reachable from the context menu
+    static let decimalRange = 0...6
+
+    /// Two decimals for millimeters, one for degrees
+    static let standard = DisplayPrecision(lengthDecimals: 2, angleDecimals: 1)
+
+    /// Inspection reports list lengths to the micrometer
+    static let report = DisplayPrecision(lengthDecimals: 3, angleDecimals: 2)
+
+    init(lengthDecimals: Int, angleDecimals: Int, unit: LengthUnit = .millimeters, engineeringNotation: Bool = false) {
+        self.lengthDecimals = Self.clamped(lengthDecimals)
+        self.angleDecimals = Self.clamped(angleDecimals)
+        self.unit = unit
+        self.engineeringNotation = engineeringNotation
+    }
+
+    static func clamped(_ decimals: Int) -> Int {
+        Swift.min(Swift.max(decimals, decimalRange.lowerBound), decimalRange.upperBound)
+    }
+
+    /// A length (in millimeters) in the display unit without the unit, e.g. "12.50"
+    func length(_ value: Double) -> String {
+        let scaled = value / unit.millimeters
+        return engineeringNotation ? Self.engineering(scaled, decimals: lengthDecimals) : number(scaled)
+    }
+
+    /// A length with the unit symbol, e.g. "12.50 mm"
+    func lengthWithUnit(_ value: Double) -> String {
+        "\(length(value)) \(unit.symbol)"
+    }
+
+    /// A length for measurement labels: millimeters are implied, other units are shown, e.g. "80.00 µm"
+    func labelLength(_ value: Double) -> String {
+        unit == .millimeters ? length(value) : lengthWithUnit(value)
+    }
+
+    /// A number that is not a length (directions, counts, volumes) with the length decimals
+    func number(_ value: Double) -> String {
+        String(format: "%.*f", Int32(lengthDecimals), value)
+    }
+
+    /// An angle with degree sign, e.g. "45.0°"
+    func angle(_ value: Double) -> String {
+        String(format: "%.*f°", Int32(angleDecimals), value)
+    }
+
+    /// Coordinates in the display unit without the unit, e.g. "(1.00, 2.00, 3.00)"
+    func point(_ point: Vector3) -> String {
+        "(\(length(point.x)), \(length(point.y)), \(length(point.z)))"
+    }
+
+    /// A unit vector (normal, direction), never rescaled
+    func direction(_ direction: Vector3) -> String {
+        "(\(number(direction.x)), \(number(direction.y)), \(number(direction.z)))"
+    }
+
+    /// The same precision with a measurement's own decimal places for both lengths and angles
+    func overridden(by decimals: Int?) -> DisplayPrecision {
+        guard let decimals else { return self }
+        var precision = self
+        precision.lengthDecimals = Self.clamped(decimals)
+        precision.angleDecimals = Self.clamped(decimals)
+        return precision
+    }
+
+    /// Engineering notation: the exponent is a multiple of three, the mantissa between 1 and 1000
+    static func engineering(_ value: Double, decimals: Int) -> String {
+        // Below a femtometer is floating point noise, not geometry
+        guard value.isFinite, abs(value) >= 1e-12 else {
+            return String(format: "%.*fe0", Int32(decimals), 0.0)
+        }
+        var exponent = Int((log10(abs(value)) / 3).rounded(.down)) * 3
+        var mantissa = value / pow(10, Double(exponent))
+        // Rounding can carry the mantissa to 1000 (999.996 with two decimals)
+        let factor = pow(10, Double(decimals))
+        if abs((mantissa * factor).rounded() / factor) >= 1000 {
+            exponent += 3
+            mantissa /= 1000
+        }
+        return String(format: "%.*fe%d", Int32(decimals), mantissa, exponent)
+    }
+}
+
+/// Unit of displayed lengths
+enum LengthUnit: String, Codable, CaseIterable, Sendable {
+    case micrometers, millimeters, meters
+
+    /// Length of one unit in millimeters
+    var millimeters: Double {
+        switch self {
+        case .micrometers: return 0.001
+        case .millimeters: return 1
+        case .meters: return 1000
+        }
+    }
+
+    var symbol: String {
+        switch self {
+        case .micrometers: return "µm"
+        case .millimeters: return "mm"
+        case .meters: return "m"
+        }
+    }
+
+    /// Unit name for the screen reader, e.g. "millimeters"
+    var spokenName: String {
+        rawValue
+    }
+}
+
+/// How lengths are shown (Settings > Display)
+enum LengthScale: String, Codable, CaseIterable, Sendable {
+    /// The unit that suits the loaded model
+    case automatic
+    case micrometers
+    case millimeters
+    case meters
+    /// Millimeters in engineering notation
+    case engineering
+
+    var displayName: String {
+        switch self {
+        case .automatic: return "Automatic"
+        case .micrometers: return "Micrometers (µm)"
+        case .millimeters: return "Millimeters (mm)"
+        case .meters: return "Meters (m)"
+        case .engineering: return "Engineering Notation (mm)"
+        }
+    }
+
+    /// Unit and notation for a model
+    ///
+    /// Automatic uses micrometers for models smaller than 1 mm and meters for models of 10 m and
+    /// more; beyond that range (below 1 µm or from 10 km) it also switches to engineering notation.
+    /// - Parameter extent: Largest dimension of the model in millimeters (nil: no model loaded)
+    func resolved(forExtent extent: Double?) -> (unit: LengthUnit, engineeringNotation: Bool) {
+        switch self {
+        case .micrometers: return (.micrometers, false)
+        case .millimeters: return (.millimeters, false)
+        case .meters: return (.meters, false)
+        case .engineering: return (.millimeters, true)
+        case .automatic:
+            guard let extent, extent > 0, extent.isFinite else { return (.millimeters, false) }
+            if extent < 1 {
+                return (.micrometers, extent < 0.001)
+            }
+            if extent >= 10_000 {
+                return (.meters, extent >= 10_000_000)
+            }
+            return (.millimeters, false)
+        }
+    }
+}
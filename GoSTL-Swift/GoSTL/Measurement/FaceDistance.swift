@@ -0,0 +1,141 @@
+import Foundation
+
+/// Distance from a picked flat face to the parallel face opposite it, e.g. the thickness of a plate
+/// or the width of a slot, from a single click
+///
+/// Rays are cast from the picked point along the face normal: inward through the material and
+/// outward across the gap. The first face a ray hits must be antiparallel to the picked face,
+/// otherwise that side has no opposite face. The distance is taken between the two face planes
+/// (area-weighted over the whole faces), so it does not depend on where exactly the click landed.
+enum FaceDistance {
+    /// Which side of the picked face to measure
+    enum Side: String, CaseIterable, Identifiable {
+        /// The closer of the two
+        case nearest
+        /// Through the material behind the face (thickness)
+        case material
+        /// Across the air in front of the face (slot width, gap)
+        case gap
+
+        var id: String { rawValue }
+
+        var title: String {
+            switch self {
+            case .nearest: return "Nearest"
+            case .material: return "Material"
+            case .gap: return "Gap"
+            }
+        }
+    }
+
+    struct Result: Equatable {
+        /// Clicked point on the picked face
+        let entry: Vector3
+        /// Where the normal through the clicked point meets the opposite face plane
+        let exit: Vector3
+        /// Outward normal of the picked face
+        let normal: Vector3
+        /// Whether the opposite face lies across the material (true) or across a gap (false)
+        let isThickness: Bool
+        let distance: Double
+        /// Angle between the picked face and the opposite face (degrees)
+        let parallelism: Double
+        let pickedArea: Double
+        let oppositeArea: Double
+    }
+
+    /// Faces whose normals are within this many degrees of opposite count as parallel
+    static let parallelTolerance = 1.0
+
+    /// Start the rays just off the surface so they do not hit the picked face again
+    static let startOffset: Float = 1e-4
+
+    /// Measure from the face under a view ray (e.g. a click)
+    /// - Returns: nil if the ray misses the model or no parallel face is opposite on the requested side
+    static func measure(
+        ray: Ray,
+        triangles: [Triangle],
+        adjacency: TriangleAdjacency,
+        side: Side = .nearest,
+        accelerator: SpatialAccelerator? = nil
+    ) -> Result? {
+        guard let hit = ThicknessProbe.closestHit(ray: ray, triangles: triangles, accelerator: accelerator),
+              let picked = SurfaceRegion.grow(from: hit.triangleIndex, kind: .flatFace, in: triangles, adjacency: adjacency) else {
+            return nil
+        }
+
+        let normal = picked.normal
+        let pickedOffset = planeOffset(of: picked, normal: normal, triangles: triangles)
+        let sides = side == .nearest ? [true, false] : [side == .material]
+
+        var best: (face: SurfaceRegion, isThickness: Bool, distance: Double)?
+        for isThickness in sides {
+            let direction = isThickness ? -normal : normal
+            guard let face = opposite(of: picked, at: hit.position, direction: direction, triangles: triangles, adjacency: adjacency, accelerator: accelerator) else { continue }
+            let distance = abs(planeOffset(of: face, normal: normal, triangles: triangles) - pickedOffset)
+            if distance < best?.distance ?? .infinity {
+                best = (face, isThickness, distance)
+            }
+        }
+        guard let best else { return nil }
+
+        // The clicked point moved onto the averaged plane of its face
+        let entry = hit.position - normal * (normal.dot(hit.position) - pickedOffset)
+        let cosine = min(max(-normal.dot(best.face.normal), -1), 1)
+        return Result(
+            entry: entry,
+            exit: entry + (best.isThickness ? -normal : normal) * best.distance,
+            normal: normal,
+            isThickness: best.isThickness,
+            distance: best.distance,
+            parallelism: acos(cosine) * 180 / .pi,
+            pickedArea: picked.area,
+            oppositeArea: best.face.area
+        )
+    }
+
+    /// Distance measurement from the picked face to the opposite face
+    static func measurement(for result: Result) -> Measurement {
+        let points = [
+            MeasurementPoint(position: result.entry, normal: result.normal),
+            MeasurementPoint(position: result.exit, normal: -result.normal)
+        ]
+        var measurement = Measurement(type: .distance, points: points, value: result.distance)
+        measurement.note = result.isThickness ? "Thickness" : "Gap"
+        return measurement
+    }
+
+    /// The flat face first hit from a point in a direction, if it faces back towards the picked face
+    private static func opposite(
+        of picked: SurfaceRegion,
+        at position: Vector3,
+        direction: Vector3,
+        triangles: [Triangle],
+        adjacency: TriangleAdjacency,
+        accelerator: SpatialAccelerator?
+    ) -> SurfaceRegion? {
+        let unit = direction.float3
+        let ray = Ray(origin: position.float3 + unit * startOffset, direction: unit)
+        guard let hit = ThicknessProbe.closestHit(ray: ray, triangles: triangles, accelerator: accelerator),
+              !picked.contains(hit.triangleIndex),
+              let face = SurfaceRegion.grow(from: hit.triangleIndex, kind: .flatFace, in: triangles, adjacency: adjacency),
+              -face.normal.dot(picked.normal) >= cos(parallelTolerance * .pi / 180) else {
+            return nil
+        }
+        return face
+    }
+
+    /// Area-weighted offset of a face's triangles along a normal (normal · point)
+    private static func planeOffset(of face: SurfaceRegion, normal: Vector3, triangles: [Triangle]) -> Double {
+        var weighted = 0.0
+        var area = 0.0
+        for index in face.triangles {
+            let triangle = triangles[index]
+            let triangleArea = triangle.area()
+            let centroid = (triangle.v1 + triangle.v2 + triangle.v3) / 3
+            weighted += normal.dot(centroid) * triangleArea
+            area += triangleArea
+        }
+        return area > 0 ? weighted / area : normal.dot(triangles[face.triangles[0]].v1)
+    }
+}
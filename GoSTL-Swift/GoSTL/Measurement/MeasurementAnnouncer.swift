@@ -0,0 +1,73 @@
+import AppKit
+import Foundation
+import Observation
+
+/// Reports measurement results in text form for users who can't rely on the 3D labels
+/// Every message is kept in a log; when announcements are enabled it is also
+/// spoken through the macOS accessibility API (VoiceOver)
+@Observable
+final class MeasurementAnnouncer: @unchecked Sendable {
+    /// A single log entry
+    struct Entry: Identifiable {
+        let id = UUID()
+        let date: Date
+        let text: String
+    }
+
+    /// Whether messages are announced via the accessibility API
+    var isEnabled: Bool = false
+
+    /// Messages in the order they were reported (oldest first)
+    private(set) var entries: [Entry] = []
+
+    /// Maximum number of entries kept in the log
+    static let maxEntries = 200
+
+    /// Announce a completed measurement
+    func announce(_ measurement: Measurement, showDiameter: Bool, precision: DisplayPrecision = .standard) {
+        announce(measurement.spokenDescription(showDiameter: showDiameter, precision: precision))
+    }
+
+    /// Announce a snap candidate while cycling through them with the keyboard
+    func announceCandidate(_ point: MeasurementPoint, index: Int, count: Int, coordinateOffset: Vector3 = .zero) {
+        let kind = point.isAirPoint ? "Surface point" : "Vertex"
+        let position = point.position + coordinateOffset
+        announce(String(format: "%@ %d of %d, X %.2f, Y %.2f, Z %.2f", kind, index + 1, count, position.x, position.y, position.z))
+    }
+
+    /// Add a message to the log and post it to the accessibility API if enabled
+    func announce(_ text: String) {
+        guard !text.isEmpty else { return }
+
+        entries.append(Entry(date: Date(), text: text))
+        if entries.count > Self.maxEntries {
+            entries.removeFirst(entries.count - Self.maxEntries)
+        }
+
+        guard isEnabled else { return }
+        Task { @MainActor in
+            // Announcements must be posted on a UI element; the key window is the natural source
+            guard let element = NSApp.keyWindow ?? NSApp.mainWindow else { return }
+            NSAccessibility.post(
+                element: element,
+                notification: .announcementRequested,
+                userInfo: [
+                    .announcement: text,
+                    .priority: NSAccessibilityPriorityLevel.high.rawValue
+                ]
+            )
+        }
+    }
+
+    /// Clear the log
+    func clear() {
+        entries.removeAll()
+    }
+
+    /// The log as plain text (one line per entry)
+    var logText: String {
+        let formatter = DateFormatter()
+        formatter.dateFormat = "HH:mm:ss"
+        return entries.map { "\(formatter.string(from: $0.date))  \($0.text)" }.joined(separator: "\n")
+    }
+}
@@ -0,0 +1,139 @@
+import Foundation
+
+/// Per-measurement value changes between two measurement sets (e.g. two model revisions)
+struct MeasurementComparison {
+    enum Status: String {
+        case unchanged
+        case changed
+        /// Change exceeds the threshold
+        case exceeded
+        /// Only in the current set
+        case added
+        /// Only in the baseline set
+        case removed
+    }
+
+    struct Row: Identifiable {
+        var id: String { name }
+        let name: String
+        let type: MeasurementType
+        let baseline: Double?
+        let current: Double?
+        let status: Status
+        /// Points of the current measurement that could not be re-bound to a vertex
+        let staleCount: Int
+
+        /// Current minus baseline value
+        var delta: Double? {
+            guard let baseline, let current else { return nil }
+            return current - baseline
+        }
+
+        var unit: String {
+            type == .angle ? "°" : "mm"
+        }
+    }
+
+    /// Distance/radius changes below this are ignored (mm)
+    static let defaultThreshold = 0.1
+    /// Angle changes below this are ignored (degrees)
+    static let defaultAngleThreshold = 0.5
+
+    let baselineName: String
+    let currentName: String
+    let threshold: Double
+    let angleThreshold: Double
+    let rows: [Row]
+
+    /// Compare measurements by name
+    static func compare(
+        baseline: MeasurementSet,
+        current: MeasurementSet,
+        threshold: Double = defaultThreshold,
+        angleThreshold: Double = defaultAngleThreshold
+    ) -> MeasurementComparison {
+        let currentByName = Dictionary(current.entries.map { ($0.name, $0.measurement) }, uniquingKeysWith: { first, _ in first })
+        let baselineNames = Set(baseline.entries.map(\.name))
+        var rows: [Row] = []
+
+        for entry in baseline.entries {
+            guard let measurement = currentByName[entry.name] else {
+                rows.append(Row(name: entry.name, type: entry.measurement.type, baseline: entry.measurement.value,
+                                current: nil, status: .removed, staleCount: 0))
+                continue
+            }
+            let delta = abs(measurement.value - entry.measurement.value)
+            let limit = entry.measurement.type == .angle ? angleThreshold : threshold
+            let status: Status
+            if delta > limit {
+                status = .exceeded
+            } else if delta > 1e-9 {
+                status = .changed
+            } else {
+                status = .unchanged
+            }
+            rows.append(Row(name: entry.name, type: entry.measurement.type, baseline: entry.measurement.value,
+                            current: measurement.value, status: status, staleCount: measurement.stalePointIndices.count))
+        }
+
+        for entry in current.entries where !baselineNames.contains(entry.name) {
+            rows.append(Row(name: entry.name, type: entry.measurement.type, baseline: nil,
+                            current: entry.measurement.value, status: .added, staleCount: 0))
+        }
+
+        return MeasurementComparison(
+            baselineName: baseline.fileName ?? "baseline",
+            currentName: current.fileName ?? "current",
+            threshold: threshold,
+            angleThreshold: angleThreshold,
+            rows: rows
+        )
+    }
+
+    /// Rows whose change exceeds the threshold
+    var exceeded: [Row] {
+        rows.filter { $0.status == .exceeded }
+    }
+
+    // MARK: - Formatting
+
+    /// Aligned plain-text diff table
+    func table() -> String {
+        func value(_ value: Double?) -> String {
+            value.map { String(format: "%.3f", $0) } ?? "-"
+        }
+
+        let header = ["Measurement", baselineName, currentName, "Delta", "Status"]
+        let body = rows.map { row -> [String] in
+            let delta = row.delta.map { String(format: "%+.3f %@", $0, row.unit) } ?? "-"
+            let status = row.staleCount > 0 ? "\(row.status.rawValue) (\(row.staleCount) stale)" : row.status.rawValue
+            return [row.name, value(row.baseline), value(row.current), delta, status]
+        }
+        let widths = header.indices.map { column in
+            ([header] + body).map { $0[column].count }.max() ?? 0
+        }
+        let lines = ([header] + body).map { cells in
+            cells.enumerated()
+                .map { $1.padding(toLength: widths[$0], withPad: " ", startingAt: 0) }
+                .joined(separator: "  ")
+                .trimmingCharacters(in: .whitespaces)
+        }
+
+        let summary = String(format: "%d of %d measurement(s) exceed the threshold (%.3f mm, %.2f°)",
+                             exceeded.count, rows.count, threshold, angleThreshold)
+        return (lines + ["", summary]).joined(separator: "\n")
+    }
+
+    /// CSV with one row per measurement
+    func csv(format: CSVFormat = .standard) -> String {
+        func value(_ value: Double?) -> String {
+            value.map { format.number($0, decimals: 6) } ?? ""
+        }
+
+        let header = format.row(["name", "unit", "baseline", "current", "delta", "status", "stale_points"])
+        let lines = rows.map { row in
+            format.row([row.name, row.unit, value(row.baseline), value(row.current), value(row.delta), row.status.rawValue, String(row.staleCount)])
+        }
+        return ([header] + lines).joined(separator: "\n") + "\n"
+    }
+}
@@ -0,0 +1,105 @@
+import Foundation
+
+/// Exported measurements of a model revision, used to compare revisions
+///
+/// Stored as JSON with all points in the file's original coordinates.
+/// Entries are named per type in order ("Distance 1", "Angle 1", ...) so the same
+/// annotations match up between exports.
+struct MeasurementSet: Codable {
+    struct Entry: Codable {
+        var name: String
+        var measurement: Measurement
+    }
+
+    static let currentVersion = 1
+
+    var version: Int = MeasurementSet.currentVersion
+    /// Name of the model the measurements were taken on
+    var fileName: String?
+    var date: Date
+    var entries: [Entry]
+    /// Pinned text annotations (in original coordinates), omitted from files without any
+    var annotations: [Annotation]?
+
+    init(fileName: String?, measurements: [Measurement], annotations: [Annotation] = [], date: Date = Date()) {
+        self.fileName = fileName
+        self.date = date
+        self.entries = Self.names(for: measurements).enumerated().map { index, name in
+            Entry(name: name, measurement: measurements[index])
+        }
+        self.annotations = annotations.isEmpty ? nil : annotations
+    }
+
+    init(fileName: String?, entries: [Entry], annotations: [Annotation]? = nil, date: Date = Date()) {
+        self.fileName = fileName
+        self.date = date
+        self.entries = entries
+        self.annotations = annotations
+    }
+
+    /// Names numbered per measurement type ("Distance 1", "Distance 2", "Angle 1", ...)
+    static func names(for measurements: [Measurement]) -> [String] {
+        var counts: [MeasurementType: Int] = [:]
+        return measurements.map { measurement in
+            counts[measurement.type, default: 0] += 1
+            return "\(measurement.label) \(counts[measurement.type]!)"
+        }
+    }
+
+    // MARK: - Re-evaluation
+
+    /// The same annotations re-evaluated on another revision of the model
+    ///
+    /// Points that were snapped to vertices are re-bound to the closest vertex of `model`
+    /// within `searchRadius`; points without a vertex nearby keep their position and are
+    /// reported as stale. Air points are kept as they are. Values are recalculated.
+    func reevaluated(on model: STLModel, fileName: String? = nil, searchRadius: Double = 0.5) -> MeasurementSet {
+        let accelerator = SpatialAccelerator(triangles: model.triangles)
+
+        let reevaluatedEntries = entries.map { entry -> Entry in
+            var staleIndices: Set<Int> = []
+            let points = entry.measurement.points.enumerated().map { index, point -> MeasurementPoint in
+                guard !point.isAirPoint else { return point }
+                guard let vertex = accelerator.findClosestVertex(to: point.position, maxDistance: searchRadius) else {
+                    staleIndices.insert(index)
+                    return point
+                }
+                return MeasurementPoint(position: vertex, normal: point.normal, isAirPoint: false)
+            }
+
+            let result = MeasurementSystem.calculateValue(type: entry.measurement.type, points: points)
+            var measurement = Measurement(type: entry.measurement.type, points: points, value: result.value, circle: result.circle)
+            measurement.stalePointIndices = staleIndices
+            measurement.createdAt = entry.measurement.createdAt
+            measurement.note = entry.measurement.note
+            measurement.decimals = entry.measurement.decimals
+            measurement.extensionAnchors = entry.measurement.extensionAnchors
+            return Entry(name: entry.name, measurement: measurement)
+        }
+
+        return MeasurementSet(fileName: fileName ?? model.name, entries: reevaluatedEntries, annotations: annotations)
+    }
+
+    // MARK: - File I/O
+
+    func write(to url: URL) throws {
+        try encoded().write(to: url)
+    }
+
+    static func load(from url: URL) throws -> MeasurementSet {
+        try decode(Data(contentsOf: url))
+    }
+
+    func encoded() throws -> Data {
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+        encoder.dateEncodingStrategy = .iso8601
+        return try encoder.encode(self)
+    }
+
+    static func decode(_ data: Data) throws -> MeasurementSet {
+        let decoder = JSONDecoder()
+        decoder.dateDecodingStrategy = .iso8601
+        return try decoder.decode(MeasurementSet.self, from: data)
+    }
+}
@@ -6,6 +6,7 @@ import simd
 enum ConstraintType {
     case axis(Int)  // 0=X, 1=Y, 2=Z
     case point(Vector3)  // Constrains to direction towards this point
+    case direction(Vector3)  // Constrains along a unit direction (reference axis or plane normal)
 }
 
 /// Manages measurement state and calculations
@@ -20,11 +21,25 @@ final class MeasurementSystem: @unchecked Sendable {
     /// Completed measurements
     var measurements: [Measurement] = []
 
+    /// Called when the user adds a measurement (not for restored, replayed or scripted ones)
+    @ObservationIgnored
+    var onMeasurementAdded: ((Measurement) -> Void)?
+
     /// Hover point (preview of where next point would be picked)
     var hoverPoint: MeasurementPoint?
 
     /// Active constraint for measurement (nil = no constraint)
-    var constraint: ConstraintType?
+    var constraint: ConstraintType? {
+        didSet {
+            if constraint == nil {
+                lengthInput = nil
+            }
+        }
+    }
+
+    /// Length typed while a constraint is active (nil = not typing)
+    /// The second point is placed at this distance along the constraint when Enter is pressed.
+    var lengthInput: String?
 
     /// The constrained endpoint position (where the measurement line will actually end)
     /// This is calculated based on the constraint axis
@@ -36,6 +51,17 @@ final class MeasurementSystem: @unchecked Sendable {
     /// Set of selected measurement indices
     var selectedMeasurements: Set<Int> = []
 
+    /// Screen frames (top-left origin) of the measurement labels as last laid out, by measurement index
+    @ObservationIgnored
+    var labelFrames: [Int: CGRect] = [:]
+
+    /// Size of the view (in points) the label frames were laid out in
+    @ObservationIgnored
+    var labelFramesViewSize: CGSize = .zero
+
+    /// Measurement whose label is under the mouse (shows the details tooltip)
+    var hoveredLabel: Int?
+
     /// Selection rectangle (in screen coordinates) - nil when not selecting
     var selectionRect: (start: CGPoint, end: CGPoint)?
 
@@ -57,6 +83,93 @@ final class MeasurementSystem: @unchecked Sendable {
     /// Whether painting to unselect (Cmd+Shift) instead of select (Cmd)
     var isPaintingToUnselect: Bool = false
 
+    /// Snap candidates for keyboard-driven picking (cycled with Tab)
+    var snapCandidates: [MeasurementPoint] = []
+
+    /// Index of the currently highlighted snap candidate
+    var snapCandidateIndex: Int = 0
+
+    /// Maximum number of snap candidates offered for keyboard picking
+    static let maxSnapCandidates = 16
+
+    /// Snap picked points to the grid instead of mesh vertices
+    var gridSnapEnabled: Bool = false
+
+    /// Whether the grid snap modifier (Control) is held, which inverts gridSnapEnabled
+    var gridSnapModifierHeld: Bool = false
+
+    /// Spacing of the displayed grid in mm (kept in sync with the grid by AppState)
+    var gridSnapSpacing: Double = 10.0
+
+    /// Offset subtracted from file coordinates (see `AppState.coordinateOffset`); points snap to round file coordinates
+    var gridSnapOffset: Vector3 = .zero
+
+    /// The displayed bottom grid, used for picking on-grid points off the model (nil without a grid)
+    var gridSnapPlane: GridPlane?
+
+    /// Extent of the bottom grid in render coordinates
+    struct GridPlane: Equatable {
+        var z: Double
+        var minX: Double
+        var maxX: Double
+        var minY: Double
+        var maxY: Double
+
+        func contains(x: Double, y: Double) -> Bool {
+            (minX...maxX).contains(x) && (minY...maxY).contains(y)
+        }
+    }
+
+    /// Whether picked points are currently snapped to the grid
+    var isGridSnapActive: Bool {
+        gridSnapEnabled != gridSnapModifierHeld
+    }
+
+    /// Refine picked surface points by fitting the local surface (plane, edge or corner)
+    var refinePicks: Bool = false
+
+    /// Refinement of the most recently picked point, if it was refined
+    private(set) var pickRefinement: PickRefinement?
+
+    /// Reference entities that picked points snap to (kept in sync by AppState)
+    @ObservationIgnored
+    var referenceEntities: [ReferenceEntity] = []
+
+    /// Minimum distance between the mouse ray and a reference entity for snapping (mm)
+    static let referenceSnapThreshold: Double = 2.0
+
+    /// Vertex snap distance when no view has set the snap radius yet (mm)
+    static let defaultVertexSnapThreshold: Double = 2.0
+
+    /// Eye position in model coordinates and the vertex snap distance per mm of distance from it
+    /// Kept in sync by AppState every frame, so vertices snap within the same radius on screen at any zoom
+    @ObservationIgnored
+    var snapEye: Vector3?
+    @ObservationIgnored
+    var vertexSnapPerDistance: Double = 0
+
+    /// Geometry that picked points snap to (the selection filter)
+    var snapTargets: Set<SnapTarget> = Set(SnapTarget.allCases)
+
+    /// Vertices of the section outlines while slicing (kept in sync by AppState)
+    @ObservationIgnored
+    var cutVertices: [Vector3] = []
+
+    /// Turn snapping to a kind of geometry on or off
+    func toggleSnapTarget(_ target: SnapTarget) {
+        if snapTargets.contains(target) {
+            snapTargets.remove(target)
+        } else {
+            snapTargets.insert(target)
+        }
+    }
+
+    /// Distance within which a surface hit snaps to the closest mesh vertex (mm)
+    func vertexSnapThreshold(at position: Vector3) -> Double {
+        guard let snapEye else { return Self.defaultVertexSnapThreshold }
+        return snapEye.distance(to: position) * vertexSnapPerDistance
+    }
+
     /// Number of points required for current mode
     var pointsNeeded: Int {
         guard let mode else { return 0 }
@@ -105,6 +218,7 @@ final class MeasurementSystem: @unchecked Sendable {
         hoverPoint = nil
         constraint = nil
         constrainedEndpoint = nil
+        snapCandidates = []
     }
 
     /// Update hover point based on mouse position
@@ -112,9 +226,11 @@ final class MeasurementSystem: @unchecked Sendable {
         guard isCollecting, let model else {
             hoverPoint = nil
             constrainedEndpoint = nil
+            pickRefinement = nil
             return
         }
-        hoverPoint = findIntersection(ray: ray, model: model, accelerator: accelerator)
+        hoverPoint = pickPoint(ray: ray, model: model, accelerator: accelerator)
+        snapCandidates = []
 
         // Update constrained endpoint if constraint is active
         updateConstrainedMeasurement()
@@ -133,9 +249,9 @@ final class MeasurementSystem: @unchecked Sendable {
                     // Clicked on existing point - create final segment first, then end measurement
                     if let lastPoint = currentPoints.last {
                         let segmentPoints = [lastPoint, existingPoint]
-                        let result = calculateValue(type: .distance, points: segmentPoints)
+                        let result = Self.calculateValue(type: .distance, points: segmentPoints)
                         let measurement = Measurement(type: .distance, points: segmentPoints, value: result.value, circle: result.circle)
-                        measurements.append(measurement)
+                        add(measurement)
                     }
                     endMeasurement()
                     print("Distance measurement ended (clicked on existing point)")
@@ -151,9 +267,9 @@ final class MeasurementSystem: @unchecked Sendable {
             if currentPoints.count >= 2 {
                 // Create a measurement for the last segment
                 let segmentPoints = Array(currentPoints.suffix(2))
-                let result = calculateValue(type: .distance, points: segmentPoints)
+                let result = Self.calculateValue(type: .distance, points: segmentPoints)
                 let measurement = Measurement(type: .distance, points: segmentPoints, value: result.value, circle: result.circle)
-                measurements.append(measurement)
+                add(measurement)
             }
             // Continue measuring - don't reset
             return false
@@ -167,6 +283,12 @@ final class MeasurementSystem: @unchecked Sendable {
         return false
     }
 
+    /// Add a measurement the user made and report it to `onMeasurementAdded`
+    func add(_ measurement: Measurement) {
+        measurements.append(measurement)
+        onMeasurementAdded?(measurement)
+    }
+
     /// Manually end the current measurement session
     func endMeasurement() {
         mode = nil
@@ -174,15 +296,16 @@ final class MeasurementSystem: @unchecked Sendable {
         hoverPoint = nil
         constraint = nil
         constrainedEndpoint = nil
+        snapCandidates = []
     }
 
     /// Complete the current measurement
     private func completeMeasurement() {
         guard let mode, currentPoints.count >= pointsNeeded else { return }
 
-        let result = calculateValue(type: mode, points: currentPoints)
+        let result = Self.calculateValue(type: mode, points: currentPoints)
         let measurement = Measurement(type: mode, points: currentPoints, value: result.value, circle: result.circle)
-        measurements.append(measurement)
+        add(measurement)
 
         // Reset for next measurement
         self.mode = nil
@@ -190,7 +313,7 @@ final class MeasurementSystem: @unchecked Sendable {
     }
 
     /// Calculate measurement value based on type
-    private func calculateValue(type: MeasurementType, points: [MeasurementPoint]) -> (value: Double, circle: Circle?) {
+    static func calculateValue(type: MeasurementType, points: [MeasurementPoint]) -> (value: Double, circle: Circle?) {
         switch type {
         case .distance:
             guard points.count >= 2 else { return (0, nil) }
@@ -222,63 +345,124 @@ final class MeasurementSystem: @unchecked Sendable {
     }
 
     /// Find intersection point on model for a ray
-    /// Snaps to nearby vertices if within threshold
+    /// Snaps to the geometry enabled in `snapTargets` within the snap radius (see `vertexSnapThreshold`)
     /// Uses spatial accelerator for O(log n) performance when available
     func findIntersection(ray: Ray, model: STLModel, accelerator: SpatialAccelerator? = nil) -> MeasurementPoint? {
-        let snapThreshold: Double = 2.0
-
-        // Use accelerator for fast ray casting if available
+        let intersection: (position: Vector3, normal: Vector3)
         if let accelerator = accelerator {
+            // Use accelerator for fast ray casting if available
             guard let hit = accelerator.raycast(ray: ray) else {
                 return nil
             }
+            intersection = (hit.position, hit.normal)
+        } else {
+            // Fallback to O(n) algorithm when accelerator not available
+            var closestDistance: Float = .infinity
+            var closestIntersection: (position: Vector3, normal: Vector3)?
+
+            // Test all triangles to find the closest hit point
+            for triangle in model.triangles {
+                if let (position, normal) = triangle.intersectionPoint(ray: ray) {
+                    let distance = ray.origin.distance(to: position.float3)
+                    if distance < closestDistance {
+                        closestDistance = distance
+                        closestIntersection = (position, normal)
+                    }
+                }
+            }
 
-            // Use spatial grid for fast vertex snapping
-            if let snappedPosition = accelerator.findClosestVertex(to: hit.position, maxDistance: snapThreshold) {
-                // Snapped to a vertex - not an air point
-                return MeasurementPoint(position: snappedPosition, normal: hit.normal, isAirPoint: false)
-            } else {
-                // No vertex within threshold - this is an air point
-                return MeasurementPoint(position: hit.position, normal: hit.normal, isAirPoint: true)
+            guard let closestIntersection else {
+                return nil
             }
+            intersection = closestIntersection
         }
 
-        // Fallback to O(n) algorithm when accelerator not available
-        var closestDistance: Float = .infinity
-        var closestIntersection: (position: Vector3, normal: Vector3)?
+        if let snapped = snapPoint(near: intersection, ray: ray, model: model, accelerator: accelerator) {
+            return snapped
+        }
+        // Nothing to snap to - this is an air point
+        return MeasurementPoint(position: intersection.position, normal: intersection.normal, isAirPoint: true)
+    }
 
-        // Test all triangles to find the closest hit point
-        for triangle in model.triangles {
-            if let (position, normal) = triangle.intersectionPoint(ray: ray) {
-                let distance = ray.origin.distance(to: position.float3)
-                if distance < closestDistance {
-                    closestDistance = distance
-                    closestIntersection = (position, normal)
+    /// Geometry within the snap radius of a surface hit, per the selection filter
+    /// The closest vertex, measurement point or cut vertex wins; edges are only used when there is none
+    private func snapPoint(near hit: (position: Vector3, normal: Vector3), ray: Ray, model: STLModel, accelerator: SpatialAccelerator?) -> MeasurementPoint? {
+        let snapThreshold = vertexSnapThreshold(at: hit.position)
+        guard snapThreshold > 0 else { return nil }
+
+        var best: (point: MeasurementPoint, distance: Double)?
+
+        if snapTargets.contains(.vertices) {
+            var vertex: Vector3?
+            if let accelerator {
+                // Use spatial grid for fast vertex snapping
+                vertex = accelerator.findClosestVertex(to: hit.position, maxDistance: snapThreshold)
+            } else {
+                var closestVertexDistance: Double = .infinity
+                for triangle in model.triangles {
+                    for candidate in [triangle.v1, triangle.v2, triangle.v3] {
+                        let distance = candidate.distance(to: hit.position)
+                        if distance < closestVertexDistance && distance <= snapThreshold {
+                            closestVertexDistance = distance
+                            vertex = candidate
+                        }
+                    }
                 }
             }
+            if let vertex {
+                // Snapped to a vertex - not an air point
+                best = (MeasurementPoint(position: vertex, normal: hit.normal, isAirPoint: false), vertex.distance(to: hit.position))
+            }
         }
 
-        guard let intersection = closestIntersection else {
-            return nil
+        // Measurement points and cut vertices may lie off the surface, so they are found along the ray
+        let origin = Vector3(Double(ray.origin.x), Double(ray.origin.y), Double(ray.origin.z))
+        let direction = Vector3(Double(ray.direction.x), Double(ray.direction.y), Double(ray.direction.z)).normalized()
+        let surfaceDepth = (hit.position - origin).dot(direction)
+
+        func consider(_ point: MeasurementPoint, behindSurface allowed: Bool) {
+            let depth = (point.position - origin).dot(direction)
+            guard depth > 0 else { return }
+            let threshold = vertexSnapThreshold(at: point.position)
+            if !allowed && depth > surfaceDepth + threshold { return }
+            let distance = point.position.distance(to: origin + direction * depth)
+            if distance <= threshold && distance < (best?.distance ?? .infinity) {
+                best = (point, distance)
+            }
         }
 
-        // Snap to nearest vertex in the model if within threshold
-        var snappedPosition = intersection.position
-        var didSnap = false
-
-        var closestVertexDistance: Double = .infinity
-        for triangle in model.triangles {
-            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
-                let distance = vertex.distance(to: intersection.position)
-                if distance < closestVertexDistance && distance <= snapThreshold {
-                    closestVertexDistance = distance
-                    snappedPosition = vertex
-                    didSnap = true
-                }
+        if snapTargets.contains(.measurementPoints) {
+            for point in measurements.flatMap(\.points) + currentPoints {
+                consider(point, behindSurface: false)
             }
         }
+        if snapTargets.contains(.cutVertices) {
+            // The surface in front of a section may be sliced away, so cut vertices behind it still count
+            for vertex in cutVertices {
+                consider(MeasurementPoint(position: vertex, normal: hit.normal, isAirPoint: true), behindSurface: true)
+            }
+        }
+
+        if let best {
+            return best.point
+        }
 
-        return MeasurementPoint(position: snappedPosition, normal: intersection.normal, isAirPoint: !didSnap)
+        guard snapTargets.contains(.edges) else { return nil }
+        let nearby: [Triangle]
+        if let accelerator {
+            nearby = accelerator.findTriangles(near: hit.position, maxDistance: snapThreshold)
+                .filter { $0 < model.triangles.count }
+                .map { model.triangles[$0] }
+        } else {
+            nearby = model.triangles.filter { triangle in
+                let box = BoundingBox(points: [triangle.v1, triangle.v2, triangle.v3])
+                return hit.position.max(box.min).min(box.max).distance(to: hit.position) <= snapThreshold
+            }
+        }
+        guard let edgePoint = SnapTarget.closestEdgePoint(to: hit.position, triangles: nearby, maxDistance: snapThreshold) else {
+            return nil
+        }
+        return MeasurementPoint(position: edgePoint, normal: hit.normal, isAirPoint: true)
     }
 
     /// Clear all measurements
@@ -288,8 +472,21 @@ final class MeasurementSystem: @unchecked Sendable {
         measurements = []
         constraint = nil
         constrainedEndpoint = nil
+        snapCandidates = []
         selectedTriangles.removeAll()
         hoveredTriangle = nil
+        hoveredLabel = nil
+    }
+
+    /// Add the overall X/Y/Z dimensions of a bounding box, replacing earlier ones
+    func addBoundingDimensions(for box: BoundingBox) {
+        if measurements.contains(where: \.isBoundingDimension) {
+            // Indices shift when measurements are removed
+            selectedMeasurements.removeAll()
+            hoveredLabel = nil
+            measurements.removeAll(where: \.isBoundingDimension)
+        }
+        BoundingDimensions.measurements(for: box).forEach(add)
     }
 
     /// Validate measurements after model reload
@@ -402,6 +599,31 @@ final class MeasurementSystem: @unchecked Sendable {
         return nil
     }
 
+    /// Get the constraining direction (if a direction constraint is active)
+    var constrainingDirection: Vector3? {
+        if case .direction(let direction) = constraint {
+            return direction
+        }
+        return nil
+    }
+
+    /// Toggle a constraint along a reference direction (axis direction or plane normal)
+    func toggleDirectionConstraint(_ direction: Vector3) {
+        guard mode == .distance && !currentPoints.isEmpty else { return }
+
+        let unitDirection = direction.normalized()
+        if let current = constrainingDirection, current.isApproximatelyEqual(to: unitDirection, tolerance: 1e-9) {
+            constraint = nil
+            constrainedEndpoint = nil
+            print("Direction constraint disabled")
+            return
+        }
+
+        constraint = .direction(unitDirection)
+        updateConstrainedMeasurement()
+        print("Direction constraint: \(unitDirection)")
+    }
+
     /// Toggle point constraint using the current hover point
     func togglePointConstraint() {
         guard mode == .distance && !currentPoints.isEmpty else { return }
@@ -424,6 +646,14 @@ final class MeasurementSystem: @unchecked Sendable {
         print("Point constraint: \(hoverPoint.position)")
     }
 
+    /// Constrain the second point to the direction towards a given position
+    func setPointConstraint(toward position: Vector3) {
+        guard mode == .distance && !currentPoints.isEmpty else { return }
+        constraint = .point(position)
+        updateConstrainedMeasurement()
+        print("Point constraint: \(position)")
+    }
+
     /// Calculate the constrained endpoint based on current constraint
     /// - Parameter snapPoint: The point under the cursor (where user would normally click)
     /// - Returns: The constrained endpoint position (along the constraint axis or direction)
@@ -469,11 +699,20 @@ final class MeasurementSystem: @unchecked Sendable {
 
             // Calculate the projected point on the constraint line
             return referencePoint + normDir * t
+
+        case .direction(let direction):
+            // Project the snap point onto the line through the reference point along the direction
+            let t = (snapPoint - referencePoint).dot(direction)
+            return referencePoint + direction * t
         }
     }
 
     /// Update constraint state when hover point changes
     func updateConstrainedMeasurement() {
+        if typedLength != nil {
+            constrainedEndpoint = typedLengthEndpoint
+            return
+        }
         guard let hoverPoint = hoverPoint,
               !currentPoints.isEmpty,
               constraint != nil else {
@@ -484,6 +723,356 @@ final class MeasurementSystem: @unchecked Sendable {
         constrainedEndpoint = calculateConstrainedEndpoint(snapPoint: hoverPoint.position)
     }
 
+    // MARK: - Typed Length Methods
+
+    /// Whether typed digits go to the length input (a distance constraint is active)
+    var acceptsLengthInput: Bool {
+        mode == .distance && !currentPoints.isEmpty && constraint != nil
+    }
+
+    /// The typed length, if it is a valid non-zero number
+    var typedLength: Double? {
+        guard let lengthInput, let length = Double(lengthInput), length.isFinite, length != 0 else { return nil }
+        return length
+    }
+
+    /// Append a typed character (digit, decimal separator or leading minus) to the length input
+    /// - Returns: Whether the character was accepted
+    @discardableResult
+    func appendLengthInput(_ character: Character) -> Bool {
+        guard acceptsLengthInput else { return false }
+        let text = lengthInput ?? ""
+
+        switch character {
+        case "0"..."9":
+            lengthInput = text + String(character)
+        case ".", ",":
+            guard !text.contains(".") else { return true }
+            lengthInput = text + (text.isEmpty || text == "-" ? "0." : ".")
+        case "-":
+            // Minus toggles the direction at any time
+            lengthInput = text.hasPrefix("-") ? String(text.dropFirst()) : "-" + text
+        default:
+            return false
+        }
+        updateConstrainedMeasurement()
+        return true
+    }
+
+    /// Remove the last typed character
+    /// - Returns: Whether there was typed input to edit
+    @discardableResult
+    func removeLastLengthInputCharacter() -> Bool {
+        guard let lengthInput else { return false }
+        let remaining = String(lengthInput.dropLast())
+        self.lengthInput = remaining.isEmpty ? nil : remaining
+        updateConstrainedMeasurement()
+        return true
+    }
+
+    /// Discard the typed length and go back to following the mouse
+    /// - Returns: Whether there was typed input to discard
+    @discardableResult
+    func cancelLengthInput() -> Bool {
+        guard lengthInput != nil else { return false }
+        lengthInput = nil
+        updateConstrainedMeasurement()
+        return true
+    }
+
+    /// Unit direction of the constraint from the last picked point
+    /// Axis and direction constraints point toward the side of the hover point (positive if unknown);
+    /// point constraints point toward the constraining point.
+    func constraintDirection() -> Vector3? {
+        guard let reference = currentPoints.last?.position, let constraint else { return nil }
+
+        let direction: Vector3
+        switch constraint {
+        case .axis(let axis):
+            guard (0...2).contains(axis) else { return nil }
+            direction = [Vector3(1, 0, 0), Vector3(0, 1, 0), Vector3(0, 0, 1)][axis]
+        case .point(let target):
+            let offset = target - reference
+            guard offset.length >= 0.0001 else { return nil }
+            return offset.normalized()
+        case .direction(let unit):
+            direction = unit
+        }
+
+        if let hoverPoint, (hoverPoint.position - reference).dot(direction) < 0 {
+            return -direction
+        }
+        return direction
+    }
+
+    /// Position at the typed length along the constraint
+    var typedLengthEndpoint: Vector3? {
+        guard let length = typedLength,
+              let reference = currentPoints.last?.position,
+              let direction = constraintDirection() else {
+            return nil
+        }
+        return reference + direction * length
+    }
+
+    /// Add a virtual point at the typed length along the constraint
+    /// - Returns: The point that was added, or nil if no valid length was typed
+    func placeTypedLength() -> MeasurementPoint? {
+        guard acceptsLengthInput, let position = typedLengthEndpoint else { return nil }
+
+        let point = MeasurementPoint(position: position, normal: Vector3(0, 1, 0), isAirPoint: true)
+        constraint = nil
+        constrainedEndpoint = nil
+        _ = addPoint(point)
+        clearSnapCandidates()
+        return point
+    }
+
+    // MARK: - Grid Snapping Methods
+
+    /// Pick a measurement point under the ray, snapped to the grid when grid snap is active
+    func pickPoint(ray: Ray, model: STLModel, accelerator: SpatialAccelerator? = nil) -> MeasurementPoint? {
+        var surfacePoint = findIntersection(ray: ray, model: model, accelerator: accelerator)
+        pickRefinement = nil
+
+        // Reference entities take precedence over the surface and the grid
+        if let referencePoint = referenceSnapPoint(ray: ray, surfacePosition: surfacePoint?.position) {
+            return referencePoint
+        }
+
+        guard isGridSnapActive else {
+            if refinePicks, let refinement = refinePick(ray: ray, model: model, accelerator: accelerator) {
+                pickRefinement = refinement
+                surfacePoint = refinement.point
+            }
+            return surfacePoint
+        }
+
+        // On the model: snap across the grid, keeping the height of the surface
+        if let surfacePoint = surfacePoint {
+            let snapped = snapToGrid(surfacePoint.position)
+            return MeasurementPoint(
+                position: Vector3(snapped.x, snapped.y, surfacePoint.position.z),
+                normal: surfacePoint.normal,
+                isAirPoint: true
+            )
+        }
+
+        // Off the model: create the point where the ray hits the displayed grid
+        guard let plane = gridSnapPlane, abs(ray.direction.z) > 1e-6 else { return nil }
+        let origin = Vector3(Double(ray.origin.x), Double(ray.origin.y), Double(ray.origin.z))
+        let direction = Vector3(Double(ray.direction.x), Double(ray.direction.y), Double(ray.direction.z))
+        let t = (plane.z - origin.z) / direction.z
+        guard t > 0 else { return nil }
+
+        let snapped = snapToGrid(origin + direction * t)
+        guard plane.contains(x: snapped.x, y: snapped.y) else { return nil }
+        return MeasurementPoint(position: Vector3(snapped.x, snapped.y, plane.z), normal: Vector3(0, 0, 1), isAirPoint: true)
+    }
+
+    /// Refine the raw surface hit under the ray (instead of snapping to the closest vertex)
+    func refinePick(ray: Ray, model: STLModel, accelerator: SpatialAccelerator? = nil) -> PickRefinement? {
+        let radius = PickRefinement.searchRadius
+        let hit: (position: Vector3, normal: Vector3)
+        let nearby: [Triangle]
+        if let accelerator {
+            guard let rayHit = accelerator.raycast(ray: ray) else { return nil }
+            hit = (rayHit.position, rayHit.normal)
+            nearby = accelerator.findTriangles(near: hit.position, maxDistance: radius)
+                .filter { $0 < model.triangles.count }
+                .map { model.triangles[$0] }
+        } else {
+            let hits = model.triangles.compactMap { $0.intersectionPoint(ray: ray) }
+            guard let closest = hits.min(by: { ray.origin.distance(to: $0.position.float3) < ray.origin.distance(to: $1.position.float3) }) else {
+                return nil
+            }
+            hit = closest
+            nearby = model.triangles.filter { triangle in
+                let box = BoundingBox(points: [triangle.v1, triangle.v2, triangle.v3])
+                return hit.position.max(box.min).min(box.max).distance(to: hit.position) <= radius
+            }
+        }
+        return PickRefinement.refine(position: hit.position, normal: hit.normal, near: nearby)
+    }
+
+    /// Snap to a visible reference point, plane origin or axis passing close to the ray
+    /// Points and plane origins win over axes so they stay pickable where an axis passes through them
+    /// - Parameter surfacePosition: Model hit under the ray; references hidden behind it are ignored
+    func referenceSnapPoint(ray: Ray, surfacePosition: Vector3?) -> MeasurementPoint? {
+        let visible = referenceEntities.filter(\.isVisible)
+        guard !visible.isEmpty else { return nil }
+
+        let origin = Vector3(Double(ray.origin.x), Double(ray.origin.y), Double(ray.origin.z))
+        let direction = Vector3(Double(ray.direction.x), Double(ray.direction.y), Double(ray.direction.z)).normalized()
+        let surfaceDepth = surfacePosition.map { ($0 - origin).dot(direction) }
+
+        // Distance from the ray, or nil if the candidate is behind the camera, the surface or too far away
+        func rayDistance(_ candidate: Vector3) -> Double? {
+            let depth = (candidate - origin).dot(direction)
+            guard depth > 0 else { return nil }
+            let threshold = max(Self.referenceSnapThreshold, depth * 0.01)
+            if let surfaceDepth, depth > surfaceDepth + threshold { return nil }
+            let distance = candidate.distance(to: origin + direction * depth)
+            return distance <= threshold ? distance : nil
+        }
+
+        var best: (position: Vector3, normal: Vector3, distance: Double)?
+        for pass in 0..<2 {
+            for entity in visible {
+                let candidate: Vector3
+                switch (entity.kind, pass) {
+                case (.point(let position), 0):
+                    candidate = position
+                case (.plane(let planeOrigin, _), 0):
+                    candidate = planeOrigin
+                case (.axis(let axisOrigin, let axisDirection), 1):
+                    // Closest point on the axis to the ray
+                    let b = axisDirection.dot(direction)
+                    let denominator = 1 - b * b
+                    guard denominator > 1e-9 else { continue }
+                    let w = axisOrigin - origin
+                    let s = (b * direction.dot(w) - axisDirection.dot(w)) / denominator
+                    candidate = axisOrigin + axisDirection * s
+                default:
+                    continue
+                }
+
+                if let distance = rayDistance(candidate), distance < (best?.distance ?? .infinity) {
+                    best = (candidate, entity.kind.direction ?? Vector3.unitZ, distance)
+                }
+            }
+            if best != nil { break }
+        }
+
+        guard let best else { return nil }
+        return MeasurementPoint(position: best.position, normal: best.normal, isAirPoint: true)
+    }
+
+    /// Round a position (render coordinates) to the nearest grid intersection in file coordinates
+    func snapToGrid(_ position: Vector3) -> Vector3 {
+        guard gridSnapSpacing > 0 else { return position }
+        let spacing = gridSnapSpacing
+        let original = position + gridSnapOffset
+        let snapped = Vector3(
+            (original.x / spacing).rounded() * spacing,
+            (original.y / spacing).rounded() * spacing,
+            (original.z / spacing).rounded() * spacing
+        )
+        return snapped - gridSnapOffset
+    }
+
+    // MARK: - Keyboard Picking Methods
+
+    /// Collect snap candidates around the point hit by a ray
+    /// Candidates are the nearby model vertices (closest first) followed by the surface hit itself
+    func updateSnapCandidates(ray: Ray, model: STLModel, accelerator: SpatialAccelerator?) {
+        guard isCollecting, mode != .triangleSelect,
+              let hit = findIntersection(ray: ray, model: model, accelerator: accelerator) else {
+            snapCandidates = []
+            return
+        }
+
+        // Search radius scales with the model so small and large parts behave alike
+        let searchRadius = max(2.0, model.boundingBox().diagonal * 0.05)
+
+        var candidates: [MeasurementPoint] = []
+        // Mesh vertices only if the selection filter allows them
+        if snapTargets.contains(.vertices) {
+            if let accelerator = accelerator {
+                let vertices = accelerator.findVertices(near: hit.position, maxDistance: searchRadius, limit: Self.maxSnapCandidates - 1)
+                candidates = vertices.map { MeasurementPoint(position: $0, normal: hit.normal, isAirPoint: false) }
+            } else {
+                var seen = Set<Vector3>()
+                var nearby: [(vertex: Vector3, distance: Double)] = []
+                for triangle in model.triangles {
+                    for vertex in triangle.vertices where !seen.contains(vertex) {
+                        seen.insert(vertex)
+                        let distance = vertex.distance(to: hit.position)
+                        if distance <= searchRadius {
+                            nearby.append((vertex, distance))
+                        }
+                    }
+                }
+                candidates = nearby
+                    .sorted { $0.distance < $1.distance }
+                    .prefix(Self.maxSnapCandidates - 1)
+                    .map { MeasurementPoint(position: $0.vertex, normal: hit.normal, isAirPoint: false) }
+            }
+        }
+
+        // Offer the raw surface point as well, unless it already is a vertex
+        if !candidates.contains(where: { $0.position == hit.position }) {
+            candidates.append(MeasurementPoint(position: hit.position, normal: hit.normal, isAirPoint: true))
+        }
+
+        snapCandidates = candidates
+        snapCandidateIndex = 0
+        hoverPoint = candidates.first
+        updateConstrainedMeasurement()
+    }
+
+    /// Highlight the next (or previous) snap candidate
+    /// - Returns: The newly highlighted candidate, or nil if there are none
+    @discardableResult
+    func cycleSnapCandidate(reverse: Bool = false) -> MeasurementPoint? {
+        guard !snapCandidates.isEmpty else { return nil }
+
+        let count = snapCandidates.count
+        snapCandidateIndex = (snapCandidateIndex + (reverse ? count - 1 : 1)) % count
+        hoverPoint = snapCandidates[snapCandidateIndex]
+        updateConstrainedMeasurement()
+        return hoverPoint
+    }
+
+    /// Drop the current snap candidates (e.g. after the camera moved)
+    func clearSnapCandidates() {
+        snapCandidates = []
+        snapCandidateIndex = 0
+    }
+
+    /// Add the highlighted point (or the constrained endpoint) to the current measurement
+    /// - Returns: The point that was added, or nil if there was nothing to confirm
+    func confirmHoverPoint() -> MeasurementPoint? {
+        guard isCollecting, mode != .triangleSelect else { return nil }
+
+        let point: MeasurementPoint
+        if let constrainedEndpoint = constrainedEndpoint, constraint != nil {
+            point = MeasurementPoint(position: constrainedEndpoint, normal: Vector3(0, 1, 0), isAirPoint: true)
+            constraint = nil
+            self.constrainedEndpoint = nil
+        } else if let hoverPoint = hoverPoint {
+            point = hoverPoint
+        } else {
+            return nil
+        }
+
+        _ = addPoint(point)
+        clearSnapCandidates()
+        return point
+    }
+
+    /// Set or clear (empty text) the note of a measurement
+    func setNote(_ note: String, forMeasurement index: Int) {
+        guard measurements.indices.contains(index) else { return }
+        let trimmed = note.trimmingCharacters(in: .whitespacesAndNewlines)
+        measurements[index].note = trimmed.isEmpty ? nil : trimmed
+    }
+
+    /// Show more (positive step) or fewer decimal places for one measurement
+    /// - Parameter base: Decimal places the measurement shows without an override of its own
+    func adjustDecimals(by step: Int, forMeasurement index: Int, base: DisplayPrecision) {
+        guard measurements.indices.contains(index) else { return }
+        let measurement = measurements[index]
+        let current = measurement.decimals ?? (measurement.type == .angle ? base.angleDecimals : base.lengthDecimals)
+        measurements[index].decimals = DisplayPrecision.clamped(current + step)
+    }
+
+    /// Return a measurement to the decimal places of the display setting
+    func resetDecimals(forMeasurement index: Int) {
+        guard measurements.indices.contains(index) else { return }
+        measurements[index].decimals = nil
+    }
+
     // MARK: - Selection Methods
 
     /// Start selection rectangle
@@ -662,6 +1251,16 @@ final class MeasurementSystem: @unchecked Sendable {
         print("Removed \(sortedIndices.count) measurement(s)")
     }
 
+    /// Remove a single measurement, keeping the selection of the others
+    func removeMeasurement(at index: Int) {
+        guard measurements.indices.contains(index) else { return }
+        measurements.remove(at: index)
+        selectedMeasurements = Set(selectedMeasurements.compactMap { selected in
+            selected == index ? nil : (selected > index ? selected - 1 : selected)
+        })
+        hoveredLabel = nil
+    }
+
     /// Remove most recent measurement
     func removeLastMeasurement() {
         if !measurements.isEmpty {
@@ -0,0 +1,279 @@
+import Foundation
+
+/// Errors in the text definition of a measurement template
+enum MeasurementTemplateError: LocalizedError, Equatable {
+    case emptyName
+    case duplicateName(String)
+    case noFeatures
+    case duplicateFeature(String)
+    case unknownKind(line: Int, kind: String)
+    case unknownFeatures(line: Int)
+    case invalidLine(Int)
+
+    var errorDescription: String? {
+        switch self {
+        case .emptyName:
+            return "The template has no name"
+        case .duplicateName(let name):
+            return "A template named \"\(name)\" already exists"
+        case .noFeatures:
+            return "The template has no features to pick"
+        case .duplicateFeature(let name):
+            return "The feature \"\(name)\" is defined twice"
+        case .unknownKind(let line, let kind):
+            return "Line \(line): unknown feature type \"\(kind)\" (use \(MeasurementTemplate.FeatureKind.allCases.map(\.rawValue).joined(separator: ", ")))"
+        case .unknownFeatures(let line):
+            return "Line \(line): write \"name = first feature to second feature\" with two features defined above"
+        case .invalidLine(let line):
+            return "Line \(line): expected \"name: type\" for a feature or \"name = first to second\" for a dimension"
+        }
+    }
+}
+
+/// A named sequence of feature picks and the dimensions between them, defined once and applied to
+/// every part of a batch (Tools > Batch Templates)
+///
+/// Templates are written one line per feature or dimension:
+///
+///     hole1 center: hole
+///     hole2 center: hole
+///     base plane: plane
+///     pitch = hole1 center to hole2 center: 40 ± 0.1
+///     tilt = angle base plane to top face
+///
+/// Hole features add their diameter as a column of their own.
+struct MeasurementTemplate: Codable, Equatable, Identifiable {
+    /// What is picked for a feature and how the picks become a reference entity
+    enum FeatureKind: String, Codable, CaseIterable {
+        /// One point on the surface
+        case point
+        /// Center of a hole or boss from three points around its rim
+        case hole
+        /// Plane through three points
+        case plane
+        /// Plane of the face under one click
+        case face
+        /// Axis of a cylinder from three points around it
+        case axis
+
+        var pointsNeeded: Int {
+            switch self {
+            case .point, .face: return 1
+            case .hole, .plane, .axis: return 3
+            }
+        }
+
+        /// What to click, e.g. "a point on the rim"
+        var instruction: String {
+            switch self {
+            case .point: return "Click the point"
+            case .hole: return "Click 3 points around the rim"
+            case .plane: return "Click 3 points on the plane"
+            case .face: return "Click the face"
+            case .axis: return "Click 3 points around the cylinder"
+            }
+        }
+
+        /// Reference entity from the picked points (nil if they are degenerate)
+        func entity(from points: [MeasurementPoint]) -> ReferenceEntity.Kind? {
+            guard points.count >= pointsNeeded else { return nil }
+            let positions = points.map(\.position)
+            switch self {
+            case .point:
+                return .point(positions[0])
+            case .hole:
+                return Circle.fit(points: positions).map { .point($0.center) }
+            case .plane:
+                return .plane(through: positions[0], positions[1], positions[2])
+            case .face:
+                return .plane(offsetFrom: positions[0], normal: points[0].normal, by: 0)
+            case .axis:
+                return .axis(fittingCylinder: positions)
+            }
+        }
+    }
+
+    struct Feature: Codable, Equatable {
+        var name: String
+        var kind: FeatureKind
+    }
+
+    enum Quantity: String, Codable {
+        case distance
+        case angle
+    }
+
+    /// Distance or angle between two features, with an optional nominal value and tolerance
+    struct Dimension: Codable, Equatable {
+        var name: String
+        var first: String
+        var second: String
+        var quantity: Quantity = .distance
+        var nominal: Double?
+        var tolerance: Double?
+    }
+
+    /// A column of the results table
+    struct Column: Equatable {
+        enum Source: Equatable {
+            case dimension(Dimension)
+            /// Diameter of a hole feature
+            case diameter(feature: String)
+        }
+
+        let title: String
+        let source: Source
+
+        var unit: String {
+            if case .dimension(let dimension) = source, dimension.quantity == .angle {
+                return "°"
+            }
+            return "mm"
+        }
+    }
+
+    var id = UUID()
+    var name: String
+    var features: [Feature]
+    var dimensions: [Dimension]
+
+    private enum CodingKeys: String, CodingKey {
+        case id, name, features, dimensions
+    }
+
+    init(name: String, features: [Feature], dimensions: [Dimension] = []) {
+        self.name = name
+        self.features = features
+        self.dimensions = dimensions
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        // Templates saved before IDs were stored get a new one
+        id = try container.decodeIfPresent(UUID.self, forKey: .id) ?? UUID()
+        name = try container.decode(String.self, forKey: .name)
+        features = try container.decode([Feature].self, forKey: .features)
+        dimensions = try container.decode([Dimension].self, forKey: .dimensions)
+    }
+
+    /// The saved templates with this one added, or replacing the one with its ID
+    /// - Throws: `MeasurementTemplateError.duplicateName` if another template has the same name
+    func saved(into templates: [MeasurementTemplate]) throws -> [MeasurementTemplate] {
+        guard !templates.contains(where: { $0.id != id && $0.name.caseInsensitiveCompare(name) == .orderedSame }) else {
+            throw MeasurementTemplateError.duplicateName(name)
+        }
+        var templates = templates
+        if let index = templates.firstIndex(where: { $0.id == id }) {
+            templates[index] = self
+        } else {
+            templates.append(self)
+        }
+        return templates
+    }
+
+    /// Hole diameters first, in feature order, then the dimensions
+    var columns: [Column] {
+        features.filter { $0.kind == .hole }.map { Column(title: "\($0.name) Ø", source: .diameter(feature: $0.name)) }
+            + dimensions.map { Column(title: $0.name, source: .dimension($0)) }
+    }
+
+    // MARK: - Text Definition
+
+    /// The template in the text syntax it is edited in
+    var definition: String {
+        let featureLines = features.map { "\($0.name): \($0.kind.rawValue)" }
+        let dimensionLines = dimensions.map { dimension -> String in
+            var line = "\(dimension.name) = \(dimension.quantity == .angle ? "angle " : "")\(dimension.first) to \(dimension.second)"
+            if let nominal = dimension.nominal {
+                line += ": \(Self.format(nominal))"
+                if let tolerance = dimension.tolerance {
+                    line += " ± \(Self.format(tolerance))"
+                }
+            }
+            return line
+        }
+        return (featureLines + dimensionLines).joined(separator: "\n")
+    }
+
+    /// Parse a template from its text definition (blank lines and lines starting with # are ignored)
+    static func parse(name: String, definition: String) throws -> MeasurementTemplate {
+        let trimmedName = name.trimmingCharacters(in: .whitespaces)
+        guard !trimmedName.isEmpty else { throw MeasurementTemplateError.emptyName }
+
+        var features: [Feature] = []
+        var dimensions: [Dimension] = []
+        for (offset, rawLine) in definition.components(separatedBy: .newlines).enumerated() {
+            let line = rawLine.trimmingCharacters(in: .whitespaces)
+            let number = offset + 1
+            guard !line.isEmpty, !line.hasPrefix("#") else { continue }
+
+            if let equals = line.firstIndex(of: "=") {
+                let dimensionName = line[..<equals].trimmingCharacters(in: .whitespaces)
+                guard !dimensionName.isEmpty else { throw MeasurementTemplateError.invalidLine(number) }
+                var dimension = try parseDimension(String(line[line.index(after: equals)...]), line: number, features: features)
+                dimension.name = dimensionName
+                dimensions.append(dimension)
+            } else if let colon = line.lastIndex(of: ":") {
+                let featureName = line[..<colon].trimmingCharacters(in: .whitespaces)
+                let kindName = line[line.index(after: colon)...].trimmingCharacters(in: .whitespaces).lowercased()
+                guard !featureName.isEmpty else { throw MeasurementTemplateError.invalidLine(number) }
+                guard let kind = FeatureKind(rawValue: kindName) else {
+                    throw MeasurementTemplateError.unknownKind(line: number, kind: kindName)
+                }
+                guard !features.contains(where: { $0.name == featureName }) else {
+                    throw MeasurementTemplateError.duplicateFeature(featureName)
+                }
+                features.append(Feature(name: featureName, kind: kind))
+            } else {
+                throw MeasurementTemplateError.invalidLine(number)
+            }
+        }
+
+        guard !features.isEmpty else { throw MeasurementTemplateError.noFeatures }
+        return MeasurementTemplate(name: trimmedName, features: features, dimensions: dimensions)
+    }
+
+    /// "[angle] first to second[: nominal [± tolerance]]" (names may contain "to" and spaces)
+    private static func parseDimension(_ text: String, line: Int, features: [Feature]) throws -> Dimension {
+        var body = text.trimmingCharacters(in: .whitespaces)
+        var nominal: Double?
+        var tolerance: Double?
+        if let colon = body.lastIndex(of: ":") {
+            let limits = body[body.index(after: colon)...]
+                .replacingOccurrences(of: "+/-", with: "±")
+                .components(separatedBy: "±")
+                .map { $0.trimmingCharacters(in: .whitespaces) }
+            guard let value = Double(limits[0]) else { throw MeasurementTemplateError.invalidLine(line) }
+            nominal = value
+            if limits.count > 1 {
+                guard let value = Double(limits[1]) else { throw MeasurementTemplateError.invalidLine(line) }
+                tolerance = abs(value)
+            }
+            body = body[..<colon].trimmingCharacters(in: .whitespaces)
+        }
+
+        var quantity = Quantity.distance
+        if body.lowercased().hasPrefix("angle ") {
+            quantity = .angle
+            body = String(body.dropFirst("angle ".count)).trimmingCharacters(in: .whitespaces)
+        }
+
+        // Try every " to " until both sides name features
+        let names = Set(features.map(\.name))
+        var searchStart = body.startIndex
+        while let separator = body.range(of: " to ", range: searchStart..<body.endIndex) {
+            let first = body[..<separator.lowerBound].trimmingCharacters(in: .whitespaces)
+            let second = body[separator.upperBound...].trimmingCharacters(in: .whitespaces)
+            if names.contains(first) && names.contains(second) {
+                return Dimension(name: "", first: first, second: second, quantity: quantity, nominal: nominal, tolerance: tolerance)
+            }
+            searchStart = separator.upperBound
+        }
+        throw MeasurementTemplateError.unknownFeatures(line: line)
+    }
+
+    /// Shortest decimal form ("40", "0.05")
+    private static func format(_ value: Double) -> String {
+        String(format: "%g", value)
+    }
+}
@@ -1,7 +1,7 @@
 import Foundation
 
 /// Types of measurements that can be performed
-enum MeasurementType {
+enum MeasurementType: String, Codable {
     case distance  // Distance between two points
     case angle     // Angle between three points
     case radius    // Radius of a circle fitted to three points
@@ -13,11 +13,13 @@ struct MeasurementPoint {
     let position: Vector3
     let normal: Vector3
     let isAirPoint: Bool  // true if created via constraint or didn't snap to vertex
+    let uncertainty: Double?  // Standard uncertainty in mm if the point was refined (see PickRefinement)
 
-    init(position: Vector3, normal: Vector3, isAirPoint: Bool = false) {
+    init(position: Vector3, normal: Vector3, isAirPoint: Bool = false, uncertainty: Double? = nil) {
         self.position = position
         self.normal = normal
         self.isAirPoint = isAirPoint
+        self.uncertainty = uncertainty
     }
 }
 
@@ -28,6 +30,18 @@ struct Measurement {
     let value: Double
     let circle: Circle? // For radius measurements, stores the fitted circle
     var stalePointIndices: Set<Int> = []  // Indices of points that no longer align with model vertices
+    var createdAt: Date?  // nil for measurements saved before creation times were recorded
+    var note: String?
+    /// Decimal places of this measurement's value (nil: the display setting)
+    var decimals: Int?
+    /// Points the measurement is attached to, one per measurement point, drawn as extension lines
+    /// to the offset dimension line (nil: the measurement is drawn between its points only)
+    var extensionAnchors: [Vector3]?
+
+    /// Whether the measurement is an overall dimension of the bounding box (see `BoundingDimensions`)
+    var isBoundingDimension: Bool {
+        extensionAnchors != nil
+    }
 
     /// Whether any points in this measurement are stale (no longer on vertices)
     var hasStalePoints: Bool {
@@ -39,6 +53,31 @@ struct Measurement {
         self.points = points
         self.value = value
         self.circle = circle
+        self.createdAt = Date()
+    }
+
+    /// A copy of the measurement with all points moved by the given offset
+    func translated(by offset: Vector3) -> Measurement {
+        guard offset != .zero else { return self }
+        let movedPoints = points.map {
+            MeasurementPoint(position: $0.position + offset, normal: $0.normal, isAirPoint: $0.isAirPoint, uncertainty: $0.uncertainty)
+        }
+        let movedCircle = circle.map {
+            Circle(center: $0.center + offset, radius: $0.radius, normal: $0.normal)
+        }
+        var moved = Measurement(type: type, points: movedPoints, value: value, circle: movedCircle)
+        moved.stalePointIndices = stalePointIndices
+        moved.createdAt = createdAt
+        moved.note = note
+        moved.decimals = decimals
+        moved.extensionAnchors = extensionAnchors?.map { $0 + offset }
+        return moved
+    }
+
+    /// Combined uncertainty of a distance between refined points (nil if no point was refined)
+    var uncertainty: Double? {
+        guard type == .distance, points.contains(where: { $0.uncertainty != nil }) else { return nil }
+        return points.reduce(0.0) { $0 + pow($1.uncertainty ?? 0, 2) }.squareRoot()
     }
 
     /// Format the measurement value for display
@@ -47,16 +86,20 @@ struct Measurement {
     }
 
     /// Format the measurement value for display with diameter option
-    func formattedValue(showDiameter: Bool) -> String {
+    /// - Parameter precision: Display setting; the measurement's own `decimals` take precedence
+    func formattedValue(showDiameter: Bool, precision: DisplayPrecision = .standard) -> String {
+        let precision = precision.overridden(by: decimals)
         switch type {
         case .distance:
-            return formatDistance(value)
+            guard let uncertainty else { return precision.labelLength(value) }
+            return precision.length(value) + String(format: " ±%.3f", uncertainty / precision.unit.millimeters)
+                + (precision.unit == .millimeters ? "" : " \(precision.unit.symbol)")
         case .angle:
-            return String(format: "%.1f°", value)
+            return precision.angle(value)
         case .radius:
             let prefix = showDiameter ? "d:" : "r:"
             let displayValue = showDiameter ? value * 2.0 : value
-            return prefix + formatDistance(displayValue)
+            return prefix + precision.labelLength(displayValue)
         case .triangleSelect:
             return ""  // Not used for triangle selection
         }
@@ -81,6 +124,63 @@ struct Measurement {
         }
     }
 
+    /// Plain-language description of the measurement (for screen readers and the measurement log)
+    func spokenDescription(showDiameter: Bool, precision: DisplayPrecision = .standard) -> String {
+        let precision = precision.overridden(by: decimals)
+        switch type {
+        case .distance:
+            return "Distance \(precision.length(value)) \(precision.unit.spokenName)"
+        case .angle:
+            return String(format: "Angle %.*f degrees", Int32(precision.angleDecimals), value)
+        case .radius:
+            let displayValue = showDiameter ? value * 2.0 : value
+            return "\(label(showDiameter: showDiameter)) \(precision.length(displayValue)) \(precision.unit.spokenName)"
+        case .triangleSelect:
+            return ""
+        }
+    }
+
+    /// Full-precision details of the measurement as label/value rows (shown in the label tooltip)
+    /// Coordinates are reported as stored; translate the measurement first for file coordinates.
+    func detailRows(showDiameter: Bool) -> [(label: String, value: String)] {
+        var rows: [(label: String, value: String)] = []
+        switch type {
+        case .distance:
+            let uncertaintyText = uncertainty.map { String(format: " ±%.4f", $0) } ?? ""
+            rows.append(("Distance", String(format: "%.6f mm", value) + uncertaintyText))
+            if points.count >= 2 {
+                let delta = points[1].position - points[0].position
+                rows.append(("ΔX", String(format: "%.6f", delta.x)))
+                rows.append(("ΔY", String(format: "%.6f", delta.y)))
+                rows.append(("ΔZ", String(format: "%.6f", delta.z)))
+            }
+        case .angle:
+            rows.append(("Angle", String(format: "%.6f°", value)))
+        case .radius:
+            let primary = showDiameter ? ("Diameter", value * 2) : ("Radius", value)
+            let secondary = showDiameter ? ("Radius", value) : ("Diameter", value * 2)
+            rows.append((primary.0, String(format: "%.6f mm", primary.1)))
+            rows.append((secondary.0, String(format: "%.6f mm", secondary.1)))
+            if let circle {
+                rows.append(("Center", formatPoint(circle.center)))
+            }
+        case .triangleSelect:
+            break
+        }
+
+        for (index, point) in points.enumerated() {
+            let stale = stalePointIndices.contains(index) ? " (stale)" : ""
+            rows.append(("P\(index + 1)", formatPoint(point.position) + stale))
+        }
+        if let createdAt {
+            rows.append(("Created", createdAt.formatted(date: .abbreviated, time: .standard)))
+        }
+        if let note {
+            rows.append(("Note", note))
+        }
+        return rows
+    }
+
     /// Position where the label should be displayed (in 3D world space)
     var labelPosition: Vector3 {
         guard points.count >= 2 else {
@@ -121,7 +221,20 @@ struct Measurement {
         }
     }
 
-    private func formatDistance(_ value: Double) -> String {
-        return String(format: "%.2f", value)
+    private func formatPoint(_ point: Vector3) -> String {
+        String(format: "(%.4f, %.4f, %.4f)", point.x, point.y, point.z)
+    }
+}
+
+// MARK: - Codable
+
+extension MeasurementPoint: Codable {}
+
+extension MeasurementPoint: Equatable {}
+
+extension Measurement: Codable {
+    // Stale points are derived from the current model and are not persisted
+    enum CodingKeys: String, CodingKey {
+        case type, points, value, circle, createdAt, note, decimals, extensionAnchors
     }
 }
@@ -0,0 +1,199 @@
+import Foundation
+
+/// A picked point refined by fitting the local surface around it
+///
+/// The triangles near the pick are grouped into faces by normal direction and a plane is fitted
+/// to each face. One face projects the point onto its plane, two faces move it onto their common
+/// edge and three faces onto their corner. The uncertainty is the standard error of the fitted
+/// planes, amplified by how steeply they meet: a shallow edge is located less precisely than a
+/// square one.
+struct PickRefinement {
+    enum Feature: String {
+        case plane = "Surface"
+        case edge = "Edge"
+        case corner = "Corner"
+    }
+
+    /// Position before refinement (the ray hit)
+    let original: Vector3
+    /// Refined position
+    let position: Vector3
+    /// Normal of the face that was hit
+    let normal: Vector3
+    let feature: Feature
+    /// Estimated standard uncertainty of the refined position (mm)
+    let uncertainty: Double
+    /// Number of mesh vertices the fitted planes are based on
+    let sampleCount: Int
+
+    /// Radius around the pick that is searched for the intended feature (mm)
+    static let searchRadius: Double = 2.0
+
+    /// Maximum angle between triangle normals of the same face (degrees)
+    static let featureAngle: Double = 25.0
+
+    /// Faces covering less than this share of the nearby area are ignored as noise
+    static let minimumAreaShare: Double = 0.1
+
+    /// Smallest determinant of the face normals that still gives a stable corner
+    static let minimumCornerDeterminant: Double = 0.2
+
+    /// The refined point for a measurement
+    var point: MeasurementPoint {
+        MeasurementPoint(position: position, normal: normal, isAirPoint: true, uncertainty: uncertainty)
+    }
+
+    /// Offset from the original pick to the refined position
+    var shift: Double {
+        original.distance(to: position)
+    }
+
+    /// Refine a surface hit using the triangles around it
+    /// - Returns: nil if no plane could be fitted or the feature lies outside the search radius
+    static func refine(position: Vector3, normal: Vector3, near triangles: [Triangle]) -> PickRefinement? {
+        let faces = self.faces(of: triangles, seedNormal: normal)
+        guard let hitFace = faces.first, let hitPlane = FittedPlane(hitFace) else { return nil }
+
+        // Only faces that meet the hit face at an angle form an edge (not the far side of a thin wall)
+        let limit = cos(featureAngle * .pi / 180)
+        let crossing = faces.dropFirst()
+            .compactMap(FittedPlane.init)
+            .filter { abs($0.normal.dot(hitPlane.normal)) < limit }
+
+        var candidates: [Candidate] = []
+        if crossing.count >= 2, let point = corner(hitPlane, crossing[0], crossing[1]) {
+            candidates.append((point.position, .corner, point.uncertainty, [hitPlane, crossing[0], crossing[1]]))
+        }
+        var edges: [Candidate] = []
+        for other in crossing {
+            if let point = edge(hitPlane, other, closestTo: position) {
+                edges.append((point.position, .edge, point.uncertainty, [hitPlane, other]))
+            }
+        }
+        candidates += edges.sorted { $0.position.distance(to: position) < $1.position.distance(to: position) }
+        candidates.append((hitPlane.project(position), .plane, hitPlane.standardError, [hitPlane]))
+
+        // The most specific feature within reach wins
+        guard let best = candidates.first(where: { $0.position.distance(to: position) <= searchRadius }) else {
+            return nil
+        }
+        return PickRefinement(
+            original: position,
+            position: best.position,
+            normal: hitPlane.normal.dot(normal) < 0 ? -hitPlane.normal : hitPlane.normal,
+            feature: best.feature,
+            uncertainty: best.uncertainty,
+            sampleCount: best.planes.reduce(0) { $0 + $1.samples }
+        )
+    }
+
+    private typealias Candidate = (position: Vector3, feature: Feature, uncertainty: Double, planes: [FittedPlane])
+
+    // MARK: - Faces
+
+    /// Triangles of one face, grouped by normal direction
+    struct Face {
+        var triangles: [Triangle]
+        var area: Double
+
+        var vertices: [Vector3] {
+            Array(Set(triangles.flatMap { [$0.v1, $0.v2, $0.v3] }))
+        }
+    }
+
+    /// Group triangles into up to three faces; the first face contains the hit normal
+    static func faces(of triangles: [Triangle], seedNormal: Vector3) -> [Face] {
+        let threshold = cos(featureAngle * .pi / 180)
+        let totalArea = triangles.reduce(0) { $0 + $1.area() }
+        guard totalArea > 0 else { return [] }
+
+        // Normals from the vertices: stored normals may be missing or inconsistent
+        var remaining = triangles
+            .filter { $0.area() > 0 }
+            .map { (triangle: $0, normal: Triangle.calculateNormal(v1: $0.v1, v2: $0.v2, v3: $0.v3)) }
+        var faces: [Face] = []
+        var seed: Vector3? = seedNormal.normalized()
+
+        while let normal = seed, faces.count < 3 {
+            let members = remaining.filter { $0.normal.dot(normal) >= threshold }.map { $0.triangle }
+            remaining.removeAll { $0.normal.dot(normal) >= threshold }
+
+            let area = members.reduce(0) { $0 + $1.area() }
+            // The hit face is always kept, other faces need enough area to be more than noise
+            if faces.isEmpty || area >= totalArea * minimumAreaShare {
+                faces.append(Face(triangles: members, area: area))
+            }
+            seed = remaining.max { $0.triangle.area() < $1.triangle.area() }?.normal
+        }
+        return faces.first?.triangles.isEmpty == false ? faces : []
+    }
+
+    // MARK: - Planes
+
+    /// Least-squares plane of a face with its residual
+    struct FittedPlane {
+        let normal: Vector3
+        /// Plane offset: normal · x = offset
+        let offset: Double
+        /// RMS distance of the face vertices from the plane
+        let residual: Double
+        let samples: Int
+
+        init?(_ face: Face) {
+            let vertices = face.vertices
+            guard let fit = ToleranceEvaluator.fitPlane(vertices) else { return nil }
+            let offset = fit.normal.dot(fit.origin)
+            let squares = vertices.reduce(0.0) { sum, vertex in
+                let distance = fit.normal.dot(vertex) - offset
+                return sum + distance * distance
+            }
+            self.normal = fit.normal
+            self.offset = offset
+            self.residual = (squares / Double(vertices.count)).squareRoot()
+            self.samples = vertices.count
+        }
+
+        /// Standard error of the plane position
+        var standardError: Double {
+            residual / Double(samples).squareRoot()
+        }
+
+        func project(_ point: Vector3) -> Vector3 {
+            point - normal * (normal.dot(point) - offset)
+        }
+    }
+
+    /// Point on the intersection line of two planes closest to a point
+    static func edge(_ a: FittedPlane, _ b: FittedPlane, closestTo point: Vector3) -> (position: Vector3, uncertainty: Double)? {
+        let direction = a.normal.cross(b.normal)
+        let sine = direction.length
+        guard sine > 1e-6 else { return nil }
+        let unit = direction / sine
+        guard let position = solve(rows: (a.normal, b.normal, unit), values: (a.offset, b.offset, unit.dot(point))) else {
+            return nil
+        }
+        let error = (a.standardError * a.standardError + b.standardError * b.standardError).squareRoot()
+        return (position, error / sine)
+    }
+
+    /// Common point of three planes
+    static func corner(_ a: FittedPlane, _ b: FittedPlane, _ c: FittedPlane) -> (position: Vector3, uncertainty: Double)? {
+        let determinant = abs(a.normal.dot(b.normal.cross(c.normal)))
+        guard determinant >= minimumCornerDeterminant,
+              let position = solve(rows: (a.normal, b.normal, c.normal), values: (a.offset, b.offset, c.offset)) else {
+            return nil
+        }
+        let error = [a, b, c].reduce(0.0) { $0 + $1.standardError * $1.standardError }.squareRoot()
+        return (position, error / determinant)
+    }
+
+    /// Solve the 3×3 linear system with the given rows (Cramer's rule)
+    private static func solve(rows: (Vector3, Vector3, Vector3), values: (Double, Double, Double)) -> Vector3? {
+        let (r0, r1, r2) = rows
+        let determinant = r0.dot(r1.cross(r2))
+        guard abs(determinant) > 1e-12 else { return nil }
+        // Columns of the inverse are the cross products of the rows
+        let solution = r1.cross(r2) * values.0 + r2.cross(r0) * values.1 + r0.cross(r1) * values.2
+        return solution / determinant
+    }
+}
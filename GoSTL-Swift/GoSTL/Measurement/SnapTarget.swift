@@ -0,0 +1,69 @@
+import Foundation
+
+/// Kind of geometry that picked points can snap to (toggled in the selection filter)
+enum SnapTarget: String, CaseIterable, Identifiable {
+    case vertices
+    case edges
+    case measurementPoints
+    case cutVertices
+
+    var id: String { rawValue }
+
+    var title: String {
+        switch self {
+        case .vertices: return "Mesh Vertices"
+        case .edges: return "Feature Edges"
+        case .measurementPoints: return "Measurement Points"
+        case .cutVertices: return "Cut Vertices"
+        }
+    }
+
+    /// Label of the filter chips in the measurement overlay
+    var shortTitle: String {
+        switch self {
+        case .vertices: return "Vertices"
+        case .edges: return "Edges"
+        case .measurementPoints: return "Points"
+        case .cutVertices: return "Cuts"
+        }
+    }
+
+    /// Edges whose faces meet at more than this angle are feature edges (degrees)
+    static let featureEdgeAngle: Double = 30.0
+
+    /// Closest point on a feature or open edge of the triangles
+    ///
+    /// Only edges whose two faces are both among the triangles can be told apart from open edges,
+    /// so pass every triangle within `maxDistance` of the position.
+    static func closestEdgePoint(to position: Vector3, triangles: [Triangle], maxDistance: Double) -> Vector3? {
+        var normals: [Edge: [Vector3]] = [:]
+        for triangle in triangles {
+            let normal = Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3)
+            for (a, b) in [(triangle.v1, triangle.v2), (triangle.v2, triangle.v3), (triangle.v3, triangle.v1)] {
+                normals[Edge(a, b), default: []].append(normal)
+            }
+        }
+
+        let limit = cos(featureEdgeAngle * .pi / 180)
+        var best: (point: Vector3, distance: Double)?
+        for (edge, faceNormals) in normals {
+            let isFeature = faceNormals.count != 2 || faceNormals[0].dot(faceNormals[1]) < limit
+            guard isFeature else { continue }
+
+            let point = closestPoint(onSegment: (edge.start, edge.end), to: position)
+            let distance = point.distance(to: position)
+            if distance <= maxDistance && distance < (best?.distance ?? .infinity) {
+                best = (point, distance)
+            }
+        }
+        return best?.point
+    }
+
+    private static func closestPoint(onSegment segment: (Vector3, Vector3), to point: Vector3) -> Vector3 {
+        let direction = segment.1 - segment.0
+        let lengthSquared = direction.dot(direction)
+        guard lengthSquared > 0 else { return segment.0 }
+        let t = min(max((point - segment.0).dot(direction) / lengthSquared, 0), 1)
+        return segment.0 + direction * t
+    }
+}
@@ -0,0 +1,160 @@
+import Foundation
+
+/// A measurement template applied to a batch of parts: guided picks for the current part and the
+/// results of the parts done so far
+struct TemplateRun {
+    /// Measured values of one part, one per template column (nil where a feature was skipped or degenerate)
+    struct PartResult: Equatable {
+        let name: String
+        let values: [Double?]
+        /// Column indices outside their tolerance
+        let failures: Set<Int>
+
+        var passed: Bool {
+            failures.isEmpty
+        }
+    }
+
+    let template: MeasurementTemplate
+    private(set) var parts: [PartResult] = []
+
+    /// Index of the feature being picked (equal to the feature count when the part is complete)
+    private(set) var featureIndex = 0
+    /// Points picked so far for the current feature
+    private(set) var points: [MeasurementPoint] = []
+    /// Features of the current part resolved so far
+    private(set) var entities: [String: ReferenceEntity.Kind] = [:]
+    /// Diameters of the hole features of the current part
+    private(set) var diameters: [String: Double] = [:]
+    /// Feedback from the last pick (e.g. why a feature could not be created)
+    private(set) var statusMessage: String?
+
+    init(template: MeasurementTemplate) {
+        self.template = template
+    }
+
+    var currentFeature: MeasurementTemplate.Feature? {
+        template.features.indices.contains(featureIndex) ? template.features[featureIndex] : nil
+    }
+
+    /// What to pick next, e.g. "hole1 center: Click 3 points around the rim (1 / 3)"
+    var prompt: String {
+        guard let feature = currentFeature else { return "All features picked" }
+        return "\(feature.name): \(feature.kind.instruction) (\(points.count) / \(feature.kind.pointsNeeded))"
+    }
+
+    /// Progress through the features of the current part, e.g. "Feature 2 of 5"
+    var progressText: String {
+        "Feature \(min(featureIndex + 1, template.features.count)) of \(template.features.count)"
+    }
+
+    // MARK: - Picking
+
+    /// Add a picked point to the current feature
+    /// - Returns: true when the pick completed the last feature of the part
+    mutating func addPoint(_ point: MeasurementPoint) -> Bool {
+        guard let feature = currentFeature else { return false }
+        statusMessage = nil
+        points.append(point)
+        guard points.count >= feature.kind.pointsNeeded else { return false }
+
+        if let entity = feature.kind.entity(from: points) {
+            entities[feature.name] = entity
+            if feature.kind == .hole, let circle = Circle.fit(points: points.map(\.position)) {
+                diameters[feature.name] = circle.radius * 2
+            }
+        } else {
+            statusMessage = "\(feature.name): the points are degenerate, feature skipped"
+        }
+        return advance()
+    }
+
+    /// Leave the current feature out of this part (its columns stay empty)
+    /// - Returns: true when it was the last feature of the part
+    mutating func skipFeature() -> Bool {
+        guard currentFeature != nil else { return false }
+        statusMessage = nil
+        return advance()
+    }
+
+    /// Drop the picks of the current part and start again with the first feature
+    mutating func restartPart() {
+        featureIndex = 0
+        points = []
+        entities = [:]
+        diameters = [:]
+        statusMessage = nil
+    }
+
+    private mutating func advance() -> Bool {
+        points = []
+        featureIndex += 1
+        return featureIndex >= template.features.count
+    }
+
+    // MARK: - Results
+
+    /// Evaluate the picked features, record the part and start the next one
+    /// - Returns: The recorded result
+    @discardableResult
+    mutating func completePart(name: String) -> PartResult {
+        let result = evaluate(name: name)
+        parts.append(result)
+        restartPart()
+        return result
+    }
+
+    /// Values of the current part's columns from the features picked so far
+    func evaluate(name: String) -> PartResult {
+        var values: [Double?] = []
+        var failures: Set<Int> = []
+        for (index, column) in template.columns.enumerated() {
+            let value: Double?
+            let limits: (nominal: Double?, tolerance: Double?)
+            switch column.source {
+            case .diameter(let feature):
+                value = diameters[feature]
+                limits = (nil, nil)
+            case .dimension(let dimension):
+                value = measure(dimension)
+                limits = (dimension.nominal, dimension.tolerance)
+            }
+            values.append(value)
+            if let value, let nominal = limits.nominal, let tolerance = limits.tolerance, abs(value - nominal) > tolerance {
+                failures.insert(index)
+            }
+        }
+        return PartResult(name: name, values: values, failures: failures)
+    }
+
+    /// Distance or angle of a dimension between two picked features
+    func measure(_ dimension: MeasurementTemplate.Dimension) -> Double? {
+        guard let result = line(for: dimension) else { return nil }
+        return dimension.quantity == .angle ? result.angle : result.distance
+    }
+
+    /// Measurement between the two features of a dimension (nil if either was not picked)
+    func line(for dimension: MeasurementTemplate.Dimension) -> ReferenceMeasurementResult? {
+        guard let first = entities[dimension.first], let second = entities[dimension.second] else { return nil }
+        return ReferenceEntity.Kind.measure(first, second)
+    }
+
+    /// Combined table of all parts, one row per part with a pass/fail column when tolerances are set
+    func csv(format: CSVFormat = .standard) -> String {
+        let columns = template.columns
+        let hasTolerances = template.dimensions.contains { $0.nominal != nil && $0.tolerance != nil }
+
+        var header = ["part"] + columns.map { "\($0.title) (\($0.unit))" }
+        if hasTolerances {
+            header.append("result")
+        }
+        let rows = parts.map { part -> String in
+            var fields = [part.name] + part.values.map { $0.map { format.number($0, decimals: 4) } ?? "" }
+            if hasTolerances {
+                fields.append(part.passed ? "pass" : "fail")
+            }
+            return format.row(fields)
+        }
+        return ([format.row(header)] + rows).joined(separator: "\n") + "\n"
+    }
+}
@@ -0,0 +1,72 @@
+import Foundation
+import simd
+
+/// Material thickness at one surface point: a ray cast inward along the face normal to where it
+/// leaves the solid through the opposite wall
+///
+/// Like `STLModel.minimumWallThickness`, only a ray that leaves through the back of a face counts,
+/// so internal faces and inverted normals give no reading instead of a wrong one.
+enum ThicknessProbe {
+    struct Result: Equatable {
+        /// Probed surface point
+        let entry: Vector3
+        /// Where the inward ray leaves the solid
+        let exit: Vector3
+        /// Outward normal of the probed face
+        let normal: Vector3
+
+        var thickness: Double {
+            entry.distance(to: exit)
+        }
+    }
+
+    /// Start the inward ray just below the surface so it does not hit the probed face again
+    static let startOffset: Float = 1e-4
+
+    /// Probe the surface point hit by a view ray (e.g. a click), nil if the ray misses the model or
+    /// the inward ray finds no opposite wall
+    static func probe(ray: Ray, triangles: [Triangle], accelerator: SpatialAccelerator? = nil) -> Result? {
+        guard let hit = closestHit(ray: ray, triangles: triangles, accelerator: accelerator) else { return nil }
+        return probe(at: hit.position, normal: triangles[hit.triangleIndex].normal, triangles: triangles, accelerator: accelerator)
+    }
+
+    /// Probe a surface point with the outward normal of its face
+    static func probe(at position: Vector3, normal: Vector3, triangles: [Triangle], accelerator: SpatialAccelerator? = nil) -> Result? {
+        let inward = -normal.normalized()
+        guard inward.length > 0.5 else { return nil }
+
+        let direction = inward.float3
+        let ray = Ray(origin: position.float3 + direction * startOffset, direction: direction)
+        guard let hit = closestHit(ray: ray, triangles: triangles, accelerator: accelerator),
+              hit.normal.dot(inward) > 0 else {
+            return nil
+        }
+        return Result(entry: position, exit: position + inward * Double(hit.distance + startOffset), normal: -inward)
+    }
+
+    /// Distance measurement from the probed point to the exit point
+    static func measurement(for result: Result) -> Measurement {
+        let points = [
+            MeasurementPoint(position: result.entry, normal: result.normal),
+            MeasurementPoint(position: result.exit, normal: -result.normal)
+        ]
+        var measurement = Measurement(type: .distance, points: points, value: result.thickness)
+        measurement.note = "Thickness"
+        return measurement
+    }
+
+    /// First triangle hit by a ray (through the accelerator when there is one)
+    static func closestHit(ray: Ray, triangles: [Triangle], accelerator: SpatialAccelerator?) -> (triangleIndex: Int, position: Vector3, normal: Vector3, distance: Float)? {
+        if let accelerator {
+            return accelerator.raycast(ray: ray)
+        }
+
+        var closest: (triangleIndex: Int, position: Vector3, normal: Vector3, distance: Float)?
+        for (index, triangle) in triangles.enumerated() {
+            guard let distance = triangle.intersect(ray: ray), distance < closest?.distance ?? .infinity else { continue }
+            let position = ray.origin + ray.direction * distance
+            closest = (index, Vector3(Double(position.x), Double(position.y), Double(position.z)), triangle.normal, distance)
+        }
+        return closest
+    }
+}
@@ -6,6 +6,8 @@ struct ModelAnalysis {
     var dimensions: Vector3
     var volume: Double
     var surfaceArea: Double
+    /// Surface area by face orientation (up, down, vertical)
+    var orientationAreas: OrientationAreas
     var triangleCount: Int
     var edgeCount: Int
     var minEdgeLength: Double
@@ -52,7 +54,8 @@ struct ModelAnalysis {
 
 extension STLModel {
     /// Perform comprehensive analysis of the model
-    func analyze() -> ModelAnalysis {
+    /// - Parameter verticalTolerance: Angle from vertical within which faces count as vertical (degrees)
+    func analyze(verticalTolerance: Double = OrientationAreas.defaultVerticalTolerance) -> ModelAnalysis {
         let bbox = boundingBox()
         let edges = edgeStatistics()
 
@@ -61,6 +64,7 @@ extension STLModel {
             dimensions: bbox.size,
             volume: volume(),
             surfaceArea: surfaceArea(),
+            orientationAreas: orientationAreas(verticalTolerance: verticalTolerance),
             triangleCount: triangleCount,
             edgeCount: edges.count,
             minEdgeLength: edges.min,
@@ -0,0 +1,183 @@
+import Foundation
+import simd
+
+/// A reference part printed to calibrate a printer, defined by its nominal outer dimensions
+///
+/// The scan of the printed part is measured along the model axes, so it has to be oriented as it
+/// was printed (X and Y on the bed, Z up).
+struct CalibrationArtifact: Equatable, Identifiable {
+    let id: String
+    let name: String
+    /// Nominal size along X, Y and Z (mm)
+    var size: Vector3
+    /// How to print and scan the artifact
+    let note: String
+
+    static let cube20 = CalibrationArtifact(
+        id: "cube20",
+        name: "20 mm Calibration Cube",
+        size: Vector3(20, 20, 20),
+        note: "The common XYZ calibration cube. Small, so fixed offsets such as elephant foot weigh in."
+    )
+
+    static let cube50 = CalibrationArtifact(
+        id: "cube50",
+        name: "50 mm Calibration Cube",
+        size: Vector3(50, 50, 50),
+        note: "A larger cube separates scale errors from fixed offsets better than the 20 mm cube."
+    )
+
+    static let plate100 = CalibrationArtifact(
+        id: "plate100",
+        name: "100 × 100 × 10 mm Test Plate",
+        size: Vector3(100, 100, 10),
+        note: "Flat test part with a long X and Y span for the bed axes. Z is better checked with a cube."
+    )
+
+    /// Shipped artifacts, smallest first
+    static let builtIn: [CalibrationArtifact] = [cube20, cube50, plate100]
+
+    static let customID = "custom"
+
+    var isCustom: Bool {
+        id == Self.customID
+    }
+
+    /// A block of any size (e.g. the envelope of an own test part)
+    static func custom(size: Vector3) -> CalibrationArtifact {
+        CalibrationArtifact(
+            id: customID,
+            name: "Custom Block",
+            size: size,
+            note: "Enter the nominal outer dimensions of the printed block."
+        )
+    }
+}
+
+/// Axis-wise scale error of a scanned calibration artifact and the compensation that cancels it
+struct CalibrationReport: Equatable {
+    struct Axis: Equatable {
+        /// "X", "Y" or "Z"
+        let name: String
+        let nominal: Double
+        let measured: Double
+        /// Measured between opposite faces; false when a side had no face and the bounding box was used
+        let fromFaces: Bool
+
+        /// Measured minus nominal (mm)
+        var error: Double {
+            measured - nominal
+        }
+
+        var errorPercent: Double {
+            error / nominal * 100
+        }
+
+        /// Slicer scale (percent) that makes the next print nominal, e.g. 100.5 for a part 0.5 % small
+        var compensation: Double {
+            nominal / measured * 100
+        }
+    }
+
+    let artifact: CalibrationArtifact
+    let axes: [Axis]
+    /// Share of the surface area facing along an axis (low when the scan is rotated)
+    let alignedFraction: Double
+
+    /// Scans with less axis-facing area than this are reported as not aligned
+    static let minimumAlignedFraction = 0.5
+    /// Errors above this (percent) point at a wrong artifact, orientation or unit rather than the printer
+    static let plausibleErrorPercent = 10.0
+
+    var isAligned: Bool {
+        alignedFraction >= Self.minimumAlignedFraction
+    }
+
+    /// Single scale for slicers with one XY setting (mean of X and Y)
+    var xyCompensation: Double {
+        (axes[0].compensation + axes[1].compensation) / 2
+    }
+
+    /// Problems that make the result doubtful
+    var warnings: [String] {
+        var warnings: [String] = []
+        if !isAligned {
+            warnings.append("The faces are not parallel to the axes. Align the scan first (Tools > Align to Principal Axes).")
+        }
+        let guessed = axes.filter { !$0.fromFaces }.map(\.name)
+        if !guessed.isEmpty {
+            warnings.append("No opposite faces along \(guessed.joined(separator: ", ")); the bounding box was used.")
+        }
+        if axes.contains(where: { abs($0.errorPercent) > Self.plausibleErrorPercent }) {
+            warnings.append("An axis is off by more than \(Int(Self.plausibleErrorPercent)) %. Check the artifact, its orientation and the model units.")
+        }
+        return warnings
+    }
+
+    /// Plain text summary, e.g. for pasting into printer notes
+    var text: String {
+        var lines = ["Calibration: \(artifact.name)"]
+        for axis in axes {
+            lines.append(String(
+                format: "%@: nominal %.3f mm, measured %.3f mm, error %+.3f mm (%+.2f %%), compensation %.2f %%",
+                axis.name, axis.nominal, axis.measured, axis.error, axis.errorPercent, axis.compensation
+            ))
+        }
+        lines.append(String(format: "XY compensation: %.2f %%", xyCompensation))
+        lines += warnings
+        return lines.joined(separator: "\n")
+    }
+}
+
+/// Measures the scan of a printed calibration artifact
+enum CalibrationCheck {
+    /// Triangles within this many degrees of an axis count as a face across that axis
+    static let faceAngle = 10.0
+
+    /// Measure the size of a scan along X, Y and Z and compare it to the artifact
+    ///
+    /// Each size is the distance between the area-weighted mean planes of the faces looking
+    /// along +axis and -axis, so scan noise, rounded edges and stray vertices average out.
+    /// - Returns: nil for an empty mesh or a non-positive nominal size
+    static func measure(triangles: [Triangle], artifact: CalibrationArtifact) -> CalibrationReport? {
+        let nominal = [artifact.size.x, artifact.size.y, artifact.size.z]
+        guard !triangles.isEmpty, nominal.allSatisfy({ $0 > 0 }) else { return nil }
+
+        let faceCos = cos(faceAngle * .pi / 180)
+        var areas = [[Double]](repeating: [0, 0], count: 3)
+        var offsets = [[Double]](repeating: [0, 0], count: 3)
+        var minimum = SIMD3<Double>(repeating: .infinity)
+        var maximum = SIMD3<Double>(repeating: -.infinity)
+        var totalArea = 0.0
+        var alignedArea = 0.0
+
+        for triangle in triangles {
+            let a = triangle.v1.value, b = triangle.v2.value, c = triangle.v3.value
+            minimum = simd_min(minimum, simd_min(a, simd_min(b, c)))
+            maximum = simd_max(maximum, simd_max(a, simd_max(b, c)))
+
+            let area = triangle.area()
+            guard area > 0 else { continue }
+            totalArea += area
+            let normal = Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3).value
+            let center = (a + b + c) / 3
+            for axis in 0..<3 where abs(normal[axis]) >= faceCos {
+                let side = normal[axis] > 0 ? 1 : 0
+                areas[axis][side] += area
+                offsets[axis][side] += center[axis] * area
+                alignedArea += area
+            }
+        }
+        guard totalArea > 0 else { return nil }
+
+        let names = ["X", "Y", "Z"]
+        let axes = (0..<3).map { axis -> CalibrationReport.Axis in
+            let fromFaces = areas[axis][0] > 0 && areas[axis][1] > 0
+            let measured = fromFaces
+                ? offsets[axis][1] / areas[axis][1] - offsets[axis][0] / areas[axis][0]
+                : maximum[axis] - minimum[axis]
+            return CalibrationReport.Axis(name: names[axis], nominal: nominal[axis], measured: measured, fromFaces: fromFaces)
+        }
+        return CalibrationReport(artifact: artifact, axes: axes, alignedFraction: alignedArea / totalArea)
+    }
+}
@@ -0,0 +1,96 @@
+import Foundation
+
+/// Result of checking two meshes against each other (e.g. an enclosure and a PCB)
+struct ClearanceResult: Equatable {
+    /// Smallest distance between the surfaces (0 where they interpenetrate)
+    let minimumDistance: Double
+    /// Closest points on the model and on the other model
+    let pointOnModel: Vector3
+    let pointOnOther: Vector3
+    /// Triangles of each mesh that pass through the other one
+    let intersectingTriangles: [Int]
+    let otherIntersectingTriangles: [Int]
+
+    var isIntersecting: Bool {
+        !intersectingTriangles.isEmpty
+    }
+
+    /// Middle of the closest points
+    var location: Vector3 {
+        (pointOnModel + pointOnOther) * 0.5
+    }
+
+    /// Extent of the interpenetrating region on the model, nil without intersections
+    func intersectionBounds(in model: STLModel) -> BoundingBox? {
+        let points = intersectingTriangles.flatMap { [model.triangles[$0].v1, model.triangles[$0].v2, model.triangles[$0].v3] }
+        return points.isEmpty ? nil : BoundingBox(points: points)
+    }
+
+    /// Distance measurement across the minimum clearance (nil when the models interpenetrate or touch)
+    func measurement() -> Measurement? {
+        guard !isIntersecting, minimumDistance > 0 else { return nil }
+        let direction = (pointOnOther - pointOnModel).normalized()
+        let points = [
+            MeasurementPoint(position: pointOnModel, normal: direction, isAirPoint: true),
+            MeasurementPoint(position: pointOnOther, normal: -direction, isAirPoint: true)
+        ]
+        return Measurement(type: .distance, points: points, value: minimumDistance)
+    }
+}
+
+/// Minimum clearance and interpenetration between two meshes
+///
+/// Triangles of the model are visited nearest-first to the other mesh, so the search radius around
+/// each triangle shrinks to the best distance found so far; the other mesh is queried through its BVH.
+enum ClearanceCheck {
+    static func check(
+        model: [Triangle],
+        other: [Triangle],
+        otherAccelerator: SpatialAccelerator? = nil,
+        job: JobContext? = nil
+    ) throws -> ClearanceResult? {
+        guard !model.isEmpty, !other.isEmpty else { return nil }
+
+        let accelerator = otherAccelerator ?? SpatialAccelerator(triangles: other)
+        let otherBounds = BoundingBox(points: other.flatMap { [$0.v1, $0.v2, $0.v3] })
+
+        let bounds = model.map { BoundingBox(points: [$0.v1, $0.v2, $0.v3]) }
+        let gaps = bounds.map { SpatialAccelerator.boxDistance($0, otherBounds) }
+        let order = model.indices.sorted { gaps[$0] < gaps[$1] }
+
+        var best: TriangleDistance.Result?
+        var intersecting = Set<Int>()
+        var otherIntersecting = Set<Int>()
+
+        for (step, index) in order.enumerated() {
+            let bestDistance = best?.distance ?? .greatestFiniteMagnitude
+            // Sorted by gap: no later triangle can come closer
+            if gaps[index] > bestDistance { break }
+
+            if step % 1024 == 0 {
+                try job?.checkCancellation()
+                job?.reportProgress(Double(step) / Double(order.count))
+            }
+
+            for candidate in accelerator.findTriangles(near: bounds[index], maxDistance: best?.distance ?? .greatestFiniteMagnitude) {
+                let result = TriangleDistance.closestPoints(model[index], other[candidate])
+                if result.intersects {
+                    intersecting.insert(index)
+                    otherIntersecting.insert(candidate)
+                }
+                if result.distance < best?.distance ?? .infinity {
+                    best = result
+                }
+            }
+        }
+
+        guard let best else { return nil }
+        return ClearanceResult(
+            minimumDistance: best.distance,
+            pointOnModel: best.pointA,
+            pointOnOther: best.pointB,
+            intersectingTriangles: intersecting.sorted(),
+            otherIntersectingTriangles: otherIntersecting.sorted()
+        )
+    }
+}
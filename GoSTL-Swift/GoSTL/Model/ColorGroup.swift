@@ -0,0 +1,51 @@
+import Foundation
+
+/// Triangles sharing one color, e.g. everything inside the same OpenSCAD color() call
+///
+/// Groups are identified by their color so hidden groups stay hidden when the file is re-rendered.
+struct ColorGroup: Identifiable, Equatable {
+    /// "#RRGGBBAA", or `uncoloredID` for triangles without a color
+    let id: String
+    let color: TriangleColor?
+    let triangleCount: Int
+
+    static let uncoloredID = "uncolored"
+
+    /// "#FF0000" (alpha only when translucent), or "No color"
+    var name: String {
+        guard color != nil else { return "No color" }
+        return id.hasSuffix("FF") ? String(id.dropLast(2)) : id
+    }
+
+    static func id(for color: TriangleColor?) -> String {
+        guard let color else { return uncoloredID }
+        let component = { (value: Float) in Int((min(max(value, 0), 1) * 255).rounded()) }
+        return String(format: "#%02X%02X%02X%02X", component(color.r), component(color.g), component(color.b), component(color.a))
+    }
+
+    /// Groups in order of first appearance
+    static func groups(in triangles: [Triangle]) -> [ColorGroup] {
+        var order: [String] = []
+        var colors: [String: TriangleColor] = [:]
+        var counts: [String: Int] = [:]
+
+        for triangle in triangles {
+            let key = id(for: triangle.color)
+            if counts[key] == nil {
+                order.append(key)
+                colors[key] = triangle.color
+            }
+            counts[key, default: 0] += 1
+        }
+
+        return order.map { ColorGroup(id: $0, color: colors[$0], triangleCount: counts[$0] ?? 0) }
+    }
+
+    /// The model without the triangles of hidden groups (the full model if that would leave nothing)
+    static func model(_ model: STLModel, hiding hidden: Set<String>) -> STLModel {
+        guard !hidden.isEmpty else { return model }
+
+        let visible = model.triangles.filter { !hidden.contains(id(for: $0.color)) }
+        return visible.isEmpty ? model : STLModel(triangles: visible, name: model.name, header: model.header)
+    }
+}
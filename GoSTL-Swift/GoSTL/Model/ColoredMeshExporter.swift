@@ -0,0 +1,219 @@
+import Foundation
+import simd
+
+/// Errors that can occur while exporting a mesh with baked colors
+enum ColoredMeshExportError: LocalizedError {
+    case emptyModel
+    case colorMismatch
+    case unsupportedFormat(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .emptyModel:
+            return "Cannot export empty model"
+        case .colorMismatch:
+            return "The analysis colors do not match the model; wait for the analysis to finish"
+        case .unsupportedFormat(let ext):
+            return "Cannot export colors as .\(ext); use .ply or .3mf"
+        }
+    }
+}
+
+/// Exports a mesh with analysis colors (draft angles, height, ...) baked in as vertex colors
+///
+/// Other viewers know nothing about GoSTL's analyses, but most show vertex colors: PLY with
+/// per-vertex RGBA (MeshLab, CloudCompare, Blender) or 3MF with a color group from the materials
+/// extension referenced per triangle corner (slicers, Windows 3D Viewer). Vertices are shared
+/// between triangles where both the position and the color match, so gradients stay smooth.
+enum ColoredMeshExporter {
+    enum Format: String, CaseIterable {
+        case ply
+        case threeMF = "3mf"
+
+        init?(url: URL) {
+            self.init(rawValue: url.pathExtension.lowercased())
+        }
+    }
+
+    /// Triangles indexed into shared, colored vertices
+    struct IndexedMesh {
+        var positions: [Vector3] = []
+        /// 8-bit sRGB(A) per vertex
+        var colors: [SIMD4<UInt8>] = []
+        var faces: [(Int, Int, Int)] = []
+    }
+
+    /// Write the triangles with their vertex colors; the format follows the file extension
+    /// - Parameter materialColor: Color for vertices without an analysis color (e.g. degenerate faces)
+    static func export(
+        triangles: [Triangle],
+        colors: VertexColors,
+        materialColor: SIMD3<Float>,
+        name: String,
+        to url: URL
+    ) throws {
+        guard let format = Format(url: url) else {
+            throw ColoredMeshExportError.unsupportedFormat(url.pathExtension)
+        }
+        let mesh = try indexedMesh(triangles: triangles, colors: colors, materialColor: materialColor)
+        let data: Data
+        switch format {
+        case .ply: data = plyData(mesh, name: name)
+        case .threeMF: data = threeMFData(mesh, name: name)
+        }
+        try data.write(to: url)
+    }
+
+    /// Shared vertices: one per distinct position and color
+    static func indexedMesh(triangles: [Triangle], colors: VertexColors, materialColor: SIMD3<Float>) throws -> IndexedMesh {
+        guard !triangles.isEmpty else { throw ColoredMeshExportError.emptyModel }
+        guard colors.triangleCount == triangles.count else { throw ColoredMeshExportError.colorMismatch }
+
+        let material = SIMD4<Float>(materialColor, 1)
+        var mesh = IndexedMesh()
+        var indices: [VertexKey: Int] = [:]
+        func index(of position: Vector3, color: SIMD4<Float>) -> Int {
+            let rgba = byteColor(color.w < 0.5 ? material : color)
+            let key = VertexKey(position: position, color: rgba)
+            if let existing = indices[key] {
+                return existing
+            }
+            mesh.positions.append(position)
+            mesh.colors.append(rgba)
+            indices[key] = mesh.positions.count - 1
+            return mesh.positions.count - 1
+        }
+
+        for (number, triangle) in triangles.enumerated() {
+            let (a, b, c) = colors.corners(ofTriangle: number)
+            mesh.faces.append((index(of: triangle.v1, color: a), index(of: triangle.v2, color: b), index(of: triangle.v3, color: c)))
+        }
+        return mesh
+    }
+
+    // MARK: - PLY
+
+    /// Binary little-endian PLY with float positions and uchar RGBA per vertex
+    static func plyData(_ mesh: IndexedMesh, name: String) -> Data {
+        let header = """
+            ply
+            format binary_little_endian 1.0
+            comment GoSTL analysis colors: \(name)
+            element vertex \(mesh.positions.count)
+            property float x
+            property float y
+            property float z
+            property uchar red
+            property uchar green
+            property uchar blue
+            property uchar alpha
+            element face \(mesh.faces.count)
+            property list uchar int vertex_indices
+            end_header
+
+            """
+        var data = Data(header.utf8)
+        data.reserveCapacity(data.count + mesh.positions.count * 16 + mesh.faces.count * 13)
+
+        for (position, color) in zip(mesh.positions, mesh.colors) {
+            for value in [position.x, position.y, position.z] {
+                withUnsafeBytes(of: Float(value).bitPattern.littleEndian) { data.append(contentsOf: $0) }
+            }
+            data.append(contentsOf: [color.x, color.y, color.z, color.w])
+        }
+        for face in mesh.faces {
+            data.append(3)
+            for index in [face.0, face.1, face.2] {
+                withUnsafeBytes(of: Int32(index).littleEndian) { data.append(contentsOf: $0) }
+            }
+        }
+        return data
+    }
+
+    // MARK: - 3MF
+
+    private static let modelPath = "3D/3dmodel.model"
+
+    /// 3MF package with one object whose triangle corners reference a color group
+    static func threeMFData(_ mesh: IndexedMesh, name: String) -> Data {
+        // Each distinct color once; triangles point at the colors of their corners
+        var palette: [SIMD4<UInt8>: Int] = [:]
+        var paletteColors: [SIMD4<UInt8>] = []
+        let colorIndex = mesh.colors.map { color -> Int in
+            if let index = palette[color] { return index }
+            paletteColors.append(color)
+            palette[color] = paletteColors.count - 1
+            return paletteColors.count - 1
+        }
+
+        var xml = """
+            <?xml version="1.0" encoding="UTF-8"?>
+            <model unit="millimeter" xml:lang="en-US" xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" \
+            xmlns:m="http://schemas.microsoft.com/3dmanufacturing/material/2015/02">
+             <metadata name="Title">\(escaped(name))</metadata>
+             <metadata name="Application">GoSTL</metadata>
+             <resources>
+              <m:colorgroup id="1">
+
+            """
+        for color in paletteColors {
+            xml += "   <m:color color=\"\(hex(color))\"/>\n"
+        }
+        xml += "  </m:colorgroup>\n  <object id=\"2\" type=\"model\" name=\"\(escaped(name))\">\n   <mesh>\n    <vertices>\n"
+        for position in mesh.positions {
+            xml += "     <vertex x=\"\(position.x)\" y=\"\(position.y)\" z=\"\(position.z)\"/>\n"
+        }
+        xml += "    </vertices>\n    <triangles>\n"
+        for (a, b, c) in mesh.faces {
+            xml += "     <triangle v1=\"\(a)\" v2=\"\(b)\" v3=\"\(c)\" pid=\"1\" "
+                + "p1=\"\(colorIndex[a])\" p2=\"\(colorIndex[b])\" p3=\"\(colorIndex[c])\"/>\n"
+        }
+        xml += "    </triangles>\n   </mesh>\n  </object>\n </resources>\n <build>\n  <item objectid=\"2\"/>\n </build>\n</model>\n"
+
+        let contentTypes = """
+            <?xml version="1.0" encoding="UTF-8"?>
+            <Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+             <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+             <Default Extension="model" ContentType="application/vnd.ms-package.3dmanufacturing-3dmodel+xml"/>
+            </Types>
+            """
+        let relationships = """
+            <?xml version="1.0" encoding="UTF-8"?>
+            <Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+             <Relationship Target="/\(modelPath)" Id="rel0" Type="http://schemas.microsoft.com/3dmanufacturing/2013/01/3dmodel"/>
+            </Relationships>
+            """
+
+        var writer = ZipWriter()
+        writer.add(path: "[Content_Types].xml", contents: Data(contentTypes.utf8))
+        writer.add(path: "_rels/.rels", contents: Data(relationships.utf8))
+        writer.add(path: modelPath, contents: Data(xml.utf8))
+        return writer.finish()
+    }
+
+    // MARK: - Helpers
+
+    private struct VertexKey: Hashable {
+        let position: Vector3
+        let color: SIMD4<UInt8>
+    }
+
+    static func byteColor(_ color: SIMD4<Float>) -> SIMD4<UInt8> {
+        let clamped = simd_clamp(color, SIMD4<Float>(repeating: 0), SIMD4<Float>(repeating: 1))
+        let scaled = (clamped * 255).rounded(.toNearestOrAwayFromZero)
+        return SIMD4<UInt8>(UInt8(scaled.x), UInt8(scaled.y), UInt8(scaled.z), UInt8(scaled.w))
+    }
+
+    /// `#RRGGBB`, or `#RRGGBBAA` for translucent colors
+    static func hex(_ color: SIMD4<UInt8>) -> String {
+        let rgb = String(format: "#%02X%02X%02X", color.x, color.y, color.z)
+        return color.w == 255 ? rgb : rgb + String(format: "%02X", color.w)
+    }
+
+    private static func escaped(_ text: String) -> String {
+        text.replacingOccurrences(of: "&", with: "&amp;")
+            .replacingOccurrences(of: "<", with: "&lt;")
+            .replacingOccurrences(of: ">", with: "&gt;")
+            .replacingOccurrences(of: "\"", with: "&quot;")
+    }
+}
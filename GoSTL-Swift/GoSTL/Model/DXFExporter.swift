@@ -0,0 +1,83 @@
+import Foundation
+import simd
+
+/// Writes section sketches as 2D DXF drawings (AutoCAD R12, millimeters)
+///
+/// Lines, arcs and circles become LINE, ARC and CIRCLE entities, which every CAD program imports
+/// as editable sketch geometry. Outer outlines go on layer PROFILE, holes on layer HOLES.
+enum DXFExporter {
+    static func dxf(_ sketch: SectionSketch) -> String {
+        var lines: [String] = []
+        func group(_ code: Int, _ value: String) {
+            lines.append(String(code))
+            lines.append(value)
+        }
+        func point(_ point: SectionSketch.Point, first code: Int = 10) {
+            group(code, format(point.x))
+            group(code + 10, format(point.y))
+            group(code + 20, "0")
+        }
+
+        group(0, "SECTION")
+        group(2, "HEADER")
+        group(9, "$ACADVER")
+        group(1, "AC1009")
+        group(9, "$INSUNITS")
+        group(70, "4")
+        group(0, "ENDSEC")
+
+        group(0, "SECTION")
+        group(2, "ENTITIES")
+        for loop in sketch.loops {
+            let layer = loop.isHole ? "HOLES" : "PROFILE"
+            for element in loop.elements {
+                switch element {
+                case .line(let from, let to):
+                    group(0, "LINE")
+                    group(8, layer)
+                    point(from)
+                    point(to, first: 11)
+                case .arc(let from, let to, let center, let sweep):
+                    // ARC runs counter-clockwise from its start to its end angle
+                    let (start, end) = sweep > 0 ? (from, to) : (to, from)
+                    group(0, "ARC")
+                    group(8, layer)
+                    point(center)
+                    group(40, format(simd_distance(from, center)))
+                    group(50, format(degrees(start - center)))
+                    group(51, format(degrees(end - center)))
+                case .circle(let center, let radius):
+                    group(0, "CIRCLE")
+                    group(8, layer)
+                    point(center)
+                    group(40, format(radius))
+                }
+            }
+        }
+        group(0, "ENDSEC")
+        group(0, "EOF")
+        return lines.joined(separator: "\n") + "\n"
+    }
+
+    static func write(_ sketch: SectionSketch, to url: URL) throws {
+        try dxf(sketch).write(to: url, atomically: true, encoding: .utf8)
+    }
+
+    /// Direction of a vector in degrees, 0 ..< 360
+    private static func degrees(_ vector: SectionSketch.Point) -> Double {
+        let angle = atan2(vector.y, vector.x) * 180 / .pi
+        return angle < 0 ? angle + 360 : angle
+    }
+
+    /// Coordinates with six decimals, without trailing zeros
+    private static func format(_ value: Double) -> String {
+        var text = String(format: "%.6f", value)
+        while text.hasSuffix("0") {
+            text.removeLast()
+        }
+        if text.hasSuffix(".") {
+            text.removeLast()
+        }
+        return text == "-0" ? "0" : text
+    }
+}
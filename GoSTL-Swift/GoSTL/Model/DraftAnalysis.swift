@@ -0,0 +1,154 @@
+import Foundation
+
+/// Draft angles of the model's faces relative to the direction a mold is pulled off
+/// (e.g. for silicone molds, casting or injection molding)
+///
+/// The draft of a face is the angle between the face and the pull direction: 90° for a face looking
+/// straight along the pull, 0° for a face parallel to it and negative for faces turned away from it.
+enum DraftAnalysis {
+    /// Direction the mold (or the part) is pulled off
+    enum PullDirection: String, CaseIterable {
+        case x, negativeX = "-x", y, negativeY = "-y", z, negativeZ = "-z"
+
+        var vector: Vector3 {
+            switch self {
+            case .x: return .unitX
+            case .negativeX: return -Vector3.unitX
+            case .y: return .unitY
+            case .negativeY: return -Vector3.unitY
+            case .z: return .unitZ
+            case .negativeZ: return -Vector3.unitZ
+            }
+        }
+
+        var displayName: String {
+            rawValue.hasPrefix("-") ? "−" + rawValue.dropFirst().uppercased() : "+" + rawValue.uppercased()
+        }
+    }
+
+    /// How a face releases from the mold
+    enum Category {
+        /// At least the minimum draft
+        case sufficient
+        /// Less than the minimum draft in either direction (including vertical walls)
+        case insufficient
+        /// Turned away from the pull direction: an undercut for a one-part mold, the other half of a two-part mold
+        case opposite
+    }
+
+    struct Result {
+        let pullDirection: PullDirection
+        /// Minimum required draft in degrees
+        let minimumAngle: Double
+        /// Draft of each triangle in degrees, nil for degenerate triangles
+        let angles: [Double?]
+        /// Triangles with less than the minimum draft
+        let insufficientTriangles: [Int]
+        let insufficientArea: Double
+        /// Triangles turned away from the pull direction by more than the minimum draft
+        let oppositeTriangles: [Int]
+        let oppositeArea: Double
+        let totalArea: Double
+    }
+
+    /// Draft commonly recommended for silicone molds and injection molding
+    static let defaultMinimumAngle = 1.0
+
+    /// Minimum draft angles offered in the menus (degrees)
+    static let minimumAngleOptions: [Double] = [0.5, 1, 2, 3, 5]
+
+    static let sufficientColor = TriangleColor(0.3, 0.78, 0.35)
+    static let oppositeColor = TriangleColor(0.35, 0.55, 0.95)
+    /// Insufficient draft runs from red (vertical wall) to yellow (just below the minimum)
+    static let verticalColor = TriangleColor(0.95, 0.15, 0.1)
+    static let nearMinimumColor = TriangleColor(1.0, 0.85, 0.2)
+
+    /// Draft angle in degrees of a face with the given unit normal
+    static func draftAngle(normal: Vector3, pull: Vector3) -> Double {
+        asin(Swift.max(-1, Swift.min(1, normal.dot(pull)))) * 180 / .pi
+    }
+
+    static func category(angle: Double, minimumAngle: Double) -> Category {
+        if angle >= minimumAngle {
+            return .sufficient
+        }
+        return angle <= -minimumAngle ? .opposite : .insufficient
+    }
+
+    static func analyze(
+        _ triangles: [Triangle],
+        pullDirection: PullDirection,
+        minimumAngle: Double = defaultMinimumAngle,
+        job: JobContext? = nil
+    ) throws -> Result {
+        let pull = pullDirection.vector
+        var angles: [Double?] = []
+        angles.reserveCapacity(triangles.count)
+        var insufficient: [Int] = [], opposite: [Int] = []
+        var insufficientArea = 0.0, oppositeArea = 0.0, totalArea = 0.0
+
+        for (index, triangle) in triangles.enumerated() {
+            if index % 65536 == 0 {
+                try job?.checkCancellation()
+                job?.reportProgress(Double(index) / Double(triangles.count))
+            }
+
+            let normal = Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3)
+            guard normal.length > 0.5 else {
+                angles.append(nil)
+                continue
+            }
+
+            let angle = draftAngle(normal: normal, pull: pull)
+            let area = triangle.area()
+            angles.append(angle)
+            totalArea += area
+
+            switch category(angle: angle, minimumAngle: minimumAngle) {
+            case .sufficient:
+                break
+            case .insufficient:
+                insufficient.append(index)
+                insufficientArea += area
+            case .opposite:
+                opposite.append(index)
+                oppositeArea += area
+            }
+        }
+
+        return Result(
+            pullDirection: pullDirection,
+            minimumAngle: minimumAngle,
+            angles: angles,
+            insufficientTriangles: insufficient,
+            insufficientArea: insufficientArea,
+            oppositeTriangles: opposite,
+            oppositeArea: oppositeArea,
+            totalArea: totalArea
+        )
+    }
+
+    /// Face color for a draft angle
+    static func color(forAngle angle: Double, minimumAngle: Double) -> TriangleColor {
+        switch category(angle: angle, minimumAngle: minimumAngle) {
+        case .sufficient:
+            return sufficientColor
+        case .opposite:
+            return oppositeColor
+        case .insufficient:
+            let t = Float(minimumAngle > 0 ? Swift.min(abs(angle) / minimumAngle, 1) : 0)
+            return TriangleColor(
+                verticalColor.r + (nearMinimumColor.r - verticalColor.r) * t,
+                verticalColor.g + (nearMinimumColor.g - verticalColor.g) * t,
+                verticalColor.b + (nearMinimumColor.b - verticalColor.b) * t
+            )
+        }
+    }
+
+    /// Vertex colors showing the draft of every face (degenerate triangles in the material color)
+    static func colors(for result: Result) -> VertexColors {
+        VertexColors(perTriangle: result.angles.map { angle in
+            angle.map { color(forAngle: $0, minimumAngle: result.minimumAngle) }
+        })
+    }
+}
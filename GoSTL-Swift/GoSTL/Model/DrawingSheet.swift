@@ -0,0 +1,191 @@
+import CoreGraphics
+import CoreText
+import Foundation
+
+/// A 2D drawing sheet (technical drawings) in millimeters, origin top-left, y down
+///
+/// The sheet only collects primitives; `svg()`, `pdf()` and `image()` write them out so all formats show the same drawing.
+struct DrawingSheet {
+    enum TextAnchor: String {
+        case start, middle, end
+    }
+
+    enum Primitive {
+        case line(CGPoint, CGPoint, weight: Double)
+        case rectangle(CGRect, weight: Double)
+        /// Filled polygon (dimension arrowheads)
+        case polygon([CGPoint])
+        /// Text with its baseline at the point; rotation in degrees, counter-clockwise on the sheet
+        case text(String, at: CGPoint, size: Double, anchor: TextAnchor, rotation: Double)
+    }
+
+    /// Sheet size in millimeters
+    let size: CGSize
+    private(set) var primitives: [Primitive] = []
+
+    init(size: CGSize) {
+        self.size = size
+    }
+
+    // MARK: - Drawing
+
+    mutating func line(from start: CGPoint, to end: CGPoint, weight: Double) {
+        primitives.append(.line(start, end, weight: weight))
+    }
+
+    mutating func rectangle(_ rect: CGRect, weight: Double) {
+        primitives.append(.rectangle(rect, weight: weight))
+    }
+
+    mutating func polygon(_ points: [CGPoint]) {
+        primitives.append(.polygon(points))
+    }
+
+    mutating func text(_ text: String, at point: CGPoint, size: Double, anchor: TextAnchor = .start, rotation: Double = 0) {
+        primitives.append(.text(text, at: point, size: size, anchor: anchor, rotation: rotation))
+    }
+
+    // MARK: - SVG
+
+    /// SVG document with the sheet size in millimeters
+    func svg(title: String? = nil) -> String {
+        let f = SVGExporter.format(_:) as (Double) -> String
+        let width = f(size.width)
+        let height = f(size.height)
+
+        var lines: [String] = []
+        lines.append("<?xml version=\"1.0\" encoding=\"UTF-8\"?>")
+        lines.append("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"\(width)mm\" height=\"\(height)mm\" viewBox=\"0 0 \(width) \(height)\">")
+        if let title {
+            lines.append("  <title>\(SVGExporter.escape(title))</title>")
+        }
+        lines.append("  <rect width=\"100%\" height=\"100%\" fill=\"white\"/>")
+        lines.append("  <g stroke=\"black\" stroke-linecap=\"round\" fill=\"none\">")
+
+        for primitive in primitives {
+            switch primitive {
+            case .line(let start, let end, let weight):
+                lines.append("    <line x1=\"\(f(start.x))\" y1=\"\(f(start.y))\" x2=\"\(f(end.x))\" y2=\"\(f(end.y))\" stroke-width=\"\(f(weight))\"/>")
+            case .rectangle(let rect, let weight):
+                lines.append("    <rect x=\"\(f(rect.minX))\" y=\"\(f(rect.minY))\" width=\"\(f(rect.width))\" height=\"\(f(rect.height))\" stroke-width=\"\(f(weight))\"/>")
+            case .polygon(let points):
+                let list = points.map { "\(f($0.x)),\(f($0.y))" }.joined(separator: " ")
+                lines.append("    <polygon points=\"\(list)\" fill=\"black\" stroke=\"none\"/>")
+            case .text(let text, let point, let size, let anchor, let rotation):
+                let transform = rotation == 0 ? "" : " transform=\"rotate(\(f(-rotation)) \(f(point.x)) \(f(point.y)))\""
+                lines.append("    <text x=\"\(f(point.x))\" y=\"\(f(point.y))\" font-family=\"Helvetica, Arial, sans-serif\" font-size=\"\(f(size))\" text-anchor=\"\(anchor.rawValue)\" fill=\"black\" stroke=\"none\"\(transform)>\(SVGExporter.escape(text))</text>")
+            }
+        }
+
+        lines.append("  </g>")
+        lines.append("</svg>")
+        return lines.joined(separator: "\n") + "\n"
+    }
+
+    // MARK: - PDF
+
+    /// Points per millimeter (PDF user space is 1/72 inch)
+    static let pointsPerMillimeter = 72.0 / 25.4
+
+    /// Single-page PDF document
+    func pdf(title: String? = nil) -> Data? {
+        let data = NSMutableData()
+        var mediaBox = CGRect(
+            x: 0, y: 0,
+            width: size.width * Self.pointsPerMillimeter,
+            height: size.height * Self.pointsPerMillimeter
+        )
+        var info: [CFString: Any] = [kCGPDFContextCreator: "GoSTL"]
+        if let title {
+            info[kCGPDFContextTitle] = title
+        }
+
+        guard let consumer = CGDataConsumer(data: data as CFMutableData),
+              let context = CGContext(consumer: consumer, mediaBox: &mediaBox, info as CFDictionary) else {
+            return nil
+        }
+
+        context.beginPDFPage(nil)
+        draw(in: context, scale: Self.pointsPerMillimeter, height: mediaBox.height)
+        context.endPDFPage()
+        context.closePDF()
+        return data as Data
+    }
+
+    // MARK: - Bitmap
+
+    /// White-background bitmap of the sheet, e.g. for label printers that take PNG
+    func image(pixelsPerMillimeter: Double) -> CGImage? {
+        let width = Int((size.width * pixelsPerMillimeter).rounded())
+        let height = Int((size.height * pixelsPerMillimeter).rounded())
+        guard width > 0, height > 0,
+              let context = CGContext(
+                data: nil,
+                width: width,
+                height: height,
+                bitsPerComponent: 8,
+                bytesPerRow: 0,
+                space: CGColorSpaceCreateDeviceGray(),
+                bitmapInfo: CGImageAlphaInfo.none.rawValue
+              ) else {
+            return nil
+        }
+        context.setFillColor(CGColor(gray: 1, alpha: 1))
+        context.fill(CGRect(x: 0, y: 0, width: width, height: height))
+        draw(in: context, scale: pixelsPerMillimeter, height: Double(height))
+        return context.makeImage()
+    }
+
+    /// Draw the primitives into a context with the origin bottom-left (PDF, bitmaps)
+    private func draw(in context: CGContext, scale: Double, height: Double) {
+        // Millimeters with the origin top-left, like the SVG output
+        context.translateBy(x: 0, y: height)
+        context.scaleBy(x: scale, y: -scale)
+        context.setStrokeColor(CGColor(gray: 0, alpha: 1))
+        context.setFillColor(CGColor(gray: 0, alpha: 1))
+        context.setLineCap(.round)
+
+        for primitive in primitives {
+            switch primitive {
+            case .line(let start, let end, let weight):
+                context.setLineWidth(weight)
+                context.strokeLineSegments(between: [start, end])
+            case .rectangle(let rect, let weight):
+                context.setLineWidth(weight)
+                context.stroke(rect)
+            case .polygon(let points):
+                context.addLines(between: points)
+                context.closePath()
+                context.fillPath()
+            case .text(let text, let point, let size, let anchor, let rotation):
+                draw(text, at: point, size: size, anchor: anchor, rotation: rotation, in: context)
+            }
+        }
+    }
+
+    private func draw(_ text: String, at point: CGPoint, size: Double, anchor: TextAnchor, rotation: Double, in context: CGContext) {
+        let font = CTFontCreateWithName("Helvetica" as CFString, size, nil)
+        let attributed = NSAttributedString(string: text, attributes: [
+            NSAttributedString.Key(kCTFontAttributeName as String): font,
+            NSAttributedString.Key(kCTForegroundColorFromContextAttributeName as String): true
+        ])
+        let line = CTLineCreateWithAttributedString(attributed)
+        let width = CTLineGetTypographicBounds(line, nil, nil, nil)
+        let offset: Double
+        switch anchor {
+        case .start: offset = 0
+        case .middle: offset = -width / 2
+        case .end: offset = -width
+        }
+
+        context.saveGState()
+        context.translateBy(x: point.x, y: point.y)
+        // Undo the y flip for glyphs, then rotate counter-clockwise on the sheet
+        context.scaleBy(x: 1, y: -1)
+        context.rotate(by: rotation * .pi / 180.0)
+        context.textMatrix = .identity
+        context.textPosition = CGPoint(x: offset, y: 0)
+        CTLineDraw(line, context)
+        context.restoreGState()
+    }
+}
@@ -0,0 +1,174 @@
+import Foundation
+
+/// Errors that can occur while reading or writing compressed model files
+enum FileCompressionError: LocalizedError {
+    case invalidGzip
+    case checksumMismatch
+    case zstdNotFound
+    case zstdFailed(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .invalidGzip:
+            return "Invalid gzip file"
+        case .checksumMismatch:
+            return "Gzip checksum mismatch (the file is corrupt or truncated)"
+        case .zstdNotFound:
+            return "zstd is required for .zst files. Install it with: brew install zstd"
+        case .zstdFailed(let message):
+            return "zstd failed: \(message)"
+        }
+    }
+}
+
+/// Transparent compression of model files, chosen by a trailing `.gz` or `.zst` extension
+///
+/// gzip is handled natively; zstd runs the `zstd` command-line tool.
+enum FileCompression: String, CaseIterable {
+    case gzip = "gz"
+    case zstd = "zst"
+
+    /// File extensions of compressed files
+    static var fileExtensions: [String] {
+        allCases.map(\.rawValue)
+    }
+
+    /// The compression of a file, from its last extension (`part.stl.gz` -> gzip)
+    init?(url: URL) {
+        self.init(rawValue: url.pathExtension.lowercased())
+    }
+
+    /// The file name without the compression extension (`part.stl.gz` -> `part.stl`)
+    static func uncompressedURL(_ url: URL) -> URL {
+        FileCompression(url: url) == nil ? url : url.deletingPathExtension()
+    }
+
+    // MARK: - File I/O
+
+    /// Read a file, decompressing it if it has a compression extension
+    static func read(_ url: URL) throws -> Data {
+        switch FileCompression(url: url) {
+        case .gzip:
+            return try gunzip(Data(contentsOf: url))
+        case .zstd:
+            return try runZstd(["-d", "-c", "-q", url.path])
+        case nil:
+            return try Data(contentsOf: url)
+        }
+    }
+
+    /// Write a file, compressing it if it has a compression extension
+    static func write(_ data: Data, to url: URL) throws {
+        switch FileCompression(url: url) {
+        case .gzip:
+            try gzip(data).write(to: url, options: .atomic)
+        case .zstd:
+            let input = TempWorkspace.shared.makeURL(prefix: "zstd", pathExtension: "bin")
+            defer { try? FileManager.default.removeItem(at: input) }
+            try data.write(to: input)
+            _ = try runZstd(["-q", "-f", input.path, "-o", url.path])
+        case nil:
+            try data.write(to: url, options: .atomic)
+        }
+    }
+
+    // MARK: - gzip
+
+    private static let gzipMagic: [UInt8] = [0x1F, 0x8B]
+    private static let deflateMethod: UInt8 = 8
+
+    /// Header flags (RFC 1952)
+    private static let flagHeaderCRC: UInt8 = 0x02
+    private static let flagExtra: UInt8 = 0x04
+    private static let flagName: UInt8 = 0x08
+    private static let flagComment: UInt8 = 0x10
+
+    /// Compress data as a single gzip member
+    static func gzip(_ data: Data) throws -> Data {
+        var output = Data(gzipMagic + [deflateMethod, 0, 0, 0, 0, 0, 0, 3])  // No flags, no time, Unix
+        output.append(try (data as NSData).compressed(using: .zlib) as Data)
+        append(ZipWriter.crc32(data), to: &output)
+        append(UInt32(truncatingIfNeeded: data.count), to: &output)
+        return output
+    }
+
+    /// Decompress the first member of gzip data, verifying its checksum and size
+    static func gunzip(_ data: Data) throws -> Data {
+        let bytes = [UInt8](data)
+        guard bytes.count >= 18, Array(bytes[0..<2]) == gzipMagic, bytes[2] == deflateMethod else {
+            throw FileCompressionError.invalidGzip
+        }
+
+        let flags = bytes[3]
+        var offset = 10
+        if flags & flagExtra != 0 {
+            guard offset + 2 <= bytes.count else { throw FileCompressionError.invalidGzip }
+            offset += 2 + (Int(bytes[offset]) | Int(bytes[offset + 1]) << 8)
+        }
+        for flag in [flagName, flagComment] where flags & flag != 0 {
+            // Zero-terminated string
+            while offset < bytes.count && bytes[offset] != 0 {
+                offset += 1
+            }
+            offset += 1
+        }
+        if flags & flagHeaderCRC != 0 {
+            offset += 2
+        }
+        guard offset <= bytes.count - 8 else { throw FileCompressionError.invalidGzip }
+
+        let deflated = data.subdata(in: offset..<(data.count - 8))
+        guard let inflated = try? (deflated as NSData).decompressed(using: .zlib) as Data else {
+            throw FileCompressionError.invalidGzip
+        }
+
+        let trailer = bytes.count - 8
+        let crc = readUInt32(bytes, at: trailer)
+        let size = readUInt32(bytes, at: trailer + 4)
+        guard crc == ZipWriter.crc32(inflated), size == UInt32(truncatingIfNeeded: inflated.count) else {
+            throw FileCompressionError.checksumMismatch
+        }
+        return inflated
+    }
+
+    private static func append(_ value: UInt32, to data: inout Data) {
+        withUnsafeBytes(of: value.littleEndian) { data.append(contentsOf: $0) }
+    }
+
+    private static func readUInt32(_ bytes: [UInt8], at offset: Int) -> UInt32 {
+        UInt32(bytes[offset]) | UInt32(bytes[offset + 1]) << 8 | UInt32(bytes[offset + 2]) << 16 | UInt32(bytes[offset + 3]) << 24
+    }
+
+    // MARK: - zstd
+
+    /// Common locations of the zstd tool on macOS
+    private static let zstdPaths = ["/opt/homebrew/bin/zstd", "/usr/local/bin/zstd", "/usr/bin/zstd"]
+
+    /// Run zstd and return its standard output
+    private static func runZstd(_ arguments: [String]) throws -> Data {
+        guard let path = zstdPaths.first(where: { FileManager.default.isExecutableFile(atPath: $0) }) else {
+            throw FileCompressionError.zstdNotFound
+        }
+
+        let process = Process()
+        process.executableURL = URL(fileURLWithPath: path)
+        process.arguments = arguments
+        let stdoutPipe = Pipe()
+        let stderrPipe = Pipe()
+        process.standardOutput = stdoutPipe
+        process.standardError = stderrPipe
+
+        try process.run()
+        // Read both pipes at once before waiting so a full pipe cannot block zstd
+        let errorReader = PipeReader(stderrPipe)
+        let output = stdoutPipe.fileHandleForReading.readDataToEndOfFile()
+        let errors = errorReader.result()
+        process.waitUntilExit()
+
+        guard process.terminationStatus == 0 else {
+            let message = String(decoding: errors, as: UTF8.self).trimmingCharacters(in: .whitespacesAndNewlines)
+            throw FileCompressionError.zstdFailed(message.isEmpty ? "exit status \(process.terminationStatus)" : message)
+        }
+        return output
+    }
+}
@@ -0,0 +1,79 @@
+import Foundation
+
+/// The model colored by one coordinate (usually Z) along a blue-to-red gradient
+///
+/// The gradient spans the model's extent along the axis, so a nominally flat plate shows its
+/// warping or tilt across the full color range even if it is only a few tenths of a millimeter.
+enum HeightColors {
+    /// Coordinate mapped to the gradient
+    enum Axis: String, CaseIterable {
+        case x, y, z
+
+        var index: Int {
+            switch self {
+            case .x: return 0
+            case .y: return 1
+            case .z: return 2
+            }
+        }
+
+        var displayName: String {
+            rawValue.uppercased()
+        }
+    }
+
+    /// Gradient stops from the lowest to the highest value
+    static let gradient: [TriangleColor] = [
+        TriangleColor(0.2, 0.3, 0.95),
+        TriangleColor(0.15, 0.75, 0.95),
+        TriangleColor(0.25, 0.8, 0.35),
+        TriangleColor(1.0, 0.85, 0.2),
+        TriangleColor(0.95, 0.2, 0.15)
+    ]
+
+    /// Color at a position of the gradient (0: lowest, 1: highest)
+    static func color(at t: Double) -> TriangleColor {
+        let position = Swift.max(0, Swift.min(1, t.isFinite ? t : 0.5)) * Double(gradient.count - 1)
+        let lower = Int(position)
+        guard lower < gradient.count - 1 else { return gradient[gradient.count - 1] }
+        let f = Float(position - Double(lower))
+        let a = gradient[lower], b = gradient[lower + 1]
+        return TriangleColor(a.r + (b.r - a.r) * f, a.g + (b.g - a.g) * f, a.b + (b.b - a.b) * f)
+    }
+
+    /// Lowest and highest vertex coordinate along the axis (nil for an empty model)
+    static func range(of triangles: [Triangle], axis: Axis) -> ClosedRange<Double>? {
+        var low = Double.infinity, high = -Double.infinity
+        for triangle in triangles {
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                low = Swift.min(low, vertex.value[axis.index])
+                high = Swift.max(high, vertex.value[axis.index])
+            }
+        }
+        return low <= high ? low...high : nil
+    }
+
+    /// Position of a value on the gradient; the middle for a range without extent
+    static func gradientPosition(of value: Double, in range: ClosedRange<Double>) -> Double {
+        let span = range.upperBound - range.lowerBound
+        return span > 0 ? (value - range.lowerBound) / span : 0.5
+    }
+
+    /// Vertex colors for the coordinate of every vertex (a smooth gradient across each face)
+    static func colors(of triangles: [Triangle], axis: Axis, range: ClosedRange<Double>, job: JobContext? = nil) throws -> VertexColors {
+        var colors: [SIMD4<Float>] = []
+        colors.reserveCapacity(triangles.count * 3)
+        for (index, triangle) in triangles.enumerated() {
+            if index % 65536 == 0 {
+                try job?.checkCancellation()
+                job?.reportProgress(Double(index) / Double(triangles.count))
+            }
+
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                let rgb = color(at: gradientPosition(of: vertex.value[axis.index], in: range))
+                colors.append(SIMD4(rgb.r, rgb.g, rgb.b, 1))
+            }
+        }
+        return VertexColors(perVertex: colors)
+    }
+}
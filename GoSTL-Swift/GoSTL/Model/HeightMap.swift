@@ -0,0 +1,197 @@
+import CoreGraphics
+import Foundation
+
+/// Errors that can occur when computing a height map
+enum HeightMapError: LocalizedError {
+    case emptyModel
+    case tooLarge(width: Int, height: Int)
+
+    var errorDescription: String? {
+        switch self {
+        case .emptyModel:
+            return "Cannot compute a height map of an empty model"
+        case .tooLarge(let width, let height):
+            return "A \(width) x \(height) pixel height map is too large; use a coarser resolution"
+        }
+    }
+}
+
+/// Height of the model's outer surface seen from one axis, sampled on a regular grid
+/// (e.g. for CNC probing comparisons and flatness checks)
+struct HeightMap {
+    /// Axis the surface is seen from (looking from the positive side)
+    enum Axis: String, CaseIterable {
+        case x, y, z
+
+        /// Image right, image up and height directions in model coordinates
+        var basis: (right: Vector3, up: Vector3, height: Vector3) {
+            switch self {
+            case .x: return (.unitY, .unitZ, .unitX)
+            case .y: return (-Vector3.unitX, .unitZ, .unitY)
+            case .z: return (.unitX, .unitY, .unitZ)
+            }
+        }
+    }
+
+    /// Largest supported map, in pixels
+    static let maxPixels = 50_000_000
+
+    let axis: Axis
+    /// Grid spacing in mm
+    let resolution: Double
+    let width: Int
+    let height: Int
+    /// Model coordinates of the center of the bottom-left sample along the image right/up directions
+    let origin: (right: Double, up: Double)
+    /// Heights row by row from the top of the image, nil where no surface is below the sample
+    let values: [Double?]
+
+    /// Height at a pixel (row 0 is the top of the image)
+    func value(row: Int, column: Int) -> Double? {
+        values[row * width + column]
+    }
+
+    /// Lowest and highest sampled heights
+    var range: ClosedRange<Double>? {
+        let heights = values.compactMap { $0 }
+        guard let low = heights.min(), let high = heights.max() else { return nil }
+        return low...high
+    }
+
+    /// Number of samples hitting the surface
+    var coveredCount: Int {
+        values.reduce(0) { $0 + ($1 == nil ? 0 : 1) }
+    }
+
+    // MARK: - Computation
+
+    static func compute(model: STLModel, axis: Axis = .z, resolution: Double) throws -> HeightMap {
+        guard !model.triangles.isEmpty else { throw HeightMapError.emptyModel }
+        let basis = axis.basis
+
+        // Bounds in image coordinates
+        var minRight = Double.infinity, maxRight = -Double.infinity
+        var minUp = Double.infinity, maxUp = -Double.infinity
+        for triangle in model.triangles {
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                let right = vertex.dot(basis.right), up = vertex.dot(basis.up)
+                minRight = Swift.min(minRight, right)
+                maxRight = Swift.max(maxRight, right)
+                minUp = Swift.min(minUp, up)
+                maxUp = Swift.max(maxUp, up)
+            }
+        }
+
+        // One sample per cell, centered in the cell
+        let width = Swift.max(1, Int(((maxRight - minRight) / resolution).rounded(.up)))
+        let height = Swift.max(1, Int(((maxUp - minUp) / resolution).rounded(.up)))
+        guard width * height <= maxPixels else {
+            throw HeightMapError.tooLarge(width: width, height: height)
+        }
+        let originRight = minRight + resolution / 2
+        let originUp = minUp + resolution / 2
+
+        var heights = [Double](repeating: -.infinity, count: width * height)
+        for triangle in model.triangles {
+            let points = [triangle.v1, triangle.v2, triangle.v3].map {
+                (right: $0.dot(basis.right), up: $0.dot(basis.up), height: $0.dot(basis.height))
+            }
+            let (a, b, c) = (points[0], points[1], points[2])
+            let area = (b.right - a.right) * (c.up - a.up) - (c.right - a.right) * (b.up - a.up)
+            // Edge-on triangles are covered by their neighbours
+            guard abs(area) > 1e-12 else { continue }
+
+            let rights = [a.right, b.right, c.right], ups = [a.up, b.up, c.up]
+            let firstColumn = Swift.max(0, Int(((rights.min()! - originRight) / resolution).rounded(.up)))
+            let lastColumn = Swift.min(width - 1, Int(((rights.max()! - originRight) / resolution).rounded(.down)))
+            let firstRow = Swift.max(0, Int(((ups.min()! - originUp) / resolution).rounded(.up)))
+            let lastRow = Swift.min(height - 1, Int(((ups.max()! - originUp) / resolution).rounded(.down)))
+            guard firstColumn <= lastColumn, firstRow <= lastRow else { continue }
+
+            for row in firstRow...lastRow {
+                let up = originUp + Double(row) * resolution
+                for column in firstColumn...lastColumn {
+                    let right = originRight + Double(column) * resolution
+
+                    // Barycentric coordinates of the sample in the projected triangle
+                    let wa = ((b.right - right) * (c.up - up) - (c.right - right) * (b.up - up)) / area
+                    let wb = ((c.right - right) * (a.up - up) - (a.right - right) * (c.up - up)) / area
+                    let wc = 1 - wa - wb
+                    let tolerance = -1e-9
+                    guard wa >= tolerance, wb >= tolerance, wc >= tolerance else { continue }
+
+                    let sample = wa * a.height + wb * b.height + wc * c.height
+                    // Bottom-up grid index; flipped to image rows below
+                    let index = row * width + column
+                    if sample > heights[index] {
+                        heights[index] = sample
+                    }
+                }
+            }
+        }
+
+        var values = [Double?](repeating: nil, count: width * height)
+        for row in 0..<height {
+            for column in 0..<width {
+                let sample = heights[row * width + column]
+                if sample.isFinite {
+                    values[(height - 1 - row) * width + column] = sample
+                }
+            }
+        }
+
+        return HeightMap(
+            axis: axis,
+            resolution: resolution,
+            width: width,
+            height: height,
+            origin: (originRight, originUp),
+            values: values
+        )
+    }
+
+    // MARK: - Output
+
+    /// 16-bit grayscale image: the lowest height is dark gray, the highest white, no surface black
+    func grayscaleImage() -> CGImage? {
+        let low = range?.lowerBound ?? 0
+        let span = (range?.upperBound ?? 0) - low
+
+        var pixels = [UInt16](repeating: 0, count: width * height)
+        for (index, value) in values.enumerated() {
+            guard let value else { continue }
+            // Reserve 0 for "no surface"
+            let level = span > 0 ? (value - low) / span : 1
+            pixels[index] = UInt16(1 + (level * 65534).rounded()).bigEndian
+        }
+
+        let data = pixels.withUnsafeBufferPointer { Data(buffer: $0) }
+        guard let provider = CGDataProvider(data: data as CFData) else { return nil }
+        return CGImage(
+            width: width,
+            height: height,
+            bitsPerComponent: 16,
+            bitsPerPixel: 16,
+            bytesPerRow: width * 2,
+            space: CGColorSpaceCreateDeviceGray(),
+            bitmapInfo: CGBitmapInfo(rawValue: CGImageAlphaInfo.none.rawValue).union(.byteOrder16Big),
+            provider: provider,
+            decode: nil,
+            shouldInterpolate: false,
+            intent: .defaultIntent
+        )
+    }
+
+    /// Height matrix as CSV, one image row per line from the top; empty cells have no surface
+    func csv(decimals: Int = 4, format: CSVFormat = .standard) -> String {
+        var lines: [String] = []
+        lines.reserveCapacity(height)
+        for row in 0..<height {
+            let cells = (0..<width).map { column in
+                value(row: row, column: column).map { format.number($0, decimals: decimals) } ?? ""
+            }
+            lines.append(format.row(cells))
+        }
+        return lines.joined(separator: "\n") + "\n"
+    }
+}
@@ -0,0 +1,177 @@
+import Foundation
+
+/// Mass properties of a solid of uniform density: center of mass, inertia tensor and principal axes
+///
+/// Integrated exactly over the signed tetrahedra spanned by each triangle and a reference point,
+/// like the volume, so the mesh must be watertight. Lengths are in mm, masses in g and moments of
+/// inertia in g·mm² (1 g·mm² = 1e-9 kg·m²). The inertia tensor is taken about the center of mass
+/// with the usual sign convention: off-diagonal entries are the negated products of inertia.
+struct MassProperties: Codable, Equatable {
+    /// Symmetric 3×3 tensor
+    struct Tensor: Codable, Equatable {
+        var xx: Double
+        var yy: Double
+        var zz: Double
+        var xy: Double
+        var xz: Double
+        var yz: Double
+
+        static let zero = Tensor(xx: 0, yy: 0, zz: 0, xy: 0, xz: 0, yz: 0)
+
+        var rows: [[Double]] {
+            [[xx, xy, xz], [xy, yy, yz], [xz, yz, zz]]
+        }
+
+        func scaled(by factor: Double) -> Tensor {
+            Tensor(xx: xx * factor, yy: yy * factor, zz: zz * factor, xy: xy * factor, xz: xz * factor, yz: yz * factor)
+        }
+    }
+
+    /// Density used for the mass (g/cm³)
+    var density: Double
+    var volume: Double
+    var mass: Double
+    /// Center of mass in the model's coordinates
+    var centerOfMass: Vector3
+    /// Inertia tensor about the center of mass (g·mm²)
+    var inertia: Tensor
+    /// Principal moments of inertia, ascending (g·mm²)
+    var principalMoments: [Double]
+    /// Unit principal axes matching `principalMoments`, forming a right-handed frame
+    var principalAxes: [Vector3]
+
+    /// Radii of gyration about the principal axes (mm): the distance at which the whole mass
+    /// would have the same moment of inertia
+    var radiiOfGyration: [Double] {
+        principalMoments.map { mass > 0 ? ($0 / mass).squareRoot() : 0 }
+    }
+
+    // MARK: - Computation
+
+    /// Mass properties of a closed mesh
+    /// - Parameter density: Uniform density in g/cm³ (e.g. `Material.density`)
+    static func compute(triangles: [Triangle], density: Double) -> MassProperties {
+        // Integrate relative to the bounding box center to keep far-off models accurate
+        let reference = BoundingBox(points: triangles.flatMap { [$0.v1, $0.v2, $0.v3] }).center
+
+        var sixVolume = 0.0
+        var firstMoment = Vector3.zero
+        var second = Tensor.zero
+        for triangle in triangles {
+            let a = triangle.v1 - reference
+            let b = triangle.v2 - reference
+            let c = triangle.v3 - reference
+            let det = a.dot(b.cross(c))
+            let s = a + b + c
+
+            sixVolume += det
+            firstMoment = firstMoment + s * det
+            // ∫ x xᵀ dV over the tetrahedron = det / 120 · (a aᵀ + b bᵀ + c cᵀ + s sᵀ)
+            second.xx += det * (a.x * a.x + b.x * b.x + c.x * c.x + s.x * s.x)
+            second.yy += det * (a.y * a.y + b.y * b.y + c.y * c.y + s.y * s.y)
+            second.zz += det * (a.z * a.z + b.z * b.z + c.z * c.z + s.z * s.z)
+            second.xy += det * (a.x * a.y + b.x * b.y + c.x * c.y + s.x * s.y)
+            second.xz += det * (a.x * a.z + b.x * b.z + c.x * c.z + s.x * s.z)
+            second.yz += det * (a.y * a.z + b.y * b.z + c.y * c.z + s.y * s.z)
+        }
+
+        // Inward-facing normals give a negative volume; the solid is the same
+        let orientation: Double = sixVolume < 0 ? -1 : 1
+        let volume = abs(sixVolume) / 6
+        guard volume > 0 else {
+            return MassProperties(
+                density: density, volume: 0, mass: 0, centerOfMass: reference, inertia: .zero,
+                principalMoments: [0, 0, 0], principalAxes: [Vector3(1, 0, 0), Vector3(0, 1, 0), Vector3(0, 0, 1)]
+            )
+        }
+
+        let center = firstMoment * (orientation / 24) / volume
+        second = second.scaled(by: orientation / 120)
+
+        // Second moments about the center of mass (parallel axis theorem)
+        let covariance = Tensor(
+            xx: second.xx - volume * center.x * center.x,
+            yy: second.yy - volume * center.y * center.y,
+            zz: second.zz - volume * center.z * center.z,
+            xy: second.xy - volume * center.x * center.y,
+            xz: second.xz - volume * center.x * center.z,
+            yz: second.yz - volume * center.y * center.z
+        )
+
+        // g/cm³ -> g/mm³
+        let densityPerMM3 = density / 1000
+        let inertia = Tensor(
+            xx: covariance.yy + covariance.zz,
+            yy: covariance.xx + covariance.zz,
+            zz: covariance.xx + covariance.yy,
+            xy: -covariance.xy,
+            xz: -covariance.xz,
+            yz: -covariance.yz
+        ).scaled(by: densityPerMM3)
+
+        let principal = eigen(inertia)
+        return MassProperties(
+            density: density,
+            volume: volume,
+            mass: volume * densityPerMM3,
+            centerOfMass: center + reference,
+            inertia: inertia,
+            principalMoments: principal.values,
+            principalAxes: principal.vectors
+        )
+    }
+
+    /// Eigenvalues (ascending) and unit eigenvectors of a symmetric tensor (cyclic Jacobi rotations)
+    static func eigen(_ tensor: Tensor) -> (values: [Double], vectors: [Vector3]) {
+        var a = tensor.rows
+        var v: [[Double]] = [[1, 0, 0], [0, 1, 0], [0, 0, 1]]
+        let scale = max(abs(tensor.xx), abs(tensor.yy), abs(tensor.zz), Double.leastNormalMagnitude)
+
+        for _ in 0..<50 {
+            let offDiagonal = abs(a[0][1]) + abs(a[0][2]) + abs(a[1][2])
+            if offDiagonal <= scale * 1e-15 { break }
+
+            for (p, q) in [(0, 1), (0, 2), (1, 2)] where a[p][q] != 0 {
+                let theta = (a[q][q] - a[p][p]) / (2 * a[p][q])
+                let t = (theta >= 0 ? 1 : -1) / (abs(theta) + (theta * theta + 1).squareRoot())
+                let c = 1 / (t * t + 1).squareRoot()
+                let s = t * c
+
+                // A' = Jᵀ A J with the rotation J in the (p, q) plane
+                for k in 0..<3 {
+                    let akp = a[k][p], akq = a[k][q]
+                    a[k][p] = c * akp - s * akq
+                    a[k][q] = s * akp + c * akq
+                }
+                for k in 0..<3 {
+                    let apk = a[p][k], aqk = a[q][k]
+                    a[p][k] = c * apk - s * aqk
+                    a[q][k] = s * apk + c * aqk
+                }
+                for k in 0..<3 {
+                    let vkp = v[k][p], vkq = v[k][q]
+                    v[k][p] = c * vkp - s * vkq
+                    v[k][q] = s * vkp + c * vkq
+                }
+            }
+        }
+
+        let order = [0, 1, 2].sorted { a[$0][$0] < a[$1][$1] }
+        var vectors = order.map { column -> Vector3 in
+            let axis = Vector3(v[0][column], v[1][column], v[2][column]).normalized()
+            // Deterministic sign: the largest component points in the positive direction
+            let largest = [axis.x, axis.y, axis.z].max { abs($0) < abs($1) } ?? 1
+            return largest < 0 ? axis * -1 : axis
+        }
+        // Right-handed frame
+        vectors[2] = vectors[0].cross(vectors[1])
+        return (order.map { a[$0][$0] }, vectors)
+    }
+}
+
+extension STLModel {
+    /// Mass properties for a uniform density in g/cm³ (see `MassProperties`)
+    func massProperties(density: Double) -> MassProperties {
+        MassProperties.compute(triangles: triangles, density: density)
+    }
+}
@@ -1,13 +1,21 @@
 import Foundation
 import simd
 
-/// Common 3D printing materials with their densities
+/// Common 3D printing and machining materials with their densities and prices
 enum Material: String, CaseIterable {
     case pla = "PLA"
     case abs = "ABS"
     case petg = "PETG"
     case tpu = "TPU"
     case nylon = "Nylon"
+    case resin = "Resin"
+    case aluminum = "Aluminum"
+    case steel = "Steel"
+
+    /// Material by name, ignoring case (e.g. `petg` on the command line)
+    static func named(_ name: String) -> Material? {
+        allCases.first { $0.rawValue.caseInsensitiveCompare(name) == .orderedSame }
+    }
 
     /// Density in g/cm³
     var density: Double {
@@ -22,6 +30,45 @@ enum Material: String, CaseIterable {
             return 1.21
         case .nylon:
             return 1.14
+        case .resin:
+            return 1.15
+        case .aluminum:
+            return 2.70
+        case .steel:
+            return 7.85
+        }
+    }
+
+    /// Typical price in $ per kg (filament spools, resin bottles, bar stock)
+    var pricePerKg: Double {
+        switch self {
+        case .pla:
+            return 20
+        case .abs:
+            return 22
+        case .petg:
+            return 22
+        case .tpu:
+            return 35
+        case .nylon:
+            return 45
+        case .resin:
+            return 40
+        case .aluminum:
+            return 8
+        case .steel:
+            return 3
+        }
+    }
+
+    /// Whether the material is printed with a sparse infill (FDM filaments);
+    /// resin prints and metal parts are solid
+    var supportsInfill: Bool {
+        switch self {
+        case .pla, .abs, .petg, .tpu, .nylon:
+            return true
+        case .resin, .aluminum, .steel:
+            return false
         }
     }
 
@@ -38,6 +85,12 @@ enum Material: String, CaseIterable {
             return SIMD3<Float>(0.75, 0.75, 0.8) // Darker, more neutral
         case .nylon:
             return SIMD3<Float>(0.95, 0.93, 0.88) // Slightly cream/beige
+        case .resin:
+            return SIMD3<Float>(0.6, 0.62, 0.65) // Gray resin
+        case .aluminum:
+            return SIMD3<Float>(0.91, 0.92, 0.92) // Bright metal
+        case .steel:
+            return SIMD3<Float>(0.56, 0.57, 0.58) // Darker metal
         }
     }
 
@@ -54,14 +107,22 @@ enum Material: String, CaseIterable {
             return 0.1  // Very matte (flexible)
         case .nylon:
             return 0.4  // Semi-glossy
+        case .resin:
+            return 0.5  // Smooth, semi-glossy
+        case .aluminum:
+            return 0.6  // Brushed metal
+        case .steel:
+            return 0.5
         }
     }
 
     /// Metalness of the material (0 = dielectric, 1 = metallic)
     var metalness: Float {
         switch self {
-        case .pla, .abs, .petg, .tpu, .nylon:
-            return 0.0  // All plastics are non-metallic
+        case .pla, .abs, .petg, .tpu, .nylon, .resin:
+            return 0.0  // Plastics are non-metallic
+        case .aluminum, .steel:
+            return 1.0
         }
     }
 
@@ -78,6 +139,12 @@ enum Material: String, CaseIterable {
             return 0.15
         case .nylon:
             return 0.5
+        case .resin:
+            return 0.6
+        case .aluminum:
+            return 0.8
+        case .steel:
+            return 0.7
         }
     }
 
@@ -88,6 +155,19 @@ enum Material: String, CaseIterable {
         return volumeInCm3 * density
     }
 
+    /// Estimated mass and cost of a part
+    /// - Parameters:
+    ///   - volume: Volume in mm³
+    ///   - infill: Infill fraction (0.0 to 1.0), ignored for solid materials
+    func estimate(volume: Double, infill: Double = 1.0) -> MassEstimate {
+        MassEstimate(material: self, volume: volume, infill: infill)
+    }
+
+    /// Format a cost for display
+    static func formatCost(_ cost: Double) -> String {
+        String(format: "$%.2f", cost)
+    }
+
     /// Format weight for display with appropriate unit
     static func formatWeight(_ grams: Double) -> String {
         if grams < 1.0 {
@@ -109,3 +189,25 @@ enum Material: String, CaseIterable {
         return all[nextIndex]
     }
 }
+
+/// Mass and material cost of a part printed or machined from a material
+///
+/// Partial infill scales the solid mass proportionally; walls and top/bottom layers make
+/// real prints heavier, so values below 100% are a lower bound.
+struct MassEstimate: Equatable {
+    let material: Material
+    /// Infill fraction actually used (1.0 for materials without infill)
+    let infill: Double
+    /// Mass in g
+    let mass: Double
+    /// Cost in $
+    let cost: Double
+
+    init(material: Material, volume: Double, infill: Double = 1.0) {
+        let infill = material.supportsInfill ? min(max(infill, 0), 1) : 1.0
+        self.material = material
+        self.infill = infill
+        self.mass = material.weight(volume: volume) * infill
+        self.cost = mass / 1000.0 * material.pricePerKg
+    }
+}
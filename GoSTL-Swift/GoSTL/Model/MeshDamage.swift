@@ -0,0 +1,174 @@
+import Foundation
+
+/// Deterministic random numbers (SplitMix64), so damage can be repeated from its seed
+struct SeededGenerator: RandomNumberGenerator {
+    private var state: UInt64
+
+    init(seed: UInt64) {
+        state = seed
+    }
+
+    mutating func next() -> UInt64 {
+        state &+= 0x9E37_79B9_7F4A_7C15
+        var z = state
+        z = (z ^ (z >> 30)) &* 0xBF58_476D_1CE4_E5B9
+        z = (z ^ (z >> 27)) &* 0x94D0_49BB_1331_11EB
+        return z ^ (z >> 31)
+    }
+}
+
+/// Number of triangles to damage: a count, or a share of the mesh
+enum DamageAmount: Equatable {
+    case count(Int)
+    case fraction(Double)
+
+    static let none = DamageAmount.count(0)
+
+    /// "10" (triangles) or "2%" (of the triangles)
+    init?(_ text: String) {
+        let text = text.trimmingCharacters(in: .whitespaces)
+        if text.hasSuffix("%") {
+            guard let percent = Double(text.dropLast()), (0...100).contains(percent) else { return nil }
+            self = .fraction(percent / 100)
+        } else {
+            guard let count = Int(text), count >= 0 else { return nil }
+            self = .count(count)
+        }
+    }
+
+    var isZero: Bool {
+        self == .count(0) || self == .fraction(0)
+    }
+
+    /// Triangles out of `total`
+    func resolve(of total: Int) -> Int {
+        switch self {
+        case .count(let count): return min(count, total)
+        case .fraction(let fraction): return min(Int((fraction * Double(total)).rounded()), total)
+        }
+    }
+}
+
+/// Deliberate mesh defects for testing repair and the viewer's robustness (`gostl fuzz`)
+///
+/// Defects are applied in a fixed order (holes, flipped normals, duplicates, degenerate
+/// triangles, noise) with a seeded generator, so the same seed damages a model the same way.
+struct MeshDamage: Equatable {
+    /// Holes to punch; each removes `holeSize` connected triangles
+    var holes = 0
+    var holeSize = 4
+    /// Triangles turned inside out (reversed winding and normal)
+    var flippedNormals = DamageAmount.none
+    /// Triangles added a second time
+    var duplicates = DamageAmount.none
+    /// Triangles collapsed to zero area (third vertex moved onto the first edge)
+    var degenerate = DamageAmount.none
+    /// Largest random displacement of a vertex (mm); shared vertices move together
+    var noise = 0.0
+
+    /// What `apply` changed
+    struct Report: Equatable {
+        var holes = 0
+        var removedTriangles = 0
+        var flippedNormals = 0
+        var duplicatedTriangles = 0
+        var degenerateTriangles = 0
+        var movedVertices = 0
+    }
+
+    var isEmpty: Bool {
+        holes == 0 && flippedNormals.isZero && duplicates.isZero && degenerate.isZero && noise == 0
+    }
+
+    func apply(to model: STLModel, seed: UInt64) -> (model: STLModel, report: Report) {
+        var random = SeededGenerator(seed: seed)
+        var report = Report()
+        var triangles = model.triangles
+
+        if holes > 0 {
+            let removed = punchHoles(in: triangles, using: &random)
+            report.holes = removed.holes
+            report.removedTriangles = removed.indices.count
+            triangles = triangles.enumerated().filter { !removed.indices.contains($0.offset) }.map(\.element)
+        }
+
+        for index in triangles.indices.shuffled(using: &random).prefix(flippedNormals.resolve(of: triangles.count)) {
+            let triangle = triangles[index]
+            triangles[index].v2 = triangle.v3
+            triangles[index].v3 = triangle.v2
+            triangles[index].normal = -triangle.normal
+            report.flippedNormals += 1
+        }
+
+        let copies = triangles.indices.shuffled(using: &random).prefix(duplicates.resolve(of: triangles.count))
+        triangles += copies.map { triangles[$0] }
+        report.duplicatedTriangles = copies.count
+
+        for index in triangles.indices.shuffled(using: &random).prefix(degenerate.resolve(of: triangles.count)) {
+            triangles[index].v3 = (triangles[index].v1 + triangles[index].v2) / 2.0
+            triangles[index].normal = .zero
+            report.degenerateTriangles += 1
+        }
+
+        if noise > 0 {
+            var moved: [Vector3: Vector3] = [:]
+            func displaced(_ vertex: Vector3) -> Vector3 {
+                if let known = moved[vertex] {
+                    return known
+                }
+                let offset = Vector3(
+                    Double.random(in: -noise...noise, using: &random),
+                    Double.random(in: -noise...noise, using: &random),
+                    Double.random(in: -noise...noise, using: &random)
+                )
+                moved[vertex] = vertex + offset
+                return vertex + offset
+            }
+            for index in triangles.indices {
+                let triangle = triangles[index]
+                triangles[index].v1 = displaced(triangle.v1)
+                triangles[index].v2 = displaced(triangle.v2)
+                triangles[index].v3 = displaced(triangle.v3)
+                // Degenerate triangles keep their zero normal
+                if triangle.normal != .zero {
+                    triangles[index].updateNormal()
+                }
+            }
+            report.movedVertices = moved.count
+        }
+
+        let name = model.name.map { "\($0)-damaged" }
+        return (STLModel(triangles: triangles, name: name, header: model.header), report)
+    }
+
+    /// Indices of connected patches of up to `holeSize` triangles, grown across shared edges
+    private func punchHoles(in triangles: [Triangle], using random: inout SeededGenerator) -> (holes: Int, indices: Set<Int>) {
+        var neighbors: [Edge: [Int]] = [:]
+        for (index, triangle) in triangles.enumerated() {
+            for edge in [Edge(triangle.v1, triangle.v2), Edge(triangle.v2, triangle.v3), Edge(triangle.v3, triangle.v1)] {
+                neighbors[edge, default: []].append(index)
+            }
+        }
+
+        var removed = Set<Int>()
+        var punched = 0
+        var candidates = triangles.indices.shuffled(using: &random)
+        while punched < holes, let start = candidates.popLast() {
+            guard !removed.contains(start) else { continue }
+            var patch = [start]
+            var next = 0
+            while patch.count < holeSize, next < patch.count {
+                let triangle = triangles[patch[next]]
+                next += 1
+                for edge in [Edge(triangle.v1, triangle.v2), Edge(triangle.v2, triangle.v3), Edge(triangle.v3, triangle.v1)] {
+                    for neighbor in neighbors[edge] ?? [] where patch.count < holeSize && !removed.contains(neighbor) && !patch.contains(neighbor) {
+                        patch.append(neighbor)
+                    }
+                }
+            }
+            removed.formUnion(patch)
+            punched += 1
+        }
+        return (punched, removed)
+    }
+}
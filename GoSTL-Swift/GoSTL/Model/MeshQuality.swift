@@ -0,0 +1,277 @@
+import Foundation
+
+/// Fast mesh checks run whenever a model loads, so defects surface before any measurement is trusted
+///
+/// Every check is a single pass with hash maps (no ray casting), so it stays quick for large
+/// meshes; `gostl analyze` and `gostl assert` cover the slower checks such as wall thickness.
+struct MeshQuality: Equatable {
+    enum Issue: String, CaseIterable {
+        case degenerateTriangles
+        case slivers
+        case openEdges
+        case nonManifoldEdges
+        case flippedTriangles
+        case insideOut
+        case nearDuplicateVertices
+
+        var title: String {
+            switch self {
+            case .degenerateTriangles: return "Degenerate triangles"
+            case .slivers: return "Sliver triangles"
+            case .openEdges: return "Open edges"
+            case .nonManifoldEdges: return "Non-manifold edges"
+            case .flippedTriangles: return "Flipped triangles"
+            case .insideOut: return "Inside out"
+            case .nearDuplicateVertices: return "Near-duplicate vertices"
+            }
+        }
+
+        /// Why the issue matters for measuring
+        var explanation: String {
+            switch self {
+            case .degenerateTriangles: return "Zero-area faces have no normal, so picks and snaps on them are unreliable"
+            case .slivers: return "Needle-like faces give noisy normals and edge snaps"
+            case .openEdges: return "The surface has holes, so volume and wall thickness are not meaningful"
+            case .nonManifoldEdges: return "Edges shared by more than two faces make inside and outside ambiguous"
+            case .flippedTriangles: return "Faces wound against their neighbors point inward"
+            case .insideOut: return "All normals point inward, so the volume and thickness checks see the model inverted"
+            case .nearDuplicateVertices: return "Vertices that almost coincide leave hairline cracks between faces"
+            }
+        }
+
+        /// Whether `MeshQuality.repaired` fixes the issue
+        var isRepairable: Bool {
+            switch self {
+            case .degenerateTriangles, .flippedTriangles, .insideOut, .nearDuplicateVertices: return true
+            case .slivers, .openEdges, .nonManifoldEdges: return false
+            }
+        }
+    }
+
+    /// Faces with less area than this are degenerate (mm²)
+    static let degenerateArea = 1e-12
+    /// Faces whose longest edge is this many times their height across it are slivers
+    static let maxAspectRatio = 1000.0
+    /// Vertices closer than this share of the bounding box diagonal count as near-duplicates
+    static let weldToleranceFactor = 1e-6
+
+    var triangleCount = 0
+    /// Indices of the degenerate triangles
+    var degenerateTriangles: [Int] = []
+    /// Indices of the sliver triangles (degenerate ones are not counted again)
+    var sliverTriangles: [Int] = []
+    var topology = MeshTopology(boundaryEdges: 0, nonManifoldEdges: 0)
+    /// Indices of triangles wound against the majority of their connected surface
+    var flippedTriangles: [Int] = []
+    /// Closed mesh with a negative enclosed volume: all normals point inward
+    var isInsideOut = false
+    /// Distinct vertices within the weld tolerance of another vertex
+    var nearDuplicateVertices = 0
+
+    var issues: [Issue] {
+        Issue.allCases.filter { count(of: $0) > 0 }
+    }
+
+    var isClean: Bool {
+        issues.isEmpty
+    }
+
+    func count(of issue: Issue) -> Int {
+        switch issue {
+        case .degenerateTriangles: return degenerateTriangles.count
+        case .slivers: return sliverTriangles.count
+        case .openEdges: return topology.boundaryEdges
+        case .nonManifoldEdges: return topology.nonManifoldEdges
+        case .flippedTriangles: return flippedTriangles.count
+        case .insideOut: return isInsideOut ? 1 : 0
+        case .nearDuplicateVertices: return nearDuplicateVertices
+        }
+    }
+
+    /// Triangles to select for an issue (empty for issues that are about edges or vertices)
+    func triangles(of issue: Issue) -> [Int] {
+        switch issue {
+        case .degenerateTriangles: return degenerateTriangles
+        case .slivers: return sliverTriangles
+        case .flippedTriangles: return flippedTriangles
+        case .openEdges, .nonManifoldEdges, .insideOut, .nearDuplicateVertices: return []
+        }
+    }
+
+    /// One line per issue, e.g. "Open edges: 12"
+    func message(for issue: Issue) -> String {
+        issue == .insideOut ? issue.title : "\(issue.title): \(count(of: issue))"
+    }
+
+    /// Short banner text, e.g. "3 mesh problems: 12 open edges, ..."
+    var summary: String {
+        guard !isClean else { return "No mesh problems found" }
+        let parts = issues.map { issue in
+            issue == .insideOut ? "inside out" : "\(count(of: issue)) \(issue.title.lowercased())"
+        }
+        return "\(issues.count) mesh problem\(issues.count == 1 ? "" : "s"): \(parts.joined(separator: ", "))"
+    }
+
+    // MARK: - Checks
+
+    static func check(_ model: STLModel, job: JobContext? = nil) throws -> MeshQuality {
+        var quality = MeshQuality()
+        let triangles = model.triangles
+        quality.triangleCount = triangles.count
+        guard !triangles.isEmpty else { return quality }
+
+        for (index, triangle) in triangles.enumerated() {
+            let area = triangle.area()
+            if area < degenerateArea {
+                quality.degenerateTriangles.append(index)
+                continue
+            }
+            let (e1, e2, e3) = triangle.edgeLengths()
+            let longest = max(e1, e2, e3)
+            // Longest edge over the height across it
+            if longest * longest / (2 * area) > maxAspectRatio {
+                quality.sliverTriangles.append(index)
+            }
+        }
+        try job?.checkCancellation()
+
+        quality.topology = model.topology()
+        try job?.checkCancellation()
+
+        let orientation = orientation(of: triangles)
+        quality.flippedTriangles = orientation.flipped
+        if quality.topology.boundaryEdges == 0 {
+            quality.isInsideOut = orientation.signedVolume < 0
+        }
+        try job?.checkCancellation()
+
+        quality.nearDuplicateVertices = nearDuplicateVertices(in: triangles, tolerance: weldTolerance(for: model))
+        return quality
+    }
+
+    /// Weld tolerance for a model (relative to its size)
+    static func weldTolerance(for model: STLModel) -> Double {
+        max(model.boundingBox().diagonal * weldToleranceFactor, 1e-9)
+    }
+
+    /// Triangles wound against their neighbors and the enclosed volume after correcting them
+    ///
+    /// Neighbors across a shared edge must run along it in opposite directions. Each connected
+    /// surface is walked from one triangle; the smaller group of the two windings is taken as flipped.
+    static func orientation(of triangles: [Triangle]) -> (flipped: [Int], signedVolume: Double) {
+        // Triangles using each edge, with whether they run from `start` to `end`
+        var edgeUse: [Edge: [(triangle: Int, forward: Bool)]] = [:]
+        edgeUse.reserveCapacity(triangles.count * 3 / 2)
+        for (index, triangle) in triangles.enumerated() {
+            for (a, b) in [(triangle.v1, triangle.v2), (triangle.v2, triangle.v3), (triangle.v3, triangle.v1)] {
+                let edge = Edge(a, b)
+                edgeUse[edge, default: []].append((index, edge.start == a))
+            }
+        }
+
+        // Winding of each triangle relative to the first of its surface (true: same)
+        var sameWinding: [Bool?] = Array(repeating: nil, count: triangles.count)
+        var flipped: [Int] = []
+        var signedVolume = 0.0
+
+        for seed in triangles.indices where sameWinding[seed] == nil {
+            sameWinding[seed] = true
+            var surface = [seed]
+            var queue = [seed]
+            while let index = queue.popLast() {
+                let triangle = triangles[index]
+                for (a, b) in [(triangle.v1, triangle.v2), (triangle.v2, triangle.v3), (triangle.v3, triangle.v1)] {
+                    let edge = Edge(a, b)
+                    // Only manifold edges say how the neighbor should be wound
+                    guard let uses = edgeUse[edge], uses.count == 2 else { continue }
+                    let forward = edge.start == a
+                    for use in uses where use.triangle != index && sameWinding[use.triangle] == nil {
+                        // Opposite directions along the edge mean the same winding
+                        sameWinding[use.triangle] = (use.forward != forward) == sameWinding[index]!
+                        surface.append(use.triangle)
+                        queue.append(use.triangle)
+                    }
+                }
+            }
+
+            let same = surface.filter { sameWinding[$0]! }
+            let other = surface.filter { !sameWinding[$0]! }
+            let minority = other.count <= same.count ? other : same
+            flipped += minority
+
+            let minoritySet = Set(minority)
+            for index in surface {
+                let triangle = triangles[index]
+                let volume = triangle.v1.dot(triangle.v2.cross(triangle.v3)) / 6
+                signedVolume += minoritySet.contains(index) ? -volume : volume
+            }
+        }
+        return (flipped.sorted(), signedVolume)
+    }
+
+    /// Distinct vertices sharing a tolerance-sized grid cell with another vertex
+    /// Pairs on either side of a cell border are missed; the check is meant to be quick, not exhaustive.
+    static func nearDuplicateVertices(in triangles: [Triangle], tolerance: Double) -> Int {
+        var vertices = Set<Vector3>()
+        for triangle in triangles {
+            vertices.insert(triangle.v1)
+            vertices.insert(triangle.v2)
+            vertices.insert(triangle.v3)
+        }
+
+        var cells: [SIMD3<Int64>: Int] = [:]
+        for vertex in vertices {
+            cells[cell(of: vertex, tolerance: tolerance), default: 0] += 1
+        }
+        return cells.values.reduce(0) { $0 + $1 - 1 }
+    }
+
+    private static func cell(of vertex: Vector3, tolerance: Double) -> SIMD3<Int64> {
+        SIMD3(Int64((vertex.x / tolerance).rounded(.down)),
+              Int64((vertex.y / tolerance).rounded(.down)),
+              Int64((vertex.z / tolerance).rounded(.down)))
+    }
+
+    // MARK: - Repair
+
+    /// Model with near-duplicate vertices welded, degenerate triangles removed and flipped or
+    /// inside-out triangles turned around; slivers and holes are left as they are
+    static func repaired(_ model: STLModel) -> STLModel {
+        let tolerance = weldTolerance(for: model)
+
+        // Weld: every vertex moves to the first vertex seen in its cell
+        var representatives: [SIMD3<Int64>: Vector3] = [:]
+        func welded(_ vertex: Vector3) -> Vector3 {
+            let key = cell(of: vertex, tolerance: tolerance)
+            if let representative = representatives[key] {
+                return representative
+            }
+            representatives[key] = vertex
+            return vertex
+        }
+
+        var triangles: [Triangle] = []
+        triangles.reserveCapacity(model.triangles.count)
+        for var triangle in model.triangles {
+            let corners = (welded(triangle.v1), welded(triangle.v2), welded(triangle.v3))
+            if corners != (triangle.v1, triangle.v2, triangle.v3) {
+                (triangle.v1, triangle.v2, triangle.v3) = corners
+                triangle.updateNormal()
+            }
+            guard triangle.area() >= degenerateArea else { continue }
+            triangles.append(triangle)
+        }
+
+        let orientation = orientation(of: triangles)
+        let closed = STLModel(triangles: triangles).topology().boundaryEdges == 0
+        // Inside out after the flipped triangles are corrected: turn all the others instead
+        let flipAll = closed && orientation.signedVolume < 0
+        let flipped = Set(orientation.flipped)
+        for index in triangles.indices where flipped.contains(index) != flipAll {
+            swap(&triangles[index].v2, &triangles[index].v3)
+            triangles[index].normal = -triangles[index].normal
+        }
+
+        return STLModel(triangles: triangles, name: model.name, header: model.header)
+    }
+}
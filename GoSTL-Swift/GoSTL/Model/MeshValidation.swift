@@ -0,0 +1,79 @@
+import Foundation
+import simd
+
+/// Edge connectivity of a mesh
+struct MeshTopology: Equatable {
+    /// Edges used by a single triangle (holes in the surface)
+    var boundaryEdges: Int
+    /// Edges shared by more than two triangles
+    var nonManifoldEdges: Int
+
+    /// Every edge is shared by exactly two triangles
+    var isWatertight: Bool {
+        boundaryEdges == 0 && nonManifoldEdges == 0
+    }
+}
+
+/// Thinnest wall found by `STLModel.minimumWallThickness`
+struct WallThickness: Equatable {
+    /// Distance to the opposite wall (mm)
+    var thickness: Double
+    /// Point on the surface where the thinnest wall starts (model coordinates)
+    var location: Vector3
+}
+
+extension STLModel {
+    /// Count open and non-manifold edges
+    func topology() -> MeshTopology {
+        var edgeUse: [Edge: Int] = [:]
+        edgeUse.reserveCapacity(triangles.count * 3 / 2)
+
+        for triangle in triangles {
+            edgeUse[Edge(triangle.v1, triangle.v2), default: 0] += 1
+            edgeUse[Edge(triangle.v2, triangle.v3), default: 0] += 1
+            edgeUse[Edge(triangle.v3, triangle.v1), default: 0] += 1
+        }
+
+        return MeshTopology(
+            boundaryEdges: edgeUse.values.filter { $0 == 1 }.count,
+            nonManifoldEdges: edgeUse.values.filter { $0 > 2 }.count
+        )
+    }
+
+    /// Thinnest wall of a closed mesh, measured by casting a ray inward from each triangle's center
+    ///
+    /// Only rays that leave the solid through the back of an outward-facing triangle count, so
+    /// inverted normals and internal faces do not produce false thin walls. Large meshes are
+    /// sampled evenly down to `maxSamples` triangles.
+    func minimumWallThickness(maxSamples: Int = 200_000) -> WallThickness? {
+        guard !triangles.isEmpty else { return nil }
+
+        // Ray casting runs in Float; keep the model near the origin
+        let offset = Self.recenteringOffset(for: boundingBox())
+        let local = offset == .zero ? self : translated(by: -offset)
+        let accelerator = SpatialAccelerator(triangles: local.triangles)
+        let step = max(1, local.triangles.count / max(1, maxSamples))
+        let startOffset: Float = 1e-4
+
+        var thinnest: WallThickness?
+        for index in stride(from: 0, to: local.triangles.count, by: step) {
+            let triangle = local.triangles[index]
+            guard triangle.area() > 1e-12 else { continue }
+
+            let center = (triangle.v1 + triangle.v2 + triangle.v3) / 3.0
+            let direction = (-triangle.normal).float3
+            let ray = Ray(origin: center.float3 + direction * startOffset, direction: direction)
+
+            guard let hit = accelerator.raycast(ray: ray),
+                  hit.normal.dot(-triangle.normal) > 0 else {
+                continue
+            }
+
+            let thickness = Double(hit.distance + startOffset)
+            if thickness < thinnest?.thickness ?? .infinity {
+                thinnest = WallThickness(thickness: thickness, location: center + offset)
+            }
+        }
+        return thinnest
+    }
+}
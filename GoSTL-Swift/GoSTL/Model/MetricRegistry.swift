@@ -0,0 +1,422 @@
+import Foundation
+
+/// Description of an analysis metric
+///
+/// Analysis output, inspection reports and `gostl explain` take labels, units and
+/// descriptions from here, so a metric registered once is documented everywhere.
+struct Metric: Equatable {
+    /// Stable identifier used in JSON/CSV output and `--select` (e.g. `dimensions.x`)
+    let key: String
+    /// Human-readable label
+    let label: String
+    /// Unit of the value (nil for counts and text)
+    let unit: String?
+    /// One-line summary
+    let summary: String
+    /// How the value is computed and how to interpret it
+    let details: String
+    /// `gostl assert` options that check the metric
+    var assertOptions: [String] = []
+    /// Related metric keys
+    var seeAlso: [String] = []
+}
+
+/// All metrics GoSTL reports
+enum MetricRegistry {
+    static let all: [Metric] = [
+        Metric(
+            key: "file",
+            label: "File",
+            unit: nil,
+            summary: "Name of the analyzed model file",
+            details: "The file name without its directory."
+        ),
+        Metric(
+            key: "triangles",
+            label: "Triangles",
+            unit: nil,
+            summary: "Number of triangles in the mesh",
+            details: """
+                Counts every facet of the mesh, including degenerate ones. Large counts slow down slicing \
+                and viewing; exports from CAD tools are often much finer than a print needs.
+                """,
+            assertOptions: ["--max-triangles"],
+            seeAlso: ["edges"]
+        ),
+        Metric(
+            key: "edges",
+            label: "Edges",
+            unit: nil,
+            summary: "Number of triangle edges (three per triangle)",
+            details: "Edges shared by two triangles are counted twice, so this is always three times the triangle count.",
+            seeAlso: ["triangles", "edge_length.avg"]
+        )
+    ]
+    + axisMetrics(
+        prefix: "dimensions",
+        labels: ["Width (X)", "Depth (Y)", "Height (Z)"],
+        summary: { "Size of the bounding box along \($0)" },
+        details: "Extent of the axis-aligned bounding box in the file's coordinates, i.e. the space the part occupies on a build plate.",
+        assertOptions: { ["--max-\($0.lowercased())", "--min-\($0.lowercased())"] }
+    )
+    + axisMetrics(
+        prefix: "bounds.min",
+        labels: ["Min X", "Min Y", "Min Z"],
+        summary: { "Smallest \($0) coordinate of the model" },
+        details: "Lower corner of the bounding box in the file's original coordinates (not recentered for display)."
+    )
+    + axisMetrics(
+        prefix: "bounds.max",
+        labels: ["Max X", "Max Y", "Max Z"],
+        summary: { "Largest \($0) coordinate of the model" },
+        details: "Upper corner of the bounding box in the file's original coordinates (not recentered for display)."
+    )
+    + [
+        Metric(
+            key: "volume",
+            label: "Volume",
+            unit: "mm³",
+            summary: "Enclosed volume of the mesh",
+            details: """
+                Sum of the signed volumes of the tetrahedra spanned by each triangle and the origin. Only meaningful \
+                for watertight meshes; holes or inverted normals make the result unreliable.
+                """,
+            assertOptions: ["--max-volume", "--min-volume"],
+            seeAlso: ["watertight", "weight.pla_100"]
+        ),
+        Metric(
+            key: "surface_area",
+            label: "Surface Area",
+            unit: "mm²",
+            summary: "Total area of all triangles",
+            details: "Sum of the triangle areas, including internal faces if the mesh has any.",
+            seeAlso: ["volume", "surface_area.up", "surface_area.down", "surface_area.vertical"]
+        ),
+        Metric(
+            key: "surface_area.up",
+            label: "Up-Facing Area",
+            unit: "mm²",
+            summary: "Area of faces looking upward (+Z)",
+            details: """
+                Faces whose normal points up by more than the vertical tolerance (`--vertical-tolerance`, \
+                \(Int(OrientationAreas.defaultVerticalTolerance))° by default). Shallow up-facing slopes show the most \
+                layer stepping, so this is the area to sand or smooth after printing.
+                """,
+            seeAlso: ["surface_area", "surface_area.down", "surface_area.vertical"]
+        ),
+        Metric(
+            key: "surface_area.down",
+            label: "Down-Facing Area",
+            unit: "mm²",
+            summary: "Area of faces looking downward (-Z)",
+            details: """
+                Faces whose normal points down by more than the vertical tolerance, including the face resting \
+                on the build plate. The rest are overhangs: an upper bound for the support contact area, which \
+                needs the most cleanup.
+                """,
+            seeAlso: ["surface_area", "surface_area.up", "surface_area.vertical"]
+        ),
+        Metric(
+            key: "surface_area.vertical",
+            label: "Vertical Area",
+            unit: "mm²",
+            summary: "Area of walls within the tolerance of vertical",
+            details: """
+                Faces whose normal is within the vertical tolerance of horizontal. Up-facing, down-facing and \
+                vertical areas add up to the surface area. Vertical walls print with the cleanest surface.
+                """,
+            seeAlso: ["surface_area", "surface_area.up", "surface_area.down"]
+        ),
+        Metric(
+            key: "edge_length.min",
+            label: "Min Edge Length",
+            unit: "mm",
+            summary: "Shortest triangle edge",
+            details: "Very short edges (far below the printer's resolution) indicate slivers or degenerate triangles.",
+            seeAlso: ["edge_length.max", "edge_length.avg"]
+        ),
+        Metric(
+            key: "edge_length.max",
+            label: "Max Edge Length",
+            unit: "mm",
+            summary: "Longest triangle edge",
+            details: "Long edges on curved surfaces show a coarse tessellation (visible facets on the print).",
+            seeAlso: ["edge_length.min", "edge_length.avg"]
+        ),
+        Metric(
+            key: "edge_length.avg",
+            label: "Avg Edge Length",
+            unit: "mm",
+            summary: "Mean triangle edge length",
+            details: "Average over all edges; a rough measure of the mesh resolution.",
+            seeAlso: ["edge_length.min", "edge_length.max"]
+        ),
+        Metric(
+            key: "weight.pla_100",
+            label: "PLA Weight (100%)",
+            unit: "g",
+            summary: "Weight when printed solid in PLA",
+            details: "Volume × 1.24 g/cm³. Ignores supports, brims and purge material.",
+            seeAlso: ["volume", "weight.pla_15"]
+        ),
+        Metric(
+            key: "weight.pla_15",
+            label: "PLA Weight (15%)",
+            unit: "g",
+            summary: "Rough weight when printed in PLA with 15% infill",
+            details: "Volume × 1.24 g/cm³ × 0.15. Walls and top/bottom layers make real prints heavier; use as a lower bound.",
+            seeAlso: ["weight.pla_100", "mass"]
+        ),
+        Metric(
+            key: "material",
+            label: "Material",
+            unit: nil,
+            summary: "Material used for the mass and cost estimate",
+            details: """
+                Chosen with `gostl analyze --material <name>` (\(Material.allCases.map { $0.rawValue.lowercased() }.joined(separator: ", "))). \
+                Only reported when --material or --infill is given.
+                """,
+            seeAlso: ["mass", "cost", "infill"]
+        ),
+        Metric(
+            key: "infill",
+            label: "Infill",
+            unit: "%",
+            summary: "Infill percentage used for the mass estimate",
+            details: """
+                Set with `--infill <percent>`. Resin and metal parts are solid, so their infill is always 100%.
+                """,
+            seeAlso: ["mass", "material"]
+        ),
+        Metric(
+            key: "mass",
+            label: "Mass",
+            unit: "g",
+            summary: "Estimated mass in the chosen material",
+            details: """
+                Volume × material density × infill. Walls and top/bottom layers make real prints heavier \
+                than the proportional infill estimate; supports, brims and purge material are ignored.
+                """,
+            seeAlso: ["volume", "material", "infill", "cost"]
+        ),
+        Metric(
+            key: "cost",
+            label: "Material Cost",
+            unit: "$",
+            summary: "Estimated material cost",
+            details: "Mass × the material's typical price per kg (e.g. $20/kg for PLA). Machine time is not included.",
+            seeAlso: ["mass", "material"]
+        ),
+        Metric(
+            key: "watertight",
+            label: "Watertight",
+            unit: nil,
+            summary: "Whether every edge is shared by exactly two triangles",
+            details: """
+                Edges used by a single triangle are open (holes in the surface); edges used by more than two triangles \
+                are non-manifold. Slicers may repair or misinterpret such meshes, and volume and wall thickness are \
+                only reliable for watertight meshes.
+                """,
+            assertOptions: ["--watertight"],
+            seeAlso: ["volume", "wall_thickness.min"]
+        ),
+        Metric(
+            key: "wall_thickness.min",
+            label: "Min Wall Thickness",
+            unit: "mm",
+            summary: "Thinnest wall of the solid",
+            details: """
+                A ray is cast inward from the center of each triangle; the distance to where it leaves the solid \
+                is the wall thickness there. Walls thinner than about two nozzle widths may not print. Large meshes \
+                are sampled; thin features between sample points can be missed.
+                """,
+            assertOptions: ["--min-wall"],
+            seeAlso: ["watertight"]
+        ),
+        Metric(
+            key: "density",
+            label: "Density",
+            unit: "g/cm³",
+            summary: "Uniform density used for the mass properties",
+            details: """
+                The material density (`--material`, PLA by default) scaled by `--infill`. Reported with \
+                `gostl analyze --mass-properties`; real prints are denser at the walls than in the infill.
+                """,
+            seeAlso: ["mass", "material", "infill"]
+        )
+    ]
+    + axisMetrics(
+        prefix: "center_of_mass",
+        labels: ["Center of Mass X", "Center of Mass Y", "Center of Mass Z"],
+        summary: { "\($0) coordinate of the center of mass" },
+        details: """
+            Centroid of the enclosed volume in the file's coordinates, assuming uniform density. Only reliable \
+            for watertight meshes. Reported with `gostl analyze --mass-properties`.
+            """
+    )
+    + inertiaMetrics()
+    + principalMetrics()
+    + stabilityMetrics()
+
+    /// Metric with the given key (case-insensitive)
+    static func metric(for key: String) -> Metric? {
+        let key = key.lowercased()
+        return all.first { $0.key == key }
+    }
+
+    /// Metric checked by a `gostl assert` option (with or without leading dashes)
+    static func metric(forAssertOption option: String) -> Metric? {
+        let option = "--" + option.drop { $0 == "-" }.lowercased()
+        return all.first { $0.assertOptions.contains(option) }
+    }
+
+    /// Metrics whose key, label or summary contain the term
+    static func search(_ term: String) -> [Metric] {
+        let term = term.lowercased()
+        return all.filter {
+            $0.key.contains(term) || $0.label.lowercased().contains(term) || $0.summary.lowercased().contains(term)
+        }
+    }
+
+    /// Label and unit for output, falling back to the key for unregistered metrics
+    static func label(for key: String) -> (label: String, unit: String?) {
+        guard let metric = metric(for: key) else { return (key, nil) }
+        return (metric.label, metric.unit)
+    }
+
+    /// Entries of the inertia tensor (`inertia.xx` ... `inertia.yz`)
+    private static func inertiaMetrics() -> [Metric] {
+        ["xx", "yy", "zz", "xy", "xz", "yz"].map { component -> Metric in
+            let isMoment = component.first == component.last
+            return Metric(
+                key: "inertia.\(component)",
+                label: "Inertia \(component.uppercased())",
+                unit: "g·mm²",
+                summary: isMoment
+                    ? "Moment of inertia about the \(component.prefix(1).uppercased()) axis through the center of mass"
+                    : "Inertia tensor entry \(component.uppercased()) (negated product of inertia)",
+                details: """
+                    Entry of the inertia tensor about the center of mass in the file's axes, integrated exactly over \
+                    the mesh volume for a uniform density. Off-diagonal entries are the negated products of inertia, \
+                    as physics engines expect. Multiply by 1e-9 for kg·m². Reported with `gostl analyze --mass-properties`.
+                    """,
+                seeAlso: ["principal_moments.1", "center_of_mass.x"]
+            )
+        }
+    }
+
+    /// Principal moments, axes and radii of gyration (1 to 3, smallest moment first)
+    private static func principalMetrics() -> [Metric] {
+        (1...3).flatMap { index -> [Metric] in
+            let others = (1...3).filter { $0 != index }.map { "principal_moments.\($0)" }
+            let moment = Metric(
+                key: "principal_moments.\(index)",
+                label: "Principal Moment \(index)",
+                unit: "g·mm²",
+                summary: "Principal moment of inertia \(index) of 3 (ascending)",
+                details: """
+                    Eigenvalues of the inertia tensor about the center of mass, smallest first. The part spins most \
+                    easily about the axis of the smallest moment. Reported with `gostl analyze --mass-properties`.
+                    """,
+                seeAlso: ["principal_axes.\(index).x", "radius_of_gyration.\(index)"] + others
+            )
+            let radius = Metric(
+                key: "radius_of_gyration.\(index)",
+                label: "Radius of Gyration \(index)",
+                unit: "mm",
+                summary: "Radius of gyration about principal axis \(index)",
+                details: """
+                    √(principal moment / mass): the distance from the axis at which the whole mass would have the \
+                    same moment of inertia. Independent of the density.
+                    """,
+                seeAlso: ["principal_moments.\(index)", "mass"]
+            )
+            let axes = ["X", "Y", "Z"].map { axis -> Metric in
+                Metric(
+                    key: "principal_axes.\(index).\(axis.lowercased())",
+                    label: "Principal Axis \(index) \(axis)",
+                    unit: nil,
+                    summary: "\(axis) component of the unit principal axis \(index)",
+                    details: """
+                        Direction of the principal axis of inertia \(index) in the file's axes. The three axes form a \
+                        right-handed frame; the sign of axes 1 and 2 is chosen so their largest component is positive.
+                        """,
+                    seeAlso: ["principal_moments.\(index)"]
+                )
+            }
+            return [moment, radius] + axes
+        }
+    }
+
+    /// Tip-over check when resting on the bottom face (`stability.*`)
+    private static func stabilityMetrics() -> [Metric] {
+        let reported = "Reported with `gostl analyze --stability`."
+        return [
+            Metric(
+                key: "stability.stable",
+                label: "Stable",
+                unit: nil,
+                summary: "Whether the model stands on its bottom face without tipping over",
+                details: """
+                    The model rests on its lowest point; vertices within \(StabilityCheck.contactTolerance) mm of it touch \
+                    the ground and their convex hull is the support polygon. The model is stable (yes) when the center \
+                    of mass, for a uniform density, lies over that polygon. \(reported)
+                    """,
+                assertOptions: ["--stable"],
+                seeAlso: ["stability.margin", "stability.tilt_angle", "center_of_mass.x"]
+            ),
+            Metric(
+                key: "stability.margin",
+                label: "Stability Margin",
+                unit: "mm",
+                summary: "Distance from the center of mass to the nearest edge of the support polygon",
+                details: """
+                    Measured in the XY plane from the projected center of mass to the edge the model tips over first; \
+                    negative when the center of mass lies outside the support polygon. \(reported)
+                    """,
+                seeAlso: ["stability.stable", "stability.tilt_angle"]
+            ),
+            Metric(
+                key: "stability.tilt_angle",
+                label: "Tilt Angle",
+                unit: "°",
+                summary: "How far the model can be tilted before it tips over",
+                details: """
+                    atan(margin / center height): the angle by which the model can be tipped over its most critical \
+                    edge before the center of mass passes it. Tall figures on small bases have small angles; \
+                    negative when the model cannot stand. \(reported)
+                    """,
+                assertOptions: ["--min-tilt"],
+                seeAlso: ["stability.margin", "stability.center_height"]
+            ),
+            Metric(
+                key: "stability.center_height",
+                label: "Center of Mass Height",
+                unit: "mm",
+                summary: "Height of the center of mass above the resting plane",
+                details: "Lowering the center of mass (e.g. a heavier or wider base) increases the tilt angle. \(reported)",
+                seeAlso: ["stability.tilt_angle", "center_of_mass.z"]
+            )
+        ]
+    }
+
+    private static func axisMetrics(
+        prefix: String,
+        labels: [String],
+        summary: (String) -> String,
+        details: String,
+        assertOptions: (String) -> [String] = { _ in [] }
+    ) -> [Metric] {
+        ["X", "Y", "Z"].enumerated().map { index, axis in
+            Metric(
+                key: "\(prefix).\(axis.lowercased())",
+                label: labels[index],
+                unit: "mm",
+                summary: summary(axis),
+                details: details,
+                assertOptions: assertOptions(axis),
+                seeAlso: ["X", "Y", "Z"].filter { $0 != axis }.map { "\(prefix).\($0.lowercased())" }
+            )
+        }
+    }
+}
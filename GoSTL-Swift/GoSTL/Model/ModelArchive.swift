@@ -0,0 +1,150 @@
+import Foundation
+
+/// Errors that can occur while opening a ZIP archive of models
+enum ModelArchiveError: LocalizedError {
+    case noModels
+    case noLoadableModels([String])
+    case unsafePath(String)
+    case entryNotFound(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .noModels:
+            return "The archive does not contain any models"
+        case .noLoadableModels(let messages):
+            return "None of the models in the archive could be loaded:\n" + messages.joined(separator: "\n")
+        case .unsafePath(let path):
+            return "The archive contains an unsafe path: \(path)"
+        case .entryNotFound(let path):
+            return "\(path) is not a model in the archive"
+        }
+    }
+}
+
+/// A ZIP archive with several models, extracted to the temp workspace
+///
+/// The whole archive is extracted with its folder structure, so OpenSCAD projects find their
+/// `use`/`include` files and Python scripts their local modules. Files that another model
+/// depends on are not listed as models of their own.
+struct ModelArchive: @unchecked Sendable {
+    struct Entry: Identifiable, Equatable {
+        /// Path inside the archive, with "/" separators
+        let path: String
+
+        var id: String { path }
+
+        /// File name shown in lists
+        var name: String {
+            (path as NSString).lastPathComponent
+        }
+    }
+
+    /// Folder the archive was extracted to
+    let folder: URL
+    /// Models in the archive, sorted by path
+    let entries: [Entry]
+
+    /// Extract an archive and list its models
+    static func open(url: URL) throws -> ModelArchive {
+        let folder = TempWorkspace.shared.makeURL(prefix: "archive", pathExtension: "contents")
+        do {
+            let entries = try extract(ZipArchive(data: Data(contentsOf: url)), to: folder)
+            guard !entries.isEmpty else { throw ModelArchiveError.noModels }
+            return ModelArchive(folder: folder, entries: entries)
+        } catch {
+            try? FileManager.default.removeItem(at: folder)
+            throw error
+        }
+    }
+
+    /// Write every file of the archive below a folder and return the models
+    static func extract(_ archive: ZipArchive, to folder: URL) throws -> [Entry] {
+        var modelFiles: [String] = []
+        for path in archive.fileNames where !path.hasSuffix("/") && !isIgnored(path) {
+            guard isSafe(path) else { throw ModelArchiveError.unsafePath(path) }
+            guard let data = try archive.extractFile(path: path) else { continue }
+
+            let destination = folder.appendingPathComponent(path)
+            try FileManager.default.createDirectory(at: destination.deletingLastPathComponent(), withIntermediateDirectories: true)
+            try data.write(to: destination)
+
+            if ModelFileLoader.renderableExtensions.contains(ModelFileLoader.modelExtension(of: destination)) {
+                modelFiles.append(path)
+            }
+        }
+
+        // Files used by another model (OpenSCAD libraries, Python modules) are not models of their own
+        let root = folder.standardizedFileURL.resolvingSymlinksInPath()
+        var dependencies = Set<String>()
+        for path in modelFiles {
+            let url = folder.appendingPathComponent(path)
+            let used: [URL]
+            switch ModelFileLoader.modelExtension(of: url) {
+            case "scad":
+                used = OpenSCADRenderer(workDir: url.deletingLastPathComponent()).resolveDependencies(scadFile: url)
+            case "py":
+                used = PythonCADRenderer(workDir: url.deletingLastPathComponent()).resolveDependencies(script: url)
+            default:
+                used = []
+            }
+            for dependency in used {
+                let components = dependency.standardizedFileURL.resolvingSymlinksInPath().pathComponents
+                let relative = components.dropFirst(root.pathComponents.count).joined(separator: "/")
+                if relative != path {
+                    dependencies.insert(relative)
+                }
+            }
+        }
+
+        return modelFiles.filter { !dependencies.contains($0) }.sorted().map { Entry(path: $0) }
+    }
+
+    /// macOS resource forks and hidden files
+    private static func isIgnored(_ path: String) -> Bool {
+        path.hasPrefix("__MACOSX/") || path.split(separator: "/").contains { $0.hasPrefix(".") }
+    }
+
+    /// Relative paths that stay inside the extraction folder
+    private static func isSafe(_ path: String) -> Bool {
+        !path.hasPrefix("/") && !path.split(separator: "/").contains("..")
+    }
+
+    // MARK: - Loading
+
+    /// Load one model of the archive (OpenSCAD and Python entries are rendered first)
+    func load(_ entry: Entry) throws -> STLModel {
+        guard entries.contains(entry) else { throw ModelArchiveError.entryNotFound(entry.path) }
+        var model = try ModelFileLoader.loadRendering(url: folder.appendingPathComponent(entry.path))
+        model.name = ModelFileLoader.modelName(of: URL(fileURLWithPath: entry.path))
+        return model
+    }
+
+    /// All models of the archive as one assembly, in their own coordinates
+    /// - Returns: The assembly and a message for every model that could not be loaded
+    func loadAssembly(name: String?) throws -> (model: STLModel, warnings: [String]) {
+        var triangles: [Triangle] = []
+        var warnings: [String] = []
+        for entry in entries {
+            do {
+                triangles += try load(entry).triangles
+            } catch {
+                warnings.append("\(entry.path): \(error.localizedDescription)")
+            }
+        }
+        guard !triangles.isEmpty else {
+            throw warnings.isEmpty ? ModelArchiveError.noModels : ModelArchiveError.noLoadableModels(warnings)
+        }
+        return (STLModel(triangles: triangles, name: name), warnings)
+    }
+
+    /// Load an entry by path, or the assembly for nil
+    func load(path: String?, name: String?) throws -> (model: STLModel, warnings: [String]) {
+        guard let path else { return try loadAssembly(name: name) }
+        return (try load(Entry(path: path)), [])
+    }
+
+    /// Remove the extracted files
+    func remove() {
+        try? FileManager.default.removeItem(at: folder)
+    }
+}
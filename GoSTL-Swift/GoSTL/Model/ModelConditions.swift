@@ -0,0 +1,149 @@
+import Foundation
+
+/// A condition a model must meet (checked by `gostl assert`)
+enum ModelCondition: Equatable {
+    /// Bounding box size along an axis (0=X, 1=Y, 2=Z) must not exceed the limit (mm)
+    case maxSize(axis: Int, limit: Double)
+    /// Bounding box size along an axis must be at least the limit (mm)
+    case minSize(axis: Int, limit: Double)
+    case maxTriangles(Int)
+    case maxVolume(Double)
+    case minVolume(Double)
+    /// No open or non-manifold edges
+    case watertight
+    /// Thinnest wall must be at least the limit (mm)
+    case minWallThickness(Double)
+    /// Center of mass over the support polygon when resting on the bottom face
+    case stable
+    /// Tilt before the model tips over must be at least the limit (degrees)
+    case minTiltAngle(Double)
+
+    /// Identifier matching the command-line option (e.g. `max-x`)
+    var name: String {
+        let axes = ["x", "y", "z"]
+        switch self {
+        case .maxSize(let axis, _): return "max-\(axes[axis])"
+        case .minSize(let axis, _): return "min-\(axes[axis])"
+        case .maxTriangles: return "max-triangles"
+        case .maxVolume: return "max-volume"
+        case .minVolume: return "min-volume"
+        case .watertight: return "watertight"
+        case .minWallThickness: return "min-wall"
+        case .stable: return "stable"
+        case .minTiltAngle: return "min-tilt"
+        }
+    }
+}
+
+/// Outcome of checking one condition
+struct ConditionResult: Codable, Equatable {
+    /// Condition name (e.g. `max-x`)
+    var check: String
+    var passed: Bool
+    /// Measured value
+    var actual: String
+    /// Required value
+    var expected: String
+    /// Additional information, e.g. where the thinnest wall is
+    var detail: String?
+}
+
+/// Evaluates conditions against a model; expensive analyses run only when a condition needs them
+enum ModelConditionEvaluator {
+    static func evaluate(_ conditions: [ModelCondition], on model: STLModel) -> [ConditionResult] {
+        let size = model.boundingBox().size
+        let sizes = [size.x, size.y, size.z]
+        var volume: Double?
+        var topology: MeshTopology?
+        var wall: WallThickness??
+        var stability: StabilityCheck??
+
+        func number(_ value: Double) -> String {
+            String(format: "%.3f", value)
+        }
+
+        return conditions.map { condition in
+            switch condition {
+            case .maxSize(let axis, let limit):
+                return ConditionResult(check: condition.name, passed: sizes[axis] <= limit,
+                                       actual: number(sizes[axis]), expected: "<= \(number(limit))")
+
+            case .minSize(let axis, let limit):
+                return ConditionResult(check: condition.name, passed: sizes[axis] >= limit,
+                                       actual: number(sizes[axis]), expected: ">= \(number(limit))")
+
+            case .maxTriangles(let limit):
+                return ConditionResult(check: condition.name, passed: model.triangleCount <= limit,
+                                       actual: String(model.triangleCount), expected: "<= \(limit)")
+
+            case .maxVolume(let limit):
+                let value = volume ?? model.volume()
+                volume = value
+                return ConditionResult(check: condition.name, passed: value <= limit,
+                                       actual: number(value), expected: "<= \(number(limit))")
+
+            case .minVolume(let limit):
+                let value = volume ?? model.volume()
+                volume = value
+                return ConditionResult(check: condition.name, passed: value >= limit,
+                                       actual: number(value), expected: ">= \(number(limit))")
+
+            case .watertight:
+                let value = topology ?? model.topology()
+                topology = value
+                return ConditionResult(
+                    check: condition.name,
+                    passed: value.isWatertight,
+                    actual: value.isWatertight ? "watertight" : "\(value.boundaryEdges) open, \(value.nonManifoldEdges) non-manifold edges",
+                    expected: "watertight"
+                )
+
+            case .minWallThickness(let limit):
+                let value = wall ?? model.minimumWallThickness()
+                wall = value
+                guard let thinnest = value else {
+                    return ConditionResult(check: condition.name, passed: false, actual: "unknown", expected: ">= \(number(limit))",
+                                           detail: "no closed walls found")
+                }
+                let location = thinnest.location
+                return ConditionResult(
+                    check: condition.name,
+                    passed: thinnest.thickness >= limit,
+                    actual: number(thinnest.thickness),
+                    expected: ">= \(number(limit))",
+                    detail: String(format: "at (%.3f, %.3f, %.3f)", location.x, location.y, location.z)
+                )
+
+            case .stable:
+                let value = stability ?? StabilityCheck.check(model: model)
+                stability = value
+                guard let check = value else {
+                    return ConditionResult(check: condition.name, passed: false, actual: "unknown", expected: "stable",
+                                           detail: "model has no volume")
+                }
+                return ConditionResult(
+                    check: condition.name,
+                    passed: check.isStable,
+                    actual: check.isStable ? "stable" : "tips over",
+                    expected: "stable",
+                    detail: String(format: "margin %.3f mm", check.margin)
+                )
+
+            case .minTiltAngle(let limit):
+                let value = stability ?? StabilityCheck.check(model: model)
+                stability = value
+                guard let check = value else {
+                    return ConditionResult(check: condition.name, passed: false, actual: "unknown", expected: ">= \(number(limit))",
+                                           detail: "model has no volume")
+                }
+                return ConditionResult(
+                    check: condition.name,
+                    passed: check.tiltAngle >= limit,
+                    actual: number(check.tiltAngle),
+                    expected: ">= \(number(limit))",
+                    detail: String(format: "center of mass %.3f mm high, margin %.3f mm", check.centerHeight, check.margin)
+                )
+            }
+        }
+    }
+}
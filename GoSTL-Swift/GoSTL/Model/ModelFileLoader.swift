@@ -0,0 +1,102 @@
+import Foundation
+
+/// Loads mesh files without the viewer (for scripts and command-line tools)
+enum ModelFileLoader {
+    /// File extensions that can be loaded without rendering through external tools
+    static let supportedExtensions = ["stl", "3mf"]
+
+    /// File extensions the viewer can open (built-in formats plus plugin importers)
+    static var viewerExtensions: [String] {
+        ["stl", "3mf", "scad", "py", "yaml", "yml", "zip"] + PluginRegistry.shared.importExtensions
+    }
+
+    /// File extensions the command-line tools accept (loadable formats plus OpenSCAD and
+    /// CadQuery/build123d scripts, rendered to a mesh)
+    static var renderableExtensions: [String] {
+        supportedExtensions + ["scad", "py"]
+    }
+
+    /// Extension that selects the file format, ignoring compression (`part.stl.gz` -> `stl`)
+    static func modelExtension(of url: URL) -> String {
+        FileCompression.uncompressedURL(url).pathExtension.lowercased()
+    }
+
+    /// File name without format and compression extensions (`part.stl.gz` -> `part`)
+    static func modelName(of url: URL) -> String {
+        FileCompression.uncompressedURL(url).deletingPathExtension().lastPathComponent
+    }
+
+    /// File URL for a command-line argument: a path, or an http(s)/s3 URL of an STL or 3MF file
+    /// downloaded into the remote file cache (revalidated if it was downloaded before)
+    static func url(forArgument argument: String) throws -> URL {
+        guard let remote = RemoteFileCache.remoteURL(argument) else {
+            return URL(fileURLWithPath: argument)
+        }
+        let (file, source) = try RemoteFileCache.shared.fetch(remote)
+        if source == .offline {
+            FileHandle.standardError.write(Data("\(argument): offline, using the cached copy\n".utf8))
+        }
+        return file
+    }
+
+    /// Load a model file, rendering .scad files through OpenSCAD and .py scripts through Python first
+    /// (a .zip archive loads all of its models as one assembly)
+    static func loadRendering(url: URL, job: JobContext? = nil) throws -> STLModel {
+        try loadRenderingWithMessages(url: url, job: job).model
+    }
+
+    /// Like `loadRendering(url:)`, also returning what the OpenSCAD or Python render printed
+    /// (echoes and warnings; empty for mesh files)
+    /// - Parameter job: Background job that terminates an importer plugin when cancelled
+    static func loadRenderingWithMessages(url: URL, job: JobContext? = nil) throws -> (model: STLModel, messages: [String]) {
+        switch modelExtension(of: url) {
+        case "scad":
+            break
+        case "py":
+            let result = try PythonCADRenderer(workDir: url.deletingLastPathComponent()).render(script: url)
+            return (result.model, result.messages)
+        case "zip":
+            let archive = try ModelArchive.open(url: url)
+            defer { archive.remove() }
+            return (try archive.loadAssembly(name: modelName(of: url)).model, [])
+        default:
+            return (try load(url: url, job: job), [])
+        }
+        let output = TempWorkspace.shared.makeURL(prefix: "render", pathExtension: "stl")
+        defer { try? FileManager.default.removeItem(at: output) }
+        let result = try OpenSCADRenderer(workDir: url.deletingLastPathComponent()).renderToSTL(scadFile: url, outputFile: output)
+        var model = try STLParser.parse(url: output)
+        model.name = url.deletingPathExtension().lastPathComponent
+        return (model, result.warnings)
+    }
+
+    /// Files whose changes affect the loaded model: the file itself plus its OpenSCAD includes
+    /// or Python imports
+    static func dependencies(of url: URL) -> [URL] {
+        switch url.pathExtension.lowercased() {
+        case "scad":
+            return OpenSCADRenderer(workDir: url.deletingLastPathComponent()).resolveDependencies(scadFile: url)
+        case "py":
+            return PythonCADRenderer(workDir: url.deletingLastPathComponent()).resolveDependencies(script: url)
+        default:
+            return [url]
+        }
+    }
+
+    /// Load an STL (optionally .gz/.zst compressed), 3MF or plugin-imported file in its original coordinates
+    /// - Parameter job: Background job that terminates an importer plugin when cancelled
+    static func load(url: URL, job: JobContext? = nil) throws -> STLModel {
+        let ext = modelExtension(of: url)
+        switch ext {
+        case "stl":
+            return try STLParser.parse(url: url)
+        case "3mf":
+            return try ThreeMFParser.parse(url: url)
+        default:
+            if let plugin = PluginRegistry.shared.importer(forExtension: ext) {
+                return try PluginRegistry.shared.importModel(from: url, using: plugin, job: job)
+            }
+            throw FileLoadError.unsupportedFileType(ext)
+        }
+    }
+}
@@ -20,6 +20,15 @@ struct ModelInfo {
     /// Selected material for weight calculation
     var material: Material = .pla
 
+    /// Infill fraction (0.0 to 1.0) for weight and cost of printed materials
+    var infill: Double = 1.0
+
+    /// Offset subtracted from the file's coordinates for rendering (zero if not recentered)
+    var coordinateOffset: Vector3 = .zero
+
+    /// Tip-over check when resting on the bottom face (nil without volume)
+    var stability: StabilityCheck?
+
     /// Computed properties for display
 
     var dimensions: Vector3 {
@@ -42,9 +51,24 @@ struct ModelInfo {
         boundingBox.center
     }
 
-    /// Calculated weight based on volume and material density
+    /// Whether the model was moved near the origin for rendering
+    var isRecentered: Bool {
+        coordinateOffset != .zero
+    }
+
+    /// Center in the file's original coordinates
+    var originalCenter: Vector3 {
+        center + coordinateOffset
+    }
+
+    /// Mass and cost for the selected material and infill
+    var estimate: MassEstimate {
+        material.estimate(volume: volume, infill: infill)
+    }
+
+    /// Calculated weight based on volume, material density and infill
     var weight: Double {
-        material.weight(volume: volume)
+        estimate.mass
     }
 
     /// Cycle to the next material type
@@ -56,12 +80,14 @@ struct ModelInfo {
     }
 
     /// Create model info from an STL model
-    init(fileName: String, model: STLModel) {
+    init(fileName: String, model: STLModel, coordinateOffset: Vector3 = .zero) {
         self.fileName = fileName
         self.triangleCount = model.triangleCount
         self.boundingBox = model.boundingBox()
         self.volume = model.volume()
         self.surfaceArea = model.surfaceArea()
+        self.coordinateOffset = coordinateOffset
+        self.stability = StabilityCheck.check(model: model)
     }
 
     /// Create model info for an empty file (no geometry)
@@ -73,6 +99,12 @@ struct ModelInfo {
         self.surfaceArea = 0
     }
 
+    /// Stability for display, e.g. "Stable, 12.3° tilt" or "Tips over"
+    static func formatStability(_ stability: StabilityCheck) -> String {
+        guard stability.isStable else { return "Tips over" }
+        return String(format: "Stable, %.1f° tilt", stability.tiltAngle)
+    }
+
     /// Format a dimension value for display (with appropriate precision)
     static func formatDimension(_ value: Double) -> String {
         if value < 1.0 {
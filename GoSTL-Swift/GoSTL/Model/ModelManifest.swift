@@ -0,0 +1,191 @@
+import CryptoKit
+import Foundation
+
+/// Hashes and key figures of every model in a library folder, used to detect changed files
+///
+/// Stored as JSON with paths relative to the library folder. Besides the SHA-256 of the
+/// file, each entry has a geometry hash over the vertex coordinates (as stored in a binary
+/// STL), so a re-export that only changes the header, normals or attribute bytes is told
+/// apart from a change of the shape itself.
+struct ModelManifest: Codable {
+    struct Entry: Codable, Equatable {
+        /// Path relative to the library folder, with "/" separators
+        var path: String
+        var sha256: String
+        var geometryHash: String
+        var triangleCount: Int
+        var dimensions: Vector3
+        var volume: Double
+    }
+
+    static let currentVersion = 1
+
+    var version: Int = ModelManifest.currentVersion
+    var date: Date
+    var entries: [Entry]
+
+    init(entries: [Entry], date: Date = Date()) {
+        self.entries = entries
+        self.date = date
+    }
+
+    // MARK: - Creation
+
+    /// Manifest of all models below a folder, sorted by path
+    /// - Parameter onFailure: Called for files that cannot be read or parsed (they are left out)
+    static func create(folder: URL, onFailure: (String, Error) -> Void = { _, _ in }) -> ModelManifest {
+        let entries = modelFiles(in: folder).compactMap { path -> Entry? in
+            do {
+                return try entry(for: folder.appendingPathComponent(path), path: path)
+            } catch {
+                onFailure(path, error)
+                return nil
+            }
+        }
+        return ModelManifest(entries: entries)
+    }
+
+    static func entry(for url: URL, path: String) throws -> Entry {
+        let data = try Data(contentsOf: url)
+        let model = try ModelFileLoader.load(url: url)
+        return Entry(
+            path: path,
+            sha256: hexDigest(SHA256.hash(data: data)),
+            geometryHash: geometryHash(of: model),
+            triangleCount: model.triangleCount,
+            dimensions: model.boundingBox().size,
+            volume: model.volume()
+        )
+    }
+
+    /// Relative paths of the .stl (also .gz/.zst compressed) and .3mf files below a folder (hidden files skipped)
+    static func modelFiles(in folder: URL) -> [String] {
+        let root = folder.standardizedFileURL.resolvingSymlinksInPath()
+        guard let enumerator = FileManager.default.enumerator(
+            at: root,
+            includingPropertiesForKeys: [.isRegularFileKey],
+            options: .skipsHiddenFiles
+        ) else { return [] }
+
+        var paths: [String] = []
+        for case let url as URL in enumerator
+        where ModelFileLoader.supportedExtensions.contains(ModelFileLoader.modelExtension(of: url)) {
+            let components = url.standardizedFileURL.resolvingSymlinksInPath().pathComponents
+            paths.append(components.dropFirst(root.pathComponents.count).joined(separator: "/"))
+        }
+        return paths.sorted()
+    }
+
+    /// SHA-256 over the vertex coordinates as little-endian Float32, in triangle order
+    static func geometryHash(of model: STLModel) -> String {
+        var hasher = SHA256()
+        var buffer = [Float](repeating: 0, count: 9)
+        for triangle in model.triangles {
+            for (index, vertex) in [triangle.v1, triangle.v2, triangle.v3].enumerated() {
+                buffer[index * 3] = Float(vertex.x)
+                buffer[index * 3 + 1] = Float(vertex.y)
+                buffer[index * 3 + 2] = Float(vertex.z)
+            }
+            buffer.withUnsafeBytes { hasher.update(bufferPointer: $0) }
+        }
+        return hexDigest(hasher.finalize())
+    }
+
+    private static func hexDigest(_ digest: SHA256.Digest) -> String {
+        digest.map { String(format: "%02x", $0) }.joined()
+    }
+
+    // MARK: - Verification
+
+    enum Status: String {
+        case unchanged
+        /// File bytes differ but the geometry is the same (header, normals, attributes)
+        case metadataChanged = "metadata changed"
+        case geometryChanged = "geometry changed"
+        case missing
+        case added
+        case unreadable
+
+        /// Whether the status counts as a failed verification
+        var isFailure: Bool {
+            self != .unchanged && self != .metadataChanged
+        }
+    }
+
+    struct Difference {
+        let path: String
+        let status: Status
+        /// What changed, e.g. "triangles 120 -> 124" (empty unless the geometry changed)
+        let details: [String]
+    }
+
+    /// Compare the manifest against the current files of a library folder
+    func verify(folder: URL) -> [Difference] {
+        let current = Set(Self.modelFiles(in: folder))
+        var differences: [Difference] = []
+
+        for entry in entries {
+            guard current.contains(entry.path) else {
+                differences.append(Difference(path: entry.path, status: .missing, details: []))
+                continue
+            }
+            guard let now = try? Self.entry(for: folder.appendingPathComponent(entry.path), path: entry.path) else {
+                differences.append(Difference(path: entry.path, status: .unreadable, details: []))
+                continue
+            }
+            differences.append(Self.compare(entry, now))
+        }
+
+        let known = Set(entries.map(\.path))
+        for path in current.subtracting(known).sorted() {
+            differences.append(Difference(path: path, status: .added, details: []))
+        }
+        return differences
+    }
+
+    /// Classify the change between a recorded and a current entry
+    static func compare(_ recorded: Entry, _ current: Entry) -> Difference {
+        if recorded.sha256 == current.sha256 {
+            return Difference(path: recorded.path, status: .unchanged, details: [])
+        }
+        if recorded.geometryHash == current.geometryHash {
+            return Difference(path: recorded.path, status: .metadataChanged, details: [])
+        }
+
+        var details: [String] = []
+        if recorded.triangleCount != current.triangleCount {
+            details.append("triangles \(recorded.triangleCount) -> \(current.triangleCount)")
+        }
+        let format = { (size: Vector3) in String(format: "%.3f × %.3f × %.3f", size.x, size.y, size.z) }
+        if format(recorded.dimensions) != format(current.dimensions) {
+            details.append("dimensions \(format(recorded.dimensions)) -> \(format(current.dimensions)) mm")
+        }
+        if String(format: "%.3f", recorded.volume) != String(format: "%.3f", current.volume) {
+            details.append(String(format: "volume %.3f -> %.3f mm³", recorded.volume, current.volume))
+        }
+        return Difference(path: recorded.path, status: .geometryChanged, details: details)
+    }
+
+    // MARK: - File I/O
+
+    func write(to url: URL) throws {
+        try encoded().write(to: url)
+    }
+
+    static func load(from url: URL) throws -> ModelManifest {
+        try decode(Data(contentsOf: url))
+    }
+
+    func encoded() throws -> Data {
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+        encoder.dateEncodingStrategy = .iso8601
+        return try encoder.encode(self)
+    }
+
+    static func decode(_ data: Data) throws -> ModelManifest {
+        let decoder = JSONDecoder()
+        decoder.dateDecodingStrategy = .iso8601
+        return try decoder.decode(ModelManifest.self, from: data)
+    }
+}
@@ -0,0 +1,137 @@
+import Foundation
+
+/// Metadata found in and around a model file (shown in the metadata panel)
+struct ModelMetadata {
+    struct Entry: Equatable, Identifiable {
+        let label: String
+        let value: String
+
+        var id: String { label }
+    }
+
+    /// File system information (format, path, size, modification date)
+    var file: [Entry] = []
+    /// Metadata stored in the file itself (STL header, 3MF metadata)
+    var embedded: [Entry] = []
+
+    /// Number of bytes of a binary STL header
+    static let stlHeaderSize = 80
+
+    /// Read the metadata of a model file
+    /// - Parameter sourceURL: File the model was generated from, if it differs from `url`
+    static func read(from url: URL, sourceURL: URL? = nil) -> ModelMetadata {
+        var metadata = ModelMetadata()
+        let format = ModelFileLoader.modelExtension(of: url).uppercased()
+        let compression = FileCompression(url: url).map { " (\($0.rawValue) compressed)" } ?? ""
+        metadata.file.append(Entry(label: "Format", value: format.isEmpty ? "Unknown" : format + compression))
+        metadata.file.append(Entry(label: "Path", value: url.path))
+
+        if let attributes = try? FileManager.default.attributesOfItem(atPath: url.path) {
+            if let size = attributes[.size] as? NSNumber {
+                metadata.file.append(Entry(
+                    label: "Size",
+                    value: ByteCountFormatter.string(fromByteCount: size.int64Value, countStyle: .file)
+                ))
+            }
+            if let modified = attributes[.modificationDate] as? Date {
+                metadata.file.append(Entry(
+                    label: "Modified",
+                    value: modified.formatted(date: .abbreviated, time: .shortened)
+                ))
+            }
+        }
+
+        if let source = sourceURL ?? siblingSource(of: url), source != url {
+            metadata.file.append(Entry(label: "Source", value: source.path))
+        }
+
+        switch ModelFileLoader.modelExtension(of: url) {
+        case "stl":
+            if let header = stlHeader(of: url) {
+                metadata.embedded.append(header)
+            }
+        case "3mf":
+            if let data = try? FileCompression.read(url) {
+                metadata.embedded = threeMFMetadata(in: data)
+            }
+        default:
+            break
+        }
+
+        return metadata
+    }
+
+    // MARK: - STL
+
+    /// Header text of a binary STL, or the solid name of an ASCII STL
+    static func stlHeader(of url: URL) -> Entry? {
+        if FileCompression(url: url) != nil {
+            return (try? FileCompression.read(url)).flatMap { stlHeader(in: $0) }
+        }
+        guard let handle = try? FileHandle(forReadingFrom: url) else { return nil }
+        defer { try? handle.close() }
+        guard let data = try? handle.read(upToCount: stlHeaderSize), !data.isEmpty else { return nil }
+        return stlHeader(in: data)
+    }
+
+    static func stlHeader(in data: Data) -> Entry? {
+        let header = data.prefix(stlHeaderSize)
+        let text = String(decoding: header.prefix { $0 != 0 }, as: UTF8.self)
+
+        if text.lowercased().hasPrefix("solid"), let line = text.split(whereSeparator: \.isNewline).first,
+           header.contains(where: { $0 == 10 || $0 == 13 }) {
+            let name = line.dropFirst("solid".count).trimmingCharacters(in: .whitespaces)
+            return name.isEmpty ? nil : Entry(label: "Solid Name", value: name)
+        }
+
+        let printable = String(text.unicodeScalars.filter { $0.value >= 32 && $0.value < 127 }.map(Character.init))
+            .trimmingCharacters(in: .whitespaces)
+        return printable.isEmpty ? nil : Entry(label: "STL Header", value: printable)
+    }
+
+    // MARK: - 3MF
+
+    static let threeMFModelPath = "3D/3dmodel.model"
+
+    /// Core `<metadata name="...">` entries of a 3MF package (Title, Designer, Application, ...)
+    static func threeMFMetadata(in data: Data) -> [Entry] {
+        guard let archive = try? ZipArchive(data: data),
+              let modelData = try? archive.extractFile(path: threeMFModelPath) else { return [] }
+        var xml = String(decoding: modelData, as: UTF8.self)
+        // Model-level metadata precedes the (possibly huge) resources section
+        if let resources = xml.range(of: "<resources") {
+            xml = String(xml[..<resources.lowerBound])
+        }
+        return threeMFMetadata(inModelXML: xml)
+    }
+
+    static func threeMFMetadata(inModelXML xml: String) -> [Entry] {
+        let pattern = #"<metadata\s+[^>]*name\s*=\s*"([^"]+)"[^>]*>([^<]*)</metadata>"#
+        guard let regex = try? NSRegularExpression(pattern: pattern) else { return [] }
+        let range = NSRange(xml.startIndex..., in: xml)
+        return regex.matches(in: xml, range: range).compactMap { match in
+            guard let nameRange = Range(match.range(at: 1), in: xml),
+                  let valueRange = Range(match.range(at: 2), in: xml) else { return nil }
+            let value = unescapeXML(String(xml[valueRange])).trimmingCharacters(in: .whitespacesAndNewlines)
+            guard !value.isEmpty else { return nil }
+            return Entry(label: String(xml[nameRange]), value: value)
+        }
+    }
+
+    private static func unescapeXML(_ text: String) -> String {
+        text.replacingOccurrences(of: "&lt;", with: "<")
+            .replacingOccurrences(of: "&gt;", with: ">")
+            .replacingOccurrences(of: "&quot;", with: "\"")
+            .replacingOccurrences(of: "&apos;", with: "'")
+            .replacingOccurrences(of: "&amp;", with: "&")
+    }
+
+    // MARK: - Source
+
+    /// A `.scad` file with the same base name next to an exported STL
+    static func siblingSource(of url: URL) -> URL? {
+        guard ModelFileLoader.modelExtension(of: url) == "stl" else { return nil }
+        let candidate = FileCompression.uncompressedURL(url).deletingPathExtension().appendingPathExtension("scad")
+        return FileManager.default.fileExists(atPath: candidate.path) ? candidate : nil
+    }
+}
@@ -0,0 +1,67 @@
+import Foundation
+
+/// Copies of a model in a row or around a circle, combined into one model (`gostl pattern`)
+enum ModelPattern: Equatable {
+    /// Copies side by side along an axis, `spacing` apart between their bounding boxes
+    case linear(count: Int, axis: SectionProperties.Axis, spacing: Double)
+    /// Copies around the Z axis through the first copy's center offset by -`radius` along X,
+    /// spread evenly over `sweep` degrees; `rotateCopies` turns each copy with the pattern
+    case polar(count: Int, radius: Double, sweep: Double, rotateCopies: Bool)
+
+    var count: Int {
+        switch self {
+        case .linear(let count, _, _), .polar(let count, _, _, _): return count
+        }
+    }
+
+    /// Angle between neighboring copies of a polar pattern (degrees); a full circle does not repeat the first copy
+    static func angularStep(count: Int, sweep: Double) -> Double {
+        guard count > 1 else { return 0 }
+        return abs(sweep) >= 360 ? sweep / Double(count) : sweep / Double(count - 1)
+    }
+
+    /// Smallest polar radius at which neighboring copies stay `spacing` apart
+    ///
+    /// Uses the circle around each copy's XY footprint, so copies that rotate with the pattern may
+    /// still clear each other at somewhat smaller radii.
+    static func minimumRadius(for model: STLModel, count: Int, sweep: Double, spacing: Double = 0) -> Double {
+        let step = angularStep(count: count, sweep: sweep)
+        guard step != 0 else { return 0 }
+        let size = model.boundingBox().size
+        let footprintRadius = (size.x * size.x + size.y * size.y).squareRoot() / 2
+        return (footprintRadius + spacing / 2) / sin(min(abs(step), 180) * .pi / 360)
+    }
+
+    /// The copies combined into one model; the first copy stays where the model is
+    func apply(to model: STLModel) -> STLModel {
+        let bounds = model.boundingBox()
+        var triangles: [Triangle] = []
+        triangles.reserveCapacity(model.triangles.count * count)
+
+        switch self {
+        case .linear(let count, let axis, let spacing):
+            var direction = Vector3.zero
+            direction.value[axis.index] = bounds.size.value[axis.index] + spacing
+            for copy in 0..<count {
+                triangles += model.translated(by: direction * Double(copy)).triangles
+            }
+
+        case .polar(let count, let radius, let sweep, let rotateCopies):
+            let center = bounds.center
+            let pivot = Vector3(center.x - radius, center.y, center.z)
+            let step = Self.angularStep(count: count, sweep: sweep) * .pi / 180
+            for copy in 0..<count {
+                let angle = step * Double(copy)
+                var placed = model
+                if rotateCopies {
+                    Rotation.rotateModel(&placed, axis: .unitZ, angle: angle, center: pivot)
+                } else {
+                    let offset = Vector3(radius * (cos(angle) - 1), radius * sin(angle), 0)
+                    placed = placed.translated(by: offset)
+                }
+                triangles += placed.triangles
+            }
+        }
+        return STLModel(triangles: triangles, name: model.name, header: model.header)
+    }
+}
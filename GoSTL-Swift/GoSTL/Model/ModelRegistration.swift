@@ -0,0 +1,241 @@
+import Foundation
+import simd
+
+/// Rigid registration of a second model onto the main model (iterative closest point) and the
+/// triangle correspondence used to mirror a selection from one model onto the other
+///
+/// Meant for two revisions of the same part: the models have to overlap roughly once their
+/// bounding box centers are aligned, larger rotations than about 30° are not recovered.
+enum ModelRegistration {
+    struct Result {
+        /// Rotation about the origin, followed by `translation`, maps the other model onto the main model
+        let rotation: simd_double3x3
+        let translation: Vector3
+        /// Root mean square distance of the sampled points to the main model after registration
+        let rmsError: Double
+        let iterations: Int
+
+        func apply(_ point: Vector3) -> Vector3 {
+            Vector3(value: rotation * point.value + translation.value)
+        }
+
+        /// The model moved onto the main model
+        func apply(to model: STLModel) -> STLModel {
+            // Fresh model: the precomputed bounds of the original would go stale when rotating
+            var result = STLModel(triangles: model.triangles, name: model.name, header: model.header)
+            for index in result.triangles.indices {
+                result.triangles[index].v1 = apply(result.triangles[index].v1)
+                result.triangles[index].v2 = apply(result.triangles[index].v2)
+                result.triangles[index].v3 = apply(result.triangles[index].v3)
+                result.triangles[index].updateNormal()
+            }
+            return result
+        }
+
+        /// Angle of the rotation in degrees
+        var rotationAngle: Double {
+            let trace = rotation[0][0] + rotation[1][1] + rotation[2][2]
+            return acos(Swift.max(-1, Swift.min(1, (trace - 1) / 2))) * 180 / .pi
+        }
+    }
+
+    /// Nearest-triangle correspondence between the main model and the registered other model
+    struct Correspondence {
+        /// For each main triangle the closest triangle of the other model (nil if the surfaces differ there)
+        let otherForMain: [Int?]
+        /// For each triangle of the other model the closest main triangle (nil if the surfaces differ there)
+        let mainForOther: [Int?]
+
+        /// Triangles of the other model in the region of the selected main triangles
+        func otherTriangles(matching selection: Set<Int>) -> Set<Int> {
+            guard !selection.isEmpty else { return [] }
+            var result = Set(selection.compactMap { $0 < otherForMain.count ? otherForMain[$0] : nil })
+            for (index, main) in mainForOther.enumerated() {
+                if let main, selection.contains(main) {
+                    result.insert(index)
+                }
+            }
+            return result
+        }
+
+        /// Main triangles in the region of a triangle of the other model
+        func mainTriangles(matching otherIndex: Int) -> Set<Int> {
+            var result: Set<Int> = []
+            if otherIndex < mainForOther.count, let main = mainForOther[otherIndex] {
+                result.insert(main)
+            }
+            for (index, other) in otherForMain.enumerated() where other == otherIndex {
+                result.insert(index)
+            }
+            return result
+        }
+    }
+
+    /// Points sampled from the other model for each iteration
+    static let maxSamples = 2000
+    static let maxIterations = 40
+
+    /// Surfaces further apart than this are treated as changed rather than corresponding
+    static func defaultTolerance(for bounds: BoundingBox) -> Double {
+        Swift.max(0.5, bounds.diagonal * 0.02)
+    }
+
+    /// Rigid transform moving `other` onto `model`, nil if either model is empty
+    static func register(_ other: [Triangle], onto model: [Triangle], job: JobContext? = nil) throws -> Result? {
+        guard !other.isEmpty, !model.isEmpty else { return nil }
+
+        let accelerator = SpatialAccelerator(triangles: model)
+        let modelBounds = STLModel(triangles: model).boundingBox()
+        let otherBounds = STLModel(triangles: other).boundingBox()
+        let searchRadius = Swift.max(modelBounds.diagonal * 0.01, 1e-6)
+        let samples = samplePoints(of: other)
+
+        // Start with the bounding box centers on top of each other
+        var rotation = matrix_identity_double3x3
+        var translation = modelBounds.center - otherBounds.center
+        var previousError = Double.infinity
+        var rmsError = Double.infinity
+        var iterations = 0
+
+        while iterations < maxIterations {
+            try job?.checkCancellation()
+            job?.reportProgress(Double(iterations) / Double(maxIterations))
+
+            let moved = samples.map { Vector3(value: rotation * $0.value + translation.value) }
+            let targets = moved.map { point in
+                closestTriangle(to: point, in: model, accelerator: accelerator, startRadius: searchRadius)?.point ?? point
+            }
+            rmsError = (zip(moved, targets).reduce(0) { $0 + $1.0.distance(to: $1.1) * $1.0.distance(to: $1.1) } / Double(moved.count)).squareRoot()
+            if previousError - rmsError < Swift.max(modelBounds.diagonal * 1e-9, 1e-12) {
+                break
+            }
+            previousError = rmsError
+
+            guard let step = bestRigidTransform(from: moved, to: targets) else { break }
+            rotation = step.rotation * rotation
+            translation = Vector3(value: step.rotation * translation.value) + step.translation
+            iterations += 1
+        }
+
+        return Result(rotation: rotation, translation: translation, rmsError: rmsError, iterations: iterations)
+    }
+
+    /// Closest triangle of each model for every triangle of the other (by triangle centroid)
+    static func correspondence(
+        model: [Triangle],
+        other: [Triangle],
+        tolerance: Double,
+        job: JobContext? = nil
+    ) throws -> Correspondence {
+        guard !model.isEmpty, !other.isEmpty else {
+            return Correspondence(
+                otherForMain: Array(repeating: nil, count: model.count),
+                mainForOther: Array(repeating: nil, count: other.count)
+            )
+        }
+
+        let total = Double(model.count + other.count)
+        func nearest(from triangles: [Triangle], to target: [Triangle], progressOffset: Int) throws -> [Int?] {
+            let accelerator = SpatialAccelerator(triangles: target)
+            let startRadius = Swift.min(tolerance, Swift.max(tolerance * 0.1, 1e-6))
+            var result: [Int?] = []
+            result.reserveCapacity(triangles.count)
+            for (index, triangle) in triangles.enumerated() {
+                if index % 4096 == 0 {
+                    try job?.checkCancellation()
+                    job?.reportProgress(Double(progressOffset + index) / total)
+                }
+                let centroid = (triangle.v1 + triangle.v2 + triangle.v3) / 3
+                let hit = closestTriangle(to: centroid, in: target, accelerator: accelerator, startRadius: startRadius, maxDistance: tolerance)
+                result.append(hit.flatMap { $0.distance <= tolerance ? $0.index : nil })
+            }
+            return result
+        }
+
+        let otherForMain = try nearest(from: model, to: other, progressOffset: 0)
+        let mainForOther = try nearest(from: other, to: model, progressOffset: model.count)
+        return Correspondence(otherForMain: otherForMain, mainForOther: mainForOther)
+    }
+
+    // MARK: - Helpers
+
+    /// Vertices and centroids of up to `maxSamples` evenly spread triangles
+    private static func samplePoints(of triangles: [Triangle]) -> [Vector3] {
+        let step = Swift.max(1, triangles.count / maxSamples)
+        var points: [Vector3] = []
+        for index in stride(from: 0, to: triangles.count, by: step) {
+            let triangle = triangles[index]
+            points += [triangle.v1, triangle.v2, triangle.v3, (triangle.v1 + triangle.v2 + triangle.v3) / 3]
+        }
+        return points
+    }
+
+    /// Closest point on the surface, searching a growing radius around the point
+    static func closestTriangle(
+        to point: Vector3,
+        in triangles: [Triangle],
+        accelerator: SpatialAccelerator,
+        startRadius: Double,
+        maxDistance: Double = .infinity
+    ) -> (index: Int, point: Vector3, distance: Double)? {
+        var radius = startRadius
+        while true {
+            let candidates = accelerator.findTriangles(near: point, maxDistance: radius)
+            var best: (index: Int, point: Vector3, distance: Double)?
+            for index in candidates {
+                let closest = TriangleDistance.closestPoint(on: triangles[index], to: point)
+                let distance = closest.distance(to: point)
+                if best == nil || distance < best!.distance {
+                    best = (index: index, point: closest, distance: distance)
+                }
+            }
+
+            // Triangles outside the search radius can only be closer if the best one lies beyond it
+            if let best, best.distance <= radius || radius >= maxDistance {
+                return best
+            }
+            if best == nil && (radius >= maxDistance || radius > 1e12) {
+                return nil
+            }
+            radius = Swift.min(best?.distance ?? radius * 4, maxDistance)
+        }
+    }
+
+    /// Least-squares rotation and translation mapping `source` onto `target` (Kabsch)
+    static func bestRigidTransform(from source: [Vector3], to target: [Vector3]) -> (rotation: simd_double3x3, translation: Vector3)? {
+        guard source.count == target.count, source.count >= 3 else { return nil }
+
+        let count = Double(source.count)
+        let sourceCenter = source.reduce(SIMD3<Double>.zero) { $0 + $1.value } / count
+        let targetCenter = target.reduce(SIMD3<Double>.zero) { $0 + $1.value } / count
+
+        // Cross-covariance M = Σ q pᵀ; the rotation maximizing trace(Rᵀ M) is its polar factor
+        var covariance = simd_double3x3()
+        for (p, q) in zip(source, target) {
+            let a = p.value - sourceCenter, b = q.value - targetCenter
+            covariance += simd_double3x3(columns: (b * a.x, b * a.y, b * a.z))
+        }
+
+        // Singular vectors from the eigenvectors of MᵀM, ordered from the largest singular value
+        let (values, vectors) = ModelAlignment.symmetricEigen(covariance.transpose * covariance)
+        let order = [0, 1, 2].sorted { values[$0] > values[$1] }
+        let w1 = simd_normalize(vectors[order[0]])
+        var w2 = vectors[order[1]] - simd_dot(vectors[order[1]], w1) * w1
+        guard simd_length(w2) > 1e-12 else { return nil }
+        w2 = simd_normalize(w2)
+
+        let m1 = covariance * w1, m2 = covariance * w2
+        guard simd_length(m1) > 1e-12 else { return nil }
+        let u1 = simd_normalize(m1)
+        var u2 = m2 - simd_dot(m2, u1) * u1
+        guard simd_length(u2) > 1e-12 else { return nil }
+        u2 = simd_normalize(u2)
+
+        // Completing both bases with cross products keeps the result a proper rotation (no reflection)
+        let u3 = simd_cross(u1, u2), w3 = simd_cross(w1, w2)
+        let rotation = simd_double3x3(columns: (u1, u2, u3)) * simd_double3x3(columns: (w1, w2, w3)).transpose
+
+        let translation = Vector3(value: targetCenter - rotation * sourceCenter)
+        return (rotation: rotation, translation: translation)
+    }
+}
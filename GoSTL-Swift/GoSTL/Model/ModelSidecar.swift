@@ -0,0 +1,189 @@
+import Foundation
+
+/// User-defined key/value property of a model (part number, revision, customer, ...)
+struct ModelProperty: Codable, Equatable, Identifiable {
+    var id = UUID()
+    var key: String
+    var value: String
+
+    private enum CodingKeys: String, CodingKey {
+        case key, value
+    }
+
+    init(key: String, value: String) {
+        self.key = key
+        self.value = value
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        key = try container.decode(String.self, forKey: .key)
+        value = try container.decode(String.self, forKey: .value)
+    }
+}
+
+/// Display units of one model, overriding Settings > Display while it is open
+struct ModelUnits: Codable, Equatable {
+    var lengthScale: LengthScale
+    /// Decimal places, nil to follow the settings
+    var lengthDecimals: Int?
+    var angleDecimals: Int?
+
+    init(lengthScale: LengthScale, lengthDecimals: Int? = nil, angleDecimals: Int? = nil) {
+        self.lengthScale = lengthScale
+        self.lengthDecimals = lengthDecimals
+        self.angleDecimals = angleDecimals
+    }
+
+    /// The settings' precision with this model's unit and decimals
+    func precision(overriding base: DisplayPrecision, forModelExtent extent: Double?) -> DisplayPrecision {
+        let scale = lengthScale.resolved(forExtent: extent)
+        return DisplayPrecision(
+            lengthDecimals: lengthDecimals ?? base.lengthDecimals,
+            angleDecimals: angleDecimals ?? base.angleDecimals,
+            unit: scale.unit,
+            engineeringNotation: scale.engineeringNotation
+        )
+    }
+}
+
+/// Key figures of the model when the project was saved, to follow a design over its revisions
+struct AnalysisSnapshot: Codable, Equatable {
+    var date: Date
+    /// Numeric fields of `gostl analyze` by key (`volume`, `dimensions.x`, ...)
+    var values: [String: Double]
+
+    init(date: Date = Date(), values: [String: Double]) {
+        self.date = date
+        self.values = values
+    }
+
+    init(record: AnalysisRecord, date: Date = Date()) {
+        var values: [String: Double] = [:]
+        for field in record.fields {
+            switch field.value {
+            case .integer(let value): values[field.key] = Double(value)
+            case .number(let value): values[field.key] = value
+            case .text: break
+            }
+        }
+        self.init(date: date, values: values)
+    }
+}
+
+enum ModelSidecarError: LocalizedError {
+    case newerVersion(file: String, version: Int)
+
+    var errorDescription: String? {
+        switch self {
+        case .newerVersion(let file, let version):
+            return "\(file) was written by a newer GoSTL (project version \(version)); update GoSTL to open it"
+        }
+    }
+}
+
+/// Per-model project stored next to the model file as `<file>.gostl` (e.g. `bracket.stl.gostl`)
+///
+/// JSON, so it can be checked in alongside the model and edited by hand. Positions are in the
+/// file's original coordinates. Properties, sections, annotations, views and units are written
+/// as they change; measurements, datums and analysis snapshots with File > Save Project.
+///
+/// Version 1 held properties, sections and annotations; version 2 added the rest. Missing keys
+/// decode as empty, so older projects load unchanged.
+struct ModelSidecar: Codable {
+    static let currentVersion = 2
+    static let pathExtension = "gostl"
+    /// Snapshots kept per model; the oldest are dropped first
+    static let maxSnapshots = 50
+
+    var version: Int = ModelSidecar.currentVersion
+    var properties: [ModelProperty] = []
+    /// Named cross-sections
+    var sections: [SectionBookmark] = []
+    /// Text pinned to points of the model
+    var annotations: [Annotation] = []
+    /// Measurements, named like in measurement exports
+    var measurements: [MeasurementSet.Entry] = []
+    /// Reference planes, axes and points
+    var datums: [ReferenceEntity] = []
+    /// Distances and angles between datums
+    var datumMeasurements: [ReferenceMeasurement] = []
+    /// Named camera positions
+    var views: [ViewBookmark] = []
+    /// Display units of this model (nil: Settings > Display)
+    var units: ModelUnits?
+    /// Analysis results of each saved revision, oldest first
+    var snapshots: [AnalysisSnapshot] = []
+
+    var isEmpty: Bool {
+        properties.isEmpty && sections.isEmpty && annotations.isEmpty && measurements.isEmpty
+            && datums.isEmpty && views.isEmpty && units == nil && snapshots.isEmpty
+    }
+
+    init() {}
+
+    init(from decoder: Decoder) throws {
+        // Sidecars written before a field existed lack its key
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        version = try container.decodeIfPresent(Int.self, forKey: .version) ?? Self.currentVersion
+        properties = try container.decodeIfPresent([ModelProperty].self, forKey: .properties) ?? []
+        sections = try container.decodeIfPresent([SectionBookmark].self, forKey: .sections) ?? []
+        annotations = try container.decodeIfPresent([Annotation].self, forKey: .annotations) ?? []
+        measurements = try container.decodeIfPresent([MeasurementSet.Entry].self, forKey: .measurements) ?? []
+        datums = try container.decodeIfPresent([ReferenceEntity].self, forKey: .datums) ?? []
+        datumMeasurements = try container.decodeIfPresent([ReferenceMeasurement].self, forKey: .datumMeasurements) ?? []
+        views = try container.decodeIfPresent([ViewBookmark].self, forKey: .views) ?? []
+        units = try container.decodeIfPresent(ModelUnits.self, forKey: .units)
+        snapshots = try container.decodeIfPresent([AnalysisSnapshot].self, forKey: .snapshots) ?? []
+    }
+
+    /// Sidecar location for a model file
+    static func url(for modelURL: URL) -> URL {
+        modelURL.appendingPathExtension(pathExtension)
+    }
+
+    /// Sidecar of a model file, empty if there is none
+    static func load(for modelURL: URL) throws -> ModelSidecar {
+        let url = url(for: modelURL)
+        guard FileManager.default.fileExists(atPath: url.path) else {
+            return ModelSidecar()
+        }
+        let sidecar = try JSONDecoder().decode(ModelSidecar.self, from: Data(contentsOf: url))
+        // Writing would drop what a newer version stored
+        guard sidecar.version <= currentVersion else {
+            throw ModelSidecarError.newerVersion(file: url.lastPathComponent, version: sidecar.version)
+        }
+        return sidecar
+    }
+
+    /// Change the sidecar of a model file and write it back
+    static func update(for modelURL: URL, _ change: (inout ModelSidecar) -> Void) throws {
+        var sidecar = try load(for: modelURL)
+        change(&sidecar)
+        try sidecar.write(for: modelURL)
+    }
+
+    /// Add a snapshot, dropping the oldest beyond `maxSnapshots`
+    mutating func addSnapshot(_ snapshot: AnalysisSnapshot) {
+        snapshots.append(snapshot)
+        if snapshots.count > Self.maxSnapshots {
+            snapshots.removeFirst(snapshots.count - Self.maxSnapshots)
+        }
+    }
+
+    /// Write the sidecar next to a model file (removes the file once nothing is left to store)
+    func write(for modelURL: URL) throws {
+        let url = Self.url(for: modelURL)
+        if isEmpty {
+            if FileManager.default.fileExists(atPath: url.path) {
+                try FileManager.default.removeItem(at: url)
+            }
+            return
+        }
+        var current = self
+        current.version = Self.currentVersion
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+        try encoder.encode(current).write(to: url, options: .atomic)
+    }
+}
@@ -0,0 +1,56 @@
+import Foundation
+
+/// Surface area split by which way the faces look: up, down or sideways
+///
+/// Up-facing faces show layer stepping and are usually sanded or smoothed; down-facing faces
+/// rest on supports (or the build plate) and need the most cleanup. A face counts as vertical
+/// when its normal is within the tolerance of horizontal, so the three buckets add up to the
+/// total surface area. Orientation is taken from the winding, not the stored facet normal.
+struct OrientationAreas: Codable, Equatable {
+    /// Default angle from vertical within which a face counts as vertical (degrees)
+    static let defaultVerticalTolerance = 5.0
+
+    /// Area of faces looking upward (+Z), mm²
+    var up: Double
+    /// Area of faces looking downward (-Z), mm²
+    var down: Double
+    /// Area of walls within the tolerance of vertical, mm²
+    var vertical: Double
+
+    static let zero = OrientationAreas(up: 0, down: 0, vertical: 0)
+
+    var total: Double {
+        up + down + vertical
+    }
+
+    /// Area per orientation
+    /// - Parameter verticalTolerance: Faces tilted at most this far from vertical count as vertical (degrees)
+    static func compute(_ triangles: [Triangle], verticalTolerance: Double = defaultVerticalTolerance) -> OrientationAreas {
+        // |n.z| of a face tilted by the tolerance from vertical
+        let limit = sin(verticalTolerance * .pi / 180)
+        var areas = OrientationAreas.zero
+        for triangle in triangles {
+            let cross = (triangle.v2 - triangle.v1).cross(triangle.v3 - triangle.v1)
+            let length = cross.length
+            guard length > 0 else { continue }
+
+            let area = length / 2
+            let nz = cross.z / length
+            if abs(nz) <= limit {
+                areas.vertical += area
+            } else if nz > 0 {
+                areas.up += area
+            } else {
+                areas.down += area
+            }
+        }
+        return areas
+    }
+}
+
+extension STLModel {
+    /// Surface area split into up-facing, down-facing and vertical faces (see `OrientationAreas`)
+    func orientationAreas(verticalTolerance: Double = OrientationAreas.defaultVerticalTolerance) -> OrientationAreas {
+        OrientationAreas.compute(triangles, verticalTolerance: verticalTolerance)
+    }
+}
@@ -0,0 +1,254 @@
+import Foundation
+
+/// A printed circuit board found in a PCB 3D export (KiCad VRML/STEP converted to STL/3MF)
+///
+/// The board is the pair of large horizontal faces a typical board thickness apart. Everything that
+/// sticks out above the top face or below the bottom face is grouped into components: triangles that
+/// share vertices form one shell, and shells with overlapping footprints (body, pins, labels) are merged.
+struct PCBBoard: Equatable {
+    struct Component: Identifiable, Equatable {
+        enum Side: String {
+            case top
+            case bottom
+        }
+
+        let id: Int
+        let side: Side
+        let bounds: BoundingBox
+        /// Height above the board top (top side) or depth below the board bottom (bottom side)
+        let height: Double
+        let triangleCount: Int
+
+        /// Size in X and Y
+        var footprint: (width: Double, depth: Double) {
+            (bounds.size.x, bounds.size.y)
+        }
+
+        /// Center of the component's highest (top side) or lowest (bottom side) extent
+        var extremePoint: Vector3 {
+            let center = bounds.center
+            return Vector3(center.x, center.y, side == .top ? bounds.max.z : bounds.min.z)
+        }
+    }
+
+    /// Z of the board's bottom and top copper/solder mask surfaces
+    let bottomZ: Double
+    let topZ: Double
+    /// XY extent of the board (Z spans the board thickness)
+    let outline: BoundingBox
+    /// Components, tallest first within each side (top side before bottom side)
+    let components: [Component]
+
+    var thickness: Double {
+        topZ - bottomZ
+    }
+
+    var topComponents: [Component] {
+        components.filter { $0.side == .top }
+    }
+
+    var bottomComponents: [Component] {
+        components.filter { $0.side == .bottom }
+    }
+
+    var tallestTopComponent: Component? {
+        topComponents.first
+    }
+
+    var deepestBottomComponent: Component? {
+        bottomComponents.first
+    }
+
+    /// Space needed above the board top
+    var topClearance: Double {
+        tallestTopComponent?.height ?? 0
+    }
+
+    /// Space needed below the board bottom
+    var bottomClearance: Double {
+        deepestBottomComponent?.height ?? 0
+    }
+
+    /// Minimum inner height of an enclosure: board plus the tallest parts on both sides
+    var stackHeight: Double {
+        thickness + topClearance + bottomClearance
+    }
+
+    // MARK: - Detection
+
+    /// Board thickness range accepted as a PCB (mm)
+    static let thicknessRange: ClosedRange<Double> = 0.2...3.5
+
+    /// Share of the model's XY footprint that both board faces must cover
+    static let minimumCoverage = 0.2
+
+    /// Heights closer than this are treated as the same plane (mm)
+    static let planeTolerance = 0.01
+
+    /// Find the board and its components, or nil if the model does not look like a PCB
+    static func detect(in model: STLModel) -> PCBBoard? {
+        guard let planes = boardPlanes(in: model.triangles) else { return nil }
+        let bottomZ = planes.bottom, topZ = planes.top
+
+        var outline = BoundingBox()
+        var hasOutline = false
+        for triangle in model.triangles where isBoardFace(triangle, bottomZ: bottomZ, topZ: topZ) {
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                if hasOutline {
+                    outline.extend(vertex)
+                } else {
+                    outline = BoundingBox(point: vertex)
+                    hasOutline = true
+                }
+            }
+        }
+        outline.min = Vector3(outline.min.x, outline.min.y, bottomZ)
+        outline.max = Vector3(outline.max.x, outline.max.y, topZ)
+
+        return PCBBoard(
+            bottomZ: bottomZ,
+            topZ: topZ,
+            outline: outline,
+            components: components(in: model.triangles, bottomZ: bottomZ, topZ: topZ)
+        )
+    }
+
+    /// Heights of the two board faces: the pair of horizontal planes with the most area on both,
+    /// a board thickness apart
+    private static func boardPlanes(in triangles: [Triangle]) -> (bottom: Double, top: Double)? {
+        var areaByLevel: [Int: Double] = [:]
+        for triangle in triangles {
+            let normal = Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3)
+            guard abs(normal.z) > 0.99 else { continue }
+            let z = (triangle.v1.z + triangle.v2.z + triangle.v3.z) / 3
+            areaByLevel[level(z), default: 0] += triangle.area()
+        }
+
+        let model = BoundingBox(points: triangles.flatMap { [$0.v1, $0.v2, $0.v3] })
+        let footprint = model.size.x * model.size.y
+        guard footprint > 0 else { return nil }
+
+        // Only the largest planes can be board faces
+        let candidates = areaByLevel.sorted { $0.value > $1.value }.prefix(8)
+        var best: (bottom: Double, top: Double, area: Double)?
+        for a in candidates {
+            for b in candidates where b.key > a.key {
+                let bottom = Double(a.key) * planeTolerance
+                let top = Double(b.key) * planeTolerance
+                let area = min(a.value, b.value)
+                guard thicknessRange.contains(top - bottom),
+                      area >= footprint * minimumCoverage,
+                      area > best?.area ?? 0 else { continue }
+                best = (bottom, top, area)
+            }
+        }
+
+        return best.map { ($0.bottom, $0.top) }
+    }
+
+    private static func level(_ z: Double) -> Int {
+        Int((z / planeTolerance).rounded())
+    }
+
+    private static func isBoardFace(_ triangle: Triangle, bottomZ: Double, topZ: Double) -> Bool {
+        let normal = Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3)
+        guard abs(normal.z) > 0.99 else { return false }
+        let z = level((triangle.v1.z + triangle.v2.z + triangle.v3.z) / 3)
+        return z == level(bottomZ) || z == level(topZ)
+    }
+
+    // MARK: - Components
+
+    private static func components(in triangles: [Triangle], bottomZ: Double, topZ: Double) -> [Component] {
+        // Triangles reaching out of the board slab
+        let outside = triangles.filter {
+            max($0.v1.z, $0.v2.z, $0.v3.z) > topZ + planeTolerance || min($0.v1.z, $0.v2.z, $0.v3.z) < bottomZ - planeTolerance
+        }
+        guard !outside.isEmpty else { return [] }
+
+        // Shells: triangles connected through shared vertices
+        let mesh = IndexedMesh(triangles: outside)
+        var parent = Array(mesh.points.indices)
+        func find(_ index: Int) -> Int {
+            var root = index
+            while parent[root] != root {
+                parent[root] = parent[parent[root]]
+                root = parent[root]
+            }
+            return root
+        }
+        for (a, b, c) in mesh.faces {
+            let root = find(a)
+            let rootB = find(b)
+            parent[rootB] = root
+            let rootC = find(c)
+            parent[rootC] = root
+        }
+
+        var shells: [Int: (bounds: BoundingBox, count: Int)] = [:]
+        for (triangle, face) in zip(outside, mesh.faces) {
+            let root = find(face.0)
+            var shell = shells[root] ?? (BoundingBox(point: triangle.v1), 0)
+            shell.bounds.extend(triangle.v1)
+            shell.bounds.extend(triangle.v2)
+            shell.bounds.extend(triangle.v3)
+            shell.count += 1
+            shells[root] = shell
+        }
+
+        // Merge shells of the same side whose footprints overlap (e.g. a body and its pins)
+        var groups = shells.values.map { (side: side(of: $0.bounds, topZ: topZ), bounds: $0.bounds, count: $0.count) }
+        var merged = true
+        while merged {
+            merged = false
+            outer: for i in groups.indices {
+                for j in groups.indices where j > i && groups[i].side == groups[j].side
+                    && footprintsOverlap(groups[i].bounds, groups[j].bounds) {
+                    groups[i].bounds.extend(groups[j].bounds)
+                    groups[i].count += groups[j].count
+                    groups.remove(at: j)
+                    merged = true
+                    break outer
+                }
+            }
+        }
+
+        let sorted = groups
+            .map { group -> (side: Component.Side, bounds: BoundingBox, count: Int, height: Double) in
+                let height = group.side == .top ? group.bounds.max.z - topZ : bottomZ - group.bounds.min.z
+                return (group.side, group.bounds, group.count, height)
+            }
+            .sorted {
+                if $0.side != $1.side { return $0.side == .top }
+                if $0.height != $1.height { return $0.height > $1.height }
+                return ($0.bounds.center.x, $0.bounds.center.y) < ($1.bounds.center.x, $1.bounds.center.y)
+            }
+
+        return sorted.enumerated().map { index, group in
+            Component(id: index + 1, side: group.side, bounds: group.bounds, height: group.height, triangleCount: group.count)
+        }
+    }
+
+    private static func side(of bounds: BoundingBox, topZ: Double) -> Component.Side {
+        bounds.max.z > topZ + planeTolerance ? .top : .bottom
+    }
+
+    private static func footprintsOverlap(_ a: BoundingBox, _ b: BoundingBox) -> Bool {
+        a.min.x < b.max.x && b.min.x < a.max.x && a.min.y < b.max.y && b.min.y < a.max.y
+    }
+
+    // MARK: - Measurements
+
+    /// Vertical distance measurement from the board surface to the component's top (or bottom)
+    func heightMeasurement(for component: Component) -> Measurement {
+        let extreme = component.extremePoint
+        let surface = Vector3(extreme.x, extreme.y, component.side == .top ? topZ : bottomZ)
+        let normal = component.side == .top ? Vector3(0, 0, 1) : Vector3(0, 0, -1)
+        let points = [
+            MeasurementPoint(position: surface, normal: normal, isAirPoint: true),
+            MeasurementPoint(position: extreme, normal: normal, isAirPoint: true)
+        ]
+        let (value, circle) = MeasurementSystem.calculateValue(type: .distance, points: points)
+        return Measurement(type: .distance, points: points, value: value, circle: circle)
+    }
+}
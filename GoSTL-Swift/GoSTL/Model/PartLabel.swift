@@ -0,0 +1,144 @@
+import CoreGraphics
+import Foundation
+
+/// Printable label for a printed part: name, key dimensions, volume and a QR code
+///
+/// Laid out on a `DrawingSheet`, so labels export as PNG, PDF or SVG like technical drawings.
+/// The QR code fills the label's height on the right and links to the report or source file;
+/// the text is sized to the remaining space.
+struct PartLabel {
+    /// Brother DK-11209 / Dymo small address label
+    static let defaultSize = CGSize(width: 62, height: 29)
+    /// Smallest label that still fits the text and a readable QR code
+    static let minimumSize = CGSize(width: 30, height: 12)
+
+    static let margin = 2.0
+    /// Smallest font size in millimeters (about 6 pt), below which text is shortened instead
+    static let minimumFontSize = 2.0
+    /// Average Helvetica glyph width relative to the font size, for fitting text without a font
+    private static let glyphWidth = 0.56
+
+    var name: String
+    var dimensions: Vector3
+    var volume: Double
+    /// Encoded in the QR code; nil leaves the code out and gives the text the full width
+    var link: String?
+    var date: Date?
+    var size = PartLabel.defaultSize
+
+    init(name: String, analysis: ModelAnalysis, link: String?, date: Date? = Date(), size: CGSize = PartLabel.defaultSize) {
+        self.name = name
+        self.dimensions = analysis.dimensions
+        self.volume = analysis.volume
+        self.link = link
+        self.date = date
+        self.size = size
+    }
+
+    /// Size in millimeters from "62x29" (or "62×29")
+    static func parseSize(_ text: String) -> CGSize? {
+        let parts = text.lowercased().split { $0 == "x" || $0 == "×" }
+        guard parts.count == 2,
+              let width = Double(parts[0].trimmingCharacters(in: .whitespaces)),
+              let height = Double(parts[1].trimmingCharacters(in: .whitespaces)),
+              width >= minimumSize.width, height >= minimumSize.height else {
+            return nil
+        }
+        return CGSize(width: width, height: height)
+    }
+
+    /// Text lines, the name first
+    var lines: [String] {
+        var lines = [
+            name,
+            String(format: "%.1f × %.1f × %.1f mm", dimensions.x, dimensions.y, dimensions.z),
+            volume > 1000 ? String(format: "Volume %.2f cm³", volume / 1000) : String(format: "Volume %.2f mm³", volume)
+        ]
+        if let date {
+            let formatter = DateFormatter()
+            formatter.locale = Locale(identifier: "en_US_POSIX")
+            formatter.dateFormat = "yyyy-MM-dd"
+            lines.append(formatter.string(from: date))
+        }
+        return lines
+    }
+
+    /// Square of the QR code at the right edge, nil without a link
+    var codeRect: CGRect? {
+        guard link != nil else { return nil }
+        let side = size.height - 2 * Self.margin
+        return CGRect(x: size.width - Self.margin - side, y: Self.margin, width: side, height: side)
+    }
+
+    func sheet() -> DrawingSheet {
+        var sheet = DrawingSheet(size: size)
+        let margin = Self.margin
+
+        var textRight = size.width - margin
+        if let link, let rect = codeRect, let modules = QRCode.modules(for: link) {
+            // One module of the square is left light on each side to widen the quiet zone
+            let module = rect.width / Double(modules.count + 2)
+            for (row, dark) in modules.enumerated() {
+                // One rectangle per run of dark modules, so bitmaps show no seams between them
+                var column = 0
+                while column < dark.count {
+                    guard dark[column] else {
+                        column += 1
+                        continue
+                    }
+                    let start = column
+                    while column < dark.count, dark[column] {
+                        column += 1
+                    }
+                    let run = CGRect(
+                        x: rect.minX + Double(start + 1) * module,
+                        y: rect.minY + Double(row + 1) * module,
+                        width: Double(column - start) * module,
+                        height: module
+                    )
+                    sheet.polygon([
+                        CGPoint(x: run.minX, y: run.minY), CGPoint(x: run.maxX, y: run.minY),
+                        CGPoint(x: run.maxX, y: run.maxY), CGPoint(x: run.minX, y: run.maxY)
+                    ])
+                }
+            }
+            textRight = rect.minX - margin
+        }
+
+        // The name twice the height of the detail lines, all lines stacked in the usable height
+        let lines = self.lines
+        let available = size.height - 2 * margin
+        let detailSize = min(available / (Double(lines.count - 1) * 1.3 + 2 * 1.2), 3.5)
+        let width = textRight - margin
+        var y = margin
+        for (index, line) in lines.enumerated() {
+            let preferred = index == 0 ? detailSize * 2 : detailSize
+            let fontSize = Self.fittingSize(for: line, width: width, preferred: preferred, minimum: min(Self.minimumFontSize, detailSize))
+            y += fontSize * (index == 0 ? 1.0 : 1.3)
+            sheet.text(Self.truncated(line, width: width, size: fontSize), at: CGPoint(x: margin, y: y), size: fontSize)
+            if index == 0 {
+                y += fontSize * 0.2
+            }
+        }
+        return sheet
+    }
+
+    /// Estimated width of a text line in millimeters
+    static func estimatedWidth(of text: String, size: Double) -> Double {
+        Double(text.count) * size * glyphWidth
+    }
+
+    /// The preferred font size, reduced down to `minimum` until the text fits the width
+    static func fittingSize(for text: String, width: Double, preferred: Double, minimum: Double) -> Double {
+        let fitting = width / max(estimatedWidth(of: text, size: 1), 1e-9)
+        return max(minimum, min(preferred, fitting))
+    }
+
+    /// The text shortened with an ellipsis in the middle until it fits the width (keeps file extensions visible)
+    static func truncated(_ text: String, width: Double, size: Double) -> String {
+        guard estimatedWidth(of: text, size: size) > width else { return text }
+        let count = max(Int(width / (size * glyphWidth)) - 1, 2)
+        let head = (count + 1) / 2, tail = count / 2
+        return String(text.prefix(head)) + "…" + String(text.suffix(tail))
+    }
+}
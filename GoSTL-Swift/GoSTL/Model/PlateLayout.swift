@@ -0,0 +1,91 @@
+import Foundation
+
+/// How `PlateLayout` places parts
+enum PlateArrangement: String, CaseIterable {
+    /// Equal cells sized for the largest part, in rows of the same count
+    case grid
+    /// Rows ("shelves") filled left to right, deepest parts first
+    case shelf
+    /// Outlines packed onto a bed with rotations (`PlateNesting`); `PlateLayout` treats it as `shelf`
+    case nest
+}
+
+/// Non-overlapping placement of parts on a print plate by their footprints (`gostl merge`)
+///
+/// Parts keep their orientation; only their XY bounding boxes are packed. The plate starts at
+/// the origin, rows run along +X and stack along +Y.
+struct PlateLayout: Equatable {
+    /// Minimum corner of each part's footprint on the plate, in input order
+    var origins: [Vector3]
+    /// Width and depth of the plate used
+    var width: Double
+    var depth: Double
+
+    /// - Parameters:
+    ///   - footprints: Width (X) and depth (Y) of each part
+    ///   - spacing: Gap between parts (mm)
+    ///   - maxWidth: Longest row (mm); nil for a roughly square plate. Parts wider than this get a row of their own.
+    init(footprints: [(width: Double, depth: Double)], arrangement: PlateArrangement, spacing: Double, maxWidth: Double? = nil) {
+        switch arrangement {
+        case .grid: origins = Self.grid(footprints, spacing: spacing, maxWidth: maxWidth)
+        case .shelf, .nest: origins = Self.shelves(footprints, spacing: spacing, maxWidth: maxWidth)
+        }
+        width = zip(origins, footprints).map { $0.0.x + $0.1.width }.max() ?? 0
+        depth = zip(origins, footprints).map { $0.0.y + $0.1.depth }.max() ?? 0
+    }
+
+    private static func grid(_ footprints: [(width: Double, depth: Double)], spacing: Double, maxWidth: Double?) -> [Vector3] {
+        guard !footprints.isEmpty else { return [] }
+        let cellWidth = footprints.map(\.width).max()! + spacing
+        let cellDepth = footprints.map(\.depth).max()! + spacing
+        let columns = maxWidth.map { Swift.max(1, Int(($0 + spacing) / cellWidth)) }
+            ?? Int(Double(footprints.count).squareRoot().rounded(.up))
+
+        return footprints.enumerated().map { index, footprint in
+            // Centered in its cell
+            let column = Double(index % columns), row = Double(index / columns)
+            return Vector3(
+                column * cellWidth + (cellWidth - spacing - footprint.width) / 2,
+                row * cellDepth + (cellDepth - spacing - footprint.depth) / 2,
+                0
+            )
+        }
+    }
+
+    private static func shelves(_ footprints: [(width: Double, depth: Double)], spacing: Double, maxWidth: Double?) -> [Vector3] {
+        guard !footprints.isEmpty else { return [] }
+        // Without a limit, aim for a square plate
+        let area = footprints.map { ($0.width + spacing) * ($0.depth + spacing) }.reduce(0, +)
+        let rowWidth = maxWidth ?? Swift.max(area.squareRoot(), footprints.map(\.width).max()!)
+
+        // Deepest first, so each shelf is as deep as its first part; stable for equal depths
+        let order = footprints.indices.sorted { footprints[$0].depth > footprints[$1].depth || (footprints[$0].depth == footprints[$1].depth && $0 < $1) }
+        var origins = [Vector3](repeating: .zero, count: footprints.count)
+        var x = 0.0, y = 0.0, shelfDepth = 0.0
+        for index in order {
+            let footprint = footprints[index]
+            if x > 0, x + footprint.width > rowWidth {
+                y += shelfDepth + spacing
+                x = 0
+                shelfDepth = 0
+            }
+            origins[index] = Vector3(x, y, 0)
+            x += footprint.width + spacing
+            shelfDepth = Swift.max(shelfDepth, footprint.depth)
+        }
+        return origins
+    }
+}
+
+extension STLModel {
+    /// Parts placed by a layout and combined into one model, each standing on z = 0
+    static func merged(_ parts: [STLModel], layout: PlateLayout, name: String? = nil) -> STLModel {
+        var triangles: [Triangle] = []
+        triangles.reserveCapacity(parts.map(\.triangles.count).reduce(0, +))
+        for (part, origin) in zip(parts, layout.origins) {
+            let bounds = part.boundingBox()
+            triangles += part.translated(by: origin - bounds.min).triangles
+        }
+        return STLModel(triangles: triangles, name: name)
+    }
+}
@@ -0,0 +1,255 @@
+import Foundation
+import simd
+
+/// Printable area of a bed, with the front left corner at the origin
+enum BedProfile: Equatable {
+    case rectangle(width: Double, depth: Double)
+    /// Round bed (delta printers)
+    case circle(diameter: Double)
+
+    /// "256x256", "d200" for a round bed, or a build plate preset ("X1C", "Prusa MK4")
+    init?(_ text: String) {
+        let trimmed = text.trimmingCharacters(in: .whitespaces).lowercased()
+        if let plate = BuildPlate.allCases.first(where: { $0 != .off && ($0.rawValue.lowercased() == trimmed || $0.displayName.lowercased() == trimmed) }) {
+            self = .rectangle(width: Double(plate.dimensions.x), depth: Double(plate.dimensions.y))
+            return
+        }
+        if trimmed.hasPrefix("d"), let diameter = Double(trimmed.dropFirst()), diameter > 0 {
+            self = .circle(diameter: diameter)
+            return
+        }
+        let values = trimmed.split { $0 == "x" || $0 == "×" }.map { Double($0.trimmingCharacters(in: .whitespaces)) }
+        guard values.count == 2, let width = values[0], let depth = values[1], width > 0, depth > 0 else { return nil }
+        self = .rectangle(width: width, depth: depth)
+    }
+
+    var width: Double {
+        switch self {
+        case .rectangle(let width, _): return width
+        case .circle(let diameter): return diameter
+        }
+    }
+
+    var depth: Double {
+        switch self {
+        case .rectangle(_, let depth): return depth
+        case .circle(let diameter): return diameter
+        }
+    }
+
+    var area: Double {
+        switch self {
+        case .rectangle(let width, let depth): return width * depth
+        case .circle(let diameter): return Double.pi * diameter * diameter / 4
+        }
+    }
+
+    /// Whether a point (mm) is on the bed
+    func contains(x: Double, y: Double) -> Bool {
+        switch self {
+        case .rectangle(let width, let depth):
+            return x >= 0 && y >= 0 && x <= width && y <= depth
+        case .circle(let diameter):
+            let radius = diameter / 2
+            return (x - radius) * (x - radius) + (y - radius) * (y - radius) <= radius * radius
+        }
+    }
+}
+
+/// Cells of a square grid covered by a part's outline seen from above
+struct FootprintMask {
+    var columns: Int
+    var rows: Int
+    /// Cells the footprint touches, most central first (a blocked center rejects a position quickly)
+    var cells: [SIMD2<Int>]
+    /// Cells whose center lies inside the footprint, for its area
+    var interiorCount: Int
+    /// Minimum corner of the grid in the part's coordinates
+    var origin: SIMD2<Double>
+
+    /// Rasterize triangles projected onto XY; every cell a triangle overlaps is covered
+    init(triangles: [Triangle], resolution: Double) {
+        let points = triangles.flatMap { [$0.v1, $0.v2, $0.v3] }.map { SIMD2($0.x, $0.y) }
+        let low = points.reduce(SIMD2(repeating: Double.infinity)) { simd_min($0, $1) }
+        let high = points.reduce(SIMD2(repeating: -Double.infinity)) { simd_max($0, $1) }
+        let origin = points.isEmpty ? SIMD2<Double>.zero : low
+        // Rotated coordinates are off by rounding; don't let that add a row of cells
+        let columns = points.isEmpty ? 0 : Swift.max(1, Int(((high.x - low.x) / resolution - 1e-9).rounded(.up)))
+        let rows = points.isEmpty ? 0 : Swift.max(1, Int(((high.y - low.y) / resolution - 1e-9).rounded(.up)))
+
+        var covered = [Bool](repeating: false, count: columns * rows)
+        var interior = [Bool](repeating: false, count: columns * rows)
+        func cellRange(_ low: Double, _ high: Double, _ start: Double, _ count: Int) -> ClosedRange<Int> {
+            let first = Int(((low - start) / resolution).rounded(.down))
+            let last = Int(((high - start) / resolution).rounded(.down))
+            return Swift.min(Swift.max(first, 0), count - 1)...Swift.min(Swift.max(last, 0), count - 1)
+        }
+
+        for triangle in triangles {
+            let a = SIMD2(triangle.v1.x, triangle.v1.y), b = SIMD2(triangle.v2.x, triangle.v2.y), c = SIMD2(triangle.v3.x, triangle.v3.y)
+            let lower = simd_min(simd_min(a, b), c), upper = simd_max(simd_max(a, b), c)
+            // Separating axes besides X and Y: the edge normals (walls project to segments)
+            let axes = [b - a, c - b, a - c].filter { simd_length_squared($0) > 0 }.map { SIMD2(-$0.y, $0.x) }
+            let area = (b - a).x * (c - a).y - (b - a).y * (c - a).x
+
+            for row in cellRange(lower.y, upper.y, origin.y, rows) {
+                for column in cellRange(lower.x, upper.x, origin.x, columns) {
+                    let index = row * columns + column
+                    let cellLow = origin + SIMD2(Double(column), Double(row)) * resolution
+                    let corners = [cellLow, cellLow + SIMD2(resolution, 0), cellLow + SIMD2(0, resolution), cellLow + resolution]
+                    if !covered[index], axes.allSatisfy({ axis in
+                        let triangle = [a, b, c].map { simd_dot($0, axis) }, cell = corners.map { simd_dot($0, axis) }
+                        return triangle.max()! >= cell.min()! && cell.max()! >= triangle.min()!
+                    }) {
+                        covered[index] = true
+                    }
+                    if !interior[index], area != 0 {
+                        let center = cellLow + resolution / 2
+                        let signs = [(a, b), (b, c), (c, a)].map { edge -> Double in
+                            let (p, q) = edge
+                            return ((q - p).x * (center - p).y - (q - p).y * (center - p).x) * area
+                        }
+                        interior[index] = signs.allSatisfy { $0 >= 0 }
+                    }
+                }
+            }
+        }
+
+        self.origin = origin
+        self.columns = columns
+        self.rows = rows
+        let middle = SIMD2(Double(columns - 1), Double(rows - 1)) / 2
+        cells = covered.indices.filter { covered[$0] }
+            .map { SIMD2($0 % columns, $0 / columns) }
+            .sorted { simd_length_squared(SIMD2<Double>($0) - middle) < simd_length_squared(SIMD2<Double>($1) - middle) }
+        interiorCount = interior.filter { $0 }.count
+    }
+}
+
+/// Packs parts onto a bed by their footprints, rotated about Z (`gostl merge --arrange nest`)
+///
+/// Footprints are rasterized at `resolution` and placed largest first, each at the position and
+/// rotation that keeps its far edge lowest, then leftmost (bottom-left fill), fully on the bed and
+/// at least `spacing` away from the parts already placed. Parts keep their up direction.
+struct PlateNesting {
+    struct Placement: Equatable {
+        /// Index of the part in the input
+        var part: Int
+        /// Rotation about Z (degrees, counter-clockwise)
+        var rotation: Double
+        /// Offset applied after the rotation; it also puts the part's lowest point on z = 0
+        var translation: Vector3
+        /// Front left corner of the grid cells the part occupies on the bed
+        var corner: Vector3
+    }
+
+    static let defaultResolution = 1.0
+    static let defaultRotationStep = 90.0
+
+    /// Placed parts in input order
+    var placements: [Placement]
+    /// Indices of parts that did not fit
+    var unplaced: [Int]
+    /// Share of the bed covered by the placed parts' footprints (0...1)
+    var efficiency: Double
+    /// Share of the placed parts' bounding rectangle covered by their footprints (0...1)
+    var density: Double
+
+    /// - Parameter rotationStep: Angle between tried rotations (degrees); 0 keeps the parts' orientation
+    init(parts: [STLModel], bed: BedProfile, spacing: Double, rotationStep: Double = defaultRotationStep, resolution: Double = defaultResolution) {
+        let angles = rotationStep > 0 ? stride(from: 0.0, to: 360.0, by: rotationStep).map { $0 } : [0]
+        let bedColumns = Int((bed.width / resolution).rounded(.down))
+        let bedRows = Int((bed.depth / resolution).rounded(.down))
+
+        // Blocked: off the bed (any corner outside) or too close to a placed part
+        var blocked = [Bool](repeating: true, count: bedColumns * bedRows)
+        for row in 0..<bedRows {
+            for column in 0..<bedColumns {
+                let x = Double(column) * resolution, y = Double(row) * resolution
+                blocked[row * bedColumns + column] = ![(x, y), (x + resolution, y), (x, y + resolution), (x + resolution, y + resolution)]
+                    .allSatisfy { bed.contains(x: $0.0, y: $0.1) }
+            }
+        }
+        // Cell centers closer than the spacing plus a cell diagonal may hold points closer than the spacing
+        let reach = spacing > 0 ? spacing / resolution + 2.0.squareRoot() : 0
+        let extent = Int(reach.rounded(.up))
+        let neighborhood = (-extent...extent).flatMap { dy in (-extent...extent).map { SIMD2($0, dy) } }
+            .filter { $0 == .zero || Double($0.x * $0.x + $0.y * $0.y) < reach * reach }
+
+        let masks = parts.map { part in
+            angles.map { FootprintMask(triangles: Self.rotated(part.triangles, degrees: $0), resolution: resolution) }
+        }
+        let order = parts.indices.sorted { masks[$0][0].interiorCount > masks[$1][0].interiorCount || (masks[$0][0].interiorCount == masks[$1][0].interiorCount && $0 < $1) }
+
+        var placements: [Placement] = []
+        var unplaced: [Int] = []
+        var usedCells = 0
+        var usedColumns = 0, usedRows = 0
+        for part in order {
+            // Lowest far edge, then leftmost, over all rotations
+            var best: (rotation: Int, column: Int, row: Int, top: Int, right: Int)?
+            for (rotation, mask) in masks[part].enumerated() where mask.columns <= bedColumns && mask.rows <= bedRows {
+                search: for row in 0...(bedRows - mask.rows) {
+                    if let best, row + mask.rows > best.top { break }
+                    for column in 0...(bedColumns - mask.columns) {
+                        let fits = mask.cells.allSatisfy { !blocked[(row + $0.y) * bedColumns + column + $0.x] }
+                        guard fits else { continue }
+                        let top = row + mask.rows, right = column + mask.columns
+                        if best == nil || top < best!.top || (top == best!.top && right < best!.right) {
+                            best = (rotation, column, row, top, right)
+                        }
+                        break search
+                    }
+                }
+            }
+            guard let best else {
+                unplaced.append(part)
+                continue
+            }
+
+            let mask = masks[part][best.rotation]
+            for cell in mask.cells {
+                let center = SIMD2(best.column, best.row) &+ cell
+                for offset in neighborhood {
+                    let x = center.x + offset.x, y = center.y + offset.y
+                    if x >= 0, y >= 0, x < bedColumns, y < bedRows {
+                        blocked[y * bedColumns + x] = true
+                    }
+                }
+            }
+            let minZ = parts[part].boundingBox().min.z
+            placements.append(Placement(
+                part: part,
+                rotation: angles[best.rotation],
+                translation: Vector3(Double(best.column) * resolution - mask.origin.x, Double(best.row) * resolution - mask.origin.y, -minZ),
+                corner: Vector3(Double(best.column) * resolution, Double(best.row) * resolution, 0)
+            ))
+            usedCells += mask.interiorCount
+            usedColumns = Swift.max(usedColumns, best.right)
+            usedRows = Swift.max(usedRows, best.top)
+        }
+
+        self.placements = placements.sorted { $0.part < $1.part }
+        self.unplaced = unplaced.sorted()
+        let usedArea = Double(usedCells) * resolution * resolution
+        efficiency = usedArea / bed.area
+        density = usedColumns * usedRows > 0 ? Double(usedCells) / Double(usedColumns * usedRows) : 0
+    }
+
+    /// The placed parts combined into one model
+    func merged(_ parts: [STLModel], name: String? = nil) -> STLModel {
+        var triangles: [Triangle] = []
+        for placement in placements {
+            let part = STLModel(triangles: Self.rotated(parts[placement.part].triangles, degrees: placement.rotation))
+            triangles += part.translated(by: placement.translation).triangles
+        }
+        return STLModel(triangles: triangles, name: name)
+    }
+
+    /// Triangles rotated about the Z axis
+    static func rotated(_ triangles: [Triangle], degrees: Double) -> [Triangle] {
+        var model = STLModel(triangles: triangles)
+        Rotation.rotateModel(&model, axis: .unitZ, angle: degrees * .pi / 180, center: .zero)
+        return model.triangles
+    }
+}
@@ -0,0 +1,467 @@
+import Foundation
+import simd
+
+/// Planes, cylinders, cones and spheres recognized in a mesh, e.g. to read off the diameter of every
+/// boss and hole of a part at once (`gostl primitives`)
+///
+/// Triangles are first grouped into flat faces (connected and coplanar). A flat face is a facet of
+/// a curved surface when a neighbor meets it at less than the feature angle and is at least half as
+/// wide; such facets are joined across those edges into curved regions. A region is fitted as a
+/// cylinder, then a cone (axis from the spread of its normals, position where the normals meet it),
+/// then a sphere, and keeps the first shape all its vertices are within the tolerance of. Regions
+/// that fit none of them are freeform.
+enum PrimitiveDetection {
+    enum Kind: String, CaseIterable {
+        case plane, cylinder, cone, sphere, freeform
+
+        var displayName: String {
+            rawValue.capitalized
+        }
+
+        var pluralName: String {
+            switch self {
+            case .plane: return "Planes"
+            case .cylinder: return "Cylinders"
+            case .cone: return "Cones"
+            case .sphere: return "Spheres"
+            case .freeform: return "Freeform"
+            }
+        }
+    }
+
+    enum Shape: Equatable {
+        case plane(normal: Vector3, offset: Double)
+        /// `center` lies on the axis in the middle of the covered length
+        case cylinder(axis: Vector3, center: Vector3, radius: Double, length: Double)
+        /// `axis` points from the apex towards the wider end; `halfAngle` is between surface and axis (degrees)
+        case cone(apex: Vector3, axis: Vector3, halfAngle: Double, radii: ClosedRange<Double>, length: Double)
+        case sphere(center: Vector3, radius: Double)
+        case freeform
+
+        var kind: Kind {
+            switch self {
+            case .plane: return .plane
+            case .cylinder: return .cylinder
+            case .cone: return .cone
+            case .sphere: return .sphere
+            case .freeform: return .freeform
+            }
+        }
+    }
+
+    struct Region {
+        var shape: Shape
+        var triangles: [Int]
+        var area: Double
+        /// Curved outwards (boss, shaft, ball) rather than inwards (hole, socket); true for planes and freeform
+        var isConvex: Bool
+        /// Angle the surface covers around its axis (degrees): 360 for a whole hole or boss, 90 for a
+        /// quarter-round fillet, 0 for planes and freeform regions
+        var sweep: Double
+        /// Largest distance of a vertex from the fitted surface (mm)
+        var deviation: Double
+    }
+
+    struct Result {
+        /// Largest first
+        let regions: [Region]
+        /// Region of each triangle (nil for degenerate triangles)
+        let triangleRegions: [Int?]
+        let totalArea: Double
+
+        func area(of kind: Kind) -> Double {
+            regions.filter { $0.shape.kind == kind }.reduce(0) { $0 + $1.area }
+        }
+
+        func count(of kind: Kind) -> Int {
+            regions.filter { $0.shape.kind == kind }.count
+        }
+
+        /// Share of the surface covered by planes, cylinders, cones and spheres (0...1)
+        var coverage: Double {
+            totalArea > 0 ? (totalArea - area(of: .freeform)) / totalArea : 0
+        }
+    }
+
+    static let defaultTolerance = 0.05
+    static let defaultFeatureAngle = 40.0
+    /// Normals of one flat face differ by at most this many degrees
+    static let flatAngle = 0.5
+
+    static let planeColor = TriangleColor(0.62, 0.67, 0.75)
+    static let cylinderColor = TriangleColor(0.25, 0.72, 0.45)
+    static let coneColor = TriangleColor(0.96, 0.62, 0.2)
+    static let sphereColor = TriangleColor(0.62, 0.42, 0.92)
+    static let freeformColor = TriangleColor(0.92, 0.3, 0.35)
+
+    // MARK: - Detection
+
+    /// - Parameters:
+    ///   - tolerance: Largest distance of a vertex from a fitted surface (mm)
+    ///   - featureAngle: Neighboring facets meeting at a smaller angle (degrees) may belong to one curved surface
+    static func detect(
+        _ triangles: [Triangle],
+        tolerance: Double = defaultTolerance,
+        featureAngle: Double = defaultFeatureAngle,
+        job: JobContext? = nil
+    ) throws -> Result {
+        let normals = triangles.map { Triangle.calculateNormal(v1: $0.v1, v2: $0.v2, v3: $0.v3) }
+        let areas = triangles.map { $0.area() }
+        let valid = normals.map { $0.length > 0.5 }
+
+        // Triangles sharing an edge (edges of more than two triangles don't connect)
+        var edgeTriangles: [Edge: [Int]] = [:]
+        edgeTriangles.reserveCapacity(triangles.count * 3 / 2)
+        for (index, triangle) in triangles.enumerated() where valid[index] {
+            for edge in [Edge(triangle.v1, triangle.v2), Edge(triangle.v2, triangle.v3), Edge(triangle.v3, triangle.v1)] {
+                edgeTriangles[edge, default: []].append(index)
+            }
+        }
+        try job?.checkCancellation()
+        var neighbors = [[Int]](repeating: [], count: triangles.count)
+        for pair in edgeTriangles.values where pair.count == 2 {
+            neighbors[pair[0]].append(pair[1])
+            neighbors[pair[1]].append(pair[0])
+        }
+
+        // Flat faces
+        let flatCos = cos(flatAngle * .pi / 180)
+        var faceOf = [Int](repeating: -1, count: triangles.count)
+        var faces: [[Int]] = []
+        for seed in triangles.indices where valid[seed] && faceOf[seed] < 0 {
+            if faces.count % 4096 == 0 {
+                try job?.checkCancellation()
+                job?.reportProgress(0.5 * Double(seed) / Double(triangles.count))
+            }
+            var face = [seed]
+            faceOf[seed] = faces.count
+            var next = 0
+            while next < face.count {
+                let current = face[next]
+                next += 1
+                for neighbor in neighbors[current] where faceOf[neighbor] < 0 && normals[neighbor].dot(normals[seed]) >= flatCos {
+                    faceOf[neighbor] = faces.count
+                    face.append(neighbor)
+                }
+            }
+            faces.append(face)
+        }
+
+        let faceNormals = faces.map { face in
+            face.reduce(Vector3.zero) { $0 + normals[$1] * areas[$1] }.normalized()
+        }
+        let widths = faces.indices.map { width(of: faces[$0], normal: faceNormals[$0], in: triangles) }
+
+        // Faces meeting at less than the feature angle
+        let featureCos = cos(featureAngle * .pi / 180)
+        var smoothNeighbors = [Set<Int>](repeating: [], count: faces.count)
+        for (index, triangle) in neighbors.enumerated() where valid[index] {
+            for neighbor in triangle where faceOf[neighbor] != faceOf[index] && normals[index].dot(normals[neighbor]) > featureCos {
+                smoothNeighbors[faceOf[index]].insert(faceOf[neighbor])
+            }
+        }
+        // Facets of a curved surface have neighbors of about their width; a flat face next to a
+        // fillet is much wider than the fillet's facets
+        let isFacet = faces.indices.map { face in
+            smoothNeighbors[face].contains { widths[$0] >= 0.5 * widths[face] }
+        }
+
+        var regions: [Region] = []
+        var regionFaces = [Int](repeating: -1, count: faces.count)
+        for seed in faces.indices where isFacet[seed] && regionFaces[seed] < 0 {
+            try job?.checkCancellation()
+            var group = [seed]
+            regionFaces[seed] = seed
+            var next = 0
+            while next < group.count {
+                let current = group[next]
+                next += 1
+                for neighbor in smoothNeighbors[current] where isFacet[neighbor] && regionFaces[neighbor] < 0 {
+                    regionFaces[neighbor] = seed
+                    group.append(neighbor)
+                }
+            }
+
+            // Two facets are no curved surface to fit; keep them flat
+            guard group.count >= 3 else {
+                group.forEach { regionFaces[$0] = -1 }
+                continue
+            }
+            let indices = group.flatMap { faces[$0] }
+            regions.append(fitCurved(indices, triangles: triangles, normals: normals, areas: areas, tolerance: tolerance))
+        }
+        job?.reportProgress(0.9)
+
+        for (index, face) in faces.enumerated() where regionFaces[index] < 0 {
+            regions.append(plane(face, normal: faceNormals[index], triangles: triangles, areas: areas))
+        }
+
+        regions.sort { $0.area > $1.area }
+        var triangleRegions = [Int?](repeating: nil, count: triangles.count)
+        for (index, region) in regions.enumerated() {
+            for triangle in region.triangles {
+                triangleRegions[triangle] = index
+            }
+        }
+        let totalArea = areas.indices.filter { valid[$0] }.reduce(0) { $0 + areas[$1] }
+        return Result(regions: regions, triangleRegions: triangleRegions, totalArea: totalArea)
+    }
+
+    // MARK: - Fitting
+
+    private static func plane(_ indices: [Int], normal: Vector3, triangles: [Triangle], areas: [Double]) -> Region {
+        let area = indices.reduce(0) { $0 + areas[$1] }
+        let points = vertices(of: indices, in: triangles)
+        let offset = points.reduce(0) { $0 + normal.dot($1) } / Double(points.count)
+        let deviation = points.map { abs(normal.dot($0) - offset) }.max() ?? 0
+        return Region(shape: .plane(normal: normal, offset: offset), triangles: indices, area: area, isConvex: true, sweep: 0, deviation: deviation)
+    }
+
+    /// The first of cylinder, cone and sphere that the region's vertices are within the tolerance of
+    static func fitCurved(_ indices: [Int], triangles: [Triangle], normals: [Vector3], areas: [Double], tolerance: Double) -> Region {
+        let area = indices.reduce(0) { $0 + areas[$1] }
+        let points = vertices(of: indices, in: triangles)
+        var region = Region(shape: .freeform, triangles: indices, area: area, isConvex: true, sweep: 0, deviation: 0)
+
+        if let revolution = fitRevolution(indices, points: points, triangles: triangles, normals: normals, areas: areas) {
+            // Cylinder: constant distance from the axis
+            let meanRadius = revolution.samples.reduce(0) { $0 + $1.radius } / Double(revolution.samples.count)
+            let cylinderDeviation = revolution.samples.map { abs($0.radius - meanRadius) }.max() ?? 0
+            let heights = revolution.samples.map(\.height)
+            let (low, high) = (heights.min() ?? 0, heights.max() ?? 0)
+
+            if cylinderDeviation <= tolerance {
+                region.shape = .cylinder(
+                    axis: revolution.axis,
+                    center: revolution.point + revolution.axis * ((low + high) / 2),
+                    radius: meanRadius,
+                    length: high - low
+                )
+                region.deviation = cylinderDeviation
+                region.isConvex = revolution.isConvex
+                region.sweep = revolution.sweep
+                return region
+            }
+
+            // Cone: distance from the axis changes linearly along it
+            if let line = linearFit(revolution.samples.map { ($0.height, $0.radius) }), abs(line.slope) > tan(flatAngle * .pi / 180) {
+                let cosine = 1 / (1 + line.slope * line.slope).squareRoot()
+                let coneDeviation = revolution.samples.map { abs($0.radius - (line.slope * $0.height + line.intercept)) * cosine }.max() ?? 0
+                if coneDeviation <= tolerance {
+                    let apexHeight = -line.intercept / line.slope
+                    let axis = line.slope > 0 ? revolution.axis : -revolution.axis
+                    let radii = [low, high].map { line.slope * $0 + line.intercept }
+                    region.shape = .cone(
+                        apex: revolution.point + revolution.axis * apexHeight,
+                        axis: axis,
+                        halfAngle: atan(abs(line.slope)) * 180 / .pi,
+                        radii: max(radii.min()!, 0)...max(radii.max()!, 0),
+                        length: high - low
+                    )
+                    region.deviation = coneDeviation
+                    region.isConvex = revolution.isConvex
+                    region.sweep = revolution.sweep
+                    return region
+                }
+            }
+        }
+
+        if let sphere = fitSphere(points) {
+            let sphereDeviation = points.map { abs($0.distance(to: sphere.center) - sphere.radius) }.max() ?? 0
+            if sphereDeviation <= tolerance {
+                let outward = indices.reduce(0.0) { sum, index in
+                    let triangle = triangles[index]
+                    let centroid = (triangle.v1 + triangle.v2 + triangle.v3) / 3.0
+                    return sum + areas[index] * normals[index].dot(centroid - sphere.center)
+                }
+                region.shape = .sphere(center: sphere.center, radius: sphere.radius)
+                region.deviation = sphereDeviation
+                region.isConvex = outward > 0
+                return region
+            }
+        }
+        return region
+    }
+
+    private struct Revolution {
+        let axis: Vector3
+        /// Point on the axis that heights are measured from
+        let point: Vector3
+        /// Height along the axis and distance from it of every vertex
+        let samples: [(height: Double, radius: Double)]
+        let isConvex: Bool
+        let sweep: Double
+    }
+
+    /// Axis of a surface of revolution: the direction the normals are spread least along, through the
+    /// point where the normals (seen along the axis) meet; nil if the normals don't define one
+    private static func fitRevolution(_ indices: [Int], points: [Vector3], triangles: [Triangle], normals: [Vector3], areas: [Double]) -> Revolution? {
+        let weight = indices.reduce(0) { $0 + areas[$1] }
+        guard weight > 0 else { return nil }
+        let mean = indices.reduce(SIMD3<Double>.zero) { $0 + normals[$1].value * areas[$1] } / weight
+        var covariance = simd_double3x3()
+        for index in indices {
+            let d = normals[index].value - mean
+            covariance += areas[index] / weight * simd_double3x3(columns: (d * d.x, d * d.y, d * d.z))
+        }
+        let (values, vectors) = ModelAlignment.symmetricEigen(covariance)
+        let order = [0, 1, 2].sorted { values[$0] < values[$1] }
+        // Normals of a cylinder or cone lie on a circle: spread in two directions, not along the axis
+        guard values[order[1]] > 1e-6, values[order[0]] < 0.1 * values[order[1]] else { return nil }
+        let axis = Vector3(value: simd_normalize(vectors[order[0]]))
+
+        // Basis of the plane across the axis
+        let helper: Vector3 = abs(axis.x) < 0.9 ? .unitX : .unitY
+        let u = axis.cross(helper).normalized()
+        let v = axis.cross(u)
+
+        // Least-squares meeting point of the normal lines projected onto that plane
+        var matrix = simd_double2x2()
+        var right = SIMD2<Double>.zero
+        for index in indices {
+            let triangle = triangles[index]
+            let centroid = (triangle.v1 + triangle.v2 + triangle.v3) / 3.0
+            let direction = SIMD2(normals[index].dot(u), normals[index].dot(v))
+            guard simd_length(direction) > 0.1 else { continue }
+            let d = simd_normalize(direction)
+            let projector = simd_double2x2(columns: (SIMD2(1 - d.x * d.x, -d.x * d.y), SIMD2(-d.x * d.y, 1 - d.y * d.y)))
+            matrix += areas[index] * projector
+            right += areas[index] * (projector * SIMD2(centroid.dot(u), centroid.dot(v)))
+        }
+        guard abs(matrix.determinant) > 1e-12 else { return nil }
+        let center2D = matrix.inverse * right
+        let point = u * center2D.x + v * center2D.y
+
+        let samples = points.map { p -> (height: Double, radius: Double) in
+            (p.dot(axis), simd_distance(SIMD2(p.dot(u), p.dot(v)), center2D))
+        }
+        guard samples.allSatisfy({ $0.radius.isFinite }) else { return nil }
+
+        let outward = indices.reduce(0.0) { sum, index in
+            let triangle = triangles[index]
+            let centroid = (triangle.v1 + triangle.v2 + triangle.v3) / 3.0
+            let radial = SIMD2(centroid.dot(u), centroid.dot(v)) - center2D
+            return sum + areas[index] * simd_dot(SIMD2(normals[index].dot(u), normals[index].dot(v)), radial)
+        }
+
+        let angles = points.map { p in atan2(p.dot(v) - center2D.y, p.dot(u) - center2D.x) * 180 / .pi }
+        return Revolution(axis: axis, point: point, samples: samples, isConvex: outward > 0, sweep: sweep(of: angles))
+    }
+
+    /// Angle covered by directions around an axis (degrees); 360 when no gap stands out
+    static func sweep(of angles: [Double]) -> Double {
+        let sorted = angles.map { $0 < 0 ? $0 + 360 : $0 }.sorted()
+        guard sorted.count >= 2 else { return 0 }
+        let gaps = zip(sorted, sorted.dropFirst()).map { $1 - $0 } + [sorted.first! + 360 - sorted.last!]
+        let ordered = gaps.sorted(by: >)
+        // Evenly spaced facets all around leave no gap much larger than the others
+        guard ordered[0] > 1.5 * ordered[1] else { return 360 }
+        return 360 - ordered[0]
+    }
+
+    /// Least-squares line y = slope · x + intercept, nil if all x are equal
+    private static func linearFit(_ samples: [(x: Double, y: Double)]) -> (slope: Double, intercept: Double)? {
+        let count = Double(samples.count)
+        let meanX = samples.reduce(0) { $0 + $1.x } / count
+        let meanY = samples.reduce(0) { $0 + $1.y } / count
+        let sxx = samples.reduce(0) { $0 + ($1.x - meanX) * ($1.x - meanX) }
+        let sxy = samples.reduce(0) { $0 + ($1.x - meanX) * ($1.y - meanY) }
+        guard sxx > 1e-12 else { return nil }
+        let slope = sxy / sxx
+        return (slope, meanY - slope * meanX)
+    }
+
+    /// Least-squares (algebraic) sphere through points, nil for coplanar points
+    static func fitSphere(_ points: [Vector3]) -> (center: Vector3, radius: Double)? {
+        guard points.count >= 4 else { return nil }
+        // Minimize Σ (|p|² + D x + E y + F z + G)² around the centroid for numerical stability
+        let mean = points.reduce(SIMD3<Double>.zero) { $0 + $1.value } / Double(points.count)
+        var matrix = simd_double4x4()
+        var right = SIMD4<Double>.zero
+        for point in points {
+            let d = point.value - mean
+            let row = SIMD4(d.x, d.y, d.z, 1)
+            matrix += simd_double4x4(columns: (row * row.x, row * row.y, row * row.z, row * row.w))
+            right -= row * simd_length_squared(d)
+        }
+        guard abs(matrix.determinant) > 1e-12 else { return nil }
+        let solution = matrix.inverse * right
+        let center = SIMD3(-solution.x / 2, -solution.y / 2, -solution.z / 2)
+        let squaredRadius = simd_length_squared(center) - solution.w
+        guard squaredRadius > 0 else { return nil }
+        return (Vector3(value: mean + center), squaredRadius.squareRoot())
+    }
+
+    // MARK: - Helpers
+
+    private static func vertices(of indices: [Int], in triangles: [Triangle]) -> [Vector3] {
+        var seen = Set<Vector3>()
+        return indices.flatMap { [triangles[$0].v1, triangles[$0].v2, triangles[$0].v3] }.filter { seen.insert($0).inserted }
+    }
+
+    /// Extent of a flat face across its longest direction
+    private static func width(of indices: [Int], normal: Vector3, in triangles: [Triangle]) -> Double {
+        let helper: Vector3 = abs(normal.x) < 0.9 ? .unitX : .unitY
+        let u = normal.cross(helper).normalized()
+        let v = normal.cross(u)
+        let points = vertices(of: indices, in: triangles).map { SIMD2($0.dot(u), $0.dot(v)) }
+        let mean = points.reduce(SIMD2<Double>.zero, +) / Double(points.count)
+        var (sxx, syy, sxy) = (0.0, 0.0, 0.0)
+        for point in points {
+            let d = point - mean
+            sxx += d.x * d.x
+            syy += d.y * d.y
+            sxy += d.x * d.y
+        }
+        // Minor axis of the point spread
+        let major = 0.5 * atan2(2 * sxy, sxx - syy)
+        let minor = SIMD2(-sin(major), cos(major))
+        let extents = points.map { simd_dot($0, minor) }
+        return (extents.max() ?? 0) - (extents.min() ?? 0)
+    }
+
+    // MARK: - Presentation
+
+    static func color(for kind: Kind) -> TriangleColor {
+        switch kind {
+        case .plane: return planeColor
+        case .cylinder: return cylinderColor
+        case .cone: return coneColor
+        case .sphere: return sphereColor
+        case .freeform: return freeformColor
+        }
+    }
+
+    /// Vertex colors by primitive kind; neighboring regions of one kind differ in brightness
+    static func colors(for result: Result) -> VertexColors {
+        VertexColors(perTriangle: result.triangleRegions.map { region in
+            region.map { index in
+                let base = color(for: result.regions[index].shape.kind)
+                let shade = Float(0.72 + 0.28 * Double((index * 3) % 4) / 3)
+                return TriangleColor(base.r * shade, base.g * shade, base.b * shade)
+            }
+        })
+    }
+
+    /// One-line description with the parameters of a region ("Cylinder Ø 6.40 mm hole, 10.00 mm long")
+    static func describe(_ region: Region) -> String {
+        let vector = { (v: Vector3) in String(format: "(%.3g, %.3g, %.3g)", v.x, v.y, v.z) }
+        let point = { (v: Vector3) in String(format: "(%.2f, %.2f, %.2f)", v.x, v.y, v.z) }
+        switch region.shape {
+        case .plane(let normal, let offset):
+            return String(format: "Plane, normal %@, offset %.2f mm", vector(normal), offset)
+        case .cylinder(let axis, let center, let radius, let length):
+            let size = region.sweep >= 360
+                ? String(format: "Ø %.2f mm %@", 2 * radius, region.isConvex ? "boss" : "hole")
+                : String(format: "R %.2f mm %@ over %.0f°", radius, region.isConvex ? "round" : "fillet", region.sweep)
+            return String(format: "Cylinder %@, %.2f mm long, axis %@ through %@", size, length, vector(axis), point(center))
+        case .cone(let apex, let axis, let halfAngle, let radii, let length):
+            return String(format: "Cone %.1f° half angle, Ø %.2f to %.2f mm %@, %.2f mm long, axis %@, apex %@",
+                          halfAngle, 2 * radii.lowerBound, 2 * radii.upperBound, region.isConvex ? "boss" : "hole", length, vector(axis), point(apex))
+        case .sphere(let center, let radius):
+            return String(format: "Sphere Ø %.2f mm %@, center %@", 2 * radius, region.isConvex ? "ball" : "socket", point(center))
+        case .freeform:
+            return "Freeform"
+        }
+    }
+}
@@ -20,20 +20,40 @@ enum STLExporter {
     /// Export model to binary STL format
     /// - Parameters:
     ///   - model: The model to export
-    ///   - url: The destination URL
-    static func exportBinary(model: STLModel, to url: URL) throws {
+    ///   - url: The destination URL (gzip or zstd compressed for `.stl.gz`/`.stl.zst`)
+    ///   - clearHeader: Write a fresh GoSTL header instead of the one read from the source file
+    ///   - clearAttributes: Write zero attribute bytes instead of the values read from the source file
+    static func exportBinary(model: STLModel, to url: URL, clearHeader: Bool = false, clearAttributes: Bool = false) throws {
         guard !model.triangles.isEmpty else {
             throw STLExportError.emptyModel
         }
 
+        let data = binaryData(model: model, clearHeader: clearHeader, clearAttributes: clearAttributes)
+
+        // Write to file (compressed for .gz/.zst destinations)
+        do {
+            try FileCompression.write(data, to: url)
+        } catch {
+            throw STLExportError.writeFailure(error.localizedDescription)
+        }
+    }
+
+    /// Encode a model as binary STL, preserving the source header and attribute bytes unless cleared
+    static func binaryData(model: STLModel, clearHeader: Bool = false, clearAttributes: Bool = false) -> Data {
         var data = Data()
+        data.reserveCapacity(84 + model.triangles.count * 50)
 
-        // Header: 80 bytes (can contain description or be empty)
-        let header = "GoSTL Export - \(model.name ?? "Untitled")"
+        // Header: 80 bytes (the original header when available, otherwise a description)
         var headerBytes = [UInt8](repeating: 0, count: 80)
-        let headerData = header.utf8.prefix(80)
-        for (index, byte) in headerData.enumerated() {
-            headerBytes[index] = byte
+        if let original = model.header, !clearHeader {
+            for (index, byte) in original.prefix(80).enumerated() {
+                headerBytes[index] = byte
+            }
+        } else {
+            let header = "GoSTL Export - \(model.name ?? "Untitled")"
+            for (index, byte) in header.utf8.prefix(80).enumerated() {
+                headerBytes[index] = byte
+            }
         }
         data.append(contentsOf: headerBytes)
 
@@ -64,16 +84,11 @@ enum STLExporter {
             appendFloat32(&data, Float(triangle.v3.z))
 
             // Attribute byte count: 2 bytes (UInt16, usually 0)
-            var attributeByteCount: UInt16 = 0
+            var attributeByteCount: UInt16 = clearAttributes ? 0 : triangle.attribute
             data.append(contentsOf: withUnsafeBytes(of: &attributeByteCount) { Array($0) })
         }
 
-        // Write to file
-        do {
-            try data.write(to: url)
-        } catch {
-            throw STLExportError.writeFailure(error.localizedDescription)
-        }
+        return data
     }
 
     /// Export model to ASCII STL format
@@ -99,9 +114,9 @@ enum STLExporter {
 
         output += "endsolid \(model.name ?? "model")\n"
 
-        // Write to file
+        // Write to file (compressed for .gz/.zst destinations)
         do {
-            try output.write(to: url, atomically: true, encoding: .utf8)
+            try FileCompression.write(Data(output.utf8), to: url)
         } catch {
             throw STLExportError.writeFailure(error.localizedDescription)
         }
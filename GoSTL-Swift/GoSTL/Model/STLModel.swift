@@ -15,14 +15,18 @@ struct STLModel {
     var triangles: [Triangle]
     var name: String?
 
+    /// The raw 80-byte header of a binary STL, written back on export (nil for ASCII files)
+    var header: Data?
+
     /// Pre-computed bounding box (computed during parsing for performance)
     private var _precomputedBounds: BoundingBox?
 
     // MARK: - Initializers
 
-    init(triangles: [Triangle] = [], name: String? = nil, precomputedBounds: BoundingBox? = nil) {
+    init(triangles: [Triangle] = [], name: String? = nil, precomputedBounds: BoundingBox? = nil, header: Data? = nil) {
         self.triangles = triangles
         self.name = name
+        self.header = header
         self._precomputedBounds = precomputedBounds
     }
 
@@ -33,6 +37,18 @@ struct STLModel {
         triangles.count
     }
 
+    /// Whether any facet carries non-zero attribute bytes
+    var hasAttributes: Bool {
+        triangles.contains { $0.attribute != 0 }
+    }
+
+    /// The header as printable text, with trailing padding removed (nil when there is no header)
+    var headerText: String? {
+        guard let header else { return nil }
+        let printable = header.map { $0 >= 32 && $0 <= 126 ? Character(UnicodeScalar($0)) : " " }
+        return String(printable).trimmingCharacters(in: .whitespaces)
+    }
+
     /// Calculate the bounding box of the entire model
     func boundingBox() -> BoundingBox {
         // Return precomputed bounds if available (computed during parsing)
@@ -359,6 +375,60 @@ struct STLModel {
     }
 }
 
+// MARK: - Recentering
+
+extension STLModel {
+    /// Coordinate magnitude (mm) above which a model is moved near the origin for rendering.
+    /// Float32 only keeps about 7 significant digits, so vertices further out visibly jitter.
+    static let recenteringThreshold: Double = 10_000
+
+    /// Offset that moves a model with the given bounds near the origin,
+    /// or zero if the model already fits comfortably in float range
+    static func recenteringOffset(for bounds: BoundingBox) -> Vector3 {
+        let extent = Swift.max(
+            abs(bounds.min.x), abs(bounds.min.y), abs(bounds.min.z),
+            abs(bounds.max.x), abs(bounds.max.y), abs(bounds.max.z)
+        )
+        guard extent > recenteringThreshold else { return .zero }
+
+        // Whole millimeters keep the offset exact and easy to read
+        let center = bounds.center
+        return Vector3(center.x.rounded(), center.y.rounded(), center.z.rounded())
+    }
+
+    /// A copy of the model with every vertex moved by the given offset
+    func translated(by offset: Vector3) -> STLModel {
+        let movedTriangles = triangles.map { triangle in
+            var moved = triangle
+            moved.v1 = triangle.v1 + offset
+            moved.v2 = triangle.v2 + offset
+            moved.v3 = triangle.v3 + offset
+            return moved
+        }
+
+        var bounds = boundingBox()
+        bounds.min = bounds.min + offset
+        bounds.max = bounds.max + offset
+        return STLModel(triangles: movedTriangles, name: name, precomputedBounds: triangles.isEmpty ? nil : bounds, header: header)
+    }
+
+    /// A copy of the model scaled uniformly about the origin (positive factors keep the normals)
+    func scaled(by factor: Double) -> STLModel {
+        let scaledTriangles = triangles.map { triangle in
+            var scaled = triangle
+            scaled.v1 = triangle.v1 * factor
+            scaled.v2 = triangle.v2 * factor
+            scaled.v3 = triangle.v3 * factor
+            return scaled
+        }
+
+        var bounds = boundingBox()
+        bounds.min = bounds.min * factor
+        bounds.max = bounds.max * factor
+        return STLModel(triangles: scaledTriangles, name: name, precomputedBounds: triangles.isEmpty ? nil : bounds, header: header)
+    }
+}
+
 // MARK: - StyledEdge
 
 /// An edge with styling information for rendering (width multiplier and alpha)
@@ -15,13 +15,13 @@ enum STLParser {
 
     // MARK: - Public API
 
-    /// Parse an STL file from a URL
+    /// Parse an STL file from a URL (`.stl.gz` and `.stl.zst` files are decompressed first)
     static func parse(url: URL) throws -> STLModel {
         let t0 = CFAbsoluteTimeGetCurrent()
-        let data = try Data(contentsOf: url)
+        let data = try FileCompression.read(url)
         print("    File read: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms (\(data.count / 1_000_000)MB)")
 
-        let name = url.deletingPathExtension().lastPathComponent
+        let name = ModelFileLoader.modelName(of: url)
         let t1 = CFAbsoluteTimeGetCurrent()
         let model = try parse(data: data, name: name)
         print("    Parse data: \(String(format: "%.2f", (CFAbsoluteTimeGetCurrent() - t1) * 1000))ms")
@@ -423,7 +423,7 @@ enum STLParser {
         //   12 bytes: Vertex 1 (3 x float32)
         //   12 bytes: Vertex 2 (3 x float32)
         //   12 bytes: Vertex 3 (3 x float32)
-        //   2 bytes: Attribute byte count (uint16, usually 0)
+        //   2 bytes: Attribute byte count (uint16, usually 0, kept on the triangle)
 
         // Read triangle count
         let triangleCount = Int(data.readUInt32(at: 80))
@@ -433,13 +433,14 @@ enum STLParser {
             throw STLError.inconsistentSize
         }
 
-        // For small files, use sequential parsing
-        if triangleCount < 10000 {
-            return parseBinarySequential(data: data, triangleCount: triangleCount, name: name)
-        }
+        // For small files use sequential parsing, for large files parallel parsing
+        var model = triangleCount < 10000
+            ? parseBinarySequential(data: data, triangleCount: triangleCount, name: name)
+            : parseBinaryParallel(data: data, triangleCount: triangleCount, name: name)
 
-        // For large files, use parallel parsing
-        return parseBinaryParallel(data: data, triangleCount: triangleCount, name: name)
+        // Keep the header so it survives a round-trip
+        model.header = Data(data.prefix(80))
+        return model
     }
 
     /// Sequential binary parsing for small files using direct memory access
@@ -562,11 +563,15 @@ enum STLParser {
         let v3y = Double(floatPtr[10])
         let v3z = Double(floatPtr[11])
 
+        // Attribute bytes follow the floats and are not 4-byte aligned
+        let attribute = UnsafeRawPointer(bytes + offset + 48).loadUnaligned(as: UInt16.self)
+
         return Triangle(
             v1: Vector3(v1x, v1y, v1z),
             v2: Vector3(v2x, v2y, v2z),
             v3: Vector3(v3x, v3y, v3z),
-            normal: Vector3(nx, ny, nz)
+            normal: Vector3(nx, ny, nz),
+            attribute: attribute
         )
     }
 
@@ -593,11 +598,15 @@ enum STLParser {
         let v3y = data.readFloat32(at: offset + 40)
         let v3z = data.readFloat32(at: offset + 44)
 
+        // Read attribute bytes
+        let attribute = data.readUInt16(at: offset + 48)
+
         return Triangle(
             v1: Vector3(Double(v1x), Double(v1y), Double(v1z)),
             v2: Vector3(Double(v2x), Double(v2y), Double(v2z)),
             v3: Vector3(Double(v3x), Double(v3y), Double(v3z)),
-            normal: Vector3(Double(nx), Double(ny), Double(nz))
+            normal: Vector3(Double(nx), Double(ny), Double(nz)),
+            attribute: attribute
         )
     }
 }
@@ -616,6 +625,17 @@ private extension Data {
         return value
     }
 
+    func readUInt16(at offset: Int) -> UInt16 {
+        // Copy bytes to ensure proper alignment
+        var value: UInt16 = 0
+        withUnsafeMutablePointer(to: &value) { pointer in
+            let buffer = UnsafeMutableRawBufferPointer(start: pointer, count: MemoryLayout<UInt16>.size)
+            let range = offset..<(offset + MemoryLayout<UInt16>.size)
+            _ = copyBytes(to: buffer, from: range)
+        }
+        return value
+    }
+
     func readUInt32(at offset: Int) -> UInt32 {
         // Copy bytes to ensure proper alignment
         var value: UInt32 = 0
@@ -0,0 +1,66 @@
+import CoreGraphics
+import Foundation
+
+/// Writes 2D line drawings (outline renders) as SVG
+enum SVGExporter {
+    /// Build an SVG document from line segments
+    /// - Parameters:
+    ///   - segments: Lines in view coordinates (origin top-left, y down)
+    ///   - size: Width and height of the drawing
+    ///   - strokeWidth: Line width in drawing units
+    ///   - title: Optional document title (e.g. the model file name)
+    /// - Returns: The SVG document with a white background and black round-capped lines
+    static func svg(segments: [(CGPoint, CGPoint)], size: CGSize, strokeWidth: Double = 1.0, title: String? = nil) -> String {
+        let width = format(size.width)
+        let height = format(size.height)
+
+        var lines: [String] = []
+        lines.append("<?xml version=\"1.0\" encoding=\"UTF-8\"?>")
+        lines.append("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"\(width)\" height=\"\(height)\" viewBox=\"0 0 \(width) \(height)\">")
+        if let title {
+            lines.append("  <title>\(escape(title))</title>")
+        }
+        lines.append("  <rect width=\"100%\" height=\"100%\" fill=\"white\"/>")
+
+        if !segments.isEmpty {
+            let path = segments
+                .map { "M\(format($0.0.x)) \(format($0.0.y))L\(format($0.1.x)) \(format($0.1.y))" }
+                .joined()
+            lines.append("  <path d=\"\(path)\" fill=\"none\" stroke=\"black\" stroke-width=\"\(format(strokeWidth))\" stroke-linecap=\"round\" stroke-linejoin=\"round\"/>")
+        }
+
+        lines.append("</svg>")
+        return lines.joined(separator: "\n") + "\n"
+    }
+
+    /// Write an SVG document to a file
+    static func write(segments: [(CGPoint, CGPoint)], size: CGSize, to url: URL, strokeWidth: Double = 1.0, title: String? = nil) throws {
+        try svg(segments: segments, size: size, strokeWidth: strokeWidth, title: title)
+            .write(to: url, atomically: true, encoding: .utf8)
+    }
+
+    /// Coordinates with two decimals, without trailing zeros ("12.5", "3")
+    static func format(_ value: Double) -> String {
+        var text = String(format: "%.2f", value)
+        while text.hasSuffix("0") {
+            text.removeLast()
+        }
+        if text.hasSuffix(".") {
+            text.removeLast()
+        }
+        return text == "-0" ? "0" : text
+    }
+
+    static func format(_ value: CGFloat) -> String {
+        format(Double(value))
+    }
+
+    /// Escape text for SVG element content and attribute values
+    static func escape(_ text: String) -> String {
+        text
+            .replacingOccurrences(of: "&", with: "&amp;")
+            .replacingOccurrences(of: "<", with: "&lt;")
+            .replacingOccurrences(of: ">", with: "&gt;")
+            .replacingOccurrences(of: "\"", with: "&quot;")
+    }
+}
@@ -0,0 +1,90 @@
+import Foundation
+
+/// Detects likely unit mistakes of a loaded model from its size
+///
+/// Files carry no unit; GoSTL reads them as millimeters. A part exported in meters shows up a
+/// thousand times too small, one in inches 25.4 times too small, and one in micrometers a
+/// thousand times too large.
+enum ScaleCheck {
+    /// A one-click scale fix
+    enum Fix: String, CaseIterable, Equatable {
+        case inchesToMillimeters
+        case metersToMillimeters
+        case micrometersToMillimeters
+
+        var factor: Double {
+            switch self {
+            case .inchesToMillimeters: return 25.4
+            case .metersToMillimeters: return 1000
+            case .micrometersToMillimeters: return 0.001
+            }
+        }
+
+        var title: String {
+            switch self {
+            case .inchesToMillimeters: return "×25.4 (Inches)"
+            case .metersToMillimeters: return "×1000 (Meters)"
+            case .micrometersToMillimeters: return "÷1000 (Micrometers)"
+            }
+        }
+    }
+
+    /// A suspicious model size with the fixes that would explain it, most likely first
+    struct Finding: Equatable {
+        /// Largest dimension in millimeters
+        let extent: Double
+        let reason: String
+        let fixes: [Fix]
+    }
+
+    /// Models smaller than this (mm) are suspected to be in meters or inches
+    static let minimumExtent = 1.0
+    /// Models larger than this (mm) are suspected to be in micrometers
+    static let maximumExtent = 2000.0
+    /// Models up to this size (mm) with inch-fraction dimensions are suspected to be in inches
+    static let inchExtent = 24.0
+
+    /// Check a model's bounding box size (nil if it looks plausible)
+    static func check(size: Vector3) -> Finding? {
+        let extent = Swift.max(size.x, size.y, size.z)
+        guard extent > 0, extent.isFinite else { return nil }
+
+        if extent < minimumExtent {
+            return Finding(
+                extent: extent,
+                reason: String(format: "The model is only %.3g mm across. It was probably exported in meters or inches.", extent),
+                fixes: [.metersToMillimeters, .inchesToMillimeters]
+            )
+        }
+        if extent > maximumExtent {
+            return Finding(
+                extent: extent,
+                reason: String(format: "The model is %.1f m across. It was probably exported in micrometers.", extent / 1000),
+                fixes: [.micrometersToMillimeters]
+            )
+        }
+        if extent <= inchExtent && looksLikeInches(size) {
+            return Finding(
+                extent: extent,
+                reason: String(format: "The model measures %@ mm, all fractions of an inch. It was probably exported in inches.",
+                               [size.x, size.y, size.z].map { String(format: "%g", $0) }.joined(separator: " × ")),
+                fixes: [.inchesToMillimeters]
+            )
+        }
+        return nil
+    }
+
+    /// Whether all dimensions are sixteenths and at least one is not a multiple of 0.5
+    ///
+    /// Millimeter designs use whole and half millimeters; 0.75 or 0.125 are typical of inches.
+    static func looksLikeInches(_ size: Vector3) -> Bool {
+        let dimensions = [size.x, size.y, size.z].filter { $0 > 0 }
+        guard !dimensions.isEmpty else { return false }
+
+        func isMultiple(_ value: Double, of step: Double) -> Bool {
+            abs(value / step - (value / step).rounded()) < 1e-3
+        }
+        return dimensions.allSatisfy { isMultiple($0, of: 1.0 / 16) }
+            && dimensions.contains { !isMultiple($0, of: 0.5) }
+    }
+}
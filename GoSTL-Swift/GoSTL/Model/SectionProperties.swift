@@ -0,0 +1,183 @@
+import Foundation
+
+enum SectionPropertiesError: LocalizedError {
+    case emptyModel
+    case tooManyStations(Int)
+
+    var errorDescription: String? {
+        switch self {
+        case .emptyModel:
+            return "Cannot compute cross-sections of an empty model"
+        case .tooManyStations(let count):
+            return "\(count) stations are too many; use a larger step"
+        }
+    }
+}
+
+/// Area and second moment of area of cross sections swept along an axis
+///
+/// Each station cuts the model with a plane normal to the axis; the filled cross-section (holes
+/// and islands included, see `CrossSection.fill`) is integrated exactly over its triangles. Second
+/// moments are taken about axes through the section's centroid, parallel to the two in-plane model
+/// axes, for quick beam bending estimates: stress = M · c / I.
+struct SectionProperties {
+    enum Axis: String, CaseIterable {
+        case x, y, z
+
+        var index: Int {
+            switch self {
+            case .x: return 0
+            case .y: return 1
+            case .z: return 2
+            }
+        }
+
+        /// In-plane axes (u, v) of a section normal to this axis
+        var planeAxes: (u: Axis, v: Axis) {
+            switch self {
+            case .x: return (.y, .z)
+            case .y: return (.z, .x)
+            case .z: return (.x, .y)
+            }
+        }
+    }
+
+    /// One cross-section
+    struct Station: Equatable {
+        /// Position of the cutting plane along the axis (file coordinates, mm)
+        var position: Double
+        /// Area of the filled cross-section (mm²)
+        var area: Double
+        /// Centroid of the cross-section (file coordinates; the section axis component is `position`)
+        var centroid: Vector3
+        /// Second moment of area about the centroidal axis parallel to u: ∫ (v - v̄)² dA (mm⁴)
+        var iu: Double
+        /// Second moment of area about the centroidal axis parallel to v: ∫ (u - ū)² dA (mm⁴)
+        var iv: Double
+        /// Product of area about the centroid: ∫ (u - ū)(v - v̄) dA (mm⁴)
+        var iuv: Double
+
+        /// Polar second moment of area (mm⁴)
+        var polar: Double {
+            iu + iv
+        }
+
+        /// Principal second moments of area, smallest first (mm⁴); the section bends most easily
+        /// about the axis of the smallest one
+        var principal: (min: Double, max: Double) {
+            let mean = (iu + iv) / 2
+            let radius = (((iu - iv) / 2) * ((iu - iv) / 2) + iuv * iuv).squareRoot()
+            return (mean - radius, mean + radius)
+        }
+    }
+
+    let axis: Axis
+    /// Distance between stations (mm)
+    let step: Double
+    let stations: [Station]
+
+    /// Largest number of stations per sweep
+    static let maxStations = 100_000
+
+    /// Station with the smallest principal second moment of area, ignoring gaps in the model
+    var weakestStation: Station? {
+        stations.filter { $0.area > 0 }.min { $0.principal.min < $1.principal.min }
+    }
+
+    /// Cross-sections at every multiple of `step` strictly inside the model's extent along the axis
+    /// - Parameter step: Station spacing in mm, nil for a round step like the automatic contour interval
+    static func compute(model: STLModel, axis: Axis, step: Double? = nil) throws -> SectionProperties {
+        guard model.triangleCount > 0 else { throw SectionPropertiesError.emptyModel }
+        let index = axis.index
+        let box = model.boundingBox()
+        let low = box.min.component(axis: index)
+        let high = box.max.component(axis: index)
+        let step = step ?? ContourLines.autoInterval(for: high - low)
+
+        let first = (low / step).rounded(.down) + 1
+        let last = (high / step).rounded(.up) - 1
+        guard first <= last else {
+            return SectionProperties(axis: axis, step: step, stations: [])
+        }
+        guard last - first < Double(maxStations) else {
+            throw SectionPropertiesError.tooManyStations(Int(last - first) + 1)
+        }
+
+        let stations = stride(from: first, through: last, by: 1).map { level -> Station in
+            let position = level * step
+            let fill = CrossSection.fill(model.triangles, axis: index, value: position, facingPositive: true)
+            return integrate(fill, axis: axis, position: position)
+        }
+        return SectionProperties(axis: axis, step: step, stations: stations)
+    }
+
+    /// Exact area, centroid and centroidal second moments of planar triangles
+    static func integrate(_ triangles: [Triangle], axis: Axis, position: Double) -> Station {
+        let (uAxis, vAxis) = (axis.planeAxes.u.index, axis.planeAxes.v.index)
+        var area = 0.0
+        var su = 0.0, sv = 0.0
+        var suu = 0.0, svv = 0.0, suv = 0.0
+        for triangle in triangles {
+            let u = [triangle.v1, triangle.v2, triangle.v3].map { $0.component(axis: uAxis) }
+            let v = [triangle.v1, triangle.v2, triangle.v3].map { $0.component(axis: vAxis) }
+            let a = abs((u[1] - u[0]) * (v[2] - v[0]) - (u[2] - u[0]) * (v[1] - v[0])) / 2
+            guard a > 0 else { continue }
+
+            area += a
+            su += a * (u[0] + u[1] + u[2]) / 3
+            sv += a * (v[0] + v[1] + v[2]) / 3
+            // ∫ x² dA = A/6 (x₁² + x₂² + x₃² + x₁x₂ + x₂x₃ + x₃x₁)
+            suu += a / 6 * (u[0] * u[0] + u[1] * u[1] + u[2] * u[2] + u[0] * u[1] + u[1] * u[2] + u[2] * u[0])
+            svv += a / 6 * (v[0] * v[0] + v[1] * v[1] + v[2] * v[2] + v[0] * v[1] + v[1] * v[2] + v[2] * v[0])
+            // ∫ xy dA = A/12 (Σ 2xᵢyᵢ + Σ xᵢyⱼ for i ≠ j)
+            suv += a / 12 * (
+                2 * (u[0] * v[0] + u[1] * v[1] + u[2] * v[2])
+                    + u[0] * (v[1] + v[2]) + u[1] * (v[0] + v[2]) + u[2] * (v[0] + v[1])
+            )
+        }
+
+        guard area > 0 else {
+            return Station(position: position, area: 0, centroid: Vector3.zero.with(axis: axis.index, value: position), iu: 0, iv: 0, iuv: 0)
+        }
+        let (cu, cv) = (su / area, sv / area)
+        let centroid = Vector3.zero
+            .with(axis: axis.index, value: position)
+            .with(axis: uAxis, value: cu)
+            .with(axis: vAxis, value: cv)
+        // Parallel axis theorem: about the centroid
+        return Station(
+            position: position,
+            area: area,
+            centroid: centroid,
+            iu: svv - area * cv * cv,
+            iv: suu - area * cu * cu,
+            iuv: suv - area * cu * cv
+        )
+    }
+
+    // MARK: - Export
+
+    /// Column headers, e.g. `z, area, centroid_x, centroid_y, i_x, i_y, i_xy, i_min, i_max, j` for the Z axis
+    var csvHeader: [String] {
+        let (u, v) = (axis.planeAxes.u.rawValue, axis.planeAxes.v.rawValue)
+        return [axis.rawValue, "area", "centroid_\(u)", "centroid_\(v)", "i_\(u)", "i_\(v)", "i_\(u)\(v)", "i_min", "i_max", "j"]
+    }
+
+    /// One row per station with a header line; empty sections (gaps) have zero area and no centroid
+    func csv(decimals: Int = 4, format: CSVFormat = .standard) -> String {
+        let (u, v) = (axis.planeAxes.u.index, axis.planeAxes.v.index)
+        let number = { (value: Double) in format.number(value, decimals: decimals) }
+        var lines = [format.row(csvHeader)]
+        for station in stations {
+            let centroid = station.area > 0
+                ? [number(station.centroid.component(axis: u)), number(station.centroid.component(axis: v))]
+                : ["", ""]
+            let principal = station.principal
+            lines.append(format.row(
+                [number(station.position), number(station.area)] + centroid
+                    + [station.iu, station.iv, station.iuv, principal.min, principal.max, station.polar].map(number)
+            ))
+        }
+        return lines.joined(separator: "\n") + "\n"
+    }
+}
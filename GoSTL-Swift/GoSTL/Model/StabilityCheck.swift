@@ -0,0 +1,123 @@
+import Foundation
+
+/// Whether a model stands on its bottom face without tipping over
+///
+/// The model rests on the lowest Z plane; every vertex within `contactTolerance` of it touches the
+/// ground, and their convex hull is the support polygon. The model is stable when the center of
+/// mass (uniform density) projects inside that polygon. The margin is the distance from the
+/// projection to the nearest polygon edge, and the tilt angle is how far the model can be tipped
+/// over that edge before it falls: atan(margin / height of the center of mass).
+struct StabilityCheck: Equatable {
+    /// Vertices this close to the lowest point count as resting on the ground (mm)
+    static let contactTolerance = 0.05
+
+    let centerOfMass: Vector3
+    /// Height of the resting plane
+    let baseZ: Double
+    /// Convex hull of the contact points (counter-clockwise, at `baseZ`)
+    let supportPolygon: [Vector3]
+    /// Distance from the projected center of mass to the nearest edge of the support polygon,
+    /// negative if the projection lies outside (mm)
+    let margin: Double
+    /// Edge the model tips over first
+    let tippingEdge: (start: Vector3, end: Vector3)?
+
+    var isStable: Bool {
+        margin > 0
+    }
+
+    /// Height of the center of mass above the resting plane
+    var centerHeight: Double {
+        centerOfMass.z - baseZ
+    }
+
+    /// Tilt toward the tipping edge before the model falls (degrees), negative if it cannot stand
+    var tiltAngle: Double {
+        atan2(margin, max(centerHeight, 0)) * 180 / .pi
+    }
+
+    static func == (lhs: StabilityCheck, rhs: StabilityCheck) -> Bool {
+        lhs.centerOfMass == rhs.centerOfMass && lhs.baseZ == rhs.baseZ
+            && lhs.supportPolygon == rhs.supportPolygon && lhs.margin == rhs.margin
+    }
+
+    // MARK: - Computation
+
+    /// Check a model resting on its lowest point, nil if it has no volume
+    static func check(model: STLModel, tolerance: Double = contactTolerance) -> StabilityCheck? {
+        let properties = MassProperties.compute(triangles: model.triangles, density: 1)
+        guard properties.volume > 0 else { return nil }
+
+        let baseZ = model.boundingBox().min.z
+        let contacts = model.triangles
+            .flatMap { [$0.v1, $0.v2, $0.v3] }
+            .filter { $0.z <= baseZ + tolerance }
+        let polygon = convexHull(contacts).map { Vector3($0.x, $0.y, baseZ) }
+
+        let center = properties.centerOfMass
+        let (margin, edge) = signedDistance(x: center.x, y: center.y, to: polygon)
+        return StabilityCheck(centerOfMass: center, baseZ: baseZ, supportPolygon: polygon, margin: margin, tippingEdge: edge)
+    }
+
+    /// Convex hull of points projected to the XY plane, counter-clockwise (Andrew's monotone chain)
+    static func convexHull(_ points: [Vector3]) -> [Vector3] {
+        let sorted = points.sorted { $0.x != $1.x ? $0.x < $1.x : $0.y < $1.y }
+        guard sorted.count > 2 else {
+            return sorted.reduce(into: []) { unique, point in
+                if unique.last.map({ $0.x != point.x || $0.y != point.y }) ?? true { unique.append(point) }
+            }
+        }
+
+        func cross(_ o: Vector3, _ a: Vector3, _ b: Vector3) -> Double {
+            (a.x - o.x) * (b.y - o.y) - (a.y - o.y) * (b.x - o.x)
+        }
+
+        var lower: [Vector3] = []
+        for point in sorted {
+            while lower.count >= 2 && cross(lower[lower.count - 2], lower[lower.count - 1], point) <= 0 {
+                lower.removeLast()
+            }
+            lower.append(point)
+        }
+        var upper: [Vector3] = []
+        for point in sorted.reversed() {
+            while upper.count >= 2 && cross(upper[upper.count - 2], upper[upper.count - 1], point) <= 0 {
+                upper.removeLast()
+            }
+            upper.append(point)
+        }
+        return Array(lower.dropLast() + upper.dropLast())
+    }
+
+    /// Distance from a point to the boundary of a counter-clockwise convex polygon (positive inside)
+    /// and the nearest edge; a single contact point or line has no inside
+    static func signedDistance(x: Double, y: Double, to polygon: [Vector3]) -> (Double, (start: Vector3, end: Vector3)?) {
+        guard let first = polygon.first else { return (-.infinity, nil) }
+        guard polygon.count > 1 else {
+            return (-((x - first.x) * (x - first.x) + (y - first.y) * (y - first.y)).squareRoot(), nil)
+        }
+
+        var nearest = Double.infinity
+        var nearestEdge: (start: Vector3, end: Vector3)?
+        var inside = polygon.count > 2
+        let edgeCount = polygon.count > 2 ? polygon.count : 1
+        for index in 0..<edgeCount {
+            let a = polygon[index]
+            let b = polygon[(index + 1) % polygon.count]
+            let (dx, dy) = (b.x - a.x, b.y - a.y)
+            let length2 = dx * dx + dy * dy
+            let t = length2 > 0 ? min(max(((x - a.x) * dx + (y - a.y) * dy) / length2, 0), 1) : 0
+            let (px, py) = (a.x + t * dx - x, a.y + t * dy - y)
+            let distance = (px * px + py * py).squareRoot()
+            if distance < nearest {
+                nearest = distance
+                nearestEdge = (a, b)
+            }
+            // Counter-clockwise: inside is to the left of every edge
+            if dx * (y - a.y) - dy * (x - a.x) <= 0 {
+                inside = false
+            }
+        }
+        return (inside ? nearest : -nearest, nearestEdge)
+    }
+}
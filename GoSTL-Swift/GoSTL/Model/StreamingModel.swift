@@ -0,0 +1,487 @@
+import Foundation
+import simd
+
+/// Thread-safe array wrapper for parallel writes to different indices
+private final class ParallelArray<T>: @unchecked Sendable {
+    var storage: [T]
+    init(_ array: [T]) { self.storage = array }
+    subscript(index: Int) -> T {
+        get { storage[index] }
+        set { storage[index] = newValue }
+    }
+}
+
+enum StreamingError: LocalizedError {
+    case notBinarySTL
+    case emptyModel
+    case chunkFileDamaged
+    case proxyOnly
+
+    var errorDescription: String? {
+        switch self {
+        case .notBinarySTL:
+            return "Only uncompressed binary STL files can be streamed"
+        case .emptyModel:
+            return "The file contains no valid triangles"
+        case .chunkFileDamaged:
+            return "The chunk cache of the streamed model could not be read"
+        case .proxyOnly:
+            return "A streamed model cannot be saved: only its decimated proxy is in memory"
+        }
+    }
+}
+
+/// Regular grid of cubic cells over a model's bounds (chunks of a streamed model, vertex clusters of its proxy)
+struct ChunkGrid: Equatable, Sendable {
+    let origin: SIMD3<Double>
+    let cellSize: Double
+    /// Cells along X, Y and Z
+    let dimensions: SIMD3<Int>
+
+    var cellCount: Int {
+        dimensions.x * dimensions.y * dimensions.z
+    }
+
+    /// Cells of a given size (flat axes get a single layer)
+    init(bounds: BoundingBox, cellSize: Double) {
+        let cells = (bounds.size.value / cellSize).rounded(.up)
+        origin = bounds.min.value
+        self.cellSize = cellSize
+        dimensions = SIMD3(max(1, Int(cells.x)), max(1, Int(cells.y)), max(1, Int(cells.z)))
+    }
+
+    /// As many cubic cells as possible, but at most `cellCount`
+    init(bounds: BoundingBox, cellCount: Int) {
+        let size = bounds.size.value
+        let longest = max(size.x, size.y, size.z, 1e-9)
+        // Fewer cells as they grow: bisect the smallest size that stays within the count
+        var low = longest / Double(max(cellCount, 1))
+        var high = longest
+        for _ in 0..<40 {
+            let middle = (low + high) / 2
+            if ChunkGrid(bounds: bounds, cellSize: middle).cellCount > cellCount {
+                low = middle
+            } else {
+                high = middle
+            }
+        }
+        self.init(bounds: bounds, cellSize: high)
+    }
+
+    /// Index of the cell containing a point (points outside go to the nearest cell)
+    func cell(of point: SIMD3<Double>) -> Int {
+        let index = (point - origin) / cellSize
+        let x = min(max(Int(index.x), 0), dimensions.x - 1)
+        let y = min(max(Int(index.y), 0), dimensions.y - 1)
+        let z = min(max(Int(index.z), 0), dimensions.z - 1)
+        return (z * dimensions.y + y) * dimensions.x + x
+    }
+}
+
+/// Triangles of one grid cell of a streamed model
+struct StreamingChunk: Equatable, Sendable {
+    /// Cell of the chunk grid
+    let cell: Int
+    /// Bounds of the chunk's triangles (file coordinates)
+    let bounds: BoundingBox
+    /// First triangle of the chunk in the chunk file
+    let offset: Int
+    let triangleCount: Int
+    /// Triangles of the proxy standing in for this chunk
+    let proxyRange: Range<Int>
+}
+
+/// A binary STL too large to load, split into spatial chunks with a decimated proxy
+///
+/// Building it reads the memory-mapped file three times: bounds, then chunk sizes and the proxy
+/// (vertex clustering on a grid), then the chunk file with the triangles grouped by chunk. Only
+/// the proxy and the chunk table stay in memory; full-resolution chunks are read from the chunk
+/// file on demand (see `StreamingResidency`).
+final class StreamingModel: Sendable {
+    let sourceURL: URL
+    /// Triangles grouped by chunk, nine Float32 each (file coordinates); removed with the model
+    let chunkFileURL: URL
+    /// Triangles of the file (without ones with invalid coordinates)
+    let triangleCount: Int
+    /// Bounds of the file's triangles (file coordinates)
+    let bounds: BoundingBox
+    let chunks: [StreamingChunk]
+    /// Decimated stand-in (file coordinates), its triangles ordered by chunk
+    let proxy: STLModel
+
+    /// Chunks are sized for about this many triangles (cells of a surface are never all occupied)
+    static let trianglesPerChunk = 200_000
+    static let maxChunks = 4096
+    /// Vertex clusters of the proxy along the longest axis
+    static let proxyResolution = 384
+    /// Bytes of one triangle in the chunk file
+    static let recordSize = 36
+    /// Triangles buffered per chunk before they are written to the chunk file
+    private static let writeBatch = 512
+    /// Triangles between progress reports and cancellation checks
+    private static let blockSize = 1 << 20
+
+    /// Binary STL files larger than this are streamed. A loaded model takes about six times its
+    /// file size (triangles plus vertex buffer), so an eighth of the memory leaves room to work.
+    static var automaticThreshold: Int64 {
+        Int64(ProcessInfo.processInfo.physicalMemory / 8)
+    }
+
+    private init(sourceURL: URL, chunkFileURL: URL, triangleCount: Int, bounds: BoundingBox, chunks: [StreamingChunk], proxy: STLModel) {
+        self.sourceURL = sourceURL
+        self.chunkFileURL = chunkFileURL
+        self.triangleCount = triangleCount
+        self.bounds = bounds
+        self.chunks = chunks
+        self.proxy = proxy
+    }
+
+    deinit {
+        try? FileManager.default.removeItem(at: chunkFileURL)
+    }
+
+    // MARK: - Detection
+
+    /// Number of triangles if the file is an uncompressed binary STL (the size matches the header's count)
+    static func binaryTriangleCount(url: URL) -> Int? {
+        guard url.pathExtension.lowercased() == "stl",
+              let handle = try? FileHandle(forReadingFrom: url) else { return nil }
+        defer { try? handle.close() }
+        guard let header = try? handle.read(upToCount: 84), header.count == 84,
+              let size = try? handle.seekToEnd() else { return nil }
+        let count = Int(UInt32(littleEndian: header.withUnsafeBytes { $0.loadUnaligned(fromByteOffset: 80, as: UInt32.self) }))
+        return UInt64(84 + count * 50) == size ? count : nil
+    }
+
+    /// Whether a file is a binary STL larger than the threshold
+    static func shouldStream(url: URL, threshold: Int64 = automaticThreshold) -> Bool {
+        let attributes = try? FileManager.default.attributesOfItem(atPath: url.path)
+        guard let size = (attributes?[.size] as? NSNumber)?.int64Value, size > threshold else { return false }
+        return binaryTriangleCount(url: url) != nil
+    }
+
+    // MARK: - Building
+
+    /// Index a binary STL into chunks and build its proxy
+    /// - Parameters:
+    ///   - chunkFileURL: Where to write the chunk file (removed when the model is released)
+    ///   - trianglesPerChunk: Target chunk size
+    ///   - proxyResolution: Vertex clusters of the proxy along the longest axis
+    ///   - job: Job to report progress to and check for cancellation
+    static func build(
+        url: URL,
+        chunkFileURL: URL,
+        trianglesPerChunk: Int = StreamingModel.trianglesPerChunk,
+        proxyResolution: Int = StreamingModel.proxyResolution,
+        job: JobContext? = nil
+    ) throws -> StreamingModel {
+        guard let count = binaryTriangleCount(url: url) else { throw StreamingError.notBinarySTL }
+        guard count > 0 else { throw StreamingError.emptyModel }
+
+        let data = try Data(contentsOf: url, options: .alwaysMapped)
+        do {
+            return try data.withUnsafeBytes { buffer in
+                guard let base = buffer.baseAddress else { throw StreamingError.notBinarySTL }
+                let records = TriangleRecords(base: base, count: count)
+
+                job?.reportProgress(0, "Measuring \(url.lastPathComponent)...")
+                guard let bounds = try measureBounds(records, job: job) else { throw StreamingError.emptyModel }
+
+                let chunkGrid = ChunkGrid(bounds: bounds, cellCount: min(maxChunks, max(1, count / trianglesPerChunk * 4)))
+                let longest = max(bounds.size.x, bounds.size.y, bounds.size.z, 1e-9)
+                let proxyGrid = ChunkGrid(bounds: bounds, cellSize: longest / Double(max(proxyResolution, 1)))
+
+                job?.reportProgress(0.2, "Building proxy of \(url.lastPathComponent)...")
+                let scan = try scanChunks(records, chunkGrid: chunkGrid, proxyGrid: proxyGrid, job: job)
+                let (chunks, proxy) = makeChunks(scan, cellCount: chunkGrid.cellCount, name: ModelFileLoader.modelName(of: url))
+
+                job?.reportProgress(0.7, "Writing chunks of \(url.lastPathComponent)...")
+                try writeChunkFile(records, to: chunkFileURL, chunks: chunks, grid: chunkGrid, job: job)
+
+                return StreamingModel(
+                    sourceURL: url,
+                    chunkFileURL: chunkFileURL,
+                    triangleCount: chunks.reduce(0) { $0 + $1.triangleCount },
+                    bounds: bounds,
+                    chunks: chunks,
+                    proxy: proxy
+                )
+            }
+        } catch {
+            try? FileManager.default.removeItem(at: chunkFileURL)
+            throw error
+        }
+    }
+
+    /// Pass 1: bounds of all valid triangles (nil if there are none)
+    private static func measureBounds(_ records: TriangleRecords, job: JobContext?) throws -> BoundingBox? {
+        let workers = ProcessInfo.processInfo.activeProcessorCount
+        let partial = ParallelArray([(min: SIMD3<Double>, max: SIMD3<Double>)](repeating: (SIMD3(repeating: .infinity), SIMD3(repeating: -.infinity)), count: workers))
+        let progress = ScanProgress(job: job, total: records.count, start: 0, span: 0.2)
+
+        DispatchQueue.concurrentPerform(iterations: workers) { worker in
+            var low = SIMD3<Double>(repeating: .infinity)
+            var high = SIMD3<Double>(repeating: -.infinity)
+            for block in records.blocks(of: worker, workers: workers, size: blockSize) {
+                guard job?.isCancelled != true else { return }
+                for index in block {
+                    guard let (a, b, c) = records.vertices(index) else { continue }
+                    low = simd_min(low, simd_min(a, simd_min(b, c)))
+                    high = simd_max(high, simd_max(a, simd_max(b, c)))
+                }
+                progress.advance(by: block.count)
+            }
+            partial[worker] = (low, high)
+        }
+        try job?.checkCancellation()
+
+        let low = partial.storage.map(\.min).reduce(SIMD3(repeating: .infinity)) { simd_min($0, $1) }
+        let high = partial.storage.map(\.max).reduce(SIMD3(repeating: -.infinity)) { simd_max($0, $1) }
+        guard low.x <= high.x else { return nil }
+        return BoundingBox(min: Vector3(value: low), max: Vector3(value: high))
+    }
+
+    /// Pass 2: triangles and bounds per chunk, vertex clusters and proxy faces
+    private static func scanChunks(_ records: TriangleRecords, chunkGrid: ChunkGrid, proxyGrid: ChunkGrid, job: JobContext?) throws -> ChunkScan {
+        let workers = ProcessInfo.processInfo.activeProcessorCount
+        let partial = ParallelArray([ChunkScan](repeating: ChunkScan(cellCount: chunkGrid.cellCount), count: workers))
+        let progress = ScanProgress(job: job, total: records.count, start: 0.2, span: 0.5)
+
+        DispatchQueue.concurrentPerform(iterations: workers) { worker in
+            var scan = ChunkScan(cellCount: chunkGrid.cellCount)
+            for block in records.blocks(of: worker, workers: workers, size: blockSize) {
+                guard job?.isCancelled != true else { return }
+                for index in block {
+                    guard let (a, b, c) = records.vertices(index) else { continue }
+                    let cell = chunkGrid.cell(of: (a + b + c) / 3)
+                    scan.counts[cell] += 1
+                    scan.minimum[cell] = simd_min(scan.minimum[cell], simd_min(a, simd_min(b, c)))
+                    scan.maximum[cell] = simd_max(scan.maximum[cell], simd_max(a, simd_max(b, c)))
+
+                    // Vertex clustering: corners snap to the mean of their cluster, collapsed faces drop out
+                    let keys = SIMD3(proxyGrid.cell(of: a), proxyGrid.cell(of: b), proxyGrid.cell(of: c))
+                    scan.clusters[keys.x, default: .zero] += SIMD4(a, 1)
+                    scan.clusters[keys.y, default: .zero] += SIMD4(b, 1)
+                    scan.clusters[keys.z, default: .zero] += SIMD4(c, 1)
+                    if keys.x != keys.y && keys.y != keys.z && keys.x != keys.z {
+                        let face = ChunkScan.canonical(keys)
+                        if scan.faces[face] == nil {
+                            scan.faces[face] = cell
+                        }
+                    }
+                }
+                progress.advance(by: block.count)
+            }
+            partial[worker] = scan
+        }
+        try job?.checkCancellation()
+
+        var merged = partial.storage[0]
+        for scan in partial.storage.dropFirst() {
+            merged.merge(scan)
+        }
+        return merged
+    }
+
+    /// Chunk table and proxy model from the scan (chunks in cell order, proxy triangles grouped by chunk)
+    private static func makeChunks(_ scan: ChunkScan, cellCount: Int, name: String) -> ([StreamingChunk], STLModel) {
+        var chunkOfCell = [Int](repeating: -1, count: cellCount)
+        var cells: [Int] = []
+        for cell in 0..<cellCount where scan.counts[cell] > 0 {
+            chunkOfCell[cell] = cells.count
+            cells.append(cell)
+        }
+
+        let means = scan.clusters.mapValues { sum in Vector3(value: SIMD3(sum.x, sum.y, sum.z) / sum.w) }
+        var proxyTriangles = [[Triangle]](repeating: [], count: cells.count)
+        for (face, cell) in scan.faces {
+            guard let a = means[face.x], let b = means[face.y], let c = means[face.z] else { continue }
+            proxyTriangles[chunkOfCell[cell]].append(Triangle(v1: a, v2: b, v3: c))
+        }
+
+        var chunks: [StreamingChunk] = []
+        var offset = 0
+        var proxyStart = 0
+        for (index, cell) in cells.enumerated() {
+            let proxyEnd = proxyStart + proxyTriangles[index].count
+            chunks.append(StreamingChunk(
+                cell: cell,
+                bounds: BoundingBox(min: Vector3(value: scan.minimum[cell]), max: Vector3(value: scan.maximum[cell])),
+                offset: offset,
+                triangleCount: scan.counts[cell],
+                proxyRange: proxyStart..<proxyEnd
+            ))
+            offset += scan.counts[cell]
+            proxyStart = proxyEnd
+        }
+
+        var bounds = chunks.first?.bounds ?? BoundingBox()
+        chunks.forEach { bounds.extend($0.bounds) }
+        let proxy = STLModel(triangles: proxyTriangles.flatMap { $0 }, name: name, precomputedBounds: bounds)
+        return (chunks, proxy)
+    }
+
+    /// Pass 3: copy every triangle to its chunk's range of the chunk file
+    private static func writeChunkFile(_ records: TriangleRecords, to url: URL, chunks: [StreamingChunk], grid: ChunkGrid, job: JobContext?) throws {
+        var chunkOfCell = [Int](repeating: -1, count: grid.cellCount)
+        for (index, chunk) in chunks.enumerated() {
+            chunkOfCell[chunk.cell] = index
+        }
+
+        guard FileManager.default.createFile(atPath: url.path, contents: nil) else { throw StreamingError.chunkFileDamaged }
+        let handle = try FileHandle(forWritingTo: url)
+        defer { try? handle.close() }
+
+        var cursors = chunks.map(\.offset)
+        var batches = [[UInt8]](repeating: [], count: chunks.count)
+        func flush(_ chunk: Int) throws {
+            guard !batches[chunk].isEmpty else { return }
+            try handle.seek(toOffset: UInt64(cursors[chunk] * recordSize))
+            try handle.write(contentsOf: batches[chunk])
+            cursors[chunk] += batches[chunk].count / recordSize
+            batches[chunk].removeAll(keepingCapacity: true)
+        }
+
+        let progress = ScanProgress(job: job, total: records.count, start: 0.7, span: 0.3)
+        for block in records.blocks(of: 0, workers: 1, size: blockSize) {
+            try job?.checkCancellation()
+            for index in block {
+                guard let (a, b, c) = records.vertices(index) else { continue }
+                let chunk = chunkOfCell[grid.cell(of: (a + b + c) / 3)]
+                // The vertices as stored in the STL (the chunk file uses the same Float32 layout)
+                batches[chunk].append(contentsOf: records.vertexBytes(index))
+                if batches[chunk].count >= writeBatch * recordSize {
+                    try flush(chunk)
+                }
+            }
+            progress.advance(by: block.count)
+        }
+        for chunk in chunks.indices {
+            try flush(chunk)
+        }
+    }
+
+    // MARK: - Reading
+
+    /// Full-resolution triangles of a chunk (file coordinates)
+    func triangles(of chunk: StreamingChunk) throws -> [Triangle] {
+        let handle = try FileHandle(forReadingFrom: chunkFileURL)
+        defer { try? handle.close() }
+        try handle.seek(toOffset: UInt64(chunk.offset * Self.recordSize))
+        let length = chunk.triangleCount * Self.recordSize
+        guard let data = try handle.read(upToCount: length), data.count == length else {
+            throw StreamingError.chunkFileDamaged
+        }
+
+        return data.withUnsafeBytes { buffer in
+            func vertex(_ index: Int) -> Vector3 {
+                let offset = index * 12
+                return Vector3(
+                    Double(buffer.loadUnaligned(fromByteOffset: offset, as: Float.self)),
+                    Double(buffer.loadUnaligned(fromByteOffset: offset + 4, as: Float.self)),
+                    Double(buffer.loadUnaligned(fromByteOffset: offset + 8, as: Float.self))
+                )
+            }
+            return (0..<chunk.triangleCount).map { index in
+                Triangle(v1: vertex(index * 3), v2: vertex(index * 3 + 1), v3: vertex(index * 3 + 2))
+            }
+        }
+    }
+}
+
+/// Vertices of the triangles of a memory-mapped binary STL
+private struct TriangleRecords: @unchecked Sendable {
+    let base: UnsafeRawPointer
+    let count: Int
+
+    /// Corners of a triangle, nil if a coordinate is not finite
+    @inline(__always)
+    func vertices(_ index: Int) -> (SIMD3<Double>, SIMD3<Double>, SIMD3<Double>)? {
+        // 12 bytes normal, 3 × 12 bytes vertices, 2 bytes attribute
+        let start = 84 + index * 50 + 12
+        func vertex(_ offset: Int) -> SIMD3<Double> {
+            SIMD3(
+                Double(base.loadUnaligned(fromByteOffset: offset, as: Float.self)),
+                Double(base.loadUnaligned(fromByteOffset: offset + 4, as: Float.self)),
+                Double(base.loadUnaligned(fromByteOffset: offset + 8, as: Float.self))
+            )
+        }
+        let a = vertex(start), b = vertex(start + 12), c = vertex(start + 24)
+        let sum = a + b + c
+        guard sum.x.isFinite && sum.y.isFinite && sum.z.isFinite else { return nil }
+        return (a, b, c)
+    }
+
+    /// The three vertices of a triangle as stored (nine little-endian Float32)
+    func vertexBytes(_ index: Int) -> UnsafeRawBufferPointer {
+        UnsafeRawBufferPointer(start: base + 84 + index * 50 + 12, count: 36)
+    }
+
+    /// A worker's share of the triangles in blocks
+    func blocks(of worker: Int, workers: Int, size: Int) -> [Range<Int>] {
+        let share = (count + workers - 1) / workers
+        let start = min(worker * share, count)
+        let end = min(start + share, count)
+        return stride(from: start, to: end, by: size).map { $0..<min($0 + size, end) }
+    }
+}
+
+/// Per-chunk counts and bounds, vertex clusters and proxy faces of a range of triangles
+private struct ChunkScan {
+    var counts: [Int]
+    var minimum: [SIMD3<Double>]
+    var maximum: [SIMD3<Double>]
+    /// Sum of the positions and number of corners per proxy cluster
+    var clusters: [Int: SIMD4<Double>] = [:]
+    /// Proxy faces (three clusters) and the chunk cell of the first triangle that made them
+    var faces: [SIMD3<Int>: Int] = [:]
+
+    init(cellCount: Int) {
+        counts = [Int](repeating: 0, count: cellCount)
+        minimum = [SIMD3<Double>](repeating: SIMD3(repeating: .infinity), count: cellCount)
+        maximum = [SIMD3<Double>](repeating: SIMD3(repeating: -.infinity), count: cellCount)
+    }
+
+    /// The same face regardless of the starting corner (the orientation is kept)
+    static func canonical(_ keys: SIMD3<Int>) -> SIMD3<Int> {
+        if keys.x < keys.y && keys.x < keys.z { return keys }
+        if keys.y < keys.z { return SIMD3(keys.y, keys.z, keys.x) }
+        return SIMD3(keys.z, keys.x, keys.y)
+    }
+
+    mutating func merge(_ other: ChunkScan) {
+        for cell in counts.indices where other.counts[cell] > 0 {
+            counts[cell] += other.counts[cell]
+            minimum[cell] = simd_min(minimum[cell], other.minimum[cell])
+            maximum[cell] = simd_max(maximum[cell], other.maximum[cell])
+        }
+        clusters.merge(other.clusters, uniquingKeysWith: +)
+        faces.merge(other.faces) { first, _ in first }
+    }
+}
+
+/// Triangles scanned in one pass, counted from the worker threads
+private final class ScanProgress: @unchecked Sendable {
+    private let lock = NSLock()
+    private let job: JobContext?
+    private let total: Int
+    /// Share of the whole build before this pass and of this pass
+    private let start: Double
+    private let span: Double
+    private var done = 0
+
+    init(job: JobContext?, total: Int, start: Double, span: Double) {
+        self.job = job
+        self.total = max(total, 1)
+        self.start = start
+        self.span = span
+    }
+
+    func advance(by count: Int) {
+        lock.lock()
+        done += count
+        let fraction = start + span * Double(done) / Double(total)
+        lock.unlock()
+        job?.reportProgress(fraction)
+    }
+}
@@ -0,0 +1,291 @@
+import CoreGraphics
+import Foundation
+
+/// Standard 2D views of a model with hidden-line removal and overall dimensions, laid out on an A4 sheet
+///
+/// Views follow third-angle projection: the top view above the front view, the right view to its right
+/// and the isometric view in the remaining corner. All views share one standard scale.
+struct TechnicalDrawing {
+    enum View: String, CaseIterable {
+        case front, top, right, isometric
+
+        var title: String {
+            rawValue.uppercased()
+        }
+
+        /// Direction the viewer looks in (model Z up)
+        var direction: Vector3 {
+            switch self {
+            case .front: return Vector3(0, 1, 0)
+            case .top: return Vector3(0, 0, -1)
+            case .right: return Vector3(-1, 0, 0)
+            case .isometric: return Vector3(-1, 1, -1).normalized()
+            }
+        }
+
+        /// Horizontal and vertical axes of the view plane
+        var axes: (right: Vector3, up: Vector3) {
+            if self == .top {
+                return (Vector3(1, 0, 0), Vector3(0, 1, 0))
+            }
+            let right = direction.cross(Vector3(0, 0, 1)).normalized()
+            return (right, right.cross(direction))
+        }
+
+        /// View-plane coordinates of a point in model units (y up)
+        func project(_ point: Vector3) -> CGPoint {
+            let axes = self.axes
+            return CGPoint(x: point.dot(axes.right), y: point.dot(axes.up))
+        }
+    }
+
+    /// A view of the model in view-plane coordinates (model units, y up)
+    struct ProjectedView {
+        let view: View
+        /// Visible edge segments
+        let segments: [(CGPoint, CGPoint)]
+        /// Extent of the model in the view
+        let bounds: CGRect
+    }
+
+    /// A4 landscape in millimeters
+    static let sheetSize = CGSize(width: 297, height: 210)
+
+    /// Scales tried from largest to smallest when fitting the views on the sheet
+    static let standardScales: [Double] = [20, 10, 5, 2, 1, 1.0 / 2, 1.0 / 5, 1.0 / 10, 1.0 / 20, 1.0 / 50, 1.0 / 100]
+
+    // Layout in millimeters
+    private static let frameMargin = 10.0
+    private static let titleBlockSize = CGSize(width: 110, height: 16)
+    /// Drawing area inside the frame, above the title block
+    private static let drawingArea = CGRect(x: 15, y: 15, width: 267, height: 164)
+    /// Space between views, and for the dimensions left of and below the views
+    private static let viewGap = 20.0
+    private static let dimensionSpace = 15.0
+    private static let dimensionOffset = 8.0
+
+    private static let visibleLineWeight = 0.35
+    private static let thinLineWeight = 0.18
+    private static let frameLineWeight = 0.5
+    private static let dimensionTextSize = 3.0
+
+    let title: String
+    /// Overall model dimensions (bounding box size)
+    let dimensions: Vector3
+    let views: [ProjectedView]
+    /// Sheet millimeters per model unit
+    let scale: Double
+
+    /// Project the model into the requested views
+    /// - Parameters:
+    ///   - model: The mesh
+    ///   - title: Drawing title (e.g. the file name)
+    ///   - views: Views to draw
+    ///   - scale: Drawing scale (nil = the largest standard scale that fits)
+    init(model: STLModel, title: String, views: [View] = View.allCases, scale: Double? = nil) {
+        let box = model.boundingBox()
+        let outline = OutlineEdges(model: model)
+        let accelerator = SpatialAccelerator(triangles: model.triangles)
+
+        self.title = title
+        self.dimensions = box.size
+        self.views = View.allCases
+            .filter { views.contains($0) }
+            .map { Self.project(model, view: $0, outline: outline, accelerator: accelerator, depth: box.diagonal * 2 + 1) }
+        self.scale = scale ?? Self.fittingScale(for: box.size)
+    }
+
+    /// Project the visible edges of a model into a view
+    static func project(
+        _ model: STLModel,
+        view: View,
+        outline: OutlineEdges,
+        accelerator: SpatialAccelerator,
+        depth: Double
+    ) -> ProjectedView {
+        let direction = view.direction
+        let segments = OutlineEdges.project(
+            outline.visibleEdges(viewDirection: direction),
+            sampleSpacing: max(depth / 200, 1e-3),
+            projection: { view.project($0) },
+            isVisible: { OutlineEdges.isVisible($0, along: direction, depth: depth, accelerator: accelerator) }
+        )
+
+        var minX = Double.infinity, minY = Double.infinity
+        var maxX = -Double.infinity, maxY = -Double.infinity
+        for triangle in model.triangles {
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                let point = view.project(vertex)
+                minX = min(minX, point.x)
+                minY = min(minY, point.y)
+                maxX = max(maxX, point.x)
+                maxY = max(maxY, point.y)
+            }
+        }
+        let bounds = minX <= maxX ? CGRect(x: minX, y: minY, width: maxX - minX, height: maxY - minY) : .zero
+        return ProjectedView(view: view, segments: segments, bounds: bounds)
+    }
+
+    // MARK: - Scale
+
+    /// Largest standard scale at which the front, top and right views fit on the sheet
+    static func fittingScale(for size: Vector3) -> Double {
+        let area = drawingArea
+        let horizontal = size.x + size.y
+        let vertical = size.y + size.z
+        guard horizontal > 0 || vertical > 0 else { return 1 }
+
+        let available = min(
+            horizontal > 0 ? (area.width - dimensionSpace - viewGap) / horizontal : .infinity,
+            vertical > 0 ? (area.height - dimensionSpace - viewGap) / vertical : .infinity
+        )
+        return standardScales.first { $0 <= available } ?? available
+    }
+
+    /// "2:1", "1:1", "1:5"
+    static func scaleLabel(_ scale: Double) -> String {
+        if scale >= 1 {
+            return "\(SVGExporter.format(scale)):1"
+        }
+        return "1:\(SVGExporter.format(1 / scale))"
+    }
+
+    /// Parse "1:2", "2:1" or a plain factor ("0.5")
+    static func parseScale(_ text: String) -> Double? {
+        let parts = text.split(separator: ":").map { Double($0.trimmingCharacters(in: .whitespaces)) }
+        switch parts.count {
+        case 1:
+            guard let factor = parts[0], factor > 0 else { return nil }
+            return factor
+        case 2:
+            guard let sheet = parts[0], let model = parts[1], sheet > 0, model > 0 else { return nil }
+            return sheet / model
+        default:
+            return nil
+        }
+    }
+
+    // MARK: - Sheet
+
+    /// Lay out the views, dimensions and title block
+    func sheet() -> DrawingSheet {
+        var sheet = DrawingSheet(size: Self.sheetSize)
+        let area = Self.drawingArea
+        let width = dimensions.x * scale
+        let depth = dimensions.y * scale
+        let height = dimensions.z * scale
+
+        // Block of front, top and right views with room for the dimensions, centered in the drawing area
+        let blockWidth = Self.dimensionSpace + width + Self.viewGap + depth
+        let blockHeight = depth + Self.viewGap + height + Self.dimensionSpace
+        let originX = area.minX + max((area.width - blockWidth) / 2, 0)
+        let originY = area.minY + max((area.height - blockHeight) / 2, 0)
+
+        let front = CGRect(x: originX + Self.dimensionSpace, y: originY + depth + Self.viewGap, width: width, height: height)
+        let top = CGRect(x: front.minX, y: originY, width: width, height: depth)
+        let right = CGRect(x: front.maxX + Self.viewGap, y: front.minY, width: depth, height: height)
+        let isometricCell = CGRect(x: right.minX, y: area.minY, width: area.maxX - right.minX, height: front.minY - Self.viewGap / 2 - area.minY)
+
+        for projected in views {
+            switch projected.view {
+            case .front:
+                draw(projected, in: front, scale: scale, on: &sheet)
+                Self.horizontalDimension(dimensions.x, from: front.minX, to: front.maxX, edge: front.maxY, on: &sheet)
+                Self.verticalDimension(dimensions.z, from: front.maxY, to: front.minY, edge: front.minX, on: &sheet)
+            case .top:
+                draw(projected, in: top, scale: scale, on: &sheet)
+            case .right:
+                draw(projected, in: right, scale: scale, on: &sheet)
+                Self.horizontalDimension(dimensions.y, from: right.minX, to: right.maxX, edge: right.maxY, on: &sheet)
+            case .isometric:
+                // Shrunk to its corner if needed, never enlarged beyond the sheet scale
+                let bounds = projected.bounds
+                let fit = min(
+                    bounds.width > 0 ? isometricCell.width * 0.9 / bounds.width : .infinity,
+                    bounds.height > 0 ? isometricCell.height * 0.8 / bounds.height : .infinity
+                )
+                let isometricScale = min(scale, fit)
+                let size = CGSize(width: bounds.width * isometricScale, height: bounds.height * isometricScale)
+                let rect = CGRect(
+                    x: isometricCell.midX - size.width / 2,
+                    y: isometricCell.midY - size.height / 2,
+                    width: size.width,
+                    height: size.height
+                )
+                draw(projected, in: rect, scale: isometricScale, on: &sheet)
+            }
+        }
+
+        drawFrame(on: &sheet)
+        return sheet
+    }
+
+    /// Draw a view's edges so its bounds fill the rectangle, with the view name above
+    private func draw(_ projected: ProjectedView, in rect: CGRect, scale: Double, on sheet: inout DrawingSheet) {
+        let bounds = projected.bounds
+        let map = { (point: CGPoint) in
+            CGPoint(x: rect.minX + (point.x - bounds.minX) * scale, y: rect.maxY - (point.y - bounds.minY) * scale)
+        }
+        for (start, end) in projected.segments {
+            sheet.line(from: map(start), to: map(end), weight: Self.visibleLineWeight)
+        }
+        sheet.text(projected.view.title, at: CGPoint(x: rect.minX, y: rect.minY - 4), size: 2.5)
+    }
+
+    private func drawFrame(on sheet: inout DrawingSheet) {
+        let margin = Self.frameMargin
+        let size = Self.sheetSize
+        sheet.rectangle(CGRect(x: margin, y: margin, width: size.width - margin * 2, height: size.height - margin * 2), weight: Self.frameLineWeight)
+
+        let block = CGRect(
+            x: size.width - margin - Self.titleBlockSize.width,
+            y: size.height - margin - Self.titleBlockSize.height,
+            width: Self.titleBlockSize.width,
+            height: Self.titleBlockSize.height
+        )
+        sheet.rectangle(block, weight: Self.frameLineWeight)
+        sheet.line(from: CGPoint(x: block.minX, y: block.midY), to: CGPoint(x: block.maxX, y: block.midY), weight: Self.thinLineWeight)
+        sheet.text(title, at: CGPoint(x: block.minX + 3, y: block.minY + 5.8), size: 4)
+        sheet.text("Scale \(Self.scaleLabel(scale))", at: CGPoint(x: block.minX + 3, y: block.maxY - 2.5), size: 2.5)
+        sheet.text("Dimensions in mm", at: CGPoint(x: block.midX, y: block.maxY - 2.5), size: 2.5, anchor: .middle)
+        sheet.text("Third-angle projection", at: CGPoint(x: block.maxX - 3, y: block.maxY - 2.5), size: 2.5, anchor: .end)
+    }
+
+    // MARK: - Dimensions
+
+    /// Dimension below a view, measured between two x positions
+    private static func horizontalDimension(_ value: Double, from x1: Double, to x2: Double, edge: Double, on sheet: inout DrawingSheet) {
+        let y = edge + dimensionOffset
+        sheet.line(from: CGPoint(x: x1, y: edge + 1), to: CGPoint(x: x1, y: y + 1.5), weight: thinLineWeight)
+        sheet.line(from: CGPoint(x: x2, y: edge + 1), to: CGPoint(x: x2, y: y + 1.5), weight: thinLineWeight)
+        dimensionLine(from: CGPoint(x: x1, y: y), to: CGPoint(x: x2, y: y), on: &sheet)
+        sheet.text(SVGExporter.format(value), at: CGPoint(x: (x1 + x2) / 2, y: y - 1), size: dimensionTextSize, anchor: .middle)
+    }
+
+    /// Dimension left of a view, measured between two y positions (text reads bottom to top)
+    private static func verticalDimension(_ value: Double, from y1: Double, to y2: Double, edge: Double, on sheet: inout DrawingSheet) {
+        let x = edge - dimensionOffset
+        sheet.line(from: CGPoint(x: edge - 1, y: y1), to: CGPoint(x: x - 1.5, y: y1), weight: thinLineWeight)
+        sheet.line(from: CGPoint(x: edge - 1, y: y2), to: CGPoint(x: x - 1.5, y: y2), weight: thinLineWeight)
+        dimensionLine(from: CGPoint(x: x, y: y1), to: CGPoint(x: x, y: y2), on: &sheet)
+        sheet.text(SVGExporter.format(value), at: CGPoint(x: x - 1, y: (y1 + y2) / 2), size: dimensionTextSize, anchor: .middle, rotation: 90)
+    }
+
+    /// Dimension line with filled arrowheads pointing at both ends
+    private static func dimensionLine(from start: CGPoint, to end: CGPoint, on sheet: inout DrawingSheet) {
+        sheet.line(from: start, to: end, weight: thinLineWeight)
+
+        let length = hypot(end.x - start.x, end.y - start.y)
+        guard length > 0 else { return }
+        let ux = (end.x - start.x) / length, uy = (end.y - start.y) / length
+        let arrowLength = min(2.5, length / 3), halfWidth = 0.6
+        for (tip, sign) in [(start, 1.0), (end, -1.0)] {
+            let base = CGPoint(x: tip.x + ux * arrowLength * sign, y: tip.y + uy * arrowLength * sign)
+            sheet.polygon([
+                tip,
+                CGPoint(x: base.x - uy * halfWidth, y: base.y + ux * halfWidth),
+                CGPoint(x: base.x + uy * halfWidth, y: base.y - ux * halfWidth)
+            ])
+        }
+    }
+}
@@ -0,0 +1,437 @@
+import Foundation
+import simd
+
+/// Errors that can occur while adding text to a face
+enum TextReliefError: LocalizedError {
+    case emptyText
+    case notFlat
+    case doesNotFit(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .emptyText:
+            return "No text to emboss"
+        case .notFlat:
+            return "The selection is not on a flat face"
+        case .doesNotFit(let text):
+            return "\"\(text)\" does not fit on the face; use smaller letters, a shorter text or move it with an offset"
+        }
+    }
+}
+
+/// Raised or engraved text on a flat face of a model (`gostl emboss`, Tools > Emboss Text)
+///
+/// The text is drawn with `BlockFont`. The face is cut around the text's rectangle, which is rebuilt
+/// as a grid of font pixels raised or sunk by `depth` with walls between them. Edges of the
+/// surrounding triangles are split where the cut meets them, so a closed model stays closed.
+/// Pixels touching only at a corner are bridged, as that contact would not be manifold.
+struct TextRelief {
+    enum Mode: String, CaseIterable {
+        /// Raised text
+        case emboss
+        /// Text cut into the face
+        case engrave
+    }
+
+    var text: String
+    var mode: Mode = .emboss
+    /// Height of raised text or depth of engraved text (mm)
+    var depth: Double = ModelGenerator.reliefDepth
+    /// Height of the letters (mm); nil for the largest text that fits the face
+    var letterHeight: Double?
+    var style: BlockFont.Style = .regular
+    /// Offset of the text's center from the face's center, along the text and up (mm)
+    var offset: SIMD2<Double> = .zero
+    /// Turn of the text on the face (degrees, counter-clockwise seen from outside)
+    var rotation: Double = 0
+
+    /// Share of the face's extent used by fitted text
+    static let fitShare = 0.8
+
+    /// Directions on a face: text runs along `right`, letters stand along `up`, `right × up` points out
+    struct Frame {
+        let origin: Vector3
+        let normal: Vector3
+        let right: Vector3
+        let up: Vector3
+
+        /// Upright text: letters stand along +Z on walls, along +Y on top and bottom faces
+        init(face: ModelAlignment.PlanarFace, triangles: [Triangle], rotation: Double = 0) {
+            normal = face.normal.normalized()
+            var centroid = Vector3.zero
+            var area = 0.0
+            for index in face.triangles {
+                let triangle = triangles[index]
+                centroid = centroid + (triangle.v1 + triangle.v2 + triangle.v3) * (triangle.area() / 3)
+                area += triangle.area()
+            }
+            centroid = area > 0 ? centroid * (1 / area) : triangles[face.triangles[0]].v1
+            // On the plane, not at the average height of a slightly uneven face
+            origin = centroid - normal * (normal.dot(centroid) - face.offset)
+
+            let reference = abs(normal.z) > 0.9 ? Vector3.unitY : Vector3.unitZ
+            let upright = (reference - normal * normal.dot(reference)).normalized()
+            let angle = rotation * .pi / 180
+            let across = normal.cross(upright)
+            up = upright * cos(angle) + across * sin(angle)
+            right = up.cross(normal)
+        }
+
+        func point(_ uv: SIMD2<Double>) -> Vector3 {
+            origin + right * uv.x + up * uv.y
+        }
+
+        func project(_ point: Vector3) -> SIMD2<Double> {
+            let relative = point - origin
+            return SIMD2(relative.dot(right), relative.dot(up))
+        }
+    }
+
+    /// Placement of the text rectangle, one font pixel of plain face around the letters
+    struct Layout {
+        let pixel: Double
+        /// Lower-left corner of the rectangle on the face
+        let corner: SIMD2<Double>
+        /// Cells of the rectangle; the outer ring is the plain margin
+        let columns: Int
+        let rows: Int
+
+        var size: SIMD2<Double> { SIMD2(Double(columns), Double(rows)) * pixel }
+    }
+
+    /// The model with the text on the flat face
+    func apply(to model: STLModel, face: ModelAlignment.PlanarFace) throws -> STLModel {
+        let unsupported = BlockFont.unsupportedCharacters(in: text)
+        guard unsupported.isEmpty else {
+            throw GenerateError.unsupportedCharacters(unsupported)
+        }
+        guard !text.trimmingCharacters(in: .whitespaces).isEmpty else {
+            throw TextReliefError.emptyText
+        }
+        guard !face.triangles.isEmpty else {
+            throw TextReliefError.notFlat
+        }
+
+        let frame = Frame(face: face, triangles: model.triangles, rotation: rotation)
+        let bitmap = Self.bridged(BlockFont.bitmap(text, style: style))
+        let layout = try self.layout(for: bitmap, on: face, in: model.triangles, frame: frame)
+        return Self.build(model, face: face, frame: frame, layout: layout, bitmap: bitmap, lift: mode == .emboss ? depth : -depth)
+    }
+
+    /// Where the text goes, fitted to the face unless the letter height is given
+    func layout(for bitmap: [[Bool]], on face: ModelAlignment.PlanarFace, in triangles: [Triangle], frame: Frame) throws -> Layout {
+        let columns = (bitmap.first?.count ?? 0) + 2
+        let rows = BlockFont.rows + 2
+        let outlines = face.triangles.map { index in
+            [triangles[index].v1, triangles[index].v2, triangles[index].v3].map(frame.project)
+        }
+
+        func placed(pixel: Double) -> Layout {
+            let size = SIMD2(Double(columns), Double(rows)) * pixel
+            return Layout(pixel: pixel, corner: offset - size / 2, columns: columns, rows: rows)
+        }
+
+        if let letterHeight {
+            let candidate = placed(pixel: letterHeight / Double(BlockFont.rows))
+            guard Self.covers(outlines, candidate) else {
+                throw TextReliefError.doesNotFit(text)
+            }
+            return candidate
+        }
+
+        let points = outlines.flatMap { $0 }
+        let low = points.reduce(SIMD2(repeating: Double.infinity)) { simd_min($0, $1) }
+        let high = points.reduce(SIMD2(repeating: -Double.infinity)) { simd_max($0, $1) }
+        var pixel = Swift.min((high.x - low.x) / Double(columns), (high.y - low.y) / Double(rows)) * Self.fitShare
+        while pixel >= BlockFont.minimumPixel {
+            let candidate = placed(pixel: pixel)
+            if Self.covers(outlines, candidate) {
+                return candidate
+            }
+            pixel *= 0.9
+        }
+        throw TextReliefError.doesNotFit(text)
+    }
+
+    /// The bitmap with a pixel added wherever two pixels touch only at a corner
+    static func bridged(_ bitmap: [[Bool]]) -> [[Bool]] {
+        var result = bitmap
+        var changed = true
+        while changed {
+            changed = false
+            for row in 0..<Swift.max(result.count - 1, 0) {
+                for column in 0..<Swift.max(result[row].count - 1, 0) {
+                    let upperLeft = result[row][column], upperRight = result[row][column + 1]
+                    let lowerLeft = result[row + 1][column], lowerRight = result[row + 1][column + 1]
+                    if upperLeft == lowerRight, upperRight == lowerLeft, upperLeft != upperRight {
+                        // Fill the empty lower pixel
+                        result[row + 1][upperLeft ? column : column + 1] = true
+                        changed = true
+                    }
+                }
+            }
+        }
+        return result
+    }
+
+    // MARK: - Geometry
+
+    /// Whether the face covers the text rectangle (checked at every pixel corner and center)
+    static func covers(_ outlines: [[SIMD2<Double>]], _ layout: Layout) -> Bool {
+        let tolerance = 1e-6
+        func inside(_ point: SIMD2<Double>) -> Bool {
+            outlines.contains { outline in
+                let (a, b, c) = (outline[0], outline[1], outline[2])
+                let area = cross(b - a, c - a)
+                guard abs(area) > 1e-12 else { return false }
+                let sign: Double = area > 0 ? 1 : -1
+                return [(a, b), (b, c), (c, a)].allSatisfy { edge in
+                    sign * cross(edge.1 - edge.0, point - edge.0) >= -tolerance * simd_length(edge.1 - edge.0)
+                }
+            }
+        }
+        for row in 0...(layout.rows * 2) {
+            for column in 0...(layout.columns * 2) {
+                if !inside(layout.corner + SIMD2(Double(column), Double(row)) * (layout.pixel / 2)) {
+                    return false
+                }
+            }
+        }
+        return true
+    }
+
+    private static func cross(_ a: SIMD2<Double>, _ b: SIMD2<Double>) -> Double {
+        a.x * b.y - a.y * b.x
+    }
+
+    /// A point of the new mesh, in face coordinates and in space
+    private struct Corner {
+        var uv: SIMD2<Double>
+        var position: Vector3
+    }
+
+    private static func build(_ model: STLModel, face: ModelAlignment.PlanarFace, frame: Frame, layout: Layout, bitmap: [[Bool]], lift: Double) -> STLModel {
+        let low = layout.corner, high = layout.corner + layout.size
+        let tolerance = 1e-6
+
+        // Every new point by its face coordinates, so neighbors share vertices exactly
+        var registry: [SIMD2<Int64>: Vector3] = [:]
+        var seam: [Corner] = []
+        func corner(_ uv: SIMD2<Double>, _ position: @autoclosure () -> Vector3) -> Corner {
+            let key = SIMD2(Int64((uv.x / tolerance).rounded()), Int64((uv.y / tolerance).rounded()))
+            if let existing = registry[key] {
+                return Corner(uv: uv, position: existing)
+            }
+            let new = position()
+            registry[key] = new
+            return Corner(uv: uv, position: new)
+        }
+        func onBoundary(_ uv: SIMD2<Double>) -> Bool {
+            let inRange = uv.x >= low.x - tolerance && uv.x <= high.x + tolerance && uv.y >= low.y - tolerance && uv.y <= high.y + tolerance
+            return inRange && (abs(uv.x - low.x) <= tolerance || abs(uv.x - high.x) <= tolerance
+                || abs(uv.y - low.y) <= tolerance || abs(uv.y - high.y) <= tolerance)
+        }
+
+        // Face triangles that reach the rectangle are cut; their vertices keep their positions
+        let clipped = Set(face.triangles.filter { index in
+            let uvs = [model.triangles[index].v1, model.triangles[index].v2, model.triangles[index].v3].map(frame.project)
+            let lower = uvs.reduce(SIMD2(repeating: Double.infinity)) { simd_min($0, $1) }
+            let upper = uvs.reduce(SIMD2(repeating: -Double.infinity)) { simd_max($0, $1) }
+            return lower.x <= high.x + tolerance && upper.x >= low.x - tolerance && lower.y <= high.y + tolerance && upper.y >= low.y - tolerance
+        })
+        for index in clipped.sorted() {
+            let triangle = model.triangles[index]
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                let point = corner(frame.project(vertex), vertex)
+                if onBoundary(point.uv) {
+                    seam.append(point)
+                }
+            }
+        }
+
+        // Grid of font pixels on the face plane
+        let grid = (0...layout.rows).map { row in
+            (0...layout.columns).map { column in
+                corner(layout.corner + SIMD2(Double(column), Double(row)) * layout.pixel,
+                       frame.point(layout.corner + SIMD2(Double(column), Double(row)) * layout.pixel))
+            }
+        }
+        for row in 0...layout.rows {
+            for column in 0...layout.columns where row == 0 || row == layout.rows || column == 0 || column == layout.columns {
+                seam.append(grid[row][column])
+            }
+        }
+
+        // The parts of the cut triangles around the rectangle, as convex polygons
+        func clip(_ polygon: [Corner], axis: Int, bound: Double, below: Bool) -> [Corner] {
+            func inside(_ point: Corner) -> Bool {
+                below ? point.uv[axis] <= bound : point.uv[axis] >= bound
+            }
+            var result: [Corner] = []
+            for (index, current) in polygon.enumerated() {
+                let next = polygon[(index + 1) % polygon.count]
+                if inside(current) {
+                    result.append(current)
+                }
+                if inside(current) != inside(next), abs(current.uv[axis] - bound) > 0, abs(next.uv[axis] - bound) > 0 {
+                    // Same order from both triangles sharing the edge
+                    let (a, b) = (current.uv.x, current.uv.y) < (next.uv.x, next.uv.y) ? (current, next) : (next, current)
+                    let t = (bound - a.uv[axis]) / (b.uv[axis] - a.uv[axis])
+                    var uv = a.uv + (b.uv - a.uv) * t
+                    uv[axis] = bound
+                    let point = corner(uv, a.position + (b.position - a.position) * t)
+                    seam.append(point)
+                    result.append(point)
+                }
+            }
+            return result
+        }
+
+        var pieces: [(polygon: [Corner], source: Triangle)] = []
+        for index in clipped.sorted() {
+            let triangle = model.triangles[index]
+            let polygon = [triangle.v1, triangle.v2, triangle.v3].map { corner(frame.project($0), $0) }
+            let left = clip(polygon, axis: 0, bound: low.x, below: true)
+            let rest = clip(polygon, axis: 0, bound: low.x, below: false)
+            let right = clip(rest, axis: 0, bound: high.x, below: false)
+            let middle = clip(rest, axis: 0, bound: high.x, below: true)
+            let bottom = clip(middle, axis: 1, bound: low.y, below: true)
+            let top = clip(middle, axis: 1, bound: high.y, below: false)
+            for piece in [left, right, bottom, top] where polygonArea(piece.map(\.uv)) > tolerance * tolerance {
+                pieces.append((piece, triangle))
+            }
+        }
+
+        // Points on an edge, in order, so both sides of every edge have the same vertices
+        func inserting(_ polygon: [Corner]) -> [Corner] {
+            var result: [Corner] = []
+            for (index, start) in polygon.enumerated() {
+                let end = polygon[(index + 1) % polygon.count]
+                result.append(start)
+                let direction = end.uv - start.uv
+                let length = simd_length(direction)
+                guard length > tolerance else { continue }
+                let between = seam.compactMap { point -> (Double, Corner)? in
+                    let along = simd_dot(point.uv - start.uv, direction) / length
+                    let off = abs(cross(direction, point.uv - start.uv)) / length
+                    return off <= tolerance && along > tolerance && along < length - tolerance ? (along, point) : nil
+                }
+                var last = start.position
+                for (_, point) in between.sorted(by: { $0.0 < $1.0 }) where point.position != last && point.position != end.position {
+                    result.append(point)
+                    last = point.position
+                }
+            }
+            return result
+        }
+
+        var triangles: [Triangle] = []
+        triangles.reserveCapacity(model.triangles.count + layout.columns * layout.rows * 4)
+
+        // Unchanged triangles, split where a cut triangle next to them gained vertices on their shared edge
+        var splits: [Edge: [Vector3]] = [:]
+        for index in clipped.sorted() {
+            let triangle = model.triangles[index]
+            let vertices = [triangle.v1, triangle.v2, triangle.v3]
+            for side in 0..<3 {
+                let (a, b) = (vertices[side], vertices[(side + 1) % 3])
+                let edge = inserting([corner(frame.project(a), a), corner(frame.project(b), b)]).map(\.position)
+                let inner = Array(edge.dropFirst().prefix { $0 != b })
+                if !inner.isEmpty {
+                    splits[Edge(a, b), default: []] += inner
+                }
+            }
+        }
+        for (index, triangle) in model.triangles.enumerated() where !clipped.contains(index) {
+            let vertices = [triangle.v1, triangle.v2, triangle.v3]
+            var outline: [Vector3] = []
+            for side in 0..<3 {
+                let (a, b) = (vertices[side], vertices[(side + 1) % 3])
+                outline.append(a)
+                if let points = splits[Edge(a, b)] {
+                    outline += Set(points).sorted { $0.distance(to: a) < $1.distance(to: a) }
+                }
+            }
+            if outline.count == 3 {
+                triangles.append(triangle)
+            } else {
+                triangles += fan(outline, facing: Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3), like: triangle)
+            }
+        }
+
+        for (polygon, source) in pieces {
+            triangles += fan(inserting(polygon).map(\.position), facing: frame.normal, like: source)
+        }
+
+        // Font pixels: the margin ring stays on the face, letters move by `lift`
+        let shift = frame.normal * lift
+        func raised(_ column: Int, _ row: Int) -> Bool {
+            guard column > 0, row > 0, column < layout.columns - 1, row < layout.rows - 1 else { return false }
+            return bitmap[layout.rows - 2 - row][column - 1]
+        }
+        for row in 0..<layout.rows {
+            for column in 0..<layout.columns {
+                let cell = [grid[row][column], grid[row][column + 1], grid[row + 1][column + 1], grid[row + 1][column]]
+                guard raised(column, row) else {
+                    let onRing = row == 0 || column == 0 || row == layout.rows - 1 || column == layout.columns - 1
+                    triangles += fan((onRing ? inserting(cell) : cell).map(\.position), facing: frame.normal, like: nil)
+                    continue
+                }
+                triangles += fan(cell.map { $0.position + shift }, facing: frame.normal, like: nil)
+
+                // Walls towards plain neighbors; raised text faces out of the letter, engraved into it
+                let sides = [(0, -1, 0, 1), (1, 0, 1, 2), (0, 1, 2, 3), (-1, 0, 3, 0)]
+                for (dx, dy, first, second) in sides where !raised(column + dx, row + dy) {
+                    let outward = frame.right * Double(dx) + frame.up * Double(dy)
+                    let a = cell[first].position, b = cell[second].position
+                    triangles += fan([a, b, b + shift, a + shift], facing: lift > 0 ? outward : outward * -1, like: nil)
+                }
+            }
+        }
+
+        return STLModel(triangles: triangles, name: model.name, header: model.header)
+    }
+
+    private static func polygonArea(_ points: [SIMD2<Double>]) -> Double {
+        guard points.count >= 3 else { return 0 }
+        var twice = 0.0
+        for (index, point) in points.enumerated() {
+            twice += cross(point, points[(index + 1) % points.count])
+        }
+        return abs(twice) / 2
+    }
+
+    /// Triangles of a convex polygon facing a direction; polygons with points along their edges
+    /// are fanned from their center so no triangle is degenerate
+    private static func fan(_ outline: [Vector3], facing: Vector3, like source: Triangle?) -> [Triangle] {
+        // Without repeated points
+        var points: [Vector3] = []
+        for point in outline where point != points.last {
+            points.append(point)
+        }
+        if points.count > 1, points.first == points.last {
+            points.removeLast()
+        }
+        guard points.count >= 3 else { return [] }
+
+        func facet(_ a: Vector3, _ b: Vector3, _ c: Vector3) -> Triangle {
+            let flipped = (b - a).cross(c - a).dot(facing) < 0
+            return Triangle(v1: a, v2: flipped ? c : b, v3: flipped ? b : c, color: source?.color, attribute: source?.attribute ?? 0)
+        }
+        if points.count == 3 {
+            return [facet(points[0], points[1], points[2])]
+        }
+        if points.count == 4, !hasCollinearCorner(points) {
+            return [facet(points[0], points[1], points[2]), facet(points[0], points[2], points[3])]
+        }
+        let center = points.reduce(Vector3.zero, +) * (1 / Double(points.count))
+        return points.indices.map { facet(center, points[$0], points[($0 + 1) % points.count]) }
+    }
+
+    private static func hasCollinearCorner(_ points: [Vector3]) -> Bool {
+        points.indices.contains { index in
+            let previous = points[(index + points.count - 1) % points.count], next = points[(index + 1) % points.count]
+            return (points[index] - previous).cross(next - points[index]).length < 1e-12
+        }
+    }
+}
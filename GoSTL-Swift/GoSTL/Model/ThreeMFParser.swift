@@ -92,8 +92,8 @@ enum ThreeMFParser {
 
     /// Parse a 3MF file with plate support
     static func parseWithPlates(url: URL) throws -> ThreeMFParseResult {
-        let data = try Data(contentsOf: url)
-        let name = url.deletingPathExtension().lastPathComponent
+        let data = try FileCompression.read(url)
+        let name = ModelFileLoader.modelName(of: url)
         return try parseWithPlates(data: data, name: name)
     }
 
@@ -255,6 +255,7 @@ private let extruderColors: [Int: TriangleColor] = [
 private struct ThreeMFObject {
     let id: Int
     var pid: Int?  // Property ID (extruder/material)
+    var pindex: Int?  // Index into the property group referenced by pid
     var triangles: [Triangle] = []
     var components: [(objectId: Int, path: String?, transform: Transform3D)] = []
 }
@@ -284,6 +285,10 @@ private class ThreeMFXMLParser: NSObject, XMLParserDelegate {
     // External paths to load after main parsing completes (to avoid reentrant parsing)
     private var pendingExternalPaths: Set<String> = []
 
+    // Display colors of <basematerials> and <m:colorgroup> resources by resource ID
+    private var propertyColors: [Int: [TriangleColor]] = [:]
+    private var currentPropertyGroupId: Int?
+
     // Current parsing state
     private var currentObjectId: Int?
     private var vertices: [Vector3] = []
@@ -351,6 +356,9 @@ private class ThreeMFXMLParser: NSObject, XMLParserDelegate {
         let lookupObjectId = parentObjectId ?? objectId
         var effectivePid = obj.pid ?? inheritedPid
 
+        // Display colors of <basematerials>/<colorgroup> resources (e.g. OpenSCAD color()) win over extruder colors
+        let materialColor = propertyColor(pid: effectivePid, index: obj.pindex)
+
         // Check for part-specific extruder from model_settings.config
         if let objectExtruders = partExtruders[lookupObjectId] {
             // First check if this specific part (objectId) has an extruder assigned
@@ -363,7 +371,7 @@ private class ThreeMFXMLParser: NSObject, XMLParserDelegate {
             }
         }
 
-        let color = effectivePid.flatMap { extruderColors[$0] }
+        let color = materialColor ?? effectivePid.flatMap { extruderColors[$0] }
 
         // Add this object's triangles with transform and color applied
         for triangle in obj.triangles {
@@ -429,7 +437,24 @@ private class ThreeMFXMLParser: NSObject, XMLParserDelegate {
             if let idStr = attributeDict["id"], let id = Int(idStr) {
                 currentObjectId = id
                 let pid = attributeDict["pid"].flatMap { Int($0) }
-                objects[id] = ThreeMFObject(id: id, pid: pid)
+                let pindex = attributeDict["pindex"].flatMap { Int($0) }
+                objects[id] = ThreeMFObject(id: id, pid: pid, pindex: pindex)
+            }
+
+        case "basematerials", "colorgroup":
+            currentPropertyGroupId = attributeDict["id"].flatMap { Int($0) }
+            if let id = currentPropertyGroupId {
+                propertyColors[id] = []
+            }
+
+        case "base":
+            if let id = currentPropertyGroupId {
+                propertyColors[id]?.append(Self.color(hex: attributeDict["displaycolor"]) ?? TriangleColor(1, 1, 1))
+            }
+
+        case "color":
+            if let id = currentPropertyGroupId {
+                propertyColors[id]?.append(Self.color(hex: attributeDict["color"]) ?? TriangleColor(1, 1, 1))
             }
 
         case "mesh":
@@ -484,6 +509,8 @@ private class ThreeMFXMLParser: NSObject, XMLParserDelegate {
         switch localName.lowercased() {
         case "object":
             currentObjectId = nil
+        case "basematerials", "colorgroup":
+            currentPropertyGroupId = nil
         case "mesh":
             inMesh = false
         case "vertices":
@@ -527,16 +554,44 @@ private class ThreeMFXMLParser: NSObject, XMLParserDelegate {
             return
         }
 
+        // Triangle properties override the object's (p1 colors the whole triangle)
+        let object = objects[objectId]
+        let pid = attributes["pid"].flatMap { Int($0) } ?? object?.pid
+        let pindex = attributes["p1"].flatMap { Int($0) } ?? object?.pindex
+
         let triangle = Triangle(
             v1: vertices[v1Idx],
             v2: vertices[v2Idx],
             v3: vertices[v3Idx],
-            normal: nil
+            normal: nil,
+            color: propertyColor(pid: pid, index: pindex)
         )
 
         objects[objectId]?.triangles.append(triangle)
     }
 
+    /// Display color of a material or color group entry (nil for other property IDs, e.g. extruders)
+    private func propertyColor(pid: Int?, index: Int?) -> TriangleColor? {
+        guard let pid, let colors = propertyColors[pid], !colors.isEmpty else { return nil }
+        let index = index ?? 0
+        return colors.indices.contains(index) ? colors[index] : colors[0]
+    }
+
+    /// Parse "#RRGGBB" or "#RRGGBBAA"
+    static func color(hex: String?) -> TriangleColor? {
+        guard let hex, hex.hasPrefix("#"), hex.count == 7 || hex.count == 9,
+              let value = UInt32(hex.dropFirst(), radix: 16) else {
+            return nil
+        }
+        let rgba = hex.count == 7 ? value << 8 | 0xFF : value
+        return TriangleColor(
+            Float((rgba >> 24) & 0xFF) / 255,
+            Float((rgba >> 16) & 0xFF) / 255,
+            Float((rgba >> 8) & 0xFF) / 255,
+            Float(rgba & 0xFF) / 255
+        )
+    }
+
     private func parseComponent(attributes: [String: String], parentObjectId: Int) {
         guard let objectIdStr = attributes["objectid"], let objectId = Int(objectIdStr) else {
             return
@@ -804,6 +859,10 @@ private class ExternalModelParser: NSObject, XMLParserDelegate {
     // Objects parsed from this file
     private var objects: [Int: ThreeMFObject] = [:]
 
+    // Display colors of <basematerials> and <m:colorgroup> resources by resource ID
+    private var propertyColors: [Int: [TriangleColor]] = [:]
+    private var currentPropertyGroupId: Int?
+
     // Current parsing state
     private var currentObjectId: Int?
     private var vertices: [Vector3] = []
@@ -842,7 +901,24 @@ private class ExternalModelParser: NSObject, XMLParserDelegate {
             if let idStr = attributeDict["id"], let id = Int(idStr) {
                 currentObjectId = id
                 let pid = attributeDict["pid"].flatMap { Int($0) }
-                objects[id] = ThreeMFObject(id: id, pid: pid)
+                let pindex = attributeDict["pindex"].flatMap { Int($0) }
+                objects[id] = ThreeMFObject(id: id, pid: pid, pindex: pindex)
+            }
+
+        case "basematerials", "colorgroup":
+            currentPropertyGroupId = attributeDict["id"].flatMap { Int($0) }
+            if let id = currentPropertyGroupId {
+                propertyColors[id] = []
+            }
+
+        case "base":
+            if let id = currentPropertyGroupId {
+                propertyColors[id]?.append(Self.color(hex: attributeDict["displaycolor"]) ?? TriangleColor(1, 1, 1))
+            }
+
+        case "color":
+            if let id = currentPropertyGroupId {
+                propertyColors[id]?.append(Self.color(hex: attributeDict["color"]) ?? TriangleColor(1, 1, 1))
             }
 
         case "mesh":
@@ -881,6 +957,8 @@ private class ExternalModelParser: NSObject, XMLParserDelegate {
         switch localName.lowercased() {
         case "object":
             currentObjectId = nil
+        case "basematerials", "colorgroup":
+            currentPropertyGroupId = nil
         case "mesh":
             inMesh = false
         case "vertices":
@@ -920,23 +998,56 @@ private class ExternalModelParser: NSObject, XMLParserDelegate {
             return
         }
 
+        // Triangle properties override the object's (p1 colors the whole triangle)
+        let object = objects[objectId]
+        let pid = attributes["pid"].flatMap { Int($0) } ?? object?.pid
+        let pindex = attributes["p1"].flatMap { Int($0) } ?? object?.pindex
+
         let triangle = Triangle(
             v1: vertices[v1Idx],
             v2: vertices[v2Idx],
             v3: vertices[v3Idx],
-            normal: nil
+            normal: nil,
+            color: propertyColor(pid: pid, index: pindex)
         )
 
         objects[objectId]?.triangles.append(triangle)
     }
+
+    /// Display color of a material or color group entry (nil for other property IDs, e.g. extruders)
+    private func propertyColor(pid: Int?, index: Int?) -> TriangleColor? {
+        guard let pid, let colors = propertyColors[pid], !colors.isEmpty else { return nil }
+        let index = index ?? 0
+        return colors.indices.contains(index) ? colors[index] : colors[0]
+    }
+
+    /// Parse "#RRGGBB" or "#RRGGBBAA"
+    static func color(hex: String?) -> TriangleColor? {
+        guard let hex, hex.hasPrefix("#"), hex.count == 7 || hex.count == 9,
+              let value = UInt32(hex.dropFirst(), radix: 16) else {
+            return nil
+        }
+        let rgba = hex.count == 7 ? value << 8 | 0xFF : value
+        return TriangleColor(
+            Float((rgba >> 24) & 0xFF) / 255,
+            Float((rgba >> 16) & 0xFF) / 255,
+            Float((rgba >> 8) & 0xFF) / 255,
+            Float(rgba & 0xFF) / 255
+        )
+    }
 }
 
 // MARK: - ZIP Archive Reader
 
-private struct ZipArchive {
+struct ZipArchive {
     private let data: Data
     private var entries: [ZipEntry] = []
 
+    /// Paths of all files in the archive, in archive order
+    var fileNames: [String] {
+        entries.map(\.filename)
+    }
+
     struct ZipEntry {
         let filename: String
         let compressedSize: UInt32
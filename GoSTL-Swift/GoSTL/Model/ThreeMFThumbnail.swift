@@ -0,0 +1,105 @@
+import Foundation
+
+/// Reads and writes the package thumbnail of 3MF files
+///
+/// The thumbnail is the PNG referenced by the package relationship of type
+/// `.../metadata/thumbnail` in `_rels/.rels` (usually `Metadata/thumbnail.png`).
+/// File managers and slicers show it as the file preview.
+enum ThreeMFThumbnail {
+    static let relationshipType = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/thumbnail"
+    static let defaultPath = "Metadata/thumbnail.png"
+
+    private static let relationshipsPath = "_rels/.rels"
+    private static let contentTypesPath = "[Content_Types].xml"
+
+    /// Embedded thumbnail of a 3MF package, if any (falls back to the first plate preview of Bambu/Orca projects)
+    static func extract(from data: Data) throws -> Data? {
+        let archive = try ZipArchive(data: data)
+        let relationships = try archive.extractFile(path: relationshipsPath).map { String(decoding: $0, as: UTF8.self) }
+        let candidates = [relationships.flatMap(thumbnailTarget(in:)), defaultPath, "Metadata/plate_1.png"].compactMap { $0 }
+        for path in candidates {
+            if let png = try archive.extractFile(path: path) {
+                return png
+            }
+        }
+        return nil
+    }
+
+    /// The package with the PNG as its thumbnail (replacing an existing one)
+    static func embed(png: Data, into data: Data) throws -> Data {
+        let archive = try ZipArchive(data: data)
+
+        var relationships = try archive.extractFile(path: relationshipsPath).map { String(decoding: $0, as: UTF8.self) }
+            ?? #"<?xml version="1.0" encoding="UTF-8"?>"# + "\n"
+            + #"<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>"#
+        let path: String
+        if let existing = thumbnailTarget(in: relationships) {
+            path = existing
+        } else {
+            path = defaultPath
+            relationships = insert(
+                #"<Relationship Target="/\#(path)" Id="rel-gostl-thumbnail" Type="\#(relationshipType)"/>"#,
+                before: "</Relationships>",
+                in: relationships
+            )
+        }
+
+        var contentTypes = try archive.extractFile(path: contentTypesPath).map { String(decoding: $0, as: UTF8.self) } ?? ""
+        if !contentTypes.isEmpty, !contentTypes.lowercased().contains(#"extension="png""#) {
+            contentTypes = insert(#"<Default Extension="png" ContentType="image/png"/>"#, before: "</Types>", in: contentTypes)
+        }
+
+        var writer = ZipWriter()
+        var written = Set<String>()
+        for name in archive.fileNames where !written.contains(name.lowercased()) {
+            let contents: Data?
+            switch name.lowercased() {
+            case path.lowercased(): contents = png
+            case relationshipsPath.lowercased(): contents = Data(relationships.utf8)
+            case contentTypesPath.lowercased(): contents = Data(contentTypes.utf8)
+            default: contents = try archive.extractFile(path: name)
+            }
+            // Directory entries and unreadable entries are dropped
+            guard let contents, !name.hasSuffix("/") else { continue }
+            writer.add(path: name, contents: contents)
+            written.insert(name.lowercased())
+        }
+        if !written.contains(relationshipsPath.lowercased()) {
+            writer.add(path: relationshipsPath, contents: Data(relationships.utf8))
+        }
+        if !written.contains(path.lowercased()) {
+            writer.add(path: path, contents: png)
+        }
+        return writer.finish()
+    }
+
+    /// Archive path of the thumbnail referenced by a relationships document
+    static func thumbnailTarget(in relationships: String) -> String? {
+        let pattern = try? NSRegularExpression(pattern: #"<Relationship\b[^>]*>"#)
+        let range = NSRange(relationships.startIndex..., in: relationships)
+        for match in pattern?.matches(in: relationships, range: range) ?? [] {
+            guard let elementRange = Range(match.range, in: relationships) else { continue }
+            let element = String(relationships[elementRange])
+            guard attribute("Type", in: element) == relationshipType,
+                  let target = attribute("Target", in: element) else { continue }
+            return target.hasPrefix("/") ? String(target.dropFirst()) : target
+        }
+        return nil
+    }
+
+    private static func attribute(_ name: String, in element: String) -> String? {
+        guard let pattern = try? NSRegularExpression(pattern: "\\b\(name)\\s*=\\s*\"([^\"]*)\""),
+              let match = pattern.firstMatch(in: element, range: NSRange(element.startIndex..., in: element)),
+              let range = Range(match.range(at: 1), in: element) else {
+            return nil
+        }
+        return String(element[range])
+    }
+
+    private static func insert(_ element: String, before closingTag: String, in document: String) -> String {
+        guard let range = document.range(of: closingTag, options: .backwards) else {
+            return document + element
+        }
+        return document.replacingCharacters(in: range.lowerBound..<range.lowerBound, with: element)
+    }
+}
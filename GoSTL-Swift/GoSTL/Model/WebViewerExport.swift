@@ -0,0 +1,303 @@
+import Foundation
+
+/// Self-contained HTML page with an interactive WebGL view of a model (for `gostl share`)
+///
+/// The mesh is embedded as base64 float32 positions, so the page needs nothing but a browser:
+/// no server-side code, no scripts from the internet. Measurements are drawn as lines with their
+/// values, annotations as pins with their text; the side panel lists them with the model's metrics.
+enum WebViewerExport {
+    /// Metrics listed in the side panel
+    static let metricKeys = AnalysisDiff.watchedKeys
+
+    /// The page for a model in file coordinates; measurements and annotations must be in the same coordinates
+    static func html(
+        model: STLModel,
+        title: String,
+        analysis: AnalysisRecord? = nil,
+        measurements: [MeasurementSet.Entry] = [],
+        annotations: [Annotation] = []
+    ) -> String {
+        var panel = "<h1>\(escaped(title))</h1>\n"
+        if let analysis {
+            panel += "<h2>Model</h2>\n<table>\n"
+            for key in metricKeys {
+                guard let field = analysis.fields.first(where: { $0.key == key }) else { continue }
+                panel += "<tr><td>\(escaped(field.label))</td><td class=\"v\">\(escaped(formatted(field)))</td></tr>\n"
+            }
+            panel += "</table>\n"
+        }
+        let shown = measurements.filter { $0.measurement.type != .triangleSelect }
+        if !shown.isEmpty {
+            panel += "<h2>Measurements</h2>\n<table>\n"
+            for entry in shown {
+                panel += "<tr><td>\(escaped(entry.name))</td><td class=\"v\">\(escaped(entry.measurement.formattedValue))</td></tr>\n"
+                if let note = entry.measurement.note, !note.isEmpty {
+                    panel += "<tr><td colspan=\"2\" class=\"note\">\(escaped(note))</td></tr>\n"
+                }
+            }
+            panel += "</table>\n"
+        }
+        if !annotations.isEmpty {
+            panel += "<h2>Annotations</h2>\n<ol>\n"
+            for annotation in annotations {
+                panel += "<li>\(escaped(annotation.text))</li>\n"
+            }
+            panel += "</ol>\n"
+        }
+        panel += "<p class=\"hint\">Drag to rotate, scroll or pinch to zoom, double-click to reset.</p>\n"
+
+        let data: [String: Any] = [
+            "mesh": meshData(model.triangles).base64EncodedString(),
+            "lines": shown.flatMap { entry in
+                zip(entry.measurement.points, entry.measurement.points.dropFirst()).map { a, b in
+                    [a.position.x, a.position.y, a.position.z, b.position.x, b.position.y, b.position.z]
+                }
+            },
+            "labels": shown.map { entry in
+                let position = entry.measurement.labelPosition
+                return ["p": [position.x, position.y, position.z], "t": entry.measurement.formattedValue] as [String: Any]
+            } + annotations.enumerated().map { index, annotation in
+                ["p": [annotation.position.x, annotation.position.y, annotation.position.z], "t": "\(index + 1). \(annotation.text)", "pin": true] as [String: Any]
+            }
+        ]
+        let json = (try? JSONSerialization.data(withJSONObject: data)).map { String(decoding: $0, as: UTF8.self) } ?? "{}"
+
+        return template
+            .replacingOccurrences(of: "__TITLE__", with: escaped(title))
+            .replacingOccurrences(of: "__PANEL__", with: panel)
+            // "</script>" in a note must not end the script element
+            .replacingOccurrences(of: "__DATA__", with: json.replacingOccurrences(of: "</", with: "<\\/"))
+    }
+
+    /// Triangle corners as little-endian float32 x, y, z
+    static func meshData(_ triangles: [Triangle]) -> Data {
+        var data = Data(capacity: triangles.count * 36)
+        for triangle in triangles {
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                for value in [vertex.x, vertex.y, vertex.z] {
+                    withUnsafeBytes(of: Float(value).bitPattern.littleEndian) { data.append(contentsOf: $0) }
+                }
+            }
+        }
+        return data
+    }
+
+    private static func formatted(_ field: AnalysisRecord.Field) -> String {
+        let value: String
+        switch field.value {
+        case .number(let number): value = String(format: "%.2f", number)
+        default: value = field.value.plain
+        }
+        return value + (field.unit.map { " \($0)" } ?? "")
+    }
+
+    static func escaped(_ text: String) -> String {
+        text.replacingOccurrences(of: "&", with: "&amp;")
+            .replacingOccurrences(of: "<", with: "&lt;")
+            .replacingOccurrences(of: ">", with: "&gt;")
+            .replacingOccurrences(of: "\"", with: "&quot;")
+    }
+
+    private static let template = #"""
+        <!DOCTYPE html>
+        <html lang="en">
+        <head>
+        <meta charset="utf-8">
+        <meta name="viewport" content="width=device-width, initial-scale=1">
+        <title>__TITLE__ – GoSTL</title>
+        <style>
+        body { margin: 0; display: flex; height: 100vh; background: #1e1e22; color: #eee;
+               font: 14px -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; }
+        #view { flex: 1; min-width: 0; position: relative; overflow: hidden; }
+        canvas { width: 100%; height: 100%; display: block; touch-action: none; }
+        .label { position: absolute; transform: translate(-50%, -120%); padding: 2px 6px; border-radius: 4px;
+                 background: rgba(255, 149, 0, 0.9); color: #000; font-size: 12px; white-space: nowrap; pointer-events: none; }
+        .label.pin { background: rgba(255, 214, 10, 0.95); }
+        aside { width: 300px; overflow: auto; padding: 12px 16px; box-sizing: border-box; background: #26262b; }
+        h1 { font-size: 16px; margin: 0 0 8px; word-break: break-all; }
+        h2 { font-size: 12px; text-transform: uppercase; color: #999; margin: 16px 0 6px; }
+        table { border-collapse: collapse; width: 100%; }
+        td { padding: 2px 0; vertical-align: top; }
+        td.v { text-align: right; font-variant-numeric: tabular-nums; padding-left: 8px; }
+        td.note, .hint { color: #999; font-size: 12px; }
+        ol { padding-left: 20px; margin: 0; }
+        @media (max-width: 700px) { body { flex-direction: column; } aside { width: auto; height: 40vh; } }
+        </style>
+        </head>
+        <body>
+        <div id="view"><canvas id="canvas"></canvas></div>
+        <aside>
+        __PANEL__</aside>
+        <script>
+        const DATA = __DATA__;
+        const bytes = Uint8Array.from(atob(DATA.mesh), c => c.charCodeAt(0));
+        const positions = new Float32Array(bytes.buffer);
+        const normals = new Float32Array(positions.length);
+        const min = [Infinity, Infinity, Infinity], max = [-Infinity, -Infinity, -Infinity];
+        for (let i = 0; i < positions.length; i += 9) {
+          const p = positions;
+          const ax = p[i + 3] - p[i], ay = p[i + 4] - p[i + 1], az = p[i + 5] - p[i + 2];
+          const bx = p[i + 6] - p[i], by = p[i + 7] - p[i + 1], bz = p[i + 8] - p[i + 2];
+          const nx = ay * bz - az * by, ny = az * bx - ax * bz, nz = ax * by - ay * bx;
+          const l = Math.hypot(nx, ny, nz) || 1;
+          for (let k = 0; k < 9; k += 3) {
+            normals[i + k] = nx / l; normals[i + k + 1] = ny / l; normals[i + k + 2] = nz / l;
+            for (let a = 0; a < 3; a++) {
+              min[a] = Math.min(min[a], p[i + k + a]); max[a] = Math.max(max[a], p[i + k + a]);
+            }
+          }
+        }
+        if (positions.length === 0) { min.fill(0); max.fill(1); }
+        const center = [0, 1, 2].map(a => (min[a] + max[a]) / 2);
+        const radius = Math.hypot(max[0] - min[0], max[1] - min[1], max[2] - min[2]) / 2 || 1;
+        const home = { yaw: -Math.PI / 4, pitch: 0.5, distance: radius * 3 };
+        let camera = { ...home };
+
+        const canvas = document.getElementById("canvas");
+        const gl = canvas.getContext("webgl", { antialias: true });
+        function shader(type, source) {
+          const s = gl.createShader(type); gl.shaderSource(s, source); gl.compileShader(s); return s;
+        }
+        const program = gl.createProgram();
+        gl.attachShader(program, shader(gl.VERTEX_SHADER, `
+          attribute vec3 position; attribute vec3 normal;
+          uniform mat4 projection; uniform mat4 view; varying vec3 viewNormal;
+          void main() {
+            viewNormal = (view * vec4(normal, 0.0)).xyz;
+            gl_Position = projection * view * vec4(position, 1.0);
+            gl_PointSize = 10.0;
+          }`));
+        gl.attachShader(program, shader(gl.FRAGMENT_SHADER, `
+          precision mediump float;
+          varying vec3 viewNormal; uniform vec3 color; uniform float lit;
+          void main() {
+            float light = lit > 0.5 ? 0.3 + 0.7 * abs(normalize(viewNormal).z) : 1.0;
+            gl_FragColor = vec4(color * light, 1.0);
+          }`));
+        gl.linkProgram(program);
+        gl.useProgram(program);
+        const loc = name => gl.getUniformLocation(program, name);
+        const attribute = { position: gl.getAttribLocation(program, "position"), normal: gl.getAttribLocation(program, "normal") };
+
+        function buffer(values) {
+          const b = gl.createBuffer(); gl.bindBuffer(gl.ARRAY_BUFFER, b);
+          gl.bufferData(gl.ARRAY_BUFFER, values, gl.STATIC_DRAW); return b;
+        }
+        const meshBuffer = buffer(positions), normalBuffer = buffer(normals);
+        const lineBuffer = buffer(new Float32Array(DATA.lines.flat()));
+        const pins = DATA.labels.filter(l => l.pin).map(l => l.p);
+        const pinBuffer = buffer(new Float32Array(pins.flat()));
+
+        function perspective(fovy, aspect, near, far) {
+          const f = 1 / Math.tan(fovy / 2), d = near - far;
+          return [f / aspect, 0, 0, 0, 0, f, 0, 0, 0, 0, (far + near) / d, -1, 0, 0, 2 * far * near / d, 0];
+        }
+        function lookAt(eye, target) {
+          const sub = (a, b) => a.map((v, i) => v - b[i]);
+          const norm = v => { const l = Math.hypot(...v) || 1; return v.map(x => x / l); };
+          const cross = (a, b) => [a[1] * b[2] - a[2] * b[1], a[2] * b[0] - a[0] * b[2], a[0] * b[1] - a[1] * b[0]];
+          const dot = (a, b) => a[0] * b[0] + a[1] * b[1] + a[2] * b[2];
+          const z = norm(sub(eye, target)), x = norm(cross([0, 0, 1], z)), y = cross(z, x);
+          return [x[0], y[0], z[0], 0, x[1], y[1], z[1], 0, x[2], y[2], z[2], 0, -dot(x, eye), -dot(y, eye), -dot(z, eye), 1];
+        }
+        function transform(m, p) {
+          const w = p.length > 3 ? p[3] : 1;
+          return [0, 1, 2, 3].map(i => m[i] * p[0] + m[4 + i] * p[1] + m[8 + i] * p[2] + m[12 + i] * w);
+        }
+
+        const view = document.getElementById("view");
+        const labels = DATA.labels.map(l => {
+          const div = document.createElement("div");
+          div.className = l.pin ? "label pin" : "label";
+          div.textContent = l.t;
+          view.appendChild(div);
+          return { p: l.p, div };
+        });
+
+        function draw() {
+          const dpr = window.devicePixelRatio || 1;
+          const width = canvas.clientWidth, height = canvas.clientHeight;
+          if (canvas.width !== width * dpr || canvas.height !== height * dpr) {
+            canvas.width = width * dpr; canvas.height = height * dpr;
+          }
+          gl.viewport(0, 0, canvas.width, canvas.height);
+          gl.clearColor(0.12, 0.12, 0.13, 1);
+          gl.clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT);
+
+          const cp = Math.cos(camera.pitch);
+          const eye = [center[0] + camera.distance * cp * Math.cos(camera.yaw),
+                       center[1] + camera.distance * cp * Math.sin(camera.yaw),
+                       center[2] + camera.distance * Math.sin(camera.pitch)];
+          const viewMatrix = lookAt(eye, center);
+          const projection = perspective(0.8, width / Math.max(height, 1), radius * 0.01, camera.distance + radius * 4);
+          gl.uniformMatrix4fv(loc("projection"), false, projection);
+          gl.uniformMatrix4fv(loc("view"), false, viewMatrix);
+
+          gl.enable(gl.DEPTH_TEST);
+          gl.bindBuffer(gl.ARRAY_BUFFER, meshBuffer);
+          gl.enableVertexAttribArray(attribute.position);
+          gl.vertexAttribPointer(attribute.position, 3, gl.FLOAT, false, 0, 0);
+          gl.bindBuffer(gl.ARRAY_BUFFER, normalBuffer);
+          gl.enableVertexAttribArray(attribute.normal);
+          gl.vertexAttribPointer(attribute.normal, 3, gl.FLOAT, false, 0, 0);
+          gl.uniform3f(loc("color"), 0.55, 0.62, 0.75);
+          gl.uniform1f(loc("lit"), 1);
+          gl.drawArrays(gl.TRIANGLES, 0, positions.length / 3);
+
+          // Measurements and pins stay visible through the model
+          gl.disable(gl.DEPTH_TEST);
+          gl.disableVertexAttribArray(attribute.normal);
+          gl.uniform1f(loc("lit"), 0);
+          gl.bindBuffer(gl.ARRAY_BUFFER, lineBuffer);
+          gl.vertexAttribPointer(attribute.position, 3, gl.FLOAT, false, 0, 0);
+          gl.uniform3f(loc("color"), 1, 0.58, 0);
+          gl.drawArrays(gl.LINES, 0, DATA.lines.length * 2);
+          gl.bindBuffer(gl.ARRAY_BUFFER, pinBuffer);
+          gl.vertexAttribPointer(attribute.position, 3, gl.FLOAT, false, 0, 0);
+          gl.uniform3f(loc("color"), 1, 0.84, 0.04);
+          gl.drawArrays(gl.POINTS, 0, pins.length);
+
+          for (const label of labels) {
+            const clip = transform(projection, transform(viewMatrix, label.p));
+            const visible = clip[3] > 0;
+            label.div.style.display = visible ? "block" : "none";
+            if (visible) {
+              label.div.style.left = ((clip[0] / clip[3] + 1) / 2 * width) + "px";
+              label.div.style.top = ((1 - clip[1] / clip[3]) / 2 * height) + "px";
+            }
+          }
+        }
+
+        const pointers = new Map();
+        canvas.addEventListener("pointerdown", e => { canvas.setPointerCapture(e.pointerId); pointers.set(e.pointerId, e); });
+        canvas.addEventListener("pointerup", e => pointers.delete(e.pointerId));
+        canvas.addEventListener("pointercancel", e => pointers.delete(e.pointerId));
+        canvas.addEventListener("pointermove", e => {
+          const last = pointers.get(e.pointerId);
+          if (!last) return;
+          if (pointers.size === 1) {
+            camera.yaw -= (e.clientX - last.clientX) * 0.01;
+            camera.pitch = Math.max(-1.55, Math.min(1.55, camera.pitch + (e.clientY - last.clientY) * 0.01));
+          } else if (pointers.size === 2) {
+            const other = [...pointers.values()].find(p => p.pointerId !== e.pointerId);
+            const before = Math.hypot(last.clientX - other.clientX, last.clientY - other.clientY);
+            const after = Math.hypot(e.clientX - other.clientX, e.clientY - other.clientY);
+            if (after > 0) camera.distance *= before / after;
+          }
+          pointers.set(e.pointerId, e);
+          requestAnimationFrame(draw);
+        });
+        canvas.addEventListener("wheel", e => {
+          e.preventDefault();
+          camera.distance *= Math.exp(e.deltaY * 0.001);
+          requestAnimationFrame(draw);
+        }, { passive: false });
+        canvas.addEventListener("dblclick", () => { camera = { ...home }; requestAnimationFrame(draw); });
+        window.addEventListener("resize", () => requestAnimationFrame(draw));
+        draw();
+        </script>
+        </body>
+        </html>
+
+        """#
+}
@@ -0,0 +1,122 @@
+import Compression
+import Foundation
+
+/// Writes ZIP archives (deflate-compressed entries), e.g. to rewrite 3MF packages
+struct ZipWriter {
+    private struct CentralEntry {
+        let name: Data
+        let method: UInt16
+        let crc: UInt32
+        let compressedSize: UInt32
+        let uncompressedSize: UInt32
+        let localHeaderOffset: UInt32
+    }
+
+    private(set) var data = Data()
+    private var entries: [CentralEntry] = []
+
+    /// Add a file; it is stored uncompressed if deflate does not make it smaller
+    mutating func add(path: String, contents: Data) {
+        let compressed = Self.deflate(contents)
+        let method: UInt16 = compressed == nil ? 0 : 8
+        let payload = compressed ?? contents
+        let name = Data(path.utf8)
+        let crc = Self.crc32(contents)
+
+        let entry = CentralEntry(
+            name: name,
+            method: method,
+            crc: crc,
+            compressedSize: UInt32(payload.count),
+            uncompressedSize: UInt32(contents.count),
+            localHeaderOffset: UInt32(data.count)
+        )
+
+        append(UInt32(0x04034B50))
+        append(UInt16(20))              // Version needed to extract
+        append(UInt16(0x0800))          // UTF-8 file names
+        append(method)
+        append(UInt16(0))               // Modification time
+        append(UInt16(0x21))            // Modification date (1980-01-01)
+        append(crc)
+        append(entry.compressedSize)
+        append(entry.uncompressedSize)
+        append(UInt16(name.count))
+        append(UInt16(0))               // Extra field length
+        data.append(name)
+        data.append(payload)
+
+        entries.append(entry)
+    }
+
+    /// Append the central directory and return the archive
+    mutating func finish() -> Data {
+        let directoryOffset = UInt32(data.count)
+        for entry in entries {
+            append(UInt32(0x02014B50))
+            append(UInt16(20))          // Version made by
+            append(UInt16(20))          // Version needed to extract
+            append(UInt16(0x0800))
+            append(entry.method)
+            append(UInt16(0))
+            append(UInt16(0x21))
+            append(entry.crc)
+            append(entry.compressedSize)
+            append(entry.uncompressedSize)
+            append(UInt16(entry.name.count))
+            append(UInt16(0))           // Extra field length
+            append(UInt16(0))           // Comment length
+            append(UInt16(0))           // Disk number
+            append(UInt16(0))           // Internal attributes
+            append(UInt32(0))           // External attributes
+            append(entry.localHeaderOffset)
+            data.append(entry.name)
+        }
+        let directorySize = UInt32(data.count) - directoryOffset
+
+        append(UInt32(0x06054B50))
+        append(UInt16(0))
+        append(UInt16(0))
+        append(UInt16(entries.count))
+        append(UInt16(entries.count))
+        append(directorySize)
+        append(directoryOffset)
+        append(UInt16(0))               // Comment length
+        return data
+    }
+
+    private mutating func append<T: FixedWidthInteger>(_ value: T) {
+        withUnsafeBytes(of: value.littleEndian) { data.append(contentsOf: $0) }
+    }
+
+    /// Raw deflate (as used by ZIP), or nil if compression does not help
+    private static func deflate(_ input: Data) -> Data? {
+        guard !input.isEmpty else { return nil }
+        let capacity = input.count
+        let destination = UnsafeMutablePointer<UInt8>.allocate(capacity: capacity)
+        defer { destination.deallocate() }
+
+        let size = input.withUnsafeBytes { source -> Int in
+            guard let pointer = source.baseAddress?.assumingMemoryBound(to: UInt8.self) else { return 0 }
+            return compression_encode_buffer(destination, capacity, pointer, input.count, nil, COMPRESSION_ZLIB)
+        }
+        guard size > 0, size < input.count else { return nil }
+        return Data(bytes: destination, count: size)
+    }
+
+    private static let crcTable: [UInt32] = (0..<256).map { index in
+        var crc = UInt32(index)
+        for _ in 0..<8 {
+            crc = crc & 1 == 1 ? 0xEDB8_8320 ^ (crc >> 1) : crc >> 1
+        }
+        return crc
+    }
+
+    static func crc32(_ data: Data) -> UInt32 {
+        var crc: UInt32 = 0xFFFF_FFFF
+        for byte in data {
+            crc = crcTable[Int((crc ^ UInt32(byte)) & 0xFF)] ^ (crc >> 8)
+        }
+        return crc ^ 0xFFFF_FFFF
+    }
+}
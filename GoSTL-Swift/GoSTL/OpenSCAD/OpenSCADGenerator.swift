@@ -87,6 +87,85 @@ class OpenSCADGenerator {
         return lines.joined(separator: "\n")
     }
 
+    // MARK: - Polyhedron Module Export
+
+    /// Generate a file with the whole mesh as a reusable polyhedron module
+    ///
+    /// The module is called at the end, so the file renders on its own with `include <...>`
+    /// and only provides the module with `use <...>`.
+    /// - Parameters:
+    ///   - mesh: Indexed mesh to write (in the file's original coordinates)
+    ///   - moduleName: Module name, turned into a valid OpenSCAD identifier
+    ///   - source: Source file name for the header
+    ///   - note: Additional header line (e.g. the decimation applied)
+    /// - Returns: OpenSCAD code string
+    static func polyhedronModule(_ mesh: IndexedMesh, moduleName: String, source: String? = nil, note: String? = nil) -> String {
+        let name = moduleIdentifier(for: moduleName)
+
+        var lines: [String] = []
+        lines.append("// OpenSCAD polyhedron generated from GoSTL")
+        if let source {
+            lines.append("// Source: \(source)")
+        }
+        lines.append("// Generated: \(formattedDate())")
+        lines.append("// \(mesh.points.count) vertices, \(mesh.faces.count) faces")
+        if let note {
+            lines.append("// \(note)")
+        }
+        lines.append("// Usage: use <file.scad> and call \(name)(), or include the file to render it directly")
+        lines.append("")
+        lines.append("module \(name)() {")
+        lines.append("    polyhedron(")
+        lines.append("        points = [")
+        for (index, point) in mesh.points.enumerated() {
+            let comma = index < mesh.points.count - 1 ? "," : ""
+            lines.append("            [\(formatNumber(point.x)), \(formatNumber(point.y)), \(formatNumber(point.z))]\(comma)")
+        }
+        lines.append("        ],")
+        lines.append("        faces = [")
+        for (index, face) in mesh.faces.enumerated() {
+            let comma = index < mesh.faces.count - 1 ? "," : ""
+            // OpenSCAD expects faces clockwise seen from outside, STL stores them counter-clockwise
+            lines.append("            [\(face.0), \(face.2), \(face.1)]\(comma)")
+        }
+        lines.append("        ],")
+        lines.append("        convexity = 10")
+        lines.append("    );")
+        lines.append("}")
+        lines.append("")
+        lines.append("\(name)();")
+
+        return lines.joined(separator: "\n") + "\n"
+    }
+
+    /// Generate a polyhedron module file for a model, optionally decimated
+    /// - Parameters:
+    ///   - model: Model in the file's original coordinates
+    ///   - fileName: Source file name for the header
+    ///   - moduleName: Module name (nil = derived from the file name)
+    ///   - decimation: Grid cell size in mm for vertex clustering (nil = full resolution)
+    static func polyhedronModule(from model: STLModel, fileName: String, moduleName: String? = nil, decimation: Double? = nil) -> String {
+        let mesh = IndexedMesh(triangles: model.triangles)
+        let moduleName = moduleName ?? fileName
+        guard let decimation, decimation > 0 else {
+            return polyhedronModule(mesh, moduleName: moduleName, source: fileName)
+        }
+
+        let decimated = mesh.decimated(cellSize: decimation)
+        let note = "Decimated from \(mesh.faces.count) faces by merging vertices within \(formatNumber(decimation)) mm cells"
+        return polyhedronModule(decimated, moduleName: moduleName, source: fileName, note: note)
+    }
+
+    /// A valid OpenSCAD identifier for a file or model name ("my part-2.stl" -> "my_part_2")
+    static func moduleIdentifier(for name: String) -> String {
+        let base = (name as NSString).deletingPathExtension
+        var identifier = String(base.map { $0.isASCII && ($0.isLetter || $0.isNumber) ? $0 : "_" })
+        if identifier.isEmpty || identifier.first?.isNumber == true {
+            identifier = "model_" + identifier
+        }
+        return identifier
+    }
+
     /// Find open edges and generate faces to close the mesh
     /// An edge is "open" if it only appears in one face (not shared by two faces)
     private static func generateClosingFaces(points: [Vector3], existingFaces: [[Int]]) -> [[Int]] {
@@ -673,6 +752,187 @@ class OpenSCADGenerator {
         return lines.joined(separator: "\n")
     }
 
+    // MARK: - Section Profiles
+
+    /// Generate a 2D profile module from a section sketch (lines, arcs and circles)
+    ///
+    /// Each outline becomes a polygon of its corners and arc points (`sketch_arc`, following `$fn`);
+    /// holes are subtracted from the outline around them, full circles use circle().
+    /// - Parameters:
+    ///   - sketch: Section sketch in the plane's coordinates
+    ///   - moduleName: Module name, turned into a valid OpenSCAD identifier
+    ///   - source: Source file name for the header
+    /// - Returns: OpenSCAD code string
+    static func profileModule(_ sketch: SectionSketch, moduleName: String, source: String? = nil) -> String {
+        let name = moduleIdentifier(for: moduleName)
+        let (u, v) = (sketch.axis.planeAxes.u.rawValue.uppercased(), sketch.axis.planeAxes.v.rawValue.uppercased())
+
+        var lines: [String] = []
+        lines.append("// OpenSCAD profile generated from GoSTL")
+        if let source {
+            lines.append("// Source: \(source)")
+        }
+        lines.append("// Generated: \(formattedDate())")
+        lines.append("// Section at \(sketch.axis.rawValue.uppercased()) = \(formatNumber(sketch.position)) mm, drawing X = \(u), Y = \(v)")
+        lines.append("// \(sketch.lineCount) lines, \(sketch.arcCount) arcs, \(sketch.circleCount) circles within \(formatNumber(sketch.tolerance)) mm of \(sketch.segmentCount) cut segments")
+        lines.append("")
+        lines.append("$fn = 64;")
+        lines.append("")
+        lines.append("// Points of an arc around c from angle a0 to a1 (degrees), without the end point")
+        lines.append("function sketch_arc(c, r, a0, a1) =")
+        lines.append("    let(n = max(1, ceil(abs(a1 - a0) / 360 * $fn)))")
+        lines.append("    [for (i = [0 : n - 1]) let(a = a0 + (a1 - a0) * i / n) c + r * [cos(a), sin(a)]];")
+        lines.append("")
+        lines.append("module \(name)() {")
+
+        // Outlines with the holes directly inside them
+        let outlines = sketch.loops.indices.filter { !sketch.loops[$0].isHole }
+        let pieces = outlines.map { outline -> (outline: Int, holes: [Int]) in
+            let loop = sketch.loops[outline]
+            let holes = sketch.loops.indices.filter {
+                sketch.loops[$0].depth == loop.depth + 1 && SectionSketch.contains(loop.outline, sketch.loops[$0].outline[0])
+            }
+            return (outline, holes)
+        }
+        let indent = pieces.count > 1 ? "        " : "    "
+        if pieces.count > 1 {
+            lines.append("    union() {")
+        }
+        for piece in pieces {
+            if piece.holes.isEmpty {
+                lines += profileShape(sketch.loops[piece.outline], indent: indent)
+            } else {
+                lines.append("\(indent)difference() {")
+                lines += profileShape(sketch.loops[piece.outline], indent: indent + "    ")
+                for hole in piece.holes {
+                    lines += profileShape(sketch.loops[hole], indent: indent + "    ")
+                }
+                lines.append("\(indent)}")
+            }
+        }
+        if pieces.count > 1 {
+            lines.append("    }")
+        }
+        lines.append("}")
+        lines.append("")
+        lines.append("\(name)();")
+
+        return lines.joined(separator: "\n") + "\n"
+    }
+
+    /// One outline as circle() or a polygon of its lines and arcs
+    private static func profileShape(_ loop: SectionSketch.Loop, indent: String) -> [String] {
+        if loop.elements.count == 1, case .circle(let center, let radius) = loop.elements[0] {
+            return ["\(indent)translate([\(formatNumber(center.x)), \(formatNumber(center.y))]) circle(r = \(formatNumber(radius)));"]
+        }
+
+        // Runs of line start points, then each arc's points
+        var parts: [String] = []
+        var corners: [String] = []
+        for element in loop.elements {
+            switch element {
+            case .line(let from, _):
+                corners.append("[\(formatNumber(from.x)), \(formatNumber(from.y))]")
+            case .arc(let from, _, let center, let sweep):
+                if !corners.isEmpty {
+                    parts.append("[\(corners.joined(separator: ", "))]")
+                    corners = []
+                }
+                let radius = ((from.x - center.x) * (from.x - center.x) + (from.y - center.y) * (from.y - center.y)).squareRoot()
+                let start = atan2(from.y - center.y, from.x - center.x) * 180 / .pi
+                parts.append("sketch_arc([\(formatNumber(center.x)), \(formatNumber(center.y))], \(formatNumber(radius)), \(formatNumber(start)), \(formatNumber(start + sweep * 180 / .pi)))")
+            case .circle:
+                break
+            }
+        }
+        if !corners.isEmpty {
+            parts.append("[\(corners.joined(separator: ", "))]")
+        }
+
+        var lines = ["\(indent)polygon(concat("]
+        for (index, part) in parts.enumerated() {
+            lines.append("\(indent)    \(part)\(index < parts.count - 1 ? "," : "")")
+        }
+        lines.append("\(indent)));")
+        return lines
+    }
+
+    // MARK: - Variables from Measurements
+
+    /// Decimal places of variables whose measurement has no precision of its own
+    static let variableDecimals = 2
+
+    /// OpenSCAD variables holding measured values, to start re-modeling a scanned part
+    ///
+    /// A measurement's note names its variable ("hole spacing" -> `hole_spacing`), otherwise its
+    /// export name does (`distance_1`). Radii also get a diameter variable. Angles are in degrees.
+    /// - Parameters:
+    ///   - entries: Named measurements (see `MeasurementSet`)
+    ///   - source: File the measurements were taken on, for the header
+    ///   - decimals: Decimal places of every value (nil: each measurement's own, else `variableDecimals`)
+    static func variables(from entries: [MeasurementSet.Entry], source: String? = nil, decimals: Int? = nil) -> String {
+        let measured = entries.filter { $0.measurement.type != .triangleSelect }
+        guard !measured.isEmpty else {
+            return "// No measurements to convert"
+        }
+
+        var lines: [String] = []
+        lines.append("// OpenSCAD variables generated from GoSTL measurements")
+        if let source {
+            lines.append("// Source: \(source)")
+        }
+        lines.append("// Generated: \(formattedDate())")
+        lines.append("")
+
+        var used: Set<String> = []
+        for entry in measured {
+            let measurement = entry.measurement
+            let note = measurement.note?.trimmingCharacters(in: .whitespacesAndNewlines) ?? ""
+            let name = uniqueIdentifier(variableIdentifier(for: note.isEmpty ? entry.name : note), in: &used)
+            let value = String(format: "%.\(max(decimals ?? measurement.decimals ?? variableDecimals, 0))f", measurement.value)
+            // Keep the export name next to a renamed variable so it can be found in the viewer
+            let origin = note.isEmpty ? "" : "\(entry.name), "
+
+            switch measurement.type {
+            case .angle:
+                lines.append("\(name) = \(value);  // \(origin)degrees")
+            case .radius:
+                lines.append("\(name) = \(value);  // \(origin)radius, mm")
+                let diameterName = name.contains("radius")
+                    ? name.replacingOccurrences(of: "radius", with: "diameter")
+                    : name + "_diameter"
+                lines.append("\(uniqueIdentifier(diameterName, in: &used)) = 2 * \(name);")
+            case .distance, .triangleSelect:
+                lines.append("\(name) = \(value);  // \(origin)mm")
+            }
+        }
+
+        return lines.joined(separator: "\n")
+    }
+
+    /// A valid OpenSCAD variable name for a measurement name or note ("Hole spacing" -> "hole_spacing")
+    static func variableIdentifier(for name: String) -> String {
+        let words = name.lowercased()
+            .split { !($0.isASCII && ($0.isLetter || $0.isNumber)) }
+        let identifier = words.joined(separator: "_")
+        if identifier.isEmpty {
+            return "value"
+        }
+        return identifier.first?.isNumber == true ? "value_" + identifier : identifier
+    }
+
+    /// `identifier`, numbered if it is already taken ("distance", "distance_2", ...)
+    private static func uniqueIdentifier(_ identifier: String, in used: inout Set<String>) -> String {
+        var candidate = identifier
+        var number = 2
+        while used.contains(candidate) {
+            candidate = "\(identifier)_\(number)"
+            number += 1
+        }
+        used.insert(candidate)
+        return candidate
+    }
+
     // MARK: - Helper Methods
 
     private static func formatNumber(_ value: Double) -> String {
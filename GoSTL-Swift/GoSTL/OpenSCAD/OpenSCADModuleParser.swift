@@ -0,0 +1,237 @@
+import Foundation
+
+/// A module defined at the top level of a .scad file
+struct OpenSCADModule: Identifiable, Equatable {
+    struct Parameter: Equatable {
+        let name: String
+        /// Default value expression as written, nil for parameters without a default
+        let defaultValue: String?
+    }
+
+    let name: String
+    let parameters: [Parameter]
+    /// 1-based line of the `module` keyword
+    let line: Int
+
+    var id: String { name }
+
+    /// Parameters that are undef when the module is called without arguments
+    var requiredParameters: [String] {
+        parameters.filter { $0.defaultValue == nil }.map(\.name)
+    }
+
+    /// "name()" or "name(width, height)" listing the parameters without defaults
+    var signature: String {
+        "\(name)(\(requiredParameters.joined(separator: ", ")))"
+    }
+}
+
+/// Finds the top-level module definitions of an OpenSCAD file
+///
+/// This is a lightweight scanner, not a full parser: it skips comments and strings, tracks braces
+/// so nested modules are ignored and splits the parameter list on top-level commas.
+enum OpenSCADModuleParser {
+    static func topLevelModules(scadFile: URL) -> [OpenSCADModule] {
+        guard let source = try? String(contentsOf: scadFile, encoding: .utf8) else { return [] }
+        return topLevelModules(in: source)
+    }
+
+    static func topLevelModules(in source: String) -> [OpenSCADModule] {
+        let code = Array(strippingComments(source))
+        var modules: [OpenSCADModule] = []
+        var depth = 0
+        var line = 1
+        var index = 0
+
+        while index < code.count {
+            let char = code[index]
+            switch char {
+            case "\n", "\r\n":
+                line += 1
+            case "\"":
+                index = endOfString(code, from: index)
+                continue
+            case "{":
+                depth += 1
+            case "}":
+                depth = max(depth - 1, 0)
+            default:
+                if depth == 0, isKeyword("module", in: code, at: index),
+                   let parsed = parseModule(code, keywordAt: index, line: line) {
+                    modules.append(parsed.module)
+                    line += code[index..<parsed.end].filter(\.isNewline).count
+                    index = parsed.end
+                    continue
+                }
+            }
+            index += 1
+        }
+
+        return modules
+    }
+
+    // MARK: - Scanning
+
+    /// Replace // and /* */ comments with spaces, keeping newlines and string literals
+    private static func strippingComments(_ source: String) -> String {
+        let chars = Array(source)
+        var result = ""
+        result.reserveCapacity(chars.count)
+        var index = 0
+
+        while index < chars.count {
+            let char = chars[index]
+            let next = index + 1 < chars.count ? chars[index + 1] : nil
+
+            if char == "\"" {
+                let end = endOfString(chars, from: index)
+                result.append(contentsOf: chars[index..<end])
+                index = end
+            } else if char == "/" && next == "/" {
+                while index < chars.count && !chars[index].isNewline {
+                    index += 1
+                }
+            } else if char == "/" && next == "*" {
+                index += 2
+                while index < chars.count && !(chars[index] == "*" && index + 1 < chars.count && chars[index + 1] == "/") {
+                    result.append(chars[index].isNewline ? chars[index] : " ")
+                    index += 1
+                }
+                index += 2
+            } else {
+                result.append(char)
+                index += 1
+            }
+        }
+
+        return result
+    }
+
+    /// Index after the closing quote of the string starting at `start`
+    private static func endOfString(_ chars: [Character], from start: Int) -> Int {
+        var index = start + 1
+        while index < chars.count {
+            if chars[index] == "\\" {
+                index += 2
+                continue
+            }
+            if chars[index] == "\"" {
+                return index + 1
+            }
+            index += 1
+        }
+        return chars.count
+    }
+
+    private static func isIdentifierCharacter(_ char: Character) -> Bool {
+        char == "_" || char == "$" || char.isLetter || char.isNumber
+    }
+
+    private static func isKeyword(_ keyword: String, in chars: [Character], at index: Int) -> Bool {
+        let word = Array(keyword)
+        guard index + word.count < chars.count, Array(chars[index..<index + word.count]) == word else { return false }
+        if index > 0 && isIdentifierCharacter(chars[index - 1]) { return false }
+        return !isIdentifierCharacter(chars[index + word.count])
+    }
+
+    private static func skipWhitespace(_ chars: [Character], from start: Int) -> Int {
+        var index = start
+        while index < chars.count && chars[index].isWhitespace {
+            index += 1
+        }
+        return index
+    }
+
+    /// Parse `module name(params)` and return the module and the index after the parameter list
+    private static func parseModule(_ chars: [Character], keywordAt start: Int, line: Int) -> (module: OpenSCADModule, end: Int)? {
+        var index = skipWhitespace(chars, from: start + "module".count)
+        let nameStart = index
+        while index < chars.count && isIdentifierCharacter(chars[index]) {
+            index += 1
+        }
+        guard index > nameStart else { return nil }
+        let name = String(chars[nameStart..<index])
+
+        index = skipWhitespace(chars, from: index)
+        guard index < chars.count, chars[index] == "(" else { return nil }
+
+        // Collect the parameter list up to the matching parenthesis
+        var depth = 0
+        let listStart = index + 1
+        while index < chars.count {
+            switch chars[index] {
+            case "\"":
+                index = endOfString(chars, from: index)
+                continue
+            case "(", "[", "{":
+                depth += 1
+            case ")", "]", "}":
+                depth -= 1
+            default:
+                break
+            }
+            if depth == 0 {
+                break
+            }
+            index += 1
+        }
+        guard index < chars.count else { return nil }
+
+        let parameters = splitParameters(chars[listStart..<index]).compactMap(parameter)
+        return (OpenSCADModule(name: name, parameters: parameters, line: line), index + 1)
+    }
+
+    /// Split a parameter list on commas outside brackets and strings
+    private static func splitParameters(_ chars: ArraySlice<Character>) -> [String] {
+        var parts: [String] = []
+        var current = ""
+        var depth = 0
+        var inString = false
+        var escaped = false
+
+        for char in chars {
+            if inString {
+                current.append(char)
+                if escaped {
+                    escaped = false
+                } else if char == "\\" {
+                    escaped = true
+                } else if char == "\"" {
+                    inString = false
+                }
+                continue
+            }
+
+            switch char {
+            case "\"":
+                inString = true
+            case "(", "[", "{":
+                depth += 1
+            case ")", "]", "}":
+                depth -= 1
+            case "," where depth == 0:
+                parts.append(current)
+                current = ""
+                continue
+            default:
+                break
+            }
+            current.append(char)
+        }
+        parts.append(current)
+
+        return parts
+    }
+
+    private static func parameter(_ text: String) -> OpenSCADModule.Parameter? {
+        let trimmed = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !trimmed.isEmpty else { return nil }
+
+        guard let equals = trimmed.firstIndex(of: "=") else {
+            return OpenSCADModule.Parameter(name: trimmed, defaultValue: nil)
+        }
+        let name = trimmed[..<equals].trimmingCharacters(in: .whitespaces)
+        let value = trimmed[trimmed.index(after: equals)...].trimmingCharacters(in: .whitespacesAndNewlines)
+        return OpenSCADModule.Parameter(name: name, defaultValue: value)
+    }
+}
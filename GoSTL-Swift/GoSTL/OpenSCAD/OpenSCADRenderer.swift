@@ -82,12 +82,19 @@ struct OpenSCADColor: Hashable, CustomStringConvertible {
 class OpenSCADRenderer {
     private let workDir: URL
 
+    /// Directory for rendered STLs; only CSG files and 2D wrappers are written next to the source
+    private let tempDir: URL
+
     /// Unique tag for color extraction
     private let colorTag = "GOSTL_COLOR"
 
+    /// Background job the render runs in; cancelling it terminates the OpenSCAD processes
+    var job: JobContext?
+
     /// Initialize renderer with a working directory
-    init(workDir: URL) {
+    init(workDir: URL, workspace: TempWorkspace = .shared) {
         self.workDir = workDir
+        self.tempDir = workspace.directory
     }
 
     /// Find the OpenSCAD executable path
@@ -168,12 +175,18 @@ class OpenSCADRenderer {
         // when multiple models are rendered simultaneously
         let sessionId = UUID().uuidString.prefix(8)
 
+        // Remove CSG files left next to the source by renders that were killed
+        TempWorkspace.removeStaleRenderFiles(in: workDir)
+
         // Step 1: Convert to CSG format (normalizes all color() calls)
         let csgFile = workDir.appendingPathComponent("gostl_\(sessionId).csg")
         defer { try? FileManager.default.removeItem(at: csgFile) }
 
         do {
+            job?.reportProgress(nil, "Converting to CSG...")
             try convertToCSG(scadFile: scadFile, outputFile: csgFile)
+        } catch is CancellationError {
+            throw CancellationError()
         } catch {
             // CSG conversion failed, fall back to regular rendering
             print("CSG conversion failed, falling back to non-colored rendering: \(error)")
@@ -184,6 +197,7 @@ class OpenSCADRenderer {
 
         // Step 2: Extract all unique colors used in the model
         let t1 = CFAbsoluteTimeGetCurrent()
+        job?.reportProgress(nil, "Extracting colors...")
         let colors = try extractColors(csgFile: csgFile, sessionId: sessionId)
         print("  Color extraction: \(String(format: "%.0f", (CFAbsoluteTimeGetCurrent() - t1) * 1000))ms - found \(colors.count) colors")
 
@@ -219,10 +233,101 @@ class OpenSCADRenderer {
         )
     }
 
+    // MARK: - 3MF Rendering
+
+    /// OpenSCAD options that keep color() as 3MF materials (manifold backend, OpenSCAD 2024 and later)
+    static let threeMFColorArguments = [
+        "--backend=manifold",
+        "-O", "export-3mf/color-mode=model",
+        "-O", "export-3mf/material-type=color"
+    ]
+
+    /// Render an OpenSCAD file to a colored model through a single 3MF export
+    ///
+    /// One OpenSCAD run instead of one per color, with the colors read from the 3MF materials.
+    /// Falls back to `renderToColoredModel` for 2D files and when the export fails or carries no
+    /// colors (OpenSCAD versions without colored 3MF export).
+    /// - Parameter scadFile: URL of the .scad file to render
+    /// - Returns: ColoredRenderResult containing the model with per-triangle colors
+    func renderViaThreeMF(scadFile: URL) throws -> ColoredRenderResult {
+        let t0 = CFAbsoluteTimeGetCurrent()
+        let temp3MF = tempDir.appendingPathComponent("gostl_\(UUID().uuidString.prefix(8)).3mf")
+        defer { try? FileManager.default.removeItem(at: temp3MF) }
+
+        let result: InternalRenderResult
+        do {
+            job?.reportProgress(nil, "Rendering to 3MF...")
+            result = try runOpenSCAD(scadFile: scadFile, outputFile: temp3MF, extraArguments: Self.threeMFColorArguments)
+        } catch is CancellationError {
+            throw CancellationError()
+        } catch {
+            print("3MF export failed, rendering colors separately: \(error)")
+            return try renderToColoredModel(scadFile: scadFile)
+        }
+
+        guard !result.isEmpty, let parsed = try? ThreeMFParser.parseWithPlates(url: temp3MF) else {
+            return try renderToColoredModel(scadFile: scadFile)
+        }
+
+        let colors = ColorGroup.groups(in: parsed.allTriangles).filter { $0.color != nil }.count
+        guard colors > 0 else {
+            print("3MF export has no colors, rendering colors separately")
+            return try renderToColoredModel(scadFile: scadFile)
+        }
+
+        print("  3MF rendering: \(String(format: "%.0f", (CFAbsoluteTimeGetCurrent() - t0) * 1000))ms - found \(colors) colors")
+        return ColoredRenderResult(
+            model: STLModel(triangles: parsed.allTriangles, name: scadFile.deletingPathExtension().lastPathComponent),
+            warnings: result.messages,
+            is2D: false,
+            colorsExtracted: colors
+        )
+    }
+
+    /// Render with the colored technique chosen in the settings
+    func renderColored(scadFile: URL, viaThreeMF: Bool) throws -> ColoredRenderResult {
+        viaThreeMF ? try renderViaThreeMF(scadFile: scadFile) : try renderToColoredModel(scadFile: scadFile)
+    }
+
+    // MARK: - Module Isolation
+
+    /// Render a single top-level module of a file, called without arguments
+    ///
+    /// The module is called from a temporary wrapper next to the source that `use`s the file, so the
+    /// file's top-level geometry is skipped while its variables and other modules stay available.
+    func renderModule(_ moduleName: String, scadFile: URL, viaThreeMF: Bool) throws -> ColoredRenderResult {
+        let wrapperFile = try createModuleWrapperFile(for: scadFile, moduleName: moduleName)
+        defer { try? FileManager.default.removeItem(at: wrapperFile) }
+
+        let result = try renderColored(scadFile: wrapperFile, viaThreeMF: viaThreeMF)
+        let name = "\(scadFile.deletingPathExtension().lastPathComponent)-\(moduleName)"
+        return ColoredRenderResult(
+            model: STLModel(triangles: result.model.triangles, name: name),
+            warnings: result.warnings,
+            is2D: result.is2D,
+            colorsExtracted: result.colorsExtracted
+        )
+    }
+
+    /// Create a temporary wrapper file that calls one module of the original file
+    private func createModuleWrapperFile(for scadFile: URL, moduleName: String) throws -> URL {
+        let wrapperContent = """
+        // Temporary wrapper to render a single module in isolation
+        use <\(scadFile.path)>
+
+        \(moduleName)();
+        """
+
+        let wrapperFile = workDir.appendingPathComponent("gostl_module_\(UUID().uuidString.prefix(8)).scad")
+        try wrapperContent.write(to: wrapperFile, atomically: true, encoding: .utf8)
+
+        return wrapperFile
+    }
+
     /// Fall back to regular (non-colored) rendering
     private func renderWithoutColors(scadFile: URL, sessionId: String.SubSequence? = nil) throws -> ColoredRenderResult {
         let id = sessionId ?? UUID().uuidString.prefix(8)
-        let tempSTL = workDir.appendingPathComponent("gostl_\(id).stl")
+        let tempSTL = tempDir.appendingPathComponent("gostl_\(id).stl")
         defer { try? FileManager.default.removeItem(at: tempSTL) }
 
         let result = try runOpenSCAD(scadFile: scadFile, outputFile: tempSTL)
@@ -260,8 +365,7 @@ class OpenSCADRenderer {
         process.standardOutput = Pipe()
         process.standardError = stderrPipe
 
-        try process.run()
-        process.waitUntilExit()
+        try JobContext.run(process, in: job)
 
         if process.terminationStatus != 0 {
             let stderrData = stderrPipe.fileHandleForReading.readDataToEndOfFile()
@@ -278,7 +382,7 @@ class OpenSCADRenderer {
         let colorExtractor = "module color(c, alpha) { echo(\(colorTag)=str(c)); }"
 
         // Use a temp file since OpenSCAD doesn't accept /dev/null
-        let tempOutput = workDir.appendingPathComponent("gostl_\(sessionId)_colors.stl")
+        let tempOutput = tempDir.appendingPathComponent("gostl_\(sessionId)_colors.stl")
         defer { try? FileManager.default.removeItem(at: tempOutput) }
 
         let process = Process()
@@ -295,8 +399,7 @@ class OpenSCADRenderer {
         process.standardOutput = stdoutPipe
         process.standardError = stderrPipe
 
-        try process.run()
-        process.waitUntilExit()
+        try JobContext.run(process, in: job)
 
         // Parse colors from stderr (ECHO statements go there)
         let stderrData = stderrPipe.fileHandleForReading.readDataToEndOfFile()
@@ -335,7 +438,7 @@ class OpenSCADRenderer {
         // Redefine color() to consume its children (output nothing)
         let colorDisabler = "module color(c, alpha) { /* discard */ }"
 
-        let tempSTL = workDir.appendingPathComponent("gostl_\(sessionId)_uncolored.stl")
+        let tempSTL = tempDir.appendingPathComponent("gostl_\(sessionId)_uncolored.stl")
         defer { try? FileManager.default.removeItem(at: tempSTL) }
 
         let process = Process()
@@ -351,8 +454,7 @@ class OpenSCADRenderer {
         process.standardOutput = Pipe()
         process.standardError = stderrPipe
 
-        try process.run()
-        process.waitUntilExit()
+        try JobContext.run(process, in: job)
 
         // Check if the output file has any geometry
         if FileManager.default.fileExists(atPath: tempSTL.path) {
@@ -380,15 +482,31 @@ class OpenSCADRenderer {
             var error: Error?
         }
 
+        final class RenderProgress: @unchecked Sendable {
+            private let lock = NSLock()
+            private var done = 0
+
+            func increment() -> Int {
+                lock.lock()
+                defer { lock.unlock() }
+                done += 1
+                return done
+            }
+        }
+
         // Add one extra slot for uncolored geometry if needed
         let totalJobs = colors.count + (includeUncolored ? 1 : 0)
         let results = (0..<totalJobs).map { _ in ColorResult() }
         let localWorkDir = self.workDir  // Capture for Sendable closure
+        let localTempDir = self.tempDir
         let localSessionId = String(sessionId)  // Capture for Sendable closure
+        let job = self.job
+        let progress = RenderProgress()
+        job?.reportProgress(0, "Rendering \(totalJobs) colors...")
 
         // Render each color in parallel (plus uncolored if requested)
         DispatchQueue.concurrentPerform(iterations: totalJobs) { index in
-            let tempSTL = localWorkDir.appendingPathComponent("gostl_\(localSessionId)_c\(index).stl")
+            let tempSTL = localTempDir.appendingPathComponent("gostl_\(localSessionId)_c\(index).stl")
 
             defer { try? FileManager.default.removeItem(at: tempSTL) }
 
@@ -431,8 +549,7 @@ class OpenSCADRenderer {
                 process.standardOutput = Pipe()
                 process.standardError = Pipe()
 
-                try process.run()
-                process.waitUntilExit()
+                try JobContext.run(process, in: job)
 
                 if process.terminationStatus == 0 && FileManager.default.fileExists(atPath: tempSTL.path) {
                     // Parse the STL
@@ -452,7 +569,11 @@ class OpenSCADRenderer {
             } catch {
                 results[index].error = error
             }
+
+            let done = progress.increment()
+            job?.reportProgress(Double(done) / Double(totalJobs), "Rendering colors \(done)/\(totalJobs)...")
         }
+        try job?.checkCancellation()
 
         // Combine all triangles
         var allTriangles: [Triangle] = []
@@ -471,7 +592,7 @@ class OpenSCADRenderer {
         let openscadPath = try findOpenSCADExecutable()
 
         // Use a temp file since OpenSCAD doesn't accept /dev/null
-        let tempOutput = workDir.appendingPathComponent("gostl_\(sessionId)_warn.stl")
+        let tempOutput = tempDir.appendingPathComponent("gostl_\(sessionId)_warn.stl")
         defer { try? FileManager.default.removeItem(at: tempOutput) }
 
         let process = Process()
@@ -484,8 +605,7 @@ class OpenSCADRenderer {
         process.standardOutput = stdoutPipe
         process.standardError = stderrPipe
 
-        try process.run()
-        process.waitUntilExit()
+        try JobContext.run(process, in: job)
 
         let stdoutData = stdoutPipe.fileHandleForReading.readDataToEndOfFile()
         let stderrData = stderrPipe.fileHandleForReading.readDataToEndOfFile()
@@ -544,14 +664,14 @@ class OpenSCADRenderer {
     ///   - outputFile: URL where the STL output should be written
     /// - Returns: InternalRenderResult with messages and empty status
     /// - Throws: Error if rendering fails (except for empty file which returns isEmpty=true)
-    private func runOpenSCAD(scadFile: URL, outputFile: URL) throws -> InternalRenderResult {
+    private func runOpenSCAD(scadFile: URL, outputFile: URL, extraArguments: [String] = []) throws -> InternalRenderResult {
         // Find OpenSCAD executable
         let openscadPath = try findOpenSCADExecutable()
 
         // Run openscad command
         let process = Process()
         process.executableURL = URL(fileURLWithPath: openscadPath)
-        process.arguments = [
+        process.arguments = extraArguments + [
             "-o", outputFile.path,
             scadFile.path
         ]
@@ -562,8 +682,7 @@ class OpenSCADRenderer {
         process.standardOutput = stdoutPipe
         process.standardError = stderrPipe
 
-        try process.run()
-        process.waitUntilExit()
+        try JobContext.run(process, in: job)
 
         // Capture both stdout and stderr
         let stdoutData = stdoutPipe.fileHandleForReading.readDataToEndOfFile()
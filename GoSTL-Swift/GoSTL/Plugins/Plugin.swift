@@ -0,0 +1,118 @@
+import Foundation
+
+/// Contents of a plugin's `plugin.json`
+///
+/// ```json
+/// {
+///   "name": "OFF Importer",
+///   "version": "1.0",
+///   "executable": "off-plugin",
+///   "importers": [{ "extensions": ["off"], "description": "Object File Format" }],
+///   "analyzers": [{ "id": "centroid", "title": "Vertex Centroid" }]
+/// }
+/// ```
+struct PluginManifest: Codable, Equatable {
+    struct Importer: Codable, Equatable {
+        /// File extensions handled by the importer (without dot, case-insensitive)
+        var extensions: [String]
+        var description: String?
+    }
+
+    struct Analyzer: Codable, Equatable {
+        /// Identifier passed to the executable
+        var id: String
+        /// Menu title
+        var title: String
+    }
+
+    var name: String
+    var version: String?
+    /// Executable path relative to the plugin directory
+    var executable: String
+    var importers: [Importer]?
+    var analyzers: [Analyzer]?
+}
+
+/// An installed plugin: a directory containing `plugin.json` and an executable
+///
+/// Plugins communicate over a subprocess protocol:
+/// - Import: `<executable> import <input-file> <output.stl>` writes an STL (binary or ASCII) to the output path
+/// - Analyze: `<executable> analyze <analyzer-id> <model.stl>` prints a JSON `PluginAnalysisResult` to stdout
+///
+/// Models are exchanged in the file's original coordinates. A non-zero exit status is an error; stderr is shown to the user.
+struct Plugin: Identifiable, Equatable {
+    let manifest: PluginManifest
+    let directory: URL
+
+    var id: String { directory.path }
+
+    var executableURL: URL {
+        directory.appendingPathComponent(manifest.executable)
+    }
+
+    /// Lowercased file extensions this plugin can import
+    var importExtensions: [String] {
+        (manifest.importers ?? []).flatMap(\.extensions).map { $0.lowercased() }
+    }
+
+    /// Load a plugin from its directory
+    static func load(from directory: URL) throws -> Plugin {
+        let manifestURL = directory.appendingPathComponent("plugin.json")
+        let data = try Data(contentsOf: manifestURL)
+        let manifest = try JSONDecoder().decode(PluginManifest.self, from: data)
+        return Plugin(manifest: manifest, directory: directory)
+    }
+}
+
+/// Output of a plugin analyzer
+///
+/// ```json
+/// {
+///   "results": [{ "name": "Centroid", "value": "(1.00, 2.00, 3.00) mm" }],
+///   "overlay": {
+///     "points": [{ "x": 1, "y": 2, "z": 3 }],
+///     "lines": [{ "from": { "x": 0, "y": 0, "z": 0 }, "to": { "x": 1, "y": 2, "z": 3 } }]
+///   }
+/// }
+/// ```
+struct PluginAnalysisResult: Codable, Equatable {
+    struct Entry: Codable, Equatable {
+        var name: String
+        var value: String
+    }
+
+    struct Line: Codable, Equatable {
+        var from: Vector3
+        var to: Vector3
+    }
+
+    /// Scene overlay drawn by the viewer (original coordinates)
+    struct Overlay: Codable, Equatable {
+        var points: [Vector3]?
+        var lines: [Line]?
+
+        /// A copy with all geometry moved by the given offset
+        func translated(by offset: Vector3) -> Overlay {
+            Overlay(
+                points: points?.map { $0 + offset },
+                lines: lines?.map { Line(from: $0.from + offset, to: $0.to + offset) }
+            )
+        }
+    }
+
+    var results: [Entry]
+    var overlay: Overlay?
+}
+
+/// State of an analyzer run shown in the viewer
+struct PluginAnalysis: Equatable {
+    /// Analyzer title
+    let title: String
+    /// Analyzer output with the overlay in render space (nil while running or after an error)
+    var result: PluginAnalysisResult?
+    var errorMessage: String?
+
+    var isRunning: Bool {
+        result == nil && errorMessage == nil
+    }
+}
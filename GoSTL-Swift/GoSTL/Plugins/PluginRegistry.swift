@@ -0,0 +1,142 @@
+import Foundation
+
+/// Errors that can occur while running a plugin
+enum PluginError: LocalizedError {
+    case failed(plugin: String, status: Int32, stderr: String)
+    case invalidOutput(plugin: String, reason: String)
+
+    var errorDescription: String? {
+        switch self {
+        case .failed(let plugin, let status, let stderr):
+            let detail = stderr.trimmingCharacters(in: .whitespacesAndNewlines)
+            return "Plugin \"\(plugin)\" failed (exit status \(status))" + (detail.isEmpty ? "" : ": \(detail)")
+        case .invalidOutput(let plugin, let reason):
+            return "Plugin \"\(plugin)\" returned invalid output: \(reason)"
+        }
+    }
+}
+
+/// Discovers installed plugins and runs them
+///
+/// Plugins are loaded from `~/Library/Application Support/GoSTL/Plugins/<plugin>/`
+/// and from the colon-separated directories in `GOSTL_PLUGIN_PATH`.
+final class PluginRegistry: @unchecked Sendable {
+    /// Registry of the installed plugins, discovered once at launch
+    static let shared = PluginRegistry(searchDirectories: PluginRegistry.defaultSearchDirectories())
+
+    /// All plugins with a valid manifest
+    let plugins: [Plugin]
+
+    init(searchDirectories: [URL]) {
+        var plugins: [Plugin] = []
+        let fileManager = FileManager.default
+        for searchDirectory in searchDirectories {
+            guard let entries = try? fileManager.contentsOfDirectory(at: searchDirectory, includingPropertiesForKeys: nil) else {
+                continue
+            }
+            for directory in entries.sorted(by: { $0.lastPathComponent < $1.lastPathComponent }) {
+                guard fileManager.fileExists(atPath: directory.appendingPathComponent("plugin.json").path) else { continue }
+                do {
+                    plugins.append(try Plugin.load(from: directory))
+                } catch {
+                    print("Skipping plugin at \(directory.path): \(error.localizedDescription)")
+                }
+            }
+        }
+        self.plugins = plugins
+    }
+
+    static func defaultSearchDirectories() -> [URL] {
+        var directories: [URL] = []
+        if let applicationSupport = FileManager.default.urls(for: .applicationSupportDirectory, in: .userDomainMask).first {
+            directories.append(applicationSupport.appendingPathComponent("GoSTL/Plugins"))
+        }
+        if let path = ProcessInfo.processInfo.environment["GOSTL_PLUGIN_PATH"] {
+            directories.append(contentsOf: path.split(separator: ":").map { URL(fileURLWithPath: String($0)) })
+        }
+        return directories
+    }
+
+    /// File extensions importable through plugins
+    var importExtensions: [String] {
+        plugins.flatMap(\.importExtensions)
+    }
+
+    /// All analyzers with the plugin that provides them
+    var analyzers: [(plugin: Plugin, analyzer: PluginManifest.Analyzer)] {
+        plugins.flatMap { plugin in
+            (plugin.manifest.analyzers ?? []).map { (plugin: plugin, analyzer: $0) }
+        }
+    }
+
+    /// First plugin that imports the given extension
+    func importer(forExtension ext: String) -> Plugin? {
+        let ext = ext.lowercased()
+        return plugins.first { $0.importExtensions.contains(ext) }
+    }
+
+    // MARK: - Running Plugins
+
+    /// Convert a file to a model through an importer plugin
+    /// - Parameter job: Load job to run in; cancelling it terminates the plugin
+    func importModel(from url: URL, using plugin: Plugin, job: JobContext? = nil) throws -> STLModel {
+        let outputURL = TempWorkspace.shared.makeURL(prefix: "plugin", pathExtension: "stl")
+        defer { try? FileManager.default.removeItem(at: outputURL) }
+
+        _ = try run(plugin, arguments: ["import", url.path, outputURL.path], job: job)
+
+        var model = try STLParser.parse(url: outputURL)
+        model.name = url.deletingPathExtension().lastPathComponent
+        return model
+    }
+
+    /// Run an analyzer plugin on a model (in original coordinates)
+    /// - Parameter job: Background job to run in; cancelling it terminates the plugin
+    func analyze(_ model: STLModel, plugin: Plugin, analyzerID: String, job: JobContext? = nil) throws -> PluginAnalysisResult {
+        let modelURL = TempWorkspace.shared.makeURL(prefix: "plugin", pathExtension: "stl")
+        defer { try? FileManager.default.removeItem(at: modelURL) }
+
+        try STLExporter.exportBinary(model: model, to: modelURL)
+        let output = try run(plugin, arguments: ["analyze", analyzerID, modelURL.path], job: job)
+
+        do {
+            return try JSONDecoder().decode(PluginAnalysisResult.self, from: output)
+        } catch {
+            throw PluginError.invalidOutput(plugin: plugin.manifest.name, reason: error.localizedDescription)
+        }
+    }
+
+    /// Run the plugin executable and return its stdout
+    private func run(_ plugin: Plugin, arguments: [String], job: JobContext? = nil) throws -> Data {
+        let process = Process()
+        process.executableURL = plugin.executableURL
+        process.arguments = arguments
+        process.currentDirectoryURL = plugin.directory
+
+        let stdoutPipe = Pipe()
+        let stderrPipe = Pipe()
+        process.standardOutput = stdoutPipe
+        process.standardError = stderrPipe
+
+        try job?.checkCancellation()
+        try process.run()
+        job?.attach(process)
+        defer { job?.detach(process) }
+
+        // Drain both pipes before waiting so large outputs cannot fill a pipe and block the plugin
+        let errors = PipeReader(stderrPipe)
+        let output = stdoutPipe.fileHandleForReading.readDataToEndOfFile()
+        let stderrData = errors.result()
+        process.waitUntilExit()
+        try job?.checkCancellation()
+
+        if process.terminationStatus != 0 {
+            throw PluginError.failed(
+                plugin: plugin.manifest.name,
+                status: process.terminationStatus,
+                stderr: String(data: stderrData, encoding: .utf8) ?? ""
+            )
+        }
+        return output
+    }
+}
@@ -0,0 +1,398 @@
+import Foundation
+
+/// Renders CadQuery and build123d scripts (.py) to a mesh with a configured Python environment
+///
+/// The script runs inside a small bootstrap that captures the objects passed to `show_object()` /
+/// `show()` (CQ-editor and ocp_vscode style) or, if nothing was shown, a top-level `result` or `part`
+/// variable, and exports each of them as STL with the library that created it.
+class PythonCADRenderer {
+    /// Environment variable naming the Python interpreter (takes precedence over the setting)
+    static let environmentKey = "GOSTL_PYTHON"
+
+    /// Exit status of the bootstrap when the script ran but produced nothing to export
+    private static let noResultStatus: Int32 = 3
+
+    private let workDir: URL
+    private let tempDir: URL
+    private let configuredInterpreter: String?
+
+    /// Background job the render runs in; cancelling it terminates Python
+    var job: JobContext?
+
+    /// Result of rendering a script
+    struct RenderResult {
+        let model: STLModel
+        /// Lines the script printed
+        let messages: [String]
+        /// Number of exported objects
+        let objectCount: Int
+    }
+
+    /// Initialize renderer with a working directory
+    /// - Parameter interpreter: Python executable from the settings (nil or empty: detect)
+    init(workDir: URL, interpreter: String? = AppSettings.shared.pythonInterpreter, workspace: TempWorkspace = .shared) {
+        self.workDir = workDir
+        self.tempDir = workspace.directory
+        self.configuredInterpreter = interpreter
+    }
+
+    // MARK: - Interpreter
+
+    /// Find the Python interpreter: GOSTL_PYTHON, the setting, a virtual environment next to the
+    /// script (or in a parent directory), then python3 from the usual locations or the login shell
+    func findInterpreter(environment: [String: String] = ProcessInfo.processInfo.environment) throws -> String {
+        for candidate in [environment[Self.environmentKey], configuredInterpreter] {
+            guard let path = candidate, !path.isEmpty else { continue }
+            let expanded = (path as NSString).expandingTildeInPath
+            guard FileManager.default.isExecutableFile(atPath: expanded) else {
+                throw PythonCADError.interpreterNotFound(expanded)
+            }
+            return expanded
+        }
+
+        if let venv = Self.virtualEnvironmentInterpreter(near: workDir) {
+            return venv
+        }
+
+        for path in ["/opt/homebrew/bin/python3", "/usr/local/bin/python3"] where FileManager.default.isExecutableFile(atPath: path) {
+            return path
+        }
+
+        // Login shell PATH picks up pyenv, conda and similar setups
+        let shellProcess = Process()
+        let pipe = Pipe()
+        shellProcess.executableURL = URL(fileURLWithPath: "/bin/zsh")
+        shellProcess.arguments = ["-l", "-c", "which python3"]
+        shellProcess.standardOutput = pipe
+        shellProcess.standardError = FileHandle.nullDevice
+
+        do {
+            try shellProcess.run()
+            shellProcess.waitUntilExit()
+
+            if shellProcess.terminationStatus == 0 {
+                let data = pipe.fileHandleForReading.readDataToEndOfFile()
+                if let path = String(data: data, encoding: .utf8)?.trimmingCharacters(in: .whitespacesAndNewlines),
+                   !path.isEmpty {
+                    return path
+                }
+            }
+        } catch {
+            // Fall through
+        }
+
+        if FileManager.default.isExecutableFile(atPath: "/usr/bin/python3") {
+            return "/usr/bin/python3"
+        }
+        throw PythonCADError.interpreterNotFound(nil)
+    }
+
+    /// `.venv/bin/python` or `venv/bin/python` in the directory or one of its parents
+    static func virtualEnvironmentInterpreter(near directory: URL) -> String? {
+        var current = directory.standardizedFileURL
+        while true {
+            for name in [".venv", "venv"] {
+                let python = current.appendingPathComponent(name).appendingPathComponent("bin/python")
+                if FileManager.default.isExecutableFile(atPath: python.path) {
+                    return python.path
+                }
+            }
+            let parent = current.deletingLastPathComponent()
+            guard parent.path != current.path else { return nil }
+            current = parent
+        }
+    }
+
+    // MARK: - Rendering
+
+    /// Run a script and load the exported objects as one model
+    func render(script: URL) throws -> RenderResult {
+        let python = try findInterpreter()
+
+        let outputDir = tempDir.appendingPathComponent("python-\(UUID().uuidString.prefix(8))")
+        try FileManager.default.createDirectory(at: outputDir, withIntermediateDirectories: true)
+        defer { try? FileManager.default.removeItem(at: outputDir) }
+
+        let bootstrap = outputDir.appendingPathComponent("gostl_bootstrap.py")
+        try Self.bootstrapScript.write(to: bootstrap, atomically: true, encoding: .utf8)
+
+        let process = Process()
+        process.executableURL = URL(fileURLWithPath: python)
+        process.arguments = [bootstrap.path, script.path, outputDir.path]
+        process.currentDirectoryURL = workDir
+
+        // No __pycache__ next to the sources; it would only add noise to the watched folder
+        var environment = ProcessInfo.processInfo.environment
+        environment["PYTHONDONTWRITEBYTECODE"] = "1"
+        environment["PYTHONUNBUFFERED"] = "1"
+        process.environment = environment
+
+        let stdoutPipe = Pipe()
+        let stderrPipe = Pipe()
+        process.standardOutput = stdoutPipe
+        process.standardError = stderrPipe
+
+        job?.reportProgress(nil, "Running \(script.lastPathComponent)...")
+        try JobContext.run(process, in: job)
+
+        let stdout = String(data: stdoutPipe.fileHandleForReading.readDataToEndOfFile(), encoding: .utf8) ?? ""
+        let stderr = String(data: stderrPipe.fileHandleForReading.readDataToEndOfFile(), encoding: .utf8) ?? ""
+        let messages = Self.parseMessages(stdout: stdout, stderr: stderr)
+
+        if process.terminationStatus == Self.noResultStatus {
+            throw PythonCADError.noResult(messages: messages)
+        }
+        if process.terminationStatus != 0 {
+            var errorMsg = "Failed to run \(script.lastPathComponent)\n"
+            if !stderr.isEmpty {
+                errorMsg += stderr
+            }
+            throw PythonCADError.scriptFailed(errorMsg, messages: messages)
+        }
+
+        // Manifest lists the exported STL files in the order the objects were shown
+        job?.reportProgress(nil, "Loading exported meshes...")
+        let manifestData = try Data(contentsOf: outputDir.appendingPathComponent("manifest.json"))
+        let entries = try JSONDecoder().decode([ManifestEntry].self, from: manifestData)
+
+        var triangles: [Triangle] = []
+        for entry in entries {
+            try job?.checkCancellation()
+            triangles.append(contentsOf: try STLParser.parse(url: outputDir.appendingPathComponent(entry.file)).triangles)
+        }
+        guard !triangles.isEmpty else {
+            throw PythonCADError.noResult(messages: messages)
+        }
+
+        let model = STLModel(triangles: triangles, name: script.deletingPathExtension().lastPathComponent)
+        return RenderResult(model: model, messages: messages, objectCount: entries.count)
+    }
+
+    private struct ManifestEntry: Decodable {
+        let file: String
+        let name: String?
+    }
+
+    /// Printed lines, plus warnings Python wrote to stderr
+    static func parseMessages(stdout: String, stderr: String) -> [String] {
+        var messages = stdout.components(separatedBy: .newlines)
+            .map { $0.trimmingCharacters(in: .whitespaces) }
+            .filter { !$0.isEmpty }
+
+        for line in stderr.components(separatedBy: .newlines) {
+            let trimmed = line.trimmingCharacters(in: .whitespaces)
+            if trimmed.contains("Warning:") || trimmed.hasPrefix("GOSTL:") {
+                messages.append(trimmed)
+            }
+        }
+
+        return messages
+    }
+
+    /// Runs the script and exports what it shows; argv: script, output directory
+    static let bootstrapScript = #"""
+        import json
+        import os
+        import runpy
+        import sys
+        import types
+
+        script, output_dir = sys.argv[1], sys.argv[2]
+        shown = []
+
+        def show_object(obj, name=None, options=None, **kwargs):
+            shown.append((obj, name))
+
+        def show(*objs, names=None, **kwargs):
+            for index, obj in enumerate(objs):
+                shown.append((obj, names[index] if names and index < len(names) else None))
+
+        class _Ignored:
+            """Stands in for any other viewer function or constant (set_port, Camera.KEEP, ...)"""
+            def __getattr__(self, attribute):
+                return self
+
+            def __call__(self, *args, **kwargs):
+                return self
+
+        # Viewer modules used by scripts are replaced, so `from ocp_vscode import show` captures too
+        for module_name in ("ocp_vscode", "cq_editor"):
+            stub = types.ModuleType(module_name)
+            stub.show = show
+            stub.show_object = show_object
+            stub.__getattr__ = lambda attribute: _Ignored()
+            sys.modules[module_name] = stub
+
+        sys.path.insert(0, os.path.dirname(os.path.abspath(script)))
+        sys.argv = [script]
+        namespace = runpy.run_path(
+            script,
+            init_globals={"show_object": show_object, "show": show},
+            run_name="__main__",
+        )
+
+        if not shown:
+            for variable in ("result", "part", "assembly"):
+                if namespace.get(variable) is not None:
+                    shown.append((namespace[variable], variable))
+                    break
+
+        if not shown:
+            print("GOSTL: nothing to export - call show_object()/show() or assign `result`", file=sys.stderr)
+            sys.exit(3)
+
+        def export(obj, path):
+            # build123d builders (BuildPart) hold their shape in .part
+            inner = getattr(obj, "part", None)
+            if inner is not None and not callable(inner):
+                obj = inner
+            try:
+                import build123d
+                if isinstance(obj, build123d.Shape):
+                    build123d.export_stl(obj, path, tolerance=0.01, angular_tolerance=0.1)
+                    return
+            except ImportError:
+                pass
+            try:
+                import cadquery
+                if isinstance(obj, cadquery.Assembly):
+                    obj = obj.toCompound()
+                cadquery.exporters.export(obj, path, exportType="STL", tolerance=0.01, angularTolerance=0.1)
+                return
+            except ImportError:
+                pass
+            raise TypeError("cannot export %s: neither build123d nor cadquery handles it" % type(obj).__name__)
+
+        manifest = []
+        for index, (obj, name) in enumerate(shown):
+            file = "object_%d.stl" % index
+            export(obj, os.path.join(output_dir, file))
+            manifest.append({"file": file, "name": name})
+
+        with open(os.path.join(output_dir, "manifest.json"), "w") as handle:
+            json.dump(manifest, handle)
+        """#
+
+    // MARK: - Dependencies
+
+    /// The script and the local modules it imports (recursively), for file watching
+    func resolveDependencies(script: URL) -> [URL] {
+        var visited = Set<URL>()
+        var deps: [URL] = []
+        resolveDependenciesRecursive(file: script.standardizedFileURL, rootDir: script.deletingLastPathComponent(), visited: &visited, deps: &deps)
+        return deps
+    }
+
+    private func resolveDependenciesRecursive(file: URL, rootDir: URL, visited: inout Set<URL>, deps: inout [URL]) {
+        guard !visited.contains(file) else { return }
+        visited.insert(file)
+
+        guard let source = try? String(contentsOf: file, encoding: .utf8) else {
+            print("PythonCADRenderer: Skipping missing dependency: \(file.lastPathComponent)")
+            return
+        }
+        deps.append(file)
+
+        for module in Self.importedModules(in: source) {
+            if let url = Self.resolveModule(module, from: file.deletingLastPathComponent(), rootDir: rootDir) {
+                resolveDependenciesRecursive(file: url, rootDir: rootDir, visited: &visited, deps: &deps)
+            }
+        }
+    }
+
+    /// Module names of `import` and `from ... import` statements; relative names keep their dots
+    ///
+    /// For `from pkg import name` both "pkg" and "pkg.name" are returned, since the name may be a submodule.
+    static func importedModules(in source: String) -> [String] {
+        var modules: [String] = []
+
+        for line in source.components(separatedBy: .newlines) {
+            let trimmed = line.trimmingCharacters(in: .whitespaces)
+
+            if trimmed.hasPrefix("import ") {
+                for part in trimmed.dropFirst("import ".count).components(separatedBy: ",") {
+                    // "pkg.mod as alias"
+                    if let name = part.split(separator: " ", omittingEmptySubsequences: true).first {
+                        modules.append(String(name))
+                    }
+                }
+            } else if trimmed.hasPrefix("from "),
+                      let importRange = trimmed.range(of: " import ") {
+                let module = trimmed[trimmed.index(trimmed.startIndex, offsetBy: 5)..<importRange.lowerBound]
+                    .trimmingCharacters(in: .whitespaces)
+                guard !module.isEmpty else { continue }
+                if !module.allSatisfy({ $0 == "." }) {
+                    modules.append(module)
+                }
+
+                let names = trimmed[importRange.upperBound...]
+                    .replacingOccurrences(of: "(", with: "")
+                    .replacingOccurrences(of: ")", with: "")
+                for part in names.components(separatedBy: ",") {
+                    guard let name = part.split(separator: " ", omittingEmptySubsequences: true).first,
+                          name != "*", name != "\\" else { continue }
+                    let separator = module.hasSuffix(".") ? "" : "."
+                    modules.append(module + separator + name)
+                }
+            }
+        }
+
+        return modules
+    }
+
+    /// File of a local module (`name.py` or `name/__init__.py`), nil for installed packages
+    static func resolveModule(_ module: String, from directory: URL, rootDir: URL) -> URL? {
+        var base = rootDir
+        var name = Substring(module)
+
+        // Relative import: one dot is the importing file's package, each further dot one level up
+        if name.hasPrefix(".") {
+            base = directory
+            name = name.dropFirst()
+            while name.hasPrefix(".") {
+                base = base.deletingLastPathComponent()
+                name = name.dropFirst()
+            }
+        }
+
+        let components = name.split(separator: ".").map(String.init)
+        guard !components.isEmpty else { return nil }
+        let path = components.reduce(base) { $0.appendingPathComponent($1) }
+
+        let candidates = [
+            path.appendingPathExtension("py"),
+            path.appendingPathComponent("__init__.py")
+        ]
+        return candidates.first { FileManager.default.fileExists(atPath: $0.path) }?.standardizedFileURL
+    }
+}
+
+/// Errors that can occur while rendering Python CAD scripts
+enum PythonCADError: LocalizedError {
+    case interpreterNotFound(String?)
+    case scriptFailed(String, messages: [String])
+    case noResult(messages: [String])
+
+    var errorDescription: String? {
+        switch self {
+        case .interpreterNotFound(let path?):
+            return "Python interpreter not found: \(path). Check Settings > Files or \(PythonCADRenderer.environmentKey)."
+        case .interpreterNotFound(nil):
+            return "Python 3 not found. Set the interpreter of your CadQuery/build123d environment in Settings > Files."
+        case .scriptFailed(let message, _):
+            return message
+        case .noResult:
+            return "The script produced nothing to show. Call show_object()/show() or assign the shape to `result`."
+        }
+    }
+
+    /// Get messages associated with the error (printed output and warnings)
+    var messages: [String] {
+        switch self {
+        case .interpreterNotFound:
+            return []
+        case .scriptFailed(_, let messages), .noResult(let messages):
+            return messages
+        }
+    }
+}
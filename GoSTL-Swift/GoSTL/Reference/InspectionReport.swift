@@ -0,0 +1,139 @@
+import Foundation
+
+/// Generates a plain-text inspection report of model statistics, reference geometry, reference measurements and tolerance checks
+enum InspectionReport {
+    /// Build the report
+    /// - Parameters:
+    ///   - fileName: Name of the inspected file
+    ///   - date: Report date
+    ///   - referenceGeometry: Entities, measurements and tolerance checks to report
+    ///   - coordinateOffset: Offset from render space back to the file's original coordinates
+    ///   - analysis: Model statistics (in file coordinates) for the MODEL section, omitted if nil
+    ///   - properties: User-defined model properties for the PROPERTIES section, omitted if empty
+    ///   - sections: Saved cross-sections with the file name of their image for the SECTIONS section, omitted if empty
+    ///   - annotations: Pinned text annotations (original coordinates) for the ANNOTATIONS section, omitted if empty
+    ///   - precision: Decimal places of numbers, coordinates and reference measurements, and the unit of lengths
+    static func generate(
+        fileName: String,
+        date: Date = Date(),
+        referenceGeometry: ReferenceGeometrySystem,
+        coordinateOffset: Vector3 = .zero,
+        analysis: AnalysisRecord? = nil,
+        properties: [ModelProperty] = [],
+        sections: [(bookmark: SectionBookmark, imageFile: String?)] = [],
+        annotations: [Annotation] = [],
+        precision: DisplayPrecision = .report
+    ) -> String {
+        var lines: [String] = []
+        lines.append("GoSTL Inspection Report")
+        lines.append("File: \(fileName)")
+        lines.append("Date: \(ISO8601DateFormatter().string(from: date))")
+        if precision.unit != .millimeters || precision.engineeringNotation {
+            lines.append("Lengths: \(precision.unit.symbol)\(precision.engineeringNotation ? " (engineering notation)" : "")")
+        }
+
+        if let analysis {
+            lines.append("")
+            lines.append("MODEL")
+            for field in analysis.fields where field.key != "file" {
+                let value: String
+                switch field.value {
+                case .number(let number): value = precision.number(number)
+                default: value = field.value.plain
+                }
+                lines.append("  \(field.label): \(value)\(field.unit.map { " \($0)" } ?? "")")
+            }
+        }
+
+        if !properties.isEmpty {
+            lines.append("")
+            lines.append("PROPERTIES")
+            for property in properties {
+                lines.append("  \(property.key): \(property.value)")
+            }
+        }
+
+        if !sections.isEmpty {
+            lines.append("")
+            lines.append("SECTIONS")
+            for section in sections {
+                var line = "  \(section.bookmark.name): \(section.bookmark.summary())"
+                if let imageFile = section.imageFile {
+                    line += " [\(imageFile)]"
+                }
+                lines.append(line)
+            }
+        }
+
+        if !annotations.isEmpty {
+            lines.append("")
+            lines.append("ANNOTATIONS")
+            for (index, annotation) in annotations.enumerated() {
+                lines.append("  \(index + 1). \(annotation.summary(precision: precision))")
+            }
+        }
+
+        lines.append("")
+        lines.append("REFERENCE GEOMETRY")
+        if referenceGeometry.entities.isEmpty {
+            lines.append("  (none)")
+        }
+        for entity in referenceGeometry.entities {
+            lines.append("  \(entity.name): \(describe(entity.kind.translated(by: coordinateOffset), precision: precision))")
+        }
+
+        lines.append("")
+        lines.append("REFERENCE MEASUREMENTS")
+        if referenceGeometry.measurements.isEmpty {
+            lines.append("  (none)")
+        }
+        for measurement in referenceGeometry.measurements {
+            let value = referenceGeometry.result(for: measurement)?.formattedValue(precision: precision) ?? "-"
+            lines.append("  \(referenceGeometry.title(for: measurement)): \(value)")
+        }
+
+        lines.append("")
+        lines.append("TOLERANCE CHECKS")
+        if referenceGeometry.toleranceChecks.isEmpty {
+            lines.append("  (none)")
+        }
+        var passedCount = 0
+        for check in referenceGeometry.toleranceChecks {
+            let title = referenceGeometry.title(for: check)
+            guard let result = referenceGeometry.result(for: check) else {
+                lines.append("  [----] \(title): not evaluable")
+                continue
+            }
+            if result.passed {
+                passedCount += 1
+            }
+            var line = "  [\(result.passed ? "PASS" : "FAIL")] \(title): \(result.formattedValue)"
+            if let nominal = check.nominal {
+                let original = nominal + coordinateOffset
+                line += " (nominal \(precision.point(original)))"
+            }
+            lines.append(line)
+        }
+
+        let total = referenceGeometry.toleranceChecks.count
+        if total > 0 {
+            lines.append("")
+            lines.append("SUMMARY")
+            lines.append("  \(passedCount) of \(total) checks passed")
+            lines.append("  Result: \(passedCount == total ? "PASS" : "FAIL")")
+        }
+
+        return lines.joined(separator: "\n") + "\n"
+    }
+
+    private static func describe(_ kind: ReferenceEntity.Kind, precision: DisplayPrecision) -> String {
+        switch kind {
+        case .plane(let origin, let normal):
+            return "plane origin \(precision.point(origin)) normal \(precision.direction(normal))"
+        case .axis(let origin, let direction):
+            return "axis origin \(precision.point(origin)) direction \(precision.direction(direction))"
+        case .point(let position):
+            return "point \(precision.point(position))"
+        }
+    }
+}
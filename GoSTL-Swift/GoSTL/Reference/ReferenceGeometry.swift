@@ -0,0 +1,347 @@
+import Foundation
+
+/// A user-defined datum (plane, axis or point) used as a measurement reference
+struct ReferenceEntity: Identifiable, Codable, Equatable {
+    /// Geometry of a reference entity
+    enum Kind: Codable, Equatable {
+        case plane(origin: Vector3, normal: Vector3)
+        case axis(origin: Vector3, direction: Vector3)
+        case point(Vector3)
+    }
+
+    let id: UUID
+    var name: String
+    var kind: Kind
+    var isVisible: Bool
+
+    init(name: String, kind: Kind, isVisible: Bool = true) {
+        self.id = UUID()
+        self.name = name
+        self.kind = kind
+        self.isVisible = isVisible
+    }
+
+    /// The same entity (same id) moved by the given offset, e.g. between render and file coordinates
+    func translated(by offset: Vector3) -> ReferenceEntity {
+        var moved = self
+        moved.kind = kind.translated(by: offset)
+        return moved
+    }
+}
+
+// MARK: - Geometry Queries
+
+extension ReferenceEntity.Kind {
+    /// Singular type name used for auto-naming and display ("Plane", "Axis", "Point")
+    var typeName: String {
+        switch self {
+        case .plane: return "Plane"
+        case .axis: return "Axis"
+        case .point: return "Point"
+        }
+    }
+
+    /// Origin of a plane or axis, or the position of a point
+    var anchor: Vector3 {
+        switch self {
+        case .plane(let origin, _): return origin
+        case .axis(let origin, _): return origin
+        case .point(let position): return position
+        }
+    }
+
+    /// Plane normal or axis direction (nil for points)
+    var direction: Vector3? {
+        switch self {
+        case .plane(_, let normal): return normal
+        case .axis(_, let direction): return direction
+        case .point: return nil
+        }
+    }
+
+    /// Closest point on the entity to the given position
+    func closestPoint(to position: Vector3) -> Vector3 {
+        switch self {
+        case .plane(let origin, let normal):
+            return position - normal * (position - origin).dot(normal)
+        case .axis(let origin, let direction):
+            return origin + direction * (position - origin).dot(direction)
+        case .point(let point):
+            return point
+        }
+    }
+
+    /// Shortest distance from the given position to the entity
+    func distance(to position: Vector3) -> Double {
+        closestPoint(to: position).distance(to: position)
+    }
+
+    /// A copy of the entity moved by the given offset
+    func translated(by offset: Vector3) -> ReferenceEntity.Kind {
+        switch self {
+        case .plane(let origin, let normal): return .plane(origin: origin + offset, normal: normal)
+        case .axis(let origin, let direction): return .axis(origin: origin + offset, direction: direction)
+        case .point(let position): return .point(position + offset)
+        }
+    }
+}
+
+// MARK: - Construction
+
+extension ReferenceEntity.Kind {
+    /// Tolerance below which vectors are considered degenerate (coincident or collinear points)
+    static let degenerateTolerance = 1e-9
+
+    /// Plane through three points, centered at their centroid
+    /// - Returns: nil if the points are collinear
+    static func plane(through p1: Vector3, _ p2: Vector3, _ p3: Vector3) -> Self? {
+        let normal = (p2 - p1).cross(p3 - p1)
+        guard normal.length > degenerateTolerance else { return nil }
+        return .plane(origin: (p1 + p2 + p3) / 3.0, normal: normal.normalized())
+    }
+
+    /// Plane parallel to a face, shifted along the face normal
+    /// - Parameters:
+    ///   - point: Point picked on the face
+    ///   - normal: Face normal at the picked point
+    ///   - offset: Signed distance along the normal (positive = outwards)
+    /// - Returns: nil if the normal is degenerate
+    static func plane(offsetFrom point: Vector3, normal: Vector3, by offset: Double) -> Self? {
+        guard normal.length > degenerateTolerance else { return nil }
+        let unitNormal = normal.normalized()
+        return .plane(origin: point + unitNormal * offset, normal: unitNormal)
+    }
+
+    /// Axis through two points, anchored at their midpoint
+    /// - Returns: nil if the points coincide
+    static func axis(through p1: Vector3, _ p2: Vector3) -> Self? {
+        let direction = p2 - p1
+        guard direction.length > degenerateTolerance else { return nil }
+        return .axis(origin: (p1 + p2) * 0.5, direction: direction.normalized())
+    }
+
+    /// Axis of a cylinder, fitted through points picked around its circumference
+    /// - Returns: nil if fewer than three points are given or no circle fits them
+    static func axis(fittingCylinder points: [Vector3]) -> Self? {
+        guard let circle = Circle.fit(points: points) else { return nil }
+        return .axis(origin: circle.center, direction: circle.normal)
+    }
+
+    /// Intersection point of two or three entities
+    ///
+    /// Supported combinations:
+    /// - axis and plane: where the axis pierces the plane
+    /// - two axes: midpoint of their closest approach (exact intersection when they meet)
+    /// - three planes: the common corner point
+    /// - Returns: nil for unsupported combinations or parallel entities
+    static func intersection(of kinds: [Self]) -> Vector3? {
+        switch kinds.count {
+        case 2:
+            switch (kinds[0], kinds[1]) {
+            case (.axis(let axisOrigin, let direction), .plane(let planeOrigin, let normal)),
+                 (.plane(let planeOrigin, let normal), .axis(let axisOrigin, let direction)):
+                return intersectAxis(origin: axisOrigin, direction: direction, withPlane: planeOrigin, normal: normal)
+            case (.axis(let origin1, let direction1), .axis(let origin2, let direction2)):
+                return closestApproach(origin1: origin1, direction1: direction1, origin2: origin2, direction2: direction2)
+            default:
+                return nil
+            }
+        case 3:
+            guard case .plane(let o1, let n1) = kinds[0],
+                  case .plane(let o2, let n2) = kinds[1],
+                  case .plane(let o3, let n3) = kinds[2] else {
+                return nil
+            }
+            return intersectPlanes((o1, n1), (o2, n2), (o3, n3))
+        default:
+            return nil
+        }
+    }
+
+    /// Intersection line of two planes
+    ///
+    /// The axis is anchored at the point on the line closest to the midpoint of both plane origins,
+    /// so it is drawn near the datums it was created from.
+    /// - Returns: nil if either entity is not a plane or the planes are parallel
+    static func intersectionLine(of first: Self, _ second: Self) -> Self? {
+        guard case .plane(let origin1, let normal1) = first,
+              case .plane(let origin2, let normal2) = second else {
+            return nil
+        }
+
+        let direction = normal1.cross(normal2)
+        let lengthSquared = direction.lengthSquared
+        guard lengthSquared > degenerateTolerance else { return nil }
+
+        // Point satisfying both plane equations: (d1 (n2 × d) + d2 (d × n1)) / |d|²
+        let d1 = normal1.dot(origin1)
+        let d2 = normal2.dot(origin2)
+        let pointOnLine = (normal2.cross(direction) * d1 + direction.cross(normal1) * d2) / lengthSquared
+
+        let unitDirection = direction.normalized()
+        let center = (origin1 + origin2) * 0.5
+        let anchor = pointOnLine + unitDirection * (center - pointOnLine).dot(unitDirection)
+        return .axis(origin: anchor, direction: unitDirection)
+    }
+
+    /// Point where a segment crosses a plane
+    /// - Returns: nil if the entity is not a plane, the segment is parallel to it or doesn't reach it
+    static func intersection(ofSegmentFrom start: Vector3, to end: Vector3, withPlane plane: Self) -> Vector3? {
+        guard case .plane(let origin, let normal) = plane else { return nil }
+
+        let segment = end - start
+        let denominator = normal.dot(segment)
+        guard abs(denominator) > degenerateTolerance else { return nil }
+
+        let t = normal.dot(origin - start) / denominator
+        guard t >= -degenerateTolerance && t <= 1 + degenerateTolerance else { return nil }
+        return start + segment * t
+    }
+
+    private static func intersectAxis(origin: Vector3, direction: Vector3, withPlane planeOrigin: Vector3, normal: Vector3) -> Vector3? {
+        let denominator = normal.dot(direction)
+        guard abs(denominator) > degenerateTolerance else { return nil }
+        let t = normal.dot(planeOrigin - origin) / denominator
+        return origin + direction * t
+    }
+
+    private static func closestApproach(origin1: Vector3, direction1: Vector3, origin2: Vector3, direction2: Vector3) -> Vector3? {
+        let b = direction1.dot(direction2)
+        guard 1 - b * b > degenerateTolerance else { return nil }
+
+        let (closest1, closest2) = closestPoints(origin1: origin1, direction1: direction1, origin2: origin2, direction2: direction2)
+        return (closest1 + closest2) * 0.5
+    }
+
+    private static func intersectPlanes(
+        _ plane1: (origin: Vector3, normal: Vector3),
+        _ plane2: (origin: Vector3, normal: Vector3),
+        _ plane3: (origin: Vector3, normal: Vector3)
+    ) -> Vector3? {
+        let n1 = plane1.normal, n2 = plane2.normal, n3 = plane3.normal
+        let denominator = n1.dot(n2.cross(n3))
+        guard abs(denominator) > degenerateTolerance else { return nil }
+
+        let d1 = n1.dot(plane1.origin)
+        let d2 = n2.dot(plane2.origin)
+        let d3 = n3.dot(plane3.origin)
+        return (n2.cross(n3) * d1 + n3.cross(n1) * d2 + n1.cross(n2) * d3) / denominator
+    }
+}
+
+// MARK: - Measurement Between Entities
+
+/// Distance and angle between two reference entities
+struct ReferenceMeasurementResult: Equatable {
+    /// Minimum distance in mm (nil when the entities cross at an angle, e.g. non-parallel planes)
+    var distance: Double?
+
+    /// Angle in degrees between 0 and 90 (nil when a point is involved)
+    var angle: Double?
+
+    /// Endpoints of the 3D annotation line
+    var from: Vector3
+    var to: Vector3
+
+    /// Display text, e.g. "12.00 mm  0.05°"
+    var formattedValue: String {
+        formattedValue(precision: DisplayPrecision(lengthDecimals: 2, angleDecimals: 2))
+    }
+
+    func formattedValue(precision: DisplayPrecision) -> String {
+        var parts: [String] = []
+        if let distance {
+            parts.append(precision.lengthWithUnit(distance))
+        }
+        if let angle {
+            parts.append(precision.angle(angle))
+        }
+        return parts.joined(separator: "  ")
+    }
+}
+
+extension ReferenceEntity.Kind {
+    /// Planes (or an axis and a plane) closer to parallel than this report a distance (degrees)
+    static let parallelDistanceThreshold: Double = 1.0
+
+    /// Measure the distance and angle between two entities
+    ///
+    /// - plane/plane: angle between the planes; distance from the second origin to the first plane when nearly parallel
+    /// - axis/axis: angle between the axes and their minimum distance (concentricity offset for parallel axes)
+    /// - axis/plane: angle between axis and plane; distance from the axis to the plane when nearly parallel
+    /// - point/any: distance from the point to the entity
+    static func measure(_ first: Self, _ second: Self) -> ReferenceMeasurementResult? {
+        switch (first, second) {
+        case (.plane(let origin1, let normal1), .plane(let origin2, let normal2)):
+            let angle = angleBetween(normal1, normal2)
+            let foot = first.closestPoint(to: origin2)
+            let distance = angle < parallelDistanceThreshold ? origin2.distance(to: foot) : nil
+            return ReferenceMeasurementResult(distance: distance, angle: angle, from: distance != nil ? foot : origin1, to: origin2)
+
+        case (.axis(let origin1, let direction1), .axis(let origin2, let direction2)):
+            let angle = angleBetween(direction1, direction2)
+            let closest = closestPoints(origin1: origin1, direction1: direction1, origin2: origin2, direction2: direction2)
+            return ReferenceMeasurementResult(
+                distance: closest.0.distance(to: closest.1),
+                angle: angle,
+                from: closest.0,
+                to: closest.1
+            )
+
+        case (.axis, .plane):
+            return measureAxis(first, toPlane: second)
+
+        case (.plane, .axis):
+            return measureAxis(second, toPlane: first)
+
+        case (.point(let position), _):
+            let foot = second.closestPoint(to: position)
+            return ReferenceMeasurementResult(distance: position.distance(to: foot), angle: nil, from: position, to: foot)
+
+        case (_, .point(let position)):
+            let foot = first.closestPoint(to: position)
+            return ReferenceMeasurementResult(distance: position.distance(to: foot), angle: nil, from: foot, to: position)
+        }
+    }
+
+    private static func measureAxis(_ axis: Self, toPlane plane: Self) -> ReferenceMeasurementResult? {
+        guard case .axis(let axisOrigin, let direction) = axis,
+              case .plane(_, let normal) = plane else {
+            return nil
+        }
+
+        // Angle between a line and a plane is the complement of the angle to the normal
+        let angle = max(0, 90 - angleBetween(direction, normal))
+        if angle < parallelDistanceThreshold {
+            let foot = plane.closestPoint(to: axisOrigin)
+            return ReferenceMeasurementResult(distance: axisOrigin.distance(to: foot), angle: angle, from: axisOrigin, to: foot)
+        }
+
+        let pierce = intersection(of: [axis, plane]) ?? axisOrigin
+        return ReferenceMeasurementResult(distance: nil, angle: angle, from: axisOrigin, to: pierce)
+    }
+
+    /// Acute angle between two lines given by their directions (degrees, 0...90)
+    private static func angleBetween(_ a: Vector3, _ b: Vector3) -> Double {
+        let cosine = min(abs(a.normalized().dot(b.normalized())), 1.0)
+        return acos(cosine) * 180.0 / .pi
+    }
+
+    /// Closest points between two lines (falls back to a perpendicular for parallel lines)
+    private static func closestPoints(origin1: Vector3, direction1: Vector3, origin2: Vector3, direction2: Vector3) -> (Vector3, Vector3) {
+        let b = direction1.dot(direction2)
+        let denominator = 1 - b * b
+        guard denominator > degenerateTolerance else {
+            // Parallel: drop a perpendicular from the second origin onto the first line
+            let foot = origin1 + direction1 * (origin2 - origin1).dot(direction1)
+            return (foot, origin2)
+        }
+
+        let w = origin1 - origin2
+        let d = direction1.dot(w)
+        let e = direction2.dot(w)
+        let s = (b * e - d) / denominator
+        let t = (e - b * d) / denominator
+        return (origin1 + direction1 * s, origin2 + direction2 * t)
+    }
+}
@@ -0,0 +1,401 @@
+import Foundation
+import Observation
+
+/// Interactive tools for creating reference entities from picked points
+enum ReferenceTool: CaseIterable {
+    case point               // Point picked on the model surface
+    case planeThroughPoints  // Plane through 3 picked points
+    case planeOffsetFromFace // Plane parallel to the picked face, shifted by faceOffset
+    case axisThroughPoints   // Axis through 2 picked points
+    case axisFromCylinder    // Cylinder axis fitted through 3 points on its circumference
+
+    /// Number of picked points needed to create the entity
+    var pointsNeeded: Int {
+        switch self {
+        case .point: return 1
+        case .planeThroughPoints: return 3
+        case .planeOffsetFromFace: return 1
+        case .axisThroughPoints: return 2
+        case .axisFromCylinder: return 3
+        }
+    }
+
+    /// Menu/button title
+    var title: String {
+        switch self {
+        case .point: return "Point"
+        case .planeThroughPoints: return "Plane (3 Points)"
+        case .planeOffsetFromFace: return "Plane (Offset Face)"
+        case .axisThroughPoints: return "Axis (2 Points)"
+        case .axisFromCylinder: return "Axis (Cylinder)"
+        }
+    }
+
+    /// Instruction shown while the tool is collecting points
+    var instruction: String {
+        switch self {
+        case .point: return "Click a point on the model"
+        case .planeThroughPoints: return "Click 3 points on the plane"
+        case .planeOffsetFromFace: return "Click a face to offset"
+        case .axisThroughPoints: return "Click 2 points on the axis"
+        case .axisFromCylinder: return "Click 3 points around the cylinder"
+        }
+    }
+}
+
+/// A persistent distance/angle measurement between two reference entities
+/// Values are computed live so the measurement follows edits to either entity
+struct ReferenceMeasurement: Identifiable, Codable, Equatable {
+    let id: UUID
+    let firstID: UUID
+    let secondID: UUID
+
+    init(firstID: UUID, secondID: UUID) {
+        self.id = UUID()
+        self.firstID = firstID
+        self.secondID = secondID
+    }
+}
+
+/// Manages user-defined reference geometry (datum planes, axes and points)
+@Observable
+final class ReferenceGeometrySystem: @unchecked Sendable {
+    /// All reference entities, in creation order
+    var entities: [ReferenceEntity] = [] {
+        didSet {
+            onEntitiesChanged?(entities)
+        }
+    }
+
+    /// Called whenever entities are added, removed or changed
+    @ObservationIgnored
+    var onEntitiesChanged: (([ReferenceEntity]) -> Void)?
+
+    /// Measurements between pairs of entities
+    var measurements: [ReferenceMeasurement] = []
+
+    /// Geometric tolerance checks evaluated against picked surfaces and entities
+    var toleranceChecks: [ToleranceCheck] = []
+
+    /// Active creation tool (nil = not creating)
+    var activeTool: ReferenceTool?
+
+    /// Points picked so far for the active tool
+    var collectedPoints: [MeasurementPoint] = []
+
+    /// Hover point (preview of where the next point would be picked)
+    var hoverPoint: MeasurementPoint?
+
+    /// Offset along the face normal for planeOffsetFromFace (mm, positive = outwards)
+    var faceOffset: Double = 0
+
+    /// Entities selected for creating an intersection point or a measurement
+    var selectedIDs: Set<UUID> = []
+
+    /// Feedback from the last creation attempt (e.g. why it failed)
+    var statusMessage: String?
+
+    /// Half-extent used to draw planes and axes (kept in sync with the model size by AppState)
+    var displaySize: Double = 50
+
+    /// Next number used for auto-naming per entity type ("Plane 1", "Axis 2", ...)
+    @ObservationIgnored private var nameCounters: [String: Int] = [:]
+
+    /// Whether a creation tool is collecting points
+    var isCollecting: Bool {
+        activeTool != nil
+    }
+
+    /// Progress text for the active tool (e.g. "1 / 3")
+    var progressText: String {
+        guard let activeTool else { return "" }
+        return "\(collectedPoints.count) / \(activeTool.pointsNeeded)"
+    }
+
+    /// Entities currently selected for intersection, in list order
+    var selectedEntities: [ReferenceEntity] {
+        entities.filter { selectedIDs.contains($0.id) }
+    }
+
+    /// Whether the current selection can be intersected into a point
+    var canCreateIntersection: Bool {
+        ReferenceEntity.Kind.intersection(of: selectedEntities.map(\.kind)) != nil
+    }
+
+    /// Whether the current selection is two planes that meet in a line
+    var canCreateIntersectionLine: Bool {
+        let selected = selectedEntities
+        return selected.count == 2 && ReferenceEntity.Kind.intersectionLine(of: selected[0].kind, selected[1].kind) != nil
+    }
+
+    /// The single selected plane, used as the datum for segment intersections
+    var selectedPlane: ReferenceEntity? {
+        let selected = selectedEntities
+        guard selected.count == 1, case .plane = selected[0].kind else { return nil }
+        return selected[0]
+    }
+
+    /// Whether exactly two entities are selected for measuring
+    var canCreateMeasurement: Bool {
+        selectedIDs.count == 2
+    }
+
+    // MARK: - Creation Tools
+
+    /// Start collecting points for a new entity
+    func startTool(_ tool: ReferenceTool) {
+        activeTool = tool
+        collectedPoints = []
+        hoverPoint = nil
+        statusMessage = nil
+    }
+
+    /// Cancel the active tool without creating anything
+    func cancelTool() {
+        activeTool = nil
+        collectedPoints = []
+        hoverPoint = nil
+    }
+
+    /// Add a picked point to the active tool
+    /// - Returns: The created entity once enough points are collected, nil otherwise
+    @discardableResult
+    func addPoint(_ point: MeasurementPoint) -> ReferenceEntity? {
+        guard let tool = activeTool else { return nil }
+
+        collectedPoints.append(point)
+        guard collectedPoints.count >= tool.pointsNeeded else { return nil }
+
+        let positions = collectedPoints.map(\.position)
+        let kind: ReferenceEntity.Kind?
+        switch tool {
+        case .point:
+            kind = .point(positions[0])
+        case .planeThroughPoints:
+            kind = .plane(through: positions[0], positions[1], positions[2])
+        case .planeOffsetFromFace:
+            kind = .plane(offsetFrom: positions[0], normal: collectedPoints[0].normal, by: faceOffset)
+        case .axisThroughPoints:
+            kind = .axis(through: positions[0], positions[1])
+        case .axisFromCylinder:
+            kind = .axis(fittingCylinder: positions)
+        }
+
+        cancelTool()
+
+        guard let kind else {
+            statusMessage = "Could not create \(tool.title.lowercased()): points are degenerate"
+            return nil
+        }
+        return add(kind)
+    }
+
+    /// Create a point at the intersection of the selected entities
+    /// - Returns: The created point, or nil if the selection doesn't intersect
+    @discardableResult
+    func createIntersectionPoint() -> ReferenceEntity? {
+        guard let position = ReferenceEntity.Kind.intersection(of: selectedEntities.map(\.kind)) else {
+            statusMessage = "Select an axis and a plane, two axes, or three planes that intersect"
+            return nil
+        }
+        selectedIDs.removeAll()
+        return add(.point(position))
+    }
+
+    /// Create an axis along the intersection line of the two selected planes
+    /// - Returns: The created axis, or nil if the selection isn't two non-parallel planes
+    @discardableResult
+    func createIntersectionLine() -> ReferenceEntity? {
+        let selected = selectedEntities
+        guard selected.count == 2,
+              let kind = ReferenceEntity.Kind.intersectionLine(of: selected[0].kind, selected[1].kind) else {
+            statusMessage = "Select two planes that are not parallel"
+            return nil
+        }
+        selectedIDs.removeAll()
+        return add(kind)
+    }
+
+    /// Create points where measurement segments cross the selected plane
+    /// - Parameter segments: Start and end of each distance measurement to test
+    /// - Returns: The created points (empty if no plane is selected or no segment reaches it)
+    @discardableResult
+    func createSegmentIntersections(with segments: [(start: Vector3, end: Vector3)]) -> [ReferenceEntity] {
+        guard let plane = selectedPlane else {
+            statusMessage = "Select one plane to intersect measurements with"
+            return []
+        }
+
+        let positions = segments.compactMap {
+            ReferenceEntity.Kind.intersection(ofSegmentFrom: $0.start, to: $0.end, withPlane: plane.kind)
+        }
+        guard !positions.isEmpty else {
+            statusMessage = "No selected measurement crosses \(plane.name)"
+            return []
+        }
+
+        selectedIDs.removeAll()
+        let created = positions.map { add(.point($0)) }
+        if created.count > 1 {
+            statusMessage = "Created \(created.count) points on \(plane.name)"
+        }
+        return created
+    }
+
+    /// Add an entity with an automatically generated name
+    @discardableResult
+    func add(_ kind: ReferenceEntity.Kind) -> ReferenceEntity {
+        let typeName = kind.typeName
+        let number = (nameCounters[typeName] ?? 0) + 1
+        nameCounters[typeName] = number
+
+        let entity = ReferenceEntity(name: "\(typeName) \(number)", kind: kind)
+        entities.append(entity)
+        statusMessage = "Created \(entity.name)"
+        return entity
+    }
+
+    // MARK: - Measurements
+
+    /// Measure between the two selected entities (in list order, so the first acts as the datum)
+    /// - Parameter precision: Formatting of the value in the status message
+    @discardableResult
+    func createMeasurement(precision: DisplayPrecision = .standard) -> ReferenceMeasurement? {
+        let selected = selectedEntities
+        guard selected.count == 2 else {
+            statusMessage = "Select exactly two entities to measure"
+            return nil
+        }
+
+        let measurement = ReferenceMeasurement(firstID: selected[0].id, secondID: selected[1].id)
+        measurements.append(measurement)
+        selectedIDs.removeAll()
+        if let result = result(for: measurement) {
+            statusMessage = "\(selected[0].name) → \(selected[1].name): \(result.formattedValue(precision: precision))"
+        }
+        return measurement
+    }
+
+    /// Current distance/angle for a measurement (nil if an entity no longer exists)
+    func result(for measurement: ReferenceMeasurement) -> ReferenceMeasurementResult? {
+        guard let first = entity(id: measurement.firstID),
+              let second = entity(id: measurement.secondID) else {
+            return nil
+        }
+        return ReferenceEntity.Kind.measure(first.kind, second.kind)
+    }
+
+    /// Display title for a measurement, e.g. "Plane 1 → Plane 2"
+    func title(for measurement: ReferenceMeasurement) -> String {
+        let first = entity(id: measurement.firstID)?.name ?? "?"
+        let second = entity(id: measurement.secondID)?.name ?? "?"
+        return "\(first) → \(second)"
+    }
+
+    /// Whether both entities of a measurement are visible (hidden entities hide their annotations)
+    func isVisible(_ measurement: ReferenceMeasurement) -> Bool {
+        entity(id: measurement.firstID)?.isVisible == true && entity(id: measurement.secondID)?.isVisible == true
+    }
+
+    /// Delete a measurement
+    func removeMeasurement(id: UUID) {
+        measurements.removeAll { $0.id == id }
+    }
+
+    // MARK: - Tolerance Checks
+
+    /// Add a tolerance check
+    /// - Returns: The check, or nil if it cannot be evaluated (missing datum, degenerate surface)
+    @discardableResult
+    func addToleranceCheck(_ check: ToleranceCheck) -> ToleranceCheck? {
+        guard let result = result(for: check) else {
+            statusMessage = "Could not evaluate \(check.characteristic.rawValue.lowercased()): check the feature and datum"
+            return nil
+        }
+        toleranceChecks.append(check)
+        statusMessage = "\(check.characteristic.rawValue): \(result.formattedValue) \(result.passed ? "PASS" : "FAIL")"
+        return check
+    }
+
+    /// Current deviation and pass/fail state for a check (nil if its feature or datum no longer exists)
+    func result(for check: ToleranceCheck) -> ToleranceResult? {
+        ToleranceEvaluator.evaluate(check, entities: entities)
+    }
+
+    /// Display title for a check, e.g. "⊥ Perpendicularity | Plane 1"
+    func title(for check: ToleranceCheck) -> String {
+        var title = "\(check.characteristic.symbol) \(check.characteristic.rawValue)"
+        switch check.feature {
+        case .surface(_, let triangleCount):
+            title += " (\(triangleCount) tri)"
+        case .entity(let id):
+            title += " \(entity(id: id)?.name ?? "?")"
+        }
+        if let datumID = check.datumID {
+            title += " | \(entity(id: datumID)?.name ?? "?")"
+        }
+        return title
+    }
+
+    /// Delete a tolerance check
+    func removeToleranceCheck(id: UUID) {
+        toleranceChecks.removeAll { $0.id == id }
+    }
+
+    // MARK: - Entity Management
+
+    /// Look up an entity by id
+    func entity(id: UUID) -> ReferenceEntity? {
+        entities.first { $0.id == id }
+    }
+
+    /// Delete an entity
+    func remove(id: UUID) {
+        entities.removeAll { $0.id == id }
+        selectedIDs.remove(id)
+        measurements.removeAll { $0.firstID == id || $0.secondID == id }
+        toleranceChecks.removeAll { check in
+            check.datumID == id || check.feature == .entity(id)
+        }
+    }
+
+    /// Show or hide an entity in the scene
+    func toggleVisibility(id: UUID) {
+        guard let index = entities.firstIndex(where: { $0.id == id }) else { return }
+        entities[index].isVisible.toggle()
+    }
+
+    /// Add or remove an entity from the intersection selection
+    func toggleSelection(id: UUID) {
+        if selectedIDs.contains(id) {
+            selectedIDs.remove(id)
+        } else {
+            selectedIDs.insert(id)
+        }
+    }
+
+    /// Remove all entities and reset naming (for loading a new file)
+    func clearAll() {
+        cancelTool()
+        entities = []
+        measurements = []
+        toleranceChecks = []
+        selectedIDs = []
+        statusMessage = nil
+        nameCounters = [:]
+    }
+
+    /// Replace everything with saved entities and measurements, continuing the auto-naming after them
+    func restore(entities: [ReferenceEntity], measurements: [ReferenceMeasurement]) {
+        clearAll()
+        self.entities = entities
+        let ids = Set(entities.map(\.id))
+        self.measurements = measurements.filter { ids.contains($0.firstID) && ids.contains($0.secondID) }
+        for entity in entities {
+            let typeName = entity.kind.typeName
+            if entity.name.hasPrefix("\(typeName) "), let number = Int(entity.name.dropFirst(typeName.count + 1)) {
+                nameCounters[typeName] = max(nameCounters[typeName] ?? 0, number)
+            }
+        }
+    }
+}
@@ -0,0 +1,215 @@
+import Foundation
+
+/// Geometric tolerance characteristics that can be evaluated (GD&T style)
+enum ToleranceCharacteristic: String, CaseIterable, Codable, Identifiable {
+    case flatness = "Flatness"
+    case parallelism = "Parallelism"
+    case perpendicularity = "Perpendicularity"
+    case position = "Position"
+
+    var id: String { rawValue }
+
+    /// GD&T symbol shown in lists and reports
+    var symbol: String {
+        switch self {
+        case .flatness: return "⏥"
+        case .parallelism: return "∥"
+        case .perpendicularity: return "⊥"
+        case .position: return "⌖"
+        }
+    }
+
+    /// Whether the characteristic is evaluated relative to a datum plane or axis
+    var requiresDatum: Bool {
+        self == .parallelism || self == .perpendicularity
+    }
+
+    /// Whether the toleranced feature is a picked surface (selected triangles) rather than a reference entity
+    var usesSurface: Bool {
+        self != .position
+    }
+}
+
+/// The toleranced feature of a check
+enum ToleranceFeature: Codable, Equatable {
+    /// Vertices of the selected triangles at the time the check was created
+    case surface(points: [Vector3], triangleCount: Int)
+    /// A reference point or axis (for position)
+    case entity(UUID)
+}
+
+/// A tolerance requirement on a feature, optionally relative to a datum
+struct ToleranceCheck: Identifiable, Codable, Equatable {
+    let id: UUID
+    var characteristic: ToleranceCharacteristic
+    /// Tolerance zone width in mm (diameter for position)
+    var tolerance: Double
+    var feature: ToleranceFeature
+    var datumID: UUID?
+    /// Nominal location for position checks (render space)
+    var nominal: Vector3?
+
+    init(characteristic: ToleranceCharacteristic, tolerance: Double, feature: ToleranceFeature, datumID: UUID? = nil, nominal: Vector3? = nil) {
+        self.id = UUID()
+        self.characteristic = characteristic
+        self.tolerance = tolerance
+        self.feature = feature
+        self.datumID = datumID
+        self.nominal = nominal
+    }
+}
+
+/// Outcome of evaluating a tolerance check
+struct ToleranceResult: Equatable {
+    /// Measured zone width in mm (diameter for position)
+    var deviation: Double
+    var tolerance: Double
+
+    var passed: Bool {
+        deviation <= tolerance
+    }
+
+    /// Display text, e.g. "0.042 / 0.050 mm"
+    var formattedValue: String {
+        String(format: "%.3f / %.3f mm", deviation, tolerance)
+    }
+}
+
+// MARK: - Evaluation
+
+enum ToleranceEvaluator {
+    /// Evaluate a check against the current reference entities
+    /// - Returns: nil if the feature or datum is missing or unsuitable for the characteristic
+    static func evaluate(_ check: ToleranceCheck, entities: [ReferenceEntity]) -> ToleranceResult? {
+        let datum = check.datumID.flatMap { id in entities.first { $0.id == id }?.kind }
+        if check.characteristic.requiresDatum && datum?.direction == nil {
+            return nil
+        }
+
+        let deviation: Double?
+        switch (check.characteristic, check.feature) {
+        case (.flatness, .surface(let points, _)):
+            deviation = flatness(of: points)
+        case (.parallelism, .surface(let points, _)):
+            deviation = datum.flatMap { parallelism(of: points, to: $0) }
+        case (.perpendicularity, .surface(let points, _)):
+            deviation = datum.flatMap { perpendicularity(of: points, to: $0) }
+        case (.position, .entity(let id)):
+            guard let nominal = check.nominal,
+                  let entity = entities.first(where: { $0.id == id }) else {
+                return nil
+            }
+            deviation = position(of: entity.kind, nominal: nominal)
+        default:
+            deviation = nil
+        }
+
+        return deviation.map { ToleranceResult(deviation: $0, tolerance: check.tolerance) }
+    }
+
+    /// Peak-to-valley distance of the points from their least-squares plane
+    static func flatness(of points: [Vector3]) -> Double? {
+        guard let plane = fitPlane(points) else { return nil }
+        return zoneWidth(of: points, along: plane.normal)
+    }
+
+    /// Width of the zone parallel to the datum that contains the surface
+    /// Datum plane: zone planes parallel to the plane. Datum axis: zone planes containing the axis direction.
+    static func parallelism(of points: [Vector3], to datum: ReferenceEntity.Kind) -> Double? {
+        switch datum {
+        case .plane(_, let normal):
+            return zoneWidth(of: points, along: normal)
+        case .axis(_, let direction):
+            guard let plane = fitPlane(points) else { return nil }
+            guard let zoneNormal = perpendicularComponent(of: plane.normal, to: direction) else { return nil }
+            return zoneWidth(of: points, along: zoneNormal)
+        case .point:
+            return nil
+        }
+    }
+
+    /// Width of the zone perpendicular to the datum that contains the surface
+    /// Datum plane: zone planes perpendicular to the plane. Datum axis: zone planes perpendicular to the axis.
+    static func perpendicularity(of points: [Vector3], to datum: ReferenceEntity.Kind) -> Double? {
+        switch datum {
+        case .plane(_, let normal):
+            guard let plane = fitPlane(points) else { return nil }
+            // A surface parallel to the datum has no perpendicular orientation; use any in-plane direction
+            let zoneNormal = perpendicularComponent(of: plane.normal, to: normal)
+                ?? perpendicularComponent(of: Vector3.unitX, to: normal)
+                ?? Vector3.unitY
+            return zoneWidth(of: points, along: zoneNormal)
+        case .axis(_, let direction):
+            return zoneWidth(of: points, along: direction)
+        case .point:
+            return nil
+        }
+    }
+
+    /// Diameter of the cylindrical zone around the nominal location that contains the feature
+    static func position(of feature: ReferenceEntity.Kind, nominal: Vector3) -> Double? {
+        switch feature {
+        case .point(let position):
+            return 2 * position.distance(to: nominal)
+        case .axis:
+            return 2 * feature.distance(to: nominal)
+        case .plane:
+            return nil
+        }
+    }
+
+    // MARK: - Helpers
+
+    /// Least-squares plane through the points
+    /// - Returns: nil for fewer than 3 points or collinear points
+    static func fitPlane(_ points: [Vector3]) -> (origin: Vector3, normal: Vector3)? {
+        guard points.count >= 3 else { return nil }
+
+        let centroid = points.reduce(Vector3.zero, +) / Double(points.count)
+
+        // Covariance of the centered points
+        var xx = 0.0, xy = 0.0, xz = 0.0, yy = 0.0, yz = 0.0, zz = 0.0
+        for point in points {
+            let r = point - centroid
+            xx += r.x * r.x
+            xy += r.x * r.y
+            xz += r.x * r.z
+            yy += r.y * r.y
+            yz += r.y * r.z
+            zz += r.z * r.z
+        }
+
+        // Solve for the normal along the axis with the best-conditioned determinant
+        let detX = yy * zz - yz * yz
+        let detY = xx * zz - xz * xz
+        let detZ = xx * yy - xy * xy
+        let maxDet = max(detX, detY, detZ)
+        guard maxDet > 1e-12 else { return nil }
+
+        let normal: Vector3
+        if maxDet == detX {
+            normal = Vector3(detX, xz * yz - xy * zz, xy * yz - xz * yy)
+        } else if maxDet == detY {
+            normal = Vector3(xz * yz - xy * zz, detY, xy * xz - yz * xx)
+        } else {
+            normal = Vector3(xy * yz - xz * yy, xy * xz - yz * xx, detZ)
+        }
+        return (centroid, normal.normalized())
+    }
+
+    /// Range of the point projections onto a direction
+    static func zoneWidth(of points: [Vector3], along direction: Vector3) -> Double {
+        let unit = direction.normalized()
+        let projections = points.map { $0.dot(unit) }
+        guard let low = projections.min(), let high = projections.max() else { return 0 }
+        return high - low
+    }
+
+    /// Part of a vector perpendicular to a direction, normalized (nil if they are parallel)
+    private static func perpendicularComponent(of vector: Vector3, to direction: Vector3) -> Vector3? {
+        let unit = direction.normalized()
+        let component = vector - unit * vector.dot(unit)
+        guard component.length > 1e-9 else { return nil }
+        return component.normalized()
+    }
+}
@@ -0,0 +1,136 @@
+import Foundation
+import simd
+
+/// Per-vertex ambient occlusion baked by ray casting, for depth cues in presentations and thumbnails
+///
+/// Every distinct vertex casts a fixed set of cosine-weighted rays into the hemisphere around its
+/// averaged normal. Rays blocked nearby darken the vertex; the result is stored per triangle
+/// corner (like `VertexColors`) and applied by the mesh shader.
+enum AmbientOcclusion {
+    /// Rays per vertex
+    static let sampleCount = 32
+    /// Blockers farther away than this share of the bounding box diagonal do not occlude
+    static let reach = 0.2
+    /// Darkening of a fully enclosed vertex (0 no effect, 1 black)
+    static let strength: Float = 0.75
+
+    /// Occlusion of every triangle corner from 0 (open) to 1 (enclosed), three values per triangle
+    /// - Parameters:
+    ///   - triangles: Mesh to bake
+    ///   - accelerator: BVH of the same triangles (built if nil)
+    ///   - job: Job to report progress to and check for cancellation
+    static func bake(
+        triangles: [Triangle],
+        accelerator: SpatialAccelerator? = nil,
+        sampleCount: Int = sampleCount,
+        job: JobContext? = nil
+    ) throws -> [Float] {
+        guard !triangles.isEmpty, sampleCount > 0 else { return [] }
+
+        // Weld the corners so vertices shared by triangles get one value and a smooth normal
+        var vertexIndex: [Vector3: Int] = [:]
+        var positions: [SIMD3<Double>] = []
+        var normals: [SIMD3<Double>] = []
+        var corners: [Int] = []
+        corners.reserveCapacity(triangles.count * 3)
+        for triangle in triangles {
+            let weighted = Triangle.calculateNormal(v1: triangle.v1, v2: triangle.v2, v3: triangle.v3).value * triangle.area()
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                let index: Int
+                if let existing = vertexIndex[vertex] {
+                    index = existing
+                } else {
+                    index = positions.count
+                    vertexIndex[vertex] = index
+                    positions.append(vertex.value)
+                    normals.append(.zero)
+                }
+                normals[index] += weighted
+                corners.append(index)
+            }
+        }
+        try job?.checkCancellation()
+
+        let bounds = STLModel(triangles: triangles).boundingBox()
+        let maxDistance = Float(bounds.diagonal * reach)
+        let epsilon = bounds.diagonal * 1e-4
+        let directions = hemisphereDirections(count: sampleCount)
+        let accelerator = accelerator ?? SpatialAccelerator(triangles: triangles)
+
+        let occlusion = OcclusionBuffer(count: positions.count)
+        let progress = OcclusionProgress()
+        let chunkSize = 2048
+        let chunkCount = (positions.count + chunkSize - 1) / chunkSize
+        DispatchQueue.concurrentPerform(iterations: chunkCount) { chunk in
+            guard job?.isCancelled != true else { return }
+            let start = chunk * chunkSize
+            for index in start..<min(start + chunkSize, positions.count) {
+                let normal = simd_length(normals[index]) > 0 ? simd_normalize(normals[index]) : SIMD3<Double>(0, 0, 1)
+                let (tangent, bitangent) = basis(around: normal)
+                let origin = SIMD3<Float>(positions[index] + normal * epsilon)
+
+                var blocked: Float = 0
+                for direction in directions {
+                    let world = tangent * direction.x + bitangent * direction.y + normal * direction.z
+                    let ray = Ray(origin: origin, direction: SIMD3<Float>(world))
+                    if let hit = accelerator.raycast(ray: ray), hit.distance < maxDistance {
+                        // Near blockers occlude fully, farther ones fade out
+                        blocked += 1 - hit.distance / maxDistance
+                    }
+                }
+                occlusion[index] = blocked / Float(directions.count)
+            }
+            job?.reportProgress(progress.advance(of: chunkCount))
+        }
+        try job?.checkCancellation()
+
+        return corners.map { occlusion[$0] }
+    }
+
+    /// Cosine-weighted directions in the hemisphere around +Z, spread on a Fibonacci spiral
+    static func hemisphereDirections(count: Int) -> [SIMD3<Double>] {
+        let goldenAngle = Double.pi * (3 - sqrt(5))
+        return (0..<count).map { i in
+            let u = (Double(i) + 0.5) / Double(count)
+            let radius = sqrt(u)
+            let angle = Double(i) * goldenAngle
+            return SIMD3(radius * cos(angle), radius * sin(angle), sqrt(1 - u))
+        }
+    }
+
+    /// Two unit vectors perpendicular to a unit normal and to each other
+    private static func basis(around normal: SIMD3<Double>) -> (SIMD3<Double>, SIMD3<Double>) {
+        let helper = abs(normal.x) < 0.9 ? SIMD3<Double>(1, 0, 0) : SIMD3<Double>(0, 1, 0)
+        let tangent = simd_normalize(simd_cross(helper, normal))
+        return (tangent, simd_cross(normal, tangent))
+    }
+}
+
+/// Occlusion per welded vertex, written from the worker threads (each index by one thread only)
+private final class OcclusionBuffer: @unchecked Sendable {
+    private var storage: [Float]
+
+    init(count: Int) {
+        storage = [Float](repeating: 0, count: count)
+    }
+
+    subscript(index: Int) -> Float {
+        get { storage[index] }
+        set { storage[index] = newValue }
+    }
+}
+
+/// Finished chunks of a bake, counted from the worker threads
+private final class OcclusionProgress: @unchecked Sendable {
+    private let lock = NSLock()
+    private var done = 0
+
+    /// Count one more finished chunk
+    /// - Returns: The finished fraction
+    func advance(of total: Int) -> Double {
+        lock.lock()
+        defer { lock.unlock() }
+        done += 1
+        return Double(done) / Double(total)
+    }
+}
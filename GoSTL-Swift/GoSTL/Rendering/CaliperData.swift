@@ -0,0 +1,49 @@
+import Metal
+import simd
+
+/// GPU-ready data for rendering the jaws of the virtual caliper
+final class CaliperData {
+    let vertexBuffer: MTLBuffer
+    let vertexCount: Int
+
+    /// Seated jaws
+    static let jawColor = SIMD4<Float>(1.0, 0.6, 0.2, 0.3)
+    /// Open jaw positions while they differ from the seated ones
+    static let openColor = SIMD4<Float>(1.0, 0.6, 0.2, 0.08)
+
+    init(device: MTLDevice, caliper: Caliper, reading: Caliper.Reading?, modelCenter: Vector3, planeSize: Float) throws {
+        let direction = caliper.direction.vector
+        var vertices: [VertexIn] = []
+
+        if let reading {
+            for position in [reading.low, reading.high] {
+                vertices += Self.jaw(direction: direction, position: position, center: modelCenter, halfSize: planeSize / 2, color: Self.jawColor)
+            }
+        }
+        for position in [caliper.opening.lowerBound, caliper.opening.upperBound]
+        where reading.map({ abs($0.low - position) > 1e-6 && abs($0.high - position) > 1e-6 }) ?? true {
+            vertices += Self.jaw(direction: direction, position: position, center: modelCenter, halfSize: planeSize / 2, color: Self.openColor)
+        }
+
+        self.vertexCount = vertices.count
+
+        // Create vertex buffer (there are always two jaws)
+        let bufferSize = vertices.count * MemoryLayout<VertexIn>.stride
+        guard let buffer = device.makeBuffer(bytes: vertices, length: bufferSize, options: []) else {
+            throw MetalError.bufferCreationFailed
+        }
+        self.vertexBuffer = buffer
+    }
+
+    /// Square across the direction at a position along it, in line with the model center
+    private static func jaw(direction: Vector3, position: Double, center: Vector3, halfSize: Float, color: SIMD4<Float>) -> [VertexIn] {
+        let helper: Vector3 = abs(direction.x) < 0.9 ? .unitX : .unitY
+        let u = direction.cross(helper).normalized().float3 * halfSize
+        let v = direction.cross(direction.cross(helper).normalized()).float3 * halfSize
+        let middle = (center + direction * (position - center.dot(direction))).float3
+        let normal = direction.float3
+
+        let corners = [middle - u - v, middle + u - v, middle + u + v, middle - u + v]
+        return [0, 1, 2, 0, 2, 3].map { VertexIn(position: corners[$0], normal: normal, color: color) }
+    }
+}
@@ -0,0 +1,40 @@
+import Metal
+import simd
+
+/// GPU data for a clearance check: the second model and the interpenetrating triangles of both models
+final class ClearanceRenderData {
+    /// The second model, tinted so it stands apart from the main model
+    let otherMesh: MeshData
+
+    /// Interpenetrating triangles (drawn in red on top of both models)
+    private(set) var highlightBuffer: MTLBuffer?
+    private(set) var highlightVertexCount: Int = 0
+
+    static let otherColor = TriangleColor(0.45, 0.72, 0.95)
+    static let highlightColor = SIMD4<Float>(1.0, 0.15, 0.1, 1.0)
+
+    init(device: MTLDevice, other: STLModel) throws {
+        let tint = VertexColors(perTriangle: Array(repeating: Self.otherColor, count: other.triangleCount))
+        self.otherMesh = try MeshData(device: device, model: other, colors: tint)
+    }
+
+    /// Replace the highlighted triangles
+    func updateHighlights(device: MTLDevice, triangles: [Triangle]) {
+        let vertices = triangles.flatMap { triangle -> [VertexIn] in
+            let normal = triangle.normal.float3
+            return [
+                VertexIn(position: triangle.v1.float3, normal: normal, color: Self.highlightColor),
+                VertexIn(position: triangle.v2.float3, normal: normal, color: Self.highlightColor),
+                VertexIn(position: triangle.v3.float3, normal: normal, color: Self.highlightColor)
+            ]
+        }
+
+        guard !vertices.isEmpty else {
+            highlightBuffer = nil
+            highlightVertexCount = 0
+            return
+        }
+        highlightBuffer = device.makeBuffer(bytes: vertices, length: vertices.count * MemoryLayout<VertexIn>.stride, options: [])
+        highlightVertexCount = highlightBuffer == nil ? 0 : vertices.count
+    }
+}
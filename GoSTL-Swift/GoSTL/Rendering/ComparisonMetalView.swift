@@ -0,0 +1,158 @@
+import SwiftUI
+import MetalKit
+
+/// Metal view of the comparison pane, sharing the main view's camera
+struct ComparisonMetalView: NSViewRepresentable {
+    let appState: AppState
+
+    func makeCoordinator() -> Coordinator {
+        Coordinator(appState: appState)
+    }
+
+    func makeNSView(context: Context) -> ComparisonMTKView {
+        guard let device = MTLCreateSystemDefaultDevice() else {
+            fatalError("Metal is not supported on this device")
+        }
+
+        let mtkView = ComparisonMTKView()
+        mtkView.device = device
+        mtkView.delegate = context.coordinator
+        mtkView.preferredFramesPerSecond = 60
+        mtkView.enableSetNeedsDisplay = false
+        mtkView.isPaused = false
+        mtkView.colorPixelFormat = .bgra8Unorm
+        mtkView.depthStencilPixelFormat = .depth32Float
+        mtkView.sampleCount = 4  // 4x MSAA, same as the main view
+        mtkView.coordinator = context.coordinator
+        context.coordinator.setupRenderer(device: device)
+
+        return mtkView
+    }
+
+    func updateNSView(_ nsView: ComparisonMTKView, context: Context) {}
+
+    class Coordinator: NSObject, MTKViewDelegate {
+        let appState: AppState
+        var renderer: MetalRenderer?
+        let inputHandler = InputHandler()
+
+        init(appState: AppState) {
+            self.appState = appState
+        }
+
+        func setupRenderer(device: MTLDevice) {
+            do {
+                renderer = try MetalRenderer(device: device)
+            } catch {
+                fatalError("Failed to initialize Metal renderer: \(error)")
+            }
+        }
+
+        func mtkView(_ view: MTKView, drawableSizeWillChange size: CGSize) {
+            renderer?.mtkView(view, drawableSizeWillChange: size)
+        }
+
+        func draw(in view: MTKView) {
+            renderer?.drawComparison(in: view, appState: appState)
+        }
+    }
+}
+
+// MARK: - Comparison MTKView
+
+/// Navigates the shared camera; a click selects the matching region of the main model
+class ComparisonMTKView: MTKView {
+    weak var coordinator: ComparisonMetalView.Coordinator?
+
+    override var acceptsFirstResponder: Bool { true }
+
+    private var mouseDownLocation: CGPoint?
+    private var didDrag = false
+    private let dragThreshold: CGFloat = 5.0
+
+    /// Location in drawable pixels (Y=0 at bottom, as Camera.pickRay expects)
+    private func scaledLocation(of event: NSEvent) -> CGPoint {
+        let location = convert(event.locationInWindow, from: nil)
+        let scale = drawableSize.width / bounds.size.width
+        return CGPoint(x: location.x * scale, y: location.y * scale)
+    }
+
+    override func mouseDown(with event: NSEvent) {
+        guard let coordinator = coordinator else { return }
+        mouseDownLocation = convert(event.locationInWindow, from: nil)
+        didDrag = false
+        coordinator.inputHandler.handleNavigationMouseDown(
+            at: scaledLocation(of: event),
+            modifierFlags: event.modifierFlags,
+            camera: coordinator.appState.camera
+        )
+    }
+
+    override func mouseDragged(with event: NSEvent) {
+        guard let coordinator = coordinator else { return }
+        if let downLocation = mouseDownLocation {
+            let location = convert(event.locationInWindow, from: nil)
+            if hypot(location.x - downLocation.x, location.y - downLocation.y) > dragThreshold {
+                didDrag = true
+            }
+        }
+        coordinator.inputHandler.handleMouseDragged(
+            to: scaledLocation(of: event),
+            camera: coordinator.appState.camera,
+            viewSize: drawableSize,
+            appState: coordinator.appState
+        )
+    }
+
+    override func mouseUp(with event: NSEvent) {
+        guard let coordinator = coordinator else { return }
+        let appState = coordinator.appState
+        coordinator.inputHandler.handleMouseUp(appState: appState, camera: appState.camera, viewSize: drawableSize)
+
+        // A click (not a drag) selects the corresponding triangles of the main model
+        if !didDrag, mouseDownLocation != nil {
+            let ray = appState.camera.pickRay(screenPos: scaledLocation(of: event), viewSize: drawableSize)
+            appState.toggleComparisonSelection(ray: ray)
+        }
+
+        mouseDownLocation = nil
+        didDrag = false
+    }
+
+    override func scrollWheel(with event: NSEvent) {
+        guard let coordinator = coordinator else { return }
+        coordinator.inputHandler.handleScroll(
+            deltaY: event.scrollingDeltaY,
+            camera: coordinator.appState.camera,
+            hasPreciseDeltas: event.hasPreciseScrollingDeltas
+        )
+    }
+
+    override func otherMouseDown(with event: NSEvent) {
+        guard let coordinator = coordinator else { return }
+        coordinator.inputHandler.handleMiddleMouseDown(
+            at: convert(event.locationInWindow, from: nil),
+            modifierFlags: event.modifierFlags,
+            camera: coordinator.appState.camera
+        )
+    }
+
+    override func otherMouseDragged(with event: NSEvent) {
+        guard let coordinator = coordinator else { return }
+        coordinator.inputHandler.handleMouseDragged(
+            to: convert(event.locationInWindow, from: nil),
+            camera: coordinator.appState.camera,
+            viewSize: drawableSize,
+            appState: coordinator.appState
+        )
+    }
+
+    override func otherMouseUp(with event: NSEvent) {
+        guard let coordinator = coordinator else { return }
+        coordinator.inputHandler.handleMouseUp(
+            appState: coordinator.appState,
+            camera: coordinator.appState.camera,
+            viewSize: drawableSize
+        )
+    }
+}
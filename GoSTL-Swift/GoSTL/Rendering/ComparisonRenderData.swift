@@ -0,0 +1,42 @@
+import Metal
+import simd
+
+/// GPU data for the comparison pane: the second model and the region matching the main model's selection
+final class ComparisonRenderData {
+    let mesh: MeshData
+
+    /// Triangles corresponding to the selection in the main pane
+    private(set) var highlightBuffer: MTLBuffer?
+    private(set) var highlightVertexCount: Int = 0
+
+    /// Main model selection the highlight was built for
+    private(set) var highlightedSelection: Set<Int> = []
+
+    /// Same cyan as the selection in the main pane
+    static let highlightColor = SIMD4<Float>(0.0, 0.8, 1.0, 1.0)
+
+    init(device: MTLDevice, model: STLModel) throws {
+        self.mesh = try MeshData(device: device, model: model)
+    }
+
+    /// Replace the highlighted triangles for a new main model selection
+    func updateHighlights(device: MTLDevice, triangles: [Triangle], selection: Set<Int>) {
+        highlightedSelection = selection
+        let vertices = triangles.flatMap { triangle -> [VertexIn] in
+            let normal = triangle.normal.float3
+            return [
+                VertexIn(position: triangle.v1.float3, normal: normal, color: Self.highlightColor),
+                VertexIn(position: triangle.v2.float3, normal: normal, color: Self.highlightColor),
+                VertexIn(position: triangle.v3.float3, normal: normal, color: Self.highlightColor)
+            ]
+        }
+
+        guard !vertices.isEmpty else {
+            highlightBuffer = nil
+            highlightVertexCount = 0
+            return
+        }
+        highlightBuffer = device.makeBuffer(bytes: vertices, length: vertices.count * MemoryLayout<VertexIn>.stride, options: [])
+        highlightVertexCount = highlightBuffer == nil ? 0 : vertices.count
+    }
+}
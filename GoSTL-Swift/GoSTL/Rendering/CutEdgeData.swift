@@ -13,7 +13,8 @@ final class CutEdgeData {
     /// Axis colors for cut edges (using centralized colors)
     private static let axisColors: [SIMD4<Float>] = AxisColors.all
 
-    init(device: MTLDevice, cutEdges: [CutEdge]) throws {
+    /// - Parameter colors: Color per edge (default: the color of the edge's cut axis)
+    init(device: MTLDevice, cutEdges: [CutEdge], colors: [SIMD4<Float>]? = nil) throws {
         guard !cutEdges.isEmpty else {
             throw MetalError.bufferCreationFailed
         }
@@ -40,8 +41,8 @@ final class CutEdgeData {
 
         // Create instance data (one transform matrix per edge, with color)
         // Filter out degenerate (zero-length) edges
-        let instances: [InstanceData] = cutEdges.compactMap { edge in
-            Self.createInstanceData(edge: edge)
+        let instances: [InstanceData] = cutEdges.indices.compactMap { index in
+            Self.createInstanceData(edge: cutEdges[index], color: colors?[index] ?? Self.axisColors[cutEdges[index].axis])
         }
 
         self.instanceCount = instances.count
@@ -61,7 +62,7 @@ final class CutEdgeData {
 
     /// Create instance data with color for a cut edge
     /// Returns nil for degenerate (zero-length) edges
-    private static func createInstanceData(edge: CutEdge) -> InstanceData? {
+    private static func createInstanceData(edge: CutEdge, color: SIMD4<Float>) -> InstanceData? {
         let start = edge.start.float3
         let end = edge.end.float3
         let direction = end - start
@@ -89,7 +90,7 @@ final class CutEdgeData {
         // Combine: translate * rotate * scale
         let modelMatrix = translation * rotation * scale
 
-        return InstanceData(modelMatrix: modelMatrix, color: axisColors[edge.axis])
+        return InstanceData(modelMatrix: modelMatrix, color: color)
     }
 
     /// Create a rotation matrix that rotates vector 'from' to vector 'to'
@@ -10,6 +10,8 @@ final class GridData {
     let mode: GridMode
     let dimensionLinesBuffer: MTLBuffer?
     let dimensionLinesCount: Int
+    /// Offset subtracted from the file's coordinates (see `AppState.coordinateOffset`)
+    let coordinateOffset: Vector3
 
     /// Grid bounds information for label rendering (Z-up coordinate system)
     struct GridBounds {
@@ -45,6 +47,7 @@ final class GridData {
         )
         self.dimensionLinesBuffer = nil
         self.dimensionLinesCount = 0
+        self.coordinateOffset = .zero
 
         // Create GPU buffer
         let bufferSize = vertices.count * MemoryLayout<VertexIn>.stride
@@ -54,7 +57,11 @@ final class GridData {
         self.vertexBuffer = buffer
     }
 
-    init(device: MTLDevice, mode: GridMode, boundingBox: BoundingBox) throws {
+    /// - Parameters:
+    ///   - planeZ: Height of the bottom grid plane (defaults to the bottom of the bounding box)
+    ///   - coordinateOffset: Recentering offset of the model; lines and labels sit on round file coordinates
+    init(device: MTLDevice, mode: GridMode, boundingBox: BoundingBox, planeZ: Double? = nil, coordinateOffset: Vector3 = .zero) throws {
+        let bottomZ = Float(planeZ ?? boundingBox.min.z)
         let padding: Float = 1.2
         var minX = Float(boundingBox.min.x) * padding
         var maxX = Float(boundingBox.max.x) * padding
@@ -75,21 +82,24 @@ final class GridData {
             spacing = GridData.calculateGridSpacing(size: maxSize)
         }
         self.gridSpacing = spacing
-
-        // Snap grid bounds to spacing
-        minX = floor(minX / spacing) * spacing
-        maxX = ceil(maxX / spacing) * spacing
-        minY = floor(minY / spacing) * spacing
-        maxY = ceil(maxY / spacing) * spacing
-        minZ = floor(minZ / spacing) * spacing
-        maxZ = ceil(maxZ / spacing) * spacing
+        self.coordinateOffset = coordinateOffset
+
+        // Snap grid bounds to spacing in file coordinates. The shift is the offset modulo the
+        // super major line distance, so it stays small enough for Float and keeps line colors.
+        let shift = GridData.shift(for: coordinateOffset, spacing: spacing, mode: mode)
+        minX = floor((minX + shift.x) / spacing) * spacing - shift.x
+        maxX = ceil((maxX + shift.x) / spacing) * spacing - shift.x
+        minY = floor((minY + shift.y) / spacing) * spacing - shift.y
+        maxY = ceil((maxY + shift.y) / spacing) * spacing - shift.y
+        minZ = floor((minZ + shift.z) / spacing) * spacing - shift.z
+        maxZ = ceil((maxZ + shift.z) / spacing) * spacing - shift.z
 
         self.mode = mode
         self.bounds = GridBounds(
             minX: minX, maxX: maxX,
             minY: minY, maxY: maxY,
             minZ: minZ, maxZ: maxZ,
-            bottomZ: Float(boundingBox.min.z),
+            bottomZ: bottomZ,
             bboxMinX: Float(boundingBox.min.x),
             bboxMaxX: Float(boundingBox.max.x),
             bboxMinY: Float(boundingBox.min.y),
@@ -100,7 +110,6 @@ final class GridData {
 
         // Generate grid vertices based on mode (Z-up coordinate system)
         var vertices: [VertexIn] = []
-        let bottomZ = Float(boundingBox.min.z)
 
         // Always draw bottom grid (XY plane at Z = bottomZ)
         GridData.addXYPlaneBottom(
@@ -109,6 +118,7 @@ final class GridData {
             minY: minY, maxY: maxY,
             z: bottomZ,
             spacing: spacing,
+            shift: shift,
             mode: mode
         )
 
@@ -121,6 +131,7 @@ final class GridData {
                 minZ: minZ, maxZ: maxZ,
                 y: maxY,
                 spacing: spacing,
+                shift: shift,
                 mode: mode
             )
 
@@ -131,6 +142,7 @@ final class GridData {
                 minZ: minZ, maxZ: maxZ,
                 x: minX,
                 spacing: spacing,
+                shift: shift,
                 mode: mode
             )
         }
@@ -194,6 +206,16 @@ final class GridData {
         return vertices
     }
 
+    /// Part of the recentering offset that moves grid lines off round render coordinates
+    private static func shift(for offset: Vector3, spacing: Float, mode: GridMode) -> SIMD3<Float> {
+        let period = Double(mode == .oneMM ? 10.0 : spacing * 10.0)
+        return SIMD3(
+            Float(offset.x.truncatingRemainder(dividingBy: period)),
+            Float(offset.y.truncatingRemainder(dividingBy: period)),
+            Float(offset.z.truncatingRemainder(dividingBy: period))
+        )
+    }
+
     private static func isSuperMajorLine(value: Float, spacing: Float, mode: GridMode) -> Bool {
         if mode == .oneMM {
             // In 1mm mode: every 10mm is super major
@@ -234,12 +256,13 @@ final class GridData {
         minY: Float, maxY: Float,
         z: Float,
         spacing: Float,
+        shift: SIMD3<Float>,
         mode: GridMode
     ) {
         // Lines parallel to X axis (running along Y)
         var y = minY
         while y <= maxY {
-            let color = getLineColor(value: y, spacing: spacing, mode: mode)
+            let color = getLineColor(value: y + shift.y, spacing: spacing, mode: mode)
             vertices.append(VertexIn(position: SIMD3(minX, y, z), normal: SIMD3(0, 0, 1), color: color))
             vertices.append(VertexIn(position: SIMD3(maxX, y, z), normal: SIMD3(0, 0, 1), color: color))
             y += spacing
@@ -248,7 +271,7 @@ final class GridData {
         // Lines parallel to Y axis (running along X)
         var x = minX
         while x <= maxX {
-            let color = getLineColor(value: x, spacing: spacing, mode: mode)
+            let color = getLineColor(value: x + shift.x, spacing: spacing, mode: mode)
             vertices.append(VertexIn(position: SIMD3(x, minY, z), normal: SIMD3(0, 0, 1), color: color))
             vertices.append(VertexIn(position: SIMD3(x, maxY, z), normal: SIMD3(0, 0, 1), color: color))
             x += spacing
@@ -262,12 +285,13 @@ final class GridData {
         minZ: Float, maxZ: Float,
         y: Float,
         spacing: Float,
+        shift: SIMD3<Float>,
         mode: GridMode
     ) {
         // Lines parallel to X axis (running along Z)
         var z = minZ
         while z <= maxZ {
-            let color = getLineColor(value: z, spacing: spacing, mode: mode)
+            let color = getLineColor(value: z + shift.z, spacing: spacing, mode: mode)
             vertices.append(VertexIn(position: SIMD3(minX, y, z), normal: SIMD3(0, -1, 0), color: color))
             vertices.append(VertexIn(position: SIMD3(maxX, y, z), normal: SIMD3(0, -1, 0), color: color))
             z += spacing
@@ -276,7 +300,7 @@ final class GridData {
         // Lines parallel to Z axis (running along X)
         var x = minX
         while x <= maxX {
-            let color = getLineColor(value: x, spacing: spacing, mode: mode)
+            let color = getLineColor(value: x + shift.x, spacing: spacing, mode: mode)
             vertices.append(VertexIn(position: SIMD3(x, y, minZ), normal: SIMD3(0, -1, 0), color: color))
             vertices.append(VertexIn(position: SIMD3(x, y, maxZ), normal: SIMD3(0, -1, 0), color: color))
             x += spacing
@@ -290,12 +314,13 @@ final class GridData {
         minZ: Float, maxZ: Float,
         x: Float,
         spacing: Float,
+        shift: SIMD3<Float>,
         mode: GridMode
     ) {
         // Lines parallel to Y axis (running along Z)
         var z = minZ
         while z <= maxZ {
-            let color = getLineColor(value: z, spacing: spacing, mode: mode)
+            let color = getLineColor(value: z + shift.z, spacing: spacing, mode: mode)
             vertices.append(VertexIn(position: SIMD3(x, minY, z), normal: SIMD3(1, 0, 0), color: color))
             vertices.append(VertexIn(position: SIMD3(x, maxY, z), normal: SIMD3(1, 0, 0), color: color))
             z += spacing
@@ -304,7 +329,7 @@ final class GridData {
         // Lines parallel to Z axis (running along Y)
         var y = minY
         while y <= maxY {
-            let color = getLineColor(value: y, spacing: spacing, mode: mode)
+            let color = getLineColor(value: y + shift.y, spacing: spacing, mode: mode)
             vertices.append(VertexIn(position: SIMD3(x, y, minZ), normal: SIMD3(1, 0, 0), color: color))
             vertices.append(VertexIn(position: SIMD3(x, y, maxZ), normal: SIMD3(1, 0, 0), color: color))
             y += spacing
@@ -362,29 +387,31 @@ final class GridData {
     }
 
     /// Generate grid label data for text rendering (Z-up coordinate system)
+    /// Labels show original file coordinates and sit on round values of them.
     func generateGridLabels() -> [(text: String, position: SIMD3<Float>, color: SIMD4<Float>, size: Float, orientation: TextOrientation)] {
         var labels: [(String, SIMD3<Float>, SIMD4<Float>, Float, TextOrientation)] = []
         let labelColor = SIMD4<Float>(200.0/255.0, 200.0/255.0, 200.0/255.0, 1.0) // White
         let labelSize: Float = 2.0
+        let offset = coordinateOffset
 
-        // Always show labels at fixed 10-unit intervals
-        let labelSpacing: Float = 10.0
+        // Always show labels at fixed 10-unit intervals (of file coordinates)
+        let labelSpacing = 10.0
 
         // X labels along front edge (at minY)
-        var x = ceil(bounds.minX / labelSpacing) * labelSpacing
-        while x <= bounds.maxX {
+        var x = ceil((Double(bounds.minX) + offset.x) / labelSpacing) * labelSpacing
+        while x - offset.x <= Double(bounds.maxX) {
             let text = String(format: "%.0f", x)
-            let pos = SIMD3(x, bounds.minY - 2, bounds.bottomZ)
+            let pos = SIMD3(Float(x - offset.x), bounds.minY - 2, bounds.bottomZ)
             labels.append((text, pos, labelColor, labelSize, .horizontal))
             x += labelSpacing
         }
 
         // Y labels along left edge (skip 0)
-        var y = ceil(bounds.minY / labelSpacing) * labelSpacing
-        while y <= bounds.maxY {
+        var y = ceil((Double(bounds.minY) + offset.y) / labelSpacing) * labelSpacing
+        while y - offset.y <= Double(bounds.maxY) {
             if abs(y) > 0.001 {
                 let text = String(format: "%.0f", y)
-                let pos = SIMD3(bounds.minX - 2, y, bounds.bottomZ)
+                let pos = SIMD3(bounds.minX - 2, Float(y - offset.y), bounds.bottomZ)
                 labels.append((text, pos, labelColor, labelSize, .horizontal))
             }
             y += labelSpacing
@@ -392,11 +419,11 @@ final class GridData {
 
         // Z labels (vertical, only in allSides and oneMM modes, skip 0)
         if mode == .allSides || mode == .oneMM {
-            var z = ceil(bounds.minZ / labelSpacing) * labelSpacing
-            while z <= bounds.maxZ {
+            var z = ceil((Double(bounds.minZ) + offset.z) / labelSpacing) * labelSpacing
+            while z - offset.z <= Double(bounds.maxZ) {
                 if abs(z) > 0.001 {
                     let text = String(format: "%.0f", z)
-                    let pos = SIMD3(bounds.minX - 2, bounds.maxY + 2, z)
+                    let pos = SIMD3(bounds.minX - 2, bounds.maxY + 2, Float(z - offset.z))
                     labels.append((text, pos, labelColor, labelSize, .verticalYZ))
                 }
                 z += labelSpacing
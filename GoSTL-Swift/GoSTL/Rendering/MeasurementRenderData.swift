@@ -47,6 +47,18 @@ final class MeasurementRenderData {
     var levelingPreviewLineInstanceBuffer: MTLBuffer?
     var levelingPreviewLineInstanceCount: Int = 0
 
+    // Reference geometry visualization
+    var referenceLineInstanceBuffer: MTLBuffer?
+    var referenceLineInstanceCount: Int = 0
+    var referencePointBuffer: MTLBuffer?
+    var referencePointVertexCount: Int = 0
+
+    // Plugin analyzer overlay
+    var pluginLineInstanceBuffer: MTLBuffer?
+    var pluginLineInstanceCount: Int = 0
+    var pluginPointBuffer: MTLBuffer?
+    var pluginPointVertexCount: Int = 0
+
     // Radius circle cylinder geometry
     let radiusCircleCylinderVertexBuffer: MTLBuffer
 
@@ -59,6 +71,12 @@ final class MeasurementRenderData {
     // Stale line cylinder geometry (gray/faded)
     let staleCylinderVertexBuffer: MTLBuffer
 
+    // Reference geometry cylinder geometry (orange, thinner than measurement lines)
+    let referenceCylinderVertexBuffer: MTLBuffer
+
+    // Plugin overlay cylinder geometry (magenta)
+    let pluginCylinderVertexBuffer: MTLBuffer
+
     init(device: MTLDevice, thickness: Float) throws {
         self.device = device
 
@@ -146,12 +164,45 @@ final class MeasurementRenderData {
             throw MetalError.bufferCreationFailed
         }
         self.staleCylinderVertexBuffer = staleVertexBuffer
+
+        // Create reference geometry cylinder with orange color (for datum planes and axes)
+        let referenceCylinderGeometry = Self.createCylinderGeometry(
+            radius: thickness * measurementThickness * 0.6,
+            segments: 8,
+            color: SIMD4<Float>(1.0, 0.6, 0.1, 0.9) // Orange
+        )
+        let referenceVertexSize = referenceCylinderGeometry.vertices.count * MemoryLayout<VertexIn>.stride
+        guard let referenceVertexBuffer = device.makeBuffer(bytes: referenceCylinderGeometry.vertices, length: referenceVertexSize, options: []) else {
+            throw MetalError.bufferCreationFailed
+        }
+        self.referenceCylinderVertexBuffer = referenceVertexBuffer
+
+        // Create plugin overlay cylinder with magenta color
+        let pluginCylinderGeometry = Self.createCylinderGeometry(
+            radius: thickness * measurementThickness * 0.6,
+            segments: 8,
+            color: SIMD4<Float>(0.9, 0.3, 0.9, 0.9) // Magenta
+        )
+        let pluginVertexSize = pluginCylinderGeometry.vertices.count * MemoryLayout<VertexIn>.stride
+        guard let pluginVertexBuffer = device.makeBuffer(bytes: pluginCylinderGeometry.vertices, length: pluginVertexSize, options: []) else {
+            throw MetalError.bufferCreationFailed
+        }
+        self.pluginCylinderVertexBuffer = pluginVertexBuffer
     }
 
-    /// Update buffers based on measurement system state and leveling state
-    func update(measurementSystem: MeasurementSystem, levelingState: LevelingState? = nil) {
+    /// Update buffers based on measurement system state, leveling state, reference geometry and plugin overlay
+    func update(
+        measurementSystem: MeasurementSystem,
+        levelingState: LevelingState? = nil,
+        referenceGeometry: ReferenceGeometrySystem? = nil,
+        pluginOverlay: PluginAnalysisResult.Overlay? = nil
+    ) {
         // Update leveling visualization
         updateLevelingVisualization(levelingState)
+        // Update reference geometry visualization
+        updateReferenceVisualization(referenceGeometry)
+        // Update plugin analyzer overlay
+        updatePluginOverlay(pluginOverlay)
         // Clear hover if not collecting
         if !measurementSystem.isCollecting {
             hoverBuffer = nil
@@ -311,6 +362,17 @@ final class MeasurementRenderData {
 
             let isSelected = measurementSystem.selectedMeasurements.contains(index)
 
+            // Extension lines from the attachment points to an offset dimension line
+            if let anchors = measurement.extensionAnchors {
+                for (anchor, point) in zip(anchors, measurement.points) {
+                    if isSelected {
+                        selectedEdges.append(Edge(anchor, point.position))
+                    } else {
+                        lineEdges.append(Edge(anchor, point.position))
+                    }
+                }
+            }
+
             if measurement.points.count >= 2 {
                 for i in 0..<(measurement.points.count - 1) {
                     let p1 = measurement.points[i].position
@@ -583,6 +645,119 @@ final class MeasurementRenderData {
         }
     }
 
+    // MARK: - Reference Geometry Visualization
+
+    /// Update reference entities (plane outlines, axis lines, point markers) and tool preview points
+    private func updateReferenceVisualization(_ referenceGeometry: ReferenceGeometrySystem?) {
+        guard let referenceGeometry = referenceGeometry else {
+            referenceLineInstanceBuffer = nil
+            referenceLineInstanceCount = 0
+            referencePointBuffer = nil
+            referencePointVertexCount = 0
+            return
+        }
+
+        let pointColor = SIMD4<Float>(1.0, 0.6, 0.1, 1.0) // Orange, matches reference lines
+        var edges: [Edge] = []
+        var pointVertices: [VertexIn] = []
+
+        for entity in referenceGeometry.entities where entity.isVisible {
+            switch entity.kind {
+            case .plane(let origin, let normal):
+                // Square outline with a short normal stub (same basis choice as createOrthogonalBasis)
+                let arbitrary = abs(normal.y) < 0.9 ? Vector3.unitY : Vector3.unitX
+                let u = normal.cross(arbitrary).normalized()
+                let v = normal.cross(u)
+                let half = referenceGeometry.displaySize * 0.5
+                let corners = [
+                    origin + (u + v) * half,
+                    origin + (v - u) * half,
+                    origin - (u + v) * half,
+                    origin + (u - v) * half
+                ]
+                for i in 0..<corners.count {
+                    edges.append(Edge(corners[i], corners[(i + 1) % corners.count]))
+                }
+                edges.append(Edge(origin, origin + normal * (half * 0.3)))
+                pointVertices.append(contentsOf: createCube(center: origin.float3, size: 0.5, color: pointColor))
+
+            case .axis(let origin, let direction):
+                let offset = direction * referenceGeometry.displaySize
+                edges.append(Edge(origin - offset, origin + offset))
+
+            case .point(let position):
+                pointVertices.append(contentsOf: createCube(center: position.float3, size: 0.8, color: pointColor))
+            }
+        }
+
+        // Annotation lines for measurements between entities (closest points or datum foot)
+        for measurement in referenceGeometry.measurements where referenceGeometry.isVisible(measurement) {
+            guard let result = referenceGeometry.result(for: measurement),
+                  result.from.distance(to: result.to) > 1e-6 else { continue }
+            edges.append(Edge(result.from, result.to))
+            pointVertices.append(contentsOf: createCube(center: result.from.float3, size: 0.4, color: pointColor))
+            pointVertices.append(contentsOf: createCube(center: result.to.float3, size: 0.4, color: pointColor))
+        }
+
+        // Points picked so far for the active tool, plus the hover preview
+        for point in referenceGeometry.collectedPoints {
+            pointVertices.append(contentsOf: createCube(center: point.position.float3, size: 0.6, color: pointColor))
+        }
+        if let hoverPoint = referenceGeometry.hoverPoint {
+            let hoverColor = SIMD4<Float>(0.3, 1.0, 0.3, 1.0) // Green for hover
+            pointVertices.append(contentsOf: createCube(center: hoverPoint.position.float3, size: 0.7, color: hoverColor))
+        }
+
+        if !edges.isEmpty {
+            let instances = Self.createWireframeInstances(edges: edges)
+            let instanceSize = instances.count * MemoryLayout<WireframeInstance>.stride
+            referenceLineInstanceBuffer = device.makeBuffer(bytes: instances, length: instanceSize, options: [])
+            referenceLineInstanceCount = instances.count
+        } else {
+            referenceLineInstanceBuffer = nil
+            referenceLineInstanceCount = 0
+        }
+
+        if !pointVertices.isEmpty {
+            referencePointVertexCount = pointVertices.count
+            let bufferSize = pointVertices.count * MemoryLayout<VertexIn>.stride
+            referencePointBuffer = device.makeBuffer(bytes: pointVertices, length: bufferSize, options: [])
+        } else {
+            referencePointBuffer = nil
+            referencePointVertexCount = 0
+        }
+    }
+
+    // MARK: - Plugin Overlay Visualization
+
+    /// Update lines and point markers returned by a plugin analyzer
+    private func updatePluginOverlay(_ overlay: PluginAnalysisResult.Overlay?) {
+        let edges = (overlay?.lines ?? []).map { Edge($0.from, $0.to) }
+        let pointColor = SIMD4<Float>(0.9, 0.3, 0.9, 1.0) // Magenta, matches plugin lines
+        let pointVertices = (overlay?.points ?? []).flatMap {
+            createCube(center: $0.float3, size: 0.6, color: pointColor)
+        }
+
+        if !edges.isEmpty {
+            let instances = Self.createWireframeInstances(edges: edges)
+            let instanceSize = instances.count * MemoryLayout<WireframeInstance>.stride
+            pluginLineInstanceBuffer = device.makeBuffer(bytes: instances, length: instanceSize, options: [])
+            pluginLineInstanceCount = instances.count
+        } else {
+            pluginLineInstanceBuffer = nil
+            pluginLineInstanceCount = 0
+        }
+
+        if !pointVertices.isEmpty {
+            pluginPointVertexCount = pointVertices.count
+            let bufferSize = pointVertices.count * MemoryLayout<VertexIn>.stride
+            pluginPointBuffer = device.makeBuffer(bytes: pointVertices, length: bufferSize, options: [])
+        } else {
+            pluginPointBuffer = nil
+            pluginPointVertexCount = 0
+        }
+    }
+
     // MARK: - Radius Circle Rendering
 
     /// Update radius circle rendering data
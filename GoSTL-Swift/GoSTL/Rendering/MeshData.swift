@@ -11,14 +11,20 @@ private final class ParallelArray<T>: @unchecked Sendable {
     }
 }
 
-/// GPU-ready mesh data with pre-baked lighting
+/// GPU-ready mesh data: positions, normals and colors (lighting is applied in the shader)
 final class MeshData {
     let vertexBuffer: MTLBuffer
     let vertexCount: Int
 
-    init(device: MTLDevice, model: STLModel) throws {
-        // Calculate vertices with baked lighting
-        let vertices = MeshData.createVertices(from: model)
+    /// - Parameters:
+    ///   - colors: Vertex colors replacing the triangles' own colors (e.g. an analysis visualization);
+    ///     ignored if they do not match the model's triangle count
+    ///   - occlusion: Baked ambient occlusion per triangle corner (see `AmbientOcclusion`), passed to the
+    ///     shader in the texture coordinate; ignored if it does not match the model's triangle count
+    init(device: MTLDevice, model: STLModel, colors: VertexColors? = nil, occlusion: [Float]? = nil) throws {
+        let colors = colors?.triangleCount == model.triangleCount ? colors : nil
+        let occlusion = occlusion?.count == model.triangleCount * 3 ? occlusion : nil
+        let vertices = MeshData.createVertices(from: model, colors: colors, occlusion: occlusion)
         self.vertexCount = vertices.count
 
         // Guard against empty models (zero-length buffers are invalid in Metal)
@@ -36,13 +42,18 @@ final class MeshData {
 
     // MARK: - Vertex Generation
 
-    private static func createVertices(from model: STLModel) -> [VertexIn] {
+    private static func createVertices(from model: STLModel, colors: VertexColors?, occlusion: [Float]?) -> [VertexIn] {
         let triangleCount = model.triangleCount
         let vertexCount = triangleCount * 3
 
         // For small models, use sequential approach
         if triangleCount < 10000 {
-            return createVerticesSequential(from: model)
+            var vertices: [VertexIn] = []
+            vertices.reserveCapacity(vertexCount)
+            for index in 0..<triangleCount {
+                vertices.append(contentsOf: triangleVertices(model.triangles[index], index: index, colors: colors, occlusion: occlusion))
+            }
+            return vertices
         }
 
         // For large models, use parallel approach
@@ -57,42 +68,34 @@ final class MeshData {
             let endTriangle = min(startTriangle + chunkSize, triangleCount)
 
             for i in startTriangle..<endTriangle {
-                let triangle = model.triangles[i]
-                let color = triangle.color?.simd4 ?? SIMD4<Float>(1.0, 1.0, 1.0, 1.0)
-                let normal = triangle.normal.float3
-
-                let vertexIndex = i * 3
-                vertices[vertexIndex] = VertexIn(position: triangle.v1.float3, normal: normal, color: color)
-                vertices[vertexIndex + 1] = VertexIn(position: triangle.v2.float3, normal: normal, color: color)
-                vertices[vertexIndex + 2] = VertexIn(position: triangle.v3.float3, normal: normal, color: color)
+                let corners = triangleVertices(model.triangles[i], index: i, colors: colors, occlusion: occlusion)
+                vertices[i * 3] = corners[0]
+                vertices[i * 3 + 1] = corners[1]
+                vertices[i * 3 + 2] = corners[2]
             }
         }
 
         return vertices.storage
     }
 
-    private static func createVerticesSequential(from model: STLModel) -> [VertexIn] {
-        var vertices: [VertexIn] = []
-        vertices.reserveCapacity(model.triangleCount * 3)
-
-        for triangle in model.triangles {
-            let color = triangle.color?.simd4 ?? SIMD4<Float>(1.0, 1.0, 1.0, 1.0)
-            let normal = triangle.normal.float3
-
-            vertices.append(VertexIn(position: triangle.v1.float3, normal: normal, color: color))
-            vertices.append(VertexIn(position: triangle.v2.float3, normal: normal, color: color))
-            vertices.append(VertexIn(position: triangle.v3.float3, normal: normal, color: color))
+    /// The three vertices of a triangle, colored by the vertex colors or the triangle's own color
+    private static func triangleVertices(_ triangle: Triangle, index: Int, colors: VertexColors?, occlusion: [Float]?) -> [VertexIn] {
+        let normal = triangle.normal.float3
+        let corners: (SIMD4<Float>, SIMD4<Float>, SIMD4<Float>)
+        if let colors {
+            corners = colors.corners(ofTriangle: index)
+        } else {
+            let color = VertexColors.vertexColor(of: triangle.color)
+            corners = (color, color, color)
         }
-
-        return vertices
-    }
-
-    // MARK: - Three-Light Shading
-
-    /// Return white color for vertices (lighting now calculated in shader with material properties)
-    private static func calculateLighting(normal: Vector3) -> SIMD4<Float> {
-        // Return white color - lighting is now calculated dynamically in the shader
-        // based on material properties (glossiness, base color, etc.)
-        return SIMD4<Float>(1.0, 1.0, 1.0, 1.0)
+        // Darkening in texCoord.x (zero: unoccluded, as for meshes without a bake)
+        let shade = { (corner: Int) -> SIMD2<Float> in
+            SIMD2((occlusion?[index * 3 + corner] ?? 0) * AmbientOcclusion.strength, 0)
+        }
+        return [
+            VertexIn(position: triangle.v1.float3, normal: normal, color: corners.0, texCoord: shade(0)),
+            VertexIn(position: triangle.v2.float3, normal: normal, color: corners.1, texCoord: shade(1)),
+            VertexIn(position: triangle.v3.float3, normal: normal, color: corners.2, texCoord: shade(2))
+        ]
     }
 }
@@ -16,8 +16,18 @@ final class MetalRenderer {
     let depthStencilState: MTLDepthStencilState
     let transparentDepthStencilState: MTLDepthStencilState
     let orientationCubeDepthStencilState: MTLDepthStencilState
+    let overlayDepthStencilState: MTLDepthStencilState
     let samplerState: MTLSamplerState
 
+    /// Draw call / primitive counters for the frame being encoded
+    private var frameCounters = FrameCounters()
+
+    /// Start time of the previous frame (for time-based camera inertia)
+    private var lastFrameStart: CFAbsoluteTime?
+
+    /// Zoom applied on top of the camera projection while the magnifier inset is encoded
+    private var magnifierTransform: simd_float4x4?
+
     init(device: MTLDevice) throws {
         print("DEBUG: Initializing MetalRenderer...")
         self.device = device
@@ -41,6 +51,7 @@ final class MetalRenderer {
         self.depthStencilState = Self.createDepthStencilState(device: device)
         self.transparentDepthStencilState = Self.createTransparentDepthStencilState(device: device)
         self.orientationCubeDepthStencilState = Self.createOrientationCubeDepthStencilState(device: device)
+        self.overlayDepthStencilState = Self.createOverlayDepthStencilState(device: device)
 
         // Create sampler state for texture sampling
         self.samplerState = Self.createSamplerState(device: device)
@@ -75,6 +86,10 @@ final class MetalRenderer {
         vertexDescriptor.attributes[2].format = .float4
         vertexDescriptor.attributes[2].offset = MemoryLayout<SIMD3<Float>>.stride * 2
         vertexDescriptor.attributes[2].bufferIndex = 0
+        // Texture coordinate (attribute 3), x carries the baked ambient occlusion
+        vertexDescriptor.attributes[3].format = .float2
+        vertexDescriptor.attributes[3].offset = MemoryLayout<SIMD3<Float>>.stride * 2 + MemoryLayout<SIMD4<Float>>.stride
+        vertexDescriptor.attributes[3].bufferIndex = 0
         // Layout
         vertexDescriptor.layouts[0].stride = MemoryLayout<VertexIn>.stride
         vertexDescriptor.layouts[0].stepFunction = .perVertex
@@ -294,6 +309,13 @@ final class MetalRenderer {
         return device.makeDepthStencilState(descriptor: depthDescriptor)!
     }
 
+    private static func createOverlayDepthStencilState(device: MTLDevice) -> MTLDepthStencilState {
+        let depthDescriptor = MTLDepthStencilDescriptor()
+        depthDescriptor.depthCompareFunction = .always // Covers whatever was drawn before
+        depthDescriptor.isDepthWriteEnabled = true
+        return device.makeDepthStencilState(descriptor: depthDescriptor)!
+    }
+
     private static func loadShaderLibrary(device: MTLDevice) throws -> MTLLibrary {
         // For SPM builds, load from the module bundle
         let bundle = Bundle.module
@@ -315,18 +337,51 @@ final class MetalRenderer {
 
     @MainActor
     func draw(in view: MTKView, appState: AppState) {
+        // Session video export reads back the drawable, which framebuffer-only textures do not allow
+        let isCapturingVideo = appState.sessionPlayer?.videoURL != nil
+        if view.framebufferOnly == isCapturingVideo {
+            view.framebufferOnly = !isCapturingVideo
+        }
+
         guard let commandBuffer = commandQueue.makeCommandBuffer() else { return }
         guard let renderPassDescriptor = view.currentRenderPassDescriptor else { return }
         guard let drawable = view.currentDrawable else { return }
 
-        // Set clear color (dark blue: RGB 15, 18, 25)
+        let frameStart = CFAbsoluteTimeGetCurrent()
+        frameCounters = FrameCounters()
+
+        // Swap in GPU resources finished by background jobs before anything reads them
+        appState.applyPendingUploads()
+
+        // Carry on camera motion (inertia, orbit target transitions)
+        if let lastFrameStart = lastFrameStart {
+            appState.camera.advance(deltaTime: frameStart - lastFrameStart)
+            appState.advanceSession(deltaTime: frameStart - lastFrameStart)
+            appState.advanceCameraFlight(deltaTime: frameStart - lastFrameStart)
+            appState.layerPreview.advance(deltaTime: frameStart - lastFrameStart)
+        }
+        lastFrameStart = frameStart
+
+        // Keep near/far planes tight around the scene to avoid z-fighting
+        appState.updateDepthRange()
+
+        // Outline mode draws its own edges for the current viewpoint
+        appState.viewSize = view.bounds.size
+        appState.updateOutlineWireframe(device: device)
+        appState.updateVertexSnap()
+        appState.updateHoveredRegionHighlight(device: device)
+        appState.updateOcclusion(device: device)
+        appState.updateStreaming(device: device)
+
+        // Set clear color (dark blue: RGB 15, 18, 25; white in outline mode)
+        let clearColor = appState.outlineMode ? AppState.outlineClearColor : appState.clearColor
         if let colorAttachment = renderPassDescriptor.colorAttachments[0] {
             colorAttachment.loadAction = .clear
             colorAttachment.clearColor = MTLClearColor(
-                red: Double(appState.clearColor.x),
-                green: Double(appState.clearColor.y),
-                blue: Double(appState.clearColor.z),
-                alpha: Double(appState.clearColor.w)
+                red: Double(clearColor.x),
+                green: Double(clearColor.y),
+                blue: Double(clearColor.z),
+                alpha: Double(clearColor.w)
             )
         }
 
@@ -334,13 +389,13 @@ final class MetalRenderer {
             return
         }
 
-        // Render build plate first (background)
-        if let buildPlateData = appState.buildPlateData {
+        // Render build plate first (background, hidden in outline mode)
+        if !appState.outlineMode, let buildPlateData = appState.buildPlateData {
             renderBuildPlate(encoder: renderEncoder, buildPlateData: buildPlateData, appState: appState, viewSize: view.drawableSize)
         }
 
         // Render grid (background)
-        if appState.gridMode != .off, let gridData = appState.gridData {
+        if !appState.outlineMode, appState.gridMode != .off, let gridData = appState.gridData {
             renderGrid(encoder: renderEncoder, gridData: gridData, appState: appState, viewSize: view.drawableSize)
         }
 
@@ -349,13 +404,28 @@ final class MetalRenderer {
             renderSlicePlanes(encoder: renderEncoder, slicePlaneData: slicePlaneData, appState: appState, viewSize: view.drawableSize)
         }
 
+        // Render caliper jaws (before mesh, like the slice planes)
+        if let caliperData = appState.caliperData {
+            renderCaliper(encoder: renderEncoder, caliperData: caliperData, appState: appState, viewSize: view.drawableSize)
+        }
+
         // Render mesh if available
         if let meshData = appState.meshData {
-            renderMesh(encoder: renderEncoder, meshData: meshData, appState: appState, viewSize: view.drawableSize)
+            renderMesh(encoder: renderEncoder, meshData: meshData, appState: appState, viewSize: view.drawableSize,
+                       draws: appState.streamingDraws(proxy: meshData))
         }
 
-        // Render wireframe if enabled and available
-        if appState.wireframeMode != .off, let wireframeData = appState.wireframeData {
+        // Render the second model of a clearance check
+        if let clearanceData = appState.clearanceRenderData {
+            renderMesh(encoder: renderEncoder, meshData: clearanceData.otherMesh, appState: appState, viewSize: view.drawableSize)
+        }
+
+        // Render wireframe if enabled and available (outline mode replaces it with silhouette and feature edges)
+        if appState.outlineMode {
+            if let outlineWireframeData = appState.outlineWireframeData {
+                renderWireframe(encoder: renderEncoder, wireframeData: outlineWireframeData, appState: appState, viewSize: view.drawableSize)
+            }
+        } else if appState.wireframeMode != .off, let wireframeData = appState.wireframeData {
             renderWireframe(encoder: renderEncoder, wireframeData: wireframeData, appState: appState, viewSize: view.drawableSize)
         }
 
@@ -364,39 +434,310 @@ final class MetalRenderer {
             renderCutEdges(encoder: renderEncoder, cutEdgeData: cutEdgeData, appState: appState, viewSize: view.drawableSize)
         }
 
+        // Render iso-height contour lines
+        if let contourData = appState.contourData {
+            renderCutEdges(encoder: renderEncoder, cutEdgeData: contourData, appState: appState, viewSize: view.drawableSize)
+        }
+
         // Update and render selected triangles
         if let selectedTrianglesData = appState.selectedTrianglesData {
             appState.updateSelectedTriangles()
             renderSelectedTriangles(encoder: renderEncoder, selectedTrianglesData: selectedTrianglesData, appState: appState, viewSize: view.drawableSize)
         }
 
-        // Update and render measurements (and leveling visualization)
+        // Render the flat face or smooth region under the cursor
+        if let buffer = appState.hoveredRegionBuffer, appState.hoveredRegionVertexCount > 0 {
+            renderHighlight(encoder: renderEncoder, buffer: buffer, vertexCount: appState.hoveredRegionVertexCount, color: AppState.hoveredRegionColor, appState: appState, viewSize: view.drawableSize)
+        }
+
+        // Render interpenetrating triangles of a clearance check
+        if let clearanceData = appState.clearanceRenderData {
+            renderClearanceHighlights(encoder: renderEncoder, clearanceData: clearanceData, appState: appState, viewSize: view.drawableSize)
+        }
+
+        // Update and render measurements (and leveling and reference geometry visualization)
         if let measurementData = appState.measurementData {
-            measurementData.update(measurementSystem: appState.measurementSystem, levelingState: appState.levelingState)
+            measurementData.update(
+                measurementSystem: appState.measurementSystem,
+                levelingState: appState.levelingState,
+                referenceGeometry: appState.referenceGeometry,
+                pluginOverlay: appState.sceneOverlay
+            )
             renderMeasurements(encoder: renderEncoder, measurementData: measurementData, appState: appState, viewSize: view.drawableSize)
         }
 
         // Render grid text labels (3D text)
-        if appState.gridMode != .off, let gridTextData = appState.gridTextData {
+        if !appState.outlineMode, appState.gridMode != .off, let gridTextData = appState.gridTextData {
             renderTextBillboards(encoder: renderEncoder, textData: gridTextData, appState: appState, viewSize: view.drawableSize)
         }
 
+        // Render the vertex snap radius around the cursor while picking
+        if let cursor = appState.snapRadiusLocation, view.bounds.height > 0 {
+            let radius = AppSettings.shared.snapRadius * view.drawableSize.height / view.bounds.height
+            renderSnapRadius(encoder: renderEncoder, cursor: cursor, radius: radius, viewSize: view.drawableSize)
+        }
+
         // Render orientation cube (top right corner)
         if let orientationCubeData = appState.orientationCubeData {
             renderOrientationCube(encoder: renderEncoder, cubeData: orientationCubeData, appState: appState, viewSize: view.drawableSize)
         }
 
+        // Render magnifier inset around the cursor while picking (on top of everything)
+        if let cursor = appState.magnifierLocation {
+            renderMagnifier(encoder: renderEncoder, cursor: cursor, appState: appState, viewSize: view.drawableSize)
+        }
+
         renderEncoder.endEncoding()
 
+        // Capture the frame while replaying a session to video
+        if let player = appState.sessionPlayer, player.videoURL != nil {
+            captureSessionFrame(drawable.texture, player: player, commandBuffer: commandBuffer, appState: appState)
+        }
+
+        // Performance statistics (GPU time arrives asynchronously)
+        let performanceStats = appState.performanceStats
+        performanceStats.recordFrame(
+            start: frameStart,
+            encodeTime: CFAbsoluteTimeGetCurrent() - frameStart,
+            counters: frameCounters,
+            device: device
+        )
+        if performanceStats.isVisible {
+            commandBuffer.addCompletedHandler { buffer in
+                let gpuTime = buffer.gpuEndTime - buffer.gpuStartTime
+                DispatchQueue.main.async {
+                    performanceStats.recordGPUTime(gpuTime)
+                }
+            }
+        }
+
         commandBuffer.present(drawable)
         commandBuffer.commit()
     }
 
+    // MARK: - Session Video Capture
+
+    /// Copy the rendered frame into a CPU-visible buffer and hand it to the video writer once the GPU is done
+    @MainActor
+    private func captureSessionFrame(_ texture: MTLTexture, player: SessionPlayer, commandBuffer: MTLCommandBuffer, appState: AppState) {
+        guard !texture.isFramebufferOnly, texture.pixelFormat == .bgra8Unorm else { return }
+
+        if player.videoWriter == nil, let videoURL = player.videoURL {
+            do {
+                player.videoWriter = try SessionVideoWriter(
+                    url: videoURL,
+                    width: texture.width,
+                    height: texture.height,
+                    frameRate: SessionPlayer.videoFrameRate
+                )
+            } catch {
+                print("Failed to start session video: \(error.localizedDescription)")
+                appState.stopSessionReplay()
+                return
+            }
+        }
+        guard let writer = player.videoWriter else { return }
+
+        let width = texture.width
+        let height = texture.height
+        let bytesPerRow = width * 4
+        guard let buffer = device.makeBuffer(length: bytesPerRow * height, options: .storageModeShared),
+              let blitEncoder = commandBuffer.makeBlitCommandEncoder() else {
+            return
+        }
+        blitEncoder.copy(
+            from: texture,
+            sourceSlice: 0,
+            sourceLevel: 0,
+            sourceOrigin: MTLOrigin(x: 0, y: 0, z: 0),
+            sourceSize: MTLSize(width: width, height: height, depth: 1),
+            to: buffer,
+            destinationOffset: 0,
+            destinationBytesPerRow: bytesPerRow,
+            destinationBytesPerImage: bytesPerRow * height
+        )
+        blitEncoder.endEncoding()
+
+        writer.beginFrame()
+        commandBuffer.addCompletedHandler { _ in
+            writer.appendFrame(bytes: buffer.contents(), bytesPerRow: bytesPerRow, width: width, height: height)
+        }
+    }
+
+    // MARK: - Snap Radius
+
+    /// Faint circle around the cursor showing how far picks reach for a mesh vertex
+    /// - Parameters:
+    ///   - cursor: Cursor position in pixels with Y=0 at the bottom
+    ///   - radius: Snap radius in pixels
+    private func renderSnapRadius(encoder: MTLRenderCommandEncoder, cursor: CGPoint, radius: Double, viewSize: CGSize) {
+        guard radius > 0, viewSize.width > 0, viewSize.height > 0 else { return }
+
+        var overlayUniforms = Uniforms(
+            modelMatrix: matrix_identity_float4x4,
+            viewMatrix: matrix_identity_float4x4,
+            projectionMatrix: matrix_identity_float4x4,
+            normalMatrix: matrix_identity_float3x3,
+            cameraPosition: .zero,
+            viewportHeight: Float(viewSize.height)
+        )
+        let color = SIMD4<Float>(1.0, 1.0, 1.0, 0.35)
+        // Closed line strip; setVertexBytes takes at most 4 KB
+        let segments = 48
+        let lineVertices = (0...segments).map { index -> VertexIn in
+            let angle = Double(index) / Double(segments) * 2 * .pi
+            let x = Float(2 * (cursor.x + radius * cos(angle)) / viewSize.width - 1)
+            let y = Float(2 * (cursor.y + radius * sin(angle)) / viewSize.height - 1)
+            return VertexIn(position: SIMD3(x, y, 0), normal: SIMD3(0, 0, 1), color: color)
+        }
+        encoder.setRenderPipelineState(gridPipelineState)
+        encoder.setDepthStencilState(overlayDepthStencilState)
+        encoder.setVertexBytes(&overlayUniforms, length: MemoryLayout<Uniforms>.size, index: 1)
+        encoder.setVertexBytes(lineVertices, length: lineVertices.count * MemoryLayout<VertexIn>.stride, index: 0)
+        encoder.drawPrimitives(type: .lineStrip, vertexStart: 0, vertexCount: lineVertices.count)
+        frameCounters.record(type: .lineStrip, vertexCount: lineVertices.count)
+    }
+
+    // MARK: - Magnifier
+
+    /// Size of the magnifier inset in pixels
+    static let magnifierSize: Double = 260
+
+    /// Distance between the cursor and the inset in pixels
+    static let magnifierOffset: Double = 32
+
+    /// Draw the scene around the cursor enlarged into a square inset next to it
+    /// The inset uses a shallow depth range like the orientation cube, so it stays in front of the scene
+    private func renderMagnifier(encoder: MTLRenderCommandEncoder, cursor: CGPoint, appState: AppState, viewSize: CGSize) {
+        let size = min(Self.magnifierSize, viewSize.width / 2, viewSize.height / 2)
+        guard size > 0 else { return }
+        let zoom = Float(AppSettings.shared.magnifierZoom)
+
+        // Cursor uses Y=0 at the bottom, the Metal viewport Y=0 at the top
+        let cursorTop = viewSize.height - cursor.y
+        let origin = Self.magnifierOrigin(cursor: CGPoint(x: cursor.x, y: cursorTop), size: size, viewSize: viewSize)
+        encoder.setViewport(MTLViewport(
+            originX: origin.x,
+            originY: origin.y,
+            width: size,
+            height: size,
+            znear: 0.0,
+            zfar: 0.01
+        ))
+
+        // Background fills the inset at its far depth
+        var overlayUniforms = Uniforms(
+            modelMatrix: matrix_identity_float4x4,
+            viewMatrix: matrix_identity_float4x4,
+            projectionMatrix: matrix_identity_float4x4,
+            normalMatrix: matrix_identity_float3x3,
+            cameraPosition: .zero,
+            viewportHeight: Float(size)
+        )
+        let clear = appState.clearColor
+        let background = [(-1, -1), (1, -1), (1, 1), (-1, -1), (1, 1), (-1, 1)].map { x, y in
+            VertexIn(position: SIMD3(Float(x), Float(y), 1), normal: SIMD3(0, 0, 1), color: clear)
+        }
+        encoder.setRenderPipelineState(gridPipelineState)
+        encoder.setDepthStencilState(overlayDepthStencilState)
+        encoder.setVertexBytes(&overlayUniforms, length: MemoryLayout<Uniforms>.size, index: 1)
+        encoder.setVertexBytes(background, length: background.count * MemoryLayout<VertexIn>.stride, index: 0)
+        encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: background.count)
+        frameCounters.record(type: .triangle, vertexCount: background.count)
+
+        // Scene with the projection zoomed around the cursor; the inset keeps the view's aspect
+        // ratio and is `size` pixels high, so screen-space widths (wireframe, lines) stay in pixels
+        magnifierTransform = Self.magnifierTransform(cursor: cursor, zoom: zoom, size: size, viewSize: viewSize)
+        let insetViewSize = CGSize(width: size * viewSize.width / viewSize.height, height: size)
+        if let meshData = appState.meshData {
+            renderMesh(encoder: encoder, meshData: meshData, appState: appState, viewSize: insetViewSize)
+        }
+        if appState.wireframeMode != .off, let wireframeData = appState.wireframeData {
+            renderWireframe(encoder: encoder, wireframeData: wireframeData, appState: appState, viewSize: insetViewSize)
+        }
+        if let cutEdgeData = appState.cutEdgeData {
+            renderCutEdges(encoder: encoder, cutEdgeData: cutEdgeData, appState: appState, viewSize: insetViewSize)
+        }
+        if let contourData = appState.contourData {
+            renderCutEdges(encoder: encoder, cutEdgeData: contourData, appState: appState, viewSize: insetViewSize)
+        }
+        if let selectedTrianglesData = appState.selectedTrianglesData {
+            renderSelectedTriangles(encoder: encoder, selectedTrianglesData: selectedTrianglesData, appState: appState, viewSize: insetViewSize)
+        }
+        if let measurementData = appState.measurementData {
+            // Includes the snap point marker under the cursor
+            renderMeasurements(encoder: encoder, measurementData: measurementData, appState: appState, viewSize: insetViewSize)
+        }
+        magnifierTransform = nil
+
+        // Crosshair at the cursor and a frame around the inset
+        let accent = SIMD4<Float>(0.39, 0.78, 1.0, 1.0)
+        let crosshair = SIMD4<Float>(1.0, 1.0, 1.0, 0.6)
+        let gap: Float = 0.06
+        let arm: Float = 0.2
+        let edge: Float = 0.995
+        let lines: [(SIMD2<Float>, SIMD2<Float>, SIMD4<Float>)] = [
+            (SIMD2(-arm, 0), SIMD2(-gap, 0), crosshair),
+            (SIMD2(gap, 0), SIMD2(arm, 0), crosshair),
+            (SIMD2(0, -arm), SIMD2(0, -gap), crosshair),
+            (SIMD2(0, gap), SIMD2(0, arm), crosshair),
+            (SIMD2(-edge, -edge), SIMD2(edge, -edge), accent),
+            (SIMD2(edge, -edge), SIMD2(edge, edge), accent),
+            (SIMD2(edge, edge), SIMD2(-edge, edge), accent),
+            (SIMD2(-edge, edge), SIMD2(-edge, -edge), accent)
+        ]
+        let lineVertices = lines.flatMap { start, end, color in
+            [start, end].map { VertexIn(position: SIMD3($0.x, $0.y, 0), normal: SIMD3(0, 0, 1), color: color) }
+        }
+        encoder.setRenderPipelineState(gridPipelineState)
+        encoder.setDepthStencilState(overlayDepthStencilState)
+        encoder.setVertexBytes(&overlayUniforms, length: MemoryLayout<Uniforms>.size, index: 1)
+        encoder.setVertexBytes(lineVertices, length: lineVertices.count * MemoryLayout<VertexIn>.stride, index: 0)
+        encoder.drawPrimitives(type: .line, vertexStart: 0, vertexCount: lineVertices.count)
+        frameCounters.record(type: .line, vertexCount: lineVertices.count)
+    }
+
+    /// Top-left corner of the inset: above and right of the cursor, flipped to stay on screen
+    /// - Parameter cursor: Cursor position with Y=0 at the top
+    static func magnifierOrigin(cursor: CGPoint, size: Double, viewSize: CGSize) -> CGPoint {
+        var x = cursor.x + magnifierOffset
+        if x + size > viewSize.width {
+            x = cursor.x - magnifierOffset - size
+        }
+        var y = cursor.y - magnifierOffset - size
+        if y < 0 {
+            y = cursor.y + magnifierOffset
+        }
+        return CGPoint(
+            x: min(max(x, 0), max(viewSize.width - size, 0)),
+            y: min(max(y, 0), max(viewSize.height - size, 0))
+        )
+    }
+
+    /// Clip-space transform that enlarges the area around the cursor to fill an inset of `size` pixels
+    /// - Parameter cursor: Cursor position in pixels with Y=0 at the bottom
+    static func magnifierTransform(cursor: CGPoint, zoom: Float, size: Double, viewSize: CGSize) -> simd_float4x4 {
+        let center = SIMD2<Float>(
+            Float(2 * cursor.x / viewSize.width - 1),
+            Float(2 * cursor.y / viewSize.height - 1)
+        )
+        let scale = SIMD2<Float>(
+            zoom * Float(viewSize.width / size),
+            zoom * Float(viewSize.height / size)
+        )
+        return simd_float4x4(
+            SIMD4(scale.x, 0, 0, 0),
+            SIMD4(0, scale.y, 0, 0),
+            SIMD4(0, 0, 1, 0),
+            SIMD4(-scale.x * center.x, -scale.y * center.y, 0, 1)
+        )
+    }
+
     // MARK: - Selected Triangles Rendering
 
     private func renderSelectedTriangles(encoder: MTLRenderCommandEncoder, selectedTrianglesData: SelectedTrianglesData, appState: AppState, viewSize: CGSize) {
         let aspect = Float(viewSize.width / viewSize.height)
-        var uniforms = createUniforms(camera: appState.camera, aspect: aspect)
+        var uniforms = createUniforms(camera: appState.camera, aspect: aspect, clipPlane: appState.camera.cutawayPlane)
 
         // Use depth bias to render overlay slightly in front of the mesh
         encoder.setDepthBias(-1.0, slopeScale: -1.0, clamp: 0.0)
@@ -420,6 +761,7 @@ final class MetalRenderer {
             encoder.setFragmentBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 0)
 
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: selectedTrianglesData.selectedVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: selectedTrianglesData.selectedVertexCount)
         }
 
         // Render hovered triangle (green overlay, or blended color if selected)
@@ -444,15 +786,102 @@ final class MetalRenderer {
             encoder.setFragmentBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 0)
 
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: selectedTrianglesData.hoveredVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: selectedTrianglesData.hoveredVertexCount)
         }
 
         // Reset depth bias
         encoder.setDepthBias(0.0, slopeScale: 0.0, clamp: 0.0)
     }
 
+    // MARK: - Clearance Rendering
+
+    private func renderClearanceHighlights(encoder: MTLRenderCommandEncoder, clearanceData: ClearanceRenderData, appState: AppState, viewSize: CGSize) {
+        guard let buffer = clearanceData.highlightBuffer, clearanceData.highlightVertexCount > 0 else { return }
+        renderHighlight(encoder: encoder, buffer: buffer, vertexCount: clearanceData.highlightVertexCount, color: ClearanceRenderData.highlightColor, appState: appState, viewSize: viewSize)
+    }
+
+    /// Flat colored triangles drawn slightly in front of the models
+    private func renderHighlight(encoder: MTLRenderCommandEncoder, buffer: MTLBuffer, vertexCount: Int, color: SIMD4<Float>, appState: AppState, viewSize: CGSize) {
+        let aspect = Float(viewSize.width / viewSize.height)
+        var uniforms = createUniforms(camera: appState.camera, aspect: aspect, clipPlane: appState.camera.cutawayPlane)
+
+        // Use depth bias to render the highlight slightly in front of both models
+        encoder.setDepthBias(-1.0, slopeScale: -1.0, clamp: 0.0)
+        encoder.setRenderPipelineState(meshPipelineState)
+        encoder.setDepthStencilState(depthStencilState)
+
+        encoder.setVertexBuffer(buffer, offset: 0, index: 0)
+        encoder.setVertexBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 1)
+
+        var materialProperties = MaterialProperties(
+            baseColor: SIMD3<Float>(color.x, color.y, color.z),
+            glossiness: 0.0,
+            metalness: 0.0,
+            specularIntensity: 0.0
+        )
+        encoder.setFragmentBytes(&materialProperties, length: MemoryLayout<MaterialProperties>.size, index: 1)
+        encoder.setFragmentBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 0)
+
+        encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: vertexCount)
+        frameCounters.record(type: .triangle, vertexCount: vertexCount)
+
+        encoder.setDepthBias(0.0, slopeScale: 0.0, clamp: 0.0)
+    }
+
+    // MARK: - Comparison Pane
+
+    /// Draw the comparison pane: the second model through the shared camera, with the region matching the main selection
+    ///
+    /// The main view advances the camera, so both panes always show the same viewpoint.
+    @MainActor
+    func drawComparison(in view: MTKView, appState: AppState) {
+        guard let commandBuffer = commandQueue.makeCommandBuffer() else { return }
+        guard let renderPassDescriptor = view.currentRenderPassDescriptor else { return }
+        guard let drawable = view.currentDrawable else { return }
+
+        frameCounters = FrameCounters()
+        appState.updateComparisonHighlights(device: device)
+
+        let clearColor = appState.outlineMode ? AppState.outlineClearColor : appState.clearColor
+        if let colorAttachment = renderPassDescriptor.colorAttachments[0] {
+            colorAttachment.loadAction = .clear
+            colorAttachment.clearColor = MTLClearColor(
+                red: Double(clearColor.x),
+                green: Double(clearColor.y),
+                blue: Double(clearColor.z),
+                alpha: Double(clearColor.w)
+            )
+        }
+
+        guard let renderEncoder = commandBuffer.makeRenderCommandEncoder(descriptor: renderPassDescriptor) else {
+            return
+        }
+
+        // Same grid as the main pane, so both models can be read against it
+        if !appState.outlineMode, appState.gridMode != .off, let gridData = appState.gridData {
+            renderGrid(encoder: renderEncoder, gridData: gridData, appState: appState, viewSize: view.drawableSize)
+        }
+
+        if let comparisonData = appState.comparisonRenderData {
+            renderMesh(encoder: renderEncoder, meshData: comparisonData.mesh, appState: appState, viewSize: view.drawableSize)
+            if let buffer = comparisonData.highlightBuffer, comparisonData.highlightVertexCount > 0 {
+                renderHighlight(encoder: renderEncoder, buffer: buffer, vertexCount: comparisonData.highlightVertexCount, color: ComparisonRenderData.highlightColor, appState: appState, viewSize: view.drawableSize)
+            }
+        }
+
+        if let orientationCubeData = appState.orientationCubeData {
+            renderOrientationCube(encoder: renderEncoder, cubeData: orientationCubeData, appState: appState, viewSize: view.drawableSize)
+        }
+
+        renderEncoder.endEncoding()
+        commandBuffer.present(drawable)
+        commandBuffer.commit()
+    }
+
     // MARK: - Mesh Rendering
 
-    private func renderMesh(encoder: MTLRenderCommandEncoder, meshData: MeshData, appState: AppState, viewSize: CGSize) {
+    /// - Parameter draws: Draw calls replacing the single draw of the mesh (chunks of a streamed model)
+    private func renderMesh(encoder: MTLRenderCommandEncoder, meshData: MeshData, appState: AppState, viewSize: CGSize, draws: [StreamingResidency.Draw]? = nil) {
         encoder.setRenderPipelineState(meshPipelineState)
         encoder.setDepthStencilState(depthStencilState)
 
@@ -461,7 +890,7 @@ final class MetalRenderer {
 
         // Create uniforms
         let aspect = Float(viewSize.width / viewSize.height)
-        var uniforms = createUniforms(camera: appState.camera, aspect: aspect)
+        var uniforms = createUniforms(camera: appState.camera, aspect: aspect, clipPlane: appState.camera.cutawayPlane)
 
         // Set uniforms
         encoder.setVertexBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 1)
@@ -473,7 +902,8 @@ final class MetalRenderer {
             glossiness: material.glossiness,
             metalness: material.metalness,
             specularIntensity: material.specularIntensity,
-            showFaceOrientation: appState.showFaceOrientation ? 1.0 : 0.0
+            showFaceOrientation: appState.showFaceOrientation ? 1.0 : 0.0,
+            outlineFill: appState.outlineMode ? 1.0 : 0.0
         )
 
         // Set material properties for fragment shader
@@ -483,7 +913,16 @@ final class MetalRenderer {
         encoder.setFragmentBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 0)
 
         // Draw triangles
-        encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: meshData.vertexCount)
+        guard let draws else {
+            encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: meshData.vertexCount)
+            frameCounters.record(type: .triangle, vertexCount: meshData.vertexCount)
+            return
+        }
+        for draw in draws {
+            encoder.setVertexBuffer(draw.buffer, offset: 0, index: 0)
+            encoder.drawPrimitives(type: .triangle, vertexStart: draw.vertexStart, vertexCount: draw.vertexCount)
+            frameCounters.record(type: .triangle, vertexCount: draw.vertexCount)
+        }
     }
 
     private func renderGrid(encoder: MTLRenderCommandEncoder, gridData: GridData, appState: AppState, viewSize: CGSize) {
@@ -498,11 +937,13 @@ final class MetalRenderer {
         // Draw grid lines
         encoder.setVertexBuffer(gridData.vertexBuffer, offset: 0, index: 0)
         encoder.drawPrimitives(type: .line, vertexStart: 0, vertexCount: gridData.vertexCount)
+        frameCounters.record(type: .line, vertexCount: gridData.vertexCount)
 
         // Draw dimension lines
         if let dimensionBuffer = gridData.dimensionLinesBuffer, gridData.dimensionLinesCount > 0 {
             encoder.setVertexBuffer(dimensionBuffer, offset: 0, index: 0)
             encoder.drawPrimitives(type: .line, vertexStart: 0, vertexCount: gridData.dimensionLinesCount)
+            frameCounters.record(type: .line, vertexCount: gridData.dimensionLinesCount)
         }
     }
 
@@ -516,6 +957,7 @@ final class MetalRenderer {
         encoder.setVertexBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 1)
 
         encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: buildPlateData.vertexCount)
+        frameCounters.record(type: .triangle, vertexCount: buildPlateData.vertexCount)
     }
 
     private func renderSlicePlanes(encoder: MTLRenderCommandEncoder, slicePlaneData: SlicePlaneData, appState: AppState, viewSize: CGSize) {
@@ -533,6 +975,22 @@ final class MetalRenderer {
 
         // Draw slice planes
         encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: slicePlaneData.vertexCount)
+        frameCounters.record(type: .triangle, vertexCount: slicePlaneData.vertexCount)
+    }
+
+    private func renderCaliper(encoder: MTLRenderCommandEncoder, caliperData: CaliperData, appState: AppState, viewSize: CGSize) {
+        // Use grid pipeline for alpha blending support
+        encoder.setRenderPipelineState(gridPipelineState)
+        encoder.setDepthStencilState(depthStencilState)
+
+        encoder.setVertexBuffer(caliperData.vertexBuffer, offset: 0, index: 0)
+
+        let aspect = Float(viewSize.width / viewSize.height)
+        var uniforms = createUniforms(camera: appState.camera, aspect: aspect)
+        encoder.setVertexBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 1)
+
+        encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: caliperData.vertexCount)
+        frameCounters.record(type: .triangle, vertexCount: caliperData.vertexCount)
     }
 
     private func renderWireframe(encoder: MTLRenderCommandEncoder, wireframeData: WireframeData, appState: AppState, viewSize: CGSize) {
@@ -544,7 +1002,12 @@ final class MetalRenderer {
 
         // Create and set uniforms with viewport height for pixel-perfect wireframe
         let aspect = Float(viewSize.width / viewSize.height)
-        var uniforms = createUniforms(camera: appState.camera, aspect: aspect, viewportHeight: Float(viewSize.height))
+        var uniforms = createUniforms(
+            camera: appState.camera,
+            aspect: aspect,
+            viewportHeight: Float(viewSize.height),
+            clipPlane: appState.camera.cutawayPlane
+        )
         encoder.setVertexBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 1)
 
         // Set instance buffer (transformation matrices for each edge)
@@ -559,6 +1022,7 @@ final class MetalRenderer {
             indexBufferOffset: 0,
             instanceCount: wireframeData.instanceCount
         )
+        frameCounters.record(type: .triangle, vertexCount: wireframeData.indexCount, instanceCount: wireframeData.instanceCount)
     }
 
     private func renderCutEdges(encoder: MTLRenderCommandEncoder, cutEdgeData: CutEdgeData, appState: AppState, viewSize: CGSize) {
@@ -585,6 +1049,7 @@ final class MetalRenderer {
             indexBufferOffset: 0,
             instanceCount: cutEdgeData.instanceCount
         )
+        frameCounters.record(type: .triangle, vertexCount: cutEdgeData.indexCount, instanceCount: cutEdgeData.instanceCount)
     }
 
     private func renderMeasurements(encoder: MTLRenderCommandEncoder, measurementData: MeasurementRenderData, appState: AppState, viewSize: CGSize) {
@@ -609,6 +1074,7 @@ final class MetalRenderer {
                 indexBufferOffset: 0,
                 instanceCount: measurementData.lineInstanceCount
             )
+            frameCounters.record(type: .triangle, vertexCount: measurementData.indexCount, instanceCount: measurementData.lineInstanceCount)
         }
 
         // Render preview line (bright green for normal, uses cylinder color for preview which is yellow for constrained)
@@ -629,6 +1095,7 @@ final class MetalRenderer {
                 indexBufferOffset: 0,
                 instanceCount: measurementData.previewLineInstanceCount
             )
+            frameCounters.record(type: .triangle, vertexCount: measurementData.indexCount, instanceCount: measurementData.previewLineInstanceCount)
         }
 
         // Render selected measurement lines (blue)
@@ -649,6 +1116,7 @@ final class MetalRenderer {
                 indexBufferOffset: 0,
                 instanceCount: measurementData.selectedLineInstanceCount
             )
+            frameCounters.record(type: .triangle, vertexCount: measurementData.indexCount, instanceCount: measurementData.selectedLineInstanceCount)
         }
 
         // Render stale measurement lines (gray/faded)
@@ -669,6 +1137,7 @@ final class MetalRenderer {
                 indexBufferOffset: 0,
                 instanceCount: measurementData.staleLineInstanceCount
             )
+            frameCounters.record(type: .triangle, vertexCount: measurementData.indexCount, instanceCount: measurementData.staleLineInstanceCount)
         }
 
         // Render constraint line (red line from constrained endpoint to snap point)
@@ -689,6 +1158,7 @@ final class MetalRenderer {
                 indexBufferOffset: 0,
                 instanceCount: measurementData.constraintLineInstanceCount
             )
+            frameCounters.record(type: .triangle, vertexCount: measurementData.indexCount, instanceCount: measurementData.constraintLineInstanceCount)
         }
 
         // Render constrained endpoint marker (yellow cube at constrained position)
@@ -700,6 +1170,7 @@ final class MetalRenderer {
             var uniformsCopy = uniforms
             encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.stride, index: 1)
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: measurementData.constrainedPointVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: measurementData.constrainedPointVertexCount)
         }
 
         // Render constraining point marker (cyan cube for point constraint)
@@ -711,6 +1182,7 @@ final class MetalRenderer {
             var uniformsCopy = uniforms
             encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.stride, index: 1)
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: measurementData.constrainingPointVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: measurementData.constrainingPointVertexCount)
         }
 
         // Render measurement points
@@ -722,6 +1194,7 @@ final class MetalRenderer {
             var uniformsCopy = uniforms
             encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.stride, index: 1)
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: measurementData.pointCount)
+            frameCounters.record(type: .triangle, vertexCount: measurementData.pointCount)
         }
 
         // Render hover point (on top, with slightly offset depth)
@@ -733,6 +1206,7 @@ final class MetalRenderer {
             var uniformsCopy = uniforms
             encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.stride, index: 1)
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: measurementData.hoverVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: measurementData.hoverVertexCount)
         }
 
         // Render radius measurement circles (using instanced cylinders)
@@ -753,6 +1227,7 @@ final class MetalRenderer {
                 indexBufferOffset: 0,
                 instanceCount: measurementData.radiusCircleInstanceCount
             )
+            frameCounters.record(type: .triangle, vertexCount: measurementData.indexCount, instanceCount: measurementData.radiusCircleInstanceCount)
         }
 
         // Render radius measurement centers
@@ -764,6 +1239,7 @@ final class MetalRenderer {
             var uniformsCopy = uniforms
             encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.stride, index: 1)
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: measurementData.radiusCenterVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: measurementData.radiusCenterVertexCount)
         }
 
         // MARK: - Leveling Visualization
@@ -777,6 +1253,7 @@ final class MetalRenderer {
             var uniformsCopy = uniforms
             encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.stride, index: 1)
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: measurementData.levelingPointVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: measurementData.levelingPointVertexCount)
         }
 
         // Render leveling hover point (green cube)
@@ -788,6 +1265,7 @@ final class MetalRenderer {
             var uniformsCopy = uniforms
             encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.stride, index: 1)
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: measurementData.levelingHoverVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: measurementData.levelingHoverVertexCount)
         }
 
         // Render leveling preview line (green line from point1 to hover/point2)
@@ -808,6 +1286,73 @@ final class MetalRenderer {
                 indexBufferOffset: 0,
                 instanceCount: measurementData.levelingPreviewLineInstanceCount
             )
+            frameCounters.record(type: .triangle, vertexCount: measurementData.indexCount, instanceCount: measurementData.levelingPreviewLineInstanceCount)
+        }
+
+        // Render reference geometry lines (orange plane outlines and axes)
+        if let referenceLineBuffer = measurementData.referenceLineInstanceBuffer, measurementData.referenceLineInstanceCount > 0 {
+            encoder.setRenderPipelineState(wireframePipelineState)
+            encoder.setDepthStencilState(depthStencilState)
+
+            encoder.setVertexBuffer(measurementData.referenceCylinderVertexBuffer, offset: 0, index: 0)
+            var uniformsCopy = uniforms
+            encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.size, index: 1)
+            encoder.setVertexBuffer(referenceLineBuffer, offset: 0, index: 2)
+
+            encoder.drawIndexedPrimitives(
+                type: .triangle,
+                indexCount: measurementData.indexCount,
+                indexType: .uint16,
+                indexBuffer: measurementData.cylinderIndexBuffer,
+                indexBufferOffset: 0,
+                instanceCount: measurementData.referenceLineInstanceCount
+            )
+            frameCounters.record(type: .triangle, vertexCount: measurementData.indexCount, instanceCount: measurementData.referenceLineInstanceCount)
+        }
+
+        // Render reference points and tool preview points (orange/green cubes)
+        if let referencePointBuffer = measurementData.referencePointBuffer, measurementData.referencePointVertexCount > 0 {
+            encoder.setRenderPipelineState(measurementPipelineState)
+            encoder.setDepthStencilState(depthStencilState)
+
+            encoder.setVertexBuffer(referencePointBuffer, offset: 0, index: 0)
+            var uniformsCopy = uniforms
+            encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.stride, index: 1)
+            encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: measurementData.referencePointVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: measurementData.referencePointVertexCount)
+        }
+
+        // Render plugin analyzer overlay lines (magenta)
+        if let pluginLineBuffer = measurementData.pluginLineInstanceBuffer, measurementData.pluginLineInstanceCount > 0 {
+            encoder.setRenderPipelineState(wireframePipelineState)
+            encoder.setDepthStencilState(depthStencilState)
+
+            encoder.setVertexBuffer(measurementData.pluginCylinderVertexBuffer, offset: 0, index: 0)
+            var uniformsCopy = uniforms
+            encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.size, index: 1)
+            encoder.setVertexBuffer(pluginLineBuffer, offset: 0, index: 2)
+
+            encoder.drawIndexedPrimitives(
+                type: .triangle,
+                indexCount: measurementData.indexCount,
+                indexType: .uint16,
+                indexBuffer: measurementData.cylinderIndexBuffer,
+                indexBufferOffset: 0,
+                instanceCount: measurementData.pluginLineInstanceCount
+            )
+            frameCounters.record(type: .triangle, vertexCount: measurementData.indexCount, instanceCount: measurementData.pluginLineInstanceCount)
+        }
+
+        // Render plugin analyzer overlay points (magenta cubes)
+        if let pluginPointBuffer = measurementData.pluginPointBuffer, measurementData.pluginPointVertexCount > 0 {
+            encoder.setRenderPipelineState(measurementPipelineState)
+            encoder.setDepthStencilState(depthStencilState)
+
+            encoder.setVertexBuffer(pluginPointBuffer, offset: 0, index: 0)
+            var uniformsCopy = uniforms
+            encoder.setVertexBytes(&uniformsCopy, length: MemoryLayout<Uniforms>.stride, index: 1)
+            encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: measurementData.pluginPointVertexCount)
+            frameCounters.record(type: .triangle, vertexCount: measurementData.pluginPointVertexCount)
         }
     }
 
@@ -834,6 +1379,7 @@ final class MetalRenderer {
 
             // Draw the quad (6 vertices = 2 triangles)
             encoder.drawPrimitives(type: .triangle, vertexStart: vertexOffset, vertexCount: 6)
+            frameCounters.record(type: .triangle, vertexCount: 6)
             vertexOffset += 6
         }
     }
@@ -891,6 +1437,7 @@ final class MetalRenderer {
 
         // Draw cube
         encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: cubeData.vertexCount)
+        frameCounters.record(type: .triangle, vertexCount: cubeData.vertexCount)
 
         // Render 3D text labels on cube faces
         if let textBuffer = cubeData.textVertexBuffer, !cubeData.textTextures.isEmpty {
@@ -903,6 +1450,7 @@ final class MetalRenderer {
             for (texture, vertexOffset) in cubeData.textTextures {
                 encoder.setFragmentTexture(texture, index: 0)
                 encoder.drawPrimitives(type: .triangle, vertexStart: vertexOffset, vertexCount: 6)
+                frameCounters.record(type: .triangle, vertexCount: 6)
             }
         }
 
@@ -921,6 +1469,7 @@ final class MetalRenderer {
                 indexBuffer: axisIndexBuffer,
                 indexBufferOffset: 0
             )
+            frameCounters.record(type: .triangle, vertexCount: cubeData.axisIndexCount)
         }
 
         // Render axis labels as camera-facing billboards
@@ -964,6 +1513,7 @@ final class MetalRenderer {
                 encoder.setVertexBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 1)
                 encoder.setFragmentTexture(label.texture, index: 0)
                 encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: 6)
+                frameCounters.record(type: .triangle, vertexCount: 6)
             }
         }
 
@@ -977,6 +1527,7 @@ final class MetalRenderer {
             encoder.setFragmentTexture(backgroundTexture, index: 0)
             encoder.setFragmentSamplerState(samplerState, index: 0)
             encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: cubeData.shortcutBackgroundCount)
+            frameCounters.record(type: .triangle, vertexCount: cubeData.shortcutBackgroundCount)
         }
 
         // Render keyboard shortcut text
@@ -990,6 +1541,7 @@ final class MetalRenderer {
             for (texture, vertexOffset) in cubeData.shortcutTextTextures {
                 encoder.setFragmentTexture(texture, index: 0)
                 encoder.drawPrimitives(type: .triangle, vertexStart: vertexOffset, vertexCount: 6)
+                frameCounters.record(type: .triangle, vertexCount: 6)
             }
         }
 
@@ -1081,18 +1633,22 @@ final class MetalRenderer {
         return vertices
     }
 
-    private func createUniforms(camera: Camera, aspect: Float, viewportHeight: Float = 0) -> Uniforms {
-        let modelMatrix = simd_float4x4(1.0) // Identity - model at origin
+    /// - Parameter clipPlane: Cutaway plane for shaders that support clipping (zero = none)
+    func createUniforms(camera: Camera, aspect: Float, viewportHeight: Float = 0, clipPlane: SIMD4<Float> = .zero) -> Uniforms {
+        let modelMatrix = camera.displayMatrix() // Identity unless Z is exaggerated
         let viewMatrix = camera.viewMatrix()
-        let projectionMatrix = camera.projectionMatrix(aspect: aspect)
-
-        // Normal matrix (inverse transpose of model-view)
-        let modelView = viewMatrix * modelMatrix
-        let normalMatrix = simd_float3x3(
-            simd_float3(modelView[0].x, modelView[0].y, modelView[0].z),
-            simd_float3(modelView[1].x, modelView[1].y, modelView[1].z),
-            simd_float3(modelView[2].x, modelView[2].y, modelView[2].z)
+        var projectionMatrix = camera.projectionMatrix(aspect: aspect)
+        if let magnifierTransform {
+            projectionMatrix = magnifierTransform * projectionMatrix
+        }
+
+        // Normal matrix (inverse transpose of model-view; the Z stretch scales normals by 1/k)
+        let view3x3 = simd_float3x3(
+            simd_float3(viewMatrix[0].x, viewMatrix[0].y, viewMatrix[0].z),
+            simd_float3(viewMatrix[1].x, viewMatrix[1].y, viewMatrix[1].z),
+            simd_float3(viewMatrix[2].x, viewMatrix[2].y, viewMatrix[2].z)
         )
+        let normalMatrix = view3x3 * simd_float3x3(diagonal: SIMD3(1, 1, 1 / camera.zExaggeration))
 
         return Uniforms(
             modelMatrix: modelMatrix,
@@ -1100,7 +1656,8 @@ final class MetalRenderer {
             projectionMatrix: projectionMatrix,
             normalMatrix: normalMatrix,
             cameraPosition: camera.position,
-            viewportHeight: viewportHeight
+            viewportHeight: viewportHeight,
+            clipPlane: clipPlane
         )
     }
 }
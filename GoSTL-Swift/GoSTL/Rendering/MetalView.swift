@@ -85,7 +85,7 @@ class InteractiveMTKView: MTKView {
     override var acceptsFirstResponder: Bool { true }
 
     /// Supported file extensions for drag and drop
-    private static let supportedExtensions: Set<String> = ["stl", "3mf", "scad", "yaml", "yml"]
+    private static var supportedExtensions: Set<String> { Set(ModelFileLoader.viewerExtensions) }
 
     override init(frame frameRect: CGRect, device: MTLDevice?) {
         super.init(frame: frameRect, device: device)
@@ -101,7 +101,7 @@ class InteractiveMTKView: MTKView {
     }
 
     private func setupTrackingArea() {
-        let options: NSTrackingArea.Options = [.activeAlways, .mouseMoved, .inVisibleRect]
+        let options: NSTrackingArea.Options = [.activeAlways, .mouseMoved, .mouseEnteredAndExited, .inVisibleRect]
         let trackingArea = NSTrackingArea(rect: bounds, options: options, owner: self, userInfo: nil)
         addTrackingArea(trackingArea)
     }
@@ -149,6 +149,9 @@ class InteractiveMTKView: MTKView {
         let scale = drawableSize.width / bounds.size.width
         let scaledLocation = CGPoint(x: location.x * scale, y: location.y * scale)
 
+        // The magnifier follows hovering only, not camera drags
+        coordinator.appState.cursorLocation = nil
+
         coordinator.inputHandler.handleMouseDragged(
             to: scaledLocation,
             camera: coordinator.appState.camera,
@@ -176,12 +179,22 @@ class InteractiveMTKView: MTKView {
                 y: location.y * scale
             )
 
-            coordinator.inputHandler.handleMouseClick(
+            // Double-click on the surface pivots the camera there
+            let handledDoubleClick = event.clickCount == 2 && coordinator.inputHandler.handleDoubleClick(
                 at: scaledLocation,
                 camera: coordinator.appState.camera,
                 viewSize: drawableSize,
                 appState: coordinator.appState
             )
+
+            if !handledDoubleClick {
+                coordinator.inputHandler.handleMouseClick(
+                    at: scaledLocation,
+                    camera: coordinator.appState.camera,
+                    viewSize: drawableSize,
+                    appState: coordinator.appState
+                )
+            }
         }
 
         mouseDownLocation = nil
@@ -209,6 +222,12 @@ class InteractiveMTKView: MTKView {
         )
     }
 
+    override func mouseExited(with event: NSEvent) {
+        coordinator?.appState.cursorLocation = nil
+        coordinator?.appState.measurementSystem.hoveredLabel = nil
+        coordinator?.appState.clearHoveredRegion()
+    }
+
     override func rightMouseDown(with event: NSEvent) {
         guard let coordinator = coordinator else { return }
         let location = convert(event.locationInWindow, from: nil)
@@ -220,20 +239,48 @@ class InteractiveMTKView: MTKView {
             y: location.y * scale
         )
 
-        // Debug ray casting
-        coordinator.inputHandler.debugRayCast(
+        // Option-right-click keeps the ray casting diagnostics
+        if event.modifierFlags.contains(.option) {
+            coordinator.inputHandler.debugRayCast(
+                at: scaledLocation,
+                camera: coordinator.appState.camera,
+                viewSize: drawableSize,
+                appState: coordinator.appState
+            )
+            return
+        }
+
+        let appState = coordinator.appState
+        guard let target = coordinator.inputHandler.contextMenuTarget(
             at: scaledLocation,
-            camera: coordinator.appState.camera,
+            camera: appState.camera,
             viewSize: drawableSize,
-            appState: coordinator.appState
-        )
+            appState: appState
+        ) else { return }
+
+        let actions = ContextMenu.actions(for: target, measurementSystem: appState.measurementSystem)
+        guard !actions.isEmpty else { return }
+        let menu = ContextMenu.makeMenu(actions, isChecked: appState.isContextMenuActionActive) { action in
+            appState.performContextMenuAction(action)
+        }
+        NSMenu.popUpContextMenu(menu, with: event, for: self)
     }
 
     override func scrollWheel(with event: NSEvent) {
         guard let coordinator = coordinator else { return }
+        let location = convert(event.locationInWindow, from: nil)
+
+        // Convert location from points to pixels for the zoom-to-cursor ray
+        let scale = drawableSize.width / bounds.size.width
+        let scaledLocation = CGPoint(x: location.x * scale, y: location.y * scale)
+
         coordinator.inputHandler.handleScroll(
             deltaY: event.scrollingDeltaY,
-            camera: coordinator.appState.camera
+            camera: coordinator.appState.camera,
+            at: scaledLocation,
+            viewSize: drawableSize,
+            appState: coordinator.appState,
+            hasPreciseDeltas: event.hasPreciseScrollingDeltas
         )
     }
 
@@ -242,7 +289,11 @@ class InteractiveMTKView: MTKView {
     override func otherMouseDown(with event: NSEvent) {
         guard let coordinator = coordinator else { return }
         let location = convert(event.locationInWindow, from: nil)
-        coordinator.inputHandler.handleMiddleMouseDown(at: location)
+        coordinator.inputHandler.handleMiddleMouseDown(
+            at: location,
+            modifierFlags: event.modifierFlags,
+            camera: coordinator.appState.camera
+        )
     }
 
     override func otherMouseDragged(with event: NSEvent) {
@@ -324,7 +375,7 @@ class InteractiveMTKView: MTKView {
 
         // Check if it's a supported file type
         if let url = extractFileURL(from: sender.draggingPasteboard),
-           Self.supportedExtensions.contains(url.pathExtension.lowercased()) {
+           Self.supportedExtensions.contains(ModelFileLoader.modelExtension(of: url)) {
             return .copy
         }
 
@@ -333,7 +384,7 @@ class InteractiveMTKView: MTKView {
 
     override func performDragOperation(_ sender: NSDraggingInfo) -> Bool {
         guard let url = extractFileURL(from: sender.draggingPasteboard),
-              Self.supportedExtensions.contains(url.pathExtension.lowercased()),
+              Self.supportedExtensions.contains(ModelFileLoader.modelExtension(of: url)),
               let coordinator = coordinator,
               let device = device else {
             return false
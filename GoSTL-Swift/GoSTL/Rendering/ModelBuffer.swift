@@ -0,0 +1,104 @@
+import Foundation
+import Metal
+
+/// A model with its vertex buffer, ready to be shown
+struct PreparedModel: @unchecked Sendable {
+    /// Model in render space
+    let model: STLModel
+    /// Offset subtracted from file coordinates (see `AppState.coordinateOffset`)
+    let coordinateOffset: Vector3
+    let meshData: MeshData
+
+    /// Recenter a model and build its vertex buffer. Safe to call off the main thread
+    /// (Metal buffer creation is thread-safe); nothing is visible until the model is swapped in.
+    /// - Parameter coordinateOffset: Offset to keep (reloads), or nil to pick one for this model
+    init(fileModel: STLModel, device: MTLDevice, coordinateOffset: Vector3? = nil) throws {
+        let offset = coordinateOffset ?? STLModel.recenteringOffset(for: fileModel.boundingBox())
+        self.model = offset == .zero ? fileModel : fileModel.translated(by: -offset)
+        self.coordinateOffset = offset
+        self.meshData = try MeshData(device: device, model: model)
+    }
+}
+
+/// Back buffer for the displayed model.
+///
+/// Background jobs stage a fully prepared model here; the main thread swaps it in as one unit,
+/// so the render loop never sees a new model with an old vertex buffer (or the other way round).
+final class ModelBuffer: @unchecked Sendable {
+    private let lock = NSLock()
+    private var staged: PreparedModel?
+    private var stagedTicket = 0
+
+    /// Stage a prepared model (any thread)
+    /// - Parameter job: Job preparing the model; checked under the lock, so a job cancelled
+    ///   by a newer one can never stage after it
+    /// - Returns: Ticket for `swap(_:)`; staging again invalidates earlier tickets
+    @discardableResult
+    func stage(_ prepared: PreparedModel, job: JobContext? = nil) throws -> Int {
+        lock.lock()
+        defer { lock.unlock() }
+        try job?.checkCancellation()
+        stagedTicket += 1
+        staged = prepared
+        return stagedTicket
+    }
+
+    /// Take the staged model if it is still the one staged with this ticket (main thread)
+    func swap(_ ticket: Int) -> PreparedModel? {
+        lock.lock()
+        defer { lock.unlock() }
+        guard ticket == stagedTicket, let prepared = staged else { return nil }
+        staged = nil
+        return prepared
+    }
+
+    /// Drop a staged model that will not be shown
+    func discard() {
+        lock.lock()
+        staged = nil
+        lock.unlock()
+    }
+}
+
+/// GPU resources built in the background, installed at the start of the next frame.
+///
+/// Each upload is tagged with the model generation it was built for; uploads for a model
+/// that has since been replaced or transformed are dropped instead of drawn over the new one.
+final class GPUUploadQueue: @unchecked Sendable {
+    private let lock = NSLock()
+    private var uploads: [(generation: Int, install: @MainActor () -> Void)] = []
+
+    var isEmpty: Bool {
+        lock.lock()
+        defer { lock.unlock() }
+        return uploads.isEmpty
+    }
+
+    /// Queue an upload (any thread)
+    func enqueue(generation: Int, _ install: @escaping @MainActor () -> Void) {
+        lock.lock()
+        uploads.append((generation, install))
+        lock.unlock()
+    }
+
+    /// Install queued uploads for the current generation and drop the rest
+    /// - Returns: Number of uploads installed
+    @MainActor
+    @discardableResult
+    func drain(currentGeneration: Int) -> Int {
+        lock.lock()
+        let pending = uploads
+        uploads.removeAll()
+        lock.unlock()
+
+        let current = pending.filter { $0.generation == currentGeneration }
+        current.forEach { $0.install() }
+        return current.count
+    }
+
+    func removeAll() {
+        lock.lock()
+        uploads.removeAll()
+        lock.unlock()
+    }
+}
@@ -0,0 +1,267 @@
+import CoreGraphics
+import Foundation
+import ImageIO
+import Metal
+
+/// Renders a model into an image without a window (thumbnails, headless previews)
+///
+/// Uses the viewer's mesh pipeline, so thumbnails are shaded like the model in the viewer.
+final class OffscreenRenderer {
+    private let renderer: MetalRenderer
+
+    /// MSAA sample count of the viewer pipelines
+    private static let sampleCount = 4
+
+    init(device: MTLDevice? = MTLCreateSystemDefaultDevice()) throws {
+        guard let device else {
+            throw OffscreenRenderError.noDevice
+        }
+        renderer = try MetalRenderer(device: device)
+    }
+
+    /// Render the model framed from the given view
+    /// - Parameters:
+    ///   - model: Model in any coordinates (it is moved to the origin for rendering)
+    ///   - size: Width and height of the image in pixels
+    ///   - material: Material used for shading
+    ///   - preset: Viewing direction
+    ///   - background: Background color (transparent by default)
+    ///   - ambientOcclusion: Bake ambient occlusion for depth cues (slower)
+    func render(
+        model: STLModel,
+        size: Int,
+        material: Material = .pla,
+        preset: CameraPreset = .home,
+        background: SIMD4<Float> = .zero,
+        ambientOcclusion: Bool = false
+    ) throws -> CGImage {
+        let bounds = model.boundingBox()
+        let centered = model.translated(by: Vector3(0, 0, 0) - bounds.center)
+
+        let camera = Camera()
+        camera.setPreset(preset)
+        camera.frameBoundingBox(centered.boundingBox())
+        // frameBoundingBox leaves room for the grid; thumbnails fill the image
+        camera.distance *= 0.8
+        camera.fitDepthRange(to: centered.boundingBox())
+
+        let occlusion = ambientOcclusion ? try AmbientOcclusion.bake(triangles: centered.triangles) : nil
+        return try render(model: centered, camera: camera, size: size, material: material, background: background, occlusion: occlusion)
+    }
+
+    /// Render the model as seen from a camera in the same coordinates (e.g. a saved view of the viewer)
+    /// The camera's cutaway plane, if any, is applied.
+    /// - Parameter occlusion: Baked ambient occlusion of the model's triangle corners (see `AmbientOcclusion`)
+    func render(
+        model: STLModel,
+        camera: Camera,
+        size: Int,
+        material: Material = .pla,
+        background: SIMD4<Float> = .zero,
+        occlusion: [Float]? = nil
+    ) throws -> CGImage {
+        try Self.image(from: renderFrame(
+            model: model,
+            camera: camera,
+            size: size,
+            material: material,
+            background: background,
+            occlusion: occlusion
+        ))
+    }
+
+    /// Render the model into raw pixels, optionally with the depth buffer (for post-processing)
+    func renderFrame(
+        model: STLModel,
+        camera: Camera,
+        size: Int,
+        material: Material = .pla,
+        background: SIMD4<Float> = .zero,
+        occlusion: [Float]? = nil,
+        includeDepth: Bool = false
+    ) throws -> OffscreenFrame {
+        let device = renderer.device
+        let meshData = try MeshData(device: device, model: model, occlusion: occlusion)
+
+        let textures = try makeTextures(size: size)
+        let passDescriptor = MTLRenderPassDescriptor()
+        passDescriptor.colorAttachments[0].texture = textures.color
+        passDescriptor.colorAttachments[0].resolveTexture = textures.resolve
+        passDescriptor.colorAttachments[0].loadAction = .clear
+        passDescriptor.colorAttachments[0].storeAction = .multisampleResolve
+        passDescriptor.colorAttachments[0].clearColor = MTLClearColor(
+            red: Double(background.x),
+            green: Double(background.y),
+            blue: Double(background.z),
+            alpha: Double(background.w)
+        )
+        passDescriptor.depthAttachment.texture = textures.depth
+        passDescriptor.depthAttachment.loadAction = .clear
+        passDescriptor.depthAttachment.storeAction = includeDepth ? .multisampleResolve : .dontCare
+        passDescriptor.depthAttachment.resolveTexture = includeDepth ? textures.depthResolve : nil
+        passDescriptor.depthAttachment.clearDepth = 1.0
+
+        let bytesPerRow = size * 4
+        guard let commandBuffer = renderer.commandQueue.makeCommandBuffer(),
+              let encoder = commandBuffer.makeRenderCommandEncoder(descriptor: passDescriptor),
+              let buffer = device.makeBuffer(length: bytesPerRow * size, options: .storageModeShared),
+              let depthBuffer = device.makeBuffer(length: includeDepth ? bytesPerRow * size : 4, options: .storageModeShared) else {
+            throw OffscreenRenderError.renderFailed
+        }
+
+        encoder.setRenderPipelineState(renderer.meshPipelineState)
+        encoder.setDepthStencilState(renderer.depthStencilState)
+        encoder.setVertexBuffer(meshData.vertexBuffer, offset: 0, index: 0)
+        var uniforms = renderer.createUniforms(camera: camera, aspect: 1, clipPlane: camera.cutawayPlane)
+        encoder.setVertexBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 1)
+        encoder.setFragmentBytes(&uniforms, length: MemoryLayout<Uniforms>.size, index: 0)
+        var materialProperties = MaterialProperties(
+            baseColor: material.baseColor,
+            glossiness: material.glossiness,
+            metalness: material.metalness,
+            specularIntensity: material.specularIntensity
+        )
+        encoder.setFragmentBytes(&materialProperties, length: MemoryLayout<MaterialProperties>.size, index: 1)
+        encoder.drawPrimitives(type: .triangle, vertexStart: 0, vertexCount: meshData.vertexCount)
+        encoder.endEncoding()
+
+        guard let blitEncoder = commandBuffer.makeBlitCommandEncoder() else {
+            throw OffscreenRenderError.renderFailed
+        }
+        blitEncoder.copy(
+            from: textures.resolve,
+            sourceSlice: 0,
+            sourceLevel: 0,
+            sourceOrigin: MTLOrigin(x: 0, y: 0, z: 0),
+            sourceSize: MTLSize(width: size, height: size, depth: 1),
+            to: buffer,
+            destinationOffset: 0,
+            destinationBytesPerRow: bytesPerRow,
+            destinationBytesPerImage: bytesPerRow * size
+        )
+        if includeDepth {
+            // 32-bit float depth: also four bytes per pixel
+            blitEncoder.copy(
+                from: textures.depthResolve,
+                sourceSlice: 0,
+                sourceLevel: 0,
+                sourceOrigin: MTLOrigin(x: 0, y: 0, z: 0),
+                sourceSize: MTLSize(width: size, height: size, depth: 1),
+                to: depthBuffer,
+                destinationOffset: 0,
+                destinationBytesPerRow: bytesPerRow,
+                destinationBytesPerImage: bytesPerRow * size
+            )
+        }
+        blitEncoder.endEncoding()
+
+        commandBuffer.commit()
+        commandBuffer.waitUntilCompleted()
+        if let error = commandBuffer.error {
+            throw error
+        }
+
+        let pixelCount = size * size
+        let color = Array(UnsafeBufferPointer(start: buffer.contents().assumingMemoryBound(to: UInt8.self), count: pixelCount * 4))
+        let depth = includeDepth
+            ? Array(UnsafeBufferPointer(start: depthBuffer.contents().assumingMemoryBound(to: Float.self), count: pixelCount))
+            : nil
+        return OffscreenFrame(size: size, bgra: color, depth: depth)
+    }
+
+    /// Image of rendered pixels (BGRA with premultiplied alpha, as rendered)
+    static func image(from frame: OffscreenFrame) throws -> CGImage {
+        let bytesPerRow = frame.size * 4
+        guard let provider = CGDataProvider(data: Data(frame.bgra) as CFData),
+              let image = CGImage(
+                width: frame.size,
+                height: frame.size,
+                bitsPerComponent: 8,
+                bitsPerPixel: 32,
+                bytesPerRow: bytesPerRow,
+                space: CGColorSpaceCreateDeviceRGB(),
+                bitmapInfo: CGBitmapInfo(rawValue: CGImageAlphaInfo.premultipliedFirst.rawValue | CGBitmapInfo.byteOrder32Little.rawValue),
+                provider: provider,
+                decode: nil,
+                shouldInterpolate: false,
+                intent: .defaultIntent
+              ) else {
+            throw OffscreenRenderError.renderFailed
+        }
+        return image
+    }
+
+    /// Render the model and encode it as PNG
+    func renderPNG(model: STLModel, size: Int, material: Material = .pla, ambientOcclusion: Bool = false) throws -> Data {
+        try Self.pngData(render(model: model, size: size, material: material, ambientOcclusion: ambientOcclusion))
+    }
+
+    static func pngData(_ image: CGImage) throws -> Data {
+        let data = NSMutableData()
+        guard let destination = CGImageDestinationCreateWithData(data, "public.png" as CFString, 1, nil) else {
+            throw OffscreenRenderError.encodingFailed
+        }
+        CGImageDestinationAddImage(destination, image, nil)
+        guard CGImageDestinationFinalize(destination) else {
+            throw OffscreenRenderError.encodingFailed
+        }
+        return data as Data
+    }
+
+    private func makeTextures(size: Int) throws -> (color: MTLTexture, resolve: MTLTexture, depth: MTLTexture, depthResolve: MTLTexture) {
+        let colorDescriptor = MTLTextureDescriptor.texture2DDescriptor(pixelFormat: .bgra8Unorm, width: size, height: size, mipmapped: false)
+        colorDescriptor.textureType = .type2DMultisample
+        colorDescriptor.sampleCount = Self.sampleCount
+        colorDescriptor.usage = .renderTarget
+        colorDescriptor.storageMode = .private
+
+        let resolveDescriptor = MTLTextureDescriptor.texture2DDescriptor(pixelFormat: .bgra8Unorm, width: size, height: size, mipmapped: false)
+        resolveDescriptor.usage = .renderTarget
+        resolveDescriptor.storageMode = .private
+
+        let depthDescriptor = MTLTextureDescriptor.texture2DDescriptor(pixelFormat: .depth32Float, width: size, height: size, mipmapped: false)
+        depthDescriptor.textureType = .type2DMultisample
+        depthDescriptor.sampleCount = Self.sampleCount
+        depthDescriptor.usage = .renderTarget
+        depthDescriptor.storageMode = .private
+
+        let depthResolveDescriptor = MTLTextureDescriptor.texture2DDescriptor(pixelFormat: .depth32Float, width: size, height: size, mipmapped: false)
+        depthResolveDescriptor.usage = .renderTarget
+        depthResolveDescriptor.storageMode = .private
+
+        let device = renderer.device
+        guard let color = device.makeTexture(descriptor: colorDescriptor),
+              let resolve = device.makeTexture(descriptor: resolveDescriptor),
+              let depth = device.makeTexture(descriptor: depthDescriptor),
+              let depthResolve = device.makeTexture(descriptor: depthResolveDescriptor) else {
+            throw OffscreenRenderError.renderFailed
+        }
+        return (color, resolve, depth, depthResolve)
+    }
+}
+
+/// Raw pixels of an offscreen render, top row first
+struct OffscreenFrame: Sendable {
+    let size: Int
+    /// Four bytes per pixel, BGRA with premultiplied alpha
+    var bgra: [UInt8]
+    /// Depth buffer value per pixel (1 where nothing was drawn), if requested
+    let depth: [Float]?
+}
+
+enum OffscreenRenderError: LocalizedError {
+    case noDevice
+    case renderFailed
+    case encodingFailed
+
+    var errorDescription: String? {
+        switch self {
+        case .noDevice:
+            return "No Metal device available for rendering"
+        case .renderFailed:
+            return "Failed to render the model"
+        case .encodingFailed:
+            return "Failed to encode the image as PNG"
+        }
+    }
+}
@@ -0,0 +1,143 @@
+import Foundation
+import Metal
+import Observation
+
+/// Draw call and primitive counters for a single frame
+struct FrameCounters {
+    var drawCalls: Int = 0
+    var triangles: Int = 0
+    var lines: Int = 0
+
+    /// Record one draw call
+    mutating func record(type: MTLPrimitiveType, vertexCount: Int, instanceCount: Int = 1) {
+        drawCalls += 1
+        switch type {
+        case .triangle:
+            triangles += vertexCount / 3 * instanceCount
+        case .triangleStrip:
+            triangles += max(0, vertexCount - 2) * instanceCount
+        case .line:
+            lines += vertexCount / 2 * instanceCount
+        case .lineStrip:
+            lines += max(0, vertexCount - 1) * instanceCount
+        default:
+            break
+        }
+    }
+}
+
+/// Collects per-frame performance numbers for the performance HUD
+/// Samples are accumulated every frame and published as averages twice per second,
+/// so the SwiftUI overlay doesn't re-render at the frame rate
+@Observable
+final class PerformanceStats: @unchecked Sendable {
+    /// Published snapshot shown by the HUD
+    struct Snapshot {
+        var fps: Double = 0
+        var frameTime: Double = 0       // ms between frames
+        var encodeTime: Double = 0      // ms CPU time spent encoding the frame
+        var gpuTime: Double = 0         // ms GPU execution time
+        var pickingTime: Double = 0     // ms for the last hover ray cast
+        var clippingTime: Double = 0    // ms for the last slicing clip
+        var drawCalls: Int = 0
+        var trianglesDrawn: Int = 0
+        var linesDrawn: Int = 0
+        var residentMemory: UInt64 = 0  // bytes
+        var gpuMemory: Int = 0          // bytes allocated by the Metal device
+    }
+
+    /// Whether the HUD is visible (stats are only published while visible)
+    var isVisible: Bool = false
+
+    /// Latest published values
+    private(set) var snapshot = Snapshot()
+
+    /// Publish interval in seconds
+    private static let publishInterval: CFAbsoluteTime = 0.5
+
+    // Accumulators (not observed to avoid per-frame view updates)
+    @ObservationIgnored private var lastFrameStart: CFAbsoluteTime = 0
+    @ObservationIgnored private var lastPublish: CFAbsoluteTime = 0
+    @ObservationIgnored private var frameCount = 0
+    @ObservationIgnored private var frameTimeSum: Double = 0
+    @ObservationIgnored private var encodeTimeSum: Double = 0
+    @ObservationIgnored private var gpuTimeSum: Double = 0
+    @ObservationIgnored private var gpuSampleCount = 0
+    @ObservationIgnored private var lastCounters = FrameCounters()
+    @ObservationIgnored private var lastPickingTime: Double = 0
+    @ObservationIgnored private var lastClippingTime: Double = 0
+
+    /// Record a rendered frame
+    /// - Parameters:
+    ///   - start: Time the frame started encoding
+    ///   - encodeTime: CPU encoding time in seconds
+    ///   - counters: Draw call / primitive counts for the frame
+    ///   - device: Metal device (for GPU memory usage)
+    func recordFrame(start: CFAbsoluteTime, encodeTime: CFAbsoluteTime, counters: FrameCounters, device: MTLDevice) {
+        if lastFrameStart > 0 {
+            frameTimeSum += (start - lastFrameStart) * 1000
+            frameCount += 1
+        }
+        lastFrameStart = start
+        encodeTimeSum += encodeTime * 1000
+        lastCounters = counters
+
+        guard isVisible, start - lastPublish >= Self.publishInterval, frameCount > 0 else { return }
+        lastPublish = start
+
+        let averageFrameTime = frameTimeSum / Double(frameCount)
+        snapshot = Snapshot(
+            fps: averageFrameTime > 0 ? 1000 / averageFrameTime : 0,
+            frameTime: averageFrameTime,
+            encodeTime: encodeTimeSum / Double(frameCount),
+            gpuTime: gpuSampleCount > 0 ? gpuTimeSum / Double(gpuSampleCount) : 0,
+            pickingTime: lastPickingTime,
+            clippingTime: lastClippingTime,
+            drawCalls: lastCounters.drawCalls,
+            trianglesDrawn: lastCounters.triangles,
+            linesDrawn: lastCounters.lines,
+            residentMemory: Self.residentMemory(),
+            gpuMemory: device.currentAllocatedSize
+        )
+
+        frameCount = 0
+        frameTimeSum = 0
+        encodeTimeSum = 0
+        gpuTimeSum = 0
+        gpuSampleCount = 0
+    }
+
+    /// Record GPU execution time of a completed command buffer (seconds)
+    func recordGPUTime(_ seconds: CFTimeInterval) {
+        guard seconds > 0 else { return }
+        gpuTimeSum += seconds * 1000
+        gpuSampleCount += 1
+    }
+
+    /// Record the duration of a hover/pick ray cast (seconds)
+    func recordPicking(_ seconds: CFAbsoluteTime) {
+        lastPickingTime = seconds * 1000
+    }
+
+    /// Record the duration of a slicing clip pass (seconds)
+    func recordClipping(_ seconds: CFAbsoluteTime) {
+        lastClippingTime = seconds * 1000
+    }
+
+    /// Physical memory footprint of the process in bytes
+    static func residentMemory() -> UInt64 {
+        var info = task_vm_info_data_t()
+        var count = mach_msg_type_number_t(MemoryLayout<task_vm_info_data_t>.size / MemoryLayout<integer_t>.size)
+        let result = withUnsafeMutablePointer(to: &info) {
+            $0.withMemoryRebound(to: integer_t.self, capacity: Int(count)) {
+                task_info(mach_task_self_, task_flavor_t(TASK_VM_INFO), $0, &count)
+            }
+        }
+        return result == KERN_SUCCESS ? info.phys_footprint : 0
+    }
+
+    /// Format a byte count for display
+    static func formatBytes(_ bytes: UInt64) -> String {
+        ByteCountFormatter.string(fromByteCount: Int64(bytes), countStyle: .memory)
+    }
+}
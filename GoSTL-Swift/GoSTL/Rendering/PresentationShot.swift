@@ -0,0 +1,356 @@
+import CoreGraphics
+import Foundation
+import simd
+
+/// Options of product shots rendered from the current view (File > Export View Image...)
+struct PresentationShotOptions: Codable, Equatable, Sendable {
+    enum Background: String, Codable, CaseIterable, Identifiable, Sendable {
+        /// The background color of the 3D view
+        case viewer
+        case transparent
+        /// Light grey studio sweep
+        case studio
+        case darkStudio
+        /// Blue sky fading into a light horizon over grey ground
+        case sky
+
+        var id: String { rawValue }
+
+        var title: String {
+            switch self {
+            case .viewer: return "View Background"
+            case .transparent: return "Transparent"
+            case .studio: return "Studio"
+            case .darkStudio: return "Dark Studio"
+            case .sky: return "Sky"
+            }
+        }
+
+        /// Top, horizon and bottom colors of the gradient (nil for flat or transparent backgrounds)
+        var gradient: (top: SIMD3<Float>, horizon: SIMD3<Float>, bottom: SIMD3<Float>)? {
+            switch self {
+            case .viewer, .transparent: return nil
+            case .studio: return (SIMD3(0.96, 0.96, 0.97), SIMD3(0.88, 0.89, 0.91), SIMD3(0.70, 0.72, 0.75))
+            case .darkStudio: return (SIMD3(0.20, 0.21, 0.24), SIMD3(0.12, 0.13, 0.15), SIMD3(0.05, 0.05, 0.06))
+            case .sky: return (SIMD3(0.42, 0.62, 0.88), SIMD3(0.92, 0.94, 0.96), SIMD3(0.58, 0.57, 0.55))
+            }
+        }
+    }
+
+    /// Offered image sizes (square, pixels)
+    static let sizes = [1024, 2048]
+
+    var size = 2048
+    var background: Background = .studio
+    /// Soft shadow of the model on a ground plane below it
+    var groundShadow = true
+    /// Blur what is in front of or behind the rotation center
+    var depthOfField = false
+    var ambientOcclusion = true
+}
+
+/// Post-processing of an offscreen render into a product shot: background, ground shadow and depth of field
+///
+/// Runs on the CPU over the rendered color and depth, so the viewer pipeline stays untouched.
+enum PresentationShot {
+    /// What the compositor needs to know about the camera and the model (display coordinates)
+    struct Scene: Sendable {
+        /// Projection × view matrix of the render (display space, X and Y equal to the model's)
+        let viewProjection: simd_float4x4
+        let cameraPosition: SIMD3<Float>
+        /// Height of the ground plane (the model's lowest point)
+        let groundZ: Float
+        /// Distance in focus (the rotation center)
+        let focusDistance: Float
+        /// Distance from the focus at which the blur is strongest
+        let focusRange: Float
+        /// Background color of the 3D view (for `.viewer`)
+        let viewerBackground: SIMD3<Float>
+    }
+
+    /// Darkness of the ground shadow right under the model
+    static let shadowStrength: Float = 0.55
+    /// Strongest depth of field blur as a share of the image size
+    static let maxBlur = 0.006
+    /// Darkening of the image corners by the studio backgrounds
+    static let vignette: Float = 0.12
+
+    /// Render the model from a camera and compose the shot
+    static func render(
+        model: STLModel,
+        camera: Camera,
+        options: PresentationShotOptions,
+        material: Material,
+        viewerBackground: SIMD3<Float>,
+        occlusion: [Float]?,
+        renderer: OffscreenRenderer
+    ) throws -> CGImage {
+        let frame = try renderer.renderFrame(
+            model: model,
+            camera: camera,
+            size: options.size,
+            material: material,
+            occlusion: options.ambientOcclusion ? occlusion : nil,
+            includeDepth: true
+        )
+
+        let bounds = model.boundingBox()
+        let scene = Scene(
+            viewProjection: camera.projectionMatrix(aspect: 1) * camera.viewMatrix(),
+            cameraPosition: camera.position,
+            groundZ: camera.toDisplay(SIMD3(0, 0, Float(bounds.min.z))).z,
+            focusDistance: Float(camera.distance),
+            focusRange: Float(bounds.diagonal / 2),
+            viewerBackground: viewerBackground
+        )
+        let shadow = options.groundShadow ? GroundShadow(triangles: model.triangles) : nil
+        return try OffscreenRenderer.image(from: compose(frame, scene: scene, options: options, shadow: shadow))
+    }
+
+    /// Put the render in front of the background and shadow, then apply the depth of field
+    static func compose(_ frame: OffscreenFrame, scene: Scene, options: PresentationShotOptions, shadow: GroundShadow?) -> OffscreenFrame {
+        let size = frame.size
+        let pixelCount = size * size
+        let inverse = scene.viewProjection.inverse
+        let cameraBelowGround = scene.cameraPosition.z <= scene.groundZ
+
+        // Premultiplied RGBA, one plane per channel (blurred separately for the depth of field)
+        var planes = [[Float]](repeating: [Float](repeating: 0, count: pixelCount), count: 4)
+        var distances = [Float](repeating: .infinity, count: pixelCount)
+
+        for y in 0..<size {
+            for x in 0..<size {
+                let index = y * size + x
+                let ndc = SIMD2<Float>((Float(x) + 0.5) / Float(size) * 2 - 1, 1 - (Float(y) + 0.5) / Float(size) * 2)
+                let alpha = Float(frame.bgra[index * 4 + 3]) / 255
+                let color = SIMD3<Float>(
+                    Float(frame.bgra[index * 4 + 2]),
+                    Float(frame.bgra[index * 4 + 1]),
+                    Float(frame.bgra[index * 4])
+                ) / 255
+
+                // Model pixels: distance from the depth buffer
+                if let depth = frame.depth?[index], depth < 1 {
+                    distances[index] = simd_distance(unproject(ndc, depth: depth, inverse: inverse), scene.cameraPosition)
+                }
+
+                // Ground under the pixel, for the shadow and the distance of background pixels
+                var shade: Float = 0
+                if !cameraBelowGround {
+                    let direction = simd_normalize(unproject(ndc, depth: 0.9, inverse: inverse) - scene.cameraPosition)
+                    if direction.z < 0 {
+                        let t = (scene.groundZ - scene.cameraPosition.z) / direction.z
+                        let hit = scene.cameraPosition + direction * t
+                        if distances[index] == .infinity {
+                            distances[index] = t
+                        }
+                        shade = (shadow?.value(at: SIMD2(hit.x, hit.y)) ?? 0) * shadowStrength
+                    }
+                }
+
+                // Background layer (premultiplied) under the render
+                let layer: SIMD4<Float>
+                if let background = backgroundColor(options.background, x: Float(x) / Float(size - 1), y: Float(y) / Float(size - 1), viewer: scene.viewerBackground) {
+                    let shaded = background * (1 - shade)
+                    layer = SIMD4(shaded.x, shaded.y, shaded.z, 1)
+                } else {
+                    layer = SIMD4(0, 0, 0, shade)
+                }
+                let composed = SIMD4(color.x, color.y, color.z, alpha) + layer * (1 - alpha)
+                for channel in 0..<4 {
+                    planes[channel][index] = composed[channel]
+                }
+            }
+        }
+
+        if options.depthOfField {
+            planes = depthOfField(planes, distances: distances, size: size, scene: scene)
+        }
+
+        var output = frame
+        for index in 0..<pixelCount {
+            output.bgra[index * 4] = byte(planes[2][index])
+            output.bgra[index * 4 + 1] = byte(planes[1][index])
+            output.bgra[index * 4 + 2] = byte(planes[0][index])
+            output.bgra[index * 4 + 3] = byte(planes[3][index])
+        }
+        return output
+    }
+
+    /// Background color at a relative image position (x right, y down, 0...1), nil if transparent
+    static func backgroundColor(_ background: PresentationShotOptions.Background, x: Float, y: Float, viewer: SIMD3<Float>) -> SIMD3<Float>? {
+        switch background {
+        case .transparent:
+            return nil
+        case .viewer:
+            return viewer
+        case .studio, .darkStudio, .sky:
+            guard let gradient = background.gradient else { return nil }
+            // Horizon a little below the middle, like a camera looking slightly down
+            let horizon: Float = 0.55
+            let color = y < horizon
+                ? simd_mix(gradient.top, gradient.horizon, SIMD3(repeating: y / horizon))
+                : simd_mix(gradient.horizon, gradient.bottom, SIMD3(repeating: (y - horizon) / (1 - horizon)))
+            let offset = SIMD2(x, y) - 0.5
+            return color * (1 - vignette * simd_length_squared(offset) * 2)
+        }
+    }
+
+    /// Blend between blurred copies of the image by each pixel's distance from the focus
+    private static func depthOfField(_ planes: [[Float]], distances: [Float], size: Int, scene: Scene) -> [[Float]] {
+        let maxRadius = Double(size) * maxBlur
+        guard maxRadius >= 1, scene.focusRange > 0 else { return planes }
+
+        // Sharp, one third, two thirds and full blur
+        let levels = (0...3).map { level -> [[Float]] in
+            let radius = Int((maxRadius * Double(level) / 3).rounded())
+            guard radius > 0 else { return planes }
+            return planes.map { plane in
+                var blurred = plane
+                // Two box passes approximate a Gaussian
+                boxBlur(&blurred, width: size, height: size, radius: max(1, radius / 2))
+                boxBlur(&blurred, width: size, height: size, radius: max(1, radius / 2))
+                return blurred
+            }
+        }
+
+        var result = planes
+        for index in 0..<distances.count {
+            let distance = distances[index].isFinite ? distances[index] : scene.focusDistance + scene.focusRange
+            let blur = min(abs(distance - scene.focusDistance) / scene.focusRange, 1) * 3
+            let lower = min(Int(blur), 2)
+            let fraction = blur - Float(lower)
+            for channel in 0..<4 {
+                result[channel][index] = levels[lower][channel][index] * (1 - fraction) + levels[lower + 1][channel][index] * fraction
+            }
+        }
+        return result
+    }
+
+    /// Separable box blur with clamped edges
+    static func boxBlur(_ values: inout [Float], width: Int, height: Int, radius: Int) {
+        guard radius > 0, width > 0, height > 0 else { return }
+        let window = Float(2 * radius + 1)
+        var line = [Float](repeating: 0, count: max(width, height))
+
+        func blurLine(count: Int, at position: (Int) -> Int) {
+            for i in 0..<count {
+                line[i] = values[position(i)]
+            }
+            var sum: Float = 0
+            for i in -radius...radius {
+                sum += line[min(max(i, 0), count - 1)]
+            }
+            for i in 0..<count {
+                values[position(i)] = sum / window
+                sum += line[min(i + radius + 1, count - 1)] - line[max(i - radius, 0)]
+            }
+        }
+
+        for y in 0..<height {
+            blurLine(count: width) { y * width + $0 }
+        }
+        for x in 0..<width {
+            blurLine(count: height) { $0 * width + x }
+        }
+    }
+
+    private static func unproject(_ ndc: SIMD2<Float>, depth: Float, inverse: simd_float4x4) -> SIMD3<Float> {
+        let point = inverse * SIMD4(ndc.x, ndc.y, depth, 1)
+        return SIMD3(point.x, point.y, point.z) / point.w
+    }
+
+    private static func byte(_ value: Float) -> UInt8 {
+        UInt8(min(max(value, 0), 1) * 255 + 0.5)
+    }
+}
+
+/// Soft footprint of a model on the ground plane below it, darker where the model is close to the ground
+struct GroundShadow: Sendable {
+    /// Corner of the grid (x, y)
+    let origin: SIMD2<Float>
+    let cellSize: Float
+    let resolution: Int
+    /// Darkness per cell from 0 to 1, row by row
+    private(set) var values: [Float]
+
+    /// Blur radius as a share of the footprint
+    static let softness: Float = 0.04
+    /// Parts higher above the ground than this share of the model size cast no shadow
+    static let falloff: Float = 0.25
+
+    /// - Returns: nil for an empty mesh or a footprint of zero size
+    init?(triangles: [Triangle], resolution: Int = 256) {
+        guard !triangles.isEmpty else { return nil }
+        let bounds = STLModel(triangles: triangles).boundingBox()
+        let footprint = Float(max(bounds.max.x - bounds.min.x, bounds.max.y - bounds.min.y))
+        guard footprint > 0 else { return nil }
+
+        let margin = footprint * 0.15
+        let extent = footprint + 2 * margin
+        let center = SIMD2(Float(bounds.center.x), Float(bounds.center.y))
+        origin = center - extent / 2
+        cellSize = extent / Float(resolution)
+        self.resolution = resolution
+        values = [Float](repeating: 0, count: resolution * resolution)
+
+        let groundZ = Float(bounds.min.z)
+        let falloffHeight = Self.falloff * max(footprint, Float(bounds.max.z - bounds.min.z))
+        for triangle in triangles {
+            let a = SIMD2(Float(triangle.v1.x), Float(triangle.v1.y))
+            let b = SIMD2(Float(triangle.v2.x), Float(triangle.v2.y))
+            let c = SIMD2(Float(triangle.v3.x), Float(triangle.v3.y))
+            let height = Float(min(triangle.v1.z, triangle.v2.z, triangle.v3.z)) - groundZ
+            let weight = 1 - height / falloffHeight
+            guard weight > 0 else { continue }
+            rasterize(a, b, c, weight: weight)
+        }
+
+        let radius = max(1, Int((Self.softness * footprint / cellSize).rounded()))
+        PresentationShot.boxBlur(&values, width: resolution, height: resolution, radius: radius)
+        PresentationShot.boxBlur(&values, width: resolution, height: resolution, radius: radius)
+    }
+
+    /// Darkness at a ground position (bilinear, 0 outside the grid)
+    func value(at point: SIMD2<Float>) -> Float {
+        let cell = (point - origin) / cellSize - 0.5
+        let x0 = Int(floor(cell.x)), y0 = Int(floor(cell.y))
+        let fx = cell.x - Float(x0), fy = cell.y - Float(y0)
+
+        func sample(_ x: Int, _ y: Int) -> Float {
+            guard (0..<resolution).contains(x), (0..<resolution).contains(y) else { return 0 }
+            return values[y * resolution + x]
+        }
+        let top = sample(x0, y0) * (1 - fx) + sample(x0 + 1, y0) * fx
+        let bottom = sample(x0, y0 + 1) * (1 - fx) + sample(x0 + 1, y0 + 1) * fx
+        return top * (1 - fy) + bottom * fy
+    }
+
+    /// Mark the cells whose centers lie in a triangle seen from above
+    private mutating func rasterize(_ a: SIMD2<Float>, _ b: SIMD2<Float>, _ c: SIMD2<Float>, weight: Float) {
+        let area = cross(b - a, c - a)
+        guard abs(area) > 1e-12 else { return }
+
+        let low = (simd_min(a, simd_min(b, c)) - origin) / cellSize
+        let high = (simd_max(a, simd_max(b, c)) - origin) / cellSize
+        let xLow = max(0, Int(low.x)), xHigh = min(resolution - 1, Int(high.x))
+        let yLow = max(0, Int(low.y)), yHigh = min(resolution - 1, Int(high.y))
+        guard xLow <= xHigh, yLow <= yHigh else { return }
+
+        for y in yLow...yHigh {
+            for x in xLow...xHigh {
+                let p = origin + (SIMD2(Float(x), Float(y)) + 0.5) * cellSize
+                let u = cross(b - a, p - a) / area
+                let v = cross(c - b, p - b) / area
+                let w = cross(a - c, p - c) / area
+                if u >= 0 && v >= 0 && w >= 0 {
+                    values[y * resolution + x] = max(values[y * resolution + x], weight)
+                }
+            }
+        }
+    }
+
+    private func cross(_ a: SIMD2<Float>, _ b: SIMD2<Float>) -> Float {
+        a.x * b.y - a.y * b.x
+    }
+}
@@ -0,0 +1,124 @@
+import CoreGraphics
+import Foundation
+
+/// Golden-image regression test of the mesh renderer (`gostl selftest-render` and the test suite)
+///
+/// Renders built-in reference models from fixed views and compares them with stored golden PNGs
+/// using `PerceptualDiff`, so shader and clipping changes can be checked for visible differences.
+/// Goldens depend on the GPU; record them on the machine that runs the comparison.
+struct RenderSelfTest {
+    struct Case {
+        var name: String
+        var model: STLModel
+        var preset: CameraPreset
+        var material: Material = .pla
+        /// Cut away the half of the model facing the camera
+        var cutaway = false
+    }
+
+    enum Outcome {
+        case passed(PerceptualDiff.Result)
+        case failed(PerceptualDiff.Result)
+        /// No golden yet (run with update to record one)
+        case missing
+        case recorded
+
+        var isFailure: Bool {
+            switch self {
+            case .failed, .missing: return true
+            case .passed, .recorded: return false
+            }
+        }
+    }
+
+    /// Image size of renders and goldens
+    static let defaultSize = 256
+
+    /// Reference models covering flat and smooth shading, materials, presets and the cutaway
+    static var cases: [Case] {
+        [
+            Case(name: "cube-home", model: ModelGenerator.cube(size: Vector3(20, 20, 20)), preset: .home),
+            Case(name: "cube-top", model: ModelGenerator.cube(size: Vector3(20, 20, 20)), preset: .top),
+            Case(name: "steps-front", model: steps(), preset: .front),
+            Case(name: "steps-home-petg", model: steps(), preset: .home, material: .petg),
+            Case(name: "sphere-home", model: ModelGenerator.sphere(radius: 10, segments: 32), preset: .home),
+            Case(name: "sphere-aluminum", model: ModelGenerator.sphere(radius: 10, segments: 32), preset: .right, material: .aluminum),
+            Case(name: "sphere-cutaway", model: ModelGenerator.sphere(radius: 10, segments: 32), preset: .home, cutaway: true)
+        ]
+    }
+
+    var goldens: URL
+    var size = RenderSelfTest.defaultSize
+    var threshold = PerceptualDiff.defaultThreshold
+    /// Share of differing pixels (percent) that still passes
+    var tolerance = PerceptualDiff.defaultTolerance
+
+    static func cases(named names: [String]) -> [Case] {
+        names.isEmpty ? cases : cases.filter { names.contains($0.name) }
+    }
+
+    func goldenURL(for testCase: Case) -> URL {
+        goldens.appendingPathComponent("\(testCase.name).png")
+    }
+
+    /// Render a case (background white, so goldens look like the page they are viewed on)
+    func render(_ testCase: Case, with renderer: OffscreenRenderer) throws -> CGImage {
+        let bounds = testCase.model.boundingBox()
+        let centered = testCase.model.translated(by: Vector3(0, 0, 0) - bounds.center)
+
+        let camera = Camera()
+        camera.setPreset(testCase.preset)
+        camera.frameBoundingBox(centered.boundingBox())
+        camera.distance *= 0.8
+        camera.fitDepthRange(to: centered.boundingBox())
+        if testCase.cutaway {
+            camera.cutawayDepth = Float(camera.distance)
+        }
+        return try renderer.render(model: centered, camera: camera, size: size, material: testCase.material, background: SIMD4(1, 1, 1, 1))
+    }
+
+    /// Render and compare a case; with `update` the render replaces the golden
+    ///
+    /// Failed cases write `<name>.png` (the render) and `<name>-diff.png` into `artifacts`.
+    func run(_ testCase: Case, with renderer: OffscreenRenderer, update: Bool, artifacts: URL?) throws -> Outcome {
+        let image = try render(testCase, with: renderer)
+        let golden = goldenURL(for: testCase)
+
+        if update {
+            try FileManager.default.createDirectory(at: goldens, withIntermediateDirectories: true)
+            try OffscreenRenderer.pngData(image).write(to: golden, options: .atomic)
+            return .recorded
+        }
+        guard FileManager.default.fileExists(atPath: golden.path) else {
+            try writeArtifacts(image: image, diff: nil, for: testCase, to: artifacts)
+            return .missing
+        }
+
+        let result = try PerceptualDiff.compare(PerceptualDiff.loadImage(at: golden), image, threshold: threshold)
+        if result.passes(tolerance: tolerance) {
+            return .passed(result)
+        }
+        try writeArtifacts(image: image, diff: result.diffImage, for: testCase, to: artifacts)
+        return .failed(result)
+    }
+
+    private func writeArtifacts(image: CGImage, diff: CGImage?, for testCase: Case, to directory: URL?) throws {
+        guard let directory else { return }
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        try OffscreenRenderer.pngData(image).write(to: directory.appendingPathComponent("\(testCase.name).png"), options: .atomic)
+        if let diff {
+            try OffscreenRenderer.pngData(diff).write(to: directory.appendingPathComponent("\(testCase.name)-diff.png"), options: .atomic)
+        }
+    }
+
+    // MARK: - Reference Models
+
+    /// Three stacked blocks, for overlapping edges and faces at different depths
+    static func steps() -> STLModel {
+        STLModel(triangles: [
+            ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(30, 20, 5)),
+            ModelGenerator.box(from: Vector3(10, 0, 5), to: Vector3(30, 20, 10)),
+            ModelGenerator.box(from: Vector3(20, 0, 10), to: Vector3(30, 20, 15))
+        ].flatMap { $0 })
+    }
+}
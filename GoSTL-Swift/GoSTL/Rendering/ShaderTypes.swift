@@ -11,6 +11,7 @@ struct Uniforms {
     var cameraPosition: simd_float3
     var viewportHeight: Float
     var _padding: SIMD2<Float> = .zero // Align to 16 bytes
+    var clipPlane: SIMD4<Float> = .zero // Cutaway plane (xyz normal, w offset), zero = no clipping
 }
 
 struct MaterialProperties {
@@ -19,7 +20,7 @@ struct MaterialProperties {
     var metalness: Float
     var specularIntensity: Float
     var showFaceOrientation: Float = 0.0  // 1.0 = show front/back face colors
-    var _padding1: Float = 0.0
+    var outlineFill: Float = 0.0  // 1.0 = flat white fill (outline mode)
     var _padding2: SIMD4<Float> = .zero // Extra padding to match Metal's 48-byte layout
 }
 
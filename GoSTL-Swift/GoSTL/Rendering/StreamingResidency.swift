@@ -0,0 +1,197 @@
+import Foundation
+import Metal
+import simd
+
+/// Full-resolution chunks of a streamed model on the GPU, paged in around the camera within a memory budget
+///
+/// Every frame the chunks in view are ranked by their distance to the camera, and the nearest ones
+/// that fit into the budget are read from the chunk file in the background. A resident chunk is
+/// drawn instead of its part of the proxy; chunks out of view are not drawn at all. Chunks that are
+/// no longer wanted stay resident until their memory is needed, least recently drawn first.
+///
+/// Used from the render loop on the main thread; loads hand their meshes back through the upload queue.
+final class StreamingResidency: @unchecked Sendable {
+    /// One draw call: a resident chunk or a chunk's range of the proxy mesh
+    struct Draw {
+        let buffer: MTLBuffer
+        let vertexStart: Int
+        let vertexCount: Int
+    }
+
+    /// What is shown at full resolution, for the status banner
+    struct Status: Equatable {
+        let visibleChunks: Int
+        let residentChunks: Int
+        /// Resident chunks that are in view
+        let shownChunks: Int
+        let residentBytes: Int
+    }
+
+    typealias Box = (min: SIMD3<Float>, max: SIMD3<Float>)
+
+    /// GPU memory of one full-resolution triangle
+    static let bytesPerTriangle = 3 * MemoryLayout<VertexIn>.stride
+    /// Chunks read at the same time (more would compete with the frame for memory bandwidth)
+    static let maxLoads = 2
+
+    let model: StreamingModel
+    /// Bytes of GPU memory for full-resolution chunks
+    var budget: Int
+
+    /// File to render coordinates (the proxy's unit scale and recentering offset)
+    private let scale: Double
+    private let offset: Vector3
+    /// Chunk bounds in render coordinates
+    private let bounds: [Box]
+    private let triangleCounts: [Int]
+
+    private var resident: [Int: (mesh: MeshData, lastDrawn: Int)] = [:]
+    private var loading: Set<Int> = []
+    /// Chunks whose file range could not be read (not retried every frame)
+    private var failed: Set<Int> = []
+    private var visible: [Int] = []
+    private var frame = 0
+    private let queue = DispatchQueue(label: "GoSTL.StreamingResidency", qos: .userInitiated, attributes: .concurrent)
+
+    init(model: StreamingModel, scale: Double, offset: Vector3, budget: Int) {
+        self.model = model
+        self.scale = scale
+        self.offset = offset
+        self.budget = budget
+        bounds = model.chunks.map { chunk in
+            ((chunk.bounds.min * scale - offset).float3, (chunk.bounds.max * scale - offset).float3)
+        }
+        triangleCounts = model.chunks.map(\.triangleCount)
+    }
+
+    var status: Status {
+        Status(
+            visibleChunks: visible.count,
+            residentChunks: resident.count,
+            shownChunks: visible.filter { resident[$0] != nil }.count,
+            residentBytes: resident.keys.reduce(0) { $0 + bytes(of: $1) }
+        )
+    }
+
+    // MARK: - Planning
+
+    /// Chunks in view and, of those, the ones to show at full resolution: nearest first, as many as fit into the budget
+    static func plan(bounds: [Box], triangleCounts: [Int], frustum: Frustum, eye: SIMD3<Float>, budget: Int) -> (visible: [Int], wanted: [Int]) {
+        let visible = bounds.indices.filter { frustum.intersects(min: bounds[$0].min, max: bounds[$0].max) }
+        let nearestFirst = visible
+            .map { (index: $0, distance: distance(from: eye, to: bounds[$0])) }
+            .sorted { $0.distance < $1.distance }
+
+        var wanted: [Int] = []
+        var used = 0
+        for candidate in nearestFirst {
+            let bytes = triangleCounts[candidate.index] * bytesPerTriangle
+            guard used + bytes <= budget else { break }
+            used += bytes
+            wanted.append(candidate.index)
+        }
+        return (visible, wanted)
+    }
+
+    /// Distance from a point to a box (0 inside)
+    static func distance(from point: SIMD3<Float>, to box: Box) -> Float {
+        simd_length(simd_max(simd_max(box.min - point, point - box.max), .zero))
+    }
+
+    // MARK: - Paging
+
+    /// Per-frame: find the chunks in view, evict what the wanted ones need room for and start loading them
+    func update(camera: Camera, aspect: Float, device: MTLDevice, uploads: GPUUploadQueue, generation: Int) {
+        frame += 1
+        let matrix = camera.projectionMatrix(aspect: aspect) * camera.viewMatrix() * camera.displayMatrix()
+        let plan = Self.plan(
+            bounds: bounds,
+            triangleCounts: triangleCounts,
+            frustum: Frustum(viewProjection: matrix),
+            eye: camera.toModel(camera.position),
+            budget: budget
+        )
+        visible = plan.visible
+
+        let wanted = plan.wanted.filter { !failed.contains($0) }
+        let missing = wanted.filter { resident[$0] == nil && !loading.contains($0) }
+        guard !missing.isEmpty else { return }
+
+        // Make room, least recently drawn first
+        let wantedSet = Set(wanted)
+        var used = (Array(resident.keys) + Array(loading)).reduce(0) { $0 + bytes(of: $1) }
+        let needed = missing.reduce(0) { $0 + bytes(of: $1) }
+        let evictable = resident.filter { !wantedSet.contains($0.key) }.sorted { $0.value.lastDrawn < $1.value.lastDrawn }
+        for (index, _) in evictable where used + needed > budget {
+            resident[index] = nil
+            used -= bytes(of: index)
+        }
+
+        for index in missing.prefix(max(0, Self.maxLoads - loading.count)) where used + bytes(of: index) <= budget {
+            used += bytes(of: index)
+            load(index, device: device, uploads: uploads, generation: generation)
+        }
+    }
+
+    /// Draw calls for this frame: resident chunks in view, and the proxy for the other chunks in view
+    func draws(proxy: MeshData) -> [Draw] {
+        var draws: [Draw] = []
+        for index in visible {
+            if let entry = resident[index] {
+                resident[index] = (entry.mesh, frame)
+                draws.append(Draw(buffer: entry.mesh.vertexBuffer, vertexStart: 0, vertexCount: entry.mesh.vertexCount))
+                continue
+            }
+            let range = model.chunks[index].proxyRange
+            guard !range.isEmpty, range.upperBound * 3 <= proxy.vertexCount else { continue }
+            draws.append(Draw(buffer: proxy.vertexBuffer, vertexStart: range.lowerBound * 3, vertexCount: range.count * 3))
+        }
+        return draws
+    }
+
+    private func bytes(of index: Int) -> Int {
+        triangleCounts[index] * Self.bytesPerTriangle
+    }
+
+    /// Read a chunk and build its vertex buffer in the background, then install it at the start of a frame
+    private func load(_ index: Int, device: MTLDevice, uploads: GPUUploadQueue, generation: Int) {
+        loading.insert(index)
+        let model = self.model
+        let scale = self.scale
+        let offset = self.offset
+
+        queue.async { [weak self] in
+            var loaded = LoadedChunk(mesh: nil)
+            do {
+                var triangles = try model.triangles(of: model.chunks[index])
+                if scale != 1 || offset != .zero {
+                    for i in triangles.indices {
+                        triangles[i].v1 = triangles[i].v1 * scale - offset
+                        triangles[i].v2 = triangles[i].v2 * scale - offset
+                        triangles[i].v3 = triangles[i].v3 * scale - offset
+                    }
+                }
+                loaded = LoadedChunk(mesh: try MeshData(device: device, model: STLModel(triangles: triangles)))
+            } catch {
+                print("ERROR: Failed to load chunk \(index) of \(model.sourceURL.lastPathComponent): \(error)")
+            }
+            uploads.enqueue(generation: generation) {
+                self?.install(index, loaded)
+            }
+        }
+    }
+
+    private func install(_ index: Int, _ loaded: LoadedChunk) {
+        loading.remove(index)
+        guard let mesh = loaded.mesh else {
+            failed.insert(index)
+            return
+        }
+        resident[index] = (mesh, frame)
+    }
+}
+
+/// Vertex buffer of a chunk handed from the loading queue to the render loop
+private struct LoadedChunk: @unchecked Sendable {
+    let mesh: MeshData?
+}
@@ -0,0 +1,72 @@
+import simd
+
+/// Colors of the mesh vertices, lit in the shader like the model's own colors
+///
+/// Analysis visualizations (draft angles, height, deviation from another model, curvature) and
+/// imported per-vertex colors reach the GPU through this one path: they provide colors for the
+/// model's vertices instead of a recolored copy of the model. Holds three colors per triangle in
+/// triangle order; vertices with the `material` marker are drawn in the material color.
+struct VertexColors: Sendable, Equatable {
+    private(set) var colors: [SIMD4<Float>]
+
+    /// Marker for vertices without a color of their own (zero alpha: the shader uses the material color)
+    static let material = SIMD4<Float>(1, 1, 1, 0)
+
+    /// Three colors per triangle
+    init(perVertex colors: [SIMD4<Float>]) {
+        precondition(colors.count % 3 == 0, "Vertex colors come in threes, one per triangle corner")
+        self.colors = colors
+    }
+
+    /// One color per triangle (nil: the material color)
+    init(perTriangle colors: [TriangleColor?]) {
+        var vertexColors: [SIMD4<Float>] = []
+        vertexColors.reserveCapacity(colors.count * 3)
+        for color in colors {
+            let vertexColor = color.map(Self.opaque) ?? Self.material
+            vertexColors.append(contentsOf: [vertexColor, vertexColor, vertexColor])
+        }
+        self.colors = vertexColors
+    }
+
+    var triangleCount: Int {
+        colors.count / 3
+    }
+
+    /// Colors of the three corners of a triangle
+    func corners(ofTriangle index: Int) -> (SIMD4<Float>, SIMD4<Float>, SIMD4<Float>) {
+        (colors[index * 3], colors[index * 3 + 1], colors[index * 3 + 2])
+    }
+
+    /// Vertex color for a triangle's own color
+    ///
+    /// White and missing colors use the material color, so single-color models and the first
+    /// extruder of a multi-material 3MF follow the material selection.
+    static func vertexColor(of color: TriangleColor?) -> SIMD4<Float> {
+        guard let color, color.r < 0.99 || color.g < 0.99 || color.b < 0.99 else { return material }
+        return opaque(color)
+    }
+
+    private static func opaque(_ color: TriangleColor) -> SIMD4<Float> {
+        SIMD4(color.r, color.g, color.b, 1)
+    }
+
+    /// The triangles with each face colored by the mean of its vertex colors
+    ///
+    /// For geometry that is clipped before it is uploaded (slicing, layer preview): the clipped
+    /// pieces keep their triangle's color, so smooth gradients become per face while cutting.
+    func faceColored(_ triangles: [Triangle]) -> [Triangle] {
+        guard triangles.count == triangleCount else { return triangles }
+        var colored = triangles
+        for index in colored.indices {
+            let (a, b, c) = corners(ofTriangle: index)
+            if a.w < 0.5 && b.w < 0.5 && c.w < 0.5 {
+                colored[index].color = nil
+                continue
+            }
+            let mean = (a + b + c) / 3
+            colored[index].color = TriangleColor(mean.x, mean.y, mean.z)
+        }
+        return colored
+    }
+}
@@ -17,6 +17,7 @@ struct VertexOut {
     float4 color;
     float3 worldPosition;
     float2 texCoord;
+    float clipDistance;  // Signed distance to the cutaway plane (negative = cut away)
 };
 
 struct Uniforms {
@@ -27,6 +28,7 @@ struct Uniforms {
     float3 cameraPosition;
     float viewportHeight;
     float2 _padding; // Align to 16 bytes
+    float4 clipPlane; // Cutaway plane (xyz normal, w offset), zero = no clipping
 };
 
 struct MaterialProperties {
@@ -35,7 +37,7 @@ struct MaterialProperties {
     float metalness;
     float specularIntensity;
     float showFaceOrientation;  // 1.0 = show front/back face colors
-    float _padding1;
+    float outlineFill;  // 1.0 = flat white fill (outline mode)
     float4 _padding2; // Extra padding for proper alignment (total 48 bytes)
 };
 
@@ -80,7 +82,9 @@ vertex VertexOut meshVertexShader(
     out.normal = uniforms.normalMatrix * in.normal;
     out.modelNormal = in.normal;  // Pass original normal for face orientation
     out.worldPosition = worldPos.xyz;
-    out.color = in.color; // Pre-baked lighting from Swift
+    out.color = in.color; // Lit in the fragment shader
+    out.texCoord = in.texCoord; // x: baked ambient occlusion (0 = none)
+    out.clipDistance = dot(uniforms.clipPlane.xyz, worldPos.xyz) + uniforms.clipPlane.w;
     return out;
 }
 
@@ -95,6 +99,23 @@ fragment float4 meshFragmentShader(
     // View direction (from fragment to camera)
     float3 V = normalize(uniforms.cameraPosition - in.worldPosition);
 
+    // Cutaway: drop everything in front of the plane. Inner surfaces seen through the cut
+    // face away from the camera; drawing them flat makes the cut look like a solid cap.
+    if (any(uniforms.clipPlane != 0.0)) {
+        if (in.clipDistance < 0.0) {
+            discard_fragment();
+        }
+        if (dot(in.modelNormal, V) < 0.0) {
+            float3 capColor = mix(material.baseColor, float3(1.0, 0.45, 0.1), 0.5) * 0.8;
+            return float4(capColor, 1.0);
+        }
+    }
+
+    // Outline mode: the mesh only hides edges behind it, the drawing comes from the edges
+    if (material.outlineFill > 0.5) {
+        return float4(1.0, 1.0, 1.0, 1.0);
+    }
+
     // Check if face orientation mode is enabled
     if (material.showFaceOrientation > 0.5) {
         // Face orientation coloring: subtle color temperature shift
@@ -179,14 +200,15 @@ fragment float4 meshFragmentShader(
     float diffuse = keyDiffuse * 0.6 + fillDiffuse * 0.3 + rimDiffuse * 0.2;
 
     // Determine base color:
-    // - If vertex color is white (1,1,1), use material base color (single-color model)
-    // - Otherwise, use vertex color directly (multi-color model like 3MF with extruders)
-    float3 vertexColor = in.color.rgb;
-    float isWhite = step(0.99, vertexColor.r) * step(0.99, vertexColor.g) * step(0.99, vertexColor.b);
-    float3 baseColor = mix(vertexColor, material.baseColor, isWhite);
+    // - Vertex color with zero alpha: no color of its own, use the material base color (single-color model)
+    // - Otherwise, use the vertex color (3MF extruders, analysis visualizations, imported vertex colors)
+    float3 baseColor = mix(material.baseColor, in.color.rgb, step(0.5, in.color.a));
+
+    // Baked ambient occlusion darkens creases and cavities (presentations only, zero otherwise)
+    float occlusion = 1.0 - in.texCoord.x;
 
     // Final color = base color * (ambient + diffuse) + specular highlights
-    float3 finalColor = baseColor * (ambient + diffuse) + float3(specular);
+    float3 finalColor = baseColor * (ambient + diffuse) * occlusion + float3(specular) * occlusion;
 
     return float4(finalColor, 1.0);
 }
@@ -235,6 +257,7 @@ vertex VertexOut wireframeVertexShader(
     out.normal = uniforms.normalMatrix * instanceRotation * in.normal;
     out.modelNormal = in.normal;
     out.worldPosition = worldPos.xyz;
+    out.clipDistance = dot(uniforms.clipPlane.xyz, worldPos.xyz) + uniforms.clipPlane.w;
 
     // Apply alpha from instance
     out.color = float4(in.color.rgb, instance.alpha);
@@ -245,6 +268,9 @@ vertex VertexOut wireframeVertexShader(
 fragment float4 wireframeFragmentShader(
     VertexOut in [[stage_in]]
 ) {
+    if (in.clipDistance < 0.0) {
+        discard_fragment();
+    }
     return in.color;
 }
 
@@ -0,0 +1,465 @@
+import Foundation
+import JavaScriptCore
+
+/// Errors that can occur while running a script
+enum ScriptError: LocalizedError {
+    case unreadable(URL)
+    case exception(message: String, line: Int?)
+    case invalidResult(String)
+    case unserializable
+    case processFailed(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .unreadable(let url):
+            return "Cannot read script: \(url.path)"
+        case .exception(let message, let line):
+            if let line {
+                return "Script error (line \(line)): \(message)"
+            }
+            return "Script error: \(message)"
+        case .invalidResult(let name):
+            return "gostl.result(\"\(name)\", value) needs a finite number, a string or a boolean"
+        case .unserializable:
+            return "Script results cannot be written as JSON"
+        case .processFailed(let message):
+            return message.isEmpty ? "The script process failed" : message
+        }
+    }
+}
+
+/// Outcome of a script run (results hold only numbers, strings and booleans)
+struct ScriptReport: @unchecked Sendable {
+    /// Lines printed by the script
+    var output: [String] = []
+    /// Measurements created by the script (in the coordinates the script worked in)
+    var measurements: [Measurement] = []
+    /// Named results recorded with gostl.result(name, value), in order (finite Double, String or Bool)
+    var results: [(name: String, value: Any)] = []
+    /// Failure messages recorded with gostl.fail(message)
+    var failures: [String] = []
+
+    /// Whether the script completed without recording failures
+    var passed: Bool {
+        failures.isEmpty
+    }
+
+    /// JSON representation of results, measurements and failures
+    func jsonData() throws -> Data {
+        let object: [String: Any] = [
+            "passed": passed,
+            "results": results.map { ["name": $0.name, "value": $0.value] as [String: Any] },
+            "measurements": measurements.map { measurement in
+                [
+                    "type": measurement.label,
+                    "value": measurement.value,
+                    "points": measurement.points.map { ScriptRuntime.pointDictionary($0.position) }
+                ] as [String: Any]
+            },
+            "failures": failures
+        ]
+        // JSONSerialization raises an exception instead of throwing for NaN and other invalid values
+        guard JSONSerialization.isValidJSONObject(object) else {
+            throw ScriptError.unserializable
+        }
+        return try JSONSerialization.data(withJSONObject: object, options: [.prettyPrinted, .sortedKeys])
+    }
+}
+
+// MARK: - Hand-over
+
+extension ScriptReport {
+    /// Everything in the report, as a `gostl script --report` subprocess hands it to the viewer
+    private struct Archive: Codable {
+        enum Value: Codable {
+            case number(Double)
+            case string(String)
+            case bool(Bool)
+        }
+
+        struct Result: Codable {
+            var name: String
+            var value: Value
+        }
+
+        var output: [String]
+        var measurements: [Measurement]
+        var results: [Result]
+        var failures: [String]
+    }
+
+    func archiveData() throws -> Data {
+        let archived = try results.map { result -> Archive.Result in
+            switch result.value {
+            case let value as Bool: return Archive.Result(name: result.name, value: .bool(value))
+            case let value as Double: return Archive.Result(name: result.name, value: .number(value))
+            case let value as String: return Archive.Result(name: result.name, value: .string(value))
+            default: throw ScriptError.invalidResult(result.name)
+            }
+        }
+        return try JSONEncoder().encode(Archive(output: output, measurements: measurements, results: archived, failures: failures))
+    }
+
+    init(archiveData: Data) throws {
+        let archive = try JSONDecoder().decode(Archive.self, from: archiveData)
+        output = archive.output
+        measurements = archive.measurements
+        results = archive.results.map { result -> (name: String, value: Any) in
+            switch result.value {
+            case .number(let value): return (result.name, value)
+            case .string(let value): return (result.name, value)
+            case .bool(let value): return (result.name, value)
+            }
+        }
+        failures = archive.failures
+    }
+}
+
+/// JavaScript runtime with bindings for custom analyses
+///
+/// Scripts see a global `gostl` object:
+/// - `gostl.model`: the model the script was started with (or null)
+/// - `gostl.load(path)`: load an STL/3MF file (relative to the script)
+/// - `gostl.triangles(model)`, `gostl.closestVertex(model, point)`: geometry queries
+///   (models carry the binary STL `header`/`headerBytes`, triangles their `attribute` value)
+/// - `gostl.distance(a, b)`, `gostl.angle(a, vertex, c)`, `gostl.radius(a, b, c)`: create measurements
+/// - `gostl.result(name, value)`, `gostl.fail(message)`, `gostl.exportResults(path)`: report results
+/// - `gostl.log(...)`, `print(...)`, `console.log(...)`: write output
+///
+/// Points are `{x, y, z}` objects or `[x, y, z]` arrays.
+///
+/// Scripts are JavaScript rather than Lua or Starlark: JavaScriptCore ships with macOS, so scripting
+/// needs no extra runtime.
+final class ScriptRuntime {
+    private let context: JSContext
+    private let baseURL: URL
+    private var models: [STLModel] = []
+    private var report = ScriptReport()
+    private var pendingError: ScriptError?
+
+    /// Called for every line the script prints (in addition to collecting it)
+    var onOutput: ((String) -> Void)?
+
+    /// - Parameter baseURL: Directory that relative paths in the script are resolved against
+    init(baseURL: URL = URL(fileURLWithPath: FileManager.default.currentDirectoryPath)) {
+        self.context = JSContext()
+        self.baseURL = baseURL
+        installBindings()
+    }
+
+    /// Make a model available to the script as `gostl.model`
+    func setModel(_ model: STLModel) {
+        context.objectForKeyedSubscript("gostl").setObject(modelObject(model), forKeyedSubscript: "model" as NSString)
+    }
+
+    /// Run a script file
+    func run(url: URL) throws -> ScriptReport {
+        guard let source = try? String(contentsOf: url, encoding: .utf8) else {
+            throw ScriptError.unreadable(url)
+        }
+        return try run(source: source, name: url.lastPathComponent)
+    }
+
+    /// Run script source
+    func run(source: String, name: String = "script.js") throws -> ScriptReport {
+        pendingError = nil
+        context.evaluateScript(source, withSourceURL: URL(fileURLWithPath: name))
+        if let pendingError {
+            throw pendingError
+        }
+        return report
+    }
+
+    // MARK: - Bindings
+
+    private func installBindings() {
+        context.exceptionHandler = { [weak self] _, exception in
+            let message = exception?.toString() ?? "Unknown error"
+            let lineValue = exception?.objectForKeyedSubscript("line")
+            let line = lineValue?.isNumber == true ? Int(lineValue!.toInt32()) : nil
+            self?.pendingError = .exception(message: message, line: line)
+        }
+
+        let log: @convention(block) () -> Void = { [unowned self] in
+            let arguments = JSContext.currentArguments() as? [JSValue] ?? []
+            self.emit(arguments.map { $0.toString() ?? "" }.joined(separator: " "))
+        }
+
+        let load: @convention(block) (String) -> JSValue? = { [unowned self] path in
+            let url = self.resolve(path)
+            do {
+                return self.modelObject(try ModelFileLoader.load(url: url))
+            } catch {
+                self.throwError("Cannot load \(path): \(error.localizedDescription)")
+                return nil
+            }
+        }
+
+        let triangles: @convention(block) (JSValue) -> [[String: Any]]? = { [unowned self] modelValue in
+            guard let model = self.model(from: modelValue) else { return nil }
+            return model.triangles.map { triangle in
+                [
+                    "v1": Self.pointDictionary(triangle.v1),
+                    "v2": Self.pointDictionary(triangle.v2),
+                    "v3": Self.pointDictionary(triangle.v3),
+                    "normal": Self.pointDictionary(triangle.normal),
+                    "area": triangle.area(),
+                    "attribute": Int(triangle.attribute)
+                ] as [String: Any]
+            }
+        }
+
+        let closestVertex: @convention(block) (JSValue, JSValue) -> [String: Double]? = { [unowned self] modelValue, pointValue in
+            guard let model = self.model(from: modelValue), let point = self.point(from: pointValue) else { return nil }
+            var closest: Vector3?
+            var closestDistance = Double.infinity
+            for triangle in model.triangles {
+                for vertex in triangle.vertices {
+                    let distance = vertex.distanceSquared(to: point)
+                    if distance < closestDistance {
+                        closestDistance = distance
+                        closest = vertex
+                    }
+                }
+            }
+            return closest.map(Self.pointDictionary)
+        }
+
+        let distance: @convention(block) (JSValue, JSValue) -> JSValue? = { [unowned self] a, b in
+            self.measure(.distance, [a, b])
+        }
+
+        let angle: @convention(block) (JSValue, JSValue, JSValue) -> JSValue? = { [unowned self] a, vertex, c in
+            self.measure(.angle, [a, vertex, c])
+        }
+
+        let radius: @convention(block) (JSValue, JSValue, JSValue) -> JSValue? = { [unowned self] a, b, c in
+            self.measure(.radius, [a, b, c])
+        }
+
+        let result: @convention(block) (String, JSValue) -> Void = { [unowned self] name, value in
+            guard let converted = Self.resultValue(value) else {
+                self.throwError(ScriptError.invalidResult(name).localizedDescription)
+                return
+            }
+            self.report.results.append((name: name, value: converted))
+        }
+
+        let fail: @convention(block) (String) -> Void = { [unowned self] message in
+            self.report.failures.append(message)
+            self.emit("FAIL: \(message)")
+        }
+
+        let exportResults: @convention(block) (String) -> Void = { [unowned self] path in
+            do {
+                try self.report.jsonData().write(to: self.resolve(path))
+            } catch {
+                self.throwError("Cannot export results to \(path): \(error.localizedDescription)")
+            }
+        }
+
+        let gostl = JSValue(newObjectIn: context)!
+        gostl.setObject(NSNull(), forKeyedSubscript: "model" as NSString)
+        gostl.setObject(log, forKeyedSubscript: "log" as NSString)
+        gostl.setObject(load, forKeyedSubscript: "load" as NSString)
+        gostl.setObject(triangles, forKeyedSubscript: "triangles" as NSString)
+        gostl.setObject(closestVertex, forKeyedSubscript: "closestVertex" as NSString)
+        gostl.setObject(distance, forKeyedSubscript: "distance" as NSString)
+        gostl.setObject(angle, forKeyedSubscript: "angle" as NSString)
+        gostl.setObject(radius, forKeyedSubscript: "radius" as NSString)
+        gostl.setObject(result, forKeyedSubscript: "result" as NSString)
+        gostl.setObject(fail, forKeyedSubscript: "fail" as NSString)
+        gostl.setObject(exportResults, forKeyedSubscript: "exportResults" as NSString)
+        context.setObject(gostl, forKeyedSubscript: "gostl" as NSString)
+
+        let console = JSValue(newObjectIn: context)!
+        console.setObject(log, forKeyedSubscript: "log" as NSString)
+        context.setObject(console, forKeyedSubscript: "console" as NSString)
+        context.setObject(log, forKeyedSubscript: "print" as NSString)
+    }
+
+    // MARK: - Helpers
+
+    /// A result value as a finite Double, a String or a Bool (nil for anything JSON cannot hold)
+    static func resultValue(_ value: JSValue) -> Any? {
+        if value.isBoolean {
+            return value.toBool()
+        }
+        if value.isNumber {
+            let number = value.toDouble()
+            return number.isFinite ? number : nil
+        }
+        if value.isString {
+            return value.toString()
+        }
+        return nil
+    }
+
+    // MARK: - Subprocess
+
+    /// Run a script file in a `gostl script` subprocess that cancelling the job terminates.
+    /// JavaScriptCore cannot interrupt a running script, so one stuck in a loop is stopped with its process.
+    /// - Parameter model: Model the script sees as `gostl.model` (in file coordinates)
+    static func runInSubprocess(
+        url: URL,
+        model: STLModel?,
+        job: JobContext,
+        executable: URL = Bundle.main.executableURL ?? URL(fileURLWithPath: CommandLine.arguments[0])
+    ) throws -> ScriptReport {
+        let workspace = TempWorkspace.shared
+        let reportURL = workspace.makeURL(prefix: "script-report", pathExtension: "json")
+        var arguments = ["script", url.path]
+        var modelURL: URL?
+        if let model {
+            let exported = workspace.makeURL(prefix: "script-model", pathExtension: "stl")
+            try STLExporter.exportBinary(model: model, to: exported)
+            arguments.append(exported.path)
+            modelURL = exported
+        }
+        arguments += ["--report", reportURL.path]
+        defer {
+            try? FileManager.default.removeItem(at: reportURL)
+            if let modelURL {
+                try? FileManager.default.removeItem(at: modelURL)
+            }
+        }
+
+        let process = Process()
+        process.executableURL = executable
+        process.arguments = arguments
+        let stdoutPipe = Pipe()
+        let stderrPipe = Pipe()
+        process.standardOutput = stdoutPipe
+        process.standardError = stderrPipe
+
+        try job.checkCancellation()
+        try process.run()
+        job.attach(process)
+        defer { job.detach(process) }
+
+        // The output is also in the report; drain both pipes so the script never blocks on them
+        let errors = PipeReader(stderrPipe)
+        _ = stdoutPipe.fileHandleForReading.readDataToEndOfFile()
+        let stderrData = errors.result()
+        process.waitUntilExit()
+        try job.checkCancellation()
+
+        // The report is written whether or not the script's checks passed; without it the script failed to run
+        guard let data = try? Data(contentsOf: reportURL) else {
+            let message = String(decoding: stderrData, as: UTF8.self).trimmingCharacters(in: .whitespacesAndNewlines)
+            throw ScriptError.processFailed(message.hasPrefix("Error: ") ? String(message.dropFirst("Error: ".count)) : message)
+        }
+        return try ScriptReport(archiveData: data)
+    }
+
+    private func emit(_ line: String) {
+        report.output.append(line)
+        onOutput?(line)
+    }
+
+    /// Raise a JavaScript exception from inside a binding
+    private func throwError(_ message: String) {
+        context.exception = JSValue(newErrorFromMessage: message, in: context)
+    }
+
+    private func resolve(_ path: String) -> URL {
+        if path.hasPrefix("/") {
+            return URL(fileURLWithPath: path)
+        }
+        return baseURL.appendingPathComponent(path)
+    }
+
+    /// Create a measurement from script points and return its value
+    private func measure(_ type: MeasurementType, _ values: [JSValue]) -> JSValue? {
+        let positions = values.compactMap(point(from:))
+        guard positions.count == values.count else {
+            throwError("Expected points as {x, y, z} or [x, y, z]")
+            return nil
+        }
+
+        let value: Double
+        var circle: Circle?
+        switch type {
+        case .distance:
+            value = positions[0].distance(to: positions[1])
+        case .angle:
+            let v1 = (positions[0] - positions[1]).normalized()
+            let v2 = (positions[2] - positions[1]).normalized()
+            value = acos(max(-1.0, min(1.0, v1.dot(v2)))) * 180.0 / .pi
+        case .radius:
+            guard let fitted = Circle.fit(points: positions) else {
+                throwError("Cannot fit a circle through collinear points")
+                return nil
+            }
+            circle = fitted
+            value = fitted.radius
+        case .triangleSelect:
+            return nil
+        }
+
+        let points = positions.map { MeasurementPoint(position: $0, normal: .zero, isAirPoint: true) }
+        report.measurements.append(Measurement(type: type, points: points, value: value, circle: circle))
+        return JSValue(double: value, in: context)
+    }
+
+    private func modelObject(_ model: STLModel) -> JSValue {
+        models.append(model)
+        let analysis = model.analyze()
+        let object: [String: Any] = [
+            "handle": models.count - 1,
+            "name": model.name ?? "",
+            "header": model.headerText ?? "",
+            "headerBytes": model.header.map { $0.map { Int($0) } } ?? [],
+            "triangleCount": analysis.triangleCount,
+            "volume": analysis.volume,
+            "surfaceArea": analysis.surfaceArea,
+            "surfaceAreaByOrientation": [
+                "up": analysis.orientationAreas.up,
+                "down": analysis.orientationAreas.down,
+                "vertical": analysis.orientationAreas.vertical
+            ],
+            "dimensions": Self.pointDictionary(analysis.dimensions),
+            "boundingBox": [
+                "min": Self.pointDictionary(analysis.boundingBox.min),
+                "max": Self.pointDictionary(analysis.boundingBox.max)
+            ],
+            "edgeCount": analysis.edgeCount,
+            "minEdgeLength": analysis.minEdgeLength,
+            "maxEdgeLength": analysis.maxEdgeLength,
+            "avgEdgeLength": analysis.avgEdgeLength,
+            "weightPLA100": analysis.weightPLA100,
+            "weightPLA15": analysis.weightPLA15
+        ]
+        return JSValue(object: object, in: context)
+    }
+
+    private func model(from value: JSValue) -> STLModel? {
+        let handle = value.objectForKeyedSubscript("handle")
+        guard let handle, handle.isNumber, models.indices.contains(Int(handle.toInt32())) else {
+            throwError("Expected a model returned by gostl.load or gostl.model")
+            return nil
+        }
+        return models[Int(handle.toInt32())]
+    }
+
+    private func point(from value: JSValue) -> Vector3? {
+        let point: Vector3
+        if value.isArray, let array = value.toArray() as? [NSNumber], array.count == 3 {
+            point = Vector3(array[0].doubleValue, array[1].doubleValue, array[2].doubleValue)
+        } else {
+            guard value.isObject,
+                  let x = value.objectForKeyedSubscript("x"), x.isNumber,
+                  let y = value.objectForKeyedSubscript("y"), y.isNumber,
+                  let z = value.objectForKeyedSubscript("z"), z.isNumber else {
+                return nil
+            }
+            point = Vector3(x.toDouble(), y.toDouble(), z.toDouble())
+        }
+        // NaN or infinite coordinates would make measurements that cannot be saved
+        return point.x.isFinite && point.y.isFinite && point.z.isFinite ? point : nil
+    }
+
+    static func pointDictionary(_ vector: Vector3) -> [String: Double] {
+        ["x": vector.x, "y": vector.y, "z": vector.z]
+    }
+}
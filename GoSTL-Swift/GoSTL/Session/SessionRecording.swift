@@ -0,0 +1,221 @@
+import Foundation
+
+/// Camera state at a point in a recorded session
+struct CameraKeyframe: Codable, Equatable {
+    var distance: Double
+    var angleX: Double
+    var angleY: Double
+    /// Orbit target in the file's original coordinates
+    var target: Vector3
+
+    init(distance: Double, angleX: Double, angleY: Double, target: Vector3) {
+        self.distance = distance
+        self.angleX = angleX
+        self.angleY = angleY
+        self.target = target
+    }
+
+    /// Capture a camera (render space) as a keyframe in original coordinates
+    init(camera: Camera, coordinateOffset: Vector3) {
+        self.distance = camera.distance
+        self.angleX = camera.angleX
+        self.angleY = camera.angleY
+        self.target = Vector3(Double(camera.target.x), Double(camera.target.y), Double(camera.target.z)) + coordinateOffset
+    }
+
+    /// Move a camera (render space) to this keyframe
+    func apply(to camera: Camera, coordinateOffset: Vector3) {
+        camera.stopInertia()
+        camera.distance = distance
+        camera.angleX = angleX
+        camera.angleY = angleY
+        camera.target = (target - coordinateOffset).float3
+    }
+
+    /// Linear interpolation between two keyframes (yaw takes the shorter way around)
+    static func interpolate(_ a: CameraKeyframe, _ b: CameraKeyframe, t: Double) -> CameraKeyframe {
+        var deltaY = (b.angleY - a.angleY).truncatingRemainder(dividingBy: 2 * .pi)
+        if deltaY > .pi { deltaY -= 2 * .pi }
+        if deltaY < -.pi { deltaY += 2 * .pi }
+        return CameraKeyframe(
+            distance: a.distance + (b.distance - a.distance) * t,
+            angleX: a.angleX + (b.angleX - a.angleX) * t,
+            angleY: a.angleY + deltaY * t,
+            target: a.target + (b.target - a.target) * t
+        )
+    }
+}
+
+/// A recorded walkthrough: timestamped camera keyframes and measurement snapshots
+///
+/// Stored as JSON in `.gostlsession` files. All positions are in the file's original
+/// coordinates so a session replays correctly after the model is reloaded.
+struct SessionRecording: Codable {
+    struct TimedCamera: Codable, Equatable {
+        /// Seconds since the start of the recording
+        var time: Double
+        var camera: CameraKeyframe
+    }
+
+    struct TimedMeasurements: Codable {
+        /// Seconds since the start of the recording
+        var time: Double
+        /// All measurements visible from this time on
+        var measurements: [Measurement]
+    }
+
+    static let fileExtension = "gostlsession"
+    static let currentVersion = 1
+
+    var version: Int = SessionRecording.currentVersion
+    /// Name of the model the session was recorded on
+    var fileName: String?
+    var cameraKeyframes: [TimedCamera] = []
+    var measurementSnapshots: [TimedMeasurements] = []
+
+    /// Length of the session in seconds
+    var duration: Double {
+        max(cameraKeyframes.last?.time ?? 0, measurementSnapshots.last?.time ?? 0)
+    }
+
+    /// Interpolated camera at a time (nil if no keyframes were recorded)
+    func camera(at time: Double) -> CameraKeyframe? {
+        guard let first = cameraKeyframes.first else { return nil }
+        if time <= first.time { return first.camera }
+
+        // Last keyframe at or before the time
+        guard let index = cameraKeyframes.lastIndex(where: { $0.time <= time }) else { return first.camera }
+        let previous = cameraKeyframes[index]
+        guard index + 1 < cameraKeyframes.count else { return previous.camera }
+
+        let next = cameraKeyframes[index + 1]
+        let span = next.time - previous.time
+        guard span > 0 else { return next.camera }
+        return CameraKeyframe.interpolate(previous.camera, next.camera, t: (time - previous.time) / span)
+    }
+
+    /// Measurements visible at a time (nil before the first snapshot)
+    func measurements(at time: Double) -> [Measurement]? {
+        measurementSnapshots.last { $0.time <= time }?.measurements
+    }
+
+    // MARK: - File I/O
+
+    func write(to url: URL) throws {
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+        try encoder.encode(self).write(to: url)
+    }
+
+    static func load(from url: URL) throws -> SessionRecording {
+        try JSONDecoder().decode(SessionRecording.self, from: Data(contentsOf: url))
+    }
+}
+
+/// Samples camera and measurement changes into a `SessionRecording`
+final class SessionRecorder {
+    /// Minimum time between camera keyframes (limits file size while orbiting)
+    static let cameraSampleInterval: Double = 1.0 / 30.0
+
+    private(set) var recording: SessionRecording
+    private let startTime: CFAbsoluteTime
+    private var lastMeasurementSignature: [[Double]]?
+
+    init(fileName: String?, startTime: CFAbsoluteTime = CFAbsoluteTimeGetCurrent()) {
+        self.recording = SessionRecording(fileName: fileName)
+        self.startTime = startTime
+    }
+
+    /// Seconds since recording started
+    func elapsed(at now: CFAbsoluteTime = CFAbsoluteTimeGetCurrent()) -> Double {
+        max(0, now - startTime)
+    }
+
+    /// Record the camera if it moved since the last keyframe
+    func record(camera keyframe: CameraKeyframe, at now: CFAbsoluteTime = CFAbsoluteTimeGetCurrent()) {
+        let time = elapsed(at: now)
+        if let last = recording.cameraKeyframes.last {
+            guard last.camera != keyframe, time - last.time >= Self.cameraSampleInterval else { return }
+        }
+        recording.cameraKeyframes.append(SessionRecording.TimedCamera(time: time, camera: keyframe))
+    }
+
+    /// Record the measurements (original coordinates) if they changed since the last snapshot
+    func record(measurements: [Measurement], at now: CFAbsoluteTime = CFAbsoluteTimeGetCurrent()) {
+        let signature = measurements.map { measurement in
+            [measurement.value] + measurement.points.flatMap { [$0.position.x, $0.position.y, $0.position.z] }
+        }
+        guard signature != lastMeasurementSignature else { return }
+        lastMeasurementSignature = signature
+        recording.measurementSnapshots.append(
+            SessionRecording.TimedMeasurements(time: elapsed(at: now), measurements: measurements)
+        )
+    }
+
+    /// Finish the recording, closing it with the final camera state
+    func finish(at now: CFAbsoluteTime = CFAbsoluteTimeGetCurrent()) -> SessionRecording {
+        if let last = recording.cameraKeyframes.last {
+            let time = elapsed(at: now)
+            if time > last.time {
+                recording.cameraKeyframes.append(SessionRecording.TimedCamera(time: time, camera: last.camera))
+            }
+        }
+        return recording
+    }
+}
+
+/// Plays a `SessionRecording` back in time
+@Observable
+final class SessionPlayer {
+    let recording: SessionRecording
+
+    /// Destination of a video export (nil for on-screen replay only)
+    let videoURL: URL?
+
+    /// Frame rate of video exports; video replays advance by exactly one frame per rendered frame
+    static let videoFrameRate: Int32 = 30
+
+    /// Writer created by the renderer once the drawable size is known
+    @ObservationIgnored
+    var videoWriter: SessionVideoWriter?
+
+    private(set) var time: Double = 0
+
+    /// Set once the frame at the end of the recording has been shown
+    private(set) var isComplete = false
+
+    @ObservationIgnored
+    private var hasShownFirstFrame = false
+    @ObservationIgnored
+    private var appliedSnapshotIndex: Int?
+
+    init(recording: SessionRecording, videoURL: URL? = nil) {
+        self.recording = recording
+        self.videoURL = videoURL
+    }
+
+    /// Advance playback (wall-clock time on screen, fixed frame steps while exporting video)
+    func advance(deltaTime: Double) {
+        // The frame at time zero is shown before time starts moving
+        guard hasShownFirstFrame else {
+            hasShownFirstFrame = true
+            return
+        }
+        guard time < recording.duration else {
+            isComplete = true
+            return
+        }
+        let step = videoURL == nil ? deltaTime : 1.0 / Double(Self.videoFrameRate)
+        time = min(time + step, recording.duration)
+    }
+
+    /// Measurements to show if the current snapshot changed since the last call
+    func takeMeasurementChange() -> [Measurement]? {
+        guard let index = recording.measurementSnapshots.lastIndex(where: { $0.time <= time }),
+              index != appliedSnapshotIndex else {
+            return nil
+        }
+        appliedSnapshotIndex = index
+        return recording.measurementSnapshots[index].measurements
+    }
+}
@@ -0,0 +1,115 @@
+import AVFoundation
+import CoreVideo
+import Foundation
+
+/// Errors that can occur while exporting a session video
+enum SessionVideoError: LocalizedError {
+    case cannotStart(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .cannotStart(let reason):
+            return "Cannot write session video: \(reason)"
+        }
+    }
+}
+
+/// Encodes rendered BGRA frames into an H.264 video
+///
+/// Frames arrive from Metal command buffer completion handlers on arbitrary threads;
+/// appends are serialized and `finish` waits for all frames that were scheduled.
+final class SessionVideoWriter: @unchecked Sendable {
+    let url: URL
+    let width: Int
+    let height: Int
+
+    private let writer: AVAssetWriter
+    private let input: AVAssetWriterInput
+    private let adaptor: AVAssetWriterInputPixelBufferAdaptor
+    private let frameRate: Int32
+    private let queue = DispatchQueue(label: "GoSTL.SessionVideoWriter")
+    private let pendingFrames = DispatchGroup()
+    private var frameIndex: Int64 = 0
+
+    init(url: URL, width: Int, height: Int, frameRate: Int32) throws {
+        // H.264 requires even dimensions
+        self.width = width & ~1
+        self.height = height & ~1
+        self.url = url
+        self.frameRate = frameRate
+
+        guard self.width > 0, self.height > 0 else {
+            throw SessionVideoError.cannotStart("invalid frame size \(width)×\(height)")
+        }
+
+        try? FileManager.default.removeItem(at: url)
+        let fileType: AVFileType = url.pathExtension.lowercased() == "mov" ? .mov : .mp4
+        writer = try AVAssetWriter(outputURL: url, fileType: fileType)
+
+        input = AVAssetWriterInput(mediaType: .video, outputSettings: [
+            AVVideoCodecKey: AVVideoCodecType.h264,
+            AVVideoWidthKey: self.width,
+            AVVideoHeightKey: self.height
+        ])
+        input.expectsMediaDataInRealTime = false
+
+        adaptor = AVAssetWriterInputPixelBufferAdaptor(assetWriterInput: input, sourcePixelBufferAttributes: [
+            kCVPixelBufferPixelFormatTypeKey as String: kCVPixelFormatType_32BGRA,
+            kCVPixelBufferWidthKey as String: self.width,
+            kCVPixelBufferHeightKey as String: self.height
+        ])
+
+        guard writer.canAdd(input) else {
+            throw SessionVideoError.cannotStart("unsupported output settings")
+        }
+        writer.add(input)
+        guard writer.startWriting() else {
+            throw SessionVideoError.cannotStart(writer.error?.localizedDescription ?? "unknown error")
+        }
+        writer.startSession(atSourceTime: .zero)
+    }
+
+    /// Announce a frame that will be appended once the GPU has finished rendering it
+    func beginFrame() {
+        pendingFrames.enter()
+    }
+
+    /// Append a BGRA frame (rows are cropped to the video size if the window was resized)
+    func appendFrame(bytes: UnsafeRawPointer, bytesPerRow sourceBytesPerRow: Int, width sourceWidth: Int, height sourceHeight: Int) {
+        defer { pendingFrames.leave() }
+        queue.sync {
+            guard writer.status == .writing,
+                  let pool = adaptor.pixelBufferPool else { return }
+
+            var pixelBuffer: CVPixelBuffer?
+            CVPixelBufferPoolCreatePixelBuffer(nil, pool, &pixelBuffer)
+            guard let pixelBuffer else { return }
+
+            CVPixelBufferLockBaseAddress(pixelBuffer, [])
+            if let destination = CVPixelBufferGetBaseAddress(pixelBuffer) {
+                let destinationBytesPerRow = CVPixelBufferGetBytesPerRow(pixelBuffer)
+                let rowBytes = min(width, sourceWidth) * 4
+                for row in 0..<min(height, sourceHeight) {
+                    memcpy(destination + row * destinationBytesPerRow, bytes + row * sourceBytesPerRow, rowBytes)
+                }
+            }
+            CVPixelBufferUnlockBaseAddress(pixelBuffer, [])
+
+            while !input.isReadyForMoreMediaData && writer.status == .writing {
+                Thread.sleep(forTimeInterval: 0.005)
+            }
+            adaptor.append(pixelBuffer, withPresentationTime: CMTime(value: frameIndex, timescale: frameRate))
+            frameIndex += 1
+        }
+    }
+
+    /// Finish the file after all pending frames were appended
+    func finish(completion: @escaping @Sendable (Error?) -> Void) {
+        pendingFrames.notify(queue: queue) { [self] in
+            input.markAsFinished()
+            writer.finishWriting { [self] in
+                completion(writer.status == .completed ? nil : writer.error)
+            }
+        }
+    }
+}
@@ -0,0 +1,116 @@
+import Foundation
+import simd
+
+/// Iso-height lines on the model surface, like the contour lines of a topographic map
+///
+/// Each line is where the mesh crosses a horizontal plane; levels are multiples of the interval in
+/// file coordinates, so a 1 mm interval draws lines at Z = 1, 2, 3 ... of the file.
+enum ContourLines {
+    /// One line segment at a contour level
+    struct Segment {
+        let start: Vector3
+        let end: Vector3
+        /// Level number (height = level × interval in file coordinates)
+        let level: Int
+    }
+
+    struct Result {
+        let segments: [Segment]
+        let interval: Double
+        /// Lowest and highest level that has segments
+        let levels: ClosedRange<Int>?
+        /// More segments than `maxSegments`: the rest was dropped
+        let isTruncated: Bool
+    }
+
+    /// Intervals offered in the menus (mm)
+    static let intervalOptions: [Double] = [0.1, 0.2, 0.5, 1, 2, 5, 10]
+
+    /// Number of levels the automatic interval aims for
+    static let autoLevelCount = 25.0
+
+    /// Every n-th level is a major (index) contour
+    static let majorEvery = 5
+
+    /// Upper bound for the line count, keeps fine intervals on dense meshes responsive
+    static let maxSegments = 2_000_000
+
+    /// Round interval (1, 2, 5 × 10ⁿ) giving about `autoLevelCount` levels over a height
+    static func autoInterval(for height: Double) -> Double {
+        guard height > 0 else { return 1 }
+        let raw = height / autoLevelCount
+        let magnitude = pow(10, floor(log10(raw)))
+        let step = [1.0, 2, 5, 10].first { $0 * magnitude >= raw } ?? 10
+        return step * magnitude
+    }
+
+    /// Contour segments of the triangles at every multiple of `interval`
+    /// - Parameters:
+    ///   - offset: Render-to-file offset along Z (file Z = render Z + offset)
+    static func compute(
+        _ triangles: [Triangle],
+        interval: Double,
+        offset: Double = 0,
+        job: JobContext? = nil
+    ) throws -> Result {
+        guard interval > 0 else {
+            return Result(segments: [], interval: interval, levels: nil, isTruncated: false)
+        }
+
+        var segments: [Segment] = []
+        var lowest = Int.max, highest = Int.min
+        var isTruncated = false
+
+        outer: for (index, triangle) in triangles.enumerated() {
+            if index % 65536 == 0 {
+                try job?.checkCancellation()
+                job?.reportProgress(Double(index) / Double(triangles.count))
+            }
+
+            // Only the levels within the triangle's Z range can cross it
+            let minZ = Swift.min(triangle.v1.z, triangle.v2.z, triangle.v3.z) + offset
+            let maxZ = Swift.max(triangle.v1.z, triangle.v2.z, triangle.v3.z) + offset
+            let first = Int((minZ / interval).rounded(.up))
+            let last = Int((maxZ / interval).rounded(.down))
+            guard first <= last else { continue }
+
+            for level in first...last {
+                let z = Double(level) * interval - offset
+                // A vertex touching the plane from below (e.g. a peak) gives a zero-length segment
+                guard let crossing = CrossSection.segment(triangle, axis: 2, value: z),
+                      crossing.0.distance(to: crossing.1) > 1e-9 else { continue }
+                guard segments.count < maxSegments else {
+                    isTruncated = true
+                    break outer
+                }
+                segments.append(Segment(start: crossing.0, end: crossing.1, level: level))
+                lowest = Swift.min(lowest, level)
+                highest = Swift.max(highest, level)
+            }
+        }
+
+        return Result(
+            segments: segments,
+            interval: interval,
+            levels: segments.isEmpty ? nil : lowest...highest,
+            isTruncated: isTruncated
+        )
+    }
+
+    /// Whether a level is a major (index) contour
+    static func isMajor(_ level: Int) -> Bool {
+        level % majorEvery == 0
+    }
+
+    /// Line color from blue (lowest level) over green to red (highest); major contours are lighter
+    static func color(for level: Int, in levels: ClosedRange<Int>) -> SIMD4<Float> {
+        let span = levels.upperBound - levels.lowerBound
+        let t = span > 0 ? Float(level - levels.lowerBound) / Float(span) : 0.5
+        let low = SIMD3<Float>(0.2, 0.45, 1.0), middle = SIMD3<Float>(0.2, 0.85, 0.35), high = SIMD3<Float>(1.0, 0.3, 0.2)
+        var rgb = t < 0.5 ? simd_mix(low, middle, SIMD3(repeating: t * 2)) : simd_mix(middle, high, SIMD3(repeating: (t - 0.5) * 2))
+        if isMajor(level) {
+            rgb = simd_mix(rgb, SIMD3(repeating: 1), SIMD3(repeating: 0.5))
+        }
+        return SIMD4(rgb, 1)
+    }
+}
@@ -0,0 +1,148 @@
+import Foundation
+
+/// Filled cross-section of a model cut by an axis-aligned plane
+///
+/// The outline is the set of segments where triangles cross the plane. It is filled with the
+/// even-odd rule by sweeping across the plane: between two consecutive segment end heights,
+/// the crossing segments are sorted and paired, and each pair spans a trapezoid. This needs no
+/// loop reconstruction, so holes, nested islands and slightly open meshes fill correctly.
+enum CrossSection {
+    /// Segments where the triangles cross the plane `axis = value`
+    static func segments(_ triangles: [Triangle], axis: Int, value: Double) -> [(Vector3, Vector3)] {
+        triangles.compactMap { segment($0, axis: axis, value: value) }
+    }
+
+    /// Segment where a triangle crosses the plane `axis = value`, nil if it doesn't
+    static func segment(_ triangle: Triangle, axis: Int, value: Double) -> (Vector3, Vector3)? {
+        let vertices = [triangle.v1, triangle.v2, triangle.v3]
+        // Vertices on the plane count as above it, so shared edges are cut consistently
+        let distances = vertices.map { $0.component(axis: axis) - value }
+        var points: [Vector3] = []
+        for i in 0..<3 {
+            let j = (i + 1) % 3
+            guard (distances[i] >= 0) != (distances[j] >= 0) else { continue }
+            let t = distances[i] / (distances[i] - distances[j])
+            points.append(vertices[i] + (vertices[j] - vertices[i]) * t)
+        }
+        return points.count == 2 ? (points[0], points[1]) : nil
+    }
+
+    /// Triangles filling the cross-section at `axis = value`
+    /// - Parameters:
+    ///   - facingPositive: Whether the fill faces towards +axis (the removed side of a max plane)
+    ///   - color: Color of the fill triangles
+    static func fill(
+        _ triangles: [Triangle],
+        axis: Int,
+        value: Double,
+        facingPositive: Bool,
+        color: TriangleColor? = nil
+    ) -> [Triangle] {
+        let outline = segments(triangles, axis: axis, value: value)
+        guard !outline.isEmpty else { return [] }
+
+        // Sweep along v, pair crossings along u
+        let u = (axis + 1) % 3
+        let v = (axis + 2) % 3
+        let edges = outline.compactMap { a, b -> SweepEdge? in
+            let (au, av, bu, bv) = (a.component(axis: u), a.component(axis: v), b.component(axis: u), b.component(axis: v))
+            guard av != bv else { return nil }  // Parallel to the sweep, never crossed
+            return av < bv ? SweepEdge(u0: au, v0: av, u1: bu, v1: bv) : SweepEdge(u0: bu, v0: bv, u1: au, v1: av)
+        }
+        .sorted { $0.v0 < $1.v0 }
+
+        let stops = Array(Set(edges.flatMap { [$0.v0, $0.v1] })).sorted()
+        let normal = Vector3.zero.with(axis: axis, value: facingPositive ? 1 : -1)
+
+        var result: [Triangle] = []
+        var active: [SweepEdge] = []
+        var next = 0
+        for (low, high) in zip(stops, stops.dropFirst()) {
+            active.removeAll { $0.v1 <= low }
+            while next < edges.count && edges[next].v0 <= low {
+                if edges[next].v1 > low {
+                    active.append(edges[next])
+                }
+                next += 1
+            }
+            guard active.count >= 2 else { continue }
+
+            let middle = (low + high) / 2
+            let crossings = active.sorted { $0.u(at: middle) < $1.u(at: middle) }
+            for pair in stride(from: 0, to: crossings.count - 1, by: 2) {
+                let left = crossings[pair]
+                let right = crossings[pair + 1]
+                let corners = [
+                    point(axis: axis, value: value, u: left.u(at: low), v: low),
+                    point(axis: axis, value: value, u: right.u(at: low), v: low),
+                    point(axis: axis, value: value, u: right.u(at: high), v: high),
+                    point(axis: axis, value: value, u: left.u(at: high), v: high)
+                ]
+                for (a, b, c) in [(0, 1, 2), (0, 2, 3)] {
+                    let triangle = facingPositive
+                        ? Triangle(v1: corners[a], v2: corners[b], v3: corners[c], normal: normal, color: color)
+                        : Triangle(v1: corners[a], v2: corners[c], v3: corners[b], normal: normal, color: color)
+                    if triangle.area() > 0 {
+                        result.append(triangle)
+                    }
+                }
+            }
+        }
+        return result
+    }
+
+    /// Fill triangles for every slicing plane that cuts into the model, clipped to the slice bounds
+    /// - Parameters:
+    ///   - bounds: Slice bounds per axis [min, max]
+    ///   - modelBounds: Model extent per axis; planes at the model's extent do not cut anything
+    static func fills(_ triangles: [Triangle], bounds: [[Double]], modelBounds: [[Double]]) -> [Triangle] {
+        var result: [Triangle] = []
+        for axis in 0..<3 {
+            // Widen the plane's own axis so clipping cannot drop the fill lying exactly on it
+            var clipBounds = bounds
+            clipBounds[axis] = [-Double.infinity, Double.infinity]
+            let color = AxisColors.color(for: axis)
+            let fillColor = TriangleColor(color.x * 0.7, color.y * 0.7, color.z * 0.7)
+
+            for (side, value) in bounds[axis].enumerated() {
+                let isMax = side == 1
+                let cutsModel = isMax ? value < modelBounds[axis][1] : value > modelBounds[axis][0]
+                guard cutsModel else { continue }
+                let fill = self.fill(triangles, axis: axis, value: value, facingPositive: isMax, color: fillColor)
+                result += TriangleSlicer.sliceTriangles(fill, bounds: clipBounds).triangles
+            }
+        }
+        return result
+    }
+
+    private static func point(axis: Int, value: Double, u: Double, v: Double) -> Vector3 {
+        Vector3.zero
+            .with(axis: axis, value: value)
+            .with(axis: (axis + 1) % 3, value: u)
+            .with(axis: (axis + 2) % 3, value: v)
+    }
+
+    /// Outline segment oriented along the sweep direction (v0 < v1)
+    private struct SweepEdge {
+        let u0: Double
+        let v0: Double
+        let u1: Double
+        let v1: Double
+
+        func u(at v: Double) -> Double {
+            u0 + (u1 - u0) * (v - v0) / (v1 - v0)
+        }
+    }
+}
+
+extension Vector3 {
+    /// Copy with one component replaced (0=X, 1=Y, 2=Z)
+    func with(axis: Int, value: Double) -> Vector3 {
+        switch axis {
+        case 0: return Vector3(value, y, z)
+        case 1: return Vector3(x, value, z)
+        case 2: return Vector3(x, y, value)
+        default: fatalError("Invalid axis: \(axis)")
+        }
+    }
+}
@@ -0,0 +1,141 @@
+import Foundation
+import Observation
+
+/// State for the slicer-like layer preview (one print layer at a time, optionally animated)
+@Observable
+final class LayerPreviewState: @unchecked Sendable {
+    /// Whether the layer preview replaces the normal view
+    var isActive: Bool = false
+
+    /// Layer height in mm (see `setLayerHeight(_:)`)
+    private(set) var layerHeight: Double = 0.2
+
+    /// Current layer (0 = bottom)
+    var layer: Int = 0
+
+    /// Whether the animation is stepping through layers
+    var isPlaying: Bool = false
+
+    /// Animation speed
+    var layersPerSecond: Double = 10
+
+    /// Show everything printed up to the current layer instead of the layer alone
+    var showsLayersBelow: Bool = false
+
+    /// Z range of the model (render space)
+    private(set) var minZ: Double = 0
+    private(set) var maxZ: Double = 0
+
+    /// Time since the last animation step
+    private var elapsed: Double = 0
+
+    /// Layer speed presets offered in the panel
+    static let speeds: [Double] = [2, 5, 10, 25, 50]
+
+    var layerCount: Int {
+        max(1, Int(((maxZ - minZ) / layerHeight).rounded(.up)))
+    }
+
+    /// Bottom of the current layer
+    var layerBottom: Double {
+        minZ + Double(layer) * layerHeight
+    }
+
+    /// Top of the current layer (the last layer ends at the top of the model)
+    var layerTop: Double {
+        min(maxZ, layerBottom + layerHeight)
+    }
+
+    /// Set the model's Z range, keeping the current layer if it still exists
+    func updateRange(minZ: Double, maxZ: Double) {
+        if self.minZ != minZ {
+            self.minZ = minZ
+        }
+        if self.maxZ != maxZ {
+            self.maxZ = maxZ
+        }
+        clampLayer()
+    }
+
+    /// Change the layer height, keeping roughly the same height in the model
+    func setLayerHeight(_ height: Double) {
+        guard height > 0, height != layerHeight else { return }
+        let z = layerBottom
+        layerHeight = height
+        layer = Int(((z - minZ) / height).rounded(.down))
+        clampLayer()
+    }
+
+    /// Slice bounds showing the current layer within the model's X/Y extent
+    func bounds(modelBounds: BoundingBox) -> [[Double]] {
+        [
+            [modelBounds.min.x, modelBounds.max.x],
+            [modelBounds.min.y, modelBounds.max.y],
+            [showsLayersBelow ? minZ : layerBottom, layerTop]
+        ]
+    }
+
+    /// Start the preview at the bottom layer
+    func activate(minZ: Double, maxZ: Double) {
+        updateRange(minZ: minZ, maxZ: maxZ)
+        layer = 0
+        elapsed = 0
+        isActive = true
+    }
+
+    func deactivate() {
+        isActive = false
+        isPlaying = false
+    }
+
+    /// Step by a number of layers (stops the animation)
+    func step(by count: Int) {
+        isPlaying = false
+        layer = min(max(layer + count, 0), layerCount - 1)
+    }
+
+    /// Start or pause the animation; restarts from the bottom when at the last layer
+    func togglePlaying() {
+        if !isPlaying && layer >= layerCount - 1 {
+            layer = 0
+        }
+        elapsed = 0
+        isPlaying.toggle()
+    }
+
+    /// Advance the animation by a frame
+    /// - Returns: Whether the layer changed
+    @discardableResult
+    func advance(deltaTime: Double) -> Bool {
+        guard isActive, isPlaying, layersPerSecond > 0 else { return false }
+        elapsed += deltaTime
+        let steps = Int(elapsed * layersPerSecond)
+        guard steps > 0 else { return false }
+        elapsed -= Double(steps) / layersPerSecond
+
+        let next = min(layer + steps, layerCount - 1)
+        if next == layerCount - 1 {
+            isPlaying = false
+        }
+        guard next != layer else { return false }
+        layer = next
+        return true
+    }
+
+    /// Full reset for loading a new file
+    func fullReset() {
+        isActive = false
+        isPlaying = false
+        layer = 0
+        elapsed = 0
+        minZ = 0
+        maxZ = 0
+    }
+
+    private func clampLayer() {
+        let clamped = min(max(layer, 0), layerCount - 1)
+        if clamped != layer {
+            layer = clamped
+        }
+    }
+}
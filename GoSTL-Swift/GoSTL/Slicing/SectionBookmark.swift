@@ -0,0 +1,86 @@
+import Foundation
+
+/// A named cross-section: slice bounds, cutaway plane and the camera looking at it
+///
+/// Positions are stored in the file's original coordinates so bookmarks stay valid when the
+/// model is reloaded and recentered. Saved in the model's `.gostl` sidecar.
+struct SectionBookmark: Codable, Equatable, Identifiable {
+    var id = UUID()
+    var name: String
+    /// Slice bounds per axis [min, max] in original coordinates, nil if slicing was off
+    var bounds: [[Double]]?
+    var fillCrossSections: Bool
+    /// Camera when the section was saved; with `cutawayDepth` it also defines the arbitrary cutaway plane
+    var camera: CameraKeyframe
+    var cutawayDepth: Float?
+
+    private enum CodingKeys: String, CodingKey {
+        case name, bounds, fillCrossSections, camera, cutawayDepth
+    }
+
+    init(name: String, bounds: [[Double]]?, fillCrossSections: Bool, camera: CameraKeyframe, cutawayDepth: Float?) {
+        self.name = name
+        self.bounds = bounds
+        self.fillCrossSections = fillCrossSections
+        self.camera = camera
+        self.cutawayDepth = cutawayDepth
+    }
+
+    /// Capture the current slicing configuration (render space) as a bookmark
+    init(name: String, slicingState: SlicingState, camera: Camera, coordinateOffset: Vector3) {
+        let offset = [coordinateOffset.x, coordinateOffset.y, coordinateOffset.z]
+        self.init(
+            name: name,
+            bounds: slicingState.isVisible ? (0..<3).map { axis in slicingState.bounds[axis].map { $0 + offset[axis] } } : nil,
+            fillCrossSections: slicingState.fillCrossSections,
+            camera: CameraKeyframe(camera: camera, coordinateOffset: coordinateOffset),
+            cutawayDepth: camera.cutawayDepth
+        )
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        name = try container.decode(String.self, forKey: .name)
+        bounds = try container.decodeIfPresent([[Double]].self, forKey: .bounds)
+        fillCrossSections = try container.decodeIfPresent(Bool.self, forKey: .fillCrossSections) ?? false
+        camera = try container.decode(CameraKeyframe.self, forKey: .camera)
+        cutawayDepth = try container.decodeIfPresent(Float.self, forKey: .cutawayDepth)
+    }
+
+    /// Slice bounds in render space, clamped to the model bounds (nil if slicing was off)
+    func renderBounds(coordinateOffset: Vector3, modelBounds: [[Double]]) -> [[Double]]? {
+        guard let bounds, bounds.count == 3 else { return nil }
+        let offset = [coordinateOffset.x, coordinateOffset.y, coordinateOffset.z]
+        return (0..<3).map { axis in
+            let low = modelBounds[axis][0], high = modelBounds[axis][1]
+            let lower = Swift.min(Swift.max(bounds[axis][0] - offset[axis], low), high)
+            let upper = Swift.min(Swift.max(bounds[axis][1] - offset[axis], lower), high)
+            return [lower, upper]
+        }
+    }
+
+    /// One-line description in original coordinates, e.g. "Z 2.500…5.000 mm, cutaway 40.0 mm"
+    func summary(modelBounds: [[Double]]? = nil, coordinateOffset: Vector3 = .zero) -> String {
+        var parts: [String] = []
+        if let bounds, bounds.count == 3 {
+            let offset = [coordinateOffset.x, coordinateOffset.y, coordinateOffset.z]
+            for (axis, name) in ["X", "Y", "Z"].enumerated() {
+                // Skip axes left at the full model extent
+                if let modelBounds {
+                    let full = modelBounds[axis].map { $0 + offset[axis] }
+                    if abs(bounds[axis][0] - full[0]) < 1e-6 && abs(bounds[axis][1] - full[1]) < 1e-6 {
+                        continue
+                    }
+                }
+                parts.append("\(name) " + String(format: "%.3f…%.3f mm", bounds[axis][0], bounds[axis][1]))
+            }
+            if parts.isEmpty {
+                parts.append("full model")
+            }
+        }
+        if let cutawayDepth {
+            parts.append(String(format: "cutaway %.1f mm", cutawayDepth))
+        }
+        return parts.isEmpty ? "view only" : parts.joined(separator: ", ")
+    }
+}
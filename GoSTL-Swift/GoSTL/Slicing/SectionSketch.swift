@@ -0,0 +1,336 @@
+import Foundation
+import simd
+
+enum SectionSketchError: LocalizedError {
+    case emptyModel
+    case noSection(axis: SectionProperties.Axis, position: Double)
+
+    var errorDescription: String? {
+        switch self {
+        case .emptyModel:
+            return "Cannot sketch a section of an empty model"
+        case .noSection(let axis, let position):
+            return String(format: "The plane %@ = %g mm does not cut the model into closed outlines", axis.rawValue.uppercased(), position)
+        }
+    }
+}
+
+/// Outline of a cross-section as lines and arcs, for re-modeling a part in CAD (`gostl sketch`)
+///
+/// The cut segments are chained into closed loops. Each loop is split greedily, starting at its
+/// sharpest corner, into the longest runs that stay within `tolerance` of a line through their end
+/// points or of a least-squares circle. Arcs pass exactly through their end points (the fitted
+/// center is moved onto the chord's bisector), so consecutive elements always connect. A loop that
+/// fits one circle becomes a circle.
+struct SectionSketch {
+    typealias Point = SIMD2<Double>
+
+    enum Element: Equatable {
+        case line(from: Point, to: Point)
+        /// Arc around `center`; a positive sweep (radians) runs counter-clockwise
+        case arc(from: Point, to: Point, center: Point, sweep: Double)
+        case circle(center: Point, radius: Double)
+
+        var start: Point {
+            switch self {
+            case .line(let from, _), .arc(let from, _, _, _): return from
+            case .circle(let center, let radius): return center + Point(radius, 0)
+            }
+        }
+    }
+
+    /// One closed outline
+    struct Loop {
+        var elements: [Element]
+        /// Loops enclosing this one: even for material boundaries, odd for holes
+        var depth: Int
+        /// Corner points of the cut outline, for containment and area
+        var outline: [Point]
+
+        var isHole: Bool {
+            depth % 2 == 1
+        }
+    }
+
+    static let defaultTolerance = 0.05
+    /// Largest direction change between neighboring points of an arc (degrees); sharper turns are
+    /// corners, and coarse polygons (hexagons, octagons) stay polygons
+    static let maxArcTurn = 40.0
+
+    /// Normal of the sketch plane; sketch coordinates are the plane's in-plane axes (`Axis.planeAxes`)
+    let axis: SectionProperties.Axis
+    /// Position of the plane along the axis (file coordinates, mm)
+    let position: Double
+    let tolerance: Double
+    /// Outlines, outer loops before the loops they enclose
+    let loops: [Loop]
+    /// Cut segments that did not close into a loop (an open mesh)
+    let openChains: Int
+    /// Number of segments of the cut outline
+    let segmentCount: Int
+
+    var lineCount: Int { count { if case .line = $0 { return true }; return false } }
+    var arcCount: Int { count { if case .arc = $0 { return true }; return false } }
+    var circleCount: Int { count { if case .circle = $0 { return true }; return false } }
+
+    private func count(_ matches: (Element) -> Bool) -> Int {
+        loops.reduce(0) { $0 + $1.elements.filter(matches).count }
+    }
+
+    /// Sketch of the model's cross-section at `axis = position`
+    init(model: STLModel, axis: SectionProperties.Axis, position: Double, tolerance: Double = defaultTolerance) throws {
+        guard model.triangleCount > 0 else { throw SectionSketchError.emptyModel }
+        let (u, v) = (axis.planeAxes.u.index, axis.planeAxes.v.index)
+        let segments = CrossSection.segments(model.triangles, axis: axis.index, value: position).map { a, b in
+            (Point(a.component(axis: u), a.component(axis: v)), Point(b.component(axis: u), b.component(axis: v)))
+        }
+        let chains = Self.loops(of: segments)
+        guard !chains.loops.isEmpty else {
+            throw SectionSketchError.noSection(axis: axis, position: position)
+        }
+
+        self.axis = axis
+        self.position = position
+        self.tolerance = tolerance
+        self.segmentCount = segments.count
+        self.openChains = chains.openChains
+
+        let outlines = chains.loops.map(Self.removingCollinearPoints).filter { $0.count >= 3 }
+        loops = outlines.enumerated()
+            .map { index, outline in
+                let depth = outlines.indices.filter { $0 != index && Self.contains(outlines[$0], outline[0]) }.count
+                return Loop(elements: Self.fit(outline, tolerance: tolerance), depth: depth, outline: outline)
+            }
+            .sorted { $0.depth < $1.depth }
+    }
+
+    // MARK: - Loops
+
+    /// Segments chained into closed loops at shared end points; chains that don't close are counted
+    static func loops(of segments: [(Point, Point)]) -> (loops: [[Point]], openChains: Int) {
+        // Neighboring triangles cut a shared edge at (nearly) the same point
+        let grid = 1e-5
+        var nodes: [SIMD2<Int64>: Int] = [:]
+        var points: [Point] = []
+        func node(_ point: Point) -> Int {
+            let key = SIMD2(Int64((point.x / grid).rounded()), Int64((point.y / grid).rounded()))
+            if let index = nodes[key] { return index }
+            nodes[key] = points.count
+            points.append(point)
+            return points.count - 1
+        }
+
+        var neighbors: [Int: [Int]] = [:]
+        var seen = Set<SIMD2<Int>>()
+        for (a, b) in segments {
+            let (i, j) = (node(a), node(b))
+            guard i != j, seen.insert(SIMD2(min(i, j), max(i, j))).inserted else { continue }
+            neighbors[i, default: []].append(j)
+            neighbors[j, default: []].append(i)
+        }
+
+        var loops: [[Point]] = []
+        var openChains = 0
+        var used = Set<SIMD2<Int>>()
+        for start in neighbors.keys.sorted() {
+            while let first = neighbors[start]!.first(where: { !used.contains(SIMD2(min(start, $0), max(start, $0))) }) {
+                var chain = [start]
+                var current = start
+                var next: Int? = first
+                while let step = next {
+                    used.insert(SIMD2(min(current, step), max(current, step)))
+                    current = step
+                    if current == start { break }
+                    chain.append(current)
+                    next = neighbors[current]!.first { !used.contains(SIMD2(min(current, $0), max(current, $0))) }
+                }
+                if current == start, chain.count >= 3 {
+                    loops.append(chain.map { points[$0] })
+                } else {
+                    openChains += 1
+                }
+            }
+        }
+        return (loops, openChains)
+    }
+
+    /// The loop without points lying on the line between their neighbors (cuts across flat facets)
+    static func removingCollinearPoints(_ loop: [Point]) -> [Point] {
+        var result = loop
+        var index = 0
+        var unchanged = 0
+        while result.count >= 3, unchanged < result.count {
+            let previous = result[(index + result.count - 1) % result.count]
+            let next = result[(index + 1) % result.count]
+            if distance(result[index], toSegment: previous, next) < 1e-6 {
+                result.remove(at: index)
+                unchanged = 0
+                index %= max(result.count, 1)
+            } else {
+                unchanged += 1
+                index = (index + 1) % result.count
+            }
+        }
+        return result
+    }
+
+    // MARK: - Fitting
+
+    /// Lines and arcs within `tolerance` of the corner points of a closed loop
+    static func fit(_ loop: [Point], tolerance: Double) -> [Element] {
+        let count = loop.count
+        guard count >= 3 else { return [] }
+        if let circle = fullCircle(loop, tolerance: tolerance) {
+            return [circle]
+        }
+
+        // Start at the sharpest corner so no element has to wrap around it
+        let turns = loop.indices.map { turn(loop[($0 + count - 1) % count], loop[$0], loop[($0 + 1) % count]) }
+        let start = loop.indices.max { abs(turns[$0]) < abs(turns[$1]) }!
+        let points = Array(loop[start...] + loop[..<start]) + [loop[start]]
+
+        var elements: [(start: Int, end: Int, element: Element)] = []
+        var index = 0
+        while index < count {
+            var lineEnd = index + 1
+            while lineEnd < count, fitsLine(points[index...(lineEnd + 1)], tolerance: tolerance) {
+                lineEnd += 1
+            }
+            var arc: (end: Int, element: Element)?
+            var end = index + 3
+            while end <= count, let element = fitArc(points[index...end], tolerance: tolerance) {
+                arc = (end, element)
+                end += 1
+            }
+
+            if let arc, arc.end > lineEnd {
+                elements.append((index, arc.end, arc.element))
+                index = arc.end
+            } else {
+                elements.append((index, lineEnd, .line(from: points[index], to: points[lineEnd])))
+                index = lineEnd
+            }
+        }
+
+        // The sharpest corner may be a tangent point inside a smooth run; join the run's two halves
+        if elements.count > 2, let first = elements.first, let last = elements.last {
+            let joined = Array(points[last.start...count] + points[1...first.end])[...]
+            if case .line = first.element, case .line = last.element, fitsLine(joined, tolerance: tolerance) {
+                elements[0].element = .line(from: joined.first!, to: joined.last!)
+                elements.removeLast()
+            } else if case .arc = first.element, case .arc = last.element, let arc = fitArc(joined, tolerance: tolerance) {
+                elements[0].element = arc
+                elements.removeLast()
+            }
+        }
+        return elements.map(\.element)
+    }
+
+    /// The loop as one circle, nil if it isn't round within the tolerance
+    static func fullCircle(_ loop: [Point], tolerance: Double) -> Element? {
+        let count = loop.count
+        guard count >= 6 else { return nil }
+        let turns = loop.indices.map { turn(loop[($0 + count - 1) % count], loop[$0], loop[($0 + 1) % count]) }
+        guard turnsFormArc(turns[...]), let circle = fitCircle(loop[...]),
+              loop.allSatisfy({ abs(simd_distance($0, circle.center) - circle.radius) <= tolerance }) else { return nil }
+        return .circle(center: circle.center, radius: circle.radius)
+    }
+
+    /// Whether all points lie within the tolerance of the segment between the first and last one
+    static func fitsLine(_ points: ArraySlice<Point>, tolerance: Double) -> Bool {
+        let (a, b) = (points.first!, points.last!)
+        return points.dropFirst().dropLast().allSatisfy { distance($0, toSegment: a, b) <= tolerance }
+    }
+
+    /// Arc from the first to the last point through all others within the tolerance, nil if there is none
+    static func fitArc(_ points: ArraySlice<Point>, tolerance: Double) -> Element? {
+        guard points.count >= 4 else { return nil }
+        let array = Array(points)
+        let turns = (1..<(array.count - 1)).map { turn(array[$0 - 1], array[$0], array[$0 + 1]) }
+        guard turnsFormArc(turns[...]), let fitted = fitCircle(points) else { return nil }
+
+        // Move the center onto the chord's bisector so the arc ends exactly at the points
+        let (from, to) = (array.first!, array.last!)
+        let middle = (from + to) / 2
+        let chord = to - from
+        guard simd_length(chord) > 0 else { return nil }
+        let bisector = simd_normalize(Point(-chord.y, chord.x))
+        let center = middle + bisector * simd_dot(fitted.center - middle, bisector)
+        let radius = simd_distance(from, center)
+        guard array.allSatisfy({ abs(simd_distance($0, center) - radius) <= tolerance }) else { return nil }
+
+        let counterClockwise = turns[0] > 0
+        var sweep = angle(of: to - center) - angle(of: from - center)
+        if counterClockwise {
+            while sweep <= 0 { sweep += 2 * .pi }
+        } else {
+            while sweep >= 0 { sweep -= 2 * .pi }
+        }
+        // Points that wrap past the start are no arc of this circle
+        let turned = turns.reduce(0, +)
+        guard abs(turned) < 2 * .pi, abs(turned) <= abs(sweep) + .pi / 2 else { return nil }
+        return .arc(from: from, to: to, center: center, sweep: sweep)
+    }
+
+    /// Whether direction changes along a polyline bend one way, gently enough for an arc
+    private static func turnsFormArc(_ turns: ArraySlice<Double>) -> Bool {
+        let limit = maxArcTurn * .pi / 180
+        return turns.allSatisfy { $0 > 0 && $0 < limit } || turns.allSatisfy { $0 < 0 && $0 > -limit }
+    }
+
+    /// Least-squares (algebraic) circle through points, nil for collinear points
+    static func fitCircle(_ points: ArraySlice<Point>) -> (center: Point, radius: Double)? {
+        guard points.count >= 3 else { return nil }
+        // Minimize Σ (x² + y² + D x + E y + F)² around the centroid for numerical stability
+        let mean = points.reduce(Point.zero, +) / Double(points.count)
+        var matrix = simd_double3x3()
+        var right = SIMD3<Double>.zero
+        for point in points {
+            let d = point - mean
+            let row = SIMD3(d.x, d.y, 1)
+            matrix += simd_double3x3(columns: (row * row.x, row * row.y, row * row.z))
+            right -= row * simd_length_squared(d)
+        }
+        guard abs(matrix.determinant) > 1e-12 else { return nil }
+        let solution = matrix.inverse * right
+        let center = Point(-solution.x / 2, -solution.y / 2)
+        let squaredRadius = simd_length_squared(center) - solution.z
+        guard squaredRadius > 0 else { return nil }
+        return (mean + center, squaredRadius.squareRoot())
+    }
+
+    // MARK: - Geometry
+
+    /// Signed direction change at `b` (radians, positive turning left)
+    private static func turn(_ a: Point, _ b: Point, _ c: Point) -> Double {
+        let (first, second) = (b - a, c - b)
+        return atan2(first.x * second.y - first.y * second.x, simd_dot(first, second))
+    }
+
+    private static func angle(of vector: Point) -> Double {
+        atan2(vector.y, vector.x)
+    }
+
+    private static func distance(_ point: Point, toSegment a: Point, _ b: Point) -> Double {
+        let direction = b - a
+        let lengthSquared = simd_length_squared(direction)
+        guard lengthSquared > 0 else { return simd_distance(point, a) }
+        let t = min(max(simd_dot(point - a, direction) / lengthSquared, 0), 1)
+        return simd_distance(point, a + direction * t)
+    }
+
+    /// Even-odd point in polygon test
+    static func contains(_ polygon: [Point], _ point: Point) -> Bool {
+        var inside = false
+        var j = polygon.count - 1
+        for i in polygon.indices {
+            let (a, b) = (polygon[i], polygon[j])
+            if (a.y > point.y) != (b.y > point.y),
+               point.x < (b.x - a.x) * (point.y - a.y) / (b.y - a.y) + a.x {
+                inside.toggle()
+            }
+            j = i
+        }
+        return inside
+    }
+}
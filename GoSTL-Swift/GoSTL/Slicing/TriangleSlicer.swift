@@ -331,7 +331,7 @@ final class TriangleSlicer {
             let intersect2 = intersectEdgeWithPlaneFast(v_keep, v_discard2, axis: axis, planePosition: planePosition)
 
             // Create new triangle (preserve original normal for consistent coloring)
-            let newTriangle = Triangle(v1: v_keep, v2: intersect1, v3: intersect2, normal: triangle.normal)
+            let newTriangle = Triangle(v1: v_keep, v2: intersect1, v3: intersect2, normal: triangle.normal, color: triangle.color)
             let cutEdge = CutEdge(start: intersect1, end: intersect2, axis: axis)
 
             return SlicedTriangles(triangles: [newTriangle], cutEdges: [cutEdge])
@@ -352,8 +352,8 @@ final class TriangleSlicer {
             let intersect2 = intersectEdgeWithPlaneFast(v_keep2, v_discard, axis: axis, planePosition: planePosition)
 
             // Create two triangles forming a quad (preserve original normal for consistent coloring)
-            let tri1 = Triangle(v1: v_keep1, v2: v_keep2, v3: intersect1, normal: triangle.normal)
-            let tri2 = Triangle(v1: v_keep2, v2: intersect2, v3: intersect1, normal: triangle.normal)
+            let tri1 = Triangle(v1: v_keep1, v2: v_keep2, v3: intersect1, normal: triangle.normal, color: triangle.color)
+            let tri2 = Triangle(v1: v_keep2, v2: intersect2, v3: intersect1, normal: triangle.normal, color: triangle.color)
             let cutEdge = CutEdge(start: intersect1, end: intersect2, axis: axis)
 
             return SlicedTriangles(triangles: [tri1, tri2], cutEdges: [cutEdge])
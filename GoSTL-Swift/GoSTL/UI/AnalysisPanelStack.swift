@@ -0,0 +1,95 @@
+import SwiftUI
+import Metal
+
+/// Analysis and tool panels stacked in the bottom-right corner of the 3D view, or in their own
+/// window when detached
+struct AnalysisPanelStack: View {
+    let appState: AppState
+
+    var body: some View {
+        VStack(alignment: .trailing, spacing: 8) {
+            if let finding = appState.scaleCheck {
+                ScaleCheckPanel(appState: appState, finding: finding)
+            }
+            if let comparison = appState.measurementComparison {
+                MeasurementComparisonPanel(
+                    comparison: comparison,
+                    onClose: { appState.measurementComparison = nil }
+                )
+            }
+            if let analysis = appState.pluginAnalysis {
+                PluginResultsPanel(
+                    analysis: analysis,
+                    onClose: { appState.closePluginAnalysis() }
+                )
+            }
+            if appState.clearanceModelName != nil {
+                ClearancePanel(
+                    appState: appState,
+                    onClose: { appState.removeClearanceModel() }
+                )
+            }
+            if appState.showDraftAnalysis {
+                DraftPanel(
+                    appState: appState,
+                    onClose: {
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        appState.setDraftAnalysis(false, device: device)
+                    }
+                )
+            }
+            if appState.isProbingThickness {
+                ThicknessProbePanel(appState: appState)
+            }
+            if appState.isMeasuringFaceDistance {
+                FaceDistancePanel(appState: appState)
+            }
+            if let run = appState.templateRun {
+                TemplateRunPanel(appState: appState, run: run)
+            }
+            if appState.showCalibration {
+                CalibrationPanel(appState: appState)
+            }
+            if let caliper = appState.caliper {
+                CaliperPanel(appState: appState, caliper: caliper)
+            }
+            if appState.showPrimitives {
+                PrimitivesPanel(
+                    appState: appState,
+                    onClose: {
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        appState.setPrimitives(false, device: device)
+                    }
+                )
+            }
+            if appState.showSectionBookmarks {
+                SectionBookmarksPanel(
+                    appState: appState,
+                    onClose: { appState.showSectionBookmarks = false }
+                )
+            }
+            if appState.showMetadataPanel {
+                MetadataPanel(
+                    appState: appState,
+                    onClose: { appState.showMetadataPanel = false }
+                )
+            }
+            if appState.showReferencePanel {
+                ReferenceGeometryPanel(
+                    appState: appState,
+                    onClose: {
+                        appState.referenceGeometry.cancelTool()
+                        appState.showReferencePanel = false
+                    }
+                )
+            }
+            if appState.showClippingPanel {
+                ClippingPlanesPanel(
+                    camera: appState.camera,
+                    maxCutawayDepth: appState.maxCutawayDepth,
+                    onClose: { appState.showClippingPanel = false }
+                )
+            }
+        }
+    }
+}
@@ -0,0 +1,127 @@
+import SwiftUI
+
+/// Overlay that shows pinned annotations: a pin at the anchor and a leader line to the text label
+///
+/// Labels face the screen at any camera angle, sit above their pin and give way to each other and
+/// to the measurement labels; annotations whose labels would overlap are merged and counted.
+struct AnnotationLabelsOverlay: View {
+    let appState: AppState
+    let viewSize: CGSize
+
+    /// Keeps label placements stable across frames
+    @State private var layout = LabelLayout()
+
+    /// Distance of the preferred label position above the pin
+    private static let labelOffset: CGFloat = 28
+
+    static let color = Color(red: 0.95, green: 0.35, blue: 0.3)
+
+    var body: some View {
+        GeometryReader { geometry in
+            let annotations = appState.showAnnotations ? appState.annotations : []
+            let pins = annotations.map { annotation in
+                appState.camera.project(
+                    worldPosition: annotation.renderPosition(coordinateOffset: appState.coordinateOffset),
+                    viewSize: viewSize
+                )
+            }
+            let placements = layout.layout(
+                labelItems(annotations, pins: pins),
+                in: CGRect(origin: .zero, size: viewSize),
+                avoiding: Array(appState.measurementSystem.labelFrames.values)
+            )
+            let _ = publishFrames(placements, annotations: annotations)
+
+            ZStack {
+                // Leader lines from the pins to their labels (also to the label an annotation was merged into)
+                ForEach(placements, id: \.id) { placement in
+                    ForEach([placement.id] + placement.clustered, id: \.self) { index in
+                        if let pin = pins[index] {
+                            Path { path in
+                                path.move(to: pin)
+                                path.addLine(to: leaderEnd(from: pin, to: placement.frame))
+                            }
+                            .stroke(Self.color.opacity(0.9), lineWidth: 1.5)
+                        }
+                    }
+                }
+
+                ForEach(Array(pins.enumerated()), id: \.offset) { _, pin in
+                    if let pin {
+                        Circle()
+                            .fill(Self.color)
+                            .overlay(Circle().stroke(Color.white, lineWidth: 1))
+                            .frame(width: 7, height: 7)
+                            .position(pin)
+                    }
+                }
+
+                ForEach(placements, id: \.id) { placement in
+                    AnnotationLabel(
+                        text: annotations[placement.id].text,
+                        position: placement.center,
+                        clusterCount: placement.clustered.count
+                    )
+                }
+            }
+            .frame(width: geometry.size.width, height: geometry.size.height)
+            .allowsHitTesting(false)
+        }
+    }
+
+    /// Layout items for the annotations in front of the camera, in the order they were added
+    private func labelItems(_ annotations: [Annotation], pins: [CGPoint?]) -> [LabelLayout.Item] {
+        annotations.indices.compactMap { index in
+            guard let pin = pins[index] else { return nil }
+            return LabelLayout.Item(
+                id: index,
+                anchor: CGPoint(x: pin.x, y: pin.y - Self.labelOffset),
+                size: LabelLayout.estimatedSize(of: annotations[index].text),
+                priority: 0
+            )
+        }
+    }
+
+    /// Hand the label frames to the input handler for the context menu
+    private func publishFrames(_ placements: [LabelLayout.Placement], annotations: [Annotation]) {
+        appState.annotationLabelFrames = Dictionary(uniqueKeysWithValues: placements.map { (annotations[$0.id].id, $0.frame) })
+        appState.annotationLabelFramesViewSize = viewSize
+    }
+
+    /// Point on the label's border closest to the pin
+    private func leaderEnd(from pin: CGPoint, to frame: CGRect) -> CGPoint {
+        CGPoint(x: min(max(pin.x, frame.minX), frame.maxX), y: min(max(pin.y, frame.minY), frame.maxY))
+    }
+}
+
+/// Text of an annotation at a specific screen position
+private struct AnnotationLabel: View {
+    let text: String
+    let position: CGPoint
+    /// Number of nearby annotations merged into this one
+    var clusterCount: Int = 0
+
+    var body: some View {
+        HStack(spacing: 4) {
+            Text(text)
+            if clusterCount > 0 {
+                Text("+\(clusterCount)")
+                    .font(.system(size: 10, weight: .bold, design: .monospaced))
+                    .padding(.horizontal, 3)
+                    .background(Capsule().fill(Color.black.opacity(0.35)))
+            }
+        }
+        .font(.system(size: 12, weight: .medium, design: .monospaced))
+        .foregroundColor(.white)
+        .lineLimit(1)
+        .padding(.horizontal, 6)
+        .padding(.vertical, 3)
+        .background(
+            RoundedRectangle(cornerRadius: 4)
+                .fill(AnnotationLabelsOverlay.color.opacity(0.9))
+                .shadow(color: .black.opacity(0.5), radius: 2, x: 0, y: 1)
+        )
+        .fixedSize()
+        .position(position)
+    }
+}
@@ -0,0 +1,73 @@
+import SwiftUI
+import Metal
+
+/// Model picker for .zip archives with several models
+/// Displayed at the bottom center of the screen
+struct ArchiveSelector: View {
+    let appState: AppState
+
+    private var entries: [ModelArchive.Entry] {
+        appState.modelArchive?.entries ?? []
+    }
+
+    var body: some View {
+        HStack(spacing: 8) {
+            Image(systemName: "doc.zipper")
+                .font(.system(size: 12))
+                .foregroundColor(.white.opacity(0.8))
+
+            Menu {
+                Button("All Models (Assembly)") { select(nil) }
+
+                Divider()
+
+                ForEach(entries) { entry in
+                    Button(entry.path) { select(entry.path) }
+                }
+            } label: {
+                HStack(spacing: 4) {
+                    Text(selectionTitle)
+                        .font(.system(size: 11, weight: .semibold))
+                        .foregroundColor(.white)
+                    Image(systemName: "chevron.down")
+                        .font(.system(size: 8))
+                        .foregroundColor(.white.opacity(0.6))
+                }
+                .padding(.horizontal, 12)
+                .padding(.vertical, 6)
+                .background(
+                    RoundedRectangle(cornerRadius: 8)
+                        .fill(Color.purple.opacity(0.5))
+                )
+            }
+            .menuStyle(.borderlessButton)
+            .fixedSize()
+
+            Text("\(entries.count) models")
+                .font(.system(size: 10))
+                .foregroundColor(.white.opacity(0.6))
+        }
+        .padding(.horizontal, 12)
+        .padding(.vertical, 8)
+        .background(
+            RoundedRectangle(cornerRadius: 12)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+    }
+
+    private var selectionTitle: String {
+        guard let path = appState.selectedArchiveEntry else { return "All Models" }
+        return entries.first { $0.path == path }?.path ?? path
+    }
+
+    private func select(_ path: String?) {
+        guard let device = MTLCreateSystemDefaultDevice() else { return }
+        do {
+            try appState.selectArchiveEntry(path, device: device)
+        } catch {
+            appState.loadError = error
+            appState.loadErrorID = UUID()
+        }
+    }
+}
@@ -0,0 +1,43 @@
+import SwiftUI
+
+/// Small status pill listing background jobs (spatial index, wireframe, analysis, exports)
+struct BackgroundJobsIndicator: View {
+    let jobs: [JobQueue.Job]
+    let onCancel: (JobQueue.Job) -> Void
+
+    var body: some View {
+        HStack(spacing: 8) {
+            ProgressView()
+                .progressViewStyle(CircularProgressViewStyle())
+                .scaleEffect(0.8)
+            VStack(alignment: .leading, spacing: 2) {
+                ForEach(jobs) { job in
+                    HStack(spacing: 6) {
+                        Text(job.message ?? job.title)
+                            .font(.caption)
+                            .foregroundColor(.secondary)
+                        if let progress = job.progress {
+                            Text("\(Int(progress * 100))%")
+                                .font(.caption.monospacedDigit())
+                                .foregroundColor(.secondary)
+                        }
+                        if job.kind.isCancellable {
+                            Button {
+                                onCancel(job)
+                            } label: {
+                                Image(systemName: "xmark.circle.fill")
+                                    .font(.caption)
+                            }
+                            .buttonStyle(.plain)
+                            .foregroundColor(.secondary)
+                            .help("Cancel")
+                        }
+                    }
+                }
+            }
+        }
+        .padding(.horizontal, 12)
+        .padding(.vertical, 8)
+        .background(.ultraThinMaterial, in: RoundedRectangle(cornerRadius: 8))
+    }
+}
@@ -0,0 +1,156 @@
+import SwiftUI
+
+/// Guided check of a scanned calibration artifact: pick the artifact, orient the scan, measure and
+/// read off the scale error and slicer compensation per axis
+struct CalibrationPanel: View {
+    let appState: AppState
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("CALIBRATION")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: { appState.setCalibration(false) }) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Close the calibration check")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            step(1, "Select the printed artifact")
+            Picker("", selection: Binding(
+                get: { appState.calibrationArtifact.id },
+                set: { id in
+                    appState.calibrationArtifact = CalibrationArtifact.builtIn.first { $0.id == id }
+                        ?? .custom(size: appState.calibrationArtifact.size)
+                }
+            )) {
+                ForEach(CalibrationArtifact.builtIn) { artifact in
+                    Text(artifact.name).tag(artifact.id)
+                }
+                Text("Custom Block").tag(CalibrationArtifact.customID)
+            }
+            .labelsHidden()
+            .controlSize(.small)
+
+            if appState.calibrationArtifact.isCustom {
+                HStack(spacing: 4) {
+                    sizeField(\.x)
+                    Text("×").foregroundColor(.white.opacity(0.5))
+                    sizeField(\.y)
+                    Text("×").foregroundColor(.white.opacity(0.5))
+                    sizeField(\.z)
+                    Text("mm")
+                        .font(.system(size: 10))
+                        .foregroundColor(.white.opacity(0.5))
+                }
+            }
+
+            Text(appState.calibrationArtifact.note)
+                .font(.system(size: 10))
+                .foregroundColor(.white.opacity(0.6))
+                .fixedSize(horizontal: false, vertical: true)
+
+            step(2, "Orient the scan as printed: X and Y on the bed, Z up")
+            step(3, "Measure the scan")
+
+            Button("Measure") {
+                appState.measureCalibration()
+            }
+            .controlSize(.small)
+            .disabled(appState.model == nil)
+
+            if let report = appState.calibrationReport {
+                Divider()
+                    .background(Color.white.opacity(0.3))
+
+                reportTable(report)
+
+                Text(String(format: "XY compensation (one setting): %.2f %%", report.xyCompensation))
+                    .font(.system(size: 10, design: .monospaced))
+                    .foregroundColor(.white.opacity(0.8))
+
+                ForEach(report.warnings, id: \.self) { warning in
+                    Label(warning, systemImage: "exclamationmark.triangle")
+                        .font(.system(size: 10))
+                        .foregroundColor(.orange)
+                        .fixedSize(horizontal: false, vertical: true)
+                }
+
+                HStack {
+                    Spacer()
+                    Button("Copy Report") {
+                        copy(report.text)
+                    }
+                    .controlSize(.small)
+                }
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 320)
+    }
+
+    private func step(_ number: Int, _ text: String) -> some View {
+        Text("\(number). \(text)")
+            .font(.system(size: 11))
+            .foregroundColor(.white.opacity(0.9))
+    }
+
+    private func sizeField(_ component: WritableKeyPath<Vector3, Double>) -> some View {
+        TextField("0", value: Binding(
+            get: { appState.calibrationArtifact.size[keyPath: component] },
+            set: { appState.calibrationArtifact.size[keyPath: component] = $0 }
+        ), format: .number)
+        .textFieldStyle(.roundedBorder)
+        .font(.system(size: 10, design: .monospaced))
+        .frame(width: 60)
+    }
+
+    private func reportTable(_ report: CalibrationReport) -> some View {
+        Grid(alignment: .trailing, horizontalSpacing: 10, verticalSpacing: 3) {
+            GridRow {
+                Text("Axis").gridColumnAlignment(.leading)
+                Text("Nominal")
+                Text("Measured")
+                Text("Error")
+                Text("Scale")
+            }
+            .font(.system(size: 9, weight: .semibold))
+            .foregroundColor(.white.opacity(0.6))
+
+            ForEach(report.axes, id: \.name) { axis in
+                GridRow {
+                    Text(axis.name)
+                    Text(String(format: "%.2f", axis.nominal))
+                    Text(String(format: "%.3f", axis.measured))
+                    Text(String(format: "%+.2f %%", axis.errorPercent))
+                        .foregroundColor(abs(axis.errorPercent) > CalibrationReport.plausibleErrorPercent ? .orange : .white)
+                    Text(String(format: "%.2f %%", axis.compensation))
+                        .textSelection(.enabled)
+                }
+                .font(.system(size: 10, design: .monospaced))
+                .foregroundColor(.white)
+            }
+        }
+    }
+
+    private func copy(_ text: String) {
+        let pasteboard = NSPasteboard.general
+        pasteboard.clearContents()
+        pasteboard.setString(text, forType: .string)
+    }
+}
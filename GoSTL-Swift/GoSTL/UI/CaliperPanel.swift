@@ -0,0 +1,152 @@
+import SwiftUI
+import Metal
+
+/// Panel of the virtual caliper: direction, jaw positions and the distance between the seated jaws
+struct CaliperPanel: View {
+    let appState: AppState
+    let caliper: Caliper
+
+    private var precision: DisplayPrecision {
+        appState.displayPrecision
+    }
+
+    /// Render space positions along the direction plus this are file coordinates
+    private var offset: Double {
+        appState.coordinateOffset.dot(caliper.direction.vector)
+    }
+
+    private var isAcrossFace: Bool {
+        if case .normal = caliper.direction { return true }
+        return false
+    }
+
+    /// Slider range: the model with some room to open the jaws past it
+    private var range: ClosedRange<Double> {
+        let margin = max((caliper.extent.upperBound - caliper.extent.lowerBound) * 0.05, 1)
+        return (caliper.extent.lowerBound - margin)...(caliper.extent.upperBound + margin)
+    }
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("CALIPER")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: { appState.hideCaliper() }) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Hide the caliper")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            HStack(spacing: 6) {
+                Text("Across")
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.8))
+                Spacer()
+                ForEach([Caliper.Direction.x, .y, .z], id: \.displayName) { direction in
+                    directionButton(direction.displayName, isSelected: caliper.direction == direction) {
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        appState.showCaliper(direction, device: device)
+                    }
+                }
+                directionButton("Face", isSelected: isAcrossFace) {
+                    guard let device = MTLCreateSystemDefaultDevice() else { return }
+                    appState.showCaliperAlongSelectedFace(device: device)
+                }
+                .disabled(appState.measurementSystem.selectedTriangles.isEmpty)
+                .help("Jaws parallel to the selected triangles")
+            }
+
+            jawSlider("Jaw 1", value: caliper.opening.lowerBound) { position in
+                min(position, caliper.opening.upperBound)...caliper.opening.upperBound
+            }
+            jawSlider("Jaw 2", value: caliper.opening.upperBound) { position in
+                caliper.opening.lowerBound...max(position, caliper.opening.lowerBound)
+            }
+
+            if let reading = appState.caliperReading {
+                HStack {
+                    Text(precision.lengthWithUnit(reading.distance))
+                        .font(.system(size: 18, weight: .semibold, design: .monospaced))
+                        .foregroundColor(.white)
+                        .textSelection(.enabled)
+                    Spacer()
+                    Text("\(precision.length(reading.low + offset)) – \(precision.length(reading.high + offset))")
+                        .font(.system(size: 10, design: .monospaced))
+                        .foregroundColor(.white.opacity(0.7))
+                        .help("Seated jaw positions along the direction")
+                }
+
+                HStack(spacing: 8) {
+                    Button("Open Jaws") {
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        appState.setCaliperOpening(caliper.extent, device: device)
+                    }
+                    .controlSize(.small)
+
+                    Button("Add as Measurement") {
+                        appState.addCaliperMeasurement()
+                    }
+                    .controlSize(.small)
+                }
+            } else {
+                Text("No surface between the jaws")
+                    .font(.system(size: 11))
+                    .foregroundColor(.orange)
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+
+    private func directionButton(_ title: String, isSelected: Bool, action: @escaping () -> Void) -> some View {
+        Button(action: action) {
+            Text(title)
+                .font(.system(size: 10, weight: isSelected ? .semibold : .regular))
+                .foregroundColor(isSelected ? .white : .white.opacity(0.6))
+                .padding(.horizontal, 6)
+                .padding(.vertical, 2)
+                .background(RoundedRectangle(cornerRadius: 4).fill(isSelected ? Color.blue.opacity(0.6) : Color.white.opacity(0.1)))
+        }
+        .buttonStyle(.plain)
+    }
+
+    /// Slider for one open jaw; the seated jaw closes onto the model from there
+    private func jawSlider(_ name: String, value: Double, opening: @escaping (Double) -> ClosedRange<Double>) -> some View {
+        HStack(spacing: 6) {
+            Text(name)
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.8))
+                .frame(width: 40, alignment: .leading)
+            Slider(
+                value: Binding(
+                    get: { value },
+                    set: { position in
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        appState.setCaliperOpening(opening(position), device: device)
+                    }
+                ),
+                in: range
+            )
+            .tint(.orange)
+            Text(precision.length(value + offset))
+                .font(.system(size: 10, design: .monospaced))
+                .foregroundColor(.white.opacity(0.7))
+                .frame(width: 60, alignment: .trailing)
+        }
+    }
+}
@@ -0,0 +1,121 @@
+import SwiftUI
+
+/// Panel showing the collision and clearance check between the model and a second model
+struct ClearancePanel: View {
+    let appState: AppState
+    let onClose: () -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("CLEARANCE")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Remove the clearance model")
+            }
+
+            if let name = appState.clearanceModelName {
+                HStack(spacing: 6) {
+                    RoundedRectangle(cornerRadius: 2)
+                        .fill(Color(
+                            red: Double(ClearanceRenderData.otherColor.r),
+                            green: Double(ClearanceRenderData.otherColor.g),
+                            blue: Double(ClearanceRenderData.otherColor.b)
+                        ))
+                        .frame(width: 10, height: 10)
+                    Text(name)
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.8))
+                        .lineLimit(1)
+                        .truncationMode(.middle)
+                }
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            if let error = appState.clearanceError {
+                Text(error)
+                    .font(.system(size: 10))
+                    .foregroundColor(.red.opacity(0.9))
+                    .fixedSize(horizontal: false, vertical: true)
+            } else if let result = appState.clearanceResult {
+                resultView(result)
+            } else {
+                HStack(spacing: 6) {
+                    ProgressView()
+                        .controlSize(.small)
+                    Text(appState.clearanceModel == nil ? "Loading model..." : "Checking clearance...")
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.8))
+                }
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+
+    @ViewBuilder
+    private func resultView(_ result: ClearanceResult) -> some View {
+        if result.isIntersecting {
+            Text("Models intersect")
+                .font(.system(size: 12, weight: .semibold))
+                .foregroundColor(.red)
+            row("Triangles", "\(result.intersectingTriangles.count) + \(result.otherIntersectingTriangles.count)")
+            if let model = appState.model, let bounds = result.intersectionBounds(in: model) {
+                row("Region", String(format: "%.2f × %.2f × %.2f mm", bounds.size.x, bounds.size.y, bounds.size.z))
+                row("At", format(appState.originalPosition(bounds.center)))
+            }
+        } else {
+            Text(result.minimumDistance > 0 ? "No collision" : "Models touch")
+                .font(.system(size: 12, weight: .semibold))
+                .foregroundColor(result.minimumDistance > 0 ? .green : .orange)
+            row("Min clearance", String(format: "%.3f mm", result.minimumDistance))
+            row("On model", format(appState.originalPosition(result.pointOnModel)))
+            row("On other", format(appState.originalPosition(result.pointOnOther)))
+        }
+
+        HStack(spacing: 8) {
+            Button(result.isIntersecting ? "Show Region" : "Measure") {
+                if result.isIntersecting {
+                    appState.focusClearance()
+                } else {
+                    appState.measureClearance()
+                }
+            }
+            .controlSize(.small)
+        }
+        .padding(.top, 2)
+    }
+
+    private func row(_ name: String, _ value: String) -> some View {
+        HStack(spacing: 6) {
+            Text(name)
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.8))
+            Spacer()
+            Text(value)
+                .font(.system(size: 11, design: .monospaced))
+                .foregroundColor(.white)
+                .textSelection(.enabled)
+        }
+    }
+
+    private func format(_ point: Vector3) -> String {
+        String(format: "%.2f, %.2f, %.2f", point.x, point.y, point.z)
+    }
+}
@@ -0,0 +1,156 @@
+import SwiftUI
+
+/// Panel for the camera near/far clipping planes and the cutaway that follows the camera
+/// Near/far sliders work on a logarithmic scale since useful values span several orders of magnitude
+struct ClippingPlanesPanel: View {
+    let camera: Camera
+    /// Upper end of the cutaway depth slider
+    let maxCutawayDepth: Double
+    let onClose: () -> Void
+
+    /// Slider range as powers of ten (0.0001 ... 100000)
+    private let exponentRange: ClosedRange<Double> = -4...5
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("CLIPPING PLANES")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            Toggle("Fit to model automatically", isOn: Binding(
+                get: { camera.autoDepthRange },
+                set: { camera.autoDepthRange = $0 }
+            ))
+            .font(.system(size: 11))
+            .foregroundColor(.white.opacity(0.9))
+
+            PlaneSliderRow(
+                label: "Near",
+                value: Binding(
+                    get: { log10(Double(camera.nearPlane)) },
+                    set: { camera.setDepthRange(near: Float(pow(10, $0)), far: camera.farPlane) }
+                ),
+                displayValue: camera.nearPlane,
+                range: exponentRange
+            )
+
+            PlaneSliderRow(
+                label: "Far",
+                value: Binding(
+                    get: { log10(Double(camera.farPlane)) },
+                    set: { camera.setDepthRange(near: camera.nearPlane, far: Float(pow(10, $0))) }
+                ),
+                displayValue: camera.farPlane,
+                range: exponentRange
+            )
+
+            HStack(spacing: 4) {
+                Text("Ratio")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.7))
+                Spacer()
+                Text(String(format: "1 : %.0f", camera.depthRatio))
+                    .font(.system(size: 10, design: .monospaced))
+                    .foregroundColor(camera.hasDepthPrecisionWarning ? .orange : .white.opacity(0.8))
+            }
+
+            if camera.hasDepthPrecisionWarning {
+                HStack(alignment: .top, spacing: 6) {
+                    Image(systemName: "exclamationmark.triangle.fill")
+                        .foregroundColor(.orange)
+                        .font(.system(size: 10))
+                    Text("Far/near ratio is very large. Expect z-fighting; raise the near plane or enable automatic fitting.")
+                        .font(.system(size: 9))
+                        .foregroundColor(.white.opacity(0.8))
+                        .fixedSize(horizontal: false, vertical: true)
+                }
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.2))
+
+            Toggle("Cutaway follows camera", isOn: Binding(
+                get: { camera.cutawayDepth != nil },
+                set: { camera.cutawayDepth = $0 ? Float(camera.distance) : nil }
+            ))
+            .font(.system(size: 11))
+            .foregroundColor(.white.opacity(0.9))
+
+            if let depth = camera.cutawayDepth {
+                HStack(spacing: 8) {
+                    Text("Depth")
+                        .font(.system(size: 10))
+                        .foregroundColor(.white.opacity(0.7))
+                        .frame(width: 30, alignment: .leading)
+
+                    Slider(
+                        value: Binding(
+                            get: { Double(depth) },
+                            set: { camera.cutawayDepth = Float($0) }
+                        ),
+                        in: 0...max(maxCutawayDepth, Double(depth), 1)
+                    )
+
+                    Text(String(format: "%.1f mm", depth))
+                        .font(.system(size: 10, design: .monospaced))
+                        .foregroundColor(.white.opacity(0.8))
+                        .frame(width: 60, alignment: .trailing)
+                }
+
+                Text("Distance of the cut ahead of the camera · [ ] adjust")
+                    .font(.system(size: 9))
+                    .foregroundColor(.white.opacity(0.5))
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+}
+
+// MARK: - Helper Views
+
+private struct PlaneSliderRow: View {
+    let label: String
+    @Binding var value: Double
+    let displayValue: Float
+    let range: ClosedRange<Double>
+
+    var body: some View {
+        HStack(spacing: 8) {
+            Text(label)
+                .font(.system(size: 10))
+                .foregroundColor(.white.opacity(0.7))
+                .frame(width: 30, alignment: .leading)
+
+            Slider(value: $value, in: range)
+
+            Text(formatDistance(displayValue))
+                .font(.system(size: 10, design: .monospaced))
+                .foregroundColor(.white.opacity(0.8))
+                .frame(width: 60, alignment: .trailing)
+        }
+    }
+
+    private func formatDistance(_ value: Float) -> String {
+        value < 1 ? String(format: "%.4f", value) : String(format: "%.1f", value)
+    }
+}
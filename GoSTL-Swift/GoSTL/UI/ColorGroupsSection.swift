@@ -0,0 +1,77 @@
+import SwiftUI
+import Metal
+
+/// Main menu section listing the color() groups of a rendered OpenSCAD file with per-group visibility
+struct ColorGroupsSectionContent: View {
+    let appState: AppState
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 4) {
+            ForEach(appState.colorGroups) { group in
+                ColorGroupRow(
+                    group: group,
+                    isVisible: !appState.hiddenColorGroups.contains(group.id),
+                    action: { toggle(group) }
+                )
+            }
+
+            if !appState.hiddenColorGroups.isEmpty {
+                Button(action: showAll) {
+                    Text("Show All")
+                        .font(.system(size: 10))
+                        .foregroundColor(.white.opacity(0.8))
+                }
+                .buttonStyle(.plain)
+                .padding(.top, 2)
+            }
+        }
+    }
+
+    private func toggle(_ group: ColorGroup) {
+        guard let device = MTLCreateSystemDefaultDevice() else { return }
+        let isVisible = !appState.hiddenColorGroups.contains(group.id)
+        try? appState.setColorGroup(group.id, visible: !isVisible, device: device)
+    }
+
+    private func showAll() {
+        guard let device = MTLCreateSystemDefaultDevice() else { return }
+        try? appState.showAllColorGroups(device: device)
+    }
+}
+
+/// Checkbox row with a color swatch and triangle count
+struct ColorGroupRow: View {
+    let group: ColorGroup
+    let isVisible: Bool
+    let action: () -> Void
+
+    private var swatch: Color {
+        guard let color = group.color else { return .gray }
+        return Color(red: Double(color.r), green: Double(color.g), blue: Double(color.b))
+    }
+
+    var body: some View {
+        Button(action: action) {
+            HStack(spacing: 4) {
+                Image(systemName: isVisible ? "checkmark.square.fill" : "square")
+                    .font(.system(size: 10))
+                    .foregroundColor(isVisible ? .orange : .white.opacity(0.5))
+                RoundedRectangle(cornerRadius: 2)
+                    .fill(swatch)
+                    .frame(width: 10, height: 10)
+                    .overlay(
+                        RoundedRectangle(cornerRadius: 2)
+                            .stroke(Color.white.opacity(0.4), lineWidth: 0.5)
+                    )
+                Text(group.name)
+                    .font(.system(size: 10, design: .monospaced))
+                    .foregroundColor(.white.opacity(isVisible ? 0.8 : 0.5))
+                Spacer()
+                Text("\(group.triangleCount) tri")
+                    .font(.system(size: 9))
+                    .foregroundColor(.white.opacity(0.5))
+            }
+        }
+        .buttonStyle(.plain)
+    }
+}
@@ -0,0 +1,95 @@
+import SwiftUI
+import Metal
+
+/// Right half of the split view: the compared model through the main camera, with its name and registration
+struct ComparisonPane: View {
+    let appState: AppState
+
+    var body: some View {
+        ZStack(alignment: .topLeading) {
+            ComparisonMetalView(appState: appState)
+                .frame(maxWidth: .infinity, maxHeight: .infinity)
+                .clipped()
+
+            header
+                .padding(12)
+        }
+    }
+
+    private var header: some View {
+        VStack(alignment: .leading, spacing: 6) {
+            HStack(spacing: 8) {
+                Text("COMPARISON")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: { appState.closeComparison() }) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Close the comparison")
+            }
+
+            if let name = appState.comparisonModelName {
+                Text(name)
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.8))
+                    .lineLimit(1)
+                    .truncationMode(.middle)
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            if let error = appState.comparisonError {
+                Text(error)
+                    .font(.system(size: 10))
+                    .foregroundColor(.red.opacity(0.9))
+                    .fixedSize(horizontal: false, vertical: true)
+            } else if appState.isPreparingComparison {
+                HStack(spacing: 6) {
+                    ProgressView()
+                        .controlSize(.small)
+                    Text(appState.registerComparison ? "Registering..." : "Loading model...")
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.8))
+                }
+            } else if let registration = appState.comparisonRegistration {
+                Text(String(format: "Registered: %.2f° rotation, %.3f mm RMS", registration.rotationAngle, registration.rmsError))
+                    .font(.system(size: 10, design: .monospaced))
+                    .foregroundColor(.white.opacity(0.8))
+                    .textSelection(.enabled)
+            } else {
+                Text("File coordinates (not registered)")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.8))
+            }
+
+            Toggle("Register onto the main model", isOn: Binding(
+                get: { appState.registerComparison },
+                set: { enabled in
+                    guard let device = MTLCreateSystemDefaultDevice() else { return }
+                    appState.setRegisterComparison(enabled, device: device)
+                }
+            ))
+            .toggleStyle(.checkbox)
+            .font(.system(size: 11))
+
+            Text("Click a region to select it in both views")
+                .font(.system(size: 9))
+                .foregroundColor(.white.opacity(0.5))
+                .italic()
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 280)
+    }
+}
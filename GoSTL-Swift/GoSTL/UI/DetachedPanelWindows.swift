@@ -0,0 +1,166 @@
+import SwiftUI
+import AppKit
+
+/// Panels that can leave the 3D view for a window of their own, e.g. on a second monitor
+enum DetachedPanel: String, CaseIterable, Hashable {
+    case measurementLog
+    case analysis
+    case openSCADLog
+
+    var title: String {
+        switch self {
+        case .measurementLog: return "Measurement Log"
+        case .analysis: return "Analysis Panels"
+        case .openSCADLog: return "OpenSCAD Log"
+        }
+    }
+}
+
+/// Window of a detached panel, kept out of the tab group of the model windows
+final class DetachedPanelWindow: NSWindow {
+    let panel: DetachedPanel
+
+    init(panel: DetachedPanel, contentViewController: NSViewController) {
+        self.panel = panel
+        super.init(contentRect: NSRect(x: 0, y: 0, width: 420, height: 360),
+                   styleMask: [.titled, .closable, .miniaturizable, .resizable],
+                   backing: .buffered,
+                   defer: false)
+        self.contentViewController = contentViewController
+        self.tabbingMode = .disallowed
+        self.isReleasedWhenClosed = false
+        self.appearance = NSAppearance(named: .darkAqua)
+    }
+}
+
+/// Opens and closes the detached panel windows of every model window
+///
+/// Closing a panel window attaches the panel to the 3D view again. The window frames are saved per
+/// panel, so a panel moved to another monitor opens there the next time.
+@MainActor
+final class DetachedPanelWindows: NSObject, NSWindowDelegate {
+    static let shared = DetachedPanelWindows()
+
+    private struct Owner {
+        weak var appState: AppState?
+        var windows: [DetachedPanel: DetachedPanelWindow]
+    }
+
+    private var owners: [ObjectIdentifier: Owner] = [:]
+
+    func setDetached(_ detached: Bool, _ panel: DetachedPanel, appState: AppState) {
+        if detached {
+            detach(panel, appState: appState)
+        } else {
+            attach(panel, appState: appState)
+        }
+    }
+
+    /// Show a panel in its own window (brings the window to the front if already detached)
+    func detach(_ panel: DetachedPanel, appState: AppState) {
+        let key = ObjectIdentifier(appState)
+        if let window = owners[key]?.windows[panel] {
+            window.makeKeyAndOrderFront(nil)
+            return
+        }
+
+        let hostingController = NSHostingController(rootView: DetachedPanelView(panel: panel, appState: appState))
+        let window = DetachedPanelWindow(panel: panel, contentViewController: hostingController)
+        window.title = Self.title(of: panel, appState: appState)
+        window.delegate = self
+        window.setFrameAutosaveName("DetachedPanel.\(panel.rawValue)")
+
+        owners[key, default: Owner(appState: appState, windows: [:])].windows[panel] = window
+        appState.detachedPanels.insert(panel)
+        if panel == .measurementLog {
+            appState.showMeasurementLog = true
+        }
+        window.makeKeyAndOrderFront(nil)
+    }
+
+    /// Close a panel's window and show the panel over the 3D view again
+    func attach(_ panel: DetachedPanel, appState: AppState) {
+        // windowWillClose does the bookkeeping
+        owners[ObjectIdentifier(appState)]?.windows[panel]?.close()
+    }
+
+    /// Detach exactly these panels, attaching all others (e.g. for a saved layout)
+    func apply(_ panels: Set<DetachedPanel>, appState: AppState) {
+        for panel in DetachedPanel.allCases {
+            setDetached(panels.contains(panel), panel, appState: appState)
+        }
+    }
+
+    /// Close all panel windows of a model window (when it closes)
+    func closeAll(of appState: AppState) {
+        for panel in DetachedPanel.allCases {
+            attach(panel, appState: appState)
+        }
+        owners[ObjectIdentifier(appState)] = nil
+    }
+
+    /// Title naming the file, so panels of several model windows can be told apart
+    private static func title(of panel: DetachedPanel, appState: AppState) -> String {
+        guard let fileName = appState.modelInfo?.fileName else { return panel.title }
+        return "\(panel.title) – \(fileName)"
+    }
+
+    // MARK: - NSWindowDelegate
+
+    func windowWillClose(_ notification: Notification) {
+        guard let window = notification.object as? DetachedPanelWindow else { return }
+        for (key, owner) in owners where owner.windows[window.panel] === window {
+            owners[key]?.windows[window.panel] = nil
+            owner.appState?.detachedPanels.remove(window.panel)
+        }
+    }
+}
+
+/// Content of a detached panel window
+private struct DetachedPanelView: View {
+    let panel: DetachedPanel
+    let appState: AppState
+
+    var body: some View {
+        Group {
+            switch panel {
+            case .measurementLog:
+                MeasurementLogPanel(
+                    announcer: appState.measurementAnnouncer,
+                    fillsWindow: true,
+                    onClose: {
+                        appState.showMeasurementLog = false
+                        DetachedPanelWindows.shared.attach(.measurementLog, appState: appState)
+                    }
+                )
+            case .analysis:
+                if appState.hasAnalysisPanels {
+                    ScrollView {
+                        AnalysisPanelStack(appState: appState)
+                            .frame(maxWidth: .infinity)
+                    }
+                } else {
+                    placeholder("No analysis or tool panels are open")
+                }
+            case .openSCADLog:
+                if appState.renderWarnings.isEmpty {
+                    placeholder("No OpenSCAD messages")
+                } else {
+                    ScrollView {
+                        WarningsPanel(warnings: appState.renderWarnings, fillsWindow: true)
+                    }
+                }
+            }
+        }
+        .padding(12)
+        .frame(minWidth: 340, maxWidth: .infinity, minHeight: 200, maxHeight: .infinity, alignment: .topLeading)
+        .background(Color(red: 0.1, green: 0.1, blue: 0.12))
+    }
+
+    private func placeholder(_ text: String) -> some View {
+        Text(text)
+            .font(.system(size: 12))
+            .foregroundColor(.white.opacity(0.6))
+            .frame(maxWidth: .infinity, maxHeight: .infinity)
+    }
+}
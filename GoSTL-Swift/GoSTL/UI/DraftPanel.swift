@@ -0,0 +1,139 @@
+import SwiftUI
+import Metal
+
+/// Panel of the draft angle analysis: pull direction, minimum draft and faces that would stick in the mold
+struct DraftPanel: View {
+    let appState: AppState
+    let onClose: () -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("DRAFT")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Hide the draft analysis")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            HStack(spacing: 6) {
+                Text("Pull")
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.8))
+                Spacer()
+                Picker("", selection: Binding(
+                    get: { appState.draftPullDirection },
+                    set: { direction in
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        appState.setDraftPullDirection(direction, device: device)
+                    }
+                )) {
+                    ForEach(DraftAnalysis.PullDirection.allCases, id: \.self) { direction in
+                        Text(direction.displayName).tag(direction)
+                    }
+                }
+                .labelsHidden()
+                .pickerStyle(.segmented)
+                .frame(width: 210)
+            }
+
+            HStack(spacing: 6) {
+                Text("Minimum draft")
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.8))
+                Spacer()
+                Picker("", selection: Binding(
+                    get: { appState.draftMinimumAngle },
+                    set: { angle in
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        appState.setDraftMinimumAngle(angle, device: device)
+                    }
+                )) {
+                    ForEach(DraftAnalysis.minimumAngleOptions, id: \.self) { angle in
+                        Text(String(format: "%g°", angle)).tag(angle)
+                    }
+                }
+                .labelsHidden()
+                .frame(width: 80)
+            }
+
+            if let result = appState.draftResult {
+                resultView(result)
+            } else {
+                HStack(spacing: 6) {
+                    ProgressView()
+                        .controlSize(.small)
+                    Text("Analyzing draft...")
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.8))
+                }
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+
+    @ViewBuilder
+    private func resultView(_ result: DraftAnalysis.Result) -> some View {
+        if result.insufficientTriangles.isEmpty {
+            Text("All faces release")
+                .font(.system(size: 12, weight: .semibold))
+                .foregroundColor(.green)
+        } else {
+            Text(String(format: "Faces below %g° draft", result.minimumAngle))
+                .font(.system(size: 12, weight: .semibold))
+                .foregroundColor(.orange)
+        }
+
+        row(DraftAnalysis.sufficientColor, String(format: "≥ %g°", result.minimumAngle), area(result.totalArea - result.insufficientArea - result.oppositeArea, of: result))
+        row(DraftAnalysis.verticalColor, String(format: "Below %g° (%d)", result.minimumAngle, result.insufficientTriangles.count), area(result.insufficientArea, of: result))
+        row(DraftAnalysis.oppositeColor, "Opposite side (\(result.oppositeTriangles.count))", area(result.oppositeArea, of: result))
+            .help("Faces turned away from the pull direction: undercuts for a one-part mold, the other half of a two-part mold")
+
+        HStack(spacing: 8) {
+            Button("Select Faces Below Minimum") {
+                appState.selectInsufficientDraft()
+            }
+            .controlSize(.small)
+            .disabled(result.insufficientTriangles.isEmpty)
+        }
+        .padding(.top, 2)
+    }
+
+    private func row(_ color: TriangleColor, _ name: String, _ value: String) -> some View {
+        HStack(spacing: 6) {
+            RoundedRectangle(cornerRadius: 2)
+                .fill(Color(red: Double(color.r), green: Double(color.g), blue: Double(color.b)))
+                .frame(width: 10, height: 10)
+            Text(name)
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.8))
+            Spacer()
+            Text(value)
+                .font(.system(size: 11, design: .monospaced))
+                .foregroundColor(.white)
+                .textSelection(.enabled)
+        }
+    }
+
+    private func area(_ area: Double, of result: DraftAnalysis.Result) -> String {
+        let share = result.totalArea > 0 ? area / result.totalArea * 100 : 0
+        return String(format: "%.1f mm² (%.0f%%)", area, share)
+    }
+}
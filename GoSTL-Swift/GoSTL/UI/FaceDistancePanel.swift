@@ -0,0 +1,79 @@
+import SwiftUI
+
+/// Panel of the face distance tool: side picker, hint and the last distance between parallel faces
+struct FaceDistancePanel: View {
+    let appState: AppState
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("FACE DISTANCE")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: { appState.setFaceDistanceTool(false) }) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("End the face distance tool (Esc)")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            Text("Click a flat face to measure to the parallel face opposite it")
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.8))
+
+            HStack(spacing: 6) {
+                Text("Across")
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.8))
+                Spacer()
+                Picker("", selection: Binding(
+                    get: { appState.faceDistanceSide },
+                    set: { appState.faceDistanceSide = $0 }
+                )) {
+                    ForEach(FaceDistance.Side.allCases) { side in
+                        Text(side.title).tag(side)
+                    }
+                }
+                .labelsHidden()
+                .pickerStyle(.segmented)
+                .frame(width: 210)
+            }
+
+            if let result = appState.faceDistance {
+                HStack {
+                    Text(appState.displayPrecision.lengthWithUnit(result.distance))
+                        .font(.system(size: 18, weight: .semibold, design: .monospaced))
+                        .foregroundColor(.white)
+                        .textSelection(.enabled)
+                    Text(result.isThickness ? "thickness" : "gap")
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.7))
+                    Spacer()
+                    Text("kept as measurement")
+                        .font(.system(size: 9))
+                        .foregroundColor(.white.opacity(0.5))
+                        .italic()
+                }
+
+                Text(String(format: "Faces %.1f mm² and %.1f mm², parallel within %@", result.pickedArea, result.oppositeArea, appState.displayPrecision.angle(result.parallelism)))
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.6))
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+}
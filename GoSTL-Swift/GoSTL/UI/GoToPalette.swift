@@ -0,0 +1,143 @@
+import SwiftUI
+
+/// Command palette for jumping to coordinates, vertices, triangles or named anchors
+struct GoToPalette: View {
+    let appState: AppState
+
+    @State private var text: String = ""
+    @State private var notFound: Bool = false
+    @FocusState private var isFocused: Bool
+
+    private var query: GoToQuery? {
+        GoToQuery.parse(text)
+    }
+
+    /// Anchor suggestions for the current search text (all anchors when empty)
+    private var suggestions: [GoToAnchor] {
+        guard let query = query else { return appState.goToAnchors }
+        return Array(query.matchingAnchors(in: appState.goToAnchors).prefix(8))
+    }
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 0) {
+            HStack(spacing: 6) {
+                Image(systemName: "location.magnifyingglass")
+                    .font(.system(size: 12))
+                    .foregroundColor(.white.opacity(0.7))
+                TextField("x, y, z  ·  v<index>  ·  t<index>  ·  anchor name", text: $text)
+                    .textFieldStyle(.plain)
+                    .font(.system(size: 12, design: .monospaced))
+                    .foregroundColor(.white)
+                    .focused($isFocused)
+                    .onSubmit(submit)
+                    .onChange(of: text) { _, _ in
+                        notFound = false
+                    }
+                    .accessibilityLabel("Go to location")
+            }
+            .padding(.horizontal, 10)
+            .padding(.vertical, 8)
+
+            if notFound {
+                Text("Nothing found")
+                    .font(.system(size: 10))
+                    .foregroundColor(.orange)
+                    .padding(.horizontal, 10)
+                    .padding(.bottom, 6)
+            }
+
+            if !suggestions.isEmpty {
+                Rectangle()
+                    .fill(Color.white.opacity(0.15))
+                    .frame(height: 1)
+
+                VStack(alignment: .leading, spacing: 0) {
+                    ForEach(Array(suggestions.enumerated()), id: \.offset) { _, anchor in
+                        Button(action: { go(to: .search(anchor.name)) }) {
+                            HStack {
+                                Text(anchor.name)
+                                    .font(.system(size: 11))
+                                    .foregroundColor(.white.opacity(0.9))
+                                Spacer()
+                                let position = appState.originalPosition(anchor.position)
+                                Text(String(format: "%.1f, %.1f, %.1f", position.x, position.y, position.z))
+                                    .font(.system(size: 9, design: .monospaced))
+                                    .foregroundColor(.white.opacity(0.5))
+                            }
+                            .padding(.horizontal, 10)
+                            .padding(.vertical, 4)
+                            .contentShape(Rectangle())
+                        }
+                        .buttonStyle(.plain)
+                    }
+                }
+                .padding(.vertical, 4)
+            }
+        }
+        .frame(width: 420)
+        .background(
+            RoundedRectangle(cornerRadius: 8)
+                .fill(Color.black.opacity(0.85))
+                .overlay(
+                    RoundedRectangle(cornerRadius: 8)
+                        .stroke(Color.white.opacity(0.2), lineWidth: 1)
+                )
+        )
+        .clipShape(RoundedRectangle(cornerRadius: 8))
+        .shadow(color: .black.opacity(0.4), radius: 8, y: 4)
+        .onAppear {
+            isFocused = true
+        }
+        .onExitCommand {
+            close()
+        }
+    }
+
+    private func submit() {
+        guard let query = query else {
+            close()
+            return
+        }
+        go(to: query)
+    }
+
+    private func go(to query: GoToQuery) {
+        if appState.goTo(query) {
+            close()
+        } else {
+            notFound = true
+        }
+    }
+
+    private func close() {
+        text = ""
+        appState.showGoToPalette = false
+    }
+}
+
+/// Temporary crosshair marker at the last go-to target
+struct GoToMarkerOverlay: View {
+    let position: Vector3
+    let camera: Camera
+    let viewSize: CGSize
+
+    var body: some View {
+        if let screenPos = camera.project(worldPosition: position, viewSize: viewSize) {
+            ZStack {
+                Circle()
+                    .stroke(Color.cyan, lineWidth: 2)
+                    .frame(width: 24, height: 24)
+                Rectangle()
+                    .fill(Color.cyan)
+                    .frame(width: 36, height: 1.5)
+                Rectangle()
+                    .fill(Color.cyan)
+                    .frame(width: 1.5, height: 36)
+            }
+            .shadow(color: .black.opacity(0.6), radius: 2)
+            .position(screenPos)
+            .allowsHitTesting(false)
+            .transition(.opacity)
+        }
+    }
+}
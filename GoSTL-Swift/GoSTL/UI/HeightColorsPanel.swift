@@ -0,0 +1,112 @@
+import SwiftUI
+import Metal
+
+/// Legend of the color-by-height rendering: axis, gradient and the coordinate range it spans
+struct HeightColorsPanel: View {
+    let appState: AppState
+    let onClose: () -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("HEIGHT")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Hide the height colors")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            HStack(spacing: 6) {
+                Text("Axis")
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.8))
+                Spacer()
+                Picker("", selection: Binding(
+                    get: { appState.heightColorAxis },
+                    set: { axis in
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        appState.setHeightColorAxis(axis, device: device)
+                    }
+                )) {
+                    ForEach(HeightColors.Axis.allCases, id: \.self) { axis in
+                        Text(axis.displayName).tag(axis)
+                    }
+                }
+                .labelsHidden()
+                .pickerStyle(.segmented)
+                .frame(width: 120)
+            }
+
+            if let range = appState.heightColorRange {
+                legend(range)
+            } else {
+                HStack(spacing: 6) {
+                    ProgressView()
+                        .controlSize(.small)
+                    Text("Coloring by height...")
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.8))
+                }
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 220)
+    }
+
+    private func legend(_ range: ClosedRange<Double>) -> some View {
+        VStack(alignment: .leading, spacing: 6) {
+            HStack(alignment: .top, spacing: 8) {
+                // Highest value at the top
+                RoundedRectangle(cornerRadius: 3)
+                    .fill(LinearGradient(
+                        colors: HeightColors.gradient.reversed().map { Color(red: Double($0.r), green: Double($0.g), blue: Double($0.b)) },
+                        startPoint: .top,
+                        endPoint: .bottom
+                    ))
+                    .frame(width: 14, height: 96)
+
+                VStack(alignment: .leading) {
+                    value(range.upperBound)
+                    Spacer()
+                    value((range.lowerBound + range.upperBound) / 2)
+                    Spacer()
+                    value(range.lowerBound)
+                }
+                .frame(height: 96)
+            }
+
+            HStack {
+                Text("Span")
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.8))
+                Spacer()
+                Text(appState.displayPrecision.lengthWithUnit(range.upperBound - range.lowerBound))
+                    .font(.system(size: 11, design: .monospaced))
+                    .foregroundColor(.white)
+                    .textSelection(.enabled)
+            }
+        }
+    }
+
+    private func value(_ coordinate: Double) -> some View {
+        Text("\(appState.heightColorAxis.displayName) \(appState.displayPrecision.lengthWithUnit(coordinate))")
+            .font(.system(size: 11, design: .monospaced))
+            .foregroundColor(.white)
+    }
+}
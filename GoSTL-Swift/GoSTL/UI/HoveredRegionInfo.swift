@@ -0,0 +1,33 @@
+import SwiftUI
+
+/// Area and normal of the flat face or smooth region highlighted under the cursor
+struct HoveredRegionInfo: View {
+    let region: SurfaceRegion
+    let precision: DisplayPrecision
+
+    var body: some View {
+        HStack(spacing: 6) {
+            RoundedRectangle(cornerRadius: 2)
+                .fill(Color(
+                    red: Double(AppState.hoveredRegionColor.x),
+                    green: Double(AppState.hoveredRegionColor.y),
+                    blue: Double(AppState.hoveredRegionColor.z)
+                ))
+                .frame(width: 10, height: 10)
+            Text(region.kind.title)
+                .font(.caption.bold())
+            Text(String(format: "%.1f mm²", region.area))
+                .font(.caption.monospacedDigit())
+            Text("normal \(precision.direction(region.normal))")
+                .font(.caption.monospacedDigit())
+                .foregroundColor(.secondary)
+            Text("\(region.triangles.count) triangle\(region.triangles.count == 1 ? "" : "s")")
+                .font(.caption)
+                .foregroundColor(.secondary)
+        }
+        .padding(.horizontal, 12)
+        .padding(.vertical, 6)
+        .background(.ultraThinMaterial, in: RoundedRectangle(cornerRadius: 8))
+        .allowsHitTesting(false)
+    }
+}
@@ -0,0 +1,151 @@
+import CoreGraphics
+
+/// Screen-space layout for labels anchored to projected 3D positions
+///
+/// Labels are placed in priority order. Each label first tries its anchor, then the offset it had
+/// in the previous frame, then positions on rings around the anchor; displaced labels get a
+/// leader line back to their anchor. A label whose anchor lies within `clusterRadius` of a
+/// higher-priority label (typically when zoomed out), or that finds no free spot, is merged into
+/// the label next to it and counted there instead of being hidden. Reusing the previous offsets
+/// and cluster memberships keeps labels from jumping or flickering while orbiting.
+final class LabelLayout {
+    struct Item: Equatable {
+        let id: Int
+        /// Projected position the label belongs to
+        let anchor: CGPoint
+        let size: CGSize
+        /// Higher priorities are placed first and win contested spots
+        let priority: Double
+    }
+
+    struct Placement: Equatable {
+        let id: Int
+        let anchor: CGPoint
+        let center: CGPoint
+        let size: CGSize
+        /// Ids of the labels merged into this one
+        var clustered: [Int] = []
+
+        var frame: CGRect {
+            CGRect(x: center.x - size.width / 2, y: center.y - size.height / 2, width: size.width, height: size.height)
+        }
+
+        /// Whether the label was moved away from its anchor and needs a leader line
+        var isDisplaced: Bool {
+            hypot(center.x - anchor.x, center.y - anchor.y) > 0.5
+        }
+
+        /// Point on the label's border closest to the anchor, where the leader line ends
+        var leaderEnd: CGPoint {
+            let frame = frame
+            return CGPoint(x: min(max(anchor.x, frame.minX), frame.maxX), y: min(max(anchor.y, frame.minY), frame.maxY))
+        }
+    }
+
+    /// Anchors closer than this to a higher-priority anchor are merged into its label
+    var clusterRadius: CGFloat = 16
+
+    /// Clustered labels only split off again beyond this multiple of the cluster radius (hysteresis)
+    var clusterHysteresis: CGFloat = 1.5
+
+    /// Free space kept between labels
+    var gap: CGFloat = 4
+
+    /// Number of rings of candidate positions around the anchor
+    var rings = 3
+
+    /// Offsets from the anchors in the previous layout
+    private var previousOffsets: [Int: CGVector] = [:]
+
+    /// Label each clustered label was merged into in the previous layout
+    private var previousParents: [Int: Int] = [:]
+
+    /// Estimated size of a label in the 12 pt monospaced measurement font
+    static func estimatedSize(of text: String) -> CGSize {
+        CGSize(width: CGFloat(text.count) * 7.3 + 12, height: 20)
+    }
+
+    /// Place the labels inside the bounds
+    /// Labels whose anchor is outside the bounds are left out.
+    /// - Parameter obstacles: Frames of other labels (e.g. of another overlay) to keep clear of
+    func layout(_ items: [Item], in bounds: CGRect, avoiding obstacles: [CGRect] = []) -> [Placement] {
+        let ordered = items
+            .filter { bounds.contains($0.anchor) }
+            .sorted { $0.priority != $1.priority ? $0.priority > $1.priority : $0.id < $1.id }
+
+        var placements: [Placement] = []
+        var parents: [Int: Int] = [:]
+
+        for item in ordered {
+            if let index = clusterIndex(for: item, in: placements) {
+                placements[index].clustered.append(item.id)
+                parents[item.id] = placements[index].id
+                continue
+            }
+
+            let frame = CGRect(x: item.anchor.x - item.size.width / 2, y: item.anchor.y - item.size.height / 2,
+                               width: item.size.width, height: item.size.height)
+            if let center = candidates(for: item).first(where: { isFree(center: $0, size: item.size, in: bounds, among: placements, avoiding: obstacles) }) {
+                placements.append(Placement(id: item.id, anchor: item.anchor, center: center, size: item.size))
+            } else if let index = placements.firstIndex(where: { $0.frame.insetBy(dx: -gap, dy: -gap).intersects(frame) }) {
+                // No free spot: count the label on the one covering its anchor rather than hiding it
+                placements[index].clustered.append(item.id)
+                parents[item.id] = placements[index].id
+            } else {
+                // Only blocked by the view's edge: keep it at the anchor, partly cut off
+                placements.append(Placement(id: item.id, anchor: item.anchor, center: item.anchor, size: item.size))
+            }
+        }
+
+        previousOffsets = Dictionary(uniqueKeysWithValues: placements.map {
+            ($0.id, CGVector(dx: $0.center.x - $0.anchor.x, dy: $0.center.y - $0.anchor.y))
+        })
+        previousParents = parents
+        return placements
+    }
+
+    /// Forget previous placements (e.g. when the measurements are replaced)
+    func reset() {
+        previousOffsets = [:]
+        previousParents = [:]
+    }
+
+    /// Placed label the item should be merged into, if its anchor is close enough
+    private func clusterIndex(for item: Item, in placements: [Placement]) -> Int? {
+        var best: (index: Int, distance: CGFloat)?
+        for (index, placement) in placements.enumerated() {
+            let distance = hypot(placement.anchor.x - item.anchor.x, placement.anchor.y - item.anchor.y)
+            let radius = previousParents[item.id] == placement.id ? clusterRadius * clusterHysteresis : clusterRadius
+            if distance <= radius && distance < (best?.distance ?? .infinity) {
+                best = (index, distance)
+            }
+        }
+        return best?.index
+    }
+
+    /// Candidate centers: the anchor, the previous offset, then rings around the anchor
+    private func candidates(for item: Item) -> [CGPoint] {
+        var offsets = [CGVector(dx: 0, dy: 0)]
+        if let previous = previousOffsets[item.id] {
+            offsets.append(previous)
+        }
+
+        let step = CGSize(width: item.size.width + gap, height: item.size.height + gap)
+        let directions: [(CGFloat, CGFloat)] = [(0, -1), (0, 1), (1, -1), (-1, -1), (1, 1), (-1, 1), (1, 0), (-1, 0)]
+        for ring in 1...max(1, rings) {
+            for (dx, dy) in directions {
+                // Diagonal moves already clear the height, so half the width is enough sideways
+                let horizontal = dy == 0 ? step.width : step.width / 2 + gap
+                offsets.append(CGVector(dx: dx * horizontal * CGFloat(ring), dy: dy * step.height * CGFloat(ring)))
+            }
+        }
+        return offsets.map { CGPoint(x: item.anchor.x + $0.dx, y: item.anchor.y + $0.dy) }
+    }
+
+    private func isFree(center: CGPoint, size: CGSize, in bounds: CGRect, among placements: [Placement], avoiding obstacles: [CGRect]) -> Bool {
+        let frame = CGRect(x: center.x - size.width / 2, y: center.y - size.height / 2, width: size.width, height: size.height)
+        guard bounds.contains(frame) else { return false }
+        return !placements.contains { $0.frame.insetBy(dx: -gap, dy: -gap).intersects(frame) }
+            && !obstacles.contains { $0.insetBy(dx: -gap, dy: -gap).intersects(frame) }
+    }
+}
@@ -0,0 +1,73 @@
+import SwiftUI
+
+/// Panel shown while picking a face to lay the model flat on
+struct LayFlatPanel: View {
+    let selectedTriangleCount: Int
+    let canUndo: Bool
+    let onUseSelection: () -> Void
+    let onCancel: () -> Void
+    let onUndo: () -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack {
+                Text("LAY FLAT")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onCancel) {
+                    Image(systemName: "xmark.circle.fill")
+                        .foregroundColor(.white.opacity(0.6))
+                }
+                .buttonStyle(.plain)
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            Text("Click a face to place it on Z = 0")
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.8))
+
+            if selectedTriangleCount > 0 {
+                Button("Fit Plane to \(selectedTriangleCount) Selected Triangles", action: onUseSelection)
+                    .controlSize(.small)
+            }
+
+            Text("Save As exports the reoriented STL")
+                .font(.system(size: 9))
+                .foregroundColor(.white.opacity(0.5))
+                .italic()
+
+            Divider()
+                .background(Color.white.opacity(0.2))
+
+            HStack(spacing: 4) {
+                LevelingKeyHint(key: "Esc")
+                Text("Cancel")
+                    .font(.system(size: 9))
+                    .foregroundColor(.white.opacity(0.6))
+
+                if canUndo {
+                    Text("|")
+                        .font(.system(size: 9))
+                        .foregroundColor(.white.opacity(0.4))
+
+                    Button(action: onUndo) {
+                        LevelingKeyHint(key: "Undo")
+                    }
+                    .buttonStyle(.plain)
+                }
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 220)
+    }
+}
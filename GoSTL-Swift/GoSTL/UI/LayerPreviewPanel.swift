@@ -0,0 +1,143 @@
+import SwiftUI
+
+/// Panel for stepping through print layers like a slicer preview
+struct LayerPreviewPanel: View {
+    let layerPreview: LayerPreviewState
+    /// Offset added to render-space Z for display in file coordinates
+    let zOffset: Double
+    let onClose: () -> Void
+
+    private let layerHeights: [Double] = [0.08, 0.12, 0.16, 0.2, 0.28, 0.3]
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("LAYER PREVIEW")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            HStack {
+                Text("Layer \(layerPreview.layer + 1) / \(layerPreview.layerCount)")
+                    .font(.system(size: 11, weight: .medium, design: .monospaced))
+                    .foregroundColor(.white)
+                Spacer()
+                Text(String(format: "Z %.2f – %.2f mm", layerPreview.layerBottom + zOffset, layerPreview.layerTop + zOffset))
+                    .font(.system(size: 10, design: .monospaced))
+                    .foregroundColor(.white.opacity(0.7))
+            }
+
+            Slider(
+                value: Binding(
+                    get: { Double(layerPreview.layer) },
+                    set: {
+                        layerPreview.isPlaying = false
+                        layerPreview.layer = Int($0.rounded())
+                    }
+                ),
+                in: 0...Double(max(1, layerPreview.layerCount - 1)),
+                step: 1
+            )
+            .tint(.orange)
+
+            // Transport controls
+            HStack(spacing: 12) {
+                TransportButton(systemName: "backward.end.fill") {
+                    layerPreview.step(by: -layerPreview.layerCount)
+                }
+                TransportButton(systemName: "backward.frame.fill") {
+                    layerPreview.step(by: -1)
+                }
+                TransportButton(systemName: layerPreview.isPlaying ? "pause.fill" : "play.fill") {
+                    layerPreview.togglePlaying()
+                }
+                TransportButton(systemName: "forward.frame.fill") {
+                    layerPreview.step(by: 1)
+                }
+                TransportButton(systemName: "forward.end.fill") {
+                    layerPreview.step(by: layerPreview.layerCount)
+                }
+
+                Spacer()
+
+                Picker("", selection: Binding(
+                    get: { layerPreview.layersPerSecond },
+                    set: { layerPreview.layersPerSecond = $0 }
+                )) {
+                    ForEach(LayerPreviewState.speeds, id: \.self) { speed in
+                        Text("\(Int(speed))/s").tag(speed)
+                    }
+                }
+                .labelsHidden()
+                .frame(width: 70)
+                .help("Layers per second")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.2))
+
+            HStack(spacing: 8) {
+                Text("Layer height")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.7))
+                Picker("", selection: Binding(
+                    get: { layerPreview.layerHeight },
+                    set: { layerPreview.setLayerHeight($0) }
+                )) {
+                    ForEach(layerHeights, id: \.self) { height in
+                        Text(String(format: "%.2f mm", height)).tag(height)
+                    }
+                }
+                .labelsHidden()
+                .frame(width: 90)
+
+                Spacer()
+
+                Toggle("Below", isOn: Binding(
+                    get: { layerPreview.showsLayersBelow },
+                    set: { layerPreview.showsLayersBelow = $0 }
+                ))
+                .toggleStyle(.checkbox)
+                .font(.system(size: 10))
+                .help("Also show the layers printed before the current one")
+            }
+
+            Text("Space play/pause · , . step (⇧ ×10)")
+                .font(.system(size: 9))
+                .foregroundColor(.white.opacity(0.5))
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+}
+
+private struct TransportButton: View {
+    let systemName: String
+    let action: () -> Void
+
+    var body: some View {
+        Button(action: action) {
+            Image(systemName: systemName)
+                .font(.system(size: 11))
+        }
+        .buttonStyle(.plain)
+        .foregroundColor(.white.opacity(0.85))
+    }
+}
@@ -158,7 +158,7 @@ private struct PointIndicator: View {
     }
 }
 
-private struct LevelingKeyHint: View {
+struct LevelingKeyHint: View {
     let key: String
 
     var body: some View {
@@ -27,6 +27,27 @@ struct MainMenuPanel: View {
                         ViewSectionContent(appState: appState)
                     }
 
+                    // OpenSCAD Section (module isolation)
+                    if appState.isOpenSCAD && !appState.openSCADModules.isEmpty {
+                        MenuSection(title: "OpenSCAD", icon: "curlybraces") {
+                            OpenSCADModuleSectionContent(appState: appState)
+                        }
+                    }
+
+                    // PCB Section (board and component heights)
+                    if appState.pcbPreset {
+                        MenuSection(title: "PCB", icon: "cpu") {
+                            PCBSectionContent(appState: appState)
+                        }
+                    }
+
+                    // Color Groups Section (OpenSCAD color() calls)
+                    if !appState.colorGroups.isEmpty {
+                        MenuSection(title: "Color Groups", icon: "paintpalette") {
+                            ColorGroupsSectionContent(appState: appState)
+                        }
+                    }
+
                     // Tools Section
                     MenuSection(title: "Tools", icon: "ruler") {
                         ToolsSectionContent(measurementSystem: appState.measurementSystem, appState: appState)
@@ -164,7 +185,14 @@ struct InfoSectionContent: View {
                     .foregroundColor(.white)
                 KeyHint(key: "m")
             }
+            if modelInfo.material.supportsInfill {
+                InfoRow(label: "Infill:", value: String(format: "%.0f%%", modelInfo.estimate.infill * 100))
+            }
             InfoRow(label: "Weight:", value: Material.formatWeight(modelInfo.weight))
+            InfoRow(label: "Cost:", value: Material.formatCost(modelInfo.estimate.cost))
+            if let stability = modelInfo.stability {
+                InfoRow(label: "Stability:", value: ModelInfo.formatStability(stability))
+            }
 
             Divider()
                 .background(Color.white.opacity(0.2))
@@ -176,10 +204,24 @@ struct InfoSectionContent: View {
                     .font(.system(size: 10))
                     .foregroundColor(.white.opacity(0.8))
                 Text(String(format: "%.1f, %.1f, %.1f",
-                           modelInfo.center.x, modelInfo.center.y, modelInfo.center.z))
+                           modelInfo.originalCenter.x, modelInfo.originalCenter.y, modelInfo.originalCenter.z))
                     .font(.system(size: 10, design: .monospaced))
                     .foregroundColor(.white)
             }
+
+            // Recentering offset for models far from the origin
+            if modelInfo.isRecentered {
+                VStack(alignment: .leading, spacing: 2) {
+                    Text("Recentered by:")
+                        .font(.system(size: 10))
+                        .foregroundColor(.white.opacity(0.8))
+                    Text(String(format: "%.0f, %.0f, %.0f",
+                               modelInfo.coordinateOffset.x, modelInfo.coordinateOffset.y, modelInfo.coordinateOffset.z))
+                        .font(.system(size: 10, design: .monospaced))
+                        .foregroundColor(.white.opacity(0.6))
+                }
+                .help("The model is rendered near the origin for precision; readouts use the original coordinates")
+            }
         }
     }
 }
@@ -241,6 +283,51 @@ struct ViewSectionContent: View {
                 KeyHint(key: "⇧X")
             }
 
+            // PCB preset toggle
+            HStack(spacing: 4) {
+                Button(action: {
+                    if let device = MTLCreateSystemDefaultDevice() {
+                        appState.setPCBPreset(!appState.pcbPreset, device: device)
+                    }
+                }) {
+                    HStack(spacing: 4) {
+                        Image(systemName: appState.pcbPreset ? "checkmark.square.fill" : "square")
+                            .font(.system(size: 10))
+                            .foregroundColor(appState.pcbPreset ? .orange : .white.opacity(0.5))
+                        Text("PCB Preset")
+                            .font(.system(size: 10))
+                            .foregroundColor(.white.opacity(0.8))
+                    }
+                }
+                .buttonStyle(.plain)
+                KeyHint(key: "⌘⇧B")
+            }
+
+            // Contour lines toggle
+            HStack(spacing: 4) {
+                Button(action: {
+                    if let device = MTLCreateSystemDefaultDevice() {
+                        appState.setContourLines(!appState.showContourLines, device: device)
+                    }
+                }) {
+                    HStack(spacing: 4) {
+                        Image(systemName: appState.showContourLines ? "checkmark.square.fill" : "square")
+                            .font(.system(size: 10))
+                            .foregroundColor(appState.showContourLines ? .orange : .white.opacity(0.5))
+                        Text("Contour Lines")
+                            .font(.system(size: 10))
+                            .foregroundColor(.white.opacity(0.8))
+                    }
+                }
+                .buttonStyle(.plain)
+                Spacer()
+                if appState.showContourLines, let interval = appState.contourInterval {
+                    Text(String(format: "every %g mm", interval) + (appState.contourTruncated ? " (partial)" : ""))
+                        .font(.system(size: 9))
+                        .foregroundColor(.white.opacity(0.5))
+                }
+            }
+
             // Build plate selector
             VStack(alignment: .leading, spacing: 3) {
                 HStack(spacing: 4) {
@@ -437,12 +524,59 @@ struct ToolsSectionContent: View {
                             .font(.system(size: 9))
                             .foregroundColor(.white.opacity(0.6))
                             .italic()
+
+                        if let refinement = measurementSystem.pickRefinement, measurementSystem.hoverPoint != nil {
+                            let position = refinement.position + (appState?.coordinateOffset ?? .zero)
+                            Text("Refined to \(refinement.feature.rawValue.lowercased()) (\(refinement.sampleCount) vertices)")
+                                .font(.system(size: 9))
+                                .foregroundColor(.cyan)
+                            Text(String(format: "(%.3f, %.3f, %.3f) ±%.3f", position.x, position.y, position.z, refinement.uncertainty))
+                                .font(.system(size: 9, design: .monospaced))
+                                .foregroundColor(.white.opacity(0.8))
+                        }
+
+                        // Selection filter: click a chip to stop or resume snapping to that geometry
+                        HStack(spacing: 3) {
+                            Text("Snap:")
+                                .font(.system(size: 9))
+                                .foregroundColor(.white.opacity(0.7))
+                            ForEach(SnapTarget.allCases) { target in
+                                let isOn = measurementSystem.snapTargets.contains(target)
+                                Button(action: { measurementSystem.toggleSnapTarget(target) }) {
+                                    Text(target.shortTitle)
+                                        .font(.system(size: 9, weight: .medium))
+                                        .foregroundColor(isOn ? .white : .white.opacity(0.4))
+                                        .padding(.horizontal, 4)
+                                        .padding(.vertical, 1)
+                                        .background(isOn ? Color.blue.opacity(0.5) : Color.white.opacity(0.08))
+                                        .cornerRadius(3)
+                                }
+                                .buttonStyle(.plain)
+                                .help(target.title)
+                            }
+                        }
+                        .padding(.top, 2)
                     }
 
                     if mode == .distance {
                         // Show constraint hint when at least one point is selected
                         if !measurementSystem.currentPoints.isEmpty {
                             if measurementSystem.constraint != nil {
+                                if let lengthInput = measurementSystem.lengthInput {
+                                    HStack(spacing: 4) {
+                                        Text("Length: \(lengthInput) mm")
+                                            .font(.system(size: 10, weight: .medium, design: .monospaced))
+                                            .foregroundColor(measurementSystem.typedLength != nil ? .yellow : .red)
+                                        KeyHint(key: "⏎")
+                                        Text("Place")
+                                            .font(.system(size: 9))
+                                            .foregroundColor(.white.opacity(0.7))
+                                    }
+                                } else {
+                                    Text("Type a length, ⏎ to place")
+                                        .font(.system(size: 9))
+                                        .foregroundColor(.white.opacity(0.5))
+                                }
                                 HStack(spacing: 4) {
                                     KeyHint(key: "⌥")
                                     Text("Release constraint")
@@ -535,6 +669,13 @@ struct ToolsSectionContent: View {
                     action: { measurementSystem.startMeasurement(type: .triangleSelect) }
                 )
 
+                MeasurementToolButton(
+                    icon: "cube.transparent",
+                    label: "Overall Dimensions",
+                    key: "b",
+                    action: { appState?.addBoundingDimensions() }
+                )
+
                 Divider()
                     .background(Color.white.opacity(0.2))
                     .padding(.vertical, 2)
@@ -553,7 +694,11 @@ struct ToolsSectionContent: View {
                     .background(Color.white.opacity(0.2))
                     .padding(.vertical, 2)
 
-                SelectedMeasurementsPanel(measurementSystem: measurementSystem)
+                SelectedMeasurementsPanel(
+                    measurementSystem: measurementSystem,
+                    coordinateOffset: appState?.coordinateOffset ?? .zero,
+                    precision: appState?.displayPrecision ?? .standard
+                )
             }
 
             // Show Clear All button if measurements exist
@@ -629,6 +774,8 @@ struct ToolsSectionContent: View {
             return ["X", "Y", "Z"][axis]
         case .point:
             return "Point"
+        case .direction:
+            return "Reference"
         }
     }
 
@@ -643,6 +790,8 @@ struct ToolsSectionContent: View {
             }
         case .point:
             return Color.cyan
+        case .direction:
+            return Color.orange
         }
     }
 
@@ -652,6 +801,8 @@ struct ToolsSectionContent: View {
             return ["X", "Y", "Z"][axis]
         case .point:
             return "→"  // Arrow to indicate direction constraint
+        case .direction:
+            return "∥"  // Parallel to a reference axis or plane normal
         }
     }
 
@@ -666,6 +817,8 @@ struct ToolsSectionContent: View {
             }
         case .point:
             return Color.cyan
+        case .direction:
+            return Color.orange
         }
     }
 }
@@ -930,9 +1083,31 @@ struct BuildPlateOrientationToggle: View {
     }
 }
 
+/// Text field for a measurement's note, saved on submit
+private struct MeasurementNoteField: View {
+    let onCommit: (String) -> Void
+
+    @State private var text: String
+
+    init(note: String, onCommit: @escaping (String) -> Void) {
+        self.onCommit = onCommit
+        _text = State(initialValue: note)
+    }
+
+    var body: some View {
+        TextField("Note", text: $text)
+            .textFieldStyle(.roundedBorder)
+            .font(.system(size: 9))
+            .onSubmit { onCommit(text) }
+    }
+}
+
 /// Panel showing details of selected measurements
 struct SelectedMeasurementsPanel: View {
     let measurementSystem: MeasurementSystem
+    /// Recentering offset added back so coordinates match the original file
+    var coordinateOffset: Vector3 = .zero
+    var precision: DisplayPrecision = .standard
 
     private var selectedMeasurements: [Measurement] {
         measurementSystem.selectedMeasurements
@@ -940,6 +1115,7 @@ struct SelectedMeasurementsPanel: View {
             .compactMap { index in
                 index < measurementSystem.measurements.count ? measurementSystem.measurements[index] : nil
             }
+            .map { $0.translated(by: coordinateOffset) }
     }
 
     private var distanceMeasurements: [Measurement] {
@@ -962,7 +1138,7 @@ struct SelectedMeasurementsPanel: View {
             ForEach(Array(selectedMeasurements.enumerated()), id: \.offset) { index, measurement in
                 if measurement.type == .distance && measurement.points.count >= 2 {
                     VStack(alignment: .leading, spacing: 2) {
-                        Text("Line \(index + 1): \(measurement.formattedValue)")
+                        Text("Line \(index + 1): \(measurement.formattedValue(showDiameter: false, precision: precision))")
                             .font(.system(size: 9, weight: .medium))
                             .foregroundColor(.yellow)
 
@@ -979,7 +1155,7 @@ struct SelectedMeasurementsPanel: View {
                     }
                 } else if measurement.type == .radius, let circle = measurement.circle {
                     VStack(alignment: .leading, spacing: 2) {
-                        Text("Circle: r=\(measurement.formattedValue)")
+                        Text("Circle: r=\(measurement.formattedValue(showDiameter: false, precision: precision))")
                             .font(.system(size: 9, weight: .medium))
                             .foregroundColor(Color(red: 1.0, green: 0.59, blue: 1.0))
 
@@ -989,7 +1165,7 @@ struct SelectedMeasurementsPanel: View {
                     }
                 } else if measurement.type == .angle && measurement.points.count >= 3 {
                     VStack(alignment: .leading, spacing: 2) {
-                        Text("Angle: \(measurement.formattedValue)")
+                        Text("Angle: \(measurement.formattedValue(showDiameter: false, precision: precision))")
                             .font(.system(size: 9, weight: .medium))
                             .foregroundColor(.cyan)
 
@@ -1001,6 +1177,16 @@ struct SelectedMeasurementsPanel: View {
                 }
             }
 
+            // Note of a single selected measurement (shown in the label tooltip)
+            if measurementSystem.selectedMeasurements.count == 1,
+               let index = measurementSystem.selectedMeasurements.first,
+               index < measurementSystem.measurements.count {
+                MeasurementNoteField(note: measurementSystem.measurements[index].note ?? "") { note in
+                    measurementSystem.setNote(note, forMeasurement: index)
+                }
+                .id(index)
+            }
+
             // Copy as polygon button (only for distance measurements)
             if !distanceMeasurements.isEmpty {
                 Button(action: {
@@ -1063,7 +1249,7 @@ struct SelectedMeasurementsPanel: View {
     }
 
     private func formatCoord(_ value: Double) -> String {
-        String(format: "%.2f", value)
+        precision.length(value)
     }
 }
 
@@ -0,0 +1,125 @@
+import SwiftUI
+import AppKit
+
+/// Panel showing value changes of a baseline measurement export on the current model
+struct MeasurementComparisonPanel: View {
+    let comparison: MeasurementComparison
+    let onClose: () -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("MEASUREMENT COMPARISON")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+            }
+
+            Text("\(comparison.baselineName) → \(comparison.currentName)")
+                .font(.system(size: 10))
+                .foregroundColor(.white.opacity(0.6))
+                .lineLimit(1)
+                .truncationMode(.middle)
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            if comparison.rows.isEmpty {
+                Text("No measurements in baseline")
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.6))
+            }
+
+            ScrollView {
+                VStack(alignment: .leading, spacing: 4) {
+                    ForEach(comparison.rows) { row in
+                        rowView(row)
+                    }
+                }
+            }
+            .frame(maxHeight: 220)
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            HStack {
+                Text("\(comparison.exceeded.count) of \(comparison.rows.count) exceed threshold")
+                    .font(.system(size: 10))
+                    .foregroundColor(comparison.exceeded.isEmpty ? .green : .red)
+
+                Spacer()
+
+                Button("Copy CSV") {
+                    copy(comparison.csv(format: AppSettings.shared.csvFormat))
+                }
+                .controlSize(.small)
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+
+    private func rowView(_ row: MeasurementComparison.Row) -> some View {
+        HStack(spacing: 6) {
+            Circle()
+                .fill(color(for: row.status))
+                .frame(width: 6, height: 6)
+
+            Text(row.name)
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.8))
+
+            if row.staleCount > 0 {
+                Image(systemName: "exclamationmark.triangle.fill")
+                    .font(.system(size: 9))
+                    .foregroundColor(.orange)
+                    .help("\(row.staleCount) point(s) have no vertex nearby on this revision")
+            }
+
+            Spacer()
+
+            Text(valueText(for: row))
+                .font(.system(size: 11, design: .monospaced))
+                .foregroundColor(row.status == .exceeded ? .red : .white)
+        }
+    }
+
+    private func valueText(for row: MeasurementComparison.Row) -> String {
+        switch row.status {
+        case .added:
+            return String(format: "new %.3f", row.current ?? 0)
+        case .removed:
+            return "removed"
+        default:
+            return String(format: "%.3f %+.3f %@", row.current ?? 0, row.delta ?? 0, row.unit)
+        }
+    }
+
+    private func color(for status: MeasurementComparison.Status) -> Color {
+        switch status {
+        case .unchanged: return .green
+        case .changed: return .yellow
+        case .exceeded: return .red
+        case .added, .removed: return .gray
+        }
+    }
+
+    private func copy(_ text: String) {
+        let pasteboard = NSPasteboard.general
+        pasteboard.clearContents()
+        pasteboard.setString(text, forType: .string)
+    }
+}
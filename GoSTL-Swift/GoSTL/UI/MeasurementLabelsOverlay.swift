@@ -5,36 +5,50 @@ struct MeasurementLabelsOverlay: View {
     let measurementSystem: MeasurementSystem
     let camera: Camera
     let viewSize: CGSize
+    /// Recentering offset added back so tooltip coordinates match the original file
+    var coordinateOffset: Vector3 = .zero
+    var precision: DisplayPrecision = .standard
+    /// Size of the labels relative to normal (larger in presentation mode)
+    var labelScale: CGFloat = 1
+
+    /// Keeps label placements stable across frames
+    @State private var layout = LabelLayout()
 
     var body: some View {
         GeometryReader { geometry in
-            ZStack {
-                // Show labels for completed measurements
-                ForEach(Array(measurementSystem.measurements.enumerated()), id: \.offset) { index, measurement in
-                    if let screenPos = camera.project(worldPosition: measurement.labelPosition, viewSize: viewSize) {
-                        let isSelected = measurementSystem.selectedMeasurements.contains(index)
-                        let isStale = measurement.hasStalePoints
-                        let baseColor: Color = {
-                            if isStale {
-                                return Color(red: 0.5, green: 0.5, blue: 0.5)  // Gray for stale
-                            } else if measurement.type == .radius {
-                                return Color(red: 1.0, green: 0.59, blue: 1.0)
-                            } else {
-                                return .yellow
-                            }
-                        }()
-                        let labelColor: Color = isSelected ? Color(red: 0.3, green: 0.5, blue: 1.0) : baseColor
+            let placements = layout.layout(labelItems(), in: CGRect(origin: .zero, size: viewSize))
+            let _ = publishFrames(placements)
 
-                        MeasurementLabel(
-                            text: measurement.formattedValue(showDiameter: measurementSystem.showDiameter),
-                            position: screenPos,
-                            color: labelColor,
-                            isSelected: isSelected,
-                            onTap: {
-                                toggleSelection(index: index)
-                            }
-                        )
+            ZStack {
+                // Leader lines from displaced labels back to their anchors
+                ForEach(placements.filter(\.isDisplaced), id: \.id) { placement in
+                    let color = labelColor(index: placement.id)
+                    Path { path in
+                        path.move(to: placement.anchor)
+                        path.addLine(to: placement.leaderEnd)
                     }
+                    .stroke(color.opacity(0.8), lineWidth: 1)
+
+                    Circle()
+                        .fill(color)
+                        .frame(width: 4, height: 4)
+                        .position(placement.anchor)
+                }
+
+                // Labels of completed measurements, merged labels are shown as a count
+                ForEach(placements, id: \.id) { placement in
+                    let index = placement.id
+                    MeasurementLabel(
+                        text: labelText(index: index),
+                        position: placement.center,
+                        color: labelColor(index: index),
+                        isSelected: measurementSystem.selectedMeasurements.contains(index),
+                        clusterCount: placement.clustered.count,
+                        scale: labelScale,
+                        onTap: {
+                            toggleSelection(index: index)
+                        }
+                    )
                 }
 
                 // Show preview label (green) when measuring
@@ -50,18 +64,86 @@ struct MeasurementLabelsOverlay: View {
 
                     if let screenPos = camera.project(worldPosition: midpoint, viewSize: viewSize) {
                         MeasurementLabel(
-                            text: formatDistance(previewDistance),
+                            text: precision.labelLength(previewDistance),
                             position: screenPos,
-                            color: .green
+                            color: .green,
+                            scale: labelScale
                         )
                     }
                 }
+
+                // Details of the hovered label
+                if let index = measurementSystem.hoveredLabel,
+                   let placement = placements.first(where: { $0.id == index }) {
+                    let measurement = measurementSystem.measurements[index].translated(by: coordinateOffset)
+                    let tooltip = MeasurementTooltip(
+                        rows: measurement.detailRows(showDiameter: measurementSystem.showDiameter),
+                        clusteredCount: placement.clustered.count
+                    )
+                    tooltip
+                        .fixedSize()
+                        .position(tooltipPosition(size: tooltip.estimatedSize, below: placement.frame))
+                }
             }
             .frame(width: geometry.size.width, height: geometry.size.height)
             .allowsHitTesting(false)
         }
     }
 
+    /// Hand the label frames to the input handler for hit testing and hover
+    private func publishFrames(_ placements: [LabelLayout.Placement]) {
+        measurementSystem.labelFrames = Dictionary(uniqueKeysWithValues: placements.map { ($0.id, $0.frame) })
+        measurementSystem.labelFramesViewSize = viewSize
+    }
+
+    /// Center of the tooltip below the label, or above it near the bottom of the view
+    private func tooltipPosition(size: CGSize, below frame: CGRect) -> CGPoint {
+        let x = min(max(frame.midX, size.width / 2 + 4), viewSize.width - size.width / 2 - 4)
+        let below = frame.maxY + 6 + size.height / 2
+        let y = below + size.height / 2 <= viewSize.height ? below : frame.minY - 6 - size.height / 2
+        return CGPoint(x: x, y: y)
+    }
+
+    /// Layout items for the measurements in front of the camera
+    /// Selected measurements come first, then the ones spanning more of the screen.
+    private func labelItems() -> [LabelLayout.Item] {
+        measurementSystem.measurements.enumerated().compactMap { index, measurement in
+            guard let anchor = camera.project(worldPosition: measurement.labelPosition, viewSize: viewSize) else {
+                return nil
+            }
+            let projected = measurement.points.compactMap { camera.project(worldPosition: $0.position, viewSize: viewSize) }
+            let span = projected.flatMap { a in projected.map { b in hypot(a.x - b.x, a.y - b.y) } }.max() ?? 0
+            let isSelected = measurementSystem.selectedMeasurements.contains(index)
+            return LabelLayout.Item(
+                id: index,
+                anchor: anchor,
+                size: scaled(LabelLayout.estimatedSize(of: labelText(index: index))),
+                priority: (isSelected ? 1_000_000 : 0) + Double(span)
+            )
+        }
+    }
+
+    private func scaled(_ size: CGSize) -> CGSize {
+        CGSize(width: size.width * labelScale, height: size.height * labelScale)
+    }
+
+    private func labelText(index: Int) -> String {
+        measurementSystem.measurements[index].formattedValue(showDiameter: measurementSystem.showDiameter, precision: precision)
+    }
+
+    private func labelColor(index: Int) -> Color {
+        let measurement = measurementSystem.measurements[index]
+        if measurementSystem.selectedMeasurements.contains(index) {
+            return Color(red: 0.3, green: 0.5, blue: 1.0)
+        } else if measurement.hasStalePoints {
+            return Color(red: 0.5, green: 0.5, blue: 0.5)  // Gray for stale
+        } else if measurement.type == .radius {
+            return Color(red: 1.0, green: 0.59, blue: 1.0)
+        } else {
+            return .yellow
+        }
+    }
+
     private func toggleSelection(index: Int) {
         if measurementSystem.selectedMeasurements.contains(index) {
             measurementSystem.selectedMeasurements.remove(index)
@@ -69,10 +151,6 @@ struct MeasurementLabelsOverlay: View {
             measurementSystem.selectedMeasurements.insert(index)
         }
     }
-
-    private func formatDistance(_ value: Double) -> String {
-        return String(format: "%.2f", value)
-    }
 }
 
 /// A single measurement label at a specific screen position
@@ -81,30 +159,85 @@ private struct MeasurementLabel: View {
     let position: CGPoint
     let color: Color
     var isSelected: Bool = false
+    /// Number of nearby labels merged into this one
+    var clusterCount: Int = 0
+    var scale: CGFloat = 1
     var onTap: (() -> Void)? = nil
 
     var body: some View {
-        Text(text)
-            .font(.system(size: 12, weight: .semibold, design: .monospaced))
-            .foregroundColor(.white)
-            .padding(.horizontal, 6)
-            .padding(.vertical, 3)
-            .background(
+        HStack(spacing: 4) {
+            Text(text)
+            if clusterCount > 0 {
+                Text("+\(clusterCount)")
+                    .font(.system(size: 10, weight: .bold, design: .monospaced))
+                    .padding(.horizontal, 3)
+                    .background(Capsule().fill(Color.black.opacity(0.35)))
+            }
+        }
+        .font(.system(size: 12, weight: .semibold, design: .monospaced))
+        .foregroundColor(.white)
+        .padding(.horizontal, 6)
+        .padding(.vertical, 3)
+        .background(
+            RoundedRectangle(cornerRadius: 4)
+                .fill(color.opacity(0.9))
+                .shadow(color: .black.opacity(0.5), radius: 2, x: 0, y: 1)
+        )
+        .overlay(
+            // Add border for selected labels
+            isSelected ?
                 RoundedRectangle(cornerRadius: 4)
-                    .fill(color.opacity(0.9))
-                    .shadow(color: .black.opacity(0.5), radius: 2, x: 0, y: 1)
-            )
-            .overlay(
-                // Add border for selected labels
-                isSelected ?
-                    RoundedRectangle(cornerRadius: 4)
-                        .stroke(Color.white, lineWidth: 2)
-                    : nil
-            )
-            .scaleEffect(isSelected ? 1.1 : 1.0)  // Slightly larger when selected
-            .position(position)
-            .onTapGesture {
-                onTap?()
+                    .stroke(Color.white, lineWidth: 2)
+                : nil
+        )
+        .scaleEffect((isSelected ? 1.1 : 1.0) * scale)  // Slightly larger when selected
+        .position(position)
+        .onTapGesture {
+            onTap?()
+        }
+    }
+}
+
+/// Tooltip with the full details of a hovered measurement label
+private struct MeasurementTooltip: View {
+    let rows: [(label: String, value: String)]
+    /// Number of labels merged into the hovered one
+    var clusteredCount: Int = 0
+
+    private static let labelWidth: CGFloat = 56
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 2) {
+            ForEach(Array(rows.enumerated()), id: \.offset) { _, row in
+                HStack(alignment: .top, spacing: 6) {
+                    Text(row.label)
+                        .font(.system(size: 10))
+                        .foregroundColor(.white.opacity(0.6))
+                        .frame(width: Self.labelWidth, alignment: .leading)
+                    Text(row.value)
+                        .font(.system(size: 10, design: .monospaced))
+                        .foregroundColor(.white)
+                        .italic(row.label == "Note")
+                }
+            }
+            if clusteredCount > 0 {
+                Text("+\(clusteredCount) more measurements nearby, zoom in to separate")
+                    .font(.system(size: 9))
+                    .foregroundColor(.white.opacity(0.5))
             }
+        }
+        .padding(8)
+        .background(
+            RoundedRectangle(cornerRadius: 6)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.4), radius: 6, x: 0, y: 2)
+        )
+    }
+
+    /// Approximate size, used to keep the tooltip inside the view
+    var estimatedSize: CGSize {
+        let longest = rows.map(\.value.count).max() ?? 0
+        let lines = rows.count + (clusteredCount > 0 ? 1 : 0)
+        return CGSize(width: Self.labelWidth + 6 + CGFloat(longest) * 6.1 + 16, height: CGFloat(lines) * 14 + 16)
     }
 }
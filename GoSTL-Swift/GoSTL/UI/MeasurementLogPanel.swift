@@ -0,0 +1,114 @@
+import SwiftUI
+import AppKit
+
+/// Panel listing measurement results and keyboard picking feedback as plain text
+/// Complements the 3D labels for screen reader and keyboard-only users
+struct MeasurementLogPanel: View {
+    let announcer: MeasurementAnnouncer
+    /// Fill a detached window instead of the fixed size over the 3D view
+    var fillsWindow: Bool = false
+    let onClose: () -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 0) {
+            // Header
+            HStack(spacing: 6) {
+                Image(systemName: "text.bubble")
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.7))
+                Text("Measurement Log")
+                    .font(.system(size: 11, weight: .semibold))
+                    .foregroundColor(.white)
+
+                Spacer()
+
+                Button(action: copyLog) {
+                    Image(systemName: "doc.on.doc")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Copy log")
+                .accessibilityLabel("Copy log")
+                .disabled(announcer.entries.isEmpty)
+
+                Button(action: { announcer.clear() }) {
+                    Image(systemName: "trash")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Clear log")
+                .accessibilityLabel("Clear log")
+                .disabled(announcer.entries.isEmpty)
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Close")
+                .accessibilityLabel("Close measurement log")
+            }
+            .padding(.horizontal, 10)
+            .padding(.vertical, 6)
+
+            Rectangle()
+                .fill(Color.white.opacity(0.15))
+                .frame(height: 1)
+
+            // Entries (newest at the bottom)
+            ScrollViewReader { proxy in
+                ScrollView {
+                    VStack(alignment: .leading, spacing: 3) {
+                        if announcer.entries.isEmpty {
+                            Text("Press Tab while measuring to cycle snap points, Return to pick")
+                                .font(.system(size: 10))
+                                .foregroundColor(.white.opacity(0.5))
+                        }
+                        ForEach(announcer.entries) { entry in
+                            HStack(alignment: .top, spacing: 6) {
+                                Text(entry.date, format: .dateTime.hour().minute().second())
+                                    .font(.system(size: 9, design: .monospaced))
+                                    .foregroundColor(.white.opacity(0.4))
+                                Text(entry.text)
+                                    .font(.system(size: 10, design: .monospaced))
+                                    .foregroundColor(.white.opacity(0.9))
+                                    .textSelection(.enabled)
+                            }
+                            .id(entry.id)
+                            .accessibilityElement(children: .combine)
+                        }
+                    }
+                    .padding(8)
+                    .frame(maxWidth: .infinity, alignment: .leading)
+                }
+                .onChange(of: announcer.entries.count) { _, _ in
+                    if let last = announcer.entries.last {
+                        proxy.scrollTo(last.id, anchor: .bottom)
+                    }
+                }
+            }
+        }
+        .frame(width: fillsWindow ? nil : 320, height: fillsWindow ? nil : 180)
+        .frame(maxWidth: fillsWindow ? .infinity : nil, maxHeight: fillsWindow ? .infinity : nil)
+        .background(
+            RoundedRectangle(cornerRadius: 6)
+                .fill(Color.black.opacity(0.85))
+                .overlay(
+                    RoundedRectangle(cornerRadius: 6)
+                        .stroke(Color.white.opacity(0.2), lineWidth: 1)
+                )
+        )
+        .clipShape(RoundedRectangle(cornerRadius: 6))
+        .accessibilityElement(children: .contain)
+        .accessibilityLabel("Measurement log")
+    }
+
+    private func copyLog() {
+        let pasteboard = NSPasteboard.general
+        pasteboard.clearContents()
+        pasteboard.setString(announcer.logText, forType: .string)
+    }
+}
@@ -76,6 +76,27 @@ struct MeasurementOverlay: View {
                             .font(.system(size: 9))
                             .foregroundColor(.white.opacity(0.6))
                             .italic()
+
+                        // Grid snap status (S toggles, Control inverts while held)
+                        HStack(spacing: 4) {
+                            if measurementSystem.isGridSnapActive {
+                                RoundedRectangle(cornerRadius: 2)
+                                    .fill(Color.green)
+                                    .frame(width: 6, height: 6)
+                                Text(String(format: "Grid snap: %g mm", measurementSystem.gridSnapSpacing))
+                                    .font(.system(size: 10, weight: .medium))
+                                    .foregroundColor(.green)
+                            } else {
+                                KeyHint_MeasurementLegacy(key: "s")
+                                Text("or hold")
+                                    .font(.system(size: 9))
+                                    .foregroundColor(.white.opacity(0.6))
+                                KeyHint_MeasurementLegacy(key: "⌃")
+                                Text("to snap to grid")
+                                    .font(.system(size: 9))
+                                    .foregroundColor(.white.opacity(0.6))
+                            }
+                        }
                     }
 
                     if mode == .distance {
@@ -194,6 +215,8 @@ struct MeasurementOverlay: View {
             return ["X", "Y", "Z"][axis]
         case .point:
             return "→"
+        case .direction:
+            return "∥"
         }
     }
 
@@ -208,6 +231,8 @@ struct MeasurementOverlay: View {
             }
         case .point:
             return Color.cyan
+        case .direction:
+            return Color.orange
         }
     }
 }
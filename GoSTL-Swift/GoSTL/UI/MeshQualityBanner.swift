@@ -0,0 +1,91 @@
+import SwiftUI
+import Metal
+
+/// Non-blocking banner shown after loading a model with mesh defects, with details and a repair
+struct MeshQualityBanner: View {
+    let appState: AppState
+    let quality: MeshQuality
+    @State private var showDetails = false
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Image(systemName: "exclamationmark.triangle.fill")
+                    .foregroundColor(.orange)
+                Text(quality.summary)
+                    .font(.system(size: 11, weight: .medium))
+                    .foregroundColor(.white)
+                    .lineLimit(1)
+                    .truncationMode(.tail)
+
+                Spacer(minLength: 8)
+
+                Button(showDetails ? "Hide Details" : "Details") {
+                    withAnimation(.easeInOut(duration: 0.2)) { showDetails.toggle() }
+                }
+                if quality.issues.contains(where: \.isRepairable) {
+                    Button("Fix") {
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        try? appState.repairMesh(device: device)
+                    }
+                    .help("Weld near-duplicate vertices, remove degenerate triangles and turn flipped ones around; save the model to keep the repair")
+                }
+
+                Button(action: { appState.dismissMeshQuality() }) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Hide until another file is opened")
+            }
+            .controlSize(.small)
+
+            if showDetails {
+                Divider()
+                    .background(Color.white.opacity(0.3))
+
+                ForEach(quality.issues, id: \.self) { issue in
+                    HStack(alignment: .top, spacing: 6) {
+                        VStack(alignment: .leading, spacing: 2) {
+                            Text(quality.message(for: issue))
+                                .font(.system(size: 11, weight: .semibold))
+                                .foregroundColor(.white)
+                            Text(issue.explanation)
+                                .font(.system(size: 10))
+                                .foregroundColor(.white.opacity(0.7))
+                                .fixedSize(horizontal: false, vertical: true)
+                        }
+                        Spacer()
+                        if !quality.triangles(of: issue).isEmpty {
+                            Button("Select") {
+                                appState.selectMeshIssue(issue)
+                            }
+                            .controlSize(.small)
+                            .help("Select the affected triangles")
+                        }
+                    }
+                }
+
+                if quality.issues.contains(where: { !$0.isRepairable }) {
+                    Text("Slivers, holes and non-manifold edges need to be fixed in the CAD model or a mesh repair tool.")
+                        .font(.system(size: 10))
+                        .foregroundColor(.white.opacity(0.6))
+                        .fixedSize(horizontal: false, vertical: true)
+                }
+            }
+        }
+        .padding(.horizontal, 12)
+        .padding(.vertical, 8)
+        .background(
+            RoundedRectangle(cornerRadius: 8)
+                .fill(.ultraThinMaterial)
+                .overlay(
+                    RoundedRectangle(cornerRadius: 8)
+                        .stroke(Color.orange.opacity(0.6), lineWidth: 1)
+                )
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(maxWidth: 520)
+    }
+}
@@ -0,0 +1,204 @@
+import SwiftUI
+import AppKit
+
+/// Panel showing file metadata and the user-defined properties stored in the model's sidecar
+struct MetadataPanel: View {
+    let appState: AppState
+    let onClose: () -> Void
+
+    @State private var newKey = ""
+    @State private var newValue = ""
+    @State private var errorMessage: String?
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("METADATA")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            if let metadata = appState.modelMetadata {
+                ScrollView {
+                    VStack(alignment: .leading, spacing: 4) {
+                        ForEach(metadata.file) { entry in
+                            EntryRow(entry: entry)
+                        }
+                        if !metadata.embedded.isEmpty {
+                            SectionTitle(title: "Embedded")
+                            ForEach(metadata.embedded) { entry in
+                                EntryRow(entry: entry)
+                            }
+                        }
+                    }
+                }
+                .frame(maxHeight: 200)
+            } else {
+                Text("No file loaded")
+                    .font(.system(size: 11))
+                    .foregroundColor(.white.opacity(0.6))
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.2))
+
+            SectionTitle(title: "Properties")
+
+            ForEach(appState.modelProperties) { property in
+                PropertyRow(
+                    property: property,
+                    onChange: { key, value in
+                        perform { try appState.updateModelProperty(property.id, key: key, value: value) }
+                    },
+                    onDelete: {
+                        perform { try appState.removeModelProperty(property.id) }
+                    }
+                )
+            }
+
+            HStack(spacing: 4) {
+                TextField("Key", text: $newKey)
+                    .frame(width: 90)
+                TextField("Value", text: $newValue)
+                    .onSubmit(addProperty)
+                Button(action: addProperty) {
+                    Image(systemName: "plus")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.8))
+                .disabled(newKey.trimmingCharacters(in: .whitespaces).isEmpty)
+            }
+            .textFieldStyle(.roundedBorder)
+            .font(.system(size: 10))
+            .disabled(appState.sourceFileURL == nil)
+
+            if let errorMessage {
+                Text(errorMessage)
+                    .font(.system(size: 10))
+                    .foregroundColor(.red)
+                    .fixedSize(horizontal: false, vertical: true)
+            } else if let url = appState.sourceFileURL {
+                Text("Stored in \(ModelSidecar.url(for: url).lastPathComponent)")
+                    .font(.system(size: 9))
+                    .foregroundColor(.white.opacity(0.5))
+                    .italic()
+                    .lineLimit(1)
+                    .truncationMode(.middle)
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+
+    private func addProperty() {
+        let key = newKey.trimmingCharacters(in: .whitespaces)
+        guard !key.isEmpty else { return }
+        perform {
+            try appState.addModelProperty(key: key, value: newValue)
+            newKey = ""
+            newValue = ""
+        }
+    }
+
+    private func perform(_ change: () throws -> Void) {
+        do {
+            try change()
+            errorMessage = nil
+        } catch {
+            errorMessage = "Could not save properties: \(error.localizedDescription)"
+        }
+    }
+}
+
+private struct SectionTitle: View {
+    let title: String
+
+    var body: some View {
+        Text(title.uppercased())
+            .font(.system(size: 10, weight: .medium))
+            .foregroundColor(.white.opacity(0.6))
+            .padding(.top, 4)
+    }
+}
+
+private struct EntryRow: View {
+    let entry: ModelMetadata.Entry
+
+    var body: some View {
+        HStack(alignment: .top, spacing: 6) {
+            Text(entry.label)
+                .font(.system(size: 10))
+                .foregroundColor(.white.opacity(0.6))
+                .frame(width: 80, alignment: .leading)
+            Text(entry.value)
+                .font(.system(size: 10, design: .monospaced))
+                .foregroundColor(.white.opacity(0.9))
+                .textSelection(.enabled)
+                .fixedSize(horizontal: false, vertical: true)
+        }
+    }
+}
+
+/// Editable property; changes are saved when a field is submitted
+private struct PropertyRow: View {
+    let property: ModelProperty
+    let onChange: (String, String) -> Void
+    let onDelete: () -> Void
+
+    @State private var key: String
+    @State private var value: String
+
+    init(property: ModelProperty, onChange: @escaping (String, String) -> Void, onDelete: @escaping () -> Void) {
+        self.property = property
+        self.onChange = onChange
+        self.onDelete = onDelete
+        _key = State(initialValue: property.key)
+        _value = State(initialValue: property.value)
+    }
+
+    var body: some View {
+        HStack(spacing: 4) {
+            TextField("Key", text: $key)
+                .frame(width: 90)
+                .onSubmit(commit)
+            TextField("Value", text: $value)
+                .onSubmit(commit)
+            Button(action: onDelete) {
+                Image(systemName: "trash")
+                    .font(.system(size: 9))
+            }
+            .buttonStyle(.plain)
+            .foregroundColor(.white.opacity(0.6))
+        }
+        .textFieldStyle(.roundedBorder)
+        .font(.system(size: 10))
+    }
+
+    private func commit() {
+        let trimmed = key.trimmingCharacters(in: .whitespaces)
+        guard !trimmed.isEmpty else {
+            key = property.key
+            return
+        }
+        guard trimmed != property.key || value != property.value else { return }
+        onChange(trimmed, value)
+    }
+}
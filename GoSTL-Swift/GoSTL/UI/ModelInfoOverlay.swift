@@ -46,7 +46,11 @@ struct ModelInfoOverlay: View {
                     .foregroundColor(.white)
                 KeyHint_Legacy(key: "m")
             }
+            if modelInfo.material.supportsInfill {
+                InfoRow_Legacy(label: "Infill:", value: String(format: "%.0f%%", modelInfo.estimate.infill * 100))
+            }
             InfoRow_Legacy(label: "Weight:", value: Material.formatWeight(modelInfo.weight))
+            InfoRow_Legacy(label: "Cost:", value: Material.formatCost(modelInfo.estimate.cost))
 
             Divider()
                 .background(Color.white.opacity(0.3))
@@ -58,11 +62,25 @@ struct ModelInfoOverlay: View {
                     .font(.system(size: 10))
                     .foregroundColor(.white.opacity(0.8))
                 Text(String(format: "%.1f, %.1f, %.1f",
-                           modelInfo.center.x, modelInfo.center.y, modelInfo.center.z))
+                           modelInfo.originalCenter.x, modelInfo.originalCenter.y, modelInfo.originalCenter.z))
                     .font(.system(size: 10, design: .monospaced))
                     .foregroundColor(.white)
             }
 
+            // Recentering offset for models far from the origin
+            if modelInfo.isRecentered {
+                VStack(alignment: .leading, spacing: 2) {
+                    Text("Recentered by:")
+                        .font(.system(size: 10))
+                        .foregroundColor(.white.opacity(0.8))
+                    Text(String(format: "%.0f, %.0f, %.0f",
+                               modelInfo.coordinateOffset.x, modelInfo.coordinateOffset.y, modelInfo.coordinateOffset.z))
+                        .font(.system(size: 10, design: .monospaced))
+                        .foregroundColor(.white.opacity(0.6))
+                }
+                .help("The model is rendered near the origin for precision; readouts use the original coordinates")
+            }
+
             // Help text
             HStack(spacing: 4) {
                 KeyHint_Legacy(key: "i")
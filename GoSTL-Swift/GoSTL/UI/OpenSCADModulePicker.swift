@@ -0,0 +1,72 @@
+import SwiftUI
+import Metal
+
+/// Main menu section for rendering a single top-level module of the open .scad file
+struct OpenSCADModuleSectionContent: View {
+    let appState: AppState
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 3) {
+            HStack(spacing: 4) {
+                Text("Module:")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.8))
+                OpenSCADModulePicker(
+                    modules: appState.openSCADModules,
+                    isolatedModule: appState.isolatedModule,
+                    appState: appState
+                )
+            }
+
+            if let name = appState.isolatedModule,
+               let module = appState.openSCADModules.first(where: { $0.name == name }),
+               !module.requiredParameters.isEmpty {
+                Text("Called without \(module.requiredParameters.joined(separator: ", ")) (undef)")
+                    .font(.system(size: 9))
+                    .foregroundColor(.orange.opacity(0.8))
+            }
+        }
+    }
+}
+
+/// Dropdown of the file's top-level modules with "Whole file" first
+struct OpenSCADModulePicker: View {
+    let modules: [OpenSCADModule]
+    let isolatedModule: String?
+    let appState: AppState
+
+    var body: some View {
+        Menu {
+            Button("Whole file") { isolate(nil) }
+
+            Divider()
+
+            ForEach(modules) { module in
+                Button(module.signature) { isolate(module.name) }
+            }
+        } label: {
+            HStack(spacing: 4) {
+                Text(isolatedModule.map { "\($0)()" } ?? "Whole file")
+                    .font(.system(size: 9, design: isolatedModule == nil ? .default : .monospaced))
+                    .foregroundColor(.white.opacity(0.9))
+                Spacer()
+                Image(systemName: "chevron.down")
+                    .font(.system(size: 8))
+                    .foregroundColor(.white.opacity(0.6))
+            }
+            .padding(.horizontal, 6)
+            .padding(.vertical, 4)
+            .background(
+                RoundedRectangle(cornerRadius: 3)
+                    .fill(isolatedModule != nil ? Color.blue.opacity(0.2) : Color.white.opacity(0.1))
+            )
+        }
+        .menuStyle(.borderlessButton)
+        .fixedSize()
+    }
+
+    private func isolate(_ name: String?) {
+        guard let device = MTLCreateSystemDefaultDevice() else { return }
+        appState.isolateModule(name, device: device)
+    }
+}
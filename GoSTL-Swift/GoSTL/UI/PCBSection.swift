@@ -0,0 +1,103 @@
+import SwiftUI
+import Metal
+
+/// Main menu section of the PCB preset: board size, Z exaggeration and component heights
+struct PCBSectionContent: View {
+    let appState: AppState
+
+    /// Components listed per side (the tallest ones matter for enclosure fitting)
+    private static let listedComponents = 8
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 4) {
+            // Z exaggeration
+            HStack(spacing: 3) {
+                Text("Z:")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.8))
+                ForEach(AppState.pcbExaggerationOptions, id: \.self) { factor in
+                    PCBExaggerationRadio(factor: factor, current: appState.pcbZExaggeration, appState: appState)
+                }
+            }
+
+            if let board = appState.pcbBoard {
+                InfoRow(label: "Board:", value: String(format: "%.1f × %.1f × %.2f mm",
+                                                        board.outline.size.x, board.outline.size.y, board.thickness))
+                InfoRow(label: "Top:", value: String(format: "%.2f mm", board.topClearance))
+                InfoRow(label: "Bottom:", value: String(format: "%.2f mm", board.bottomClearance))
+                InfoRow(label: "Stack:", value: String(format: "%.2f mm", board.stackHeight))
+
+                if !board.components.isEmpty {
+                    Button(action: { appState.measureTallestComponents() }) {
+                        Text("Measure Tallest")
+                            .font(.system(size: 10))
+                            .foregroundColor(.white.opacity(0.8))
+                    }
+                    .buttonStyle(.plain)
+                    .padding(.vertical, 2)
+                }
+
+                ForEach(Array(board.topComponents.prefix(Self.listedComponents) + board.bottomComponents.prefix(Self.listedComponents))) { component in
+                    PCBComponentRow(component: component) {
+                        appState.measureComponentHeight(component)
+                    }
+                }
+            } else {
+                Text("No board found (two flat faces 0.2–3.5 mm apart)")
+                    .font(.system(size: 9))
+                    .foregroundColor(.orange.opacity(0.8))
+            }
+        }
+    }
+}
+
+/// Radio button for one Z exaggeration factor
+struct PCBExaggerationRadio: View {
+    let factor: Float
+    let current: Float
+    let appState: AppState
+
+    var body: some View {
+        Button(action: {
+            if let device = MTLCreateSystemDefaultDevice() {
+                appState.setPCBZExaggeration(factor, device: device)
+            }
+        }) {
+            Text(String(format: "%g×", factor))
+                .font(.system(size: 9))
+                .foregroundColor(.white.opacity(0.8))
+                .padding(.horizontal, 6)
+                .padding(.vertical, 3)
+                .background(
+                    RoundedRectangle(cornerRadius: 3)
+                        .fill(current == factor ? Color.blue.opacity(0.2) : Color.white.opacity(0.1))
+                )
+        }
+        .buttonStyle(.plain)
+    }
+}
+
+/// Component height and footprint; clicking adds a height measurement
+struct PCBComponentRow: View {
+    let component: PCBBoard.Component
+    let action: () -> Void
+
+    var body: some View {
+        Button(action: action) {
+            HStack(spacing: 4) {
+                Image(systemName: component.side == .top ? "arrow.up" : "arrow.down")
+                    .font(.system(size: 8))
+                    .foregroundColor(.white.opacity(0.5))
+                Text(String(format: "%.2f mm", component.height))
+                    .font(.system(size: 10, design: .monospaced))
+                    .foregroundColor(.white.opacity(0.8))
+                Spacer()
+                Text(String(format: "%.1f × %.1f", component.footprint.width, component.footprint.depth))
+                    .font(.system(size: 9))
+                    .foregroundColor(.white.opacity(0.5))
+            }
+        }
+        .buttonStyle(.plain)
+        .help(component.side == .top ? "Measure height above the board" : "Measure depth below the board")
+    }
+}
@@ -0,0 +1,100 @@
+import SwiftUI
+import AppKit
+
+/// Heads-up display with frame timing, draw statistics and memory usage
+/// Intended for performance reports and for tuning the acceleration structures
+struct PerformanceHUD: View {
+    let stats: PerformanceStats
+    let modelTriangleCount: Int
+
+    var body: some View {
+        let snapshot = stats.snapshot
+
+        VStack(alignment: .leading, spacing: 3) {
+            HStack {
+                Text("Performance")
+                    .font(.system(size: 10, weight: .semibold))
+                    .foregroundColor(.white)
+                Spacer()
+                Button(action: { copyReport(snapshot) }) {
+                    Image(systemName: "doc.on.doc")
+                        .font(.system(size: 9))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Copy report")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            HUDRow(label: "FPS", value: String(format: "%.0f", snapshot.fps))
+            HUDRow(label: "Frame", value: formatMs(snapshot.frameTime))
+            HUDRow(label: "  Encode", value: formatMs(snapshot.encodeTime))
+            HUDRow(label: "  GPU", value: formatMs(snapshot.gpuTime))
+            HUDRow(label: "  Picking", value: formatMs(snapshot.pickingTime))
+            HUDRow(label: "  Clipping", value: formatMs(snapshot.clippingTime))
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            HUDRow(label: "Draw calls", value: "\(snapshot.drawCalls)")
+            HUDRow(label: "Triangles", value: ModelInfo.formatCount(snapshot.trianglesDrawn))
+            HUDRow(label: "Lines", value: ModelInfo.formatCount(snapshot.linesDrawn))
+            HUDRow(label: "Model", value: ModelInfo.formatCount(modelTriangleCount))
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            HUDRow(label: "Memory", value: PerformanceStats.formatBytes(snapshot.residentMemory))
+            HUDRow(label: "GPU mem", value: PerformanceStats.formatBytes(UInt64(snapshot.gpuMemory)))
+        }
+        .padding(8)
+        .frame(width: 170)
+        .background(
+            RoundedRectangle(cornerRadius: 6)
+                .fill(Color.black.opacity(0.7))
+        )
+        .accessibilityElement(children: .combine)
+        .accessibilityLabel("Performance statistics")
+    }
+
+    private func formatMs(_ value: Double) -> String {
+        String(format: "%.2f ms", value)
+    }
+
+    /// Copy the current numbers as plain text (for bug reports)
+    private func copyReport(_ snapshot: PerformanceStats.Snapshot) {
+        let report = """
+        FPS: \(String(format: "%.1f", snapshot.fps))
+        Frame: \(formatMs(snapshot.frameTime)) (encode \(formatMs(snapshot.encodeTime)), GPU \(formatMs(snapshot.gpuTime)))
+        Picking: \(formatMs(snapshot.pickingTime))
+        Clipping: \(formatMs(snapshot.clippingTime))
+        Draw calls: \(snapshot.drawCalls)
+        Triangles drawn: \(snapshot.trianglesDrawn) (model: \(modelTriangleCount))
+        Lines drawn: \(snapshot.linesDrawn)
+        Memory: \(PerformanceStats.formatBytes(snapshot.residentMemory)), GPU: \(PerformanceStats.formatBytes(UInt64(snapshot.gpuMemory)))
+        """
+        let pasteboard = NSPasteboard.general
+        pasteboard.clearContents()
+        pasteboard.setString(report, forType: .string)
+    }
+}
+
+/// Single label/value row of the performance HUD
+private struct HUDRow: View {
+    let label: String
+    let value: String
+
+    var body: some View {
+        HStack {
+            Text(label)
+                .font(.system(size: 9))
+                .foregroundColor(.white.opacity(0.7))
+            Spacer()
+            Text(value)
+                .font(.system(size: 9, design: .monospaced))
+                .foregroundColor(.white)
+        }
+    }
+}
@@ -13,10 +13,17 @@ struct PlateSelector: View {
                 .font(.system(size: 12))
                 .foregroundColor(.white.opacity(0.8))
 
+            // All plates together
+            PlateButton(
+                title: "All",
+                isSelected: appState.selectedPlateId == nil,
+                action: { selectPlate(nil) }
+            )
+
             // Plate buttons
             ForEach(appState.availablePlates) { plate in
                 PlateButton(
-                    plate: plate,
+                    title: plate.name,
                     isSelected: appState.selectedPlateId == plate.id,
                     action: { selectPlate(plate.id) }
                 )
@@ -31,7 +38,7 @@ struct PlateSelector: View {
         )
     }
 
-    private func selectPlate(_ plateId: Int) {
+    private func selectPlate(_ plateId: Int?) {
         guard let device = MTLCreateSystemDefaultDevice() else { return }
         try? appState.selectPlate(plateId, device: device)
     }
@@ -39,13 +46,13 @@ struct PlateSelector: View {
 
 /// Individual plate button
 struct PlateButton: View {
-    let plate: ThreeMFPlate
+    let title: String
     let isSelected: Bool
     let action: () -> Void
 
     var body: some View {
         Button(action: action) {
-            Text(plate.name)
+            Text(title)
                 .font(.system(size: 11, weight: isSelected ? .semibold : .regular))
                 .foregroundColor(isSelected ? .white : .white.opacity(0.7))
                 .padding(.horizontal, 12)
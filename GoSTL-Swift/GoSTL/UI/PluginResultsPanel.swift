@@ -0,0 +1,69 @@
+import SwiftUI
+
+/// Panel showing the output of a plugin analyzer
+struct PluginResultsPanel: View {
+    let analysis: PluginAnalysis
+    let onClose: () -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text(analysis.title.uppercased())
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            if analysis.isRunning {
+                HStack(spacing: 6) {
+                    ProgressView()
+                        .controlSize(.small)
+                    Text("Running plugin...")
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.8))
+                }
+            } else if let errorMessage = analysis.errorMessage {
+                Text(errorMessage)
+                    .font(.system(size: 10))
+                    .foregroundColor(.red.opacity(0.9))
+                    .fixedSize(horizontal: false, vertical: true)
+            } else if let result = analysis.result {
+                if result.results.isEmpty {
+                    Text("No results")
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.6))
+                }
+                ForEach(Array(result.results.enumerated()), id: \.offset) { _, entry in
+                    HStack(spacing: 6) {
+                        Text(entry.name)
+                            .font(.system(size: 11))
+                            .foregroundColor(.white.opacity(0.8))
+                        Spacer()
+                        Text(entry.value)
+                            .font(.system(size: 11, design: .monospaced))
+                            .foregroundColor(.white)
+                            .textSelection(.enabled)
+                    }
+                }
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+}
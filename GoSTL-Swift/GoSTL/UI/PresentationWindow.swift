@@ -0,0 +1,22 @@
+import AppKit
+
+/// Full screen of the model window in presentation mode
+@MainActor
+enum PresentationWindow {
+    /// Windows that presentation mode switched to full screen (they leave it when it ends)
+    private static var enteredFullScreen: Set<Int> = []
+
+    static func enter(windowNumber: Int) {
+        guard let window = NSApp.window(withWindowNumber: windowNumber),
+              !window.styleMask.contains(.fullScreen) else { return }
+        enteredFullScreen.insert(windowNumber)
+        window.toggleFullScreen(nil)
+    }
+
+    static func leave(windowNumber: Int) {
+        guard enteredFullScreen.remove(windowNumber) != nil,
+              let window = NSApp.window(withWindowNumber: windowNumber),
+              window.styleMask.contains(.fullScreen) else { return }
+        window.toggleFullScreen(nil)
+    }
+}
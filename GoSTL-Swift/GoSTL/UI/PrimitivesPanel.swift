@@ -0,0 +1,119 @@
+import SwiftUI
+
+/// Panel of the primitive detection: surface share per primitive kind and the curved surfaces with their sizes
+struct PrimitivesPanel: View {
+    let appState: AppState
+    let onClose: () -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("PRIMITIVES")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Hide the primitive surfaces")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            if let result = appState.primitiveResult {
+                resultView(result)
+            } else {
+                HStack(spacing: 6) {
+                    ProgressView()
+                        .controlSize(.small)
+                    Text("Detecting surfaces...")
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.8))
+                }
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+
+    @ViewBuilder
+    private func resultView(_ result: PrimitiveDetection.Result) -> some View {
+        Text(String(format: "%.0f%% of the surface recognized", result.coverage * 100))
+            .font(.system(size: 12, weight: .semibold))
+            .foregroundColor(result.coverage >= 0.9 ? .green : .orange)
+
+        ForEach(PrimitiveDetection.Kind.allCases, id: \.self) { kind in
+            row(PrimitiveDetection.color(for: kind), "\(kind.pluralName) (\(result.count(of: kind)))", area(result.area(of: kind), of: result))
+        }
+
+        let curved = result.regions.indices.filter { result.regions[$0].shape.kind != .plane }
+        if !curved.isEmpty {
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            ScrollView {
+                VStack(alignment: .leading, spacing: 4) {
+                    ForEach(curved, id: \.self) { index in
+                        Button {
+                            appState.selectPrimitiveRegion(index)
+                        } label: {
+                            row(PrimitiveDetection.color(for: result.regions[index].shape.kind), label(result.regions[index]), "")
+                        }
+                        .buttonStyle(.plain)
+                        .help(PrimitiveDetection.describe(result.regions[index]))
+                    }
+                }
+            }
+            .frame(maxHeight: 220)
+        }
+    }
+
+    /// Short name and size of a curved region ("Ø 6.40 hole", "R 2.00 fillet")
+    private func label(_ region: PrimitiveDetection.Region) -> String {
+        switch region.shape {
+        case .cylinder(_, _, let radius, let length):
+            if region.sweep >= 360 {
+                return String(format: "Ø %.2f %@, %.2f long", 2 * radius, region.isConvex ? "boss" : "hole", length)
+            }
+            return String(format: "R %.2f %@, %.0f°", radius, region.isConvex ? "round" : "fillet", region.sweep)
+        case .cone(_, _, let halfAngle, let radii, _):
+            return String(format: "Cone Ø %.2f–%.2f, %.1f°", 2 * radii.lowerBound, 2 * radii.upperBound, halfAngle)
+        case .sphere(_, let radius):
+            return String(format: "Sphere Ø %.2f %@", 2 * radius, region.isConvex ? "ball" : "socket")
+        case .plane, .freeform:
+            return String(format: "Freeform, %.1f mm²", region.area)
+        }
+    }
+
+    private func row(_ color: TriangleColor, _ name: String, _ value: String) -> some View {
+        HStack(spacing: 6) {
+            RoundedRectangle(cornerRadius: 2)
+                .fill(Color(red: Double(color.r), green: Double(color.g), blue: Double(color.b)))
+                .frame(width: 10, height: 10)
+            Text(name)
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.8))
+            Spacer()
+            Text(value)
+                .font(.system(size: 11, design: .monospaced))
+                .foregroundColor(.white)
+                .textSelection(.enabled)
+        }
+    }
+
+    private func area(_ area: Double, of result: PrimitiveDetection.Result) -> String {
+        let share = result.totalArea > 0 ? area / result.totalArea * 100 : 0
+        return String(format: "%.1f mm² (%.0f%%)", area, share)
+    }
+}
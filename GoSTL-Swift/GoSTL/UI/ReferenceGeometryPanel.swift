@@ -0,0 +1,315 @@
+import SwiftUI
+
+/// Panel for creating and managing datum planes, axes and points
+struct ReferenceGeometryPanel: View {
+    let appState: AppState
+    let onClose: () -> Void
+
+    private var referenceGeometry: ReferenceGeometrySystem { appState.referenceGeometry }
+
+    /// Direction constraints only apply while a distance measurement has a start point
+    private var canConstrain: Bool {
+        appState.measurementSystem.mode == .distance && !appState.measurementSystem.currentPoints.isEmpty
+    }
+
+    /// Distance measurements to intersect with a plane: the selected ones, or all if none are selected
+    private var distanceSegments: [(start: Vector3, end: Vector3)] {
+        let measurementSystem = appState.measurementSystem
+        let selected = measurementSystem.selectedMeasurements
+        return measurementSystem.measurements.enumerated().compactMap { index, measurement in
+            guard measurement.type == .distance,
+                  measurement.points.count == 2,
+                  selected.isEmpty || selected.contains(index) else {
+                return nil
+            }
+            return (start: measurement.points[0].position, end: measurement.points[1].position)
+        }
+    }
+
+    /// Segment intersections need one selected plane and at least one distance measurement
+    private var canIntersectSegments: Bool {
+        referenceGeometry.selectedPlane != nil && !distanceSegments.isEmpty
+    }
+
+    private let toolColumns = [GridItem(.flexible(), spacing: 6), GridItem(.flexible(), spacing: 6)]
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("REFERENCE GEOMETRY")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            // Creation tools
+            LazyVGrid(columns: toolColumns, spacing: 6) {
+                ForEach(ReferenceTool.allCases, id: \.self) { tool in
+                    ToolButton(
+                        title: tool.title,
+                        isActive: referenceGeometry.activeTool == tool,
+                        action: { startTool(tool) }
+                    )
+                }
+                ToolButton(
+                    title: "Point (Intersect)",
+                    isActive: false,
+                    action: { referenceGeometry.createIntersectionPoint() }
+                )
+                .disabled(!referenceGeometry.canCreateIntersection)
+                .opacity(referenceGeometry.canCreateIntersection ? 1.0 : 0.5)
+                ToolButton(
+                    title: "Axis (Intersect)",
+                    isActive: false,
+                    action: { referenceGeometry.createIntersectionLine() }
+                )
+                .disabled(!referenceGeometry.canCreateIntersectionLine)
+                .opacity(referenceGeometry.canCreateIntersectionLine ? 1.0 : 0.5)
+                ToolButton(
+                    title: "Point (Segment ∩ Plane)",
+                    isActive: false,
+                    action: { referenceGeometry.createSegmentIntersections(with: distanceSegments) }
+                )
+                .disabled(!canIntersectSegments)
+                .opacity(canIntersectSegments ? 1.0 : 0.5)
+                ToolButton(
+                    title: "Measure",
+                    isActive: false,
+                    action: { referenceGeometry.createMeasurement(precision: appState.displayPrecision) }
+                )
+                .disabled(!referenceGeometry.canCreateMeasurement)
+                .opacity(referenceGeometry.canCreateMeasurement ? 1.0 : 0.5)
+            }
+
+            HStack(spacing: 6) {
+                Text("Face offset")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.7))
+                TextField("0", value: Binding(
+                    get: { referenceGeometry.faceOffset },
+                    set: { referenceGeometry.faceOffset = $0 }
+                ), format: .number)
+                .textFieldStyle(.roundedBorder)
+                .font(.system(size: 10, design: .monospaced))
+                .frame(width: 70)
+                Text("mm")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.5))
+            }
+
+            // Active tool instructions
+            if let tool = referenceGeometry.activeTool {
+                HStack(spacing: 6) {
+                    Text(tool.instruction)
+                        .font(.system(size: 11))
+                        .foregroundColor(.white.opacity(0.9))
+                    Spacer()
+                    Text(referenceGeometry.progressText)
+                        .font(.system(size: 10, design: .monospaced))
+                        .foregroundColor(.orange)
+                }
+            } else if let message = referenceGeometry.statusMessage {
+                Text(message)
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.7))
+                    .fixedSize(horizontal: false, vertical: true)
+            }
+
+            if !referenceGeometry.entities.isEmpty {
+                Divider()
+                    .background(Color.white.opacity(0.2))
+
+                ScrollView {
+                    VStack(alignment: .leading, spacing: 4) {
+                        ForEach(referenceGeometry.entities) { entity in
+                            EntityRow(
+                                entity: entity,
+                                anchor: appState.originalPosition(entity.kind.anchor),
+                                isSelected: referenceGeometry.selectedIDs.contains(entity.id),
+                                canConstrain: canConstrain && entity.kind.direction != nil,
+                                isConstraining: isConstraining(entity),
+                                onSelect: { referenceGeometry.toggleSelection(id: entity.id) },
+                                onToggleVisibility: { referenceGeometry.toggleVisibility(id: entity.id) },
+                                onConstrain: { constrain(to: entity) },
+                                onDelete: { referenceGeometry.remove(id: entity.id) }
+                            )
+                        }
+                    }
+                }
+                .frame(maxHeight: 180)
+
+                if !referenceGeometry.measurements.isEmpty {
+                    Divider()
+                        .background(Color.white.opacity(0.2))
+
+                    VStack(alignment: .leading, spacing: 4) {
+                        ForEach(referenceGeometry.measurements) { measurement in
+                            MeasurementRow(
+                                title: referenceGeometry.title(for: measurement),
+                                value: referenceGeometry.result(for: measurement)?.formattedValue(precision: appState.displayPrecision) ?? "-",
+                                onDelete: { referenceGeometry.removeMeasurement(id: measurement.id) }
+                            )
+                        }
+                    }
+                }
+
+                Text("Select entities to intersect or measure. Select one plane to intersect distance measurements with it. Picked points snap to visible references.")
+                    .font(.system(size: 9))
+                    .foregroundColor(.white.opacity(0.5))
+                    .italic()
+                    .fixedSize(horizontal: false, vertical: true)
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.2))
+
+            ToleranceChecksSection(appState: appState)
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+
+    private func startTool(_ tool: ReferenceTool) {
+        // Point picking for references replaces any in-progress measurement or leveling
+        appState.measurementSystem.cancelMeasurement()
+        appState.levelingState.reset()
+        referenceGeometry.startTool(tool)
+    }
+
+    private func constrain(to entity: ReferenceEntity) {
+        guard let direction = entity.kind.direction else { return }
+        appState.measurementSystem.toggleDirectionConstraint(direction)
+    }
+
+    private func isConstraining(_ entity: ReferenceEntity) -> Bool {
+        guard let current = appState.measurementSystem.constrainingDirection,
+              let direction = entity.kind.direction else {
+            return false
+        }
+        return current.isApproximatelyEqual(to: direction.normalized(), tolerance: 1e-9)
+    }
+}
+
+// MARK: - Helper Views
+
+private struct ToolButton: View {
+    let title: String
+    let isActive: Bool
+    let action: () -> Void
+
+    var body: some View {
+        Button(action: action) {
+            Text(title)
+                .font(.system(size: 10, weight: .medium))
+                .foregroundColor(.white)
+                .frame(maxWidth: .infinity)
+                .padding(.vertical, 5)
+                .background(
+                    RoundedRectangle(cornerRadius: 5)
+                        .fill(isActive ? Color.orange.opacity(0.6) : Color.white.opacity(0.12))
+                )
+        }
+        .buttonStyle(.plain)
+    }
+}
+
+private struct MeasurementRow: View {
+    let title: String
+    let value: String
+    let onDelete: () -> Void
+
+    var body: some View {
+        HStack(spacing: 6) {
+            Text(title)
+                .font(.system(size: 10))
+                .foregroundColor(.white.opacity(0.8))
+            Spacer()
+            Text(value)
+                .font(.system(size: 10, design: .monospaced))
+                .foregroundColor(.orange)
+            Button(action: onDelete) {
+                Image(systemName: "trash")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.7))
+            }
+            .buttonStyle(.plain)
+        }
+    }
+}
+
+private struct EntityRow: View {
+    let entity: ReferenceEntity
+    let anchor: Vector3
+    let isSelected: Bool
+    let canConstrain: Bool
+    let isConstraining: Bool
+    let onSelect: () -> Void
+    let onToggleVisibility: () -> Void
+    let onConstrain: () -> Void
+    let onDelete: () -> Void
+
+    var body: some View {
+        HStack(spacing: 6) {
+            Button(action: onSelect) {
+                Image(systemName: isSelected ? "checkmark.square.fill" : "square")
+                    .font(.system(size: 10))
+                    .foregroundColor(isSelected ? .orange : .white.opacity(0.6))
+            }
+            .buttonStyle(.plain)
+            .help("Select for intersection or measurement")
+
+            VStack(alignment: .leading, spacing: 1) {
+                Text(entity.name)
+                    .font(.system(size: 11, weight: .medium))
+                    .foregroundColor(entity.isVisible ? .white : .white.opacity(0.4))
+                Text(String(format: "(%.2f, %.2f, %.2f)", anchor.x, anchor.y, anchor.z))
+                    .font(.system(size: 9, design: .monospaced))
+                    .foregroundColor(.white.opacity(0.5))
+            }
+
+            Spacer()
+
+            if entity.kind.direction != nil {
+                Button(action: onConstrain) {
+                    Text("∥")
+                        .font(.system(size: 11, weight: .bold))
+                        .foregroundColor(isConstraining ? .orange : .white.opacity(canConstrain ? 0.8 : 0.3))
+                }
+                .buttonStyle(.plain)
+                .disabled(!canConstrain)
+                .help("Constrain the distance measurement along this \(entity.kind.typeName.lowercased())")
+            }
+
+            Button(action: onToggleVisibility) {
+                Image(systemName: entity.isVisible ? "eye" : "eye.slash")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.7))
+            }
+            .buttonStyle(.plain)
+
+            Button(action: onDelete) {
+                Image(systemName: "trash")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.7))
+            }
+            .buttonStyle(.plain)
+        }
+        .padding(.vertical, 2)
+    }
+}
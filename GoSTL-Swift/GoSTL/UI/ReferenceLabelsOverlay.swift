@@ -0,0 +1,46 @@
+import SwiftUI
+
+/// Overlay that shows reference entity names and reference measurement values at their 3D positions
+struct ReferenceLabelsOverlay: View {
+    let referenceGeometry: ReferenceGeometrySystem
+    let camera: Camera
+    let viewSize: CGSize
+    var precision: DisplayPrecision = .standard
+
+    var body: some View {
+        GeometryReader { geometry in
+            ZStack {
+                // Entity names next to their anchors
+                ForEach(referenceGeometry.entities.filter(\.isVisible)) { entity in
+                    if let screenPos = camera.project(worldPosition: entity.kind.anchor, viewSize: viewSize) {
+                        Text(entity.name)
+                            .font(.system(size: 10, weight: .medium))
+                            .foregroundColor(.orange)
+                            .shadow(color: .black.opacity(0.8), radius: 1)
+                            .position(x: screenPos.x + 8, y: screenPos.y - 10)
+                    }
+                }
+
+                // Measurement values at the middle of their annotation lines
+                ForEach(referenceGeometry.measurements.filter { referenceGeometry.isVisible($0) }) { measurement in
+                    if let result = referenceGeometry.result(for: measurement),
+                       let screenPos = camera.project(worldPosition: (result.from + result.to) * 0.5, viewSize: viewSize) {
+                        Text(result.formattedValue(precision: precision))
+                            .font(.system(size: 12, weight: .semibold, design: .monospaced))
+                            .foregroundColor(.white)
+                            .padding(.horizontal, 6)
+                            .padding(.vertical, 3)
+                            .background(
+                                RoundedRectangle(cornerRadius: 4)
+                                    .fill(Color.orange.opacity(0.9))
+                                    .shadow(color: .black.opacity(0.5), radius: 2, x: 0, y: 1)
+                            )
+                            .position(screenPos)
+                    }
+                }
+            }
+            .frame(width: geometry.size.width, height: geometry.size.height)
+            .allowsHitTesting(false)
+        }
+    }
+}
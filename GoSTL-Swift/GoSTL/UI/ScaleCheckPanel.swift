@@ -0,0 +1,62 @@
+import SwiftUI
+import Metal
+
+/// Prompt shown after loading a model whose size suggests a unit mistake, with one-click scale fixes
+struct ScaleCheckPanel: View {
+    let appState: AppState
+    let finding: ScaleCheck.Finding
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Image(systemName: "ruler")
+                    .foregroundColor(.orange)
+                Text("SCALE")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: { appState.dismissScaleCheck() }) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("Keep the model as it is")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            Text(finding.reason)
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.9))
+                .fixedSize(horizontal: false, vertical: true)
+
+            HStack(spacing: 6) {
+                ForEach(finding.fixes, id: \.self) { fix in
+                    Button(fix.title) {
+                        guard let device = MTLCreateSystemDefaultDevice() else { return }
+                        try? appState.applyScaleFix(fix, device: device)
+                    }
+                    .help("Scale the model \(fix.title); the file is not changed")
+                }
+
+                Spacer()
+
+                Button("Keep") {
+                    appState.dismissScaleCheck()
+                }
+            }
+            .controlSize(.small)
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 280)
+    }
+}
@@ -0,0 +1,177 @@
+import SwiftUI
+
+/// Panel listing the named cross-sections of the model; clicking one restores it
+struct SectionBookmarksPanel: View {
+    let appState: AppState
+    let onClose: () -> Void
+
+    @State private var newName = ""
+    @State private var errorMessage: String?
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("SECTIONS")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: onClose) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            if appState.sectionBookmarks.isEmpty {
+                Text("Slice the model or set a cutaway, then save the view under a name")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.6))
+                    .fixedSize(horizontal: false, vertical: true)
+            } else {
+                ScrollView {
+                    VStack(alignment: .leading, spacing: 4) {
+                        ForEach(appState.sectionBookmarks) { bookmark in
+                            BookmarkRow(
+                                bookmark: bookmark,
+                                summary: bookmark.summary(
+                                    modelBounds: appState.slicingState.modelBounds,
+                                    coordinateOffset: appState.coordinateOffset
+                                ),
+                                onRecall: { appState.recallSectionBookmark(bookmark) },
+                                onRename: { name in
+                                    perform { try appState.renameSectionBookmark(bookmark.id, to: name) }
+                                },
+                                onDelete: {
+                                    perform { try appState.removeSectionBookmark(bookmark.id) }
+                                }
+                            )
+                        }
+                    }
+                }
+                .frame(maxHeight: 220)
+            }
+
+            HStack(spacing: 4) {
+                TextField("Name", text: $newName)
+                    .onSubmit(saveSection)
+                Button("Save Current", action: saveSection)
+                    .controlSize(.small)
+                    .disabled(newName.trimmingCharacters(in: .whitespaces).isEmpty)
+            }
+            .textFieldStyle(.roundedBorder)
+            .font(.system(size: 10))
+
+            if let errorMessage {
+                Text(errorMessage)
+                    .font(.system(size: 10))
+                    .foregroundColor(.red)
+                    .fixedSize(horizontal: false, vertical: true)
+            } else if let url = appState.sourceFileURL {
+                Text("Stored in \(ModelSidecar.url(for: url).lastPathComponent)")
+                    .font(.system(size: 9))
+                    .foregroundColor(.white.opacity(0.5))
+                    .italic()
+                    .lineLimit(1)
+                    .truncationMode(.middle)
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+
+    private func saveSection() {
+        let name = newName.trimmingCharacters(in: .whitespaces)
+        guard !name.isEmpty else { return }
+        perform {
+            try appState.saveSectionBookmark(name: name)
+            newName = ""
+        }
+    }
+
+    private func perform(_ change: () throws -> Void) {
+        do {
+            try change()
+            errorMessage = nil
+        } catch {
+            errorMessage = "Could not save sections: \(error.localizedDescription)"
+        }
+    }
+}
+
+/// Saved section; click to recall, the name is saved when the field is submitted
+private struct BookmarkRow: View {
+    let bookmark: SectionBookmark
+    let summary: String
+    let onRecall: () -> Void
+    let onRename: (String) -> Void
+    let onDelete: () -> Void
+
+    @State private var name: String
+
+    init(
+        bookmark: SectionBookmark,
+        summary: String,
+        onRecall: @escaping () -> Void,
+        onRename: @escaping (String) -> Void,
+        onDelete: @escaping () -> Void
+    ) {
+        self.bookmark = bookmark
+        self.summary = summary
+        self.onRecall = onRecall
+        self.onRename = onRename
+        self.onDelete = onDelete
+        _name = State(initialValue: bookmark.name)
+    }
+
+    var body: some View {
+        HStack(spacing: 4) {
+            Button(action: onRecall) {
+                Image(systemName: "scope")
+                    .font(.system(size: 10))
+            }
+            .buttonStyle(.plain)
+            .foregroundColor(.white.opacity(0.8))
+            .help("Show this section")
+
+            VStack(alignment: .leading, spacing: 1) {
+                TextField("Name", text: $name)
+                    .textFieldStyle(.roundedBorder)
+                    .font(.system(size: 10))
+                    .onSubmit(commit)
+                Text(summary)
+                    .font(.system(size: 9, design: .monospaced))
+                    .foregroundColor(.white.opacity(0.6))
+                    .lineLimit(1)
+                    .truncationMode(.tail)
+            }
+
+            Button(action: onDelete) {
+                Image(systemName: "trash")
+                    .font(.system(size: 9))
+            }
+            .buttonStyle(.plain)
+            .foregroundColor(.white.opacity(0.6))
+        }
+    }
+
+    private func commit() {
+        let trimmed = name.trimmingCharacters(in: .whitespaces)
+        guard !trimmed.isEmpty else {
+            name = bookmark.name
+            return
+        }
+        guard trimmed != bookmark.name else { return }
+        onRename(trimmed)
+    }
+}
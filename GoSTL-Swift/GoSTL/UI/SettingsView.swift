@@ -0,0 +1,368 @@
+import SwiftUI
+import AppKit
+
+/// Preferences window (GoSTL > Settings...)
+struct SettingsView: View {
+    var body: some View {
+        TabView {
+            NavigationSettingsView()
+                .tabItem {
+                    Label("Navigation", systemImage: "computermouse")
+                }
+
+            DisplaySettingsView()
+                .tabItem {
+                    Label("Display", systemImage: "textformat.123")
+                }
+
+            AutomationSettingsView()
+                .tabItem {
+                    Label("Automation", systemImage: "bolt.horizontal")
+                }
+
+            FilesSettingsView()
+                .tabItem {
+                    Label("Files", systemImage: "folder")
+                }
+        }
+        .frame(width: 420)
+    }
+}
+
+/// Mouse navigation scheme selection
+struct NavigationSettingsView: View {
+    @Bindable private var settings = AppSettings.shared
+
+    var body: some View {
+        Form {
+            Picker("Mouse scheme:", selection: $settings.navigationScheme) {
+                ForEach(NavigationScheme.allCases) { scheme in
+                    Text(scheme.rawValue).tag(scheme)
+                }
+            }
+            .pickerStyle(.menu)
+
+            VStack(alignment: .leading, spacing: 4) {
+                ForEach(settings.navigationScheme.bindings, id: \.action) { binding in
+                    HStack(alignment: .top) {
+                        Text(binding.action)
+                            .frame(width: 60, alignment: .leading)
+                            .foregroundColor(.secondary)
+                        Text(binding.gesture)
+                    }
+                    .font(.system(size: 11))
+                }
+            }
+            .padding(.top, 4)
+
+            Text("Left drag always rotates (Shift: pan) so trackpads keep working.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+
+            Divider()
+                .padding(.vertical, 4)
+
+            Toggle("Inertia", isOn: $settings.navigationInertia)
+            Text("Keep rotating after a quick drag and glide through mouse wheel zoom steps.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+
+            Toggle("Zoom to cursor", isOn: $settings.zoomToCursor)
+            Text("Zoom toward the point under the mouse instead of the view center.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+
+            Divider()
+                .padding(.vertical, 4)
+
+            Toggle("Magnifier while picking", isOn: $settings.showMagnifier)
+            Picker("Magnification:", selection: $settings.magnifierZoom) {
+                ForEach(AppSettings.magnifierZoomLevels, id: \.self) { zoom in
+                    Text("\(Int(zoom))×").tag(zoom)
+                }
+            }
+            .pickerStyle(.segmented)
+            .disabled(!settings.showMagnifier)
+            Text("Show an enlarged view around the cursor with the snap point highlighted while measuring or picking points.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+
+            Divider()
+                .padding(.vertical, 4)
+
+            HStack {
+                Slider(value: $settings.snapRadius, in: AppSettings.snapRadiusRange, step: AppSettings.snapRadiusStep) {
+                    Text("Snap radius:")
+                }
+                Text(settings.snapRadius > 0 ? "\(Int(settings.snapRadius)) pt" : "Off")
+                    .font(.system(size: 11, design: .monospaced))
+                    .frame(width: 44, alignment: .trailing)
+            }
+            Toggle("Show snap radius around the cursor", isOn: $settings.showSnapRadius)
+            Text("Picked points snap to the closest mesh vertex within this distance on screen, at any zoom. Press + or - in the 3D view to change it while picking.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+        }
+        .padding(20)
+    }
+}
+
+/// Decimal places of displayed values
+struct DisplaySettingsView: View {
+    @Bindable private var settings = AppSettings.shared
+
+    var body: some View {
+        Form {
+            Picker("Lengths:", selection: $settings.lengthDecimals) {
+                ForEach(DisplayPrecision.decimalRange, id: \.self) { decimals in
+                    Text(DisplayPrecision(lengthDecimals: decimals, angleDecimals: 0).length(12.345678) + " mm").tag(decimals)
+                }
+            }
+            .pickerStyle(.menu)
+
+            Picker("Angles:", selection: $settings.angleDecimals) {
+                ForEach(DisplayPrecision.decimalRange, id: \.self) { decimals in
+                    Text(DisplayPrecision(lengthDecimals: 0, angleDecimals: decimals).angle(45.678901)).tag(decimals)
+                }
+            }
+            .pickerStyle(.menu)
+
+            Text("Decimal places of measurement labels, panels, the measurement log and inspection reports. Right-click a measurement label to show more or fewer decimals for that measurement only.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+
+            Picker("Units:", selection: $settings.lengthScale) {
+                ForEach(LengthScale.allCases, id: \.self) { scale in
+                    Text(scale.displayName).tag(scale)
+                }
+            }
+            .pickerStyle(.menu)
+
+            Text("Automatic shows micrometers for models smaller than 1 mm and meters for models of 10 m and more, with engineering notation beyond that.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+
+            Toggle("Check model scale on load", isOn: $settings.checkModelScale)
+
+            Text("Offers ×25.4, ×1000 or ÷1000 when a model looks like it was exported in inches, meters or micrometers.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+
+            Toggle("Check mesh quality on load", isOn: $settings.checkMeshQuality)
+
+            Text("Shows a banner when a model has degenerate or sliver triangles, holes, flipped normals or near-duplicate vertices.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+
+            Toggle("Ambient occlusion in presentations", isOn: $settings.presentationOcclusion)
+
+            Text("Darkens creases and cavities in presentation mode for depth cues. Baked in the background when a presentation starts; the normal view stays unshaded.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+
+            Picker("CSV delimiter:", selection: $settings.csvFormat.delimiter) {
+                ForEach(CSVFormat.Delimiter.allCases, id: \.self) { delimiter in
+                    Text(delimiter.displayName).tag(delimiter)
+                }
+            }
+            .pickerStyle(.menu)
+
+            Picker("CSV decimals:", selection: $settings.csvFormat.decimalSeparator) {
+                ForEach(CSVFormat.DecimalSeparator.allCases, id: \.self) { separator in
+                    Text(separator.displayName).tag(separator)
+                }
+            }
+            .pickerStyle(.menu)
+
+            HStack {
+                Text("Copied CSV tables. Excel in locales with a decimal comma expects semicolons.")
+                    .font(.system(size: 10))
+                    .foregroundColor(.secondary)
+                    .fixedSize(horizontal: false, vertical: true)
+
+                Button("Match System") {
+                    settings.csvFormat = .matching(.current)
+                }
+                .controlSize(.small)
+            }
+        }
+        .padding(20)
+    }
+}
+
+/// Location of temporary files (rendered OpenSCAD models, go3mf builds, plugin exchange files)
+/// and the tools used to render source files
+struct FilesSettingsView: View {
+    @Bindable private var settings = AppSettings.shared
+
+    private var workspace: TempWorkspace { TempWorkspace.shared }
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            Text("Temporary files:")
+                .font(.system(size: 11))
+            Text(TempWorkspace.configuredRoot(setting: settings.temporaryDirectory).path)
+                .font(.system(size: 11, design: .monospaced))
+                .lineLimit(1)
+                .truncationMode(.middle)
+                .textSelection(.enabled)
+
+            HStack {
+                Button("Choose...") {
+                    chooseDirectory()
+                }
+                Button("Use Default") {
+                    settings.temporaryDirectory = nil
+                }
+                .disabled(settings.temporaryDirectory == nil)
+                Button("Show in Finder") {
+                    NSWorkspace.shared.activateFileViewerSelecting([workspace.directory])
+                }
+            }
+
+            Text("Each running GoSTL keeps its files in its own folder, which is removed on quit. Folders left by crashes are removed on the next start. Changes take effect after a restart; \(TempWorkspace.environmentKey) overrides this setting.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+
+            Divider()
+                .padding(.vertical, 4)
+
+            Toggle("Render OpenSCAD colors through 3MF", isOn: $settings.openSCADThreeMFExport)
+                .font(.system(size: 11))
+            Text("Renders once and keeps color() groups as 3MF materials instead of rendering every color separately. Needs an OpenSCAD version with colored 3MF export (2024 or later); otherwise colors are rendered separately as before.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+
+            Divider()
+                .padding(.vertical, 4)
+
+            Toggle("Stream large STL files", isOn: $settings.streamLargeModels)
+                .font(.system(size: 11))
+            Stepper("Full-resolution memory: \(settings.streamingBudget) MB", value: $settings.streamingBudget, in: 256...16384, step: 256)
+                .font(.system(size: 11))
+                .disabled(!settings.streamLargeModels)
+            Text("Binary STL files larger than an eighth of the memory open as a decimated proxy, and the parts near the camera are paged in at full resolution. Measurements use the proxy, and a streamed model cannot be saved.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+
+            Divider()
+                .padding(.vertical, 4)
+
+            Text("Python interpreter (CadQuery/build123d):")
+                .font(.system(size: 11))
+            Text(settings.pythonInterpreter ?? "Detect automatically")
+                .font(.system(size: 11, design: .monospaced))
+                .lineLimit(1)
+                .truncationMode(.middle)
+                .textSelection(.enabled)
+
+            HStack {
+                Button("Choose...") {
+                    chooseInterpreter()
+                }
+                Button("Detect Automatically") {
+                    settings.pythonInterpreter = nil
+                }
+                .disabled(settings.pythonInterpreter == nil)
+            }
+
+            Text("Used to run .py scripts. Choose the python of the environment CadQuery or build123d is installed in. Automatic detection uses a .venv or venv folder next to the script or above it, then python3 from your shell. \(PythonCADRenderer.environmentKey) overrides this setting.")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+                .fixedSize(horizontal: false, vertical: true)
+        }
+        .padding(20)
+        .frame(maxWidth: .infinity, alignment: .leading)
+    }
+
+    private func chooseDirectory() {
+        let panel = NSOpenPanel()
+        panel.canChooseDirectories = true
+        panel.canChooseFiles = false
+        panel.canCreateDirectories = true
+        panel.allowsMultipleSelection = false
+        guard panel.runModal() == .OK, let url = panel.url else { return }
+        settings.temporaryDirectory = url.path
+    }
+
+    private func chooseInterpreter() {
+        let panel = NSOpenPanel()
+        panel.canChooseDirectories = false
+        panel.canChooseFiles = true
+        panel.allowsMultipleSelection = false
+        panel.treatsFilePackagesAsDirectories = true
+        panel.showsHiddenFiles = true
+        // Keep venv/bin/python instead of the interpreter it links to, or the venv's packages are missing
+        panel.resolvesAliases = false
+        guard panel.runModal() == .OK, let url = panel.url else { return }
+        settings.pythonInterpreter = url.path
+    }
+}
+
+/// Overview of the automation hooks configured in hooks.json
+struct AutomationSettingsView: View {
+    @State private var hooks: [Hook] = HookManager.shared.hooks
+
+    private var configFile: URL { HookManager.shared.configFile }
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            Text("Hooks run a shell command or POST a JSON payload when an event occurs.")
+                .font(.system(size: 11))
+                .foregroundColor(.secondary)
+
+            if hooks.isEmpty {
+                Text("No hooks configured")
+                    .font(.system(size: 11))
+            } else {
+                ForEach(Array(hooks.enumerated()), id: \.offset) { _, hook in
+                    HStack(alignment: .top) {
+                        Text(hook.event)
+                            .frame(width: 120, alignment: .leading)
+                            .foregroundColor(.secondary)
+                        Text(hook.command ?? hook.url?.absoluteString ?? "-")
+                            .lineLimit(1)
+                            .truncationMode(.middle)
+                    }
+                    .font(.system(size: 11, design: .monospaced))
+                }
+            }
+
+            Text("Events: " + HookEvent.allCases.map(\.rawValue).joined(separator: ", ") + ", or * for all")
+                .font(.system(size: 10))
+                .foregroundColor(.secondary)
+
+            HStack {
+                Button("Open hooks.json") {
+                    openConfigFile()
+                }
+                Button("Reload") {
+                    HookManager.shared.reload()
+                    hooks = HookManager.shared.hooks
+                }
+            }
+            .padding(.top, 4)
+        }
+        .padding(20)
+        .frame(maxWidth: .infinity, alignment: .leading)
+    }
+
+    /// Open the hooks file in the default editor, creating an empty one first
+    private func openConfigFile() {
+        if !FileManager.default.fileExists(atPath: configFile.path) {
+            try? FileManager.default.createDirectory(at: configFile.deletingLastPathComponent(), withIntermediateDirectories: true)
+            try? "{\n  \"hooks\": []\n}\n".write(to: configFile, atomically: true, encoding: .utf8)
+        }
+        NSWorkspace.shared.open(configFile)
+    }
+}
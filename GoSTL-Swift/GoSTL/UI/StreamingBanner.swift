@@ -0,0 +1,46 @@
+import SwiftUI
+
+/// Banner shown while a streamed model is open: what is at full resolution and what works on the proxy
+struct StreamingBanner: View {
+    let appState: AppState
+    let streamingModel: StreamingModel
+
+    var body: some View {
+        HStack(spacing: 8) {
+            Image(systemName: "square.stack.3d.down.right")
+                .foregroundColor(.blue)
+            VStack(alignment: .leading, spacing: 2) {
+                Text("Streaming \(streamingModel.triangleCount) triangles")
+                    .font(.system(size: 11, weight: .medium))
+                    .foregroundColor(.white)
+                Text(detail)
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.7))
+                    .lineLimit(2)
+                    .fixedSize(horizontal: false, vertical: true)
+            }
+        }
+        .padding(.horizontal, 12)
+        .padding(.vertical, 8)
+        .background(
+            RoundedRectangle(cornerRadius: 8)
+                .fill(.ultraThinMaterial)
+                .overlay(
+                    RoundedRectangle(cornerRadius: 8)
+                        .stroke(Color.blue.opacity(0.6), lineWidth: 1)
+                )
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(maxWidth: 520)
+        .help("Only the decimated proxy is in memory; chunks near the camera are read from disk at full resolution")
+    }
+
+    private var detail: String {
+        let proxy = "Measurements use the \(streamingModel.proxy.triangleCount)-triangle proxy"
+        guard let status = appState.streamingStatus else {
+            return "Full resolution paused after changing the model; reopen the file to resume. \(proxy)."
+        }
+        let memory = ByteCountFormatter.string(fromByteCount: Int64(status.residentBytes), countStyle: .memory)
+        return "\(status.shownChunks) of \(status.visibleChunks) chunks in view at full resolution (\(memory)). \(proxy)."
+    }
+}
@@ -0,0 +1,141 @@
+import SwiftUI
+import AppKit
+
+/// Panel of a batch template run: what to pick next and the results of the parts done so far
+struct TemplateRunPanel: View {
+    let appState: AppState
+    let run: TemplateRun
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("BATCH: \(run.template.name.uppercased())")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+                    .lineLimit(1)
+
+                Spacer()
+
+                Button(action: { appState.endTemplateRun() }) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("End the batch (export the results first)")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            HStack {
+                Text(appState.modelInfo?.fileName ?? "Part \(run.parts.count + 1)")
+                    .font(.system(size: 11, weight: .medium))
+                    .foregroundColor(.white)
+                    .lineLimit(1)
+                Spacer()
+                Text(run.progressText)
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.6))
+            }
+
+            Text(run.prompt)
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.8))
+
+            if let message = run.statusMessage {
+                Text(message)
+                    .font(.system(size: 10))
+                    .foregroundColor(.orange)
+            }
+
+            HStack {
+                Button("Skip Feature") {
+                    appState.skipTemplateFeature()
+                }
+                Button("Restart Part") {
+                    appState.restartTemplatePart()
+                }
+            }
+            .controlSize(.small)
+
+            if !run.parts.isEmpty {
+                Divider()
+                    .background(Color.white.opacity(0.3))
+
+                ScrollView {
+                    VStack(alignment: .leading, spacing: 4) {
+                        ForEach(Array(run.parts.enumerated()), id: \.offset) { _, part in
+                            partView(part)
+                        }
+                    }
+                }
+                .frame(maxHeight: 160)
+
+                HStack {
+                    let failed = run.parts.filter { !$0.passed }.count
+                    Text("\(run.parts.count) part(s), \(failed) out of tolerance")
+                        .font(.system(size: 10))
+                        .foregroundColor(failed == 0 ? .green : .red)
+
+                    Spacer()
+
+                    Button("Copy CSV") {
+                        copy(run.csv(format: AppSettings.shared.csvFormat))
+                    }
+                    .controlSize(.small)
+                }
+            } else {
+                Text("Each part is recorded after its last feature; then open the next part")
+                    .font(.system(size: 9))
+                    .foregroundColor(.white.opacity(0.5))
+                    .italic()
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+
+    private func partView(_ part: TemplateRun.PartResult) -> some View {
+        let columns = run.template.columns
+        return VStack(alignment: .leading, spacing: 1) {
+            HStack(spacing: 6) {
+                Circle()
+                    .fill(part.passed ? Color.green : Color.red)
+                    .frame(width: 6, height: 6)
+                Text(part.name)
+                    .font(.system(size: 10, weight: .medium))
+                    .foregroundColor(.white)
+                    .lineLimit(1)
+            }
+            ForEach(Array(columns.enumerated()), id: \.offset) { index, column in
+                HStack {
+                    Text(column.title)
+                        .foregroundColor(.white.opacity(0.6))
+                    Spacer()
+                    Text(value(part.values[index], column: column))
+                        .foregroundColor(part.failures.contains(index) ? .red : .white)
+                }
+                .font(.system(size: 9, design: .monospaced))
+                .padding(.leading, 12)
+            }
+        }
+    }
+
+    private func value(_ value: Double?, column: MeasurementTemplate.Column) -> String {
+        guard let value else { return "-" }
+        let precision = appState.displayPrecision
+        return column.unit == "°" ? precision.angle(value) : precision.lengthWithUnit(value)
+    }
+
+    private func copy(_ text: String) {
+        let pasteboard = NSPasteboard.general
+        pasteboard.clearContents()
+        pasteboard.setString(text, forType: .string)
+    }
+}
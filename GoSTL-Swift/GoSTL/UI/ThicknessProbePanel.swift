@@ -0,0 +1,54 @@
+import SwiftUI
+
+/// Panel of the thickness probe mode: hint and the last probed thickness
+struct ThicknessProbePanel: View {
+    let appState: AppState
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack(spacing: 8) {
+                Text("THICKNESS PROBE")
+                    .font(.system(size: 14, weight: .semibold))
+                    .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+
+                Spacer()
+
+                Button(action: { appState.setThicknessProbe(false) }) {
+                    Image(systemName: "xmark")
+                        .font(.system(size: 10))
+                }
+                .buttonStyle(.plain)
+                .foregroundColor(.white.opacity(0.7))
+                .help("End the thickness probe (Esc)")
+            }
+
+            Divider()
+                .background(Color.white.opacity(0.3))
+
+            Text("Click the surface to measure the material behind it")
+                .font(.system(size: 11))
+                .foregroundColor(.white.opacity(0.8))
+
+            if let probe = appState.thicknessProbe {
+                HStack {
+                    Text(appState.displayPrecision.lengthWithUnit(probe.thickness))
+                        .font(.system(size: 18, weight: .semibold, design: .monospaced))
+                        .foregroundColor(.white)
+                        .textSelection(.enabled)
+                    Spacer()
+                    Text("kept as measurement")
+                        .font(.system(size: 9))
+                        .foregroundColor(.white.opacity(0.5))
+                        .italic()
+                }
+            }
+        }
+        .padding(12)
+        .background(
+            RoundedRectangle(cornerRadius: 10)
+                .fill(.ultraThinMaterial)
+                .shadow(color: .black.opacity(0.3), radius: 10, x: 0, y: 4)
+        )
+        .frame(width: 300)
+    }
+}
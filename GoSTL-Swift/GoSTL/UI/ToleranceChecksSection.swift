@@ -0,0 +1,216 @@
+import SwiftUI
+
+/// Section of the reference geometry panel for defining and listing GD&T tolerance checks
+struct ToleranceChecksSection: View {
+    let appState: AppState
+
+    @State private var characteristic: ToleranceCharacteristic = .flatness
+    @State private var tolerance: Double = 0.1
+    @State private var datumID: UUID?
+    @State private var featureID: UUID?
+    @State private var nominalX: Double = 0
+    @State private var nominalY: Double = 0
+    @State private var nominalZ: Double = 0
+
+    private var referenceGeometry: ReferenceGeometrySystem { appState.referenceGeometry }
+
+    /// Planes and axes usable as datums
+    private var datums: [ReferenceEntity] {
+        referenceGeometry.entities.filter { $0.kind.direction != nil }
+    }
+
+    /// Points and axes whose position can be toleranced
+    private var positionFeatures: [ReferenceEntity] {
+        referenceGeometry.entities.filter {
+            if case .plane = $0.kind { return false }
+            return true
+        }
+    }
+
+    private var selectedTriangleCount: Int {
+        appState.measurementSystem.selectedTriangles.count
+    }
+
+    private var canAdd: Bool {
+        guard tolerance > 0 else { return false }
+        if characteristic.requiresDatum && datumID == nil {
+            return false
+        }
+        if characteristic.usesSurface {
+            return selectedTriangleCount > 0
+        }
+        return featureID != nil
+    }
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 6) {
+            HStack(spacing: 6) {
+                Text("TOLERANCES")
+                    .font(.system(size: 10, weight: .semibold))
+                    .foregroundColor(.white.opacity(0.7))
+                Spacer()
+                Button("Copy Report") {
+                    appState.copyInspectionReport()
+                }
+                .buttonStyle(.plain)
+                .font(.system(size: 10))
+                .foregroundColor(Color(red: 0.39, green: 0.78, blue: 1.0))
+                .help("Copy the inspection report to the clipboard")
+            }
+
+            Picker("", selection: $characteristic) {
+                ForEach(ToleranceCharacteristic.allCases) { characteristic in
+                    Text(characteristic.symbol).tag(characteristic)
+                }
+            }
+            .pickerStyle(.segmented)
+            .labelsHidden()
+            .help(characteristic.rawValue)
+
+            HStack(spacing: 6) {
+                Text(characteristic.rawValue)
+                    .font(.system(size: 10, weight: .medium))
+                    .foregroundColor(.white.opacity(0.9))
+                Spacer()
+                TextField("0.1", value: $tolerance, format: .number)
+                    .textFieldStyle(.roundedBorder)
+                    .font(.system(size: 10, design: .monospaced))
+                    .frame(width: 60)
+                Text(characteristic == .position ? "mm ⌀" : "mm")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.5))
+            }
+
+            if characteristic.requiresDatum {
+                entityPicker(title: "Datum", selection: $datumID, entities: datums)
+            }
+
+            if characteristic.usesSurface {
+                Text(selectedTriangleCount > 0
+                     ? "Feature: \(selectedTriangleCount) selected triangles"
+                     : "Select the toleranced surface with the triangle tool (t)")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.6))
+            } else {
+                entityPicker(title: "Feature", selection: $featureID, entities: positionFeatures)
+                HStack(spacing: 4) {
+                    Text("Nominal")
+                        .font(.system(size: 10))
+                        .foregroundColor(.white.opacity(0.7))
+                    nominalField($nominalX)
+                    nominalField($nominalY)
+                    nominalField($nominalZ)
+                }
+            }
+
+            Button(action: addCheck) {
+                Text("Add Check")
+                    .font(.system(size: 10, weight: .medium))
+                    .foregroundColor(.white)
+                    .frame(maxWidth: .infinity)
+                    .padding(.vertical, 5)
+                    .background(
+                        RoundedRectangle(cornerRadius: 5)
+                            .fill(Color.white.opacity(0.12))
+                    )
+            }
+            .buttonStyle(.plain)
+            .disabled(!canAdd)
+            .opacity(canAdd ? 1.0 : 0.5)
+
+            ForEach(referenceGeometry.toleranceChecks) { check in
+                ToleranceCheckRow(
+                    title: referenceGeometry.title(for: check),
+                    result: referenceGeometry.result(for: check),
+                    onDelete: { referenceGeometry.removeToleranceCheck(id: check.id) }
+                )
+            }
+        }
+    }
+
+    private func entityPicker(title: String, selection: Binding<UUID?>, entities: [ReferenceEntity]) -> some View {
+        HStack(spacing: 6) {
+            Text(title)
+                .font(.system(size: 10))
+                .foregroundColor(.white.opacity(0.7))
+            Picker("", selection: selection) {
+                Text("None").tag(UUID?.none)
+                ForEach(entities) { entity in
+                    Text(entity.name).tag(UUID?.some(entity.id))
+                }
+            }
+            .labelsHidden()
+            .font(.system(size: 10))
+        }
+    }
+
+    private func nominalField(_ value: Binding<Double>) -> some View {
+        TextField("0", value: value, format: .number)
+            .textFieldStyle(.roundedBorder)
+            .font(.system(size: 10, design: .monospaced))
+    }
+
+    private func addCheck() {
+        let feature: ToleranceFeature
+        var nominal: Vector3?
+        if characteristic.usesSurface {
+            feature = .surface(points: appState.selectedSurfacePoints(), triangleCount: selectedTriangleCount)
+        } else {
+            guard let featureID else { return }
+            feature = .entity(featureID)
+            // Nominal is entered in the file's coordinates
+            nominal = appState.renderPosition(Vector3(nominalX, nominalY, nominalZ))
+        }
+
+        let check = ToleranceCheck(
+            characteristic: characteristic,
+            tolerance: tolerance,
+            feature: feature,
+            datumID: characteristic.requiresDatum ? datumID : nil,
+            nominal: nominal
+        )
+        if appState.addToleranceCheck(check) != nil, characteristic.usesSurface {
+            appState.measurementSystem.clearTriangleSelection()
+            appState.updateSelectedTriangles()
+        }
+    }
+}
+
+private struct ToleranceCheckRow: View {
+    let title: String
+    let result: ToleranceResult?
+    let onDelete: () -> Void
+
+    var body: some View {
+        HStack(spacing: 6) {
+            Text(result.map { $0.passed ? "PASS" : "FAIL" } ?? "----")
+                .font(.system(size: 9, weight: .bold, design: .monospaced))
+                .foregroundColor(.white)
+                .padding(.horizontal, 4)
+                .padding(.vertical, 1)
+                .background(
+                    RoundedRectangle(cornerRadius: 3)
+                        .fill(badgeColor)
+                )
+            Text(title)
+                .font(.system(size: 10))
+                .foregroundColor(.white.opacity(0.8))
+                .lineLimit(1)
+            Spacer()
+            Text(result?.formattedValue ?? "-")
+                .font(.system(size: 10, design: .monospaced))
+                .foregroundColor(.orange)
+            Button(action: onDelete) {
+                Image(systemName: "trash")
+                    .font(.system(size: 10))
+                    .foregroundColor(.white.opacity(0.7))
+            }
+            .buttonStyle(.plain)
+        }
+    }
+
+    private var badgeColor: Color {
+        guard let result else { return .gray.opacity(0.6) }
+        return result.passed ? .green.opacity(0.7) : .red.opacity(0.7)
+    }
+}
@@ -55,7 +55,15 @@ private enum MessageType: Int, Comparable {
 /// Panel showing OpenSCAD messages in the bottom-right corner
 struct WarningsPanel: View {
     let warnings: [String]
-    @State private var isExpanded: Bool = false
+    /// Fill a detached window instead of floating over the 3D view (always expanded)
+    let fillsWindow: Bool
+    @State private var isExpanded: Bool
+
+    init(warnings: [String], fillsWindow: Bool = false) {
+        self.warnings = warnings
+        self.fillsWindow = fillsWindow
+        self._isExpanded = State(initialValue: fillsWindow)
+    }
 
     /// Determine the highest severity message type
     private var highestSeverity: MessageType {
@@ -108,7 +116,7 @@ struct WarningsPanel: View {
                 .frame(maxWidth: .infinity, alignment: .leading)
             }
         }
-        .frame(maxWidth: isExpanded ? 400 : nil)
+        .frame(maxWidth: fillsWindow ? .infinity : (isExpanded ? 400 : nil), alignment: .topLeading)
         .background(
             RoundedRectangle(cornerRadius: 6)
                 .fill(Color.black.opacity(0.85))
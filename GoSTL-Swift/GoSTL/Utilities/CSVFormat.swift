@@ -0,0 +1,93 @@
+import Foundation
+
+/// Field delimiter and decimal separator of exported CSV files
+///
+/// Spreadsheets in many European locales expect semicolons between fields and a decimal comma;
+/// opened there, a comma-separated "1.25" is split or turned into a date. Numbers are never
+/// grouped, and fields containing the delimiter are quoted.
+struct CSVFormat: Codable, Equatable, Sendable {
+    enum Delimiter: String, Codable, CaseIterable, Sendable {
+        case comma
+        case semicolon
+        case tab
+
+        var character: Character {
+            switch self {
+            case .comma: return ","
+            case .semicolon: return ";"
+            case .tab: return "\t"
+            }
+        }
+
+        var displayName: String {
+            switch self {
+            case .comma: return "Comma (,)"
+            case .semicolon: return "Semicolon (;)"
+            case .tab: return "Tab"
+            }
+        }
+    }
+
+    enum DecimalSeparator: String, Codable, CaseIterable, Sendable {
+        case point
+        case comma
+
+        var character: Character {
+            switch self {
+            case .point: return "."
+            case .comma: return ","
+            }
+        }
+
+        var displayName: String {
+            switch self {
+            case .point: return "Point (1.5)"
+            case .comma: return "Comma (1,5)"
+            }
+        }
+    }
+
+    var delimiter: Delimiter
+    var decimalSeparator: DecimalSeparator
+
+    /// Comma-separated with a decimal point (RFC 4180, what scripts expect)
+    static let standard = CSVFormat(delimiter: .comma, decimalSeparator: .point)
+
+    /// Semicolon-separated with a decimal comma (Excel in German, French, ... locales)
+    static let european = CSVFormat(delimiter: .semicolon, decimalSeparator: .comma)
+
+    init(delimiter: Delimiter = .comma, decimalSeparator: DecimalSeparator = .point) {
+        self.delimiter = delimiter
+        self.decimalSeparator = decimalSeparator
+    }
+
+    /// The format a spreadsheet in a locale expects: semicolons wherever the decimal separator is a comma
+    static func matching(_ locale: Locale) -> CSVFormat {
+        locale.decimalSeparator == "," ? .european : .standard
+    }
+
+    // MARK: - Formatting
+
+    /// A number with a fixed number of decimals, e.g. "12,500" with a decimal comma
+    func number(_ value: Double, decimals: Int) -> String {
+        localized(String(format: "%.*f", Int32(decimals), value))
+    }
+
+    /// A number formatted with a decimal point, converted to the decimal separator
+    func localized(_ number: String) -> String {
+        decimalSeparator == .point ? number : number.replacingOccurrences(of: ".", with: String(decimalSeparator.character))
+    }
+
+    /// A field, quoted if it contains the delimiter, a quote or a line break
+    func field(_ value: String) -> String {
+        guard value.contains(where: { $0 == delimiter.character || $0 == "\"" || $0 == "\n" || $0 == "\r" }) else {
+            return value
+        }
+        return "\"" + value.replacingOccurrences(of: "\"", with: "\"\"") + "\""
+    }
+
+    /// A line of fields (quoted as needed), without line break
+    func row(_ fields: [String]) -> String {
+        fields.map(field).joined(separator: String(delimiter.character))
+    }
+}
@@ -0,0 +1,206 @@
+import CoreGraphics
+import Foundation
+import ImageIO
+
+/// Errors that can occur while comparing images
+enum PerceptualDiffError: LocalizedError {
+    case unreadable(String)
+    case sizeMismatch(width: Int, height: Int, otherWidth: Int, otherHeight: Int)
+
+    var errorDescription: String? {
+        switch self {
+        case .unreadable(let path):
+            return "Cannot read the image \(path)"
+        case .sizeMismatch(let width, let height, let otherWidth, let otherHeight):
+            return "Image sizes differ: \(width)×\(height) and \(otherWidth)×\(otherHeight)"
+        }
+    }
+}
+
+/// Compares images the way people see them, for golden-image tests of the renderers
+///
+/// Colors are compared in CIELAB: a difference below `threshold` (ΔE, 2.3 is about the smallest
+/// difference people notice) counts as equal. Anti-aliased edges shift by a pixel between GPUs and
+/// driver versions, so a pixel only counts as different if no pixel within `radius` in the other
+/// image matches it. Transparent pixels are compared on white.
+enum PerceptualDiff {
+    /// Just noticeable difference (CIE76 ΔE)
+    static let defaultThreshold = 2.3
+    /// Share of differing pixels (percent) tolerated by `gostl imagediff` and the render self-test
+    static let defaultTolerance = 0.1
+
+    struct Result {
+        var width: Int
+        var height: Int
+        var differingPixels: Int
+        /// Largest color difference of a differing pixel (0 if none differ)
+        var maxDeltaE: Double
+        /// Differing pixels red on a faded copy of the first image
+        var diffImage: CGImage?
+
+        var percentage: Double {
+            width * height == 0 ? 0 : Double(differingPixels) / Double(width * height) * 100
+        }
+
+        /// Whether the share of differing pixels is within `tolerance` percent
+        func passes(tolerance: Double) -> Bool {
+            percentage <= tolerance
+        }
+    }
+
+    static func loadImage(at url: URL) throws -> CGImage {
+        guard let source = CGImageSourceCreateWithURL(url as CFURL, nil),
+              let image = CGImageSourceCreateImageAtIndex(source, 0, nil) else {
+            throw PerceptualDiffError.unreadable(url.path)
+        }
+        return image
+    }
+
+    static func compare(
+        _ image: CGImage,
+        _ other: CGImage,
+        threshold: Double = defaultThreshold,
+        radius: Int = 1
+    ) throws -> Result {
+        guard image.width == other.width, image.height == other.height else {
+            throw PerceptualDiffError.sizeMismatch(width: image.width, height: image.height, otherWidth: other.width, otherHeight: other.height)
+        }
+        let width = image.width, height = image.height
+        guard let pixels = rgb(image), let otherPixels = rgb(other) else {
+            throw PerceptualDiffError.unreadable("(in memory)")
+        }
+        let lab = labColors(pixels), otherLab = labColors(otherPixels)
+
+        /// Whether a color has a match within the radius around (x, y) in a Lab image
+        func hasMatch(_ color: SIMD3<Double>, x: Int, y: Int, in colors: [SIMD3<Double>]) -> Bool {
+            for ny in max(0, y - radius)...min(height - 1, y + radius) {
+                for nx in max(0, x - radius)...min(width - 1, x + radius) where deltaE(color, colors[ny * width + nx]) <= threshold {
+                    return true
+                }
+            }
+            return false
+        }
+
+        var differing = [Bool](repeating: false, count: width * height)
+        var count = 0
+        var maxDeltaE = 0.0
+        for y in 0..<height {
+            for x in 0..<width {
+                let index = y * width + x
+                let difference = deltaE(lab[index], otherLab[index])
+                guard difference > threshold else { continue }
+                // Both directions, so a missing thin line is found whichever image has it
+                if !hasMatch(lab[index], x: x, y: y, in: otherLab) || !hasMatch(otherLab[index], x: x, y: y, in: lab) {
+                    differing[index] = true
+                    count += 1
+                    maxDeltaE = max(maxDeltaE, difference)
+                }
+            }
+        }
+
+        return Result(
+            width: width,
+            height: height,
+            differingPixels: count,
+            maxDeltaE: maxDeltaE,
+            diffImage: diffImage(pixels, differing: differing, width: width, height: height)
+        )
+    }
+
+    // MARK: - Colors
+
+    /// CIE76 color difference
+    static func deltaE(_ a: SIMD3<Double>, _ b: SIMD3<Double>) -> Double {
+        let d = a - b
+        return (d * d).sum().squareRoot()
+    }
+
+    /// CIELAB (D65) of an sRGB color with components 0-255
+    static func lab(red: UInt8, green: UInt8, blue: UInt8) -> SIMD3<Double> {
+        func linear(_ value: UInt8) -> Double {
+            let c = Double(value) / 255
+            return c <= 0.04045 ? c / 12.92 : pow((c + 0.055) / 1.055, 2.4)
+        }
+        let r = linear(red), g = linear(green), b = linear(blue)
+        let x = (0.4124 * r + 0.3576 * g + 0.1805 * b) / 0.95047
+        let y = 0.2126 * r + 0.7152 * g + 0.0722 * b
+        let z = (0.0193 * r + 0.1192 * g + 0.9505 * b) / 1.08883
+
+        func f(_ t: Double) -> Double {
+            t > 216.0 / 24389.0 ? cbrt(t) : (24389.0 / 27.0 * t + 16) / 116
+        }
+        let fx = f(x), fy = f(y), fz = f(z)
+        return SIMD3(116 * fy - 16, 500 * (fx - fy), 200 * (fy - fz))
+    }
+
+    // MARK: - Pixels
+
+    /// RGBX bytes of the image composited on white
+    private static func rgb(_ image: CGImage) -> [UInt8]? {
+        let width = image.width, height = image.height
+        var bytes = [UInt8](repeating: 255, count: width * height * 4)
+        let drawn = bytes.withUnsafeMutableBytes { buffer -> Bool in
+            guard let context = CGContext(
+                data: buffer.baseAddress,
+                width: width,
+                height: height,
+                bitsPerComponent: 8,
+                bytesPerRow: width * 4,
+                space: CGColorSpace(name: CGColorSpace.sRGB) ?? CGColorSpaceCreateDeviceRGB(),
+                bitmapInfo: CGImageAlphaInfo.noneSkipLast.rawValue
+            ) else {
+                return false
+            }
+            context.draw(image, in: CGRect(x: 0, y: 0, width: width, height: height))
+            return true
+        }
+        return drawn ? bytes : nil
+    }
+
+    private static func labColors(_ pixels: [UInt8]) -> [SIMD3<Double>] {
+        // Renders have few distinct colors; convert each only once
+        var cache: [UInt32: SIMD3<Double>] = [:]
+        return stride(from: 0, to: pixels.count, by: 4).map { offset in
+            let key = UInt32(pixels[offset]) << 16 | UInt32(pixels[offset + 1]) << 8 | UInt32(pixels[offset + 2])
+            if let color = cache[key] {
+                return color
+            }
+            let color = lab(red: pixels[offset], green: pixels[offset + 1], blue: pixels[offset + 2])
+            cache[key] = color
+            return color
+        }
+    }
+
+    private static func diffImage(_ pixels: [UInt8], differing: [Bool], width: Int, height: Int) -> CGImage? {
+        var bytes = [UInt8](repeating: 255, count: pixels.count)
+        for index in differing.indices {
+            let offset = index * 4
+            if differing[index] {
+                bytes[offset] = 255
+                bytes[offset + 1] = 0
+                bytes[offset + 2] = 0
+            } else {
+                // Faded gray, so the red stands out and the model stays recognizable
+                let gray = (Int(pixels[offset]) + Int(pixels[offset + 1]) + Int(pixels[offset + 2])) / 3
+                let faded = UInt8(255 - (255 - gray) / 3)
+                bytes[offset] = faded
+                bytes[offset + 1] = faded
+                bytes[offset + 2] = faded
+            }
+        }
+        guard let provider = CGDataProvider(data: Data(bytes) as CFData) else { return nil }
+        return CGImage(
+            width: width,
+            height: height,
+            bitsPerComponent: 8,
+            bitsPerPixel: 32,
+            bytesPerRow: width * 4,
+            space: CGColorSpace(name: CGColorSpace.sRGB) ?? CGColorSpaceCreateDeviceRGB(),
+            bitmapInfo: CGBitmapInfo(rawValue: CGImageAlphaInfo.noneSkipLast.rawValue),
+            provider: provider,
+            decode: nil,
+            shouldInterpolate: false,
+            intent: .defaultIntent
+        )
+    }
+}
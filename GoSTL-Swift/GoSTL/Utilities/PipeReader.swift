@@ -0,0 +1,25 @@
+import Foundation
+
+/// Reads a pipe to its end on a background thread
+///
+/// A process writing to both stdout and stderr blocks once either pipe is full. Reading one of them
+/// here while the other is read on the calling thread keeps both flowing.
+final class PipeReader: @unchecked Sendable {
+    private var data = Data()
+    private let done = DispatchGroup()
+
+    init(_ pipe: Pipe) {
+        let handle = pipe.fileHandleForReading
+        done.enter()
+        DispatchQueue.global(qos: .utility).async {
+            self.data = handle.readDataToEndOfFile()
+            self.done.leave()
+        }
+    }
+
+    /// Everything written to the pipe (waits until the writer closed it)
+    func result() -> Data {
+        done.wait()
+        return data
+    }
+}
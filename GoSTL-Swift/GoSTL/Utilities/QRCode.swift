@@ -0,0 +1,70 @@
+import CoreImage
+import CoreImage.CIFilterBuiltins
+import Foundation
+
+/// QR codes for URLs: module matrices for labels, block characters for terminals (`gostl share`)
+enum QRCode {
+    /// Light modules around the code, required by scanners
+    static let quietZone = 2
+
+    /// Modules of the code, true for dark, in reading order (top row first); nil if the text is too long
+    static func modules(for text: String) -> [[Bool]]? {
+        let filter = CIFilter.qrCodeGenerator()
+        filter.message = Data(text.utf8)
+        filter.correctionLevel = "M"
+        guard let image = filter.outputImage else { return nil }
+
+        let width = Int(image.extent.width), height = Int(image.extent.height)
+        var pixels = [UInt8](repeating: 255, count: width * height)
+        CIContext().render(image, toBitmap: &pixels, rowBytes: width, bounds: image.extent, format: .L8, colorSpace: nil)
+        var rows = (0..<height).map { y in (0..<width).map { x in pixels[y * width + x] < 128 } }
+
+        // Crop the generator's margin
+        let dark = rows.indices.filter { rows[$0].contains(true) }
+        let columns = (0..<width).filter { x in rows.contains { $0[x] } }
+        guard let top = dark.first, let bottom = dark.last, let left = columns.first, let right = columns.last else { return nil }
+        rows = rows[top...bottom].map { Array($0[left...right]) }
+
+        // Finder patterns sit in the top-left, top-right and bottom-left corners; a bitmap read
+        // bottom up has one in the bottom-right corner instead
+        if hasFinderPattern(rows, row: rows.count - 7, column: rows.count - 7) {
+            rows.reverse()
+        }
+        return rows
+    }
+
+    /// The code as text, two module rows per line: `█` where both are dark, `▀`/`▄` where one is,
+    /// dark modules meant for dark text on a light background
+    static func lines(for text: String) -> [String]? {
+        guard let modules = modules(for: text) else { return nil }
+        let size = modules.count + 2 * quietZone
+        func isDark(_ row: Int, _ column: Int) -> Bool {
+            let r = row - quietZone, c = column - quietZone
+            return modules.indices.contains(r) && modules[r].indices.contains(c) && modules[r][c]
+        }
+        return stride(from: 0, to: size, by: 2).map { row in
+            String((0..<size).map { column -> Character in
+                switch (isDark(row, column), isDark(row + 1, column)) {
+                case (true, true): return "█"
+                case (true, false): return "▀"
+                case (false, true): return "▄"
+                case (false, false): return " "
+                }
+            })
+        }
+    }
+
+    /// Whether a 7×7 finder pattern (dark ring, light ring, dark 3×3 center) starts at the module
+    private static func hasFinderPattern(_ modules: [[Bool]], row: Int, column: Int) -> Bool {
+        guard row >= 0, column >= 0, row + 7 <= modules.count, column + 7 <= modules.count else { return false }
+        for y in 0..<7 {
+            for x in 0..<7 {
+                let ring = max(abs(y - 3), abs(x - 3))
+                if modules[row + y][column + x] != (ring != 2) {
+                    return false
+                }
+            }
+        }
+        return true
+    }
+}
@@ -0,0 +1,320 @@
+import CryptoKit
+import Foundation
+
+/// Errors that can occur while downloading remote models
+enum RemoteFileError: LocalizedError {
+    case unsupportedURL(String)
+    case unsupportedFormat(String)
+    case httpStatus(Int, url: String)
+    case downloadFailed(String, reason: String)
+
+    var errorDescription: String? {
+        switch self {
+        case .unsupportedURL(let url):
+            return "Unsupported URL \(url); use http, https or s3"
+        case .unsupportedFormat(let url):
+            return "Cannot open \(url): only STL and 3MF files (also .gz or .zst compressed) are downloaded; scripts, go3mf configs and archives must be opened from disk"
+        case .httpStatus(let status, let url) where url.hasPrefix("s3://") && status == 403:
+            return "Access to \(url) denied (HTTP 403); use a presigned https URL for private buckets"
+        case .httpStatus(let status, let url):
+            return "Cannot download \(url): HTTP \(status) \(HTTPURLResponse.localizedString(forStatusCode: status))"
+        case .downloadFailed(let url, let reason):
+            return "Cannot download \(url): \(reason)"
+        }
+    }
+}
+
+/// Local copies of models opened from http(s) and S3 URLs
+///
+/// Only meshes are downloaded: OpenSCAD and Python scripts, go3mf configs and archives would run
+/// code from the server when opened. Downloads stream to disk. Every URL gets its own directory below
+/// the cache root, holding the file under its original name (so formats and compression are detected
+/// as for local files) and the ETag and Last-Modified date of the response. Fetching the URL again sends a conditional request:
+/// an unchanged file (HTTP 304) is not downloaded again, and without a connection the cached copy is used. Other
+/// failures (e.g. an untrusted certificate) are reported, not hidden behind a stale copy.
+final class RemoteFileCache: @unchecked Sendable {
+    /// Environment variable overriding the cache directory
+    static let environmentKey = "GOSTL_CACHE_DIR"
+
+    static let schemes = ["http", "https", "s3"]
+
+    /// Formats opened from URLs (mesh files, optionally compressed)
+    static let remoteExtensions = ["stl", "3mf"]
+
+    static let shared = RemoteFileCache(root: configuredRoot())
+
+    /// Validators of a cached download, stored next to the file
+    struct Entry: Codable, Equatable {
+        var url: String
+        var fileName: String
+        var etag: String?
+        var lastModified: String?
+        var downloaded: Date
+    }
+
+    /// How a fetch was answered
+    enum Source: Equatable {
+        /// New or changed file
+        case downloaded
+        /// The server confirmed the cached copy (HTTP 304)
+        case revalidated
+        /// The server was unreachable; the cached copy may be stale
+        case offline
+    }
+
+    private static let entryFileName = "entry.json"
+
+    /// Errors that mean there is no connection to the server, so the cached copy is used
+    static let offlineErrors: Set<URLError.Code> = [
+        .notConnectedToInternet,
+        .networkConnectionLost,
+        .cannotFindHost,
+        .cannotConnectToHost,
+        .dnsLookupFailed,
+        .timedOut,
+        .internationalRoamingOff,
+        .dataNotAllowed
+    ]
+
+    let root: URL
+    private let session: URLSession
+    /// Guards `directoryLocks`
+    private let lock = NSLock()
+    /// One download per URL at a time, so two windows opening the same URL share the cached copy
+    private var directoryLocks: [URL: NSLock] = [:]
+
+    init(root: URL, session: URLSession = .shared) {
+        self.root = root
+        self.session = session
+    }
+
+    /// Root from `GOSTL_CACHE_DIR`, otherwise `~/Library/Caches/GoSTL/Remote`
+    static func configuredRoot(environment: [String: String] = ProcessInfo.processInfo.environment) -> URL {
+        if let path = environment[environmentKey], !path.isEmpty {
+            return URL(fileURLWithPath: (path as NSString).expandingTildeInPath, isDirectory: true)
+        }
+        let caches = FileManager.default.urls(for: .cachesDirectory, in: .userDomainMask).first
+            ?? FileManager.default.temporaryDirectory
+        return caches.appendingPathComponent("GoSTL/Remote", isDirectory: true)
+    }
+
+    /// The URL if the argument is an http(s) or s3 URL, nil for local paths
+    static func remoteURL(_ argument: String) -> URL? {
+        guard let url = URL(string: argument),
+              let scheme = url.scheme?.lowercased(),
+              schemes.contains(scheme),
+              let host = url.host, !host.isEmpty else {
+            return nil
+        }
+        return url
+    }
+
+    /// The http(s) URL a remote URL is downloaded from
+    ///
+    /// `s3://bucket/key` maps to the bucket's virtual-hosted endpoint (in `AWS_REGION` if set), or to
+    /// `$AWS_ENDPOINT_URL/bucket/key` for S3-compatible storage. Requests are unsigned, so private
+    /// objects need a presigned https URL.
+    static func httpURL(for url: URL, environment: [String: String] = ProcessInfo.processInfo.environment) -> URL? {
+        switch url.scheme?.lowercased() {
+        case "http", "https":
+            return url
+        case "s3":
+            guard let bucket = url.host, !bucket.isEmpty else { return nil }
+            let key = url.path.hasPrefix("/") ? String(url.path.dropFirst()) : url.path
+            guard !key.isEmpty else { return nil }
+            let encodedKey = key.addingPercentEncoding(withAllowedCharacters: .urlPathAllowed) ?? key
+            if let endpoint = environment["AWS_ENDPOINT_URL"], !endpoint.isEmpty {
+                let base = endpoint.hasSuffix("/") ? String(endpoint.dropLast()) : endpoint
+                return URL(string: "\(base)/\(bucket)/\(encodedKey)")
+            }
+            let region = environment["AWS_REGION"] ?? environment["AWS_DEFAULT_REGION"]
+            let host = region.map { "\(bucket).s3.\($0).amazonaws.com" } ?? "\(bucket).s3.amazonaws.com"
+            return URL(string: "https://\(host)/\(encodedKey)")
+        default:
+            return nil
+        }
+    }
+
+    /// Whether a file name is an STL or 3MF file (`part.stl.gz` included)
+    static func isRemoteModel(_ name: String) -> Bool {
+        remoteExtensions.contains(ModelFileLoader.modelExtension(of: URL(fileURLWithPath: name)))
+    }
+
+    /// Whether a URL may be downloaded: it names a mesh file, or no format the viewer knows (e.g.
+    /// attachment links of issue trackers, whose format is detected from the content)
+    static func isDownloadable(_ url: URL) -> Bool {
+        let name = url.lastPathComponent
+        return isRemoteModel(name)
+            || !ModelFileLoader.viewerExtensions.contains(ModelFileLoader.modelExtension(of: URL(fileURLWithPath: name)))
+    }
+
+    /// Name of the cached copy: the URL's file name when it names a mesh file. Otherwise the
+    /// server's suggested name without its extension, with the format detected from the content;
+    /// an extension from the server could turn the download into a script.
+    static func fileName(for url: URL, suggested: String?, contents: URL) -> String {
+        let name = url.lastPathComponent
+        if isRemoteModel(name) {
+            return name
+        }
+        let base = suggested.map { ModelFileLoader.modelName(of: URL(fileURLWithPath: $0)) } ?? ""
+        let stem = base.isEmpty || base.hasPrefix(".") ? "model" : base
+        return "\(stem).\(detectedExtension(of: contents))"
+    }
+
+    /// Mesh format of a download by its first bytes: 3MF (a ZIP container), compressed or plain STL
+    static func detectedExtension(of file: URL) -> String {
+        let handle = try? FileHandle(forReadingFrom: file)
+        defer { try? handle?.close() }
+        let magic = [UInt8]((try? handle?.read(upToCount: 4)) ?? Data())
+        switch magic {
+        case [0x50, 0x4B, 0x03, 0x04]:
+            return "3mf"
+        case let bytes where bytes.starts(with: [0x1F, 0x8B]):
+            return "stl.gz"
+        case [0x28, 0xB5, 0x2F, 0xFD]:
+            return "stl.zst"
+        default:
+            return "stl"
+        }
+    }
+
+    /// Directory of a URL's cached copy
+    func directory(for url: URL) -> URL {
+        let digest = SHA256.hash(data: Data(url.absoluteString.utf8))
+        let hex = digest.map { String(format: "%02x", $0) }.joined()
+        return root.appendingPathComponent(String(hex.prefix(16)), isDirectory: true)
+    }
+
+    /// Validators of the cached copy, nil if the URL has not been downloaded
+    func entry(for url: URL) -> Entry? {
+        let directory = directory(for: url)
+        guard let data = try? Data(contentsOf: directory.appendingPathComponent(Self.entryFileName)),
+              let entry = try? JSONDecoder().decode(Entry.self, from: data),
+              Self.isRemoteModel(entry.fileName),
+              FileManager.default.fileExists(atPath: directory.appendingPathComponent(entry.fileName).path) else {
+            return nil
+        }
+        return entry
+    }
+
+    /// Local copy of a remote file, downloaded or revalidated as needed (blocks until done)
+    func fetch(_ url: URL) throws -> (file: URL, source: Source) {
+        guard let httpURL = Self.httpURL(for: url) else {
+            throw RemoteFileError.unsupportedURL(url.absoluteString)
+        }
+        guard Self.isDownloadable(url) else {
+            throw RemoteFileError.unsupportedFormat(url.absoluteString)
+        }
+        let directory = directory(for: url)
+        let directoryLock = fetchLock(for: directory)
+        directoryLock.lock()
+        defer { directoryLock.unlock() }
+
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        let cached = entry(for: url)
+
+        var request = URLRequest(url: httpURL)
+        // Revalidation is done here, against the validators of the cached copy
+        request.cachePolicy = .reloadIgnoringLocalCacheData
+        if let cached {
+            request.setValue(cached.etag, forHTTPHeaderField: "If-None-Match")
+            request.setValue(cached.lastModified, forHTTPHeaderField: "If-Modified-Since")
+        }
+
+        let download: (file: URL?, response: HTTPURLResponse)
+        do {
+            download = try self.download(request, into: directory)
+        } catch {
+            if let cached, Self.isOffline(error) {
+                return (directory.appendingPathComponent(cached.fileName), .offline)
+            }
+            throw RemoteFileError.downloadFailed(url.absoluteString, reason: error.localizedDescription)
+        }
+        defer {
+            if let staged = download.file {
+                try? FileManager.default.removeItem(at: staged)
+            }
+        }
+
+        let status = download.response.statusCode
+        if status == 304, let cached {
+            return (directory.appendingPathComponent(cached.fileName), .revalidated)
+        }
+        guard (200..<300).contains(status), let staged = download.file else {
+            throw RemoteFileError.httpStatus(status, url: url.absoluteString)
+        }
+
+        if let cached {
+            try? FileManager.default.removeItem(at: directory.appendingPathComponent(cached.fileName))
+        }
+        let fileName = Self.fileName(for: url, suggested: download.response.suggestedFilename, contents: staged)
+        let destination = directory.appendingPathComponent(fileName)
+        try? FileManager.default.removeItem(at: destination)
+        try FileManager.default.moveItem(at: staged, to: destination)
+
+        let entry = Entry(
+            url: url.absoluteString,
+            fileName: fileName,
+            etag: download.response.value(forHTTPHeaderField: "ETag"),
+            lastModified: download.response.value(forHTTPHeaderField: "Last-Modified"),
+            downloaded: Date()
+        )
+        try JSONEncoder().encode(entry).write(to: directory.appendingPathComponent(Self.entryFileName))
+        return (destination, .downloaded)
+    }
+
+    /// Whether a download failed because the server could not be reached
+    static func isOffline(_ error: Error) -> Bool {
+        guard let error = error as? URLError else { return false }
+        return offlineErrors.contains(error.code)
+    }
+
+    /// Lock serializing fetches of the URL cached in the directory
+    private func fetchLock(for directory: URL) -> NSLock {
+        lock.lock()
+        defer { lock.unlock() }
+        if let existing = directoryLocks[directory] {
+            return existing
+        }
+        let created = NSLock()
+        directoryLocks[directory] = created
+        return created
+    }
+
+    /// Stream the response body to a staging file in the directory
+    private func download(_ request: URLRequest, into directory: URL) throws -> (file: URL?, response: HTTPURLResponse) {
+        final class Outcome: @unchecked Sendable {
+            var file: URL?
+            var response: URLResponse?
+            var error: Error?
+        }
+        let outcome = Outcome()
+        let staging = directory.appendingPathComponent("download-\(UUID().uuidString.prefix(8))")
+        let done = DispatchSemaphore(value: 0)
+
+        session.downloadTask(with: request) { location, response, error in
+            // The temporary file is removed when the handler returns
+            if let location {
+                do {
+                    try FileManager.default.moveItem(at: location, to: staging)
+                    outcome.file = staging
+                } catch {
+                    outcome.error = error
+                }
+            }
+            outcome.response = response
+            outcome.error = outcome.error ?? error
+            done.signal()
+        }
+        .resume()
+        done.wait()
+
+        if let error = outcome.error {
+            throw error
+        }
+        guard let response = outcome.response as? HTTPURLResponse else {
+            throw URLError(.badServerResponse)
+        }
+        return (outcome.file, response)
+    }
+}
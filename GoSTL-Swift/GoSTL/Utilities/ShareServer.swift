@@ -0,0 +1,330 @@
+import Foundation
+import Network
+
+/// Errors that can occur while starting the share server
+enum ShareServerError: LocalizedError {
+    case invalidPort(Int)
+
+    var errorDescription: String? {
+        switch self {
+        case .invalidPort(let port):
+            return "Invalid port \(port); use 1-65535, or 0 for any free port"
+        }
+    }
+}
+
+/// Minimal HTTP server for `gostl share`: serves one page, optionally behind basic auth
+///
+/// Every connection answers a single request and is closed. Only the request head is read,
+/// so bodies of unexpected requests are ignored. Connections that do not send a complete head
+/// in time are dropped, and wrong passwords are answered slowly and lock the client out after a few tries.
+final class ShareServer: @unchecked Sendable {
+    struct Credentials: Equatable {
+        var user: String
+        var password: String
+    }
+
+    /// Largest request head read before answering 431
+    static let maxRequestHeadSize = 16 * 1024
+    /// Seconds a connection may take to send its request head
+    static let requestHeadTimeout: TimeInterval = 10
+    /// Seconds before answering a wrong password
+    static let failedLoginDelay: TimeInterval = 1
+
+    let page: Data
+    let credentials: Credentials?
+    private let listener: NWListener
+    private let queue = DispatchQueue(label: "com.gostl.share")
+    /// Called with one line per answered request
+    private let log: @Sendable (String) -> Void
+    /// Connections still waiting for their request head (used on `queue` only)
+    private var pendingConnections: Set<ObjectIdentifier> = []
+    private var loginThrottle = LoginThrottle()
+
+    /// - Parameter port: Port to listen on, 0 for any free port
+    init(page: Data, credentials: Credentials?, port: Int, log: @escaping @Sendable (String) -> Void = { _ in }) throws {
+        guard (0...65535).contains(port), let endpointPort = NWEndpoint.Port(rawValue: UInt16(port)) else {
+            throw ShareServerError.invalidPort(port)
+        }
+        self.page = page
+        self.credentials = credentials
+        self.log = log
+        let parameters = NWParameters.tcp
+        parameters.allowLocalEndpointReuse = true
+        listener = try NWListener(using: parameters, on: endpointPort)
+    }
+
+    /// Start listening; `ready` gets the port once the server accepts connections, or the error
+    func start(ready: @escaping @Sendable (Result<UInt16, Error>) -> Void) {
+        listener.stateUpdateHandler = { [weak self] state in
+            switch state {
+            case .ready:
+                ready(.success(self?.listener.port?.rawValue ?? 0))
+            case .failed(let error):
+                ready(.failure(error))
+            default:
+                break
+            }
+        }
+        listener.newConnectionHandler = { [weak self] connection in
+            guard let self else {
+                connection.cancel()
+                return
+            }
+            self.pendingConnections.insert(ObjectIdentifier(connection))
+            self.queue.asyncAfter(deadline: .now() + Self.requestHeadTimeout) { [weak self, weak connection] in
+                guard let self, let connection, self.pendingConnections.remove(ObjectIdentifier(connection)) != nil else { return }
+                connection.cancel()
+            }
+            connection.start(queue: self.queue)
+            self.receive(on: connection, buffer: Data())
+        }
+        listener.start(queue: queue)
+    }
+
+    func stop() {
+        listener.cancel()
+    }
+
+    private func receive(on connection: NWConnection, buffer: Data) {
+        connection.receive(minimumIncompleteLength: 1, maximumLength: 4096) { [weak self] data, _, isComplete, error in
+            guard let self else {
+                connection.cancel()
+                return
+            }
+            var buffer = buffer
+            if let data {
+                buffer.append(data)
+            }
+            if let end = buffer.range(of: Data("\r\n\r\n".utf8)) {
+                self.pendingConnections.remove(ObjectIdentifier(connection))
+                let head = String(decoding: buffer[..<end.lowerBound], as: UTF8.self)
+                self.answer(head, on: connection)
+            } else if buffer.count > Self.maxRequestHeadSize {
+                self.pendingConnections.remove(ObjectIdentifier(connection))
+                self.send(Self.response(status: 431), on: connection)
+            } else if isComplete || error != nil {
+                self.pendingConnections.remove(ObjectIdentifier(connection))
+                connection.cancel()
+            } else {
+                self.receive(on: connection, buffer: buffer)
+            }
+        }
+    }
+
+    /// Answer a request, throttling clients that keep sending wrong passwords
+    private func answer(_ head: String, on connection: NWConnection) {
+        let requestLine = head.components(separatedBy: "\r\n").first ?? ""
+        let client = Self.client(of: connection.endpoint)
+        let now = Date()
+
+        var response: Response
+        if credentials != nil, let retryAfter = loginThrottle.lockout(of: client, at: now) {
+            response = Self.response(status: 429)
+            response.headers.append(("Retry-After", String(Int(retryAfter.rounded(.up)))))
+        } else {
+            response = Self.response(to: head, page: page, credentials: credentials)
+            if response.isFailedLogin {
+                loginThrottle.recordFailure(of: client, at: now)
+            } else if credentials != nil, ![400, 401].contains(response.status) {
+                // Past the password check (malformed requests are answered before it)
+                loginThrottle.recordSuccess(of: client)
+            }
+        }
+        log("\(connection.endpoint) \(requestLine) \(response.status)")
+
+        if response.isFailedLogin {
+            let delayed = response
+            queue.asyncAfter(deadline: .now() + Self.failedLoginDelay) { [weak self] in
+                self?.send(delayed, on: connection)
+            }
+        } else {
+            send(response, on: connection)
+        }
+    }
+
+    /// Host of a connection's remote endpoint, the unit failed logins are counted for
+    static func client(of endpoint: NWEndpoint) -> String {
+        if case .hostPort(let host, _) = endpoint {
+            return "\(host)"
+        }
+        return "\(endpoint)"
+    }
+
+    private func send(_ response: Response, on connection: NWConnection) {
+        connection.send(content: response.data, completion: .contentProcessed { _ in
+            connection.cancel()
+        })
+    }
+
+    // MARK: - Requests
+
+    struct Response {
+        var status: Int
+        var headers: [(name: String, value: String)] = []
+        var body = Data()
+        /// Whether the request carried wrong credentials (not sent)
+        var isFailedLogin = false
+
+        /// Status line, headers and body as sent
+        var data: Data {
+            var head = "HTTP/1.1 \(status) \(ShareServer.reason(for: status))\r\n"
+            for header in headers {
+                head += "\(header.name): \(header.value)\r\n"
+            }
+            return Data((head + "\r\n").utf8) + body
+        }
+
+        func header(_ name: String) -> String? {
+            headers.first { $0.name.caseInsensitiveCompare(name) == .orderedSame }?.value
+        }
+    }
+
+    /// Answer to a request head (request line and headers, without the blank line)
+    static func response(to head: String, page: Data, credentials: Credentials?) -> Response {
+        let lines = head.components(separatedBy: "\r\n")
+        let requestLine = lines[0].split(separator: " ")
+        guard requestLine.count == 3, requestLine[2].hasPrefix("HTTP/") else {
+            return response(status: 400)
+        }
+
+        if let credentials {
+            let authorization = lines.dropFirst().first { $0.lowercased().hasPrefix("authorization:") }
+                .map { $0.dropFirst("authorization:".count).trimmingCharacters(in: .whitespaces) }
+            guard let authorization, isAuthorized(authorization, credentials: credentials) else {
+                var unauthorized = response(status: 401)
+                unauthorized.headers.append(("WWW-Authenticate", "Basic realm=\"GoSTL\", charset=\"UTF-8\""))
+                unauthorized.isFailedLogin = authorization != nil
+                return unauthorized
+            }
+        }
+
+        let method = requestLine[0]
+        guard method == "GET" || method == "HEAD" else {
+            var notAllowed = response(status: 405)
+            notAllowed.headers.append(("Allow", "GET, HEAD"))
+            return notAllowed
+        }
+        let path = requestLine[1].split(separator: "?", maxSplits: 1).first.map(String.init) ?? "/"
+        guard path == "/" || path == "/index.html" else {
+            return response(status: 404)
+        }
+
+        return Response(
+            status: 200,
+            headers: [
+                ("Content-Type", "text/html; charset=utf-8"),
+                ("Content-Length", String(page.count)),
+                ("Cache-Control", "no-store"),
+                ("X-Content-Type-Options", "nosniff"),
+                ("Connection", "close")
+            ],
+            body: method == "HEAD" ? Data() : page
+        )
+    }
+
+    /// Plain-text error response
+    static func response(status: Int) -> Response {
+        let body = Data("\(status) \(reason(for: status))\n".utf8)
+        return Response(
+            status: status,
+            headers: [
+                ("Content-Type", "text/plain; charset=utf-8"),
+                ("Content-Length", String(body.count)),
+                ("Connection", "close")
+            ],
+            body: body
+        )
+    }
+
+    static func reason(for status: Int) -> String {
+        switch status {
+        case 200: return "OK"
+        case 400: return "Bad Request"
+        case 401: return "Unauthorized"
+        case 404: return "Not Found"
+        case 405: return "Method Not Allowed"
+        case 429: return "Too Many Requests"
+        case 431: return "Request Header Fields Too Large"
+        default: return "Error"
+        }
+    }
+
+    /// Whether an `Authorization` header value carries the credentials
+    static func isAuthorized(_ authorization: String, credentials: Credentials) -> Bool {
+        let parts = authorization.split(separator: " ", maxSplits: 1)
+        guard parts.count == 2, parts[0].lowercased() == "basic",
+              let decoded = Data(base64Encoded: String(parts[1])) else {
+            return false
+        }
+        return constantTimeEquals(decoded, Data("\(credentials.user):\(credentials.password)".utf8))
+    }
+
+    /// Compare without returning early, so the time taken does not tell how much of a guess was right
+    static func constantTimeEquals(_ lhs: Data, _ rhs: Data) -> Bool {
+        var difference: UInt8 = lhs.count == rhs.count ? 0 : 1
+        let left = Array(lhs)
+        let right = Array(rhs)
+        for index in 0..<max(left.count, right.count) {
+            let a = index < left.count ? left[index] : 0
+            let b = index < right.count ? right[index] : 0
+            difference |= a ^ b
+        }
+        return difference == 0
+    }
+
+    /// Failed logins per client; after `maxFailures` wrong passwords in a row a client is locked out for `lockoutDuration`
+    struct LoginThrottle {
+        static let maxFailures = 5
+        static let lockoutDuration: TimeInterval = 60
+
+        private var failures: [String: Int] = [:]
+        private var lockedUntil: [String: Date] = [:]
+
+        /// Seconds until the client may try again, or nil if it is not locked out
+        func lockout(of client: String, at now: Date) -> TimeInterval? {
+            guard let until = lockedUntil[client], until > now else { return nil }
+            return until.timeIntervalSince(now)
+        }
+
+        mutating func recordFailure(of client: String, at now: Date) {
+            let count = failures[client, default: 0] + 1
+            if count >= Self.maxFailures {
+                failures[client] = nil
+                lockedUntil[client] = now.addingTimeInterval(Self.lockoutDuration)
+            } else {
+                failures[client] = count
+            }
+        }
+
+        mutating func recordSuccess(of client: String) {
+            failures[client] = nil
+            lockedUntil[client] = nil
+        }
+    }
+
+    // MARK: - Addresses
+
+    /// IPv4 addresses of the active network interfaces, for URLs that work from other machines
+    static func localAddresses() -> [String] {
+        var list: UnsafeMutablePointer<ifaddrs>?
+        guard getifaddrs(&list) == 0, let first = list else { return [] }
+        defer { freeifaddrs(list) }
+
+        var addresses: [String] = []
+        for pointer in sequence(first: first, next: { $0.pointee.ifa_next }) {
+            let interface = pointer.pointee
+            guard let address = interface.ifa_addr,
+                  address.pointee.sa_family == UInt8(AF_INET),
+                  interface.ifa_flags & UInt32(IFF_UP) != 0,
+                  interface.ifa_flags & UInt32(IFF_LOOPBACK) == 0 else {
+                continue
+            }
+            var host = [CChar](repeating: 0, count: Int(NI_MAXHOST))
+            if getnameinfo(address, socklen_t(address.pointee.sa_len), &host, socklen_t(host.count), nil, 0, NI_NUMERICHOST) == 0 {
+                addresses.append(String(cString: host))
+            }
+        }
+        return addresses
+    }
+}
@@ -0,0 +1,115 @@
+import Foundation
+
+/// Managed location for temporary files (rendered STLs, go3mf builds, plugin exchange files).
+///
+/// Every process gets its own `session-<pid>` directory below the root, removed when the process exits.
+/// Directories of processes that crashed or were killed are removed on the next start.
+final class TempWorkspace: @unchecked Sendable {
+    /// Environment variable overriding the root directory (takes precedence over the setting)
+    static let environmentKey = "GOSTL_TEMP_DIR"
+
+    /// Root used when neither the environment variable nor the setting is set
+    static let defaultRoot = FileManager.default.temporaryDirectory.appendingPathComponent("gostl")
+
+    static let shared = TempWorkspace(root: configuredRoot())
+
+    /// Directory containing the session directories
+    let root: URL
+
+    /// Directory of this process
+    let sessionDirectory: URL
+
+    private let lock = NSLock()
+    private var isCreated = false
+
+    init(root: URL, processID: Int32 = ProcessInfo.processInfo.processIdentifier) {
+        self.root = root
+        self.sessionDirectory = root.appendingPathComponent("session-\(processID)")
+    }
+
+    /// Root from `GOSTL_TEMP_DIR`, then the "Temporary files" setting, then the system temp directory
+    static func configuredRoot(
+        environment: [String: String] = ProcessInfo.processInfo.environment,
+        setting: String? = AppSettings.shared.temporaryDirectory
+    ) -> URL {
+        if let path = environment[environmentKey], !path.isEmpty {
+            return URL(fileURLWithPath: (path as NSString).expandingTildeInPath, isDirectory: true)
+        }
+        if let path = setting, !path.isEmpty {
+            return URL(fileURLWithPath: (path as NSString).expandingTildeInPath, isDirectory: true)
+        }
+        return defaultRoot
+    }
+
+    /// The session directory, created on first use
+    var directory: URL {
+        lock.lock()
+        defer { lock.unlock() }
+        if !isCreated {
+            try? FileManager.default.createDirectory(at: sessionDirectory, withIntermediateDirectories: true)
+            isCreated = true
+        }
+        return sessionDirectory
+    }
+
+    /// A fresh file URL in the session directory, e.g. `go3mf-1a2b3c4d.3mf`
+    func makeURL(prefix: String, pathExtension: String) -> URL {
+        directory.appendingPathComponent("\(prefix)-\(UUID().uuidString.prefix(8)).\(pathExtension)")
+    }
+
+    /// Remove the session directory (on exit)
+    func cleanUp() {
+        lock.lock()
+        defer { lock.unlock() }
+        try? FileManager.default.removeItem(at: sessionDirectory)
+        isCreated = false
+    }
+
+    /// Remove session directories of processes that are no longer running
+    /// - Returns: Number of directories removed
+    @discardableResult
+    func removeStaleSessions() -> Int {
+        let entries = (try? FileManager.default.contentsOfDirectory(at: root, includingPropertiesForKeys: nil)) ?? []
+        var removed = 0
+        for entry in entries where entry != sessionDirectory {
+            let name = entry.lastPathComponent
+            guard name.hasPrefix("session-"), let pid = Int32(name.dropFirst("session-".count)),
+                  !Self.isRunning(pid) else { continue }
+            if (try? FileManager.default.removeItem(at: entry)) != nil {
+                removed += 1
+            }
+        }
+        return removed
+    }
+
+    /// Remove leftovers of interrupted OpenSCAD renders next to a source file.
+    ///
+    /// CSG files and 2D wrappers have to be written next to the .scad file so relative imports
+    /// keep resolving; only files matching those names and older than `age` are touched.
+    /// - Returns: Number of files removed
+    @discardableResult
+    static func removeStaleRenderFiles(in directory: URL, olderThan age: TimeInterval = 3600, now: Date = Date()) -> Int {
+        let entries = (try? FileManager.default.contentsOfDirectory(
+            at: directory, includingPropertiesForKeys: [.contentModificationDateKey]
+        )) ?? []
+        var removed = 0
+        for entry in entries where isRenderFile(entry.lastPathComponent) {
+            let modified = (try? entry.resourceValues(forKeys: [.contentModificationDateKey]))?.contentModificationDate ?? now
+            guard now.timeIntervalSince(modified) > age else { continue }
+            if (try? FileManager.default.removeItem(at: entry)) != nil {
+                removed += 1
+            }
+        }
+        return removed
+    }
+
+    /// Names written by `OpenSCADRenderer` next to the source: `gostl_<id>.csg`, `gostl_2d_<id>.scad`,
+    /// `gostl_module_<id>.scad` and the `gostl_<id>[_suffix].stl` files of older versions that rendered there
+    static func isRenderFile(_ name: String) -> Bool {
+        name.range(of: #"^gostl_(2d_|module_)?[0-9A-F]{8}(_[a-z0-9]+)?\.(csg|scad|stl)$"#, options: .regularExpression) != nil
+    }
+
+    private static func isRunning(_ pid: Int32) -> Bool {
+        kill(pid, 0) == 0 || errno == EPERM
+    }
+}
@@ -0,0 +1,73 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class AmbientOcclusionTests: XCTestCase {
+
+    /// 10 mm block standing on a 24 x 10 mm base plate, with a concave crease where they meet
+    private let blockOnBase = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(24, 10, 2))
+        + ModelGenerator.box(from: Vector3(0, 0, 2), to: Vector3(10, 10, 12))
+
+    func testOneValuePerCorner() throws {
+        let occlusion = try AmbientOcclusion.bake(triangles: blockOnBase)
+
+        XCTAssertEqual(occlusion.count, blockOnBase.count * 3)
+        XCTAssertTrue(occlusion.allSatisfy { (0...1).contains($0) })
+    }
+
+    func testConvexShapeIsUnoccluded() throws {
+        let cube = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let occlusion = try AmbientOcclusion.bake(triangles: cube)
+
+        XCTAssertEqual(occlusion.max() ?? 1, 0, accuracy: 1e-6)
+    }
+
+    func testCreaseIsOccluded() throws {
+        let occlusion = try AmbientOcclusion.bake(triangles: blockOnBase)
+        let crease = Vector3(10, 10, 2)
+        let top = Vector3(0, 0, 12)
+
+        let creaseValues = occlusion.indices.filter { corner(of: $0) == crease }.map { occlusion[$0] }
+        let topValues = occlusion.indices.filter { corner(of: $0) == top }.map { occlusion[$0] }
+        XCTAssertFalse(creaseValues.isEmpty)
+        XCTAssertGreaterThan(creaseValues.min() ?? 0, 0.05)
+        XCTAssertEqual(topValues.max() ?? 1, 0, accuracy: 1e-6)
+    }
+
+    func testSharedVerticesGetOneValue() throws {
+        let occlusion = try AmbientOcclusion.bake(triangles: blockOnBase)
+        let crease = Vector3(10, 10, 2)
+
+        let values = Set(occlusion.indices.filter { corner(of: $0) == crease }.map { occlusion[$0] })
+        XCTAssertEqual(values.count, 1)
+    }
+
+    func testHemisphereDirections() {
+        let directions = AmbientOcclusion.hemisphereDirections(count: 32)
+
+        XCTAssertEqual(directions.count, 32)
+        for direction in directions {
+            XCTAssertEqual(simd_length(direction), 1, accuracy: 1e-9)
+            XCTAssertGreaterThan(direction.z, 0)
+        }
+    }
+
+    func testCancelledBakeThrows() {
+        let job = JobContext()
+        job.cancel()
+
+        XCTAssertThrowsError(try AmbientOcclusion.bake(triangles: blockOnBase, job: job)) { error in
+            XCTAssertTrue(error is CancellationError)
+        }
+    }
+
+    func testEmptyMesh() throws {
+        XCTAssertEqual(try AmbientOcclusion.bake(triangles: []), [])
+    }
+
+    /// Position of a triangle corner by its index in the bake result
+    private func corner(of index: Int) -> Vector3 {
+        let triangle = blockOnBase[index / 3]
+        return [triangle.v1, triangle.v2, triangle.v3][index % 3]
+    }
+}
@@ -0,0 +1,124 @@
+import XCTest
+@testable import GoSTL
+
+final class AnalysisFormatterTests: XCTestCase {
+    private let record = AnalysisRecord(fields: [
+        .init(key: "file", label: "File", unit: nil, value: .text("part, v2.stl")),
+        .init(key: "triangles", label: "Triangles", unit: nil, value: .integer(12)),
+        .init(key: "dimensions.x", label: "Width (X)", unit: "mm", value: .number(20.5)),
+        .init(key: "volume", label: "Volume", unit: "mm³", value: .number(1000))
+    ])
+
+    // MARK: - Formatter Tests
+
+    func testTextFormatterAlignsLabels() throws {
+        let text = try TextAnalysisFormatter().format([record])
+
+        XCTAssertEqual(text, """
+            File       part, v2.stl
+            Triangles  12
+            Width (X)  20.50 mm
+            Volume     1000.00 mm³
+            """)
+    }
+
+    func testJSONFormatterNestsDottedKeys() throws {
+        let json = try JSONAnalysisFormatter().format([record])
+        let object = try XCTUnwrap(JSONSerialization.jsonObject(with: Data(json.utf8)) as? [String: Any])
+
+        XCTAssertEqual(object["triangles"] as? Int, 12)
+        XCTAssertEqual((object["dimensions"] as? [String: Any])?["x"] as? Double, 20.5)
+    }
+
+    func testJSONFormatterReturnsArrayForSeveralModels() throws {
+        let json = try JSONAnalysisFormatter().format([record, record])
+
+        XCTAssertEqual((try JSONSerialization.jsonObject(with: Data(json.utf8)) as? [Any])?.count, 2)
+    }
+
+    func testCSVFormatterQuotesValues() throws {
+        let csv = try CSVAnalysisFormatter().format([record, record])
+
+        XCTAssertEqual(csv, """
+            file,triangles,dimensions.x,volume
+            "part, v2.stl",12,20.5,1000.0
+            "part, v2.stl",12,20.5,1000.0
+            """)
+    }
+
+    func testSelectPrintsSingleValue() throws {
+        XCTAssertEqual(try ValueAnalysisFormatter(key: "volume").format([record]), "1000.0")
+        XCTAssertThrowsError(try ValueAnalysisFormatter(key: "mass").format([record])) { error in
+            XCTAssertTrue(error.localizedDescription.contains("dimensions.x"))
+        }
+    }
+
+    // MARK: - Record Tests
+
+    func testRecordFromModelAnalysis() {
+        let model = STLModel(triangles: [
+            Triangle(v1: Vector3(0, 0, 0), v2: Vector3(10, 0, 0), v3: Vector3(0, 5, 2))
+        ])
+
+        let record = AnalysisRecord(fileName: "tri.stl", analysis: model.analyze())
+
+        XCTAssertEqual(record.value(for: "file"), .text("tri.stl"))
+        XCTAssertEqual(record.value(for: "triangles"), .integer(1))
+        XCTAssertEqual(record.value(for: "dimensions.y"), .number(5))
+        XCTAssertEqual(record.value(for: "bounds.max.z"), .number(2))
+        XCTAssertNil(record.value(for: "mass"))
+    }
+
+    func testRecordIncludesMassEstimate() {
+        let record = AnalysisRecord(
+            fileName: "cube.stl",
+            analysis: STLModel().analyze(),
+            estimate: Material.petg.estimate(volume: 10_000, infill: 0.2)
+        )
+
+        XCTAssertEqual(record.value(for: "material"), .text("PETG"))
+        XCTAssertEqual(record.value(for: "infill"), .number(20))
+        XCTAssertEqual(record.value(for: "mass"), .number(10 * 1.27 * 0.2))
+    }
+
+    // MARK: - Diff Tests
+
+    func testDiffListsChangedMetrics() {
+        let changed = AnalysisRecord(fields: [
+            .init(key: "file", label: "File", unit: nil, value: .text("part, v2.stl")),
+            .init(key: "triangles", label: "Triangles", unit: nil, value: .integer(16)),
+            .init(key: "dimensions.x", label: "Width (X)", unit: "mm", value: .number(20.5)),
+            .init(key: "volume", label: "Volume", unit: "mm³", value: .number(950))
+        ])
+
+        let diff = AnalysisDiff(from: record, to: changed)
+
+        XCTAssertEqual(diff.changes.map(\.key), ["triangles", "volume"])
+        XCTAssertEqual(diff.formatted(), """
+            Triangles  12 → 16  (Δ +4, +33.3%)
+            Volume     1000.00 → 950.00 mm³  (Δ -50.00, -5.0%)
+            """)
+    }
+
+    func testDiffWithoutChanges() {
+        let diff = AnalysisDiff(from: record, to: record)
+
+        XCTAssertTrue(diff.isEmpty)
+        XCTAssertEqual(diff.formatted(), "no changes")
+    }
+
+    func testWatchRequiresSingleTextModel() {
+        XCTAssertThrowsError(try AnalyzeCommand.parse(["a.scad", "b.scad", "--watch"]))
+        XCTAssertThrowsError(try AnalyzeCommand.parse(["a.scad", "--watch", "--format", "json"]))
+        XCTAssertNoThrow(try AnalyzeCommand.parse(["a.scad", "--watch"]))
+    }
+
+    func testMaterialAndInfillOptions() throws {
+        let command = try AnalyzeCommand.parse(["part.stl", "--material", "petg", "--infill", "20"])
+
+        XCTAssertEqual(command.material, .petg)
+        XCTAssertEqual(command.infill, 20)
+        XCTAssertThrowsError(try AnalyzeCommand.parse(["part.stl", "--material", "unobtainium"]))
+        XCTAssertThrowsError(try AnalyzeCommand.parse(["part.stl", "--infill", "120"]))
+    }
+}
@@ -0,0 +1,119 @@
+import XCTest
+@testable import GoSTL
+
+final class AnnotationTests: XCTestCase {
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-tests-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    // MARK: - Annotation Tests
+
+    func testAnnotationAtPickedPointUsesOriginalCoordinates() {
+        let point = MeasurementPoint(position: Vector3(1, 2, 3), normal: Vector3(0, 0, 1))
+        let offset = Vector3(100, 0, -50)
+
+        let annotation = Annotation(text: "crack here", at: point, coordinateOffset: offset)
+
+        XCTAssertEqual(annotation.position, Vector3(101, 2, -47))
+        XCTAssertEqual(annotation.normal, Vector3(0, 0, 1))
+        XCTAssertEqual(annotation.renderPosition(coordinateOffset: offset), Vector3(1, 2, 3))
+    }
+
+    func testSummary() {
+        let annotation = Annotation(text: "crack here", position: Vector3(12.5, 3, 40.25))
+
+        XCTAssertEqual(annotation.summary, "\"crack here\" at (12.500, 3.000, 40.250)")
+    }
+
+    func testCodableRoundTrip() throws {
+        let annotation = Annotation(text: "check fit", position: Vector3(1, 2, 3), normal: Vector3(0, 1, 0), createdAt: Date(timeIntervalSince1970: 1000))
+
+        let decoded = try JSONDecoder().decode(Annotation.self, from: JSONEncoder().encode(annotation))
+
+        XCTAssertEqual(decoded.text, "check fit")
+        XCTAssertEqual(decoded.position, annotation.position)
+        XCTAssertEqual(decoded.normal, annotation.normal)
+        XCTAssertEqual(decoded.createdAt, annotation.createdAt)
+    }
+
+    func testDecodeWithoutOptionalFields() throws {
+        let json = #"{"text": "burr", "position": {"x": 1, "y": 2, "z": 3}}"#
+
+        let decoded = try JSONDecoder().decode(Annotation.self, from: Data(json.utf8))
+
+        XCTAssertEqual(decoded.text, "burr")
+        XCTAssertEqual(decoded.normal, .zero)
+    }
+
+    // MARK: - Persistence Tests
+
+    func testSidecarRoundTrip() throws {
+        let model = directory.appendingPathComponent("housing.stl")
+        var sidecar = ModelSidecar()
+        XCTAssertTrue(sidecar.isEmpty)
+        sidecar.annotations = [Annotation(text: "crack here", position: Vector3(5, 6, 7))]
+        XCTAssertFalse(sidecar.isEmpty)
+
+        try sidecar.write(for: model)
+        let loaded = try ModelSidecar.load(for: model)
+
+        XCTAssertEqual(loaded.annotations.map(\.text), ["crack here"])
+        XCTAssertEqual(loaded.annotations.first?.position, Vector3(5, 6, 7))
+    }
+
+    func testSidecarWithoutAnnotations() throws {
+        let model = directory.appendingPathComponent("bracket.stl")
+        let json = #"{"version": 1, "properties": [{"key": "Revision", "value": "B"}]}"#
+        try Data(json.utf8).write(to: ModelSidecar.url(for: model))
+
+        XCTAssertTrue(try ModelSidecar.load(for: model).annotations.isEmpty)
+    }
+
+    func testMeasurementSetOmitsEmptyAnnotations() throws {
+        let empty = MeasurementSet(fileName: "part.stl", measurements: [])
+        XCTAssertNil(empty.annotations)
+        XCTAssertFalse(String(decoding: try empty.encoded(), as: UTF8.self).contains("annotations"))
+
+        let annotated = MeasurementSet(fileName: "part.stl", measurements: [], annotations: [Annotation(text: "burr", position: Vector3(1, 0, 0))])
+        let decoded = try MeasurementSet.decode(annotated.encoded())
+        XCTAssertEqual(decoded.annotations?.map(\.text), ["burr"])
+    }
+
+    // MARK: - Report Tests
+
+    func testReportListsAnnotations() {
+        let report = InspectionReport.generate(
+            fileName: "housing.stl",
+            referenceGeometry: ReferenceGeometrySystem(),
+            annotations: [Annotation(text: "crack here", position: Vector3(1, 2, 3)), Annotation(text: "burr", position: Vector3(4, 5, 6))]
+        )
+
+        XCTAssertTrue(report.contains("ANNOTATIONS"))
+        XCTAssertTrue(report.contains("  1. \"crack here\" at (1.000, 2.000, 3.000)"))
+        XCTAssertTrue(report.contains("  2. \"burr\" at (4.000, 5.000, 6.000)"))
+    }
+
+    func testReportWithoutAnnotations() {
+        let report = InspectionReport.generate(fileName: "housing.stl", referenceGeometry: ReferenceGeometrySystem())
+
+        XCTAssertFalse(report.contains("ANNOTATIONS"))
+    }
+
+    // MARK: - Layout Tests
+
+    func testLabelsAvoidMeasurementLabels() throws {
+        let obstacle = CGRect(x: 70, y: 90, width: 60, height: 20)
+        let item = LabelLayout.Item(id: 0, anchor: CGPoint(x: 100, y: 100), size: CGSize(width: 60, height: 20), priority: 0)
+
+        let placement = try XCTUnwrap(LabelLayout().layout([item], in: CGRect(x: 0, y: 0, width: 800, height: 600), avoiding: [obstacle]).first)
+
+        XCTAssertFalse(placement.frame.intersects(obstacle))
+    }
+}
@@ -0,0 +1,65 @@
+import XCTest
+@testable import GoSTL
+
+final class BoundingDimensionsTests: XCTestCase {
+    private let box = BoundingBox(min: Vector3(-10, -5, 0), max: Vector3(10, 5, 4))
+
+    func testOverallDimensions() {
+        let measurements = BoundingDimensions.measurements(for: box)
+
+        XCTAssertEqual(measurements.map(\.type), [.distance, .distance, .distance])
+        XCTAssertEqual(measurements.map(\.value), [20, 10, 4])
+        XCTAssertTrue(measurements.allSatisfy(\.isBoundingDimension))
+        XCTAssertTrue(measurements.allSatisfy { $0.points.allSatisfy(\.isAirPoint) })
+    }
+
+    func testDimensionLinesAreOffsetFromBoxCorners() {
+        let width = BoundingDimensions.measurements(for: box)[0]
+        let gap = 20 * BoundingDimensions.offsetFraction
+
+        XCTAssertEqual(width.extensionAnchors, [Vector3(-10, -5, 0), Vector3(10, -5, 0)])
+        XCTAssertEqual(width.points.map(\.position), [Vector3(-10, -5 - gap, 0), Vector3(10, -5 - gap, 0)])
+        // The measured value is the length of the dimension line
+        XCTAssertEqual(width.points[0].position.distance(to: width.points[1].position), width.value, accuracy: 1e-9)
+    }
+
+    func testFlatModelHasNoHeightDimension() {
+        let flat = BoundingBox(min: Vector3(0, 0, 0), max: Vector3(30, 20, 0))
+
+        XCTAssertEqual(BoundingDimensions.measurements(for: flat).map(\.value), [30, 20])
+        XCTAssertTrue(BoundingDimensions.measurements(for: BoundingBox()).isEmpty)
+    }
+
+    func testAddingAgainReplacesOverallDimensions() {
+        let system = MeasurementSystem()
+        let picked = Measurement(type: .angle, points: [], value: 90)
+        system.measurements = [picked]
+
+        system.addBoundingDimensions(for: box)
+        system.selectedMeasurements = [1]
+        system.addBoundingDimensions(for: BoundingBox(min: .zero, max: Vector3(1, 2, 3)))
+
+        XCTAssertEqual(system.measurements.map(\.value), [90, 1, 2, 3])
+        XCTAssertTrue(system.selectedMeasurements.isEmpty)
+    }
+
+    func testAddedDimensionsAreReported() {
+        let system = MeasurementSystem()
+        var reported: [Double] = []
+        system.onMeasurementAdded = { reported.append($0.value) }
+
+        system.addBoundingDimensions(for: BoundingBox(min: .zero, max: Vector3(1, 2, 3)))
+
+        XCTAssertEqual(reported, [1, 2, 3])
+    }
+
+    func testAnchorsMoveAndPersistWithMeasurement() throws {
+        let width = BoundingDimensions.measurements(for: box)[0]
+
+        let moved = width.translated(by: Vector3(1, 2, 3))
+        XCTAssertEqual(moved.extensionAnchors?.first, Vector3(-9, -3, 3))
+
+        let decoded = try MeasurementSet.decode(MeasurementSet(fileName: "part.stl", measurements: [width]).encoded())
+        XCTAssertEqual(decoded.entries[0].measurement.extensionAnchors, width.extensionAnchors)
+    }
+}
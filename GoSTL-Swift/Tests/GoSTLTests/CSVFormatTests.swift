@@ -0,0 +1,71 @@
+import XCTest
+@testable import GoSTL
+
+final class CSVFormatTests: XCTestCase {
+    // MARK: - Format Tests
+
+    func testNumbersUseDecimalSeparator() {
+        XCTAssertEqual(CSVFormat.standard.number(1234.5, decimals: 3), "1234.500")
+        XCTAssertEqual(CSVFormat.european.number(1234.5, decimals: 3), "1234,500")
+        XCTAssertEqual(CSVFormat.european.number(-0.25, decimals: 2), "-0,25")
+        XCTAssertEqual(CSVFormat.european.localized("1.0e-05"), "1,0e-05")
+    }
+
+    func testFieldsWithDelimiterAreQuoted() {
+        XCTAssertEqual(CSVFormat.standard.row(["a,b", "c;d", "say \"hi\""]), "\"a,b\",c;d,\"say \"\"hi\"\"\"")
+        XCTAssertEqual(CSVFormat.european.row(["a,b", "c;d"]), "a,b;\"c;d\"")
+        XCTAssertEqual(CSVFormat(delimiter: .tab).row(["x", "y z"]), "x\ty z")
+    }
+
+    func testDecimalCommaWithCommaDelimiterStaysParseable() {
+        let format = CSVFormat(delimiter: .comma, decimalSeparator: .comma)
+
+        XCTAssertEqual(format.row(["a", format.number(1.5, decimals: 1)]), "a,\"1,5\"")
+    }
+
+    func testMatchingLocale() {
+        XCTAssertEqual(CSVFormat.matching(Locale(identifier: "de_DE")), .european)
+        XCTAssertEqual(CSVFormat.matching(Locale(identifier: "fr_FR")), .european)
+        XCTAssertEqual(CSVFormat.matching(Locale(identifier: "en_US")), .standard)
+    }
+
+    // MARK: - Exporter Tests
+
+    func testAnalysisCSVInEuropeanFormat() throws {
+        let record = AnalysisRecord(fields: [
+            .init(key: "file", label: "File", unit: nil, value: .text("part; v2.stl")),
+            .init(key: "triangles", label: "Triangles", unit: nil, value: .integer(12)),
+            .init(key: "volume", label: "Volume", unit: "mm³", value: .number(1000.25))
+        ])
+
+        let csv = try CSVAnalysisFormatter(csv: .european).format([record])
+
+        XCTAssertEqual(csv, """
+            file;triangles;volume
+            "part; v2.stl";12;1000,25
+            """)
+    }
+
+    func testComparisonCSVWithTabs() {
+        let baseline = MeasurementSet(fileName: "a", measurements: [Measurement(type: .distance, points: [], value: 1.5)])
+
+        let csv = MeasurementComparison.compare(baseline: baseline, current: baseline)
+            .csv(format: CSVFormat(delimiter: .tab, decimalSeparator: .comma))
+
+        XCTAssertEqual(csv, """
+            name\tunit\tbaseline\tcurrent\tdelta\tstatus\tstale_points
+            Distance 1\tmm\t1,500000\t1,500000\t0,000000\tunchanged\t0
+
+            """)
+    }
+
+    func testHeightMapCSVInEuropeanFormat() throws {
+        let model = STLModel(triangles: [
+            Triangle(v1: Vector3(0, 0, 1.5), v2: Vector3(1, 0, 1.5), v3: Vector3(1, 1, 1.5)),
+            Triangle(v1: Vector3(0, 0, 1.5), v2: Vector3(1, 1, 1.5), v3: Vector3(0, 1, 1.5))
+        ])
+        let map = try HeightMap.compute(model: model, resolution: 1)
+
+        XCTAssertEqual(map.csv(decimals: 1, format: .european), "1,5\n")
+    }
+}
@@ -0,0 +1,80 @@
+import XCTest
+@testable import GoSTL
+
+final class CalibrationCheckTests: XCTestCase {
+
+    /// A 20 mm cube printed 1 % too large in X and 0.5 % too small in Y
+    private let printedCube = ModelGenerator.box(from: Vector3(5, 5, 0), to: Vector3(25.2, 24.9, 20))
+
+    func testAxisErrorsOfPrintedCube() throws {
+        let report = try XCTUnwrap(CalibrationCheck.measure(triangles: printedCube, artifact: .cube20))
+
+        XCTAssertEqual(report.axes.map(\.name), ["X", "Y", "Z"])
+        XCTAssertEqual(report.axes[0].measured, 20.2, accuracy: 1e-9)
+        XCTAssertEqual(report.axes[0].error, 0.2, accuracy: 1e-9)
+        XCTAssertEqual(report.axes[0].errorPercent, 1, accuracy: 1e-9)
+        XCTAssertEqual(report.axes[1].errorPercent, -0.5, accuracy: 1e-9)
+        XCTAssertEqual(report.axes[2].error, 0, accuracy: 1e-9)
+        XCTAssertTrue(report.axes.allSatisfy(\.fromFaces))
+        XCTAssertEqual(report.alignedFraction, 1, accuracy: 1e-9)
+        XCTAssertTrue(report.warnings.isEmpty)
+    }
+
+    func testCompensationCancelsScaleError() throws {
+        let report = try XCTUnwrap(CalibrationCheck.measure(triangles: printedCube, artifact: .cube20))
+
+        XCTAssertEqual(report.axes[0].compensation, 2000 / 20.2, accuracy: 1e-9)
+        XCTAssertEqual(report.axes[1].compensation, 2000 / 19.9, accuracy: 1e-9)
+        XCTAssertEqual(report.axes[2].compensation, 100, accuracy: 1e-9)
+        XCTAssertEqual(report.xyCompensation, (2000 / 20.2 + 2000 / 19.9) / 2, accuracy: 1e-9)
+        // Scaling the measured size by the compensation gives the nominal size
+        XCTAssertEqual(report.axes[0].measured * report.axes[0].compensation / 100, 20, accuracy: 1e-9)
+    }
+
+    func testCustomBlock() throws {
+        let plate = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(60, 30, 5))
+        let report = try XCTUnwrap(CalibrationCheck.measure(triangles: plate, artifact: .custom(size: Vector3(60, 30, 5))))
+
+        XCTAssertTrue(report.artifact.isCustom)
+        XCTAssertEqual(report.axes.map(\.nominal), [60, 30, 5])
+        XCTAssertTrue(report.axes.allSatisfy { abs($0.error) < 1e-9 })
+    }
+
+    func testWrongArtifactIsFlagged() throws {
+        let report = try XCTUnwrap(CalibrationCheck.measure(triangles: printedCube, artifact: .cube50))
+        XCTAssertEqual(report.axes[2].errorPercent, -60, accuracy: 1e-9)
+        XCTAssertEqual(report.warnings.count, 1)
+        XCTAssertTrue(report.warnings[0].contains("more than 10 %"))
+    }
+
+    func testRotatedScanIsNotAligned() throws {
+        let angle = Double.pi / 4
+        let rotated = printedCube.map { triangle -> Triangle in
+            func rotate(_ v: Vector3) -> Vector3 {
+                Vector3(v.x * cos(angle) - v.y * sin(angle), v.x * sin(angle) + v.y * cos(angle), v.z)
+            }
+            return Triangle(v1: rotate(triangle.v1), v2: rotate(triangle.v2), v3: rotate(triangle.v3))
+        }
+        let report = try XCTUnwrap(CalibrationCheck.measure(triangles: rotated, artifact: .cube20))
+
+        XCTAssertFalse(report.isAligned)
+        XCTAssertFalse(report.axes[0].fromFaces)
+        XCTAssertTrue(report.axes[2].fromFaces)
+        XCTAssertTrue(report.warnings.contains { $0.contains("Align the scan") })
+        XCTAssertTrue(report.warnings.contains { $0.contains("X, Y") })
+    }
+
+    func testReportText() throws {
+        let report = try XCTUnwrap(CalibrationCheck.measure(triangles: printedCube, artifact: .cube20))
+        let lines = report.text.components(separatedBy: "\n")
+
+        XCTAssertEqual(lines.first, "Calibration: 20 mm Calibration Cube")
+        XCTAssertTrue(lines[1].hasPrefix("X: nominal 20.000 mm, measured 20.200 mm, error +0.200 mm (+1.00 %)"))
+        XCTAssertTrue(lines.last?.hasPrefix("XY compensation:") ?? false)
+    }
+
+    func testEmptyMeshAndInvalidSize() {
+        XCTAssertNil(CalibrationCheck.measure(triangles: [], artifact: .cube20))
+        XCTAssertNil(CalibrationCheck.measure(triangles: printedCube, artifact: .custom(size: Vector3(20, 0, 20))))
+    }
+}
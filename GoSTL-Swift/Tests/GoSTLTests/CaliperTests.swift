@@ -0,0 +1,66 @@
+import XCTest
+@testable import GoSTL
+
+final class CaliperTests: XCTestCase {
+
+    func testOpensAroundModel() throws {
+        let cube = ModelGenerator.cube(size: Vector3(10, 20, 30))
+
+        let caliper = try XCTUnwrap(Caliper(direction: .y, triangles: cube.triangles))
+
+        XCTAssertEqual(caliper.opening, -10...10)
+        let reading = try XCTUnwrap(caliper.reading(of: cube.triangles))
+        XCTAssertEqual(reading.distance, 20, accuracy: 1e-9)
+    }
+
+    func testSeatsOnCrestOfRoundFace() throws {
+        // 12 segments: no vertex lies on the 45° diagonal, the jaws still rest on the outermost ones
+        let cylinder = ModelGenerator.cylinder(radius: 5, height: 10, segments: 12)
+        let diagonal = Vector3(1, 1, 0).normalized()
+
+        let reading = try XCTUnwrap(Caliper.reading(of: cylinder.triangles, along: diagonal, between: -100...100))
+
+        // Widest between opposite vertices at 30° and 60°: 2 · 5 · cos 15°
+        XCTAssertEqual(reading.distance, 10 * cos(15 * Double.pi / 180), accuracy: 1e-9)
+    }
+
+    func testJawsCloseFromWherePlaced() throws {
+        // Step: 10 mm tall block next to a 20 mm tall one
+        let low = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let high = ModelGenerator.box(from: Vector3(20, 0, 0), to: Vector3(30, 10, 20))
+
+        // Jaws around the lower block only along X
+        let reading = try XCTUnwrap(Caliper.reading(of: low + high, along: .unitX, between: -5...15))
+
+        XCTAssertEqual(reading.low, 0, accuracy: 1e-9)
+        XCTAssertEqual(reading.high, 10, accuracy: 1e-9)
+    }
+
+    func testJawStopsAtSurfaceReachingPast() throws {
+        let block = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+
+        let reading = try XCTUnwrap(Caliper.reading(of: block, along: .unitZ, between: 2...20))
+
+        XCTAssertEqual(reading.low, 2, accuracy: 1e-9)
+        XCTAssertEqual(reading.high, 10, accuracy: 1e-9)
+        XCTAssertEqual(reading.lowContact.z, 2, accuracy: 1e-9)
+    }
+
+    func testNoSurfaceBetweenJaws() {
+        let block = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+
+        XCTAssertNil(Caliper.reading(of: block, along: .unitX, between: 20...30))
+    }
+
+    func testMeasurement() throws {
+        let block = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let reading = try XCTUnwrap(Caliper.reading(of: block, along: .unitX, between: -1...11))
+
+        let measurement = Caliper.measurement(for: reading, along: .unitX)
+
+        XCTAssertEqual(measurement.type, .distance)
+        XCTAssertEqual(measurement.value, 10, accuracy: 1e-9)
+        XCTAssertEqual(measurement.points[1].position.x - measurement.points[0].position.x, 10, accuracy: 1e-9)
+        XCTAssertFalse(measurement.isBoundingDimension)
+    }
+}
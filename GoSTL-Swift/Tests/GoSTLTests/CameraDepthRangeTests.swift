@@ -0,0 +1,81 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class CameraDepthRangeTests: XCTestCase {
+
+    func testFitDepthRangeEnclosesScene() {
+        let camera = Camera()
+        let bounds = BoundingBox(min: Vector3(-10, -10, -10), max: Vector3(10, 10, 10))
+        camera.target = .zero
+        camera.distance = 100
+
+        camera.fitDepthRange(to: bounds)
+
+        let radius = Float(bounds.diagonal / 2)
+        XCTAssertLessThan(camera.nearPlane, 100 - radius)
+        XCTAssertGreaterThan(camera.farPlane, 100 + radius)
+        XCTAssertFalse(camera.hasDepthPrecisionWarning)
+    }
+
+    func testFitDepthRangeInsideSceneKeepsRatioBounded() {
+        let camera = Camera()
+        let bounds = BoundingBox(min: Vector3(-1000, -1000, -1000), max: Vector3(1000, 1000, 1000))
+        camera.distance = 10
+
+        camera.fitDepthRange(to: bounds)
+
+        XCTAssertGreaterThan(camera.nearPlane, 0)
+        XCTAssertLessThanOrEqual(camera.depthRatio, Camera.maxAutoDepthRatio * 1.001)
+    }
+
+    func testManualDepthRangeWarnsOnExtremeRatio() {
+        let camera = Camera()
+
+        camera.setDepthRange(near: 0.001, far: 10000)
+
+        XCTAssertFalse(camera.autoDepthRange)
+        XCTAssertTrue(camera.hasDepthPrecisionWarning)
+    }
+
+    // MARK: - Cutaway Tests
+
+    func testCutawayPlaneIsOffWithoutDepth() {
+        let camera = Camera()
+
+        XCTAssertNil(camera.cutawayDepth)
+        XCTAssertEqual(camera.cutawayPlane, .zero)
+    }
+
+    func testCutawayPlaneCutsAheadOfCamera() {
+        let camera = Camera()
+        camera.target = .zero
+        camera.distance = 100
+        camera.cutawayDepth = 100
+
+        let plane = camera.cutawayPlane
+        func signedDistance(_ point: SIMD3<Float>) -> Float {
+            simd_dot(SIMD3(plane.x, plane.y, plane.z), point) + plane.w
+        }
+
+        // The cut passes through the target: the near half is cut away, the far half stays
+        XCTAssertEqual(signedDistance(camera.target), 0, accuracy: 0.001)
+        XCTAssertLessThan(signedDistance(camera.position), 0)
+        XCTAssertGreaterThan(signedDistance(camera.target + camera.forward * 10), 0)
+    }
+
+    func testPickRayStartsAtCutaway() {
+        let camera = Camera()
+        camera.target = .zero
+        camera.distance = 100
+        let viewSize = CGSize(width: 800, height: 600)
+        let center = CGPoint(x: 400, y: 300)
+
+        XCTAssertEqual(simd_distance(camera.pickRay(screenPos: center, viewSize: viewSize).origin, camera.position), 0, accuracy: 0.001)
+
+        camera.cutawayDepth = 40
+        let ray = camera.pickRay(screenPos: center, viewSize: viewSize)
+        XCTAssertEqual(simd_distance(ray.origin, camera.position), 40, accuracy: 0.01)
+        XCTAssertEqual(simd_dot(ray.direction, camera.forward), 1, accuracy: 0.001)
+    }
+}
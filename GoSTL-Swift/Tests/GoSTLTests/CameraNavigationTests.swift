@@ -0,0 +1,146 @@
+import XCTest
+@testable import GoSTL
+
+final class CameraNavigationTests: XCTestCase {
+
+    // MARK: - Zoom To Cursor Tests
+
+    func testZoomTowardPointKeepsItOnScreen() throws {
+        let camera = Camera()
+        camera.distance = 200
+        let viewSize = CGSize(width: 800, height: 600)
+        let point = Vector3(20, -15, 10)
+
+        let before = try XCTUnwrap(camera.project(worldPosition: point, viewSize: viewSize))
+        camera.zoom(delta: -80, toward: point.float3)
+        let after = try XCTUnwrap(camera.project(worldPosition: point, viewSize: viewSize))
+
+        XCTAssertEqual(camera.distance, 120, accuracy: 1e-9)
+        XCTAssertEqual(after.x, before.x, accuracy: 0.5)
+        XCTAssertEqual(after.y, before.y, accuracy: 0.5)
+    }
+
+    func testZoomWithoutPointKeepsTarget() {
+        let camera = Camera()
+        camera.target = SIMD3(1, 2, 3)
+
+        camera.zoom(delta: 10, toward: nil)
+
+        XCTAssertEqual(camera.target, SIMD3(1, 2, 3))
+    }
+
+    // MARK: - Inertia Tests
+
+    func testRotationInertiaDecaysToRest() {
+        let camera = Camera()
+        let startAngle = camera.angleY
+        camera.rotationVelocity = SIMD2(0, 1)
+
+        for _ in 0..<300 {
+            camera.stepInertia(deltaTime: 1.0 / 60.0)
+        }
+
+        XCTAssertFalse(camera.hasInertia)
+        // Total travel is velocity / damping
+        XCTAssertEqual(camera.angleY - startAngle, 1 / Camera.inertiaDamping, accuracy: 0.02)
+    }
+
+    func testZoomInertiaTravelsRequestedDistance() {
+        let camera = Camera()
+        camera.distance = 200
+        camera.zoomVelocity = -50 * Camera.inertiaDamping
+
+        for _ in 0..<300 {
+            camera.stepInertia(deltaTime: 1.0 / 60.0)
+        }
+
+        XCTAssertFalse(camera.hasInertia)
+        XCTAssertEqual(camera.distance, 150, accuracy: 5)
+    }
+
+    func testResetStopsInertia() {
+        let camera = Camera()
+        camera.rotationVelocity = SIMD2(1, 1)
+        camera.zoomVelocity = 10
+
+        camera.reset()
+
+        XCTAssertFalse(camera.hasInertia)
+    }
+
+    // MARK: - Target Transition Tests
+
+    func testAnimateTargetReachesPointKeepingDistance() {
+        let camera = Camera()
+        camera.distance = 150
+        let destination = SIMD3<Float>(10, -5, 2)
+
+        camera.animateTarget(to: destination)
+        XCTAssertTrue(camera.isTransitioningTarget)
+
+        // Halfway the target is between start and destination
+        camera.advance(deltaTime: Camera.targetTransitionDuration / 2)
+        XCTAssertEqual(camera.target.x, 5, accuracy: 0.01)
+
+        camera.advance(deltaTime: Camera.targetTransitionDuration / 2)
+        XCTAssertFalse(camera.isTransitioningTarget)
+        XCTAssertEqual(camera.target, destination)
+        XCTAssertEqual(camera.distance, 150)
+    }
+
+    func testNewDragCancelsTargetTransition() {
+        let camera = Camera()
+        camera.animateTarget(to: SIMD3(10, 0, 0))
+
+        camera.stopInertia()
+        camera.advance(deltaTime: 1)
+
+        XCTAssertEqual(camera.target, .zero)
+    }
+
+    // MARK: - Snapped Rotation Tests
+
+    func testSnappedAnglesRoundToFifteenDegrees() {
+        let camera = Camera()
+        let degree = Double.pi / 180
+
+        camera.setSnappedAngles(x: 37 * degree, y: -98 * degree)
+
+        XCTAssertEqual(camera.angleX, 30 * degree, accuracy: 1e-9)
+        XCTAssertEqual(camera.angleY, -105 * degree, accuracy: 1e-9)
+    }
+
+    func testSnappedPitchStaysClamped() {
+        let camera = Camera()
+
+        camera.setSnappedAngles(x: Double.pi / 2, y: 0)
+
+        XCTAssertEqual(camera.angleX, Double.pi / 2 - 0.1, accuracy: 1e-9)
+    }
+
+    func testQuarterTurnsFromHomeView() {
+        let camera = Camera()
+        camera.rotationVelocity = SIMD2(0, 1)
+
+        // Home view (yaw 180° + 0.5 rad) rounds to the front view first
+        camera.rotateQuarterTurns(pitch: 0, yaw: 1)
+
+        XCTAssertEqual(camera.angleX, 0, accuracy: 1e-9)
+        XCTAssertEqual(camera.angleY, 1.5 * Double.pi, accuracy: 1e-9)
+        XCTAssertFalse(camera.hasInertia)
+    }
+
+    func testQuarterTurnUpReachesTopView() {
+        let camera = Camera()
+        camera.setPreset(.front)
+
+        camera.rotateQuarterTurns(pitch: 1, yaw: 0)
+
+        XCTAssertEqual(camera.angleX, CameraPreset.top.angles.x, accuracy: 1e-9)
+        XCTAssertEqual(camera.angleY, CameraPreset.top.angles.y, accuracy: 1e-9)
+
+        // Already at the top: stays there
+        camera.rotateQuarterTurns(pitch: 1, yaw: 0)
+        XCTAssertEqual(camera.angleX, CameraPreset.top.angles.x, accuracy: 1e-9)
+    }
+}
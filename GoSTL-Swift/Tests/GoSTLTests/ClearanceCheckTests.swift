@@ -0,0 +1,135 @@
+import XCTest
+@testable import GoSTL
+
+final class ClearanceCheckTests: XCTestCase {
+
+    // MARK: - Clearance Tests
+
+    func testSeparatedBoxes() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let other = TestModels.box(from: Vector3(12.5, 2, 2), to: Vector3(20, 8, 8))
+
+        let result = try XCTUnwrap(ClearanceCheck.check(model: model, other: other))
+
+        XCTAssertFalse(result.isIntersecting)
+        XCTAssertEqual(result.minimumDistance, 2.5, accuracy: 1e-9)
+        XCTAssertEqual(result.pointOnModel.x, 10, accuracy: 1e-9)
+        XCTAssertEqual(result.pointOnOther.x, 12.5, accuracy: 1e-9)
+        XCTAssertEqual(result.location.x, 11.25, accuracy: 1e-9)
+    }
+
+    func testDiagonalGap() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let other = TestModels.box(from: Vector3(13, 14, 0), to: Vector3(20, 20, 10))
+
+        let result = try XCTUnwrap(ClearanceCheck.check(model: model, other: other))
+
+        XCTAssertEqual(result.minimumDistance, 5, accuracy: 1e-9)  // 3-4-5 between the vertical edges
+    }
+
+    func testOverlappingBoxesIntersect() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let other = TestModels.box(from: Vector3(8, 2, 2), to: Vector3(15, 8, 8))
+
+        let result = try XCTUnwrap(ClearanceCheck.check(model: model, other: other))
+
+        XCTAssertTrue(result.isIntersecting)
+        XCTAssertEqual(result.minimumDistance, 0)
+        XCTAssertFalse(result.otherIntersectingTriangles.isEmpty)
+        // Only the wall at x = 10 is pierced
+        for index in result.intersectingTriangles {
+            XCTAssertEqual(model[index].v1.x, 10, accuracy: 1e-9)
+        }
+        XCTAssertNil(result.measurement())
+    }
+
+    func testTouchingBoxesAreNotIntersecting() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let other = TestModels.box(from: Vector3(2, 2, 10), to: Vector3(8, 8, 15))
+
+        let result = try XCTUnwrap(ClearanceCheck.check(model: model, other: other))
+
+        XCTAssertFalse(result.isIntersecting)
+        XCTAssertEqual(result.minimumDistance, 0, accuracy: 1e-9)
+        XCTAssertNil(result.measurement())
+    }
+
+    func testEmptyModel() throws {
+        XCTAssertNil(try ClearanceCheck.check(model: [], other: TestModels.box(from: .zero, to: Vector3(1, 1, 1))))
+    }
+
+    func testMeasurement() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let other = TestModels.box(from: Vector3(0, 0, 13), to: Vector3(10, 10, 20))
+
+        let result = try XCTUnwrap(ClearanceCheck.check(model: model, other: other))
+        let measurement = try XCTUnwrap(result.measurement())
+
+        XCTAssertEqual(measurement.type, .distance)
+        XCTAssertEqual(measurement.value, 3, accuracy: 1e-9)
+        XCTAssertEqual(measurement.points[0].position.z, 10, accuracy: 1e-9)
+        XCTAssertEqual(measurement.points[1].position.z, 13, accuracy: 1e-9)
+    }
+
+    func testIntersectionBounds() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let other = TestModels.box(from: Vector3(8, 2, 2), to: Vector3(15, 8, 8))
+
+        let result = try XCTUnwrap(ClearanceCheck.check(model: model, other: other))
+        let bounds = try XCTUnwrap(result.intersectionBounds(in: STLModel(triangles: model)))
+
+        XCTAssertEqual(bounds.min.x, 10, accuracy: 1e-9)
+        XCTAssertEqual(bounds.max.x, 10, accuracy: 1e-9)
+    }
+
+    // MARK: - Triangle Distance Tests
+
+    func testSegmentIntersection() throws {
+        let triangle = Triangle(v1: Vector3(0, 0, 0), v2: Vector3(10, 0, 0), v3: Vector3(0, 10, 0))
+
+        let hit = try XCTUnwrap(TriangleDistance.segmentIntersection(from: Vector3(2, 2, -1), to: Vector3(2, 2, 1), triangle: triangle))
+        XCTAssertEqual(hit.z, 0, accuracy: 1e-12)
+
+        // Ending on the face is touching, not piercing
+        XCTAssertNil(TriangleDistance.segmentIntersection(from: Vector3(2, 2, 0), to: Vector3(2, 2, 1), triangle: triangle))
+        // Outside the triangle and on its border
+        XCTAssertNil(TriangleDistance.segmentIntersection(from: Vector3(5, 0, -1), to: Vector3(5, 0, 1), triangle: triangle))
+        XCTAssertNil(TriangleDistance.segmentIntersection(from: Vector3(8, 8, -1), to: Vector3(8, 8, 1), triangle: triangle))
+    }
+
+    func testClosestPointOnTriangle() {
+        let triangle = Triangle(v1: Vector3(0, 0, 0), v2: Vector3(10, 0, 0), v3: Vector3(0, 10, 0))
+
+        XCTAssertEqual(TriangleDistance.closestPoint(on: triangle, to: Vector3(2, 3, 5)).distance(to: Vector3(2, 3, 0)), 0, accuracy: 1e-12)
+        XCTAssertEqual(TriangleDistance.closestPoint(on: triangle, to: Vector3(-5, -5, 1)).distance(to: Vector3(0, 0, 0)), 0, accuracy: 1e-12)
+        XCTAssertEqual(TriangleDistance.closestPoint(on: triangle, to: Vector3(5, -3, 0)).distance(to: Vector3(5, 0, 0)), 0, accuracy: 1e-12)
+    }
+
+    func testSkewEdges() {
+        let a = Triangle(v1: Vector3(-5, 0, 0), v2: Vector3(5, 0, 0), v3: Vector3(0, 0, -5))
+        let b = Triangle(v1: Vector3(0, -5, 2), v2: Vector3(0, 5, 2), v3: Vector3(0, 0, 7))
+
+        let result = TriangleDistance.closestPoints(a, b)
+
+        XCTAssertFalse(result.intersects)
+        XCTAssertEqual(result.distance, 2, accuracy: 1e-9)
+    }
+
+    // MARK: - Spatial Accelerator Tests
+
+    func testBoxDistance() {
+        let a = BoundingBox(min: Vector3(0, 0, 0), max: Vector3(1, 1, 1))
+
+        XCTAssertEqual(SpatialAccelerator.boxDistance(a, BoundingBox(min: Vector3(4, 1, 1), max: Vector3(5, 2, 2))), 3, accuracy: 1e-12)
+        XCTAssertEqual(SpatialAccelerator.boxDistance(a, BoundingBox(min: Vector3(0.5, 0.5, 0.5), max: Vector3(2, 2, 2))), 0)
+    }
+
+    func testFindTrianglesNearBox() {
+        let triangles = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        let accelerator = SpatialAccelerator(triangles: triangles)
+        let query = BoundingBox(min: Vector3(12, 2, 2), max: Vector3(13, 8, 8))
+
+        XCTAssertEqual(Set(accelerator.findTriangles(near: query, maxDistance: 2.5)), [6, 7])
+        XCTAssertTrue(accelerator.findTriangles(near: query, maxDistance: 1).isEmpty)
+    }
+}
@@ -0,0 +1,114 @@
+import XCTest
+@testable import GoSTL
+
+final class ColorGroupTests: XCTestCase {
+
+    private let red = TriangleColor(1, 0, 0)
+    private let blue = TriangleColor(0, 0, 1, 0.5)
+
+    private func triangle(x: Double, color: TriangleColor?) -> Triangle {
+        Triangle(v1: Vector3(x, 0, 0), v2: Vector3(x + 1, 0, 0), v3: Vector3(x, 1, 0), normal: nil, color: color)
+    }
+
+    private func model() -> STLModel {
+        STLModel(triangles: [
+            triangle(x: 0, color: red),
+            triangle(x: 1, color: nil),
+            triangle(x: 2, color: red),
+            triangle(x: 3, color: blue)
+        ], name: "assembly")
+    }
+
+    // MARK: - Grouping Tests
+
+    func testGroupsInOrderOfAppearance() {
+        let groups = ColorGroup.groups(in: model().triangles)
+
+        XCTAssertEqual(groups.map(\.id), ["#FF0000FF", ColorGroup.uncoloredID, "#0000FF80"])
+        XCTAssertEqual(groups.map(\.triangleCount), [2, 1, 1])
+    }
+
+    func testNames() {
+        let groups = ColorGroup.groups(in: model().triangles)
+
+        XCTAssertEqual(groups.map(\.name), ["#FF0000", "No color", "#0000FF80"])
+    }
+
+    func testHidingGroups() {
+        let filtered = ColorGroup.model(model(), hiding: ["#FF0000FF"])
+
+        XCTAssertEqual(filtered.triangles.count, 2)
+        XCTAssertEqual(filtered.name, "assembly")
+        XCTAssertFalse(filtered.triangles.contains { $0.color == red })
+    }
+
+    func testHidingEverythingKeepsTheModel() {
+        let all = Set(ColorGroup.groups(in: model().triangles).map(\.id))
+
+        XCTAssertEqual(ColorGroup.model(model(), hiding: all).triangles.count, 4)
+    }
+
+    // MARK: - 3MF Material Tests
+
+    private func threeMF(_ resources: String) throws -> ThreeMFParseResult {
+        var zip = ZipWriter()
+        zip.add(path: ModelMetadata.threeMFModelPath, contents: Data("""
+            <?xml version="1.0" encoding="UTF-8"?>
+            <model unit="millimeter" xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02">
+              <resources>
+            \(resources)
+              </resources>
+              <build><item objectid="2"/><item objectid="3"/></build>
+            </model>
+            """.utf8))
+        return try ThreeMFParser.parseWithPlates(data: zip.finish(), name: "assembly")
+    }
+
+    private let mesh = """
+        <mesh>
+          <vertices><vertex x="0" y="0" z="0"/><vertex x="1" y="0" z="0"/><vertex x="0" y="1" z="0"/></vertices>
+          <triangles><triangle v1="0" v2="1" v3="2"/></triangles>
+        </mesh>
+        """
+
+    func testBaseMaterialColors() throws {
+        // Layout written by OpenSCAD's 3MF export with color-mode=model
+        let result = try threeMF("""
+            <basematerials id="1">
+              <base name="red" displaycolor="#FF0000"/>
+              <base name="blue" displaycolor="#0000FF80"/>
+            </basematerials>
+            <object id="2" type="model" pid="1" pindex="0">\(mesh)</object>
+            <object id="3" type="model" pid="1" pindex="1">\(mesh)</object>
+            """)
+
+        let groups = ColorGroup.groups(in: result.allTriangles)
+        XCTAssertEqual(groups.map(\.id), ["#FF0000FF", "#0000FF80"])
+    }
+
+    func testTriangleColorGroupProperty() throws {
+        let result = try threeMF("""
+            <m:colorgroup id="5" xmlns:m="http://schemas.microsoft.com/3dmanufacturing/material/2015/02">
+              <m:color color="#00FF00"/>
+            </m:colorgroup>
+            <object id="2" type="model">
+              <mesh>
+                <vertices><vertex x="0" y="0" z="0"/><vertex x="1" y="0" z="0"/><vertex x="0" y="1" z="0"/></vertices>
+                <triangles><triangle v1="0" v2="1" v3="2" pid="5" p1="0"/></triangles>
+              </mesh>
+            </object>
+            <object id="3" type="model">\(mesh)</object>
+            """)
+
+        XCTAssertEqual(result.allTriangles.map(\.color), [TriangleColor(0, 1, 0), nil])
+    }
+
+    // MARK: - OpenSCAD Tests
+
+    func testThreeMFExportArgumentsRequestColors() {
+        let arguments = OpenSCADRenderer.threeMFColorArguments
+
+        XCTAssertTrue(arguments.contains("export-3mf/color-mode=model"))
+        XCTAssertTrue(arguments.contains("--backend=manifold"))
+    }
+}
@@ -0,0 +1,69 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class ColoredMeshExporterTests: XCTestCase {
+    /// Unit square of two triangles sharing the diagonal
+    private let square = [
+        Triangle(v1: Vector3(0, 0, 0), v2: Vector3(1, 0, 0), v3: Vector3(1, 1, 0)),
+        Triangle(v1: Vector3(0, 0, 0), v2: Vector3(1, 1, 0), v3: Vector3(0, 1, 0))
+    ]
+    private let red = TriangleColor(1, 0, 0)
+    private let blue = TriangleColor(0, 0, 1)
+    private let gray = SIMD3<Float>(0.5, 0.5, 0.5)
+
+    func testVerticesAreSharedOnlyWhereColorsMatch() throws {
+        let uniform = try ColoredMeshExporter.indexedMesh(triangles: square, colors: VertexColors(perTriangle: [red, red]), materialColor: gray)
+        XCTAssertEqual(uniform.positions.count, 4)
+        XCTAssertEqual(uniform.faces.count, 2)
+
+        let split = try ColoredMeshExporter.indexedMesh(triangles: square, colors: VertexColors(perTriangle: [red, blue]), materialColor: gray)
+        XCTAssertEqual(split.positions.count, 6)
+        XCTAssertEqual(split.colors.first, SIMD4<UInt8>(255, 0, 0, 255))
+    }
+
+    func testUncoloredVerticesUseTheMaterialColor() throws {
+        let mesh = try ColoredMeshExporter.indexedMesh(triangles: square, colors: VertexColors(perTriangle: [nil, blue]), materialColor: gray)
+
+        XCTAssertEqual(mesh.colors[0], SIMD4<UInt8>(128, 128, 128, 255))
+    }
+
+    func testColorsMustMatchTheModel() {
+        XCTAssertThrowsError(try ColoredMeshExporter.indexedMesh(triangles: square, colors: VertexColors(perTriangle: [red]), materialColor: gray))
+        XCTAssertThrowsError(try ColoredMeshExporter.indexedMesh(triangles: [], colors: VertexColors(perTriangle: []), materialColor: gray))
+    }
+
+    func testBinaryPLYLayout() throws {
+        let mesh = try ColoredMeshExporter.indexedMesh(triangles: square, colors: VertexColors(perTriangle: [red, red]), materialColor: gray)
+        let data = ColoredMeshExporter.plyData(mesh, name: "square.stl")
+        let headerEnd = try XCTUnwrap(data.range(of: Data("end_header\n".utf8)))
+        let header = String(decoding: data[..<headerEnd.upperBound], as: UTF8.self)
+
+        XCTAssertTrue(header.hasPrefix("ply\nformat binary_little_endian 1.0\n"))
+        XCTAssertTrue(header.contains("element vertex 4\n"))
+        XCTAssertTrue(header.contains("property uchar red\n"))
+        XCTAssertTrue(header.contains("element face 2\n"))
+        // 3 floats + 4 color bytes per vertex, count byte + 3 ints per face
+        XCTAssertEqual(data.count - headerEnd.upperBound, 4 * 16 + 2 * 13)
+    }
+
+    func testThreeMFRoundTripKeepsColors() throws {
+        let mesh = try ColoredMeshExporter.indexedMesh(triangles: square, colors: VertexColors(perTriangle: [red, blue]), materialColor: gray)
+        let model = try ThreeMFParser.parse(data: ColoredMeshExporter.threeMFData(mesh, name: "a & b"))
+
+        XCTAssertEqual(model.triangleCount, 2)
+        XCTAssertEqual(model.triangles[0].color, red)
+        XCTAssertEqual(model.triangles[1].color, blue)
+    }
+
+    func testFormatFollowsExtension() throws {
+        XCTAssertEqual(ColoredMeshExporter.Format(url: URL(fileURLWithPath: "/tmp/part.PLY")), .ply)
+        XCTAssertEqual(ColoredMeshExporter.Format(url: URL(fileURLWithPath: "/tmp/part.3mf")), .threeMF)
+        XCTAssertThrowsError(try ColoredMeshExporter.export(
+            triangles: square, colors: VertexColors(perTriangle: [red, red]), materialColor: gray, name: "part",
+            to: URL(fileURLWithPath: "/tmp/part.obj")
+        ))
+        XCTAssertThrowsError(try DraftCommand.parse(["part.stl", "--colors", "part.obj"]))
+        XCTAssertEqual(ColoredMeshExporter.hex(SIMD4<UInt8>(255, 128, 0, 255)), "#FF8000")
+    }
+}
@@ -0,0 +1,36 @@
+import XCTest
+@testable import GoSTL
+
+final class CommandLineTests: XCTestCase {
+    // MARK: - Dispatch Tests
+
+    func testSubcommandsAndCompletionCallbacksAreHeadless() {
+        XCTAssertTrue(GoSTLCommand.handles(["gostl", "open", "model.stl"]))
+        XCTAssertTrue(GoSTLCommand.handles(["gostl", "completion", "zsh"]))
+        XCTAssertTrue(GoSTLCommand.handles(["gostl", "---completion", "analyze", "--", "--select"]))
+        XCTAssertTrue(GoSTLCommand.handles(["gostl", "--help"]))
+        XCTAssertFalse(GoSTLCommand.handles(["gostl", "-NSDocumentRevisionsDebugMode", "YES"]))
+    }
+
+    // MARK: - Completion Tests
+
+    func testCompletionScriptListsSubcommands() {
+        let script = GoSTLCommand.completionScript(for: .zsh)
+
+        for subcommand in ["open", "analyze", "assert", "compare", "explain", "script", "plugins", "completion"] {
+            XCTAssertTrue(script.contains(subcommand), "missing \(subcommand)")
+        }
+        XCTAssertTrue(script.contains("dimensions.z"))
+    }
+
+    func testAnalysisFieldKeys() {
+        XCTAssertTrue(AnalysisRecord.fieldKeys.contains("volume"))
+        XCTAssertTrue(AnalysisRecord.fieldKeys.contains("weight.pla_15"))
+    }
+
+    // MARK: - Open Tests
+
+    func testOpenFallsBackToTextOverSSH() {
+        XCTAssertEqual(OpenCommand.preferredViewer(environment: ["SSH_CONNECTION": "10.0.0.1 50000 10.0.0.2 22"]), .text)
+    }
+}
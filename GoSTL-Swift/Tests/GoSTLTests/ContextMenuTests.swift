@@ -0,0 +1,110 @@
+import XCTest
+@testable import GoSTL
+
+final class ContextMenuTests: XCTestCase {
+    private func point(_ x: Double, _ y: Double, _ z: Double) -> MeasurementPoint {
+        MeasurementPoint(position: Vector3(x, y, z), normal: Vector3(0, 0, 1))
+    }
+
+    private func system(measurementCount: Int) -> MeasurementSystem {
+        let system = MeasurementSystem()
+        for index in 0..<measurementCount {
+            let x = Double(index) * 10
+            system.measurements.append(Measurement(type: .distance, points: [point(x, 0, 0), point(x, 5, 0)], value: 5))
+        }
+        return system
+    }
+
+    // MARK: - Action Tests
+
+    func testMeasurementActions() {
+        let system = system(measurementCount: 2)
+        let groups = ContextMenu.actions(for: .measurement(1), measurementSystem: system)
+
+        XCTAssertEqual(groups.first, [.copyValue(1), .editNote(1), .toggleSelection(1)])
+        XCTAssertEqual(groups.last, [.deleteMeasurement(1)])
+        XCTAssertTrue(groups.joined().contains(.setOrbitTarget(system.measurements[1].labelPosition)))
+    }
+
+    func testMeasurementActionsForMissingMeasurementAreEmpty() {
+        XCTAssertTrue(ContextMenu.actions(for: .measurement(3), measurementSystem: system(measurementCount: 1)).isEmpty)
+    }
+
+    func testPointActionsOfferStartingMeasurementsAndDeletingTheOwner() {
+        let target = point(0, 5, 0)
+        let actions = Array(ContextMenu.actions(for: .point(target, measurement: 0), measurementSystem: system(measurementCount: 1)).joined())
+
+        XCTAssertEqual(actions.first, .copyCoordinates(target.position))
+        XCTAssertTrue(actions.contains(.startMeasurement(.distance, at: target)))
+        XCTAssertTrue(actions.contains(.startMeasurement(.radius, at: target)))
+        XCTAssertEqual(actions.last, .deleteMeasurement(0))
+    }
+
+    func testPointOfMeasurementInProgressCannotBeDeleted() {
+        let actions = ContextMenu.actions(for: .point(point(1, 2, 3), measurement: nil), measurementSystem: MeasurementSystem())
+
+        XCTAssertFalse(actions.joined().contains { if case .deleteMeasurement = $0 { return true } else { return false } })
+    }
+
+    func testSurfaceActionsStartEveryMeasurementType() {
+        let target = point(1, 2, 3)
+        let actions = Array(ContextMenu.actions(for: .surface(target), measurementSystem: MeasurementSystem()).joined())
+
+        XCTAssertEqual(actions, [
+            .copyCoordinates(target.position),
+            .addAnnotation(at: target),
+            .startMeasurement(.distance, at: target),
+            .startMeasurement(.angle, at: target),
+            .startMeasurement(.radius, at: target),
+            .setOrbitTarget(target.position)
+        ])
+    }
+
+    func testAnnotationActions() {
+        let id = UUID()
+        let position = Vector3(4, 5, 6)
+        let groups = ContextMenu.actions(for: .annotation(id, position: position), measurementSystem: MeasurementSystem())
+
+        XCTAssertEqual(groups.first, [.editAnnotation(id), .copyCoordinates(position)])
+        XCTAssertTrue(groups.joined().contains(.setOrbitTarget(position)))
+        XCTAssertEqual(groups.last, [.deleteAnnotation(id)])
+    }
+
+    func testConstraintActionsWhileMeasuringDistance() {
+        let system = MeasurementSystem()
+        system.startMeasurement(type: .distance)
+        _ = system.addPoint(point(0, 0, 0))
+
+        let surface = point(4, 0, 0)
+        let surfaceActions = Array(ContextMenu.actions(for: .surface(surface), measurementSystem: system).joined())
+        XCTAssertTrue(surfaceActions.contains(.constrainToAxis(0)))
+        XCTAssertTrue(surfaceActions.contains(.constrainToAxis(2)))
+        XCTAssertTrue(surfaceActions.contains(.constrainAlong(surface.normal)))
+        XCTAssertFalse(surfaceActions.contains(.startMeasurement(.distance, at: surface)))
+
+        let target = point(3, 3, 3)
+        let pointActions = Array(ContextMenu.actions(for: .point(target, measurement: nil), measurementSystem: system).joined())
+        XCTAssertTrue(pointActions.contains(.constrainToward(target.position)))
+    }
+
+    func testNoConstraintsBeforeTheFirstPoint() {
+        let system = MeasurementSystem()
+        system.startMeasurement(type: .distance)
+
+        let actions = Array(ContextMenu.actions(for: .surface(point(0, 0, 0)), measurementSystem: system).joined())
+        XCTAssertFalse(actions.contains(.constrainToAxis(0)))
+    }
+
+    // MARK: - Removal Tests
+
+    func testRemovingMeasurementKeepsSelectionOfOthers() {
+        let system = system(measurementCount: 3)
+        system.selectedMeasurements = [0, 1, 2]
+
+        system.removeMeasurement(at: 1)
+
+        XCTAssertEqual(system.measurements.count, 2)
+        XCTAssertEqual(system.selectedMeasurements, [0, 1])
+        XCTAssertEqual(system.measurements[1].points[0].position, Vector3(20, 0, 0))
+    }
+}
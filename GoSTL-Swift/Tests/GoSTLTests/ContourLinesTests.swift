@@ -0,0 +1,86 @@
+import XCTest
+@testable import GoSTL
+
+final class ContourLinesTests: XCTestCase {
+
+    /// Square pyramid: 10 x 10 base at z = 0, apex at z = 5
+    private func pyramid() -> [Triangle] {
+        let a = Vector3(0, 0, 0), b = Vector3(10, 0, 0), c = Vector3(10, 10, 0), d = Vector3(0, 10, 0)
+        let apex = Vector3(5, 5, 5)
+        return [
+            Triangle(v1: a, v2: c, v3: b), Triangle(v1: a, v2: d, v3: c),
+            Triangle(v1: a, v2: b, v3: apex), Triangle(v1: b, v2: c, v3: apex),
+            Triangle(v1: c, v2: d, v3: apex), Triangle(v1: d, v2: a, v3: apex)
+        ]
+    }
+
+    func testLevelsAtIntervalMultiples() throws {
+        let result = try ContourLines.compute(pyramid(), interval: 1)
+
+        // Levels 1...4 cut all four sides; the base (level 0) and the apex (level 5) are single points or edges
+        let levels = Set(result.segments.map(\.level))
+        XCTAssertEqual(levels, [1, 2, 3, 4])
+        XCTAssertEqual(result.segments.count, 16)
+        XCTAssertFalse(result.isTruncated)
+        for segment in result.segments {
+            XCTAssertEqual(segment.start.z, Double(segment.level), accuracy: 1e-9)
+            XCTAssertEqual(segment.end.z, Double(segment.level), accuracy: 1e-9)
+        }
+    }
+
+    func testContourLength() throws {
+        let result = try ContourLines.compute(pyramid(), interval: 1)
+
+        // At z = 1 the pyramid's cross-section is an 8 x 8 square
+        let length = result.segments.filter { $0.level == 1 }.reduce(0) { $0 + $1.start.distance(to: $1.end) }
+        XCTAssertEqual(length, 32, accuracy: 1e-9)
+    }
+
+    func testLevelsFollowFileCoordinates() throws {
+        // Rendered 0.5 mm lower than in the file: file levels 1...4 are at render z 0.5...3.5
+        let result = try ContourLines.compute(pyramid(), interval: 1, offset: 0.5)
+
+        XCTAssertEqual(result.levels, 1...5)
+        let first = try XCTUnwrap(result.segments.first { $0.level == 1 })
+        XCTAssertEqual(first.start.z, 0.5, accuracy: 1e-9)
+    }
+
+    func testNoContoursWithoutInterval() throws {
+        let result = try ContourLines.compute(pyramid(), interval: 0)
+
+        XCTAssertTrue(result.segments.isEmpty)
+        XCTAssertNil(result.levels)
+    }
+
+    func testAutoInterval() {
+        XCTAssertEqual(ContourLines.autoInterval(for: 48), 2)
+        XCTAssertEqual(ContourLines.autoInterval(for: 10), 0.5)
+        XCTAssertEqual(ContourLines.autoInterval(for: 250), 10)
+        XCTAssertEqual(ContourLines.autoInterval(for: 0), 1)
+    }
+
+    func testMajorLevels() {
+        XCTAssertTrue(ContourLines.isMajor(0))
+        XCTAssertTrue(ContourLines.isMajor(10))
+        XCTAssertTrue(ContourLines.isMajor(-5))
+        XCTAssertFalse(ContourLines.isMajor(3))
+    }
+
+    func testColorRunsFromBlueToRed() {
+        let low = ContourLines.color(for: 1, in: 1...9)
+        let high = ContourLines.color(for: 9, in: 1...9)
+
+        XCTAssertGreaterThan(low.z, low.x)
+        XCTAssertGreaterThan(high.x, high.z)
+    }
+
+    func testSegmentOfTriangle() throws {
+        let triangle = Triangle(v1: Vector3(0, 0, 0), v2: Vector3(2, 0, 2), v3: Vector3(0, 2, 2))
+
+        let segment = try XCTUnwrap(CrossSection.segment(triangle, axis: 2, value: 1))
+
+        XCTAssertEqual(segment.0.z, 1, accuracy: 1e-12)
+        XCTAssertEqual(segment.1.z, 1, accuracy: 1e-12)
+        XCTAssertNil(CrossSection.segment(triangle, axis: 2, value: 3))
+    }
+}
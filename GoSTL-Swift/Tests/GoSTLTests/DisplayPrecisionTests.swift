@@ -0,0 +1,180 @@
+import XCTest
+@testable import GoSTL
+
+final class DisplayPrecisionTests: XCTestCase {
+    private func point(_ x: Double, _ y: Double, _ z: Double) -> MeasurementPoint {
+        MeasurementPoint(position: Vector3(x, y, z), normal: Vector3(0, 0, 1))
+    }
+
+    private func distance(_ value: Double) -> Measurement {
+        Measurement(type: .distance, points: [point(0, 0, 0), point(value, 0, 0)], value: value)
+    }
+
+    // MARK: - Formatting Tests
+
+    func testFormatting() {
+        let precision = DisplayPrecision(lengthDecimals: 3, angleDecimals: 0)
+
+        XCTAssertEqual(precision.length(12.3456), "12.346")
+        XCTAssertEqual(precision.angle(44.6), "45°")
+        XCTAssertEqual(precision.point(Vector3(1, 2.5, -3)), "(1.000, 2.500, -3.000)")
+    }
+
+    func testDecimalsAreClamped() {
+        let precision = DisplayPrecision(lengthDecimals: 12, angleDecimals: -1)
+
+        XCTAssertEqual(precision.lengthDecimals, 6)
+        XCTAssertEqual(precision.angleDecimals, 0)
+    }
+
+    func testOverride() {
+        XCTAssertEqual(DisplayPrecision.standard.overridden(by: nil), .standard)
+        XCTAssertEqual(DisplayPrecision.standard.overridden(by: 4), DisplayPrecision(lengthDecimals: 4, angleDecimals: 4))
+    }
+
+    // MARK: - Unit Tests
+
+    func testRescaledUnits() {
+        let micrometers = DisplayPrecision(lengthDecimals: 1, angleDecimals: 1, unit: .micrometers)
+        let meters = DisplayPrecision(lengthDecimals: 3, angleDecimals: 1, unit: .meters)
+
+        XCTAssertEqual(micrometers.lengthWithUnit(0.0806), "80.6 µm")
+        XCTAssertEqual(meters.lengthWithUnit(12_345), "12.345 m")
+        XCTAssertEqual(meters.point(Vector3(1000, 0, -2500)), "(1.000, 0.000, -2.500)")
+        XCTAssertEqual(meters.direction(Vector3(0, 0, 1)), "(0.000, 0.000, 1.000)")
+    }
+
+    func testLabelsShowOnlyUnitsOtherThanMillimeters() {
+        XCTAssertEqual(DisplayPrecision.standard.labelLength(12.5), "12.50")
+        XCTAssertEqual(DisplayPrecision(lengthDecimals: 2, angleDecimals: 1, unit: .meters).labelLength(12_500), "12.50 m")
+    }
+
+    func testEngineeringNotation() {
+        XCTAssertEqual(DisplayPrecision.engineering(12_346, decimals: 2), "12.35e3")
+        XCTAssertEqual(DisplayPrecision.engineering(0.000_42, decimals: 1), "420.0e-6")
+        XCTAssertEqual(DisplayPrecision.engineering(-1.5, decimals: 2), "-1.50e0")
+        XCTAssertEqual(DisplayPrecision.engineering(999.999, decimals: 2), "1.00e3")
+        XCTAssertEqual(DisplayPrecision.engineering(1e-15, decimals: 2), "0.00e0")
+    }
+
+    func testAutomaticUnitFollowsModelSize() {
+        XCTAssertEqual(LengthScale.automatic.resolved(forExtent: nil).unit, .millimeters)
+        XCTAssertEqual(LengthScale.automatic.resolved(forExtent: 0.08).unit, .micrometers)
+        XCTAssertEqual(LengthScale.automatic.resolved(forExtent: 250).unit, .millimeters)
+        XCTAssertEqual(LengthScale.automatic.resolved(forExtent: 8000).unit, .millimeters)
+        XCTAssertEqual(LengthScale.automatic.resolved(forExtent: 25_000).unit, .meters)
+        XCTAssertFalse(LengthScale.automatic.resolved(forExtent: 25_000).engineeringNotation)
+        XCTAssertTrue(LengthScale.automatic.resolved(forExtent: 0.0002).engineeringNotation)
+        XCTAssertTrue(LengthScale.automatic.resolved(forExtent: 5e7).engineeringNotation)
+    }
+
+    func testFixedScaleIgnoresModelSize() {
+        XCTAssertEqual(LengthScale.millimeters.resolved(forExtent: 0.08).unit, .millimeters)
+        XCTAssertTrue(LengthScale.engineering.resolved(forExtent: 100).engineeringNotation)
+    }
+
+    func testMeasurementInMicrometers() {
+        let precision = DisplayPrecision(lengthDecimals: 1, angleDecimals: 1, unit: .micrometers)
+        let measurement = distance(0.0427)
+
+        XCTAssertEqual(measurement.formattedValue(showDiameter: false, precision: precision), "42.7 µm")
+        XCTAssertEqual(measurement.spokenDescription(showDiameter: false, precision: precision), "Distance 42.7 micrometers")
+    }
+
+    // MARK: - Measurement Tests
+
+    func testMeasurementUsesDisplayPrecision() {
+        let measurement = distance(25.04567)
+
+        XCTAssertEqual(measurement.formattedValue, "25.05")
+        XCTAssertEqual(measurement.formattedValue(showDiameter: false, precision: DisplayPrecision(lengthDecimals: 0, angleDecimals: 0)), "25")
+        XCTAssertEqual(measurement.spokenDescription(showDiameter: false, precision: .report), "Distance 25.046 millimeters")
+    }
+
+    func testMeasurementDecimalsOverrideTheSetting() {
+        var measurement = distance(25.04567)
+        measurement.decimals = 4
+
+        XCTAssertEqual(measurement.formattedValue(showDiameter: false, precision: .standard), "25.0457")
+    }
+
+    func testAngleUsesAngleDecimals() {
+        let measurement = Measurement(type: .angle, points: [point(1, 0, 0), point(0, 0, 0), point(0, 1, 0)], value: 90.123)
+
+        XCTAssertEqual(measurement.formattedValue(showDiameter: false, precision: DisplayPrecision(lengthDecimals: 4, angleDecimals: 2)), "90.12°")
+    }
+
+    func testDecimalsAreSaved() throws {
+        var measurement = distance(5)
+        measurement.decimals = 3
+
+        let decoded = try JSONDecoder().decode(Measurement.self, from: JSONEncoder().encode(measurement))
+        XCTAssertEqual(decoded.decimals, 3)
+
+        let plain = try JSONDecoder().decode(Measurement.self, from: JSONEncoder().encode(distance(5)))
+        XCTAssertNil(plain.decimals)
+    }
+
+    // MARK: - Override Tests
+
+    func testAdjustDecimals() {
+        let system = MeasurementSystem()
+        system.measurements = [distance(5)]
+
+        system.adjustDecimals(by: 1, forMeasurement: 0, base: .standard)
+        XCTAssertEqual(system.measurements[0].decimals, 3)
+
+        system.adjustDecimals(by: -1, forMeasurement: 0, base: .standard)
+        system.adjustDecimals(by: -1, forMeasurement: 0, base: .standard)
+        XCTAssertEqual(system.measurements[0].decimals, 1)
+
+        system.resetDecimals(forMeasurement: 0)
+        XCTAssertNil(system.measurements[0].decimals)
+    }
+
+    func testAdjustDecimalsStaysInRange() {
+        let system = MeasurementSystem()
+        system.measurements = [distance(5)]
+
+        for _ in 0..<10 {
+            system.adjustDecimals(by: -1, forMeasurement: 0, base: .standard)
+        }
+
+        XCTAssertEqual(system.measurements[0].decimals, 0)
+    }
+
+    func testContextMenuOffersResetOnlyWithOverride() {
+        let system = MeasurementSystem()
+        system.measurements = [distance(5)]
+
+        XCTAssertTrue(ContextMenu.actions(for: .measurement(0), measurementSystem: system).contains([.moreDecimals(0), .fewerDecimals(0)]))
+
+        system.measurements[0].decimals = 4
+        XCTAssertTrue(ContextMenu.actions(for: .measurement(0), measurementSystem: system).contains([.moreDecimals(0), .fewerDecimals(0), .resetDecimals(0)]))
+    }
+
+    // MARK: - Report Tests
+
+    func testReportPrecision() {
+        let annotations = [Annotation(text: "burr", position: Vector3(1, 2, 3))]
+
+        let standard = InspectionReport.generate(fileName: "part.stl", referenceGeometry: ReferenceGeometrySystem(), annotations: annotations)
+        let coarse = InspectionReport.generate(fileName: "part.stl", referenceGeometry: ReferenceGeometrySystem(), annotations: annotations, precision: DisplayPrecision(lengthDecimals: 1, angleDecimals: 1))
+
+        XCTAssertTrue(standard.contains("\"burr\" at (1.000, 2.000, 3.000)"))
+        XCTAssertTrue(coarse.contains("\"burr\" at (1.0, 2.0, 3.0)"))
+        XCTAssertFalse(standard.contains("Lengths:"))
+    }
+
+    func testReportInMeters() {
+        let report = InspectionReport.generate(
+            fileName: "hall.stl",
+            referenceGeometry: ReferenceGeometrySystem(),
+            annotations: [Annotation(text: "column", position: Vector3(12_000, 3_500, 0))],
+            precision: DisplayPrecision(lengthDecimals: 2, angleDecimals: 1, unit: .meters)
+        )
+
+        XCTAssertTrue(report.contains("Lengths: m"))
+        XCTAssertTrue(report.contains("\"column\" at (12.00, 3.50, 0.00)"))
+    }
+}
@@ -0,0 +1,123 @@
+import XCTest
+@testable import GoSTL
+
+final class DraftAnalysisTests: XCTestCase {
+
+    /// 20 x 20 mm base, 10 mm high, side walls leaning inwards by `degrees` towards the top
+    private func taperedBox(degrees: Double) -> [Triangle] {
+        let inset = 10 * tan(degrees * .pi / 180)
+        let a = Vector3(0, 0, 0), b = Vector3(20, 0, 0), c = Vector3(20, 20, 0), d = Vector3(0, 20, 0)
+        let e = Vector3(inset, inset, 10), f = Vector3(20 - inset, inset, 10)
+        let g = Vector3(20 - inset, 20 - inset, 10), h = Vector3(inset, 20 - inset, 10)
+        return [
+            Triangle(v1: a, v2: c, v3: b), Triangle(v1: a, v2: d, v3: c),
+            Triangle(v1: e, v2: f, v3: g), Triangle(v1: e, v2: g, v3: h),
+            Triangle(v1: a, v2: b, v3: f), Triangle(v1: a, v2: f, v3: e),
+            Triangle(v1: b, v2: c, v3: g), Triangle(v1: b, v2: g, v3: f),
+            Triangle(v1: c, v2: d, v3: h), Triangle(v1: c, v2: h, v3: g),
+            Triangle(v1: d, v2: a, v3: e), Triangle(v1: d, v2: e, v3: h)
+        ]
+    }
+
+    // MARK: - Draft Angle Tests
+
+    func testDraftAngle() {
+        XCTAssertEqual(DraftAnalysis.draftAngle(normal: .unitZ, pull: .unitZ), 90, accuracy: 1e-9)
+        XCTAssertEqual(DraftAnalysis.draftAngle(normal: .unitX, pull: .unitZ), 0, accuracy: 1e-9)
+        XCTAssertEqual(DraftAnalysis.draftAngle(normal: .unitZ, pull: -Vector3.unitZ), -90, accuracy: 1e-9)
+    }
+
+    func testCategories() {
+        XCTAssertEqual(DraftAnalysis.category(angle: 3, minimumAngle: 1), .sufficient)
+        XCTAssertEqual(DraftAnalysis.category(angle: 1, minimumAngle: 1), .sufficient)
+        XCTAssertEqual(DraftAnalysis.category(angle: 0.5, minimumAngle: 1), .insufficient)
+        XCTAssertEqual(DraftAnalysis.category(angle: -0.5, minimumAngle: 1), .insufficient)
+        XCTAssertEqual(DraftAnalysis.category(angle: -45, minimumAngle: 1), .opposite)
+    }
+
+    // MARK: - Analysis Tests
+
+    func testStraightBox() throws {
+        let result = try DraftAnalysis.analyze(TestModels.box(from: .zero, to: Vector3(10, 10, 10)), pullDirection: .z)
+
+        // Four vertical walls have no draft, the bottom faces away from the pull
+        XCTAssertEqual(result.insufficientTriangles, [4, 5, 6, 7, 8, 9, 10, 11])
+        XCTAssertEqual(result.insufficientArea, 400, accuracy: 1e-9)
+        XCTAssertEqual(result.oppositeTriangles, [0, 1])
+        XCTAssertEqual(result.oppositeArea, 100, accuracy: 1e-9)
+        XCTAssertEqual(result.totalArea, 600, accuracy: 1e-9)
+    }
+
+    func testPullDownwards() throws {
+        let result = try DraftAnalysis.analyze(TestModels.box(from: .zero, to: Vector3(10, 10, 10)), pullDirection: .negativeZ)
+
+        XCTAssertEqual(result.oppositeTriangles, [2, 3])
+        XCTAssertEqual(result.insufficientTriangles.count, 8)
+    }
+
+    func testTaperedWalls() throws {
+        let tapered = taperedBox(degrees: 3)
+
+        let lenient = try DraftAnalysis.analyze(tapered, pullDirection: .z, minimumAngle: 2)
+        XCTAssertTrue(lenient.insufficientTriangles.isEmpty)
+        for index in 4..<12 {
+            XCTAssertEqual(try XCTUnwrap(lenient.angles[index]), 3, accuracy: 1e-9)
+        }
+
+        let strict = try DraftAnalysis.analyze(tapered, pullDirection: .z, minimumAngle: 5)
+        XCTAssertEqual(strict.insufficientTriangles, [4, 5, 6, 7, 8, 9, 10, 11])
+    }
+
+    func testSideways() throws {
+        let result = try DraftAnalysis.analyze(TestModels.box(from: .zero, to: Vector3(10, 10, 10)), pullDirection: .x)
+
+        // Only the +X wall faces the pull, the -X wall faces away
+        XCTAssertEqual(result.oppositeTriangles, [10, 11])
+        XCTAssertEqual(result.insufficientTriangles, [0, 1, 2, 3, 4, 5, 8, 9])
+    }
+
+    func testDegenerateTriangleIsSkipped() throws {
+        let sliver = Triangle(v1: .zero, v2: Vector3(1, 0, 0), v3: Vector3(2, 0, 0))
+
+        let result = try DraftAnalysis.analyze([sliver], pullDirection: .z)
+
+        XCTAssertNil(result.angles[0])
+        XCTAssertTrue(result.insufficientTriangles.isEmpty)
+        XCTAssertEqual(result.totalArea, 0)
+    }
+
+    // MARK: - Coloring Tests
+
+    func testColors() {
+        XCTAssertEqual(DraftAnalysis.color(forAngle: 45, minimumAngle: 1), DraftAnalysis.sufficientColor)
+        XCTAssertEqual(DraftAnalysis.color(forAngle: -45, minimumAngle: 1), DraftAnalysis.oppositeColor)
+        XCTAssertEqual(DraftAnalysis.color(forAngle: 0, minimumAngle: 1), DraftAnalysis.verticalColor)
+
+        // Closer to the minimum draft turns towards yellow
+        let nearMinimum = DraftAnalysis.color(forAngle: 0.9, minimumAngle: 1)
+        XCTAssertGreaterThan(nearMinimum.g, DraftAnalysis.verticalColor.g)
+    }
+
+    func testVertexColors() throws {
+        let model = STLModel(triangles: TestModels.box(from: .zero, to: Vector3(10, 10, 10)), name: "box")
+        let result = try DraftAnalysis.analyze(model.triangles, pullDirection: .z)
+
+        let colors = DraftAnalysis.colors(for: result)
+
+        func rgba(_ color: TriangleColor) -> SIMD4<Float> {
+            SIMD4(color.r, color.g, color.b, 1)
+        }
+        XCTAssertEqual(colors.triangleCount, 12)
+        XCTAssertEqual(colors.corners(ofTriangle: 2).0, rgba(DraftAnalysis.sufficientColor))
+        XCTAssertEqual(colors.corners(ofTriangle: 0).1, rgba(DraftAnalysis.oppositeColor))
+        XCTAssertEqual(colors.corners(ofTriangle: 4).2, rgba(DraftAnalysis.verticalColor))
+        XCTAssertNil(model.triangles[0].color)
+    }
+
+    func testPullDirections() {
+        XCTAssertEqual(DraftAnalysis.PullDirection.negativeY.vector, -Vector3.unitY)
+        XCTAssertEqual(DraftAnalysis.PullDirection.x.displayName, "+X")
+        XCTAssertEqual(DraftAnalysis.PullDirection.negativeZ.displayName, "−Z")
+        XCTAssertEqual(DraftAnalysis.PullDirection(rawValue: "-z"), .negativeZ)
+    }
+}
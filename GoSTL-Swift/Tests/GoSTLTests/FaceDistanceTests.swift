@@ -0,0 +1,72 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class FaceDistanceTests: XCTestCase {
+
+    /// 40 x 30 mm plate, 2.5 mm thick
+    private let plate = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(40, 30, 2.5))
+
+    /// Two 10 mm blocks on a base, leaving a 4 mm slot between them
+    private let slotted = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(24, 10, 2))
+        + ModelGenerator.box(from: Vector3(0, 0, 2), to: Vector3(10, 10, 12))
+        + ModelGenerator.box(from: Vector3(14, 0, 2), to: Vector3(24, 10, 12))
+
+    private func measure(_ triangles: [Triangle], ray: Ray, side: FaceDistance.Side = .nearest) -> FaceDistance.Result? {
+        FaceDistance.measure(ray: ray, triangles: triangles, adjacency: TriangleAdjacency(triangles: triangles), side: side)
+    }
+
+    func testPlateThickness() throws {
+        let result = try XCTUnwrap(measure(plate, ray: Ray(origin: SIMD3(10, 10, 50), direction: SIMD3(0, 0, -1))))
+
+        XCTAssertEqual(result.distance, 2.5, accuracy: 1e-9)
+        XCTAssertTrue(result.isThickness)
+        XCTAssertEqual(result.entry.z, 2.5, accuracy: 1e-9)
+        XCTAssertEqual(result.exit.z, 0, accuracy: 1e-9)
+        XCTAssertEqual(result.pickedArea, 1200, accuracy: 1e-9)
+        XCTAssertEqual(result.parallelism, 0, accuracy: 1e-4)
+    }
+
+    func testDistanceIgnoresViewAngle() throws {
+        // Oblique click onto the top face still measures straight across the plate
+        let ray = Ray(origin: SIMD3(0, 0, 20), direction: simd_normalize(SIMD3(1, 1, -1)))
+        let result = try XCTUnwrap(measure(plate, ray: ray))
+
+        XCTAssertEqual(result.distance, 2.5, accuracy: 1e-9)
+        XCTAssertEqual(result.exit.x, result.entry.x, accuracy: 1e-9)
+    }
+
+    func testSlotWidth() throws {
+        // Looking at the inner wall of the left block from inside the slot
+        let ray = Ray(origin: SIMD3(13, -21, 7), direction: simd_normalize(SIMD3(-3, 24, 0)))
+
+        let gap = try XCTUnwrap(measure(slotted, ray: ray, side: .gap))
+        XCTAssertFalse(gap.isThickness)
+        XCTAssertEqual(gap.distance, 4, accuracy: 1e-9)
+
+        let material = try XCTUnwrap(measure(slotted, ray: ray, side: .material))
+        XCTAssertTrue(material.isThickness)
+        XCTAssertEqual(material.distance, 10, accuracy: 1e-9)
+
+        // The slot is closer than the far side of the block
+        XCTAssertEqual(try XCTUnwrap(measure(slotted, ray: ray)).distance, 4, accuracy: 1e-9)
+    }
+
+    func testNoParallelFace() {
+        // The top face alone: nothing opposite it
+        let top = plate.filter { $0.normal.z > 0.5 }
+        XCTAssertNil(measure(top, ray: Ray(origin: SIMD3(10, 10, 50), direction: SIMD3(0, 0, -1))))
+    }
+
+    func testMeasurementIsKept() {
+        let result = FaceDistance.Result(
+            entry: Vector3(0, 0, 2), exit: Vector3(0, 0, 0), normal: Vector3(0, 0, 1), isThickness: true,
+            distance: 2, parallelism: 0, pickedArea: 1, oppositeArea: 1
+        )
+        let measurement = FaceDistance.measurement(for: result)
+
+        XCTAssertEqual(measurement.type, .distance)
+        XCTAssertEqual(measurement.value, 2)
+        XCTAssertEqual(measurement.note, "Thickness")
+    }
+}
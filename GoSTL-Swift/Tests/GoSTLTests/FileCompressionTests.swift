@@ -0,0 +1,96 @@
+import XCTest
+@testable import GoSTL
+
+final class FileCompressionTests: XCTestCase {
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-compression-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    private let model = STLModel(triangles: [
+        Triangle(v1: Vector3(0, 0, 0), v2: Vector3(10, 0, 0), v3: Vector3(0, 10, 0)),
+        Triangle(v1: Vector3(0, 0, 0), v2: Vector3(0, 10, 0), v3: Vector3(0, 0, 10))
+    ], name: "scan")
+
+    // MARK: - File Names
+
+    func testModelExtensionAndNameIgnoreCompression() {
+        let url = URL(fileURLWithPath: "/scans/part.STL.gz")
+
+        XCTAssertEqual(FileCompression(url: url), .gzip)
+        XCTAssertEqual(ModelFileLoader.modelExtension(of: url), "stl")
+        XCTAssertEqual(ModelFileLoader.modelName(of: url), "part")
+        XCTAssertEqual(ModelFileLoader.modelExtension(of: URL(fileURLWithPath: "/scans/part.stl.zst")), "stl")
+        XCTAssertEqual(ModelFileLoader.modelExtension(of: URL(fileURLWithPath: "/scans/part.stl")), "stl")
+        XCTAssertEqual(ModelFileLoader.modelExtension(of: URL(fileURLWithPath: "/scans/backup.tar.gz")), "tar")
+    }
+
+    // MARK: - gzip
+
+    func testGzipRoundTrip() throws {
+        let data = Data(String(repeating: "facet normal 0 0 1\n", count: 500).utf8)
+        let compressed = try FileCompression.gzip(data)
+
+        XCTAssertEqual(Array(compressed.prefix(3)), [0x1F, 0x8B, 0x08])
+        XCTAssertLessThan(compressed.count, data.count)
+        XCTAssertEqual(try FileCompression.gunzip(compressed), data)
+    }
+
+    func testGunzipSkipsFileNameField() throws {
+        let data = Data("solid named".utf8)
+        var compressed = try FileCompression.gzip(data)
+        // Set FNAME and insert "part.stl\0" after the 10-byte header, as gzip(1) does
+        compressed[3] = 0x08
+        compressed.insert(contentsOf: Array("part.stl".utf8) + [0], at: 10)
+
+        XCTAssertEqual(try FileCompression.gunzip(compressed), data)
+    }
+
+    func testGunzipDetectsCorruption() throws {
+        var compressed = try FileCompression.gzip(Data(String(repeating: "vertex 1 2 3\n", count: 100).utf8))
+        compressed[compressed.count - 5] ^= 0xFF  // Damage the stored CRC
+
+        XCTAssertThrowsError(try FileCompression.gunzip(compressed))
+        XCTAssertThrowsError(try FileCompression.gunzip(Data("solid plain".utf8)))
+    }
+
+    func testGzipSTLWriteAndLoad() throws {
+        let url = directory.appendingPathComponent("scan.stl.gz")
+        try STLExporter.exportBinary(model: model, to: url)
+
+        let raw = try Data(contentsOf: url)
+        XCTAssertEqual(Array(raw.prefix(2)), [0x1F, 0x8B])
+
+        let loaded = try ModelFileLoader.load(url: url)
+        XCTAssertEqual(loaded.name, "scan")
+        XCTAssertEqual(loaded.triangles, model.triangles)
+    }
+
+    func testGzipASCIIExport() throws {
+        let url = directory.appendingPathComponent("scan.stl.gz")
+        try STLExporter.exportASCII(model: model, to: url)
+
+        let text = String(decoding: try FileCompression.read(url), as: UTF8.self)
+        XCTAssertTrue(text.hasPrefix("solid scan"))
+        XCTAssertEqual(try ModelFileLoader.load(url: url).triangleCount, 2)
+    }
+
+    // MARK: - zstd
+
+    func testZstdSTLWriteAndLoad() throws {
+        let url = directory.appendingPathComponent("scan.stl.zst")
+        do {
+            try STLExporter.exportBinary(model: model, to: url)
+        } catch STLExportError.writeFailure(let message) where message == FileCompressionError.zstdNotFound.localizedDescription {
+            throw XCTSkip("zstd is not installed")
+        }
+
+        XCTAssertEqual(try ModelFileLoader.load(url: url).triangles, model.triangles)
+    }
+}
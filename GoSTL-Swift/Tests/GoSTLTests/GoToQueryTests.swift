@@ -0,0 +1,48 @@
+import XCTest
+@testable import GoSTL
+
+final class GoToQueryTests: XCTestCase {
+
+    // MARK: - Parsing Tests
+
+    func testParseCoordinates() {
+        XCTAssertEqual(GoToQuery.parse("10, 20, 5"), .coordinates(Vector3(10, 20, 5)))
+        XCTAssertEqual(GoToQuery.parse("10 20 5"), .coordinates(Vector3(10, 20, 5)))
+        XCTAssertEqual(GoToQuery.parse("-1.5;2;3e1"), .coordinates(Vector3(-1.5, 2, 30)))
+    }
+
+    func testParseFeatureIDs() {
+        XCTAssertEqual(GoToQuery.parse("v42"), .vertex(42))
+        XCTAssertEqual(GoToQuery.parse("T 7"), .triangle(7))
+    }
+
+    func testParseSearch() {
+        XCTAssertEqual(GoToQuery.parse("center"), .search("center"))
+        XCTAssertEqual(GoToQuery.parse("vertex"), .search("vertex"))
+        XCTAssertNil(GoToQuery.parse("   "))
+    }
+
+    // MARK: - Resolve Tests
+
+    func testResolveVertexAndTriangle() {
+        let triangle = Triangle(v1: Vector3(0, 0, 0), v2: Vector3(3, 0, 0), v3: Vector3(0, 3, 0))
+        let model = STLModel(triangles: [triangle])
+
+        XCTAssertEqual(GoToQuery.vertex(1).resolve(model: model, anchors: [])?.position, Vector3(3, 0, 0))
+        XCTAssertEqual(GoToQuery.triangle(0).resolve(model: model, anchors: [])?.position, Vector3(1, 1, 0))
+        XCTAssertNil(GoToQuery.vertex(3).resolve(model: model, anchors: []))
+        XCTAssertNil(GoToQuery.triangle(1).resolve(model: nil, anchors: []))
+    }
+
+    func testSearchPrefersPrefixMatches() {
+        let anchors = [
+            GoToAnchor(name: "Model center", position: Vector3(1, 1, 1)),
+            GoToAnchor(name: "Center hole", position: Vector3(2, 2, 2))
+        ]
+
+        let matches = GoToQuery.search("cent").matchingAnchors(in: anchors)
+        XCTAssertEqual(matches.map(\.name), ["Center hole", "Model center"])
+        XCTAssertEqual(GoToQuery.search("model").resolve(model: nil, anchors: anchors)?.position, Vector3(1, 1, 1))
+        XCTAssertNil(GoToQuery.search("missing").resolve(model: nil, anchors: anchors))
+    }
+}
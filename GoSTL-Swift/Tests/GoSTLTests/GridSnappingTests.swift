@@ -0,0 +1,49 @@
+import XCTest
+@testable import GoSTL
+
+final class GridSnappingTests: XCTestCase {
+    /// 40 × 40 × 7 mm block on the grid floor
+    private let block = STLModel(triangles: ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(40, 40, 7)))
+
+    private func snappingSystem(offset: Vector3 = .zero) -> MeasurementSystem {
+        let system = MeasurementSystem()
+        system.gridSnapEnabled = true
+        system.gridSnapSpacing = 10
+        system.gridSnapOffset = offset
+        system.gridSnapPlane = MeasurementSystem.GridPlane(z: 0, minX: -50, maxX: 50, minY: -50, maxY: 50)
+        return system
+    }
+
+    func testSurfacePointsSnapAcrossTheGridOnly() throws {
+        let system = snappingSystem()
+        let ray = Ray(origin: SIMD3(13, 27, 100), direction: SIMD3(0, 0, -1))
+
+        let point = try XCTUnwrap(system.pickPoint(ray: ray, model: block))
+
+        // X and Y move to the grid, Z stays on the top face
+        XCTAssertEqual(point.position, Vector3(10, 30, 7))
+        XCTAssertTrue(point.isAirPoint)
+    }
+
+    func testSnapsToRoundFileCoordinates() {
+        // A recentered model: render coordinate 0 is file coordinate 123_456
+        let system = snappingSystem(offset: Vector3(123_456, 654_321, 0))
+
+        let snapped = system.snapToGrid(Vector3(1, 1, 0))
+
+        XCTAssertEqual(snapped + system.gridSnapOffset, Vector3(123_460, 654_320, 0))
+    }
+
+    func testOffModelPointsLandOnTheGridPlane() throws {
+        let system = snappingSystem()
+        system.gridSnapPlane?.z = -5
+
+        let point = try XCTUnwrap(system.pickPoint(ray: Ray(origin: SIMD3(-22, -18, 100), direction: SIMD3(0, 0, -1)), model: block))
+        XCTAssertEqual(point.position, Vector3(-20, -20, -5))
+
+        // Beyond the displayed grid, or without one, nothing is picked
+        XCTAssertNil(system.pickPoint(ray: Ray(origin: SIMD3(-80, 0, 100), direction: SIMD3(0, 0, -1)), model: block))
+        system.gridSnapPlane = nil
+        XCTAssertNil(system.pickPoint(ray: Ray(origin: SIMD3(-22, -18, 100), direction: SIMD3(0, 0, -1)), model: block))
+    }
+}
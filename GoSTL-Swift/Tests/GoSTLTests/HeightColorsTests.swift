@@ -0,0 +1,72 @@
+import XCTest
+@testable import GoSTL
+
+final class HeightColorsTests: XCTestCase {
+
+    // MARK: - Gradient Tests
+
+    func testGradientEnds() {
+        XCTAssertEqual(HeightColors.color(at: 0), HeightColors.gradient.first)
+        XCTAssertEqual(HeightColors.color(at: 1), HeightColors.gradient.last)
+        XCTAssertEqual(HeightColors.color(at: 0.5), HeightColors.gradient[2])
+    }
+
+    func testGradientIsClamped() {
+        XCTAssertEqual(HeightColors.color(at: -3), HeightColors.gradient.first)
+        XCTAssertEqual(HeightColors.color(at: 7), HeightColors.gradient.last)
+    }
+
+    func testGradientInterpolatesBetweenStops() {
+        let color = HeightColors.color(at: 0.125)
+        let a = HeightColors.gradient[0], b = HeightColors.gradient[1]
+
+        XCTAssertEqual(color.r, (a.r + b.r) / 2, accuracy: 1e-6)
+        XCTAssertEqual(color.g, (a.g + b.g) / 2, accuracy: 1e-6)
+        XCTAssertEqual(color.b, (a.b + b.b) / 2, accuracy: 1e-6)
+    }
+
+    func testGradientPositionOfFlatRange() {
+        XCTAssertEqual(HeightColors.gradientPosition(of: 5, in: 5...5), 0.5)
+        XCTAssertEqual(HeightColors.gradientPosition(of: 7.5, in: 5...10), 0.5)
+    }
+
+    // MARK: - Range Tests
+
+    func testRangeAlongEachAxis() {
+        let triangles = TestModels.box(from: Vector3(-1, 2, 3), to: Vector3(4, 6, 3.3))
+
+        XCTAssertEqual(HeightColors.range(of: triangles, axis: .x), -1...4)
+        XCTAssertEqual(HeightColors.range(of: triangles, axis: .y), 2...6)
+        XCTAssertEqual(HeightColors.range(of: triangles, axis: .z), 3...3.3)
+        XCTAssertNil(HeightColors.range(of: [], axis: .z))
+    }
+
+    // MARK: - Vertex Color Tests
+
+    private func rgba(_ color: TriangleColor?) -> SIMD4<Float>? {
+        color.map { SIMD4($0.r, $0.g, $0.b, 1) }
+    }
+
+    func testBottomIsBlueAndTopIsRed() throws {
+        let triangles = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 2))
+        let range = try XCTUnwrap(HeightColors.range(of: triangles, axis: .z))
+
+        let colors = try HeightColors.colors(of: triangles, axis: .z, range: range)
+
+        XCTAssertEqual(colors.triangleCount, 12)
+        XCTAssertEqual(colors.corners(ofTriangle: 0).0, rgba(HeightColors.gradient.first))
+        XCTAssertEqual(colors.corners(ofTriangle: 2).0, rgba(HeightColors.gradient.last))
+    }
+
+    func testSideFacesRunThroughTheGradient() throws {
+        let triangles = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 2))
+
+        let colors = try HeightColors.colors(of: triangles, axis: .z, range: 0...2)
+
+        // Side face a-b-f: two corners at the bottom, one at the top
+        let (a, b, f) = colors.corners(ofTriangle: 4)
+        XCTAssertEqual(a, rgba(HeightColors.gradient.first))
+        XCTAssertEqual(b, rgba(HeightColors.gradient.first))
+        XCTAssertEqual(f, rgba(HeightColors.gradient.last))
+    }
+}
@@ -0,0 +1,99 @@
+import XCTest
+@testable import GoSTL
+
+final class HeightMapTests: XCTestCase {
+
+    /// 10 x 4 x 2 mm base with a 2 x 2 x 3 mm post on its right end
+    private func part() -> STLModel {
+        let base = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 4, 2))
+        let post = TestModels.box(from: Vector3(8, 0, 2), to: Vector3(10, 2, 5))
+        return STLModel(triangles: base + post, name: "part")
+    }
+
+    // MARK: - Computation Tests
+
+    func testGridSize() throws {
+        let map = try HeightMap.compute(model: part(), resolution: 0.5)
+
+        XCTAssertEqual(map.width, 20)
+        XCTAssertEqual(map.height, 8)
+        XCTAssertEqual(map.coveredCount, 160)
+        XCTAssertEqual(map.origin.right, 0.25, accuracy: 1e-12)
+    }
+
+    func testHighestSurfaceWins() throws {
+        let map = try HeightMap.compute(model: part(), resolution: 1)
+
+        // Row 0 is the top of the image (y = 3.5), the post covers y 0...2 (rows 2 and 3)
+        XCTAssertEqual(try XCTUnwrap(map.value(row: 0, column: 0)), 2, accuracy: 1e-9)
+        XCTAssertEqual(try XCTUnwrap(map.value(row: 3, column: 9)), 5, accuracy: 1e-9)
+        XCTAssertEqual(try XCTUnwrap(map.value(row: 0, column: 9)), 2, accuracy: 1e-9)
+        XCTAssertEqual(map.range, 2...5)
+    }
+
+    func testUncoveredCells() throws {
+        // Two posts with a gap between them
+        let model = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(1, 1, 1)) + TestModels.box(from: Vector3(3, 0, 0), to: Vector3(4, 1, 2)))
+
+        let map = try HeightMap.compute(model: model, resolution: 1)
+
+        XCTAssertEqual(map.width, 4)
+        XCTAssertNil(map.value(row: 0, column: 1))
+        XCTAssertNil(map.value(row: 0, column: 2))
+        XCTAssertEqual(map.coveredCount, 2)
+    }
+
+    func testSlopedSurfaceIsInterpolated() throws {
+        let ramp = [
+            Triangle(v1: Vector3(0, 0, 0), v2: Vector3(4, 0, 4), v3: Vector3(4, 4, 4)),
+            Triangle(v1: Vector3(0, 0, 0), v2: Vector3(4, 4, 4), v3: Vector3(0, 4, 0))
+        ]
+
+        let map = try HeightMap.compute(model: STLModel(triangles: ramp), resolution: 1)
+
+        XCTAssertEqual(try XCTUnwrap(map.value(row: 0, column: 0)), 0.5, accuracy: 1e-9)
+        XCTAssertEqual(try XCTUnwrap(map.value(row: 0, column: 3)), 3.5, accuracy: 1e-9)
+    }
+
+    func testAxisX() throws {
+        let map = try HeightMap.compute(model: part(), axis: .x, resolution: 1)
+
+        // Seen from +X: 4 mm wide (Y) and 5 mm high (Z), everything at x = 10
+        XCTAssertEqual(map.width, 4)
+        XCTAssertEqual(map.height, 5)
+        XCTAssertEqual(map.range, 10...10)
+        XCTAssertNil(map.value(row: 0, column: 3))  // Above the base, beside the post
+    }
+
+    func testEmptyModel() {
+        XCTAssertThrowsError(try HeightMap.compute(model: STLModel(), resolution: 1))
+    }
+
+    func testTooLarge() {
+        XCTAssertThrowsError(try HeightMap.compute(model: part(), resolution: 0.0005)) { error in
+            guard case HeightMapError.tooLarge = error else {
+                return XCTFail("Unexpected error \(error)")
+            }
+        }
+    }
+
+    // MARK: - Output Tests
+
+    func testCSV() throws {
+        let model = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(1, 1, 1)) + TestModels.box(from: Vector3(2, 0, 0), to: Vector3(3, 1, 2)))
+        let map = try HeightMap.compute(model: model, resolution: 1)
+
+        XCTAssertEqual(map.csv(decimals: 1), "1.0,,2.0\n")
+    }
+
+    func testGrayscaleImage() throws {
+        let map = try HeightMap.compute(model: part(), resolution: 0.5)
+
+        let image = try XCTUnwrap(map.grayscaleImage())
+
+        XCTAssertEqual(image.width, 20)
+        XCTAssertEqual(image.height, 8)
+        XCTAssertEqual(image.bitsPerComponent, 16)
+        XCTAssertFalse(try OffscreenRenderer.pngData(image).isEmpty)
+    }
+}
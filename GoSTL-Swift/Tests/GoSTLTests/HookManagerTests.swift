@@ -0,0 +1,132 @@
+import XCTest
+@testable import GoSTL
+
+final class HookManagerTests: XCTestCase {
+    private var configFile: URL!
+
+    override func setUpWithError() throws {
+        configFile = FileManager.default.temporaryDirectory
+            .appendingPathComponent("gostl-hooks-\(UUID().uuidString).json")
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: configFile)
+    }
+
+    // MARK: - Configuration Tests
+
+    func testMissingConfigHasNoHooks() {
+        XCTAssertTrue(HookManager(configFile: configFile).hooks.isEmpty)
+    }
+
+    func testHooksAreLoadedFromConfig() throws {
+        try """
+            {
+              "hooks": [
+                { "event": "measurementAdded", "command": "cat" },
+                { "event": "*", "url": "https://qa.example.com/hook", "headers": { "Authorization": "Bearer x" } }
+              ]
+            }
+            """.write(to: configFile, atomically: true, encoding: .utf8)
+
+        let hooks = HookManager(configFile: configFile).hooks
+
+        XCTAssertEqual(hooks.count, 2)
+        XCTAssertEqual(hooks[0].command, "cat")
+        XCTAssertEqual(hooks[1].url?.host, "qa.example.com")
+        XCTAssertEqual(hooks[1].headers?["Authorization"], "Bearer x")
+    }
+
+    func testInvalidConfigHasNoHooks() throws {
+        try "{ not json".write(to: configFile, atomically: true, encoding: .utf8)
+
+        XCTAssertTrue(HookManager(configFile: configFile).hooks.isEmpty)
+    }
+
+    func testEditedConfigIsReloaded() throws {
+        try """
+            { "hooks": [{ "event": "modelLoaded", "command": "true" }] }
+            """.write(to: configFile, atomically: true, encoding: .utf8)
+        let manager = HookManager(configFile: configFile)
+        XCTAssertEqual(manager.hooks.count, 1)
+
+        try """
+            { "hooks": [{ "event": "modelLoaded", "command": "true" }, { "event": "*", "command": "true" }] }
+            """.write(to: configFile, atomically: true, encoding: .utf8)
+
+        let deadline = Date().addingTimeInterval(5)
+        while Date() < deadline && manager.hooks.count != 2 {
+            Thread.sleep(forTimeInterval: 0.05)
+        }
+        XCTAssertEqual(manager.hooks.count, 2)
+    }
+
+    func testCommandIgnoringPayloadDoesNotRaiseSIGPIPE() {
+        // Exits before reading stdin; writing the payload would raise SIGPIPE without F_SETNOSIGPIPE
+        let payload = Data(repeating: 0x20, count: 1 << 20)
+        try? """
+            { "hooks": [{ "event": "modelLoaded", "command": "exit 0" }] }
+            """.write(to: configFile, atomically: true, encoding: .utf8)
+
+        HookManager(configFile: configFile).fire(.modelLoaded, file: nil, data: ["padding": String(decoding: payload, as: UTF8.self)])
+        Thread.sleep(forTimeInterval: 0.5)
+    }
+
+    func testEventMatching() {
+        let specific = Hook(event: "modelReloaded", command: "true")
+        let wildcard = Hook(event: "*", command: "true")
+
+        XCTAssertTrue(specific.matches(.modelReloaded))
+        XCTAssertFalse(specific.matches(.measurementAdded))
+        XCTAssertTrue(wildcard.matches(.analysisCompleted))
+    }
+
+    // MARK: - Payload Tests
+
+    func testPayloadContainsEventFileAndData() throws {
+        let date = Date(timeIntervalSince1970: 0)
+        let data = try HookManager.payload(
+            event: .measurementAdded,
+            file: URL(fileURLWithPath: "/tmp/part.stl"),
+            data: ["type": "Distance", "value": 12.5],
+            date: date
+        )
+
+        let json = try XCTUnwrap(JSONSerialization.jsonObject(with: data) as? [String: Any])
+        XCTAssertEqual(json["event"] as? String, "measurementAdded")
+        XCTAssertEqual(json["file"] as? String, "/tmp/part.stl")
+        XCTAssertEqual(json["timestamp"] as? String, "1970-01-01T00:00:00Z")
+        XCTAssertEqual((json["data"] as? [String: Any])?["value"] as? Double, 12.5)
+    }
+
+    func testPayloadCleansValuesJSONCannotHold() throws {
+        let data = try HookManager.payload(
+            event: .analysisCompleted,
+            file: nil,
+            data: ["results": [["name": "r", "value": Double.nan], ["name": "s", "value": Double.infinity]], "date": Date(timeIntervalSince1970: 0)]
+        )
+
+        let json = try XCTUnwrap(JSONSerialization.jsonObject(with: data) as? [String: Any])
+        let payload = try XCTUnwrap(json["data"] as? [String: Any])
+        let results = try XCTUnwrap(payload["results"] as? [[String: Any]])
+        XCTAssertTrue(results.allSatisfy { $0["value"] is NSNull })
+        XCTAssertEqual(payload["date"] as? String, "1970-01-01T00:00:00Z")
+    }
+
+    func testCommandHookReceivesPayloadOnStdin() throws {
+        let output = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-hook-output-\(UUID().uuidString).json")
+        defer { try? FileManager.default.removeItem(at: output) }
+        try """
+            { "hooks": [{ "event": "modelLoaded", "command": "cat > '\(output.path)'" }] }
+            """.write(to: configFile, atomically: true, encoding: .utf8)
+
+        HookManager(configFile: configFile).fire(.modelLoaded, file: nil, data: ["triangleCount": 12])
+
+        let deadline = Date().addingTimeInterval(5)
+        while Date() < deadline && (try? Data(contentsOf: output))?.isEmpty != false {
+            Thread.sleep(forTimeInterval: 0.05)
+        }
+        let json = try XCTUnwrap(JSONSerialization.jsonObject(with: Data(contentsOf: output)) as? [String: Any])
+        XCTAssertEqual(json["event"] as? String, "modelLoaded")
+    }
+}
@@ -0,0 +1,68 @@
+import XCTest
+@testable import GoSTL
+
+final class IntegrationTests: XCTestCase {
+    // MARK: - Link Tests
+
+    func testLinkRoundTrip() throws {
+        let camera = CameraKeyframe(distance: 120, angleX: 20 * .pi / 180, angleY: 210 * .pi / 180, target: Vector3(500_100.5, 0, 15))
+        let measurement = Measurement(
+            type: .distance,
+            points: [
+                MeasurementPoint(position: Vector3(0, 0, 0), normal: Vector3(0, 0, 1), isAirPoint: false),
+                MeasurementPoint(position: Vector3(10, 0, 0), normal: Vector3(0, 0, 1), isAirPoint: false)
+            ],
+            value: 10
+        )
+        let link = GoSTLLink(file: URL(fileURLWithPath: "/parts/my case.stl"), camera: camera, measurements: [measurement])
+
+        let parsed = try GoSTLLink(url: try link.url())
+
+        XCTAssertEqual(parsed.file.path, "/parts/my case.stl")
+        let parsedCamera = try XCTUnwrap(parsed.camera)
+        XCTAssertEqual(parsedCamera.distance, 120)
+        XCTAssertEqual(parsedCamera.angleY, camera.angleY, accuracy: 1e-4)
+        XCTAssertEqual(parsedCamera.target, Vector3(500_100.5, 0, 15))
+        XCTAssertEqual(parsed.measurements?.count, 1)
+        XCTAssertEqual(parsed.measurements?.first?.value, 10)
+    }
+
+    func testLinkWithFileOnly() throws {
+        let link = try GoSTLLink(url: XCTUnwrap(URL(string: "gostl://open?file=/parts/case.3mf")))
+
+        XCTAssertEqual(link.file.path, "/parts/case.3mf")
+        XCTAssertNil(link.camera)
+        XCTAssertNil(link.measurements)
+    }
+
+    func testInvalidLinks() throws {
+        XCTAssertThrowsError(try GoSTLLink(url: XCTUnwrap(URL(string: "gostl://open"))))
+        XCTAssertThrowsError(try GoSTLLink(url: XCTUnwrap(URL(string: "gostl://delete?file=/a.stl"))))
+        XCTAssertThrowsError(try GoSTLLink(url: XCTUnwrap(URL(string: "gostl://open?file=/a.stl&camera=1,2,3"))))
+    }
+
+    // MARK: - Info.plist Tests
+
+    func testIntegrationAddsMissingTypesAndScheme() throws {
+        let plist: [String: Any] = [
+            "CFBundleDocumentTypes": [["CFBundleTypeExtensions": ["stl", "STL"]]]
+        ]
+
+        let updated = try XCTUnwrap(InstallIntegrationCommand.integrated(plist, extensions: ["stl", "3mf"]))
+
+        let types = try XCTUnwrap(updated["CFBundleDocumentTypes"] as? [[String: Any]])
+        XCTAssertEqual(types.count, 2)
+        XCTAssertEqual(types[1]["CFBundleTypeExtensions"] as? [String], ["3mf", "3MF"])
+        let urlTypes = try XCTUnwrap(updated["CFBundleURLTypes"] as? [[String: Any]])
+        XCTAssertEqual(urlTypes.first?["CFBundleURLSchemes"] as? [String], ["gostl"])
+    }
+
+    func testIntegrationLeavesCompletePlistAlone() throws {
+        let plist: [String: Any] = [
+            "CFBundleDocumentTypes": [["CFBundleTypeExtensions": ["stl"]]],
+            "CFBundleURLTypes": [["CFBundleURLSchemes": ["gostl"]]]
+        ]
+
+        XCTAssertNil(InstallIntegrationCommand.integrated(plist, extensions: ["stl"]))
+    }
+}
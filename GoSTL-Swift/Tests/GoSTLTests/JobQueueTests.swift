@@ -0,0 +1,104 @@
+import XCTest
+@testable import GoSTL
+
+final class JobQueueTests: XCTestCase {
+    // MARK: - Queue Tests
+
+    func testCompletionRunsOnMainThread() {
+        let queue = JobQueue()
+        let done = expectation(description: "completion")
+
+        queue.submit(.analysis, title: "Answer") { _ in
+            XCTAssertFalse(Thread.isMainThread)
+            return 42
+        } completion: { result in
+            XCTAssertTrue(Thread.isMainThread)
+            XCTAssertEqual(try? result.get(), 42)
+            done.fulfill()
+        }
+        XCTAssertTrue(queue.isRunning(.analysis))
+
+        wait(for: [done], timeout: 5)
+        XCTAssertTrue(queue.jobs.isEmpty)
+    }
+
+    func testNewJobSupersedesRunningJobOfSameKind() {
+        let queue = JobQueue()
+        let stale = expectation(description: "stale completion")
+        stale.isInverted = true
+        let fresh = expectation(description: "fresh completion")
+
+        queue.submit(.load, title: "First") { job -> Int in
+            while !job.isCancelled {
+                Thread.sleep(forTimeInterval: 0.01)
+            }
+            return 1
+        } completion: { _ in
+            stale.fulfill()
+        }
+        queue.submit(.load, title: "Second") { _ in 2 } completion: { result in
+            XCTAssertEqual(try? result.get(), 2)
+            fresh.fulfill()
+        }
+
+        wait(for: [fresh, stale], timeout: 1)
+    }
+
+    func testExportsRunSideBySide() {
+        let queue = JobQueue()
+        let done = expectation(description: "both exports")
+        done.expectedFulfillmentCount = 2
+
+        queue.submit(.export, title: "a.stl") { _ in 1 } completion: { _ in done.fulfill() }
+        queue.submit(.export, title: "b.stl") { _ in 2 } completion: { _ in done.fulfill() }
+        XCTAssertEqual(queue.jobs.count, 2)
+
+        wait(for: [done], timeout: 5)
+    }
+
+    func testFailureIsDelivered() {
+        let queue = JobQueue()
+        let done = expectation(description: "completion")
+
+        queue.submit(.export, title: "Broken") { _ -> Int in
+            throw STLExportError.emptyModel
+        } completion: { result in
+            XCTAssertThrowsError(try result.get())
+            done.fulfill()
+        }
+
+        wait(for: [done], timeout: 5)
+    }
+
+    // MARK: - Context Tests
+
+    func testCancelTerminatesProcess() throws {
+        let job = JobContext()
+        let process = Process()
+        process.executableURL = URL(fileURLWithPath: "/bin/sleep")
+        process.arguments = ["10"]
+
+        DispatchQueue.global().asyncAfter(deadline: .now() + 0.2) {
+            job.cancel()
+        }
+
+        let start = Date()
+        XCTAssertThrowsError(try job.run(process)) { error in
+            XCTAssertTrue(error is CancellationError)
+        }
+        XCTAssertLessThan(Date().timeIntervalSince(start), 5)
+    }
+
+    func testProgressIsClamped() {
+        final class Reports: @unchecked Sendable {
+            var values: [Double?] = []
+        }
+        let reports = Reports()
+        let job = JobContext { progress, _ in reports.values.append(progress) }
+
+        job.reportProgress(1.5, "done")
+        job.reportProgress(nil)
+
+        XCTAssertEqual(reports.values, [1.0, nil])
+    }
+}
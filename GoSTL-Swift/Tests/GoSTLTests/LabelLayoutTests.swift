@@ -0,0 +1,84 @@
+import XCTest
+@testable import GoSTL
+
+final class LabelLayoutTests: XCTestCase {
+
+    private let bounds = CGRect(x: 0, y: 0, width: 800, height: 600)
+
+    private func item(_ id: Int, _ x: CGFloat, _ y: CGFloat, priority: Double = 0) -> LabelLayout.Item {
+        LabelLayout.Item(id: id, anchor: CGPoint(x: x, y: y), size: CGSize(width: 60, height: 20), priority: priority)
+    }
+
+    // MARK: - Placement Tests
+
+    func testSeparateLabelsStayAtAnchors() {
+        let placements = LabelLayout().layout([item(0, 100, 100), item(1, 400, 300)], in: bounds)
+
+        XCTAssertEqual(placements.count, 2)
+        XCTAssertFalse(placements.contains(where: \.isDisplaced))
+    }
+
+    func testOverlappingLabelIsDisplacedWithoutOverlap() throws {
+        let placements = LabelLayout().layout([item(0, 100, 100, priority: 1), item(1, 130, 105)], in: bounds)
+
+        XCTAssertEqual(placements.count, 2)
+        let first = try XCTUnwrap(placements.first { $0.id == 0 })
+        let second = try XCTUnwrap(placements.first { $0.id == 1 })
+        XCTAssertFalse(first.isDisplaced)
+        XCTAssertTrue(second.isDisplaced)
+        XCTAssertFalse(first.frame.intersects(second.frame))
+    }
+
+    func testHigherPriorityKeepsAnchor() throws {
+        let placements = LabelLayout().layout([item(0, 100, 100), item(1, 130, 105, priority: 5)], in: bounds)
+
+        XCTAssertFalse(try XCTUnwrap(placements.first { $0.id == 1 }).isDisplaced)
+        XCTAssertTrue(try XCTUnwrap(placements.first { $0.id == 0 }).isDisplaced)
+    }
+
+    func testLeaderEndsOnLabelBorder() throws {
+        let placement = LabelLayout.Placement(id: 0, anchor: CGPoint(x: 100, y: 100), center: CGPoint(x: 100, y: 130), size: CGSize(width: 60, height: 20))
+
+        XCTAssertEqual(placement.leaderEnd, CGPoint(x: 100, y: 120))
+    }
+
+    func testLabelsOutsideViewAreLeftOut() {
+        let placements = LabelLayout().layout([item(0, -50, 100)], in: bounds)
+
+        XCTAssertTrue(placements.isEmpty)
+    }
+
+    // MARK: - Cluster Tests
+
+    func testCloseAnchorsAreClustered() throws {
+        let placements = LabelLayout().layout([item(0, 200, 200, priority: 2), item(1, 205, 203), item(2, 198, 210)], in: bounds)
+
+        let placement = try XCTUnwrap(placements.first)
+        XCTAssertEqual(placements.count, 1)
+        XCTAssertEqual(placement.id, 0)
+        XCTAssertEqual(Set(placement.clustered), [1, 2])
+    }
+
+    func testClusterSplitsWithHysteresis() {
+        let layout = LabelLayout()
+        _ = layout.layout([item(0, 200, 200, priority: 1), item(1, 210, 200)], in: bounds)
+
+        // Slightly beyond the cluster radius: still merged because it was merged before
+        XCTAssertEqual(layout.layout([item(0, 200, 200, priority: 1), item(1, 220, 200)], in: bounds).count, 1)
+        // Well beyond: split up again
+        XCTAssertEqual(layout.layout([item(0, 200, 200, priority: 1), item(1, 240, 200)], in: bounds).count, 2)
+    }
+
+    // MARK: - Stability Tests
+
+    func testDisplacedLabelKeepsItsOffset() throws {
+        let layout = LabelLayout()
+        let first = try XCTUnwrap(layout.layout([item(0, 100, 100, priority: 1), item(1, 130, 105)], in: bounds).first { $0.id == 1 })
+
+        // Moving both anchors a little (orbiting) keeps the same side
+        let second = try XCTUnwrap(layout.layout([item(0, 103, 101, priority: 1), item(1, 133, 106)], in: bounds).first { $0.id == 1 })
+
+        XCTAssertEqual(second.center.x - second.anchor.x, first.center.x - first.anchor.x, accuracy: 0.001)
+        XCTAssertEqual(second.center.y - second.anchor.y, first.center.y - first.anchor.y, accuracy: 0.001)
+    }
+}
@@ -0,0 +1,60 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class MagnifierTests: XCTestCase {
+    private let viewSize = CGSize(width: 1600, height: 1000)
+
+    /// Clip-space position of a pixel (Y=0 at bottom) after the magnifier transform
+    private func magnified(_ pixel: CGPoint, cursor: CGPoint, zoom: Float, size: Double) -> SIMD2<Float> {
+        let ndc = SIMD4<Float>(
+            Float(2 * pixel.x / viewSize.width - 1),
+            Float(2 * pixel.y / viewSize.height - 1),
+            0.5,
+            1
+        )
+        let result = MetalRenderer.magnifierTransform(cursor: cursor, zoom: zoom, size: size, viewSize: viewSize) * ndc
+        return SIMD2(result.x / result.w, result.y / result.w)
+    }
+
+    // MARK: - Transform Tests
+
+    func testCursorMapsToInsetCenter() {
+        let cursor = CGPoint(x: 420, y: 310)
+
+        let center = magnified(cursor, cursor: cursor, zoom: 3, size: 260)
+
+        XCTAssertEqual(center.x, 0, accuracy: 0.0001)
+        XCTAssertEqual(center.y, 0, accuracy: 0.0001)
+    }
+
+    func testInsetShowsZoomedNeighborhood() {
+        let cursor = CGPoint(x: 800, y: 500)
+        let size = 240.0
+        let zoom: Float = 4
+
+        // Half the inset covers size / 2 / zoom pixels of the view
+        let reach = size / 2 / Double(zoom)
+        let right = magnified(CGPoint(x: cursor.x + reach, y: cursor.y), cursor: cursor, zoom: zoom, size: size)
+        let top = magnified(CGPoint(x: cursor.x, y: cursor.y + reach), cursor: cursor, zoom: zoom, size: size)
+
+        XCTAssertEqual(right.x, 1, accuracy: 0.0001)
+        XCTAssertEqual(top.y, 1, accuracy: 0.0001)
+    }
+
+    // MARK: - Placement Tests
+
+    func testInsetPlacedAboveRightOfCursor() {
+        let origin = MetalRenderer.magnifierOrigin(cursor: CGPoint(x: 400, y: 500), size: 200, viewSize: viewSize)
+
+        XCTAssertEqual(origin.x, 400 + MetalRenderer.magnifierOffset)
+        XCTAssertEqual(origin.y, 500 - MetalRenderer.magnifierOffset - 200)
+    }
+
+    func testInsetFlipsAtScreenEdges() {
+        let origin = MetalRenderer.magnifierOrigin(cursor: CGPoint(x: 1550, y: 20), size: 200, viewSize: viewSize)
+
+        XCTAssertEqual(origin.x, 1550 - MetalRenderer.magnifierOffset - 200)
+        XCTAssertEqual(origin.y, 20 + MetalRenderer.magnifierOffset)
+    }
+}
@@ -0,0 +1,105 @@
+import XCTest
+@testable import GoSTL
+
+final class MassPropertiesTests: XCTestCase {
+    // MARK: - Box
+
+    func testSolidBoxMatchesClosedForm() {
+        // 40 × 20 × 10 mm at density 1 g/cm³: 8 g
+        let properties = MassProperties.compute(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(40, 20, 10)), density: 1)
+
+        XCTAssertEqual(properties.volume, 8000, accuracy: 1e-6)
+        XCTAssertEqual(properties.mass, 8, accuracy: 1e-9)
+        XCTAssertEqual(properties.centerOfMass.x, 20, accuracy: 1e-9)
+        XCTAssertEqual(properties.centerOfMass.y, 10, accuracy: 1e-9)
+        XCTAssertEqual(properties.centerOfMass.z, 5, accuracy: 1e-9)
+
+        // I = m (b² + c²) / 12
+        XCTAssertEqual(properties.inertia.xx, 8 * (20 * 20 + 10 * 10) / 12, accuracy: 1e-6)
+        XCTAssertEqual(properties.inertia.yy, 8 * (40 * 40 + 10 * 10) / 12, accuracy: 1e-6)
+        XCTAssertEqual(properties.inertia.zz, 8 * (40 * 40 + 20 * 20) / 12, accuracy: 1e-6)
+        XCTAssertEqual(properties.inertia.xy, 0, accuracy: 1e-6)
+        XCTAssertEqual(properties.inertia.xz, 0, accuracy: 1e-6)
+        XCTAssertEqual(properties.inertia.yz, 0, accuracy: 1e-6)
+    }
+
+    func testPrincipalAxesOfBox() {
+        let properties = MassProperties.compute(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(40, 20, 10)), density: 1.24)
+
+        // Smallest moment about the long X axis, largest about Z
+        XCTAssertEqual(properties.principalMoments[0], properties.inertia.xx, accuracy: 1e-6)
+        XCTAssertEqual(properties.principalMoments[2], properties.inertia.zz, accuracy: 1e-6)
+        XCTAssertEqual(properties.principalAxes[0].x, 1, accuracy: 1e-9)
+        XCTAssertEqual(properties.principalAxes[1].y, 1, accuracy: 1e-9)
+        XCTAssertEqual(properties.principalAxes[2].z, 1, accuracy: 1e-9)
+
+        // Radius of gyration about X: √((20² + 10²) / 12)
+        XCTAssertEqual(properties.radiiOfGyration[0], ((400.0 + 100) / 12).squareRoot(), accuracy: 1e-9)
+    }
+
+    func testRotatedBoxHasProductsOfInertia() {
+        // The same box rotated 45° about Z
+        let angle = Double.pi / 4
+        let rotate = { (v: Vector3) in Vector3(v.x * cos(angle) - v.y * sin(angle), v.x * sin(angle) + v.y * cos(angle), v.z) }
+        let triangles = TestModels.box(from: Vector3(-20, -10, -5), to: Vector3(20, 10, 5)).map {
+            Triangle(v1: rotate($0.v1), v2: rotate($0.v2), v3: rotate($0.v3))
+        }
+
+        let properties = MassProperties.compute(triangles: triangles, density: 1)
+
+        XCTAssertNotEqual(properties.inertia.xy, 0, accuracy: 1e-3)
+        XCTAssertEqual(properties.principalMoments[0], 8 * (400.0 + 100) / 12, accuracy: 1e-6)
+        XCTAssertEqual(properties.principalMoments[2], 8 * (1600.0 + 400) / 12, accuracy: 1e-6)
+        // The long axis is the diagonal of the XY plane
+        XCTAssertEqual(properties.principalAxes[0].x, cos(angle), accuracy: 1e-9)
+        XCTAssertEqual(properties.principalAxes[0].y, sin(angle), accuracy: 1e-9)
+        let axes = properties.principalAxes
+        XCTAssertEqual(axes[0].cross(axes[1]).dot(axes[2]), 1, accuracy: 1e-9)
+    }
+
+    func testFarFromOriginAndInwardNormals() {
+        let offset = Vector3(1e6, -2e6, 5e5)
+        let triangles = TestModels.box(from: offset, to: offset + Vector3(40, 20, 10))
+        let inverted = triangles.map { Triangle(v1: $0.v1, v2: $0.v3, v3: $0.v2) }
+
+        for mesh in [triangles, inverted] {
+            let properties = MassProperties.compute(triangles: mesh, density: 1)
+            XCTAssertEqual(properties.mass, 8, accuracy: 1e-6)
+            XCTAssertEqual(properties.centerOfMass.x, offset.x + 20, accuracy: 1e-6)
+            XCTAssertEqual(properties.inertia.xx, 8 * (400.0 + 100) / 12, accuracy: 1e-3)
+        }
+    }
+
+    func testEmptyModel() {
+        let properties = STLModel().massProperties(density: 1.24)
+
+        XCTAssertEqual(properties.mass, 0)
+        XCTAssertEqual(properties.radiiOfGyration, [0, 0, 0])
+    }
+
+    // MARK: - Analysis Output
+
+    func testAnalysisRecordFields() throws {
+        let model = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(40, 20, 10)))
+        let analysis = model.analyze()
+        let record = AnalysisRecord(
+            fileName: "box.stl",
+            analysis: analysis,
+            estimate: Material.pla.estimate(volume: analysis.volume),
+            massProperties: model.massProperties(density: Material.pla.density)
+        )
+
+        guard case .number(let centerY) = record.value(for: "center_of_mass.y") else {
+            return XCTFail("Missing center of mass")
+        }
+        XCTAssertEqual(centerY, 10, accuracy: 1e-9)
+        XCTAssertNotNil(record.value(for: "inertia.xx"))
+        XCTAssertNotNil(record.value(for: "principal_axes.3.z"))
+        XCTAssertNotNil(record.value(for: "radius_of_gyration.2"))
+        XCTAssertNil(AnalysisRecord(fileName: "box.stl", analysis: analysis).value(for: "inertia.xx"))
+
+        let json = try JSONAnalysisFormatter().format([record])
+        let object = try XCTUnwrap(JSONSerialization.jsonObject(with: Data(json.utf8)) as? [String: Any])
+        XCTAssertNotNil((object["principal_axes"] as? [String: Any])?["1"])
+    }
+}
@@ -0,0 +1,34 @@
+import XCTest
+@testable import GoSTL
+
+final class MaterialTests: XCTestCase {
+    // MARK: - Lookup Tests
+
+    func testNamedIgnoresCase() {
+        XCTAssertEqual(Material.named("petg"), .petg)
+        XCTAssertEqual(Material.named("ALUMINUM"), .aluminum)
+        XCTAssertNil(Material.named("wood"))
+    }
+
+    // MARK: - Estimate Tests
+
+    func testEstimateScalesWithInfill() {
+        // 10 cm³ of PLA at 20 % infill
+        let estimate = Material.pla.estimate(volume: 10_000, infill: 0.2)
+
+        XCTAssertEqual(estimate.mass, 10 * 1.24 * 0.2, accuracy: 1e-9)
+        XCTAssertEqual(estimate.cost, estimate.mass / 1000 * Material.pla.pricePerKg, accuracy: 1e-9)
+    }
+
+    func testSolidMaterialsIgnoreInfill() {
+        let estimate = Material.aluminum.estimate(volume: 1000, infill: 0.2)
+
+        XCTAssertEqual(estimate.infill, 1.0)
+        XCTAssertEqual(estimate.mass, 2.70, accuracy: 1e-9)
+    }
+
+    func testInfillIsClamped() {
+        XCTAssertEqual(Material.petg.estimate(volume: 1000, infill: 1.5).infill, 1.0)
+        XCTAssertEqual(Material.petg.estimate(volume: 1000, infill: -1).mass, 0)
+    }
+}
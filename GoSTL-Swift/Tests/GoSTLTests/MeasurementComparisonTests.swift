@@ -0,0 +1,103 @@
+import XCTest
+@testable import GoSTL
+
+final class MeasurementComparisonTests: XCTestCase {
+    private func distance(from a: Vector3, to b: Vector3) -> Measurement {
+        Measurement(
+            type: .distance,
+            points: [
+                MeasurementPoint(position: a, normal: Vector3(0, 0, 1)),
+                MeasurementPoint(position: b, normal: Vector3(0, 0, 1))
+            ],
+            value: a.distance(to: b)
+        )
+    }
+
+    private func angle(_ degrees: Double) -> Measurement {
+        Measurement(type: .angle, points: [], value: degrees)
+    }
+
+    // MARK: - Measurement Set Tests
+
+    func testNamesAreNumberedPerType() {
+        let measurements = [distance(from: Vector3(0, 0, 0), to: Vector3(1, 0, 0)), angle(90), distance(from: Vector3(0, 0, 0), to: Vector3(2, 0, 0))]
+
+        XCTAssertEqual(MeasurementSet.names(for: measurements), ["Distance 1", "Angle 1", "Distance 2"])
+    }
+
+    func testRoundTrip() throws {
+        let set = MeasurementSet(fileName: "rev-a.stl", measurements: [distance(from: Vector3(0, 0, 0), to: Vector3(3, 4, 0))])
+        let url = FileManager.default.temporaryDirectory.appendingPathComponent("measurements-\(UUID().uuidString).json")
+        defer { try? FileManager.default.removeItem(at: url) }
+
+        try set.write(to: url)
+        let loaded = try MeasurementSet.load(from: url)
+
+        XCTAssertEqual(loaded.fileName, "rev-a.stl")
+        XCTAssertEqual(loaded.entries.map(\.name), ["Distance 1"])
+        XCTAssertEqual(loaded.entries.first?.measurement.value, 5)
+    }
+
+    func testReevaluationRebindsToClosestVertex() {
+        let baseline = MeasurementSet(fileName: "rev-a.stl", measurements: [distance(from: Vector3(0, 0, 0), to: Vector3(10, 0, 0))])
+        // Revision B: the far vertex moved 0.2 mm, and there is no vertex at the origin any more
+        let model = STLModel(triangles: [
+            Triangle(v1: Vector3(0.1, 5, 0), v2: Vector3(10.2, 0, 0), v3: Vector3(0, 10, 0))
+        ])
+
+        let current = baseline.reevaluated(on: model, fileName: "rev-b.stl", searchRadius: 0.5)
+        let measurement = current.entries[0].measurement
+
+        XCTAssertEqual(current.fileName, "rev-b.stl")
+        XCTAssertEqual(measurement.points[1].position, Vector3(10.2, 0, 0))
+        XCTAssertEqual(measurement.value, 10.2, accuracy: 1e-9)
+        XCTAssertEqual(measurement.stalePointIndices, [0])
+    }
+
+    // MARK: - Comparison Tests
+
+    func testStatuses() {
+        let baseline = MeasurementSet(fileName: "a", entries: [
+            .init(name: "Distance 1", measurement: distance(from: Vector3(0, 0, 0), to: Vector3(10, 0, 0))),
+            .init(name: "Distance 2", measurement: distance(from: Vector3(0, 0, 0), to: Vector3(20, 0, 0))),
+            .init(name: "Distance 3", measurement: distance(from: Vector3(0, 0, 0), to: Vector3(30, 0, 0))),
+            .init(name: "Angle 1", measurement: angle(90)),
+            .init(name: "Radius 1", measurement: Measurement(type: .radius, points: [], value: 4))
+        ])
+        let current = MeasurementSet(fileName: "b", entries: [
+            .init(name: "Distance 1", measurement: distance(from: Vector3(0, 0, 0), to: Vector3(10, 0, 0))),
+            .init(name: "Distance 2", measurement: distance(from: Vector3(0, 0, 0), to: Vector3(20.05, 0, 0))),
+            .init(name: "Distance 3", measurement: distance(from: Vector3(0, 0, 0), to: Vector3(30.5, 0, 0))),
+            .init(name: "Angle 1", measurement: angle(90.3)),
+            .init(name: "Angle 2", measurement: angle(45))
+        ])
+
+        let comparison = MeasurementComparison.compare(baseline: baseline, current: current, threshold: 0.1, angleThreshold: 0.5)
+
+        XCTAssertEqual(comparison.rows.map(\.status), [.unchanged, .changed, .exceeded, .changed, .removed, .added])
+        XCTAssertEqual(comparison.exceeded.map(\.name), ["Distance 3"])
+        XCTAssertEqual(comparison.rows[2].delta ?? 0, 0.5, accuracy: 1e-9)
+    }
+
+    func testCSVOutput() {
+        let baseline = MeasurementSet(fileName: "a", measurements: [angle(90)])
+        let current = MeasurementSet(fileName: "b", measurements: [angle(92)])
+
+        let csv = MeasurementComparison.compare(baseline: baseline, current: current).csv()
+
+        XCTAssertEqual(csv, """
+            name,unit,baseline,current,delta,status,stale_points
+            Angle 1,°,90.000000,92.000000,2.000000,exceeded,0
+
+            """)
+    }
+
+    func testTableSummary() {
+        let baseline = MeasurementSet(fileName: "a.stl", measurements: [angle(90)])
+
+        let table = MeasurementComparison.compare(baseline: baseline, current: baseline).table()
+
+        XCTAssertTrue(table.hasPrefix("Measurement  a.stl   a.stl   Delta"))
+        XCTAssertTrue(table.hasSuffix("0 of 1 measurement(s) exceed the threshold (0.100 mm, 0.50°)"))
+    }
+}
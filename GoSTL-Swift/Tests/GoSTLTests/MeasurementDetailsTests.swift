@@ -0,0 +1,104 @@
+import XCTest
+@testable import GoSTL
+
+final class MeasurementDetailsTests: XCTestCase {
+    private func distance(from a: Vector3, to b: Vector3) -> Measurement {
+        Measurement(
+            type: .distance,
+            points: [
+                MeasurementPoint(position: a, normal: Vector3(0, 0, 1)),
+                MeasurementPoint(position: b, normal: Vector3(0, 0, 1))
+            ],
+            value: a.distance(to: b)
+        )
+    }
+
+    private func value(_ label: String, in rows: [(label: String, value: String)]) -> String? {
+        rows.first { $0.label == label }?.value
+    }
+
+    // MARK: - Detail Tests
+
+    func testDistanceDetailsShowFullPrecisionAndDeltas() {
+        let rows = distance(from: Vector3(1, 2, 3), to: Vector3(4, 6, 3)).detailRows(showDiameter: false)
+
+        XCTAssertEqual(value("Distance", in: rows), "5.000000 mm")
+        XCTAssertEqual(value("ΔX", in: rows), "3.000000")
+        XCTAssertEqual(value("ΔY", in: rows), "4.000000")
+        XCTAssertEqual(value("ΔZ", in: rows), "0.000000")
+        XCTAssertEqual(value("P1", in: rows), "(1.0000, 2.0000, 3.0000)")
+        XCTAssertEqual(value("P2", in: rows), "(4.0000, 6.0000, 3.0000)")
+        XCTAssertNotNil(value("Created", in: rows))
+    }
+
+    func testRadiusDetailsShowBothRadiusAndDiameter() {
+        let measurement = Measurement(type: .radius, points: [], value: 2.5, circle: Circle(center: Vector3(1, 1, 0), radius: 2.5, normal: .unitZ))
+        let rows = measurement.detailRows(showDiameter: true)
+
+        XCTAssertEqual(rows.first?.label, "Diameter")
+        XCTAssertEqual(value("Diameter", in: rows), "5.000000 mm")
+        XCTAssertEqual(value("Radius", in: rows), "2.500000 mm")
+        XCTAssertEqual(value("Center", in: rows), "(1.0000, 1.0000, 0.0000)")
+    }
+
+    func testStalePointsAreMarked() {
+        var measurement = distance(from: .zero, to: .unitX)
+        measurement.stalePointIndices = [1]
+
+        XCTAssertEqual(value("P2", in: measurement.detailRows(showDiameter: false)), "(1.0000, 0.0000, 0.0000) (stale)")
+    }
+
+    // MARK: - Note Tests
+
+    func testNoteIsShownAndKeptWhenTranslated() {
+        var measurement = distance(from: .zero, to: .unitX)
+        measurement.note = "Bore spacing"
+
+        let moved = measurement.translated(by: Vector3(10, 0, 0))
+
+        XCTAssertEqual(moved.note, "Bore spacing")
+        XCTAssertEqual(moved.createdAt, measurement.createdAt)
+        XCTAssertEqual(value("Note", in: moved.detailRows(showDiameter: false)), "Bore spacing")
+    }
+
+    func testSetNoteTrimsAndClears() {
+        let system = MeasurementSystem()
+        system.measurements = [distance(from: .zero, to: .unitX)]
+
+        system.setNote("  Check after print ", forMeasurement: 0)
+        XCTAssertEqual(system.measurements[0].note, "Check after print")
+
+        system.setNote("   ", forMeasurement: 0)
+        XCTAssertNil(system.measurements[0].note)
+    }
+
+    func testNoteAndCreationTimeRoundTrip() throws {
+        var measurement = distance(from: .zero, to: Vector3(3, 4, 0))
+        measurement.note = "Wall"
+        let set = MeasurementSet(fileName: "part.stl", measurements: [measurement])
+
+        let loaded = try MeasurementSet.decode(try set.encoded())
+        let loadedMeasurement = try XCTUnwrap(loaded.entries.first?.measurement)
+
+        XCTAssertEqual(loadedMeasurement.note, "Wall")
+        XCTAssertNotNil(loadedMeasurement.createdAt)
+    }
+
+    // MARK: - Added Notifications
+
+    func testOnlyPickedMeasurementsAreReported() {
+        let system = MeasurementSystem()
+        var reported: [Measurement] = []
+        system.onMeasurementAdded = { reported.append($0) }
+
+        // Restored, replayed or scripted measurements are not announced again
+        system.measurements = [distance(from: Vector3(0, 0, 0), to: Vector3(1, 0, 0))]
+        system.measurements.append(distance(from: Vector3(0, 0, 0), to: Vector3(2, 0, 0)))
+        XCTAssertTrue(reported.isEmpty)
+
+        system.startMeasurement(type: .distance)
+        _ = system.addPoint(MeasurementPoint(position: Vector3(0, 0, 0), normal: Vector3(0, 0, 1)))
+        _ = system.addPoint(MeasurementPoint(position: Vector3(3, 0, 0), normal: Vector3(0, 0, 1)))
+        XCTAssertEqual(reported.map(\.value), [3])
+    }
+}
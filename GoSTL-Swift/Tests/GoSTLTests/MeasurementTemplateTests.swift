@@ -0,0 +1,159 @@
+import XCTest
+@testable import GoSTL
+
+final class MeasurementTemplateTests: XCTestCase {
+
+    private let definition = """
+        hole1: hole
+        hole2: hole
+        # datum
+        base: face
+        pitch = hole1 to hole2: 40 ± 0.1
+        height = hole1 to base
+        """
+
+    /// Three points on a horizontal circle of 5 mm radius
+    private func rim(centerX: Double) -> [MeasurementPoint] {
+        [Vector3(centerX + 5, 0, 2), Vector3(centerX, 5, 2), Vector3(centerX - 5, 0, 2)].map {
+            MeasurementPoint(position: $0, normal: Vector3(0, 0, 1))
+        }
+    }
+
+    private func pickPart(_ run: inout TemplateRun, secondHoleX: Double) -> Bool {
+        var completed = false
+        for point in rim(centerX: 0) + rim(centerX: secondHoleX) + [MeasurementPoint(position: Vector3(10, 10, 0), normal: Vector3(0, 0, 1))] {
+            completed = run.addPoint(point)
+        }
+        return completed
+    }
+
+    // MARK: - Definition
+
+    func testParse() throws {
+        let template = try MeasurementTemplate.parse(name: " Bracket ", definition: definition)
+
+        XCTAssertEqual(template.name, "Bracket")
+        XCTAssertEqual(template.features.map(\.kind), [.hole, .hole, .face])
+        XCTAssertEqual(template.dimensions.count, 2)
+        XCTAssertEqual(template.dimensions[0], MeasurementTemplate.Dimension(name: "pitch", first: "hole1", second: "hole2", nominal: 40, tolerance: 0.1))
+        XCTAssertNil(template.dimensions[1].nominal)
+        XCTAssertEqual(template.columns.map(\.title), ["hole1 Ø", "hole2 Ø", "pitch", "height"])
+    }
+
+    func testNamesMayContainTo() throws {
+        let template = try MeasurementTemplate.parse(name: "Lid", definition: """
+            top face: face
+            bottom to: plane
+            tilt = angle top face to bottom to
+            """)
+
+        XCTAssertEqual(template.dimensions[0].first, "top face")
+        XCTAssertEqual(template.dimensions[0].second, "bottom to")
+        XCTAssertEqual(template.dimensions[0].quantity, .angle)
+    }
+
+    func testDefinitionRoundTrip() throws {
+        let template = try MeasurementTemplate.parse(name: "Bracket", definition: definition)
+        XCTAssertEqual(try MeasurementTemplate.parse(name: "Bracket", definition: template.definition).definition, template.definition)
+    }
+
+    func testErrors() {
+        XCTAssertThrowsError(try MeasurementTemplate.parse(name: "", definition: "a: point")) {
+            XCTAssertEqual($0 as? MeasurementTemplateError, .emptyName)
+        }
+        XCTAssertThrowsError(try MeasurementTemplate.parse(name: "T", definition: "a: cone")) {
+            XCTAssertEqual($0 as? MeasurementTemplateError, .unknownKind(line: 1, kind: "cone"))
+        }
+        XCTAssertThrowsError(try MeasurementTemplate.parse(name: "T", definition: "a: point\nd = a to b")) {
+            XCTAssertEqual($0 as? MeasurementTemplateError, .unknownFeatures(line: 2))
+        }
+        XCTAssertThrowsError(try MeasurementTemplate.parse(name: "T", definition: "a: point\na: plane")) {
+            XCTAssertEqual($0 as? MeasurementTemplateError, .duplicateFeature("a"))
+        }
+        XCTAssertThrowsError(try MeasurementTemplate.parse(name: "T", definition: "# nothing")) {
+            XCTAssertEqual($0 as? MeasurementTemplateError, .noFeatures)
+        }
+    }
+
+    // MARK: - Saving
+
+    func testIDSurvivesEncoding() throws {
+        let template = try MeasurementTemplate.parse(name: "Bracket", definition: definition)
+
+        let decoded = try JSONDecoder().decode(MeasurementTemplate.self, from: JSONEncoder().encode(template))
+
+        XCTAssertEqual(decoded.id, template.id)
+        XCTAssertEqual(decoded, template)
+
+        // Templates saved without an ID still load
+        let legacy = #"{"name":"Old","features":[{"name":"a","kind":"point"}],"dimensions":[]}"#
+        XCTAssertEqual(try JSONDecoder().decode(MeasurementTemplate.self, from: Data(legacy.utf8)).name, "Old")
+    }
+
+    func testSavingReplacesByIDAndRejectsDuplicateNames() throws {
+        let bracket = try MeasurementTemplate.parse(name: "Bracket", definition: definition)
+        let lid = try MeasurementTemplate.parse(name: "Lid", definition: "a: point")
+        var templates = try lid.saved(into: bracket.saved(into: []))
+
+        // Renaming keeps the template in place
+        var renamed = bracket
+        renamed.name = "Bracket v2"
+        templates = try renamed.saved(into: templates)
+        XCTAssertEqual(templates.map(\.name), ["Bracket v2", "Lid"])
+
+        // A new template must not take an existing name
+        let other = try MeasurementTemplate.parse(name: "lid", definition: "b: point")
+        XCTAssertThrowsError(try other.saved(into: templates)) {
+            XCTAssertEqual($0 as? MeasurementTemplateError, .duplicateName("lid"))
+        }
+    }
+
+    // MARK: - Run
+
+    func testGuidedPicks() throws {
+        var run = TemplateRun(template: try MeasurementTemplate.parse(name: "Bracket", definition: definition))
+        XCTAssertEqual(run.prompt, "hole1: Click 3 points around the rim (0 / 3)")
+
+        XCTAssertFalse(run.addPoint(rim(centerX: 0)[0]))
+        XCTAssertEqual(run.prompt, "hole1: Click 3 points around the rim (1 / 3)")
+        run.restartPart()
+        XCTAssertEqual(run.featureIndex, 0)
+
+        XCTAssertTrue(pickPart(&run, secondHoleX: 40.05))
+        let result = run.completePart(name: "a.stl")
+
+        XCTAssertEqual(try XCTUnwrap(result.values[0]), 10, accuracy: 1e-9)
+        XCTAssertEqual(try XCTUnwrap(result.values[2]), 40.05, accuracy: 1e-9)
+        XCTAssertEqual(try XCTUnwrap(result.values[3]), 2, accuracy: 1e-9)
+        XCTAssertTrue(result.passed)
+        XCTAssertEqual(run.featureIndex, 0)
+    }
+
+    func testToleranceAndCSV() throws {
+        var run = TemplateRun(template: try MeasurementTemplate.parse(name: "Bracket", definition: definition))
+        _ = pickPart(&run, secondHoleX: 40.05)
+        run.completePart(name: "a.stl")
+        _ = pickPart(&run, secondHoleX: 40.2)
+        let failed = run.completePart(name: "b.stl")
+        XCTAssertEqual(failed.failures, [2])
+
+        XCTAssertEqual(run.csv(), """
+            part,hole1 Ø (mm),hole2 Ø (mm),pitch (mm),height (mm),result
+            a.stl,10.0000,10.0000,40.0500,2.0000,pass
+            b.stl,10.0000,10.0000,40.2000,2.0000,fail
+
+            """)
+    }
+
+    func testSkippedFeatureLeavesColumnsEmpty() throws {
+        var run = TemplateRun(template: try MeasurementTemplate.parse(name: "Bracket", definition: definition))
+        XCTAssertFalse(run.skipFeature())
+        rim(centerX: 40).forEach { _ = run.addPoint($0) }
+        XCTAssertTrue(run.skipFeature())
+
+        let result = run.completePart(name: "c.stl")
+        XCTAssertNil(result.values[0])
+        XCTAssertNil(result.values[2])
+        XCTAssertTrue(result.passed)
+    }
+}
@@ -0,0 +1,91 @@
+import XCTest
+@testable import GoSTL
+
+final class MeshDamageTests: XCTestCase {
+    private let sphere = ModelGenerator.sphere(radius: 10, segments: 24)
+
+    func testHolesOpenTheMesh() {
+        let damage = MeshDamage(holes: 3, holeSize: 4)
+
+        let (damaged, report) = damage.apply(to: sphere, seed: 1)
+
+        XCTAssertEqual(report.holes, 3)
+        XCTAssertLessThanOrEqual(report.removedTriangles, 12)
+        XCTAssertGreaterThanOrEqual(report.removedTriangles, 3)
+        XCTAssertEqual(damaged.triangles.count, sphere.triangles.count - report.removedTriangles)
+        XCTAssertGreaterThan(damaged.topology().boundaryEdges, 0)
+    }
+
+    func testFlippedNormalsReverseWinding() {
+        let damage = MeshDamage(flippedNormals: .fraction(0.1))
+
+        let (damaged, report) = damage.apply(to: sphere, seed: 7)
+        let flipped = zip(sphere.triangles, damaged.triangles).filter { $0.0.v2 == $0.1.v3 && $0.0.v3 == $0.1.v2 }
+
+        XCTAssertEqual(report.flippedNormals, Int((Double(sphere.triangles.count) * 0.1).rounded()))
+        XCTAssertEqual(flipped.count, report.flippedNormals)
+        for (original, inverted) in flipped {
+            XCTAssertEqual(inverted.normal, -original.normal)
+        }
+        // Winding alone does not open edges
+        XCTAssertTrue(damaged.topology().isWatertight)
+        XCTAssertLessThan(damaged.analyze().volume, sphere.analyze().volume)
+    }
+
+    func testDuplicatesAndDegenerateTriangles() {
+        let damage = MeshDamage(duplicates: .count(5), degenerate: .count(3))
+
+        let (damaged, report) = damage.apply(to: sphere, seed: 3)
+
+        XCTAssertEqual(report.duplicatedTriangles, 5)
+        XCTAssertEqual(report.degenerateTriangles, 3)
+        XCTAssertEqual(damaged.triangles.count, sphere.triangles.count + 5)
+        XCTAssertEqual(damaged.triangles.filter { $0.area() < 1e-12 }.count, 3)
+        XCTAssertGreaterThan(damaged.topology().nonManifoldEdges, 0)
+    }
+
+    func testNoiseMovesSharedVerticesTogether() {
+        let damage = MeshDamage(noise: 0.2)
+
+        let (damaged, report) = damage.apply(to: sphere, seed: 11)
+
+        XCTAssertEqual(report.movedVertices, Set(sphere.triangles.flatMap { [$0.v1, $0.v2, $0.v3] }).count)
+        XCTAssertTrue(damaged.topology().isWatertight)
+        for (original, moved) in zip(sphere.triangles, damaged.triangles) {
+            XCTAssertLessThanOrEqual(abs(moved.v1.x - original.v1.x), 0.2)
+            XCTAssertLessThanOrEqual(abs(moved.v1.z - original.v1.z), 0.2)
+        }
+    }
+
+    func testSameSeedSameDamage() {
+        let damage = MeshDamage(holes: 2, flippedNormals: .fraction(0.05), noise: 0.1)
+
+        let first = damage.apply(to: sphere, seed: 42)
+        let second = damage.apply(to: sphere, seed: 42)
+        let other = damage.apply(to: sphere, seed: 43)
+
+        XCTAssertEqual(first.report, second.report)
+        XCTAssertEqual(first.model.triangles.map(\.v1), second.model.triangles.map(\.v1))
+        XCTAssertNotEqual(first.model.triangles.map(\.v1), other.model.triangles.map(\.v1))
+    }
+
+    func testAmounts() {
+        XCTAssertEqual(DamageAmount("2%"), .fraction(0.02))
+        XCTAssertEqual(DamageAmount("10"), .count(10))
+        XCTAssertNil(DamageAmount("-1"))
+        XCTAssertNil(DamageAmount("150%"))
+        XCTAssertEqual(DamageAmount.fraction(0.02).resolve(of: 1000), 20)
+        XCTAssertEqual(DamageAmount.count(10).resolve(of: 4), 4)
+        XCTAssertTrue(MeshDamage().isEmpty)
+    }
+
+    func testCommandRequiresADefect() {
+        XCTAssertThrowsError(try FuzzCommand.parse(["model.stl"]))
+        XCTAssertThrowsError(try FuzzCommand.parse(["model.stl", "--flip-normals", "abc"]))
+        XCTAssertNoThrow(try FuzzCommand.parse(["model.stl", "--holes", "5", "--flip-normals", "2%", "--out", "damaged.stl"]))
+        XCTAssertEqual(
+            FuzzCommand.summary(MeshDamage.Report(holes: 1, removedTriangles: 4, flippedNormals: 2)),
+            ["Punched 1 hole (4 triangles removed)", "Flipped 2 normals"]
+        )
+    }
+}
@@ -0,0 +1,122 @@
+import XCTest
+@testable import GoSTL
+
+final class MeshQualityTests: XCTestCase {
+    private let sphere = ModelGenerator.sphere(radius: 10, segments: 24)
+    private let cube = ModelGenerator.cube(size: Vector3(10, 10, 10))
+
+    private func invertedIndices(_ original: STLModel, _ damaged: STLModel) -> [Int] {
+        zip(original.triangles, damaged.triangles).enumerated()
+            .filter { $0.element.0.v2 == $0.element.1.v3 && $0.element.0.v3 == $0.element.1.v2 }
+            .map(\.offset)
+    }
+
+    // MARK: - Checks
+
+    func testGeneratedModelsAreClean() throws {
+        for model in [sphere, cube] {
+            let quality = try MeshQuality.check(model)
+            XCTAssertTrue(quality.isClean, quality.summary)
+            XCTAssertEqual(quality.triangleCount, model.triangles.count)
+        }
+    }
+
+    func testFindsFlippedTriangles() throws {
+        let (damaged, report) = MeshDamage(flippedNormals: .fraction(0.1)).apply(to: sphere, seed: 7)
+
+        let quality = try MeshQuality.check(damaged)
+
+        XCTAssertEqual(quality.flippedTriangles.count, report.flippedNormals)
+        XCTAssertEqual(quality.flippedTriangles, invertedIndices(sphere, damaged))
+        XCTAssertFalse(quality.isInsideOut)
+        XCTAssertEqual(quality.issues, [.flippedTriangles])
+    }
+
+    func testFindsInsideOutModel() throws {
+        let inverted = STLModel(triangles: sphere.triangles.map { Triangle(v1: $0.v1, v2: $0.v3, v3: $0.v2, normal: -$0.normal) })
+
+        let quality = try MeshQuality.check(inverted)
+
+        XCTAssertTrue(quality.isInsideOut)
+        XCTAssertTrue(quality.flippedTriangles.isEmpty)
+        XCTAssertEqual(quality.message(for: .insideOut), "Inside out")
+    }
+
+    func testOpenMeshIsNotInsideOut() throws {
+        let (damaged, _) = MeshDamage(holes: 2, holeSize: 3).apply(to: sphere, seed: 1)
+
+        let quality = try MeshQuality.check(damaged)
+
+        XCTAssertGreaterThan(quality.count(of: .openEdges), 0)
+        XCTAssertFalse(quality.isInsideOut)
+        XCTAssertFalse(MeshQuality.Issue.openEdges.isRepairable)
+    }
+
+    func testFindsDegenerateAndSliverTriangles() throws {
+        var triangles = cube.triangles
+        triangles.append(Triangle(v1: Vector3(0, 0, 20), v2: Vector3(100, 0, 20), v3: Vector3(50, 0.01, 20)))
+        triangles.append(Triangle(v1: Vector3(0, 0, 30), v2: Vector3(1, 0, 30), v3: Vector3(2, 0, 30)))
+
+        let quality = try MeshQuality.check(STLModel(triangles: triangles))
+
+        XCTAssertEqual(quality.sliverTriangles, [cube.triangles.count])
+        XCTAssertEqual(quality.degenerateTriangles, [cube.triangles.count + 1])
+        XCTAssertEqual(quality.triangles(of: .slivers), [cube.triangles.count])
+    }
+
+    func testCountsNearDuplicateVertices() {
+        let triangle = Triangle(v1: Vector3(0.0002, 0, 0), v2: Vector3(0.0004, 0, 0), v3: Vector3(5, 5, 0))
+
+        XCTAssertEqual(MeshQuality.nearDuplicateVertices(in: [triangle], tolerance: 0.001), 1)
+        XCTAssertEqual(MeshQuality.nearDuplicateVertices(in: [triangle], tolerance: 0.0001), 0)
+    }
+
+    func testSummary() {
+        var quality = MeshQuality()
+        XCTAssertEqual(quality.summary, "No mesh problems found")
+
+        quality.topology = MeshTopology(boundaryEdges: 12, nonManifoldEdges: 0)
+        quality.isInsideOut = true
+        XCTAssertEqual(quality.summary, "2 mesh problems: 12 open edges, inside out")
+    }
+
+    // MARK: - Repair
+
+    func testRepairTurnsFlippedTrianglesAround() throws {
+        let (damaged, _) = MeshDamage(flippedNormals: .fraction(0.2)).apply(to: sphere, seed: 3)
+
+        let repaired = MeshQuality.repaired(damaged)
+
+        XCTAssertTrue(try MeshQuality.check(repaired).isClean)
+        XCTAssertEqual(repaired.volume(), sphere.volume(), accuracy: 1e-6)
+    }
+
+    func testRepairTurnsInsideOutModel() throws {
+        let inverted = STLModel(triangles: sphere.triangles.map { Triangle(v1: $0.v1, v2: $0.v3, v3: $0.v2, normal: -$0.normal) })
+
+        let repaired = MeshQuality.repaired(inverted)
+
+        XCTAssertTrue(try MeshQuality.check(repaired).isClean)
+        for (original, fixed) in zip(sphere.triangles, repaired.triangles) {
+            XCTAssertEqual(fixed.normal.dot(original.normal), 1, accuracy: 1e-9)
+        }
+    }
+
+    func testRepairWeldsAndRemovesDegenerateTriangles() throws {
+        var triangles = cube.triangles
+        // Cube corners are at x = ±5, well inside their weld cells
+        triangles[0].v1 = triangles[0].v1 + Vector3(1e-7, 0, 0)
+        triangles.append(Triangle(v1: Vector3(0, 0, 30), v2: Vector3(1, 0, 30), v3: Vector3(2, 0, 30)))
+        let damaged = STLModel(triangles: triangles)
+
+        let before = try MeshQuality.check(damaged)
+        XCTAssertEqual(before.nearDuplicateVertices, 1)
+        XCTAssertGreaterThan(before.count(of: .openEdges), 0)
+
+        let repaired = MeshQuality.repaired(damaged)
+
+        XCTAssertEqual(repaired.triangles.count, cube.triangles.count)
+        XCTAssertTrue(repaired.topology().isWatertight)
+        XCTAssertTrue(try MeshQuality.check(repaired).isClean)
+    }
+}
@@ -0,0 +1,64 @@
+import XCTest
+@testable import GoSTL
+
+final class MeshValidationTests: XCTestCase {
+    private func makeBox(size: Vector3, origin: Vector3 = Vector3(0, 0, 0)) -> STLModel {
+        STLModel(triangles: TestModels.box(from: origin, to: origin + size), name: "box")
+    }
+
+    // MARK: - Topology Tests
+
+    func testClosedBoxIsWatertight() {
+        let topology = makeBox(size: Vector3(10, 10, 10)).topology()
+
+        XCTAssertEqual(topology, MeshTopology(boundaryEdges: 0, nonManifoldEdges: 0))
+        XCTAssertTrue(topology.isWatertight)
+    }
+
+    func testMissingFaceLeavesOpenEdges() {
+        var box = makeBox(size: Vector3(10, 10, 10))
+        box.triangles.removeLast(2)
+
+        let topology = box.topology()
+
+        XCTAssertEqual(topology.boundaryEdges, 4)
+        XCTAssertFalse(topology.isWatertight)
+    }
+
+    // MARK: - Wall Thickness Tests
+
+    func testWallThicknessOfPlate() throws {
+        let wall = try XCTUnwrap(makeBox(size: Vector3(50, 30, 1.5)).minimumWallThickness())
+
+        XCTAssertEqual(wall.thickness, 1.5, accuracy: 1e-3)
+    }
+
+    func testWallThicknessFarFromOrigin() throws {
+        let wall = try XCTUnwrap(makeBox(size: Vector3(20, 2, 20), origin: Vector3(500_000, 200_000, 0)).minimumWallThickness())
+
+        XCTAssertEqual(wall.thickness, 2, accuracy: 1e-3)
+        XCTAssertGreaterThan(wall.location.x, 400_000)
+    }
+
+    // MARK: - Condition Tests
+
+    func testConditionsReportFailures() {
+        let box = makeBox(size: Vector3(210, 100, 1))
+
+        let results = ModelConditionEvaluator.evaluate(
+            [.maxSize(axis: 0, limit: 200), .maxTriangles(500_000), .watertight, .minWallThickness(1.2)],
+            on: box
+        )
+
+        XCTAssertEqual(results.map(\.check), ["max-x", "max-triangles", "watertight", "min-wall"])
+        XCTAssertEqual(results.map(\.passed), [false, true, true, false])
+        XCTAssertEqual(results[0].actual, "210.000")
+        XCTAssertEqual(results[0].expected, "<= 200.000")
+    }
+
+    func testAssertOptionsBuildConditions() throws {
+        let command = try AssertCommand.parse(["part.stl", "--max-x", "200", "--watertight", "--min-wall", "1.2", "--max-triangles", "500000"])
+
+        XCTAssertEqual(command.conditions, [.maxSize(axis: 0, limit: 200), .maxTriangles(500_000), .watertight, .minWallThickness(1.2)])
+    }
+}
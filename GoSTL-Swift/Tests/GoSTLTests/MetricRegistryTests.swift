@@ -0,0 +1,82 @@
+import XCTest
+@testable import GoSTL
+
+final class MetricRegistryTests: XCTestCase {
+    // MARK: - Registry Tests
+
+    func testEveryAnalysisFieldIsRegistered() {
+        for key in AnalysisRecord.fieldKeys {
+            XCTAssertNotNil(MetricRegistry.metric(for: key), "missing metric \(key)")
+        }
+    }
+
+    func testKeysAreUnique() {
+        let keys = MetricRegistry.all.map(\.key)
+        XCTAssertEqual(Set(keys).count, keys.count)
+    }
+
+    func testSeeAlsoReferencesExist() {
+        for metric in MetricRegistry.all {
+            for key in metric.seeAlso {
+                XCTAssertNotNil(MetricRegistry.metric(for: key), "\(metric.key) refers to unknown \(key)")
+            }
+        }
+    }
+
+    func testAssertConditionsAreDocumented() {
+        let conditions: [ModelCondition] = [
+            .maxSize(axis: 0, limit: 1), .minSize(axis: 2, limit: 1), .maxTriangles(1),
+            .maxVolume(1), .minVolume(1), .watertight, .minWallThickness(1), .stable, .minTiltAngle(1)
+        ]
+        for condition in conditions {
+            XCTAssertNotNil(MetricRegistry.metric(forAssertOption: condition.name), "undocumented \(condition.name)")
+        }
+    }
+
+    func testAnalysisRecordTakesLabelsFromRegistry() throws {
+        let record = AnalysisRecord(fileName: "part.stl", analysis: STLModel().analyze())
+        let volume = try XCTUnwrap(record.fields.first { $0.key == "volume" })
+
+        XCTAssertEqual(volume.label, "Volume")
+        XCTAssertEqual(volume.unit, "mm³")
+    }
+
+    // MARK: - Explain Tests
+
+    func testResolveAcceptsKeysOptionsAndSearchTerms() throws {
+        XCTAssertEqual(try ExplainCommand.resolve("watertight").key, "watertight")
+        XCTAssertEqual(try ExplainCommand.resolve("--max-z").key, "dimensions.z")
+        XCTAssertEqual(try ExplainCommand.resolve("min-wall").key, "wall_thickness.min")
+        XCTAssertEqual(try ExplainCommand.resolve("surface").key, "surface_area")
+    }
+
+    func testResolveRejectsUnknownAndAmbiguousTerms() {
+        XCTAssertThrowsError(try ExplainCommand.resolve("sparkle"))
+        XCTAssertThrowsError(try ExplainCommand.resolve("edge_length"))
+    }
+
+    func testPageShowsUnitChecksAndSeeAlso() throws {
+        let page = ExplainCommand.page(for: try XCTUnwrap(MetricRegistry.metric(for: "volume")))
+
+        XCTAssertTrue(page.hasPrefix("NAME\n    volume - "))
+        XCTAssertTrue(page.contains("UNIT\n    mm³"))
+        XCTAssertTrue(page.contains("gostl assert --max-volume"))
+        XCTAssertTrue(page.contains("gostl analyze --select volume"))
+        XCTAssertTrue(page.contains("SEE ALSO\n    watertight, weight.pla_100"))
+    }
+
+    func testIndexListsAllMetrics() {
+        let lines = ExplainCommand.index().split(separator: "\n")
+        XCTAssertEqual(lines.count, MetricRegistry.all.count)
+    }
+
+    // MARK: - Report Tests
+
+    func testInspectionReportIncludesModelSection() {
+        let record = AnalysisRecord(fileName: "part.stl", analysis: STLModel().analyze())
+        let report = InspectionReport.generate(fileName: "part.stl", referenceGeometry: ReferenceGeometrySystem(), analysis: record)
+
+        XCTAssertTrue(report.contains("MODEL\n  Triangles: 0\n"))
+        XCTAssertTrue(report.contains("  Volume: 0.000 mm³"))
+    }
+}
@@ -0,0 +1,177 @@
+import XCTest
+@testable import GoSTL
+
+final class ModelAlignmentTests: XCTestCase {
+
+    private func rotated(_ model: STLModel, axis: Vector3, degrees: Double) -> STLModel {
+        var result = STLModel(triangles: model.triangles, name: model.name)
+        Rotation.rotateModel(&result, axis: axis.normalized(), angle: degrees * .pi / 180, center: .zero)
+        return result
+    }
+
+    /// 40 x 20 x 5 mm plate
+    private func plate() -> STLModel {
+        STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(40, 20, 5)), name: "plate")
+    }
+
+    // MARK: - Principal Axes Tests
+
+    func testPrincipalAxesOfPlate() throws {
+        let axes = try XCTUnwrap(ModelAlignment.principalAxes(of: plate().triangles))
+
+        XCTAssertEqual(abs(axes.axes[0].x), 1, accuracy: 1e-9)
+        XCTAssertEqual(abs(axes.axes[1].y), 1, accuracy: 1e-9)
+        XCTAssertEqual(abs(axes.axes[2].z), 1, accuracy: 1e-9)
+        XCTAssertEqual(axes.centroid.x, 20, accuracy: 1e-9)
+        XCTAssertGreaterThan(axes.variances[0], axes.variances[1])
+        XCTAssertGreaterThan(axes.variances[1], axes.variances[2])
+    }
+
+    func testAlignRotatedPlate() throws {
+        let tilted = rotated(plate(), axis: Vector3(1, 2, 3), degrees: 37)
+
+        let aligned = try XCTUnwrap(ModelAlignment.alignedToPrincipalAxes(tilted))
+        let size = aligned.boundingBox().size
+
+        XCTAssertEqual(size.x, 40, accuracy: 1e-6)
+        XCTAssertEqual(size.y, 20, accuracy: 1e-6)
+        XCTAssertEqual(size.z, 5, accuracy: 1e-6)
+        XCTAssertEqual(aligned.boundingBox().center.distance(to: tilted.boundingBox().center), 0, accuracy: 1e-6)
+    }
+
+    func testAlignSwapsAxesByExtent() throws {
+        let upright = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(5, 20, 40)))
+
+        let size = try XCTUnwrap(ModelAlignment.alignedToPrincipalAxes(upright)).boundingBox().size
+
+        XCTAssertEqual(size.x, 40, accuracy: 1e-9)
+        XCTAssertEqual(size.y, 20, accuracy: 1e-9)
+        XCTAssertEqual(size.z, 5, accuracy: 1e-9)
+    }
+
+    func testAlignedModelStaysPut() throws {
+        let model = plate()
+
+        let aligned = try XCTUnwrap(ModelAlignment.alignedToPrincipalAxes(model))
+
+        for (original, result) in zip(model.triangles, aligned.triangles) {
+            XCTAssertTrue(original.v1.isApproximatelyEqual(to: result.v1, tolerance: 1e-9))
+        }
+    }
+
+    func testEmptyModelHasNoAxes() {
+        XCTAssertNil(ModelAlignment.principalAxes(of: []))
+    }
+
+    func testSymmetricEigen() {
+        let matrix = simd_double3x3(rows: [SIMD3(2, 1, 0), SIMD3(1, 2, 0), SIMD3(0, 0, 5)])
+
+        let (values, vectors) = ModelAlignment.symmetricEigen(matrix)
+
+        XCTAssertEqual(values.sorted()[0], 1, accuracy: 1e-12)
+        XCTAssertEqual(values.sorted()[1], 3, accuracy: 1e-12)
+        XCTAssertEqual(values.sorted()[2], 5, accuracy: 1e-12)
+        for (value, vector) in zip(values, vectors) {
+            XCTAssertLessThan(simd_length(matrix * vector - value * vector), 1e-12)
+        }
+    }
+
+    // MARK: - Planar Face Tests
+
+    func testPlanarFacesOfPlate() {
+        let faces = ModelAlignment.planarFaces(of: plate().triangles)
+
+        XCTAssertEqual(faces.count, 6)
+        XCTAssertEqual(faces[0].area, 800, accuracy: 1e-9)
+        XCTAssertEqual(faces[0].triangles.count, 2)
+        XCTAssertEqual(abs(faces[0].normal.z), 1, accuracy: 1e-9)
+    }
+
+    func testLargestSupportingFaceSkipsInnerFaces() throws {
+        // Cup: 10 x 10 foot under a 16 x 16 bowl; the bowl's bottom faces are larger but walled in
+        let foot = TestModels.box(from: Vector3(3, 3, 0), to: Vector3(13, 13, 2))
+        let bowlBottom = TestModels.box(from: Vector3(0, 0, 2), to: Vector3(16, 16, 3))
+        let walls = TestModels.box(from: Vector3(0, 0, 3), to: Vector3(16, 1, 8))
+            + TestModels.box(from: Vector3(0, 15, 3), to: Vector3(16, 16, 8))
+            + TestModels.box(from: Vector3(0, 1, 3), to: Vector3(1, 15, 8))
+            + TestModels.box(from: Vector3(15, 1, 3), to: Vector3(16, 15, 8))
+        let model = STLModel(triangles: foot + bowlBottom + walls)
+
+        let face = try XCTUnwrap(ModelAlignment.largestSupportingFace(of: model))
+
+        XCTAssertEqual(face.normal.z, -1, accuracy: 1e-9)
+        XCTAssertEqual(face.offset, 0, accuracy: 1e-9)  // -z at z = 0
+        XCTAssertEqual(face.area, 100, accuracy: 1e-9)
+    }
+
+    func testFaceContainingTriangle() throws {
+        let triangles = plate().triangles
+
+        // Triangles 6 and 7 form the 20 x 5 mm side at x = 40
+        let face = try XCTUnwrap(ModelAlignment.face(containing: 6, in: triangles))
+
+        XCTAssertEqual(Set(face.triangles), [6, 7])
+        XCTAssertEqual(face.area, 100, accuracy: 1e-9)
+        XCTAssertEqual(face.normal.x, 1, accuracy: 1e-9)
+        XCTAssertEqual(face.offset, 40, accuracy: 1e-9)
+        XCTAssertNil(ModelAlignment.face(containing: 99, in: triangles))
+    }
+
+    func testFittedPlaneNormalOfUnevenPatch() throws {
+        // Slightly rippled 10 x 10 patch facing up
+        var triangles: [Triangle] = []
+        for i in 0..<10 {
+            for j in 0..<10 {
+                let z = { (x: Int, y: Int) in (x + y) % 2 == 0 ? 0.0 : 0.05 }
+                let a = Vector3(Double(i), Double(j), z(i, j)), b = Vector3(Double(i + 1), Double(j), z(i + 1, j))
+                let c = Vector3(Double(i + 1), Double(j + 1), z(i + 1, j + 1)), d = Vector3(Double(i), Double(j + 1), z(i, j + 1))
+                triangles.append(Triangle(v1: a, v2: b, v3: c))
+                triangles.append(Triangle(v1: a, v2: c, v3: d))
+            }
+        }
+
+        let normal = try XCTUnwrap(ModelAlignment.fittedPlaneNormal(of: triangles))
+
+        XCTAssertEqual(normal.z, 1, accuracy: 1e-4)
+    }
+
+    func testFittedPlaneNormalFacesOutward() throws {
+        let bottom = Array(plate().triangles[0...1])
+
+        let normal = try XCTUnwrap(ModelAlignment.fittedPlaneNormal(of: bottom))
+
+        XCTAssertEqual(normal.z, -1, accuracy: 1e-9)
+    }
+
+    // MARK: - Lay Flat Tests
+
+    func testLayFlatOnSide() {
+        let model = plate()
+
+        // Lay the plate on its 40 x 5 side (normal -Y)
+        let flat = ModelAlignment.laidFlat(model, faceNormal: Vector3(0, -1, 0), floorZ: 0)
+        let bounds = flat.boundingBox()
+
+        XCTAssertEqual(bounds.min.z, 0, accuracy: 1e-9)
+        XCTAssertEqual(bounds.size.z, 20, accuracy: 1e-9)
+        XCTAssertEqual(bounds.size.x, 40, accuracy: 1e-9)
+    }
+
+    func testLayFlatTurnsUpwardFaceOver() {
+        let flat = ModelAlignment.laidFlat(plate(), faceNormal: Vector3(0, 0, 1), floorZ: -10)
+
+        XCTAssertEqual(flat.boundingBox().min.z, -10, accuracy: 1e-9)
+        XCTAssertEqual(flat.boundingBox().size.z, 5, accuracy: 1e-9)
+    }
+
+    func testLayFlatOnLargestFaceOfTiltedPlate() throws {
+        let tilted = rotated(plate(), axis: Vector3(1, 1, 0), degrees: 60)
+        let face = try XCTUnwrap(ModelAlignment.largestSupportingFace(of: tilted))
+
+        let flat = ModelAlignment.laidFlat(tilted, faceNormal: face.normal, floorZ: 0)
+
+        XCTAssertEqual(face.area, 800, accuracy: 1e-6)
+        XCTAssertEqual(flat.boundingBox().size.z, 5, accuracy: 1e-6)
+        XCTAssertEqual(flat.boundingBox().min.z, 0, accuracy: 1e-9)
+    }
+}
@@ -0,0 +1,118 @@
+import XCTest
+@testable import GoSTL
+
+final class ModelArchiveTests: XCTestCase {
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-archive-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    private func stl(triangles: Int, offset: Double = 0) -> Data {
+        let model = STLModel(triangles: (0..<triangles).map { index in
+            let x = Double(index) + offset
+            return Triangle(v1: Vector3(x, 0, 0), v2: Vector3(x + 1, 0, 0), v3: Vector3(x, 1, 0))
+        })
+        return STLExporter.binaryData(model: model)
+    }
+
+    private func writeArchive(_ files: [(String, Data)]) throws -> URL {
+        var writer = ZipWriter()
+        for (path, contents) in files {
+            writer.add(path: path, contents: contents)
+        }
+        let url = directory.appendingPathComponent("parts.zip")
+        try writer.finish().write(to: url)
+        return url
+    }
+
+    // MARK: - Listing
+
+    func testListsModelsButNotDependenciesOrJunk() throws {
+        let url = try writeArchive([
+            ("bracket.stl", stl(triangles: 2)),
+            ("case/case.3mf", Data()),
+            ("project/main.scad", Data("use <lib/util.scad>\ncube(util_size());\n".utf8)),
+            ("project/lib/util.scad", Data("function util_size() = 10;\n".utf8)),
+            ("README.md", Data("parts".utf8)),
+            ("__MACOSX/._bracket.stl", Data([0, 1])),
+            (".hidden/ghost.stl", stl(triangles: 1))
+        ])
+
+        let archive = try ModelArchive.open(url: url)
+        defer { archive.remove() }
+
+        XCTAssertEqual(archive.entries.map(\.path), ["bracket.stl", "case/case.3mf", "project/main.scad"])
+        XCTAssertEqual(archive.entries[2].name, "main.scad")
+        // Dependencies are extracted next to the project so OpenSCAD finds them
+        XCTAssertTrue(FileManager.default.fileExists(atPath: archive.folder.appendingPathComponent("project/lib/util.scad").path))
+    }
+
+    func testArchiveWithoutModelsFails() throws {
+        let url = try writeArchive([("notes.txt", Data("nothing here".utf8))])
+        XCTAssertThrowsError(try ModelArchive.open(url: url))
+    }
+
+    func testUnsafePathsAreRejected() throws {
+        let url = try writeArchive([("../escape.stl", stl(triangles: 1))])
+
+        XCTAssertThrowsError(try ModelArchive.open(url: url)) { error in
+            guard case ModelArchiveError.unsafePath = error else {
+                return XCTFail("Expected unsafePath, got \(error)")
+            }
+        }
+        XCTAssertFalse(FileManager.default.fileExists(atPath: directory.deletingLastPathComponent().appendingPathComponent("escape.stl").path))
+    }
+
+    // MARK: - Loading
+
+    func testLoadSingleEntry() throws {
+        let url = try writeArchive([("a.stl", stl(triangles: 2)), ("sub/b.stl.gz", try FileCompression.gzip(stl(triangles: 3)))])
+        let archive = try ModelArchive.open(url: url)
+        defer { archive.remove() }
+
+        let model = try archive.load(path: "sub/b.stl.gz", name: nil).model
+
+        XCTAssertEqual(model.triangleCount, 3)
+        XCTAssertEqual(model.name, "b")
+    }
+
+    func testAssemblyCombinesAllModels() throws {
+        let url = try writeArchive([("a.stl", stl(triangles: 2)), ("b.stl", stl(triangles: 3, offset: 50))])
+        let archive = try ModelArchive.open(url: url)
+        defer { archive.remove() }
+
+        let assembly = try archive.loadAssembly(name: "parts")
+
+        XCTAssertEqual(assembly.model.triangleCount, 5)
+        XCTAssertEqual(assembly.model.name, "parts")
+        XCTAssertTrue(assembly.warnings.isEmpty)
+        XCTAssertEqual(assembly.model.boundingBox().max.x, 53, accuracy: 1e-6)
+    }
+
+    func testAssemblyReportsBrokenModels() throws {
+        let url = try writeArchive([("a.stl", stl(triangles: 2)), ("broken.stl", Data("x".utf8))])
+        let archive = try ModelArchive.open(url: url)
+        defer { archive.remove() }
+
+        let assembly = try archive.loadAssembly(name: nil)
+
+        XCTAssertEqual(assembly.model.triangleCount, 2)
+        XCTAssertEqual(assembly.warnings.count, 1)
+        XCTAssertTrue(assembly.warnings[0].hasPrefix("broken.stl"))
+    }
+
+    func testLoaderOpensArchiveAsAssembly() throws {
+        let url = try writeArchive([("a.stl", stl(triangles: 2)), ("b.stl", stl(triangles: 1))])
+
+        let model = try ModelFileLoader.loadRendering(url: url)
+
+        XCTAssertEqual(model.triangleCount, 3)
+        XCTAssertEqual(model.name, "parts")
+    }
+}
@@ -0,0 +1,62 @@
+import Metal
+import XCTest
+@testable import GoSTL
+
+final class ModelBufferTests: XCTestCase {
+    private let triangle = STLModel(triangles: [
+        Triangle(v1: Vector3(0, 0, 0), v2: Vector3(10, 0, 0), v3: Vector3(0, 10, 0))
+    ])
+
+    // MARK: - Model Buffer Tests
+
+    func testSwapTakesLatestStagedModel() throws {
+        let device = try XCTUnwrap(MTLCreateSystemDefaultDevice(), "Metal is not available")
+        let buffer = ModelBuffer()
+
+        let first = try buffer.stage(try PreparedModel(fileModel: triangle, device: device))
+        let second = try buffer.stage(try PreparedModel(fileModel: triangle, device: device))
+
+        XCTAssertNil(buffer.swap(first), "an older reload must not replace a newer one")
+        XCTAssertNotNil(buffer.swap(second))
+        XCTAssertNil(buffer.swap(second), "a staged model is swapped in only once")
+    }
+
+    func testCancelledJobDoesNotStage() throws {
+        let device = try XCTUnwrap(MTLCreateSystemDefaultDevice(), "Metal is not available")
+        let buffer = ModelBuffer()
+        let current = try buffer.stage(try PreparedModel(fileModel: triangle, device: device))
+        let job = JobContext()
+        job.cancel()
+
+        XCTAssertThrowsError(try buffer.stage(try PreparedModel(fileModel: triangle, device: device), job: job)) { error in
+            XCTAssertTrue(error is CancellationError)
+        }
+        XCTAssertNotNil(buffer.swap(current), "a cancelled reload must not invalidate the current one")
+    }
+
+    func testPreparedModelKeepsGivenOffset() throws {
+        let device = try XCTUnwrap(MTLCreateSystemDefaultDevice(), "Metal is not available")
+        let offset = Vector3(5, 5, 0)
+
+        let prepared = try PreparedModel(fileModel: triangle, device: device, coordinateOffset: offset)
+
+        XCTAssertEqual(prepared.coordinateOffset, offset)
+        XCTAssertEqual(prepared.model.triangles[0].v1, Vector3(-5, -5, 0))
+        XCTAssertEqual(prepared.meshData.vertexCount, 3)
+    }
+
+    // MARK: - Upload Queue Tests
+
+    @MainActor
+    func testDrainInstallsCurrentGenerationOnly() {
+        let uploads = GPUUploadQueue()
+        var installed: [String] = []
+
+        uploads.enqueue(generation: 1) { installed.append("stale wireframe") }
+        uploads.enqueue(generation: 2) { installed.append("spatial index") }
+
+        XCTAssertEqual(uploads.drain(currentGeneration: 2), 1)
+        XCTAssertEqual(installed, ["spatial index"])
+        XCTAssertTrue(uploads.isEmpty)
+    }
+}
@@ -0,0 +1,104 @@
+import XCTest
+@testable import GoSTL
+
+final class ModelGeneratorTests: XCTestCase {
+    // MARK: - Primitive Tests
+
+    func testPrimitivesAreClosedAndStandOnThePlate() {
+        let models = [
+            ModelGenerator.cube(size: Vector3(30, 20, 10)),
+            ModelGenerator.sphere(radius: 10),
+            ModelGenerator.cylinder(radius: 10, height: 40),
+            ModelGenerator.torus(majorRadius: 15, minorRadius: 5)
+        ]
+        for model in models {
+            let name = model.name ?? ""
+            XCTAssertTrue(model.topology().isWatertight, name)
+            let bounds = model.boundingBox()
+            XCTAssertEqual(bounds.min.z, 0, accuracy: 1e-9, name)
+            XCTAssertEqual(bounds.center.x, 0, accuracy: 1e-9, name)
+            XCTAssertEqual(bounds.center.y, 0, accuracy: 1e-9, name)
+            // Outward-facing: positive volume
+            XCTAssertGreaterThan(model.analyze().volume, 0, name)
+        }
+    }
+
+    func testVolumesApproachTheExactShapes() {
+        XCTAssertEqual(ModelGenerator.cube(size: Vector3(30, 20, 10)).analyze().volume, 6000, accuracy: 1e-6)
+        XCTAssertEqual(ModelGenerator.sphere(radius: 10).analyze().volume, 4 / 3 * .pi * 1000, accuracy: 4188.79 * 0.01)
+        XCTAssertEqual(ModelGenerator.cylinder(radius: 10, height: 40).analyze().volume, .pi * 100 * 40, accuracy: 12566.4 * 0.01)
+        XCTAssertEqual(ModelGenerator.torus(majorRadius: 15, minorRadius: 5).analyze().volume, 2 * .pi * .pi * 15 * 25, accuracy: 7402.2 * 0.01)
+    }
+
+    func testSegmentsFollowTheChordTolerance() {
+        XCTAssertEqual(ModelGenerator.segments(for: 10) % 4, 0)
+        XCTAssertGreaterThan(ModelGenerator.segments(for: 50), ModelGenerator.segments(for: 5))
+        XCTAssertEqual(ModelGenerator.segments(for: 0.01), 12)
+        XCTAssertEqual(ModelGenerator.segments(for: 10_000), 360)
+        XCTAssertEqual(ModelGenerator.cylinder(radius: 10, height: 1, segments: 6).triangles.count, 24)
+    }
+
+    // MARK: - Calibration Tests
+
+    func testCalibrationCubeHasEmbossedLetters() throws {
+        let plain = ModelGenerator.cube(size: Vector3(20, 20, 20))
+        let cube = try ModelGenerator.calibrationCube(size: 20)
+        let bounds = cube.boundingBox()
+
+        XCTAssertGreaterThan(cube.triangles.count, plain.triangles.count)
+        // Letters stand out of the front, right and top faces only
+        XCTAssertEqual(bounds.min.y, -10 - ModelGenerator.reliefDepth, accuracy: 1e-9)
+        XCTAssertEqual(bounds.max.x, 10 + ModelGenerator.reliefDepth, accuracy: 1e-9)
+        XCTAssertEqual(bounds.max.z, 20 + ModelGenerator.reliefDepth, accuracy: 1e-9)
+        XCTAssertEqual(bounds.min.x, -10, accuracy: 1e-9)
+
+        let labeled = try ModelGenerator.calibrationCube(size: 20, text: "pla")
+        XCTAssertEqual(labeled.boundingBox().min.x, -10 - ModelGenerator.reliefDepth, accuracy: 1e-9)
+    }
+
+    func testCalibrationTextMustFit() {
+        XCTAssertThrowsError(try ModelGenerator.calibrationCube(size: 20, text: "PLA @ 210°")) { error in
+            XCTAssertEqual(error.localizedDescription, "Cannot emboss @°; use letters, digits, space and . - + / %")
+        }
+        XCTAssertThrowsError(try ModelGenerator.calibrationCube(size: 20, text: "PETG 0.4 NOZZLE")) { error in
+            XCTAssertEqual(error.localizedDescription, "\"PETG 0.4 NOZZLE\" does not fit; use at most 7 characters or a larger model")
+        }
+    }
+
+    func testToleranceGaugeHasOneHolePerClearance() throws {
+        let gauge = try ModelGenerator.toleranceGauge(pinDiameter: 5, clearances: [0.1, 0.2, 0.3])
+        let cell = 5 + 0.3 + 2 * 2.4
+
+        XCTAssertEqual(gauge.boundingBox().size.x, 3 * cell, accuracy: 1e-9)
+        XCTAssertEqual(ModelGenerator.formatClearance(0.1), "0.1")
+        XCTAssertEqual(ModelGenerator.formatClearance(0.25), "0.25")
+        XCTAssertEqual(ModelGenerator.formatClearance(0), "0.0")
+        XCTAssertThrowsError(try ModelGenerator.toleranceGauge(clearances: []))
+    }
+
+    func testHoleBlockIsClosed() {
+        let block = STLModel(triangles: ModelGenerator.squareWithHole(center: Vector3(0, 0, 0), side: 10, radius: 2.6, height: 4))
+
+        XCTAssertTrue(block.topology().isWatertight)
+        XCTAssertEqual(block.analyze().volume, (100 - .pi * 2.6 * 2.6) * 4, accuracy: 0.5)
+        XCTAssertEqual(block.boundingBox().size.x, 10, accuracy: 1e-9)
+    }
+
+    func testBlockFont() {
+        XCTAssertEqual(BlockFont.glyphs.values.filter { $0.count != BlockFont.rows || $0.contains { $0.count != BlockFont.columns } }.count, 0)
+        XCTAssertEqual(BlockFont.width(of: "0.1"), 17)
+        XCTAssertEqual(BlockFont.unsupportedCharacters(in: "abc!"), "!")
+        XCTAssertNil(BlockFont.pixelSize(for: "TOO LONG FOR THIS", width: 10, height: 10))
+    }
+
+    func testCommandValidatesOptionsPerShape() throws {
+        XCTAssertNoThrow(try GenerateCommand.parse(["cylinder", "--r", "10", "--h", "40"]))
+        XCTAssertThrowsError(try GenerateCommand.parse(["sphere", "--h", "40"]))
+        XCTAssertThrowsError(try GenerateCommand.parse(["torus", "--r", "5", "--major-r", "4"]))
+        XCTAssertThrowsError(try GenerateCommand.parse(["calibration-cube", "--size", "20x10x10"]))
+        XCTAssertThrowsError(try GenerateCommand.parse(["tolerance-gauge", "--clearances", "0.1,-0.2"]))
+        XCTAssertEqual(GenerateCommand.parseSize("30x20x10"), Vector3(30, 20, 10))
+        XCTAssertEqual(GenerateCommand.parseSize("20"), Vector3(20, 20, 20))
+        XCTAssertNil(GenerateCommand.parseSize("20x10"))
+    }
+}
@@ -0,0 +1,110 @@
+import XCTest
+@testable import GoSTL
+
+final class ModelManifestTests: XCTestCase {
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-manifest-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: directory.appendingPathComponent("brackets"), withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    private func tetrahedron(size: Double) -> STLModel {
+        let a = Vector3(0, 0, 0), b = Vector3(size, 0, 0), c = Vector3(0, size, 0), d = Vector3(0, 0, size)
+        return STLModel(triangles: [
+            Triangle(v1: a, v2: c, v3: b),
+            Triangle(v1: a, v2: b, v3: d),
+            Triangle(v1: a, v2: d, v3: c),
+            Triangle(v1: b, v2: c, v3: d)
+        ], name: "tetra")
+    }
+
+    private func write(_ model: STLModel, to path: String) throws {
+        try STLExporter.exportBinary(model: model, to: directory.appendingPathComponent(path))
+    }
+
+    // MARK: - Creation
+
+    func testCreateListsModelsRecursivelyWithFigures() throws {
+        try write(tetrahedron(size: 10), to: "a.stl")
+        try write(tetrahedron(size: 20), to: "brackets/b.stl")
+        try Data("notes".utf8).write(to: directory.appendingPathComponent("README.txt"))
+
+        let manifest = ModelManifest.create(folder: directory)
+
+        XCTAssertEqual(manifest.entries.map(\.path), ["a.stl", "brackets/b.stl"])
+        let entry = manifest.entries[1]
+        XCTAssertEqual(entry.triangleCount, 4)
+        XCTAssertEqual(entry.dimensions, Vector3(20, 20, 20))
+        XCTAssertEqual(entry.volume, 8000.0 / 6, accuracy: 1e-6)
+        XCTAssertEqual(entry.sha256.count, 64)
+    }
+
+    func testManifestRoundTripsThroughJSON() throws {
+        try write(tetrahedron(size: 10), to: "a.stl")
+        let manifest = ModelManifest.create(folder: directory)
+
+        let decoded = try ModelManifest.decode(manifest.encoded())
+
+        XCTAssertEqual(decoded.entries, manifest.entries)
+        XCTAssertEqual(decoded.version, ModelManifest.currentVersion)
+    }
+
+    // MARK: - Verification
+
+    func testUnchangedLibraryVerifies() throws {
+        try write(tetrahedron(size: 10), to: "a.stl")
+        let manifest = ModelManifest.create(folder: directory)
+
+        let differences = manifest.verify(folder: directory)
+
+        XCTAssertEqual(differences.map(\.status), [.unchanged])
+        XCTAssertFalse(differences.contains { $0.status.isFailure })
+    }
+
+    func testHeaderOnlyChangeIsMetadata() throws {
+        var model = tetrahedron(size: 10)
+        try write(model, to: "a.stl")
+        let manifest = ModelManifest.create(folder: directory)
+
+        model.header = Data(repeating: 0x41, count: 80)
+        try write(model, to: "a.stl")
+
+        XCTAssertEqual(manifest.verify(folder: directory).map(\.status), [.metadataChanged])
+    }
+
+    func testGeometryChangeMissingAndAddedFilesFail() throws {
+        try write(tetrahedron(size: 10), to: "a.stl")
+        try write(tetrahedron(size: 10), to: "brackets/b.stl")
+        let manifest = ModelManifest.create(folder: directory)
+
+        try write(tetrahedron(size: 12), to: "a.stl")
+        try FileManager.default.removeItem(at: directory.appendingPathComponent("brackets/b.stl"))
+        try write(tetrahedron(size: 10), to: "c.stl")
+
+        let differences = manifest.verify(folder: directory)
+
+        XCTAssertEqual(differences.map(\.path), ["a.stl", "brackets/b.stl", "c.stl"])
+        XCTAssertEqual(differences.map(\.status), [.geometryChanged, .missing, .added])
+        XCTAssertTrue(differences.allSatisfy { $0.status.isFailure })
+        XCTAssertTrue(differences[0].details.contains { $0.hasPrefix("dimensions") })
+        XCTAssertFalse(differences[0].details.contains { $0.hasPrefix("triangles") })
+    }
+
+    func testGeometryHashIgnoresNormals() {
+        let model = tetrahedron(size: 10)
+        var flipped = model
+        flipped.triangles = model.triangles.map { triangle in
+            var copy = triangle
+            copy.normal = -triangle.normal
+            return copy
+        }
+
+        XCTAssertEqual(ModelManifest.geometryHash(of: model), ModelManifest.geometryHash(of: flipped))
+        XCTAssertNotEqual(ModelManifest.geometryHash(of: model), ModelManifest.geometryHash(of: tetrahedron(size: 11)))
+    }
+}
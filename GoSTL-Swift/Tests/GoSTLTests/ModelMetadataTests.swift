@@ -0,0 +1,102 @@
+import XCTest
+@testable import GoSTL
+
+final class ModelMetadataTests: XCTestCase {
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-tests-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    // MARK: - STL Header Tests
+
+    func testBinaryHeaderTextIsTrimmed() {
+        var header = Data("Exported by FreeCAD  ".utf8)
+        header.append(Data(repeating: 0, count: 80 - header.count))
+        header.append(Data(repeating: 0xFF, count: 4))
+
+        let entry = ModelMetadata.stlHeader(in: header)
+
+        XCTAssertEqual(entry, ModelMetadata.Entry(label: "STL Header", value: "Exported by FreeCAD"))
+    }
+
+    func testASCIISolidName() {
+        let entry = ModelMetadata.stlHeader(in: Data("solid bracket\n  facet normal 0 0 1\n".utf8))
+
+        XCTAssertEqual(entry, ModelMetadata.Entry(label: "Solid Name", value: "bracket"))
+    }
+
+    func testEmptyHeaderHasNoEntry() {
+        XCTAssertNil(ModelMetadata.stlHeader(in: Data(repeating: 0, count: 84)))
+    }
+
+    // MARK: - 3MF Tests
+
+    func testThreeMFMetadata() {
+        var zip = ZipWriter()
+        zip.add(path: ModelMetadata.threeMFModelPath, contents: Data("""
+            <?xml version="1.0" encoding="UTF-8"?>
+            <model unit="millimeter" xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02">
+              <metadata name="Title">Bracket &amp; Clip</metadata>
+              <metadata name="Designer">Jane</metadata>
+              <metadata name="Description"></metadata>
+              <resources><object id="1"><metadata name="Ignored">x</metadata></object></resources>
+            </model>
+            """.utf8))
+
+        let entries = ModelMetadata.threeMFMetadata(in: zip.finish())
+
+        XCTAssertEqual(entries, [
+            ModelMetadata.Entry(label: "Title", value: "Bracket & Clip"),
+            ModelMetadata.Entry(label: "Designer", value: "Jane")
+        ])
+    }
+
+    // MARK: - File Tests
+
+    func testReadsFileInfoAndSiblingSource() throws {
+        let stl = directory.appendingPathComponent("bracket.stl")
+        try Data("solid bracket\nendsolid bracket\n".utf8).write(to: stl)
+        try Data("cube(10);".utf8).write(to: directory.appendingPathComponent("bracket.scad"))
+
+        let metadata = ModelMetadata.read(from: stl)
+        let labels = metadata.file.map(\.label)
+
+        XCTAssertEqual(labels, ["Format", "Path", "Size", "Modified", "Source"])
+        XCTAssertEqual(metadata.file.last?.value, directory.appendingPathComponent("bracket.scad").path)
+        XCTAssertEqual(metadata.embedded.first?.value, "bracket")
+    }
+
+    // MARK: - Sidecar Tests
+
+    func testSidecarRoundTrip() throws {
+        let model = directory.appendingPathComponent("bracket.stl")
+        var sidecar = ModelSidecar()
+        sidecar.properties = [ModelProperty(key: "Part Number", value: "BR-1042")]
+
+        try sidecar.write(for: model)
+        let loaded = try ModelSidecar.load(for: model)
+
+        XCTAssertEqual(ModelSidecar.url(for: model).lastPathComponent, "bracket.stl.gostl")
+        XCTAssertEqual(loaded.version, ModelSidecar.currentVersion)
+        XCTAssertEqual(loaded.properties.map(\.key), ["Part Number"])
+        XCTAssertEqual(loaded.properties.map(\.value), ["BR-1042"])
+    }
+
+    func testEmptySidecarRemovesFile() throws {
+        let model = directory.appendingPathComponent("bracket.stl")
+        var sidecar = ModelSidecar()
+        sidecar.properties = [ModelProperty(key: "Revision", value: "B")]
+        try sidecar.write(for: model)
+
+        try ModelSidecar().write(for: model)
+
+        XCTAssertFalse(FileManager.default.fileExists(atPath: ModelSidecar.url(for: model).path))
+        XCTAssertTrue(try ModelSidecar.load(for: model).isEmpty)
+    }
+}
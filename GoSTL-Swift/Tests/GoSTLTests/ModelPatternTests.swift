@@ -0,0 +1,71 @@
+import XCTest
+@testable import GoSTL
+
+final class ModelPatternTests: XCTestCase {
+    private let cube = ModelGenerator.cube(size: Vector3(10, 10, 10))
+
+    func testLinearCopiesAreSpacedByTheirSize() {
+        let result = ModelPattern.linear(count: 4, axis: .x, spacing: 3).apply(to: cube)
+        let bounds = result.boundingBox()
+
+        XCTAssertEqual(result.triangles.count, cube.triangles.count * 4)
+        XCTAssertEqual(bounds.min, cube.boundingBox().min)
+        XCTAssertEqual(bounds.size.x, 4 * 10 + 3 * 3, accuracy: 1e-9)
+        XCTAssertEqual(bounds.size.y, 10, accuracy: 1e-9)
+    }
+
+    func testLinearAlongZ() {
+        let result = ModelPattern.linear(count: 3, axis: .z, spacing: 0.5).apply(to: cube)
+
+        XCTAssertEqual(result.boundingBox().size.z, 31, accuracy: 1e-9)
+    }
+
+    func testPolarCopiesAreEvenlySpaced() {
+        let result = ModelPattern.polar(count: 6, radius: 40, sweep: 360, rotateCopies: true).apply(to: cube)
+        let center = cube.boundingBox().center
+        let pivot = Vector3(center.x - 40, center.y, center.z)
+
+        XCTAssertEqual(result.triangles.count, cube.triangles.count * 6)
+        for copy in 0..<6 {
+            let part = STLModel(triangles: Array(result.triangles[(copy * cube.triangles.count)..<((copy + 1) * cube.triangles.count)]))
+            let offset = part.boundingBox().center - pivot
+            let angle = Double(copy) * .pi / 3
+            XCTAssertEqual(offset.x, 40 * cos(angle), accuracy: 1e-9)
+            XCTAssertEqual(offset.y, 40 * sin(angle), accuracy: 1e-9)
+        }
+    }
+
+    func testPolarArcIncludesBothEnds() {
+        XCTAssertEqual(ModelPattern.angularStep(count: 5, sweep: 90), 22.5)
+        XCTAssertEqual(ModelPattern.angularStep(count: 6, sweep: 360), 60)
+    }
+
+    func testPolarWithoutRotationKeepsOrientation() {
+        let bar = ModelGenerator.cube(size: Vector3(20, 4, 4))
+
+        let result = ModelPattern.polar(count: 4, radius: 30, sweep: 360, rotateCopies: false).apply(to: bar)
+        let second = STLModel(triangles: Array(result.triangles[bar.triangles.count..<(2 * bar.triangles.count)])).boundingBox()
+
+        XCTAssertEqual(second.size.x, 20, accuracy: 1e-9)
+        XCTAssertEqual(second.size.y, 4, accuracy: 1e-9)
+    }
+
+    func testMinimumRadiusKeepsCopiesApart() {
+        let radius = ModelPattern.minimumRadius(for: cube, count: 6, sweep: 360, spacing: 2)
+        let result = ModelPattern.polar(count: 6, radius: radius, sweep: 360, rotateCopies: false).apply(to: cube)
+        let first = STLModel(triangles: Array(result.triangles.prefix(cube.triangles.count))).boundingBox()
+        let second = STLModel(triangles: Array(result.triangles[cube.triangles.count..<(2 * cube.triangles.count)])).boundingBox()
+
+        // Half diagonal plus half the spacing over sin(30°)
+        XCTAssertEqual(radius, (50.0.squareRoot() + 1) * 2, accuracy: 1e-9)
+        XCTAssertGreaterThanOrEqual((second.center - first.center).length, 2 * 50.0.squareRoot() + 2 - 1e-9)
+    }
+
+    func testCommandValidation() {
+        XCTAssertThrowsError(try PatternCommand.parse(["part.stl", "--count", "1"]))
+        XCTAssertThrowsError(try PatternCommand.parse(["part.stl", "--count", "3", "--radius", "40"]))
+        XCTAssertThrowsError(try PatternCommand.parse(["part.stl", "--count", "3", "--polar", "--angle", "0"]))
+        XCTAssertNoThrow(try PatternCommand.parse(["part.stl", "--count", "6", "--polar", "--radius", "40"]))
+        XCTAssertNoThrow(try PatternCommand.parse(["part.stl", "--count", "4", "--axis", "y", "--spacing", "3", "--open"]))
+    }
+}
@@ -0,0 +1,139 @@
+import XCTest
+@testable import GoSTL
+
+final class ModelProjectTests: XCTestCase {
+    private var directory: URL!
+    private var model: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-tests-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        model = directory.appendingPathComponent("bracket.stl")
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    // MARK: - File Tests
+
+    func testProjectRoundTrip() throws {
+        let distance = Measurement(
+            type: .distance,
+            points: [MeasurementPoint(position: Vector3(0, 0, 0), normal: .unitZ), MeasurementPoint(position: Vector3(10, 0, 0), normal: .unitZ)],
+            value: 10
+        )
+        let plane = ReferenceEntity(name: "Plane 1", kind: .plane(origin: Vector3(0, 0, 5), normal: .unitZ))
+        let point = ReferenceEntity(name: "Point 1", kind: .point(Vector3(1, 2, 3)))
+
+        var sidecar = ModelSidecar()
+        sidecar.measurements = MeasurementSet(fileName: "bracket.stl", measurements: [distance]).entries
+        sidecar.datums = [plane, point]
+        sidecar.datumMeasurements = [ReferenceMeasurement(firstID: plane.id, secondID: point.id)]
+        sidecar.views = [ViewBookmark(name: "Mounting holes", camera: CameraKeyframe(distance: 80, angleX: 0.5, angleY: 1, target: Vector3(5, 5, 0)))]
+        sidecar.units = ModelUnits(lengthScale: .micrometers, lengthDecimals: 1)
+        sidecar.addSnapshot(AnalysisSnapshot(values: ["volume": 1200, "triangles": 12]))
+        try sidecar.write(for: model)
+
+        let loaded = try ModelSidecar.load(for: model)
+
+        XCTAssertEqual(loaded.measurements.map(\.name), ["Distance 1"])
+        XCTAssertEqual(loaded.measurements.first?.measurement.points.last?.position, Vector3(10, 0, 0))
+        XCTAssertEqual(loaded.datums, [plane, point])
+        XCTAssertEqual(loaded.datumMeasurements.first?.secondID, point.id)
+        XCTAssertEqual(loaded.views.map(\.name), ["Mounting holes"])
+        XCTAssertEqual(loaded.views.first?.camera.target, Vector3(5, 5, 0))
+        XCTAssertEqual(loaded.units, ModelUnits(lengthScale: .micrometers, lengthDecimals: 1))
+        XCTAssertEqual(loaded.snapshots.first?.values["volume"], 1200)
+    }
+
+    func testVersionOneProjectLoads() throws {
+        let json = """
+            {"version": 1, "properties": [{"key": "Revision", "value": "B"}], "annotations": []}
+            """
+        try Data(json.utf8).write(to: ModelSidecar.url(for: model))
+
+        let loaded = try ModelSidecar.load(for: model)
+
+        XCTAssertEqual(loaded.version, 1)
+        XCTAssertEqual(loaded.properties.map(\.value), ["B"])
+        XCTAssertTrue(loaded.measurements.isEmpty)
+        XCTAssertNil(loaded.units)
+
+        // Updating migrates the file to the current version
+        try ModelSidecar.update(for: model) { $0.views = [ViewBookmark(name: "Front", camera: CameraKeyframe(distance: 50, angleX: 0, angleY: 0, target: .zero))] }
+        XCTAssertEqual(try ModelSidecar.load(for: model).version, ModelSidecar.currentVersion)
+        XCTAssertEqual(try ModelSidecar.load(for: model).properties.map(\.key), ["Revision"])
+    }
+
+    func testNewerProjectIsNotOverwritten() throws {
+        let url = ModelSidecar.url(for: model)
+        try Data(#"{"version": 99, "properties": []}"#.utf8).write(to: url)
+
+        XCTAssertThrowsError(try ModelSidecar.update(for: model) { $0.units = ModelUnits(lengthScale: .meters) }) { error in
+            XCTAssertEqual(error.localizedDescription, "bracket.stl.gostl was written by a newer GoSTL (project version 99); update GoSTL to open it")
+        }
+        XCTAssertEqual(try String(contentsOf: url, encoding: .utf8), #"{"version": 99, "properties": []}"#)
+    }
+
+    func testSnapshotsAreCapped() {
+        var sidecar = ModelSidecar()
+        for index in 0..<(ModelSidecar.maxSnapshots + 5) {
+            sidecar.addSnapshot(AnalysisSnapshot(values: ["volume": Double(index)]))
+        }
+
+        XCTAssertEqual(sidecar.snapshots.count, ModelSidecar.maxSnapshots)
+        XCTAssertEqual(sidecar.snapshots.first?.values["volume"], 5)
+    }
+
+    func testSnapshotKeepsNumericFields() {
+        let record = AnalyzeCommand.record(for: ModelGenerator.cube(size: Vector3(10, 10, 10)), fileName: "cube.stl")
+
+        let snapshot = AnalysisSnapshot(record: record)
+
+        XCTAssertEqual(snapshot.values["volume"] ?? 0, 1000, accuracy: 1e-9)
+        XCTAssertEqual(snapshot.values["triangles"], 12)
+        XCTAssertNil(snapshot.values["file"])
+    }
+
+    // MARK: - Units Tests
+
+    func testModelUnitsOverrideSettings() {
+        let settings = DisplayPrecision(lengthDecimals: 2, angleDecimals: 1)
+
+        let micrometers = ModelUnits(lengthScale: .micrometers).precision(overriding: settings, forModelExtent: 50)
+        let decimals = ModelUnits(lengthScale: .automatic, lengthDecimals: 4).precision(overriding: settings, forModelExtent: 50)
+
+        XCTAssertEqual(micrometers.unit, .micrometers)
+        XCTAssertEqual(micrometers.lengthDecimals, 2)
+        XCTAssertEqual(decimals.unit, .millimeters)
+        XCTAssertEqual(decimals.lengthDecimals, 4)
+        XCTAssertEqual(decimals.angleDecimals, 1)
+    }
+
+    // MARK: - Datum Tests
+
+    func testRestoredDatumsContinueNaming() {
+        let system = ReferenceGeometrySystem()
+        let plane = ReferenceEntity(name: "Plane 3", kind: .plane(origin: .zero, normal: .unitZ))
+        let axis = ReferenceEntity(name: "Spindle", kind: .axis(origin: .zero, direction: .unitX))
+
+        system.restore(entities: [plane, axis], measurements: [
+            ReferenceMeasurement(firstID: plane.id, secondID: axis.id),
+            ReferenceMeasurement(firstID: plane.id, secondID: UUID())
+        ])
+
+        XCTAssertEqual(system.measurements.count, 1)
+        XCTAssertEqual(system.add(.plane(origin: .zero, normal: .unitX)).name, "Plane 4")
+        XCTAssertEqual(system.add(.axis(origin: .zero, direction: .unitY)).name, "Axis 1")
+    }
+
+    func testDatumsMoveBetweenCoordinateSystems() {
+        let point = ReferenceEntity(name: "Point 1", kind: .point(Vector3(1, 2, 3)))
+
+        let moved = point.translated(by: Vector3(10, 0, 0))
+
+        XCTAssertEqual(moved.id, point.id)
+        XCTAssertEqual(moved.kind, .point(Vector3(11, 2, 3)))
+    }
+}
@@ -0,0 +1,126 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class ModelRegistrationTests: XCTestCase {
+
+    private func rotationZ(degrees: Double) -> simd_double3x3 {
+        let angle = degrees * .pi / 180
+        return simd_double3x3(rows: [
+            SIMD3(cos(angle), -sin(angle), 0),
+            SIMD3(sin(angle), cos(angle), 0),
+            SIMD3(0, 0, 1)
+        ])
+    }
+
+    /// The triangles rotated about `center` and then moved by `offset`
+    private func moved(_ triangles: [Triangle], rotation: simd_double3x3, center: Vector3, offset: Vector3) -> [Triangle] {
+        func move(_ point: Vector3) -> Vector3 {
+            Vector3(value: rotation * (point.value - center.value) + center.value + offset.value)
+        }
+        return triangles.map { Triangle(v1: move($0.v1), v2: move($0.v2), v3: move($0.v3)) }
+    }
+
+    private func assertEqual(_ a: Vector3, _ b: Vector3, accuracy: Double, file: StaticString = #filePath, line: UInt = #line) {
+        XCTAssertEqual(a.x, b.x, accuracy: accuracy, file: file, line: line)
+        XCTAssertEqual(a.y, b.y, accuracy: accuracy, file: file, line: line)
+        XCTAssertEqual(a.z, b.z, accuracy: accuracy, file: file, line: line)
+    }
+
+    // MARK: - Rigid Transform Tests
+
+    func testBestRigidTransformRecoversRotationAndTranslation() throws {
+        let source = [Vector3(0, 0, 0), Vector3(1, 0, 0), Vector3(0, 2, 0), Vector3(0, 0, 3)]
+        let rotation = rotationZ(degrees: 30)
+        let target = source.map { Vector3(value: rotation * $0.value) + Vector3(5, -2, 1) }
+
+        let transform = try XCTUnwrap(ModelRegistration.bestRigidTransform(from: source, to: target))
+
+        for (p, q) in zip(source, target) {
+            assertEqual(Vector3(value: transform.rotation * p.value) + transform.translation, q, accuracy: 1e-9)
+        }
+        XCTAssertEqual(simd_determinant(transform.rotation), 1, accuracy: 1e-9)
+    }
+
+    func testBestRigidTransformOfPlanarPointsIsARotation() throws {
+        // All points in one plane: the third singular value is zero, the result must not be a reflection
+        let source = [Vector3(0, 0, 0), Vector3(4, 0, 0), Vector3(0, 3, 0), Vector3(4, 3, 0)]
+        let rotation = rotationZ(degrees: -45)
+        let target = source.map { Vector3(value: rotation * $0.value) }
+
+        let transform = try XCTUnwrap(ModelRegistration.bestRigidTransform(from: source, to: target))
+
+        XCTAssertEqual(simd_determinant(transform.rotation), 1, accuracy: 1e-9)
+        for (p, q) in zip(source, target) {
+            assertEqual(Vector3(value: transform.rotation * p.value) + transform.translation, q, accuracy: 1e-9)
+        }
+    }
+
+    // MARK: - Registration Tests
+
+    func testRegisterTranslatedCopy() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(20, 10, 5))
+        let other = moved(model, rotation: matrix_identity_double3x3, center: .zero, offset: Vector3(100, 50, -3))
+
+        let result = try XCTUnwrap(try ModelRegistration.register(other, onto: model))
+
+        assertEqual(result.translation, Vector3(-100, -50, 3), accuracy: 1e-6)
+        XCTAssertEqual(result.rotationAngle, 0, accuracy: 1e-6)
+        XCTAssertLessThan(result.rmsError, 1e-6)
+    }
+
+    func testRegisterRotatedAndTranslatedCopy() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(20, 10, 5))
+        let other = moved(model, rotation: rotationZ(degrees: 10), center: Vector3(10, 5, 2.5), offset: Vector3(100, 50, 0))
+
+        let result = try XCTUnwrap(try ModelRegistration.register(other, onto: model))
+
+        XCTAssertEqual(result.rotationAngle, 10, accuracy: 0.01)
+        XCTAssertLessThan(result.rmsError, 1e-4)
+        for (registered, original) in zip(result.apply(to: STLModel(triangles: other)).triangles, model) {
+            assertEqual(registered.v1, original.v1, accuracy: 1e-4)
+            assertEqual(registered.v2, original.v2, accuracy: 1e-4)
+            assertEqual(registered.v3, original.v3, accuracy: 1e-4)
+        }
+    }
+
+    func testRegisterEmptyModel() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+        XCTAssertNil(try ModelRegistration.register([], onto: model))
+        XCTAssertNil(try ModelRegistration.register(model, onto: []))
+    }
+
+    // MARK: - Correspondence Tests
+
+    func testCorrespondenceOfIdenticalModels() throws {
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(20, 10, 5))
+
+        let correspondence = try ModelRegistration.correspondence(model: model, other: model, tolerance: 0.5)
+
+        XCTAssertEqual(correspondence.otherForMain, Array(0..<12))
+        XCTAssertEqual(correspondence.mainForOther, Array(0..<12))
+        XCTAssertEqual(correspondence.otherTriangles(matching: [0, 5]), [0, 5])
+        XCTAssertEqual(correspondence.mainTriangles(matching: 2), [2])
+        XCTAssertEqual(correspondence.otherTriangles(matching: []), [])
+    }
+
+    func testChangedRegionHasNoCounterpart() throws {
+        // The revision is 3 mm taller: its top no longer corresponds to the original top
+        let model = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(20, 10, 5))
+        let revision = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(20, 10, 8))
+
+        let correspondence = try ModelRegistration.correspondence(model: model, other: revision, tolerance: 0.5)
+
+        XCTAssertNil(correspondence.otherForMain[2])
+        XCTAssertNil(correspondence.otherForMain[3])
+        XCTAssertEqual(correspondence.otherForMain[0], 0)
+        XCTAssertEqual(correspondence.otherForMain[1], 1)
+        XCTAssertNil(correspondence.mainForOther[2])
+        XCTAssertTrue(correspondence.mainTriangles(matching: 3).isEmpty)
+    }
+
+    func testDefaultTolerance() {
+        XCTAssertEqual(ModelRegistration.defaultTolerance(for: BoundingBox(min: .zero, max: Vector3(3, 4, 0))), 0.5)
+        XCTAssertEqual(ModelRegistration.defaultTolerance(for: BoundingBox(min: .zero, max: Vector3(300, 400, 0))), 10, accuracy: 1e-9)
+    }
+}
@@ -0,0 +1,56 @@
+import XCTest
+import AppKit
+@testable import GoSTL
+
+final class NavigationSchemeTests: XCTestCase {
+
+    // MARK: - Left Button Tests
+
+    func testLeftDragIsTheSameInEveryScheme() {
+        for scheme in NavigationScheme.allCases {
+            XCTAssertEqual(scheme.action(for: .left, modifiers: []), .rotate, "\(scheme)")
+            XCTAssertEqual(scheme.action(for: .left, modifiers: .shift), .pan, "\(scheme)")
+        }
+    }
+
+    func testRightDragDoesNotNavigate() {
+        for scheme in NavigationScheme.allCases {
+            XCTAssertNil(scheme.action(for: .right, modifiers: []), "\(scheme)")
+        }
+    }
+
+    // MARK: - Middle Button Tests
+
+    func testGoSTLMiddleDragPans() {
+        XCTAssertEqual(NavigationScheme.gostl.action(for: .middle, modifiers: []), .pan)
+    }
+
+    func testFusion360() {
+        let scheme = NavigationScheme.fusion360
+        XCTAssertEqual(scheme.action(for: .middle, modifiers: []), .pan)
+        XCTAssertEqual(scheme.action(for: .middle, modifiers: .shift), .rotate)
+    }
+
+    func testSolidWorks() {
+        let scheme = NavigationScheme.solidworks
+        XCTAssertEqual(scheme.action(for: .middle, modifiers: []), .rotate)
+        XCTAssertEqual(scheme.action(for: .middle, modifiers: .control), .pan)
+        XCTAssertEqual(scheme.action(for: .middle, modifiers: .shift), .zoom)
+    }
+
+    func testBlender() {
+        let scheme = NavigationScheme.blender
+        XCTAssertEqual(scheme.action(for: .middle, modifiers: []), .rotate)
+        XCTAssertEqual(scheme.action(for: .middle, modifiers: .shift), .pan)
+        XCTAssertEqual(scheme.action(for: .middle, modifiers: .control), .zoom)
+    }
+
+    // MARK: - Persistence Tests
+
+    func testRawValueRoundTrip() throws {
+        for scheme in NavigationScheme.allCases {
+            let data = try JSONEncoder().encode(scheme)
+            XCTAssertEqual(try JSONDecoder().decode(NavigationScheme.self, from: data), scheme)
+        }
+    }
+}
@@ -0,0 +1,74 @@
+import XCTest
+@testable import GoSTL
+
+final class OpenSCADModuleTests: XCTestCase {
+
+    private let assembly = """
+        // module commented_out() {}
+        include <BOSL2/std.scad>
+
+        width = 40;
+
+        /* module in_block_comment() {
+           cube(1);
+        } */
+        module base(w = width, h = [1, 2], label = "a, b") {
+            cube([w, w, h[0]]);
+        }
+
+        module lid(w, inset = 2) {
+            module knob() { cylinder(d = 5, h = 3); }
+            translate([0, 0, 10]) cube([w - inset, w - inset, 2]);
+            knob();
+        }
+
+        function area(w) = w * w;
+
+        echo("module not_a_module() {}");
+        module_count = 2;
+
+        module
+            wrapped_name ( ) { lid(width); }
+
+        base();
+        lid(width);
+        """
+
+    func testTopLevelModules() {
+        let modules = OpenSCADModuleParser.topLevelModules(in: assembly)
+
+        XCTAssertEqual(modules.map(\.name), ["base", "lid", "wrapped_name"])
+    }
+
+    func testParameters() {
+        let modules = OpenSCADModuleParser.topLevelModules(in: assembly)
+
+        XCTAssertEqual(modules[0].parameters, [
+            OpenSCADModule.Parameter(name: "w", defaultValue: "width"),
+            OpenSCADModule.Parameter(name: "h", defaultValue: "[1, 2]"),
+            OpenSCADModule.Parameter(name: "label", defaultValue: "\"a, b\"")
+        ])
+        XCTAssertEqual(modules[1].requiredParameters, ["w"])
+        XCTAssertTrue(modules[2].parameters.isEmpty)
+    }
+
+    func testSignatureAndLine() {
+        let modules = OpenSCADModuleParser.topLevelModules(in: assembly)
+
+        XCTAssertEqual(modules[0].signature, "base()")
+        XCTAssertEqual(modules[1].signature, "lid(w)")
+        XCTAssertEqual(modules.map(\.line), [9, 13, 24])
+    }
+
+    func testCRLFLineEndings() {
+        let source = "// header\r\nmodule a() {}\r\n\r\nmodule b(x) {}\r\n"
+        let modules = OpenSCADModuleParser.topLevelModules(in: source)
+
+        XCTAssertEqual(modules.map(\.name), ["a", "b"])
+        XCTAssertEqual(modules.map(\.line), [2, 4])
+    }
+
+    func testUnterminatedParameterListIsIgnored() {
+        XCTAssertTrue(OpenSCADModuleParser.topLevelModules(in: "module broken(a = 1").isEmpty)
+    }
+}
@@ -0,0 +1,77 @@
+import XCTest
+@testable import GoSTL
+
+final class OpenSCADVariablesTests: XCTestCase {
+
+    private func measurement(_ type: MeasurementType, _ value: Double, note: String? = nil, decimals: Int? = nil) -> Measurement {
+        var measurement = Measurement(type: type, points: [], value: value)
+        measurement.note = note
+        measurement.decimals = decimals
+        return measurement
+    }
+
+    private func lines(_ code: String) -> [String] {
+        code.components(separatedBy: "\n").filter { !$0.hasPrefix("//") && !$0.isEmpty }
+    }
+
+    func testVariablesNamedAfterExportNames() {
+        let set = MeasurementSet(fileName: "bracket.stl", measurements: [
+            measurement(.distance, 32.0412),
+            measurement(.angle, 90),
+            measurement(.distance, 12.5)
+        ])
+
+        let code = OpenSCADGenerator.variables(from: set.entries, source: "bracket.stl")
+
+        XCTAssertTrue(code.hasPrefix("// OpenSCAD variables generated from GoSTL measurements\n// Source: bracket.stl"))
+        XCTAssertEqual(lines(code), [
+            "distance_1 = 32.04;  // mm",
+            "angle_1 = 90.00;  // degrees",
+            "distance_2 = 12.50;  // mm"
+        ])
+    }
+
+    func testNotesNameVariables() {
+        let entries = [
+            MeasurementSet.Entry(name: "Distance 1", measurement: measurement(.distance, 32.04, note: "Hole spacing")),
+            MeasurementSet.Entry(name: "Distance 2", measurement: measurement(.distance, 40, note: "hole-spacing"))
+        ]
+
+        XCTAssertEqual(lines(OpenSCADGenerator.variables(from: entries)), [
+            "hole_spacing = 32.04;  // Distance 1, mm",
+            "hole_spacing_2 = 40.00;  // Distance 2, mm"
+        ])
+    }
+
+    func testRadiusAddsDiameter() {
+        let entries = [
+            MeasurementSet.Entry(name: "Radius 1", measurement: measurement(.radius, 1.6)),
+            MeasurementSet.Entry(name: "Radius 2", measurement: measurement(.radius, 4, note: "boss"))
+        ]
+
+        XCTAssertEqual(lines(OpenSCADGenerator.variables(from: entries)), [
+            "radius_1 = 1.60;  // radius, mm",
+            "diameter_1 = 2 * radius_1;",
+            "boss = 4.00;  // Radius 2, radius, mm",
+            "boss_diameter = 2 * boss;"
+        ])
+    }
+
+    func testDecimals() {
+        let entries = [MeasurementSet.Entry(name: "Distance 1", measurement: measurement(.distance, 3.14159, decimals: 3))]
+
+        XCTAssertEqual(lines(OpenSCADGenerator.variables(from: entries)), ["distance_1 = 3.142;  // mm"])
+        XCTAssertEqual(lines(OpenSCADGenerator.variables(from: entries, decimals: 1)), ["distance_1 = 3.1;  // mm"])
+    }
+
+    func testVariableIdentifiers() {
+        XCTAssertEqual(OpenSCADGenerator.variableIdentifier(for: "Distance 1"), "distance_1")
+        XCTAssertEqual(OpenSCADGenerator.variableIdentifier(for: "  M3 insert (Ø) "), "m3_insert")
+        XCTAssertEqual(OpenSCADGenerator.variableIdentifier(for: "3 mm wall"), "value_3_mm_wall")
+        XCTAssertEqual(OpenSCADGenerator.variableIdentifier(for: "---"), "value")
+    }
+
+    func testNoMeasurements() {
+        XCTAssertEqual(OpenSCADGenerator.variables(from: []), "// No measurements to convert")
+    }
+}
@@ -0,0 +1,47 @@
+import XCTest
+@testable import GoSTL
+
+final class OrientationAreasTests: XCTestCase {
+    /// Square face of 10 × 10 mm tilted `degrees` back from vertical, facing -Y and slightly up
+    private func tiltedWall(degrees: Double) -> [Triangle] {
+        let angle = degrees * .pi / 180
+        let top = Vector3(0, 10 * sin(angle), 10 * cos(angle))
+        let a = Vector3(0, 0, 0), b = Vector3(10, 0, 0)
+        return [Triangle(v1: a, v2: b, v3: b + top), Triangle(v1: a, v2: b + top, v3: top)]
+    }
+
+    func testBoxFaces() {
+        let areas = OrientationAreas.compute(TestModels.box(from: Vector3(0, 0, 0), to: Vector3(40, 20, 10)))
+
+        XCTAssertEqual(areas.up, 800, accuracy: 1e-9)
+        XCTAssertEqual(areas.down, 800, accuracy: 1e-9)
+        XCTAssertEqual(areas.vertical, 1200, accuracy: 1e-9)
+    }
+
+    func testVerticalTolerance() {
+        let wall = tiltedWall(degrees: 3)
+
+        XCTAssertEqual(OrientationAreas.compute(wall, verticalTolerance: 5).vertical, 100, accuracy: 1e-9)
+        XCTAssertEqual(OrientationAreas.compute(wall, verticalTolerance: 2).up, 100, accuracy: 1e-9)
+        XCTAssertEqual(OrientationAreas.compute(wall, verticalTolerance: 0).vertical, 0)
+    }
+
+    func testOrientationFollowsWindingNotStoredNormal() {
+        let flipped = Triangle(v1: Vector3(0, 0, 0), v2: Vector3(0, 10, 0), v3: Vector3(10, 0, 0), normal: Vector3(0, 0, 1))
+
+        XCTAssertEqual(OrientationAreas.compute([flipped]).down, 50, accuracy: 1e-9)
+        XCTAssertEqual(OrientationAreas.compute([flipped, Triangle(v1: .zero, v2: .zero, v3: .zero)]).total, 50, accuracy: 1e-9)
+    }
+
+    func testAnalysisRecordSplitsSurfaceArea() throws {
+        let model = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(40, 20, 10)))
+        let record = AnalysisRecord(fileName: "box.stl", analysis: model.analyze())
+
+        XCTAssertEqual(record.value(for: "surface_area.up"), .number(800))
+        XCTAssertEqual(record.value(for: "surface_area.vertical"), .number(1200))
+        XCTAssertEqual(try XCTUnwrap(MetricRegistry.metric(for: "surface_area.down")).unit, "mm²")
+
+        XCTAssertEqual(try AnalyzeCommand.parse(["box.stl", "--vertical-tolerance", "10"]).verticalTolerance, 10)
+        XCTAssertThrowsError(try AnalyzeCommand.parse(["box.stl", "--vertical-tolerance", "90"]))
+    }
+}
@@ -0,0 +1,92 @@
+import XCTest
+@testable import GoSTL
+
+final class OutlineTests: XCTestCase {
+
+    private func cube() -> STLModel {
+        STLModel(triangles: TestModels.box(from: .zero, to: Vector3(10, 20, 30)), name: "cube")
+    }
+
+    // MARK: - Edge Tests
+
+    func testCoplanarDiagonalsAreNotCandidates() {
+        let outline = OutlineEdges(model: cube())
+
+        XCTAssertEqual(outline.candidates.count, 12)
+        XCTAssertTrue(outline.candidates.allSatisfy(\.isFeature))
+    }
+
+    func testFeatureEdgesFacingTheEye() {
+        let outline = OutlineEdges(model: cube())
+
+        let fromAbove = outline.visibleEdges(from: Vector3(5, 10, 1000))
+        XCTAssertEqual(fromAbove.count, 4)
+        XCTAssertTrue(fromAbove.allSatisfy { $0.start.z == 30 && $0.end.z == 30 })
+
+        XCTAssertEqual(outline.visibleEdges(from: Vector3(100, 100, 100)).count, 9)
+    }
+
+    func testSilhouetteOfSmoothEdges() {
+        // With a threshold above 90° the box edges are only drawn where they form the silhouette
+        let outline = OutlineEdges(model: cube(), featureAngle: 100)
+
+        XCTAssertTrue(outline.candidates.allSatisfy { !$0.isFeature })
+        XCTAssertEqual(outline.visibleEdges(from: Vector3(100, 100, 100)).count, 6)
+    }
+
+    func testOpenBoundaryIsAlwaysAFeature() {
+        let model = STLModel(triangles: [Triangle(v1: Vector3(0, 0, 0), v2: Vector3(1, 0, 0), v3: Vector3(0, 1, 0))])
+        let outline = OutlineEdges(model: model, featureAngle: 100)
+
+        XCTAssertEqual(outline.candidates.count, 3)
+        XCTAssertEqual(outline.visibleEdges(from: Vector3(0, 0, 10)).count, 3)
+        XCTAssertTrue(outline.visibleEdges(from: Vector3(0, 0, -10)).isEmpty)
+    }
+
+    // MARK: - Projection Tests
+
+    func testHiddenEdgesAreRemoved() {
+        let model = cube()
+        let camera = Camera()
+        camera.target = SIMD3<Float>(5, 10, 15)
+        camera.distance = 200
+        camera.angleX = 1.4
+        camera.angleY = .pi
+        let viewSize = CGSize(width: 400, height: 300)
+
+        let topEdge = Edge(Vector3(0, 0, 30), Vector3(10, 0, 30))
+        let bottomEdge = Edge(Vector3(10, 20, 0), Vector3(0, 20, 0))
+        let accelerator = SpatialAccelerator(triangles: model.triangles)
+
+        XCTAssertEqual(OutlineEdges.project([topEdge, bottomEdge], camera: camera, viewSize: viewSize, accelerator: nil).count, 2)
+
+        let visible = OutlineEdges.project([topEdge, bottomEdge], camera: camera, viewSize: viewSize, accelerator: accelerator)
+        XCTAssertEqual(visible.count, 1)
+        if let segment = visible.first, let start = camera.project(worldPosition: topEdge.start, viewSize: viewSize) {
+            XCTAssertEqual(segment.0.x, start.x, accuracy: 0.01)
+            XCTAssertEqual(segment.0.y, start.y, accuracy: 0.01)
+        }
+    }
+
+    // MARK: - SVG Tests
+
+    func testSVGDocument() {
+        let svg = SVGExporter.svg(
+            segments: [(CGPoint(x: 0, y: 0), CGPoint(x: 10.5, y: 20)), (CGPoint(x: 1.25, y: 2), CGPoint(x: 3, y: 4))],
+            size: CGSize(width: 100, height: 50),
+            title: "a<b>.stl"
+        )
+
+        XCTAssertTrue(svg.contains("width=\"100\" height=\"50\" viewBox=\"0 0 100 50\""))
+        XCTAssertTrue(svg.contains("<title>a&lt;b&gt;.stl</title>"))
+        XCTAssertTrue(svg.contains("d=\"M0 0L10.5 20M1.25 2L3 4\""))
+        XCTAssertTrue(svg.contains("fill=\"white\""))
+    }
+
+    func testEmptySVGHasNoPath() {
+        let svg = SVGExporter.svg(segments: [], size: CGSize(width: 10, height: 10))
+
+        XCTAssertFalse(svg.contains("<path"))
+        XCTAssertTrue(svg.hasSuffix("</svg>\n"))
+    }
+}
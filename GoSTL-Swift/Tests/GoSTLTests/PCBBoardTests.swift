@@ -0,0 +1,134 @@
+import XCTest
+@testable import GoSTL
+
+final class PCBBoardTests: XCTestCase {
+
+    /// 50 x 30 x 1.6 mm board with a 12 mm capacitor, a 3 mm header with a pin and a 2 mm connector underneath
+    private func pcb() -> STLModel {
+        let board = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(50, 30, 1.6))
+        let capacitor = TestModels.box(from: Vector3(10, 10, 1.6), to: Vector3(16, 16, 13.6))
+        let header = TestModels.box(from: Vector3(30, 5, 1.6), to: Vector3(40, 8, 4.6))
+        let pin = TestModels.box(from: Vector3(34, 6, 4.6), to: Vector3(35, 7, 5.6))
+        let connector = TestModels.box(from: Vector3(20, 20, -2), to: Vector3(28, 28, 0))
+        return STLModel(triangles: board + capacitor + header + pin + connector, name: "board")
+    }
+
+    // MARK: - Detection Tests
+
+    func testDetectsBoardPlanes() throws {
+        let board = try XCTUnwrap(PCBBoard.detect(in: pcb()))
+
+        XCTAssertEqual(board.bottomZ, 0, accuracy: 1e-9)
+        XCTAssertEqual(board.topZ, 1.6, accuracy: 1e-9)
+        XCTAssertEqual(board.thickness, 1.6, accuracy: 1e-9)
+        XCTAssertEqual(board.outline.size.x, 50, accuracy: 1e-9)
+        XCTAssertEqual(board.outline.size.y, 30, accuracy: 1e-9)
+    }
+
+    func testSolidBlockIsNotABoard() {
+        let block = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10)), name: "block")
+
+        XCTAssertNil(PCBBoard.detect(in: block))
+    }
+
+    func testBareBoardHasNoComponents() throws {
+        let bare = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(20, 20, 1)), name: "bare")
+        let board = try XCTUnwrap(PCBBoard.detect(in: bare))
+
+        XCTAssertTrue(board.components.isEmpty)
+        XCTAssertEqual(board.stackHeight, 1, accuracy: 1e-9)
+    }
+
+    // MARK: - Component Tests
+
+    func testComponentsTallestFirstPerSide() throws {
+        let board = try XCTUnwrap(PCBBoard.detect(in: pcb()))
+
+        XCTAssertEqual(board.components.map(\.side), [.top, .top, .bottom])
+        XCTAssertEqual(board.components[0].height, 12, accuracy: 1e-9)
+        XCTAssertEqual(board.components[1].height, 4, accuracy: 1e-9)  // Header and pin merged
+        XCTAssertEqual(board.components[2].height, 2, accuracy: 1e-9)
+        XCTAssertEqual(board.components.map(\.id), [1, 2, 3])
+    }
+
+    func testFootprint() throws {
+        let capacitor = try XCTUnwrap(PCBBoard.detect(in: pcb())?.tallestTopComponent)
+
+        XCTAssertEqual(capacitor.footprint.width, 6, accuracy: 1e-9)
+        XCTAssertEqual(capacitor.footprint.depth, 6, accuracy: 1e-9)
+    }
+
+    func testClearances() throws {
+        let board = try XCTUnwrap(PCBBoard.detect(in: pcb()))
+
+        XCTAssertEqual(board.topClearance, 12, accuracy: 1e-9)
+        XCTAssertEqual(board.bottomClearance, 2, accuracy: 1e-9)
+        XCTAssertEqual(board.stackHeight, 15.6, accuracy: 1e-9)
+    }
+
+    func testHeightMeasurement() throws {
+        let board = try XCTUnwrap(PCBBoard.detect(in: pcb()))
+        let capacitor = try XCTUnwrap(board.tallestTopComponent)
+
+        let measurement = board.heightMeasurement(for: capacitor)
+
+        XCTAssertEqual(measurement.type, .distance)
+        XCTAssertEqual(measurement.value, 12, accuracy: 1e-9)
+        XCTAssertEqual(measurement.points[0].position.z, 1.6, accuracy: 1e-9)
+        XCTAssertEqual(measurement.points[1].position.x, 13, accuracy: 1e-9)
+        XCTAssertEqual(measurement.points[1].position.z, 13.6, accuracy: 1e-9)
+    }
+
+    func testBottomHeightMeasurementStartsAtBoardBottom() throws {
+        let board = try XCTUnwrap(PCBBoard.detect(in: pcb()))
+        let connector = try XCTUnwrap(board.deepestBottomComponent)
+
+        let measurement = board.heightMeasurement(for: connector)
+
+        XCTAssertEqual(measurement.points[0].position.z, 0, accuracy: 1e-9)
+        XCTAssertEqual(measurement.points[1].position.z, -2, accuracy: 1e-9)
+        XCTAssertEqual(measurement.value, 2, accuracy: 1e-9)
+    }
+
+    // MARK: - Z Exaggeration Tests
+
+    func testDisplayTransformRoundTrip() {
+        let camera = Camera()
+        camera.zExaggeration = 5
+        camera.exaggerationOrigin = 1.6
+
+        let displayed = camera.toDisplay(SIMD3<Float>(3, 4, 2.6))
+        XCTAssertEqual(displayed.z, 6.6, accuracy: 1e-5)
+        XCTAssertEqual(displayed.x, 3)
+
+        let back = camera.toModel(displayed)
+        XCTAssertEqual(back.z, 2.6, accuracy: 1e-5)
+
+        let matrix = camera.displayMatrix() * SIMD4<Float>(3, 4, 2.6, 1)
+        XCTAssertEqual(matrix.z, 6.6, accuracy: 1e-5)
+    }
+
+    func testPickRayHitsProjectedModelPoint() throws {
+        let camera = Camera()
+        camera.distance = 150
+        camera.zExaggeration = 5
+        camera.exaggerationOrigin = 0
+        let viewSize = CGSize(width: 800, height: 600)
+        let point = Vector3(10, -5, 8)
+
+        // project() returns top-left based coordinates, mouse rays use bottom-left based ones
+        let screen = try XCTUnwrap(camera.project(worldPosition: point, viewSize: viewSize))
+        let ray = camera.pickRay(screenPos: CGPoint(x: screen.x, y: viewSize.height - screen.y), viewSize: viewSize)
+
+        XCTAssertLessThan(ray.distance(to: point.float3), 0.05)
+    }
+
+    func testNoExaggerationIsIdentity() {
+        let camera = Camera()
+        camera.exaggerationOrigin = 5
+
+        XCTAssertFalse(camera.isExaggerated)
+        XCTAssertEqual(camera.displayMatrix(), matrix_identity_float4x4)
+        XCTAssertEqual(camera.toDisplay(SIMD3<Float>(1, 2, 3)), SIMD3(1, 2, 3))
+    }
+}
@@ -0,0 +1,82 @@
+import XCTest
+@testable import GoSTL
+
+final class PartLabelTests: XCTestCase {
+    private func analysis() -> ModelAnalysis {
+        STLModel(triangles: [
+            Triangle(v1: Vector3(0, 0, 0), v2: Vector3(40, 0, 0), v3: Vector3(0, 25.5, 12))
+        ]).analyze()
+    }
+
+    private func texts(in sheet: DrawingSheet) -> [(text: String, point: CGPoint, size: Double)] {
+        sheet.primitives.compactMap { primitive in
+            guard case .text(let text, let point, let size, _, _) = primitive else { return nil }
+            return (text, point, size)
+        }
+    }
+
+    func testLabelShowsNameDimensionsVolumeAndDate() {
+        let date = ISO8601DateFormatter().date(from: "2026-10-17T12:00:00Z")!
+        let label = PartLabel(name: "bracket.stl", analysis: analysis(), link: nil, date: date)
+
+        XCTAssertEqual(label.lines, ["bracket.stl", "40.0 × 25.5 × 12.0 mm", "Volume 0.00 mm³", "2026-10-17"])
+        XCTAssertNil(label.codeRect)
+
+        let texts = texts(in: label.sheet())
+        XCTAssertEqual(texts.map(\.text), label.lines)
+        // The name is the largest text, and all lines fit the label
+        XCTAssertGreaterThan(texts[0].size, texts[1].size)
+        XCTAssertTrue(texts.allSatisfy { $0.point.y <= PartLabel.defaultSize.height - PartLabel.margin })
+    }
+
+    func testQRCodeFillsTheRightSide() throws {
+        let label = PartLabel(name: "bracket.stl", analysis: analysis(), link: "https://example.com/reports/bracket.html", date: nil)
+        let rect = try XCTUnwrap(label.codeRect)
+
+        XCTAssertEqual(rect.height, PartLabel.defaultSize.height - 2 * PartLabel.margin)
+        XCTAssertEqual(rect.maxX, PartLabel.defaultSize.width - PartLabel.margin)
+
+        let sheet = label.sheet()
+        let squares = sheet.primitives.compactMap { primitive -> [CGPoint]? in
+            guard case .polygon(let points) = primitive else { return nil }
+            return points
+        }
+        XCTAssertFalse(squares.isEmpty)
+        XCTAssertTrue(squares.allSatisfy { points in points.allSatisfy { rect.insetBy(dx: -1e-9, dy: -1e-9).contains($0) } })
+        // Text stays left of the code
+        XCTAssertTrue(texts(in: sheet).allSatisfy { $0.point.x < rect.minX })
+    }
+
+    func testLongNamesAreShortened() {
+        let name = "enclosure-lid-with-snap-fits-and-cable-gland-v12.stl"
+        let label = PartLabel(name: name, analysis: analysis(), link: "https://example.com", date: nil)
+        let texts = texts(in: label.sheet())
+
+        XCTAssertNotEqual(texts[0].text, name)
+        XCTAssertTrue(texts[0].text.contains("…"))
+        XCTAssertTrue(texts[0].text.hasSuffix("v12.stl"))
+        XCTAssertEqual(PartLabel.truncated("short.stl", width: 50, size: 3), "short.stl")
+    }
+
+    func testSizeParsing() {
+        XCTAssertEqual(PartLabel.parseSize("62x29"), CGSize(width: 62, height: 29))
+        XCTAssertEqual(PartLabel.parseSize("89 × 36"), CGSize(width: 89, height: 36))
+        XCTAssertNil(PartLabel.parseSize("10x5"))
+        XCTAssertNil(PartLabel.parseSize("62"))
+    }
+
+    func testPNGHasTheRequestedResolution() throws {
+        let label = PartLabel(name: "bracket.stl", analysis: analysis(), link: "https://example.com", date: nil)
+        let image = try XCTUnwrap(label.sheet().image(pixelsPerMillimeter: 300 / 25.4))
+
+        XCTAssertEqual(image.width, 732)
+        XCTAssertEqual(image.height, 343)
+    }
+
+    func testCommandValidatesOptions() throws {
+        XCTAssertNoThrow(try LabelCommand.parse(["part.stl", "-o", "part.pdf", "--size", "89x36"]))
+        XCTAssertThrowsError(try LabelCommand.parse(["part.stl", "-o", "part.txt"]))
+        XCTAssertThrowsError(try LabelCommand.parse(["part.stl", "--size", "big"]))
+        XCTAssertThrowsError(try LabelCommand.parse(["part.stl", "--no-qr", "--link", "https://example.com"]))
+    }
+}
@@ -0,0 +1,95 @@
+import CoreGraphics
+import XCTest
+@testable import GoSTL
+
+final class PerceptualDiffTests: XCTestCase {
+    /// White image with filled gray rectangles
+    private func image(size: Int = 32, rects: [(CGRect, gray: CGFloat)]) throws -> CGImage {
+        let context = try XCTUnwrap(CGContext(
+            data: nil,
+            width: size,
+            height: size,
+            bitsPerComponent: 8,
+            bytesPerRow: 0,
+            space: CGColorSpaceCreateDeviceRGB(),
+            bitmapInfo: CGImageAlphaInfo.premultipliedLast.rawValue
+        ))
+        context.setFillColor(CGColor(gray: 1, alpha: 1))
+        context.fill(CGRect(x: 0, y: 0, width: size, height: size))
+        for (rect, gray) in rects {
+            context.setFillColor(CGColor(gray: gray, alpha: 1))
+            context.fill(rect)
+        }
+        return try XCTUnwrap(context.makeImage())
+    }
+
+    func testIdenticalImagesPass() throws {
+        let a = try image(rects: [(CGRect(x: 8, y: 8, width: 16, height: 16), 0.3)])
+
+        let result = try PerceptualDiff.compare(a, a)
+
+        XCTAssertEqual(result.differingPixels, 0)
+        XCTAssertEqual(result.maxDeltaE, 0)
+        XCTAssertTrue(result.passes(tolerance: 0))
+    }
+
+    func testUnnoticeableColorChangesAreIgnored() throws {
+        let a = try image(rects: [(CGRect(x: 8, y: 8, width: 16, height: 16), 0.500)])
+        let b = try image(rects: [(CGRect(x: 8, y: 8, width: 16, height: 16), 0.505)])
+
+        XCTAssertEqual(try PerceptualDiff.compare(a, b).differingPixels, 0)
+        XCTAssertEqual(try PerceptualDiff.compare(a, b, threshold: 0.1).differingPixels, 256)
+    }
+
+    func testEdgesShiftedByOnePixelAreIgnored() throws {
+        let a = try image(rects: [(CGRect(x: 8, y: 8, width: 16, height: 16), 0.2)])
+        let b = try image(rects: [(CGRect(x: 9, y: 8, width: 16, height: 16), 0.2)])
+
+        XCTAssertEqual(try PerceptualDiff.compare(a, b).differingPixels, 0)
+        XCTAssertEqual(try PerceptualDiff.compare(a, b, radius: 0).differingPixels, 32)
+    }
+
+    func testMissingShapeIsFound() throws {
+        let a = try image(rects: [(CGRect(x: 8, y: 8, width: 16, height: 16), 0.2)])
+        let b = try image(rects: [
+            (CGRect(x: 8, y: 8, width: 16, height: 16), 0.2),
+            // A thin line, as lost by a broken clipping plane
+            (CGRect(x: 2, y: 28, width: 20, height: 1), 0.0)
+        ])
+
+        let result = try PerceptualDiff.compare(a, b)
+
+        XCTAssertEqual(result.differingPixels, 20)
+        XCTAssertEqual(result.percentage, 20.0 / 1024 * 100, accuracy: 1e-9)
+        XCTAssertGreaterThan(result.maxDeltaE, 90)
+        XCTAssertFalse(result.passes(tolerance: PerceptualDiff.defaultTolerance))
+        XCTAssertEqual(result.diffImage?.width, 32)
+    }
+
+    func testSizesMustMatch() throws {
+        let a = try image(size: 32, rects: [])
+        let b = try image(size: 16, rects: [])
+
+        XCTAssertThrowsError(try PerceptualDiff.compare(a, b)) { error in
+            XCTAssertEqual(error.localizedDescription, "Image sizes differ: 32×32 and 16×16")
+        }
+    }
+
+    func testLabColors() {
+        let white = PerceptualDiff.lab(red: 255, green: 255, blue: 255)
+        let black = PerceptualDiff.lab(red: 0, green: 0, blue: 0)
+
+        XCTAssertEqual(white.x, 100, accuracy: 0.01)
+        XCTAssertEqual(white.y, 0, accuracy: 0.01)
+        XCTAssertEqual(white.z, 0, accuracy: 0.01)
+        XCTAssertEqual(PerceptualDiff.deltaE(white, black), 100, accuracy: 0.01)
+        XCTAssertEqual(PerceptualDiff.lab(red: 255, green: 0, blue: 0).x, 53.24, accuracy: 0.05)
+    }
+
+    func testReferenceCasesHaveUniqueNames() {
+        for testCase in RenderSelfTest.cases {
+            XCTAssertFalse(testCase.model.triangles.isEmpty, testCase.name)
+        }
+        XCTAssertEqual(Set(RenderSelfTest.cases.map(\.name)).count, RenderSelfTest.cases.count)
+    }
+}
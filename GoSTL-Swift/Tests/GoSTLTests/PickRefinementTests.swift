@@ -0,0 +1,131 @@
+import XCTest
+@testable import GoSTL
+
+final class PickRefinementTests: XCTestCase {
+
+    /// Triangulated square grid of the given size, mapped into 3D
+    private func patch(steps: Int, size: Double, noise: (Int, Int) -> Double = { _, _ in 0 }, map: (Double, Double, Double) -> Vector3) -> [Triangle] {
+        func vertex(_ i: Int, _ j: Int) -> Vector3 {
+            map(Double(i) / Double(steps) * size, Double(j) / Double(steps) * size, noise(i, j))
+        }
+        var triangles: [Triangle] = []
+        for i in 0..<steps {
+            for j in 0..<steps {
+                triangles.append(Triangle(v1: vertex(i, j), v2: vertex(i + 1, j), v3: vertex(i + 1, j + 1)))
+                triangles.append(Triangle(v1: vertex(i, j), v2: vertex(i + 1, j + 1), v3: vertex(i, j + 1)))
+            }
+        }
+        return triangles
+    }
+
+    /// Top face (z = 0), front face (y = 0) and left face (x = 0) of a box corner at the origin
+    private var boxCorner: [Triangle] {
+        patch(steps: 2, size: 5) { u, v, _ in Vector3(u, v, 0) }
+            + patch(steps: 2, size: 5) { u, v, _ in Vector3(u, 0, -v) }
+            + patch(steps: 2, size: 5) { u, v, _ in Vector3(0, u, -v) }
+    }
+
+    // MARK: - Plane Tests
+
+    func testPointOnFaceIsProjectedOntoPlane() throws {
+        let triangles = patch(steps: 4, size: 10) { u, v, _ in Vector3(u, v, 0) }
+
+        let refinement = try XCTUnwrap(PickRefinement.refine(position: Vector3(5.3, 4.7, 0.01), normal: .unitZ, near: triangles))
+
+        XCTAssertEqual(refinement.feature, .plane)
+        XCTAssertEqual(refinement.position.z, 0, accuracy: 1e-9)
+        XCTAssertEqual(refinement.position.x, 5.3, accuracy: 1e-9)
+        XCTAssertEqual(refinement.uncertainty, 0, accuracy: 1e-9)
+        XCTAssertEqual(refinement.normal.z, 1, accuracy: 1e-9)
+    }
+
+    func testNoisySurfaceHasUncertainty() throws {
+        // Alternating ±0.05 mm noise around z = 1
+        let triangles = patch(steps: 6, size: 6, noise: { i, j in (i + j) % 2 == 0 ? 0.05 : -0.05 }) { u, v, n in
+            Vector3(u, v, 1 + n)
+        }
+
+        let refinement = try XCTUnwrap(PickRefinement.refine(position: Vector3(3, 3, 1.05), normal: .unitZ, near: triangles))
+
+        XCTAssertEqual(refinement.feature, .plane)
+        XCTAssertEqual(refinement.position.z, 1, accuracy: 0.01)
+        XCTAssertGreaterThan(refinement.uncertainty, 0)
+        XCTAssertLessThan(refinement.uncertainty, 0.05)
+    }
+
+    // MARK: - Edge and Corner Tests
+
+    func testPointNearEdgeMovesOntoEdge() throws {
+        let triangles = patch(steps: 2, size: 10) { u, v, _ in Vector3(u, v, 0) }
+            + patch(steps: 2, size: 10) { u, v, _ in Vector3(u, 0, -v) }
+
+        let refinement = try XCTUnwrap(PickRefinement.refine(position: Vector3(5, 0.8, 0), normal: .unitZ, near: triangles))
+
+        XCTAssertEqual(refinement.feature, .edge)
+        XCTAssertTrue(refinement.position.isApproximatelyEqual(to: Vector3(5, 0, 0), tolerance: 1e-9))
+    }
+
+    func testPointNearCornerMovesOntoCorner() throws {
+        let refinement = try XCTUnwrap(PickRefinement.refine(position: Vector3(0.6, 0.9, 0), normal: .unitZ, near: boxCorner))
+
+        XCTAssertEqual(refinement.feature, .corner)
+        XCTAssertTrue(refinement.position.isApproximatelyEqual(to: .zero, tolerance: 1e-9))
+    }
+
+    func testThinWallIsNotTreatedAsEdge() throws {
+        // Top and bottom of a 0.5 mm plate face in opposite directions and never intersect
+        let triangles = patch(steps: 2, size: 10) { u, v, _ in Vector3(u, v, 0) }
+            + patch(steps: 2, size: 10) { u, v, _ in Vector3(v, u, -0.5) }
+
+        let refinement = try XCTUnwrap(PickRefinement.refine(position: Vector3(5, 5, 0), normal: .unitZ, near: triangles))
+
+        XCTAssertEqual(refinement.feature, .plane)
+        XCTAssertEqual(refinement.position.z, 0, accuracy: 1e-9)
+    }
+
+    func testShallowEdgeIsLessCertain() throws {
+        let noise: (Int, Int) -> Double = { i, j in (i + j) % 2 == 0 ? 0.02 : -0.02 }
+        let top = PickRefinement.FittedPlane(PickRefinement.Face(
+            triangles: patch(steps: 4, size: 4, noise: noise) { u, v, n in Vector3(u, v, n) }, area: 16
+        ))
+        let square = PickRefinement.FittedPlane(PickRefinement.Face(
+            triangles: patch(steps: 4, size: 4, noise: noise) { u, v, n in Vector3(u, n, -v) }, area: 16
+        ))
+        let shallow = PickRefinement.FittedPlane(PickRefinement.Face(
+            triangles: patch(steps: 4, size: 4, noise: noise) { u, v, n in
+                // Noise along the plane normal (0, -sin, cos)
+                Vector3(u, -v * cos(0.3) - n * sin(0.3), -v * sin(0.3) + n * cos(0.3))
+            }, area: 16
+        ))
+
+        let squareEdge = try XCTUnwrap(PickRefinement.edge(try XCTUnwrap(top), try XCTUnwrap(square), closestTo: .zero))
+        let shallowEdge = try XCTUnwrap(PickRefinement.edge(try XCTUnwrap(top), try XCTUnwrap(shallow), closestTo: .zero))
+
+        XCTAssertGreaterThan(shallowEdge.uncertainty, squareEdge.uncertainty * 2)
+    }
+
+    func testNothingWithinReach() {
+        XCTAssertNil(PickRefinement.refine(position: Vector3(0, 0, 5), normal: .unitZ, near: []))
+    }
+
+    // MARK: - Measurement Tests
+
+    func testDistanceShowsCombinedUncertainty() {
+        let points = [
+            MeasurementPoint(position: .zero, normal: .unitZ, uncertainty: 0.003),
+            MeasurementPoint(position: Vector3(25, 0, 0), normal: .unitZ, uncertainty: 0.004)
+        ]
+        let measurement = Measurement(type: .distance, points: points, value: 25)
+
+        XCTAssertEqual(measurement.uncertainty ?? 0, 0.005, accuracy: 1e-9)
+        XCTAssertEqual(measurement.formattedValue, "25.00 ±0.005")
+    }
+
+    func testUnrefinedDistanceHasNoUncertainty() {
+        let points = [MeasurementPoint(position: .zero, normal: .unitZ), MeasurementPoint(position: .unitX, normal: .unitZ)]
+        let measurement = Measurement(type: .distance, points: points, value: 1)
+
+        XCTAssertNil(measurement.uncertainty)
+        XCTAssertEqual(measurement.formattedValue, "1.00")
+    }
+}
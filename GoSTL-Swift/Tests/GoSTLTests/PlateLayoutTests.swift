@@ -0,0 +1,95 @@
+import XCTest
+@testable import GoSTL
+
+final class PlateLayoutTests: XCTestCase {
+    /// Whether two footprints overlap (touching is fine)
+    private func overlaps(_ a: Vector3, _ sizeA: (width: Double, depth: Double), _ b: Vector3, _ sizeB: (width: Double, depth: Double)) -> Bool {
+        a.x < b.x + sizeB.width - 1e-9 && b.x < a.x + sizeA.width - 1e-9
+            && a.y < b.y + sizeB.depth - 1e-9 && b.y < a.y + sizeA.depth - 1e-9
+    }
+
+    private func assertNoOverlap(_ layout: PlateLayout, _ footprints: [(width: Double, depth: Double)], file: StaticString = #filePath, line: UInt = #line) {
+        for i in footprints.indices {
+            for j in footprints.indices where j > i {
+                XCTAssertFalse(overlaps(layout.origins[i], footprints[i], layout.origins[j], footprints[j]), "parts \(i) and \(j)", file: file, line: line)
+            }
+        }
+    }
+
+    func testGridUsesEqualCells() {
+        let footprints: [(width: Double, depth: Double)] = [(20, 10), (10, 10), (10, 30), (5, 5)]
+
+        let layout = PlateLayout(footprints: footprints, arrangement: .grid, spacing: 5)
+
+        // 2 × 2 cells of 25 × 35 mm, parts centered
+        XCTAssertEqual(layout.origins[0], Vector3(0, 10, 0))
+        XCTAssertEqual(layout.origins[1], Vector3(30, 10, 0))
+        XCTAssertEqual(layout.origins[2], Vector3(5, 35, 0))
+        XCTAssertEqual(layout.width, 40)
+        XCTAssertEqual(layout.depth, 65)
+        assertNoOverlap(layout, footprints)
+    }
+
+    func testGridColumnsFollowWidth() {
+        let footprints = [(width: Double, depth: Double)](repeating: (10, 10), count: 7)
+
+        let layout = PlateLayout(footprints: footprints, arrangement: .grid, spacing: 2, maxWidth: 50)
+
+        // (50 + 2) / 12 = 4 columns
+        XCTAssertEqual(layout.origins[3].y, 0)
+        XCTAssertEqual(layout.origins[4], Vector3(0, 12, 0))
+        XCTAssertLessThanOrEqual(layout.width, 50)
+    }
+
+    func testShelvesStartWithDeepestParts() {
+        let footprints: [(width: Double, depth: Double)] = [(30, 10), (30, 40), (30, 20), (30, 10)]
+
+        let layout = PlateLayout(footprints: footprints, arrangement: .shelf, spacing: 5, maxWidth: 70)
+
+        XCTAssertEqual(layout.origins[1], Vector3(0, 0, 0))
+        XCTAssertEqual(layout.origins[2], Vector3(35, 0, 0))
+        XCTAssertEqual(layout.origins[0], Vector3(0, 45, 0))
+        XCTAssertEqual(layout.origins[3], Vector3(35, 45, 0))
+        XCTAssertEqual(layout.depth, 55)
+        assertNoOverlap(layout, footprints)
+    }
+
+    func testShelvesWithoutWidthAreRoughlySquare() {
+        let footprints = (0..<12).map { (width: Double(10 + $0 % 4 * 5), depth: Double(8 + $0 % 3 * 6)) }
+
+        let layout = PlateLayout(footprints: footprints, arrangement: .shelf, spacing: 3)
+
+        assertNoOverlap(layout, footprints)
+        XCTAssertLessThan(max(layout.width, layout.depth) / min(layout.width, layout.depth), 2)
+    }
+
+    func testWidePartGetsItsOwnRow() {
+        let footprints: [(width: Double, depth: Double)] = [(100, 10), (10, 10)]
+
+        let layout = PlateLayout(footprints: footprints, arrangement: .shelf, spacing: 5, maxWidth: 50)
+
+        XCTAssertEqual(layout.origins[0], Vector3(0, 0, 0))
+        XCTAssertEqual(layout.origins[1], Vector3(0, 15, 0))
+    }
+
+    func testMergedPartsStandOnThePlate() {
+        let cube = ModelGenerator.cube(size: Vector3(10, 10, 10)).translated(by: Vector3(100, -40, 7))
+        let cylinder = ModelGenerator.cylinder(radius: 5, height: 20, segments: 16)
+        let layout = PlateLayout(footprints: [(10, 10), (10, 10)], arrangement: .grid, spacing: 5)
+
+        let plate = STLModel.merged([cube, cylinder], layout: layout, name: "plate")
+        let bounds = plate.boundingBox()
+
+        XCTAssertEqual(plate.triangles.count, cube.triangles.count + cylinder.triangles.count)
+        XCTAssertEqual(bounds.min, Vector3(0, 0, 0))
+        XCTAssertEqual(bounds.max.x, 25, accuracy: 1e-9)
+        XCTAssertEqual(bounds.max.z, 20, accuracy: 1e-9)
+        XCTAssertEqual(plate.name, "plate")
+    }
+
+    func testCommandNeedsTwoModels() {
+        XCTAssertThrowsError(try MergeCommand.parse(["a.stl"]))
+        XCTAssertThrowsError(try MergeCommand.parse(["a.stl", "b.stl", "--arrange", "spiral"]))
+        XCTAssertNoThrow(try MergeCommand.parse(["a.stl", "b.stl", "c.stl", "--arrange", "grid", "--spacing", "5", "--out", "plate.stl"]))
+    }
+}
@@ -0,0 +1,101 @@
+import XCTest
+@testable import GoSTL
+
+final class PlateNestingTests: XCTestCase {
+    private let cube = ModelGenerator.cube(size: Vector3(10, 10, 10))
+
+    // MARK: - Bed Tests
+
+    func testBedProfiles() {
+        XCTAssertEqual(BedProfile("256x256"), .rectangle(width: 256, depth: 256))
+        XCTAssertEqual(BedProfile("250 × 210"), .rectangle(width: 250, depth: 210))
+        XCTAssertEqual(BedProfile("d200"), .circle(diameter: 200))
+        XCTAssertEqual(BedProfile("x1c"), .rectangle(width: 256, depth: 256))
+        XCTAssertEqual(BedProfile("A1 mini"), .rectangle(width: 180, depth: 180))
+        XCTAssertNil(BedProfile("off"))
+        XCTAssertNil(BedProfile("256"))
+        XCTAssertNil(BedProfile("0x100"))
+    }
+
+    // MARK: - Nesting Tests
+
+    func testPartsKeepTheirSpacing() {
+        let nesting = PlateNesting(parts: [cube, cube], bed: .rectangle(width: 100, depth: 100), spacing: 5)
+        let plate = nesting.merged([cube, cube])
+
+        XCTAssertEqual(nesting.placements.count, 2)
+        XCTAssertTrue(nesting.unplaced.isEmpty)
+        let first = STLModel(triangles: Array(plate.triangles.prefix(cube.triangles.count))).boundingBox()
+        let second = STLModel(triangles: Array(plate.triangles.suffix(cube.triangles.count))).boundingBox()
+        let gap = max(second.min.x - first.max.x, second.min.y - first.max.y, first.min.x - second.max.x, first.min.y - second.max.y)
+        XCTAssertGreaterThanOrEqual(gap, 5 - 1e-9)
+        XCTAssertLessThanOrEqual(gap, 7 + 1e-9)
+        XCTAssertEqual(plate.boundingBox().min.z, 0, accuracy: 1e-9)
+        XCTAssertEqual(nesting.efficiency, 0.02, accuracy: 1e-9)
+    }
+
+    func testLongPartIsRotatedToFit() {
+        let bar = ModelGenerator.cube(size: Vector3(90, 10, 5))
+
+        let nesting = PlateNesting(parts: [bar], bed: .rectangle(width: 50, depth: 100), spacing: 0)
+        let bounds = nesting.merged([bar]).boundingBox()
+
+        XCTAssertEqual(nesting.placements.first.map { $0.rotation.truncatingRemainder(dividingBy: 180) }, 90)
+        XCTAssertGreaterThanOrEqual(bounds.min.x, -1e-9)
+        XCTAssertGreaterThanOrEqual(bounds.min.y, -1e-9)
+        XCTAssertLessThanOrEqual(bounds.max.x, 50 + 1e-9)
+        XCTAssertLessThanOrEqual(bounds.max.y, 100 + 1e-9)
+    }
+
+    func testNoRotationKeepsOrientation() {
+        let bar = ModelGenerator.cube(size: Vector3(90, 10, 5))
+
+        let nesting = PlateNesting(parts: [bar], bed: .rectangle(width: 50, depth: 100), spacing: 0, rotationStep: 0)
+
+        XCTAssertTrue(nesting.placements.isEmpty)
+        XCTAssertEqual(nesting.unplaced, [0])
+    }
+
+    func testPartsThatDoNotFitAreReported() {
+        let big = ModelGenerator.cube(size: Vector3(60, 60, 5))
+
+        let nesting = PlateNesting(parts: [big, cube, big], bed: .rectangle(width: 100, depth: 100), spacing: 2)
+
+        XCTAssertEqual(nesting.placements.map(\.part), [0, 1])
+        XCTAssertEqual(nesting.unplaced, [2])
+    }
+
+    func testRoundBedKeepsPartsInside() {
+        let bed = BedProfile.circle(diameter: 50)
+
+        let nesting = PlateNesting(parts: [cube, cube, cube], bed: bed, spacing: 2)
+        let plate = nesting.merged([cube, cube, cube])
+
+        XCTAssertFalse(nesting.placements.isEmpty)
+        for triangle in plate.triangles {
+            for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                XCTAssertTrue(bed.contains(x: vertex.x, y: vertex.y), "\(vertex) is off the bed")
+            }
+        }
+    }
+
+    func testFootprintFollowsOutline() {
+        let cylinder = ModelGenerator.cylinder(radius: 10, height: 5, segments: 64)
+
+        let mask = FootprintMask(triangles: cylinder.triangles, resolution: 0.5)
+
+        // Covered cells include the rim; interior cells approximate the disc area
+        XCTAssertEqual(mask.columns, 40)
+        XCTAssertEqual(Double(mask.interiorCount) * 0.25, Double.pi * 100, accuracy: 10)
+        XCTAssertLessThan(mask.cells.count, mask.columns * mask.rows)
+    }
+
+    // MARK: - Command Tests
+
+    func testCommandNeedsBedForNesting() {
+        XCTAssertThrowsError(try MergeCommand.parse(["a.stl", "b.stl", "--arrange", "nest"]))
+        XCTAssertThrowsError(try MergeCommand.parse(["a.stl", "b.stl", "--bed", "X1C"]))
+        XCTAssertThrowsError(try MergeCommand.parse(["a.stl", "b.stl", "--arrange", "nest", "--bed", "round"]))
+        XCTAssertNoThrow(try MergeCommand.parse(["a.stl", "b.stl", "--arrange", "nest", "--bed", "d200", "--rotation-step", "45", "--resolution", "0.5"]))
+    }
+}
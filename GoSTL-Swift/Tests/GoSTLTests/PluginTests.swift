@@ -0,0 +1,151 @@
+import XCTest
+@testable import GoSTL
+
+final class PluginTests: XCTestCase {
+    private var pluginsDirectory: URL!
+
+    override func setUpWithError() throws {
+        pluginsDirectory = FileManager.default.temporaryDirectory
+            .appendingPathComponent("gostl-plugin-tests-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: pluginsDirectory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: pluginsDirectory)
+    }
+
+    /// Create a plugin directory with a manifest and a shell script executable
+    @discardableResult
+    private func makePlugin(named name: String, manifest: String, script: String) throws -> URL {
+        let directory = pluginsDirectory.appendingPathComponent(name)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        try manifest.write(to: directory.appendingPathComponent("plugin.json"), atomically: true, encoding: .utf8)
+        let executable = directory.appendingPathComponent("run.sh")
+        try ("#!/bin/sh\n" + script).write(to: executable, atomically: true, encoding: .utf8)
+        try FileManager.default.setAttributes([.posixPermissions: 0o755], ofItemAtPath: executable.path)
+        return directory
+    }
+
+    private let importerManifest = """
+        {
+          "name": "Triangle Importer",
+          "executable": "run.sh",
+          "importers": [{ "extensions": ["TRI"] }],
+          "analyzers": [{ "id": "echo", "title": "Echo" }]
+        }
+        """
+
+    // MARK: - Discovery Tests
+
+    func testRegistryDiscoversPlugins() throws {
+        try makePlugin(named: "triangle", manifest: importerManifest, script: "exit 0")
+        try FileManager.default.createDirectory(at: pluginsDirectory.appendingPathComponent("not-a-plugin"), withIntermediateDirectories: true)
+
+        let registry = PluginRegistry(searchDirectories: [pluginsDirectory])
+
+        XCTAssertEqual(registry.plugins.map(\.manifest.name), ["Triangle Importer"])
+        XCTAssertEqual(registry.importExtensions, ["tri"])
+        XCTAssertEqual(registry.importer(forExtension: "Tri")?.manifest.name, "Triangle Importer")
+        XCTAssertNil(registry.importer(forExtension: "off"))
+        XCTAssertEqual(registry.analyzers.map(\.analyzer.title), ["Echo"])
+    }
+
+    func testInvalidManifestIsSkipped() throws {
+        try makePlugin(named: "broken", manifest: "{ \"name\": 1 }", script: "exit 0")
+
+        let registry = PluginRegistry(searchDirectories: [pluginsDirectory])
+
+        XCTAssertTrue(registry.plugins.isEmpty)
+    }
+
+    // MARK: - Protocol Tests
+
+    func testImporterWritesSTL() throws {
+        try makePlugin(named: "triangle", manifest: importerManifest, script: """
+            cat > "$3" <<'STL'
+            solid t
+              facet normal 0 0 1
+                outer loop
+                  vertex 0 0 0
+                  vertex 1 0 0
+                  vertex 0 1 0
+                endloop
+              endfacet
+            endsolid t
+            STL
+            """)
+        let registry = PluginRegistry(searchDirectories: [pluginsDirectory])
+        let plugin = try XCTUnwrap(registry.plugins.first)
+
+        let model = try registry.importModel(from: URL(fileURLWithPath: "/tmp/part.tri"), using: plugin)
+
+        XCTAssertEqual(model.triangleCount, 1)
+        XCTAssertEqual(model.name, "part")
+    }
+
+    func testFailingPluginReportsStderr() throws {
+        try makePlugin(named: "triangle", manifest: importerManifest, script: "echo 'bad input' >&2\nexit 3")
+        let registry = PluginRegistry(searchDirectories: [pluginsDirectory])
+        let plugin = try XCTUnwrap(registry.plugins.first)
+
+        XCTAssertThrowsError(try registry.importModel(from: URL(fileURLWithPath: "/tmp/part.tri"), using: plugin)) { error in
+            XCTAssertTrue(error.localizedDescription.contains("exit status 3"))
+            XCTAssertTrue(error.localizedDescription.contains("bad input"))
+        }
+    }
+
+    func testCancelledLoadJobDoesNotRunImporter() throws {
+        let marker = pluginsDirectory.appendingPathComponent("ran")
+        try makePlugin(named: "triangle", manifest: importerManifest, script: "touch '\(marker.path)'")
+        let registry = PluginRegistry(searchDirectories: [pluginsDirectory])
+        let plugin = try XCTUnwrap(registry.plugins.first)
+        let job = JobContext()
+        job.cancel()
+
+        XCTAssertThrowsError(try registry.importModel(from: URL(fileURLWithPath: "/tmp/part.tri"), using: plugin, job: job)) { error in
+            XCTAssertTrue(error is CancellationError)
+        }
+        XCTAssertFalse(FileManager.default.fileExists(atPath: marker.path))
+    }
+
+    func testLargeStderrDoesNotBlockPlugin() throws {
+        // 200 KB of warnings, more than a pipe holds, before the result on stdout
+        try makePlugin(named: "triangle", manifest: importerManifest, script: """
+            head -c 200000 /dev/zero | tr '\\0' 'w' >&2
+            echo '{"results":[{"name":"Done","value":"yes"}]}'
+            """)
+        let registry = PluginRegistry(searchDirectories: [pluginsDirectory])
+        let plugin = try XCTUnwrap(registry.plugins.first)
+        let model = STLModel(triangles: [Triangle(v1: Vector3(0, 0, 0), v2: Vector3(1, 0, 0), v3: Vector3(0, 1, 0))])
+
+        let result = try registry.analyze(model, plugin: plugin, analyzerID: "echo")
+
+        XCTAssertEqual(result.results, [PluginAnalysisResult.Entry(name: "Done", value: "yes")])
+    }
+
+    func testAnalyzerOutputIsDecoded() throws {
+        try makePlugin(named: "triangle", manifest: importerManifest, script: """
+            echo '{"results":[{"name":"Args","value":"'"$1 $2"'"}],"overlay":{"points":[{"x":1,"y":2,"z":3}]}}'
+            """)
+        let registry = PluginRegistry(searchDirectories: [pluginsDirectory])
+        let plugin = try XCTUnwrap(registry.plugins.first)
+        let model = STLModel(triangles: [Triangle(v1: Vector3(0, 0, 0), v2: Vector3(1, 0, 0), v3: Vector3(0, 1, 0))])
+
+        let result = try registry.analyze(model, plugin: plugin, analyzerID: "echo")
+
+        XCTAssertEqual(result.results, [PluginAnalysisResult.Entry(name: "Args", value: "analyze echo")])
+        XCTAssertEqual(result.overlay?.points, [Vector3(1, 2, 3)])
+    }
+
+    func testOverlayTranslation() {
+        let overlay = PluginAnalysisResult.Overlay(
+            points: [Vector3(1, 1, 1)],
+            lines: [PluginAnalysisResult.Line(from: Vector3(0, 0, 0), to: Vector3(1, 0, 0))]
+        )
+
+        let moved = overlay.translated(by: Vector3(10, 0, 0))
+
+        XCTAssertEqual(moved.points, [Vector3(11, 1, 1)])
+        XCTAssertEqual(moved.lines?.first?.to, Vector3(11, 0, 0))
+    }
+}
@@ -0,0 +1,78 @@
+import XCTest
+@testable import GoSTL
+
+final class PolyhedronExportTests: XCTestCase {
+
+    private func cube(size: Double = 10) -> STLModel {
+        STLModel(triangles: TestModels.box(from: .zero, to: Vector3(size, size, size)), name: "cube")
+    }
+
+    // MARK: - Indexed Mesh Tests
+
+    func testSharedVertices() {
+        let mesh = IndexedMesh(triangles: cube().triangles)
+
+        XCTAssertEqual(mesh.points.count, 8)
+        XCTAssertEqual(mesh.faces.count, 12)
+        XCTAssertEqual(mesh.points[mesh.faces[0].1], Vector3(10, 10, 0))
+    }
+
+    func testDecimationMergesVerticesInACell() {
+        // A thin strip whose short sides lie within one cell collapses; the third triangle spans three cells
+        let triangles = [
+            Triangle(v1: Vector3(0, 0, 0), v2: Vector3(10, 0, 0), v3: Vector3(0, 0.2, 0)),
+            Triangle(v1: Vector3(0, 0.2, 0), v2: Vector3(10, 0, 0), v3: Vector3(10, 0.2, 0)),
+            Triangle(v1: Vector3(0, 0, 0), v2: Vector3(10, 0, 0), v3: Vector3(0, 0, 10))
+        ]
+        let mesh = IndexedMesh(triangles: triangles).decimated(cellSize: 1)
+
+        XCTAssertEqual(mesh.faces.count, 1)
+        XCTAssertEqual(mesh.points.count, 3)
+        XCTAssertTrue(mesh.points.contains(Vector3(0, 0.1, 0)))
+    }
+
+    func testCoarseDecimationKeepsValidIndices() {
+        let mesh = IndexedMesh(triangles: cube().triangles).decimated(cellSize: 6)
+
+        XCTAssertEqual(mesh.points.count, 8)
+        XCTAssertEqual(mesh.faces.count, 12)
+        XCTAssertTrue(IndexedMesh(triangles: cube().triangles).decimated(cellSize: 100).faces.isEmpty)
+        XCTAssertTrue(mesh.faces.allSatisfy { [$0.0, $0.1, $0.2].allSatisfy { $0 < mesh.points.count } })
+    }
+
+    // MARK: - OpenSCAD Tests
+
+    func testModuleIdentifier() {
+        XCTAssertEqual(OpenSCADGenerator.moduleIdentifier(for: "my part-2.stl"), "my_part_2")
+        XCTAssertEqual(OpenSCADGenerator.moduleIdentifier(for: "3d-print.3mf"), "model_3d_print")
+        XCTAssertEqual(OpenSCADGenerator.moduleIdentifier(for: "Bracket v2.stl"), "Bracket_v2")
+    }
+
+    func testPolyhedronModule() {
+        let code = OpenSCADGenerator.polyhedronModule(from: cube(), fileName: "bracket.stl")
+
+        XCTAssertTrue(code.contains("// Source: bracket.stl"))
+        XCTAssertTrue(code.contains("// 8 vertices, 12 faces"))
+        XCTAssertTrue(code.contains("module bracket() {"))
+        XCTAssertTrue(code.contains("            [10, 10, 0],"))
+        XCTAssertTrue(code.hasSuffix("bracket();\n"))
+    }
+
+    func testFacesAreWoundClockwise() {
+        // First STL triangle a, c, b (indices 0, 1, 2) is written reversed
+        let code = OpenSCADGenerator.polyhedronModule(from: cube(), fileName: "cube.stl")
+        XCTAssertTrue(code.contains("            [0, 2, 1],"))
+    }
+
+    func testDecimationNoteAndModuleName() {
+        let code = OpenSCADGenerator.polyhedronModule(from: cube(), fileName: "scan.stl", moduleName: "reference", decimation: 0.5)
+
+        XCTAssertTrue(code.contains("module reference() {"))
+        XCTAssertTrue(code.contains("// Decimated from 12 faces by merging vertices within 0.5 mm cells"))
+    }
+
+    func testCommandRejectsInvalidDecimation() {
+        XCTAssertThrowsError(try PolyhedronCommand.parse(["part.stl", "--decimate", "0"]))
+        XCTAssertNoThrow(try PolyhedronCommand.parse(["part.stl", "--decimate", "0.5", "-o", "ref.scad"]))
+    }
+}
@@ -0,0 +1,103 @@
+import XCTest
+@testable import GoSTL
+
+final class PresentationModeTests: XCTestCase {
+    private func keyframe(distance: Double = 100, angleX: Double = 0.3, angleY: Double = 0, target: Vector3 = .zero) -> CameraKeyframe {
+        CameraKeyframe(distance: distance, angleX: angleX, angleY: angleY, target: target)
+    }
+
+    // MARK: - Camera Flight
+
+    func testFlightStartsAndEndsAtTheViews() {
+        let from = keyframe(target: Vector3(0, 0, 0))
+        let to = keyframe(distance: 50, angleY: 1, target: Vector3(10, 0, 0))
+        var flight = CameraFlight(from: from, to: to, duration: 1)
+
+        XCTAssertEqual(flight.keyframe, from)
+        for _ in 0..<20 {
+            _ = flight.advance(deltaTime: 0.1)
+        }
+        XCTAssertTrue(flight.isComplete)
+        XCTAssertEqual(flight.keyframe.distance, 50, accuracy: 1e-9)
+        XCTAssertEqual(flight.keyframe.angleY, 1, accuracy: 1e-9)
+        XCTAssertEqual(flight.keyframe.target, Vector3(10, 0, 0))
+    }
+
+    func testFlightEasesInAndOut() {
+        var flight = CameraFlight(from: keyframe(target: Vector3(0, 0, 0)), to: keyframe(target: Vector3(100, 0, 0)), duration: 1)
+
+        let early = flight.advance(deltaTime: 0.1).target.x
+        for _ in 0..<4 {
+            _ = flight.advance(deltaTime: 0.1)
+        }
+        let middle = flight.keyframe.target.x
+
+        XCTAssertLessThan(early, 10)
+        XCTAssertEqual(middle, 50, accuracy: 1e-9)
+    }
+
+    func testFlightIgnoresStalledFrames() {
+        var flight = CameraFlight(from: keyframe(), to: keyframe(distance: 200), duration: 1)
+        _ = flight.advance(deltaTime: 5)
+        XCTAssertEqual(flight.elapsed, 0.1, accuracy: 1e-12)
+    }
+
+    func testFlightPullsBackWhileTurning() {
+        var flight = CameraFlight(from: keyframe(angleX: 0, angleY: 0), to: keyframe(angleX: 0, angleY: .pi), duration: 1)
+        XCTAssertEqual(flight.turn, 1, accuracy: 1e-9)
+
+        for _ in 0..<5 {
+            _ = flight.advance(deltaTime: 0.1)
+        }
+        XCTAssertEqual(flight.keyframe.distance, 100 * (1 + CameraFlight.pullBack), accuracy: 1e-9)
+    }
+
+    func testFlightWithoutTurnKeepsDistance() {
+        var flight = CameraFlight(from: keyframe(target: .zero), to: keyframe(target: Vector3(0, 0, 20)), duration: 1)
+        XCTAssertEqual(flight.turn, 0, accuracy: 1e-9)
+
+        for _ in 0..<5 {
+            _ = flight.advance(deltaTime: 0.1)
+        }
+        XCTAssertEqual(flight.keyframe.distance, 100, accuracy: 1e-9)
+    }
+
+    // MARK: - Workspace Layouts
+
+    func testLayoutRoundTrip() throws {
+        var layout = WorkspaceLayout(name: "Review")
+        layout.showModelInfo = false
+        layout.showMeasurementLog = true
+        layout.showPerformanceHUD = true
+        layout.detachedPanels = [.measurementLog, .analysis]
+
+        let data = try JSONEncoder().encode(layout)
+        let decoded = try JSONDecoder().decode(WorkspaceLayout.self, from: data)
+
+        XCTAssertEqual(decoded.name, "Review")
+        XCTAssertFalse(decoded.showModelInfo)
+        XCTAssertTrue(decoded.showMeasurementLog)
+        XCTAssertTrue(decoded.showPerformanceHUD)
+        XCTAssertEqual(decoded.detachedPanels, [.measurementLog, .analysis])
+    }
+
+    func testLayoutMissingFieldsKeepDefaults() throws {
+        let json = #"{"name": "Old", "showMeasurementLog": true, "detachedPanels": ["openSCADLog", "future"]}"#
+        let layout = try JSONDecoder().decode(WorkspaceLayout.self, from: Data(json.utf8))
+
+        XCTAssertTrue(layout.showModelInfo)
+        XCTAssertTrue(layout.showAnnotations)
+        XCTAssertTrue(layout.showMeasurementLog)
+        XCTAssertFalse(layout.showClippingPanel)
+        XCTAssertEqual(layout.detachedPanels, [.openSCADLog])
+    }
+
+    func testPresentationLayoutHidesPanels() {
+        let layout = WorkspaceLayout.presentation
+        XCTAssertFalse(layout.showModelInfo)
+        XCTAssertFalse(layout.showMeasurementLog)
+        XCTAssertFalse(layout.showPerformanceHUD)
+        XCTAssertTrue(layout.showAnnotations)
+        XCTAssertTrue(layout.detachedPanels.isEmpty)
+    }
+}
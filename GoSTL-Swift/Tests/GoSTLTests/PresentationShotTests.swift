@@ -0,0 +1,112 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class PresentationShotTests: XCTestCase {
+
+    /// 10 x 10 x 2 mm plate on the ground
+    private let plate = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 2))
+
+    func testStudioGradient() throws {
+        let gradient = try XCTUnwrap(PresentationShotOptions.Background.studio.gradient)
+        let top = try XCTUnwrap(PresentationShot.backgroundColor(.studio, x: 0.5, y: 0, viewer: .zero))
+        let bottom = try XCTUnwrap(PresentationShot.backgroundColor(.studio, x: 0.5, y: 1, viewer: .zero))
+        let corner = try XCTUnwrap(PresentationShot.backgroundColor(.studio, x: 0, y: 0, viewer: .zero))
+
+        XCTAssertEqual(simd_distance(top, gradient.top * (1 - PresentationShot.vignette * 0.5)), 0, accuracy: 1e-5)
+        XCTAssertGreaterThan(top.x, bottom.x)
+        // Corners are darkened by the vignette
+        XCTAssertLessThan(corner.x, top.x)
+    }
+
+    func testFlatAndTransparentBackgrounds() {
+        let viewer = SIMD3<Float>(0.1, 0.2, 0.3)
+        XCTAssertEqual(PresentationShot.backgroundColor(.viewer, x: 0, y: 1, viewer: viewer), viewer)
+        XCTAssertNil(PresentationShot.backgroundColor(.transparent, x: 0.5, y: 0.5, viewer: viewer))
+    }
+
+    func testGroundShadowUnderModel() throws {
+        let shadow = try XCTUnwrap(GroundShadow(triangles: plate))
+
+        XCTAssertEqual(shadow.value(at: SIMD2(5, 5)), 1, accuracy: 1e-3)
+        // Soft edge: partly dark at the border, clear beyond the blur
+        XCTAssertGreaterThan(shadow.value(at: SIMD2(10, 5)), 0.2)
+        XCTAssertLessThan(shadow.value(at: SIMD2(10, 5)), 0.8)
+        XCTAssertEqual(shadow.value(at: SIMD2(50, 50)), 0)
+    }
+
+    func testRaisedPartsCastLighterShadows() throws {
+        // Post standing on the plate's corner, and a block floating high above its other corner
+        let model = plate
+            + ModelGenerator.box(from: Vector3(0, 0, 2), to: Vector3(1, 1, 20))
+            + ModelGenerator.box(from: Vector3(12, 12, 15), to: Vector3(14, 14, 16))
+        let shadow = try XCTUnwrap(GroundShadow(triangles: model))
+
+        XCTAssertGreaterThan(shadow.value(at: SIMD2(5, 5)), 0.9)
+        XCTAssertLessThan(shadow.value(at: SIMD2(13, 13)), 0.1)
+    }
+
+    func testEmptyModelHasNoShadow() {
+        XCTAssertNil(GroundShadow(triangles: []))
+    }
+
+    func testBoxBlurSpreadsEvenly() {
+        var values = [Float](repeating: 0, count: 81)
+        values[40] = 1
+        PresentationShot.boxBlur(&values, width: 9, height: 9, radius: 1)
+
+        XCTAssertEqual(values.reduce(0, +), 1, accuracy: 1e-5)
+        for y in 3...5 {
+            for x in 3...5 {
+                XCTAssertEqual(values[y * 9 + x], 1 / 9, accuracy: 1e-6)
+            }
+        }
+        XCTAssertEqual(values[0], 0)
+    }
+
+    func testComposeOverBackground() {
+        let viewer = SIMD3<Float>(0.2, 0.4, 0.6)
+        var options = PresentationShotOptions()
+        options.background = .viewer
+        options.groundShadow = false
+
+        let composed = PresentationShot.compose(modelFrame(), scene: scene(viewer: viewer), options: options, shadow: nil)
+
+        // Background pixel: opaque view color (BGRA)
+        XCTAssertEqual(Array(composed.bgra[0..<4]), [153, 102, 51, 255])
+        // Model pixel: unchanged
+        XCTAssertEqual(Array(composed.bgra[4..<8]), [0, 0, 255, 255])
+    }
+
+    func testComposeTransparent() {
+        var options = PresentationShotOptions()
+        options.background = .transparent
+        options.groundShadow = false
+
+        let composed = PresentationShot.compose(modelFrame(), scene: scene(viewer: .zero), options: options, shadow: nil)
+
+        XCTAssertEqual(composed.bgra[3], 0)
+        XCTAssertEqual(composed.bgra[7], 255)
+    }
+
+    /// 4 x 4 frame, empty except an opaque red pixel at (1, 0)
+    private func modelFrame() -> OffscreenFrame {
+        var bgra = [UInt8](repeating: 0, count: 64)
+        bgra[4...7] = [0, 0, 255, 255]
+        var depth = [Float](repeating: 1, count: 16)
+        depth[1] = 0.5
+        return OffscreenFrame(size: 4, bgra: bgra, depth: depth)
+    }
+
+    private func scene(viewer: SIMD3<Float>) -> PresentationShot.Scene {
+        let camera = Camera()
+        return PresentationShot.Scene(
+            viewProjection: camera.projectionMatrix(aspect: 1) * camera.viewMatrix(),
+            cameraPosition: camera.position,
+            groundZ: -10,
+            focusDistance: Float(camera.distance),
+            focusRange: 10,
+            viewerBackground: viewer
+        )
+    }
+}
@@ -0,0 +1,122 @@
+import XCTest
+@testable import GoSTL
+
+final class PrimitiveDetectionTests: XCTestCase {
+
+    /// Upright cone standing on its base, counter-clockwise seen from outside
+    private func cone(radius: Double, height: Double, segments: Int) -> [Triangle] {
+        let base = ModelGenerator.circle(center: .zero, radius: radius, segments: segments)
+        let apex = Vector3(0, 0, height)
+        var triangles: [Triangle] = []
+        for index in 0..<segments {
+            let next = (index + 1) % segments
+            triangles.append(Triangle(v1: .zero, v2: base[next], v3: base[index]))
+            triangles.append(Triangle(v1: base[index], v2: base[next], v3: apex))
+        }
+        return triangles
+    }
+
+    func testCubeIsPlanes() throws {
+        let result = try PrimitiveDetection.detect(ModelGenerator.cube(size: Vector3(10, 20, 30)).triangles)
+
+        XCTAssertEqual(result.regions.count, 6)
+        XCTAssertEqual(result.count(of: .plane), 6)
+        XCTAssertEqual(result.coverage, 1, accuracy: 1e-9)
+        XCTAssertEqual(result.totalArea, 2 * (200 + 300 + 600), accuracy: 1e-6)
+    }
+
+    func testCylinderBoss() throws {
+        let result = try PrimitiveDetection.detect(ModelGenerator.cylinder(radius: 5, height: 10, segments: 48).triangles)
+
+        XCTAssertEqual(result.count(of: .cylinder), 1)
+        XCTAssertEqual(result.count(of: .plane), 2)
+        XCTAssertEqual(result.coverage, 1, accuracy: 1e-9)
+
+        let region = try XCTUnwrap(result.regions.first { $0.shape.kind == .cylinder })
+        guard case .cylinder(let axis, let center, let radius, let length) = region.shape else {
+            return XCTFail("Expected a cylinder")
+        }
+        XCTAssertEqual(abs(axis.z), 1, accuracy: 1e-6)
+        XCTAssertEqual(center.x, 0, accuracy: 1e-6)
+        XCTAssertEqual(center.y, 0, accuracy: 1e-6)
+        XCTAssertEqual(center.z, 5, accuracy: 1e-6)
+        XCTAssertEqual(radius, 5, accuracy: 1e-6)
+        XCTAssertEqual(length, 10, accuracy: 1e-6)
+        XCTAssertEqual(region.sweep, 360)
+        XCTAssertTrue(region.isConvex)
+        XCTAssertEqual(region.triangles.count, 96)
+    }
+
+    func testBlockWithHole() throws {
+        // Inverted cylinder inside a block: faces look towards the axis like the wall of a bore
+        let block = ModelGenerator.cube(size: Vector3(20, 20, 10)).triangles
+        let bore = ModelGenerator.cylinder(center: Vector3(2, 3, 0), radius: 3.2, height: 10, segments: 48)
+            .map { Triangle(v1: $0.v1, v2: $0.v3, v3: $0.v2) }
+
+        let result = try PrimitiveDetection.detect(block + bore)
+
+        let region = try XCTUnwrap(result.regions.first { $0.shape.kind == .cylinder })
+        guard case .cylinder(_, let center, let radius, _) = region.shape else {
+            return XCTFail("Expected a cylinder")
+        }
+        XCTAssertFalse(region.isConvex)
+        XCTAssertEqual(center.x, 2, accuracy: 1e-6)
+        XCTAssertEqual(center.y, 3, accuracy: 1e-6)
+        XCTAssertEqual(radius, 3.2, accuracy: 1e-6)
+        XCTAssertTrue(PrimitiveDetection.describe(region).hasPrefix("Cylinder Ø 6.40 mm hole, 10.00 mm long"))
+    }
+
+    func testCone() throws {
+        let result = try PrimitiveDetection.detect(cone(radius: 5, height: 10, segments: 48))
+
+        let region = try XCTUnwrap(result.regions.first { $0.shape.kind == .cone })
+        guard case .cone(let apex, let axis, let halfAngle, let radii, let length) = region.shape else {
+            return XCTFail("Expected a cone")
+        }
+        XCTAssertEqual(apex.z, 10, accuracy: 1e-6)
+        XCTAssertEqual(axis.z, -1, accuracy: 1e-6)
+        XCTAssertEqual(halfAngle, atan(0.5) * 180 / .pi, accuracy: 1e-6)
+        XCTAssertEqual(radii.upperBound, 5, accuracy: 1e-6)
+        XCTAssertEqual(length, 10, accuracy: 1e-6)
+        XCTAssertTrue(region.isConvex)
+    }
+
+    func testSphere() throws {
+        let result = try PrimitiveDetection.detect(ModelGenerator.sphere(radius: 4).triangles)
+
+        XCTAssertEqual(result.regions.count, 1)
+        guard case .sphere(let center, let radius) = result.regions[0].shape else {
+            return XCTFail("Expected a sphere")
+        }
+        XCTAssertEqual(center.z, 4, accuracy: 1e-6)
+        XCTAssertEqual(radius, 4, accuracy: 1e-6)
+        XCTAssertTrue(result.regions[0].isConvex)
+    }
+
+    func testTolerance() throws {
+        // The polygon of a coarse cylinder is more than 0.05 mm off a circle between its vertices,
+        // but the vertices themselves lie on it: still a cylinder
+        let coarse = try PrimitiveDetection.detect(ModelGenerator.cylinder(radius: 5, height: 10, segments: 12).triangles)
+        XCTAssertEqual(coarse.count(of: .cylinder), 1)
+
+        let torus = try PrimitiveDetection.detect(ModelGenerator.torus(majorRadius: 10, minorRadius: 3).triangles)
+        XCTAssertEqual(torus.count(of: .freeform), 1)
+        XCTAssertEqual(torus.coverage, 0, accuracy: 1e-9)
+    }
+
+    func testSweep() {
+        XCTAssertEqual(PrimitiveDetection.sweep(of: stride(from: 0.0, to: 360, by: 30).map { $0 }), 360)
+        XCTAssertEqual(PrimitiveDetection.sweep(of: [0, 30, 60, 90]), 90, accuracy: 1e-9)
+        XCTAssertEqual(PrimitiveDetection.sweep(of: [170, -170]), 20, accuracy: 1e-9)
+    }
+
+    func testColors() throws {
+        let model = ModelGenerator.cylinder(radius: 5, height: 10, segments: 48)
+        let result = try PrimitiveDetection.detect(model.triangles)
+
+        let colors = PrimitiveDetection.colors(for: result)
+
+        XCTAssertEqual(colors.triangleCount, model.triangleCount)
+        XCTAssertTrue((0..<colors.triangleCount).allSatisfy { colors.corners(ofTriangle: $0).0.w == 1 })
+    }
+}
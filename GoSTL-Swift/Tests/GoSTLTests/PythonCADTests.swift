@@ -0,0 +1,106 @@
+import XCTest
+@testable import GoSTL
+
+final class PythonCADTests: XCTestCase {
+    private var root: URL!
+
+    override func setUpWithError() throws {
+        root = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-tests-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: root, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: root)
+    }
+
+    private func write(_ path: String, _ contents: String = "") throws -> URL {
+        let url = root.appendingPathComponent(path)
+        try FileManager.default.createDirectory(at: url.deletingLastPathComponent(), withIntermediateDirectories: true)
+        try contents.write(to: url, atomically: true, encoding: .utf8)
+        return url
+    }
+
+    // MARK: - Import Tests
+
+    func testImportedModules() {
+        let source = """
+            import cadquery as cq, math
+            from build123d import *
+            from parts.hinge import Hinge, pin
+            from . import common
+            from ..shared import (bolts)
+            """
+
+        XCTAssertEqual(PythonCADRenderer.importedModules(in: source), [
+            "cadquery", "math",
+            "build123d",
+            "parts.hinge", "parts.hinge.Hinge", "parts.hinge.pin",
+            ".common",
+            "..shared", "..shared.bolts"
+        ])
+    }
+
+    func testDependenciesFollowLocalImports() throws {
+        let script = try write("project/box.py", "import cadquery as cq\nfrom parts import lid\nimport dims\n")
+        let lid = try write("project/parts/lid.py", "from .hinge import Hinge\n")
+        let hinge = try write("project/parts/hinge.py")
+        let initFile = try write("project/parts/__init__.py")
+        let dims = try write("project/dims.py")
+
+        let deps = PythonCADRenderer(workDir: script.deletingLastPathComponent(), interpreter: nil)
+            .resolveDependencies(script: script)
+
+        XCTAssertEqual(Set(deps), Set([script, initFile, lid, hinge, dims].map(\.standardizedFileURL)))
+        XCTAssertEqual(deps.first, script.standardizedFileURL)
+    }
+
+    func testInstalledPackagesAreNotResolved() throws {
+        XCTAssertNil(PythonCADRenderer.resolveModule("cadquery", from: root, rootDir: root))
+    }
+
+    // MARK: - Interpreter Tests
+
+    func testVirtualEnvironmentInParentDirectory() throws {
+        let python = try write(".venv/bin/python", "#!/bin/sh\n")
+        try FileManager.default.setAttributes([.posixPermissions: 0o755], ofItemAtPath: python.path)
+        let scripts = root.appendingPathComponent("src/parts")
+        try FileManager.default.createDirectory(at: scripts, withIntermediateDirectories: true)
+
+        XCTAssertEqual(PythonCADRenderer.virtualEnvironmentInterpreter(near: scripts), python.standardizedFileURL.path)
+    }
+
+    func testMissingConfiguredInterpreterIsReported() {
+        let renderer = PythonCADRenderer(workDir: root, interpreter: root.appendingPathComponent("missing/python").path)
+
+        XCTAssertThrowsError(try renderer.findInterpreter(environment: [:])) { error in
+            guard case PythonCADError.interpreterNotFound(let path) = error else {
+                return XCTFail("Unexpected error: \(error)")
+            }
+            XCTAssertEqual(path, self.root.appendingPathComponent("missing/python").path)
+        }
+    }
+
+    func testEnvironmentOverridesSetting() throws {
+        let python = try write("env/python", "#!/bin/sh\n")
+        try FileManager.default.setAttributes([.posixPermissions: 0o755], ofItemAtPath: python.path)
+        let renderer = PythonCADRenderer(workDir: root, interpreter: "/nonexistent/python")
+
+        XCTAssertEqual(try renderer.findInterpreter(environment: [PythonCADRenderer.environmentKey: python.path]), python.path)
+    }
+
+    // MARK: - Output Tests
+
+    func testMessages() {
+        let messages = PythonCADRenderer.parseMessages(
+            stdout: "volume 1234.5\n\n  wall ok\n",
+            stderr: "some/module.py:12: DeprecationWarning: old API\nTraceback line\n"
+        )
+
+        XCTAssertEqual(messages, ["volume 1234.5", "wall ok", "some/module.py:12: DeprecationWarning: old API"])
+    }
+
+    func testScriptsAreRenderable() {
+        XCTAssertTrue(ModelFileLoader.renderableExtensions.contains("py"))
+        XCTAssertTrue(ModelFileLoader.viewerExtensions.contains("py"))
+    }
+}
@@ -0,0 +1,331 @@
+import XCTest
+@testable import GoSTL
+
+final class ReferenceGeometryTests: XCTestCase {
+
+    // MARK: - Construction Tests
+
+    func testPlaneThroughThreePoints() throws {
+        let kind = try XCTUnwrap(ReferenceEntity.Kind.plane(through: Vector3(0, 0, 5), Vector3(10, 0, 5), Vector3(0, 10, 5)))
+
+        guard case .plane(let origin, let normal) = kind else {
+            return XCTFail("Expected a plane")
+        }
+        XCTAssertTrue(origin.isApproximatelyEqual(to: Vector3(10.0 / 3.0, 10.0 / 3.0, 5), tolerance: 1e-9))
+        XCTAssertTrue(normal.isApproximatelyEqual(to: Vector3(0, 0, 1), tolerance: 1e-9))
+    }
+
+    func testPlaneThroughCollinearPointsFails() {
+        XCTAssertNil(ReferenceEntity.Kind.plane(through: Vector3(0, 0, 0), Vector3(1, 1, 1), Vector3(2, 2, 2)))
+    }
+
+    func testPlaneOffsetFromFace() throws {
+        let kind = try XCTUnwrap(ReferenceEntity.Kind.plane(offsetFrom: Vector3(1, 2, 3), normal: Vector3(0, 0, 2), by: -4))
+
+        XCTAssertEqual(kind, .plane(origin: Vector3(1, 2, -1), normal: Vector3(0, 0, 1)))
+    }
+
+    func testAxisThroughTwoPoints() throws {
+        let kind = try XCTUnwrap(ReferenceEntity.Kind.axis(through: Vector3(0, 0, 0), Vector3(0, 0, 10)))
+
+        XCTAssertEqual(kind, .axis(origin: Vector3(0, 0, 5), direction: Vector3(0, 0, 1)))
+        XCTAssertNil(ReferenceEntity.Kind.axis(through: Vector3(1, 1, 1), Vector3(1, 1, 1)))
+    }
+
+    func testAxisFromCylinderFit() throws {
+        let points = [Vector3(15, 20, 7), Vector3(10, 25, 7), Vector3(5, 20, 7)]
+        let kind = try XCTUnwrap(ReferenceEntity.Kind.axis(fittingCylinder: points))
+
+        guard case .axis(let origin, let direction) = kind else {
+            return XCTFail("Expected an axis")
+        }
+        XCTAssertTrue(origin.isApproximatelyEqual(to: Vector3(10, 20, 7), tolerance: 1e-6))
+        XCTAssertEqual(abs(direction.z), 1, accuracy: 1e-9)
+    }
+
+    // MARK: - Intersection Tests
+
+    func testAxisPlaneIntersection() throws {
+        let axis = ReferenceEntity.Kind.axis(origin: Vector3(3, 4, 0), direction: Vector3(0, 0, 1))
+        let plane = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 12), normal: Vector3(0, 0, 1))
+
+        let point = try XCTUnwrap(ReferenceEntity.Kind.intersection(of: [plane, axis]))
+
+        XCTAssertTrue(point.isApproximatelyEqual(to: Vector3(3, 4, 12), tolerance: 1e-9))
+    }
+
+    func testParallelAxisAndPlaneDoNotIntersect() {
+        let axis = ReferenceEntity.Kind.axis(origin: Vector3(0, 0, 0), direction: Vector3(1, 0, 0))
+        let plane = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 5), normal: Vector3(0, 0, 1))
+
+        XCTAssertNil(ReferenceEntity.Kind.intersection(of: [axis, plane]))
+    }
+
+    func testSkewAxesUseClosestApproachMidpoint() throws {
+        let axis1 = ReferenceEntity.Kind.axis(origin: Vector3(0, 0, 0), direction: Vector3(1, 0, 0))
+        let axis2 = ReferenceEntity.Kind.axis(origin: Vector3(5, 0, 2), direction: Vector3(0, 1, 0))
+
+        let point = try XCTUnwrap(ReferenceEntity.Kind.intersection(of: [axis1, axis2]))
+
+        XCTAssertTrue(point.isApproximatelyEqual(to: Vector3(5, 0, 1), tolerance: 1e-9))
+    }
+
+    func testThreePlaneIntersection() throws {
+        let planes: [ReferenceEntity.Kind] = [
+            .plane(origin: Vector3(1, 0, 0), normal: Vector3(1, 0, 0)),
+            .plane(origin: Vector3(0, 2, 0), normal: Vector3(0, 1, 0)),
+            .plane(origin: Vector3(0, 0, 3), normal: Vector3(0, 0, 1))
+        ]
+
+        let point = try XCTUnwrap(ReferenceEntity.Kind.intersection(of: planes))
+
+        XCTAssertTrue(point.isApproximatelyEqual(to: Vector3(1, 2, 3), tolerance: 1e-9))
+    }
+
+    func testPlanePlaneIntersectionLine() throws {
+        let plane1 = ReferenceEntity.Kind.plane(origin: Vector3(5, 0, 0), normal: Vector3(1, 0, 0))
+        let plane2 = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 3), normal: Vector3(0, 0, 1))
+
+        let line = try XCTUnwrap(ReferenceEntity.Kind.intersectionLine(of: plane1, plane2))
+
+        guard case .axis(let origin, let direction) = line else {
+            return XCTFail("Expected an axis")
+        }
+        XCTAssertEqual(abs(direction.y), 1, accuracy: 1e-9)
+        XCTAssertTrue(origin.isApproximatelyEqual(to: Vector3(5, 0, 3), tolerance: 1e-9))
+        XCTAssertEqual(plane1.distance(to: origin + direction * 20), 0, accuracy: 1e-9)
+        XCTAssertEqual(plane2.distance(to: origin + direction * 20), 0, accuracy: 1e-9)
+    }
+
+    func testTiltedPlanesIntersectionLiesOnBothPlanes() throws {
+        let plane1 = ReferenceEntity.Kind.plane(origin: Vector3(1, 2, 3), normal: Vector3(1, 1, 0).normalized())
+        let plane2 = ReferenceEntity.Kind.plane(origin: Vector3(-4, 0, 7), normal: Vector3(0, 1, 1).normalized())
+
+        let line = try XCTUnwrap(ReferenceEntity.Kind.intersectionLine(of: plane1, plane2))
+
+        XCTAssertEqual(plane1.distance(to: line.anchor), 0, accuracy: 1e-9)
+        XCTAssertEqual(plane2.distance(to: line.anchor), 0, accuracy: 1e-9)
+    }
+
+    func testParallelPlanesHaveNoIntersectionLine() {
+        let plane1 = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 0), normal: Vector3(0, 0, 1))
+        let plane2 = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 5), normal: Vector3(0, 0, -1))
+        let axis = ReferenceEntity.Kind.axis(origin: Vector3(0, 0, 0), direction: Vector3(1, 0, 0))
+
+        XCTAssertNil(ReferenceEntity.Kind.intersectionLine(of: plane1, plane2))
+        XCTAssertNil(ReferenceEntity.Kind.intersectionLine(of: plane1, axis))
+    }
+
+    func testSegmentPlaneIntersection() throws {
+        let plane = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 4), normal: Vector3(0, 0, 1))
+
+        let point = try XCTUnwrap(ReferenceEntity.Kind.intersection(ofSegmentFrom: Vector3(0, 0, 0), to: Vector3(10, 0, 10), withPlane: plane))
+
+        XCTAssertTrue(point.isApproximatelyEqual(to: Vector3(4, 0, 4), tolerance: 1e-9))
+    }
+
+    func testSegmentThatDoesNotReachPlaneHasNoIntersection() {
+        let plane = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 4), normal: Vector3(0, 0, 1))
+
+        XCTAssertNil(ReferenceEntity.Kind.intersection(ofSegmentFrom: Vector3(0, 0, 0), to: Vector3(0, 0, 3), withPlane: plane))
+        XCTAssertNil(ReferenceEntity.Kind.intersection(ofSegmentFrom: Vector3(0, 0, 1), to: Vector3(9, 0, 1), withPlane: plane))
+    }
+
+    func testClosestPointAndDistance() {
+        let plane = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 2), normal: Vector3(0, 0, 1))
+        let axis = ReferenceEntity.Kind.axis(origin: Vector3(0, 0, 0), direction: Vector3(0, 0, 1))
+
+        XCTAssertEqual(plane.distance(to: Vector3(4, 5, 7)), 5, accuracy: 1e-9)
+        XCTAssertEqual(axis.distance(to: Vector3(3, 4, 9)), 5, accuracy: 1e-9)
+        XCTAssertEqual(axis.closestPoint(to: Vector3(3, 4, 9)), Vector3(0, 0, 9))
+    }
+
+    // MARK: - Reference Measurement Tests
+
+    func testParallelPlaneDistanceAndAngle() throws {
+        let datum = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 0), normal: Vector3(0, 0, 1))
+        let other = ReferenceEntity.Kind.plane(origin: Vector3(7, 3, 10), normal: Vector3(0, 0, -1))
+
+        let result = try XCTUnwrap(ReferenceEntity.Kind.measure(datum, other))
+
+        XCTAssertEqual(try XCTUnwrap(result.distance), 10, accuracy: 1e-9)
+        XCTAssertEqual(try XCTUnwrap(result.angle), 0, accuracy: 1e-9)
+        XCTAssertEqual(result.from, Vector3(7, 3, 0))
+    }
+
+    func testPerpendicularPlanesHaveNoDistance() throws {
+        let first = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 0), normal: Vector3(0, 0, 1))
+        let second = ReferenceEntity.Kind.plane(origin: Vector3(5, 0, 0), normal: Vector3(1, 0, 0))
+
+        let result = try XCTUnwrap(ReferenceEntity.Kind.measure(first, second))
+
+        XCTAssertNil(result.distance)
+        XCTAssertEqual(try XCTUnwrap(result.angle), 90, accuracy: 1e-9)
+    }
+
+    func testParallelAxesReportConcentricityOffset() throws {
+        let first = ReferenceEntity.Kind.axis(origin: Vector3(0, 0, 0), direction: Vector3(0, 0, 1))
+        let second = ReferenceEntity.Kind.axis(origin: Vector3(0.3, 0, 25), direction: Vector3(0, 0, -1))
+
+        let result = try XCTUnwrap(ReferenceEntity.Kind.measure(first, second))
+
+        XCTAssertEqual(try XCTUnwrap(result.distance), 0.3, accuracy: 1e-9)
+        XCTAssertEqual(try XCTUnwrap(result.angle), 0, accuracy: 1e-9)
+    }
+
+    func testSkewAxesAngleAndMinimumDistance() throws {
+        let first = ReferenceEntity.Kind.axis(origin: Vector3(0, 0, 0), direction: Vector3(1, 0, 0))
+        let second = ReferenceEntity.Kind.axis(origin: Vector3(4, 9, 2), direction: Vector3(0, 1, 0))
+
+        let result = try XCTUnwrap(ReferenceEntity.Kind.measure(first, second))
+
+        XCTAssertEqual(try XCTUnwrap(result.distance), 2, accuracy: 1e-9)
+        XCTAssertEqual(try XCTUnwrap(result.angle), 90, accuracy: 1e-9)
+        XCTAssertTrue(result.from.isApproximatelyEqual(to: Vector3(4, 0, 0), tolerance: 1e-9))
+        XCTAssertTrue(result.to.isApproximatelyEqual(to: Vector3(4, 0, 2), tolerance: 1e-9))
+    }
+
+    func testAxisParallelToPlaneReportsDistance() throws {
+        let axis = ReferenceEntity.Kind.axis(origin: Vector3(0, 0, 6), direction: Vector3(1, 0, 0))
+        let plane = ReferenceEntity.Kind.plane(origin: Vector3(0, 0, 1), normal: Vector3(0, 0, 1))
+
+        let result = try XCTUnwrap(ReferenceEntity.Kind.measure(plane, axis))
+
+        XCTAssertEqual(try XCTUnwrap(result.distance), 5, accuracy: 1e-9)
+        XCTAssertEqual(try XCTUnwrap(result.angle), 0, accuracy: 1e-9)
+    }
+
+    func testPointToAxisDistance() throws {
+        let point = ReferenceEntity.Kind.point(Vector3(3, 4, 20))
+        let axis = ReferenceEntity.Kind.axis(origin: Vector3(0, 0, 0), direction: Vector3(0, 0, 1))
+
+        let result = try XCTUnwrap(ReferenceEntity.Kind.measure(point, axis))
+
+        XCTAssertEqual(try XCTUnwrap(result.distance), 5, accuracy: 1e-9)
+        XCTAssertNil(result.angle)
+        XCTAssertEqual(result.to, Vector3(0, 0, 20))
+    }
+
+    // MARK: - System Tests
+
+    func testToolCreatesEntityWithAutoName() throws {
+        let system = ReferenceGeometrySystem()
+        system.startTool(.axisThroughPoints)
+
+        XCTAssertNil(system.addPoint(MeasurementPoint(position: Vector3(0, 0, 0), normal: Vector3(0, 0, 1))))
+        let entity = try XCTUnwrap(system.addPoint(MeasurementPoint(position: Vector3(0, 10, 0), normal: Vector3(0, 0, 1))))
+
+        XCTAssertEqual(entity.name, "Axis 1")
+        XCTAssertNil(system.activeTool)
+        XCTAssertEqual(system.entities.count, 1)
+    }
+
+    func testNamesAreNotReusedAfterDelete() {
+        let system = ReferenceGeometrySystem()
+        let first = system.add(.point(Vector3(0, 0, 0)))
+        system.remove(id: first.id)
+        let second = system.add(.point(Vector3(1, 0, 0)))
+
+        XCTAssertEqual(second.name, "Point 2")
+    }
+
+    func testIntersectionFromSelection() throws {
+        let system = ReferenceGeometrySystem()
+        let axis = system.add(.axis(origin: Vector3(1, 1, 0), direction: Vector3(0, 0, 1)))
+        let plane = system.add(.plane(origin: Vector3(0, 0, 4), normal: Vector3(0, 0, 1)))
+        system.toggleSelection(id: axis.id)
+        system.toggleSelection(id: plane.id)
+
+        let point = try XCTUnwrap(system.createIntersectionPoint())
+
+        XCTAssertEqual(point.kind, .point(Vector3(1, 1, 4)))
+        XCTAssertTrue(system.selectedIDs.isEmpty)
+    }
+
+    func testIntersectionLineFromSelection() throws {
+        let system = ReferenceGeometrySystem()
+        let plane1 = system.add(.plane(origin: Vector3(0, 0, 0), normal: Vector3(1, 0, 0)))
+        let plane2 = system.add(.plane(origin: Vector3(0, 0, 0), normal: Vector3(0, 1, 0)))
+        system.toggleSelection(id: plane1.id)
+        XCTAssertFalse(system.canCreateIntersectionLine)
+        system.toggleSelection(id: plane2.id)
+        XCTAssertTrue(system.canCreateIntersectionLine)
+
+        let axis = try XCTUnwrap(system.createIntersectionLine())
+
+        XCTAssertEqual(axis.name, "Axis 1")
+        XCTAssertTrue(system.selectedIDs.isEmpty)
+    }
+
+    func testSegmentIntersectionsWithSelectedPlane() {
+        let system = ReferenceGeometrySystem()
+        let plane = system.add(.plane(origin: Vector3(0, 0, 5), normal: Vector3(0, 0, 1)))
+        system.toggleSelection(id: plane.id)
+
+        let created = system.createSegmentIntersections(with: [
+            (start: Vector3(0, 0, 0), end: Vector3(0, 0, 10)),
+            (start: Vector3(1, 0, 0), end: Vector3(1, 0, 2)),
+            (start: Vector3(2, 0, 10), end: Vector3(2, 0, 0))
+        ])
+
+        XCTAssertEqual(created.map(\.kind), [.point(Vector3(0, 0, 5)), .point(Vector3(2, 0, 5))])
+        XCTAssertTrue(system.selectedIDs.isEmpty)
+    }
+
+    func testSegmentIntersectionsRequireOnePlane() {
+        let system = ReferenceGeometrySystem()
+        let axis = system.add(.axis(origin: Vector3(0, 0, 0), direction: Vector3(0, 0, 1)))
+        system.toggleSelection(id: axis.id)
+
+        XCTAssertNil(system.selectedPlane)
+        XCTAssertTrue(system.createSegmentIntersections(with: [(start: Vector3(0, 0, 0), end: Vector3(0, 0, 10))]).isEmpty)
+        XCTAssertNotNil(system.statusMessage)
+    }
+
+    func testMeasurementFollowsSelectionAndEntityDeletion() throws {
+        let system = ReferenceGeometrySystem()
+        let plane1 = system.add(.plane(origin: Vector3(0, 0, 0), normal: Vector3(0, 0, 1)))
+        let plane2 = system.add(.plane(origin: Vector3(0, 0, 12), normal: Vector3(0, 0, 1)))
+        system.toggleSelection(id: plane1.id)
+        system.toggleSelection(id: plane2.id)
+
+        let measurement = try XCTUnwrap(system.createMeasurement())
+
+        XCTAssertEqual(system.title(for: measurement), "Plane 1 → Plane 2")
+        XCTAssertEqual(try XCTUnwrap(system.result(for: measurement)?.distance), 12, accuracy: 1e-9)
+
+        system.remove(id: plane2.id)
+        XCTAssertTrue(system.measurements.isEmpty)
+    }
+
+    // MARK: - Measurement Integration Tests
+
+    func testDirectionConstraintProjectsAlongReference() throws {
+        let measurementSystem = MeasurementSystem()
+        measurementSystem.startMeasurement(type: .distance)
+        _ = measurementSystem.addPoint(MeasurementPoint(position: Vector3(0, 0, 0), normal: Vector3(0, 0, 1)))
+
+        measurementSystem.toggleDirectionConstraint(Vector3(0, 2, 0))
+        let endpoint = try XCTUnwrap(measurementSystem.calculateConstrainedEndpoint(snapPoint: Vector3(3, 7, 1)))
+
+        XCTAssertEqual(endpoint, Vector3(0, 7, 0))
+    }
+
+    func testPickedPointsSnapToReferencePoint() throws {
+        let measurementSystem = MeasurementSystem()
+        measurementSystem.referenceEntities = [ReferenceEntity(name: "Point 1", kind: .point(Vector3(0.5, 0, 0)))]
+        let ray = Ray(origin: SIMD3(0, 0, 100), direction: SIMD3(0, 0, -1))
+
+        let point = try XCTUnwrap(measurementSystem.referenceSnapPoint(ray: ray, surfacePosition: nil))
+
+        XCTAssertEqual(point.position, Vector3(0.5, 0, 0))
+    }
+
+    func testHiddenReferencesBehindSurfaceDoNotSnap() {
+        let measurementSystem = MeasurementSystem()
+        measurementSystem.referenceEntities = [ReferenceEntity(name: "Point 1", kind: .point(Vector3(0, 0, -50)))]
+        let ray = Ray(origin: SIMD3(0, 0, 100), direction: SIMD3(0, 0, -1))
+
+        XCTAssertNil(measurementSystem.referenceSnapPoint(ray: ray, surfacePosition: Vector3(0, 0, 0)))
+    }
+}
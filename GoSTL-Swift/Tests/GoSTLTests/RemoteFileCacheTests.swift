@@ -0,0 +1,156 @@
+import XCTest
+@testable import GoSTL
+
+final class RemoteFileCacheTests: XCTestCase {
+    private var root: URL!
+    private var cache: RemoteFileCache!
+
+    override func setUp() {
+        super.setUp()
+        root = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-remote-\(UUID().uuidString.prefix(8))")
+        let configuration = URLSessionConfiguration.ephemeral
+        configuration.protocolClasses = [StubProtocol.self]
+        cache = RemoteFileCache(root: root, session: URLSession(configuration: configuration))
+        StubProtocol.responses = []
+        StubProtocol.requests = []
+    }
+
+    override func tearDown() {
+        try? FileManager.default.removeItem(at: root)
+        super.tearDown()
+    }
+
+    func testRemoteArgumentsAreRecognized() {
+        XCTAssertNotNil(RemoteFileCache.remoteURL("https://example.com/part.stl"))
+        XCTAssertNotNil(RemoteFileCache.remoteURL("s3://bucket/parts/part.3mf"))
+        XCTAssertNil(RemoteFileCache.remoteURL("/tmp/part.stl"))
+        XCTAssertNil(RemoteFileCache.remoteURL("part.stl"))
+        XCTAssertNil(RemoteFileCache.remoteURL("ftp://example.com/part.stl"))
+    }
+
+    func testS3URLsMapToHTTPEndpoints() throws {
+        let url = try XCTUnwrap(URL(string: "s3://models/parts/bracket%20v2.stl"))
+
+        XCTAssertEqual(RemoteFileCache.httpURL(for: url, environment: [:])?.absoluteString,
+                       "https://models.s3.amazonaws.com/parts/bracket%20v2.stl")
+        XCTAssertEqual(RemoteFileCache.httpURL(for: url, environment: ["AWS_REGION": "eu-central-1"])?.absoluteString,
+                       "https://models.s3.eu-central-1.amazonaws.com/parts/bracket%20v2.stl")
+        XCTAssertEqual(RemoteFileCache.httpURL(for: url, environment: ["AWS_ENDPOINT_URL": "http://localhost:9000/"])?.absoluteString,
+                       "http://localhost:9000/models/parts/bracket%20v2.stl")
+        XCTAssertNil(RemoteFileCache.httpURL(for: try XCTUnwrap(URL(string: "s3://models")), environment: [:]))
+    }
+
+    func testCachedFileKeepsAModelName() throws {
+        let attachment = try XCTUnwrap(URL(string: "https://tracker.example.com/attachments/download/123"))
+        try FileManager.default.createDirectory(at: root, withIntermediateDirectories: true)
+        let gzip = root.appendingPathComponent("gzip")
+        try Data([0x1F, 0x8B, 0x08, 0x00]).write(to: gzip)
+        let plain = root.appendingPathComponent("plain")
+        try Data("solid part".utf8).write(to: plain)
+
+        XCTAssertEqual(RemoteFileCache.fileName(for: attachment, suggested: "case.stl.gz", contents: gzip), "case.stl.gz")
+        XCTAssertEqual(RemoteFileCache.fileName(for: try XCTUnwrap(URL(string: "https://example.com/a/part.3mf")), suggested: "x", contents: plain), "part.3mf")
+        // The server's extension is never used
+        XCTAssertEqual(RemoteFileCache.fileName(for: attachment, suggested: "run.py", contents: plain), "run.stl")
+        XCTAssertEqual(RemoteFileCache.fileName(for: attachment, suggested: nil, contents: gzip), "model.stl.gz")
+        XCTAssertEqual(cache.directory(for: attachment), cache.directory(for: attachment))
+        XCTAssertNotEqual(cache.directory(for: attachment), cache.directory(for: try XCTUnwrap(URL(string: "https://example.com/a/part.3mf"))))
+    }
+
+    func testScriptsAndArchivesAreNotDownloaded() throws {
+        for name in ["part.scad", "build.py", "plates.yaml", "plates.yml", "models.zip", "part.scad.gz"] {
+            let url = try XCTUnwrap(URL(string: "https://example.com/files/\(name)"))
+            XCTAssertFalse(RemoteFileCache.isDownloadable(url), name)
+            XCTAssertThrowsError(try cache.fetch(url), name)
+        }
+        XCTAssertTrue(StubProtocol.requests.isEmpty)
+        XCTAssertTrue(RemoteFileCache.isDownloadable(try XCTUnwrap(URL(string: "https://example.com/files/part.stl.zst"))))
+        XCTAssertTrue(RemoteFileCache.isDownloadable(try XCTUnwrap(URL(string: "https://tracker.example.com/attachments/download/123"))))
+    }
+
+    func testDownloadsOnceAndRevalidatesWithTheETag() throws {
+        let url = try XCTUnwrap(URL(string: "https://example.com/files/part.stl"))
+        StubProtocol.responses = [
+            (200, ["ETag": "\"v1\"", "Last-Modified": "Wed, 14 Oct 2026 10:00:00 GMT"], Data("solid part".utf8)),
+            (304, [:], Data())
+        ]
+
+        let first = try cache.fetch(url)
+        XCTAssertEqual(first.source, .downloaded)
+        XCTAssertEqual(first.file.lastPathComponent, "part.stl")
+        XCTAssertEqual(try Data(contentsOf: first.file), Data("solid part".utf8))
+        XCTAssertEqual(cache.entry(for: url)?.etag, "\"v1\"")
+
+        let second = try cache.fetch(url)
+        XCTAssertEqual(second.source, .revalidated)
+        XCTAssertEqual(second.file, first.file)
+        XCTAssertEqual(StubProtocol.requests.last?.value(forHTTPHeaderField: "If-None-Match"), "\"v1\"")
+        XCTAssertEqual(StubProtocol.requests.last?.value(forHTTPHeaderField: "If-Modified-Since"), "Wed, 14 Oct 2026 10:00:00 GMT")
+
+        // No more responses: the stub fails like a missing connection
+        let third = try cache.fetch(url)
+        XCTAssertEqual(third.source, .offline)
+        XCTAssertEqual(try Data(contentsOf: third.file), Data("solid part".utf8))
+    }
+
+    func testChangedFileReplacesTheCachedCopy() throws {
+        let url = try XCTUnwrap(URL(string: "https://example.com/files/part.stl"))
+        StubProtocol.responses = [
+            (200, ["ETag": "\"v1\""], Data("v1".utf8)),
+            (200, ["ETag": "\"v2\""], Data("v2".utf8))
+        ]
+
+        _ = try cache.fetch(url)
+        let changed = try cache.fetch(url)
+
+        XCTAssertEqual(changed.source, .downloaded)
+        XCTAssertEqual(try Data(contentsOf: changed.file), Data("v2".utf8))
+        XCTAssertEqual(cache.entry(for: url)?.etag, "\"v2\"")
+    }
+
+    func testOnlyConnectionErrorsUseTheCachedCopy() {
+        XCTAssertTrue(RemoteFileCache.isOffline(URLError(.notConnectedToInternet)))
+        XCTAssertTrue(RemoteFileCache.isOffline(URLError(.timedOut)))
+        XCTAssertFalse(RemoteFileCache.isOffline(URLError(.serverCertificateUntrusted)))
+        XCTAssertFalse(RemoteFileCache.isOffline(CocoaError(.fileWriteOutOfSpace)))
+    }
+
+    func testErrorsWithoutACachedCopy() throws {
+        StubProtocol.responses = [(404, [:], Data())]
+
+        XCTAssertThrowsError(try cache.fetch(try XCTUnwrap(URL(string: "https://example.com/missing.stl")))) { error in
+            XCTAssertTrue(error.localizedDescription.contains("HTTP 404"))
+        }
+        XCTAssertThrowsError(try cache.fetch(try XCTUnwrap(URL(string: "https://example.com/offline.stl"))))
+        XCTAssertThrowsError(try AnalyzeCommand.parse(["https://example.com/part.scad", "--watch"]))
+    }
+}
+
+/// Answers requests with queued responses; fails like a missing connection when none are left
+private final class StubProtocol: URLProtocol {
+    nonisolated(unsafe) static var responses: [(status: Int, headers: [String: String], body: Data)] = []
+    nonisolated(unsafe) static var requests: [URLRequest] = []
+
+    override class func canInit(with request: URLRequest) -> Bool {
+        true
+    }
+
+    override class func canonicalRequest(for request: URLRequest) -> URLRequest {
+        request
+    }
+
+    override func startLoading() {
+        Self.requests.append(request)
+        guard !Self.responses.isEmpty, let url = request.url else {
+            client?.urlProtocol(self, didFailWithError: URLError(.notConnectedToInternet))
+            return
+        }
+        let next = Self.responses.removeFirst()
+        let response = HTTPURLResponse(url: url, statusCode: next.status, httpVersion: "HTTP/1.1", headerFields: next.headers)!
+        client?.urlProtocol(self, didReceive: response, cacheStoragePolicy: .notAllowed)
+        client?.urlProtocol(self, didLoad: next.body)
+        client?.urlProtocolDidFinishLoading(self)
+    }
+
+    override func stopLoading() {}
+}
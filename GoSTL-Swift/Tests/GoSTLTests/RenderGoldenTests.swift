@@ -0,0 +1,53 @@
+import Metal
+import XCTest
+@testable import GoSTL
+
+/// Golden-image tests of the mesh renderer
+///
+/// Goldens live in Tests/Goldens and depend on the GPU. Record them before changing shaders or
+/// clipping with `GOSTL_UPDATE_GOLDENS=1 swift test --filter RenderGoldenTests`, then run the tests
+/// again after the change. Renders and diff images of failing cases are written to a temporary folder.
+final class RenderGoldenTests: XCTestCase {
+    private static let goldens = URL(fileURLWithPath: #filePath)
+        .deletingLastPathComponent()
+        .deletingLastPathComponent()
+        .appendingPathComponent("Goldens", isDirectory: true)
+
+    func testReferenceRendersMatchGoldens() throws {
+        guard MTLCreateSystemDefaultDevice() != nil else {
+            throw XCTSkip("Metal is not available")
+        }
+        let renderer = try OffscreenRenderer()
+        let test = RenderSelfTest(goldens: Self.goldens)
+        let update = ProcessInfo.processInfo.environment["GOSTL_UPDATE_GOLDENS"] == "1"
+        let artifacts = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-render-goldens", isDirectory: true)
+
+        var missing: [String] = []
+        for testCase in RenderSelfTest.cases {
+            switch try test.run(testCase, with: renderer, update: update, artifacts: artifacts) {
+            case .passed, .recorded:
+                break
+            case .missing:
+                missing.append(testCase.name)
+            case .failed(let result):
+                XCTFail("\(testCase.name): \(SelfTestRenderCommand.summary(result)); see \(artifacts.path)")
+            }
+        }
+        if !missing.isEmpty {
+            throw XCTSkip("No goldens for \(missing.joined(separator: ", ")); record them with GOSTL_UPDATE_GOLDENS=1")
+        }
+    }
+
+    func testRenderIsDeterministic() throws {
+        guard MTLCreateSystemDefaultDevice() != nil else {
+            throw XCTSkip("Metal is not available")
+        }
+        let renderer = try OffscreenRenderer()
+        let test = RenderSelfTest(goldens: Self.goldens, size: 64)
+        let sphere = try XCTUnwrap(RenderSelfTest.cases.first { $0.name == "sphere-cutaway" })
+
+        let result = try PerceptualDiff.compare(test.render(sphere, with: renderer), test.render(sphere, with: renderer))
+
+        XCTAssertEqual(result.differingPixels, 0)
+    }
+}
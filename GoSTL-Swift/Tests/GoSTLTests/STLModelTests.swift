@@ -290,4 +290,35 @@ final class STLModelTests: XCTestCase {
         XCTAssertGreaterThan(analysis.weightPLA15, 0)
         XCTAssertLessThan(analysis.weightPLA15, analysis.weightPLA100)
     }
+
+    // MARK: - Recentering Tests
+
+    func testRecenteringOffsetZeroForSmallModels() {
+        let model = createTestCube()
+        XCTAssertEqual(STLModel.recenteringOffset(for: model.boundingBox()), Vector3.zero)
+    }
+
+    func testRecenteringOffsetForLargeCoordinates() {
+        let model = createTestCube().translated(by: Vector3(2_500_000.4, 1_200_000, 300))
+        let offset = STLModel.recenteringOffset(for: model.boundingBox())
+
+        // Rounded bounding box center
+        XCTAssertEqual(offset, Vector3(2_500_001, 1_200_001, 301))
+    }
+
+    func testTranslatedRoundTrip() {
+        let original = createTestCube().translated(by: Vector3(654_321.125, 123_456.5, 1_000))
+        let offset = STLModel.recenteringOffset(for: original.boundingBox())
+        let recentered = original.translated(by: -offset)
+
+        // Rendering space stays small enough for float precision
+        let bbox = recentered.boundingBox()
+        XCTAssertLessThan(Swift.max(abs(bbox.min.x), abs(bbox.max.x), abs(bbox.min.y), abs(bbox.max.y)), 1)
+
+        // Translating back restores the original coordinates exactly
+        let restored = recentered.translated(by: offset)
+        XCTAssertEqual(restored.triangles[0].v1, original.triangles[0].v1)
+        XCTAssertEqual(restored.boundingBox().min, original.boundingBox().min)
+        XCTAssertEqual(recentered.volume(), createTestCube().volume(), accuracy: 1e-6)
+    }
 }
@@ -0,0 +1,107 @@
+import XCTest
+@testable import GoSTL
+
+final class STLRoundTripTests: XCTestCase {
+
+    /// A binary STL with a custom header and one facet per attribute value
+    private func binarySTL(header: String, attributes: [UInt16]) -> Data {
+        var headerBytes = [UInt8](repeating: 0, count: 80)
+        for (index, byte) in header.utf8.prefix(80).enumerated() {
+            headerBytes[index] = byte
+        }
+        var data = Data(headerBytes)
+
+        var count = UInt32(attributes.count)
+        data.append(Data(bytes: &count, count: 4))
+
+        for (index, attribute) in attributes.enumerated() {
+            let x = Float(index)
+            var floats: [Float] = [0, 0, 1, x, 0, 0, x + 1, 0, 0, x, 1, 0]
+            data.append(Data(bytes: &floats, count: 48))
+            var value = attribute
+            data.append(Data(bytes: &value, count: 2))
+        }
+        return data
+    }
+
+    // MARK: - Parsing
+
+    func testParseKeepsHeaderAndAttributes() throws {
+        let data = binarySTL(header: "COLOR=\u{7F}\u{7F}\u{7F}\u{FF} exported by CAD", attributes: [0x7C1F, 0, 0x801F])
+        let model = try STLParser.parse(data: data, name: "part")
+
+        XCTAssertEqual(model.header, data.prefix(80))
+        XCTAssertEqual(model.triangles.map(\.attribute), [0x7C1F, 0, 0x801F])
+        XCTAssertTrue(model.hasAttributes)
+    }
+
+    func testHeaderText() throws {
+        let model = try STLParser.parse(data: binarySTL(header: "made by CAD", attributes: [0]))
+        XCTAssertEqual(model.headerText, "made by CAD")
+    }
+
+    func testASCIIHasNoHeaderOrAttributes() throws {
+        let ascii = """
+        solid t
+        facet normal 0 0 1
+          outer loop
+            vertex 0 0 0
+            vertex 1 0 0
+            vertex 0 1 0
+          endloop
+        endfacet
+        endsolid t
+        """
+        let model = try STLParser.parse(data: ascii.data(using: .ascii)!)
+
+        XCTAssertNil(model.header)
+        XCTAssertNil(model.headerText)
+        XCTAssertFalse(model.hasAttributes)
+    }
+
+    // MARK: - Export
+
+    func testRoundTripIsByteIdentical() throws {
+        let data = binarySTL(header: "solidworks header", attributes: [1, 2, 0xFFFF])
+        let model = try STLParser.parse(data: data)
+
+        XCTAssertEqual(STLExporter.binaryData(model: model), data)
+    }
+
+    func testClearHeaderWritesGoSTLHeader() throws {
+        let data = binarySTL(header: "original", attributes: [5])
+        let model = try STLParser.parse(data: data, name: "part")
+        let exported = STLExporter.binaryData(model: model, clearHeader: true)
+
+        let header = String(decoding: exported.prefix(80), as: UTF8.self).trimmingCharacters(in: CharacterSet(charactersIn: "\0"))
+        XCTAssertEqual(header, "GoSTL Export - part")
+        XCTAssertEqual(try STLParser.parse(data: exported).triangles[0].attribute, 5)
+    }
+
+    func testClearAttributesWritesZeros() throws {
+        let data = binarySTL(header: "original", attributes: [5, 6])
+        let model = try STLParser.parse(data: data)
+        let exported = try STLParser.parse(data: STLExporter.binaryData(model: model, clearAttributes: true))
+
+        XCTAssertEqual(exported.header, data.prefix(80))
+        XCTAssertFalse(exported.hasAttributes)
+    }
+
+    func testModelWithoutHeaderGetsGoSTLHeader() {
+        let model = STLModel(triangles: [Triangle(v1: .zero, v2: Vector3(1, 0, 0), v3: Vector3(0, 1, 0))], name: "cube")
+        let exported = STLExporter.binaryData(model: model)
+
+        XCTAssertTrue(String(decoding: exported.prefix(80), as: UTF8.self).hasPrefix("GoSTL Export - cube"))
+    }
+
+    // MARK: - Transforms
+
+    func testTransformsKeepHeaderAndAttributes() throws {
+        let model = try STLParser.parse(data: binarySTL(header: "keep me", attributes: [42]))
+
+        for transformed in [model.translated(by: Vector3(1, 2, 3)), model.scaled(by: 25.4)] {
+            XCTAssertEqual(transformed.header, model.header)
+            XCTAssertEqual(transformed.triangles[0].attribute, 42)
+        }
+    }
+}
@@ -0,0 +1,65 @@
+import XCTest
+@testable import GoSTL
+
+final class ScaleCheckTests: XCTestCase {
+    // MARK: - Detection Tests
+
+    func testTinyModelSuggestsMetersFirst() throws {
+        let finding = try XCTUnwrap(ScaleCheck.check(size: Vector3(0.05, 0.03, 0.08)))
+
+        XCTAssertEqual(finding.fixes, [.metersToMillimeters, .inchesToMillimeters])
+        XCTAssertEqual(finding.extent, 0.08)
+        XCTAssertTrue(finding.reason.contains("meters"))
+    }
+
+    func testHugeModelSuggestsMicrometers() throws {
+        let finding = try XCTUnwrap(ScaleCheck.check(size: Vector3(3000, 2000, 8000)))
+
+        XCTAssertEqual(finding.fixes, [.micrometersToMillimeters])
+        XCTAssertTrue(finding.reason.contains("8.0 m"))
+    }
+
+    func testInchFractionsSuggestInches() throws {
+        let finding = try XCTUnwrap(ScaleCheck.check(size: Vector3(4, 2.5, 0.75)))
+
+        XCTAssertEqual(finding.fixes, [.inchesToMillimeters])
+        XCTAssertTrue(finding.reason.contains("4 × 2.5 × 0.75"))
+    }
+
+    func testPlausibleModelsAreNotFlagged() {
+        XCTAssertNil(ScaleCheck.check(size: Vector3(120, 80, 35)))
+        XCTAssertNil(ScaleCheck.check(size: Vector3(10, 10, 10)))
+        XCTAssertNil(ScaleCheck.check(size: Vector3(20, 20, 1.5)))
+        XCTAssertNil(ScaleCheck.check(size: Vector3(12.3, 4.1, 2.7)))
+        XCTAssertNil(ScaleCheck.check(size: Vector3(1500, 600, 900)))
+    }
+
+    func testLargeInchLikeModelsAreNotFlagged() {
+        // 40.25 mm parts are common; only small ones are suspicious
+        XCTAssertNil(ScaleCheck.check(size: Vector3(40.25, 30, 10)))
+    }
+
+    func testEmptyModelIsNotFlagged() {
+        XCTAssertNil(ScaleCheck.check(size: .zero))
+    }
+
+    func testFixFactors() {
+        XCTAssertEqual(ScaleCheck.Fix.inchesToMillimeters.factor, 25.4)
+        XCTAssertEqual(ScaleCheck.Fix.metersToMillimeters.factor, 1000)
+        XCTAssertEqual(ScaleCheck.Fix.micrometersToMillimeters.factor, 0.001)
+    }
+
+    // MARK: - Scaling Tests
+
+    func testScaledModel() {
+        let triangle = Triangle(v1: Vector3(0, 0, 0), v2: Vector3(1, 0, 0), v3: Vector3(0, 2, 0))
+        let model = STLModel(triangles: [triangle], name: "part")
+
+        let scaled = model.scaled(by: 25.4)
+
+        XCTAssertEqual(scaled.triangles[0].v3, Vector3(0, 50.8, 0))
+        XCTAssertEqual(scaled.triangles[0].normal, triangle.normal)
+        XCTAssertEqual(scaled.boundingBox().size, Vector3(25.4, 50.8, 0))
+        XCTAssertEqual(scaled.name, "part")
+    }
+}
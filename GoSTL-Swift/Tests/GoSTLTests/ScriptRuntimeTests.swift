@@ -0,0 +1,152 @@
+import XCTest
+@testable import GoSTL
+
+final class ScriptRuntimeTests: XCTestCase {
+
+    private func cube() -> STLModel {
+        STLModel(triangles: TestModels.box(from: .zero, to: Vector3(10, 20, 30)), name: "cube")
+    }
+
+    // MARK: - Binding Tests
+
+    func testScriptSeesModelAnalysis() throws {
+        let runtime = ScriptRuntime()
+        runtime.setModel(cube())
+
+        let report = try runtime.run(source: """
+            print(gostl.model.name, gostl.model.triangleCount);
+            gostl.result("height", gostl.model.dimensions.z);
+            """)
+
+        XCTAssertEqual(report.output, ["cube 12"])
+        XCTAssertEqual(report.results.first?.name, "height")
+        XCTAssertEqual(report.results.first?.value as? Double, 30)
+    }
+
+    func testMeasurementsAreRecorded() throws {
+        let runtime = ScriptRuntime()
+
+        let report = try runtime.run(source: """
+            const d = gostl.distance([0, 0, 0], {x: 3, y: 4, z: 0});
+            const a = gostl.angle([1, 0, 0], [0, 0, 0], [0, 1, 0]);
+            console.log(d, a);
+            """)
+
+        XCTAssertEqual(report.output, ["5 90"])
+        XCTAssertEqual(report.measurements.count, 2)
+        XCTAssertEqual(report.measurements[0].type, .distance)
+        XCTAssertEqual(report.measurements[1].value, 90, accuracy: 1e-9)
+    }
+
+    func testClosestVertexSnapsToMesh() throws {
+        let runtime = ScriptRuntime()
+        runtime.setModel(cube())
+
+        let report = try runtime.run(source: """
+            const v = gostl.closestVertex(gostl.model, [9, 19, 29]);
+            print(v.x, v.y, v.z);
+            """)
+
+        XCTAssertEqual(report.output, ["10 20 30"])
+    }
+
+    func testFailMarksReportAsFailed() throws {
+        let runtime = ScriptRuntime()
+
+        let report = try runtime.run(source: "gostl.fail('too large');")
+
+        XCTAssertFalse(report.passed)
+        XCTAssertEqual(report.output, ["FAIL: too large"])
+    }
+
+    func testUncaughtExceptionThrows() {
+        let runtime = ScriptRuntime()
+
+        XCTAssertThrowsError(try runtime.run(source: "\n\nthrow new Error('boom');")) { error in
+            guard case ScriptError.exception(let message, let line) = error else {
+                return XCTFail("Expected a script exception")
+            }
+            XCTAssertTrue(message.contains("boom"))
+            XCTAssertEqual(line, 3)
+        }
+    }
+
+    func testLoadingMissingFileCanBeCaught() throws {
+        let runtime = ScriptRuntime(baseURL: FileManager.default.temporaryDirectory)
+
+        let report = try runtime.run(source: """
+            try { gostl.load("does-not-exist.stl"); } catch (e) { print("caught"); }
+            """)
+
+        XCTAssertEqual(report.output, ["caught"])
+    }
+
+    func testReportEncodesJSON() throws {
+        let runtime = ScriptRuntime()
+        let report = try runtime.run(source: "gostl.result('count', 3); gostl.distance([0,0,0],[0,0,2]);")
+
+        let json = try XCTUnwrap(JSONSerialization.jsonObject(with: report.jsonData()) as? [String: Any])
+
+        XCTAssertEqual(json["passed"] as? Bool, true)
+        XCTAssertEqual((json["measurements"] as? [[String: Any]])?.first?["value"] as? Double, 2)
+    }
+
+    func testResultsMustBeJSONValues() throws {
+        let runtime = ScriptRuntime()
+        let report = try runtime.run(source: "gostl.result('ok', true); gostl.result('name', 'part');")
+        XCTAssertEqual(report.results.map(\.name), ["ok", "name"])
+        XCTAssertNoThrow(try report.jsonData())
+
+        for value in ["0 / 0", "1 / 0", "new Date()", "{a: 1}"] {
+            XCTAssertThrowsError(try ScriptRuntime().run(source: "gostl.result('r', \(value));"), value) { error in
+                XCTAssertTrue(error.localizedDescription.contains("finite number"), value)
+            }
+        }
+    }
+
+    func testNonFinitePointsAreRejected() {
+        XCTAssertThrowsError(try ScriptRuntime().run(source: "gostl.distance([0, 0, 0], [0 / 0, 0, 0]);"))
+    }
+
+    func testReportSurvivesHandOver() throws {
+        let report = try ScriptRuntime().run(source: """
+            print("checked");
+            gostl.result("count", 3);
+            gostl.result("ok", true);
+            gostl.result("name", "part");
+            gostl.radius([5, 0, 0], [0, 5, 0], [-5, 0, 0]);
+            gostl.fail("too large");
+            """)
+
+        let handedOver = try ScriptReport(archiveData: report.archiveData())
+
+        XCTAssertEqual(handedOver.output, report.output)
+        XCTAssertEqual(handedOver.failures, ["too large"])
+        XCTAssertEqual(handedOver.results.map(\.name), ["count", "ok", "name"])
+        XCTAssertEqual(handedOver.results[0].value as? Double, 3)
+        XCTAssertEqual(handedOver.results[1].value as? Bool, true)
+        XCTAssertEqual(handedOver.results[2].value as? String, "part")
+        XCTAssertEqual(handedOver.measurements.first?.type, .radius)
+        XCTAssertEqual(handedOver.measurements.first?.circle?.radius ?? 0, 5, accuracy: 1e-9)
+    }
+
+    func testMissingReportIsAnError() throws {
+        // /usr/bin/false exits without writing a report, like a script that failed to run
+        let job = JobContext()
+        let script = FileManager.default.temporaryDirectory.appendingPathComponent("missing.js")
+
+        XCTAssertThrowsError(try ScriptRuntime.runInSubprocess(url: script, model: nil, job: job, executable: URL(fileURLWithPath: "/usr/bin/false"))) { error in
+            guard case ScriptError.processFailed = error else {
+                return XCTFail("unexpected error \(error)")
+            }
+        }
+    }
+
+    // MARK: - Command Line Tests
+
+    func testCommandLineRecognizesSubcommands() {
+        XCTAssertTrue(GoSTLCommand.handles(["gostl", "script", "inspect.js"]))
+        XCTAssertFalse(GoSTLCommand.handles(["gostl", "model.stl"]))
+        XCTAssertFalse(GoSTLCommand.handles(["gostl"]))
+    }
+}
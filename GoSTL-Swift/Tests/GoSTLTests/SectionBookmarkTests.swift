@@ -0,0 +1,141 @@
+import XCTest
+@testable import GoSTL
+
+final class SectionBookmarkTests: XCTestCase {
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-tests-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    /// 40 x 20 x 10 mm model rendered 100 mm left of its file position
+    private let offset = Vector3(100, 0, 0)
+    private let modelBounds: [[Double]] = [[-20, 20], [0, 20], [0, 10]]
+
+    private func slicing(zMax: Double) -> SlicingState {
+        let state = SlicingState()
+        state.modelBounds = modelBounds
+        state.bounds = modelBounds
+        state.bounds[2][1] = zMax
+        state.fillCrossSections = true
+        state.isVisible = true
+        return state
+    }
+
+    // MARK: - Capture Tests
+
+    func testCaptureStoresOriginalCoordinates() {
+        let camera = Camera()
+        camera.target = SIMD3(1, 2, 3)
+        camera.cutawayDepth = 40
+
+        let bookmark = SectionBookmark(name: "Top half", slicingState: slicing(zMax: 5), camera: camera, coordinateOffset: offset)
+
+        XCTAssertEqual(bookmark.bounds, [[80, 120], [0, 20], [0, 5]])
+        XCTAssertTrue(bookmark.fillCrossSections)
+        XCTAssertEqual(bookmark.camera.target, Vector3(101, 2, 3))
+        XCTAssertEqual(bookmark.cutawayDepth, 40)
+    }
+
+    func testCaptureWithoutSlicing() {
+        let state = slicing(zMax: 5)
+        state.isVisible = false
+
+        let bookmark = SectionBookmark(name: "View", slicingState: state, camera: Camera(), coordinateOffset: offset)
+
+        XCTAssertNil(bookmark.bounds)
+        XCTAssertNil(bookmark.renderBounds(coordinateOffset: offset, modelBounds: modelBounds))
+        XCTAssertEqual(bookmark.summary(), "view only")
+    }
+
+    // MARK: - Recall Tests
+
+    func testRenderBoundsFollowOffset() throws {
+        let bookmark = SectionBookmark(name: "Top half", slicingState: slicing(zMax: 5), camera: Camera(), coordinateOffset: offset)
+
+        // Recentered differently after a reload
+        let bounds = try XCTUnwrap(bookmark.renderBounds(coordinateOffset: Vector3(90, 0, 0), modelBounds: [[-10, 30], [0, 20], [0, 10]]))
+
+        XCTAssertEqual(bounds, [[-10, 30], [0, 20], [0, 5]])
+    }
+
+    func testRenderBoundsAreClampedToModel() throws {
+        let bookmark = SectionBookmark(
+            name: "Old revision",
+            bounds: [[80, 120], [0, 20], [4, 12]],
+            fillCrossSections: false,
+            camera: CameraKeyframe(distance: 100, angleX: 0, angleY: 0, target: .zero),
+            cutawayDepth: nil
+        )
+
+        // The model got shorter: 8 mm high
+        let bounds = try XCTUnwrap(bookmark.renderBounds(coordinateOffset: offset, modelBounds: [[-20, 20], [0, 20], [0, 8]]))
+
+        XCTAssertEqual(bounds[2], [4, 8])
+    }
+
+    // MARK: - Summary Tests
+
+    func testSummaryListsClippedAxes() {
+        let camera = Camera()
+        camera.cutawayDepth = 40
+        let bookmark = SectionBookmark(name: "Top half", slicingState: slicing(zMax: 5), camera: camera, coordinateOffset: offset)
+
+        XCTAssertEqual(bookmark.summary(modelBounds: modelBounds, coordinateOffset: offset), "Z 0.000…5.000 mm, cutaway 40.0 mm")
+        XCTAssertTrue(bookmark.summary().hasPrefix("X 80.000…120.000 mm"))
+    }
+
+    // MARK: - Persistence Tests
+
+    func testSidecarRoundTrip() throws {
+        let model = directory.appendingPathComponent("housing.stl")
+        var sidecar = ModelSidecar()
+        sidecar.sections = [SectionBookmark(name: "Top half", slicingState: slicing(zMax: 5), camera: Camera(), coordinateOffset: offset)]
+
+        try sidecar.write(for: model)
+        let loaded = try ModelSidecar.load(for: model)
+
+        XCTAssertEqual(loaded.sections.map(\.name), ["Top half"])
+        XCTAssertEqual(loaded.sections.first?.bounds, [[80, 120], [0, 20], [0, 5]])
+        XCTAssertEqual(loaded.sections.first?.fillCrossSections, true)
+        XCTAssertFalse(loaded.isEmpty)
+    }
+
+    func testSidecarWithoutSections() throws {
+        let model = directory.appendingPathComponent("bracket.stl")
+        let json = #"{"version": 1, "properties": [{"key": "Revision", "value": "B"}]}"#
+        try Data(json.utf8).write(to: ModelSidecar.url(for: model))
+
+        let loaded = try ModelSidecar.load(for: model)
+
+        XCTAssertEqual(loaded.properties.map(\.value), ["B"])
+        XCTAssertTrue(loaded.sections.isEmpty)
+    }
+
+    // MARK: - Report Tests
+
+    func testReportListsSections() {
+        let bookmark = SectionBookmark(name: "Top half", slicingState: slicing(zMax: 5), camera: Camera(), coordinateOffset: offset)
+
+        let report = InspectionReport.generate(
+            fileName: "housing.stl",
+            referenceGeometry: ReferenceGeometrySystem(),
+            sections: [(bookmark: bookmark, imageFile: "housing-inspection-section-1.png")]
+        )
+
+        XCTAssertTrue(report.contains("SECTIONS"))
+        XCTAssertTrue(report.contains("  Top half: X 80.000…120.000 mm"))
+        XCTAssertTrue(report.contains("[housing-inspection-section-1.png]"))
+    }
+
+    func testReportWithoutSections() {
+        let report = InspectionReport.generate(fileName: "housing.stl", referenceGeometry: ReferenceGeometrySystem())
+
+        XCTAssertFalse(report.contains("SECTIONS"))
+    }
+}
@@ -0,0 +1,69 @@
+import XCTest
+@testable import GoSTL
+
+final class SectionPropertiesTests: XCTestCase {
+    func testRectangularBeamMatchesClosedForm() throws {
+        // 20 × 10 mm section, 100 mm long along Z
+        let beam = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(20, 10, 100)))
+        let sections = try SectionProperties.compute(model: beam, axis: .z, step: 10)
+
+        XCTAssertEqual(sections.stations.map(\.position), [10, 20, 30, 40, 50, 60, 70, 80, 90])
+        let station = sections.stations[4]
+        XCTAssertEqual(station.area, 200, accuracy: 1e-9)
+        XCTAssertEqual(station.centroid.x, 10, accuracy: 1e-9)
+        XCTAssertEqual(station.centroid.y, 5, accuracy: 1e-9)
+        XCTAssertEqual(station.centroid.z, 50)
+        // b h³ / 12 about the centroidal X and Y axes
+        XCTAssertEqual(station.iu, 20 * 1000 / 12, accuracy: 1e-6)
+        XCTAssertEqual(station.iv, 10 * 8000 / 12, accuracy: 1e-6)
+        XCTAssertEqual(station.iuv, 0, accuracy: 1e-6)
+        XCTAssertEqual(station.principal.min, station.iu, accuracy: 1e-6)
+        XCTAssertEqual(station.polar, station.iu + station.iv)
+    }
+
+    func testHollowSectionSubtractsTheHole() throws {
+        // 20 × 20 mm tube with a 10 × 10 mm bore along X
+        let outer = TestModels.box(from: Vector3(0, -10, -10), to: Vector3(50, 10, 10))
+        let bore = TestModels.box(from: Vector3(0, -5, -5), to: Vector3(50, 5, 5)).map { Triangle(v1: $0.v1, v2: $0.v3, v3: $0.v2) }
+        let sections = try SectionProperties.compute(model: STLModel(triangles: outer + bore), axis: .x, step: 25)
+
+        let station = try XCTUnwrap(sections.stations.first)
+        XCTAssertEqual(station.position, 25)
+        XCTAssertEqual(station.area, 400 - 100, accuracy: 1e-9)
+        XCTAssertEqual(station.iu, (20 * 8000 - 10 * 1000) / 12, accuracy: 1e-6)
+        XCTAssertEqual(station.iv, station.iu, accuracy: 1e-6)
+    }
+
+    func testGapsHaveZeroAreaAndAreNotTheWeakestStation() throws {
+        let triangles = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10)) + TestModels.box(from: Vector3(0, 0, 20), to: Vector3(4, 10, 30))
+        let sections = try SectionProperties.compute(model: STLModel(triangles: triangles), axis: .z, step: 3)
+
+        XCTAssertEqual(sections.stations.filter { $0.area == 0 }.map(\.position), [12, 15, 18])
+        XCTAssertEqual(sections.weakestStation?.area ?? 0, 40, accuracy: 1e-9)
+    }
+
+    func testAutomaticStepAndEmptyModel() throws {
+        let beam = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 100)))
+
+        XCTAssertEqual(try SectionProperties.compute(model: beam, axis: .z).step, 5)
+        XCTAssertThrowsError(try SectionProperties.compute(model: STLModel(), axis: .z))
+    }
+
+    func testCSVNamesColumnsAfterTheSectionPlane() throws {
+        let beam = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 20, 10)))
+        let sections = try SectionProperties.compute(model: beam, axis: .y, step: 10)
+        let lines = sections.csv(decimals: 1, format: .european).split(separator: "\n")
+
+        XCTAssertEqual(lines[0], "y;area;centroid_z;centroid_x;i_z;i_x;i_zx;i_min;i_max;j")
+        XCTAssertEqual(lines.count, 2)
+        XCTAssertTrue(lines[1].hasPrefix("10,0;100,0;5,0;5,0;"))
+    }
+
+    func testCommandParsesAxisAndStep() throws {
+        let command = try SectionsCommand.parse(["beam.stl", "--axis", "x", "--step", "2.5"])
+
+        XCTAssertEqual(command.axis, .x)
+        XCTAssertEqual(command.step, 2.5)
+        XCTAssertThrowsError(try SectionsCommand.parse(["beam.stl", "--step", "0"]))
+    }
+}
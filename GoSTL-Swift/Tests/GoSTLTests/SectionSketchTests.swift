@@ -0,0 +1,104 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class SectionSketchTests: XCTestCase {
+
+    /// 20 mm square block with a 5 mm radius pin standing inside it
+    private func blockWithPin() -> STLModel {
+        let block = ModelGenerator.cube(size: Vector3(20, 20, 10))
+        let pin = ModelGenerator.cylinder(center: Vector3(2, 3, 0), radius: 5, height: 10, segments: 48)
+        return STLModel(triangles: block.triangles + pin, name: "block")
+    }
+
+    /// Slot outline: 20 mm between the centers of two 5 mm radius ends, 16 segments per half circle
+    private func slot() -> [SectionSketch.Point] {
+        var points: [SectionSketch.Point] = []
+        for step in 0...16 {
+            let angle = -Double.pi / 2 + Double.pi * Double(step) / 16
+            points.append(SectionSketch.Point(20 + 5 * cos(angle), 5 + 5 * sin(angle)))
+        }
+        for step in 0...16 {
+            let angle = Double.pi / 2 + Double.pi * Double(step) / 16
+            points.append(SectionSketch.Point(5 * cos(angle), 5 + 5 * sin(angle)))
+        }
+        return points
+    }
+
+    func testSquareWithCircle() throws {
+        let sketch = try SectionSketch(model: blockWithPin(), axis: .z, position: 5)
+
+        XCTAssertEqual(sketch.loops.count, 2)
+        XCTAssertEqual(sketch.lineCount, 4)
+        XCTAssertEqual(sketch.circleCount, 1)
+        XCTAssertEqual(sketch.arcCount, 0)
+        XCTAssertEqual(sketch.openChains, 0)
+
+        let hole = try XCTUnwrap(sketch.loops.last)
+        XCTAssertTrue(hole.isHole)
+        guard case .circle(let center, let radius) = hole.elements[0] else {
+            return XCTFail("Expected a circle")
+        }
+        XCTAssertEqual(center.x, 2, accuracy: 1e-6)
+        XCTAssertEqual(center.y, 3, accuracy: 1e-6)
+        XCTAssertEqual(radius, 5, accuracy: 1e-6)
+    }
+
+    func testSlotBecomesTwoLinesAndTwoArcs() {
+        let elements = SectionSketch.fit(slot(), tolerance: 0.05)
+
+        XCTAssertEqual(elements.count, 4)
+        let arcs = elements.compactMap { element -> (center: SectionSketch.Point, sweep: Double)? in
+            guard case .arc(_, _, let center, let sweep) = element else { return nil }
+            return (center, sweep)
+        }
+        XCTAssertEqual(arcs.count, 2)
+        for arc in arcs {
+            XCTAssertEqual(arc.sweep, .pi, accuracy: 1e-6)
+            XCTAssertTrue([SectionSketch.Point(20, 5), SectionSketch.Point(0, 5)].contains { simd_distance($0, arc.center) < 1e-6 })
+        }
+    }
+
+    func testElementsConnect() {
+        let elements = SectionSketch.fit(slot(), tolerance: 0.05)
+
+        for (element, next) in zip(elements, elements.dropFirst() + elements.prefix(1)) {
+            switch element {
+            case .line(_, let to), .arc(_, let to, _, _):
+                XCTAssertLessThan(simd_distance(to, next.start), 1e-9)
+            case .circle:
+                XCTFail("Unexpected circle")
+            }
+        }
+    }
+
+    func testCoarsePolygonStaysPolygon() {
+        let hexagon = (0..<6).map { SectionSketch.Point(cos(Double($0) * .pi / 3), sin(Double($0) * .pi / 3)) * 4 }
+
+        let elements = SectionSketch.fit(hexagon, tolerance: 0.05)
+
+        XCTAssertEqual(elements.count, 6)
+        XCTAssertTrue(elements.allSatisfy { if case .line = $0 { return true }; return false })
+    }
+
+    func testNoSection() {
+        XCTAssertThrowsError(try SectionSketch(model: blockWithPin(), axis: .z, position: 50))
+    }
+
+    func testDXFEntities() throws {
+        let dxf = DXFExporter.dxf(try SectionSketch(model: blockWithPin(), axis: .z, position: 5))
+
+        XCTAssertEqual(dxf.components(separatedBy: "\nLINE\n").count - 1, 4)
+        XCTAssertTrue(dxf.contains("CIRCLE\n8\nHOLES\n10\n2\n20\n3\n30\n0\n40\n5\n"))
+        XCTAssertTrue(dxf.hasSuffix("0\nEOF\n"))
+    }
+
+    func testOpenSCADProfile() throws {
+        let code = OpenSCADGenerator.profileModule(try SectionSketch(model: blockWithPin(), axis: .z, position: 5), moduleName: "block_section")
+
+        XCTAssertTrue(code.contains("module block_section() {"))
+        XCTAssertTrue(code.contains("difference() {"))
+        XCTAssertTrue(code.contains("translate([2, 3]) circle(r = 5);"))
+        XCTAssertTrue(code.hasSuffix("block_section();\n"))
+    }
+}
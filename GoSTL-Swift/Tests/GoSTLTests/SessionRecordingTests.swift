@@ -0,0 +1,137 @@
+import XCTest
+@testable import GoSTL
+
+final class SessionRecordingTests: XCTestCase {
+    private func keyframe(distance: Double = 100, angleY: Double = 0, target: Vector3 = Vector3(0, 0, 0)) -> CameraKeyframe {
+        CameraKeyframe(distance: distance, angleX: 0.3, angleY: angleY, target: target)
+    }
+
+    private func distanceMeasurement(length: Double) -> Measurement {
+        Measurement(
+            type: .distance,
+            points: [
+                MeasurementPoint(position: Vector3(0, 0, 0), normal: Vector3(0, 0, 1)),
+                MeasurementPoint(position: Vector3(length, 0, 0), normal: Vector3(0, 0, 1))
+            ],
+            value: length
+        )
+    }
+
+    // MARK: - Recorder Tests
+
+    func testRecorderSkipsUnchangedCamera() {
+        let recorder = SessionRecorder(fileName: "part.stl", startTime: 0)
+
+        recorder.record(camera: keyframe(), at: 0)
+        recorder.record(camera: keyframe(), at: 1)
+        recorder.record(camera: keyframe(distance: 50), at: 2)
+        recorder.record(camera: keyframe(distance: 40), at: 2.001)
+
+        XCTAssertEqual(recorder.recording.cameraKeyframes.map(\.time), [0, 2])
+    }
+
+    func testRecorderSnapshotsMeasurementChanges() {
+        let recorder = SessionRecorder(fileName: nil, startTime: 0)
+
+        recorder.record(measurements: [], at: 0)
+        recorder.record(measurements: [distanceMeasurement(length: 10)], at: 1)
+        recorder.record(measurements: [distanceMeasurement(length: 10)], at: 2)
+        recorder.record(measurements: [], at: 3)
+
+        XCTAssertEqual(recorder.recording.measurementSnapshots.map(\.time), [0, 1, 3])
+        XCTAssertEqual(recorder.recording.measurementSnapshots.map(\.measurements.count), [0, 1, 0])
+    }
+
+    func testFinishHoldsLastCameraUntilStop() {
+        let recorder = SessionRecorder(fileName: nil, startTime: 0)
+        recorder.record(camera: keyframe(), at: 0)
+
+        let recording = recorder.finish(at: 5)
+
+        XCTAssertEqual(recording.duration, 5)
+        XCTAssertEqual(recording.cameraKeyframes.last?.camera, keyframe())
+    }
+
+    // MARK: - Playback Tests
+
+    func testCameraInterpolation() {
+        var recording = SessionRecording()
+        recording.cameraKeyframes = [
+            .init(time: 0, camera: keyframe(distance: 100, target: Vector3(0, 0, 0))),
+            .init(time: 2, camera: keyframe(distance: 50, target: Vector3(10, 0, 0)))
+        ]
+
+        let midway = recording.camera(at: 1)
+
+        XCTAssertEqual(midway?.distance ?? 0, 75, accuracy: 1e-9)
+        XCTAssertEqual(midway?.target.x ?? 0, 5, accuracy: 1e-9)
+        XCTAssertEqual(recording.camera(at: -1), recording.cameraKeyframes.first?.camera)
+        XCTAssertEqual(recording.camera(at: 10), recording.cameraKeyframes.last?.camera)
+    }
+
+    func testYawInterpolatesTheShortWay() {
+        let a = keyframe(angleY: 0.1)
+        let b = keyframe(angleY: 2 * .pi - 0.1)
+
+        let midway = CameraKeyframe.interpolate(a, b, t: 0.5)
+
+        XCTAssertEqual(midway.angleY, 0, accuracy: 1e-9)
+    }
+
+    func testMeasurementsAtTime() {
+        var recording = SessionRecording()
+        recording.measurementSnapshots = [
+            .init(time: 1, measurements: [distanceMeasurement(length: 10)]),
+            .init(time: 3, measurements: [])
+        ]
+
+        XCTAssertNil(recording.measurements(at: 0.5))
+        XCTAssertEqual(recording.measurements(at: 2)?.first?.value, 10)
+        XCTAssertEqual(recording.measurements(at: 4)?.count, 0)
+    }
+
+    func testPlayerShowsFirstFrameThenCompletes() {
+        var recording = SessionRecording()
+        recording.cameraKeyframes = [
+            .init(time: 0, camera: keyframe()),
+            .init(time: 1, camera: keyframe(distance: 10))
+        ]
+        let player = SessionPlayer(recording: recording, videoURL: URL(fileURLWithPath: "/tmp/session.mp4"))
+
+        player.advance(deltaTime: 5)
+        XCTAssertEqual(player.time, 0)
+
+        // Video replays step one frame at a time regardless of wall-clock time
+        player.advance(deltaTime: 5)
+        XCTAssertEqual(player.time, 1.0 / Double(SessionPlayer.videoFrameRate), accuracy: 1e-9)
+        XCTAssertFalse(player.isComplete)
+
+        for _ in 0..<(2 * Int(SessionPlayer.videoFrameRate)) {
+            player.advance(deltaTime: 5)
+        }
+        XCTAssertEqual(player.time, 1)
+        XCTAssertTrue(player.isComplete)
+    }
+
+    // MARK: - File Tests
+
+    func testRoundTrip() throws {
+        var recording = SessionRecording(fileName: "part.stl")
+        recording.cameraKeyframes = [.init(time: 0, camera: keyframe(target: Vector3(1, 2, 3)))]
+        recording.measurementSnapshots = [.init(time: 0.5, measurements: [distanceMeasurement(length: 12.5)])]
+        let url = FileManager.default.temporaryDirectory
+            .appendingPathComponent("session-\(UUID().uuidString).\(SessionRecording.fileExtension)")
+        defer { try? FileManager.default.removeItem(at: url) }
+
+        try recording.write(to: url)
+        let loaded = try SessionRecording.load(from: url)
+
+        XCTAssertEqual(loaded.version, SessionRecording.currentVersion)
+        XCTAssertEqual(loaded.fileName, "part.stl")
+        XCTAssertEqual(loaded.cameraKeyframes, recording.cameraKeyframes)
+        let measurement = try XCTUnwrap(loaded.measurementSnapshots.first?.measurements.first)
+        XCTAssertEqual(measurement.type, .distance)
+        XCTAssertEqual(measurement.value, 12.5)
+        XCTAssertEqual(measurement.points.last?.position, Vector3(12.5, 0, 0))
+    }
+}
@@ -0,0 +1,160 @@
+import XCTest
+@testable import GoSTL
+
+final class ShareServerTests: XCTestCase {
+    private let page = Data("<html>part</html>".utf8)
+    private let credentials = ShareServer.Credentials(user: "gostl", password: "secret")
+
+    private func authorization(_ user: String, _ password: String) -> String {
+        "Authorization: Basic \(Data("\(user):\(password)".utf8).base64EncodedString())"
+    }
+
+    func testServesThePage() {
+        let response = ShareServer.response(to: "GET / HTTP/1.1\r\nHost: mac.local:8080", page: page, credentials: nil)
+
+        XCTAssertEqual(response.status, 200)
+        XCTAssertEqual(response.body, page)
+        XCTAssertEqual(response.header("content-type"), "text/html; charset=utf-8")
+        XCTAssertEqual(response.header("Content-Length"), String(page.count))
+        XCTAssertEqual(response.header("Cache-Control"), "no-store")
+        XCTAssertTrue(String(decoding: response.data, as: UTF8.self).hasPrefix("HTTP/1.1 200 OK\r\n"))
+
+        let head = ShareServer.response(to: "HEAD /index.html?v=2 HTTP/1.1", page: page, credentials: nil)
+        XCTAssertEqual(head.status, 200)
+        XCTAssertTrue(head.body.isEmpty)
+        XCTAssertEqual(head.header("Content-Length"), String(page.count))
+    }
+
+    func testAsksForCredentials() {
+        let missing = ShareServer.response(to: "GET / HTTP/1.1\r\nHost: mac.local", page: page, credentials: credentials)
+        XCTAssertEqual(missing.status, 401)
+        XCTAssertEqual(missing.header("WWW-Authenticate"), "Basic realm=\"GoSTL\", charset=\"UTF-8\"")
+        XCTAssertFalse(missing.body == page)
+
+        let wrong = ShareServer.response(to: "GET / HTTP/1.1\r\n\(authorization("gostl", "guess"))", page: page, credentials: credentials)
+        XCTAssertEqual(wrong.status, 401)
+
+        let right = ShareServer.response(to: "GET / HTTP/1.1\r\n\(authorization("gostl", "secret"))", page: page, credentials: credentials)
+        XCTAssertEqual(right.status, 200)
+        XCTAssertEqual(right.body, page)
+
+        // Without the password, other paths do not reveal whether they exist
+        XCTAssertEqual(ShareServer.response(to: "GET /other HTTP/1.1", page: page, credentials: credentials).status, 401)
+    }
+
+    func testChecksBasicAuthorization() {
+        let token = Data("gostl:secret".utf8).base64EncodedString()
+
+        XCTAssertTrue(ShareServer.isAuthorized("Basic \(token)", credentials: credentials))
+        XCTAssertTrue(ShareServer.isAuthorized("basic \(token)", credentials: credentials))
+        XCTAssertFalse(ShareServer.isAuthorized("Bearer \(token)", credentials: credentials))
+        XCTAssertFalse(ShareServer.isAuthorized("Basic not-base64", credentials: credentials))
+        XCTAssertFalse(ShareServer.isAuthorized("Basic \(Data("other:secret".utf8).base64EncodedString())", credentials: credentials))
+    }
+
+    func testMarksFailedLogins() {
+        let missing = ShareServer.response(to: "GET / HTTP/1.1", page: page, credentials: credentials)
+        XCTAssertFalse(missing.isFailedLogin, "the browser's first request without credentials is not a guess")
+
+        let wrong = ShareServer.response(to: "GET / HTTP/1.1\r\n\(authorization("gostl", "guess"))", page: page, credentials: credentials)
+        XCTAssertTrue(wrong.isFailedLogin)
+    }
+
+    func testComparesInConstantTime() {
+        XCTAssertTrue(ShareServer.constantTimeEquals(Data("gostl:secret".utf8), Data("gostl:secret".utf8)))
+        XCTAssertFalse(ShareServer.constantTimeEquals(Data("gostl:secret".utf8), Data("gostl:secreT".utf8)))
+        XCTAssertFalse(ShareServer.constantTimeEquals(Data("gostl:secret".utf8), Data("gostl:secret2".utf8)))
+        XCTAssertFalse(ShareServer.constantTimeEquals(Data(), Data("x".utf8)))
+        XCTAssertTrue(ShareServer.constantTimeEquals(Data(), Data()))
+    }
+
+    func testLocksOutRepeatedFailures() {
+        var throttle = ShareServer.LoginThrottle()
+        let start = Date()
+
+        for _ in 1..<ShareServer.LoginThrottle.maxFailures {
+            throttle.recordFailure(of: "10.0.0.5", at: start)
+        }
+        XCTAssertNil(throttle.lockout(of: "10.0.0.5", at: start))
+
+        throttle.recordFailure(of: "10.0.0.5", at: start)
+        XCTAssertEqual(throttle.lockout(of: "10.0.0.5", at: start.addingTimeInterval(10)) ?? 0, ShareServer.LoginThrottle.lockoutDuration - 10, accuracy: 1e-6)
+        XCTAssertNil(throttle.lockout(of: "10.0.0.6", at: start), "other clients are not locked out")
+        XCTAssertNil(throttle.lockout(of: "10.0.0.5", at: start.addingTimeInterval(ShareServer.LoginThrottle.lockoutDuration)))
+
+        throttle.recordFailure(of: "10.0.0.6", at: start)
+        throttle.recordSuccess(of: "10.0.0.6")
+        for _ in 1..<ShareServer.LoginThrottle.maxFailures {
+            throttle.recordFailure(of: "10.0.0.6", at: start)
+        }
+        XCTAssertNil(throttle.lockout(of: "10.0.0.6", at: start), "a successful login resets the count")
+    }
+
+        func testRejectsOtherRequests() {
+        XCTAssertEqual(ShareServer.response(to: "GET /favicon.ico HTTP/1.1", page: page, credentials: nil).status, 404)
+        XCTAssertEqual(ShareServer.response(to: "garbage", page: page, credentials: nil).status, 400)
+
+        let post = ShareServer.response(to: "POST / HTTP/1.1", page: page, credentials: nil)
+        XCTAssertEqual(post.status, 405)
+        XCTAssertEqual(post.header("Allow"), "GET, HEAD")
+        XCTAssertThrowsError(try ShareServer(page: page, credentials: nil, port: 70000))
+    }
+
+    func testPageEmbedsModelAndEscapesText() throws {
+        let model = STLModel(triangles: [
+            Triangle(v1: Vector3(0, 0, 0), v2: Vector3(10, 0, 0), v3: Vector3(0, 5, 2))
+        ])
+        let html = WebViewerExport.html(
+            model: model,
+            title: "<bracket> & co.stl",
+            analysis: AnalyzeCommand.record(for: model, fileName: "bracket.stl"),
+            annotations: [Annotation(text: "crack </script> here", position: Vector3(1, 2, 3))]
+        )
+
+        XCTAssertTrue(html.contains("<title>&lt;bracket&gt; &amp; co.stl – GoSTL</title>"))
+        XCTAssertTrue(html.contains("<li>crack &lt;/script&gt; here</li>"))
+        XCTAssertFalse(html.contains("crack </script>"))
+        XCTAssertTrue(html.contains(WebViewerExport.meshData(model.triangles).base64EncodedString()))
+        XCTAssertEqual(WebViewerExport.meshData(model.triangles).count, 9 * 4)
+    }
+
+    func testParsesExpiry() {
+        XCTAssertEqual(ShareCommand.Expiry("90s")?.seconds, 90)
+        XCTAssertEqual(ShareCommand.Expiry("30m")?.seconds, 1800)
+        XCTAssertEqual(ShareCommand.Expiry("2h")?.seconds, 7200)
+        XCTAssertEqual(ShareCommand.Expiry("15")?.seconds, 900)
+        XCTAssertNil(ShareCommand.Expiry("0m"))
+        XCTAssertNil(ShareCommand.Expiry("soon"))
+        XCTAssertEqual(ShareCommand.Expiry("2h")?.description, "2 h")
+        XCTAssertEqual(ShareCommand.Expiry("90s")?.description, "90 s")
+        XCTAssertThrowsError(try ShareCommand.parse(["part.stl", "--password", ""]))
+    }
+
+    func testPasswordSources() throws {
+        let environment = [ShareCommand.passwordVariable: "from-env"]
+
+        XCTAssertNil(try ShareCommand.parse(["part.stl"]).resolvedPassword(environment: [:]))
+        XCTAssertEqual(try ShareCommand.parse(["part.stl"]).resolvedPassword(environment: environment), "from-env")
+        XCTAssertEqual(try ShareCommand.parse(["part.stl", "--password", "flag"]).resolvedPassword(environment: environment), "flag")
+        XCTAssertEqual(try ShareCommand.parse(["part.stl", "--ask-password"]).resolvedPassword(environment: environment, prompt: { "typed" }), "typed")
+        XCTAssertThrowsError(try ShareCommand.parse(["part.stl", "--ask-password"]).resolvedPassword(environment: [:], prompt: { nil }))
+        XCTAssertThrowsError(try ShareCommand.parse(["part.stl"]).resolvedPassword(environment: [ShareCommand.passwordVariable: ""]))
+        XCTAssertThrowsError(try ShareCommand.parse(["part.stl", "--ask-password", "--password", "flag"]))
+    }
+
+    func testQRCodeHasFinderPatterns() throws {
+        let modules = try XCTUnwrap(QRCode.modules(for: "http://192.168.1.20:49152/"))
+
+        // Version 2 (25×25) or larger, square
+        XCTAssertGreaterThanOrEqual(modules.count, 25)
+        XCTAssertTrue(modules.allSatisfy { $0.count == modules.count })
+        // Top-left finder pattern: dark ring, light ring, dark center
+        XCTAssertEqual(modules[0][0..<7], [true, true, true, true, true, true, true])
+        XCTAssertEqual(modules[1][0..<7], [true, false, false, false, false, false, true])
+        XCTAssertEqual(modules[3][0..<7], [true, false, true, true, true, false, true])
+
+        let lines = try XCTUnwrap(QRCode.lines(for: "http://192.168.1.20:49152/"))
+        XCTAssertEqual(lines.count, (modules.count + 2 * QRCode.quietZone + 1) / 2)
+        XCTAssertTrue(lines[0].allSatisfy { $0 == " " })
+    }
+}
@@ -0,0 +1,95 @@
+import XCTest
+@testable import GoSTL
+
+final class SlicingTests: XCTestCase {
+    private func area(_ triangles: [Triangle]) -> Double {
+        triangles.reduce(0) { $0 + $1.area() }
+    }
+
+    // MARK: - Cross-Section Tests
+
+    func testFillCoversCrossSection() {
+        let fill = CrossSection.fill(TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 20, 30)), axis: 2, value: 15, facingPositive: true)
+
+        XCTAssertEqual(area(fill), 200, accuracy: 1e-9)
+        XCTAssertTrue(fill.allSatisfy { $0.v1.z == 15 && $0.normal == Vector3(0, 0, 1) })
+    }
+
+    func testFillLeavesHolesOpen() {
+        // A 4x4 hole through a 10x10 block
+        let triangles = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10)) + TestModels.box(from: Vector3(3, 3, 0), to: Vector3(7, 7, 10))
+
+        let fill = CrossSection.fill(triangles, axis: 2, value: 5, facingPositive: false)
+
+        XCTAssertEqual(area(fill), 100 - 16, accuracy: 1e-9)
+        XCTAssertTrue(fill.allSatisfy { $0.normal == Vector3(0, 0, -1) })
+    }
+
+    func testFillsAreClippedToSliceBounds() {
+        let triangles = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 20, 30))
+        let modelBounds = [[0.0, 10.0], [0.0, 20.0], [0.0, 30.0]]
+        let bounds = [[0.0, 5.0], [0.0, 20.0], [0.0, 15.0]]
+
+        let fills = CrossSection.fills(triangles, bounds: bounds, modelBounds: modelBounds)
+
+        // X cap 20 x 15 plus Z cap 5 x 20; planes at the model's extent add nothing
+        XCTAssertEqual(area(fills), 300 + 100, accuracy: 1e-6)
+    }
+
+    func testClippingKeepsTriangleColor() {
+        let red = TriangleColor(1, 0, 0)
+        let triangles = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10)).map {
+            Triangle(v1: $0.v1, v2: $0.v2, v3: $0.v3, color: red)
+        }
+
+        let sliced = TriangleSlicer.sliceTriangles(triangles, bounds: [[0, 10], [0, 10], [0, 5]])
+
+        XCTAssertTrue(sliced.triangles.allSatisfy { $0.color == red })
+    }
+
+    // MARK: - Layer Preview Tests
+
+    func testLayerBounds() {
+        let preview = LayerPreviewState()
+        preview.activate(minZ: 0, maxZ: 1.1)
+
+        XCTAssertEqual(preview.layerCount, 6)
+        preview.step(by: 5)
+        XCTAssertEqual(preview.layerBottom, 1.0, accuracy: 1e-9)
+        XCTAssertEqual(preview.layerTop, 1.1, accuracy: 1e-9, "the last layer ends at the top of the model")
+
+        preview.showsLayersBelow = true
+        let bounds = preview.bounds(modelBounds: BoundingBox(min: Vector3(-1, -2, 0), max: Vector3(1, 2, 1.1)))
+        XCTAssertEqual(bounds[0], [-1, 1])
+        XCTAssertEqual(bounds[2][0], 0)
+    }
+
+    func testAnimationStepsAndStopsAtTop() {
+        let preview = LayerPreviewState()
+        preview.activate(minZ: 0, maxZ: 1)
+        preview.layersPerSecond = 10
+        preview.togglePlaying()
+
+        XCTAssertFalse(preview.advance(deltaTime: 0.05))
+        XCTAssertTrue(preview.advance(deltaTime: 0.06))
+        XCTAssertEqual(preview.layer, 1)
+
+        preview.advance(deltaTime: 10)
+        XCTAssertEqual(preview.layer, preview.layerCount - 1)
+        XCTAssertFalse(preview.isPlaying)
+
+        preview.togglePlaying()
+        XCTAssertEqual(preview.layer, 0, "playing again restarts from the bottom")
+    }
+
+    func testChangingLayerHeightKeepsHeight() {
+        let preview = LayerPreviewState()
+        preview.activate(minZ: 0, maxZ: 10)
+        preview.step(by: 10)  // Z 2.0
+
+        preview.setLayerHeight(0.1)
+
+        XCTAssertEqual(preview.layer, 20)
+        XCTAssertEqual(preview.layerCount, 100)
+    }
+}
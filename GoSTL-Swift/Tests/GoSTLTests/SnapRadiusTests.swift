@@ -0,0 +1,68 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class SnapRadiusTests: XCTestCase {
+
+    /// 10 mm cube; the ray hits the top face 1.41 mm from the corner at (10, 10, 10)
+    private let cube = STLModel(triangles: ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10)))
+    private let ray = Ray(origin: SIMD3(9, 9, 50), direction: SIMD3(0, 0, -1))
+
+    func testSnapsToVertexWithinRadius() throws {
+        let measurementSystem = MeasurementSystem()
+        measurementSystem.snapEye = Vector3(9, 9, 50)
+        measurementSystem.vertexSnapPerDistance = 0.05  // 2 mm at the 40 mm to the hit
+
+        let point = try XCTUnwrap(measurementSystem.findIntersection(ray: ray, model: cube))
+
+        XCTAssertEqual(point.position, Vector3(10, 10, 10))
+        XCTAssertFalse(point.isAirPoint)
+    }
+
+    func testKeepsSurfacePointOutsideRadius() throws {
+        let measurementSystem = MeasurementSystem()
+        measurementSystem.snapEye = Vector3(9, 9, 50)
+        measurementSystem.vertexSnapPerDistance = 0.02  // 0.8 mm
+
+        let point = try XCTUnwrap(measurementSystem.findIntersection(ray: ray, model: cube))
+
+        XCTAssertEqual(point.position.x, 9, accuracy: 1e-6)
+        XCTAssertEqual(point.position.y, 9, accuracy: 1e-6)
+        XCTAssertTrue(point.isAirPoint)
+    }
+
+    func testZeroRadiusTurnsVertexSnappingOff() throws {
+        let measurementSystem = MeasurementSystem()
+        measurementSystem.snapEye = Vector3(9, 9, 50)
+        measurementSystem.vertexSnapPerDistance = 0
+
+        let point = try XCTUnwrap(measurementSystem.findIntersection(ray: ray, model: cube))
+
+        XCTAssertTrue(point.isAirPoint)
+    }
+
+    func testRadiusGrowsWithDistanceFromEye() {
+        let measurementSystem = MeasurementSystem()
+        XCTAssertEqual(measurementSystem.vertexSnapThreshold(at: Vector3(0, 0, 0)), MeasurementSystem.defaultVertexSnapThreshold)
+
+        measurementSystem.snapEye = Vector3(0, 0, 100)
+        measurementSystem.vertexSnapPerDistance = 0.01
+        XCTAssertEqual(measurementSystem.vertexSnapThreshold(at: Vector3(0, 0, 0)), 1, accuracy: 1e-9)
+        XCTAssertEqual(measurementSystem.vertexSnapThreshold(at: Vector3(0, 0, -100)), 2, accuracy: 1e-9)
+    }
+
+    func testWorldSizeOfScreenPoint() {
+        // The view height covers 2·d·tan(fov/2) at distance d
+        let size = Camera.worldSize(ofPointAtDistance: 100, viewHeight: 800)
+        XCTAssertEqual(size, 200 * tan(Double.pi / 8) / 800, accuracy: 1e-12)
+        XCTAssertEqual(Camera.worldSize(ofPointAtDistance: 100, viewHeight: 0), 0)
+    }
+
+    func testSnapRadiusSteps() {
+        XCTAssertEqual(AppSettings.snapRadius(10, steppedBy: 1), 12)
+        XCTAssertEqual(AppSettings.snapRadius(10, steppedBy: -1), 8)
+        XCTAssertEqual(AppSettings.snapRadius(AppSettings.snapRadiusRange.upperBound, steppedBy: 1), AppSettings.snapRadiusRange.upperBound)
+        XCTAssertEqual(AppSettings.snapRadius(0, steppedBy: -1), 0)
+        XCTAssertEqual(AppSettings.snapRadius(55, steppedBy: 0), AppSettings.snapRadiusRange.upperBound)
+    }
+}
@@ -0,0 +1,93 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class SnapTargetTests: XCTestCase {
+
+    /// 10 mm cube from the origin
+    private let cube = STLModel(triangles: ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10)))
+
+    /// Measurement system snapping within the default 2 mm to the given targets only
+    private func system(_ targets: Set<SnapTarget>) -> MeasurementSystem {
+        let system = MeasurementSystem()
+        system.snapTargets = targets
+        return system
+    }
+
+    private func ray(x: Float, y: Float) -> Ray {
+        Ray(origin: SIMD3(x, y, 50), direction: SIMD3(0, 0, -1))
+    }
+
+    // MARK: - Edges
+
+    func testClosestEdgePointOnFeatureEdge() throws {
+        let point = try XCTUnwrap(SnapTarget.closestEdgePoint(to: Vector3(9, 5, 10), triangles: cube.triangles, maxDistance: 2))
+        XCTAssertEqual(point, Vector3(10, 5, 10))
+    }
+
+    func testFlatDiagonalsAreNotEdges() {
+        // The diagonals splitting the top face into triangles pass through its center
+        XCTAssertNil(SnapTarget.closestEdgePoint(to: Vector3(5, 5, 10), triangles: cube.triangles, maxDistance: 2))
+    }
+
+    func testSnapsToEdgeWhenEnabled() throws {
+        let point = try XCTUnwrap(system([.edges]).findIntersection(ray: ray(x: 5, y: 9), model: cube))
+        XCTAssertEqual(point.position.x, 5, accuracy: 1e-6)
+        XCTAssertEqual(point.position.y, 10, accuracy: 1e-6)
+        XCTAssertTrue(point.isAirPoint)
+    }
+
+    func testVerticesWinOverEdges() throws {
+        let point = try XCTUnwrap(system([.vertices, .edges]).findIntersection(ray: ray(x: 9, y: 9), model: cube))
+        XCTAssertEqual(point.position, Vector3(10, 10, 10))
+        XCTAssertFalse(point.isAirPoint)
+    }
+
+    // MARK: - Filter
+
+    func testExcludedVerticesAreIgnored() throws {
+        let point = try XCTUnwrap(system([]).findIntersection(ray: ray(x: 9, y: 9), model: cube))
+        XCTAssertEqual(point.position.x, 9, accuracy: 1e-6)
+        XCTAssertEqual(point.position.y, 9, accuracy: 1e-6)
+        XCTAssertTrue(point.isAirPoint)
+    }
+
+    func testSnapsToMeasurementPoint() throws {
+        let system = system([.vertices, .measurementPoints])
+        let endpoint = MeasurementPoint(position: Vector3(3.5, 3, 10), normal: Vector3(0, 0, 1), isAirPoint: true)
+        system.measurements.append(Measurement(type: .distance, points: [MeasurementPoint(position: Vector3(3.5, 8, 10), normal: Vector3(0, 0, 1)), endpoint], value: 5))
+
+        let point = try XCTUnwrap(system.findIntersection(ray: ray(x: 3, y: 3), model: cube))
+        XCTAssertEqual(point.position, endpoint.position)
+
+        system.toggleSnapTarget(.measurementPoints)
+        let unsnapped = try XCTUnwrap(system.findIntersection(ray: ray(x: 3, y: 3), model: cube))
+        XCTAssertEqual(unsnapped.position.x, 3, accuracy: 1e-6)
+    }
+
+    func testMeasurementPointsBehindSurfaceAreIgnored() throws {
+        let system = system([.measurementPoints])
+        system.currentPoints = [MeasurementPoint(position: Vector3(3.5, 3, 0), normal: Vector3(0, 0, -1))]
+
+        let point = try XCTUnwrap(system.findIntersection(ray: ray(x: 3, y: 3), model: cube))
+        XCTAssertEqual(point.position.z, 10, accuracy: 1e-6)
+    }
+
+    func testSnapsToCutVerticesBehindSurface() throws {
+        // The cube's top may be sliced away, so the section at z = 5 counts
+        let system = system([.cutVertices])
+        system.cutVertices = [Vector3(3, 3.5, 5)]
+
+        let point = try XCTUnwrap(system.findIntersection(ray: ray(x: 3, y: 3), model: cube))
+        XCTAssertEqual(point.position, Vector3(3, 3.5, 5))
+    }
+
+    func testToggleSnapTarget() {
+        let system = MeasurementSystem()
+        XCTAssertEqual(system.snapTargets, Set(SnapTarget.allCases))
+        system.toggleSnapTarget(.cutVertices)
+        XCTAssertFalse(system.snapTargets.contains(.cutVertices))
+        system.toggleSnapTarget(.cutVertices)
+        XCTAssertTrue(system.snapTargets.contains(.cutVertices))
+    }
+}
@@ -0,0 +1,80 @@
+import XCTest
+@testable import GoSTL
+
+final class StabilityCheckTests: XCTestCase {
+    func testBoxTipsOverItsNarrowSide() throws {
+        // 20 × 10 × 40 mm tower: center 5 mm from the long edges, 20 mm high
+        let model = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(20, 10, 40)))
+        let check = try XCTUnwrap(StabilityCheck.check(model: model))
+
+        XCTAssertTrue(check.isStable)
+        XCTAssertEqual(check.supportPolygon.count, 4)
+        XCTAssertEqual(check.margin, 5, accuracy: 1e-9)
+        XCTAssertEqual(check.centerHeight, 20, accuracy: 1e-9)
+        XCTAssertEqual(check.tiltAngle, atan(5.0 / 20) * 180 / .pi, accuracy: 1e-9)
+
+        let edge = try XCTUnwrap(check.tippingEdge)
+        XCTAssertEqual(abs(edge.start.x - edge.end.x), 20, accuracy: 1e-9)
+    }
+
+    func testOverhangTipsOver() throws {
+        // Small foot with a heavy block hanging out to the side
+        let foot = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(5, 5, 5))
+        let block = TestModels.box(from: Vector3(0, 0, 5), to: Vector3(40, 5, 15))
+        let check = try XCTUnwrap(StabilityCheck.check(model: STLModel(triangles: foot + block)))
+
+        XCTAssertFalse(check.isStable)
+        XCTAssertLessThan(check.margin, 0)
+        XCTAssertLessThan(check.tiltAngle, 0)
+        XCTAssertEqual(check.supportPolygon.map(\.x).max(), 5)
+    }
+
+    func testRestsOnLowestPointWithinTolerance() throws {
+        // A slightly uneven base still counts as one resting face
+        var triangles = TestModels.box(from: Vector3(0, 0, 10), to: Vector3(10, 10, 20))
+        triangles = triangles.map { triangle in
+            let raise = { (v: Vector3) in v.z == 10 && v.x == 10 ? Vector3(v.x, v.y, v.z + 0.01) : v }
+            return Triangle(v1: raise(triangle.v1), v2: raise(triangle.v2), v3: raise(triangle.v3))
+        }
+        let check = try XCTUnwrap(StabilityCheck.check(model: STLModel(triangles: triangles)))
+
+        XCTAssertEqual(check.baseZ, 10)
+        XCTAssertEqual(check.supportPolygon.count, 4)
+        XCTAssertTrue(check.isStable)
+    }
+
+    func testConvexHullDropsInteriorAndCollinearPoints() {
+        let points = [
+            Vector3(0, 0, 0), Vector3(5, 0, 0), Vector3(10, 0, 0),
+            Vector3(10, 10, 0), Vector3(0, 10, 0), Vector3(4, 6, 0), Vector3(0, 0, 0)
+        ]
+        let hull = StabilityCheck.convexHull(points)
+
+        XCTAssertEqual(hull, [Vector3(0, 0, 0), Vector3(10, 0, 0), Vector3(10, 10, 0), Vector3(0, 10, 0)])
+    }
+
+    func testSinglePointContactIsNeverStable() {
+        let (margin, edge) = StabilityCheck.signedDistance(x: 0, y: 0, to: [Vector3(0, 0, 0)])
+
+        XCTAssertEqual(margin, 0)
+        XCTAssertNil(edge)
+        XCTAssertNil(StabilityCheck.check(model: STLModel()))
+    }
+
+    // MARK: - Output
+
+    func testAnalysisRecordAndAssertConditions() throws {
+        let model = STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(20, 10, 40)))
+        let record = AnalysisRecord(fileName: "tower.stl", analysis: model.analyze(), stability: StabilityCheck.check(model: model))
+
+        XCTAssertEqual(record.value(for: "stability.stable"), .text("yes"))
+        XCTAssertNotNil(record.value(for: "stability.tilt_angle"))
+
+        let results = ModelConditionEvaluator.evaluate([.stable, .minTiltAngle(10), .minTiltAngle(20)], on: model)
+        XCTAssertEqual(results.map(\.check), ["stable", "min-tilt", "min-tilt"])
+        XCTAssertEqual(results.map(\.passed), [true, true, false])
+
+        let command = try AssertCommand.parse(["tower.stl", "--stable", "--min-tilt", "15"])
+        XCTAssertEqual(command.conditions, [.stable, .minTiltAngle(15)])
+    }
+}
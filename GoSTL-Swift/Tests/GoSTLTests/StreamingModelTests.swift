@@ -0,0 +1,164 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class StreamingModelTests: XCTestCase {
+
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory.appendingPathComponent("StreamingModelTests-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    /// Ten 2 mm cubes in a row along X, 10 mm apart (120 triangles)
+    private func writeRow() throws -> URL {
+        let triangles = (0..<10).flatMap { index in
+            ModelGenerator.box(from: Vector3(Double(index) * 10, 0, 0), to: Vector3(Double(index) * 10 + 2, 2, 2))
+        }
+        let url = directory.appendingPathComponent("row.stl")
+        try STLExporter.exportBinary(model: STLModel(triangles: triangles), to: url)
+        return url
+    }
+
+    // MARK: - Chunk grid
+
+    func testChunkGridStaysWithinCount() {
+        let bounds = BoundingBox(min: Vector3(0, 0, 0), max: Vector3(100, 50, 10))
+        let grid = ChunkGrid(bounds: bounds, cellCount: 64)
+
+        XCTAssertLessThanOrEqual(grid.cellCount, 64)
+        XCTAssertGreaterThan(grid.cellCount, 16)
+    }
+
+    func testChunkGridCells() {
+        let grid = ChunkGrid(bounds: BoundingBox(min: Vector3(0, 0, 0), max: Vector3(4, 2, 0)), cellSize: 1)
+
+        XCTAssertEqual(grid.dimensions, SIMD3(4, 2, 1))
+        XCTAssertEqual(grid.cell(of: SIMD3(0.5, 0.5, 0)), 0)
+        XCTAssertEqual(grid.cell(of: SIMD3(3.5, 1.5, 0)), 7)
+        // Points on or beyond the far side go to the last cell
+        XCTAssertEqual(grid.cell(of: SIMD3(4, 2, 0)), 7)
+        XCTAssertEqual(grid.cell(of: SIMD3(-1, -1, -1)), 0)
+    }
+
+    // MARK: - Building
+
+    func testBuildSplitsIntoChunks() throws {
+        let url = try writeRow()
+        let chunkURL = directory.appendingPathComponent("row.chunks")
+        let model = try StreamingModel.build(url: url, chunkFileURL: chunkURL, trianglesPerChunk: 12, proxyResolution: 64)
+
+        XCTAssertEqual(model.triangleCount, 120)
+        XCTAssertGreaterThan(model.chunks.count, 1)
+        XCTAssertEqual(model.chunks.reduce(0) { $0 + $1.triangleCount }, 120)
+        XCTAssertEqual(model.bounds.max.x, 92, accuracy: 1e-6)
+
+        var total = 0
+        for chunk in model.chunks {
+            let triangles = try model.triangles(of: chunk)
+            XCTAssertEqual(triangles.count, chunk.triangleCount)
+            for triangle in triangles {
+                for vertex in [triangle.v1, triangle.v2, triangle.v3] {
+                    XCTAssertGreaterThanOrEqual(vertex.x, chunk.bounds.min.x - 1e-6)
+                    XCTAssertLessThanOrEqual(vertex.x, chunk.bounds.max.x + 1e-6)
+                }
+            }
+            total += triangles.count
+        }
+        XCTAssertEqual(total, 120)
+    }
+
+    func testProxyRangesCoverProxy() throws {
+        let url = try writeRow()
+        let model = try StreamingModel.build(url: url, chunkFileURL: directory.appendingPathComponent("row.chunks"), trianglesPerChunk: 12)
+
+        var next = 0
+        for chunk in model.chunks {
+            XCTAssertEqual(chunk.proxyRange.lowerBound, next)
+            next = chunk.proxyRange.upperBound
+        }
+        XCTAssertEqual(next, model.proxy.triangleCount)
+        XCTAssertGreaterThan(model.proxy.triangleCount, 0)
+        XCTAssertLessThanOrEqual(model.proxy.triangleCount, 120)
+    }
+
+    func testChunkFileRemovedWithModel() throws {
+        let url = try writeRow()
+        let chunkURL = directory.appendingPathComponent("row.chunks")
+        var model: StreamingModel? = try StreamingModel.build(url: url, chunkFileURL: chunkURL, trianglesPerChunk: 12)
+
+        XCTAssertTrue(FileManager.default.fileExists(atPath: chunkURL.path))
+        XCTAssertNotNil(model)
+        model = nil
+        XCTAssertFalse(FileManager.default.fileExists(atPath: chunkURL.path))
+    }
+
+    // MARK: - Detection
+
+    func testShouldStreamOnlyLargeBinaryFiles() throws {
+        let url = try writeRow()
+        XCTAssertEqual(StreamingModel.binaryTriangleCount(url: url), 120)
+        XCTAssertTrue(StreamingModel.shouldStream(url: url, threshold: 0))
+        XCTAssertFalse(StreamingModel.shouldStream(url: url, threshold: 1_000_000))
+
+        let ascii = directory.appendingPathComponent("ascii.stl")
+        try "solid empty\nendsolid empty\n".write(to: ascii, atomically: true, encoding: .utf8)
+        XCTAssertNil(StreamingModel.binaryTriangleCount(url: ascii))
+        XCTAssertFalse(StreamingModel.shouldStream(url: ascii, threshold: 0))
+    }
+
+    func testBuildRejectsASCII() throws {
+        let ascii = directory.appendingPathComponent("ascii.stl")
+        try "solid empty\nendsolid empty\n".write(to: ascii, atomically: true, encoding: .utf8)
+
+        XCTAssertThrowsError(try StreamingModel.build(url: ascii, chunkFileURL: directory.appendingPathComponent("ascii.chunks")))
+    }
+
+    // MARK: - Residency
+
+    func testFrustumCulling() {
+        let camera = Camera()
+        let frustum = Frustum(viewProjection: camera.projectionMatrix(aspect: 1) * camera.viewMatrix())
+        let target = camera.target
+        let behind = camera.position + (camera.position - target)
+
+        XCTAssertTrue(frustum.intersects(min: target - 1, max: target + 1))
+        XCTAssertFalse(frustum.intersects(min: behind - 1, max: behind + 1))
+    }
+
+    func testPlanPrefersNearChunksWithinBudget() {
+        let camera = Camera()
+        let frustum = Frustum(viewProjection: camera.projectionMatrix(aspect: 1) * camera.viewMatrix())
+        let target = camera.target
+        let toward = simd_normalize(camera.position - target)
+        // Three chunks on the line of sight, the middle one nearest to the camera
+        let bounds: [StreamingResidency.Box] = [
+            (target - 1, target + 1),
+            (target + toward * 20 - 1, target + toward * 20 + 1),
+            (target - toward * 20 - 1, target - toward * 20 + 1)
+        ]
+        let perChunk = 100 * StreamingResidency.bytesPerTriangle
+
+        let plan = StreamingResidency.plan(
+            bounds: bounds,
+            triangleCounts: [100, 100, 100],
+            frustum: frustum,
+            eye: camera.position,
+            budget: 2 * perChunk
+        )
+
+        XCTAssertEqual(plan.visible.sorted(), [0, 1, 2])
+        XCTAssertEqual(plan.wanted, [1, 0])
+    }
+
+    func testDistanceToBox() {
+        let box: StreamingResidency.Box = (SIMD3(0, 0, 0), SIMD3(1, 1, 1))
+        XCTAssertEqual(StreamingResidency.distance(from: SIMD3(0.5, 0.5, 0.5), to: box), 0)
+        XCTAssertEqual(StreamingResidency.distance(from: SIMD3(4, 1, 1), to: box), 3, accuracy: 1e-6)
+    }
+}
@@ -0,0 +1,63 @@
+import XCTest
+@testable import GoSTL
+
+final class SurfaceRegionTests: XCTestCase {
+
+    private let cube = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(10, 10, 10))
+
+    /// Index of the first triangle whose normal points along the direction
+    private func triangle(facing direction: Vector3, in triangles: [Triangle]) throws -> Int {
+        try XCTUnwrap(triangles.firstIndex {
+            Triangle.calculateNormal(v1: $0.v1, v2: $0.v2, v3: $0.v3).dot(direction) > 0.99
+        })
+    }
+
+    func testFlatFaceOfCube() throws {
+        let seed = try triangle(facing: Vector3(0, 0, 1), in: cube)
+        let region = try XCTUnwrap(SurfaceRegion.grow(from: seed, kind: .flatFace, in: cube, adjacency: TriangleAdjacency(triangles: cube)))
+
+        XCTAssertEqual(region.triangles.count, 2)
+        XCTAssertEqual(region.area, 100, accuracy: 1e-9)
+        XCTAssertEqual(region.normal.z, 1, accuracy: 1e-9)
+        XCTAssertTrue(region.contains(seed))
+    }
+
+    func testFlatFaceStopsAtShallowFold() throws {
+        // Two 10 mm squares sharing an edge, the second one tilted up by 1°
+        let rise = 10 * tan(Double.pi / 180)
+        let triangles = ModelGenerator.quad(Vector3(0, 0, 0), Vector3(10, 0, 0), Vector3(10, 10, 0), Vector3(0, 10, 0))
+            + ModelGenerator.quad(Vector3(10, 0, 0), Vector3(20, 0, rise), Vector3(20, 10, rise), Vector3(10, 10, 0))
+        let adjacency = TriangleAdjacency(triangles: triangles)
+
+        let face = try XCTUnwrap(SurfaceRegion.grow(from: 0, kind: .flatFace, in: triangles, adjacency: adjacency))
+        XCTAssertEqual(face.triangles, [0, 1])
+        XCTAssertEqual(face.area, 100, accuracy: 1e-9)
+
+        let smooth = try XCTUnwrap(SurfaceRegion.grow(from: 0, kind: .smoothRegion, in: triangles, adjacency: adjacency))
+        XCTAssertEqual(smooth.triangles, [0, 1, 2, 3])
+    }
+
+    func testSmoothRegionCoversCylinderSide() throws {
+        let triangles = ModelGenerator.cylinder(radius: 5, height: 10, segments: 48).triangles
+        let adjacency = TriangleAdjacency(triangles: triangles)
+        let seed = try triangle(facing: Vector3(1, 0, 0), in: triangles)
+
+        let side = try XCTUnwrap(SurfaceRegion.grow(from: seed, kind: .smoothRegion, in: triangles, adjacency: adjacency))
+        XCTAssertEqual(side.area, 2 * 48 * 5 * sin(Double.pi / 48) * 10, accuracy: 1e-6)
+        XCTAssertEqual(side.normal.length, 1, accuracy: 1e-9)
+
+        // A flat face on the side is a single facet
+        let facet = try XCTUnwrap(SurfaceRegion.grow(from: seed, kind: .flatFace, in: triangles, adjacency: adjacency))
+        XCTAssertLessThan(facet.area, side.area / 40)
+    }
+
+    func testSmoothRegionOfCubeIsOneFace() throws {
+        let seed = try triangle(facing: Vector3(1, 0, 0), in: cube)
+        let region = try XCTUnwrap(SurfaceRegion.grow(from: seed, kind: .smoothRegion, in: cube, adjacency: TriangleAdjacency(triangles: cube)))
+        XCTAssertEqual(region.triangles.count, 2)
+    }
+
+    func testInvalidSeed() {
+        XCTAssertNil(SurfaceRegion.grow(from: 99, kind: .flatFace, in: cube, adjacency: TriangleAdjacency(triangles: cube)))
+    }
+}
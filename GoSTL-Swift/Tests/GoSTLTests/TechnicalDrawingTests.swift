@@ -0,0 +1,102 @@
+import XCTest
+@testable import GoSTL
+
+final class TechnicalDrawingTests: XCTestCase {
+
+    private func cuboid() -> STLModel {
+        STLModel(triangles: TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 20, 30)), name: "cuboid")
+    }
+
+    private func view(_ view: TechnicalDrawing.View, in drawing: TechnicalDrawing) throws -> TechnicalDrawing.ProjectedView {
+        try XCTUnwrap(drawing.views.first { $0.view == view })
+    }
+
+    // MARK: - View Tests
+
+    func testViewAxes() {
+        XCTAssertEqual(TechnicalDrawing.View.front.project(Vector3(1, 2, 3)), CGPoint(x: 1, y: 3))
+        XCTAssertEqual(TechnicalDrawing.View.top.project(Vector3(1, 2, 3)), CGPoint(x: 1, y: 2))
+        XCTAssertEqual(TechnicalDrawing.View.right.project(Vector3(1, 2, 3)), CGPoint(x: 2, y: 3))
+
+        let axes = TechnicalDrawing.View.isometric.axes
+        XCTAssertEqual(axes.right.z, 0, accuracy: 1e-9)
+        XCTAssertGreaterThan(axes.up.z, 0)
+    }
+
+    func testOrthographicViewsShowOneFace() throws {
+        let drawing = TechnicalDrawing(model: cuboid(), title: "cuboid.stl")
+
+        let front = try view(.front, in: drawing)
+        XCTAssertEqual(front.segments.count, 4)
+        XCTAssertEqual(front.bounds.width, 10, accuracy: 1e-9)
+        XCTAssertEqual(front.bounds.height, 30, accuracy: 1e-9)
+
+        let right = try view(.right, in: drawing)
+        XCTAssertEqual(right.bounds.width, 20, accuracy: 1e-9)
+        XCTAssertEqual(right.bounds.height, 30, accuracy: 1e-9)
+
+        XCTAssertEqual(try view(.isometric, in: drawing).segments.count, 9)
+    }
+
+    func testHiddenLinesAreRemoved() throws {
+        // A smaller box directly behind the first one is completely covered in the front view
+        let triangles = TestModels.box(from: Vector3(0, 0, 0), to: Vector3(10, 5, 10)) + TestModels.box(from: Vector3(2, 10, 2), to: Vector3(8, 15, 8))
+        let drawing = TechnicalDrawing(model: STLModel(triangles: triangles), title: "boxes.stl", views: [.front, .top])
+
+        XCTAssertEqual(drawing.views.map(\.view), [.front, .top])
+        XCTAssertEqual(try view(.front, in: drawing).segments.count, 4)
+        XCTAssertEqual(try view(.top, in: drawing).segments.count, 8)
+    }
+
+    // MARK: - Scale Tests
+
+    func testFittingScale() {
+        XCTAssertEqual(TechnicalDrawing.fittingScale(for: Vector3(10, 20, 30)), 2)
+        XCTAssertEqual(TechnicalDrawing.fittingScale(for: Vector3(200, 150, 100)), 0.5)
+        XCTAssertEqual(TechnicalDrawing.fittingScale(for: Vector3(0, 0, 0)), 1)
+    }
+
+    func testScaleLabelsAndParsing() {
+        XCTAssertEqual(TechnicalDrawing.scaleLabel(2), "2:1")
+        XCTAssertEqual(TechnicalDrawing.scaleLabel(1), "1:1")
+        XCTAssertEqual(TechnicalDrawing.scaleLabel(0.2), "1:5")
+
+        XCTAssertEqual(TechnicalDrawing.parseScale("1:2"), 0.5)
+        XCTAssertEqual(TechnicalDrawing.parseScale("5:1"), 5)
+        XCTAssertEqual(TechnicalDrawing.parseScale("0.25"), 0.25)
+        XCTAssertNil(TechnicalDrawing.parseScale("1:0"))
+        XCTAssertNil(TechnicalDrawing.parseScale("abc"))
+    }
+
+    // MARK: - Sheet Tests
+
+    func testSheetHasDimensionsAndTitleBlock() {
+        let drawing = TechnicalDrawing(model: cuboid(), title: "cuboid.stl", scale: 1)
+        let svg = drawing.sheet().svg(title: drawing.title)
+
+        XCTAssertTrue(svg.contains("width=\"297mm\" height=\"210mm\" viewBox=\"0 0 297 210\""))
+        for value in ["10", "20", "30"] {
+            XCTAssertTrue(svg.contains(">\(value)</text>"), "missing dimension \(value)")
+        }
+        XCTAssertTrue(svg.contains(">Scale 1:1</text>"))
+        XCTAssertTrue(svg.contains(">cuboid.stl</text>"))
+        XCTAssertTrue(svg.contains("rotate(-90"))
+    }
+
+    func testPDFDocument() throws {
+        var sheet = DrawingSheet(size: CGSize(width: 100, height: 50))
+        sheet.line(from: CGPoint(x: 0, y: 0), to: CGPoint(x: 10, y: 10), weight: 0.35)
+        sheet.text("A", at: CGPoint(x: 5, y: 5), size: 3)
+
+        let data = try XCTUnwrap(sheet.pdf())
+        XCTAssertTrue(String(decoding: data.prefix(5), as: UTF8.self).hasPrefix("%PDF"))
+    }
+
+    func testCommandParsesViewsAndScale() throws {
+        let command = try DrawingCommand.parse(["part.stl", "--view", "front", "--view", "isometric", "--scale", "1:2"])
+        XCTAssertEqual(command.views, [.front, .isometric])
+
+        XCTAssertThrowsError(try DrawingCommand.parse(["part.stl", "--scale", "0"]))
+        XCTAssertThrowsError(try DrawingCommand.parse(["part.stl", "-o", "part.png"]))
+    }
+}
@@ -0,0 +1,85 @@
+import XCTest
+@testable import GoSTL
+
+final class TempWorkspaceTests: XCTestCase {
+    private var root: URL!
+
+    override func setUpWithError() throws {
+        root = FileManager.default.temporaryDirectory.appendingPathComponent("gostl-tests-\(UUID().uuidString)")
+        try FileManager.default.createDirectory(at: root, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: root)
+    }
+
+    // MARK: - Session Tests
+
+    func testSessionDirectoryIsCreatedLazilyAndRemovedOnCleanUp() {
+        let workspace = TempWorkspace(root: root, processID: 4242)
+        XCTAssertFalse(FileManager.default.fileExists(atPath: workspace.sessionDirectory.path))
+
+        let url = workspace.makeURL(prefix: "go3mf", pathExtension: "3mf")
+        XCTAssertEqual(url.deletingLastPathComponent(), workspace.sessionDirectory)
+        XCTAssertEqual(url.pathExtension, "3mf")
+        XCTAssertTrue(FileManager.default.fileExists(atPath: workspace.sessionDirectory.path))
+
+        workspace.cleanUp()
+        XCTAssertFalse(FileManager.default.fileExists(atPath: workspace.sessionDirectory.path))
+    }
+
+    func testRemovesSessionsOfExitedProcesses() throws {
+        let finished = Process()
+        finished.executableURL = URL(fileURLWithPath: "/usr/bin/true")
+        try finished.run()
+        finished.waitUntilExit()
+
+        let stale = TempWorkspace(root: root, processID: finished.processIdentifier)
+        let current = TempWorkspace(root: root)
+        _ = stale.directory
+        _ = current.directory
+        let unrelated = root.appendingPathComponent("notes")
+        try FileManager.default.createDirectory(at: unrelated, withIntermediateDirectories: true)
+
+        XCTAssertEqual(current.removeStaleSessions(), 1)
+        XCTAssertFalse(FileManager.default.fileExists(atPath: stale.sessionDirectory.path))
+        XCTAssertTrue(FileManager.default.fileExists(atPath: current.sessionDirectory.path))
+        XCTAssertTrue(FileManager.default.fileExists(atPath: unrelated.path))
+    }
+
+    func testConfiguredRootPrefersEnvironment() {
+        let fromSetting = TempWorkspace.configuredRoot(environment: [:], setting: "/tmp/from-setting")
+        let fromEnvironment = TempWorkspace.configuredRoot(environment: [TempWorkspace.environmentKey: "/tmp/from-env"], setting: "/tmp/from-setting")
+
+        XCTAssertEqual(fromSetting.path, "/tmp/from-setting")
+        XCTAssertEqual(fromEnvironment.path, "/tmp/from-env")
+        XCTAssertEqual(TempWorkspace.configuredRoot(environment: [:], setting: nil), TempWorkspace.defaultRoot)
+    }
+
+    // MARK: - Render File Tests
+
+    func testRecognizesRenderFiles() {
+        XCTAssertTrue(TempWorkspace.isRenderFile("gostl_1A2B3C4D.csg"))
+        XCTAssertTrue(TempWorkspace.isRenderFile("gostl_2d_1A2B3C4D.scad"))
+        XCTAssertTrue(TempWorkspace.isRenderFile("gostl_module_1A2B3C4D.scad"))
+        XCTAssertTrue(TempWorkspace.isRenderFile("gostl_1A2B3C4D_c3.stl"))
+        XCTAssertFalse(TempWorkspace.isRenderFile("gostl_bracket.scad"))
+        XCTAssertFalse(TempWorkspace.isRenderFile("bracket.csg"))
+    }
+
+    func testRemovesOnlyOldRenderFiles() throws {
+        let old = root.appendingPathComponent("gostl_1A2B3C4D.csg")
+        let fresh = root.appendingPathComponent("gostl_5E6F7A8B.csg")
+        let source = root.appendingPathComponent("part.scad")
+        for url in [old, fresh, source] {
+            try Data().write(to: url)
+        }
+        try FileManager.default.setAttributes([.modificationDate: Date().addingTimeInterval(-7200)], ofItemAtPath: old.path)
+        try FileManager.default.setAttributes([.modificationDate: Date().addingTimeInterval(-7200)], ofItemAtPath: source.path)
+
+        XCTAssertEqual(TempWorkspace.removeStaleRenderFiles(in: root), 1)
+        XCTAssertFalse(FileManager.default.fileExists(atPath: old.path))
+        XCTAssertTrue(FileManager.default.fileExists(atPath: fresh.path))
+        XCTAssertTrue(FileManager.default.fileExists(atPath: source.path))
+    }
+}
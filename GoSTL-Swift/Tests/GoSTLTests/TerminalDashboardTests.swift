@@ -0,0 +1,74 @@
+import XCTest
+@testable import GoSTL
+
+final class TerminalDashboardTests: XCTestCase {
+    private func record(volume: Double, triangles: Int = 12) -> AnalysisRecord {
+        AnalysisRecord(fields: [
+            .init(key: "file", label: "File", unit: nil, value: .text("part.scad")),
+            .init(key: "triangles", label: "Triangles", unit: nil, value: .integer(triangles)),
+            .init(key: "bounds.min.x", label: "Min X", unit: "mm", value: .number(0)),
+            .init(key: "volume", label: "Volume", unit: "mm³", value: .number(volume))
+        ])
+    }
+
+    func testLayoutFillsTheTerminal() {
+        var dashboard = TerminalDashboard(fileName: "part.scad")
+        dashboard.update(record: record(volume: 1000), messages: ["ECHO: \"ok\""], duration: 0.25)
+        dashboard.watchedFiles = [URL(fileURLWithPath: "/tmp/part.scad"), URL(fileURLWithPath: "/tmp/lib.scad")]
+        let lines = dashboard.lines(width: 60, height: 16)
+
+        XCTAssertEqual(lines.count, 16)
+        XCTAssertTrue(lines.allSatisfy { $0.count <= 60 })
+        XCTAssertEqual(lines[0].count, 60)
+        XCTAssertTrue(lines[0].hasPrefix(" GoSTL  part.scad"))
+        XCTAssertEqual(lines[1], "Status    Up to date, analyzed in 0.25 s (run 1)")
+        XCTAssertEqual(lines[2], "Watching  2 files: part.scad, lib.scad")
+        XCTAssertTrue(lines.contains("Volume     1000.00 mm³"))
+        XCTAssertFalse(lines.contains { $0.hasPrefix("Min X") || $0.hasPrefix("File") })
+        XCTAssertTrue(lines.contains("ECHO: \"ok\""))
+        XCTAssertEqual(lines.last, " q quit   r re-render")
+    }
+
+    func testChangedMetricsAreMarked() {
+        var dashboard = TerminalDashboard(fileName: "part.scad")
+        dashboard.update(record: record(volume: 1000), messages: [], duration: 1)
+        dashboard.update(record: record(volume: 950.5, triangles: 20), messages: [], duration: 1)
+        let lines = dashboard.lines(width: 80, height: 20)
+
+        XCTAssertTrue(lines.contains("Triangles  20          ▲ +8"))
+        XCTAssertTrue(lines.contains("Volume     950.50 mm³  ▼ -49.50"))
+        XCTAssertTrue(lines.contains("(no messages)"))
+    }
+
+    func testFailureKeepsMetricsAndShowsTheRenderLog() {
+        var dashboard = TerminalDashboard(fileName: "part.scad")
+        dashboard.update(record: record(volume: 1000), messages: [], duration: 1)
+        dashboard.fail(OpenSCADError.renderFailed("Failed to render part.scad\nstderr: ...", messages: ["ERROR: Parser error in line 3"]))
+        let lines = dashboard.lines(width: 80, height: 20)
+
+        XCTAssertEqual(lines[1], "Status    Failed: Failed to render part.scad")
+        XCTAssertTrue(lines.contains("Volume     1000.00 mm³"))
+        XCTAssertTrue(lines.contains("ERROR: Parser error in line 3"))
+        XCTAssertEqual(dashboard.runs, 2)
+    }
+
+    func testSmallTerminalsKeepTheNewestLogLines() {
+        var dashboard = TerminalDashboard(fileName: "a-very-long-model-name-that-does-not-fit.scad")
+        dashboard.update(record: record(volume: 1000), messages: (1...10).map { "WARNING: \($0)" }, duration: 1)
+        let lines = dashboard.lines(width: 30, height: 10)
+
+        XCTAssertEqual(lines.count, 10)
+        XCTAssertTrue(lines.allSatisfy { $0.count <= 30 })
+        XCTAssertTrue(lines[0].contains("…"))
+        XCTAssertEqual(Array(lines[6..<9]), ["WARNING: 8", "WARNING: 9", "WARNING: 10"])
+        XCTAssertEqual(dashboard.lines(width: 20, height: 5).first, "Terminal too small")
+    }
+
+    func testCommandParsesAnalysisOptions() throws {
+        let command = try TUICommand.parse(["part.scad", "--material", "petg", "--stability"])
+
+        XCTAssertEqual(command.material, .petg)
+        XCTAssertTrue(command.stability)
+        XCTAssertThrowsError(try TUICommand.parse(["part.scad", "--infill", "150"]))
+    }
+}
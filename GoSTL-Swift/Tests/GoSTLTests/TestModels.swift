@@ -0,0 +1,20 @@
+@testable import GoSTL
+
+/// Meshes shared by the tests
+enum TestModels {
+    /// Closed axis-aligned box between two corners, with outward normals (12 triangles)
+    static func box(from min: Vector3, to max: Vector3) -> [Triangle] {
+        let a = Vector3(min.x, min.y, min.z), b = Vector3(max.x, min.y, min.z)
+        let c = Vector3(max.x, max.y, min.z), d = Vector3(min.x, max.y, min.z)
+        let e = Vector3(min.x, min.y, max.z), f = Vector3(max.x, min.y, max.z)
+        let g = Vector3(max.x, max.y, max.z), h = Vector3(min.x, max.y, max.z)
+        return [
+            Triangle(v1: a, v2: c, v3: b), Triangle(v1: a, v2: d, v3: c),
+            Triangle(v1: e, v2: f, v3: g), Triangle(v1: e, v2: g, v3: h),
+            Triangle(v1: a, v2: b, v3: f), Triangle(v1: a, v2: f, v3: e),
+            Triangle(v1: b, v2: c, v3: g), Triangle(v1: b, v2: g, v3: f),
+            Triangle(v1: c, v2: d, v3: h), Triangle(v1: c, v2: h, v3: g),
+            Triangle(v1: d, v2: a, v3: e), Triangle(v1: d, v2: e, v3: h)
+        ]
+    }
+}
@@ -0,0 +1,107 @@
+import XCTest
+@testable import GoSTL
+
+final class TextReliefTests: XCTestCase {
+    private let cube = ModelGenerator.cube(size: Vector3(20, 20, 20))
+
+    private func face(_ side: EmbossCommand.Side) throws -> ModelAlignment.PlanarFace {
+        try XCTUnwrap(EmbossCommand.face(of: cube, towards: side))
+    }
+
+    // MARK: - Font Tests
+
+    func testBitmapSpacesCharacters() {
+        let regular = BlockFont.bitmap("HI")
+        let bold = BlockFont.bitmap("HI", style: .bold)
+
+        XCTAssertEqual(regular.count, BlockFont.rows)
+        XCTAssertEqual(regular[0].count, BlockFont.width(of: "HI"))
+        XCTAssertEqual(bold[0].count, 13)
+        // "I" row 2 is "..#.." and becomes "..##.." in bold
+        XCTAssertEqual(bold[1].suffix(6), [false, false, true, true, false, false])
+    }
+
+    func testCornerContactsAreBridged() {
+        let bridged = TextRelief.bridged([[true, false], [false, true]])
+
+        XCTAssertEqual(bridged, [[true, false], [true, true]])
+    }
+
+    // MARK: - Relief Tests
+
+    func testEmbossedTextStaysClosed() throws {
+        let relief = TextRelief(text: "H", depth: 0.6, letterHeight: 7)
+
+        let result = try relief.apply(to: cube, face: face(.top))
+
+        XCTAssertTrue(result.topology().isWatertight)
+        XCTAssertEqual(result.boundingBox().max.z, 20.6, accuracy: 1e-9)
+        // 17 pixels of 1 × 1 mm
+        XCTAssertEqual(result.analyze().volume, 8000 + 17 * 0.6, accuracy: 1e-6)
+    }
+
+    func testEngravedTextStaysClosed() throws {
+        let relief = TextRelief(text: "H", mode: .engrave, depth: 1, letterHeight: 7)
+
+        let result = try relief.apply(to: cube, face: face(.top))
+
+        XCTAssertTrue(result.topology().isWatertight)
+        XCTAssertEqual(result.boundingBox().max.z, 20, accuracy: 1e-9)
+        XCTAssertEqual(result.analyze().volume, 8000 - 17, accuracy: 1e-6)
+    }
+
+    func testDiagonalGlyphsStayManifold() throws {
+        let relief = TextRelief(text: "X/N", mode: .engrave, letterHeight: 3.5)
+
+        let result = try relief.apply(to: cube, face: face(.front))
+
+        XCTAssertTrue(result.topology().isWatertight)
+    }
+
+    func testFittedTextUsesTheFace() throws {
+        let result = try TextRelief(text: "ID-42").apply(to: cube, face: face(.right))
+        let raised = result.triangles.flatMap { [$0.v1, $0.v2, $0.v3] }.filter { $0.x > 10 + 1e-9 }
+
+        XCTAssertTrue(result.topology().isWatertight)
+        XCTAssertFalse(raised.isEmpty)
+        XCTAssertTrue(raised.allSatisfy { abs($0.y) < 10 && $0.z > 0 && $0.z < 20 })
+    }
+
+    func testOffsetMovesText() throws {
+        let relief = TextRelief(text: "A", letterHeight: 3.5, offset: SIMD2(4, -5))
+
+        let result = try relief.apply(to: cube, face: face(.top))
+        let raised = STLModel(triangles: result.triangles.filter { min($0.v1.z, $0.v2.z, $0.v3.z) > 20 + 1e-9 })
+
+        XCTAssertEqual(raised.boundingBox().center.x, 4, accuracy: 1e-9)
+        XCTAssertEqual(raised.boundingBox().center.y, -5, accuracy: 1e-9)
+    }
+
+    func testTextMustFitTheFace() throws {
+        XCTAssertThrowsError(try TextRelief(text: "TOO LONG", letterHeight: 7).apply(to: cube, face: face(.top))) { error in
+            XCTAssertEqual(error.localizedDescription, "\"TOO LONG\" does not fit on the face; use smaller letters, a shorter text or move it with an offset")
+        }
+        XCTAssertThrowsError(try TextRelief(text: "A", letterHeight: 7, offset: SIMD2(8, 0)).apply(to: cube, face: face(.top)))
+    }
+
+    func testTextReadsUpright() throws {
+        let top = TextRelief.Frame(face: try face(.top), triangles: cube.triangles)
+        let front = TextRelief.Frame(face: try face(.front), triangles: cube.triangles)
+        let turned = TextRelief.Frame(face: try face(.top), triangles: cube.triangles, rotation: 90)
+
+        XCTAssertEqual(top.right.x, 1, accuracy: 1e-9)
+        XCTAssertEqual(top.up.y, 1, accuracy: 1e-9)
+        XCTAssertEqual(front.right.x, 1, accuracy: 1e-9)
+        XCTAssertEqual(front.up.z, 1, accuracy: 1e-9)
+        XCTAssertEqual(turned.up.x, -1, accuracy: 1e-9)
+    }
+
+    // MARK: - Command Tests
+
+    func testCommandValidation() {
+        XCTAssertThrowsError(try EmbossCommand.parse(["part.stl", "A#1"]))
+        XCTAssertThrowsError(try EmbossCommand.parse(["part.stl", "A1", "--offset", "3"]))
+        XCTAssertThrowsError(try EmbossCommand.parse(["part.stl", "A1", "--height", "1"]))
+        XCTAssertNoThrow(try EmbossCommand.parse(["part.stl", "ID-042", "--face", "front", "--engrave", "--depth", "0.4", "--font", "bold"]))
+    }
+}
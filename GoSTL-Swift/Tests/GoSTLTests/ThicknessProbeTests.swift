@@ -0,0 +1,56 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class ThicknessProbeTests: XCTestCase {
+
+    /// 40 x 30 mm plate, 2.5 mm thick
+    private let plate = ModelGenerator.box(from: Vector3(0, 0, 0), to: Vector3(40, 30, 2.5))
+
+    func testProbeFromAbove() throws {
+        // Looking straight down onto the top face
+        let ray = Ray(origin: SIMD3(10, 10, 50), direction: SIMD3(0, 0, -1))
+
+        let result = try XCTUnwrap(ThicknessProbe.probe(ray: ray, triangles: plate))
+
+        XCTAssertEqual(result.thickness, 2.5, accuracy: 1e-4)
+        XCTAssertEqual(result.entry.z, 2.5, accuracy: 1e-4)
+        XCTAssertEqual(result.exit.z, 0, accuracy: 1e-4)
+        XCTAssertEqual(result.normal.z, 1, accuracy: 1e-9)
+    }
+
+    func testProbeAlongNormalNotViewRay() throws {
+        // Oblique view onto a side face: the probe still runs across the wall, not along the view
+        let ray = Ray(origin: SIMD3(60, 5, 1.25), direction: simd_normalize(SIMD3(-1, 0.2, 0)))
+
+        let result = try XCTUnwrap(ThicknessProbe.probe(ray: ray, triangles: plate, accelerator: SpatialAccelerator(triangles: plate)))
+
+        XCTAssertEqual(result.thickness, 40, accuracy: 1e-3)
+        XCTAssertEqual(result.exit.y, result.entry.y, accuracy: 1e-4)
+    }
+
+    func testMiss() {
+        let ray = Ray(origin: SIMD3(100, 100, 50), direction: SIMD3(0, 0, -1))
+
+        XCTAssertNil(ThicknessProbe.probe(ray: ray, triangles: plate))
+    }
+
+    func testOpenSurfaceHasNoThickness() {
+        // The top face alone: nothing behind it
+        let top = plate.filter { $0.normal.z > 0.5 }
+        let ray = Ray(origin: SIMD3(10, 10, 50), direction: SIMD3(0, 0, -1))
+
+        XCTAssertNil(ThicknessProbe.probe(ray: ray, triangles: top))
+    }
+
+    func testMeasurement() throws {
+        let result = try XCTUnwrap(ThicknessProbe.probe(at: Vector3(10, 10, 2.5), normal: .unitZ, triangles: plate))
+
+        let measurement = ThicknessProbe.measurement(for: result)
+
+        XCTAssertEqual(measurement.type, .distance)
+        XCTAssertEqual(measurement.value, 2.5, accuracy: 1e-4)
+        XCTAssertEqual(measurement.note, "Thickness")
+        XCTAssertEqual(measurement.points[1].position.z, 0, accuracy: 1e-4)
+    }
+}
@@ -0,0 +1,78 @@
+import XCTest
+@testable import GoSTL
+
+final class ThumbnailTests: XCTestCase {
+    private let relationships = """
+        <?xml version="1.0" encoding="UTF-8"?>
+        <Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">\
+        <Relationship Target="/3D/3dmodel.model" Id="rel0" Type="http://schemas.microsoft.com/3dmanufacturing/2013/01/3dmodel"/>\
+        </Relationships>
+        """
+
+    private func makePackage(relationships: String) -> Data {
+        var writer = ZipWriter()
+        writer.add(path: "[Content_Types].xml", contents: Data("<Types></Types>".utf8))
+        writer.add(path: "_rels/.rels", contents: Data(relationships.utf8))
+        writer.add(path: "3D/3dmodel.model", contents: Data(String(repeating: "<vertex x=\"0\" y=\"0\" z=\"0\"/>", count: 100).utf8))
+        return writer.finish()
+    }
+
+    // MARK: - ZIP Tests
+
+    func testZipWriterRoundTrip() throws {
+        let model = Data(String(repeating: "<triangle v1=\"0\" v2=\"1\" v3=\"2\"/>", count: 200).utf8)
+        var writer = ZipWriter()
+        writer.add(path: "3D/3dmodel.model", contents: model)
+        writer.add(path: "empty.txt", contents: Data())
+
+        let archive = try ZipArchive(data: writer.finish())
+
+        XCTAssertEqual(archive.fileNames, ["3D/3dmodel.model", "empty.txt"])
+        XCTAssertEqual(try archive.extractFile(path: "3D/3dmodel.model"), model)
+    }
+
+    func testCRC32() {
+        XCTAssertEqual(ZipWriter.crc32(Data("123456789".utf8)), 0xCBF4_3926)
+    }
+
+    // MARK: - 3MF Thumbnail Tests
+
+    func testEmbedAddsRelationshipAndContentType() throws {
+        let png = Data([0x89, 0x50, 0x4E, 0x47, 1, 2, 3])
+
+        let package = try ThreeMFThumbnail.embed(png: png, into: makePackage(relationships: relationships))
+        let archive = try ZipArchive(data: package)
+
+        XCTAssertEqual(try ThreeMFThumbnail.extract(from: package), png)
+        let rels = String(decoding: try XCTUnwrap(archive.extractFile(path: "_rels/.rels")), as: UTF8.self)
+        XCTAssertEqual(ThreeMFThumbnail.thumbnailTarget(in: rels), ThreeMFThumbnail.defaultPath)
+        let types = String(decoding: try XCTUnwrap(archive.extractFile(path: "[Content_Types].xml")), as: UTF8.self)
+        XCTAssertTrue(types.contains(#"Extension="png""#))
+        XCTAssertNotNil(try archive.findModelFile())
+    }
+
+    func testEmbedReplacesExistingThumbnail() throws {
+        let first = try ThreeMFThumbnail.embed(png: Data([1]), into: makePackage(relationships: relationships))
+        let second = try ThreeMFThumbnail.embed(png: Data([2]), into: first)
+
+        let archive = try ZipArchive(data: second)
+        XCTAssertEqual(archive.fileNames.filter { $0.hasSuffix(".png") }, [ThreeMFThumbnail.defaultPath])
+        XCTAssertEqual(try ThreeMFThumbnail.extract(from: second), Data([2]))
+    }
+
+    func testPackageWithoutThumbnail() throws {
+        XCTAssertNil(try ThreeMFThumbnail.extract(from: makePackage(relationships: relationships)))
+    }
+
+    // MARK: - Command Tests
+
+    func testThumbnailFileNames() {
+        let folder = URL(fileURLWithPath: "/tmp/parts/thumbnails")
+
+        XCTAssertEqual(ThumbsCommand.thumbnailURL(for: URL(fileURLWithPath: "/tmp/parts/case.3mf"), in: folder).lastPathComponent, "case.3mf.png")
+    }
+
+    func testThumbsRejectsEmbedWithExtract() {
+        XCTAssertThrowsError(try ThumbsCommand.parse(["parts", "--embed", "--extract"]))
+    }
+}
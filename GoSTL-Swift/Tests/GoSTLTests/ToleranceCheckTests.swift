@@ -0,0 +1,139 @@
+import XCTest
+@testable import GoSTL
+
+final class ToleranceCheckTests: XCTestCase {
+
+    /// Grid of points on z = 0 with one point raised by `bump`
+    private func surface(bump: Double) -> [Vector3] {
+        var points: [Vector3] = []
+        for x in 0...4 {
+            for y in 0...4 {
+                points.append(Vector3(Double(x) * 10, Double(y) * 10, 0))
+            }
+        }
+        points.append(Vector3(20, 20, bump))
+        return points
+    }
+
+    // MARK: - Evaluation Tests
+
+    func testFlatnessOfPerfectPlaneIsZero() throws {
+        let points = [Vector3(0, 0, 3), Vector3(10, 0, 3), Vector3(0, 10, 3), Vector3(10, 10, 3)]
+
+        XCTAssertEqual(try XCTUnwrap(ToleranceEvaluator.flatness(of: points)), 0, accuracy: 1e-9)
+    }
+
+    func testFlatnessIgnoresTilt() throws {
+        // A tilted but flat surface is still perfectly flat
+        let points = [Vector3(0, 0, 0), Vector3(10, 0, 5), Vector3(0, 10, 0), Vector3(10, 10, 5)]
+
+        XCTAssertEqual(try XCTUnwrap(ToleranceEvaluator.flatness(of: points)), 0, accuracy: 1e-9)
+    }
+
+    func testFlatnessMeasuresPeakToValley() throws {
+        let flatness = try XCTUnwrap(ToleranceEvaluator.flatness(of: surface(bump: 0.2)))
+
+        XCTAssertEqual(flatness, 0.2, accuracy: 0.01)
+    }
+
+    func testFlatnessRequiresNonCollinearPoints() {
+        XCTAssertNil(ToleranceEvaluator.flatness(of: [Vector3(0, 0, 0), Vector3(1, 0, 0), Vector3(2, 0, 0)]))
+    }
+
+    func testParallelismToDatumPlaneIncludesTilt() throws {
+        // Top face rises 0.5 mm over its length relative to the datum
+        let points = [Vector3(0, 0, 10), Vector3(100, 0, 10.5), Vector3(0, 50, 10), Vector3(100, 50, 10.5)]
+        let datum = ReferenceEntity.Kind.plane(origin: .zero, normal: Vector3(0, 0, 1))
+
+        let parallelism = try XCTUnwrap(ToleranceEvaluator.parallelism(of: points, to: datum))
+
+        XCTAssertEqual(parallelism, 0.5, accuracy: 1e-9)
+    }
+
+    func testPerpendicularityToDatumPlane() throws {
+        // Side wall leaning 0.1 mm over its 20 mm height
+        let points = [Vector3(0, 0, 0), Vector3(0, 30, 0), Vector3(0.1, 0, 20), Vector3(0.1, 30, 20)]
+        let datum = ReferenceEntity.Kind.plane(origin: .zero, normal: Vector3(0, 0, 1))
+
+        let perpendicularity = try XCTUnwrap(ToleranceEvaluator.perpendicularity(of: points, to: datum))
+
+        XCTAssertEqual(perpendicularity, 0.1, accuracy: 1e-6)
+    }
+
+    func testPerpendicularityToDatumAxis() throws {
+        let points = [Vector3(0, 0, 5), Vector3(10, 0, 5), Vector3(0, 10, 5.05)]
+        let datum = ReferenceEntity.Kind.axis(origin: .zero, direction: Vector3(0, 0, 1))
+
+        let perpendicularity = try XCTUnwrap(ToleranceEvaluator.perpendicularity(of: points, to: datum))
+
+        XCTAssertEqual(perpendicularity, 0.05, accuracy: 1e-9)
+    }
+
+    func testPositionIsDiameterOfZone() throws {
+        let position = try XCTUnwrap(ToleranceEvaluator.position(of: .point(Vector3(10.03, 20.04, 0)), nominal: Vector3(10, 20, 0)))
+
+        XCTAssertEqual(position, 0.1, accuracy: 1e-9)
+    }
+
+    func testPositionOfAxisUsesRadialOffset() throws {
+        let axis = ReferenceEntity.Kind.axis(origin: Vector3(5.1, 0, 0), direction: Vector3(0, 0, 1))
+
+        let position = try XCTUnwrap(ToleranceEvaluator.position(of: axis, nominal: Vector3(5, 0, 30)))
+
+        XCTAssertEqual(position, 0.2, accuracy: 1e-9)
+    }
+
+    // MARK: - Check Tests
+
+    func testCheckPassesWithinTolerance() throws {
+        let check = ToleranceCheck(characteristic: .flatness, tolerance: 0.25, feature: .surface(points: surface(bump: 0.2), triangleCount: 32))
+
+        let result = try XCTUnwrap(ToleranceEvaluator.evaluate(check, entities: []))
+
+        XCTAssertTrue(result.passed)
+    }
+
+    func testCheckFailsOutsideTolerance() throws {
+        let check = ToleranceCheck(characteristic: .flatness, tolerance: 0.1, feature: .surface(points: surface(bump: 0.2), triangleCount: 32))
+
+        let result = try XCTUnwrap(ToleranceEvaluator.evaluate(check, entities: []))
+
+        XCTAssertFalse(result.passed)
+    }
+
+    func testDatumCheckWithoutDatumIsNotEvaluable() {
+        let check = ToleranceCheck(characteristic: .parallelism, tolerance: 0.1, feature: .surface(points: surface(bump: 0), triangleCount: 32))
+
+        XCTAssertNil(ToleranceEvaluator.evaluate(check, entities: []))
+    }
+
+    // MARK: - System Tests
+
+    func testRemovingDatumRemovesDependentChecks() throws {
+        let system = ReferenceGeometrySystem()
+        let datum = system.add(.plane(origin: .zero, normal: Vector3(0, 0, 1)))
+        let check = ToleranceCheck(characteristic: .parallelism, tolerance: 0.1, feature: .surface(points: surface(bump: 0.05), triangleCount: 32), datumID: datum.id)
+
+        XCTAssertNotNil(system.addToleranceCheck(check))
+        XCTAssertEqual(system.title(for: check), "∥ Parallelism (32 tri) | Plane 1")
+
+        system.remove(id: datum.id)
+        XCTAssertTrue(system.toleranceChecks.isEmpty)
+    }
+
+    func testReportSummarizesChecks() throws {
+        let system = ReferenceGeometrySystem()
+        let point = system.add(.point(Vector3(10.03, 20.04, 0)))
+        system.addToleranceCheck(ToleranceCheck(characteristic: .flatness, tolerance: 0.1, feature: .surface(points: surface(bump: 0.2), triangleCount: 32)))
+        system.addToleranceCheck(ToleranceCheck(characteristic: .position, tolerance: 0.2, feature: .entity(point.id), nominal: Vector3(10, 20, 0)))
+
+        let report = InspectionReport.generate(fileName: "part.stl", referenceGeometry: system, coordinateOffset: Vector3(100, 0, 0))
+
+        XCTAssertTrue(report.contains("File: part.stl"))
+        XCTAssertTrue(report.contains("Point 1: point (110.030, 20.040, 0.000)"))
+        XCTAssertTrue(report.contains("[FAIL] ⏥ Flatness (32 tri)"))
+        XCTAssertTrue(report.contains("[PASS] ⌖ Position Point 1: 0.100 / 0.200 mm (nominal 110.000, 20.000, 0.000)"))
+        XCTAssertTrue(report.contains("1 of 2 checks passed"))
+        XCTAssertTrue(report.contains("Result: FAIL"))
+    }
+}
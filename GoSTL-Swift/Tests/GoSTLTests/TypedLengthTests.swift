@@ -0,0 +1,148 @@
+import XCTest
+@testable import GoSTL
+
+final class TypedLengthTests: XCTestCase {
+    private func point(_ x: Double, _ y: Double, _ z: Double) -> MeasurementPoint {
+        MeasurementPoint(position: Vector3(x, y, z), normal: Vector3(0, 0, 1))
+    }
+
+    private func constrainedSystem(axis: Int = 0) -> MeasurementSystem {
+        let system = MeasurementSystem()
+        system.startMeasurement(type: .distance)
+        _ = system.addPoint(point(1, 2, 3))
+        system.toggleAxisConstraint(axis)
+        return system
+    }
+
+    private func type(_ text: String, into system: MeasurementSystem) {
+        for character in text {
+            system.appendLengthInput(character)
+        }
+    }
+
+    // MARK: - Input Tests
+
+    func testInputRequiresActiveConstraint() {
+        let system = MeasurementSystem()
+        system.startMeasurement(type: .distance)
+        _ = system.addPoint(point(0, 0, 0))
+
+        XCTAssertFalse(system.acceptsLengthInput)
+        XCTAssertFalse(system.appendLengthInput("5"))
+        XCTAssertNil(system.lengthInput)
+    }
+
+    func testTypingDigitsAndDecimalSeparator() {
+        let system = constrainedSystem()
+        type("25,4", into: system)
+
+        XCTAssertEqual(system.lengthInput, "25.4")
+        XCTAssertEqual(system.typedLength, 25.4)
+    }
+
+    func testLeadingSeparatorAndSecondSeparator() {
+        let system = constrainedSystem()
+        type(".5.", into: system)
+
+        XCTAssertEqual(system.lengthInput, "0.5")
+    }
+
+    func testMinusTogglesSign() {
+        let system = constrainedSystem()
+        type("10-", into: system)
+        XCTAssertEqual(system.lengthInput, "-10")
+
+        system.appendLengthInput("-")
+        XCTAssertEqual(system.lengthInput, "10")
+    }
+
+    func testRejectsOtherCharacters() {
+        let system = constrainedSystem()
+        XCTAssertFalse(system.appendLengthInput("x"))
+        XCTAssertNil(system.lengthInput)
+    }
+
+    func testZeroIsNotAValidLength() {
+        let system = constrainedSystem()
+        type("0.0", into: system)
+
+        XCTAssertNil(system.typedLength)
+        XCTAssertNil(system.placeTypedLength())
+    }
+
+    func testBackspaceAndCancel() {
+        let system = constrainedSystem()
+        type("12", into: system)
+
+        XCTAssertTrue(system.removeLastLengthInputCharacter())
+        XCTAssertEqual(system.lengthInput, "1")
+        XCTAssertTrue(system.removeLastLengthInputCharacter())
+        XCTAssertNil(system.lengthInput)
+        XCTAssertFalse(system.removeLastLengthInputCharacter())
+
+        type("3", into: system)
+        XCTAssertTrue(system.cancelLengthInput())
+        XCTAssertNil(system.lengthInput)
+        XCTAssertFalse(system.cancelLengthInput())
+    }
+
+    func testReleasingConstraintDiscardsInput() {
+        let system = constrainedSystem()
+        type("7", into: system)
+        system.constraint = nil
+
+        XCTAssertNil(system.lengthInput)
+    }
+
+    // MARK: - Placement Tests
+
+    func testPreviewEndpointFollowsTypedLength() throws {
+        let system = constrainedSystem(axis: 1)
+        type("5", into: system)
+
+        let endpoint = try XCTUnwrap(system.constrainedEndpoint)
+        XCTAssertTrue(endpoint.isApproximatelyEqual(to: Vector3(1, 7, 3), tolerance: 1e-9))
+    }
+
+    func testPlaceAlongAxisCreatesAirPointAndSegment() throws {
+        let system = constrainedSystem(axis: 0)
+        type("25.4", into: system)
+
+        let placed = try XCTUnwrap(system.placeTypedLength())
+
+        XCTAssertTrue(placed.isAirPoint)
+        XCTAssertEqual(placed.position.x, 26.4, accuracy: 1e-9)
+        XCTAssertEqual(system.measurements.last?.value ?? 0, 25.4, accuracy: 1e-9)
+        XCTAssertNil(system.constraint)
+        XCTAssertNil(system.lengthInput)
+    }
+
+    func testAxisDirectionFollowsHoverSide() throws {
+        let system = constrainedSystem(axis: 2)
+        system.hoverPoint = point(1, 2, -10)
+        type("4", into: system)
+
+        let placed = try XCTUnwrap(system.placeTypedLength())
+        XCTAssertEqual(placed.position.z, -1, accuracy: 1e-9)
+    }
+
+    func testNegativeLengthFlipsDirection() throws {
+        let system = constrainedSystem(axis: 0)
+        type("-3", into: system)
+
+        let placed = try XCTUnwrap(system.placeTypedLength())
+        XCTAssertEqual(placed.position.x, -2, accuracy: 1e-9)
+    }
+
+    func testPointConstraintPointsTowardTarget() throws {
+        let system = MeasurementSystem()
+        system.startMeasurement(type: .distance)
+        _ = system.addPoint(point(0, 0, 0))
+        system.setPointConstraint(toward: Vector3(0, 10, 0))
+        system.hoverPoint = point(0, -5, 0)
+        type("2", into: system)
+
+        let placed = try XCTUnwrap(system.placeTypedLength())
+        XCTAssertEqual(placed.position.y, 2, accuracy: 1e-9)
+    }
+}
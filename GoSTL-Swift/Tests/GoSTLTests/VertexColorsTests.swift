@@ -0,0 +1,66 @@
+import XCTest
+import simd
+@testable import GoSTL
+
+final class VertexColorsTests: XCTestCase {
+    private let red = TriangleColor(1, 0, 0)
+    private let blue = TriangleColor(0, 0, 1)
+
+    private func triangle(color: TriangleColor? = nil) -> Triangle {
+        var triangle = Triangle(v1: Vector3(0, 0, 0), v2: Vector3(1, 0, 0), v3: Vector3(0, 1, 0))
+        triangle.color = color
+        return triangle
+    }
+
+    // MARK: - Triangle Color Tests
+
+    func testMissingAndWhiteColorsUseTheMaterial() {
+        XCTAssertEqual(VertexColors.vertexColor(of: nil), VertexColors.material)
+        XCTAssertEqual(VertexColors.vertexColor(of: .white), VertexColors.material)
+        XCTAssertEqual(VertexColors.vertexColor(of: red), SIMD4(1, 0, 0, 1))
+    }
+
+    func testPerTriangleColors() {
+        let colors = VertexColors(perTriangle: [red, nil])
+
+        XCTAssertEqual(colors.triangleCount, 2)
+        XCTAssertEqual(colors.corners(ofTriangle: 0).2, SIMD4(1, 0, 0, 1))
+        XCTAssertEqual(colors.corners(ofTriangle: 1).0, VertexColors.material)
+    }
+
+    func testAnalysisColorsAreKeptEvenIfWhite() {
+        // Only triangle colors treat white as "no color"; explicit vertex colors are drawn as they are
+        let colors = VertexColors(perTriangle: [.white])
+
+        XCTAssertEqual(colors.corners(ofTriangle: 0).0, SIMD4(1, 1, 1, 1))
+    }
+
+    // MARK: - Face Color Tests
+
+    func testFaceColoredUsesTheMeanOfTheCorners() throws {
+        let colors = VertexColors(perVertex: [SIMD4(1, 0, 0, 1), SIMD4(0, 0, 1, 1), SIMD4(0, 1, 0, 1)])
+
+        let color = try XCTUnwrap(colors.faceColored([triangle(color: red)]).first?.color)
+
+        XCTAssertEqual(color.r, 1.0 / 3, accuracy: 1e-6)
+        XCTAssertEqual(color.g, 1.0 / 3, accuracy: 1e-6)
+        XCTAssertEqual(color.b, 1.0 / 3, accuracy: 1e-6)
+    }
+
+    func testFaceColoredKeepsUncoloredFacesInTheMaterialColor() {
+        let colors = VertexColors(perTriangle: [nil, blue])
+
+        let colored = colors.faceColored([triangle(color: red), triangle()])
+
+        XCTAssertNil(colored[0].color)
+        XCTAssertEqual(colored[1].color, blue)
+    }
+
+    func testFaceColoredIgnoresMismatchedTriangles() {
+        let colors = VertexColors(perTriangle: [blue])
+
+        let colored = colors.faceColored([triangle(color: red), triangle()])
+
+        XCTAssertEqual(colored.map(\.color), [red, nil])
+    }
+}
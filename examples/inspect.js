@@ -0,0 +1,38 @@
+// Example inspection script
+//
+//   gostl script examples/inspect.js examples/cube.stl --output results.json
+//
+// Bindings:
+//   gostl.model                       model passed on the command line or loaded in the viewer
+//   gostl.load(path)                  load another STL/3MF file (relative to this script)
+//   gostl.triangles(model)            [{v1, v2, v3, normal, area}]
+//   gostl.closestVertex(model, point) nearest mesh vertex
+//   gostl.distance(a, b)              create a distance measurement, returns mm
+//   gostl.angle(a, vertex, c)         create an angle measurement, returns degrees
+//   gostl.radius(a, b, c)             create a radius measurement, returns mm
+//   gostl.result(name, value)         record a named result
+//   gostl.fail(message)               record a failed check (exit status 1)
+//   gostl.exportResults(path)         write results as JSON
+//   print(...) / console.log(...)     write output
+
+const model = gostl.model;
+if (!model) {
+    throw new Error("Pass a model: gostl script inspect.js model.stl");
+}
+
+print(`${model.name}: ${model.triangleCount} triangles`);
+
+const size = model.dimensions;
+gostl.result("width", size.x);
+gostl.result("depth", size.y);
+gostl.result("height", size.z);
+
+// Diagonal of the bounding box as a measurement
+const box = model.boundingBox;
+const diagonal = gostl.distance(box.min, box.max);
+print(`Diagonal: ${diagonal.toFixed(2)} mm`);
+
+// Example check: the part must fit a 250 mm build plate
+if (size.x > 250 || size.y > 250) {
+    gostl.fail("Part does not fit a 250 mm build plate");
+}
@@ -0,0 +1,54 @@
+@rendering @presentation @ambient_occlusion
+Feature: Ambient Occlusion
+  As a user presenting complex parts
+  I want creases and cavities shaded darker
+  So that the geometry reads with depth on a projector and in thumbnails
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Off by default
+    Given I have not changed the setting
+    When I start presentation mode
+    Then no ambient occlusion should be baked
+    And the model should be shaded as in the normal view
+
+  Scenario: Bake when a presentation starts
+    Given "Ambient Occlusion in Presentations" is enabled in the View menu
+    When I start presentation mode
+    Then "Baking ambient occlusion..." should run as a background job with progress
+    And the presentation should stay interactive while it runs
+    And creases and cavities should be shaded darker once it finishes
+
+  Scenario: The normal view stays unshaded
+    Given "Ambient Occlusion in Presentations" is enabled in the View menu
+    And the ambient occlusion was baked in a presentation
+    When I leave presentation mode
+    Then the model should be shaded without occlusion
+
+  Scenario: The bake is reused until the model changes
+    Given the ambient occlusion was baked in a presentation
+    When I leave and start presentation mode again
+    Then no new bake should run
+    When I reload the model in a presentation
+    Then the ambient occlusion should be baked again
+
+  Scenario: Leaving the presentation cancels a running bake
+    Given "Ambient Occlusion in Presentations" is enabled in the View menu
+    And a bake is running
+    When I leave presentation mode
+    Then the bake should be cancelled
+
+  Scenario: Sliced views are not shaded
+    Given the ambient occlusion was baked in a presentation
+    When I slice the model
+    Then the clipped geometry should be shaded without occlusion
+
+  Scenario: Enable in the settings
+    When I open the Display settings
+    Then I should see "Ambient occlusion in presentations"
+
+  Scenario: Thumbnails with ambient occlusion
+    When I run "gostl thumbs parts --ambient-occlusion"
+    Then the thumbnails should show creases and cavities darker than open surfaces
@@ -0,0 +1,47 @@
+@export @analysis
+Feature: Export with Analysis Colors
+  As a user sharing analysis results with customers who use other viewers
+  I want to export the model with the analysis colors baked in as vertex colors
+  So that the results can be viewed in MeshLab, Blender, slicers or the Windows 3D Viewer
+
+  Scenario: Export the draft analysis as PLY
+    Given a model "mold.stl" is loaded
+    And Tools > Draft Analysis is enabled
+    When I select File > Export with Analysis Colors...
+    Then the save panel should suggest "mold-draft.ply"
+    When I save
+    Then "mold-draft.ply" should be a binary PLY with one RGBA color per vertex
+    And the colors should match the draft colors shown in the viewer
+    And the vertices should be in the file's original coordinates
+
+  Scenario: Export the height colors as 3MF
+    Given View > Color by Height is enabled
+    When I select File > Export with Analysis Colors... and save as "plate-height.3mf"
+    Then the 3MF should contain a color group with the gradient colors
+    And every triangle corner should reference its color
+    And opening the 3MF in GoSTL should show the colored faces
+
+  Scenario: Smooth gradients
+    When the model is exported with analysis colors
+    Then triangles should share a vertex only where both position and color match
+
+  Scenario: Faces without an analysis color
+    Given degenerate triangles that have no draft angle
+    When the model is exported with analysis colors
+    Then their vertices should be written in the material color
+
+  Scenario: No analysis shown
+    Given neither Draft Analysis nor Color by Height is enabled
+    Then File > Export with Analysis Colors... should be disabled
+
+  Scenario: Analysis still running
+    Given the analysis colors are still being computed
+    When I export with analysis colors
+    Then an error should ask me to wait for the analysis to finish
+
+  Scenario: Draft colors from the command line
+    When I run "gostl draft mold.stl --pull z --min 1 --colors mold-draft.ply"
+    Then the draft report should be printed
+    And "mold-draft.ply" should contain the model with the draft colors
+    When I run "gostl draft mold.stl --colors mold.obj"
+    Then the command should fail with a usage error
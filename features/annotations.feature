@@ -0,0 +1,55 @@
+@measurement @annotations
+Feature: Annotations
+  As a user reviewing a model with others
+  I want to pin short text notes to points of the model
+  So that remarks like "crack here" stay attached to the geometry they describe
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Add an annotation
+    When I right-click the model surface
+    And I choose "Add Annotation…" and enter "crack here"
+    Then a pin should be shown at the clicked point
+    And a label "crack here" should be connected to it by a leader line
+
+  Scenario: Annotations stay readable at any angle
+    Given an annotation "crack here" is pinned to the model
+    When I orbit the camera
+    Then the label should always face the screen
+    And the pin and leader line should follow the anchor point
+
+  Scenario: Annotations give way to measurement labels
+    Given a distance measurement label is near an annotation
+    Then the annotation label should be moved aside so that both stay readable
+    And overlapping annotations should be merged into one label with a "+N" count
+
+  Scenario: Edit and delete an annotation
+    Given an annotation "crack here" is pinned to the model
+    When I right-click its label or pin and choose "Edit Annotation…"
+    And I enter "crack near the boss"
+    Then the label should read "crack near the boss"
+    When I clear the text
+    Then the annotation should be removed
+    When I right-click another annotation and choose "Delete Annotation"
+    Then it should be removed
+
+  Scenario: Hide and remove annotations
+    When I turn off View > Annotations
+    Then pins, leader lines and labels should be hidden
+    When I select Tools > Remove All Annotations
+    Then all annotations of the model should be removed
+
+  Scenario: Annotations are stored in the sidecar
+    When I add an annotation to "housing.stl"
+    Then it should be written to "housing.stl.gostl" in file coordinates
+    And it should be shown again at the same point after reopening or reloading the file
+    And sidecars written before annotations existed should still load
+
+  Scenario: Annotations in exports
+    Given the annotations "crack here" and "burr" are pinned to the model
+    When I save the measurements
+    Then the measurement set should include both annotations
+    When I select File > Export Inspection Report...
+    Then the report should have an ANNOTATIONS section listing each text with its coordinates
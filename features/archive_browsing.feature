@@ -0,0 +1,61 @@
+@file-io @archive
+Feature: ZIP Archive Browsing
+  As a user
+  I want to open a ZIP archive of models directly
+  So that I can look at downloaded model packs without unpacking them first
+
+  Background:
+    Given the application is running
+
+  Scenario: Open a ZIP archive with several models
+    When I open a .zip file containing "base.stl", "lid.stl" and "parts/clip.3mf"
+    Then the archive selector should appear at the bottom-center
+    And it should list "base.stl", "lid.stl" and "parts/clip.3mf"
+    And the first model should be displayed
+    And the window title should show "pack.zip › base.stl"
+
+  Scenario: Switch between models of the archive
+    Given I have a ZIP archive with several models open
+    When I choose "lid.stl" in the archive selector
+    Then "lid.stl" should be displayed
+    And the camera should reframe to the new model
+
+  Scenario: View all models as an assembly
+    Given I have a ZIP archive with several models open
+    When I choose "All Models (Assembly)" in the archive selector
+    Then all models should be displayed together in their own coordinates
+    And the window title should show "pack.zip (all 3 models)"
+
+  Scenario: Broken models in the assembly
+    Given a ZIP archive where one of the models cannot be parsed
+    When I show the assembly
+    Then the other models should be displayed
+    And a warning should name the model that could not be loaded
+
+  Scenario: Archive with a single model
+    When I open a .zip file containing only "part.stl"
+    Then the model should be displayed
+    And the archive selector should not appear
+
+  Scenario: OpenSCAD projects inside an archive
+    Given a ZIP archive with "project/main.scad" that uses "project/lib/util.scad"
+    When I open the archive
+    Then only "project/main.scad" should be listed
+    And it should render with its library files found next to it
+
+  Scenario: Junk and unsafe entries
+    Given a ZIP archive created by the macOS Finder
+    When I open the archive
+    Then "__MACOSX" entries and hidden files should not be listed
+    And an archive with paths leaving the archive folder (for example "../part.stl") should be rejected
+
+  Scenario: Reload keeps the selected model
+    Given I have "lid.stl" of a ZIP archive selected
+    When the archive changes on disk
+    Then the archive should be extracted again
+    And "lid.stl" should still be selected
+    And the first model should be selected if "lid.stl" was removed
+
+  Scenario: Archives on the command line
+    When I run "gostl info pack.zip"
+    Then the statistics should cover all models of the archive as one assembly
@@ -0,0 +1,78 @@
+@internal @background-jobs
+Feature: Background Jobs
+  As a user
+  I want slow work to run in the background
+  So that the viewer stays responsive and I can stop work I no longer need
+
+  Background:
+    Given the application is running
+    And I have a 3D model file open
+
+  Scenario: Reload runs in the background
+    When the file is auto-reloaded
+    Then rendering and parsing should run off the main thread
+    And the previous model should stay visible and navigable until the new one is ready
+    And the new model should be swapped in on the main thread
+
+  @openscad
+  Scenario: Reload progress for colored OpenSCAD models
+    Given I have an OpenSCAD file with 5 colors open
+    When the file is auto-reloaded
+    Then the loading overlay should show the current step
+    And it should show "Rendering colors 3/5..." with a progress bar while the colors render
+
+  @openscad
+  Scenario: Cancel a reload
+    Given a reload of an OpenSCAD file is in progress
+    When I click "Cancel" in the loading overlay
+    Then the OpenSCAD processes should be terminated
+    And the previous model should remain visible
+    And the file watcher should resume
+
+  @openscad
+  Scenario: A newer reload replaces a running one
+    Given a reload of an OpenSCAD file is in progress
+    When I press Cmd+R
+    Then the running render should be cancelled
+    And only the newer render should be shown
+
+  Scenario Outline: Background work indicator
+    When <work> runs in the background
+    Then the background indicator should show "<title>"
+
+    Examples:
+      | work                          | title                     |
+      | the spatial index build       | Building spatial index... |
+      | the wireframe edge extraction | Building wireframe...     |
+      | a plugin analyzer             | Running <analyzer>...     |
+      | saving the model              | Saving <file name>...     |
+
+  Scenario: Cancel a plugin analyzer
+    Given a plugin analyzer is running
+    When I click the cancel button next to it in the background indicator
+    Then the plugin process should be terminated
+    And the plugin results panel should close
+
+  Scenario: Save in the background
+    When I save the model
+    Then the STL should be written off the main thread
+    And the window title should update when writing has finished
+    And an error dialog should appear if writing fails
+
+  Scenario: Opening another file stops background work
+    Given a reload is in progress
+    When I open a different file in the same window
+    Then all running jobs should be cancelled
+    And their results should be discarded
+
+  Scenario: Reloaded model is swapped in as one unit
+    Given a reload is in progress
+    When the new mesh has been prepared in the background
+    Then the model, its coordinate offset and its vertex buffer should be replaced together on the main thread
+    And no frame should draw the new model with the old vertex buffer
+
+  Scenario: Late background results are discarded
+    Given the spatial index or wireframe of a model is still being built
+    When the model is replaced, leveled or cleared
+    Then the late result should not be installed
+    And finished results should only be installed at the start of a frame
@@ -0,0 +1,72 @@
+@measurement @batch
+Feature: Batch Measurement Templates
+  As a user checking a production batch of the same part
+  I want to define the feature picks and dimensions once and apply them to every part
+  So that each part is measured the same way and the results end up in one table
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Define a template
+    When I choose Tools > Batch Templates > New Template...
+    And I enter the name "Bracket" and the definition:
+      """
+      hole1 center: hole
+      hole2 center: hole
+      base plane: face
+      pitch = hole1 center to hole2 center: 40 ± 0.1
+      height = hole1 center to base plane
+      """
+    Then "Apply Bracket" should appear in the Batch Templates submenu
+    And the template should be kept for later sessions
+
+  Scenario: Invalid definitions are rejected
+    When I save a template with a dimension between features that are not defined
+    Then an alert should name the line to fix
+    And the template should not be saved
+
+  Scenario: Template names are unique
+    Given a template "Bracket" is saved
+    When I save a new template named "bracket"
+    Then an alert should say that a template named "bracket" already exists
+    And the saved "Bracket" template should be unchanged
+
+  Scenario: Rename a template
+    When I edit the template "Bracket" and change its name to "Bracket v2"
+    Then the template should be replaced in place, also after restarting the app
+
+  Scenario: Guided picks
+    Given the template "Bracket" is applied
+    Then the Batch panel should prompt "hole1 center: Click 3 points around the rim (0 / 3)"
+    When I click 3 points around the first hole
+    Then the panel should prompt for "hole2 center"
+
+  Scenario: A part is recorded after its last feature
+    Given the template "Bracket" is applied
+    When I pick every feature of the template
+    Then a row named after the file should be added to the results
+    And it should list both hole diameters, the pitch and the height
+    And the distances should be shown as measurements named after the dimensions
+    And the prompts should start again with the first feature
+
+  Scenario: Measure the next part
+    Given a part of the batch has been recorded
+    When I open the next part
+    Then the prompts should start with the first feature
+    And the results of the earlier parts should be kept
+
+  Scenario: Tolerances
+    Given the pitch of a part is 40.2 mm
+    Then the part should be marked out of tolerance
+    And the pitch should be shown in red
+
+  Scenario: Skip a feature
+    Given the template "Bracket" is applied
+    When I press "Skip Feature" for "hole1 center"
+    Then the columns depending on it should stay empty for this part
+
+  Scenario: Export the combined results
+    Given several parts have been recorded
+    When I choose Tools > Batch Templates > Export Batch Results...
+    Then a CSV file should be written with one row per part, one column per dimension and a pass/fail column
@@ -0,0 +1,46 @@
+@measurement @dimensions
+Feature: Overall Bounding Box Dimensions
+  As a user
+  I want the overall width, depth and height dimensioned with one key
+  So that screenshots and exports always show the envelope without manual picking
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Dimension the bounding box
+    When I press B
+    Then three distance measurements should be added for the X, Y and Z extents
+    And each value should equal the bounding box size along its axis
+
+  Scenario: Dimensions are drawn like drawing dimensions
+    When I press B
+    Then the width should be drawn in front of the model (toward -Y)
+    And the depth should be drawn to the right of the model (toward +X)
+    And the height should be drawn at the front-left edge (toward -X)
+    And each dimension line should be offset from the bounding box by 10% of its largest extent
+    And extension lines should connect the dimension line to the bounding box corners
+
+  Scenario: Menu and tool panel
+    When I choose Tools > Dimension Bounding Box
+    Then the overall dimensions should be added
+    And the measurement tools in the menu panel should offer "Overall Dimensions" with key B
+
+  Scenario: Pressing B again replaces the dimensions
+    Given the overall dimensions were added
+    And the model was reloaded with a different size
+    When I press B
+    Then the previous overall dimensions should be removed
+    And new overall dimensions should be added for the current size
+    And picked measurements should be kept
+
+  Scenario: Flat models
+    Given a model without thickness along Z
+    When I press B
+    Then only the X and Y dimensions should be added
+
+  Scenario: Overall dimensions are regular measurements
+    Given the overall dimensions were added
+    Then they should appear in screenshots, the measurement log and inspection reports
+    And they should be saved with exported measurement sets, including their extension lines
+    And they can be selected, noted and deleted like picked measurements
@@ -0,0 +1,60 @@
+@measurement @calibration
+Feature: Calibration Check
+  As a user tuning a printer
+  I want to measure the scan of a printed calibration artifact against its nominal size
+  So that I get the scale error per axis and the compensation to enter in the slicer
+
+  Background:
+    Given the application is running
+    And the scan of a printed calibration artifact is loaded
+    And "Calibration Check" is enabled in the Tools menu
+
+  Scenario: Select a shipped artifact
+    When I open the artifact picker in the Calibration panel
+    Then I should see "20 mm Calibration Cube", "50 mm Calibration Cube", "100 × 100 × 10 mm Test Plate" and "Custom Block"
+    And the panel should show how to print the selected artifact
+
+  Scenario: Measure a cube printed too large in X
+    Given the scan of a 20 mm cube measures 20.2 mm across X
+    And "20 mm Calibration Cube" is selected
+    When I click "Measure"
+    Then the X row should show an error of "+1.00 %"
+    And the X row should show a scale of "99.01 %"
+    And the Y and Z rows should show their own errors and scales
+
+  Scenario: Sizes are taken between opposite faces
+    Given the scan has noisy faces and rounded edges
+    When I click "Measure"
+    Then each axis should be measured between the average planes of its two opposite faces
+    And stray vertices should not change the result
+
+  Scenario: One compensation for slicers with a single XY setting
+    When I click "Measure"
+    Then the panel should show the XY compensation as the mean of X and Y
+
+  Scenario: Custom block
+    When I select "Custom Block"
+    And I enter 60 × 30 × 5 mm
+    And I click "Measure"
+    Then the errors should be relative to 60, 30 and 5 mm
+
+  Scenario: Warn about a rotated scan
+    Given the scan is rotated 45° around Z
+    When I click "Measure"
+    Then I should see a warning to align the scan first
+    And I should see that the bounding box was used for X and Y
+
+  Scenario: Warn about an implausible error
+    Given "50 mm Calibration Cube" is selected for a 20 mm cube
+    When I click "Measure"
+    Then I should see a warning that an axis is off by more than 10 %
+
+  Scenario: Copy the report
+    Given the scan was measured
+    When I click "Copy Report"
+    Then the clipboard should contain the nominal, measured, error and compensation of each axis
+
+  Scenario: The report is dropped when the model changes
+    Given the scan was measured
+    When I align the scan to its principal axes
+    Then the report should be cleared until I measure again
@@ -0,0 +1,35 @@
+@measurement @caliper
+Feature: Virtual Caliper
+  As a user measuring a printed or scanned part
+  I want two parallel jaws that close onto the model like a physical caliper
+  So that rounded faces are measured at their outermost points without picking vertices
+
+  Scenario: Open the caliper across an axis
+    Given a model is loaded
+    When I choose "Across X" in the Tools > Caliper menu
+    Then two orange jaw planes across X should be shown
+    And the jaws should be opened around the whole model
+    And the Caliper panel should show the distance between the seated jaws
+
+  Scenario: Jaws close onto the outermost surface between them
+    Given the caliper is shown
+    When I drag a jaw slider into the model
+    Then the faint open jaw should follow the slider
+    And the seated jaw should rest on the outermost surface point between the jaws
+    And surface reaching past a jaw should stop that jaw where it is
+    And "No surface between the jaws" should be shown when the jaws enclose no surface
+
+  Scenario: Caliper across a face normal
+    Given triangles of a flat face are selected
+    When I choose "Across Selected Face" in the Tools > Caliper menu
+    Then the jaws should be parallel to the plane fitted through the selected triangles
+
+  Scenario: Keep a reading
+    Given the caliper is shown
+    When I click "Add as Measurement"
+    Then a distance measurement noted "Caliper" should be added from the contact point to the other jaw
+
+  Scenario: Reopen the jaws
+    Given the jaws were closed onto a detail
+    When I click "Open Jaws"
+    Then the jaws should open around the whole model again
@@ -15,6 +15,26 @@ Feature: Camera Navigation
     And the rotation should follow the drag direction
     And the rotation should be free orbital rotation
 
+  @mouse @snapping
+  Scenario: Snap rotation to 15° steps
+    When I hold Control while rotating with a drag
+    Then the pitch and yaw should jump in 15° steps
+    And releasing Control should continue the rotation freely from there
+    And the camera should not keep spinning after a snapped drag
+
+  @keyboard @snapping
+  Scenario Outline: Turn by exact quarter turns
+    When I press <key>
+    Then the camera should turn to the next multiple of 90° <direction>
+    And a view between quarter turns should first round to the nearest one
+
+    Examples:
+      | key         | direction                        |
+      | Shift+Left  | around the Z axis                |
+      | Shift+Right | around the Z axis                |
+      | Shift+Up    | upward, up to the top view       |
+      | Shift+Down  | downward, down to the bottom view |
+
   @mouse
   Scenario: Pan camera with shift-click drag
     When I hold Shift and left-click and drag on the viewport
@@ -35,9 +55,148 @@ Feature: Camera Navigation
     And scroll down should zoom out
     And the zoom sensitivity should be adjustable
 
+  @mouse @navigation-scheme
+  Scenario: Choose a CAD navigation preset
+    When I open GoSTL > Settings (Cmd+,) and select the Navigation tab
+    Then I should be able to choose the mouse scheme GoSTL, Fusion 360, SolidWorks or Blender
+    And the rotate, pan and zoom gestures of the selected scheme should be listed
+    And the selection should be stored in ~/.config/gostl/settings.json and apply to all windows
+
+  @mouse @navigation-scheme
+  Scenario Outline: Middle mouse drag follows the selected scheme
+    Given the mouse scheme is "<scheme>"
+    When I <gesture>
+    Then the camera should <action>
+
+    Examples:
+      | scheme     | gesture                             | action |
+      | GoSTL      | middle-click and drag               | pan    |
+      | Fusion 360 | middle-click and drag               | pan    |
+      | Fusion 360 | hold Shift and middle-click drag    | rotate |
+      | SolidWorks | middle-click and drag               | rotate |
+      | SolidWorks | hold Control and middle-click drag  | pan    |
+      | SolidWorks | hold Shift and middle-click drag    | zoom   |
+      | Blender    | middle-click and drag               | rotate |
+      | Blender    | hold Shift and middle-click drag    | pan    |
+      | Blender    | hold Control and middle-click drag  | zoom   |
+
+  @mouse @navigation-scheme
+  Scenario: Left drag works in every scheme
+    Given any mouse scheme is selected
+    When I left-click and drag on the viewport
+    Then the camera should rotate
+    And Shift + left drag should pan
+    And trackpad users should still be able to navigate
+
+  @mouse @inertia
+  Scenario: Rotation inertia
+    Given "Inertia" is enabled in Settings > Navigation
+    When I quickly drag to rotate and release the mouse while still moving
+    Then the camera should keep rotating in the same direction
+    And the rotation should slow down smoothly and stop
+    When I click or start a new drag
+    Then the camera should stop immediately
+
+  @mouse @inertia
+  Scenario: Smooth mouse wheel zoom
+    Given "Inertia" is enabled in Settings > Navigation
+    When I scroll one notch with a mouse wheel
+    Then the camera should glide to the new zoom distance instead of jumping
+    And trackpad scrolling should keep the system momentum
+
+  @mouse @zoom-to-cursor
+  Scenario: Zoom to cursor
+    Given "Zoom to cursor" is enabled in Settings > Navigation
+    When I scroll with the mouse over a point on the model
+    Then the point under the cursor should stay at the same screen position
+    And the orbit target should move toward that point
+    When I scroll over the background
+    Then the zoom should move toward the cursor direction at the orbit target depth
+
+  @mouse @inertia
+  Scenario: Navigation extras are off by default
+    Given a fresh installation
+    Then "Inertia" and "Zoom to cursor" should be disabled
+    And scrolling should zoom toward the orbit target
+
+  @mouse @orbit-target
+  Scenario: Double-click sets the orbit target
+    When I double-click a point on the model surface
+    Then the orbit target should move to that point over about 0.35 seconds
+    And the camera distance and viewing angles should stay the same
+    And subsequent rotation should pivot around that point
+
+  @mouse @orbit-target
+  Scenario Outline: Double-click is ignored where clicks have another meaning
+    When I double-click <where>
+    Then the orbit target should not change
+
+    Examples:
+      | where                                              |
+      | the background                                     |
+      | the orientation cube                               |
+      | a measurement label                                |
+      | the model while a measurement tool is collecting   |
+      | the model while leveling is active                 |
+
+  @mouse @orbit-target
+  Scenario: Interrupt the target transition
+    Given the orbit target is moving to a double-clicked point
+    When I press the mouse button again
+    Then the transition should stop where it is
+
   @zoom-limits
   Scenario: Camera distance clamping
     When I zoom in to the maximum extent
     Then the camera distance should not go below 1.0 units
     When I zoom out to the maximum extent
     Then the camera distance should not exceed 1000.0 units
+
+  @clipping-planes
+  Scenario: Automatic depth range fitting
+    Given "Fit to model automatically" is enabled in the clipping planes panel
+    When the camera moves
+    Then the near and far planes should be fitted around the model, grid and build plate every frame
+    And the far/near ratio should never exceed 1:10000
+    And very large and very small models should render without z-fighting
+
+  @clipping-planes
+  Scenario: Manual near/far planes
+    When I select View > Clipping Planes
+    Then the clipping planes panel should appear in the bottom-right corner
+    When I drag the Near or Far slider (logarithmic scale, 0.0001 to 100000)
+    Then automatic fitting should be disabled
+    And the camera should use the chosen near/far planes
+
+  @clipping-planes
+  Scenario: Depth precision warning
+    Given the near/far planes are set manually
+    When the far/near ratio exceeds 1:100000
+    Then the ratio should be shown in orange
+    And a warning about z-fighting should be shown in the clipping planes panel
+
+  @clipping-planes @cutaway
+  Scenario: Cutaway follows the camera
+    When I select View > Cutaway Follows Camera
+    Then the model should be cut by a plane facing the camera through the orbit target
+    And the clipping planes panel should show the cutaway depth
+    And the inside of the model should be drawn as a flat cap where it is cut
+    When I move the camera into the model
+    Then the cut should move with the camera and reveal the interior
+
+  @clipping-planes @cutaway
+  Scenario: Adjust the cutaway depth
+    Given the cutaway is enabled
+    When I press "]"
+    Then the cut should move away from the camera by 2% of the model size
+    When I press Shift+"["
+    Then the cut should move toward the camera by 10% of the model size
+    When I drag the Depth slider in the clipping planes panel
+    Then the cut should be placed at the chosen distance from the camera
+
+  @clipping-planes @cutaway
+  Scenario: Picking through the cutaway
+    Given the cutaway is enabled
+    When I hover or click on the model
+    Then surfaces in front of the cut should be ignored
+    And the surface behind the cut should be picked
@@ -0,0 +1,62 @@
+@analysis @clearance
+Feature: Collision and Clearance Check
+  As a user fitting parts together (e.g. a PCB into its enclosure)
+  I want to check a second model against the loaded one
+  So that I can see where they collide and how much clearance is left
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Add a second model
+    When I select "Check Clearance Against..." from the Tools menu and choose "pcb.stl"
+    Then "pcb.stl" should be shown in a light blue tint in its own file coordinates
+    And a background job "Checking clearance..." should run
+    And the clearance panel should show the model name
+
+  Scenario: Models with clearance
+    Given the second model is 1.5 mm away from the loaded model at its closest point
+    When the clearance check finishes
+    Then the panel should show "No collision"
+    And the minimum clearance should be 1.500 mm
+    And the closest points should be listed in file coordinates
+    And a line should connect the closest points in the scene
+
+  Scenario: Add the clearance as a measurement
+    Given the clearance check found a minimum clearance of 1.5 mm
+    When I click "Measure" in the clearance panel
+    Then a 1.500 mm distance measurement should be added between the closest points
+    And the camera should orbit around the gap
+
+  Scenario: Interpenetrating models
+    Given the second model passes through a wall of the loaded model
+    When the clearance check finishes
+    Then the panel should show "Models intersect" with the triangle counts of both models
+    And the size and location of the intersecting region should be listed
+    And the intersecting triangles of both models should be highlighted in red
+    When I click "Show Region"
+    Then the camera should orbit around the intersecting region
+
+  Scenario: Touching models
+    Given the second model rests on a face of the loaded model
+    When the clearance check finishes
+    Then the panel should show "Models touch"
+    And the minimum clearance should be 0.000 mm
+    And no triangles should be highlighted
+
+  Scenario: Model changes
+    Given a clearance model is loaded
+    When I level the model
+    Then the clearance check should run again against the levelled model
+
+  Scenario: Remove the second model
+    Given a clearance model is loaded
+    When I click the close button of the clearance panel
+    Then the second model, its highlights and the panel should be removed
+    And opening another file should also remove it
+
+  Scenario: Check clearance from the command line
+    When I run "gostl clearance enclosure.stl pcb.stl --min 0.5"
+    Then the minimum clearance and the closest points should be printed
+    And the command should exit with status 1 if the models intersect
+    And the command should exit with status 1 if the clearance is below 0.5 mm
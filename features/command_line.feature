@@ -0,0 +1,188 @@
+@cli
+Feature: Command Line Interface
+  As a user automating model checks
+  I want headless gostl subcommands with machine-readable output
+  So that scripts can consume results without parsing human-formatted text
+
+  Background:
+    Given the gostl binary is on the PATH
+
+  Scenario: Analyze a model
+    When I run "gostl analyze part.stl"
+    Then the triangle count, dimensions, bounds, volume, surface area, edge lengths and PLA weights should be printed
+    And the values should be aligned with their units
+
+  Scenario Outline: Machine-readable formats
+    When I run "gostl analyze part.stl --format <format>"
+    Then the output should be <output>
+
+    Examples:
+      | format | output                                                         |
+      | text   | one labelled line per value                                    |
+      | json   | a JSON object with nested dimensions, bounds and edge_length   |
+      | csv    | a header row of field keys and one row of values               |
+
+  Scenario: Analyze several models
+    When I run "gostl analyze a.stl b.stl --format csv"
+    Then the output should have a header row and one row per model
+    And "--format json" should print an array with one object per model
+
+  Scenario: Select a single value
+    When I run "gostl analyze part.stl --select volume"
+    Then only the volume in mm³ should be printed
+    And "--select dimensions.z" should print only the height
+
+  Scenario: Unknown field
+    When I run "gostl analyze part.stl --select sparkle"
+    Then the command should fail
+    And the error should list the available fields
+
+  Scenario: Mass and cost for a material
+    When I run "gostl analyze part.stl --material petg --infill 20"
+    Then the output should include Material "PETG", Infill "20.00 %", Mass in g and Material Cost in $
+    And "--select mass" should print only the estimated mass
+    And without --material and --infill these fields should not be printed
+
+  Scenario: Mass properties for dynamics simulations
+    When I run "gostl analyze part.stl --mass-properties --material petg"
+    Then the output should include the density in g/cm³ and the mass in g
+    And the center of mass in mm in the file's coordinates
+    And the inertia tensor about the center of mass in g·mm² (inertia.xx ... inertia.yz)
+    And three principal moments, smallest first, with their unit principal axes
+    And the radius of gyration in mm about each principal axis
+    And without --material the density of PLA should be used
+
+  Scenario: Mass properties of a box
+    Given "box.stl" is a closed 40 × 20 × 10 mm box
+    When I run "gostl analyze box.stl --mass-properties --select inertia.xx"
+    Then the value should equal mass × (20² + 10²) / 12
+
+  Scenario: Invalid material or infill
+    When I run "gostl analyze part.stl --material unobtainium"
+    Then the command should fail and list the known materials
+    When I run "gostl analyze part.stl --infill 120"
+    Then the command should fail with a usage error
+
+  Scenario: Assert model conditions in CI
+    When I run "gostl assert part.stl --max-x 200 --watertight --min-wall 1.2 --max-triangles 500000"
+    Then one PASS or FAIL line should be printed per condition with the measured and expected value
+    And the exit status should be 0 if all conditions pass
+    And the exit status should be 1 if any condition fails
+
+  Scenario Outline: Assertion conditions
+    When I run "gostl assert part.stl <option>"
+    Then the "<check>" check should compare <measured>
+
+    Examples:
+      | option                | check         | measured                                                |
+      | --max-x 200           | max-x         | the bounding box size along X with 200 mm               |
+      | --min-z 5             | min-z         | the bounding box size along Z with 5 mm                 |
+      | --max-triangles 50000 | max-triangles | the triangle count with 50000                           |
+      | --max-volume 100000   | max-volume    | the volume with 100000 mm³                              |
+      | --watertight          | watertight    | the number of open and non-manifold edges with zero     |
+      | --min-wall 1.2        | min-wall      | the thinnest wall (rays cast inward from each triangle) |
+      | --stable              | stable        | the center of mass with the support polygon             |
+      | --min-tilt 15         | min-tilt      | the tilt before tipping over with 15°                   |
+
+  Scenario: Machine-readable failure list
+    When I run "gostl assert part.stl --max-x 200 --format json --quiet"
+    Then a JSON report with the file, overall result and the failed checks should be printed
+    And each check should include its name, actual value, expected value and details
+
+  Scenario: Assert without conditions
+    When I run "gostl assert part.stl"
+    Then the command should fail with a usage error
+
+  Scenario: Open the viewer
+    When I run "gostl part.stl"
+    Then the viewer should open the model
+
+  Scenario Outline: Open with the best available viewer
+    Given <environment>
+    When I run "gostl open part.stl"
+    Then <result>
+
+    Examples:
+      | environment                          | result                                                 |
+      | a local graphical session            | the model should open in the GoSTL window              |
+      | an SSH session                       | a text summary of the model should be printed          |
+      | no window server                     | a text summary of the model should be printed          |
+
+  Scenario: Force a viewer
+    When I run "gostl open part.stl --viewer text"
+    Then a text summary of the model should be printed
+
+  Scenario Outline: Shell completion scripts
+    When I run "gostl completion <shell>"
+    Then a <shell> completion script should be printed
+    And it should complete subcommands and their options
+    And it should complete model file arguments with .stl, .3mf and plugin extensions
+    And it should complete "--select" with the analysis field names
+
+    Examples:
+      | shell |
+      | bash  |
+      | zsh   |
+      | fish  |
+
+  Scenario: Unsupported shell
+    When I run "gostl completion powershell"
+    Then the command should fail listing the supported shells
+
+  Scenario: List metrics
+    When I run "gostl explain"
+    Then every analysis metric should be listed with its unit and summary
+
+  Scenario Outline: Explain a metric
+    When I run "gostl explain <term>"
+    Then the description of the "<metric>" metric should be printed
+    And it should show the unit, how the value is computed and related metrics
+
+    Examples:
+      | term       | metric             |
+      | watertight | watertight         |
+      | volume     | volume             |
+      | min-wall   | wall_thickness.min |
+      | --max-z    | dimensions.z       |
+
+  Scenario: Unknown metric
+    When I run "gostl explain sparkle"
+    Then the command should fail with "Unknown metric"
+
+  Scenario: Metric descriptions in reports
+    Given a model is loaded
+    When I export the inspection report
+    Then the MODEL section should label each statistic with the same name and unit as "gostl analyze"
+
+  Scenario: Analyze an OpenSCAD file
+    Given OpenSCAD is installed
+    When I run "gostl analyze part.scad"
+    Then the file should be rendered through OpenSCAD
+    And the analysis of the rendered mesh should be printed
+
+  Scenario: Watch a model while editing
+    Given OpenSCAD is installed
+    When I run "gostl analyze --watch part.scad"
+    Then the full analysis should be printed once
+    When I save "part.scad" with a wider base
+    Then a timestamped block should list only the changed metrics
+    And each line should show the old and new value with the absolute and relative change
+    When I save "part.scad" without geometric changes
+    Then the block should read "no changes"
+
+  Scenario: Watch follows OpenSCAD includes
+    Given "part.scad" includes "lib/threads.scad"
+    And "gostl analyze --watch part.scad" is running
+    When I save "lib/threads.scad"
+    Then the analysis should run again
+
+  Scenario: Render error while watching
+    Given "gostl analyze --watch part.scad" is running
+    When I save "part.scad" with a syntax error
+    Then the OpenSCAD error should be printed
+    And watching should continue
+    And the next successful render should be compared with the last successful one
+
+  Scenario: Watch takes a single model
+    When I run "gostl analyze --watch a.scad b.scad"
+    Then the command should fail with a usage error
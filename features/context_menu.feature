@@ -0,0 +1,66 @@
+@measurement @context-menu
+Feature: Context Menu
+  As a user
+  I want a right-click menu on measurements, points and the model
+  So that I can reach the relevant actions without memorizing keys
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+
+  Scenario: Measurement label menu
+    Given I have created a distance measurement
+    When I right-click its label
+    Then I should see "Copy Value", "Add Note…" and "Select"
+    And I should see "Set Orbit Target"
+    And I should see "Show More Decimals" and "Show Fewer Decimals"
+    And I should see "Delete Measurement"
+
+  Scenario: Copy a measurement value
+    When I choose "Copy Value" from the label menu of a 25 mm distance
+    Then "25.0000" should be on the clipboard
+    And a radius measurement should copy the diameter while diameters are shown
+
+  Scenario: Add a note
+    When I choose "Add Note…" from the label menu
+    Then a dialog should ask for the note
+    When I enter "Bore spacing" and confirm
+    Then the label tooltip should show the note
+
+  Scenario: Delete a measurement
+    Given measurements 1, 2 and 3 are selected
+    When I choose "Delete Measurement" from the label menu of measurement 2
+    Then only measurement 2 should be removed
+    And measurements 1 and 3 should stay selected
+
+  Scenario: Measurement point menu
+    When I right-click within 12 pixels of a measurement endpoint
+    Then I should see "Copy Coordinates"
+    And I should see "Start Distance Here" and "Start Radius Here"
+    And I should see "Set Orbit Target"
+    And I should see "Delete Measurement" for the measurement the point belongs to
+
+  Scenario: Model surface menu
+    When I right-click the model surface
+    Then I should see "Copy Coordinates" with the picked point in file coordinates
+    And I should see "Start Distance Here", "Start Angle Here" and "Start Radius Here"
+    And I should see "Set Orbit Target"
+
+  Scenario: Start a measurement from the menu
+    When I choose "Start Radius Here" from the surface menu
+    Then a radius measurement should start
+    And the picked point should be its first point
+
+  Scenario: Constraints while measuring a distance
+    Given I am measuring a distance and have picked the first point
+    When I right-click the model surface
+    Then I should see "Constrain to X Axis", "Constrain to Y Axis" and "Constrain to Z Axis"
+    And I should see "Constrain Along Surface Normal"
+    And the active constraint should be checked
+    When I right-click a measurement point
+    Then I should see "Constrain Toward Point"
+
+  Scenario: Ray casting diagnostics
+    When I Option-right-click the model
+    Then no menu should appear
+    And ray casting diagnostics should be printed to the console
@@ -0,0 +1,50 @@
+@visualization @contours
+Feature: Contour Lines
+  As a user checking draft, warp or the shape of organic surfaces
+  I want iso-height lines on the model like a topographic map
+  So that I can read heights and slopes at a glance
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Show contour lines
+    When I select View > Contour Lines
+    Then lines should be drawn where the surface crosses horizontal planes
+    And the planes should be multiples of the interval in file coordinates
+    And the lines should run from blue at the lowest level over green to red at the highest
+    And every fifth line should be drawn lighter as an index contour
+
+  Scenario: Automatic interval
+    Given the contour interval is "Auto"
+    And the model is 48 mm high
+    When the contour lines are shown
+    Then the interval should be 2 mm
+    And the menu panel should read "every 2 mm"
+
+  Scenario Outline: Choose an interval
+    When I select View > Contour Interval > "<interval>"
+    Then the contour lines should be shown every <interval>
+
+    Examples:
+      | interval |
+      | 0.1 mm   |
+      | 0.5 mm   |
+      | 1 mm     |
+      | 10 mm    |
+
+  Scenario: Contours follow model changes
+    Given contour lines are shown
+    When I level the model or reload the file
+    Then the contour lines should be recomputed in a background job
+
+  Scenario: Contour lines of a horizontal face
+    Given a face lies exactly at a contour level
+    Then the line should run along the face's edge instead of across the face
+
+  @edge-case
+  Scenario: Very fine interval on a dense mesh
+    Given a model with several million triangles
+    When I select a contour interval of 0.1 mm
+    Then at most 2,000,000 line segments should be drawn
+    And the menu panel should mark the lines as "partial"
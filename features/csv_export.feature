@@ -0,0 +1,49 @@
+@csv @export
+Feature: CSV Delimiter and Decimal Separator
+  As a user with a European spreadsheet locale
+  I want CSV exports with semicolons and decimal commas
+  So that Excel opens them without splitting numbers or turning them into dates
+
+  Background:
+    Given the application is running
+
+  Scenario: Standard CSV by default
+    When I run "gostl analyze part.stl --format csv"
+    Then fields should be separated by commas
+    And numbers should use a decimal point
+
+  Scenario Outline: Choose the delimiter and decimal separator
+    When I run "gostl analyze part.stl --format csv --delimiter <delimiter> --decimal-separator <separator>"
+    Then the volume should be written as <volume> between <between>
+
+    Examples:
+      | delimiter | separator | volume  | between    |
+      | comma     | point     | 1000.25 | commas     |
+      | semicolon | comma     | 1000,25 | semicolons |
+      | tab       | comma     | 1000,25 | tabs       |
+
+  Scenario: The options apply to every CSV export of the command line
+    Then "gostl compare" with --csv should accept --delimiter and --decimal-separator
+    And "gostl heightmap" with --csv should accept --delimiter and --decimal-separator
+
+  Scenario: Fields containing the delimiter are quoted
+    When I export CSV with semicolons for a file named "part; v2.stl"
+    Then the file name should be written as "\"part; v2.stl\""
+    And a decimal comma with a comma delimiter should be quoted so the columns stay intact
+
+  Scenario: CSV format for copied tables
+    When I open Settings > Display
+    Then I should be able to choose the CSV delimiter (comma, semicolon, tab)
+    And I should be able to choose the CSV decimal separator (point, comma)
+    And "Copy CSV" in the measurement comparison should use the chosen format
+
+  Scenario: Match the system locale
+    Given the system region uses a decimal comma
+    When I click "Match System" in Settings > Display
+    Then the CSV delimiter should be set to semicolon
+    And the CSV decimal separator should be set to comma
+
+  Scenario: CSV format is remembered
+    Given I chose semicolons and decimal commas
+    When I restart the application
+    Then copied CSV should still use semicolons and decimal commas
@@ -0,0 +1,52 @@
+@integration @cli
+Feature: Desktop Integration
+  As a user who previews models from Finder, chat and issue trackers
+  I want GoSTL registered as the default viewer and link handler
+  So that double-clicking a file or a gostl:// link opens the right view
+
+  Scenario: Install the integration
+    Given GoSTL.app is installed next to the gostl binary
+    When I run "gostl install-integration"
+    Then the app's Info.plist should declare .stl, .3mf and .scad documents and the gostl URL scheme
+    And the app should be registered with Launch Services
+    And GoSTL should be the default viewer for .stl, .3mf and .scad files
+    And GoSTL should handle gostl:// links
+
+  Scenario: Only offer GoSTL under Open With
+    When I run "gostl install-integration --no-set-default"
+    Then the app should be registered with Launch Services
+    And the default viewers should be unchanged
+
+  Scenario: Dry run
+    When I run "gostl install-integration --dry-run"
+    Then the steps should be printed
+    And nothing should be changed
+
+  Scenario: App not found
+    Given no GoSTL.app is installed
+    When I run "gostl install-integration"
+    Then the command should fail asking for --app
+
+  Scenario: Copy a link to the current view
+    Given "case.stl" is open with two measurements
+    When I select "Copy Link to View" from the File menu
+    Then a gostl://open link with the file path, camera and measurements should be on the clipboard
+    And the camera target and measurements should be in file coordinates
+
+  Scenario: Open a link
+    When I open "gostl://open?file=/parts/case.stl&camera=120,20,210,0,0,15"
+    Then "case.stl" should open in a new window
+    And the camera should be 120 mm from (0, 0, 15) at 20° pitch and 210° yaw
+
+  Scenario: Open a link with a measurement export
+    When I open "gostl://open?file=/parts/case.stl&measurements=/parts/rev-a.json"
+    Then the measurements of "rev-a.json" should be shown on the model
+
+  Scenario: Open a link to a file that is already open
+    Given "case.stl" is open in a window
+    When I open a gostl:// link to "case.stl"
+    Then that window should come to the front with the link's view
+
+  Scenario: Malformed link
+    When I open "gostl://open?camera=1,2,3"
+    Then an alert "Cannot Open Link" should explain what is wrong
@@ -0,0 +1,59 @@
+@ui @windows
+Feature: Detached Panels
+  As a user working with two monitors
+  I want to move the data panels out of the 3D view into their own windows
+  So that the model can use the full screen on one monitor while the numbers live on another
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Detach the measurement log
+    When I select View > Detach Panels > Measurement Log
+    Then the measurement log should open in a separate window
+    And it should no longer be shown over the 3D view
+    And new measurements should appear in the separate window
+
+  Scenario: Detach the analysis panels
+    Given Draft Analysis is enabled
+    When I select View > Detach Panels > Analysis Panels
+    Then the draft panel should move into a separate window with the other open analysis and tool panels
+    And the bottom-right corner of the 3D view should stay free
+    And panels opened later should appear in the separate window as well
+
+  Scenario: Detach the OpenSCAD log
+    Given a .scad file with ECHO output is loaded
+    When I select View > Detach Panels > OpenSCAD Log
+    Then all OpenSCAD messages should be listed expanded in a separate window
+    And the window should update when the file is re-rendered
+
+  Scenario: Empty detached window
+    Given the analysis panels are detached
+    When no analysis or tool panel is open
+    Then the window should say that no panels are open
+
+  Scenario: Reattach by closing the window
+    Given the measurement log is detached
+    When I close its window
+    Then the measurement log should be shown over the 3D view again
+    And "Measurement Log" should be unchecked in View > Detach Panels
+
+  Scenario: Closing the log from its window
+    Given the measurement log is detached
+    When I click the close button in the log header
+    Then the window should close
+    And the measurement log should be hidden
+
+  Scenario: Window position is remembered
+    Given I moved the detached measurement log window to a second monitor
+    When I detach the measurement log again later
+    Then the window should open where it was last placed
+
+  Scenario: Panel windows stay separate from tabs
+    When I detach a panel
+    Then its window should not be merged into the tab group of the model windows
+
+  Scenario: Panel windows close with their model window
+    Given panels of a model window are detached
+    When I close the model window
+    Then its detached panel windows should close as well
@@ -0,0 +1,90 @@
+@measurement @settings
+Feature: Display Precision
+  As a user
+  I want to choose how many decimal places values are shown with
+  So that ±0.05 mm features are readable and meter-scale scans are not noisy
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+
+  Scenario: Default precision
+    When I measure a distance of 25.04567 mm
+    Then the label should show "25.05"
+    And an angle of 90.123 degrees should show "90.1°"
+
+  Scenario: Change the length decimals
+    Given I open Settings > Display
+    When I choose 3 decimals for lengths
+    Then the label of a 25.04567 mm distance should show "25.046"
+    And the selection panel coordinates should use 3 decimals
+    And reference measurement values should use 3 decimals
+    And the setting should be saved in ~/.config/gostl/settings.json
+
+  Scenario: Change the angle decimals
+    Given I open Settings > Display
+    When I choose 2 decimals for angles
+    Then an angle of 90.123 degrees should show "90.12°"
+
+  Scenario: Decimals are limited
+    Then the settings should offer 0 to 6 decimal places
+    And out-of-range values in the settings file should be clamped
+
+  Scenario: More decimals for one measurement
+    Given lengths are shown with 2 decimals
+    When I right-click the label of a 25.04567 mm distance
+    And I choose "Show More Decimals"
+    Then that label should show "25.046"
+    And other labels should keep 2 decimals
+    And the measurement log and screen reader should use the same decimals
+
+  Scenario: Fewer decimals for one measurement
+    When I choose "Show Fewer Decimals" from the label menu twice
+    Then that label should show 2 fewer decimals than before, but not less than 0
+
+  Scenario: Back to the setting
+    Given a measurement has its own decimal places
+    When I right-click its label
+    Then I should see "Default Decimals"
+    When I choose "Default Decimals"
+    Then the label should use the decimals of the setting again
+    And "Default Decimals" should not be offered for measurements without an override
+
+  Scenario: Overrides are saved
+    Given a measurement shows 4 decimals
+    When I save and reload the measurement set
+    Then the measurement should still show 4 decimals
+
+  Scenario: Inspection report precision
+    Given lengths are shown with 1 decimal
+    When I export the inspection report
+    Then coordinates, properties and reference measurement values should use 1 decimal
+
+  Scenario: Micrometers for micro-scale models
+    Given Units is "Automatic" in Settings > Display
+    When I open a model that is 0.08 mm tall
+    Then a 0.0427 mm distance should show "42.70 µm"
+    And the screen reader should announce "Distance 42.70 micrometers"
+
+  Scenario: Meters for architectural models
+    Given Units is "Automatic" in Settings > Display
+    When I open a model that is 25 m long
+    Then a 12500 mm distance should show "12.50 m"
+    And reference measurement values and the height legend should use meters
+
+  Scenario: Engineering notation beyond the unit range
+    Given Units is "Automatic" in Settings > Display
+    When I open a model smaller than 1 µm or larger than 10 km
+    Then lengths should show as mantissa and a power of ten in steps of three, e.g. "420.00e-3 µm"
+
+  Scenario: Fixed unit
+    When I choose "Millimeters (mm)" as Units in Settings > Display
+    Then lengths should always show in millimeters regardless of the model size
+    When I choose "Engineering Notation (mm)"
+    Then a 12346 mm distance should show "12.35e3"
+
+  Scenario: Report unit
+    Given lengths are shown in meters
+    When I export the inspection report
+    Then its header should state "Lengths: m"
+    And coordinates should be in meters while normals and directions stay unit vectors
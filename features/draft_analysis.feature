@@ -0,0 +1,70 @@
+@analysis @draft
+Feature: Draft Analysis
+  As a user preparing a model for a silicone mold, casting or injection molding
+  I want to see which faces have too little draft for the pull direction
+  So that the part releases from the mold without sticking
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Color faces by draft angle
+    When I select Tools > Draft Analysis
+    Then faces with at least the minimum draft relative to +Z should be green
+    And faces with less than the minimum draft should run from red (vertical walls) to yellow
+    And faces turned away from the pull direction should be blue
+    And the DRAFT panel should show the area of each group and its share of the surface
+
+  Scenario: Choose the pull direction
+    Given the draft analysis is shown
+    When I select Tools > Pull Direction > "−Z"
+    Then the faces should be recolored for a mold pulled off downwards
+
+  Scenario Outline: Choose the minimum draft
+    When I select Tools > Minimum Draft > "<angle>"
+    Then faces with less than <angle> of draft should be reported
+
+    Examples:
+      | angle |
+      | 0.5°  |
+      | 1°    |
+      | 5°    |
+
+  Scenario: Tapered box
+    Given a box whose side walls lean 3° inwards towards the top
+    And the pull direction is +Z
+    When the minimum draft is 2°
+    Then the side walls should be green
+    When the minimum draft is 5°
+    Then the side walls should be reported as below the minimum draft
+
+  Scenario: Straight walls
+    Given a box with vertical side walls
+    Then the side walls should be reported as below the minimum draft
+    And the bottom face should be on the opposite side
+
+  Scenario: Inspect the faces below the minimum draft
+    Given the draft analysis reports faces below the minimum draft
+    When I click "Select Faces Below Minimum"
+    Then those triangles should be selected
+
+  Scenario: Draft follows model changes
+    Given the draft analysis is shown
+    When I lay the model flat or reload the file
+    Then the draft angles should be recomputed in a background job
+
+  Scenario: Hide the draft analysis
+    Given the draft analysis is shown
+    When I close the DRAFT panel
+    Then the model should be shown in its own colors again
+
+  @cli
+  Scenario: Check draft from the command line
+    When I run "gostl draft part.stl --pull z --min 1"
+    Then the area below 1° of draft and the area on the opposite side should be printed
+    And the exit status should be 1 if any face is below the minimum draft
+
+  @cli
+  Scenario: Fail on undercuts for a one-part mold
+    When I run "gostl draft part.stl --no-undercuts"
+    Then the exit status should be 1 if any face is turned away from the pull direction
@@ -0,0 +1,40 @@
+@measurement @face_distance
+Feature: Face Distance
+  As a user checking plate thicknesses and slot widths
+  I want to click one flat face and get the distance to the parallel face opposite it
+  So that the most common dimension does not depend on placing two points by hand
+
+  Background:
+    Given the application is running
+    And a model is loaded
+    And "Face Distance" is enabled in the Tools menu
+
+  Scenario: Measure the thickness of a plate
+    When I click the top face of a 2.5 mm plate
+    Then the Face Distance panel should show "2.50 mm" and "thickness"
+    And it should be kept as a distance measurement noted "Thickness"
+
+  Scenario: The distance is taken between the face planes
+    When I click the top face of a plate at an oblique angle
+    Then the measurement should run along the face normal
+    And it should not depend on where on the face I clicked
+
+  Scenario: Measure the width of a slot
+    Given the side is set to "Gap" in the Face Distance panel
+    When I click an inner wall of a 4 mm slot
+    Then the panel should show "4.00 mm" and "gap"
+    And it should be kept as a distance measurement noted "Gap"
+
+  Scenario: Nearest side
+    Given the side is set to "Nearest"
+    When I click an inner wall of a 4 mm slot in a 10 mm thick block
+    Then the 4 mm slot width should be measured
+
+  Scenario: No parallel face opposite
+    When I click a face whose opposite side is slanted by more than 1°
+    Then no measurement should be added
+    And the log should report "No parallel face found"
+
+  Scenario: End the tool
+    When I press Escape
+    Then clicks should pick measurement points again
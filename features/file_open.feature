@@ -22,6 +22,33 @@ Feature: Open 3D Model Files
     And the file should be added to recent files
     And the model information panel should show the triangle count
 
+  @stl @compressed
+  Scenario Outline: Open compressed STL file
+    When I open "scan.stl.<extension>"
+    Then the file should be decompressed in memory without writing a temporary STL
+    And the model should be displayed with the name "scan"
+    And the info panel should show the format as "STL (<extension> compressed)"
+    And auto-reload should watch "scan.stl.<extension>"
+
+    Examples:
+      | extension |
+      | gz        |
+      | zst       |
+
+  @stl @compressed
+  Scenario: Save a compressed STL
+    Given I have opened and leveled "scan.stl.gz"
+    When I choose File > Save
+    Then "scan.stl.gz" should be written gzip compressed
+    And File > Save As... should suggest "scan.stl.gz"
+    And saving as "scan.stl" should write an uncompressed file
+
+  @stl @compressed
+  Scenario: zstd is not installed
+    Given the zstd command-line tool is not installed
+    When I open "scan.stl.zst"
+    Then an error should say that zstd is required and can be installed with "brew install zstd"
+
   @3mf
   Scenario: Open 3MF file
     When I open a 3MF file
@@ -121,6 +148,7 @@ Feature: Open 3D Model Files
       | .stl      |
       | .3mf      |
       | .scad     |
+      | .py       |
       | .yaml     |
       | .yml      |
 
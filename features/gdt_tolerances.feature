@@ -0,0 +1,64 @@
+@measurement @reference-geometry @tolerances
+Feature: GD&T Tolerance Checks
+  As a user doing a first-article inspection on a scan
+  I want to evaluate geometric tolerances against picked features
+  So that I can see pass/fail results without leaving the viewer
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+    And the reference geometry panel is open
+
+  Scenario: Check flatness of a face
+    Given I have selected the triangles of a face with the triangle tool
+    When I choose the ⏥ characteristic in the "TOLERANCES" section
+    And I enter a tolerance of 0.05 mm
+    And I click "Add Check"
+    Then a flatness check should be listed with the measured peak-to-valley deviation
+    And it should show a green PASS badge when the deviation is within 0.05 mm
+    And it should show a red FAIL badge otherwise
+    And the triangle selection should be cleared
+
+  Scenario Outline: Check orientation against a datum
+    Given "Plane 1" exists as a datum
+    And I have selected the triangles of a face
+    When I choose the <symbol> characteristic
+    And I choose "Plane 1" as the datum
+    And I click "Add Check"
+    Then the check should report the width of the zone <zone> that contains the face
+
+    Examples:
+      | symbol | zone                                  |
+      | ∥      | parallel to "Plane 1"                 |
+      | ⊥      | perpendicular to "Plane 1"            |
+
+  Scenario: Orientation checks require a datum
+    When I choose the ∥ characteristic
+    And no datum is chosen
+    Then the "Add Check" button should be disabled
+
+  Scenario: Check the position of a hole
+    Given "Axis 1" was fitted to a bore
+    When I choose the ⌖ characteristic
+    And I choose "Axis 1" as the feature
+    And I enter the nominal location in file coordinates
+    And I enter a tolerance of 0.2 mm
+    And I click "Add Check"
+    Then the check should report the diameter of the zone around the nominal location that contains the axis
+
+  Scenario: Checks follow their datums
+    Given a parallelism check relative to "Plane 1" exists
+    When I delete "Plane 1"
+    Then the check should be removed
+
+  Scenario: Export an inspection report
+    Given tolerance checks exist
+    When I select File > Export Inspection Report...
+    And I choose a file name
+    Then a text report should be written with the reference geometry, reference measurements and tolerance checks
+    And it should end with a summary of how many checks passed and the overall result
+
+  Scenario: Copy the inspection report
+    Given tolerance checks exist
+    When I click "Copy Report" in the "TOLERANCES" section
+    Then the inspection report should be copied to the clipboard
@@ -0,0 +1,53 @@
+@camera @navigation
+Feature: Go To Location
+  As a user
+  I want to jump to a coordinate, vertex, triangle or named anchor
+  So that I can find a specific datum on a large model quickly
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+
+  Scenario: Open the go-to palette
+    When I select View > Go to... or press Cmd+Shift+G
+    Then a palette should appear at the top of the window
+    And the text field should have keyboard focus
+    And the available anchors should be listed
+
+  Scenario Outline: Navigate to a location
+    Given the go-to palette is open
+    When I type "<input>" and press Return
+    Then the camera target should move to <target>
+    And the camera angles and distance should be preserved
+    And a temporary crosshair marker should appear at the target for 4 seconds
+    And the palette should close
+
+    Examples:
+      | input     | target                                           |
+      | 10, 20, 5 | world coordinates X=10, Y=20, Z=5                |
+      | 10 20 5   | world coordinates X=10, Y=20, Z=5                |
+      | v42       | vertex 42 (STL order: triangle index × 3 + corner) |
+      | t7        | the centroid of triangle 7                       |
+      | center    | the first anchor whose name contains "center"    |
+
+  Scenario: Search named anchors
+    Given the go-to palette is open
+    When I type part of an anchor name
+    Then matching anchors should be listed, prefix matches first
+    And clicking an anchor should navigate to it
+
+  Scenario: Available anchors
+    Then the anchors should include "Origin"
+    And "Model center", "Bounding box min" and "Bounding box max"
+    And one entry per measurement (e.g. "Distance #1: 12.34") at its label position
+
+  Scenario: Unknown location
+    Given the go-to palette is open
+    When I enter a vertex or triangle index that does not exist
+    Then "Nothing found" should be shown
+    And the palette should stay open
+
+  Scenario: Close the palette
+    Given the go-to palette is open
+    When I press Escape
+    Then the palette should close without moving the camera
@@ -0,0 +1,57 @@
+@measurement @grid-snap
+Feature: Grid Snapping
+  As a user checking a part against nominal dimensions
+  I want to create measurement points on exact grid positions
+  So that I can measure a feature's offset from a grid plane
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+    And a measurement mode is active
+
+  Scenario: Toggle grid snapping
+    When I press S or select Tools > Snap to Grid
+    Then grid snapping should be enabled
+    And the measurement overlay should show "Grid snap" with the grid spacing
+    When I press S again
+    Then grid snapping should be disabled
+
+  Scenario: Temporarily invert grid snapping with Control
+    Given grid snapping is disabled
+    When I hold Control while hovering the model
+    Then the hover point should snap to the grid
+    When I release Control
+    Then the hover point should snap to mesh vertices again
+
+  Scenario: Snap to the displayed grid spacing
+    Given the grid spacing is 10 mm
+    And grid snapping is enabled
+    When I hover or click a point on the model surface
+    Then the point should move to the nearest grid intersection (multiples of 10 mm on X and Y)
+    And the point should keep the height of the surface
+    And the point should be marked as an air point
+
+  Scenario: Grid spacing follows the grid mode
+    Given grid snapping is enabled
+    When I switch to the 1mm grid
+    Then points should snap to 1 mm multiples
+    When I turn the grid off
+    Then clicking the background should not create a point
+
+  Scenario: Create points on the grid floor
+    Given grid snapping is enabled
+    When I click on the background where the view ray hits the displayed bottom grid
+    Then a point should be created on the grid plane at the nearest grid intersection
+    And clicking beyond the edge of the grid should not create a point
+
+  Scenario: Measure offset from a nominal grid plane
+    Given distance measurement mode is active
+    When I pick a vertex of a feature without grid snapping
+    And I constrain the measurement to the X axis
+    And I pick a second point with grid snapping
+    Then the distance should be the feature's offset from the nearest X grid plane
+
+  Scenario: Grid snapping with recentered models
+    Given a model with large coordinates was recentered
+    When I snap a point to the grid
+    Then the point should land on round values in the original coordinates, not the recentered ones
@@ -0,0 +1,49 @@
+@rendering @analysis
+Feature: Color by Height
+  As a user checking printed or machined parts
+  I want faces colored by their height with a legend
+  So that warping and tilt of nominally flat parts are visible at a glance
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Color the model by height
+    When I select View > Color by Height
+    Then the model should be colored by the Z coordinate of each vertex in a smooth gradient
+    And the lowest faces should be blue and the highest red
+    And the HEIGHT legend should show the gradient with the highest, middle and lowest Z in file coordinates
+    And the legend should show the span between the lowest and highest Z
+
+  Scenario: Warping of a flat plate
+    Given a 100 × 100 × 2 mm plate whose corners are 0.3 mm higher than its center
+    When I turn on Color by Height
+    Then the gradient should span only the 2.3 mm height of the plate
+    And the raised corners should be visibly red against the blue-green center
+
+  Scenario Outline: Choose the axis
+    When I select View > Height Axis > <axis>
+    Then the faces should be colored by their <axis> coordinate
+    And the legend should show the <axis> range
+
+    Examples:
+      | axis |
+      | X    |
+      | Y    |
+      | Z    |
+
+  Scenario: Only one coloring at a time
+    Given Color by Height is on
+    When I turn on Tools > Draft Analysis
+    Then the height colors should be turned off
+    When I turn on Color by Height again
+    Then the draft analysis should be turned off
+
+  Scenario: Colors follow reloads
+    Given Color by Height is on
+    When the model is reloaded after a change
+    Then the faces should be recolored for the new geometry
+
+  Scenario: Hide the height colors
+    When I click the close button of the HEIGHT legend
+    Then the model should be shown in its own colors again
@@ -0,0 +1,44 @@
+@export @cli
+Feature: Height Map Export
+  As a user comparing parts with CNC probing data or checking flatness
+  I want to export a height map of a model's top surface
+  So that I can compare heights point by point or see unevenness at a glance
+
+  Scenario: Export a height map
+    When I run "gostl heightmap plate.stl --axis z --resolution 0.1 --out depth.png"
+    Then "depth.png" should be a 16-bit grayscale image with one pixel per 0.1 mm cell
+    And each pixel should hold the highest surface seen from above at the cell center
+    And the lowest height should be dark gray and the highest white
+    And pixels without surface should be black
+    And the image size, the height range and its span should be printed
+
+  Scenario: Height matrix as CSV
+    When I run "gostl heightmap plate.stl --resolution 0.5 --csv depth.csv"
+    Then "depth.csv" should contain one line per image row, top row first
+    And each cell should hold the height in mm with four decimals
+    And cells without surface should be empty
+
+  Scenario Outline: View axis
+    When I run "gostl heightmap part.stl --axis <axis>"
+    Then the surface should be seen from the positive <axis> side
+    And the image should show <right> to the right and <up> upwards
+
+    Examples:
+      | axis | right | up |
+      | z    | +X    | +Y |
+      | y    | -X    | +Z |
+      | x    | +Y    | +Z |
+
+  Scenario: Default output file
+    When I run "gostl heightmap plate.stl"
+    Then "plate-heightmap.png" should be written next to the model
+
+  @edge-case
+  Scenario: Too fine a resolution
+    When I run "gostl heightmap large.stl --resolution 0.001"
+    Then the command should fail asking for a coarser resolution
+
+  @edge-case
+  Scenario: Invalid resolution
+    When I run "gostl heightmap plate.stl --resolution 0"
+    Then the command should fail with a usage error
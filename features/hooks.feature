@@ -0,0 +1,52 @@
+@automation @hooks
+Feature: Event Hooks
+  As a user integrating gostl with other systems
+  I want hooks that fire on application events
+  So that measured dimensions reach our QA database automatically
+
+  Background:
+    Given the application is running
+    And hooks are configured in ~/.config/gostl/hooks.json
+
+  Scenario Outline: Hooks fire on events
+    Given a hook for the "<event>" event
+    When <action>
+    Then the hook should receive a JSON payload with "event" set to "<event>"
+    And the payload should contain the file path, a timestamp and <data>
+
+    Examples:
+      | event             | action                                   | data                                              |
+      | modelLoaded       | I open a model                           | the triangle count, volume and dimensions         |
+      | modelReloaded     | the model file changes on disk           | the triangle count, volume and dimensions         |
+      | measurementAdded  | I complete a distance measurement        | the type, value and points in file coordinates    |
+      | analysisCompleted | a plugin analyzer finishes               | the analyzer title and its results                |
+      | analysisCompleted | I add a tolerance check                  | the deviation, tolerance and pass/fail result     |
+      | analysisCompleted | I run a script from the viewer           | the script results, failures and pass/fail result |
+
+  Scenario: Shell command hooks
+    Given the hook { "event": "measurementAdded", "command": "cat >> ~/measurements.jsonl" }
+    When I complete a measurement
+    Then the command should run through /bin/sh with the payload on stdin
+    And GOSTL_EVENT should be set to "measurementAdded"
+
+  Scenario: Webhooks
+    Given the hook { "event": "*", "url": "https://qa.example.com/gostl" }
+    When any event occurs
+    Then the payload should be sent as an HTTP POST with Content-Type application/json
+    And the X-GoSTL-Event header should name the event
+    And configured headers should be added to the request
+
+  Scenario: Hooks do not block the viewer
+    Given a hook command that takes several seconds
+    When the event fires
+    Then the viewer should stay responsive
+
+  Scenario: Edit hooks while running
+    Given the application is running
+    When I change hooks.json
+    Then the next event should use the updated hooks
+
+  Scenario: Review hooks in settings
+    When I open Settings > Automation
+    Then the configured hooks should be listed with their events
+    And "Open hooks.json" should open the file, creating it if missing
@@ -0,0 +1,45 @@
+@visualization @hover_highlight
+Feature: Hover Highlight
+  As a user fitting planes and measuring between faces
+  I want the whole surface under the cursor to light up
+  So that I know which face a plane fit or measurement will reference
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Off by default
+    When I open View > Highlight Under Cursor
+    Then "Off" should be checked
+    And hovering the model should not highlight anything
+
+  Scenario: Highlight a flat face
+    Given View > Highlight Under Cursor is set to "Flat Face"
+    When I hover over the top face of a box
+    Then all connected triangles in the plane of the hovered triangle should be highlighted
+    And the info bar should show "Flat Face" with the face area in mm² and its normal
+
+  Scenario: Flat faces stop at shallow folds
+    Given View > Highlight Under Cursor is set to "Flat Face"
+    And a neighboring face meets the hovered face at a 1° fold
+    When I hover over the face
+    Then the neighboring face should not be highlighted
+    And with "Smooth Region" both faces should be highlighted
+
+  Scenario: Highlight a smooth region
+    Given View > Highlight Under Cursor is set to "Smooth Region"
+    When I hover over the side of a cylinder
+    Then the whole curved side should be highlighted
+    And the flat caps should not be highlighted
+    And the info bar should show the area and the area-weighted normal of the region
+
+  Scenario: The region is kept while the cursor stays on it
+    Given View > Highlight Under Cursor is set to "Flat Face"
+    When I move the cursor within the highlighted face
+    Then the region should not be recomputed
+
+  Scenario: Leaving the model clears the highlight
+    Given a face is highlighted
+    When I move the cursor off the model
+    Then nothing should be highlighted
+    And the info bar should disappear
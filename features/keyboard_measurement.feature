@@ -0,0 +1,85 @@
+@measurement @keyboard @accessibility
+Feature: Keyboard-Only Measurement and Accessible Output
+  As a user who cannot rely on precise mouse picking
+  I want to pick measurement points with the keyboard and hear the results
+  So that I can measure models without a mouse
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+
+  @navigation
+  Scenario Outline: Orbit the camera with arrow keys
+    When I press <key>
+    Then the camera should rotate by 15 degrees <direction>
+    And any listed snap candidates should be discarded
+
+    Examples:
+      | key   | direction         |
+      | Left  | around the Z axis |
+      | Right | around the Z axis |
+      | Up    | upward (pitch)    |
+      | Down  | downward (pitch)  |
+
+  @snap-candidates
+  Scenario: Collect snap candidates at the view center
+    Given a distance, angle or radius measurement is active
+    And no snap candidates are listed
+    When I press Tab
+    Then a ray should be cast from the camera through the view center
+    And the model vertices near the hit point should become snap candidates (closest first, at most 16)
+    And the surface point itself should be offered as the last candidate
+    And the first candidate should be highlighted as the hover point
+
+  @snap-candidates
+  Scenario: Cycle snap candidates
+    Given snap candidates are listed
+    When I press Tab
+    Then the next candidate should be highlighted
+    When I press Shift+Tab
+    Then the previous candidate should be highlighted
+    And the selection should wrap around at either end
+
+  @snap-candidates
+  Scenario: Confirm a snap candidate
+    Given a snap candidate is highlighted
+    When I press Return or Keypad Enter
+    Then the highlighted point should be added to the current measurement
+    And the snap candidates should be cleared
+
+  @snap-candidates
+  Scenario: Confirm respects axis constraints
+    Given distance measurement mode is active with at least one point
+    And an axis constraint is active
+    When I press Return
+    Then the constrained endpoint should be added as an air point
+
+  @snap-candidates
+  Scenario: Mouse movement replaces keyboard candidates
+    Given snap candidates are listed
+    When I move the mouse over the model
+    Then the snap candidates should be cleared
+    And the hover point should follow the mouse again
+
+  @log
+  Scenario: Measurement log
+    When I select View > Measurement Log or press Cmd+Shift+L
+    Then a log panel should appear in the bottom-left corner
+    And each completed measurement should be listed in plain language (e.g. "Distance 12.34 millimeters")
+    And each highlighted snap candidate should be listed with its index and coordinates
+    And the log can be copied to the clipboard or cleared
+
+  @announcements
+  Scenario: Screen reader announcements
+    Given View > Announce Measurements is enabled
+    When a measurement is completed
+    Then its value should be announced via the macOS accessibility API (VoiceOver)
+    When I cycle snap candidates with Tab
+    Then the highlighted candidate should be announced
+
+  @announcements
+  Scenario: Announcements disabled
+    Given View > Announce Measurements is disabled
+    When a measurement is completed
+    Then nothing should be announced
+    But the measurement should still appear in the measurement log
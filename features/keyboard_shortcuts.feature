@@ -20,6 +20,7 @@ Feature: Keyboard Shortcuts
       | Cmd+S        | the model is saved (if modified) |
       | Cmd+Shift+S  | the save as dialog opens         |
       | Cmd+R        | the current file is reloaded     |
+      | Cmd+,        | the settings window opens        |
 
   @camera
   Scenario Outline: Camera preset shortcuts
@@ -35,8 +36,11 @@ Feature: Keyboard Shortcuts
       | Cmd+5    | top view            |
       | Cmd+6    | bottom view         |
       | Cmd+0    | reset view          |
+      | Cmd+Shift+G | go-to palette opens |
       | 7        | home/isometric view |
       | F        | frame model in view |
+      | Arrows   | orbit camera 15°    |
+      | Shift+Arrows | turn camera to the next 90° step |
 
   @view
   Scenario Outline: View toggle shortcuts
@@ -46,11 +50,19 @@ Feature: Keyboard Shortcuts
     Examples:
       | shortcut     | action                        |
       | Cmd+I        | info panel toggles            |
+      | Cmd+Option+I | metadata panel toggles        |
       | Cmd+W        | wireframe mode cycles         |
       | Cmd+G        | grid mode cycles              |
       | Cmd+B        | build plate cycles            |
       | Cmd+Shift+F  | face orientation mode toggles |
+      | Cmd+Shift+O  | outline mode toggles          |
       | Cmd+Shift+X  | slicing panel toggles         |
+      | Cmd+Shift+Y  | layer preview toggles         |
+      | Cmd+Shift+B  | PCB preset toggles            |
+      | Cmd+Shift+U  | camera cutaway toggles        |
+      | Cmd+Shift+L  | measurement log toggles       |
+      | Cmd+Shift+P  | performance HUD toggles       |
+      | Cmd+Option+P | presentation mode toggles     |
 
   @measurement
   Scenario Outline: Measurement shortcuts
@@ -63,6 +75,11 @@ Feature: Keyboard Shortcuts
       | Cmd+A        | angle measurement mode starts                   |
       | R            | radius measurement mode starts                  |
       | T            | triangle selection mode starts                  |
+      | B            | overall bounding box dimensions are added       |
+      | S            | grid snapping toggles                           |
+      | Cmd+Shift+M  | picking magnifier toggles                       |
+      | + / -        | vertex snap radius grows / shrinks              |
+      | Cmd+Shift+R  | reference geometry panel toggles                |
       | Cmd+M        | material cycles                                 |
       | Cmd+Shift+K  | all measurements are cleared                    |
       | Cmd+Shift+C  | selected/all measurements copied as OpenSCAD    |
@@ -82,6 +99,10 @@ Feature: Keyboard Shortcuts
       | Option    | toggles direction constraint         |
       | Backspace | removes last point or selected items |
       | Delete    | removes last point or selected items |
+      | Tab       | highlights next snap candidate       |
+      | Shift+Tab | highlights previous snap candidate   |
+      | Return    | picks highlighted snap candidate     |
+      | 0-9 . -   | types a length while a constraint is active |
 
   @triangle-select
   Scenario: Paint mode for triangle selection
@@ -0,0 +1,49 @@
+@file-handling @rendering @precision
+Feature: Large Coordinate Recentering
+  As a user opening models exported in world coordinates (e.g. surveying data)
+  I want the model to render without jitter
+  So that I can inspect and measure it while readouts keep the original coordinates
+
+  Background:
+    Given the application is running
+
+  Scenario: Model far from the origin is recentered for rendering
+    When I open an STL file whose coordinates exceed 10000 mm on any axis
+    Then the model should be moved near the origin before it is converted to float
+    And the offset should be the bounding box center rounded to whole millimeters
+    And vertices should not jitter when orbiting or zooming
+
+  Scenario: Models near the origin are left untouched
+    When I open an STL file whose coordinates stay within 10000 mm
+    Then no recentering offset should be applied
+
+  Scenario: Info panel shows original coordinates
+    Given a recentered model is loaded
+    When I open the model info panel
+    Then the center should be shown in the file's original coordinates
+    And a "Recentered by" row should show the applied offset
+
+  Scenario: Readouts use original coordinates
+    Given a recentered model is loaded
+    Then grid labels should show original coordinates
+    And grid lines should stay aligned with round original coordinates
+    And selected measurement point coordinates should show original coordinates
+    And keyboard snap candidate announcements should use original coordinates
+    And go-to anchors should list original coordinates
+    And distances, angles, radii, volume and surface area should be unaffected by the offset
+
+  Scenario: Go to typed coordinates
+    Given a recentered model is loaded
+    When I open the go-to palette and enter coordinates from the original file
+    Then the camera should focus on that location of the model
+
+  Scenario: Exports keep original coordinates
+    Given a recentered model is loaded
+    When I save the model or copy measurements or selected triangles as OpenSCAD
+    Then the exported coordinates should match the original file
+
+  Scenario: Reload keeps the offset
+    Given a recentered model is loaded with measurements
+    When the file changes on disk and is reloaded
+    Then the same offset should be reused
+    And existing measurements should stay on the same vertices
@@ -0,0 +1,67 @@
+@slicing @visualization
+Feature: Layer Preview
+  As a user
+  I want to step through a model one print layer at a time
+  So that I can get a slicer-like preview without leaving GoSTL
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+
+  Scenario: Open layer preview
+    When I press Cmd+Shift+Y
+    Then the layer preview panel should appear at the bottom-right
+    And only the geometry between the bottom of the model and one layer height above it should be visible
+    And the layer should be shown as a filled cross-section
+    And the slicing panel should be hidden
+
+  Scenario: Layer information
+    When the layer preview is active
+    Then the panel should show "Layer <n> / <count>"
+    And the panel should show the Z range of the layer in file coordinates
+
+  Scenario Outline: Step through layers
+    Given the layer preview is active
+    When I <action>
+    Then the preview should move <layers>
+
+    Examples:
+      | action                       | layers              |
+      | press "."                    | one layer up        |
+      | press ","                    | one layer down      |
+      | press Shift+"."              | ten layers up       |
+      | drag the layer slider        | to the chosen layer |
+      | click the skip-to-end button | to the top layer    |
+
+  Scenario: Play animation
+    Given the layer preview is active
+    When I press Space
+    Then the preview should step up through the layers at the selected speed
+    And the animation should stop at the top layer
+    When I press Space at the top layer
+    Then the animation should restart from the bottom layer
+
+  Scenario: Animation speed
+    When I select "25/s" in the layer preview panel
+    Then the animation should advance 25 layers per second
+
+  Scenario: Change layer height
+    Given the preview shows the layer at Z 2.0 mm with 0.2 mm layers
+    When I select a layer height of 0.1 mm
+    Then the layer count should double
+    And the preview should stay at Z 2.0 mm
+
+  Scenario: Show printed layers below
+    When I enable "Below" in the layer preview panel
+    Then all layers up to and including the current one should be visible
+    And the current layer should be capped with a filled cross-section
+
+  Scenario: Reload keeps the layer
+    Given the layer preview is active
+    When the file changes on disk and is reloaded
+    Then the same layer should stay selected if it still exists
+
+  Scenario: Slicing closes the layer preview
+    Given the layer preview is active
+    When I open the slicing panel
+    Then the layer preview should close
@@ -0,0 +1,53 @@
+@measurement @magnifier
+Feature: Picking Magnifier
+  As a user
+  I want an enlarged view around the cursor while picking points
+  So that I can pick the right vertex in dense triangulation without zooming the camera
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+    And "Magnifier while picking" is enabled in Settings > Navigation
+
+  Scenario: Magnifier appears while picking
+    When I start a distance measurement
+    And I move the mouse over the model
+    Then a square inset should appear above and to the right of the cursor
+    And it should show the area around the cursor enlarged 3×
+    And a crosshair should mark the cursor position in the inset
+    And the snap point marker should be visible in the inset
+
+  Scenario: Magnifier follows the cursor
+    Given the magnifier is visible
+    When I move the mouse
+    Then the inset should move with the cursor
+    And the inset should flip to the other side of the cursor near the window edges
+
+  Scenario: Magnifier only while picking
+    When no measurement, leveling or reference geometry tool is collecting points
+    Then the magnifier should not be shown
+    When I drag to rotate the camera
+    Then the magnifier should be hidden until the mouse moves again
+    When the mouse leaves the view
+    Then the magnifier should be hidden
+
+  Scenario Outline: Magnification
+    When I select <zoom> for "Magnification" in Settings > Navigation
+    Then the inset should show the area around the cursor enlarged <zoom>
+
+    Examples:
+      | zoom |
+      | 2×   |
+      | 3×   |
+      | 4×   |
+
+  Scenario: Toggle the magnifier
+    When I press Cmd+Shift+M
+    Then the magnifier should be turned off
+    And the setting should be remembered after a restart
+
+  Scenario: Inset content
+    Given the magnifier is visible
+    Then the inset should show the model, wireframe, cut edges, selected triangles and measurements
+    And screen-space line widths should match the main view
+    And the camera cutaway should apply to the inset
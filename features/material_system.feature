@@ -16,7 +16,7 @@ Feature: Material System
   Scenario: Cycle material with keyboard
     When I press Cmd+M
     Then the material should cycle to the next type
-    And the cycle order should be: PLA -> ABS -> PETG -> TPU -> Nylon -> PLA
+    And the cycle order should be: PLA -> ABS -> PETG -> TPU -> Nylon -> Resin -> Aluminum -> Steel -> PLA
 
   Scenario: PLA material properties
     When I select PLA material
@@ -48,12 +48,41 @@ Feature: Material System
     And the material density should be 1.14 g/cm³
     And the material should have moderate gloss (glossiness 0.4)
 
+  Scenario Outline: Resin and metal material properties
+    When I select <material> material
+    Then the material density should be <density> g/cm³
+    And the material should be <appearance>
+    And no infill should be shown in the info panel
+
+    Examples:
+      | material | density | appearance                 |
+      | Resin    | 1.15    | non-metallic, semi-glossy  |
+      | Aluminum | 2.70    | metallic (metalness 1.0)   |
+      | Steel    | 7.85    | metallic (metalness 1.0)   |
+
   Scenario: Weight calculation
     Given I have a model with known volume
     When a material is selected
-    Then the weight should be calculated using the formula: Volume (mm³) / 1000 × Density (g/cm³)
+    Then the weight should be calculated using the formula: Volume (mm³) / 1000 × Density (g/cm³) × Infill
     And the weight should be displayed in the info panel
 
+  Scenario: Infill for printed materials
+    Given PLA material is selected
+    When I select "20%" from the Tools → Infill menu
+    Then the info panel should show "Infill: 20%"
+    And the weight should be 20% of the solid weight
+    And the infill should be kept when the file is auto-reloaded
+
+  Scenario: Infill does not apply to solid materials
+    Given Aluminum material is selected
+    Then the Infill menu should be disabled
+    And the weight should be calculated at 100%
+
+  Scenario: Material cost
+    When a material is selected
+    Then the info panel should show the cost as Weight (kg) × the material's price per kg
+    And the cost should be formatted with two decimal places (e.g., "$1.23")
+
   Scenario: Weight display formatting - grams
     Given the calculated weight is less than 1 kilogram
     Then the weight should be displayed in grams
@@ -91,6 +91,36 @@ Feature: Distance Measurement
     Then the axis constraint should be released
     And I should return to free movement
 
+  @constraint
+  Scenario: Type an exact length along a constraint
+    Given distance measurement mode is active
+    And an axis constraint is active
+    When I type "25.4"
+    Then the typed length should be shown in the measurement panel
+    And the preview endpoint should be 25.4 mm from the previous point along the constraint
+    When I press Return
+    Then a point should be added 25.4 mm along the constraint as an air point
+    And the constraint should be released
+
+  @constraint
+  Scenario: Direction of a typed length
+    Given distance measurement mode is active
+    And an axis constraint is active
+    When I type a length
+    Then the point should be placed on the side of the constraint the cursor is on
+    And typing "-" should flip the direction
+    And a point constraint should always point toward the constraining point
+
+  @constraint
+  Scenario: Edit a typed length
+    Given I have typed a length along a constraint
+    When I press Backspace
+    Then the last typed character should be removed
+    When I press ESC
+    Then the typed length should be discarded
+    And the preview should follow the cursor again
+    And the digit keys should select camera presets only when no constraint is active
+
   Scenario: Distance label display
     Given I have completed a distance measurement
     Then a label should be displayed at the measurement location
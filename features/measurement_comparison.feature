@@ -0,0 +1,54 @@
+@measurement @comparison
+Feature: Measurement Comparison Across Revisions
+  As a user tracking design revisions
+  I want to compare measurements between model revisions
+  So that I can see which dimensions changed and by how much
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Export measurements
+    Given I have measured two distances and an angle
+    When I select "Export Measurements..." from the File menu
+    Then a JSON file should be written with the measurements in file coordinates
+    And they should be named "Distance 1", "Distance 2" and "Angle 1"
+
+  Scenario: Compare a baseline with the loaded revision
+    Given a measurement export of an earlier revision
+    When I select "Compare Measurements..." from the Tools menu and choose the export
+    Then the baseline points should be re-bound to the closest vertices of the loaded model
+    And the values should be recalculated
+    And the comparison panel should list each measurement with its value change
+
+  Scenario Outline: Change classification
+    Given a baseline distance of 10.0 mm
+    When the re-evaluated distance is <current> mm
+    Then the measurement should be marked "<status>"
+
+    Examples:
+      | current | status    |
+      | 10.0    | unchanged |
+      | 10.05   | changed   |
+      | 10.5    | exceeded  |
+
+  Scenario: Points without a vertex nearby
+    Given a baseline point has no vertex within 0.5 mm on the new revision
+    When I compare the measurements
+    Then the point should keep its position
+    And the measurement should be flagged with a stale point warning
+
+  Scenario: Copy the comparison
+    Given a comparison is shown
+    When I click "Copy CSV"
+    Then the comparison table should be copied as CSV
+
+  Scenario: Compare from the command line
+    When I run "gostl compare rev-a.json rev-b.stl --threshold 0.05"
+    Then a table with baseline, current, delta and status should be printed
+    And the exit status should be 1 if any change exceeds the threshold
+
+  Scenario: Compare two exports
+    When I run "gostl compare rev-a.json rev-b.json --csv"
+    Then measurements should be matched by name
+    And measurements only in one export should be listed as added or removed
@@ -46,6 +46,54 @@ Feature: Measurement Selection and Management
     And the label should be rendered as 2D text over the 3D model
     And the label should use screen-space coordinates
 
+  @labels
+  Scenario: Overlapping labels are displaced
+    Given I have created several measurements close to each other
+    When their labels would overlap
+    Then lower priority labels should move to a free spot around their measurement point
+    And a leader line should connect each moved label to its measurement point
+    And selected measurements should keep their position before larger measurements, then smaller ones
+
+  @labels
+  Scenario: Labels cluster when zoomed out
+    Given I have created many measurements
+    When I zoom out until their measurement points are close together on screen
+    Then nearby labels should be merged into one label showing a "+N" count
+    And the labels should split up again when I zoom back in
+
+  @labels
+  Scenario: Label placement is stable while orbiting
+    Given I have created a dense set of measurements
+    When I rotate the camera
+    Then labels should keep their placement while it stays free
+    And labels should not flicker between hidden and visible
+
+  @labels @tooltip
+  Scenario: Hovering a label shows measurement details
+    Given I have created a distance measurement
+    When I hover over its label
+    Then a tooltip should appear below the label
+    And it should show the distance with full precision
+    And it should show the X, Y and Z deltas between the endpoints
+    And it should show the endpoint coordinates in file coordinates
+    And it should show when the measurement was created
+    When I move the mouse away from the label
+    Then the tooltip should disappear
+
+  @labels @tooltip
+  Scenario: Measurement notes
+    Given I have selected a single measurement
+    When I enter "Bore spacing" in the note field of the info panel and press Return
+    Then the tooltip of its label should show the note "Bore spacing"
+    And the note should be saved with exported measurement sets
+
+  @labels @tooltip
+  Scenario: Tooltip of a merged label
+    Given nearby labels are merged into a label showing "+2"
+    When I hover over the merged label
+    Then the tooltip should show the details of the visible measurement
+    And it should mention the 2 merged measurements
+
   Scenario: Measurement color coding
     Given I have created different types of measurements
     Then each measurement type should have a distinct color
@@ -11,23 +11,54 @@ Feature: Application Menus
     When I open the File menu
     Then I should see "New Tab" with shortcut Cmd+T
     And I should see "Open..." with shortcut Cmd+O
+    And I should see "Open URL..."
     And I should see "Open Recent" as a submenu
     And "Open Recent" should have "Clear Menu" option
+    And I should see "Save As Without STL Header/Attributes..." (disabled unless a model is loaded)
+    And I should see "Export Inspection Report..." (disabled unless a model is loaded)
+    And I should see "Export Measurements..." (disabled unless there are measurements)
+    And I should see "Export Outline as SVG..." (disabled unless a model is loaded)
+    And I should see "Export as OpenSCAD Polyhedron..." (disabled unless a model is loaded)
+    And I should see "Export with Analysis Colors..." (disabled unless draft, primitive or height colors are shown)
+    And I should see "Export View Image..." (disabled unless a model is loaded)
+    And I should see "Copy Link to View" (disabled unless a file is open)
     And I should see "Reload" with shortcut Cmd+R
 
   Scenario: View menu structure
     When I open the View menu
     Then I should see "Info Panel" toggle with Cmd+I
+    And I should see "Metadata" toggle with Cmd+Option+I
     And I should see "Wireframe" submenu with Off/All/Edge options
     And I should see "Cycle Wireframe Mode" with Cmd+W
     And I should see "Face Orientation" toggle with Cmd+Shift+F
+    And I should see "Outline Mode" toggle with Cmd+Shift+O
+    And I should see "Highlight Under Cursor" submenu with Off/Flat Face/Smooth Region options
     And I should see "Grid" submenu with Off/Bottom/All Sides/1mm Grid options
     And I should see "Cycle Grid Mode" with Cmd+G
     And I should see "Build Plate" submenu with printer options
     And I should see "Cycle Build Plate" with Cmd+B
     And I should see "Slicing" toggle with Cmd+Shift+S
+    And I should see "Layer Preview" toggle with Cmd+Shift+Y
+    And I should see "Section Bookmarks" toggle (disabled unless a model is loaded)
+    And I should see "PCB Preset" toggle with Cmd+Shift+B
+    And I should see "Contour Lines" toggle
+    And I should see "Contour Interval" submenu with Auto and 0.1/0.2/0.5/1/2/5/10 mm options
+    And I should see "Color by Height" toggle
+    And I should see "Height Axis" submenu with X/Y/Z options
     And I should see "Show Diameter" toggle for radius measurements
+    And I should see "Performance HUD" toggle with Cmd+Shift+P
+    And I should see "Measurement Log" toggle with Cmd+Shift+L
+    And I should see "Detach Panels" submenu with "Measurement Log", "Analysis Panels" and "OpenSCAD Log" toggles
+    And I should see "Announce Measurements" toggle
+    And I should see "Annotations" toggle
     And I should see "Camera" submenu with view presets
+    And I should see "Saved Views" submenu (disabled unless a model is loaded)
+    And I should see "Layouts" submenu with "Save Current Layout..." and the saved layouts
+    And I should see "Presentation Mode" toggle with Cmd+Option+P (disabled unless a model is loaded)
+    And I should see "Ambient Occlusion in Presentations" toggle
+    And I should see "Clipping Planes" toggle
+    And I should see "Cutaway Follows Camera" toggle with Cmd+Shift+U
+    And I should see "Go to..." with Cmd+Shift+G
 
   Scenario: Camera submenu
     When I open the View menu
@@ -45,14 +76,46 @@ Feature: Application Menus
     Then I should see "Measure Distance" with Cmd+D
     And I should see "Measure Angle" with Cmd+A
     And I should see "Measure Radius"
+    And I should see "Dimension Bounding Box" with B
     And I should see "Select Triangles" with T
+    And I should see "Snap to Grid" toggle with S
+    And I should see "Refine Picks" toggle
+    And I should see "Snap To" submenu with "Mesh Vertices", "Feature Edges", "Measurement Points" and "Cut Vertices" toggles
+    And I should see "Magnifier" toggle with Cmd+Shift+M
+    And I should see "Reference Geometry" toggle with Cmd+Shift+R
+    And I should see "Run Script..." (disabled unless a model is loaded)
+    And I should see "Compare Measurements..." (disabled unless a model is loaded)
+    And I should see "Batch Templates" submenu with "New Template...", the saved templates, "Export Batch Results..." and "End Batch"
+    And I should see "Check Clearance Against..." (disabled unless a model is loaded)
+    And I should see "Remove Clearance Model" (disabled unless a clearance model is loaded)
+    And I should see "Compare Side by Side..." (disabled unless a model is loaded)
+    And I should see "Register Comparison" toggle (disabled unless a model is shown side by side)
+    And I should see "Close Comparison" (disabled unless a model is shown side by side)
+    And I should see "Draft Analysis" toggle (disabled unless a model is loaded)
+    And I should see "Pull Direction" submenu with +X/−X/+Y/−Y/+Z/−Z options
+    And I should see "Minimum Draft" submenu with 0.5/1/2/3/5° options
+    And I should see "Primitive Surfaces" toggle (disabled unless a model is loaded)
+    And I should see "Thickness Probe" toggle (disabled unless a model is loaded)
+    And I should see "Face Distance" toggle (disabled unless a model is loaded)
+    And I should see "Calibration Check" toggle (disabled unless a model is loaded)
+    And I should see "Caliper" submenu with "Across X", "Across Y", "Across Z", "Across Selected Face" and "Hide Caliper" (disabled unless a model is loaded)
+    And I should see "Align to Principal Axes" (disabled unless a model is loaded)
+    And I should see "Lay Flat on Largest Face" (disabled unless a model is loaded)
+    And I should see "Lay Flat on Face..." (disabled unless a model is loaded)
+    And I should see "Lay Flat on Selected Triangles" (disabled unless triangles are selected)
+    And I should see "Model Scale" submenu with "×25.4 (Inches)", "×1000 (Meters)", "÷1000 (Micrometers)" and "Reset Scale" (disabled unless a model is loaded)
+    And I should see "Plugin Analyzers" submenu with the installed analyzers
+    And I should see "Session Recording" submenu with "Start Recording", "Save Recording...", "Replay Session...", "Replay Session to Video..." and "Stop Replay"
     And I should see "Clear All Measurements" with Cmd+Shift+K
+    And I should see "Remove All Annotations" (disabled without annotations)
     And I should see "Copy as OpenSCAD" with Cmd+Shift+C
     And I should see "Change Material" with Cmd+M
+    And I should see "Infill" submenu with 100%/50%/40%/20%/15%/10% options
     And I should see "Open with go3mf"
     And I should see "Open in OpenSCAD" with Cmd+E (disabled unless .scad file is loaded)
 
   Scenario: Help menu structure
     When I open the Help menu
     Then I should see "About GoSTL"
+    And I should see "Settings..." with Cmd+, (mouse scheme, inertia, zoom to cursor, magnifier)
     And selecting it should show version info, build date, and commit hash
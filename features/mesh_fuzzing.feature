@@ -0,0 +1,30 @@
+@cli
+Feature: Mesh Fuzzing
+  As a developer of repair tools or the viewer
+  I want to damage models on purpose
+  So that I can test how defects are detected, repaired and displayed
+
+  Scenario: Punch holes and flip normals
+    When I run "gostl fuzz model.stl --holes 5 --flip-normals 2% --out damaged.stl"
+    Then "damaged.stl" should be missing 5 patches of 4 connected triangles
+    And 2% of the remaining triangles should be inside out
+    And the output should list each defect, the open and non-manifold edges of the result and the seed
+
+  Scenario: Repeat a run
+    Given "gostl fuzz model.stl --holes 3" printed "Seed: 1234"
+    When I run "gostl fuzz model.stl --holes 3 --seed 1234"
+    Then the same triangles should be removed
+
+  Scenario: Duplicate and degenerate triangles
+    When I run "gostl fuzz model.stl --duplicates 10 --degenerate 1%"
+    Then "model-damaged.stl" should contain 10 duplicated triangles
+    And 1% of its triangles should have zero area
+
+  Scenario: Vertex noise
+    When I run "gostl fuzz model.stl --noise 0.05"
+    Then every vertex should move by at most 0.05 mm along each axis
+    And vertices shared by triangles should move together, so the mesh stays closed
+
+  Scenario: No defect chosen
+    When I run "gostl fuzz model.stl"
+    Then the error should ask to choose at least one defect
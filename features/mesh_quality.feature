@@ -0,0 +1,68 @@
+@analysis @mesh_quality
+Feature: Mesh Quality Warnings
+  As a user
+  I want mesh defects to be reported as soon as a model loads
+  So that I know whether its measurements can be trusted
+
+  Background:
+    Given the application is running
+    And "Check mesh quality on load" is enabled in Settings > Display
+
+  Scenario: Problems are reported on load
+    When I open a model with 12 open edges and 3 flipped triangles
+    Then a banner at the top should read "2 mesh problems: 12 open edges, 3 flipped triangles"
+    And the checks should run in the background without blocking the view
+
+  Scenario: Clean models show no banner
+    When I open a closed model with consistent normals
+    Then no mesh quality banner should be shown
+
+  Scenario: Show details
+    Given the mesh quality banner is shown
+    When I click "Details"
+    Then every problem should be listed with its count
+    And each problem should explain why it matters for measuring
+    And a model with all normals pointing inward should be listed as "Inside out"
+
+  Scenario: Select the affected triangles
+    Given the details list degenerate, sliver or flipped triangles
+    When I click "Select" next to one of them
+    Then the affected triangles should be selected
+
+  Scenario: Fix repairable problems
+    Given the model has near-duplicate vertices, degenerate and flipped triangles
+    When I click "Fix"
+    Then near-duplicate vertices should be welded
+    And degenerate triangles should be removed
+    And flipped triangles should be turned around
+    And an inside-out model should be turned right side out
+    And the model should be marked as modified
+    And the mesh should be checked again
+    And Undo Leveling should restore the original mesh
+    And the file should not change until I save it
+
+  Scenario: Problems that need external repair
+    Given the model only has sliver triangles, open edges or non-manifold edges
+    Then the banner should not offer "Fix"
+    And the details should mention that these need a repair tool
+
+  Scenario: Dismiss the banner
+    Given the mesh quality banner is shown
+    When I click the close button
+    Then the banner should disappear
+    And it should stay hidden until another file is opened
+
+  Scenario: Reloading checks again
+    Given a model is open with the banner dismissed
+    When the file changes on disk and is reloaded
+    Then the new mesh should be checked again
+
+  Scenario: Turn the check off
+    When I turn off "Check mesh quality on load" in Settings > Display
+    And I open a model with open edges
+    Then no mesh quality banner should be shown
+
+  Scenario: Hidden while presenting
+    Given the mesh quality banner is shown
+    When I turn on presentation mode
+    Then the banner should be hidden
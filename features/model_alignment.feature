@@ -0,0 +1,75 @@
+@leveling @transformation @alignment
+Feature: Automatic Model Alignment
+  As a user importing models that arrive rotated arbitrarily
+  I want to normalize their orientation with one click
+  So that axis-constrained measurements and dimensions make sense
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Align to principal axes
+    Given a 40 x 20 x 5 mm plate rotated arbitrarily
+    When I select Tools > Align to Principal Axes
+    Then the model should be rotated about its bounding box center
+    And its longest extent should run along X, the next along Y and the smallest along Z
+    And the dimensions should read 40 x 20 x 5 mm
+    And the grid and model info should be updated
+
+  Scenario: Already aligned model
+    Given a 40 x 20 x 5 mm plate aligned with the axes
+    When I select Tools > Align to Principal Axes
+    Then the model should not move
+
+  Scenario: Lay flat on the largest face
+    Given a bracket whose base plate faces sideways
+    When I select Tools > Lay Flat on Largest Face
+    Then the model should be rotated so the base plate faces down
+    And the base plate should rest on Z = 0 in file coordinates
+
+  Scenario: Only faces the model can rest on
+    Given a cup whose inner bottom is larger than its foot
+    When I select Tools > Lay Flat on Largest Face
+    Then the cup should stand on its foot
+
+  Scenario: Lay flat on a picked face
+    When I select Tools > Lay Flat on Face...
+    Then the lay flat panel should ask me to click a face
+    When I click a side face of the model
+    Then the model should be rotated so all coplanar triangles of that face point down
+    And the face should lie on Z = 0 in file coordinates
+    And the grid, dimensions and model info should be updated
+    And the lay flat panel should close
+
+  Scenario: Lay flat on a plane fitted through selected triangles
+    Given I selected triangles on a slightly uneven surface with the triangle selection tool
+    When I select Tools > Lay Flat on Selected Triangles
+    Then the plane best fitting the selected triangles should be placed on Z = 0
+    And the triangle selection should be kept
+
+  Scenario: Fit a plane from the lay flat panel
+    Given triangles are selected
+    When I select Tools > Lay Flat on Face...
+    Then the lay flat panel should offer "Fit Plane to <n> Selected Triangles"
+
+  Scenario: Export the reoriented model
+    Given the model was laid flat on a face
+    When I select File > Save As...
+    Then the STL should be written in the new orientation
+
+  Scenario: Cancel face picking
+    Given I selected Tools > Lay Flat on Face...
+    When I press Escape
+    Then the lay flat panel should close
+    And the model should not change
+
+  Scenario: Undo an alignment
+    Given I aligned the model to its principal axes
+    When I select Tools > Undo Leveling
+    Then the model should return to its original orientation
+
+  @edge-case
+  Scenario: Symmetric models
+    Given a cube
+    When I select Tools > Align to Principal Axes
+    Then the orientation may be any of the equivalent axis assignments
@@ -18,6 +18,20 @@ Feature: Model Analysis
     Then the surface area should be calculated as the sum of all triangle areas
     And the surface area should be displayed in mm² or cm²
 
+  Scenario: Surface area by face orientation
+    Given a closed 40 × 20 × 10 mm box
+    When I run "gostl analyze box.stl"
+    Then the output should include Up-Facing Area 800 mm², Down-Facing Area 800 mm² and Vertical Area 1200 mm²
+    And the three areas should add up to the surface area
+    And the orientation should follow the triangle winding, not the stored facet normals
+
+  Scenario: Vertical tolerance
+    Given a wall tilted 3° back from vertical
+    When I run "gostl analyze part.stl --vertical-tolerance 5"
+    Then the wall should count as vertical
+    When I run "gostl analyze part.stl --vertical-tolerance 2"
+    Then the wall should count as up-facing
+
   Scenario: Bounding box calculation
     When the model is analyzed
     Then the bounding box should be calculated
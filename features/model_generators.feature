@@ -0,0 +1,39 @@
+@cli
+Feature: Model Generators
+  As a developer or printer owner
+  I want parametric test and calibration models from the command line
+  So that I have known geometry to test with and to tune my printer
+
+  Scenario: Generate a primitive
+    When I run "gostl generate cylinder --r 10 --h 40"
+    Then "cylinder.stl" should contain a closed cylinder 20 × 20 × 40 mm standing on z = 0
+    And the output should list the file, the triangle count and the dimensions
+
+  Scenario: Smooth round shapes by default
+    When I run "gostl generate sphere --r 10"
+    Then the sphere should deviate less than 0.02 mm from a true sphere
+    When I run "gostl generate sphere --r 10 --segments 16"
+    Then the sphere should have 16 segments around
+
+  Scenario: Options must fit the shape
+    When I run "gostl generate sphere --h 40"
+    Then the error should be "--h does not apply to sphere."
+
+  Scenario: Calibration cube
+    When I run "gostl generate calibration-cube --size 20 --text PLA"
+    Then "calibration-cube.stl" should be a 20 mm cube with X, Y and Z raised 0.6 mm on the front, right and top faces
+    And "PLA" should be raised on the left face
+
+  Scenario: Text that cannot be embossed
+    When I run "gostl generate calibration-cube --text 'PLA @ 210°'"
+    Then the error should be "Cannot emboss @°; use letters, digits, space and . - + / %"
+
+  Scenario: Tolerance gauge
+    When I run "gostl generate tolerance-gauge --pin-d 5 --clearances 0.1,0.2,0.3"
+    Then "tolerance-gauge.stl" should contain a plate with holes of 5.1, 5.2 and 5.3 mm
+    And each hole should have its clearance "0.1", "0.2" or "0.3" embossed in front of it
+    And a 5 mm pin should stand behind the plate
+
+  Scenario: Compressed output
+    When I run "gostl generate torus --r 5 --major-r 20 -o ring.stl.zst"
+    Then "ring.stl.zst" should be a zstd-compressed binary STL
@@ -0,0 +1,59 @@
+@manifest @cli
+Feature: Model Library Manifest
+  As a maintainer of released model sets
+  I want a manifest of hashes and key figures for every model
+  So that I can detect files that were changed or tampered with after release
+
+  Background:
+    Given a folder "models" with "bracket.stl" and "clips/case.3mf"
+
+  Scenario: Write a manifest
+    When I run "gostl manifest models --out manifest.json"
+    Then "manifest.json" should list "bracket.stl" and "clips/case.3mf" with paths relative to "models"
+    And each entry should have the file's SHA-256, a geometry hash, the triangle count, the dimensions and the volume
+    And the output should say "2 models written to manifest.json"
+
+  Scenario: Print the manifest
+    When I run "gostl manifest models"
+    Then the manifest JSON should be printed to stdout
+
+  Scenario: Unreadable models
+    Given "models/broken.stl" is not a valid STL
+    When I run "gostl manifest models --out manifest.json"
+    Then "broken.stl" should be reported on stderr and left out of the manifest
+    And the exit status should be 1
+
+  Scenario: Verify an unchanged library
+    Given "manifest.json" was written for "models"
+    When I run "gostl manifest models --verify manifest.json"
+    Then the output should say "2 of 2 files OK"
+    And the exit status should be 0
+
+  Scenario: Geometry changed
+    Given "manifest.json" was written for "models"
+    And "bracket.stl" was edited so it is 2 mm taller
+    When I run "gostl manifest models --verify manifest.json"
+    Then the output should contain "geometry changed: bracket.stl (dimensions ... -> ... mm, volume ... -> ... mm³)"
+    And the exit status should be 1
+
+  Scenario: Only the header or attribute bytes changed
+    Given "manifest.json" was written for "models"
+    And "bracket.stl" was re-saved with a different header
+    When I run "gostl manifest models --verify manifest.json"
+    Then the output should contain "metadata changed: bracket.stl"
+    And the exit status should be 0
+
+  Scenario: Missing and added files
+    Given "manifest.json" was written for "models"
+    And "clips/case.3mf" was deleted and "extra.stl" was added
+    When I run "gostl manifest models --verify manifest.json"
+    Then the output should contain "missing: clips/case.3mf" and "added: extra.stl"
+    And the exit status should be 1
+
+  Scenario: List unchanged files
+    When I run "gostl manifest models --verify manifest.json --verbose"
+    Then unchanged files should be listed as "unchanged: <path>"
+
+  Scenario: Write and verify are exclusive
+    When I run "gostl manifest models --out a.json --verify b.json"
+    Then an error should say that --out and --verify cannot be combined
@@ -0,0 +1,71 @@
+@ui @metadata
+Feature: Model Metadata and Custom Properties
+  As a user
+  I want to see the metadata stored in and around a model file and attach my own properties
+  So that I can track part numbers, revisions and where a model came from
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+
+  Scenario: Toggle metadata panel
+    When I select "Metadata" from the View menu
+    Then the metadata panel should appear at the bottom-right of the screen
+    When I press Cmd+Option+I
+    Then the metadata panel should be hidden
+
+  Scenario: File information
+    When the metadata panel is visible
+    Then I should see the file format and full path
+    And I should see the file size and modification date
+
+  Scenario: Binary STL header text
+    Given a binary STL whose 80-byte header contains "Exported by FreeCAD"
+    When the metadata panel is visible
+    Then I should see "STL Header" with value "Exported by FreeCAD"
+    And trailing zero bytes and non-printable characters should be removed
+
+  Scenario: ASCII STL solid name
+    Given an ASCII STL starting with "solid bracket"
+    When the metadata panel is visible
+    Then I should see "Solid Name" with value "bracket"
+
+  Scenario: 3MF metadata
+    Given a 3MF file with metadata "Title" and "Designer" in its model part
+    When the metadata panel is visible
+    Then I should see "Title" and "Designer" under "Embedded"
+
+  Scenario: Source file of an exported STL
+    Given "bracket.stl" was exported from "bracket.scad" in the same folder
+    When "bracket.stl" is loaded
+    Then the metadata panel should show "bracket.scad" as source
+
+  Scenario: Add a custom property
+    When I enter key "Part Number" and value "BR-1042" in the metadata panel
+    And I press Enter
+    Then the property should be listed under "Properties"
+    And it should be saved to "<file>.gostl" next to the model file
+
+  Scenario: Edit and remove custom properties
+    Given the model has a property "Revision" with value "B"
+    When I change the value to "C" and press Enter
+    Then the sidecar should contain "Revision" with value "C"
+    When I click the trash button of "Revision"
+    Then the property should be removed from the sidecar
+    And the sidecar file should be deleted when no properties are left
+
+  Scenario: Properties are restored when the file is opened again
+    Given "bracket.stl.gostl" contains property "Customer" with value "ACME"
+    When I open "bracket.stl"
+    Then the metadata panel should list "Customer" with value "ACME"
+
+  Scenario: Properties in the inspection report
+    Given the model has a property "Part Number" with value "BR-1042"
+    When I export the inspection report
+    Then the report should contain a "PROPERTIES" section with "Part Number: BR-1042"
+
+  Scenario: Reload keeps properties
+    Given the model has custom properties
+    When the file changes on disk and is reloaded
+    Then the file size and modification date should be updated
+    And the custom properties should be unchanged
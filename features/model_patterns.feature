@@ -0,0 +1,43 @@
+@cli @export @transformation
+Feature: Linear and Polar Patterns
+  As a user preparing several copies or a radial fixture
+  I want to repeat a model in a row or around a circle
+  So that I get one STL with all copies placed
+
+  Scenario: Linear pattern
+    When I run "gostl pattern clip.stl --count 4 --spacing 3"
+    Then "clip-pattern.stl" should contain 4 copies of the clip along X
+    And neighboring copies should be 3 mm apart
+    And the first copy should stay where the model is
+
+  Scenario: Linear pattern along another axis
+    When I run "gostl pattern plate.stl --count 3 --axis z --spacing 0.2"
+    Then the copies should be stacked along Z with 0.2 mm gaps
+
+  Scenario: Polar pattern
+    When I run "gostl pattern blade.stl --count 6 --polar --radius 40"
+    Then the copies should be 60° apart around an axis 40 mm from the model's center
+    And each copy should be turned with the pattern
+    And the output should list the radius and the angle between copies
+
+  Scenario: Polar pattern over an arc without rotation
+    When I run "gostl pattern peg.stl --count 5 --polar --radius 30 --angle 90 --no-rotate"
+    Then the copies should be 22.5° apart from 0° to 90°
+    And every copy should keep the model's orientation
+
+  Scenario: Default radius
+    When I run "gostl pattern blade.stl --count 8 --polar"
+    Then the radius should be just large enough for the copies to stay 5 mm apart
+
+  Scenario: Radius too small
+    When I run "gostl pattern blade.stl --count 12 --polar --radius 5"
+    Then the pattern should still be written
+    And stderr should warn that the copies may overlap
+
+  Scenario: Preview in the viewer
+    When I run "gostl pattern blade.stl --count 6 --polar --open"
+    Then the pattern should be written and opened in the GoSTL window
+
+  Scenario: Polar options without --polar
+    When I run "gostl pattern clip.stl --count 3 --radius 40"
+    Then the error should be "--radius, --angle and --no-rotate need --polar."
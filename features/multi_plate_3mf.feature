@@ -18,6 +18,12 @@ Feature: 3MF Multi-Plate Support
     Then the first plate should be displayed
     And the first plate button should be highlighted
 
+  Scenario: Show all plates together
+    Given I have a 3MF file with multiple plates open
+    When I click the "All" button in the plate selector
+    Then the models of all plates should be displayed together
+    And the "All" button should be highlighted
+
   Scenario: Switch between plates
     Given I have a 3MF file with multiple plates open
     When I click on a different plate button
@@ -0,0 +1,63 @@
+@openscad @color
+Feature: OpenSCAD Color Groups
+  As a user designing multi-part or multi-color models in OpenSCAD
+  I want the parts of each color() call kept apart
+  So that I can check them individually without editing the source
+
+  Background:
+    Given OpenSCAD is installed
+    And "assembly.scad" contains "color("red") cube(10);" and "color("blue") translate([20, 0, 0]) sphere(5);"
+
+  Scenario: Colors are shown after rendering
+    When I open "assembly.scad"
+    Then the cube should be drawn in red and the sphere in blue
+
+  Scenario: Color groups in the menu panel
+    When I open "assembly.scad"
+    Then the menu panel should show a "Color Groups" section
+    And it should list "#FF0000" and "#0000FF" with a swatch and their triangle counts
+    And every group should be checked
+
+  Scenario: Hide a color group
+    Given "assembly.scad" is open
+    When I uncheck "#0000FF" in the Color Groups section
+    Then only the cube should be shown
+    And the camera should not move
+    And measurements and analysis should only use the visible triangles
+    When I click "Show All"
+    Then the sphere should be shown again
+
+  Scenario: The last visible group cannot be hidden
+    Given "assembly.scad" is open
+    And "#0000FF" is hidden
+    When I uncheck "#FF0000"
+    Then the cube should stay visible
+
+  Scenario: Hidden groups survive a reload
+    Given "assembly.scad" is open
+    And "#0000FF" is hidden
+    When I save a change to the cube size in my editor
+    Then the file should be re-rendered
+    And the sphere should stay hidden
+
+  Scenario: Hidden colors that disappear are forgotten
+    Given "assembly.scad" is open
+    And "#0000FF" is hidden
+    When I change the sphere to "color("green")" and save
+    Then the green sphere should be shown
+
+  Scenario: Files without colors
+    When I open a .scad file without color() calls
+    Then the Color Groups section should not be shown
+
+  Scenario: Render through 3MF export
+    Given "Render OpenSCAD colors through 3MF" is enabled in Settings > Files
+    When I open "assembly.scad"
+    Then OpenSCAD should be run once with the manifold backend and 3MF color export
+    And the colors should be read from the 3MF base materials
+
+  Scenario: 3MF export falls back to per-color rendering
+    Given "Render OpenSCAD colors through 3MF" is enabled in Settings > Files
+    And the installed OpenSCAD cannot export colored 3MF files
+    When I open "assembly.scad"
+    Then each color should be rendered separately as without the setting
@@ -0,0 +1,61 @@
+@openscad
+Feature: OpenSCAD Module Isolation
+  As a user working on a large OpenSCAD assembly
+  I want to render a single module of the file
+  So that I can inspect sub-parts without editing the source
+
+  Background:
+    Given OpenSCAD is installed
+    And "assembly.scad" defines the top-level modules "base(w = 40)" and "lid(w)"
+    And the file ends with "base(); lid(40);"
+
+  Scenario: Modules are listed in the menu panel
+    When I open "assembly.scad"
+    Then the menu panel should show an "OpenSCAD" section with a "Module" dropdown set to "Whole file"
+    And the dropdown should offer "Whole file", "base()" and "lid(w)"
+
+  Scenario: Nested and commented-out modules are not listed
+    Given "lid" contains the nested module "knob()"
+    And a comment contains "module old_lid() {}"
+    When I open "assembly.scad"
+    Then the dropdown should not offer "knob()" or "old_lid()"
+
+  Scenario: Render a single module
+    Given "assembly.scad" is open
+    When I choose "base()" in the Module dropdown
+    Then only the base should be rendered
+    And the camera should frame the base
+    And the source file should not be modified
+
+  Scenario: Module rendering uses a temporary wrapper
+    When the module "base" is rendered in isolation
+    Then a temporary "gostl_module_<id>.scad" next to the source should contain "use <assembly.scad>" and "base();"
+    And the wrapper should be removed after rendering
+    And top-level variables and other modules of the file should remain available to "base"
+
+  Scenario: Modules with required parameters
+    Given "assembly.scad" is open
+    When I choose "lid(w)" in the Module dropdown
+    Then "lid" should be called without arguments
+    And the section should note that "w" is undef
+
+  Scenario: Back to the whole file
+    Given the module "base" is isolated
+    When I choose "Whole file" in the Module dropdown
+    Then the whole assembly should be rendered
+
+  Scenario: The isolated module is kept on reload
+    Given the module "base" is isolated
+    When I save a change to "base" in my editor
+    Then only the updated base should be rendered
+    And the camera should not move
+
+  Scenario: The isolated module is removed from the file
+    Given the module "base" is isolated
+    When I rename "base" to "plate" and save
+    Then the whole file should be rendered
+    And the dropdown should offer "plate()"
+
+  Scenario: Files without modules
+    When I open a .scad file without module definitions
+    Then the OpenSCAD section should not be shown
@@ -0,0 +1,49 @@
+@openscad @export @measurement
+Feature: OpenSCAD Variables from Measurements
+  As a user re-modeling a scanned part in OpenSCAD
+  I want my measurements as named OpenSCAD variables
+  So that I can start the parametric model from the measured values
+
+  Scenario: Copy measurements as variables
+    Given a model "bracket.stl" is loaded
+    And I measured a distance of 32.04 mm, an angle of 90° and a radius of 1.6 mm
+    When I select Tools > Copy as OpenSCAD Variables
+    Then the clipboard should contain "distance_1 = 32.04;", "angle_1 = 90.00;" and "radius_1 = 1.60;"
+    And the radius should also be given as "diameter_1 = 2 * radius_1;"
+    And the header should name "bracket.stl"
+
+  Scenario: Notes name the variables
+    Given the first distance has the note "Hole spacing"
+    When I copy the measurements as OpenSCAD variables
+    Then the clipboard should contain "hole_spacing = 32.04;  // Distance 1, mm"
+    And a second measurement noted "hole spacing" should become "hole_spacing_2"
+
+  Scenario: Selected measurements keep their names
+    Given three distances were measured
+    And only the third distance is selected
+    When I copy the measurements as OpenSCAD variables
+    Then only "distance_3" should be copied
+
+  Scenario: Values in file coordinates and precision
+    When measurements are converted to variables
+    Then angles should be in degrees and lengths in mm
+    And values should use the measurement's own decimal places, else 2
+    And triangle selections should be skipped
+
+  Scenario: Export to a file
+    When I select File > Export Measurements as OpenSCAD Variables...
+    Then a save dialog should suggest "bracket-measurements.scad"
+
+  @cli
+  Scenario: Variables from the command line
+    Given "bracket.stl.gostl" holds saved measurements
+    When I run "gostl scad-vars bracket.stl"
+    Then the variables should be printed to standard output
+    When I run "gostl scad-vars bracket-measurements.json -o dims.scad --decimals 3"
+    Then "dims.scad" should hold the exported measurements with 3 decimal places
+
+  @cli
+  Scenario: Nothing to convert
+    Given "part.stl" has no saved measurements
+    When I run "gostl scad-vars part.stl"
+    Then it should fail with "No measurements in part.stl"
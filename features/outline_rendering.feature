@@ -0,0 +1,46 @@
+@visualization @outline
+Feature: Outline Rendering
+  As a user preparing documentation
+  I want a line-drawing view of the model
+  So that I can use clean figures in assembly instructions and manuals
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+
+  Scenario: Turn on outline mode
+    When I press Cmd+Shift+O or select View > Outline Mode
+    Then the background should turn white
+    And the model surface should be filled flat white without shading
+    And only the silhouette and feature edges should be drawn as dark lines
+    And the grid and build plate should be hidden
+
+  Scenario: Silhouette follows the camera
+    Given outline mode is active
+    When I rotate the camera
+    Then the silhouette should be recomputed for the new viewpoint
+    And edges hidden behind the model should not be visible
+
+  Scenario: Feature edges use the edge angle threshold
+    Given outline mode is active
+    Then creases sharper than the edge angle threshold should be drawn on faces toward the camera
+    And open mesh boundaries should always be drawn
+    And edges between coplanar triangles should never be drawn
+
+  Scenario: Measurements stay visible
+    Given outline mode is active
+    And I have completed measurements
+    Then the measurements should be drawn on top of the line drawing
+
+  Scenario: Turn off outline mode
+    Given outline mode is active
+    When I press Cmd+Shift+O again
+    Then the shaded view should return with the previous wireframe, grid and build plate settings
+
+  Scenario: Export the outline as SVG
+    When I select File > Export Outline as SVG...
+    And I choose a destination file
+    Then an SVG file should be written with the silhouette and feature edges for the current view
+    And hidden parts of edges should be removed
+    And the drawing should have a white background and the size of the 3D view
+    And outline mode does not need to be active for the export
@@ -0,0 +1,47 @@
+@cli @export
+Feature: Part Labels
+  As a user printing parts for the shop
+  I want printable labels with the key facts of a model
+  So that physical parts can be identified and traced back to their source
+
+  Scenario: Create a label
+    When I run "gostl label bracket.stl"
+    Then "bracket-label.png" should be written next to the model
+    And the label should be 62×29 mm at 300 dpi
+    And it should show "bracket.stl", the overall dimensions in mm, the volume and today's date
+    And a QR code on the right should link to the model file as a file:// URL
+
+  Scenario: Link to a report
+    When I run "gostl label bracket.stl --link https://wiki.example.com/parts/bracket"
+    Then the QR code should open "https://wiki.example.com/parts/bracket"
+
+  Scenario: Remote models
+    When I run "gostl label https://example.com/files/bracket.stl"
+    Then the QR code should link to "https://example.com/files/bracket.stl"
+    And "bracket-label.png" should be written to the current directory
+
+  Scenario Outline: Output formats
+    When I run "gostl label bracket.stl -o <file>"
+    Then a <format> label should be written
+
+    Examples:
+      | file               | format |
+      | bracket-label.png  | PNG    |
+      | bracket-label.pdf  | PDF    |
+      | bracket-label.svg  | SVG    |
+
+  Scenario: Label size and resolution
+    When I run "gostl label bracket.stl --size 89x36 --dpi 600"
+    Then the label should be 89×36 mm at 600 dpi
+    And the QR code should fill the label's height
+    And labels smaller than 30×12 mm should be rejected
+
+  Scenario: Long file names
+    Given a model named "enclosure-lid-with-snap-fits-and-cable-gland-v12.stl"
+    When I create a label
+    Then the name should be shrunk to fit
+    And if it still does not fit it should be shortened in the middle, keeping "v12.stl"
+
+  Scenario: Without QR code or date
+    When I run "gostl label bracket.stl --no-qr --no-date"
+    Then the label should only show the name, dimensions and volume across its full width
@@ -0,0 +1,73 @@
+@visualization @measurement
+Feature: PCB Viewing Preset
+  As a user fitting an electronics enclosure
+  I want a viewing mode for PCB 3D exports
+  So that I can quickly read board thickness and component heights
+
+  Background:
+    Given "board.stl" is a KiCad 3D export converted to STL
+    And the board is 1.6mm thick with its bottom at Z=0
+    And a 12mm tall electrolytic capacitor and a 3mm tall pin header are on the top side
+    And a 2mm tall connector is on the bottom side
+
+  Scenario: Turn the preset on
+    Given "board.stl" is open
+    When I choose View > PCB Preset or press Cmd+Shift+B
+    Then the menu panel should show a "PCB" section
+    And the board should be detected as "W × D × 1.60 mm"
+    And the camera should frame the model
+
+  Scenario: Grid follows the board plane
+    Given the PCB preset is on
+    Then the bottom grid should lie at the board bottom (Z=0), not at the bottom of the lowest component
+    And grid-snapped measurement points should snap on the board plane
+
+  Scenario: Board detection
+    Given the PCB preset is on
+    Then the board faces should be the two largest horizontal planes 0.2 to 3.5mm apart
+    And the board faces together should cover at least 20% of the model footprint
+    When the model has no such faces
+    Then the PCB section should show "No board found"
+
+  Scenario: Components and clearances
+    Given the PCB preset is on
+    Then the PCB section should list the components tallest first, top side before bottom side
+    And each row should show the height above (or depth below) the board and the footprint
+    And the section should show "Top: 12.00 mm", "Bottom: 2.00 mm" and "Stack: 15.60 mm"
+    And shells with overlapping footprints (a body and its pins) should be listed as one component
+
+  Scenario: Measure a component height
+    Given the PCB preset is on
+    When I click the capacitor row
+    Then a vertical distance measurement of 12.00mm should be added from the board top to the capacitor's top
+    And the orbit target should move to the capacitor's top
+
+  Scenario: Measure the tallest components
+    Given the PCB preset is on
+    When I click "Measure Tallest"
+    Then height measurements should be added for the tallest top component and the deepest bottom component
+
+  Scenario Outline: Z exaggeration
+    Given the PCB preset is on
+    When I choose <factor> in the "Z" row of the PCB section
+    Then the model should be drawn stretched <factor> along Z around the board top
+    And picking, snapping and measurement values should use the true dimensions
+    And measurement labels should stay attached to their points
+
+    Examples:
+      | factor |
+      | 1×     |
+      | 2×     |
+      | 5×     |
+      | 10×    |
+
+  Scenario: Reloads keep the preset
+    Given the PCB preset is on
+    When the file is reloaded or leveled
+    Then the board and components should be detected again
+
+  Scenario: Turn the preset off
+    Given the PCB preset is on with 5× Z exaggeration
+    When I press Cmd+Shift+B
+    Then the model should be drawn at true scale
+    And the grid should return to the bottom of the model
@@ -0,0 +1,43 @@
+@visualization @performance @ui
+Feature: Performance HUD
+  As a user reporting a performance problem
+  I want to see frame timing and draw statistics
+  So that I can describe where time is spent
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+
+  Scenario: Toggle the performance HUD
+    When I select View > Performance HUD or press Cmd+Shift+P
+    Then the performance HUD should appear below the orientation cube
+    When I press Cmd+Shift+P again
+    Then the performance HUD should be hidden
+
+  Scenario: Frame time breakdown
+    Given the performance HUD is visible
+    Then it should show frames per second and the average frame time
+    And the CPU time spent encoding the frame
+    And the GPU execution time of the frame
+    And the duration of the last hover pick (ray cast and vertex snapping)
+    And the duration of the last slicing clip pass
+
+  Scenario: Draw statistics
+    Given the performance HUD is visible
+    Then it should show the number of draw calls in the last frame
+    And the number of triangles and lines drawn (including instanced wireframe cylinders)
+    And the triangle count of the loaded model
+
+  Scenario: Memory usage
+    Given the performance HUD is visible
+    Then it should show the physical memory footprint of the application
+    And the memory allocated by the Metal device
+
+  Scenario: Update rate
+    Given the performance HUD is visible
+    Then the values should be averaged and refreshed twice per second
+
+  Scenario: Copy a performance report
+    Given the performance HUD is visible
+    When I click the copy button
+    Then all displayed values should be copied to the clipboard as plain text
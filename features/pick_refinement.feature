@@ -0,0 +1,47 @@
+@measurement @pick-refinement
+Feature: Pick Refinement
+  As a user
+  I want picked points to be refined by fitting the surface around them
+  So that I can measure edges and faces more precisely than the mesh vertices allow
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+    And "Refine Picks" is enabled in the Tools menu
+
+  Scenario: Point on a flat face
+    When I start a distance measurement
+    And I hover over a flat face away from its edges
+    Then the tools panel should show "Refined to surface"
+    And the point should lie on the plane fitted to the face
+    And the refined coordinates should be shown with an uncertainty
+
+  Scenario: Point near an edge
+    When I hover within 2 mm of the edge between two faces
+    Then the point should move onto the intersection line of the fitted face planes
+    And the tools panel should show "Refined to edge"
+
+  Scenario: Point near a corner
+    When I hover within 2 mm of the corner where three faces meet
+    Then the point should move onto the intersection of the three fitted planes
+    And the tools panel should show "Refined to corner"
+
+  Scenario: Uncertainty of a noisy surface
+    Given the model is a noisy scan
+    When I hover over a face
+    Then the uncertainty should be the standard error of the fitted plane
+    And a shallow edge should have a larger uncertainty than a square edge
+
+  Scenario: Distance between refined points
+    When I measure a distance between two refined points
+    Then the label should show the distance with the combined uncertainty, e.g. "25.00 ±0.004"
+
+  Scenario: Refinement bypasses vertex snapping
+    When I hover over a dense mesh
+    Then the refined point should be used instead of the closest vertex
+    And reference snapping and grid snapping should still take precedence
+
+  Scenario: Refinement disabled
+    Given "Refine Picks" is disabled
+    When I pick a point
+    Then the point should snap to the closest vertex as before
@@ -0,0 +1,49 @@
+@cli @export
+Feature: Merge Models onto a Plate
+  As a user printing a parts list
+  I want to combine several models into one file without overlap
+  So that I can print them on one plate
+
+  Scenario: Shelf layout
+    When I run "gostl merge a.stl b.stl c.stl --spacing 5 --out plate.stl"
+    Then "plate.stl" should contain all three models standing on z = 0
+    And their footprints should be at least 5 mm apart
+    And the output should list the plate size and where each part was placed
+
+  Scenario: Grid layout
+    When I run "gostl merge a.stl b.stl c.stl d.stl --arrange grid --spacing 5 --out plate.stl"
+    Then the parts should be centered in 2 × 2 equal cells sized for the largest part
+
+  Scenario: Limit the row length to the build plate
+    When I run "gostl merge a.stl b.stl c.stl --width 256"
+    Then no row should be longer than 256 mm
+    And a part wider than 256 mm should get a row of its own with a warning
+
+  Scenario: Nest outlines on a bed
+    When I run "gostl merge l-bracket.stl l-bracket.stl disc.stl --arrange nest --bed X1C --spacing 3"
+    Then the parts should be packed by their outlines, turned about Z in 90° steps where that fits better
+    And every part should lie on the 256 × 256 mm bed at least 3 mm from the others
+    And the output should list each part's position and rotation
+    And the output should report the packing efficiency as a share of the bed
+
+  Scenario: Round bed and finer rotations
+    When I run "gostl merge a.stl b.stl --arrange nest --bed d200 --rotation-step 15 --resolution 0.5"
+    Then every part should lie inside the 200 mm circle
+
+  Scenario: Parts that do not fit
+    When I run "gostl merge big.stl big.stl --arrange nest --bed 100x100"
+    Then "plate.stl" should contain the parts that fit
+    And the parts left over should be listed on stderr
+    And the command should exit with a failure
+
+  Scenario: Nesting needs a bed
+    When I run "gostl merge a.stl b.stl --arrange nest"
+    Then the error should be "--arrange nest needs a --bed, e.g. 256x256, d200 or X1C."
+
+  Scenario: Several copies
+    When I run "gostl merge clip.stl clip.stl clip.stl bracket.stl"
+    Then "plate.stl" should contain three clips and one bracket
+
+  Scenario: Too few models
+    When I run "gostl merge a.stl"
+    Then the error should be "Give at least two models to merge."
@@ -0,0 +1,50 @@
+@plugins
+Feature: Plugins
+  As a user with formats and analyses that are not built in
+  I want to install plugins
+  So that I can extend gostl without forking it
+
+  Background:
+    Given the application is running
+    And the example "OFF Importer" plugin is installed in ~/Library/Application Support/GoSTL/Plugins
+
+  Scenario: Open a file through an importer plugin
+    When I open "part.off"
+    Then the plugin should convert it to STL
+    And the model should be displayed like an STL file
+
+  Scenario: Importer plugins extend the supported file types
+    Then "part.off" should be selectable in the Open dialog
+    And dropping "part.off" onto the window should open it
+    And "gostl script inspect.js part.off" should load it headless
+
+  Scenario: Run an analyzer plugin
+    Given a 3D model is loaded
+    When I select Tools > Plugin Analyzers > Vertex Centroid
+    Then a "VERTEX CENTROID" panel should appear in the bottom-right corner
+    And it should list the results returned by the plugin
+    And the plugin overlay points and lines should be drawn in magenta
+
+  Scenario: Plugin errors are shown
+    Given an analyzer plugin that exits with a non-zero status
+    When I run it
+    Then the results panel should show the exit status and the plugin's error output
+
+  Scenario: Plugin results belong to the loaded file
+    Given plugin results are shown
+    When I open a different file
+    Then the plugin results panel should be closed
+
+  Scenario: Invalid plugins are ignored
+    Given a plugin directory with an invalid plugin.json
+    When the application starts
+    Then the plugin should be skipped
+
+  Scenario: List installed plugins
+    When I run "gostl plugins"
+    Then each plugin should be listed with its importers and analyzers
+
+  Scenario: Load plugins from a custom path
+    Given GOSTL_PLUGIN_PATH is set to "examples/plugins"
+    When I run "gostl plugins"
+    Then the "OFF Importer" plugin should be listed
@@ -0,0 +1,47 @@
+@openscad @export
+Feature: OpenSCAD Polyhedron Export
+  As a user combining measured or repaired meshes with parametric designs
+  I want to export a model as an OpenSCAD polyhedron module
+  So that I can use the geometry as a reference inside my OpenSCAD projects
+
+  Scenario: Export from the viewer
+    Given a model "bracket.stl" is loaded
+    When I select File > Export as OpenSCAD Polyhedron...
+    Then a save dialog should suggest "bracket-polyhedron.scad"
+    And the dialog should offer "Full resolution" and vertex merging within 0.1, 0.5 or 1 mm
+    When I save with "Full resolution"
+    Then the file should define "module bracket()" containing a polyhedron of every triangle
+    And the file should end with a call of "bracket();"
+
+  Scenario: Module contents
+    When a model is exported as a polyhedron
+    Then identical vertices should be written once and referenced by index
+    And the faces should be wound clockwise seen from outside as OpenSCAD expects
+    And the vertices should be in the file's original coordinates, even when the view was recentered
+    And the header should name the source file and the vertex and face counts
+
+  Scenario: Use the module in a design
+    Given "bracket.scad" was exported
+    When an OpenSCAD file contains "use <bracket.scad>" and "difference() { cube(50); bracket(); }"
+    Then the bracket geometry should be subtracted without rendering it twice
+
+  Scenario: Module name from the file name
+    When "my part-2.stl" is exported
+    Then the module should be named "my_part_2"
+    And names starting with a digit should get a "model_" prefix
+
+  Scenario: Export from the command line
+    When I run "gostl polyhedron bracket.stl"
+    Then "bracket.scad" should be written next to the model
+    When I run "gostl polyhedron bracket.stl -o ref.scad --name bracket_ref"
+    Then "ref.scad" should define "module bracket_ref()"
+
+  Scenario: Decimated export
+    When I run "gostl polyhedron scan.stl --decimate 0.5"
+    Then vertices within the same 0.5 mm grid cell should be merged into their average
+    And triangles that collapse to a line or point should be dropped
+    And the header should note the original face count and the cell size
+
+  Scenario: Invalid decimation
+    When I run "gostl polyhedron scan.stl --decimate 0"
+    Then an error should ask for a positive cell size in mm
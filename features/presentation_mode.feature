@@ -0,0 +1,61 @@
+@ui @presentation
+Feature: Workspace Layouts and Presentation Mode
+  As a user leading a design review on a projector
+  I want to switch between panel layouts and present the model without any clutter
+  So that everyone sees the model, the dimensions and the views I prepared
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Save a layout
+    Given the info panel is hidden and the measurement log is detached
+    When I select View > Layouts > Save Current Layout... and enter "Review"
+    Then "Review" should be listed in View > Layouts
+    And it should be stored in the settings for all models
+
+  Scenario: Restore a layout
+    Given a layout "Review" with the measurement log detached
+    When I select View > Layouts > Review
+    Then the panels saved in the layout should be shown and all others hidden
+    And the measurement log should open in its own window
+
+  Scenario: Replace and remove layouts
+    When I save a layout under a name that already exists
+    Then the existing layout should be replaced
+    When I select it in View > Layouts > Remove
+    Then it should no longer be listed
+
+  Scenario: Enter presentation mode
+    When I press Cmd+Option+P
+    Then the window should go full screen
+    And all panels over the 3D view should be hidden
+    And measurement labels should be shown larger
+
+  Scenario: Fly between saved views
+    Given the model has the saved views "Front", "Detail" and "Bottom"
+    And presentation mode is active
+    When I press the right arrow key, Space or Page Down
+    Then the camera should fly smoothly to "Front"
+    When I press it again
+    Then the camera should fly to "Detail", easing in and out and pulling back while it turns
+    When I press the left arrow key or Page Up
+    Then the camera should fly back to "Front"
+    And stepping past the last view should wrap around to the first
+
+  Scenario: Recall a saved view while presenting
+    Given presentation mode is active
+    When I select a view from View > Saved Views
+    Then the camera should fly there instead of jumping
+
+  Scenario: Without saved views
+    Given the model has no saved views
+    And presentation mode is active
+    When I press the arrow keys
+    Then the camera should orbit as usual
+
+  Scenario: Leave presentation mode
+    Given presentation mode is active
+    When I press ESC or Cmd+Option+P
+    Then the window should leave full screen
+    And the panels shown before should be restored
@@ -0,0 +1,63 @@
+@rendering @presentation @export
+Feature: Presentation Shots
+  As a user sharing parts with customers
+  I want product shots with a clean background, a ground shadow and depth of field
+  So that I do not have to export the model to a render tool for a nice image
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Export the current view as an image
+    When I select "Export View Image..." from the File menu
+    Then a save dialog for a PNG image should open
+    And it should offer the image size, the background, a ground shadow, depth of field and ambient occlusion
+    When I save the image
+    Then "Rendering" should run as a background job
+    And the image should show the model from the current view
+
+  Scenario Outline: Backgrounds
+    Given I chose the "<background>" background
+    When I export the view image
+    Then the image background should be <look>
+
+    Examples:
+      | background      | look                                          |
+      | Studio          | a light grey gradient with darker corners     |
+      | Dark Studio     | a dark grey gradient with darker corners      |
+      | Sky             | blue fading into a light horizon over grey    |
+      | View Background | the background color of the 3D view           |
+      | Transparent     | transparent                                   |
+
+  Scenario: Ground shadow
+    Given "Ground shadow" is checked
+    When I export the view image from above the model
+    Then a soft shadow should lie under the model
+    And it should be darkest where the model touches the ground
+    And it should show through a transparent background as semi-transparent black
+
+  Scenario: No shadow from below
+    Given "Ground shadow" is checked
+    When I export the view image looking up at the model
+    Then no ground shadow should be drawn
+
+  Scenario: Depth of field
+    Given "Depth of field" is checked
+    When I export the view image
+    Then the geometry at the rotation center should be sharp
+    And geometry and ground in front of or behind it should be subtly blurred
+
+  Scenario: Ambient occlusion
+    Given "Ambient occlusion" is checked
+    When I export the view image
+    Then creases and cavities should be shaded darker
+    And an ambient occlusion baked for presentation mode should be reused
+
+  Scenario: Options are remembered
+    Given I exported a view image with the "Sky" background and 1024 px
+    When I export the next view image
+    Then the save dialog should preselect the "Sky" background and 1024 px
+
+  Scenario: No turntable animation
+    When I select "Export View Image..." from the File menu
+    Then a single still image should be exported
@@ -0,0 +1,45 @@
+@analysis @primitives
+Feature: Primitive Surface Detection
+  As a user inspecting an unfamiliar part
+  I want the planes, cylinders, cones and spheres of the mesh recognized with their sizes
+  So that I can read off every boss and hole diameter at once instead of measuring each one
+
+  Scenario: Color the model by primitive surface
+    Given a model is loaded
+    When I enable "Primitive Surfaces" in the Tools menu
+    Then planes should be gray, cylinders green, cones orange and spheres purple
+    And surfaces that fit no primitive should be red
+    And neighboring surfaces of the same kind should differ in brightness
+    And the Primitives panel should show the recognized share of the surface per kind
+
+  Scenario: Detected parameters
+    Given a block with a 6.4 mm bore and a 10 mm boss
+    When the primitives are detected
+    Then the panel should list "Ø 6.40 hole" and "Ø 10.00 boss" with their lengths
+    And partial cylinders like fillets should be listed with radius and covered angle
+    And parameters should be in file coordinates
+
+  Scenario: Select a detected surface
+    Given the primitives are shown
+    When I click a surface in the Primitives panel
+    Then its triangles should be selected
+
+  Scenario: Segmentation
+    When the primitives are detected
+    Then connected coplanar triangles should form one flat face
+    And flat faces meeting at less than 40° with neighbors of similar width should form one curved surface
+    And a large flat face next to a fillet should stay a plane
+    And a curved surface should be a cylinder, cone or sphere only if all its vertices lie within 0.05 mm of it
+
+  Scenario: Only one analysis colors the model
+    Given the draft analysis or the height colors are shown
+    When I enable "Primitive Surfaces"
+    Then the other analysis should be turned off
+
+  @cli
+  Scenario: Report primitives from the command line
+    When I run "gostl primitives part.stl"
+    Then the output should show the recognized share of the surface per primitive kind
+    And every cylinder, cone and sphere with diameter, length and axis
+    And "--planes" should list the flat faces as well
+    And "--colors part-primitives.ply" should write the model with the primitive colors
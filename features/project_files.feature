@@ -0,0 +1,49 @@
+@ui @persistence
+Feature: Project Files
+  As a user reviewing the same model over several sessions
+  I want my measurements, datums, views and units stored next to the model
+  So that opening the model brings back where I left off
+
+  Background:
+    Given "bracket.stl" is open
+
+  Scenario: Save the project
+    Given I have taken 2 measurements and created a datum plane
+    When I choose File → Save Project
+    Then "bracket.stl.gostl" should contain the measurements and the datum in file coordinates
+    And it should contain an analysis snapshot with the volume, dimensions and triangle count
+
+  Scenario: Project is loaded with the model
+    Given "bracket.stl.gostl" contains measurements, datums, saved views and units
+    When I open "bracket.stl"
+    Then the measurements and datums should be shown
+    And View → Saved Views should list the saved views
+    And lengths should be shown in the saved units
+
+  Scenario: Saved views
+    When I choose View → Saved Views → Save Current View... and enter "Mounting holes"
+    And I rotate the model
+    And I choose View → Saved Views → "Mounting holes"
+    Then the camera should return to the saved position
+
+  Scenario: Units for one model
+    When I choose View → Units for This Model → Micrometers (µm)
+    Then lengths of this model should be shown in µm
+    And other models should keep the units of Settings > Display
+
+  Scenario: Snapshots follow revisions
+    Given the project was saved for revision A
+    When "bracket.stl" changes and I save the project again
+    Then the project should contain one snapshot per save, oldest first
+
+  Scenario: Older projects
+    Given "bracket.stl.gostl" was written with only properties, sections and annotations
+    When I open "bracket.stl"
+    Then the properties, sections and annotations should load as before
+    And the next change should write the file in the current version
+
+  Scenario: Newer projects are not overwritten
+    Given "bracket.stl.gostl" was written by a newer GoSTL
+    When I open "bracket.stl"
+    Then a warning should be logged
+    And saving should fail without changing the file
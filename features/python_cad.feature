@@ -0,0 +1,84 @@
+@python
+Feature: CadQuery and build123d Scripts
+  As a user designing parts in Python with CadQuery or build123d
+  I want to open my scripts in GoSTL
+  So that I get the same live-preview loop as with OpenSCAD files
+
+  Background:
+    Given a Python environment with build123d or CadQuery is available
+
+  Scenario: Open a build123d script
+    Given "bracket.py" builds a part and calls "show(part)"
+    When I open "bracket.py"
+    Then the script should run in the configured Python environment
+    And the part should be displayed
+    And the info panel should show "bracket.py"
+
+  Scenario: Open a CadQuery script written for CQ-editor
+    Given "box.py" calls "show_object(result)" on a CadQuery workplane
+    When I open "box.py"
+    Then the box should be displayed
+
+  Scenario: Script without show calls
+    Given "plate.py" assigns a shape to "result" and shows nothing
+    When I open "plate.py"
+    Then the shape in "result" should be displayed
+
+  Scenario: Several shown objects
+    Given "assembly.py" calls "show(base, lid)"
+    When I open "assembly.py"
+    Then base and lid should be displayed together
+
+  Scenario: Scripts using the ocp_vscode viewer
+    Given "part.py" contains "from ocp_vscode import show, set_port" and "set_port(3939)"
+    When I open "part.py"
+    Then the script should run without a viewer connection
+    And the shown objects should be displayed in GoSTL
+
+  Scenario: Auto-reload on save
+    Given "bracket.py" is open
+    When I save a change to "bracket.py" in my editor
+    Then the script should run again in the background
+    And the camera should not move
+
+  Scenario: Local modules are watched
+    Given "bracket.py" contains "from parts import hinge"
+    And "parts/hinge.py" exists next to it
+    When I save a change to "parts/hinge.py"
+    Then "bracket.py" should be rendered again
+    And installed packages like "cadquery" should not be watched
+
+  Scenario: Printed output
+    Given "bracket.py" prints "volume: 1234"
+    When I open "bracket.py"
+    Then the warnings panel should list "volume: 1234"
+
+  Scenario: Script error
+    Given "bracket.py" raises an exception
+    When I open "bracket.py"
+    Then an error should show the Python traceback
+
+  Scenario: Nothing to show
+    Given "empty.py" neither shows an object nor assigns "result"
+    When I open "empty.py"
+    Then an error should explain to call show_object() or show() or assign "result"
+
+  Scenario: Choose the Python interpreter
+    When I choose ".venv/bin/python" of my project in Settings > Files
+    Then scripts should run with that interpreter
+    And GOSTL_PYTHON should take precedence over the setting
+
+  Scenario: Detect a virtual environment
+    Given no interpreter is configured
+    And the project folder above "parts/bracket.py" contains ".venv/bin/python"
+    When I open "parts/bracket.py"
+    Then the script should run with the project's virtual environment
+
+  Scenario: Python not found
+    Given no Python 3 interpreter can be found
+    When I open "bracket.py"
+    Then an error should explain how to configure the interpreter
+
+  Scenario: Command-line analysis
+    When I run "gostl analyze bracket.py"
+    Then the script should be rendered and its analysis printed
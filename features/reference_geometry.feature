@@ -0,0 +1,165 @@
+@measurement @reference-geometry
+Feature: Reference Geometry
+  As a user inspecting a part
+  I want to define datum planes, axes and points
+  So that I can measure features relative to stable references instead of raw mesh vertices
+
+  Background:
+    Given the application is running
+    And a 3D model is loaded
+    And the reference geometry panel is open
+
+  Scenario: Open the reference geometry panel
+    Given the reference geometry panel is closed
+    When I press Cmd+Shift+R or select Tools > Reference Geometry
+    Then the "REFERENCE GEOMETRY" panel should appear in the bottom-right corner
+
+  Scenario: Create a plane through three points
+    When I click "Plane (3 Points)"
+    And I click 3 points on the model
+    Then a plane named "Plane 1" should be created through the 3 points
+    And it should be drawn as an orange square outline with a normal stub
+
+  Scenario: Create a plane offset from a face
+    Given the face offset is set to 2 mm
+    When I click "Plane (Offset Face)"
+    And I click a face of the model
+    Then a plane parallel to the face should be created 2 mm outside of it
+
+  Scenario: Create an axis through two points
+    When I click "Axis (2 Points)"
+    And I click 2 points on the model
+    Then an axis named "Axis 1" should be created through both points
+    And it should be drawn as an orange line through the model
+
+  Scenario: Create the axis of a cylinder
+    When I click "Axis (Cylinder)"
+    And I click 3 points around a cylindrical feature
+    Then an axis should be created through the center of the fitted circle
+    And it should point along the cylinder axis
+
+  Scenario: Create a point on the model
+    When I click "Point"
+    And I click a point on the model
+    Then a point named "Point 1" should be created as an orange marker
+
+  Scenario Outline: Create a point from an intersection
+    Given the entities <entities> exist
+    When I select them in the entity list
+    And I click "Point (Intersect)"
+    Then a point should be created at <location>
+
+    Examples:
+      | entities             | location                              |
+      | an axis and a plane  | where the axis pierces the plane      |
+      | two axes             | the midpoint of their closest approach |
+      | three planes         | the common corner of the planes       |
+
+  Scenario: Intersection requires a compatible selection
+    Given only two parallel planes are selected
+    Then the "Point (Intersect)" button should be disabled
+    And the "Axis (Intersect)" button should be disabled
+
+  Scenario: Create an axis from two intersecting planes
+    Given two planes that are not parallel exist
+    When I select both planes in the entity list
+    And I click "Axis (Intersect)"
+    Then an axis should be created along the line where the planes meet
+    And it should be anchored near the origins of both planes
+
+  Scenario: Intersect distance measurements with a datum plane
+    Given a plane exists
+    And I have completed distance measurements
+    When I select only the plane in the entity list
+    And I click "Point (Segment ∩ Plane)"
+    Then a point should be created where each selected distance measurement crosses the plane
+    And all distance measurements should be used when none are selected
+    And measurements that do not reach the plane should be skipped
+
+  Scenario: Degenerate picks are rejected
+    When I create a plane from 3 collinear points
+    Then no plane should be created
+    And the panel should explain that the points are degenerate
+
+  Scenario: Cancel creation
+    Given a reference creation tool is collecting points
+    When I press ESC
+    Then the tool should be cancelled without creating an entity
+
+  Scenario: Use reference geometry as measurement endpoints
+    Given a reference point exists
+    And distance measurement mode is active
+    When I hover near the reference point
+    Then the hover point should snap to the reference point
+    And points on visible axes and plane origins should be snappable the same way
+
+  Scenario: References hidden behind the model do not capture picks
+    Given a reference point is behind the model surface under the cursor
+    When I hover the model surface
+    Then the hover point should stay on the surface
+
+  Scenario: Constrain a distance along a reference direction
+    Given a reference axis exists
+    And I have picked the first point of a distance measurement
+    When I click the ∥ button next to the axis
+    Then the measurement should be constrained along the axis direction
+    And the constraint indicator should show "∥" in orange
+    When I click the ∥ button again
+    Then the constraint should be removed
+
+  Scenario: Constrain a distance along a plane normal
+    Given a reference plane exists
+    And I have picked the first point of a distance measurement
+    When I click the ∥ button next to the plane
+    Then the measurement should be constrained along the plane normal
+
+  Scenario Outline: Measure between reference entities
+    Given the entities <entities> exist
+    When I select them in the entity list
+    And I click "Measure"
+    Then a measurement "<first> → <second>" should be listed in the panel
+    And it should report <values>
+    And an orange annotation line with a value label should be drawn in the scene
+
+    Examples:
+      | entities                  | first   | second  | values                                                  |
+      | two parallel planes       | Plane 1 | Plane 2 | the plane-to-plane distance and angle                   |
+      | two intersecting planes   | Plane 1 | Plane 2 | only the angle between the planes                       |
+      | two axes                  | Axis 1  | Axis 2  | the axis angle and their minimum distance               |
+      | a point and an axis       | Point 1 | Axis 1  | the distance from the point to the axis                 |
+      | an axis and a plane       | Axis 1  | Plane 1 | the angle, plus the distance when they are parallel     |
+
+  Scenario: Check concentricity of two bores
+    Given "Axis 1" was fitted to one bore
+    And "Axis 2" was fitted to a second bore
+    When I measure between "Axis 1" and "Axis 2"
+    Then the angle should show how far the bores are from parallel
+    And the distance should show the offset between the bore centers
+
+  Scenario: Check parallelism against a datum plane
+    Given "Plane 1" is the datum face
+    And "Plane 2" was created on the opposite face
+    When I measure between "Plane 1" and "Plane 2"
+    Then the distance should be measured from the origin of "Plane 2" to "Plane 1"
+    And the angle should show the deviation from parallel
+
+  Scenario: Reference measurements follow their entities
+    Given a measurement between "Plane 1" and "Plane 2" exists
+    When I hide "Plane 2"
+    Then the measurement annotation should be hidden
+    When I delete "Plane 2"
+    Then the measurement should be removed
+
+  Scenario: Manage reference entities
+    Given reference entities exist
+    When I click the eye icon next to an entity
+    Then the entity should be hidden and no longer snap measurement points
+    When I click the trash icon next to an entity
+    Then the entity should be removed
+
+  Scenario: Reference geometry survives reloads
+    Given reference entities exist
+    When the file is reloaded after a change
+    Then the reference entities should be kept
+    When I open a different file
+    Then the reference entities should be cleared
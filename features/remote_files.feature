@@ -0,0 +1,81 @@
+@file-handling @cli
+Feature: Remote Files
+  As a user reviewing models linked from issue trackers
+  I want to open http(s) and S3 URLs directly
+  So that I can inspect models without downloading them by hand
+
+  Scenario: Analyze a model from a URL
+    When I run "gostl analyze https://example.com/files/bracket.stl"
+    Then the model should be downloaded into the cache
+    And the analysis should show the file name "bracket.stl"
+
+  Scenario: Unchanged files are not downloaded again
+    Given "https://example.com/files/bracket.stl" was downloaded before with an ETag
+    When I run "gostl analyze https://example.com/files/bracket.stl" again
+    Then the request should send the ETag as If-None-Match and the date as If-Modified-Since
+    And on HTTP 304 the cached copy should be analyzed without downloading it
+
+  Scenario: Changed files replace the cached copy
+    Given the file on the server has changed
+    When I analyze the URL again
+    Then the new file should be downloaded and its ETag stored
+
+  Scenario: Offline
+    Given "https://example.com/files/bracket.stl" was downloaded before
+    And the server cannot be reached
+    When I analyze the URL
+    Then the cached copy should be analyzed
+    And "offline, using the cached copy" should be printed to standard error
+
+  Scenario: Download links without a file name
+    When I open "https://tracker.example.com/attachments/download/123"
+    Then the cached copy should be named after the server's suggested file name without its extension
+    And its format and compression should be detected from the content, e.g. "case.stl.gz"
+
+  Scenario Outline: Scripts and archives are not downloaded
+    When I open "https://example.com/files/<file>"
+    Then nothing should be downloaded
+    And an error should say that only STL and 3MF files are opened from URLs
+
+    Examples:
+      | file        |
+      | part.scad   |
+      | build.py    |
+      | plates.yaml |
+      | models.zip  |
+
+  Scenario Outline: S3 URLs
+    Given the environment <environment>
+    When I run "gostl analyze s3://models/parts/bracket.stl"
+    Then the model should be downloaded from "<endpoint>"
+
+    Examples:
+      | environment                                | endpoint                                                  |
+      | has no AWS settings                        | https://models.s3.amazonaws.com/parts/bracket.stl         |
+      | sets AWS_REGION=eu-central-1               | https://models.s3.eu-central-1.amazonaws.com/parts/bracket.stl |
+      | sets AWS_ENDPOINT_URL=http://localhost:9000 | http://localhost:9000/models/parts/bracket.stl            |
+
+  Scenario: Private S3 objects
+    When an S3 download is denied with HTTP 403
+    Then the error should suggest a presigned https URL
+
+  Scenario: Open a URL in the viewer
+    Given a model URL is on the clipboard
+    When I select File > Open URL...
+    Then the URL field should be prefilled with the copied URL
+    When I click "Open"
+    Then the model should be downloaded in the background and opened in a window
+    When the download fails
+    Then an alert "Cannot Open URL" should show the reason
+
+  Scenario: Open a URL from the command line
+    When I run "gostl open https://example.com/files/bracket.stl"
+    Then the cached copy should open in the viewer
+
+  Scenario: Cache location
+    Then downloads should be cached in ~/Library/Caches/GoSTL/Remote
+    And GOSTL_CACHE_DIR should override the location
+
+  Scenario: Watching needs a local file
+    When I run "gostl analyze --watch https://example.com/files/bracket.stl"
+    Then the command should fail with "--watch needs a local file."
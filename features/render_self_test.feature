@@ -0,0 +1,57 @@
+@cli @rendering
+Feature: Render Self-Test
+  As a developer changing shaders or clipping
+  I want renders of reference models compared with golden images
+  So that visible rendering regressions are caught before release
+
+  Scenario: Record goldens
+    When I run "gostl selftest-render --update"
+    Then the cube, steps and sphere reference cases should be rendered at 256×256 pixels on white
+    And each render should be saved as "<case>.png" in "~/Library/Application Support/GoSTL/RenderGoldens"
+
+  Scenario: Compare with goldens
+    Given goldens were recorded on this machine
+    When I run "gostl selftest-render"
+    Then each case should be listed with "ok", the number and share of differing pixels and the largest ΔE
+    And the exit status should be 0
+
+  Scenario: Rendering regression
+    Given a shader change darkened the model
+    When I run "gostl selftest-render -o ./failures"
+    Then the affected cases should be listed as "FAILED"
+    And "./failures" should contain the render and "<case>-diff.png" with the differing pixels in red
+    And the exit status should be 1
+
+  Scenario: Missing goldens
+    Given no goldens were recorded
+    When I run "gostl selftest-render"
+    Then every case should be listed as "MISSING"
+    And the output should suggest "gostl selftest-render --update"
+    And the exit status should be 1
+
+  Scenario: Selected cases and tolerances
+    When I run "gostl selftest-render --case sphere-cutaway --goldens Tests/Goldens --tolerance 0.5"
+    Then only the cutaway case should be compared, against "Tests/Goldens/sphere-cutaway.png"
+    And up to 0.5% differing pixels should pass
+
+  Scenario: Test suite
+    Given goldens in "GoSTL-Swift/Tests/Goldens"
+    When I run "swift test --filter RenderGoldenTests"
+    Then every reference case should be compared with its golden
+    And cases without a golden should be skipped until recorded with GOSTL_UPDATE_GOLDENS=1
+
+  Scenario: Perceptual diff of own images
+    When I run "gostl imagediff before.png after.png --diff diff.png"
+    Then the number and share of differing pixels and the largest ΔE should be printed
+    And "diff.png" should show the differing pixels in red on a faded copy of "before.png"
+    And the exit status should be 1 if more than --tolerance percent (default 0.1) of the pixels differ
+
+  Scenario: Differences people do not see
+    Given two renders that differ by a color difference below ΔE 2.3
+    And two renders whose anti-aliased edges are shifted by one pixel
+    When I compare them with "gostl imagediff"
+    Then no pixels should be reported as different
+
+  Scenario: Images of different sizes
+    When I run "gostl imagediff small.png large.png"
+    Then the error should be "Image sizes differ: 128×128 and 256×256"
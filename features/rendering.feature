@@ -26,6 +26,17 @@ Feature: 3D Rendering
     And surfaces facing the light should be brighter
     And surfaces facing away should be in shadow
 
+  Scenario: Vertex colors
+    Given a multi-color 3MF file or an analysis visualization such as Color by Height
+    Then each vertex should be drawn in its own color, lit in the shader like the material color
+    And colors should blend smoothly across a face whose corners differ
+    And uncolored and white 3MF faces should use the selected material's color
+
+  Scenario: Vertex colors while slicing
+    Given Color by Height is on
+    When the model is sliced
+    Then each remaining face should keep the mean color of its corners
+
   Scenario: Transparent rendering
     When transparent elements are present (like cutting planes)
     Then they should be rendered with proper transparency
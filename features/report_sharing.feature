@@ -0,0 +1,82 @@
+@cli @export
+Feature: Report Sharing
+  As a user who found something while measuring
+  I want to serve a web view of the model from my machine
+  So that a colleague can look at it on their own device without installing anything
+
+  Scenario: Share a model
+    When I run "gostl share bracket.stl"
+    Then a web server should start on a free port
+    And the URLs with the machine's network addresses and host name should be printed
+    And a QR code for the first URL should be printed in black on white
+    And the output should say that the page is not password protected
+
+  Scenario: Shared page
+    Given "gostl share bracket.stl" is running
+    When I open the printed URL in a browser
+    Then I should see the model in 3D
+    And I should be able to rotate it by dragging, zoom by scrolling or pinching and reset it by double-clicking
+    And the side panel should list the model's dimensions, volume, surface area and triangle count
+    And the page should not load anything from the internet
+
+  Scenario: Share measurements and annotations
+    Given "bracket.json" is a measurement set exported for "bracket.stl"
+    When I run "gostl share bracket.stl --measurements bracket.json"
+    Then the measurements should be drawn as lines labeled with their values
+    And the side panel should list each measurement with its value and note
+    And annotations of the set and pinned annotations of the model should be shown as numbered pins
+
+  Scenario: Password protection
+    Given the environment variable "GOSTL_SHARE_PASSWORD" is "secret"
+    When I run "gostl share bracket.stl"
+    Then the browser should ask for a user name and password
+    And only user "gostl" with password "secret" should see the page
+    And "--user" should change the user name
+
+  Scenario: Password prompt
+    When I run "gostl share bracket.stl --ask-password"
+    Then I should be asked for the password without it being echoed
+    And the page should be protected with the typed password
+
+  Scenario: Password on the command line
+    When I run "gostl share bracket.stl --password secret"
+    Then the page should be protected with password "secret"
+    And a warning should say that the password is visible in ps and the shell history
+
+  Scenario: Password guessing
+    Given "gostl share bracket.stl --ask-password" is running with password "secret"
+    When a client sends a wrong password
+    Then the server should answer after a delay of one second
+    And after 5 wrong passwords in a row the client should get "429 Too Many Requests" for a minute
+
+  Scenario: Stalled connections
+    Given "gostl share bracket.stl" is running
+    When a client connects and does not finish its request within 10 seconds
+    Then the connection should be closed
+
+  Scenario: Expiry
+    When I run "gostl share bracket.stl --expires 30m"
+    Then the output should say "Expires in 30 min."
+    And after 30 minutes the server should stop and print "Share expired after 30 min."
+    And "90s" and "2h" should be accepted as well, plain numbers as minutes
+
+  Scenario: Until stopped
+    When I run "gostl share bracket.stl" without "--expires"
+    Then the server should run until I press Ctrl-C
+
+  Scenario: Request log
+    Given "gostl share bracket.stl" is running
+    When a device opens the page
+    Then the client address, request line and status should be printed to standard error
+
+  Scenario: Other requests
+    Given "gostl share bracket.stl" is running
+    Then paths other than "/" and "/index.html" should answer 404
+    And methods other than GET and HEAD should answer 405
+    And with a password, every request without valid credentials should answer 401
+
+  Scenario: Remote models and without QR code
+    When I run "gostl share https://example.com/files/bracket.stl --no-qr --port 8080"
+    Then the model should be downloaded like for "gostl analyze"
+    And the server should listen on port 8080
+    And no QR code should be printed
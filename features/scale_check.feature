@@ -0,0 +1,66 @@
+@model @scale
+Feature: Model Scale Check
+  As a user
+  I want unit mistakes of imported models to be caught on load
+  So that I do not waste time measuring a model at the wrong scale
+
+  Background:
+    Given the application is running
+    And "Check model scale on load" is enabled in Settings > Display
+
+  Scenario: Model exported in meters
+    When I open a model that is 0.08 mm tall
+    Then a scale prompt should say the model was probably exported in meters or inches
+    And it should offer "×1000 (Meters)" and "×25.4 (Inches)"
+    When I click "×1000 (Meters)"
+    Then the model should be 80 mm tall
+    And the camera should frame the scaled model
+    And the prompt should disappear
+
+  Scenario: Model exported in micrometers
+    When I open a model that is 8000 mm tall
+    Then the scale prompt should offer "÷1000 (Micrometers)"
+    When I click it
+    Then the model should be 8 mm tall
+
+  Scenario: Model exported in inches
+    When I open a model of 4 × 2.5 × 0.75 mm
+    Then the scale prompt should say the dimensions are fractions of an inch
+    And it should offer "×25.4 (Inches)"
+    When I click it
+    Then the model should be 101.6 × 63.5 × 19.05 mm
+
+  Scenario: Plausible models are not flagged
+    When I open a model of 120 × 80 × 35 mm
+    Then no scale prompt should be shown
+    And a 10 × 10 × 10 mm calibration cube should not be flagged
+
+  Scenario: Keep the model as it is
+    Given the scale prompt is shown
+    When I click "Keep" or the close button
+    Then the prompt should disappear
+    And it should not appear again when the file reloads
+
+  Scenario: The fix is non-destructive
+    Given I applied "×1000 (Meters)"
+    Then the file on disk should be unchanged
+    And the model should be marked as modified so File > Save As... can write the scaled model
+    When the file changes on disk and reloads
+    Then the reloaded model should be scaled ×1000 as well
+    And measurements taken at the wrong scale should be cleared when the fix is applied
+
+  Scenario: Scale from the menu
+    When I choose Tools > Model Scale > "×25.4 (Inches)"
+    Then the model should be scaled by 25.4 without a prompt
+    When I choose Tools > Model Scale > "Reset Scale"
+    Then the model should have the size in the file again
+
+  Scenario: Turn the check off
+    Given "Check model scale on load" is disabled in Settings > Display
+    When I open a model that is 0.08 mm tall
+    Then no scale prompt should be shown
+
+  Scenario: New file
+    Given I applied a scale fix
+    When I open another file
+    Then it should be shown at the size in its file
@@ -0,0 +1,67 @@
+@scripting
+Feature: Scripting
+  As a user with shop-specific checks
+  I want to write analysis scripts against loaded models
+  So that I can run custom checks without recompiling gostl
+
+  Scenario: Run a script headless
+    Given a script "inspect.js" that prints the triangle count of gostl.model
+    When I run "gostl script inspect.js model.stl"
+    Then the viewer should not open
+    And the script output should be printed to the terminal
+    And the exit status should be 0
+
+  Scenario: Script checks control the exit status
+    Given a script that calls gostl.fail("too large")
+    When I run it with "gostl script"
+    Then "FAIL: too large" should be printed
+    And the exit status should be 1
+
+  Scenario: Write results as JSON
+    Given a script that records gostl.result("height", model.dimensions.z)
+    When I run "gostl script inspect.js model.stl --output results.json"
+    Then "results.json" should contain the named results, measurements and failures
+    And it should report whether the script passed
+
+  Scenario: Create measurements from a script
+    Given a script that calls gostl.distance with two points
+    When the script runs
+    Then a distance measurement should be recorded
+    And the call should return the distance in mm
+
+  Scenario: Load additional models
+    Given a script that calls gostl.load("reference.stl")
+    When the script runs
+    Then the file should be loaded relative to the script
+    And its triangles should be available through gostl.triangles
+
+  Scenario: Script errors are reported
+    Given a script with a syntax error on line 3
+    When I run it with "gostl script"
+    Then an error mentioning line 3 should be printed
+    And the exit status should be non-zero
+
+  Scenario: Run a script from the viewer
+    Given a 3D model is loaded
+    When I select Tools > Run Script... and choose a script
+    Then the script should run as a background job against the loaded model in its original coordinates
+    And measurements created by the script should appear in the scene
+    And the last lines of the script output should be shown in a dialog
+
+  Scenario: Stop a script stuck in a loop
+    Given a script that never finishes is running from the viewer
+    And it runs in a separate "gostl script" process
+    When I cancel its job
+    Then the script process should be terminated
+    And the script should stop within a fraction of a second
+    And the viewer should stay responsive while it runs
+
+  Scenario: Results must be JSON values
+    Given a script that calls gostl.result("r", 0 / 0)
+    When I run it with "gostl script"
+    Then an error should say that results need a finite number, a string or a boolean
+    And gostl should not crash
+
+  Scenario: Launch the viewer without a subcommand
+    When I run "gostl model.stl"
+    Then the viewer should open the model
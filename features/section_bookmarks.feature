@@ -0,0 +1,62 @@
+@slicing @sections
+Feature: Section Bookmarks
+  As a user reviewing the same cross-sections again and again
+  I want to save slicing configurations under a name
+  So that I can recall them instantly and include them in reports
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Save the current section
+    Given the model is sliced at Z 5 mm with filled cross-sections
+    When I select View > Section Bookmarks
+    And I enter "Top half" and click "Save Current"
+    Then "Top half" should be listed in the SECTIONS panel
+    And its summary should read "Z …5.000 mm" in file coordinates
+
+  Scenario: Save a cutaway view
+    Given the cutaway follows the camera
+    When I save the current section as "Inside"
+    Then the bookmark should store the camera and the cutaway depth
+    And recalling it should show the same arbitrary cut plane
+
+  Scenario: Recall a section
+    Given the sections "Top half" and "Inside" are saved
+    When I click the recall button of "Top half"
+    Then the slice bounds, fill setting, cutaway and camera should be restored
+    And the slicing panel should be shown
+
+  Scenario: Recall a view saved without slicing
+    Given a section was saved while slicing was off
+    When I recall it
+    Then slicing should be turned off and only the camera and cutaway restored
+
+  Scenario: Saving under an existing name replaces the section
+    Given a section "Top half" is saved
+    When I save the current section as "Top half" again
+    Then there should still be one section named "Top half" with the new configuration
+
+  Scenario: Rename and delete sections
+    When I edit the name of a section and press Return
+    Then the section should be renamed
+    When I click its trash button
+    Then the section should be removed
+
+  Scenario: Sections are stored in the sidecar
+    When I save a section of "housing.stl"
+    Then it should be written to "housing.stl.gostl"
+    And it should be listed again after reopening the file
+    And sidecars written before sections existed should still load
+
+  Scenario: Sections survive reloads and recentering
+    Given a section of a model far from the origin is saved
+    When the model is reloaded after a change
+    Then recalling the section should cut at the same file coordinates
+    And bounds beyond the new model should be clamped to it
+
+  Scenario: Sections in the inspection report
+    Given the sections "Top half" and "Inside" are saved
+    When I select File > Export Inspection Report... and save "housing-inspection.txt"
+    Then "housing-inspection-section-1.png" and "housing-inspection-section-2.png" should be written next to it
+    And the report should have a SECTIONS section listing each section with its image
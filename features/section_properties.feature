@@ -0,0 +1,43 @@
+@analysis @cli
+Feature: Cross-Section Properties
+  As a user designing printed beams and brackets
+  I want the area and second moment of area of cross sections along an axis
+  So that I can estimate bending stiffness and find the weakest section without FEA
+
+  Scenario: Sweep a beam along an axis
+    When I run "gostl sections beam.stl --axis z --step 1"
+    Then one CSV row per station at every multiple of 1 mm strictly inside the model should be printed
+    And the columns should be "z, area, centroid_x, centroid_y, i_x, i_y, i_xy, i_min, i_max, j"
+    And areas should be in mm² and second moments of area in mm⁴
+
+  Scenario: Rectangular section
+    Given "beam.stl" is a 20 × 10 mm bar, 100 mm long along Z
+    When I run "gostl sections beam.stl --axis z --step 10"
+    Then every station should have an area of 200 mm²
+    And i_x should be 20 × 10³ / 12 and i_y 10 × 20³ / 12
+    And i_xy should be 0
+
+  Scenario: Hollow sections
+    Given a tube with a bore along the sweep axis
+    Then the bore should be subtracted from the area and the second moments of area
+
+  Scenario: Automatic station spacing
+    When I run "gostl sections beam.stl" without --step
+    Then a round step (1, 2 or 5 × 10ⁿ mm) giving about 25 stations should be used
+
+  Scenario: Gaps along the axis
+    Given a model made of two parts separated along the sweep axis
+    Then stations in the gap should have zero area and empty centroid columns
+
+  Scenario: Write to a file
+    When I run "gostl sections bracket.stl --axis x --out sections.csv"
+    Then "sections.csv" should contain the stations
+    And the station with the smallest principal second moment of area should be printed as the weakest
+
+  Scenario: European spreadsheets
+    When I run "gostl sections beam.stl --delimiter semicolon --decimal-separator comma"
+    Then the CSV should use semicolons between fields and decimal commas
+
+  Scenario: Invalid step
+    When I run "gostl sections beam.stl --step 0"
+    Then the command should fail with a usage error
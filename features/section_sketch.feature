@@ -0,0 +1,58 @@
+@cli @export @openscad
+Feature: Section Sketch Export
+  As a user re-modeling a legacy part
+  I want a cross-section as clean lines and arcs
+  So that I can rebuild the profile in CAD instead of tracing a dense polyline
+
+  Scenario: Sketch a section from the command line
+    Given "bracket.stl" is a 40 x 20 mm plate with rounded ends and a 6.4 mm hole
+    When I run "gostl sketch bracket.stl --axis z --at 2"
+    Then "bracket-section.dxf" should be written next to the model
+    And the output should report "2 outlines at Z = 2 mm: 2 lines, 2 arcs, 1 circles"
+
+  Scenario: Fitting lines and arcs
+    When a section outline is sketched
+    Then each run of cut points within the tolerance of a line through its ends should become one line
+    And each run within the tolerance of a least-squares circle should become one arc
+    And arcs should start and end exactly on the neighboring elements
+    And an outline that is round all the way should become a circle
+    And coarse polygons like hexagon nuts should stay polygons
+    And points where the plane cuts across flat facets should not split lines
+
+  Scenario: Tolerance
+    When I run "gostl sketch scan.stl --tolerance 0.2"
+    Then noisy scanned outlines should be fitted with fewer, longer elements
+
+  Scenario: DXF output
+    When a sketch is written as DXF
+    Then it should use R12 LINE, ARC and CIRCLE entities in millimeters
+    And outer outlines should be on layer "PROFILE" and holes on layer "HOLES"
+
+  Scenario: OpenSCAD profile
+    When I run "gostl sketch bracket.stl -o bracket-profile.scad"
+    Then the file should define "module bracket_section()"
+    And arcs should be generated with "sketch_arc()" following $fn
+    And holes should be subtracted from the outline around them with difference()
+    And full circles should use circle()
+
+  Scenario: Plane axes
+    When I sketch a section along X
+    Then the drawing's X and Y should be the model's Y and Z
+    And the section position should default to the middle of the model
+
+  Scenario: Plane misses the model
+    When I run "gostl sketch bracket.stl --at 100"
+    Then it should fail with "The plane Z = 100 mm does not cut the model into closed outlines"
+
+  Scenario: Open mesh
+    Given the section crosses a hole in the mesh
+    When I sketch the section
+    Then the closed outlines should be exported
+    And a warning should report the open outlines that were skipped
+
+  Scenario: Export from the viewer
+    Given a model is loaded and a slicing plane cuts into it
+    When I select File > Export Section Sketch...
+    Then a save dialog should suggest "<model>-section-z.dxf"
+    And saving as .scad should write the OpenSCAD profile
+    And the menu item should be disabled while no slicing plane cuts the model
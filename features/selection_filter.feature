@@ -0,0 +1,54 @@
+@measurement @snapping
+Feature: Selection Filter
+  As a user measuring dense models
+  I want to choose which geometry picked points snap to
+  So that measurement endpoints and cut vertices do not steal picks meant for mesh vertices
+
+  Background:
+    Given the application is running
+    And a model is loaded
+    And a distance measurement is active
+
+  Scenario: Everything is a snap target by default
+    When I open Tools > Snap To
+    Then "Mesh Vertices", "Feature Edges", "Measurement Points" and "Cut Vertices" should be checked
+    And the measurement panel should show the chips "Vertices", "Edges", "Points" and "Cuts"
+
+  Scenario: The closest point target wins
+    Given a mesh vertex and a measurement endpoint are inside the snap radius
+    When I hover between them
+    Then the point should snap to the one closer to the cursor
+
+  Scenario: Edges are used only without point targets
+    Given no vertex, measurement point or cut vertex is inside the snap radius
+    And a feature edge is inside it
+    When I hover near the edge
+    Then the point should snap onto the edge
+    And edges between flat neighboring faces should not count
+
+  Scenario: Exclude measurement points
+    Given measurement endpoints lie next to mesh vertices
+    When I click the "Points" chip
+    Then picks should snap to the mesh vertices only
+    And right-clicking near an endpoint should show the surface menu instead of the point menu
+
+  Scenario: Cut vertices while slicing
+    Given the slicing panel cuts the model
+    When I hover a vertex of the section outline
+    Then the point should snap to it, even if the model in front of the section is sliced away
+    When I turn off "Cut Vertices" in Tools > Snap To
+    Then section outline vertices should no longer be snap targets
+
+  Scenario: Exclude mesh vertices
+    When I turn off "Mesh Vertices"
+    Then picks should not snap to mesh vertices
+    And Tab should offer only the surface point under the view center
+
+  Scenario: Nothing selected
+    When I turn off every snap target
+    Then picked points should stay where the cursor hits the surface
+
+  Scenario: The filter belongs to the window
+    Given I turned off "Feature Edges" in one window
+    When I open another model in a new window
+    Then that window should snap to all targets
@@ -0,0 +1,56 @@
+@session @recording
+Feature: Session Recording and Replay
+  As a user preparing an inspection review
+  I want to record and replay my camera movements and measurements
+  So that I can walk others through the findings
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Record a session
+    When I select "Start Recording" from the Tools > Session Recording menu
+    Then a "Recording session" indicator should appear at the top of the view
+    When I orbit the camera and measure a distance
+    And I select "Stop Recording"
+    Then the recording should contain timestamped camera keyframes
+    And it should contain a measurement snapshot for every change
+
+  Scenario: Save a session
+    Given I have stopped a recording
+    When I select "Save Recording..."
+    Then a .gostlsession file should be written
+    And camera targets and measurement points should be stored in file coordinates
+
+  Scenario: Replay a session
+    When I select "Replay Session..." and choose a .gostlsession file
+    Then the camera should follow the recorded path with interpolation between keyframes
+    And measurements should appear and disappear at their recorded times
+    And the indicator should show the replay progress
+
+  Scenario: Replay a session after reload
+    Given a session recorded on a model with large coordinates
+    When I reload the model and replay the session
+    Then the camera and measurements should match the original recording
+
+  Scenario: Render a session to video
+    When I select "Replay Session to Video..." and choose a session and an output file
+    Then the session should replay at a fixed 30 frames per second
+    And every rendered frame should be written to an H.264 .mp4 file
+    And the video should be revealed in Finder when the replay finishes
+
+  Scenario: Stop a replay
+    Given a session is replaying
+    When I select "Stop Replay"
+    Then the camera and measurements should be back to how they were before the replay
+    And a video being rendered should be finished with the frames so far
+
+  Scenario: Measurements survive a replay
+    Given I have taken measurements
+    When I replay a session to the end
+    Then my measurements and view should be restored
+
+  Scenario: Recording stops when a replay starts
+    Given a session is being recorded
+    When I start replaying a session
+    Then the recording should stop and be kept for saving
@@ -0,0 +1,67 @@
+@analysis @comparison
+Feature: Side-by-Side Comparison
+  As a user reviewing two models or two revisions of a part
+  I want to see them next to each other through the same camera
+  So that I can spot differences visually before diffing them numerically
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Open a second model side by side
+    When I select "Compare Side by Side..." from the Tools menu and choose "bracket-v2.stl"
+    Then the window should split into two panes
+    And the right pane should show "bracket-v2.stl" with a "COMPARISON" header
+    And a background job "Registering comparison..." should run
+
+  Scenario: Synchronized cameras
+    Given a model is shown side by side
+    When I rotate, pan or zoom in either pane
+    Then both panes should show the same viewpoint
+    And camera presets, inertia and orbit transitions should apply to both panes
+
+  Scenario: Register the second model onto the main model
+    Given "bracket-v2.stl" was exported rotated by 10° and moved 100 mm along X
+    When the registration finishes
+    Then the second model should be moved onto the main model
+    And the comparison header should show "Registered: 10.00° rotation" and the RMS distance
+
+  Scenario: Keep the file coordinates
+    Given a model is shown side by side
+    When I uncheck "Register onto the main model"
+    Then the second model should be shown in its own file coordinates
+    And the comparison header should show "File coordinates (not registered)"
+
+  Scenario: Selection in the main pane highlights the same region
+    Given a registered model is shown side by side
+    When I select triangles on the main model
+    Then the corresponding triangles of the second model should be highlighted in cyan
+
+  Scenario: Selection in the comparison pane
+    Given a registered model is shown side by side
+    When I click a face in the comparison pane
+    Then the corresponding triangles of the main model should be selected
+    And the clicked region should be highlighted in both panes
+    When I click the same face again
+    Then those triangles should be deselected
+
+  Scenario: Changed regions have no counterpart
+    Given the second model has a boss that the main model does not have
+    When I click the boss in the comparison pane
+    Then nothing should be selected
+    And the console should report that the surfaces differ there
+
+  Scenario: Main model reloads
+    Given a model is shown side by side
+    When the main model file changes on disk
+    Then the second model should be registered again against the reloaded model
+
+  Scenario: Close the comparison
+    Given a model is shown side by side
+    When I select "Close Comparison" from the Tools menu
+    Then the window should show the main model in a single pane again
+
+  Scenario: Opening another file closes the comparison
+    Given a model is shown side by side
+    When I open a different file
+    Then the comparison pane should be closed
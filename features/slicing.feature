@@ -52,6 +52,9 @@ Feature: Model Slicing and Cross-Sections
     When I enable "Fill Cross-Sections"
     Then the cut areas should be filled with a solid surface
     And the fill should show the internal cross-section
+    And the fill should use a darker shade of the axis color
+    And holes and islands in the cross-section should be left open or filled using the even-odd rule
+    And the fill should be clipped by the bounds of the other axes
 
   Scenario: Show cutting planes
     Given slicing is active on at least one axis
@@ -0,0 +1,61 @@
+@measurement @snapping
+Feature: Vertex Snap Radius
+  As a user
+  I want to control how far picks reach for a mesh vertex
+  So that I understand why a vertex is or isn't being picked
+
+  Background:
+    Given the application is running
+    And a model is loaded
+
+  Scenario: Snap radius circle
+    When I start a distance measurement
+    Then a faint circle should be drawn around the cursor
+    And its radius should be the snap radius from Settings > Navigation (10 pt by default)
+
+  Scenario: Snap to a vertex inside the circle
+    Given a distance measurement is active
+    When I hover the surface with a vertex inside the circle
+    Then the picked point should snap to the closest vertex
+    When no vertex is inside the circle
+    Then the point should stay on the surface under the cursor
+
+  Scenario: The radius stays the same on screen at any zoom
+    Given a distance measurement is active
+    When I zoom in on a small detail
+    Then the circle should keep its size on screen
+    And vertices should snap within the circle, not within a fixed distance in mm
+
+  Scenario: Change the radius with the keyboard
+    Given a distance measurement is active
+    When I press "+"
+    Then the snap radius should grow by 2 pt up to 40 pt
+    When I press "-"
+    Then the snap radius should shrink by 2 pt
+    And the circle should follow the new radius
+
+  Scenario: Turn vertex snapping off
+    When I set the snap radius to "Off" in Settings > Navigation
+    Then picked points should stay where the cursor hits the surface
+    And no circle should be drawn
+
+  Scenario: Hide the circle
+    When I turn off "Show snap radius around the cursor" in Settings > Navigation
+    Then no circle should be drawn
+    And picks should still snap within the radius
+
+  Scenario Outline: No circle without vertex snapping
+    When <mode> is active
+    Then no circle should be drawn
+
+    Examples:
+      | mode               |
+      | grid snapping      |
+      | pick refinement    |
+      | triangle selection |
+
+  Scenario: Minus while typing a length
+    Given a distance constraint is waiting for a typed length
+    When I press "-"
+    Then the typed length should change its direction
+    And the snap radius should not change
@@ -0,0 +1,41 @@
+@model @analysis
+Feature: Stability Check
+  As a maker of freestanding figures and fixtures
+  I want to know whether a model stands on its bottom face without tipping over
+  So that I can widen the base or move mass down before printing
+
+  Background:
+    Given the application is running
+    And a watertight model is loaded
+
+  Scenario: Stable model in the info panel
+    Given a 20 × 10 × 40 mm tower standing upright
+    Then the info panel should show "Stability: Stable, 14.0° tilt"
+    # atan(5 mm to the nearest base edge / 20 mm center of mass height)
+
+  Scenario: Model that tips over
+    Given a figure whose center of mass lies outside its base
+    Then the info panel should show "Stability: Tips over"
+
+  Scenario: Support polygon of the resting face
+    Given a model resting on several feet
+    Then the vertices within 0.05 mm of the lowest point should count as touching the ground
+    And the support polygon should be the convex hull of all feet
+    And the model should be stable while its center of mass lies over that polygon
+
+  Scenario: Stability on the command line
+    When I run "gostl analyze figure.stl --stability"
+    Then the output should include Stable "yes" or "no"
+    And the stability margin in mm from the center of mass to the nearest base edge (negative outside)
+    And the tilt angle in degrees before the model tips over
+    And the height of the center of mass above the resting plane
+
+  Scenario: Stability as a CI condition
+    When I run "gostl assert figure.stl --stable --min-tilt 10"
+    Then the "stable" check should fail if the model tips over
+    And the "min-tilt" check should fail if the model tips over at less than 10°
+
+  Scenario: Model without volume
+    Given an open surface without enclosed volume
+    Then no stability should be shown in the info panel
+    And "gostl assert --stable" should fail with "model has no volume"
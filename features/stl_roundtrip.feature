@@ -0,0 +1,39 @@
+@file @stl
+Feature: STL Header and Attribute Preservation
+  As a user
+  I want saving a binary STL to keep the bytes other tools wrote into it
+  So that color-coded facets and header tags survive leveling, scaling or alignment
+
+  Background:
+    Given the application is running
+    And I have opened a binary STL whose header reads "COLOR=" followed by a default color
+    And its facets carry non-zero attribute bytes (e.g. VisCAM/SolidView colors)
+
+  Scenario: Header and attributes survive a transform
+    When I level the model and choose File > Save As...
+    Then the saved file should have the original 80-byte header
+    And every facet should keep its attribute value
+
+  Scenario: Unmodified round-trip
+    When I save the model without changing it
+    Then the saved file should be byte-identical to the original
+
+  Scenario: Scale fix keeps the metadata
+    When I apply the "×25.4 (Inches)" scale fix and save
+    Then the header and attribute bytes should be unchanged
+
+  Scenario: Clearing the metadata explicitly
+    When I choose File > Save As Without STL Header/Attributes...
+    Then the header should read "GoSTL Export - <name>"
+    And every attribute value should be 0
+
+  Scenario: Files without a binary header
+    Given I have opened an ASCII STL, a 3MF or a rendered OpenSCAD file
+    When I save it as STL
+    Then the header should read "GoSTL Export - <name>"
+    And every attribute value should be 0
+
+  Scenario: Reading the values from a script
+    Given a script loads the binary STL
+    Then the model object should expose "header" as text and "headerBytes" as 80 numbers
+    And every entry of gostl.triangles(model) should have an "attribute" value
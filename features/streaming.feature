@@ -0,0 +1,64 @@
+@performance @large_files
+Feature: Streaming Large Models
+  As a user inspecting scans of many gigabytes
+  I want files larger than the memory to open as a navigable proxy
+  So that I can look around the whole scan and see details where I zoom in
+
+  Background:
+    Given the application is running
+    And "Stream large STL files" is enabled in the Files settings
+
+  Scenario: Open a binary STL larger than an eighth of the memory
+    When I open a binary STL file larger than an eighth of the physical memory
+    Then "Indexing" should run as a background job with progress
+    And the file should be split into spatial chunks in a temporary chunk file
+    And a decimated proxy of the whole model should be shown
+    And a banner should show the full triangle count
+
+  Scenario: Full resolution near the camera
+    Given a streamed model is open
+    When I zoom in on a part of the model
+    Then the chunks nearest to the camera should be read from the chunk file in the background
+    And they should be drawn at full resolution instead of the proxy
+    And chunks outside the view should not be drawn
+    And the banner should show how many chunks in view are at full resolution and their memory
+
+  Scenario: Memory budget
+    Given a streamed model is open
+    And the full-resolution memory is set to 1024 MB
+    When I move around the model
+    Then the full-resolution chunks should never use more than 1024 MB of GPU memory
+    And the chunks drawn longest ago should be released first
+
+  Scenario: Measuring a streamed model
+    Given a streamed model is open
+    When I measure on the model
+    Then the measurement should snap to the proxy
+    And the banner should say that measurements use the proxy
+
+  Scenario: Changing a streamed model
+    Given a streamed model is open
+    When I level or align the model
+    Then only the changed proxy should be shown
+    And the banner should say that full resolution is paused until the file is reopened
+
+  Scenario: Saving a streamed model
+    Given a streamed model is open
+    When I save the model
+    Then an error should say that a streamed model cannot be saved
+
+  Scenario: Files that are loaded as a whole
+    When I open an ASCII STL, a compressed STL or any other format
+    Then the model should be loaded as a whole, whatever its size
+
+  Scenario: No automatic reload
+    Given a streamed model is open
+    When the file changes on disk
+    Then the model should not be reloaded automatically
+    When I reload the model
+    Then the file should be indexed again
+
+  Scenario: Turn streaming off
+    Given "Stream large STL files" is disabled in the Files settings
+    When I open a large binary STL file
+    Then the model should be loaded as a whole
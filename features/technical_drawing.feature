@@ -0,0 +1,61 @@
+@technical_drawing @cli
+Feature: Technical Drawing Export
+  As a user documenting a part for manufacturing or review
+  I want standard 2D views with dimensions generated from the model
+  So that I get a drawing sheet without redrawing the part in a CAD tool
+
+  Background:
+    Given a model file "bracket.stl" measuring 40 x 20 x 30 mm
+
+  Scenario: Export a drawing sheet
+    When I run "gostl drawing bracket.stl"
+    Then "bracket.svg" should be written as an A4 landscape sheet in millimeters
+    And it should show the front, top, right and isometric views in third-angle projection
+    And the top view should be above the front view and the right view to its right
+    And the output path and the chosen scale should be printed
+
+  Scenario: Hidden lines are removed
+    When I run "gostl drawing bracket.stl"
+    Then each view should show only the silhouette and feature edges facing the viewer
+    And edges covered by other parts of the model should be omitted or cut where they disappear
+
+  Scenario: Overall dimensions
+    When I run "gostl drawing bracket.stl"
+    Then the front view should be dimensioned with the width "40" below and the height "30" to the left
+    And the right view should be dimensioned with the depth "20" below
+    And the dimension lines should have arrowheads and extension lines
+
+  Scenario: Title block
+    When I run "gostl drawing bracket.stl"
+    Then the title block should show "bracket.stl", the scale, "Dimensions in mm" and "Third-angle projection"
+
+  Scenario: Standard scale that fits the sheet
+    Given a model file "housing.stl" measuring 200 x 150 x 100 mm
+    When I run "gostl drawing housing.stl"
+    Then the scale should be "1:2"
+    And the isometric view should be shrunk if needed to fit its corner
+
+  Scenario: Choose scale and views
+    When I run "gostl drawing bracket.stl --scale 1:1 --view front --view right"
+    Then only the front and right views should be drawn at 1:1
+
+  Scenario Outline: Output format
+    When I run "gostl drawing bracket.stl <options>"
+    Then "<file>" should be written as <format>
+
+    Examples:
+      | options                    | file         | format |
+      | -o sheet.pdf               | sheet.pdf    | PDF    |
+      | -o sheet.svg               | sheet.svg    | SVG    |
+      | --format pdf               | bracket.pdf  | PDF    |
+
+  Scenario: Invalid options
+    When I run "gostl drawing bracket.stl -o sheet.png"
+    Then an error should ask for an .svg or .pdf output file or --format
+    When I run "gostl drawing bracket.stl --scale 0"
+    Then an error should explain the expected scale format
+
+  Scenario: Drawing an OpenSCAD file
+    When I run "gostl drawing part.scad"
+    Then the file should be rendered with OpenSCAD first
+    And "part.svg" should be written
@@ -0,0 +1,48 @@
+@file-handling @temporary-files
+Feature: Temporary Files
+  As a user
+  I want rendered intermediate files to be cleaned up automatically
+  So that my temp folder and project folders do not fill with orphaned files
+
+  Background:
+    Given the application is running
+
+  Scenario: Per-process temporary folder
+    When I open an OpenSCAD or go3mf file
+    Then rendered STL and 3MF files should be written to "session-<pid>" inside the temporary files folder
+    And no rendered STL files should be written next to the source file
+
+  Scenario: Cleanup on quit
+    Given I have rendered OpenSCAD files in this session
+    When I quit GoSTL
+    Then the folder of this process should be removed
+
+  Scenario: Cleanup after a crash
+    Given a previous GoSTL process crashed and left its "session-<pid>" folder
+    When GoSTL or a gostl command starts
+    Then folders of processes that are no longer running should be removed
+    And folders of other running GoSTL processes should be kept
+
+  @openscad
+  Scenario: Leftover CSG files next to the source
+    Given an interrupted render left "gostl_1A2B3C4D.csg" next to "part.scad" more than an hour ago
+    When "part.scad" is rendered
+    Then the leftover CSG file should be removed
+    And files that do not match the render file names should be kept
+
+  Scenario Outline: Temporary files location
+    Given <configuration>
+    When GoSTL starts
+    Then temporary files should be written below "<location>"
+
+    Examples:
+      | configuration                                     | location             |
+      | nothing is configured                             | $TMPDIR/gostl        |
+      | Settings > Files points to "~/scratch"            | ~/scratch            |
+      | GOSTL_TEMP_DIR is "/Volumes/fast/tmp"             | /Volumes/fast/tmp    |
+
+  Scenario: Environment variable wins over the setting
+    Given Settings > Files points to "~/scratch"
+    And GOSTL_TEMP_DIR is "/Volumes/fast/tmp"
+    When GoSTL starts
+    Then temporary files should be written below "/Volumes/fast/tmp"
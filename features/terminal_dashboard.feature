@@ -0,0 +1,46 @@
+@cli @analysis
+Feature: Terminal Dashboard
+  As a user working over SSH or on a headless machine
+  I want a live-updating analysis dashboard in the terminal
+  So that I can iterate on OpenSCAD models without the viewer
+
+  Scenario: Start the dashboard
+    Given OpenSCAD is installed
+    When I run "gostl tui bracket.scad" in an interactive terminal
+    Then the terminal should switch to a full-screen dashboard
+    And the title bar should show "bracket.scad" and the time of the last analysis
+    And the status should show "Up to date, analyzed in" with the duration and run number
+    And the watched files should list bracket.scad and its includes
+    And the metrics panel should show triangles, dimensions, volume, surface area and weight
+
+  Scenario: Re-analyze on save
+    Given the dashboard shows "bracket.scad"
+    When I save a change to an included file
+    Then the status should show "Rendering…"
+    And the metrics should update when the render finishes
+    And changed metrics should be marked with ▲ or ▼ and the difference
+
+  Scenario: Render log
+    Given the model prints ECHO messages and warnings
+    Then the render log panel should show the newest messages
+    When the render fails
+    Then the status should show "Failed:" and the first line of the error
+    And the render log should show the OpenSCAD errors
+    And the metrics of the last successful analysis should stay on screen
+
+  Scenario: Keys and resizing
+    Given the dashboard is running
+    When I press "r"
+    Then the model should be rendered and analyzed again
+    When I resize the terminal
+    Then the dashboard should be laid out for the new size, keeping the newest log lines
+    When I press "q" or Ctrl-C
+    Then the terminal should be restored
+
+  Scenario: Analysis options
+    When I run "gostl tui part.stl --material petg --infill 20 --stability"
+    Then the metrics should include the mass, cost and stability check
+
+  Scenario: No interactive terminal
+    When I run "gostl tui part.scad > out.txt"
+    Then the command should fail and suggest "gostl analyze --watch"
@@ -0,0 +1,36 @@
+@cli @export @transformation
+Feature: Embossed and Engraved Text
+  As a user labeling printed parts with IDs
+  I want to put text on a flat face of a model
+  So that I do not have to round-trip through OpenSCAD
+
+  Scenario: Emboss a part ID on the top face
+    When I run "gostl emboss bracket.stl ID-042"
+    Then "bracket-embossed.stl" should have "ID-042" raised 0.6 mm on the top face
+    And the letters should be as large as fits the face, centered on it
+    And a closed model should stay closed
+
+  Scenario: Engrave on a side face
+    When I run "gostl emboss bracket.stl A7 --face front --engrave --depth 0.4 --height 5"
+    Then "bracket-engraved.stl" should have "A7" cut 0.4 mm into the front face
+    And the letters should be 5 mm high and read upright
+
+  Scenario: Position, rotation and weight
+    When I run "gostl emboss plate.stl REV-B --offset 0,-8 --rotate 90 --font bold"
+    Then the text should be 8 mm below the face center, turned by 90°, with wider strokes
+
+  Scenario: Text that does not fit
+    When I run "gostl emboss clip.stl LONG-PART-NAME --height 10"
+    Then the error should say the text does not fit on the face
+
+  Scenario: Unsupported characters
+    When I run "gostl emboss bracket.stl A#1"
+    Then the error should be "Cannot emboss #; use letters, digits, space and . - + / %."
+
+  Scenario: Emboss in the viewer
+    Given a model is loaded
+    And I have selected triangles on a flat face
+    When I choose Tools > Emboss Text on Selected Face...
+    And I enter the text and choose Emboss or Engrave
+    Then the text should appear on the face
+    And Undo Leveling should restore the model
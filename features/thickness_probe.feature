@@ -0,0 +1,29 @@
+@measurement @thickness
+Feature: Thickness Probe
+  As a user checking a wall or rib
+  I want to click a surface point and get the material thickness behind it
+  So that I get one spot without running the full wall thickness analysis
+
+  Scenario: Probe a wall
+    Given a model is loaded
+    And "Thickness Probe" is enabled in the Tools menu
+    When I click a point on the surface
+    Then a ray should be cast inward along the face normal
+    And the distance to where it leaves the solid should be shown in the Thickness Probe panel
+    And it should be kept as a distance measurement noted "Thickness" with a marker at the exit point
+
+  Scenario: The probe follows the normal, not the view
+    Given "Thickness Probe" is enabled
+    When I click a side wall at an oblique angle
+    Then the thickness should be measured straight across the wall
+
+  Scenario: No opposite wall
+    Given "Thickness Probe" is enabled
+    When I click a face of an open mesh with nothing behind it
+    Then no measurement should be added
+    And the log should report "No opposite wall found"
+
+  Scenario: End the probe mode
+    Given "Thickness Probe" is enabled
+    When I press Escape
+    Then clicks should pick measurement points again
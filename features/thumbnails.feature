@@ -0,0 +1,52 @@
+@thumbnails @cli
+Feature: Thumbnails
+  As a user browsing folders of printable parts
+  I want preview images of my models
+  So that file managers and slicers show what each file contains
+
+  Background:
+    Given a folder "parts" with "bracket.stl" and "case.3mf"
+
+  Scenario: Render a folder of thumbnails
+    When I run "gostl thumbs parts"
+    Then "parts/thumbnails/bracket.stl.png" and "parts/thumbnails/case.3mf.png" should be written
+    And each image should be 256 x 256 pixels with a transparent background
+    And each model should be shaded with the viewer's mesh pipeline from the home view
+
+  Scenario: Choose size and output folder
+    When I run "gostl thumbs parts --size 512 --output previews"
+    Then 512 x 512 pixel thumbnails should be written to "previews"
+
+  Scenario: Skip up-to-date thumbnails
+    Given the thumbnails of "parts" are newer than their models
+    When I run "gostl thumbs parts"
+    Then no thumbnail should be rendered
+    When I run "gostl thumbs parts --force"
+    Then every thumbnail should be rendered again
+
+  Scenario: Embed thumbnails into 3MF files
+    When I run "gostl thumbs parts --embed"
+    Then "case.3mf" should contain the thumbnail as "Metadata/thumbnail.png"
+    And its package relationships should reference the thumbnail
+    And "bracket.stl" should be unchanged because STL has no room for a preview
+
+  Scenario: Replace an existing 3MF thumbnail
+    Given "case.3mf" already has a package thumbnail
+    When I run "gostl thumbs parts --embed"
+    Then the existing thumbnail should be replaced at its original path
+
+  Scenario: Extract embedded thumbnails
+    Given "case.3mf" was saved by a slicer with a plate preview
+    When I run "gostl thumbs parts --extract"
+    Then "parts/thumbnails/case.3mf.png" should be the embedded preview
+    And "bracket.stl" should be rendered
+
+  Scenario: Thumbnails with ambient occlusion
+    When I run "gostl thumbs parts --ambient-occlusion"
+    Then the thumbnails should show creases and cavities darker than open surfaces
+
+  Scenario: Unreadable model
+    Given "parts/broken.stl" is not a valid STL file
+    When I run "gostl thumbs parts"
+    Then the other thumbnails should still be written
+    And the command should exit with status 1